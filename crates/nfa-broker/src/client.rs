@@ -47,6 +47,8 @@ impl BrokerClient {
         
         let request = Request::new(RegisterIntentRequest {
             contract: Some(proto_contract),
+            protocol_version: crate::service::PROTOCOL_VERSION,
+            ..Default::default()
         });
         
         let response = self.client.register_intent(request).await?;
@@ -136,6 +138,15 @@ impl BrokerClient {
                 parameters: std::collections::HashMap::new(), // 需要完整转换
             }),
             constraints: None,
+            streaming: pattern.streaming as i32,
+            classification: pattern.classification.map(|c| nfa::intent::v1alpha::DataClassification {
+                residency: match c.residency {
+                    nfa_common::intent::Residency::OnDevice => 1,
+                    nfa_common::intent::Residency::Region => 2,
+                },
+                regions: c.regions,
+            }),
+            aliases: pattern.aliases,
         })
     }
     
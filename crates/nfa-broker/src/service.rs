@@ -1,10 +1,12 @@
 use crate::BrokerError;
-use nfa_common::intent::{IntentRequest, IntentResponse};
+use nfa_common::intent::{IntentRequest, IntentResponse, StreamingMode};
+use nfa_common::redact::{rules_from_patterns, Redactor};
 use nfa_idl::IntentContract;
 use std::collections::HashMap;
 use std::sync::Arc;
 use tokio::sync::RwLock;
 use tonic::{Request, Response, Status};
+use tracing::{info, warn};
 
 use nfa::intent::v1alpha::{
     intent_broker_server::IntentBroker, RegisterIntentRequest, RegisterIntentResponse,
@@ -18,10 +20,53 @@ pub struct RegisteredService {
     pub is_healthy: bool,
 }
 
+/// 超过该时长没有心跳的服务视为不健康，其稳定ID可被新实例接管
+const LIVENESS_TIMEOUT_SECS: u64 = 30;
+
+impl RegisteredService {
+    fn is_live(&self) -> bool {
+        self.last_heartbeat.elapsed().as_secs() < LIVENESS_TIMEOUT_SECS
+    }
+}
+
+/// 注册握手的协议版本，1为协商之前的运行时和Broker所用版本，与Go的broker.ProtocolVersion一致
+pub const PROTOCOL_VERSION: u32 = 2;
+pub const MIN_PROTOCOL_VERSION: u32 = 1;
+
+/// 本Broker提供的可选特性，名称与Go的broker.Feature*常量一致
+pub const BROKER_FEATURES: &[&str] = &["streaming", "take_over", "residency"];
+
+/// 协商协议版本：运行时未发送版本（0）时视为1，取双方较高版本中的较低者
+pub fn negotiate_version(requested: u32) -> Result<u32, Status> {
+    let requested = requested.max(1);
+    if requested < MIN_PROTOCOL_VERSION {
+        return Err(Status::failed_precondition(format!(
+            "incompatible broker protocol version: runtime speaks {}, broker supports {} to {}",
+            requested, MIN_PROTOCOL_VERSION, PROTOCOL_VERSION
+        )));
+    }
+    Ok(requested.min(PROTOCOL_VERSION))
+}
+
+/// 由契约名和实例键确定服务ID：`<name>-<FNV-1a 64位哈希的16位十六进制>`，
+/// 与Go客户端的broker.StableServiceID一致
+pub fn stable_service_id(name: &str, instance_key: &str) -> String {
+    let mut hash: u64 = 0xcbf29ce484222325;
+    for byte in name.bytes().chain(std::iter::once(0)).chain(instance_key.bytes()) {
+        hash ^= byte as u64;
+        hash = hash.wrapping_mul(0x100000001b3);
+    }
+    format!("{}-{:016x}", name, hash)
+}
+
 #[derive(Debug, Default)]
 pub struct BrokerService {
     services: Arc<RwLock<HashMap<String, RegisteredService>>>,
     pattern_index: Arc<RwLock<HashMap<String, Vec<String>>>>, // pattern -> service_ids
+    // 由已注册契约中的参数敏感级别得到的脱敏规则，记录意图参数前使用
+    redactor: Arc<Redactor>,
+    // 每个旧名称（别名 -> 当前名称）的匹配次数，用于决定何时移除别名
+    alias_matches: Arc<RwLock<HashMap<(String, String), u64>>>,
 }
 
 #[tonic::async_trait]
@@ -31,6 +76,7 @@ impl IntentBroker for BrokerService {
         request: Request<RegisterIntentRequest>,
     ) -> Result<Response<RegisterIntentResponse>, Status> {
         let req = request.into_inner();
+        let protocol_version = negotiate_version(req.protocol_version)?;
         let proto_contract = req.contract.ok_or(Status::invalid_argument("contract is required"))?;
         
         // Convert proto contract to internal representation
@@ -40,11 +86,30 @@ impl IntentBroker for BrokerService {
         nfa_idl::validate_contract(&contract)
             .map_err(|e| Status::invalid_argument(e.to_string()))?;
         
-        // Generate service ID
-        let service_id = format!("{}-{}", contract.metadata.name, uuid::Uuid::new_v4());
-        
-        // Register service
+        // 提供了实例键时服务ID由契约名和实例键确定，重启后保持不变
+        let service_id = if req.instance_key.is_empty() {
+            format!("{}-{}", contract.metadata.name, uuid::Uuid::new_v4())
+        } else {
+            stable_service_id(&contract.metadata.name, &req.instance_key)
+        };
+
         let mut services = self.services.write().await;
+        let resumed = match services.get(&service_id) {
+            Some(existing) if existing.is_live() && !req.take_over => {
+                return Err(Status::already_exists(format!(
+                    "service id {} is held by a live instance; retry after {}s or set take_over",
+                    service_id, LIVENESS_TIMEOUT_SECS
+                )));
+            }
+            Some(_) => true,
+            None => false,
+        };
+        if resumed {
+            // 旧注册的契约可能提供不同的动作，先移除其索引
+            self.unindex_patterns(&service_id).await;
+        }
+
+        // Register service
         services.insert(
             service_id.clone(),
             RegisteredService {
@@ -57,11 +122,23 @@ impl IntentBroker for BrokerService {
         // Index patterns
         self.index_patterns(&service_id, &services[&service_id].contract)
             .await;
+        self.redactor.set(
+            &service_id,
+            rules_from_patterns(&services[&service_id].contract.spec.intent_patterns),
+        );
         
+        let message = if resumed {
+            "Service re-registered with its previous id"
+        } else {
+            "Service registered successfully"
+        };
         Ok(Response::new(RegisterIntentResponse {
             service_id,
             success: true,
-            message: "Service registered successfully".to_string(),
+            message: message.to_string(),
+            resumed,
+            protocol_version,
+            features: BROKER_FEATURES.iter().map(|f| f.to_string()).collect(),
         }))
     }
 
@@ -71,46 +148,113 @@ impl IntentBroker for BrokerService {
     ) -> Result<Response<IntentMatchResponse>, Status> {
         let req = request.into_inner();
         let action = req.action.ok_or(Status::invalid_argument("action is required"))?;
+        let streaming = StreamingMode::from_proto(req.streaming)
+            .ok_or(Status::invalid_argument("unknown streaming mode"))?;
         
+        let consumer_device = req.context.as_ref().map(|c| c.device_id.as_str());
+
         // Find matching services
         let pattern_index = self.pattern_index.read().await;
         let services = self.services.read().await;
         
         let mut matches = Vec::new();
+        let mut current = None;
         
         if let Some(service_ids) = pattern_index.get(&action) {
             for service_id in service_ids {
                 if let Some(service) = services.get(service_id) {
-                    if service.is_healthy {
-                        matches.push(service_id.clone());
+                    // 同一动作可能以不同的调用方式提供，只匹配请求的方式
+                    let pattern = service.contract.spec.intent_patterns.iter().find(|p| {
+                        p.serves(&action) && p.streaming == streaming
+                    });
+                    let pattern = match pattern {
+                        Some(pattern) if service.is_healthy => pattern,
+                        _ => continue,
+                    };
+                    // 意图数据有驻留限制时，不路由到边界外的提供者，并记录审计
+                    if let Some(reason) = residency_refusal(&service.contract, &action, consumer_device) {
+                        warn!(
+                            target: "nfa::audit",
+                            action = %action,
+                            service_id = %service_id,
+                            reason = %reason,
+                            "refused to route intent outside its data boundary"
+                        );
+                        continue;
+                    }
+                    if pattern.pattern.action != action {
+                        current = Some(pattern.pattern.action.clone());
                     }
+                    matches.push(service_id.clone());
                 }
             }
         }
+
+        // 按旧名称匹配时返回当前名称并计数，以便确定何时可以移除别名
+        if let Some(current) = &current {
+            *self.alias_matches.write().await.entry((action.clone(), current.clone())).or_insert(0) += 1;
+            info!(alias = %action, action = %current, "intent matched by deprecated alias");
+        }
         
         Ok(Response::new(IntentMatchResponse {
             service_ids: matches,
+            action: current.unwrap_or_default(),
         }))
     }
 }
 
+/// 服务的契约为动作声明了数据分类且提供者不在边界内时返回拒绝原因
+fn residency_refusal(contract: &IntentContract, action: &str, consumer_device: Option<&str>) -> Option<String> {
+    let empty = HashMap::new();
+    let labels = contract.metadata.labels.as_ref().unwrap_or(&empty);
+    contract
+        .spec
+        .intent_patterns
+        .iter()
+        .filter(|p| p.serves(action))
+        .filter_map(|p| p.classification.as_ref())
+        .find_map(|c| c.refuse(consumer_device, labels))
+}
+
 impl BrokerService {
     pub fn new() -> Self {
         Self::default()
     }
+
+    /// 各别名（旧名称, 当前名称）的匹配次数
+    pub async fn alias_matches(&self) -> HashMap<(String, String), u64> {
+        self.alias_matches.read().await.clone()
+    }
+
+    /// 脱敏规则，日志、追踪、审计和分析数据中的意图参数须先经它遮盖
+    pub fn redactor(&self) -> Arc<Redactor> {
+        self.redactor.clone()
+    }
     
     async fn index_patterns(&self, service_id: &str, contract: &IntentContract) {
         let mut pattern_index = self.pattern_index.write().await;
         
         for pattern in &contract.spec.intent_patterns {
-            let action = pattern.pattern.action.clone();
-            pattern_index
-                .entry(action)
-                .or_insert_with(Vec::new)
-                .push(service_id.to_string());
+            // 旧名称与当前名称一样索引，匹配时再转发到当前名称
+            let actions = std::iter::once(&pattern.pattern.action).chain(&pattern.aliases);
+            for action in actions {
+                let service_ids = pattern_index.entry(action.clone()).or_insert_with(Vec::new);
+                // 同一动作的多种调用方式只索引一次
+                if !service_ids.iter().any(|id| id == service_id) {
+                    service_ids.push(service_id.to_string());
+                }
+            }
         }
     }
     
+    async fn unindex_patterns(&self, service_id: &str) {
+        let mut pattern_index = self.pattern_index.write().await;
+        for service_ids in pattern_index.values_mut() {
+            service_ids.retain(|id| id != service_id);
+        }
+        pattern_index.retain(|_, service_ids| !service_ids.is_empty());
+    }
+
     fn proto_to_contract(&self, proto: ProtoIntentContract) -> Result<IntentContract, Status> {
         // Implementation for converting proto to internal contract representation
         // This is a simplified version - actual implementation would be more complex
@@ -143,7 +287,21 @@ impl BrokerService {
         let mut services = self.services.write().await;
         for (_, service) in services.iter_mut() {
             let elapsed = service.last_heartbeat.elapsed();
-            service.is_healthy = elapsed.as_secs() < 30; // 30 second timeout
+            service.is_healthy = elapsed.as_secs() < LIVENESS_TIMEOUT_SECS;
         }
     }
-}
\ No newline at end of file
+}
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn stable_service_id_is_deterministic() {
+        let id = stable_service_id("translator", "device-7");
+        assert_eq!(id, stable_service_id("translator", "device-7"));
+        assert!(id.starts_with("translator-"));
+        assert_ne!(id, stable_service_id("translator", "device-8"));
+        // 名称和实例键之间有分隔符，拼接相同的不同组合不会冲突
+        assert_ne!(stable_service_id("ab", "c"), stable_service_id("a", "bc"));
+    }
+}
@@ -66,10 +66,11 @@ impl StorageBackend for MemoryStorage {
         let mut pattern_index = self.pattern_index.write().await;
         for intent_pattern in &contract.spec.intent_patterns {
             let action = intent_pattern.pattern.action.clone();
-            pattern_index
-                .entry(action)
-                .or_insert_with(Vec::new)
-                .push(service_id.clone());
+            let service_ids = pattern_index.entry(action).or_insert_with(Vec::new);
+            // 同一动作的多种调用方式只索引一次
+            if !service_ids.contains(&service_id) {
+                service_ids.push(service_id.clone());
+            }
         }
         
         Ok(())
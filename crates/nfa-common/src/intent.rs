@@ -5,6 +5,97 @@ use serde::{Deserialize, Serialize};
 pub struct IntentPattern {
     pub pattern: Pattern,
     pub constraints: Option<PatternConstraints>,
+    #[serde(default)]
+    pub streaming: StreamingMode,
+    /// 意图数据的驻留限制，None表示可路由到任意提供者
+    #[serde(default)]
+    pub classification: Option<DataClassification>,
+    /// 动作的旧名称，按旧名称发出的意图转发到本动作
+    #[serde(default)]
+    pub aliases: Vec<String>,
+}
+
+impl IntentPattern {
+    /// 模式是否以当前名称或旧名称提供该动作
+    pub fn serves(&self, action: &str) -> bool {
+        self.pattern.action == action || self.aliases.iter().any(|a| a == action)
+    }
+}
+
+/// 提供者与消费者所在设备、区域的标签
+pub const LABEL_DEVICE: &str = "nfa.device";
+pub const LABEL_REGION: &str = "nfa.region";
+
+/// 数据分类，限制意图可以被路由到哪些提供者
+#[derive(Debug, Serialize, Deserialize, Clone, PartialEq, Eq)]
+pub struct DataClassification {
+    pub residency: Residency,
+    #[serde(default)]
+    pub regions: Vec<String>,
+}
+
+#[derive(Debug, Serialize, Deserialize, Clone, Copy, PartialEq, Eq)]
+#[serde(rename_all = "kebab-case")]
+pub enum Residency {
+    /// 只能由与消费者位于同一设备的提供者处理
+    OnDevice,
+    /// 只能由位于regions中的提供者处理
+    Region,
+}
+
+impl DataClassification {
+    /// 提供者不在边界内时返回拒绝原因
+    pub fn refuse(
+        &self,
+        consumer_device: Option<&str>,
+        provider_labels: &std::collections::HashMap<String, String>,
+    ) -> Option<String> {
+        match self.residency {
+            Residency::OnDevice => match consumer_device {
+                None | Some("") => Some("consumer does not report its device".to_string()),
+                Some(device) if provider_labels.get(LABEL_DEVICE).map(String::as_str) != Some(device) => {
+                    Some(format!("provider is not on the consumer's device {}", device))
+                }
+                _ => None,
+            },
+            Residency::Region => {
+                let region = provider_labels.get(LABEL_REGION).cloned().unwrap_or_default();
+                if self.regions.contains(&region) {
+                    None
+                } else {
+                    Some(format!(
+                        "provider region {:?} is not one of {}",
+                        region,
+                        self.regions.join(", ")
+                    ))
+                }
+            }
+        }
+    }
+}
+
+/// 意图的调用方式
+#[derive(Debug, Serialize, Deserialize, Clone, Copy, PartialEq, Eq, Default)]
+#[serde(rename_all = "lowercase")]
+pub enum StreamingMode {
+    #[default]
+    Unary = 0,
+    Server = 1,
+    Client = 2,
+    Bidi = 3,
+}
+
+impl StreamingMode {
+    /// 从proto中的StreamingMode枚举值转换
+    pub fn from_proto(value: i32) -> Option<Self> {
+        match value {
+            0 => Some(StreamingMode::Unary),
+            1 => Some(StreamingMode::Server),
+            2 => Some(StreamingMode::Client),
+            3 => Some(StreamingMode::Bidi),
+            _ => None,
+        }
+    }
 }
 
 #[derive(Debug, Serialize, Deserialize, Clone)]
@@ -26,6 +117,37 @@ pub struct ParameterConstraint {
     pub enum_values: Option<Vec<String>>,
     pub min: Option<f64>,
     pub max: Option<f64>,
+    /// 参数值在日志、追踪、审计和分析数据中的脱敏方式
+    #[serde(default)]
+    pub sensitivity: Option<Sensitivity>,
+}
+
+/// 参数的敏感级别，与proto中的Sensitivity枚举对应
+#[derive(Debug, Serialize, Deserialize, Clone, Copy, PartialEq, Eq)]
+#[serde(rename_all = "lowercase")]
+pub enum Sensitivity {
+    /// 个人身份信息，如邮箱、电话、位置
+    Pii,
+    /// 凭据等其他机密值
+    Sensitive,
+}
+
+impl Sensitivity {
+    /// 从proto中的Sensitivity枚举值转换，未知值按Sensitive处理以免明文泄露
+    pub fn from_proto(value: i32) -> Option<Self> {
+        match value {
+            0 => None,
+            1 => Some(Sensitivity::Pii),
+            _ => Some(Sensitivity::Sensitive),
+        }
+    }
+
+    pub fn as_str(&self) -> &'static str {
+        match self {
+            Sensitivity::Pii => "pii",
+            Sensitivity::Sensitive => "sensitive",
+        }
+    }
 }
 
 /// 意图请求和响应
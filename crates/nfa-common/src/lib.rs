@@ -1,6 +1,7 @@
 pub mod intent;
 pub mod types;
 pub mod errors;
+pub mod redact;
 
 // 重新导出常用类型
 pub use intent::*;
\ No newline at end of file
@@ -0,0 +1,157 @@
+//! 敏感参数脱敏
+//!
+//! 契约在参数约束中声明参数的敏感级别，Broker、网关和运行时据此在写日志、
+//! 追踪、审计和分析数据前遮盖参数值。遮盖格式与Go的`pkg/redact`一致：
+//! `[REDACTED:<sensitivity>]`。
+
+use crate::intent::{IntentPattern, Sensitivity};
+use std::collections::HashMap;
+use std::sync::RwLock;
+
+/// 动作 -> 参数名 -> 敏感级别
+pub type Rules = HashMap<String, HashMap<String, Sensitivity>>;
+
+/// 替换某一敏感级别参数值的占位符
+pub fn mask(sensitivity: Sensitivity) -> String {
+    format!("[REDACTED:{}]", sensitivity.as_str())
+}
+
+/// 从契约的意图模式中提取脱敏规则
+pub fn rules_from_patterns(patterns: &[IntentPattern]) -> Rules {
+    let mut rules = Rules::new();
+    for pattern in patterns {
+        let constraints = pattern
+            .constraints
+            .as_ref()
+            .and_then(|c| c.parameter_constraints.as_ref());
+        for (name, constraint) in constraints.into_iter().flatten() {
+            if let Some(sensitivity) = constraint.sensitivity {
+                add(&mut rules, &pattern.pattern.action, name, sensitivity);
+            }
+        }
+    }
+    rules
+}
+
+fn add(rules: &mut Rules, action: &str, parameter: &str, sensitivity: Sensitivity) {
+    let params = rules.entry(action.to_string()).or_default();
+    // 多个契约标记同一参数时取更严格的级别
+    if params.get(parameter) != Some(&Sensitivity::Sensitive) {
+        params.insert(parameter.to_string(), sensitivity);
+    }
+}
+
+/// 按一组契约的规则脱敏，同一动作由多个契约提供时任一契约标记的参数都会被遮盖
+#[derive(Debug, Default)]
+pub struct Redactor {
+    inner: RwLock<RedactorState>,
+}
+
+#[derive(Debug, Default)]
+struct RedactorState {
+    sources: HashMap<String, Rules>,
+    merged: Rules,
+}
+
+impl Redactor {
+    pub fn new() -> Self {
+        Self::default()
+    }
+
+    /// 设置或替换某个契约（或服务）的规则
+    pub fn set(&self, source: &str, rules: Rules) {
+        let mut state = self.inner.write().unwrap();
+        state.sources.insert(source.to_string(), rules);
+        state.merge();
+    }
+
+    /// 移除某个契约（或服务）的规则，例如服务注销时
+    pub fn remove(&self, source: &str) {
+        let mut state = self.inner.write().unwrap();
+        state.sources.remove(source);
+        state.merge();
+    }
+
+    /// 参数的敏感级别，None表示可以明文记录
+    pub fn sensitivity(&self, action: &str, parameter: &str) -> Option<Sensitivity> {
+        let state = self.inner.read().unwrap();
+        state.merged.get(action)?.get(parameter).copied()
+    }
+
+    /// 返回遮盖了敏感值的字符串参数副本
+    pub fn redact_strings(&self, action: &str, params: &HashMap<String, String>) -> HashMap<String, String> {
+        params
+            .iter()
+            .map(|(k, v)| match self.sensitivity(action, k) {
+                Some(s) => (k.clone(), mask(s)),
+                None => (k.clone(), v.clone()),
+            })
+            .collect()
+    }
+
+    /// 返回遮盖了敏感值的JSON参数副本，敏感参数的嵌套值整体遮盖
+    pub fn redact_json(
+        &self,
+        action: &str,
+        params: &HashMap<String, serde_json::Value>,
+    ) -> HashMap<String, serde_json::Value> {
+        params
+            .iter()
+            .map(|(k, v)| match self.sensitivity(action, k) {
+                Some(s) => (k.clone(), serde_json::Value::String(mask(s))),
+                None => (k.clone(), v.clone()),
+            })
+            .collect()
+    }
+}
+
+impl RedactorState {
+    fn merge(&mut self) {
+        let mut merged = Rules::new();
+        for rules in self.sources.values() {
+            for (action, params) in rules {
+                for (parameter, sensitivity) in params {
+                    add(&mut merged, action, parameter, *sensitivity);
+                }
+            }
+        }
+        self.merged = merged;
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn masks_marked_parameters_only() {
+        let mut rules = Rules::new();
+        add(&mut rules, "send", "to", Sensitivity::Pii);
+        let redactor = Redactor::new();
+        redactor.set("mail", rules);
+
+        let params = HashMap::from([
+            ("to".to_string(), "a@example.com".to_string()),
+            ("subject".to_string(), "hi".to_string()),
+        ]);
+        let redacted = redactor.redact_strings("send", &params);
+        assert_eq!(redacted["to"], "[REDACTED:pii]");
+        assert_eq!(redacted["subject"], "hi");
+        assert_eq!(redactor.redact_strings("other", &params)["to"], "a@example.com");
+
+        redactor.remove("mail");
+        assert_eq!(redactor.sensitivity("send", "to"), None);
+    }
+
+    #[test]
+    fn strictest_sensitivity_wins() {
+        let mut a = Rules::new();
+        add(&mut a, "pay", "card", Sensitivity::Sensitive);
+        let mut b = Rules::new();
+        add(&mut b, "pay", "card", Sensitivity::Pii);
+        let redactor = Redactor::new();
+        redactor.set("a", a);
+        redactor.set("b", b);
+        assert_eq!(redactor.sensitivity("pay", "card"), Some(Sensitivity::Sensitive));
+    }
+}
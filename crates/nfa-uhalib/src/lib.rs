@@ -300,13 +300,17 @@ impl HardwareAbstraction for UnifiedHardwareAbstraction {
     }
     
     async fn get_node_resource_info(&self) -> Result<NodeResourceInfo, UHAError> {
-        // 获取系统总资源信息（简化实现）
+        // 获取系统总资源信息，内存按平台检测，无法检测时为0
+        let cpus = std::thread::available_parallelism()
+            .map(|n| n.get() as f64)
+            .unwrap_or(1.0);
+        let (total_memory, available_memory) = platform::system_memory().unwrap_or((0, 0));
         Ok(NodeResourceInfo {
             node_id: "local-node".to_string(),
-            total_cpu: 16.0,
-            available_cpu: 16.0,
-            total_memory: 16 * 1024 * 1024 * 1024, // 16GB
-            available_memory: 16 * 1024 * 1024 * 1024,
+            total_cpu: cpus,
+            available_cpu: cpus,
+            total_memory,
+            available_memory,
             accelerators: Vec::new(),
             network_bandwidth: 1000, // 1Gbps
             network_latency: 1,      // 1ms
@@ -315,8 +319,56 @@ impl HardwareAbstraction for UnifiedHardwareAbstraction {
     }
 }
 
+/// 平台相关的系统内存检测，返回(总内存, 可用内存)字节数
+mod platform {
+    #[cfg(target_os = "linux")]
+    pub fn system_memory() -> Option<(u64, u64)> {
+        let meminfo = std::fs::read_to_string("/proc/meminfo").ok()?;
+        parse_meminfo(&meminfo)
+    }
+
+    #[cfg(not(target_os = "linux"))]
+    pub fn system_memory() -> Option<(u64, u64)> {
+        None
+    }
+
+    /// 解析/proc/meminfo中的MemTotal和MemAvailable（单位kB）
+    #[cfg_attr(not(target_os = "linux"), allow(dead_code))]
+    pub fn parse_meminfo(meminfo: &str) -> Option<(u64, u64)> {
+        let field = |name: &str| {
+            meminfo
+                .lines()
+                .find_map(|line| line.strip_prefix(name)?.strip_prefix(':'))
+                .and_then(|value| value.split_whitespace().next()?.parse::<u64>().ok())
+                .map(|kb| kb * 1024)
+        };
+        Some((field("MemTotal")?, field("MemAvailable").unwrap_or(0)))
+    }
+
+    #[cfg(test)]
+    mod tests {
+        use super::*;
+
+        #[test]
+        fn parses_meminfo() {
+            let meminfo = "MemTotal:        8024380 kB\nMemFree:  512000 kB\nMemAvailable:    4012190 kB\n";
+            assert_eq!(parse_meminfo(meminfo), Some((8024380 * 1024, 4012190 * 1024)));
+            assert_eq!(parse_meminfo("MemFree: 1 kB\n"), None);
+        }
+
+        #[test]
+        fn detects_memory_on_supported_platforms() {
+            let memory = system_memory();
+            if cfg!(target_os = "linux") {
+                let (total, available) = memory.expect("memory should be detected on Linux");
+                assert!(total > 0 && available <= total);
+            }
+        }
+    }
+}
+
 /// 平台特定的硬件抽象
-#[cfg(target_os = "linux")]
+#[cfg(all(target_os = "linux", feature = "udev"))]
 pub mod linux {
     use super::*;
     
@@ -2,15 +2,9 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"log"
-	"net"
 
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/health"
-	"google.golang.org/grpc/health/grpc_health_v1"
-
-	"github.com/neuro-fluidic-architecture/nfa-core/go/runtime"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/runtime"
 	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
 )
 
@@ -60,14 +54,14 @@ func (s *TranslatorService) TranslateText(ctx context.Context, req *nfa_intent_v
 
 func main() {
 	// Create and connect runtime
-	runtime := runtime.NewIntentRuntime("localhost:50051")
-	if err := runtime.Connect(); err != nil {
+	rt := runtime.NewIntentRuntime("localhost:50051")
+	if err := rt.Connect(); err != nil {
 		log.Fatalf("Failed to connect to broker: %v", err)
 	}
-	defer runtime.Close()
+	defer rt.Close()
 	
 	// Register the intent service
-	serviceID, err := runtime.RegisterFromFile("translator.intent.yaml")
+	serviceID, err := rt.RegisterFromFile("translator.intent.yaml")
 	if err != nil {
 		log.Fatalf("Failed to register service: %v", err)
 	}
@@ -75,14 +69,14 @@ func main() {
 	log.Printf("Service registered with ID: %s", serviceID)
 	
 	// Start health reporting
-	go runtime.StartHealthReporting()
+	go rt.StartHealthReporting(context.Background())
 	
 	// Create and start gRPC server
 	server := runtime.NewIntentServer(50052)
 	translatorService := &TranslatorService{}
 	
 	// Register the translator service
-	nfa_intent_v1alpha.RegisterTranslatorServer(server.Server, translatorService)
+	nfa_intent_v1alpha.RegisterTranslatorServer(server, translatorService)
 	
 	// Start the server
 	if err := server.Start(); err != nil {
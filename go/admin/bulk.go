@@ -0,0 +1,185 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/control"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LabelNamespace is the contract label naming the namespace of a service
+const LabelNamespace = broker.LabelNamespace
+
+// Registry holds the registrations bulk operations act on, such as an
+// embedded broker
+type Registry interface {
+	// Services lists the registrations sorted by service ID
+	Services() []broker.ServiceInfo
+	// Remove deletes a registration and reports whether it existed
+	Remove(serviceID string) bool
+	// SetLabels replaces the labels of a registration
+	SetLabels(serviceID string, labels map[string]string) error
+}
+
+// SetRegistry enables the bulk operations on the registrations of reg.
+// Providers are drained through hub, to which their runtimes connect with
+// their service ID; without a hub DrainNamespace fails as unavailable.
+func (s *Server) SetRegistry(reg Registry, hub *control.Hub) {
+	s.registry = reg
+	s.hub = hub
+}
+
+// DrainNamespace sends a drain command to every provider of a namespace. A
+// provider without an open control stream is reported as failed.
+func (s *Server) DrainNamespace(req *nfa_admin_v1alpha.DrainNamespaceRequest, stream nfa_admin_v1alpha.AdminService_DrainNamespaceServer) error {
+	if s.registry == nil || s.hub == nil {
+		return status.Error(codes.Unavailable, "bulk operations are not enabled")
+	}
+	if req.Namespace == "" {
+		return status.Error(codes.InvalidArgument, "namespace is required")
+	}
+	targets := s.matching(func(svc broker.ServiceInfo) bool {
+		return svc.Labels[LabelNamespace] == req.Namespace
+	})
+	return s.bulk("drain", targets, req.DryRun, stream, func(svc broker.ServiceInfo) (nfa_admin_v1alpha.BulkOutcome, string) {
+		if req.DryRun {
+			return nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_PLANNED, "would drain"
+		}
+		id, err := s.hub.SendCommand(svc.ServiceID, &nfa_control_v1alpha.Command{
+			Command: &nfa_control_v1alpha.Command_Drain{Drain: &nfa_control_v1alpha.Drain{
+				GracePeriodSecs: req.GracePeriodSecs,
+				Reason:          req.Reason,
+			}},
+		})
+		if err != nil {
+			return nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_FAILED, err.Error()
+		}
+		return nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_DONE, "drain command " + id + " sent"
+	})
+}
+
+// PurgeStaleRegistrations removes the registrations without a recent heartbeat
+func (s *Server) PurgeStaleRegistrations(req *nfa_admin_v1alpha.PurgeStaleRegistrationsRequest, stream nfa_admin_v1alpha.AdminService_PurgeStaleRegistrationsServer) error {
+	if s.registry == nil {
+		return status.Error(codes.Unavailable, "bulk operations are not enabled")
+	}
+	now := time.Now()
+	staleAfter := time.Duration(req.StaleAfterSecs) * time.Second
+	targets := s.matching(func(svc broker.ServiceInfo) bool {
+		if staleAfter == 0 {
+			return !svc.Live
+		}
+		return now.Sub(svc.LastHeartbeat) >= staleAfter
+	})
+	return s.bulk("purge", targets, req.DryRun, stream, func(svc broker.ServiceInfo) (nfa_admin_v1alpha.BulkOutcome, string) {
+		detail := "last heartbeat " + svc.LastHeartbeat.UTC().Format(time.RFC3339)
+		if req.DryRun {
+			return nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_PLANNED, detail
+		}
+		if !s.registry.Remove(svc.ServiceID) {
+			return nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_SKIPPED, "already unregistered"
+		}
+		return nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_DONE, detail
+	})
+}
+
+// RetagServices sets and removes labels of the services matching a selector
+func (s *Server) RetagServices(req *nfa_admin_v1alpha.RetagServicesRequest, stream nfa_admin_v1alpha.AdminService_RetagServicesServer) error {
+	if s.registry == nil {
+		return status.Error(codes.Unavailable, "bulk operations are not enabled")
+	}
+	if len(req.Selector) == 0 {
+		return status.Error(codes.InvalidArgument, "selector is required")
+	}
+	if len(req.SetLabels) == 0 && len(req.RemoveLabels) == 0 {
+		return status.Error(codes.InvalidArgument, "no labels to set or remove")
+	}
+	targets := s.matching(func(svc broker.ServiceInfo) bool {
+		return control.MatchLabels(req.Selector, svc.Labels)
+	})
+	return s.bulk("retag", targets, req.DryRun, stream, func(svc broker.ServiceInfo) (nfa_admin_v1alpha.BulkOutcome, string) {
+		labels := maps.Clone(svc.Labels)
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		maps.Copy(labels, req.SetLabels)
+		for _, key := range req.RemoveLabels {
+			delete(labels, key)
+		}
+		detail := formatLabels(labels)
+		switch {
+		case maps.Equal(labels, svc.Labels):
+			return nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_SKIPPED, "labels unchanged"
+		case req.DryRun:
+			return nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_PLANNED, detail
+		}
+		if err := s.registry.SetLabels(svc.ServiceID, labels); err != nil {
+			return nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_FAILED, err.Error()
+		}
+		return nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_DONE, detail
+	})
+}
+
+// matching returns the registrations for which fn reports true
+func (s *Server) matching(fn func(broker.ServiceInfo) bool) []broker.ServiceInfo {
+	var targets []broker.ServiceInfo
+	for _, svc := range s.registry.Services() {
+		if fn(svc) {
+			targets = append(targets, svc)
+		}
+	}
+	return targets
+}
+
+// progressStream is the server stream of every bulk operation
+type progressStream interface {
+	Send(*nfa_admin_v1alpha.BulkProgress) error
+	Context() context.Context
+}
+
+// bulk applies fn to every target in order, sending its outcome as progress.
+// It stops when the caller goes away; targets already processed stay changed.
+func (s *Server) bulk(op string, targets []broker.ServiceInfo, dryRun bool, stream progressStream, fn func(broker.ServiceInfo) (nfa_admin_v1alpha.BulkOutcome, string)) error {
+	counts := make(map[nfa_admin_v1alpha.BulkOutcome]int)
+	for i, svc := range targets {
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		outcome, detail := fn(svc)
+		counts[outcome]++
+		if err := stream.Send(&nfa_admin_v1alpha.BulkProgress{
+			ServiceId: svc.ServiceID,
+			Outcome:   outcome,
+			Detail:    detail,
+			Completed: uint32(i + 1),
+			Total:     uint32(len(targets)),
+		}); err != nil {
+			return err
+		}
+	}
+	logging.Logger(logging.Control).Info("bulk operation finished", "operation", op, "dry_run", dryRun,
+		"services", len(targets),
+		"done", counts[nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_DONE],
+		"failed", counts[nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_FAILED])
+	return nil
+}
+
+// formatLabels renders labels as sorted key=value pairs
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
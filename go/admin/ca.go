@@ -0,0 +1,65 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/ca"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultTokenTTL is the lifetime of bootstrap tokens created without one
+const defaultTokenTTL = time.Hour
+
+// SetCertificateAuthority manages enrollment with the broker's built-in CA;
+// without one CreateBootstrapToken and RevokeDevice fail as unavailable
+func (s *Server) SetCertificateAuthority(a *ca.Authority) {
+	s.authority = a
+}
+
+// CreateBootstrapToken creates a one-time token enrolling a device of a
+// tenant, returned with the CA hash the runtime pins
+func (s *Server) CreateBootstrapToken(ctx context.Context, req *nfa_admin_v1alpha.CreateBootstrapTokenRequest) (*nfa_admin_v1alpha.CreateBootstrapTokenResponse, error) {
+	if s.authority == nil {
+		return nil, status.Error(codes.Unavailable, "built-in CA is not enabled")
+	}
+	ttl := defaultTokenTTL
+	if req.TtlSecs > 0 {
+		ttl = time.Duration(req.TtlSecs) * time.Second
+	}
+	token, err := s.authority.CreateToken(req.Tenant, req.Device, ttl)
+	if errors.Is(err, ca.ErrState) {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	logging.Logger(logging.Security).Info("bootstrap token created", "tenant", req.Tenant, "device", req.Device, "ttl", ttl)
+	return &nfa_admin_v1alpha.CreateBootstrapTokenResponse{
+		Token:       token,
+		CaHash:      s.authority.Hash(),
+		ExpiresUnix: time.Now().Add(ttl).Unix(),
+	}, nil
+}
+
+// RevokeDevice stops renewing the certificates of a device and refuses its
+// calls where the broker checks identities
+func (s *Server) RevokeDevice(ctx context.Context, req *nfa_admin_v1alpha.RevokeDeviceRequest) (*nfa_admin_v1alpha.RevokeDeviceResponse, error) {
+	if s.authority == nil {
+		return nil, status.Error(codes.Unavailable, "built-in CA is not enabled")
+	}
+	if req.Tenant == "" || req.Device == "" {
+		return nil, status.Error(codes.InvalidArgument, "tenant and device are required")
+	}
+	id := ca.Identity{Tenant: req.Tenant, Device: req.Device}
+	if err := s.authority.Revoke(id); err != nil {
+		logging.Logger(logging.Security).Error("device revoked until restart", "tenant", id.Tenant, "device", id.Device, "error", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	logging.Logger(logging.Security).Info("device revoked", "tenant", id.Tenant, "device", id.Device)
+	return &nfa_admin_v1alpha.RevokeDeviceResponse{}, nil
+}
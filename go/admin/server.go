@@ -0,0 +1,244 @@
+// Package admin implements the operator-facing AdminService shared by the
+// broker and gateway processes
+package admin
+
+import (
+	"context"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/ca"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/config"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/control"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/deprecation"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/errorreport"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/retention"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/sla"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errNoReloader fails the configuration RPCs of a server without a reloader,
+// e.g. of a process started without a configuration file
+var errNoReloader = status.Error(codes.Unimplemented, "the process was started without a configuration file to reload")
+
+// Server implements the AdminService gRPC API
+type Server struct {
+	nfa_admin_v1alpha.UnimplementedAdminServiceServer
+
+	reloader  *config.Reloader
+	sla       *sla.Recorder
+	registry  Registry
+	hub       *control.Hub
+	retention *retention.Manager
+	authority *ca.Authority
+
+	deprecations *deprecation.Tracker
+	errors       *errorreport.Tracker
+}
+
+// NewServer creates an admin server backed by the given configuration
+// reloader; without one the configuration RPCs fail as unimplemented
+func NewServer(reloader *config.Reloader) *Server {
+	return &Server{
+		reloader: reloader,
+	}
+}
+
+// SetSLARecorder serves SLA reports from rec; without one GetSLAReport fails
+// as unavailable
+func (s *Server) SetSLARecorder(rec *sla.Recorder) {
+	s.sla = rec
+}
+
+// SetRetention reports the stores of m; without a manager GetStorageUsage
+// fails as unavailable
+func (s *Server) SetRetention(m *retention.Manager) {
+	s.retention = m
+}
+
+// SetDeprecations serves deprecation reports from t; without one
+// GetDeprecationReport fails as unavailable
+func (s *Server) SetDeprecations(t *deprecation.Tracker) {
+	s.deprecations = t
+}
+
+// SetErrorReports serves error reports from t; without one GetErrorReport
+// fails as unavailable
+func (s *Server) SetErrorReports(t *errorreport.Tracker) {
+	s.errors = t
+}
+
+// Register registers the admin service on a gRPC server
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	nfa_admin_v1alpha.RegisterAdminServiceServer(registrar, s)
+}
+
+// ReloadConfig reloads configuration from disk. A rejected reload is reported
+// in the response rather than as an RPC error so callers can show the reason.
+func (s *Server) ReloadConfig(ctx context.Context, req *nfa_admin_v1alpha.ReloadConfigRequest) (*nfa_admin_v1alpha.ReloadConfigResponse, error) {
+	if s.reloader == nil {
+		return nil, errNoReloader
+	}
+	entry, err := s.reloader.Reload("admin")
+	if err != nil {
+		return &nfa_admin_v1alpha.ReloadConfigResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+	return &nfa_admin_v1alpha.ReloadConfigResponse{
+		Success:         true,
+		Message:         "configuration reloaded",
+		ChangedSections: entry.Changed,
+	}, nil
+}
+
+// ListConfigChanges returns the configuration reload audit trail
+func (s *Server) ListConfigChanges(ctx context.Context, req *nfa_admin_v1alpha.ListConfigChangesRequest) (*nfa_admin_v1alpha.ListConfigChangesResponse, error) {
+	if s.reloader == nil {
+		return nil, errNoReloader
+	}
+	entries := s.reloader.AuditLog()
+	changes := make([]*nfa_admin_v1alpha.ConfigChange, 0, len(entries))
+	for _, entry := range entries {
+		changes = append(changes, &nfa_admin_v1alpha.ConfigChange{
+			TimestampUnix:   entry.Time.Unix(),
+			Source:          entry.Source,
+			ChangedSections: entry.Changed,
+			Rejected:        entry.Rejected,
+			Error:           entry.Error,
+		})
+	}
+	return &nfa_admin_v1alpha.ListConfigChangesResponse{Changes: changes}, nil
+}
+
+// GetEffectiveConfig returns the redacted effective configuration
+func (s *Server) GetEffectiveConfig(ctx context.Context, req *nfa_admin_v1alpha.GetEffectiveConfigRequest) (*nfa_admin_v1alpha.GetEffectiveConfigResponse, error) {
+	if s.reloader == nil {
+		return nil, errNoReloader
+	}
+	eff := s.reloader.Effective()
+	values := eff.Values()
+	resp := &nfa_admin_v1alpha.GetEffectiveConfigResponse{
+		Profile: string(eff.Config.Profile),
+		Values:  make([]*nfa_admin_v1alpha.ConfigValue, 0, len(values)),
+	}
+	for _, v := range values {
+		resp.Values = append(resp.Values, &nfa_admin_v1alpha.ConfigValue{
+			Key:    v.Key,
+			Value:  v.Value,
+			Source: v.Source,
+		})
+	}
+	return resp, nil
+}
+
+// SetLogLevel changes a component's log level at runtime
+func (s *Server) SetLogLevel(ctx context.Context, req *nfa_admin_v1alpha.SetLogLevelRequest) (*nfa_admin_v1alpha.SetLogLevelResponse, error) {
+	if req.Component == "" {
+		return nil, status.Error(codes.InvalidArgument, "component is required")
+	}
+	if err := logging.SetLevel(req.Component, req.Level); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	logging.Logger(logging.Control).Info("log level changed", "target", req.Component, "level", req.Level)
+	return &nfa_admin_v1alpha.SetLogLevelResponse{Levels: logging.Levels()}, nil
+}
+
+// GetLogLevels returns the current log level of every component
+func (s *Server) GetLogLevels(ctx context.Context, req *nfa_admin_v1alpha.GetLogLevelsRequest) (*nfa_admin_v1alpha.GetLogLevelsResponse, error) {
+	return &nfa_admin_v1alpha.GetLogLevelsResponse{Levels: logging.Levels()}, nil
+}
+
+// GetSLAReport reports availability and latency of providers against their
+// declared QoS
+func (s *Server) GetSLAReport(ctx context.Context, req *nfa_admin_v1alpha.GetSLAReportRequest) (*nfa_admin_v1alpha.SLAReport, error) {
+	if s.sla == nil {
+		return nil, status.Error(codes.Unavailable, "SLA tracking is not enabled")
+	}
+	q := sla.Query{
+		Namespace: req.Namespace,
+		ServiceID: req.ServiceId,
+		Bucket:    time.Duration(req.BucketSecs) * time.Second,
+	}
+	if req.StartUnix != 0 {
+		q.Start = time.Unix(req.StartUnix, 0)
+	}
+	if req.EndUnix != 0 {
+		q.End = time.Unix(req.EndUnix, 0)
+	}
+	if !q.Start.IsZero() && !q.End.IsZero() && !q.Start.Before(q.End) {
+		return nil, status.Error(codes.InvalidArgument, "start must be before end")
+	}
+	return s.sla.Report(q), nil
+}
+
+// GetDeprecationReport lists the consumers still calling deprecated action
+// aliases, with their call rates and when they were last seen
+func (s *Server) GetDeprecationReport(ctx context.Context, req *nfa_admin_v1alpha.GetDeprecationReportRequest) (*nfa_admin_v1alpha.DeprecationReport, error) {
+	if s.deprecations == nil {
+		return nil, status.Error(codes.Unavailable, "deprecation tracking is not enabled")
+	}
+	q := deprecation.Query{Alias: req.Alias}
+	if req.StartUnix != 0 {
+		q.Start = time.Unix(req.StartUnix, 0)
+	}
+	if req.EndUnix != 0 {
+		q.End = time.Unix(req.EndUnix, 0)
+	}
+	if !q.Start.IsZero() && !q.End.IsZero() && !q.Start.Before(q.End) {
+		return nil, status.Error(codes.InvalidArgument, "start must be before end")
+	}
+	return s.deprecations.Report(q), nil
+}
+
+// GetErrorReport lists the errors providers' handlers returned, as their
+// runtimes reported them, most first
+func (s *Server) GetErrorReport(ctx context.Context, req *nfa_admin_v1alpha.GetErrorReportRequest) (*nfa_admin_v1alpha.ErrorReport, error) {
+	if s.errors == nil {
+		return nil, status.Error(codes.Unavailable, "error reports are not enabled")
+	}
+	q := errorreport.Query{ServiceID: req.ServiceId}
+	if req.SinceUnix != 0 {
+		q.Since = time.Unix(req.SinceUnix, 0)
+	}
+	return s.errors.Report(q), nil
+}
+
+// GetStorageUsage reports the data held by each retained store, compacting
+// them first when asked to
+func (s *Server) GetStorageUsage(ctx context.Context, req *nfa_admin_v1alpha.GetStorageUsageRequest) (*nfa_admin_v1alpha.GetStorageUsageResponse, error) {
+	if s.retention == nil {
+		return nil, status.Error(codes.Unavailable, "storage retention is not enabled")
+	}
+	var stores []retention.Status
+	if req.Compact {
+		stores = s.retention.Compact()
+	} else {
+		stores = s.retention.Status()
+	}
+	resp := &nfa_admin_v1alpha.GetStorageUsageResponse{
+		Stores: make([]*nfa_admin_v1alpha.StoreUsage, 0, len(stores)),
+	}
+	for _, st := range stores {
+		usage := &nfa_admin_v1alpha.StoreUsage{
+			Name:             st.Name,
+			Entries:          uint64(st.Usage.Entries),
+			Bytes:            st.Usage.Bytes,
+			MaxAgeSecs:       uint32(st.Policy.MaxAgeSecs),
+			MaxBytes:         st.Policy.MaxBytes,
+			CompactedEntries: st.Removed,
+		}
+		if !st.Usage.Oldest.IsZero() {
+			usage.OldestUnix = st.Usage.Oldest.Unix()
+		}
+		if !st.LastCompaction.IsZero() {
+			usage.LastCompactionUnix = st.LastCompaction.Unix()
+		}
+		resp.Stores = append(resp.Stores, usage)
+	}
+	return resp, nil
+}
@@ -0,0 +1,208 @@
+// Package atrest encrypts the data a broker persists, such as registry
+// contents and event logs, with a separate key per namespace. Keys come from
+// a pluggable KeyProvider: a local keyfile or a key management service. A
+// copy of the broker's disk then exposes no tenant's contract metadata or
+// history without access to the keys.
+package atrest
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// KeySize is the size of the AES-256 keys data is sealed with
+const KeySize = 32
+
+// ErrUnknownKey is returned when data was sealed with a key the provider
+// does not have, e.g. one removed from the keyfile
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// KeyProvider supplies the keys of each namespace
+type KeyProvider interface {
+	// Key returns the key new data of a namespace is sealed with and its ID,
+	// which is stored alongside the sealed data
+	Key(ctx context.Context, namespace string) (id string, key []byte, err error)
+	// KeyByID returns the key with the given ID, to open data sealed with it
+	KeyByID(ctx context.Context, namespace, id string) ([]byte, error)
+}
+
+// KeyFile is a KeyProvider backed by master keys read from a local file.
+// The key of a namespace is derived from a master key and the namespace, so
+// a single file serves every tenant without sharing a key between them.
+type KeyFile struct {
+	current string
+	masters map[string][]byte
+}
+
+// LoadKeyFile reads master keys from path. Each line holds a key ID and a
+// base64-encoded 32-byte key separated by whitespace; blank lines and lines
+// starting with # are ignored. The first key seals new data, the others only
+// open data sealed before a rotation.
+func LoadKeyFile(path string) (*KeyFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open keyfile: %w", err)
+	}
+	defer f.Close()
+
+	kf := &KeyFile{masters: make(map[string][]byte)}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("keyfile line %d: expected a key ID and a key", line)
+		}
+		id := fields[0]
+		key, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("keyfile line %d: invalid key: %w", line, err)
+		}
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("keyfile line %d: key %s has %d bytes, want %d", line, id, len(key), KeySize)
+		}
+		if _, dup := kf.masters[id]; dup {
+			return nil, fmt.Errorf("keyfile line %d: duplicate key ID %s", line, id)
+		}
+		if kf.current == "" {
+			kf.current = id
+		}
+		kf.masters[id] = key
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read keyfile: %w", err)
+	}
+	if kf.current == "" {
+		return nil, fmt.Errorf("keyfile %s holds no keys", path)
+	}
+	return kf, nil
+}
+
+// Key implements KeyProvider
+func (kf *KeyFile) Key(ctx context.Context, namespace string) (string, []byte, error) {
+	return kf.current, derive(kf.masters[kf.current], namespace), nil
+}
+
+// KeyByID implements KeyProvider
+func (kf *KeyFile) KeyByID(ctx context.Context, namespace, id string) ([]byte, error) {
+	master, ok := kf.masters[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, id)
+	}
+	return derive(master, namespace), nil
+}
+
+// derive returns the key of a namespace under a master key
+func derive(master []byte, namespace string) []byte {
+	mac := hmac.New(sha256.New, master)
+	mac.Write([]byte("nfa-atrest/v1\x00" + namespace))
+	return mac.Sum(nil)
+}
+
+// KMSClient is the part of a key management service the KMS provider needs.
+// Adapters for a cloud KMS pass context as its encryption context, so a data
+// key only decrypts for the namespace it was generated for.
+type KMSClient interface {
+	// GenerateDataKey returns a new data key in plaintext and wrapped by the
+	// master key keyName
+	GenerateDataKey(ctx context.Context, keyName string, context map[string]string) (plaintext, wrapped []byte, err error)
+	// Decrypt unwraps a data key returned by GenerateDataKey
+	Decrypt(ctx context.Context, wrapped []byte, context map[string]string) ([]byte, error)
+}
+
+// KMS is a KeyProvider using envelope encryption: each namespace gets a data
+// key generated by the key management service, stored wrapped as the key ID
+// of the data it sealed. Master keys never leave the service, and unwrapped
+// data keys are kept only in memory.
+type KMS struct {
+	client  KMSClient
+	keyName string
+
+	mu      sync.Mutex
+	current map[string]string            // namespace -> wrapped key ID
+	keys    map[string]map[string][]byte // namespace -> wrapped key ID -> data key
+}
+
+// NewKMS creates a provider generating data keys under the master key keyName
+func NewKMS(client KMSClient, keyName string) *KMS {
+	return &KMS{
+		client:  client,
+		keyName: keyName,
+		current: make(map[string]string),
+		keys:    make(map[string]map[string][]byte),
+	}
+}
+
+// Key implements KeyProvider. A namespace's data key is generated on first
+// use and reused until Rotate.
+func (k *KMS) Key(ctx context.Context, namespace string) (string, []byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if id, ok := k.current[namespace]; ok {
+		return id, k.keys[namespace][id], nil
+	}
+	plaintext, wrapped, err := k.client.GenerateDataKey(ctx, k.keyName, kmsContext(namespace))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate data key for %s: %w", namespace, err)
+	}
+	if len(plaintext) != KeySize {
+		return "", nil, fmt.Errorf("data key for %s has %d bytes, want %d", namespace, len(plaintext), KeySize)
+	}
+	id := base64.RawURLEncoding.EncodeToString(wrapped)
+	k.current[namespace] = id
+	k.cache(namespace, id, plaintext)
+	return id, plaintext, nil
+}
+
+// KeyByID implements KeyProvider
+func (k *KMS) KeyByID(ctx context.Context, namespace, id string) ([]byte, error) {
+	k.mu.Lock()
+	key, ok := k.keys[namespace][id]
+	k.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	wrapped, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed data key ID", ErrUnknownKey)
+	}
+	key, err = k.client.Decrypt(ctx, wrapped, kmsContext(namespace))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key for %s: %w", namespace, err)
+	}
+	k.mu.Lock()
+	k.cache(namespace, id, key)
+	k.mu.Unlock()
+	return key, nil
+}
+
+// Rotate makes the next Key call generate a new data key for namespace.
+// Data sealed with earlier keys can still be opened.
+func (k *KMS) Rotate(namespace string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.current, namespace)
+}
+
+func (k *KMS) cache(namespace, id string, key []byte) {
+	if k.keys[namespace] == nil {
+		k.keys[namespace] = make(map[string][]byte)
+	}
+	k.keys[namespace][id] = key
+}
+
+// kmsContext binds a data key to its namespace
+func kmsContext(namespace string) map[string]string {
+	return map[string]string{"nfa.namespace": namespace}
+}
@@ -0,0 +1,112 @@
+package atrest
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// version is the first byte of sealed data
+const version = 1
+
+// ErrCorrupt is returned when sealed data is malformed, was modified or was
+// sealed for another namespace
+var ErrCorrupt = errors.New("sealed data is corrupt")
+
+// Sealer encrypts and authenticates data with the keys of its namespace
+type Sealer struct {
+	keys KeyProvider
+}
+
+// NewSealer creates a sealer using the keys of keys
+func NewSealer(keys KeyProvider) *Sealer {
+	return &Sealer{keys: keys}
+}
+
+// Seal encrypts plaintext with the current key of namespace using AES-256-GCM.
+// The result carries the key ID, so it can be opened after the key rotates,
+// and is bound to namespace: opening it under another namespace fails.
+func (s *Sealer) Seal(ctx context.Context, namespace string, plaintext []byte) ([]byte, error) {
+	id, key, err := s.keys.Key(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(id) > 0xffff {
+		return nil, fmt.Errorf("key ID of %s is too long", namespace)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 3, 3+len(id)+aead.NonceSize())
+	header[0] = version
+	binary.BigEndian.PutUint16(header[1:], uint16(len(id)))
+	header = append(header, id...)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	header = append(header, nonce...)
+	return aead.Seal(header, nonce, plaintext, []byte(namespace)), nil
+}
+
+// Open decrypts data sealed for namespace
+func (s *Sealer) Open(ctx context.Context, namespace string, sealed []byte) ([]byte, error) {
+	id, rest, err := KeyID(sealed)
+	if err != nil {
+		return nil, err
+	}
+	key, err := s.keys.KeyByID(ctx, namespace, id)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < aead.NonceSize() {
+		return nil, fmt.Errorf("%w: truncated", ErrCorrupt)
+	}
+	nonce, ciphertext := rest[:aead.NonceSize()], rest[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(namespace))
+	if err != nil {
+		return nil, fmt.Errorf("%w: authentication failed", ErrCorrupt)
+	}
+	return plaintext, nil
+}
+
+// KeyID returns the ID of the key sealed was sealed with, e.g. to find data
+// to seal again after a rotation, and the data following it
+func KeyID(sealed []byte) (string, []byte, error) {
+	if len(sealed) < 3 || sealed[0] != version {
+		return "", nil, fmt.Errorf("%w: unknown format", ErrCorrupt)
+	}
+	n := int(binary.BigEndian.Uint16(sealed[1:]))
+	if len(sealed) < 3+n {
+		return "", nil, fmt.Errorf("%w: truncated", ErrCorrupt)
+	}
+	return string(sealed[3 : 3+n]), sealed[3+n:], nil
+}
+
+// IsSealed reports whether data is in the format Seal produces, to tell it
+// from data a store wrote before sealing was enabled. JSON documents and
+// protobuf messages never start with the version byte of sealed data.
+func IsSealed(data []byte) bool {
+	return len(data) >= 3 && data[0] == version
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("encryption key has %d bytes, want %d", len(key), KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,217 @@
+package atrest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newKey returns a keyfile line with a random key
+func newKey(t *testing.T, id string) string {
+	t.Helper()
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return id + " " + base64.StdEncoding.EncodeToString(key)
+}
+
+// keyFile loads a keyfile of lines
+func keyFile(t *testing.T, lines ...string) *KeyFile {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	kf, err := LoadKeyFile(path)
+	if err != nil {
+		t.Fatalf("LoadKeyFile() error = %v", err)
+	}
+	return kf
+}
+
+func TestSealOpen(t *testing.T) {
+	ctx := context.Background()
+	s := NewSealer(keyFile(t, "# current key first", newKey(t, "k1")))
+	plaintext := []byte(`{"service_id":"translator-1"}`)
+
+	sealed, err := s.Seal(ctx, "tenant-a", plaintext)
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if bytes.Contains(sealed, plaintext[2:12]) || !IsSealed(sealed) || IsSealed(plaintext) {
+		t.Errorf("Seal() = %q, want it encrypted", sealed)
+	}
+	if id, _, err := KeyID(sealed); err != nil || id != "k1" {
+		t.Errorf("KeyID() = %s, %v, want k1", id, err)
+	}
+	again, _ := s.Seal(ctx, "tenant-a", plaintext)
+	if bytes.Equal(sealed, again) {
+		t.Errorf("Seal() twice = the same data, want a new nonce")
+	}
+	for _, data := range [][]byte{sealed, again} {
+		got, err := s.Open(ctx, "tenant-a", data)
+		if err != nil || !bytes.Equal(got, plaintext) {
+			t.Errorf("Open() = %q, %v, want %q", got, err, plaintext)
+		}
+	}
+	if got, err := s.Open(ctx, "tenant-b", sealed); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Open() in another namespace = %q, %v, want ErrCorrupt", got, err)
+	}
+}
+
+func TestOpenWrongKey(t *testing.T) {
+	ctx := context.Background()
+	sealed, err := NewSealer(keyFile(t, newKey(t, "k1"))).Seal(ctx, "registry", []byte("contract"))
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if _, err := NewSealer(keyFile(t, newKey(t, "k1"))).Open(ctx, "registry", sealed); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Open() with another key of the same ID error = %v, want ErrCorrupt", err)
+	}
+	if _, err := NewSealer(keyFile(t, newKey(t, "k2"))).Open(ctx, "registry", sealed); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Open() without the key error = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestOpenTampered(t *testing.T) {
+	ctx := context.Background()
+	s := NewSealer(keyFile(t, newKey(t, "k1")))
+	sealed, err := s.Seal(ctx, "registry", []byte("contract of translator"))
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	header := 3 + len("k1")
+	tamper := func(change func([]byte) []byte) []byte {
+		return change(bytes.Clone(sealed))
+	}
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"ciphertext", tamper(func(b []byte) []byte { b[header+12] ^= 1; return b }), ErrCorrupt},
+		{"nonce", tamper(func(b []byte) []byte { b[header] ^= 1; return b }), ErrCorrupt},
+		{"tag", tamper(func(b []byte) []byte { b[len(b)-1] ^= 1; return b }), ErrCorrupt},
+		{"truncated", sealed[:len(sealed)-1], ErrCorrupt},
+		{"truncated nonce", sealed[:header+4], ErrCorrupt},
+		{"truncated key ID", sealed[:4], ErrCorrupt},
+		{"version", tamper(func(b []byte) []byte { b[0] = 2; return b }), ErrCorrupt},
+		{"key ID", tamper(func(b []byte) []byte { b[4] = '9'; return b }), ErrUnknownKey},
+		{"appended", append(bytes.Clone(sealed), 0), ErrCorrupt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := s.Open(ctx, "registry", tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("Open() = %q, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyFileRotation(t *testing.T) {
+	ctx := context.Background()
+	k1 := newKey(t, "k1")
+	old, err := NewSealer(keyFile(t, k1)).Seal(ctx, "registry", []byte("before"))
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+
+	// k2 rotates in: it seals new data, k1 still opens the old
+	rotated := NewSealer(keyFile(t, newKey(t, "k2"), k1))
+	sealed, err := rotated.Seal(ctx, "registry", []byte("after"))
+	if err != nil {
+		t.Fatalf("Seal() after the rotation error = %v", err)
+	}
+	if id, _, _ := KeyID(sealed); id != "k2" {
+		t.Errorf("KeyID() after the rotation = %s, want k2", id)
+	}
+	for data, want := range map[*[]byte]string{&old: "before", &sealed: "after"} {
+		if got, err := rotated.Open(ctx, "registry", *data); err != nil || string(got) != want {
+			t.Errorf("Open() after the rotation = %q, %v, want %q", got, err, want)
+		}
+	}
+}
+
+// fakeKMS wraps data keys by remembering them under a counter, bound to
+// the encryption context
+type fakeKMS struct {
+	keys map[string][]byte
+}
+
+func (f *fakeKMS) GenerateDataKey(ctx context.Context, keyName string, context map[string]string) ([]byte, []byte, error) {
+	key := make([]byte, KeySize)
+	rand.Read(key)
+	wrapped := fmt.Sprintf("%s/%s/%d", keyName, context["nfa.namespace"], len(f.keys))
+	f.keys[wrapped] = key
+	return key, []byte(wrapped), nil
+}
+
+func (f *fakeKMS) Decrypt(ctx context.Context, wrapped []byte, context map[string]string) ([]byte, error) {
+	key, ok := f.keys[string(wrapped)]
+	if !ok || !strings.Contains(string(wrapped), "/"+context["nfa.namespace"]+"/") {
+		return nil, errors.New("access denied")
+	}
+	return key, nil
+}
+
+func TestKMSRotation(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeKMS{keys: make(map[string][]byte)}
+	kms := NewKMS(client, "master")
+	s := NewSealer(kms)
+	old, _ := s.Seal(ctx, "registry", []byte("before"))
+	same, _ := s.Seal(ctx, "registry", []byte("before"))
+	kms.Rotate("registry")
+	sealed, err := s.Seal(ctx, "registry", []byte("after"))
+	if err != nil {
+		t.Fatalf("Seal() after Rotate error = %v", err)
+	}
+	oldID, _, _ := KeyID(old)
+	sameID, _, _ := KeyID(same)
+	newID, _, _ := KeyID(sealed)
+	if oldID != sameID || oldID == newID || len(client.keys) != 2 {
+		t.Errorf("key IDs %s, %s then %s after Rotate, want one data key per rotation", oldID, sameID, newID)
+	}
+
+	// A restarted broker unwraps both data keys through the KMS
+	restarted := NewSealer(NewKMS(client, "master"))
+	for data, want := range map[*[]byte]string{&old: "before", &sealed: "after"} {
+		if got, err := restarted.Open(ctx, "registry", *data); err != nil || string(got) != want {
+			t.Errorf("Open() after a restart = %q, %v, want %q", got, err, want)
+		}
+	}
+	if _, err := restarted.Open(ctx, "events", old); err == nil {
+		t.Errorf("Open() in another namespace succeeded, want the KMS to refuse the data key")
+	}
+}
+
+func TestLoadKeyFileErrors(t *testing.T) {
+	short := base64.StdEncoding.EncodeToString(make([]byte, 16))
+	k1 := newKey(t, "k1")
+	tests := []struct {
+		name, data, want string
+	}{
+		{"empty", "# no keys\n", "holds no keys"},
+		{"missing key", "k1\n", "line 1: expected a key ID and a key"},
+		{"not base64", "k1 !!!\n", "line 1: invalid key"},
+		{"short key", "k1 " + short + "\n", "key k1 has 16 bytes, want 32"},
+		{"duplicate", k1 + "\n\n" + k1 + "\n", "line 3: duplicate key ID k1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keys")
+			os.WriteFile(path, []byte(tt.data), 0o600)
+			if _, err := LoadKeyFile(path); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadKeyFile() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+// Package bandit selects providers with a multi-armed bandit: for every
+// context, an action and optionally some consumer labels, it learns which
+// provider earns the best reward from observed latency, success and user
+// feedback, while still exploring the others. The learning state can be
+// exported and restored, e.g. to survive broker restarts.
+package bandit
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/policy"
+)
+
+// Algorithms
+const (
+	// EpsilonGreedy picks a random provider with probability Exploration and
+	// the best one otherwise
+	EpsilonGreedy = "epsilon_greedy"
+	// UCB1 picks the provider with the highest upper confidence bound;
+	// Exploration scales the bonus of rarely chosen providers
+	UCB1 = "ucb1"
+)
+
+// Defaults of optional settings
+const (
+	DefaultEpsilon         = 0.1
+	DefaultUCBExploration  = 1.0
+	DefaultLatencyTargetMs = 200
+)
+
+// Config configures a bandit
+type Config struct {
+	// Algorithm is EpsilonGreedy (the default) or UCB1
+	Algorithm string `toml:"algorithm,omitempty" yaml:"algorithm,omitempty"`
+	// Exploration is the exploration rate; 0 uses DefaultEpsilon or
+	// DefaultUCBExploration
+	Exploration float64 `toml:"exploration,omitempty" yaml:"exploration,omitempty"`
+	// ContextLabels are the consumer labels that, with the action, make up
+	// the context a provider is learned for, e.g. "nfa.region"
+	ContextLabels []string `toml:"context_labels,omitempty" yaml:"context_labels,omitempty"`
+	// Reward weighs the signals an outcome is rewarded for
+	Reward RewardWeights `toml:"reward,omitempty" yaml:"reward,omitempty"`
+}
+
+// RewardWeights weigh the signals a provider is rewarded for; each signal is
+// scored from 0 to 1 and the reward is their weighted mean. All zero weighs
+// success only.
+type RewardWeights struct {
+	Success float64 `toml:"success,omitempty" yaml:"success,omitempty"`
+	Latency float64 `toml:"latency,omitempty" yaml:"latency,omitempty"`
+	// Feedback weighs the user feedback reported with Bandit.Feedback
+	Feedback float64 `toml:"feedback,omitempty" yaml:"feedback,omitempty"`
+	// LatencyTargetMs is the latency scored 0.5; faster scores higher
+	LatencyTargetMs int `toml:"latency_target_ms,omitempty" yaml:"latency_target_ms,omitempty"`
+}
+
+// Validate checks that the configuration can be applied
+func (c *Config) Validate() error {
+	switch c.Algorithm {
+	case "", EpsilonGreedy:
+		if c.Exploration < 0 || c.Exploration > 1 {
+			return fmt.Errorf("exploration %v is not between 0 and 1", c.Exploration)
+		}
+	case UCB1:
+		if c.Exploration < 0 {
+			return fmt.Errorf("exploration must not be negative")
+		}
+	default:
+		return fmt.Errorf("unknown algorithm %q", c.Algorithm)
+	}
+	w := c.Reward
+	if w.Success < 0 || w.Latency < 0 || w.Feedback < 0 || w.LatencyTargetMs < 0 {
+		return fmt.Errorf("reward weights and latency target must not be negative")
+	}
+	return nil
+}
+
+// Outcome is what was observed of one invocation of a provider
+type Outcome struct {
+	Success bool
+	Latency time.Duration
+}
+
+// Bandit learns the best provider of every context
+type Bandit struct {
+	config Config
+
+	mu       sync.Mutex
+	rand     *rand.Rand
+	contexts map[string]map[string]*arm // context -> service ID -> arm
+}
+
+type arm struct {
+	pulls     uint64
+	outcome   float64 // mean reward of the outcomes
+	feedbacks uint64
+	feedback  float64 // mean feedback score
+}
+
+// New creates a bandit that has learned nothing yet. The configuration is
+// expected to be valid.
+func New(config Config) *Bandit {
+	if config.Algorithm == "" {
+		config.Algorithm = EpsilonGreedy
+	}
+	if config.Exploration == 0 {
+		config.Exploration = DefaultEpsilon
+		if config.Algorithm == UCB1 {
+			config.Exploration = DefaultUCBExploration
+		}
+	}
+	if config.Reward.LatencyTargetMs == 0 {
+		config.Reward.LatencyTargetMs = DefaultLatencyTargetMs
+	}
+	if config.Reward.Success == 0 && config.Reward.Latency == 0 && config.Reward.Feedback == 0 {
+		config.Reward.Success = 1
+	}
+	return &Bandit{
+		config:   config,
+		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		contexts: make(map[string]map[string]*arm),
+	}
+}
+
+// Select orders candidates for an intent: the provider chosen for it first,
+// then the others by their learned reward
+func (b *Bandit) Select(input policy.Input, candidates []policy.Candidate) []policy.Candidate {
+	if len(candidates) < 2 {
+		return candidates
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	arms := b.contexts[b.context(input)]
+	var total uint64
+	for _, c := range candidates {
+		if a := arms[c.ServiceID]; a != nil {
+			total += a.pulls
+		}
+	}
+
+	score := func(c policy.Candidate) float64 {
+		a := arms[c.ServiceID]
+		if a == nil || a.pulls == 0 {
+			// Every provider is tried before its reward is trusted
+			return math.Inf(1)
+		}
+		if b.config.Algorithm == UCB1 {
+			return b.reward(a) + b.config.Exploration*math.Sqrt(2*math.Log(float64(total))/float64(a.pulls))
+		}
+		return b.reward(a)
+	}
+	ordered := append([]policy.Candidate(nil), candidates...)
+	sort.SliceStable(ordered, func(i, j int) bool { return score(ordered[i]) > score(ordered[j]) })
+	if b.config.Algorithm == EpsilonGreedy && b.rand.Float64() < b.config.Exploration {
+		i := b.rand.Intn(len(ordered))
+		chosen := ordered[i]
+		copy(ordered[1:i+1], ordered[:i])
+		ordered[0] = chosen
+	}
+	return ordered
+}
+
+// Observe rewards the provider serviceID for the outcome of an intent
+func (b *Bandit) Observe(input policy.Input, serviceID string, outcome Outcome) {
+	w := b.config.Reward
+	var reward float64
+	if weights := w.Success + w.Latency; weights > 0 && outcome.Success {
+		target := float64(time.Duration(w.LatencyTargetMs) * time.Millisecond)
+		reward = (w.Success + w.Latency*target/(target+float64(outcome.Latency))) / weights
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	a := b.arm(input, serviceID)
+	a.pulls++
+	a.outcome += (reward - a.outcome) / float64(a.pulls)
+}
+
+// Feedback rewards the provider serviceID with a user feedback score from 0
+// (bad) to 1 (good) for an intent it served
+func (b *Bandit) Feedback(input policy.Input, serviceID string, score float64) {
+	score = math.Max(0, math.Min(1, score))
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	a := b.arm(input, serviceID)
+	a.feedbacks++
+	a.feedback += (score - a.feedback) / float64(a.feedbacks)
+}
+
+// arm returns the arm of a provider in the context of an intent, creating it
+// if needed; mu must be held
+func (b *Bandit) arm(input policy.Input, serviceID string) *arm {
+	key := b.context(input)
+	arms := b.contexts[key]
+	if arms == nil {
+		arms = make(map[string]*arm)
+		b.contexts[key] = arms
+	}
+	a := arms[serviceID]
+	if a == nil {
+		a = &arm{}
+		arms[serviceID] = a
+	}
+	return a
+}
+
+// reward returns the learned reward of an arm: the weighted mean of its
+// outcome reward and, once reported, its feedback score
+func (b *Bandit) reward(a *arm) float64 {
+	w := b.config.Reward
+	outcome, feedback := w.Success+w.Latency, w.Feedback
+	if a.pulls == 0 {
+		outcome = 0
+	}
+	if a.feedbacks == 0 {
+		feedback = 0
+	}
+	if outcome+feedback == 0 {
+		return 0
+	}
+	return (outcome*a.outcome + feedback*a.feedback) / (outcome + feedback)
+}
+
+// context returns the key of the context of an intent
+func (b *Bandit) context(input policy.Input) string {
+	parts := []string{input.Action}
+	for _, label := range b.config.ContextLabels {
+		parts = append(parts, label+"="+input.Labels[label])
+	}
+	return strings.Join(parts, ",")
+}
+
+// State is the learning state of a bandit
+type State struct {
+	Contexts []ContextState `json:"contexts"`
+}
+
+// ContextState is what was learned for one context
+type ContextState struct {
+	Context string     `json:"context"`
+	Arms    []ArmState `json:"arms"`
+}
+
+// ArmState is what was learned of one provider in a context
+type ArmState struct {
+	ServiceID string `json:"service_id"`
+	// Pulls counts the observed outcomes and OutcomeReward is their mean reward
+	Pulls         uint64  `json:"pulls"`
+	OutcomeReward float64 `json:"outcome_reward"`
+	// Feedbacks counts the feedback reports and FeedbackScore is their mean
+	Feedbacks     uint64  `json:"feedbacks,omitempty"`
+	FeedbackScore float64 `json:"feedback_score,omitempty"`
+	// Reward combines both as used for selection
+	Reward float64 `json:"reward"`
+}
+
+// State exports the learning state, ordered by context and descending reward
+func (b *Bandit) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state := State{Contexts: make([]ContextState, 0, len(b.contexts))}
+	for key, arms := range b.contexts {
+		cs := ContextState{Context: key, Arms: make([]ArmState, 0, len(arms))}
+		for id, a := range arms {
+			cs.Arms = append(cs.Arms, ArmState{
+				ServiceID:     id,
+				Pulls:         a.pulls,
+				OutcomeReward: a.outcome,
+				Feedbacks:     a.feedbacks,
+				FeedbackScore: a.feedback,
+				Reward:        b.reward(a),
+			})
+		}
+		sort.Slice(cs.Arms, func(i, j int) bool {
+			if cs.Arms[i].Reward != cs.Arms[j].Reward {
+				return cs.Arms[i].Reward > cs.Arms[j].Reward
+			}
+			return cs.Arms[i].ServiceID < cs.Arms[j].ServiceID
+		})
+		state.Contexts = append(state.Contexts, cs)
+	}
+	sort.Slice(state.Contexts, func(i, j int) bool { return state.Contexts[i].Context < state.Contexts[j].Context })
+	return state
+}
+
+// Restore replaces the learning state with one exported by State
+func (b *Bandit) Restore(state State) {
+	contexts := make(map[string]map[string]*arm, len(state.Contexts))
+	for _, cs := range state.Contexts {
+		arms := make(map[string]*arm, len(cs.Arms))
+		for _, a := range cs.Arms {
+			arms[a.ServiceID] = &arm{pulls: a.Pulls, outcome: a.OutcomeReward, feedbacks: a.Feedbacks, feedback: a.FeedbackScore}
+		}
+		contexts[cs.Context] = arms
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.contexts = contexts
+}
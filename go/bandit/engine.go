@@ -0,0 +1,34 @@
+package bandit
+
+import (
+	"context"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/policy"
+)
+
+// Engine is a policy.Engine implementing the "bandit" routing strategy. The
+// wrapped engine decides authorization and filters the candidates; the
+// bandit then orders them. Outcomes are reported with Bandit.Observe.
+type Engine struct {
+	engine policy.Engine
+	bandit *Bandit
+}
+
+// NewEngine wraps engine with the provider selection of bandit
+func NewEngine(engine policy.Engine, bandit *Bandit) *Engine {
+	return &Engine{engine: engine, bandit: bandit}
+}
+
+// Authorize implements policy.Engine
+func (e *Engine) Authorize(ctx context.Context, input policy.Input) (policy.Decision, error) {
+	return e.engine.Authorize(ctx, input)
+}
+
+// Route implements policy.Engine
+func (e *Engine) Route(ctx context.Context, input policy.Input, candidates []policy.Candidate) ([]policy.Candidate, error) {
+	routed, err := e.engine.Route(ctx, input, candidates)
+	if err != nil {
+		return nil, err
+	}
+	return e.bandit.Select(input, routed), nil
+}
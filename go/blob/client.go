@@ -0,0 +1,157 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	nfa_blob_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/blob/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxResumeAttempts = 5
+	resumeBackoff     = 500 * time.Millisecond
+)
+
+// Client uploads and downloads blobs
+type Client struct {
+	client nfa_blob_v1alpha.BlobServiceClient
+}
+
+// NewClient creates a blob client on an existing connection to the blob service
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{
+		client: nfa_blob_v1alpha.NewBlobServiceClient(cc),
+	}
+}
+
+// Upload stores the data read from r and returns the blob; pass blob.Ref in
+// the intent instead of the data. A zero ttl selects the server default. When
+// reading r fails the upload is abandoned and the server keeps nothing of it.
+func (c *Client) Upload(ctx context.Context, r io.Reader, contentType string, ttl time.Duration) (*nfa_blob_v1alpha.Blob, error) {
+	// Cancelling rather than closing the stream keeps the server from taking
+	// the data sent so far for the whole blob
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.client.Upload(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start upload: %w", err)
+	}
+	header := &nfa_blob_v1alpha.UploadRequest{
+		Data: &nfa_blob_v1alpha.UploadRequest_Header_{
+			Header: &nfa_blob_v1alpha.UploadRequest_Header{
+				ContentType: contentType,
+				TtlSecs:     uint32(ttl / time.Second),
+			},
+		},
+	}
+	if err := stream.Send(header); err != nil {
+		return nil, fmt.Errorf("failed to upload blob: %w", uploadError(stream, err))
+	}
+
+	buf := make([]byte, ChunkSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			chunk := &nfa_blob_v1alpha.UploadRequest{
+				Data: &nfa_blob_v1alpha.UploadRequest_Chunk{Chunk: buf[:n]},
+			}
+			if err := stream.Send(chunk); err != nil {
+				return nil, fmt.Errorf("failed to upload blob: %w", uploadError(stream, err))
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read blob data: %w", err)
+		}
+	}
+
+	blob, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload blob: %w", err)
+	}
+	return blob, nil
+}
+
+// Download writes the data of a blob, given by ID or reference, to w. An
+// interrupted download is resumed from the last byte received.
+func (c *Client) Download(ctx context.Context, idOrRef string, w io.Writer) error {
+	id := idOrRef
+	if parsed, ok := ParseRef(idOrRef); ok {
+		id = parsed
+	}
+
+	var offset uint64
+	attempts := 0
+	for {
+		err := c.download(ctx, id, &offset, w)
+		if err == nil {
+			return nil
+		}
+		code := status.Code(err)
+		if (code != codes.Unavailable && code != codes.DeadlineExceeded && code != codes.Internal) || attempts >= maxResumeAttempts {
+			return fmt.Errorf("failed to download blob %s: %w", id, err)
+		}
+
+		attempts++
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(resumeBackoff * time.Duration(attempts)):
+		}
+	}
+}
+
+func (c *Client) download(ctx context.Context, id string, offset *uint64, w io.Writer) error {
+	stream, err := c.client.Download(ctx, &nfa_blob_v1alpha.DownloadRequest{Id: id, Offset: *offset})
+	if err != nil {
+		return err
+	}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if chunk.Offset != *offset {
+			return status.Errorf(codes.DataLoss, "received chunk at offset %d, expected %d", chunk.Offset, *offset)
+		}
+		if _, err := w.Write(chunk.Data); err != nil {
+			// Not a transport failure, so it is not retried
+			return status.Errorf(codes.Aborted, "failed to write blob data: %v", err)
+		}
+		*offset += uint64(len(chunk.Data))
+	}
+}
+
+// Delete removes a blob, given by ID or reference, before it expires
+func (c *Client) Delete(ctx context.Context, idOrRef string) error {
+	id := idOrRef
+	if parsed, ok := ParseRef(idOrRef); ok {
+		id = parsed
+	}
+	if _, err := c.client.Delete(ctx, &nfa_blob_v1alpha.DeleteBlobRequest{Id: id}); err != nil {
+		return fmt.Errorf("failed to delete blob %s: %w", id, err)
+	}
+	return nil
+}
+
+// uploadError returns the server's error when a send failed because the
+// server already ended the stream
+func uploadError(stream nfa_blob_v1alpha.BlobService_UploadClient, err error) error {
+	if err != io.EOF {
+		return err
+	}
+	if _, recvErr := stream.CloseAndRecv(); recvErr != nil {
+		return recvErr
+	}
+	return err
+}
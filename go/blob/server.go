@@ -0,0 +1,333 @@
+// Package blob implements out-of-band transfer of large intent payloads.
+// Payloads are uploaded once to the blob service and intents carry only a
+// reference such as "nfa-blob://blob-3f2a...", which the provider resolves by
+// downloading the data in chunks or through a pre-signed HTTP URL.
+package blob
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	nfa_blob_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/blob/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// DefaultTTL is how long a blob is kept when the uploader sets no lifetime
+	DefaultTTL = time.Hour
+	// DefaultMaxSize bounds the size of a single blob
+	DefaultMaxSize = 1 << 30
+
+	// ChunkSize is the size of the chunks sent by Download and the client
+	ChunkSize = 256 << 10
+
+	maxTTL            = 7 * 24 * time.Hour
+	defaultURLExpiry  = 15 * time.Minute
+	maxURLExpiry      = 24 * time.Hour
+	signingKeyLength  = 32
+	blobIDRandomBytes = 16
+)
+
+// Server stores blobs in a directory and serves the BlobService API and
+// pre-signed HTTP URLs
+type Server struct {
+	nfa_blob_v1alpha.UnimplementedBlobServiceServer
+
+	dir     string
+	baseURL string
+	maxSize uint64
+	key     []byte
+
+	mu    sync.Mutex
+	blobs map[string]*entry
+}
+
+type entry struct {
+	blob *nfa_blob_v1alpha.Blob
+	// reserved marks an ID handed out in a PUT URL whose data has not arrived yet
+	reserved    bool
+	contentType string
+}
+
+// NewServer creates a blob server storing data under dir. baseURL is the
+// externally reachable address of the HTTP handler, e.g.
+// "https://broker.example.com:8090"; signed URLs are unavailable when it is
+// empty. A zero maxSize selects DefaultMaxSize.
+func NewServer(dir, baseURL string, maxSize uint64) (*Server, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+	if maxSize == 0 {
+		maxSize = DefaultMaxSize
+	}
+	key := make([]byte, signingKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	return &Server{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		maxSize: maxSize,
+		key:     key,
+		blobs:   make(map[string]*entry),
+	}, nil
+}
+
+// Register registers the blob service on a gRPC server
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	nfa_blob_v1alpha.RegisterBlobServiceServer(registrar, s)
+}
+
+// Upload implements the Upload RPC
+func (s *Server) Upload(stream nfa_blob_v1alpha.BlobService_UploadServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	header := first.GetHeader()
+	if header == nil {
+		return status.Error(codes.InvalidArgument, "first upload message must be a header")
+	}
+
+	blob, err := s.store(newBlobID(), header, &uploadReader{stream: stream})
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(blob)
+}
+
+// Download implements the Download RPC
+func (s *Server) Download(req *nfa_blob_v1alpha.DownloadRequest, stream nfa_blob_v1alpha.BlobService_DownloadServer) error {
+	blob, err := s.lookup(req.Id)
+	if err != nil {
+		return err
+	}
+	if req.Offset > blob.Size {
+		return status.Errorf(codes.OutOfRange, "offset %d is beyond the end of blob %s", req.Offset, req.Id)
+	}
+
+	f, err := os.Open(s.path(blob.Id))
+	if err != nil {
+		return status.Errorf(codes.NotFound, "blob %s not found", req.Id)
+	}
+	defer f.Close()
+	if _, err := f.Seek(int64(req.Offset), io.SeekStart); err != nil {
+		return status.Errorf(codes.Internal, "failed to read blob %s: %v", req.Id, err)
+	}
+
+	buf := make([]byte, ChunkSize)
+	offset := req.Offset
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&nfa_blob_v1alpha.BlobChunk{Offset: offset, Data: buf[:n]}); err != nil {
+				return err
+			}
+			offset += uint64(n)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read blob %s: %v", req.Id, err)
+		}
+	}
+}
+
+// Stat implements the Stat RPC
+func (s *Server) Stat(ctx context.Context, req *nfa_blob_v1alpha.StatBlobRequest) (*nfa_blob_v1alpha.Blob, error) {
+	return s.lookup(req.Id)
+}
+
+// Delete implements the Delete RPC
+func (s *Server) Delete(ctx context.Context, req *nfa_blob_v1alpha.DeleteBlobRequest) (*nfa_blob_v1alpha.DeleteBlobResponse, error) {
+	s.mu.Lock()
+	e, ok := s.blobs[req.Id]
+	if ok {
+		delete(s.blobs, req.Id)
+	}
+	s.mu.Unlock()
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "blob %s not found", req.Id)
+	}
+	if !e.reserved {
+		os.Remove(s.path(req.Id))
+	}
+	return &nfa_blob_v1alpha.DeleteBlobResponse{}, nil
+}
+
+// store writes the data read from src as blob id and records it
+func (s *Server) store(id string, header *nfa_blob_v1alpha.UploadRequest_Header, src io.Reader) (*nfa_blob_v1alpha.Blob, error) {
+	if header.Size > s.maxSize {
+		return nil, status.Errorf(codes.ResourceExhausted, "blob of %d bytes exceeds the limit of %d", header.Size, s.maxSize)
+	}
+	ttl := DefaultTTL
+	if header.TtlSecs > 0 {
+		ttl = time.Duration(header.TtlSecs) * time.Second
+	}
+	if ttl > maxTTL {
+		return nil, status.Errorf(codes.InvalidArgument, "blob lifetime cannot exceed %s", maxTTL)
+	}
+	s.sweep()
+
+	tmp, err := os.CreateTemp(s.dir, "upload-*")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store blob: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	digest := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, digest), &limitReader{r: src, remaining: s.maxSize})
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "failed to store blob: %v", err)
+	}
+	if err := verify(header, uint64(size), digest); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), s.path(id)); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store blob: %v", err)
+	}
+
+	now := time.Now()
+	blob := &nfa_blob_v1alpha.Blob{
+		Id:          id,
+		Ref:         Ref(id),
+		Size:        uint64(size),
+		ContentType: header.ContentType,
+		Sha256:      hex.EncodeToString(digest.Sum(nil)),
+		CreateTime:  timestamppb.New(now),
+		ExpireTime:  timestamppb.New(now.Add(ttl)),
+	}
+	s.mu.Lock()
+	s.blobs[id] = &entry{blob: blob}
+	s.mu.Unlock()
+	return proto.Clone(blob).(*nfa_blob_v1alpha.Blob), nil
+}
+
+// lookup returns a stored, unexpired blob
+func (s *Server) lookup(id string) (*nfa_blob_v1alpha.Blob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.blobs[id]
+	if !ok || e.reserved {
+		return nil, status.Errorf(codes.NotFound, "blob %s not found", id)
+	}
+	if time.Now().After(e.blob.ExpireTime.AsTime()) {
+		delete(s.blobs, id)
+		os.Remove(s.path(id))
+		return nil, status.Errorf(codes.NotFound, "blob %s has expired", id)
+	}
+	return proto.Clone(e.blob).(*nfa_blob_v1alpha.Blob), nil
+}
+
+// sweep deletes expired blobs and reservations
+func (s *Server) sweep() {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, e := range s.blobs {
+		if now.After(e.blob.ExpireTime.AsTime()) {
+			delete(s.blobs, id)
+			if !e.reserved {
+				os.Remove(s.path(id))
+			}
+		}
+	}
+}
+
+func (s *Server) path(id string) string {
+	return filepath.Join(s.dir, id)
+}
+
+func verify(header *nfa_blob_v1alpha.UploadRequest_Header, size uint64, digest hash.Hash) error {
+	if header.Size > 0 && size != header.Size {
+		return status.Errorf(codes.DataLoss, "received %d bytes, expected %d", size, header.Size)
+	}
+	if header.Sha256 != "" && !strings.EqualFold(header.Sha256, hex.EncodeToString(digest.Sum(nil))) {
+		return status.Error(codes.DataLoss, "sha256 of the received data does not match the header")
+	}
+	return nil
+}
+
+// uploadReader reads the data chunks of an Upload stream
+type uploadReader struct {
+	stream nfa_blob_v1alpha.BlobService_UploadServer
+	buf    []byte
+}
+
+func (r *uploadReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		msg, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		chunk, ok := msg.Data.(*nfa_blob_v1alpha.UploadRequest_Chunk)
+		if !ok {
+			return 0, status.Error(codes.InvalidArgument, "only the first upload message may be a header")
+		}
+		r.buf = chunk.Chunk
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// limitReader fails once more than remaining bytes are read
+type limitReader struct {
+	r         io.Reader
+	remaining uint64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	if uint64(n) > l.remaining {
+		return 0, errTooLarge
+	}
+	l.remaining -= uint64(n)
+	return n, err
+}
+
+var errTooLarge = status.Error(codes.ResourceExhausted, "blob exceeds the size limit")
+
+// RefScheme prefixes blob references
+const RefScheme = "nfa-blob://"
+
+// Ref returns the reference passed in intents instead of a blob's data
+func Ref(id string) string {
+	return RefScheme + id
+}
+
+// ParseRef returns the blob ID of a reference created by Ref
+func ParseRef(ref string) (string, bool) {
+	id, ok := strings.CutPrefix(ref, RefScheme)
+	if !ok || id == "" {
+		return "", false
+	}
+	return id, true
+}
+
+func newBlobID() string {
+	b := make([]byte, blobIDRandomBytes)
+	rand.Read(b)
+	return "blob-" + hex.EncodeToString(b)
+}
@@ -0,0 +1,309 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	nfa_blob_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/blob/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// dial serves srv in memory and returns a connection to it
+func dial(t *testing.T, srv nfa_blob_v1alpha.BlobServiceServer, opts ...grpc.ServerOption) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(opts...)
+	nfa_blob_v1alpha.RegisterBlobServiceServer(server, srv)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func randomData(t *testing.T, n int) []byte {
+	t.Helper()
+	data := make([]byte, n)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// recordingServer records the offsets downloads start from
+type recordingServer struct {
+	*Server
+
+	mu      sync.Mutex
+	offsets []uint64
+}
+
+func (r *recordingServer) Download(req *nfa_blob_v1alpha.DownloadRequest, stream nfa_blob_v1alpha.BlobService_DownloadServer) error {
+	r.mu.Lock()
+	r.offsets = append(r.offsets, req.Offset)
+	r.mu.Unlock()
+	return r.Server.Download(req, stream)
+}
+
+// breakingStream fails the sends after the first, like a dropped connection
+type breakingStream struct {
+	grpc.ServerStream
+	sent int
+}
+
+func (b *breakingStream) SendMsg(m interface{}) error {
+	if b.sent++; b.sent > 1 {
+		return status.Error(codes.Unavailable, "connection reset")
+	}
+	return b.ServerStream.SendMsg(m)
+}
+
+func TestDownloadResumes(t *testing.T) {
+	s, err := NewServer(t.TempDir(), "", 0)
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	srv := &recordingServer{Server: s}
+	broken := false
+	breakFirst := grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if strings.HasSuffix(info.FullMethod, "/Download") && !broken {
+			broken = true
+			return handler(srv, &breakingStream{ServerStream: ss})
+		}
+		return handler(srv, ss)
+	})
+	client := NewClient(dial(t, srv, breakFirst))
+	ctx := context.Background()
+
+	data := randomData(t, 2*ChunkSize+1000)
+	blob, err := client.Upload(ctx, bytes.NewReader(data), "application/octet-stream", 0)
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	sum := sha256.Sum256(data)
+	if blob.Size != uint64(len(data)) || blob.Sha256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("Upload() = %v, want %d bytes with their sha256", blob, len(data))
+	}
+
+	var got bytes.Buffer
+	if err := client.Download(ctx, blob.Ref, &got); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if !bytes.Equal(got.Bytes(), data) {
+		t.Errorf("Download() = %d bytes, want the %d uploaded", got.Len(), len(data))
+	}
+	if !slices.Equal(srv.offsets, []uint64{0, ChunkSize}) {
+		t.Errorf("downloads started at %v, want 0 then resumed at %d", srv.offsets, ChunkSize)
+	}
+}
+
+// failingReader returns data, then fails
+type failingReader struct {
+	data []byte
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	if len(f.data) == 0 {
+		return 0, errors.New("disk unplugged")
+	}
+	n := copy(p, f.data)
+	f.data = f.data[n:]
+	return n, nil
+}
+
+func TestUploadAfterPartialUpload(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewServer(dir, "", 0)
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	client := NewClient(dial(t, s))
+	ctx := context.Background()
+	data := randomData(t, 3*ChunkSize)
+
+	if _, err := client.Upload(ctx, &failingReader{data: data[:ChunkSize+10]}, "", 0); err == nil {
+		t.Fatal("Upload() of a failing reader succeeded")
+	}
+	// The server drops the partial data instead of storing it as a blob
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		files, _ := os.ReadDir(dir)
+		s.mu.Lock()
+		blobs := len(s.blobs)
+		s.mu.Unlock()
+		if len(files) == 0 && blobs == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d files and %d blobs after the partial upload, want none", len(files), blobs)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	blob, err := client.Upload(ctx, bytes.NewReader(data), "", 0)
+	if err != nil {
+		t.Fatalf("Upload() again error = %v", err)
+	}
+	var got bytes.Buffer
+	if err := client.Download(ctx, blob.Id, &got); err != nil || !bytes.Equal(got.Bytes(), data) {
+		t.Errorf("Download() = %d bytes, %v, want the %d uploaded again", got.Len(), err, len(data))
+	}
+}
+
+func TestUploadMismatch(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewServer(dir, "", 0)
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	raw := nfa_blob_v1alpha.NewBlobServiceClient(dial(t, s))
+	data := randomData(t, ChunkSize+10)
+	other := sha256.Sum256([]byte("other data"))
+
+	for name, header := range map[string]*nfa_blob_v1alpha.UploadRequest_Header{
+		"hash": {Sha256: hex.EncodeToString(other[:])},
+		"size": {Size: uint64(len(data)) + 1},
+	} {
+		stream, err := raw.Upload(context.Background())
+		if err != nil {
+			t.Fatalf("Upload() error = %v", err)
+		}
+		stream.Send(&nfa_blob_v1alpha.UploadRequest{Data: &nfa_blob_v1alpha.UploadRequest_Header_{Header: header}})
+		for _, chunk := range [][]byte{data[:ChunkSize], data[ChunkSize:]} {
+			stream.Send(&nfa_blob_v1alpha.UploadRequest{Data: &nfa_blob_v1alpha.UploadRequest_Chunk{Chunk: chunk}})
+		}
+		if _, err := stream.CloseAndRecv(); status.Code(err) != codes.DataLoss {
+			t.Errorf("Upload() with a wrong %s error = %v, want DataLoss", name, err)
+		}
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("%d files after the rejected uploads, want none", len(files))
+	}
+}
+
+// shiftedServer sends the chunks of a blob at the wrong offset
+type shiftedServer struct {
+	*Server
+	calls int
+}
+
+func (s *shiftedServer) Download(req *nfa_blob_v1alpha.DownloadRequest, stream nfa_blob_v1alpha.BlobService_DownloadServer) error {
+	s.calls++
+	return stream.Send(&nfa_blob_v1alpha.BlobChunk{Offset: req.Offset + 1, Data: []byte("data")})
+}
+
+func TestDownloadChunkMismatch(t *testing.T) {
+	srv := &shiftedServer{}
+	client := NewClient(dial(t, srv))
+	err := client.Download(context.Background(), "blob-1", io.Discard)
+	if status.Code(errors.Unwrap(err)) != codes.DataLoss || srv.calls != 1 {
+		t.Errorf("Download() of a misplaced chunk error = %v after %d calls, want DataLoss without a retry", err, srv.calls)
+	}
+}
+
+func TestSignedURLs(t *testing.T) {
+	const baseURL = "https://blobs.example.com"
+	s, err := NewServer(t.TempDir(), baseURL, 0)
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	ctx := context.Background()
+	do := func(method, rawURL string, body []byte) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, strings.TrimPrefix(rawURL, baseURL), bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		return w
+	}
+	tamper := func(rawURL, param, value string) string {
+		u, _ := url.Parse(rawURL)
+		q := u.Query()
+		q.Set(param, value)
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+
+	put, err := s.CreateSignedURL(ctx, &nfa_blob_v1alpha.CreateSignedURLRequest{Method: http.MethodPut, ContentType: "text/plain"})
+	if err != nil {
+		t.Fatalf("CreateSignedURL(PUT) error = %v", err)
+	}
+	u, _ := url.Parse(put.Url)
+	signature, expires := u.Query().Get("signature"), u.Query().Get("expires")
+	flipped := []byte(signature)
+	flipped[0] ^= 1
+	later, _ := strconv.ParseInt(expires, 10, 64)
+	for name, rawURL := range map[string]string{
+		"signature": tamper(put.Url, "signature", string(flipped)),
+		"expiry":    tamper(put.Url, "expires", strconv.FormatInt(later+3600, 10)),
+		"no expiry": tamper(put.Url, "expires", ""),
+		"blob":      strings.Replace(put.Url, put.Id, "blob-0000", 1),
+	} {
+		if w := do(http.MethodPut, rawURL, []byte("hello")); w.Code != http.StatusForbidden {
+			t.Errorf("PUT with a tampered %s = %d, want 403", name, w.Code)
+		}
+	}
+	if w := do(http.MethodGet, put.Url, nil); w.Code != http.StatusForbidden {
+		t.Errorf("GET on the PUT URL = %d, want 403", w.Code)
+	}
+	if w := do(http.MethodPut, put.Url, []byte("hello")); w.Code != http.StatusCreated || w.Body.String() != Ref(put.Id) {
+		t.Fatalf("PUT = %d %s, want 201 with the reference", w.Code, w.Body)
+	}
+	if w := do(http.MethodPut, put.Url, []byte("again")); w.Code != http.StatusConflict {
+		t.Errorf("PUT again = %d, want 409", w.Code)
+	}
+
+	get, err := s.CreateSignedURL(ctx, &nfa_blob_v1alpha.CreateSignedURLRequest{Method: http.MethodGet, Id: put.Id})
+	if err != nil {
+		t.Fatalf("CreateSignedURL(GET) error = %v", err)
+	}
+	if w := do(http.MethodGet, get.Url, nil); w.Code != http.StatusOK || w.Body.String() != "hello" || w.Header().Get("Content-Type") != "text/plain" {
+		t.Errorf("GET = %d %q %s, want the uploaded data", w.Code, w.Body, w.Header().Get("Content-Type"))
+	}
+
+	// A URL signed with an expiry that passed
+	past := time.Now().Add(-time.Minute).Unix()
+	expired := baseURL + HTTPPrefix + put.Id + "?" + url.Values{
+		"expires":   {strconv.FormatInt(past, 10)},
+		"signature": {s.sign(http.MethodGet, put.Id, past)},
+	}.Encode()
+	if w := do(http.MethodGet, expired, nil); w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), "expired") {
+		t.Errorf("GET on an expired URL = %d %q, want 403", w.Code, w.Body)
+	}
+
+	// An expired blob is gone, whatever the URL
+	s.mu.Lock()
+	s.blobs[put.Id].blob.ExpireTime = timestamppb.New(time.Now().Add(-time.Second))
+	s.mu.Unlock()
+	if w := do(http.MethodGet, get.Url, nil); w.Code != http.StatusNotFound {
+		t.Errorf("GET of an expired blob = %d, want 404", w.Code)
+	}
+	if _, err := os.Stat(s.path(put.Id)); !os.IsNotExist(err) {
+		t.Errorf("data of the expired blob: %v, want it deleted", err)
+	}
+}
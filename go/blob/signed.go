@@ -0,0 +1,165 @@
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	nfa_blob_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/blob/v1alpha"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// HTTPPrefix is the path under which the HTTP handler serves blobs
+const HTTPPrefix = "/blobs/"
+
+// CreateSignedURL implements the CreateSignedURL RPC
+func (s *Server) CreateSignedURL(ctx context.Context, req *nfa_blob_v1alpha.CreateSignedURLRequest) (*nfa_blob_v1alpha.SignedURL, error) {
+	if s.baseURL == "" {
+		return nil, status.Error(codes.FailedPrecondition, "signed URLs are not enabled on this server")
+	}
+	expiresIn := defaultURLExpiry
+	if req.ExpiresInSecs > 0 {
+		expiresIn = time.Duration(req.ExpiresInSecs) * time.Second
+	}
+	if expiresIn > maxURLExpiry {
+		return nil, status.Errorf(codes.InvalidArgument, "signed URLs cannot be valid for more than %s", maxURLExpiry)
+	}
+	expires := time.Now().Add(expiresIn)
+
+	id := req.Id
+	switch req.Method {
+	case http.MethodGet:
+		if _, err := s.lookup(id); err != nil {
+			return nil, err
+		}
+	case http.MethodPut:
+		id = newBlobID()
+		s.mu.Lock()
+		s.blobs[id] = &entry{
+			blob:        &nfa_blob_v1alpha.Blob{Id: id, ExpireTime: timestamppb.New(expires)},
+			reserved:    true,
+			contentType: req.ContentType,
+		}
+		s.mu.Unlock()
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "method must be GET or PUT, got %q", req.Method)
+	}
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	query.Set("signature", s.sign(req.Method, id, expires.Unix()))
+	return &nfa_blob_v1alpha.SignedURL{
+		Url:        s.baseURL + HTTPPrefix + id + "?" + query.Encode(),
+		Method:     req.Method,
+		Id:         id,
+		ExpireTime: timestamppb.New(expires),
+	}, nil
+}
+
+// ServeHTTP serves GET and PUT requests on pre-signed blob URLs
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, HTTPPrefix)
+	if id == "" || strings.Contains(id, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	if err := s.checkSignature(r.Method, id, r.URL.Query()); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		blob, err := s.lookup(id)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		f, err := os.Open(s.path(id))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		defer f.Close()
+		if blob.ContentType != "" {
+			w.Header().Set("Content-Type", blob.ContentType)
+		}
+		w.Header().Set("ETag", `"`+blob.Sha256+`"`)
+		http.ServeContent(w, r, "", blob.CreateTime.AsTime(), f)
+
+	case http.MethodPut:
+		s.mu.Lock()
+		e, ok := s.blobs[id]
+		if ok && e.reserved {
+			// A URL uploads at most once
+			delete(s.blobs, id)
+		}
+		s.mu.Unlock()
+		if !ok || !e.reserved {
+			http.Error(w, "upload URL was already used", http.StatusConflict)
+			return
+		}
+		contentType := e.contentType
+		if contentType == "" {
+			contentType = r.Header.Get("Content-Type")
+		}
+		header := &nfa_blob_v1alpha.UploadRequest_Header{ContentType: contentType}
+		if r.ContentLength > 0 {
+			header.Size = uint64(r.ContentLength)
+		}
+		blob, err := s.store(id, header, r.Body)
+		if err != nil {
+			http.Error(w, status.Convert(err).Message(), httpStatus(err))
+			return
+		}
+		w.Header().Set("ETag", `"`+blob.Sha256+`"`)
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, blob.Ref)
+
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) checkSignature(method, id string, query url.Values) error {
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("missing or malformed expires parameter")
+	}
+	if time.Now().Unix() > expires {
+		return fmt.Errorf("URL has expired")
+	}
+	if !hmac.Equal([]byte(s.sign(method, id, expires)), []byte(query.Get("signature"))) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+func (s *Server) sign(method, id string, expires int64) string {
+	mac := hmac.New(sha256.New, s.key)
+	fmt.Fprintf(mac, "%s\n%s\n%d", method, id, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func httpStatus(err error) int {
+	switch status.Code(err) {
+	case codes.ResourceExhausted:
+		return http.StatusRequestEntityTooLarge
+	case codes.InvalidArgument, codes.DataLoss:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
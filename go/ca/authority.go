@@ -0,0 +1,492 @@
+// Package ca is the broker's optional built-in certificate authority. It
+// issues short-lived client certificates identifying a runtime's tenant and
+// device, so home and edge fleets get mTLS without an external PKI. A
+// runtime enrolls once with a one-time bootstrap token created by the
+// operator, pinning the CA by its hash, and renews its certificate before it
+// expires over connections authenticated with the current one. A device
+// whose renewals are revoked loses access when its certificate expires, or
+// at once where the broker checks identities with the interceptors.
+package ca
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTTL is the lifetime of issued certificates
+	DefaultTTL = 24 * time.Hour
+	// caValidity is the lifetime of a generated CA certificate
+	caValidity = 10 * 365 * 24 * time.Hour
+	// backdate allows for devices whose clocks run behind the broker's
+	backdate = 5 * time.Minute
+	// uriScheme is the scheme of the URI naming an identity in certificates,
+	// nfa://<tenant>/<device>
+	uriScheme = "nfa"
+)
+
+var (
+	// ErrInvalidToken is returned for bootstrap tokens that are unknown,
+	// expired or already used
+	ErrInvalidToken = errors.New("invalid or expired bootstrap token")
+	// ErrRevoked is returned when renewing the certificate of a revoked device
+	ErrRevoked = errors.New("device revoked")
+)
+
+// validName restricts tenant and device names to what a URI host and path
+// segment hold unescaped
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Identity is who a certificate was issued to
+type Identity struct {
+	Tenant string `json:"tenant"`
+	Device string `json:"device"`
+}
+
+func (id Identity) String() string {
+	return id.Tenant + "/" + id.Device
+}
+
+// validate checks that the names can be put in a certificate
+func (id Identity) validate() error {
+	if !validName.MatchString(id.Tenant) {
+		return fmt.Errorf("invalid tenant %q", id.Tenant)
+	}
+	if !validName.MatchString(id.Device) {
+		return fmt.Errorf("invalid device %q", id.Device)
+	}
+	return nil
+}
+
+// Authority issues and verifies certificates. It is safe for concurrent use.
+type Authority struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+	ttl  time.Duration
+	now  func() time.Time
+	// dir keeps the tokens and revocations, see Open; empty keeps them in
+	// memory
+	dir string
+
+	mu      sync.Mutex
+	tokens  map[string]*bootstrapToken // by ID
+	revoked map[Identity]bool
+	server  map[string]*tls.Certificate // serving certificates by host list
+}
+
+// bootstrapToken is a pending enrollment; only a hash of its secret is kept
+type bootstrapToken struct {
+	secret  [sha256.Size]byte
+	id      Identity // Device is empty when the runtime names itself
+	expires time.Time
+}
+
+// New generates an authority with a new self-signed CA certificate named
+// name. Keep its key with CertificatePEM and KeyPEM, or use Open, so
+// enrolled devices keep working after a broker restart.
+func New(name string) (*Authority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key: %w", err)
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             now.Add(-backdate),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return newAuthority(cert, key), nil
+}
+
+// Load creates an authority from a PEM encoded CA certificate and its
+// private key, in PKCS #8, EC or PKCS #1 form
+func Load(certPEM, keyPEM []byte) (*Authority, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no CA certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+	if !cert.IsCA {
+		return nil, fmt.Errorf("certificate of %s is not a CA certificate", cert.Subject)
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CA key: %w", err)
+	}
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("CA key cannot sign")
+	}
+	return newAuthority(cert, key), nil
+}
+
+// Open loads the authority kept in dir as ca.pem and ca-key.pem, generating
+// and writing one named name the first time. The pending bootstrap tokens
+// and the revocations are kept in dir too, so they survive a restart.
+func Open(dir, name string) (*Authority, error) {
+	certFile, keyFile := filepath.Join(dir, "ca.pem"), filepath.Join(dir, "ca-key.pem")
+	certPEM, err := os.ReadFile(certFile)
+	if err == nil {
+		keyPEM, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA key: %w", err)
+		}
+		a, err := Load(certPEM, keyPEM)
+		if err != nil {
+			return nil, err
+		}
+		a.dir = dir
+		if err := a.loadState(); err != nil {
+			return nil, err
+		}
+		return a, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+	a, err := New(name)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := a.KeyPEM()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create CA directory: %w", err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write CA key: %w", err)
+	}
+	if err := os.WriteFile(certFile, a.CertificatePEM(), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write CA certificate: %w", err)
+	}
+	a.dir = dir
+	return a, nil
+}
+
+func newAuthority(cert *x509.Certificate, key crypto.Signer) *Authority {
+	return &Authority{
+		cert:    cert,
+		key:     key,
+		ttl:     DefaultTTL,
+		now:     time.Now,
+		tokens:  make(map[string]*bootstrapToken),
+		revoked: make(map[Identity]bool),
+		server:  make(map[string]*tls.Certificate),
+	}
+}
+
+// SetTTL changes the lifetime of certificates issued from now on
+func (a *Authority) SetTTL(ttl time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.ttl = ttl
+}
+
+// Certificate returns the CA certificate
+func (a *Authority) Certificate() *x509.Certificate {
+	return a.cert
+}
+
+// CertificatePEM returns the CA certificate, PEM encoded
+func (a *Authority) CertificatePEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: a.cert.Raw})
+}
+
+// KeyPEM returns the CA private key, PEM encoded in PKCS #8 form
+func (a *Authority) KeyPEM() ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(a.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal CA key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// Hash returns the hash runtimes pin the CA with when they enroll,
+// "sha256:" and the hex SHA-256 of the CA's public key info
+func (a *Authority) Hash() string {
+	return Hash(a.cert)
+}
+
+// Hash returns the pinning hash of a CA certificate, see Authority.Hash
+func Hash(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// CreateToken creates a one-time bootstrap token enrolling a device of
+// tenant, valid for ttl. With an empty device the runtime names itself in
+// its certificate request. A new token naming a device lifts its
+// revocation; one with an empty device enrolls no revoked device.
+func (a *Authority) CreateToken(tenant, device string, ttl time.Duration) (string, error) {
+	id := Identity{Tenant: tenant, Device: device}
+	check := id
+	if device == "" {
+		check.Device = "any"
+	}
+	if err := check.validate(); err != nil {
+		return "", err
+	}
+	if ttl <= 0 {
+		return "", fmt.Errorf("token lifetime must be positive")
+	}
+	var raw [24]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	tokenID, secret := hex.EncodeToString(raw[:8]), hex.EncodeToString(raw[8:])
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.expireTokens()
+	a.tokens[tokenID] = &bootstrapToken{
+		secret:  sha256.Sum256([]byte(secret)),
+		id:      id,
+		expires: a.now().Add(ttl),
+	}
+	wasRevoked := a.revoked[id]
+	if device != "" {
+		delete(a.revoked, id)
+	}
+	if err := a.saveState(); err != nil {
+		delete(a.tokens, tokenID)
+		if wasRevoked {
+			a.revoked[id] = true
+		}
+		return "", err
+	}
+	return tokenID + "." + secret, nil
+}
+
+// redeem uses up a bootstrap token, returning the identity it enrolls;
+// requested names the device when the token does not, and must not be
+// revoked
+func (a *Authority) redeem(token, requested string) (Identity, error) {
+	tokenID, secret, ok := strings.Cut(token, ".")
+	if !ok {
+		return Identity{}, ErrInvalidToken
+	}
+	sum := sha256.Sum256([]byte(secret))
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.expireTokens()
+	t, ok := a.tokens[tokenID]
+	if !ok || subtle.ConstantTimeCompare(sum[:], t.secret[:]) != 1 {
+		return Identity{}, ErrInvalidToken
+	}
+	id := t.id
+	if id.Device == "" {
+		id.Device = requested
+	}
+	if err := id.validate(); err != nil {
+		return Identity{}, err
+	}
+	if a.revoked[id] {
+		return Identity{}, fmt.Errorf("%w: %s", ErrRevoked, id)
+	}
+	delete(a.tokens, tokenID)
+	// A token that could not be used up is not handed out again
+	if err := a.saveState(); err != nil {
+		return Identity{}, err
+	}
+	return id, nil
+}
+
+// expireTokens drops expired tokens; mu must be held
+func (a *Authority) expireTokens() {
+	now := a.now()
+	for id, t := range a.tokens {
+		if !now.Before(t.expires) {
+			delete(a.tokens, id)
+		}
+	}
+}
+
+// Revoke refuses to renew the certificates of a device, and its calls where
+// the broker uses the interceptors. Its current certificate stays valid
+// elsewhere until it expires, within the TTL. The revocation applies even
+// when it could not be kept, until the broker restarts.
+func (a *Authority) Revoke(id Identity) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.revoked[id] = true
+	return a.saveState()
+}
+
+// Revoked reports whether a device has been revoked
+func (a *Authority) Revoked(id Identity) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.revoked[id]
+}
+
+// Issue signs a client certificate for the key of csr, identifying id and
+// valid for the authority's TTL
+func (a *Authority) Issue(csr *x509.CertificateRequest, id Identity) (*x509.Certificate, error) {
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("invalid certificate request: %w", err)
+	}
+	if err := id.validate(); err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	ttl := a.ttl
+	a.mu.Unlock()
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, err
+	}
+	now := a.now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: id.Device, Organization: []string{id.Tenant}},
+		URIs:         []*url.URL{{Scheme: uriScheme, Host: id.Tenant, Path: "/" + id.Device}},
+		NotBefore:    now.Add(-backdate),
+		NotAfter:     now.Add(ttl),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, a.cert, csr.PublicKey, a.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue certificate: %w", err)
+	}
+	return x509.ParseCertificate(der)
+}
+
+// IdentityOf returns the identity a certificate issued by an authority
+// names, without verifying it
+func IdentityOf(cert *x509.Certificate) (Identity, bool) {
+	for _, u := range cert.URIs {
+		if u.Scheme == uriScheme {
+			id := Identity{Tenant: u.Host, Device: strings.TrimPrefix(u.Path, "/")}
+			return id, id.validate() == nil
+		}
+	}
+	return Identity{}, false
+}
+
+// ServerTLSConfig returns the TLS configuration of a broker serving hosts,
+// DNS names or IP addresses, with a certificate issued by the authority and
+// renewed before it expires. Client certificates are verified against the
+// authority when presented, so runtimes can still enroll without one; use
+// the interceptors to require them on the other services.
+func (a *Authority) ServerTLSConfig(hosts ...string) (*tls.Config, error) {
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("at least one host is required")
+	}
+	if _, err := a.serverCertificate(hosts); err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(a.cert)
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ClientAuth: tls.VerifyClientCertIfGiven,
+		ClientCAs:  pool,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return a.serverCertificate(hosts)
+		},
+	}, nil
+}
+
+// serverCertificate returns the serving certificate for hosts, issuing a new
+// one when two thirds of the current one's lifetime have passed. The chain
+// includes the CA certificate so enrolling runtimes can pin it.
+func (a *Authority) serverCertificate(hosts []string) (*tls.Certificate, error) {
+	key := strings.Join(hosts, ",")
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	if cert, ok := a.server[key]; ok && now.Before(renewalTime(cert.Leaf)) {
+		return cert, nil
+	}
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate server key: %w", err)
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: hosts[0]},
+		NotBefore:    now.Add(-backdate),
+		NotAfter:     now.Add(a.ttl),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, a.cert, priv.Public(), a.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue server certificate: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	cert := &tls.Certificate{Certificate: [][]byte{der, a.cert.Raw}, PrivateKey: priv, Leaf: leaf}
+	a.server[key] = cert
+	return cert, nil
+}
+
+// renewalTime returns when a certificate should be replaced: once two
+// thirds of its lifetime have passed
+func renewalTime(cert *x509.Certificate) time.Time {
+	return cert.NotBefore.Add(cert.NotAfter.Sub(cert.NotBefore) * 2 / 3)
+}
+
+func serialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	return serial, nil
+}
@@ -0,0 +1,205 @@
+package ca
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// request returns a certificate request of a device named name
+func request(t *testing.T, name string) *x509.CertificateRequest {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: name}}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return csr
+}
+
+func open(t *testing.T, dir string) *Authority {
+	t.Helper()
+	a, err := Open(dir, "test CA")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	return a
+}
+
+func createToken(t *testing.T, a *Authority, tenant, device string) string {
+	t.Helper()
+	token, err := a.CreateToken(tenant, device, time.Hour)
+	if err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+	return token
+}
+
+func TestIssue(t *testing.T) {
+	a, err := New("test CA")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	id := Identity{Tenant: "home", Device: "kitchen-hub"}
+	cert, err := a.Issue(request(t, "ignored"), id)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if got, ok := IdentityOf(cert); !ok || got != id {
+		t.Errorf("IdentityOf() = %v, %t, want %v", got, ok, id)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(a.Certificate())
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if got := cert.NotAfter.Sub(cert.NotBefore); got != DefaultTTL+backdate {
+		t.Errorf("certificate valid for %v, want %v", got, DefaultTTL+backdate)
+	}
+	if _, err := a.Issue(request(t, "x"), Identity{Tenant: "home", Device: "a/b"}); err == nil {
+		t.Errorf("Issue() for an invalid device succeeded")
+	}
+}
+
+func TestRedeem(t *testing.T) {
+	a, err := New("test CA")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return now }
+
+	named := createToken(t, a, "home", "kitchen-hub")
+	if id, err := a.redeem(named, "ignored"); err != nil || id != (Identity{"home", "kitchen-hub"}) {
+		t.Errorf("redeem() = %v, %v, want home/kitchen-hub", id, err)
+	}
+	if _, err := a.redeem(named, "ignored"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("redeem() of a used token error = %v, want ErrInvalidToken", err)
+	}
+
+	wildcard := createToken(t, a, "home", "")
+	if _, err := a.redeem(wildcard, "bad/name"); err == nil {
+		t.Errorf("redeem() naming an invalid device succeeded")
+	}
+	if id, err := a.redeem(wildcard, "porch"); err != nil || id != (Identity{"home", "porch"}) {
+		t.Errorf("redeem() = %v, %v, want the device named by the request", id, err)
+	}
+
+	expired := createToken(t, a, "home", "garage")
+	now = now.Add(time.Hour)
+	if _, err := a.redeem(expired, "ignored"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("redeem() of an expired token error = %v, want ErrInvalidToken", err)
+	}
+	tokenID, _, _ := strings.Cut(createToken(t, a, "home", "garage"), ".")
+	if _, err := a.redeem(tokenID+".wrong", "ignored"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("redeem() with a wrong secret error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestRevocation(t *testing.T) {
+	a, err := New("test CA")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	id := Identity{Tenant: "home", Device: "kitchen-hub"}
+	if err := a.Revoke(id); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+
+	// A token leaving the device to the runtime must not re-enroll it
+	wildcard := createToken(t, a, "home", "")
+	if _, err := a.redeem(wildcard, id.Device); !errors.Is(err, ErrRevoked) {
+		t.Errorf("redeem() of a wildcard token for a revoked device error = %v, want ErrRevoked", err)
+	}
+	if !a.Revoked(id) {
+		t.Errorf("wildcard token lifted the revocation")
+	}
+	if _, err := a.redeem(wildcard, "porch"); err != nil {
+		t.Errorf("redeem() for another device error = %v, want the token still usable", err)
+	}
+
+	// The operator lifts it with a token for the device
+	named := createToken(t, a, id.Tenant, id.Device)
+	if a.Revoked(id) {
+		t.Errorf("token for the device did not lift the revocation")
+	}
+	if _, err := a.redeem(named, ""); err != nil {
+		t.Errorf("redeem() error = %v", err)
+	}
+}
+
+func TestOpenKeepsState(t *testing.T) {
+	dir := t.TempDir()
+	a := open(t, dir)
+	revoked := Identity{Tenant: "home", Device: "garage"}
+	if err := a.Revoke(revoked); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	pending := createToken(t, a, "home", "kitchen-hub")
+	used := createToken(t, a, "home", "porch")
+	if _, err := a.redeem(used, ""); err != nil {
+		t.Fatalf("redeem() error = %v", err)
+	}
+
+	reopened := open(t, dir)
+	if reopened.Hash() != a.Hash() {
+		t.Errorf("Open() = CA %s, want the one kept, %s", reopened.Hash(), a.Hash())
+	}
+	if !reopened.Revoked(revoked) {
+		t.Errorf("revocation lost on reopening")
+	}
+	if _, err := reopened.redeem(used, ""); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("redeem() of a token used before reopening error = %v, want ErrInvalidToken", err)
+	}
+	if id, err := reopened.redeem(pending, ""); err != nil || id.Device != "kitchen-hub" {
+		t.Errorf("redeem() of a token created before reopening = %v, %v, want kitchen-hub", id, err)
+	}
+}
+
+func TestServerCertificateRenewal(t *testing.T) {
+	a, err := New("test CA")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	now := time.Now()
+	a.now = func() time.Time { return now }
+	config, err := a.ServerTLSConfig("broker.example.com", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("ServerTLSConfig() error = %v", err)
+	}
+	first, err := config.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("GetCertificate() error = %v", err)
+	}
+	if err := first.Leaf.VerifyHostname("broker.example.com"); err != nil {
+		t.Errorf("VerifyHostname() error = %v", err)
+	}
+	if err := first.Leaf.VerifyHostname("127.0.0.1"); err != nil {
+		t.Errorf("VerifyHostname() of the IP address error = %v", err)
+	}
+
+	now = now.Add(DefaultTTL / 2)
+	if cert, _ := config.GetCertificate(nil); cert != first {
+		t.Errorf("certificate replaced halfway through its lifetime")
+	}
+	now = now.Add(DefaultTTL / 4)
+	if cert, _ := config.GetCertificate(nil); cert == first {
+		t.Errorf("certificate kept past two thirds of its lifetime")
+	}
+	if _, err := a.ServerTLSConfig(); err == nil {
+		t.Errorf("ServerTLSConfig() without hosts succeeded")
+	}
+}
@@ -0,0 +1,138 @@
+package ca
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"log"
+	"strings"
+
+	nfa_ca_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/ca/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// servicePrefix starts the full method names of the CA service, which
+// runtimes call before they have a certificate
+var servicePrefix = "/" + nfa_ca_v1alpha.CertificateAuthority_ServiceDesc.ServiceName + "/"
+
+// server implements the CertificateAuthority service of an authority
+type server struct {
+	nfa_ca_v1alpha.UnimplementedCertificateAuthorityServer
+	a *Authority
+}
+
+// Register registers the CertificateAuthority service on a gRPC server,
+// which should serve ServerTLSConfig
+func (a *Authority) Register(registrar grpc.ServiceRegistrar) {
+	nfa_ca_v1alpha.RegisterCertificateAuthorityServer(registrar, server{a: a})
+}
+
+// IssueCertificate implements the IssueCertificate RPC: it enrolls a device
+// in exchange for a bootstrap token, or renews the certificate the caller
+// presented
+func (s server) IssueCertificate(ctx context.Context, req *nfa_ca_v1alpha.IssueCertificateRequest) (*nfa_ca_v1alpha.IssueCertificateResponse, error) {
+	csr, err := x509.ParseCertificateRequest(req.Csr)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid certificate request: %v", err)
+	}
+	var id Identity
+	if req.BootstrapToken != "" {
+		id, err = s.a.redeem(req.BootstrapToken, csr.Subject.CommonName)
+		switch {
+		case errors.Is(err, ErrInvalidToken):
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		case errors.Is(err, ErrRevoked):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case errors.Is(err, ErrState):
+			return nil, status.Error(codes.Internal, err.Error())
+		case err != nil:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	} else {
+		var ok bool
+		if id, ok = s.a.PeerIdentity(ctx); !ok {
+			return nil, status.Error(codes.Unauthenticated, "bootstrap token or client certificate required")
+		}
+		if s.a.Revoked(id) {
+			return nil, status.Errorf(codes.PermissionDenied, "%v: %s", ErrRevoked, id)
+		}
+	}
+	cert, err := s.a.Issue(csr, id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.BootstrapToken != "" {
+		log.Printf("Enrolled device %s, certificate valid until %s", id, cert.NotAfter.Format("2006-01-02 15:04:05"))
+	}
+	return &nfa_ca_v1alpha.IssueCertificateResponse{
+		Certificate:   cert.Raw,
+		CaCertificate: s.a.cert.Raw,
+		NotAfterUnix:  cert.NotAfter.Unix(),
+		Tenant:        id.Tenant,
+		Device:        id.Device,
+	}, nil
+}
+
+// PeerIdentity returns the identity of the client certificate the caller of
+// ctx presented, if the authority issued it
+func (a *Authority) PeerIdentity(ctx context.Context) (Identity, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return Identity{}, false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 {
+		return Identity{}, false
+	}
+	leaf := info.State.VerifiedChains[0][0]
+	if leaf.CheckSignatureFrom(a.cert) != nil {
+		return Identity{}, false
+	}
+	return IdentityOf(leaf)
+}
+
+// authorize admits calls to the CA service, and other calls from devices
+// with a certificate of the authority that are not revoked. Calls over the
+// in-process connections of an embedded broker, e.g. those its v1 shim
+// forwards once admitted, are admitted too.
+func (a *Authority) authorize(ctx context.Context, method string) error {
+	if strings.HasPrefix(method, servicePrefix) {
+		return nil
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil && p.Addr.Network() == "bufconn" {
+		return nil
+	}
+	id, ok := a.PeerIdentity(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "client certificate issued by the broker CA required")
+	}
+	if a.Revoked(id) {
+		return status.Errorf(codes.PermissionDenied, "%v: %s", ErrRevoked, id)
+	}
+	return nil
+}
+
+// UnaryInterceptor requires every unary call but those of the CA service to
+// come from a device enrolled with the authority and not revoked
+func (a *Authority) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor is UnaryInterceptor for streaming calls
+func (a *Authority) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authorize(stream.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
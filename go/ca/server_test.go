@@ -0,0 +1,81 @@
+package ca
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"testing"
+
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	nfa_ca_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/ca/v1alpha"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// bufconnAddr is the address of an in-process connection of an embedded broker
+type bufconnAddr struct{}
+
+func (bufconnAddr) Network() string { return "bufconn" }
+func (bufconnAddr) String() string  { return "bufconn" }
+
+// from returns a context of a call from a peer at addr presenting cert
+func from(addr net.Addr, cert *x509.Certificate) context.Context {
+	p := &peer.Peer{Addr: addr}
+	if cert != nil {
+		p.AuthInfo = credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}}
+	}
+	return peer.NewContext(context.Background(), p)
+}
+
+func TestAuthorize(t *testing.T) {
+	a, err := New("test CA")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	other, err := New("other CA")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	id := Identity{Tenant: "home", Device: "kitchen-hub"}
+	cert, err := a.Issue(request(t, ""), id)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	foreign, err := other.Issue(request(t, ""), id)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	tcp := &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 4000}
+	register := nfa_broker_v1alpha.IntentBroker_RegisterIntent_FullMethodName
+	issue := nfa_ca_v1alpha.CertificateAuthority_IssueCertificate_FullMethodName
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		want   codes.Code
+	}{
+		{"enrolled device", from(tcp, cert), register, codes.OK},
+		{"no certificate", from(tcp, nil), register, codes.Unauthenticated},
+		{"certificate of another CA", from(tcp, foreign), register, codes.Unauthenticated},
+		{"enrollment without a certificate", from(tcp, nil), issue, codes.OK},
+		{"in-process connection", from(bufconnAddr{}, nil), register, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(a.authorize(tt.ctx, tt.method)); got != tt.want {
+				t.Errorf("authorize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := a.Revoke(id); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if got := status.Code(a.authorize(from(tcp, cert), register)); got != codes.PermissionDenied {
+		t.Errorf("authorize() of a revoked device = %v, want PermissionDenied", got)
+	}
+}
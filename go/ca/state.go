@@ -0,0 +1,119 @@
+package ca
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// stateFile keeps the pending bootstrap tokens and the revocations of an
+// authority opened from a directory, next to ca.pem
+const stateFile = "state.json"
+
+// ErrState wraps the failures to keep the tokens and revocations of an
+// authority in its directory
+var ErrState = errors.New("failed to keep CA state")
+
+// state is the content of stateFile
+type state struct {
+	Tokens  []storedToken `json:"tokens,omitempty"`
+	Revoked []Identity    `json:"revoked,omitempty"`
+}
+
+// storedToken is a pending bootstrap token, with the hash of its secret
+type storedToken struct {
+	ID      string    `json:"id"`
+	Secret  string    `json:"secret_sha256"`
+	Tenant  string    `json:"tenant"`
+	Device  string    `json:"device,omitempty"`
+	Expires time.Time `json:"expires"`
+}
+
+// loadState reads the tokens and revocations kept in dir, if any
+func (a *Authority) loadState() error {
+	data, err := os.ReadFile(filepath.Join(a.dir, stateFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read CA state: %w", err)
+	}
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fmt.Errorf("failed to parse CA state: %w", err)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, t := range st.Tokens {
+		secret, err := hex.DecodeString(t.Secret)
+		if err != nil || len(secret) != len(bootstrapToken{}.secret) {
+			return fmt.Errorf("failed to parse CA state: invalid secret hash of token %s", t.ID)
+		}
+		token := &bootstrapToken{id: Identity{Tenant: t.Tenant, Device: t.Device}, expires: t.Expires}
+		copy(token.secret[:], secret)
+		a.tokens[t.ID] = token
+	}
+	for _, id := range st.Revoked {
+		a.revoked[id] = true
+	}
+	a.expireTokens()
+	return nil
+}
+
+// saveState writes the tokens and revocations to dir, replacing the previous
+// version atomically; mu must be held
+func (a *Authority) saveState() error {
+	if a.dir == "" {
+		return nil
+	}
+	var st state
+	for id, t := range a.tokens {
+		st.Tokens = append(st.Tokens, storedToken{
+			ID:      id,
+			Secret:  hex.EncodeToString(t.secret[:]),
+			Tenant:  t.id.Tenant,
+			Device:  t.id.Device,
+			Expires: t.expires,
+		})
+	}
+	for id := range a.revoked {
+		st.Revoked = append(st.Revoked, id)
+	}
+	sort.Slice(st.Tokens, func(i, j int) bool { return st.Tokens[i].ID < st.Tokens[j].ID })
+	sort.Slice(st.Revoked, func(i, j int) bool { return st.Revoked[i].String() < st.Revoked[j].String() })
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrState, err)
+	}
+	if err := writeFile(a.dir, stateFile, data); err != nil {
+		return fmt.Errorf("%w: %v", ErrState, err)
+	}
+	return nil
+}
+
+// writeFile writes data to name in dir, replacing the previous version
+// atomically
+func writeFile(dir, name string, data []byte) error {
+	tmp, err := os.CreateTemp(dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
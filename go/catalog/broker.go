@@ -0,0 +1,149 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/admin"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_catalog_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/catalog/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/protobuf/proto"
+)
+
+// errNoBroker fails the calls of a BrokerStore before SetBroker
+var errNoBroker = errors.New("catalog store has no broker")
+
+// BrokerStore is a Store over the registry of an embedded broker. The
+// contracts of a namespace are those of the registrations labelled with it,
+// see admin.LabelNamespace. Importing a catalog registers its contracts as
+// static providers, so they need fixed endpoints, and removes the other
+// registrations of the namespace. Static providers are not persisted: they
+// are lost on restart unless also kept in the static contracts directory.
+// The embedded broker routes without policies and weights, so those are
+// only kept in memory for the next export.
+type BrokerStore struct {
+	// broker is set once, before the catalog service is served
+	broker *broker.Embedded
+
+	// mu serializes imports, which read the registry before changing it
+	mu       sync.Mutex
+	settings *MemoryStore
+}
+
+// NewBrokerStore creates a store over the registry of a broker, set with
+// SetBroker, so the catalog service can be registered on that broker with
+// broker.WithService
+func NewBrokerStore() *BrokerStore {
+	return &BrokerStore{settings: NewMemoryStore()}
+}
+
+// SetBroker sets the broker whose registry holds the contracts
+func (s *BrokerStore) SetBroker(b *broker.Embedded) {
+	s.broker = b
+}
+
+// Load implements Store. A contract registered by several services is listed
+// once, as registered by the first service ID.
+func (s *BrokerStore) Load(ctx context.Context, namespace string) (*nfa_catalog_v1alpha.Catalog, error) {
+	if s.broker == nil {
+		return nil, errNoBroker
+	}
+	catalog, err := s.settings.Load(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	registered := s.registered(namespace)
+	ids := make([]string, 0, len(registered))
+	for id := range registered {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		c := registered[id]
+		if name := c.GetMetadata().GetName(); !seen[name] {
+			seen[name] = true
+			catalog.Contracts = append(catalog.Contracts, c)
+		}
+	}
+	sort.Slice(catalog.Contracts, func(i, j int) bool {
+		return catalog.Contracts[i].GetMetadata().GetName() < catalog.Contracts[j].GetMetadata().GetName()
+	})
+	return catalog, nil
+}
+
+// Replace implements Store. The contracts are labelled with namespace and
+// registered before the registrations they replace are removed; nothing
+// changes when any contract cannot be registered.
+func (s *BrokerStore) Replace(ctx context.Context, namespace string, catalog *nfa_catalog_v1alpha.Catalog) error {
+	if s.broker == nil {
+		return errNoBroker
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current := s.registered(namespace)
+	var contracts []*contract.IntentContract
+	kept := make(map[string]bool)
+	for _, pb := range catalog.GetContracts() {
+		pb = proto.Clone(pb).(*nfa_intent_v1alpha.IntentContract)
+		if pb.Metadata == nil {
+			pb.Metadata = &nfa_intent_v1alpha.Metadata{}
+		}
+		if pb.Metadata.Labels == nil {
+			pb.Metadata.Labels = make(map[string]string)
+		}
+		pb.Metadata.Labels[admin.LabelNamespace] = namespace
+		c, err := contract.FromProto(pb)
+		if err != nil {
+			return fmt.Errorf("contract %s: %w", pb.Metadata.Name, err)
+		}
+		// A registration of the same contract is kept as it is
+		registered := false
+		for id, reg := range current {
+			if proto.Equal(reg, c.ToProto()) {
+				kept[id] = true
+				registered = true
+			}
+		}
+		if !registered {
+			contracts = append(contracts, c)
+		}
+	}
+	ids, err := s.broker.RegisterStatic(contracts...)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		kept[id] = true
+	}
+	for id := range current {
+		if !kept[id] {
+			s.broker.Remove(id)
+		}
+	}
+	settings := &nfa_catalog_v1alpha.Catalog{
+		Namespace:      namespace,
+		Policies:       catalog.GetPolicies(),
+		RoutingWeights: catalog.GetRoutingWeights(),
+	}
+	return s.settings.Replace(ctx, namespace, settings)
+}
+
+// registered returns the contracts registered in namespace, by service ID
+func (s *BrokerStore) registered(namespace string) map[string]*nfa_intent_v1alpha.IntentContract {
+	contracts := make(map[string]*nfa_intent_v1alpha.IntentContract)
+	for _, svc := range s.broker.Services() {
+		if svc.Labels[admin.LabelNamespace] != namespace {
+			continue
+		}
+		if c, ok := s.broker.Contract(svc.ServiceID); ok {
+			contracts[svc.ServiceID] = c
+		}
+	}
+	return contracts
+}
@@ -0,0 +1,215 @@
+package catalog
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/admin"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	nfa_catalog_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/catalog/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func generateKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return public, private
+}
+
+// staticContract is a contract with a fixed endpoint, in namespace unless it
+// is empty
+func staticContract(name, description, namespace string) *nfa_intent_v1alpha.IntentContract {
+	port := 50052
+	c := &contract.IntentContract{
+		Version:  "v1alpha",
+		Kind:     "IntentContract",
+		Metadata: contract.ContractMetadata{Name: name, Description: description},
+		Spec: contract.IntentSpec{
+			IntentPatterns: []contract.IntentPattern{{Pattern: contract.Pattern{Action: name}}},
+			Implementation: contract.Implementation{Endpoint: contract.Endpoint{Type: "grpc", Host: "10.0.0.5", Port: &port}},
+		},
+	}
+	if namespace != "" {
+		c.Metadata.Labels = map[string]string{admin.LabelNamespace: namespace}
+	}
+	return c.ToProto()
+}
+
+func TestSignVerify(t *testing.T) {
+	public, private := generateKey(t)
+	other, _ := generateKey(t)
+	catalog := &nfa_catalog_v1alpha.Catalog{
+		Namespace: "home",
+		Contracts: []*nfa_intent_v1alpha.IntentContract{staticContract("lights", "v1", "")},
+	}
+	bundle, err := Sign(catalog, "staging", private)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	got, err := Verify(bundle, map[string]ed25519.PublicKey{"staging": public})
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if got.Namespace != "home" || len(got.Contracts) != 1 {
+		t.Errorf("Verify() = %v, want the signed catalog", got)
+	}
+
+	tampered := &nfa_catalog_v1alpha.CatalogBundle{
+		Catalog:   append([]byte(nil), bundle.Catalog...),
+		KeyId:     bundle.KeyId,
+		Signature: bundle.Signature,
+	}
+	tampered.Catalog[len(tampered.Catalog)-1] ^= 1
+	for name, tt := range map[string]struct {
+		bundle  *nfa_catalog_v1alpha.CatalogBundle
+		trusted map[string]ed25519.PublicKey
+	}{
+		"tampered":      {tampered, map[string]ed25519.PublicKey{"staging": public}},
+		"untrusted key": {bundle, map[string]ed25519.PublicKey{"prod": public}},
+		"other key":     {bundle, map[string]ed25519.PublicKey{"staging": other}},
+	} {
+		if _, err := Verify(tt.bundle, tt.trusted); err == nil {
+			t.Errorf("%s: Verify() succeeded, want an error", name)
+		}
+	}
+}
+
+func TestImportCatalog(t *testing.T) {
+	public, private := generateKey(t)
+	store := NewMemoryStore()
+	ctx := context.Background()
+	store.Replace(ctx, "home", &nfa_catalog_v1alpha.Catalog{
+		Contracts:      []*nfa_intent_v1alpha.IntentContract{staticContract("lights", "v1", ""), staticContract("heating", "v1", "")},
+		RoutingWeights: map[string]float64{"lights": 1},
+	})
+	s := NewServer(store, "staging", private, map[string]ed25519.PublicKey{"staging": public})
+	bundle, err := Sign(&nfa_catalog_v1alpha.Catalog{
+		Namespace:      "home",
+		Contracts:      []*nfa_intent_v1alpha.IntentContract{staticContract("lights", "v2", ""), staticContract("blinds", "v1", "")},
+		RoutingWeights: map[string]float64{"lights": 2},
+	}, "staging", private)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	resp, err := s.ImportCatalog(ctx, &nfa_catalog_v1alpha.ImportCatalogRequest{Bundle: bundle, DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportCatalog() dry run error = %v", err)
+	}
+	want := []struct {
+		kind nfa_catalog_v1alpha.ChangeKind
+		item string
+	}{
+		{nfa_catalog_v1alpha.ChangeKind_CHANGE_KIND_ADDED, "contract/blinds"},
+		{nfa_catalog_v1alpha.ChangeKind_CHANGE_KIND_REMOVED, "contract/heating"},
+		{nfa_catalog_v1alpha.ChangeKind_CHANGE_KIND_MODIFIED, "contract/lights"},
+		{nfa_catalog_v1alpha.ChangeKind_CHANGE_KIND_MODIFIED, "weight/lights"},
+	}
+	if resp.Applied || len(resp.Changes) != len(want) {
+		t.Fatalf("ImportCatalog() dry run = %v, want %d changes not applied", resp, len(want))
+	}
+	for i, change := range resp.Changes {
+		if change.Kind != want[i].kind || change.Item != want[i].item {
+			t.Errorf("change %d = %v, want %v %s", i, change, want[i].kind, want[i].item)
+		}
+	}
+	if current, _ := store.Load(ctx, "home"); len(current.Contracts) != 2 || current.Contracts[1].Metadata.Name != "heating" {
+		t.Errorf("catalog after a dry run = %v, want it unchanged", current)
+	}
+
+	resp, err = s.ImportCatalog(ctx, &nfa_catalog_v1alpha.ImportCatalogRequest{Bundle: bundle})
+	if err != nil {
+		t.Fatalf("ImportCatalog() error = %v", err)
+	}
+	if !resp.Applied || len(resp.Changes) != len(want) {
+		t.Errorf("ImportCatalog() = %v, want %d changes applied", resp, len(want))
+	}
+	resp, err = s.ImportCatalog(ctx, &nfa_catalog_v1alpha.ImportCatalogRequest{Bundle: bundle, DryRun: true})
+	if err != nil || len(resp.Changes) != 0 {
+		t.Errorf("ImportCatalog() of the applied bundle = %v, %v, want no changes", resp, err)
+	}
+
+	untrusted := NewServer(store, "prod", private, nil)
+	if _, err := untrusted.ImportCatalog(ctx, &nfa_catalog_v1alpha.ImportCatalogRequest{Bundle: bundle}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ImportCatalog() of an untrusted bundle error = %v, want PERMISSION_DENIED", err)
+	}
+}
+
+func TestBrokerStore(t *testing.T) {
+	b := broker.NewEmbedded()
+	defer b.Close()
+	ctx := context.Background()
+	register := func(c *nfa_intent_v1alpha.IntentContract) string {
+		t.Helper()
+		resp, err := b.RegisterIntent(ctx, &nfa_broker_v1alpha.RegisterIntentRequest{Contract: c})
+		if err != nil {
+			t.Fatalf("RegisterIntent() error = %v", err)
+		}
+		return resp.ServiceId
+	}
+	heating := register(staticContract("heating", "v1", "home"))
+	other := register(staticContract("printer", "v1", "office"))
+
+	s := NewBrokerStore()
+	s.SetBroker(b)
+	current, err := s.Load(ctx, "home")
+	if err != nil || len(current.Contracts) != 1 || current.Contracts[0].Metadata.Name != "heating" {
+		t.Fatalf("Load() = %v, %v, want the heating contract", current, err)
+	}
+
+	invalid := &nfa_catalog_v1alpha.Catalog{Contracts: []*nfa_intent_v1alpha.IntentContract{
+		staticContract("lights", "v1", ""),
+		{Metadata: &nfa_intent_v1alpha.Metadata{Name: "blinds"}},
+	}}
+	if err := s.Replace(ctx, "home", invalid); err == nil {
+		t.Errorf("Replace() with an invalid contract succeeded, want an error")
+	}
+	if _, ok := b.Contract(heating); !ok || len(b.Services()) != 2 {
+		t.Errorf("registry after a failed Replace = %v, want it unchanged", b.Services())
+	}
+
+	// Contracts of another namespace are imported into this one
+	imported := &nfa_catalog_v1alpha.Catalog{
+		Contracts:      []*nfa_intent_v1alpha.IntentContract{staticContract("lights", "v1", "staging")},
+		RoutingWeights: map[string]float64{"lights": 2},
+	}
+	if err := s.Replace(ctx, "home", imported); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	if _, ok := b.Contract(heating); ok {
+		t.Errorf("heating still registered, want it removed with the catalog")
+	}
+	if _, ok := b.Contract(other); !ok {
+		t.Errorf("printer of another namespace removed, want it kept")
+	}
+	lights := broker.StableServiceID("lights", broker.StaticInstanceKey)
+	if c, ok := b.Contract(lights); !ok || c.Metadata.Labels[admin.LabelNamespace] != "home" {
+		t.Errorf("Contract(%s) = %v, %v, want lights registered in home", lights, c, ok)
+	}
+	current, err = s.Load(ctx, "home")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(current.Contracts) != 1 || current.RoutingWeights["lights"] != 2 {
+		t.Errorf("Load() = %v, want the imported catalog", current)
+	}
+	if changes := Diff(current, imported); len(changes) != 1 || changes[0].Item != "contract/lights" {
+		t.Errorf("Diff() against the source = %v, want only the namespace label changed", changes)
+	}
+	// Importing the catalog of the broker keeps its registrations
+	if err := s.Replace(ctx, "home", current); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	if again, _ := s.Load(ctx, "home"); len(Diff(current, again)) != 0 {
+		t.Errorf("Replace() with the loaded catalog changed it: %v", Diff(current, again))
+	}
+}
@@ -0,0 +1,71 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	nfa_catalog_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/catalog/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Client exports and imports catalogs on a broker
+type Client struct {
+	client nfa_catalog_v1alpha.CatalogServiceClient
+}
+
+// NewClient creates a catalog client on an existing broker connection
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{
+		client: nfa_catalog_v1alpha.NewCatalogServiceClient(cc),
+	}
+}
+
+// Export returns the signed catalog bundle of a namespace
+func (c *Client) Export(ctx context.Context, namespace string) (*nfa_catalog_v1alpha.CatalogBundle, error) {
+	bundle, err := c.client.ExportCatalog(ctx, &nfa_catalog_v1alpha.ExportCatalogRequest{Namespace: namespace})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export catalog of %s: %w", namespace, err)
+	}
+	return bundle, nil
+}
+
+// Import imports a bundle into namespace, or into the bundle's own namespace
+// when namespace is empty. With dryRun the changes are returned but not applied.
+func (c *Client) Import(ctx context.Context, bundle *nfa_catalog_v1alpha.CatalogBundle, namespace string, dryRun bool) (*nfa_catalog_v1alpha.ImportCatalogResponse, error) {
+	resp, err := c.client.ImportCatalog(ctx, &nfa_catalog_v1alpha.ImportCatalogRequest{
+		Bundle:    bundle,
+		Namespace: namespace,
+		DryRun:    dryRun,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to import catalog: %w", err)
+	}
+	return resp, nil
+}
+
+// WriteBundle saves a bundle as JSON
+func WriteBundle(path string, bundle *nfa_catalog_v1alpha.CatalogBundle) error {
+	data, err := protojson.MarshalOptions{Multiline: true}.Marshal(bundle)
+	if err != nil {
+		return fmt.Errorf("failed to encode bundle: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
+
+// ReadBundle loads a bundle written by WriteBundle
+func ReadBundle(path string) (*nfa_catalog_v1alpha.CatalogBundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	bundle := &nfa_catalog_v1alpha.CatalogBundle{}
+	if err := protojson.Unmarshal(data, bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle %s: %w", path, err)
+	}
+	return bundle, nil
+}
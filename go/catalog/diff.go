@@ -0,0 +1,104 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	nfa_catalog_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/catalog/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/protobuf/proto"
+)
+
+// Diff lists the changes that replacing current with incoming makes, sorted by item
+func Diff(current, incoming *nfa_catalog_v1alpha.Catalog) []*nfa_catalog_v1alpha.CatalogChange {
+	var changes []*nfa_catalog_v1alpha.CatalogChange
+	add := func(kind nfa_catalog_v1alpha.ChangeKind, item, detail string) {
+		changes = append(changes, &nfa_catalog_v1alpha.CatalogChange{Kind: kind, Item: item, Detail: detail})
+	}
+
+	before := contractsByName(current.GetContracts())
+	after := contractsByName(incoming.GetContracts())
+	for name, contract := range after {
+		old, ok := before[name]
+		switch {
+		case !ok:
+			add(nfa_catalog_v1alpha.ChangeKind_CHANGE_KIND_ADDED, "contract/"+name, "version "+contract.Version)
+		case !proto.Equal(old, contract):
+			add(nfa_catalog_v1alpha.ChangeKind_CHANGE_KIND_MODIFIED, "contract/"+name, versionChange(old.Version, contract.Version))
+		}
+	}
+	for name, contract := range before {
+		if _, ok := after[name]; !ok {
+			add(nfa_catalog_v1alpha.ChangeKind_CHANGE_KIND_REMOVED, "contract/"+name, "version "+contract.Version)
+		}
+	}
+
+	if !policiesEqual(current.GetPolicies(), incoming.GetPolicies()) {
+		add(nfa_catalog_v1alpha.ChangeKind_CHANGE_KIND_MODIFIED, "policies",
+			fmt.Sprintf("%d rules -> %d rules: %s", len(current.GetPolicies()), len(incoming.GetPolicies()), describePolicies(incoming.GetPolicies())))
+	}
+
+	for target, weight := range incoming.GetRoutingWeights() {
+		old, ok := current.GetRoutingWeights()[target]
+		switch {
+		case !ok:
+			add(nfa_catalog_v1alpha.ChangeKind_CHANGE_KIND_ADDED, "weight/"+target, fmt.Sprintf("%g", weight))
+		case old != weight:
+			add(nfa_catalog_v1alpha.ChangeKind_CHANGE_KIND_MODIFIED, "weight/"+target, fmt.Sprintf("%g -> %g", old, weight))
+		}
+	}
+	for target, weight := range current.GetRoutingWeights() {
+		if _, ok := incoming.GetRoutingWeights()[target]; !ok {
+			add(nfa_catalog_v1alpha.ChangeKind_CHANGE_KIND_REMOVED, "weight/"+target, fmt.Sprintf("%g", weight))
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Item < changes[j].Item })
+	return changes
+}
+
+func contractsByName(contracts []*nfa_intent_v1alpha.IntentContract) map[string]*nfa_intent_v1alpha.IntentContract {
+	byName := make(map[string]*nfa_intent_v1alpha.IntentContract, len(contracts))
+	for _, contract := range contracts {
+		byName[contract.GetMetadata().GetName()] = contract
+	}
+	return byName
+}
+
+func versionChange(before, after string) string {
+	if before == after {
+		return "version " + after + ", spec changed"
+	}
+	return "version " + before + " -> " + after
+}
+
+func policiesEqual(a, b []*nfa_catalog_v1alpha.PolicyRule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func describePolicies(rules []*nfa_catalog_v1alpha.PolicyRule) string {
+	if len(rules) == 0 {
+		return "none"
+	}
+	parts := make([]string, len(rules))
+	for i, rule := range rules {
+		parts[i] = fmt.Sprintf("%s %s for %s", rule.Effect, listOrAll(rule.Actions), listOrAll(rule.Consumers))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func listOrAll(patterns []string) string {
+	if len(patterns) == 0 {
+		return "*"
+	}
+	return strings.Join(patterns, ",")
+}
@@ -0,0 +1,149 @@
+// Package catalog exports the intent catalog of a namespace (contracts,
+// authorization policies and routing weights) as a signed bundle and imports
+// it into another broker, e.g. to promote a tested catalog from staging to
+// production. Imports are verified against trusted keys and can be previewed
+// as a diff before they are applied.
+package catalog
+
+import (
+	"context"
+	"crypto/ed25519"
+	"sync"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	nfa_catalog_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/catalog/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Store holds the catalogs of a broker. The broker embedding the catalog
+// service provides it on top of its registry, policy and routing state, as
+// BrokerStore does over the registry of an embedded broker.
+type Store interface {
+	// Load returns the catalog of a namespace; an unknown namespace has an empty catalog
+	Load(ctx context.Context, namespace string) (*nfa_catalog_v1alpha.Catalog, error)
+	// Replace makes catalog the complete catalog of a namespace
+	Replace(ctx context.Context, namespace string, catalog *nfa_catalog_v1alpha.Catalog) error
+}
+
+// Server implements the catalog service
+type Server struct {
+	nfa_catalog_v1alpha.UnimplementedCatalogServiceServer
+
+	store   Store
+	keyID   string
+	key     ed25519.PrivateKey
+	trusted map[string]ed25519.PublicKey
+}
+
+// NewServer creates a catalog server. Exports are signed with key under
+// keyID; a nil key disables export. Imports are accepted only when signed by
+// one of the trusted keys, indexed by key ID.
+func NewServer(store Store, keyID string, key ed25519.PrivateKey, trusted map[string]ed25519.PublicKey) *Server {
+	return &Server{
+		store:   store,
+		keyID:   keyID,
+		key:     key,
+		trusted: trusted,
+	}
+}
+
+// Register registers the catalog service on a gRPC server
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	nfa_catalog_v1alpha.RegisterCatalogServiceServer(registrar, s)
+}
+
+// ExportCatalog implements the ExportCatalog RPC
+func (s *Server) ExportCatalog(ctx context.Context, req *nfa_catalog_v1alpha.ExportCatalogRequest) (*nfa_catalog_v1alpha.CatalogBundle, error) {
+	if s.key == nil {
+		return nil, status.Error(codes.FailedPrecondition, "catalog export is disabled: no signing key configured")
+	}
+	if req.Namespace == "" {
+		return nil, status.Error(codes.InvalidArgument, "namespace is required")
+	}
+	catalog, err := s.store.Load(ctx, req.Namespace)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load catalog of %s: %v", req.Namespace, err)
+	}
+	catalog = proto.Clone(catalog).(*nfa_catalog_v1alpha.Catalog)
+	catalog.Namespace = req.Namespace
+	catalog.ExportTime = timestamppb.Now()
+
+	bundle, err := Sign(catalog, s.keyID, s.key)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	logging.Logger(logging.Registry).Info("catalog exported", "namespace", req.Namespace, "contracts", len(catalog.Contracts))
+	return bundle, nil
+}
+
+// ImportCatalog implements the ImportCatalog RPC
+func (s *Server) ImportCatalog(ctx context.Context, req *nfa_catalog_v1alpha.ImportCatalogRequest) (*nfa_catalog_v1alpha.ImportCatalogResponse, error) {
+	if req.Bundle == nil {
+		return nil, status.Error(codes.InvalidArgument, "bundle is required")
+	}
+	incoming, err := Verify(req.Bundle, s.trusted)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	namespace := req.Namespace
+	if namespace == "" {
+		namespace = incoming.Namespace
+	}
+	if namespace == "" {
+		return nil, status.Error(codes.InvalidArgument, "namespace is required")
+	}
+
+	current, err := s.store.Load(ctx, namespace)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load catalog of %s: %v", namespace, err)
+	}
+	resp := &nfa_catalog_v1alpha.ImportCatalogResponse{
+		Changes: Diff(current, incoming),
+		KeyId:   req.Bundle.KeyId,
+	}
+	if req.DryRun {
+		return resp, nil
+	}
+
+	incoming.Namespace = namespace
+	if err := s.store.Replace(ctx, namespace, incoming); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to import catalog into %s: %v", namespace, err)
+	}
+	resp.Applied = true
+	logging.Logger(logging.Registry).Info("catalog imported", "namespace", namespace, "key_id", req.Bundle.KeyId, "changes", len(resp.Changes))
+	return resp, nil
+}
+
+// MemoryStore is an in-memory Store, for tests and brokers without persistent state
+type MemoryStore struct {
+	mu       sync.Mutex
+	catalogs map[string]*nfa_catalog_v1alpha.Catalog
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{catalogs: make(map[string]*nfa_catalog_v1alpha.Catalog)}
+}
+
+// Load implements Store
+func (m *MemoryStore) Load(ctx context.Context, namespace string) (*nfa_catalog_v1alpha.Catalog, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	catalog, ok := m.catalogs[namespace]
+	if !ok {
+		return &nfa_catalog_v1alpha.Catalog{Namespace: namespace}, nil
+	}
+	return proto.Clone(catalog).(*nfa_catalog_v1alpha.Catalog), nil
+}
+
+// Replace implements Store
+func (m *MemoryStore) Replace(ctx context.Context, namespace string, catalog *nfa_catalog_v1alpha.Catalog) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.catalogs[namespace] = proto.Clone(catalog).(*nfa_catalog_v1alpha.Catalog)
+	return nil
+}
@@ -0,0 +1,90 @@
+package catalog
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	nfa_catalog_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/catalog/v1alpha"
+	"google.golang.org/protobuf/proto"
+)
+
+// Sign serializes catalog into a bundle signed with key
+func Sign(catalog *nfa_catalog_v1alpha.Catalog, keyID string, key ed25519.PrivateKey) (*nfa_catalog_v1alpha.CatalogBundle, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(catalog)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize catalog: %w", err)
+	}
+	return &nfa_catalog_v1alpha.CatalogBundle{
+		Catalog:   data,
+		KeyId:     keyID,
+		Signature: ed25519.Sign(key, data),
+	}, nil
+}
+
+// Verify checks the signature of a bundle against the trusted keys and
+// returns the catalog it carries
+func Verify(bundle *nfa_catalog_v1alpha.CatalogBundle, trusted map[string]ed25519.PublicKey) (*nfa_catalog_v1alpha.Catalog, error) {
+	key, ok := trusted[bundle.KeyId]
+	if !ok {
+		return nil, fmt.Errorf("bundle is signed by untrusted key %q", bundle.KeyId)
+	}
+	if !ed25519.Verify(key, bundle.Catalog, bundle.Signature) {
+		return nil, fmt.Errorf("bundle signature does not match key %q", bundle.KeyId)
+	}
+	catalog := &nfa_catalog_v1alpha.Catalog{}
+	if err := proto.Unmarshal(bundle.Catalog, catalog); err != nil {
+		return nil, fmt.Errorf("failed to parse catalog: %w", err)
+	}
+	return catalog, nil
+}
+
+// LoadSigningKey reads a PEM-encoded PKCS #8 Ed25519 private key, as written
+// by `openssl genpkey -algorithm ed25519`
+func LoadSigningKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key %s: %w", path, err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is not an Ed25519 key", path)
+	}
+	return key, nil
+}
+
+// LoadTrustedKey reads a PEM-encoded PKIX Ed25519 public key, as written by
+// `openssl pkey -pubout`
+func LoadTrustedKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse trusted key %s: %w", path, err)
+	}
+	key, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("trusted key %s is not an Ed25519 key", path)
+	}
+	return key, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s does not contain a PEM block", path)
+	}
+	return block, nil
+}
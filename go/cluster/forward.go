@@ -0,0 +1,78 @@
+package cluster
+
+import (
+	"context"
+	"strings"
+
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// forwardedHeader marks a call a node forwarded to the leader, with the ID
+// of that node, so it is not forwarded again
+const forwardedHeader = "nfa-cluster-forwarded-by"
+
+// forwardedMethods are the broker RPCs changing the registry, which the
+// leader handles, with the type of their response
+var forwardedMethods = map[string]func() proto.Message{
+	nfa_broker_v1alpha.IntentBroker_RegisterIntent_FullMethodName: func() proto.Message {
+		return &nfa_broker_v1alpha.RegisterIntentResponse{}
+	},
+	nfa_broker_v1alpha.IntentBroker_Heartbeat_FullMethodName: func() proto.Message {
+		return &nfa_broker_v1alpha.HeartbeatResponse{}
+	},
+	nfa_broker_v1alpha.IntentBroker_UnregisterIntent_FullMethodName: func() proto.Message {
+		return &nfa_broker_v1alpha.UnregisterIntentResponse{}
+	},
+}
+
+// UnaryServerInterceptor forwards the registrations, heartbeats and
+// deregistrations a node receives to the leader, so runtimes can use any
+// node. Install it on the broker with broker.WithServerOptions. Calls fail
+// with UNAVAILABLE while no leader is elected.
+func (n *Node) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		newResponse, ok := forwardedMethods[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+		n.mu.Lock()
+		isLeader, leaderID := n.role == leader, n.leaderID
+		n.mu.Unlock()
+		if isLeader {
+			return handler(ctx, req)
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		if by := md.Get(forwardedHeader); len(by) > 0 {
+			return nil, status.Errorf(codes.Unavailable, "forwarded by node %s to node %s, which is not the cluster leader", by[0], n.cfg.ID)
+		}
+		conn, ok := n.conns[leaderID]
+		if !ok {
+			return nil, status.Error(codes.Unavailable, "no cluster leader is elected")
+		}
+		out := metadata.MD{}
+		for k, v := range md {
+			// Pseudo-headers are set by the transport
+			if !strings.HasPrefix(k, ":") {
+				out[k] = v
+			}
+		}
+		out.Set(forwardedHeader, n.cfg.ID)
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, n.cfg.CommitTimeout)
+			defer cancel()
+		}
+		resp := newResponse()
+		// Waiting for the connection, which may be backing off from before
+		// the leader was up
+		if err := conn.Invoke(metadata.NewOutgoingContext(ctx, out), info.FullMethod, req, resp, grpc.WaitForReady(true)); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+}
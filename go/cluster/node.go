@@ -0,0 +1,878 @@
+// Package cluster replicates the registry of the reference broker across
+// nodes with Raft, so the intent fabric keeps working while a majority of
+// them is up. A Node is the broker.Store of an embedded broker: the leader
+// elected among the nodes appends every registration change to a log it
+// replicates, and a change is stored once a majority has it. Every node
+// applies the committed changes to its broker, so followers match intents
+// from the same registry, and forwards the registrations, heartbeats and
+// deregistrations it receives to the leader. When the leader fails the
+// others elect a new one after the election timeout.
+//
+// Membership is static: every node is configured with the IDs and addresses
+// of all of them. Timers, control streams and outcomes stay local to each
+// node.
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/atrest"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	nfa_cluster_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/cluster/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultElectionTimeout   = time.Second
+	defaultHeartbeatInterval = 100 * time.Millisecond
+	defaultSnapshotThreshold = 1024
+	defaultCommitTimeout     = 5 * time.Second
+	// maxEntriesPerAppend bounds the entries of one AppendEntries call
+	maxEntriesPerAppend = 256
+)
+
+// ErrNotLeader is returned by the writes of a node that is not the leader
+var ErrNotLeader = errors.New("not the cluster leader")
+
+// ErrCommitTimeout is returned by a write the cluster did not commit within
+// the commit timeout, e.g. without a majority of nodes up; it may still be
+// committed later
+var ErrCommitTimeout = errors.New("change not committed in time")
+
+// Config configures a node of a cluster
+type Config struct {
+	// ID names the node among Peers
+	ID string
+	// Peers are the addresses of the broker of every node, this one
+	// included, by node ID
+	Peers map[string]string
+	// Dir keeps the term, vote, log and snapshot of the node. Empty keeps
+	// them in memory, which is only safe in tests.
+	Dir string
+	// ElectionTimeout is how long a follower waits for the leader before
+	// starting an election, randomized up to twice as long; 1s by default
+	ElectionTimeout time.Duration
+	// HeartbeatInterval is how often the leader replicates, 100ms by default
+	HeartbeatInterval time.Duration
+	// SnapshotThreshold is how many committed entries the log keeps before
+	// they are replaced by a snapshot of the registry, 1024 by default
+	SnapshotThreshold int
+	// CommitTimeout bounds how long a write waits to be committed, 5s by
+	// default
+	CommitTimeout time.Duration
+	// DialOptions connect to the other nodes; insecure by default
+	DialOptions []grpc.DialOption
+	// Sealer, if set, encrypts the snapshot and log entries kept in Dir,
+	// which hold the registrations; the term and vote are kept in plaintext
+	Sealer *atrest.Sealer
+}
+
+// Registry is the registry of the broker committed changes are applied to,
+// implemented by *broker.Embedded
+type Registry interface {
+	ApplyStored(rec broker.StoredRegistration)
+	ApplyDeleted(serviceID string)
+}
+
+type role int
+
+const (
+	follower role = iota
+	candidate
+	leader
+)
+
+func (r role) String() string {
+	switch r {
+	case candidate:
+		return "CANDIDATE"
+	case leader:
+		return "LEADER"
+	default:
+		return "FOLLOWER"
+	}
+}
+
+// Node is a member of a cluster of brokers. It implements broker.Store and
+// serves the Raft and cluster services.
+type Node struct {
+	nfa_cluster_v1alpha.UnimplementedRaftServiceServer
+	nfa_cluster_v1alpha.UnimplementedClusterServiceServer
+
+	cfg     Config
+	storage storage
+	conns   map[string]*grpc.ClientConn // to the other nodes, by ID
+	clients map[string]nfa_cluster_v1alpha.RaftServiceClient
+
+	mu       sync.Mutex
+	role     role
+	term     uint64
+	votedFor string
+	leaderID string
+	votes    int
+	// deadline is when a follower or candidate starts an election
+	deadline time.Time
+
+	snapshot    *nfa_cluster_v1alpha.Snapshot
+	log         []*nfa_cluster_v1alpha.LogEntry // after the snapshot
+	commitIndex uint64
+	// records are the registrations as of commitIndex
+	records map[string]*nfa_cluster_v1alpha.Registration
+	// pending are the changes to apply to the registry: the committed ones,
+	// those of this node's broker included, and the committed state of the
+	// registrations whose changes were discarded
+	pending []*nfa_cluster_v1alpha.Command
+
+	// leader state, by node ID
+	nextIndex  map[string]uint64
+	matchIndex map[string]uint64
+	sending    map[string]bool
+
+	// changed is closed and replaced when the commit index or the role
+	// changes
+	changed chan struct{}
+	// wake tells Run to apply the pending changes
+	wake chan struct{}
+}
+
+// NewNode creates a node and loads its state from its directory. Run starts
+// it.
+func NewNode(cfg Config) (*Node, error) {
+	if cfg.ID == "" {
+		return nil, fmt.Errorf("node ID is required")
+	}
+	if _, ok := cfg.Peers[cfg.ID]; !ok {
+		return nil, fmt.Errorf("node %s is not one of the peers", cfg.ID)
+	}
+	if cfg.ElectionTimeout <= 0 {
+		cfg.ElectionTimeout = defaultElectionTimeout
+	}
+	if cfg.HeartbeatInterval <= 0 {
+		cfg.HeartbeatInterval = defaultHeartbeatInterval
+	}
+	if cfg.SnapshotThreshold <= 0 {
+		cfg.SnapshotThreshold = defaultSnapshotThreshold
+	}
+	if cfg.CommitTimeout <= 0 {
+		cfg.CommitTimeout = defaultCommitTimeout
+	}
+	if cfg.DialOptions == nil {
+		cfg.DialOptions = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	n := &Node{
+		cfg:        cfg,
+		storage:    storage{dir: cfg.Dir, sealer: cfg.Sealer},
+		conns:      make(map[string]*grpc.ClientConn),
+		clients:    make(map[string]nfa_cluster_v1alpha.RaftServiceClient),
+		records:    make(map[string]*nfa_cluster_v1alpha.Registration),
+		nextIndex:  make(map[string]uint64),
+		matchIndex: make(map[string]uint64),
+		sending:    make(map[string]bool),
+		changed:    make(chan struct{}),
+		wake:       make(chan struct{}, 1),
+	}
+	state, snapshot, entries, err := n.storage.load()
+	if err != nil {
+		return nil, err
+	}
+	n.term, n.votedFor = state.Term, state.VotedFor
+	n.snapshot, n.log = snapshot, entries
+	n.commitIndex = snapshot.LastIndex
+	for _, reg := range snapshot.Registrations {
+		n.records[reg.ServiceId] = reg
+	}
+	for id, addr := range cfg.Peers {
+		if id == cfg.ID {
+			continue
+		}
+		// Dialing is lazy, so peers need not be up yet
+		conn, err := grpc.Dial(addr, cfg.DialOptions...)
+		if err != nil {
+			n.Close()
+			return nil, fmt.Errorf("failed to connect to node %s: %w", id, err)
+		}
+		n.conns[id] = conn
+		n.clients[id] = nfa_cluster_v1alpha.NewRaftServiceClient(conn)
+	}
+	n.resetDeadline()
+	return n, nil
+}
+
+// Register registers the Raft and cluster services on a gRPC server,
+// typically with broker.WithService
+func (n *Node) Register(registrar grpc.ServiceRegistrar) {
+	nfa_cluster_v1alpha.RegisterRaftServiceServer(registrar, n)
+	nfa_cluster_v1alpha.RegisterClusterServiceServer(registrar, n)
+}
+
+// Run takes part in elections and replication and applies the committed
+// changes to registry until ctx ends
+func (n *Node) Run(ctx context.Context, registry Registry) {
+	// Applying waits for the registry, which may be waiting for a commit,
+	// so it must not hold up the heartbeats
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-n.wake:
+				n.apply(registry)
+			}
+		}
+	}()
+	ticker := time.NewTicker(n.cfg.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n.tick()
+		}
+	}
+}
+
+// Close closes the connections to the other nodes
+func (n *Node) Close() error {
+	for _, conn := range n.conns {
+		conn.Close()
+	}
+	return nil
+}
+
+// Load implements broker.Store, returning the committed registrations
+func (n *Node) Load() ([]broker.StoredRegistration, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	recs := make([]broker.StoredRegistration, 0, len(n.records))
+	for _, reg := range n.records {
+		recs = append(recs, registrationFromProto(reg))
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].ServiceID < recs[j].ServiceID })
+	return recs, nil
+}
+
+// Put implements broker.Store. It returns once a majority of the nodes
+// stored rec; nodes other than the leader fail with ErrNotLeader.
+func (n *Node) Put(rec broker.StoredRegistration) error {
+	return n.propose(&nfa_cluster_v1alpha.Command{
+		Change: &nfa_cluster_v1alpha.Command_Put{Put: registrationToProto(rec)},
+	})
+}
+
+// Delete implements broker.Store, as Put
+func (n *Node) Delete(serviceID string) error {
+	return n.propose(&nfa_cluster_v1alpha.Command{
+		Change: &nfa_cluster_v1alpha.Command_Delete{Delete: serviceID},
+	})
+}
+
+// propose appends a change to the log of the leader and waits until it is
+// committed. The broker may have made the change to its registry already:
+// once committed, it is applied like every other; once discarded, the
+// registration is reverted to its committed state. A change that fails
+// with ErrCommitTimeout is applied or reverted the same way once it is
+// committed or discarded.
+func (n *Node) propose(cmd *nfa_cluster_v1alpha.Command) error {
+	n.mu.Lock()
+	if n.role != leader {
+		defer n.mu.Unlock()
+		n.revert(cmd)
+		return n.notLeader()
+	}
+	entry := &nfa_cluster_v1alpha.LogEntry{Term: n.term, Index: n.lastIndex() + 1, Command: cmd}
+	if err := n.storage.append([]*nfa_cluster_v1alpha.LogEntry{entry}); err != nil {
+		n.revert(cmd)
+		n.mu.Unlock()
+		return fmt.Errorf("failed to append to the log: %w", err)
+	}
+	n.log = append(n.log, entry)
+	n.maybeCommit()
+	n.replicateAll()
+	n.mu.Unlock()
+
+	timeout := time.NewTimer(n.cfg.CommitTimeout)
+	defer timeout.Stop()
+	for {
+		n.mu.Lock()
+		term, ok := n.termAt(entry.Index)
+		if ok && term != entry.Term {
+			// Replaced by the log of another leader
+			defer n.mu.Unlock()
+			return n.notLeader()
+		}
+		if n.commitIndex >= entry.Index {
+			n.mu.Unlock()
+			return nil
+		}
+		changed := n.changed
+		n.mu.Unlock()
+		select {
+		case <-changed:
+		case <-timeout.C:
+			return ErrCommitTimeout
+		}
+	}
+}
+
+// notLeader returns ErrNotLeader with the leader, if known; mu must be held
+func (n *Node) notLeader() error {
+	if n.leaderID == "" {
+		return fmt.Errorf("%w, no leader is elected", ErrNotLeader)
+	}
+	return fmt.Errorf("%w, node %s is", ErrNotLeader, n.leaderID)
+}
+
+// tick replicates to the other nodes on the leader and starts an election
+// on the others once the leader is silent for too long
+func (n *Node) tick() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.role == leader {
+		n.replicateAll()
+	} else if time.Now().After(n.deadline) {
+		n.startElection()
+	}
+}
+
+// startElection makes the node a candidate for the next term; mu must be
+// held
+func (n *Node) startElection() {
+	n.role = candidate
+	n.term++
+	n.votedFor = n.cfg.ID
+	n.leaderID = ""
+	n.resetDeadline()
+	if err := n.saveState(); err != nil {
+		log.Printf("Cluster node %s failed to persist its vote: %v", n.cfg.ID, err)
+		n.role = follower
+		return
+	}
+	n.votes = 1
+	n.notify()
+	if n.quorum(n.votes) {
+		n.becomeLeader()
+		return
+	}
+	req := &nfa_cluster_v1alpha.RequestVoteRequest{
+		Term:         n.term,
+		CandidateId:  n.cfg.ID,
+		LastLogIndex: n.lastIndex(),
+		LastLogTerm:  n.lastTerm(),
+	}
+	for id, client := range n.clients {
+		go n.requestVote(id, client, req)
+	}
+}
+
+func (n *Node) requestVote(id string, client nfa_cluster_v1alpha.RaftServiceClient, req *nfa_cluster_v1alpha.RequestVoteRequest) {
+	ctx, cancel := context.WithTimeout(context.Background(), n.cfg.ElectionTimeout)
+	defer cancel()
+	resp, err := client.RequestVote(ctx, req)
+	if err != nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if resp.Term > n.term {
+		n.becomeFollower(resp.Term)
+		return
+	}
+	if n.role != candidate || n.term != req.Term || !resp.VoteGranted {
+		return
+	}
+	n.votes++
+	if n.quorum(n.votes) {
+		n.becomeLeader()
+	}
+}
+
+// becomeLeader starts leading the current term with an empty entry, which
+// commits the entries of the previous terms; mu must be held
+func (n *Node) becomeLeader() {
+	n.role = leader
+	n.leaderID = n.cfg.ID
+	for id := range n.clients {
+		n.nextIndex[id] = n.lastIndex() + 1
+		n.matchIndex[id] = 0
+	}
+	entry := &nfa_cluster_v1alpha.LogEntry{Term: n.term, Index: n.lastIndex() + 1}
+	if err := n.storage.append([]*nfa_cluster_v1alpha.LogEntry{entry}); err != nil {
+		log.Printf("Cluster node %s failed to append to its log: %v", n.cfg.ID, err)
+		n.becomeFollower(n.term)
+		return
+	}
+	n.log = append(n.log, entry)
+	log.Printf("Cluster node %s is the leader for term %d", n.cfg.ID, n.term)
+	n.notify()
+	n.maybeCommit()
+	n.replicateAll()
+}
+
+// becomeFollower steps down to follower of term, or of the current term
+// when it is not newer; mu must be held
+func (n *Node) becomeFollower(term uint64) {
+	if term > n.term {
+		n.term = term
+		n.votedFor = ""
+		n.leaderID = ""
+		if err := n.saveState(); err != nil {
+			log.Printf("Cluster node %s failed to persist its term: %v", n.cfg.ID, err)
+		}
+	}
+	if n.role != follower {
+		n.role = follower
+		n.resetDeadline()
+		n.notify()
+	}
+}
+
+// replicateAll sends the entries each node lacks, or a heartbeat, to every
+// node not already being sent to; mu must be held
+func (n *Node) replicateAll() {
+	for id := range n.clients {
+		if !n.sending[id] {
+			n.sending[id] = true
+			go n.replicate(id)
+		}
+	}
+}
+
+// replicate sends AppendEntries, or InstallSnapshot to a node lagging
+// behind the snapshot, until the node has every entry or a call fails
+func (n *Node) replicate(id string) {
+	client := n.clients[id]
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	defer func() { n.sending[id] = false }()
+	for n.role == leader {
+		term := n.term
+		next := n.nextIndex[id]
+		if next <= n.snapshot.LastIndex {
+			snapshot := n.snapshot
+			n.mu.Unlock()
+			ctx, cancel := context.WithTimeout(context.Background(), 10*n.cfg.ElectionTimeout)
+			resp, err := client.InstallSnapshot(ctx, &nfa_cluster_v1alpha.InstallSnapshotRequest{
+				Term:     term,
+				LeaderId: n.cfg.ID,
+				Snapshot: snapshot,
+			})
+			cancel()
+			n.mu.Lock()
+			if err != nil {
+				return
+			}
+			if resp.Term > n.term {
+				n.becomeFollower(resp.Term)
+				return
+			}
+			if n.role != leader || n.term != term {
+				return
+			}
+			n.matchIndex[id] = max(n.matchIndex[id], snapshot.LastIndex)
+			n.nextIndex[id] = snapshot.LastIndex + 1
+			continue
+		}
+
+		prev := next - 1
+		prevTerm, _ := n.termAt(prev)
+		// Copied, as the log may be truncated during the call once another
+		// leader is elected
+		entries := n.log[next-n.snapshot.LastIndex-1:]
+		entries = append([]*nfa_cluster_v1alpha.LogEntry(nil), entries[:min(len(entries), maxEntriesPerAppend)]...)
+		req := &nfa_cluster_v1alpha.AppendEntriesRequest{
+			Term:         term,
+			LeaderId:     n.cfg.ID,
+			PrevLogIndex: prev,
+			PrevLogTerm:  prevTerm,
+			Entries:      entries,
+			LeaderCommit: n.commitIndex,
+		}
+		n.mu.Unlock()
+		ctx, cancel := context.WithTimeout(context.Background(), n.cfg.ElectionTimeout)
+		resp, err := client.AppendEntries(ctx, req)
+		cancel()
+		n.mu.Lock()
+		if err != nil {
+			return
+		}
+		if resp.Term > n.term {
+			n.becomeFollower(resp.Term)
+			return
+		}
+		if n.role != leader || n.term != term {
+			return
+		}
+		if !resp.Success {
+			n.nextIndex[id] = max(1, min(resp.LastLogIndex+1, next-1))
+			continue
+		}
+		match := prev + uint64(len(entries))
+		if match > n.matchIndex[id] {
+			n.matchIndex[id] = match
+			n.maybeCommit()
+		}
+		n.nextIndex[id] = match + 1
+		if n.nextIndex[id] > n.lastIndex() {
+			return
+		}
+	}
+}
+
+// maybeCommit commits the last entry of the current term a majority of the
+// nodes has; mu must be held
+func (n *Node) maybeCommit() {
+	for index := n.lastIndex(); index > n.commitIndex; index-- {
+		if term, _ := n.termAt(index); term != n.term {
+			// Entries of previous terms are committed by those of this one
+			return
+		}
+		count := 1 // the leader itself
+		for id := range n.clients {
+			if n.matchIndex[id] >= index {
+				count++
+			}
+		}
+		if n.quorum(count) {
+			n.commitTo(index)
+			return
+		}
+	}
+}
+
+// commitTo commits the entries up to index, applies them to the records and
+// queues them for the registry, whichever node proposed them; mu must be
+// held
+func (n *Node) commitTo(index uint64) {
+	for i := n.commitIndex + 1; i <= index; i++ {
+		entry := n.log[i-n.snapshot.LastIndex-1]
+		if entry.Command == nil {
+			continue
+		}
+		switch c := entry.Command.Change.(type) {
+		case *nfa_cluster_v1alpha.Command_Put:
+			n.records[c.Put.ServiceId] = c.Put
+		case *nfa_cluster_v1alpha.Command_Delete:
+			delete(n.records, c.Delete)
+		}
+		n.pending = append(n.pending, entry.Command)
+	}
+	n.commitIndex = index
+	n.signal()
+	n.notify()
+	n.maybeSnapshot()
+}
+
+// maybeSnapshot replaces the committed entries with a snapshot of the
+// records once there are SnapshotThreshold of them; mu must be held
+func (n *Node) maybeSnapshot() {
+	if n.commitIndex-n.snapshot.LastIndex < uint64(n.cfg.SnapshotThreshold) {
+		return
+	}
+	lastTerm, _ := n.termAt(n.commitIndex)
+	snapshot := &nfa_cluster_v1alpha.Snapshot{LastIndex: n.commitIndex, LastTerm: lastTerm}
+	for _, reg := range n.records {
+		snapshot.Registrations = append(snapshot.Registrations, reg)
+	}
+	sort.Slice(snapshot.Registrations, func(i, j int) bool {
+		return snapshot.Registrations[i].ServiceId < snapshot.Registrations[j].ServiceId
+	})
+	rest := append([]*nfa_cluster_v1alpha.LogEntry(nil), n.log[n.commitIndex-n.snapshot.LastIndex:]...)
+	if err := n.storage.saveSnapshot(snapshot, rest); err != nil {
+		// Retried with the next commit
+		log.Printf("Cluster node %s failed to save a snapshot: %v", n.cfg.ID, err)
+		return
+	}
+	n.snapshot, n.log = snapshot, rest
+}
+
+// revert queues the committed state of the registration a discarded change
+// was about, undoing the change if the broker made it; mu must be held
+func (n *Node) revert(cmd *nfa_cluster_v1alpha.Command) {
+	var serviceID string
+	switch c := cmd.GetChange().(type) {
+	case *nfa_cluster_v1alpha.Command_Put:
+		serviceID = c.Put.ServiceId
+	case *nfa_cluster_v1alpha.Command_Delete:
+		serviceID = c.Delete
+	default:
+		return
+	}
+	if reg, ok := n.records[serviceID]; ok {
+		n.pending = append(n.pending, &nfa_cluster_v1alpha.Command{
+			Change: &nfa_cluster_v1alpha.Command_Put{Put: reg},
+		})
+	} else {
+		n.pending = append(n.pending, &nfa_cluster_v1alpha.Command{
+			Change: &nfa_cluster_v1alpha.Command_Delete{Delete: serviceID},
+		})
+	}
+	n.signal()
+}
+
+// apply applies the pending changes to registry, without holding mu as
+// applying waits for the lock of the registry
+func (n *Node) apply(registry Registry) {
+	n.mu.Lock()
+	pending := n.pending
+	n.pending = nil
+	n.mu.Unlock()
+	for _, cmd := range pending {
+		switch c := cmd.Change.(type) {
+		case *nfa_cluster_v1alpha.Command_Put:
+			registry.ApplyStored(registrationFromProto(c.Put))
+		case *nfa_cluster_v1alpha.Command_Delete:
+			registry.ApplyDeleted(c.Delete)
+		}
+	}
+}
+
+// RequestVote implements the RequestVote RPC
+func (n *Node) RequestVote(ctx context.Context, req *nfa_cluster_v1alpha.RequestVoteRequest) (*nfa_cluster_v1alpha.RequestVoteResponse, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if req.Term > n.term {
+		n.becomeFollower(req.Term)
+	}
+	granted := false
+	upToDate := req.LastLogTerm > n.lastTerm() || (req.LastLogTerm == n.lastTerm() && req.LastLogIndex >= n.lastIndex())
+	if req.Term == n.term && (n.votedFor == "" || n.votedFor == req.CandidateId) && upToDate {
+		n.votedFor = req.CandidateId
+		if err := n.saveState(); err != nil {
+			n.votedFor = ""
+			return nil, status.Errorf(codes.Internal, "failed to persist vote: %v", err)
+		}
+		granted = true
+		n.resetDeadline()
+	}
+	return &nfa_cluster_v1alpha.RequestVoteResponse{Term: n.term, VoteGranted: granted}, nil
+}
+
+// AppendEntries implements the AppendEntries RPC
+func (n *Node) AppendEntries(ctx context.Context, req *nfa_cluster_v1alpha.AppendEntriesRequest) (*nfa_cluster_v1alpha.AppendEntriesResponse, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if req.Term < n.term {
+		return &nfa_cluster_v1alpha.AppendEntriesResponse{Term: n.term}, nil
+	}
+	n.becomeFollower(req.Term)
+	n.leaderID = req.LeaderId
+	n.resetDeadline()
+
+	if req.PrevLogIndex > n.lastIndex() {
+		return &nfa_cluster_v1alpha.AppendEntriesResponse{Term: n.term, LastLogIndex: n.lastIndex()}, nil
+	}
+	match := req.PrevLogIndex + uint64(len(req.Entries))
+	entries := req.Entries
+	if req.PrevLogIndex < n.snapshot.LastIndex {
+		// The entries up to the snapshot are committed, so they match
+		skip := n.snapshot.LastIndex - req.PrevLogIndex
+		entries = entries[min(skip, uint64(len(entries))):]
+		match = max(match, n.snapshot.LastIndex)
+	} else if term, _ := n.termAt(req.PrevLogIndex); term != req.PrevLogTerm {
+		// Skip back over the whole conflicting term
+		index := req.PrevLogIndex
+		for index-1 > n.snapshot.LastIndex {
+			if t, _ := n.termAt(index - 1); t != term {
+				break
+			}
+			index--
+		}
+		return &nfa_cluster_v1alpha.AppendEntriesResponse{Term: n.term, LastLogIndex: index - 1}, nil
+	}
+
+	truncated := false
+	for i, entry := range entries {
+		if entry.Index <= n.lastIndex() {
+			if term, _ := n.termAt(entry.Index); term == entry.Term {
+				continue
+			}
+			// Uncommitted, so discarded, e.g. proposed by this node while
+			// it led without a majority
+			for _, discarded := range n.log[entry.Index-n.snapshot.LastIndex-1:] {
+				n.revert(discarded.Command)
+			}
+			n.log = n.log[:entry.Index-n.snapshot.LastIndex-1]
+			truncated = true
+		}
+		entries = entries[i:]
+		var err error
+		if truncated {
+			err = n.storage.rewrite(append(append([]*nfa_cluster_v1alpha.LogEntry(nil), n.log...), entries...))
+		} else {
+			err = n.storage.append(entries)
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to append to the log: %v", err)
+		}
+		n.log = append(n.log, entries...)
+		break
+	}
+	if req.LeaderCommit > n.commitIndex {
+		if commit := min(req.LeaderCommit, match); commit > n.commitIndex {
+			n.commitTo(commit)
+		}
+	}
+	return &nfa_cluster_v1alpha.AppendEntriesResponse{Term: n.term, Success: true, LastLogIndex: match}, nil
+}
+
+// InstallSnapshot implements the InstallSnapshot RPC
+func (n *Node) InstallSnapshot(ctx context.Context, req *nfa_cluster_v1alpha.InstallSnapshotRequest) (*nfa_cluster_v1alpha.InstallSnapshotResponse, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if req.Term < n.term {
+		return &nfa_cluster_v1alpha.InstallSnapshotResponse{Term: n.term}, nil
+	}
+	n.becomeFollower(req.Term)
+	n.leaderID = req.LeaderId
+	n.resetDeadline()
+	snapshot := req.GetSnapshot()
+	if snapshot.GetLastIndex() <= n.commitIndex {
+		return &nfa_cluster_v1alpha.InstallSnapshotResponse{Term: n.term}, nil
+	}
+
+	// Entries after the snapshot are kept if the log agrees with it
+	var rest []*nfa_cluster_v1alpha.LogEntry
+	if term, ok := n.termAt(snapshot.LastIndex); ok && term == snapshot.LastTerm {
+		rest = append(rest, n.log[snapshot.LastIndex-n.snapshot.LastIndex:]...)
+	}
+	if err := n.storage.saveSnapshot(snapshot, rest); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save snapshot: %v", err)
+	}
+	records := make(map[string]*nfa_cluster_v1alpha.Registration, len(snapshot.Registrations))
+	for _, reg := range snapshot.Registrations {
+		records[reg.ServiceId] = reg
+		n.pending = append(n.pending, &nfa_cluster_v1alpha.Command{
+			Change: &nfa_cluster_v1alpha.Command_Put{Put: reg},
+		})
+	}
+	for id := range n.records {
+		if _, ok := records[id]; !ok {
+			n.pending = append(n.pending, &nfa_cluster_v1alpha.Command{
+				Change: &nfa_cluster_v1alpha.Command_Delete{Delete: id},
+			})
+		}
+	}
+	n.snapshot, n.log, n.records = snapshot, rest, records
+	n.commitIndex = snapshot.LastIndex
+	n.signal()
+	n.notify()
+	return &nfa_cluster_v1alpha.InstallSnapshotResponse{Term: n.term}, nil
+}
+
+// GetStatus implements the GetStatus RPC
+func (n *Node) GetStatus(ctx context.Context, req *nfa_cluster_v1alpha.GetStatusRequest) (*nfa_cluster_v1alpha.ClusterStatus, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	st := &nfa_cluster_v1alpha.ClusterStatus{
+		NodeId:        n.cfg.ID,
+		LeaderId:      n.leaderID,
+		Term:          n.term,
+		Role:          n.role.String(),
+		LastLogIndex:  n.lastIndex(),
+		CommitIndex:   n.commitIndex,
+		SnapshotIndex: n.snapshot.LastIndex,
+	}
+	for id, addr := range n.cfg.Peers {
+		m := &nfa_cluster_v1alpha.Member{Id: id, Address: addr, Self: id == n.cfg.ID}
+		if n.role == leader {
+			m.MatchIndex = n.matchIndex[id]
+			if m.Self {
+				m.MatchIndex = n.lastIndex()
+			}
+		}
+		st.Members = append(st.Members, m)
+	}
+	sort.Slice(st.Members, func(i, j int) bool { return st.Members[i].Id < st.Members[j].Id })
+	return st, nil
+}
+
+// lastIndex is the index of the last entry of the log; mu must be held
+func (n *Node) lastIndex() uint64 {
+	if len(n.log) > 0 {
+		return n.log[len(n.log)-1].Index
+	}
+	return n.snapshot.LastIndex
+}
+
+// lastTerm is the term of the last entry of the log; mu must be held
+func (n *Node) lastTerm() uint64 {
+	if len(n.log) > 0 {
+		return n.log[len(n.log)-1].Term
+	}
+	return n.snapshot.LastTerm
+}
+
+// termAt returns the term of the entry at index, if the log or the snapshot
+// still has it; mu must be held
+func (n *Node) termAt(index uint64) (uint64, bool) {
+	switch {
+	case index == n.snapshot.LastIndex:
+		return n.snapshot.LastTerm, true
+	case index < n.snapshot.LastIndex || index > n.lastIndex():
+		return 0, false
+	default:
+		return n.log[index-n.snapshot.LastIndex-1].Term, true
+	}
+}
+
+// quorum reports whether votes are a majority of the nodes
+func (n *Node) quorum(votes int) bool {
+	return votes*2 > len(n.cfg.Peers)
+}
+
+func (n *Node) saveState() error {
+	return n.storage.saveState(&nfa_cluster_v1alpha.PersistentState{Term: n.term, VotedFor: n.votedFor})
+}
+
+// resetDeadline sets the next election after a random timeout, so the
+// nodes rarely start one at the same time; mu must be held
+func (n *Node) resetDeadline() {
+	timeout := n.cfg.ElectionTimeout + time.Duration(rand.Int63n(int64(n.cfg.ElectionTimeout)))
+	n.deadline = time.Now().Add(timeout)
+}
+
+// notify wakes the writes waiting for a commit; mu must be held
+func (n *Node) notify() {
+	close(n.changed)
+	n.changed = make(chan struct{})
+}
+
+// signal wakes Run to apply the pending changes
+func (n *Node) signal() {
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+}
+
+func registrationToProto(rec broker.StoredRegistration) *nfa_cluster_v1alpha.Registration {
+	return &nfa_cluster_v1alpha.Registration{
+		ServiceId:     rec.ServiceID,
+		Contract:      proto.Clone(rec.Contract).(*nfa_intent_v1alpha.IntentContract),
+		LastHeartbeat: timestamppb.New(rec.LastHeartbeat),
+		Expires:       timestamppb.New(rec.Expires),
+	}
+}
+
+func registrationFromProto(reg *nfa_cluster_v1alpha.Registration) broker.StoredRegistration {
+	return broker.StoredRegistration{
+		ServiceID:     reg.ServiceId,
+		Contract:      proto.Clone(reg.Contract).(*nfa_intent_v1alpha.IntentContract),
+		LastHeartbeat: reg.LastHeartbeat.AsTime(),
+		Expires:       reg.Expires.AsTime(),
+	}
+}
@@ -0,0 +1,460 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	nfa_cluster_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/cluster/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// network carries the calls between the nodes of a test cluster, failing
+// those drop reports, e.g. to partition a node
+type network struct {
+	mu    sync.Mutex
+	nodes map[string]string // node IDs by address
+	drop  func(from, to string, req interface{}) bool
+}
+
+// intercept fails the calls of node from that the network drops
+func (nw *network) intercept(from string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		nw.mu.Lock()
+		drop := nw.drop != nil && nw.drop(from, nw.nodes[cc.Target()], req)
+		nw.mu.Unlock()
+		if drop {
+			return status.Error(codes.Unavailable, "partitioned")
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func (nw *network) setDrop(drop func(from, to string, req interface{}) bool) {
+	nw.mu.Lock()
+	defer nw.mu.Unlock()
+	nw.drop = drop
+}
+
+// isolate cuts node id off the others
+func (nw *network) isolate(id string) {
+	nw.setDrop(func(from, to string, _ interface{}) bool { return from == id || to == id })
+}
+
+func (nw *network) heal() {
+	nw.setDrop(nil)
+}
+
+// testNode is a node of a test cluster with its broker
+type testNode struct {
+	*Node
+	broker *broker.Embedded
+	addr   string
+}
+
+// newCluster starts a broker and a node for each of ids, configured by cfg
+func newCluster(t *testing.T, cfg Config, ids ...string) (map[string]*testNode, *network) {
+	t.Helper()
+	nw := &network{nodes: make(map[string]string)}
+	listeners := make(map[string]net.Listener)
+	peers := make(map[string]string)
+	for _, id := range ids {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		listeners[id], peers[id] = lis, lis.Addr().String()
+		nw.nodes[lis.Addr().String()] = id
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	nodes := make(map[string]*testNode)
+	for _, id := range ids {
+		cfg := cfg
+		cfg.ID, cfg.Peers = id, peers
+		cfg.DialOptions = []grpc.DialOption{
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithUnaryInterceptor(nw.intercept(id)),
+		}
+		node, err := NewNode(cfg)
+		if err != nil {
+			t.Fatalf("NewNode() error = %v", err)
+		}
+		b := broker.NewEmbedded(
+			broker.WithStore(node),
+			broker.WithService(node.Register),
+			broker.WithServerOptions(grpc.UnaryInterceptor(node.UnaryServerInterceptor())))
+		go b.Serve(listeners[id])
+		go node.Run(ctx, b)
+		nodes[id] = &testNode{Node: node, broker: b, addr: peers[id]}
+	}
+	t.Cleanup(func() {
+		cancel()
+		for _, n := range nodes {
+			n.broker.Close()
+			n.Close()
+		}
+	})
+	return nodes, nw
+}
+
+// testConfig elects and replicates quickly
+var testConfig = Config{
+	ElectionTimeout:   300 * time.Millisecond,
+	HeartbeatInterval: 20 * time.Millisecond,
+	CommitTimeout:     time.Second,
+}
+
+// eventually fails t unless cond holds within 10s
+func eventually(t *testing.T, cond func() bool, format string, args ...interface{}) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf(format, args...)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (n *testNode) status() *nfa_cluster_v1alpha.ClusterStatus {
+	st, _ := n.GetStatus(context.Background(), &nfa_cluster_v1alpha.GetStatusRequest{})
+	return st
+}
+
+// electedLeader waits until ids agree on a leader among them and returns it
+func electedLeader(t *testing.T, nodes map[string]*testNode, ids ...string) string {
+	t.Helper()
+	var id string
+	eventually(t, func() bool {
+		id = nodes[ids[0]].status().LeaderId
+		for _, other := range ids {
+			if st := nodes[other].status(); st.LeaderId != id || st.Term != nodes[ids[0]].status().Term {
+				return false
+			}
+		}
+		return slices.Contains(ids, id) && nodes[id].status().Role == "LEADER"
+	}, "nodes %v elected no leader", ids)
+	return id
+}
+
+// contracts returns the contract names registered with b, sorted
+func contracts(b *broker.Embedded) []string {
+	var names []string
+	for _, svc := range b.Services() {
+		names = append(names, svc.Contract)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// converge waits until the brokers of ids have the registrations of want
+func converge(t *testing.T, nodes map[string]*testNode, want []string, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		eventually(t, func() bool { return slices.Equal(contracts(nodes[id].broker), want) },
+			"node %s has %v, want %v", id, contracts(nodes[id].broker), want)
+	}
+}
+
+func registerRequest(name string) *nfa_broker_v1alpha.RegisterIntentRequest {
+	return &nfa_broker_v1alpha.RegisterIntentRequest{
+		Contract: &nfa_intent_v1alpha.IntentContract{Metadata: &nfa_intent_v1alpha.Metadata{Name: name}},
+	}
+}
+
+func register(t *testing.T, b *broker.Embedded, name string) string {
+	t.Helper()
+	resp, err := b.RegisterIntent(context.Background(), registerRequest(name))
+	if err != nil {
+		t.Fatalf("RegisterIntent(%s) error = %v", name, err)
+	}
+	return resp.ServiceId
+}
+
+func TestElection(t *testing.T) {
+	nodes, _ := newCluster(t, testConfig, "a", "b", "c")
+	id := electedLeader(t, nodes, "a", "b", "c")
+	for _, n := range nodes {
+		st := n.status()
+		if want := "FOLLOWER"; n.cfg.ID != id && st.Role != want {
+			t.Errorf("node %s is %s, want %s of %s", n.cfg.ID, st.Role, want, id)
+		}
+		if len(st.Members) != 3 {
+			t.Errorf("node %s has members %v, want 3", n.cfg.ID, st.Members)
+		}
+	}
+}
+
+func TestReplication(t *testing.T) {
+	nodes, _ := newCluster(t, testConfig, "a", "b", "c")
+	l := nodes[electedLeader(t, nodes, "a", "b", "c")]
+	translator := register(t, l.broker, "translator")
+	register(t, l.broker, "lights")
+	converge(t, nodes, []string{"lights", "translator"}, "a", "b", "c")
+
+	if err := l.broker.SetLabels(translator, map[string]string{"env": "prod"}); err != nil {
+		t.Fatalf("SetLabels() error = %v", err)
+	}
+	l.broker.Remove(translator)
+	converge(t, nodes, []string{"lights"}, "a", "b", "c")
+	eventually(t, func() bool {
+		commit := l.status().CommitIndex
+		for _, n := range nodes {
+			if n.status().CommitIndex != commit || n.status().LastLogIndex != commit {
+				return false
+			}
+		}
+		return true
+	}, "logs of the nodes differ")
+}
+
+func TestFailover(t *testing.T) {
+	nodes, nw := newCluster(t, testConfig, "a", "b", "c")
+	old := electedLeader(t, nodes, "a", "b", "c")
+	term := nodes[old].status().Term
+	register(t, nodes[old].broker, "translator")
+	converge(t, nodes, []string{"translator"}, "a", "b", "c")
+
+	nw.isolate(old)
+	var rest []string
+	for id := range nodes {
+		if id != old {
+			rest = append(rest, id)
+		}
+	}
+	l := nodes[electedLeader(t, nodes, rest...)]
+	if st := l.status(); st.Term <= term {
+		t.Errorf("new leader's term = %d, want after %d", st.Term, term)
+	}
+	register(t, l.broker, "lights")
+	converge(t, nodes, []string{"lights", "translator"}, rest...)
+
+	// The old leader steps down and catches up once it is back
+	nw.heal()
+	converge(t, nodes, []string{"lights", "translator"}, old)
+	if got := electedLeader(t, nodes, "a", "b", "c"); got == old {
+		t.Errorf("leader after healing = %s, want the new one", got)
+	}
+}
+
+func TestSnapshotInstall(t *testing.T) {
+	cfg := testConfig
+	cfg.SnapshotThreshold = 4
+	nodes, nw := newCluster(t, cfg, "a", "b", "c")
+	id := electedLeader(t, nodes, "a", "b", "c")
+	l := nodes[id]
+	var lagging string
+	for other := range nodes {
+		if other != id {
+			lagging = other
+		}
+	}
+	gone := register(t, l.broker, "gone")
+	converge(t, nodes, []string{"gone"}, "a", "b", "c")
+
+	// The lagging node misses more changes than the log keeps
+	nw.setDrop(func(from, to string, _ interface{}) bool { return from == lagging || to == lagging })
+	var want []string
+	for i := 0; i < 6; i++ {
+		name := fmt.Sprintf("svc%d", i)
+		register(t, l.broker, name)
+		want = append(want, name)
+	}
+	l.broker.Remove(gone)
+	if st := l.status(); st.SnapshotIndex <= nodes[lagging].status().LastLogIndex {
+		t.Fatalf("leader's snapshot at %d, want it past the lagging node's log at %d", st.SnapshotIndex, nodes[lagging].status().LastLogIndex)
+	}
+
+	nw.heal()
+	converge(t, nodes, want, lagging)
+	if st := nodes[lagging].status(); st.SnapshotIndex == 0 {
+		t.Errorf("lagging node's snapshot index = 0, want the installed snapshot")
+	}
+}
+
+func TestForwarding(t *testing.T) {
+	nodes, _ := newCluster(t, testConfig, "a", "b", "c")
+	id := electedLeader(t, nodes, "a", "b", "c")
+	var follower *testNode
+	for other, n := range nodes {
+		if other != id {
+			follower = n
+		}
+	}
+	conn, err := grpc.Dial(follower.addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := nfa_broker_v1alpha.NewIntentBrokerClient(conn)
+	ctx := context.Background()
+
+	resp, err := client.RegisterIntent(ctx, registerRequest("translator"))
+	if err != nil {
+		t.Fatalf("RegisterIntent() through a follower error = %v", err)
+	}
+	converge(t, nodes, []string{"translator"}, "a", "b", "c")
+	if _, err := client.Heartbeat(ctx, &nfa_broker_v1alpha.HeartbeatRequest{ServiceId: resp.ServiceId}); err != nil {
+		t.Errorf("Heartbeat() through a follower error = %v", err)
+	}
+	// Reads are served by the follower itself
+	if _, err := client.MatchIntent(ctx, &nfa_broker_v1alpha.IntentMatchRequest{}); status.Code(err) == codes.Unavailable {
+		t.Errorf("MatchIntent() on a follower error = %v, want it served locally", err)
+	}
+
+	forwarded := metadata.AppendToOutgoingContext(ctx, forwardedHeader, "x")
+	if _, err := client.RegisterIntent(forwarded, registerRequest("lights")); status.Code(err) != codes.Unavailable {
+		t.Errorf("RegisterIntent() forwarded to a follower error = %v, want Unavailable", err)
+	}
+
+	unregistered, err := client.UnregisterIntent(ctx, &nfa_broker_v1alpha.UnregisterIntentRequest{ServiceId: resp.ServiceId})
+	if err != nil || !unregistered.Success {
+		t.Fatalf("UnregisterIntent() through a follower = %v, %v, want success", unregistered, err)
+	}
+	converge(t, nodes, nil, "a", "b", "c")
+}
+
+func TestCommitAfterTimeout(t *testing.T) {
+	// Commits time out long before the followers would elect another leader
+	cfg := testConfig
+	cfg.ElectionTimeout = 2 * time.Second
+	cfg.CommitTimeout = 100 * time.Millisecond
+	nodes, nw := newCluster(t, cfg, "a", "b", "c")
+	id := electedLeader(t, nodes, "a", "b", "c")
+
+	// The leader's entries reach the followers only once the registration
+	// timed out
+	nw.setDrop(func(from, to string, req interface{}) bool {
+		entries, ok := req.(*nfa_cluster_v1alpha.AppendEntriesRequest)
+		return ok && from == id && len(entries.Entries) > 0
+	})
+	_, err := nodes[id].broker.RegisterIntent(context.Background(), registerRequest("translator"))
+	if !strings.Contains(fmt.Sprint(err), ErrCommitTimeout.Error()) {
+		t.Fatalf("RegisterIntent() error = %v, want %v", err, ErrCommitTimeout)
+	}
+	nw.heal()
+
+	// Committed after all, it is registered on every node, the leader too
+	converge(t, nodes, []string{"translator"}, "a", "b", "c")
+	if got := electedLeader(t, nodes, "a", "b", "c"); got != id {
+		t.Errorf("leader = %s, want %s still", got, id)
+	}
+}
+
+func TestDiscardedChangeReverted(t *testing.T) {
+	cfg := testConfig
+	cfg.CommitTimeout = 200 * time.Millisecond
+	nodes, nw := newCluster(t, cfg, "a", "b", "c")
+	old := electedLeader(t, nodes, "a", "b", "c")
+	translator := register(t, nodes[old].broker, "translator")
+	converge(t, nodes, []string{"translator"}, "a", "b", "c")
+
+	// The isolated leader removes the registration, but cannot commit it
+	nw.isolate(old)
+	nodes[old].broker.Remove(translator)
+	if got := contracts(nodes[old].broker); len(got) != 0 {
+		t.Fatalf("isolated leader has %v after Remove, want none", got)
+	}
+	var rest []string
+	for id := range nodes {
+		if id != old {
+			rest = append(rest, id)
+		}
+	}
+	l := nodes[electedLeader(t, nodes, rest...)]
+	register(t, l.broker, "lights")
+
+	// Its log is replaced by the new leader's, and the removal undone
+	nw.heal()
+	converge(t, nodes, []string{"lights", "translator"}, "a", "b", "c")
+}
+
+// fakeRegistry is a Registry of the registrations applied to it
+type fakeRegistry map[string]broker.StoredRegistration
+
+func (r fakeRegistry) ApplyStored(rec broker.StoredRegistration) { r[rec.ServiceID] = rec }
+
+func (r fakeRegistry) ApplyDeleted(serviceID string) { delete(r, serviceID) }
+
+func (r fakeRegistry) ids() []string {
+	var ids []string
+	for id := range r {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// del returns a log entry deleting the service of contract name
+func del(term, index uint64, name string) *nfa_cluster_v1alpha.LogEntry {
+	return &nfa_cluster_v1alpha.LogEntry{Term: term, Index: index, Command: &nfa_cluster_v1alpha.Command{
+		Change: &nfa_cluster_v1alpha.Command_Delete{Delete: name + "-1"},
+	}}
+}
+
+func TestAppendEntriesConflict(t *testing.T) {
+	n, err := NewNode(Config{ID: "a", Peers: map[string]string{"a": "127.0.0.1:1", "b": "127.0.0.1:2", "c": "127.0.0.1:3"}})
+	if err != nil {
+		t.Fatalf("NewNode() error = %v", err)
+	}
+	defer n.Close()
+	ctx := context.Background()
+	registry := fakeRegistry{}
+	appendEntries := func(req *nfa_cluster_v1alpha.AppendEntriesRequest) *nfa_cluster_v1alpha.AppendEntriesResponse {
+		t.Helper()
+		resp, err := n.AppendEntries(ctx, req)
+		if err != nil {
+			t.Fatalf("AppendEntries() error = %v", err)
+		}
+		n.apply(registry)
+		return resp
+	}
+
+	// b leads term 1 and commits the first entry only
+	appendEntries(&nfa_cluster_v1alpha.AppendEntriesRequest{
+		Term: 1, LeaderId: "b", LeaderCommit: 1,
+		Entries: []*nfa_cluster_v1alpha.LogEntry{put(1, 1, "translator"), put(1, 2, "lights"), del(1, 3, "translator")},
+	})
+	if got := registry.ids(); !slices.Equal(got, []string{"translator-1"}) {
+		t.Fatalf("registry = %v, want the committed entry", got)
+	}
+	// As the broker of b made them before proposing them
+	registry["lights-1"] = broker.StoredRegistration{ServiceID: "lights-1"}
+	delete(registry, "translator-1")
+
+	// c leads term 2 without the uncommitted entries
+	resp := appendEntries(&nfa_cluster_v1alpha.AppendEntriesRequest{Term: 2, LeaderId: "c", PrevLogIndex: 3, PrevLogTerm: 2})
+	if resp.Success || resp.LastLogIndex != 0 {
+		t.Errorf("AppendEntries() with a conflicting previous entry = %v, want to retry before term 1", resp)
+	}
+	resp = appendEntries(&nfa_cluster_v1alpha.AppendEntriesRequest{
+		Term: 2, LeaderId: "c", PrevLogIndex: 1, PrevLogTerm: 1, LeaderCommit: 2,
+		Entries: []*nfa_cluster_v1alpha.LogEntry{put(2, 2, "thermostat")},
+	})
+	if !resp.Success || resp.LastLogIndex != 2 {
+		t.Errorf("AppendEntries() = %v, want success up to 2", resp)
+	}
+	var terms []uint64
+	for _, entry := range n.log {
+		terms = append(terms, entry.Term)
+	}
+	if !slices.Equal(terms, []uint64{1, 2}) {
+		t.Errorf("log terms = %v, want the conflicting entries truncated", terms)
+	}
+	if got := registry.ids(); !slices.Equal(got, []string{"thermostat-1", "translator-1"}) {
+		t.Errorf("registry = %v, want the discarded changes reverted", got)
+	}
+}
@@ -0,0 +1,248 @@
+package cluster
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/atrest"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	nfa_cluster_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/cluster/v1alpha"
+	"google.golang.org/protobuf/proto"
+)
+
+// Files of a node's directory
+const (
+	stateFile    = "state.pb"
+	snapshotFile = "snapshot.pb"
+	logFile      = "log.pb"
+)
+
+// maxEntrySize bounds the entries read back from the log, so a corrupt
+// length does not allocate without limit
+const maxEntrySize = 64 << 20
+
+// storage persists the Raft state of a node under a directory: its term and
+// vote, its last snapshot and the log entries after it, each entry prefixed
+// by its length. The state and the snapshot are replaced atomically; the log
+// is appended to, and replaced when truncated or compacted. An empty
+// directory keeps nothing, for tests. With a sealer, the snapshot and the
+// entries, which hold the registrations, are sealed in
+// broker.RegistryNamespace; those written before are read as they are.
+type storage struct {
+	dir    string
+	sealer *atrest.Sealer
+}
+
+// load reads the persisted state; a new node has none
+func (s *storage) load() (*nfa_cluster_v1alpha.PersistentState, *nfa_cluster_v1alpha.Snapshot, []*nfa_cluster_v1alpha.LogEntry, error) {
+	state := &nfa_cluster_v1alpha.PersistentState{}
+	snapshot := &nfa_cluster_v1alpha.Snapshot{}
+	if s.dir == "" {
+		return state, snapshot, nil, nil
+	}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create cluster directory: %w", err)
+	}
+	if tmps, err := filepath.Glob(filepath.Join(s.dir, "tmp-*")); err == nil {
+		// Left over by writes interrupted before their rename
+		for _, tmp := range tmps {
+			os.Remove(tmp)
+		}
+	}
+	if err := s.readMessage(filepath.Join(s.dir, stateFile), state); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load state: %w", err)
+	}
+	if err := s.readMessage(filepath.Join(s.dir, snapshotFile), snapshot); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load snapshot: %w", err)
+	}
+	f, err := os.Open(filepath.Join(s.dir, logFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return state, snapshot, nil, nil
+	}
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load log: %w", err)
+	}
+	defer f.Close()
+	var entries []*nfa_cluster_v1alpha.LogEntry
+	torn := false
+	r := bufio.NewReader(f)
+	for {
+		size, err := binary.ReadUvarint(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil || size > maxEntrySize {
+			torn = true
+			break
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			torn = true
+			break
+		}
+		if data, err = s.open(data); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to load log entry: %w", err)
+		}
+		entry := &nfa_cluster_v1alpha.LogEntry{}
+		if err := proto.Unmarshal(data, entry); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to load log entry: %w", err)
+		}
+		// Entries compacted into the snapshot survive a crash before the
+		// log was rewritten
+		if entry.Index > snapshot.LastIndex {
+			entries = append(entries, entry)
+		}
+	}
+	if torn {
+		// An append interrupted by a crash; its entries were never
+		// acknowledged, so they are dropped before appending after them
+		if err := s.rewrite(entries); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to repair log: %w", err)
+		}
+	}
+	return state, snapshot, entries, nil
+}
+
+// saveState persists the term and vote
+func (s *storage) saveState(state *nfa_cluster_v1alpha.PersistentState) error {
+	if s.dir == "" {
+		return nil
+	}
+	data, err := proto.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return writeAtomic(s.dir, stateFile, data)
+}
+
+// saveSnapshot persists a snapshot and the entries after it
+func (s *storage) saveSnapshot(snapshot *nfa_cluster_v1alpha.Snapshot, entries []*nfa_cluster_v1alpha.LogEntry) error {
+	if s.dir == "" {
+		return nil
+	}
+	data, err := proto.Marshal(snapshot)
+	if err == nil {
+		data, err = s.seal(data)
+	}
+	if err != nil {
+		return err
+	}
+	if err := writeAtomic(s.dir, snapshotFile, data); err != nil {
+		return err
+	}
+	return s.rewrite(entries)
+}
+
+// append adds entries to the end of the log
+func (s *storage) append(entries []*nfa_cluster_v1alpha.LogEntry) error {
+	if s.dir == "" || len(entries) == 0 {
+		return nil
+	}
+	data, err := s.encodeEntries(entries)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(s.dir, logFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// rewrite replaces the log with entries
+func (s *storage) rewrite(entries []*nfa_cluster_v1alpha.LogEntry) error {
+	if s.dir == "" {
+		return nil
+	}
+	data, err := s.encodeEntries(entries)
+	if err != nil {
+		return err
+	}
+	return writeAtomic(s.dir, logFile, data)
+}
+
+func (s *storage) encodeEntries(entries []*nfa_cluster_v1alpha.LogEntry) ([]byte, error) {
+	var buf []byte
+	for _, entry := range entries {
+		data, err := proto.Marshal(entry)
+		if err == nil {
+			data, err = s.seal(data)
+		}
+		if err != nil {
+			return nil, err
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(data)))
+		buf = append(buf, data...)
+	}
+	return buf, nil
+}
+
+// seal encrypts the encoding of a message, if the storage has a sealer
+func (s *storage) seal(data []byte) ([]byte, error) {
+	if s.sealer == nil {
+		return data, nil
+	}
+	return s.sealer.Seal(context.Background(), broker.RegistryNamespace, data)
+}
+
+// open decrypts the encoding of a message if it is sealed
+func (s *storage) open(data []byte) ([]byte, error) {
+	if !atrest.IsSealed(data) {
+		return data, nil
+	}
+	if s.sealer == nil {
+		return nil, errors.New("data is sealed, but the node has no keys")
+	}
+	return s.sealer.Open(context.Background(), broker.RegistryNamespace, data)
+}
+
+// readMessage reads a message from a file, leaving m empty if the file does
+// not exist
+func (s *storage) readMessage(path string, m proto.Message) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err == nil {
+		data, err = s.open(data)
+	}
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, m)
+}
+
+// writeAtomic replaces the file name in dir with data
+func writeAtomic(dir, name string, data []byte) error {
+	tmp, err := os.CreateTemp(dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
@@ -0,0 +1,80 @@
+package cluster
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/atrest"
+	nfa_cluster_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/cluster/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+)
+
+// put returns a log entry registering a service of contract name
+func put(term, index uint64, name string) *nfa_cluster_v1alpha.LogEntry {
+	return &nfa_cluster_v1alpha.LogEntry{Term: term, Index: index, Command: &nfa_cluster_v1alpha.Command{
+		Change: &nfa_cluster_v1alpha.Command_Put{Put: &nfa_cluster_v1alpha.Registration{
+			ServiceId: name + "-1",
+			Contract:  &nfa_intent_v1alpha.IntentContract{Metadata: &nfa_intent_v1alpha.Metadata{Name: name}},
+		}},
+	}}
+}
+
+func TestStorageSealed(t *testing.T) {
+	keyfile := filepath.Join(t.TempDir(), "keys")
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, atrest.KeySize))
+	if err := os.WriteFile(keyfile, []byte("k1 "+key+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := atrest.LoadKeyFile(keyfile)
+	if err != nil {
+		t.Fatalf("LoadKeyFile() error = %v", err)
+	}
+	dir := t.TempDir()
+	plain := &storage{dir: dir}
+	sealed := &storage{dir: dir, sealer: atrest.NewSealer(keys)}
+
+	// Entries appended before sealing was enabled stay readable
+	if _, _, _, err := plain.load(); err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	if err := plain.append([]*nfa_cluster_v1alpha.LogEntry{put(1, 1, "translator")}); err != nil {
+		t.Fatalf("append() error = %v", err)
+	}
+	if err := sealed.append([]*nfa_cluster_v1alpha.LogEntry{put(1, 2, "lights")}); err != nil {
+		t.Fatalf("append() sealed error = %v", err)
+	}
+	_, _, entries, err := sealed.load()
+	if err != nil || len(entries) != 2 || entries[1].GetCommand().GetPut().GetContract().GetMetadata().GetName() != "lights" {
+		t.Fatalf("load() = %v, %v, want the plaintext and the sealed entry", entries, err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, logFile))
+	if bytes.Contains(data, []byte("lights")) {
+		t.Errorf("log holds the sealed contract in plaintext")
+	}
+
+	// A compaction seals the snapshot and rewrites the log sealed
+	snapshot := &nfa_cluster_v1alpha.Snapshot{LastIndex: 2, LastTerm: 1, Registrations: []*nfa_cluster_v1alpha.Registration{
+		entries[0].Command.GetPut(), entries[1].Command.GetPut(),
+	}}
+	if err := sealed.saveSnapshot(snapshot, []*nfa_cluster_v1alpha.LogEntry{put(2, 3, "thermostat")}); err != nil {
+		t.Fatalf("saveSnapshot() error = %v", err)
+	}
+	for _, name := range []string{snapshotFile, logFile} {
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		for _, contract := range []string{"translator", "lights", "thermostat"} {
+			if bytes.Contains(data, []byte(contract)) {
+				t.Errorf("%s holds contract %s in plaintext", name, contract)
+			}
+		}
+	}
+	_, loaded, entries, err := sealed.load()
+	if err != nil || len(loaded.Registrations) != 2 || len(entries) != 1 || entries[0].Index != 3 {
+		t.Fatalf("load() after saveSnapshot = %v, %v, %v, want the snapshot and entry 3", loaded, entries, err)
+	}
+	if _, _, _, err := plain.load(); err == nil {
+		t.Errorf("load() of sealed data without keys succeeded, want an error")
+	}
+}
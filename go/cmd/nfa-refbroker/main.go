@@ -0,0 +1,424 @@
+// Command nfa-refbroker runs the reference Intent Broker of the Go SDK, the
+// embedded broker of package broker, as a standalone process. Together with
+// nfa-runtime it makes a runnable stack without the Rust broker: it serves
+// registration, heartbeats, matching and deregistration, the control and
+// timer services, and the admin service: `nfactl` reloads the configuration,
+// changes log levels, drains or retags providers and reports deprecated
+// aliases and handler errors through it; SIGHUP also reloads the
+// configuration. Without -config it runs with the defaults of the NFA_PROFILE
+// profile. Reloads apply its logging, rate limits of intent matches
+// and TLS certificate without a restart. It also serves the pub/sub, webhook and data subject
+// services, the latter covering the pub/sub events, and with -blob-dir the
+// blob service. Webhooks are told of services registering, unregistering
+// and missing their heartbeats, and of providers missing the objectives of
+// their contracts' QoS, which the SLA reports of the admin service measure
+// from the outcomes consumers report. The [retention] section of the
+// configuration bounds the pub/sub events, SLA samples and reload audit
+// trail, which nfactl storage reports on. The catalog service exports the contracts
+// registered in a namespace, signed with -catalog-key, and imports bundles
+// signed by a -catalog-trusted key as static providers. It leaves out the
+// resumable stream service, which providers serve for their own streaming
+// actions, and the experiment service, since the embedded broker matches
+// without the policy engine experiments route through. Registrations are kept in memory unless the "file" storage
+// backend persists them in a directory, or the "badger" one in an embedded
+// Badger database, restoring them after a restart. With
+// a node ID and peers it joins a cluster replicating the registrations with
+// Raft, see package cluster, so the fabric survives the loss of a node.
+// Either way, -keyfile seals the registrations kept on disk, see package
+// atrest, and -timer-dir keeps the timers, so pending ones survive a
+// restart.
+// With -ca-dir the built-in CA of package ca issues the broker's certificate
+// and enrolls runtimes, whose certificates every call but enrollment then
+// requires; -admin-listen serves the admin API, and so nfactl, in plaintext
+// on another address. With -metrics-listen it serves the Prometheus metrics
+// of the broker, their series bounded by the [metrics.limits] section of the
+// configuration.
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/admin"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/atrest"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/blob"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/ca"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/catalog"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/cluster"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/config"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/internal/cli"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	nfaprom "github.com/neuro-fluidic-architecture/nfa-core/go/metrics/prometheus"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/privacy"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pubsub"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/ratelimit"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/retention"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/sla"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/store/badger"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/webhook"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+func main() {
+	configPath := flag.String("config", "", "Path to a TOML configuration file; its [broker] section sets the defaults of the other flags")
+	listen := flag.String("listen", "", "Address to serve on (default: listen_address of the configuration, 0.0.0.0:50051)")
+	staticDir := flag.String("static-contracts", "", "Directory of contracts registered as static providers at startup")
+	heartbeatTimeout := flag.Duration("heartbeat-timeout", 0, "How long a registration stays live without heartbeats (default: heartbeat_timeout_secs of the configuration, 30s)")
+	reapAfter := flag.Duration("reap-after", 10*time.Minute, "Remove registrations whose lease expired this long ago (0 keeps them)")
+	storage := flag.String("storage", "", "Storage backend of the registrations: memory, file or badger (default: storage_backend of the configuration, memory)")
+	storageDir := flag.String("storage-dir", "", "Directory of the file and badger storage backends (default: storage_dir of the configuration)")
+	nodeID := flag.String("node-id", "", "ID of this node in a cluster (default: cluster_node_id of the configuration); empty runs a single broker")
+	peers := flag.String("peers", "", "Broker addresses of every node of the cluster, this one included, as id=host:port[,id=host:port] (default: cluster_peers of the configuration)")
+	clusterDir := flag.String("cluster-dir", "", "Directory of the replicated log of this node (default: cluster_dir of the configuration)")
+	timerDir := flag.String("timer-dir", "", "Directory keeping the timers, so pending timers survive restarts (default: timer_dir of the configuration); empty keeps them in memory")
+	keyFile := flag.String("keyfile", "", "Keyfile sealing the registrations kept in -storage-dir or -cluster-dir (default: key_file of the configuration); empty keeps them in plaintext")
+	pubsubRetention := flag.Int("pubsub-retention", 0, "Events retained per pub/sub topic (default: 10000)")
+	blobDir := flag.String("blob-dir", "", "Directory of the blob service; empty leaves the blob service out")
+	blobListen := flag.String("blob-listen", "", "Address serving pre-signed blob URLs over HTTP; empty serves none")
+	blobURL := flag.String("blob-url", "", "Externally reachable URL of -blob-listen, e.g. https://broker.example.com:8090 (default: http://<blob-listen>)")
+	metricsListen := flag.String("metrics-listen", "", "Address serving Prometheus metrics at /metrics, bounded by [metrics.limits] of the configuration (default: listen_address of its [metrics] section); empty serves none")
+	caDir := flag.String("ca-dir", "", "Directory of the built-in CA, which issues the broker's certificate and enrolls runtimes; empty runs none")
+	caHosts := flag.String("ca-hosts", "localhost", "DNS names or IP addresses of the broker in the certificate of the built-in CA, comma separated")
+	catalogKey := flag.String("catalog-key", "", "PEM Ed25519 private key signing catalog exports; empty disables exports")
+	catalogKeyID := flag.String("catalog-key-id", "nfa-refbroker", "Key ID of -catalog-key, which importing brokers trust it under")
+	catalogTrusted := flag.String("catalog-trusted", "", "PEM Ed25519 public keys trusted to sign catalog imports, as id=path[,id=path]; empty refuses every import")
+	adminListen := flag.String("admin-listen", "", "Address also serving the admin API, in plaintext, e.g. localhost:50052 for nfactl next to -ca-dir; empty serves it only on -listen")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 30*time.Second, "How long in-flight calls get to finish on shutdown")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var cfg *config.Config
+	var reloader *config.Reloader
+	if *configPath != "" {
+		var err error
+		if reloader, err = config.NewReloader(*configPath); err != nil {
+			log.Fatalf("Failed to load configuration: %v", err)
+		}
+		cfg = reloader.Current()
+		go reloader.WatchSignals(ctx)
+	} else {
+		profile, err := config.ActiveProfile()
+		if err != nil {
+			log.Fatalf("Failed to load configuration: %v", err)
+		}
+		if cfg, err = config.DefaultFor(profile); err != nil {
+			log.Fatalf("Failed to load configuration: %v", err)
+		}
+	}
+	if err := logging.Configure(cfg.Logging.Format, cfg.Logging.Level); err != nil {
+		log.Fatalf("Invalid logging configuration: %v", err)
+	}
+	if *listen == "" {
+		*listen = cfg.Broker.ListenAddress
+	}
+	if *staticDir == "" {
+		*staticDir = cfg.Broker.StaticContractsDir
+	}
+	if *storage == "" {
+		*storage = cfg.Broker.StorageBackend
+	}
+	if *storageDir == "" {
+		*storageDir = cfg.Broker.StorageDir
+	}
+	if *nodeID == "" {
+		*nodeID = cfg.Broker.ClusterNodeID
+	}
+	if *clusterDir == "" {
+		*clusterDir = cfg.Broker.ClusterDir
+	}
+	if *timerDir == "" {
+		*timerDir = cfg.Broker.TimerDir
+	}
+	if *keyFile == "" {
+		*keyFile = cfg.Broker.KeyFile
+	}
+	peerAddrs := cfg.Broker.ClusterPeers
+	if *peers != "" {
+		var err error
+		if peerAddrs, err = cli.ParsePairs(*peers); err != nil {
+			log.Fatalf("Invalid -peers: %v", err)
+		}
+	}
+	if *heartbeatTimeout == 0 {
+		*heartbeatTimeout = time.Duration(cfg.Broker.HeartbeatTimeoutSecs) * time.Second
+	}
+	if *metricsListen == "" {
+		*metricsListen = cfg.Metrics.ListenAddress
+	}
+
+	adminServer := admin.NewServer(reloader)
+	var signingKey ed25519.PrivateKey
+	if *catalogKey != "" {
+		var err error
+		if signingKey, err = catalog.LoadSigningKey(*catalogKey); err != nil {
+			log.Fatalf("Failed to load the catalog signing key: %v", err)
+		}
+	}
+	trustedPaths, err := cli.ParsePairs(*catalogTrusted)
+	if err != nil {
+		log.Fatalf("Invalid -catalog-trusted: %v", err)
+	}
+	trusted := make(map[string]ed25519.PublicKey, len(trustedPaths))
+	for id, path := range trustedPaths {
+		if trusted[id], err = catalog.LoadTrustedKey(path); err != nil {
+			log.Fatalf("Failed to load trusted catalog key %s: %v", id, err)
+		}
+	}
+	catalogs := catalog.NewBrokerStore()
+	// SLA reports are built from the outcomes consumers report
+	slaRecorder := sla.NewRecorder(0)
+	adminServer.SetSLARecorder(slaRecorder)
+	events := pubsub.NewBroker(*pubsubRetention)
+	// The reload audit trail is the broker's only audit trail, since it
+	// routes without a residency guard
+	retainer := retention.NewManager()
+	policies := cfg.Retention.Policies()
+	retainer.Add(retention.Events, events, policies[retention.Events])
+	retainer.Add(retention.Analytics, slaRecorder, policies[retention.Analytics])
+	if reloader != nil {
+		retainer.Add(retention.Audit, reloader, policies[retention.Audit])
+	}
+	adminServer.SetRetention(retainer)
+	dispatcher := webhook.NewDispatcher()
+	limiter := ratelimit.New(cfg.RateLimits.Limits())
+	interceptors := []grpc.UnaryServerInterceptor{limiter.UnaryServerInterceptor()}
+	if reloader != nil {
+		reloader.OnChange(func(old, updated *config.Config) {
+			if updated.Logging != old.Logging {
+				if err := logging.Configure(updated.Logging.Format, updated.Logging.Level); err != nil {
+					log.Printf("Logging configuration not applied: %v", err)
+				}
+			}
+			limiter.Set(updated.RateLimits.Limits())
+			for name, policy := range updated.Retention.Policies() {
+				if err := retainer.SetPolicy(name, policy); err != nil {
+					log.Printf("Retention policy of %s not applied: %v", name, err)
+				}
+			}
+		})
+	}
+	opts := []broker.EmbeddedOption{
+		broker.WithReapAfter(*reapAfter),
+		broker.WithTimerDir(*timerDir),
+		broker.WithService(adminServer.Register),
+		broker.WithService(events.Register),
+		broker.WithService(privacy.NewServer(privacy.Events(events)).Register),
+		broker.WithService(dispatcher.Register),
+		broker.WithService(catalog.NewServer(catalogs, *catalogKeyID, signingKey, trusted).Register),
+		broker.WithLifecycle(webhook.Lifecycle(dispatcher)),
+		broker.WithOutcomes(slaRecorder.ObserveOutcome),
+	}
+	var blobs *blob.Server
+	if *blobDir != "" {
+		if *blobListen != "" && *blobURL == "" {
+			*blobURL = "http://" + *blobListen
+		}
+		var err error
+		if blobs, err = blob.NewServer(*blobDir, *blobURL, 0); err != nil {
+			log.Fatalf("Failed to start blob service: %v", err)
+		}
+		opts = append(opts, broker.WithService(blobs.Register))
+	} else if *blobListen != "" {
+		log.Fatal("-blob-listen requires -blob-dir")
+	}
+	if *heartbeatTimeout > 0 {
+		opts = append(opts, broker.WithLivenessTimeout(*heartbeatTimeout))
+	}
+	var metrics *nfaprom.BrokerMetrics
+	if *metricsListen != "" {
+		if err := cfg.Metrics.Limits.Validate(); err != nil {
+			log.Fatalf("Invalid metrics limits: %v", err)
+		}
+		metrics = nfaprom.NewBroker(nfaprom.WithLimits(cfg.Metrics.Limits))
+		opts = append(opts, broker.WithMetrics(metrics))
+	}
+	var sealer *atrest.Sealer
+	if *keyFile != "" {
+		keys, err := atrest.LoadKeyFile(*keyFile)
+		if err != nil {
+			log.Fatalf("Failed to load keys: %v", err)
+		}
+		sealer = atrest.NewSealer(keys)
+	}
+	switch *storage {
+	case "", "memory":
+	case "file":
+		if *storageDir == "" {
+			log.Fatal("The file storage backend requires -storage-dir")
+		}
+		var storeOpts []broker.DirStoreOption
+		if sealer != nil {
+			storeOpts = append(storeOpts, broker.SealedWith(sealer))
+		}
+		store, err := broker.NewDirStore(*storageDir, storeOpts...)
+		if err != nil {
+			log.Fatalf("Failed to open storage: %v", err)
+		}
+		opts = append(opts, broker.WithStore(store))
+	case "badger":
+		if *storageDir == "" {
+			log.Fatal("The badger storage backend requires -storage-dir")
+		}
+		var storeOpts []badger.Option
+		if sealer != nil {
+			storeOpts = append(storeOpts, badger.WithSealer(sealer))
+		}
+		store, err := badger.Open(*storageDir, storeOpts...)
+		if err != nil {
+			log.Fatalf("Failed to open storage: %v", err)
+		}
+		defer store.Close()
+		opts = append(opts, broker.WithStore(store))
+	default:
+		log.Fatalf("Unsupported storage backend %q, expected memory, file or badger", *storage)
+	}
+	var node *cluster.Node
+	if *nodeID != "" {
+		if *storage == "file" || *storage == "badger" {
+			log.Fatalf("A cluster node keeps the registrations in -cluster-dir, not the %s storage backend", *storage)
+		}
+		if *clusterDir == "" {
+			log.Fatal("A cluster node requires -cluster-dir")
+		}
+		var err error
+		node, err = cluster.NewNode(cluster.Config{ID: *nodeID, Peers: peerAddrs, Dir: *clusterDir, Sealer: sealer})
+		if err != nil {
+			log.Fatalf("Failed to start cluster node: %v", err)
+		}
+		defer node.Close()
+		opts = append(opts, broker.WithStore(node), broker.WithService(node.Register))
+		interceptors = append(interceptors, node.UnaryServerInterceptor())
+	}
+	if *caDir != "" {
+		if node != nil {
+			log.Fatal("-ca-dir does not support clusters: the nodes do not enroll with each other")
+		}
+		if cfg.TLS.Enabled {
+			log.Fatal("-ca-dir issues the broker's certificate; disable the [tls] section of the configuration")
+		}
+		authority, err := ca.Open(*caDir, "nfa-refbroker CA")
+		if err != nil {
+			log.Fatalf("Failed to open the built-in CA: %v", err)
+		}
+		tlsConfig, err := authority.ServerTLSConfig(strings.Split(*caHosts, ",")...)
+		if err != nil {
+			log.Fatalf("Failed to issue the broker's certificate: %v", err)
+		}
+		opts = append(opts,
+			broker.WithService(authority.Register),
+			broker.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
+			broker.WithServerOptions(grpc.ChainStreamInterceptor(authority.StreamInterceptor())))
+		// Identities are checked before anything else
+		interceptors = append([]grpc.UnaryServerInterceptor{authority.UnaryInterceptor()}, interceptors...)
+		adminServer.SetCertificateAuthority(authority)
+		log.Printf("Built-in CA %s", authority.Hash())
+	}
+	opts = append(opts, broker.WithServerOptions(grpc.ChainUnaryInterceptor(interceptors...)))
+	// Only the [tls] section of a configuration file enables TLS, never a
+	// profile, so there is a reloader. The certificate is read on every
+	// handshake, so reloads rotate it.
+	if reloader != nil && cfg.TLS.Enabled {
+		opts = append(opts, broker.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			GetCertificate: reloader.GetCertificate,
+			MinVersion:     tls.VersionTLS12,
+		})))
+	}
+	b := broker.NewEmbedded(opts...)
+	adminServer.SetRegistry(b, b.Hub())
+	catalogs.SetBroker(b)
+	adminServer.SetDeprecations(b.Deprecations())
+	adminServer.SetErrorReports(b.ErrorReports())
+	if *staticDir != "" {
+		ids, err := b.LoadStatic(*staticDir)
+		if err != nil {
+			log.Fatalf("Failed to load static contracts: %v", err)
+		}
+		log.Printf("Registered %d static providers", len(ids))
+	}
+	if *storage == "file" || *storage == "badger" || node != nil {
+		ids, err := b.Restore()
+		if err != nil {
+			log.Fatalf("Failed to restore registrations: %v", err)
+		}
+		log.Printf("Restored %d registrations", len(ids))
+	}
+	go slaRecorder.RunPeriodic(ctx, sla.DefaultBucket, webhook.SLOViolations(dispatcher))
+	go retainer.Run(ctx, time.Duration(cfg.Retention.IntervalSecs)*time.Second)
+	if node != nil {
+		go node.Run(ctx, b)
+		log.Printf("Cluster node %s of %d", *nodeID, len(peerAddrs))
+	}
+
+	lis, err := net.Listen("tcp", *listen)
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
+	go func() {
+		log.Printf("Intent broker listening on %s", lis.Addr())
+		if err := b.Serve(lis); err != nil {
+			log.Fatalf("Failed to serve: %v", err)
+		}
+	}()
+
+	var adminGRPC *grpc.Server
+	if *adminListen != "" {
+		adminLis, err := net.Listen("tcp", *adminListen)
+		if err != nil {
+			log.Fatalf("Failed to listen for the admin API: %v", err)
+		}
+		adminGRPC = grpc.NewServer()
+		adminServer.Register(adminGRPC)
+		go func() {
+			log.Printf("Admin API listening on %s", adminLis.Addr())
+			if err := adminGRPC.Serve(adminLis); err != nil {
+				log.Fatalf("Failed to serve the admin API: %v", err)
+			}
+		}()
+	}
+
+	if blobs != nil && *blobListen != "" {
+		mux := http.NewServeMux()
+		mux.Handle(blob.HTTPPrefix, blobs)
+		go func() {
+			log.Printf("Blob URLs served on %s", *blobListen)
+			if err := http.ListenAndServe(*blobListen, mux); err != nil {
+				log.Fatalf("Failed to serve blob URLs: %v", err)
+			}
+		}()
+	}
+
+	if metrics != nil {
+		go func() {
+			log.Printf("Metrics served on %s", *metricsListen)
+			if err := metrics.ListenAndServe(ctx, *metricsListen); err != nil && ctx.Err() == nil {
+				log.Fatalf("Failed to serve metrics: %v", err)
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	log.Println("Shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+	if adminGRPC != nil {
+		adminGRPC.Stop()
+	}
+	if err := b.Shutdown(shutdownCtx, &nfa_control_v1alpha.GoAway{Reason: "broker shutting down"}); err != nil {
+		log.Printf("Shutdown incomplete: %v", err)
+	}
+	log.Println("Broker stopped")
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/internal/cli"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/runtime"
+)
+
+func main() {
+	// 解析命令行参数
+	brokerAddr := flag.String("broker", "localhost:50051", "Broker address")
+	contractPath := flag.String("contract", "", "Path to intent contract YAML or JSON file")
+	servicePort := flag.Int("port", 0, "Service port (0 for auto)")
+	flagsPath := flag.String("feature-flags", "", "Path to feature flags YAML file")
+	labels := flag.String("labels", "", "Runtime labels for broker-pushed config, as key=value pairs separated by commas")
+	instanceKey := flag.String("instance-key", "", "Stable instance identity, e.g. a device serial, to keep the service ID across restarts")
+	takeOver := flag.Bool("take-over", false, "Replace a live registration holding the same stable service ID")
+	useTLS := flag.Bool("tls", false, "Connect to the broker over TLS, verified against the system roots or -ca-file")
+	caFile := flag.String("ca-file", "", "CA certificates to verify the broker with")
+	certFile := flag.String("cert-file", "", "Client certificate for mutual TLS with the broker")
+	keyFile := flag.String("key-file", "", "Private key of -cert-file")
+	enrollDir := flag.String("enroll-dir", "", "Directory keeping the client certificate issued by the broker's built-in CA; enrolls with -enroll-token-file when empty")
+	enrollTokenFile := flag.String("enroll-token-file", "", "File holding the one-time bootstrap token from nfactl ca token; defaults to $NFA_ENROLL_TOKEN")
+	brokerCAHash := flag.String("broker-ca-hash", "", "Hash of the broker CA to pin when enrolling, as printed by nfactl ca token")
+	tokenFile := flag.String("token-file", "", "File holding a bearer token for the broker; defaults to $NFA_AUTH_TOKEN")
+	debugTokenFile := flag.String("debug-token-file", "", "File holding the bearer token operators present to stream logs with nfactl logs; defaults to $NFA_DEBUG_TOKEN, the debug service is off without one")
+	connectTimeout := flag.Duration("connect-timeout", 30*time.Second, "How long to wait for the broker to become reachable")
+	heartbeatInterval := flag.Duration("heartbeat-interval", 0, "How often to send heartbeats to the broker, e.g. longer on low-power devices (0: the default, or as the broker sets)")
+	hostPressure := flag.Bool("advertise-host-pressure", true, "Advertise CPU and memory pressure with heartbeats, for the broker to route to less loaded replicas")
+	heartbeatJitter := flag.Float64("heartbeat-jitter", 0.1, "Fraction by which heartbeats are randomly sent early, so devices started together do not beat in lockstep")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 30*time.Second, "How long in-flight requests get to finish on shutdown")
+	concurrencyLimit := flag.Int("concurrency-limit", 0, "Run at most this many requests at a time, queueing the rest with interactive ones first (0 no limit)")
+	adaptiveConcurrency := flag.Bool("adaptive-concurrency", false, "Tune the concurrency limit to host CPU and memory pressure, up to -concurrency-limit (0: 4 per CPU)")
+	keepalive := runtime.DefaultKeepalive()
+	flag.DurationVar(&keepalive.Time, "keepalive-time", keepalive.Time, "How long a connection may be idle before a ping checks it (0 disables pings)")
+	flag.DurationVar(&keepalive.Timeout, "keepalive-timeout", keepalive.Timeout, "How long an unanswered ping waits before the connection is closed")
+	flag.DurationVar(&keepalive.MaxConnectionAge, "max-connection-age", keepalive.MaxConnectionAge, "Close service connections once they are this old (0 never)")
+	flag.DurationVar(&keepalive.MaxConnectionAgeGrace, "max-connection-age-grace", keepalive.MaxConnectionAgeGrace, "How long requests on a connection closed for its age may still take (0 no limit)")
+	flag.Parse()
+
+	// 检查必需参数
+	if *contractPath == "" {
+		log.Fatal("Contract path is required")
+	}
+	runtimeLabels, err := cli.ParsePairs(*labels)
+	if err != nil {
+		log.Fatalf("Invalid labels: %v", err)
+	}
+
+	// 收到终止信号时取消ctx，健康报告和控制流随之退出
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// 创建运行时实例
+	opts := []runtime.Option{runtime.WithKeepalive(keepalive)}
+	if *hostPressure {
+		opts = append(opts, runtime.WithLoadCollector(runtime.CollectHostPressure))
+	}
+	if *instanceKey != "" {
+		opts = append(opts, runtime.WithInstanceKey(*instanceKey))
+	}
+	if *takeOver {
+		opts = append(opts, runtime.WithTakeOver())
+	}
+	credentialOpts, err := brokerCredentials(*useTLS, *caFile, *certFile, *keyFile, *tokenFile)
+	if err != nil {
+		log.Fatalf("Invalid broker credentials: %v", err)
+	}
+	opts = append(opts, credentialOpts...)
+	enrollOpts, err := enrollment(*enrollDir, *enrollTokenFile, *brokerCAHash, *certFile != "")
+	if err != nil {
+		log.Fatalf("Invalid enrollment: %v", err)
+	}
+	opts = append(opts, enrollOpts...)
+	debugOpts, err := debugService(*debugTokenFile)
+	if err != nil {
+		log.Fatalf("Invalid debug token: %v", err)
+	}
+	opts = append(opts, debugOpts...)
+	rt := runtime.NewIntentRuntime(*brokerAddr, opts...)
+	if *flagsPath != "" {
+		if err := rt.LoadFeatureFlags(*flagsPath); err != nil {
+			log.Fatalf("Failed to load feature flags: %v", err)
+		}
+	}
+	
+	// 连接到Broker，等待其可达，超时或收到终止信号时退出
+	connectCtx, cancelConnect := context.WithTimeout(ctx, *connectTimeout)
+	err = rt.ConnectCtx(connectCtx)
+	cancelConnect()
+	if err != nil {
+		log.Fatalf("Failed to connect to broker: %v", err)
+	}
+	defer rt.Close()
+
+	// 端点类型为exec时，在沙箱中运行契约的本地命令处理广播的意图，须在打开控制流前设置
+	intentContract, err := contract.LoadFile(*contractPath)
+	if err != nil {
+		log.Fatalf("Failed to load contract: %v", err)
+	}
+	if strings.EqualFold(intentContract.Spec.Implementation.Endpoint.Type, "exec") {
+		handler, err := runtime.ExecHandler(intentContract)
+		if err != nil {
+			log.Fatalf("Invalid exec endpoint: %v", err)
+		}
+		rt.OnBroadcast(handler)
+	}
+
+	// 先绑定端口再注册，-port 0 时由内核分配，Broker下发的端点须带实际端口
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", *servicePort))
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
+	if !strings.EqualFold(intentContract.Spec.Implementation.Endpoint.Type, "exec") {
+		port := lis.Addr().(*net.TCPAddr).Port
+		intentContract.Spec.Implementation.Endpoint.Port = &port
+	}
+
+	// 注册意图服务，收到终止信号时取消；重新注册时沿用带实际端口的契约
+	serviceID, err := rt.Register(ctx, intentContract)
+	if err != nil {
+		log.Fatalf("Failed to register service: %v", err)
+	}
+
+	log.Printf("Service registered with ID: %s", serviceID)
+
+	// 启动健康报告
+	go func() {
+		err := rt.StartHealthReporting(ctx,
+			runtime.WithHeartbeatInterval(*heartbeatInterval),
+			runtime.WithHeartbeatJitter(*heartbeatJitter))
+		if err != nil {
+			log.Printf("Health reporting stopped: %v", err)
+		}
+	}()
+
+	// Broker重启后自动重连并重新注册契约
+	rt.OnReRegister(func(id string) {
+		log.Printf("Service re-registered with ID: %s", id)
+	})
+	go rt.StartSupervisor(ctx)
+
+	// 打开控制流，接收Broker下发的配置
+	go func() {
+		if err := rt.StartControlStream(ctx, runtimeLabels); err != nil {
+			log.Printf("Control stream closed: %v", err)
+		}
+	}()
+
+	// 创建gRPC服务器，拒绝不匹配契约中任何意图模式的请求
+	serverOpts := []runtime.Option{runtime.WithServiceID(serviceID), runtime.WithCapabilities(rt), runtime.WithAdmission(intentContract), runtime.WithKeepalive(keepalive), concurrency(*concurrencyLimit, *adaptiveConcurrency)}
+	server := runtime.NewIntentServer(*servicePort, append(serverOpts, debugOpts...)...)
+	
+	// 这里可以注册服务实现
+	// 例如: translator.RegisterTranslatorServer(server, &translator.TranslatorService{})
+	
+	// 启动服务器，在注册前绑定的端口上提供服务
+	go func() {
+		log.Printf("Starting server on port %d", lis.Addr().(*net.TCPAddr).Port)
+		if err := server.Serve(lis); err != nil {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// 等待终止信号
+	<-ctx.Done()
+	log.Println("Shutting down...")
+	
+	// 优雅关闭：先从Broker注销，再等待处理中的请求完成
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancelShutdown()
+	if err := rt.Shutdown(shutdownCtx, server); err != nil {
+		log.Printf("Shutdown incomplete: %v", err)
+	}
+	
+	log.Println("Service stopped")
+}
+
+// concurrency 返回限制服务同时处理请求数的选项：固定上限，或按主机负载调整、不超过limit的上限
+func concurrency(limit int, adaptive bool) runtime.Option {
+	if adaptive {
+		return runtime.WithAdaptiveConcurrency(limit)
+	}
+	return runtime.WithConcurrencyLimit(limit)
+}
+
+// debugService 有调试令牌时返回选项：运行时日志写入缓冲区，意图服务器向持有令牌的运维工具提供调试服务
+func debugService(tokenFile string) ([]runtime.Option, error) {
+	token := os.Getenv("NFA_DEBUG_TOKEN")
+	if tokenFile != "" {
+		data, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token == "" {
+		return nil, nil
+	}
+	logs := runtime.NewLogBuffer(slog.NewTextHandler(os.Stderr, nil), 0)
+	// log.Printf的输出也进入缓冲区
+	slog.SetDefault(slog.New(logs))
+	return []runtime.Option{
+		runtime.WithLogger(slog.New(logs)),
+		runtime.WithDebug(logs, runtime.TokenAuthorizer(token)),
+	}, nil
+}
+
+// enrollment 配置了证书目录时返回向Broker内置CA注册并自动续期证书的选项
+func enrollment(dir, tokenFile, caHash string, mtls bool) ([]runtime.Option, error) {
+	if dir == "" {
+		if tokenFile != "" || caHash != "" {
+			return nil, fmt.Errorf("-enroll-token-file and -broker-ca-hash require -enroll-dir")
+		}
+		return nil, nil
+	}
+	if mtls {
+		return nil, fmt.Errorf("-enroll-dir and -cert-file are mutually exclusive")
+	}
+	token := os.Getenv("NFA_ENROLL_TOKEN")
+	if tokenFile != "" {
+		data, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	return []runtime.Option{runtime.WithEnrollment(dir, token, caHash)}, nil
+}
+
+// brokerCredentials 根据命令行参数构造连接Broker的凭据选项
+func brokerCredentials(useTLS bool, caFile, certFile, keyFile, tokenFile string) ([]runtime.Option, error) {
+	var opts []runtime.Option
+	switch {
+	case certFile != "" || keyFile != "":
+		if certFile == "" || keyFile == "" {
+			return nil, fmt.Errorf("-cert-file and -key-file must be given together")
+		}
+		opts = append(opts, runtime.WithMTLS(certFile, keyFile, caFile))
+	case useTLS || caFile != "":
+		config := &tls.Config{MinVersion: tls.VersionTLS12}
+		if caFile != "" {
+			pem, err := os.ReadFile(caFile)
+			if err != nil {
+				return nil, err
+			}
+			config.RootCAs = x509.NewCertPool()
+			if !config.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in %s", caFile)
+			}
+		}
+		opts = append(opts, runtime.WithTLS(config))
+	}
+
+	token := os.Getenv("NFA_AUTH_TOKEN")
+	if tokenFile != "" {
+		data, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token != "" {
+		opts = append(opts, runtime.WithTokenAuth(token))
+	}
+	return opts, nil
+}
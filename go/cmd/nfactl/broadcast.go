@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/internal/cli"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/fulfillment"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/provenance"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func runBroadcast(args []string) error {
+	fs := flag.NewFlagSet("broadcast", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Broker address")
+	selector := fs.String("selector", "", "Runtime labels to match, as key=value[,key=value]; empty matches every runtime")
+	params := fs.String("params", "", "Intent parameters, as key=value[,key=value]")
+	payload := fs.String("payload", "", "Intent payload")
+	timeout := fs.Duration("timeout", 10*time.Second, "How long to wait for targets to report")
+	debug := fs.Bool("debug", false, "Show where each target's parameter values came from and how it fulfilled the intent")
+	fs.Usage = func() {
+		fmt.Println("Usage: nfactl broadcast [-addr host:port] [-selector k=v,...] [-params k=v,...] [-payload data] [-debug] action")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one action")
+	}
+	selectorLabels, err := cli.ParsePairs(*selector)
+	if err != nil {
+		return fmt.Errorf("invalid -selector: %w", err)
+	}
+	parameters, err := cli.ParsePairs(*params)
+	if err != nil {
+		return fmt.Errorf("invalid -params: %w", err)
+	}
+
+	conn, err := grpc.Dial(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to broker: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout+5*time.Second)
+	defer cancel()
+	resp, err := nfa_control_v1alpha.NewBroadcastServiceClient(conn).Broadcast(ctx, &nfa_control_v1alpha.BroadcastRequest{
+		Selector: selectorLabels,
+		Intent: &nfa_control_v1alpha.Invoke{
+			Action:     fs.Arg(0),
+			Parameters: parameters,
+			Payload:    []byte(*payload),
+			Debug:      *debug,
+		},
+		TimeoutSecs: uint32(timeout.Seconds()),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to broadcast intent: %w", err)
+	}
+
+	if len(resp.Targets) == 0 {
+		fmt.Println("No runtimes matched the selector")
+		return nil
+	}
+	if failed := printTargets(resp.Targets, *debug); failed > 0 {
+		return fmt.Errorf("%d of %d targets did not succeed", failed, len(resp.Targets))
+	}
+	return nil
+}
+
+// printTargets prints the state and output of every target of a broadcast,
+// with the provenance of its parameters and, for debug intents, how it was
+// fulfilled, and returns how many did not succeed
+func printTargets(targets []*nfa_control_v1alpha.TargetStatus, debug bool) int {
+	failed := 0
+	for _, target := range targets {
+		state := strings.ToLower(strings.TrimPrefix(target.State.String(), "TARGET_STATE_"))
+		detail := target.Error
+		if detail == "" {
+			detail = string(target.Output)
+		}
+		fmt.Printf("%-32s %-12s %s\n", target.RuntimeId, state, detail)
+		for _, line := range provenance.FromProto(target.Provenance).Strings() {
+			fmt.Printf("    %s\n", line)
+		}
+		if debug && target.Fulfillment != nil {
+			f := fulfillment.FromProto(target.Fulfillment)
+			fmt.Printf("    fulfilled by %s: processing %s, queued %s, %d hops\n",
+				f.ServiceID, f.ProcessingTime, f.QueueTime, f.Hops)
+		}
+		if target.State != nfa_control_v1alpha.TargetState_TARGET_STATE_SUCCEEDED {
+			failed++
+		}
+	}
+	return failed
+}
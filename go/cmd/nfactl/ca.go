@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+)
+
+const caUsage = `Usage: nfactl ca <command> [arguments]
+
+Commands:
+  token   Create a one-time bootstrap token enrolling a device with the broker's CA
+  revoke  Stop renewing a device's certificates and refuse its calls
+`
+
+func runCA(args []string) error {
+	if len(args) < 1 {
+		fmt.Print(caUsage)
+		return fmt.Errorf("missing ca command")
+	}
+	switch args[0] {
+	case "token":
+		return runCAToken(args[1:])
+	case "revoke":
+		return runCARevoke(args[1:])
+	default:
+		fmt.Print(caUsage)
+		return fmt.Errorf("unknown ca command %q", args[0])
+	}
+}
+
+func runCAToken(args []string) error {
+	fs := flag.NewFlagSet("ca token", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Admin API address of the broker")
+	tenant := fs.String("tenant", "", "Tenant of the device (required)")
+	device := fs.String("device", "", "Device the token enrolls; empty lets the runtime name itself after its host")
+	ttl := fs.Duration("ttl", time.Hour, "How long the token can be used")
+	fs.Parse(args)
+	if *tenant == "" {
+		fs.Usage()
+		return fmt.Errorf("-tenant is required")
+	}
+
+	return withAdmin(*addr, func(ctx context.Context, client nfa_admin_v1alpha.AdminServiceClient) error {
+		resp, err := client.CreateBootstrapToken(ctx, &nfa_admin_v1alpha.CreateBootstrapTokenRequest{
+			Tenant:  *tenant,
+			Device:  *device,
+			TtlSecs: uint32(ttl.Seconds()),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create bootstrap token: %w", err)
+		}
+		fmt.Printf("Token:   %s\n", resp.Token)
+		fmt.Printf("CA hash: %s\n", resp.CaHash)
+		fmt.Printf("Expires: %s\n", time.Unix(resp.ExpiresUnix, 0).Format(time.DateTime))
+		fmt.Printf("\nnfa-runtime -enroll-dir <dir> -broker-ca-hash %s with the token in $NFA_ENROLL_TOKEN\n", resp.CaHash)
+		return nil
+	})
+}
+
+func runCARevoke(args []string) error {
+	fs := flag.NewFlagSet("ca revoke", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Admin API address of the broker")
+	tenant := fs.String("tenant", "", "Tenant of the device (required)")
+	device := fs.String("device", "", "Device to revoke (required)")
+	fs.Parse(args)
+	if *tenant == "" || *device == "" {
+		fs.Usage()
+		return fmt.Errorf("-tenant and -device are required")
+	}
+
+	return withAdmin(*addr, func(ctx context.Context, client nfa_admin_v1alpha.AdminServiceClient) error {
+		if _, err := client.RevokeDevice(ctx, &nfa_admin_v1alpha.RevokeDeviceRequest{Tenant: *tenant, Device: *device}); err != nil {
+			return fmt.Errorf("failed to revoke device: %w", err)
+		}
+		fmt.Printf("Revoked %s/%s\n", *tenant, *device)
+		return nil
+	})
+}
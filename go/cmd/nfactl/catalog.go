@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/catalog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const catalogUsage = `Usage: nfactl catalog <command> [arguments]
+
+Commands:
+  export  Save a namespace's signed intent catalog to a bundle file
+  import  Show the changes a bundle makes to a broker and apply them
+`
+
+func runCatalog(args []string) error {
+	if len(args) < 1 {
+		fmt.Print(catalogUsage)
+		return fmt.Errorf("missing catalog command")
+	}
+
+	fs := flag.NewFlagSet("catalog "+args[0], flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Broker address")
+	switch args[0] {
+	case "export":
+		namespace := fs.String("namespace", "", "Namespace to export")
+		fs.Usage = func() {
+			fmt.Println("Usage: nfactl catalog export [-addr host:port] -namespace ns <bundle.json>")
+			fs.PrintDefaults()
+		}
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 || *namespace == "" {
+			fs.Usage()
+			return fmt.Errorf("expected a namespace and a bundle path")
+		}
+		return withCatalog(*addr, func(ctx context.Context, client *catalog.Client) error {
+			bundle, err := client.Export(ctx, *namespace)
+			if err != nil {
+				return err
+			}
+			if err := catalog.WriteBundle(fs.Arg(0), bundle); err != nil {
+				return err
+			}
+			fmt.Printf("Exported catalog of %s to %s (signed by %s)\n", *namespace, fs.Arg(0), bundle.KeyId)
+			return nil
+		})
+
+	case "import":
+		namespace := fs.String("namespace", "", "Namespace to import into (default the bundle's namespace)")
+		dryRun := fs.Bool("dry-run", false, "Only show the changes")
+		fs.Usage = func() {
+			fmt.Println("Usage: nfactl catalog import [-addr host:port] [-namespace ns] [-dry-run] <bundle.json>")
+			fs.PrintDefaults()
+		}
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			fs.Usage()
+			return fmt.Errorf("expected a bundle path")
+		}
+		bundle, err := catalog.ReadBundle(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		return withCatalog(*addr, func(ctx context.Context, client *catalog.Client) error {
+			resp, err := client.Import(ctx, bundle, *namespace, *dryRun)
+			if err != nil {
+				return err
+			}
+			if len(resp.Changes) == 0 {
+				fmt.Println("No changes")
+			}
+			for _, change := range resp.Changes {
+				kind := strings.ToLower(strings.TrimPrefix(change.Kind.String(), "CHANGE_KIND_"))
+				fmt.Printf("%-9s %-40s %s\n", kind, change.Item, change.Detail)
+			}
+			if resp.Applied {
+				fmt.Printf("Applied %d changes from bundle signed by %s\n", len(resp.Changes), resp.KeyId)
+			} else {
+				fmt.Println("Dry run, nothing applied")
+			}
+			return nil
+		})
+
+	default:
+		fmt.Print(catalogUsage)
+		return fmt.Errorf("unknown catalog command %q", args[0])
+	}
+}
+
+func withCatalog(addr string, fn func(ctx context.Context, client *catalog.Client) error) error {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to broker: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return fn(ctx, catalog.NewClient(conn))
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	nfa_cluster_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/cluster/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func runCluster(args []string) error {
+	fs := flag.NewFlagSet("cluster", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Address of a node of the broker cluster")
+	fs.Usage = func() {
+		fmt.Println("Usage: nfactl cluster [-addr host:port]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	conn, err := grpc.Dial(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to broker: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	st, err := nfa_cluster_v1alpha.NewClusterServiceClient(conn).GetStatus(ctx, &nfa_cluster_v1alpha.GetStatusRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get cluster status: %w", err)
+	}
+
+	leader := st.LeaderId
+	if leader == "" {
+		leader = "(election in progress)"
+	}
+	fmt.Printf("Node %s is %s in term %d, leader %s\n", st.NodeId, st.Role, st.Term, leader)
+	fmt.Printf("Log: last index %d, committed %d, snapshot %d\n\n", st.LastLogIndex, st.CommitIndex, st.SnapshotIndex)
+	fmt.Printf("%-12s %-24s %-8s %s\n", "MEMBER", "ADDRESS", "ROLE", "MATCHED")
+	for _, m := range st.Members {
+		role := "follower"
+		if m.Id == st.LeaderId {
+			role = "leader"
+		}
+		// Only the leader knows how far each member replicated
+		matched := "-"
+		if st.Role == "LEADER" {
+			matched = fmt.Sprint(m.MatchIndex)
+		}
+		name := m.Id
+		if m.Self {
+			name += "*"
+		}
+		fmt.Printf("%-12s %-24s %-8s %s\n", name, m.Address, role, matched)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/config"
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func runConfig(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: nfactl config <check|effective> [arguments]")
+	}
+	switch args[0] {
+	case "check":
+		return configCheck(args[1:])
+	case "effective":
+		return configEffective(args[1:])
+	default:
+		return fmt.Errorf("unknown config subcommand %q", args[0])
+	}
+}
+
+// configCheck validates each file and reports every problem found
+func configCheck(args []string) error {
+	fs := flag.NewFlagSet("config check", flag.ExitOnError)
+	profile := fs.String("profile", "", "Profile whose defaults apply (defaults to $NFA_PROFILE)")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("at least one config file is required")
+	}
+
+	selected := config.Profile(*profile)
+	if selected == "" {
+		active, err := config.ActiveProfile()
+		if err != nil {
+			return err
+		}
+		selected = active
+	}
+
+	failed := 0
+	for _, path := range fs.Args() {
+		err := config.CheckFile(path, selected)
+		if err == nil {
+			fmt.Printf("%s: OK\n", path)
+			continue
+		}
+		failed++
+		var verrs config.ValidationErrors
+		if !errors.As(err, &verrs) {
+			fmt.Printf("%s: %v\n", path, err)
+			continue
+		}
+		fmt.Printf("%s: %d problem(s)\n", path, len(verrs))
+		for _, verr := range verrs {
+			fmt.Printf("  - %v\n", verr)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d config file(s) are invalid", failed, fs.NArg())
+	}
+	return nil
+}
+
+// configEffective prints the effective configuration of a running process
+// (-addr) or computes it locally from a file, the environment and -set overrides
+func configEffective(args []string) error {
+	fs := flag.NewFlagSet("config effective", flag.ExitOnError)
+	addr := fs.String("addr", "", "Admin API address of a running broker or gateway")
+	file := fs.String("file", "", "Config file to merge when computing locally")
+	profile := fs.String("profile", "", "Profile whose defaults apply (defaults to $NFA_PROFILE)")
+	var overrides keyValues
+	fs.Var(&overrides, "set", "Override a key, e.g. -set logging.level=debug (repeatable)")
+	fs.Parse(args)
+
+	if *addr != "" {
+		return remoteEffective(*addr)
+	}
+
+	eff, err := config.LoadEffective(config.LoadOptions{
+		Profile:   config.Profile(*profile),
+		File:      *file,
+		Overrides: overrides,
+	})
+	if err != nil {
+		return err
+	}
+	dump, err := eff.Dump()
+	if err != nil {
+		return err
+	}
+	fmt.Print(dump)
+	return nil
+}
+
+func remoteEffective(addr string) error {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to admin API: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := nfa_admin_v1alpha.NewAdminServiceClient(conn).GetEffectiveConfig(ctx, &nfa_admin_v1alpha.GetEffectiveConfigRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get effective config: %w", err)
+	}
+
+	if resp.Profile != "" {
+		fmt.Printf("# profile: %s\n", resp.Profile)
+	}
+	for _, v := range resp.Values {
+		fmt.Printf("%s = %s  # %s\n", v.Key, v.Value, v.Source)
+	}
+	return nil
+}
+
+// keyValues collects repeated key=value flags
+type keyValues map[string]string
+
+func (kv *keyValues) String() string {
+	pairs := make([]string, 0, len(*kv))
+	for k, v := range *kv {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (kv *keyValues) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	if *kv == nil {
+		*kv = make(keyValues)
+	}
+	(*kv)[key] = val
+	return nil
+}
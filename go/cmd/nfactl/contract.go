@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/catalog"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract/contracttest"
+	nfa_catalog_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/catalog/v1alpha"
+	"google.golang.org/protobuf/proto"
+)
+
+const contractUsage = `Usage: nfactl contract <command> [arguments]
+
+Commands:
+  docs      Write the reference documentation of a contract, with its examples, as Markdown
+  export    Write the contracts registered in a namespace as YAML
+  fixtures  Generate a Go test table of valid and invalid requests from a contract
+  schema    Write the JSON Schema of the contract format for editors and CI validators
+`
+
+func runContract(args []string) error {
+	if len(args) < 1 {
+		fmt.Print(contractUsage)
+		return fmt.Errorf("missing contract command")
+	}
+	switch args[0] {
+	case "docs":
+		return runContractDocs(args[1:])
+	case "export":
+		return runContractExport(args[1:])
+	case "fixtures":
+		return runContractFixtures(args[1:])
+	case "schema":
+		return runContractSchema(args[1:])
+	default:
+		fmt.Print(contractUsage)
+		return fmt.Errorf("unknown contract command %q", args[0])
+	}
+}
+
+func runContractDocs(args []string) error {
+	fs := flag.NewFlagSet("contract docs", flag.ExitOnError)
+	output := fs.String("o", "", "Write the documentation to a file instead of stdout")
+	fs.Usage = func() {
+		fmt.Println("Usage: nfactl contract docs [-o file.md] contract.yaml")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one contract file")
+	}
+
+	intentContract, err := contract.LoadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := intentContract.Validate(); err != nil {
+		return fmt.Errorf("invalid contract %s: %w", fs.Arg(0), err)
+	}
+	docs := contract.Markdown(intentContract)
+	if *output == "" {
+		_, err = os.Stdout.Write(docs)
+		return err
+	}
+	return os.WriteFile(*output, docs, 0o644)
+}
+
+func runContractFixtures(args []string) error {
+	fs := flag.NewFlagSet("contract fixtures", flag.ExitOnError)
+	path := fs.String("contract", "", "Intent contract YAML or JSON file (required)")
+	pkg := fs.String("package", "", "Package of the generated file (required)")
+	name := fs.String("var", "contractCases", "Name of the generated variable")
+	output := fs.String("o", "", "Write the cases to a file instead of stdout")
+	fs.Usage = func() {
+		fmt.Println("Usage: nfactl contract fixtures -contract file.yaml -package name [-var contractCases] [-o cases_test.go]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *path == "" || *pkg == "" {
+		fs.Usage()
+		return fmt.Errorf("-contract and -package are required")
+	}
+
+	intentContract, err := contract.LoadFile(*path)
+	if err != nil {
+		return err
+	}
+	if err := intentContract.Validate(); err != nil {
+		return fmt.Errorf("invalid contract %s: %w", *path, err)
+	}
+
+	var buf bytes.Buffer
+	cases := contracttest.Cases(intentContract)
+	if err := contracttest.WriteGo(&buf, cases, contracttest.GoOptions{
+		Package: *pkg,
+		Var:     *name,
+		Source:  filepath.Base(*path),
+	}); err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(*output, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write cases: %w", err)
+	}
+	fmt.Printf("Wrote %d cases to %s\n", len(cases), *output)
+	return nil
+}
+
+// runContractSchema writes the JSON Schema of contracts, to be referenced
+// from editors or checked in next to contracts and regenerated on upgrades
+func runContractSchema(args []string) error {
+	fs := flag.NewFlagSet("contract schema", flag.ExitOnError)
+	version := fs.String("version", "", "Contract schema version, e.g. v1alpha; empty covers every version")
+	output := fs.String("o", "", "Write the schema to a file instead of stdout")
+	fs.Usage = func() {
+		fmt.Println("Usage: nfactl contract schema [-version v1alpha|v1beta] [-o intent-contract.schema.json]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	schema, err := contract.JSONSchema(*version)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(schema)
+		return err
+	}
+	if err := os.WriteFile(*output, schema, 0o644); err != nil {
+		return fmt.Errorf("failed to write schema: %w", err)
+	}
+	fmt.Printf("Wrote schema to %s\n", *output)
+	return nil
+}
+
+func runContractExport(args []string) error {
+	fs := flag.NewFlagSet("contract export", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Broker address")
+	namespace := fs.String("namespace", "", "Namespace whose contracts to export (required)")
+	name := fs.String("name", "", "Only export the contract with this name")
+	dir := fs.String("o", "", "Write each contract to <dir>/<name>.intent.yaml instead of stdout")
+	fs.Usage = func() {
+		fmt.Println("Usage: nfactl contract export [-addr host:port] -namespace ns [-name contract] [-o dir]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *namespace == "" {
+		fs.Usage()
+		return fmt.Errorf("-namespace is required")
+	}
+
+	return withCatalog(*addr, func(ctx context.Context, client *catalog.Client) error {
+		bundle, err := client.Export(ctx, *namespace)
+		if err != nil {
+			return err
+		}
+		// Only the contracts are shown, so the signature is not checked
+		var cat nfa_catalog_v1alpha.Catalog
+		if err := proto.Unmarshal(bundle.Catalog, &cat); err != nil {
+			return fmt.Errorf("failed to decode catalog: %w", err)
+		}
+
+		exported := 0
+		for _, pb := range cat.Contracts {
+			if *name != "" && pb.GetMetadata().GetName() != *name {
+				continue
+			}
+			c, err := contract.FromProto(pb)
+			if err != nil {
+				return fmt.Errorf("contract %s: %w", pb.GetMetadata().GetName(), err)
+			}
+			data, err := contract.MarshalYAML(c)
+			if err != nil {
+				return err
+			}
+			exported++
+			if *dir == "" {
+				if exported > 1 {
+					fmt.Println("---")
+				}
+				os.Stdout.Write(data)
+				continue
+			}
+			path := filepath.Join(*dir, c.Metadata.Name+".intent.yaml")
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				return fmt.Errorf("failed to write contract: %w", err)
+			}
+			fmt.Printf("Wrote %s\n", path)
+		}
+		if exported == 0 && *name != "" {
+			return fmt.Errorf("no contract %q registered in namespace %s", *name, *namespace)
+		}
+		return nil
+	})
+}
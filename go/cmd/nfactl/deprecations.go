@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/deprecation"
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func runDeprecations(args []string) error {
+	fs := flag.NewFlagSet("deprecations", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Admin API address of the broker")
+	alias := fs.String("alias", "", "Report only this deprecated action name")
+	since := fs.Duration("since", deprecation.DefaultPeriod, "Length of the reporting period, ending now")
+	format := fs.String("format", "table", "Output format: table, json or csv")
+	output := fs.String("o", "", "Write the report to a file instead of stdout")
+	fs.Usage = func() {
+		fmt.Println("Usage: nfactl deprecations [-addr host:port] [-alias name] [-since 168h] [-format table|json|csv] [-o file]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *format != "table" && *format != "json" && *format != "csv" {
+		fs.Usage()
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	conn, err := grpc.Dial(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to admin API: %w", err)
+	}
+	defer conn.Close()
+	client := nfa_admin_v1alpha.NewAdminServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	end := time.Now()
+	report, err := client.GetDeprecationReport(ctx, &nfa_admin_v1alpha.GetDeprecationReportRequest{
+		Alias:     *alias,
+		StartUnix: end.Add(-*since).Unix(),
+		EndUnix:   end.Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get deprecation report: %w", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("failed to create report file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "json":
+		data, err := protojson.MarshalOptions{Multiline: true}.Marshal(report)
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	case "csv":
+		return deprecation.WriteCSV(w, report)
+	}
+
+	if len(report.Actions) == 0 {
+		fmt.Fprintln(w, "No calls to deprecated actions in the period")
+		return nil
+	}
+	for _, a := range report.Actions {
+		fmt.Fprintf(w, "%s -> %s: %d calls, %.2f/min, last seen %s\n", a.Alias, a.Action, a.Calls,
+			a.CallsPerMinute, time.Unix(a.LastSeenUnix, 0).Format(time.RFC3339))
+		fmt.Fprintf(w, "    %-40s %8s %9s  %s\n", "CONSUMER", "CALLS", "RATE", "LAST SEEN")
+		for _, c := range a.Consumers {
+			fmt.Fprintf(w, "    %-40s %8d %7.2f/m  %s\n", c.Consumer, c.Calls, c.CallsPerMinute,
+				time.Unix(c.LastSeenUnix, 0).Format(time.RFC3339))
+		}
+	}
+	return nil
+}
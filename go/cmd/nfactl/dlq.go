@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	nfa_pubsub_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/pubsub/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const dlqUsage = `Usage: nfactl dlq <command> [arguments]
+
+Commands:
+  list     List dead-lettered events
+  requeue  Deliver dead-lettered events to their subscription again
+  purge    Discard dead-lettered events
+`
+
+func runDLQ(args []string) error {
+	if len(args) < 1 {
+		fmt.Print(dlqUsage)
+		return fmt.Errorf("missing dlq command")
+	}
+	switch args[0] {
+	case "list":
+		return runDLQList(args[1:])
+	case "requeue":
+		return runDLQRequeue(args[1:])
+	case "purge":
+		return runDLQPurge(args[1:])
+	default:
+		fmt.Print(dlqUsage)
+		return fmt.Errorf("unknown dlq command %q", args[0])
+	}
+}
+
+func runDLQList(args []string) error {
+	fs := flag.NewFlagSet("dlq list", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Broker address")
+	subscription := fs.String("subscription", "", "Only list dead letters of this subscription")
+	fs.Parse(args)
+
+	return withPubSub(*addr, func(ctx context.Context, client nfa_pubsub_v1alpha.PubSubServiceClient) error {
+		resp, err := client.ListDeadLetters(ctx, &nfa_pubsub_v1alpha.ListDeadLettersRequest{
+			Subscription: *subscription,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list dead letters: %w", err)
+		}
+		if len(resp.DeadLetters) == 0 {
+			fmt.Println("No dead letters")
+			return nil
+		}
+		for _, dl := range resp.DeadLetters {
+			fmt.Printf("%-32s %-28s %s  %s\n",
+				dl.Id, dl.Event.Topic, dl.DeadLetteredTime.AsTime().Format(time.RFC3339), dl.Reason)
+		}
+		return nil
+	})
+}
+
+func runDLQRequeue(args []string) error {
+	fs := flag.NewFlagSet("dlq requeue", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Broker address")
+	subscription := fs.String("subscription", "", "Subscription whose dead letters are requeued (required)")
+	fs.Usage = func() {
+		fmt.Println("Usage: nfactl dlq requeue -subscription name [-addr host:port] [id...]")
+		fmt.Println("Without ids every dead letter of the subscription is requeued.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *subscription == "" {
+		fs.Usage()
+		return fmt.Errorf("-subscription is required")
+	}
+
+	return withPubSub(*addr, func(ctx context.Context, client nfa_pubsub_v1alpha.PubSubServiceClient) error {
+		resp, err := client.RequeueDeadLetters(ctx, &nfa_pubsub_v1alpha.RequeueDeadLettersRequest{
+			Subscription: *subscription,
+			Ids:          fs.Args(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to requeue dead letters: %w", err)
+		}
+		fmt.Printf("Requeued %d dead letters\n", resp.Requeued)
+		return nil
+	})
+}
+
+func runDLQPurge(args []string) error {
+	fs := flag.NewFlagSet("dlq purge", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Broker address")
+	subscription := fs.String("subscription", "", "Subscription whose dead letters are purged (required)")
+	fs.Usage = func() {
+		fmt.Println("Usage: nfactl dlq purge -subscription name [-addr host:port] [id...]")
+		fmt.Println("Without ids every dead letter of the subscription is purged.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *subscription == "" {
+		fs.Usage()
+		return fmt.Errorf("-subscription is required")
+	}
+
+	return withPubSub(*addr, func(ctx context.Context, client nfa_pubsub_v1alpha.PubSubServiceClient) error {
+		resp, err := client.PurgeDeadLetters(ctx, &nfa_pubsub_v1alpha.PurgeDeadLettersRequest{
+			Subscription: *subscription,
+			Ids:          fs.Args(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to purge dead letters: %w", err)
+		}
+		fmt.Printf("Purged %d dead letters\n", resp.Purged)
+		return nil
+	})
+}
+
+func withPubSub(addr string, fn func(ctx context.Context, client nfa_pubsub_v1alpha.PubSubServiceClient) error) error {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to broker: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return fn(ctx, nfa_pubsub_v1alpha.NewPubSubServiceClient(conn))
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func runErrors(args []string) error {
+	fs := flag.NewFlagSet("errors", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Admin API address of the broker")
+	service := fs.String("service", "", "Report only the errors of this service ID")
+	since := fs.Duration("since", 0, "Report only errors seen within this duration; 0 reports all the broker retains")
+	format := fs.String("format", "table", "Output format: table or json")
+	fs.Usage = func() {
+		fmt.Println("Usage: nfactl errors [-addr host:port] [-service id] [-since 1h] [-format table|json]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *format != "table" && *format != "json" {
+		fs.Usage()
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	conn, err := grpc.Dial(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to admin API: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req := &nfa_admin_v1alpha.GetErrorReportRequest{ServiceId: *service}
+	if *since > 0 {
+		req.SinceUnix = time.Now().Add(-*since).Unix()
+	}
+	report, err := nfa_admin_v1alpha.NewAdminServiceClient(conn).GetErrorReport(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to get error report: %w", err)
+	}
+
+	if *format == "json" {
+		data, err := protojson.MarshalOptions{Multiline: true}.Marshal(report)
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+		_, err = os.Stdout.Write(append(data, '\n'))
+		return err
+	}
+
+	if len(report.Errors) == 0 {
+		fmt.Println("No errors reported")
+	} else {
+		fmt.Printf("%-32s %-24s %-18s %8s  %-20s %s\n", "SERVICE", "ACTION", "CODE", "COUNT", "LAST SEEN", "SAMPLE")
+		for _, e := range report.Errors {
+			fmt.Printf("%-32s %-24s %-18s %8d  %-20s %s\n", e.ServiceId, e.Action, e.Code, e.Count,
+				time.Unix(e.LastSeenUnix, 0).Format(time.RFC3339), e.SampleMessage)
+		}
+	}
+	if report.Dropped > 0 {
+		fmt.Printf("\n%d errors of further kinds were counted but not summarized\n", report.Dropped)
+	}
+	return nil
+}
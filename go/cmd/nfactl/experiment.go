@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/experiment"
+	nfa_analytics_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/analytics/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const experimentUsage = `Usage: nfactl experiment <command> [arguments]
+
+Commands:
+  create   Start an experiment defined in a JSON file
+  list     List experiments and their state
+  stop     End a running experiment
+  results  Compare the metrics of an experiment's variants
+`
+
+func runExperiment(args []string) error {
+	if len(args) < 1 {
+		fmt.Print(experimentUsage)
+		return fmt.Errorf("missing experiment command")
+	}
+
+	fs := flag.NewFlagSet("experiment "+args[0], flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Broker address")
+	switch args[0] {
+	case "create":
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			fmt.Println("Usage: nfactl experiment create [-addr host:port] <experiment.json>")
+			return fmt.Errorf("expected an experiment file")
+		}
+		data, err := os.ReadFile(fs.Arg(0))
+		if err != nil {
+			return fmt.Errorf("failed to read experiment: %w", err)
+		}
+		exp := &nfa_analytics_v1alpha.Experiment{}
+		if err := protojson.Unmarshal(data, exp); err != nil {
+			return fmt.Errorf("failed to parse experiment: %w", err)
+		}
+		return withAnalytics(*addr, func(ctx context.Context, client *experiment.Client) error {
+			created, err := client.Create(ctx, exp)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Started experiment %s with %d variants\n", created.Name, len(created.Variants))
+			return nil
+		})
+
+	case "list":
+		fs.Parse(args[1:])
+		return withAnalytics(*addr, func(ctx context.Context, client *experiment.Client) error {
+			experiments, err := client.List(ctx)
+			if err != nil {
+				return err
+			}
+			if len(experiments) == 0 {
+				fmt.Println("No experiments")
+			}
+			for _, exp := range experiments {
+				variants := make([]string, len(exp.Variants))
+				for i, v := range exp.Variants {
+					variants[i] = v.Name
+				}
+				fmt.Printf("%-24s %-10s %s  %s\n", exp.Name, stateName(exp.State),
+					exp.StartTime.AsTime().Format(time.RFC3339), strings.Join(variants, ","))
+			}
+			return nil
+		})
+
+	case "stop":
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			fmt.Println("Usage: nfactl experiment stop [-addr host:port] <name>")
+			return fmt.Errorf("expected an experiment name")
+		}
+		return withAnalytics(*addr, func(ctx context.Context, client *experiment.Client) error {
+			exp, err := client.Stop(ctx, fs.Arg(0))
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Experiment %s is %s\n", exp.Name, stateName(exp.State))
+			return nil
+		})
+
+	case "results":
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			fmt.Println("Usage: nfactl experiment results [-addr host:port] <name>")
+			return fmt.Errorf("expected an experiment name")
+		}
+		return withAnalytics(*addr, func(ctx context.Context, client *experiment.Client) error {
+			results, err := client.Results(ctx, fs.Arg(0))
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Experiment %s (%s)\n", results.Experiment.Name, stateName(results.Experiment.State))
+			for _, v := range results.Variants {
+				fmt.Printf("  %-16s %d assignments\n", v.Variant, v.Assignments)
+				for _, m := range v.Metrics {
+					fmt.Printf("    %-14s n=%-6d mean=%-10.3f min=%-10.3f max=%.3f\n", m.Name, m.Count, m.Mean, m.Min, m.Max)
+				}
+			}
+			return nil
+		})
+
+	default:
+		fmt.Print(experimentUsage)
+		return fmt.Errorf("unknown experiment command %q", args[0])
+	}
+}
+
+func stateName(state nfa_analytics_v1alpha.ExperimentState) string {
+	return strings.ToLower(strings.TrimPrefix(state.String(), "EXPERIMENT_STATE_"))
+}
+
+func withAnalytics(addr string, fn func(ctx context.Context, client *experiment.Client) error) error {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to broker: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return fn(ctx, experiment.NewClient(conn))
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/internal/cli"
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const fleetUsage = `Usage: nfactl fleet <command> [arguments]
+
+Commands:
+  drain        Drain every provider of a namespace
+  purge-stale  Remove registrations of providers that stopped sending heartbeats
+  retag        Set or remove labels of every service matching a selector
+
+Every command accepts -dry-run to show what would change.
+`
+
+func runFleet(args []string) error {
+	if len(args) < 1 {
+		fmt.Print(fleetUsage)
+		return fmt.Errorf("missing fleet command")
+	}
+	switch args[0] {
+	case "drain":
+		return runFleetDrain(args[1:])
+	case "purge-stale":
+		return runFleetPurge(args[1:])
+	case "retag":
+		return runFleetRetag(args[1:])
+	default:
+		fmt.Print(fleetUsage)
+		return fmt.Errorf("unknown fleet command %q", args[0])
+	}
+}
+
+func runFleetDrain(args []string) error {
+	fs := flag.NewFlagSet("fleet drain", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Admin API address of the broker")
+	namespace := fs.String("namespace", "", "Namespace whose providers are drained (required)")
+	grace := fs.Duration("grace", 30*time.Second, "Time providers get to finish in-flight work")
+	reason := fs.String("reason", "", "Reason reported to the providers")
+	dryRun := fs.Bool("dry-run", false, "Show the providers that would be drained")
+	fs.Parse(args)
+	if *namespace == "" {
+		fs.Usage()
+		return fmt.Errorf("-namespace is required")
+	}
+
+	return withAdmin(*addr, func(ctx context.Context, client nfa_admin_v1alpha.AdminServiceClient) error {
+		stream, err := client.DrainNamespace(ctx, &nfa_admin_v1alpha.DrainNamespaceRequest{
+			Namespace:       *namespace,
+			GracePeriodSecs: uint32(grace.Seconds()),
+			Reason:          *reason,
+			DryRun:          *dryRun,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to drain namespace: %w", err)
+		}
+		return printProgress(stream)
+	})
+}
+
+func runFleetPurge(args []string) error {
+	fs := flag.NewFlagSet("fleet purge-stale", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Admin API address of the broker")
+	staleAfter := fs.Duration("stale-after", 0, "Remove registrations without a heartbeat for this long; 0 removes those no longer live")
+	dryRun := fs.Bool("dry-run", false, "Show the registrations that would be removed")
+	fs.Parse(args)
+
+	return withAdmin(*addr, func(ctx context.Context, client nfa_admin_v1alpha.AdminServiceClient) error {
+		stream, err := client.PurgeStaleRegistrations(ctx, &nfa_admin_v1alpha.PurgeStaleRegistrationsRequest{
+			StaleAfterSecs: uint32(staleAfter.Seconds()),
+			DryRun:         *dryRun,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to purge stale registrations: %w", err)
+		}
+		return printProgress(stream)
+	})
+}
+
+func runFleetRetag(args []string) error {
+	fs := flag.NewFlagSet("fleet retag", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Admin API address of the broker")
+	selector := fs.String("selector", "", "Service labels to match, as key=value[,key=value] (required)")
+	set := fs.String("set", "", "Labels to set, as key=value[,key=value]")
+	remove := fs.String("remove", "", "Labels to remove, as key[,key]")
+	dryRun := fs.Bool("dry-run", false, "Show the new labels without changing them")
+	fs.Parse(args)
+	selectorLabels, err := cli.ParsePairs(*selector)
+	if err != nil {
+		return fmt.Errorf("invalid -selector: %w", err)
+	}
+	if len(selectorLabels) == 0 {
+		fs.Usage()
+		return fmt.Errorf("-selector is required")
+	}
+	setLabels, err := cli.ParsePairs(*set)
+	if err != nil {
+		return fmt.Errorf("invalid -set: %w", err)
+	}
+	var removeLabels []string
+	if *remove != "" {
+		removeLabels = strings.Split(*remove, ",")
+	}
+
+	return withAdmin(*addr, func(ctx context.Context, client nfa_admin_v1alpha.AdminServiceClient) error {
+		stream, err := client.RetagServices(ctx, &nfa_admin_v1alpha.RetagServicesRequest{
+			Selector:     selectorLabels,
+			SetLabels:    setLabels,
+			RemoveLabels: removeLabels,
+			DryRun:       *dryRun,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to retag services: %w", err)
+		}
+		return printProgress(stream)
+	})
+}
+
+// printProgress prints the outcome of every service as the broker reports it
+func printProgress(stream interface {
+	Recv() (*nfa_admin_v1alpha.BulkProgress, error)
+}) error {
+	total, failed := 0, 0
+	for {
+		progress, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("bulk operation interrupted after %d services: %w", total, err)
+		}
+		outcome := strings.ToLower(strings.TrimPrefix(progress.Outcome.String(), "BULK_OUTCOME_"))
+		fmt.Printf("[%d/%d] %-32s %-8s %s\n", progress.Completed, progress.Total, progress.ServiceId, outcome, progress.Detail)
+		total++
+		if progress.Outcome == nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_FAILED {
+			failed++
+		}
+	}
+	if total == 0 {
+		fmt.Println("No services matched")
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d services failed", failed, total)
+	}
+	return nil
+}
+
+func withAdmin(addr string, fn func(ctx context.Context, client nfa_admin_v1alpha.AdminServiceClient) error) error {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to admin API: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	return fn(ctx, nfa_admin_v1alpha.NewAdminServiceClient(conn))
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/internal/cli"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func runInvoke(args []string) error {
+	fs := flag.NewFlagSet("invoke", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Broker address")
+	selector := fs.String("selector", "", "Runtime labels to match, as key=value[,key=value]; empty matches every runtime")
+	contractPath := fs.String("contract", "", "Intent contract YAML or JSON file declaring -example")
+	example := fs.String("example", "", "Send the parameters of this example of -contract and check the result it expects")
+	params := fs.String("params", "", "Intent parameters, as key=value[,key=value]; added to those of -example")
+	payload := fs.String("payload", "", "Intent payload")
+	timeout := fs.Duration("timeout", 10*time.Second, "How long to wait for targets to report")
+	fs.Usage = func() {
+		fmt.Println("Usage: nfactl invoke [-addr host:port] [-selector k=v,...] (-contract file -example name | [-params k=v,...] action)")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	selectorLabels, err := cli.ParsePairs(*selector)
+	if err != nil {
+		return fmt.Errorf("invalid -selector: %w", err)
+	}
+	parameters, err := cli.ParsePairs(*params)
+	if err != nil {
+		return fmt.Errorf("invalid -params: %w", err)
+	}
+	var action string
+	var expected *contract.Example
+	switch {
+	case *example != "":
+		if *contractPath == "" || fs.NArg() > 0 {
+			fs.Usage()
+			return fmt.Errorf("-example requires -contract and takes no action")
+		}
+		c, err := contract.LoadFile(*contractPath)
+		if err != nil {
+			return err
+		}
+		a, e, err := c.Example(*example)
+		if err != nil {
+			return err
+		}
+		action, expected = a, &e
+		if parameters == nil {
+			parameters = make(map[string]string)
+		}
+		for name, value := range e.Parameters {
+			if _, ok := parameters[name]; !ok {
+				parameters[name] = parameterString(value)
+			}
+		}
+	case fs.NArg() == 1:
+		action = fs.Arg(0)
+	default:
+		fs.Usage()
+		return fmt.Errorf("expected exactly one action or -example")
+	}
+
+	conn, err := grpc.Dial(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to broker: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout+5*time.Second)
+	defer cancel()
+	resp, err := nfa_control_v1alpha.NewBroadcastServiceClient(conn).Broadcast(ctx, &nfa_control_v1alpha.BroadcastRequest{
+		Selector: selectorLabels,
+		Intent: &nfa_control_v1alpha.Invoke{
+			Action:     action,
+			Parameters: parameters,
+			Payload:    []byte(*payload),
+		},
+		TimeoutSecs: uint32(timeout.Seconds()),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to invoke %s: %w", action, err)
+	}
+	if len(resp.Targets) == 0 {
+		fmt.Println("No runtimes matched the selector")
+		return nil
+	}
+
+	failed := printTargets(resp.Targets, false)
+	if failed == len(resp.Targets) {
+		return fmt.Errorf("no target handled %s", action)
+	}
+	if expected == nil || expected.Result == nil {
+		return nil
+	}
+	mismatched := 0
+	for _, target := range resp.Targets {
+		if target.State != nfa_control_v1alpha.TargetState_TARGET_STATE_SUCCEEDED {
+			continue
+		}
+		if err := checkOutput(*expected, target.Output); err != nil {
+			fmt.Printf("%-32s differs from example %s: %v\n", target.RuntimeId, expected.Name, err)
+			mismatched++
+		}
+	}
+	if mismatched > 0 {
+		return fmt.Errorf("%d targets did not return the result of example %s", mismatched, expected.Name)
+	}
+	fmt.Printf("Every target that handled %s returned the result of example %s\n", action, expected.Name)
+	return nil
+}
+
+// parameterString converts a parameter of an example to the string form of
+// broadcast parameters: strings as they are, other values as JSON
+func parameterString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// checkOutput compares the output of a target, a JSON object, with the
+// result expected by an example
+func checkOutput(expected contract.Example, output []byte) error {
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(output, &decoded); err != nil {
+		return fmt.Errorf("output is not a JSON object: %w", err)
+	}
+	fields := make(map[string]*nfa_intent_v1alpha.Value, len(decoded))
+	for name, v := range decoded {
+		if value := contract.ValueToProto(v); value != nil {
+			fields[name] = value
+		}
+	}
+	return expected.CheckResult(fields)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+)
+
+// lintResult is the JSON output of one contract file
+type lintResult struct {
+	Path     string             `json:"path"`
+	Error    string             `json:"error,omitempty"`
+	Findings []contract.Finding `json:"findings,omitempty"`
+}
+
+// runLint lints contract files, and the contract files under directories,
+// failing when a contract is invalid or has error findings, or warnings too
+// with -strict, so it can gate CI
+func runLint(args []string) error {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	strict := flags.Bool("strict", false, "Fail on warnings as well as errors")
+	ignore := flags.String("ignore", "", "Comma-separated rules not to report, e.g. missing-description")
+	format := flags.String("format", "text", "Output format: text or json")
+	flags.Usage = func() {
+		fmt.Println("Usage: nfactl lint [-strict] [-ignore rule,...] [-format text|json] file-or-dir...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() == 0 || (*format != "text" && *format != "json") {
+		flags.Usage()
+		return fmt.Errorf("at least one contract file or directory is required")
+	}
+	var ignored []string
+	if *ignore != "" {
+		ignored = strings.Split(*ignore, ",")
+	}
+
+	paths, err := contractFiles(flags.Args())
+	if err != nil {
+		return err
+	}
+	var results []lintResult
+	failed := 0
+	for _, path := range paths {
+		result := lintResult{Path: path}
+		c, err := contract.LoadFile(path)
+		if err == nil {
+			result.Findings, err = contract.Lint(c)
+		}
+		if err != nil {
+			result.Error = err.Error()
+			failed++
+		}
+		result.Findings = slices.DeleteFunc(result.Findings, func(f contract.Finding) bool {
+			return slices.Contains(ignored, f.Rule)
+		})
+		for _, f := range result.Findings {
+			if f.Severity == contract.SeverityError || *strict {
+				failed++
+				break
+			}
+		}
+		results = append(results, result)
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		for _, r := range results {
+			switch {
+			case r.Error != "":
+				fmt.Printf("%s: invalid: %s\n", r.Path, r.Error)
+			case len(r.Findings) == 0:
+				fmt.Printf("%s: OK\n", r.Path)
+			}
+			for _, f := range r.Findings {
+				fmt.Printf("%s: %v\n", r.Path, f)
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d contract file(s) failed linting", failed, len(paths))
+	}
+	return nil
+}
+
+// contractFiles expands directories in args to the YAML and JSON files under
+// them; files named explicitly are kept whatever their extension
+func contractFiles(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			paths = append(paths, arg)
+			continue
+		}
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			switch filepath.Ext(path) {
+			case ".yaml", ".yml", ".json":
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"time"
+
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func runLogLevel(args []string) error {
+	fs := flag.NewFlagSet("log-level", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Admin API address of the broker or gateway")
+	fs.Usage = func() {
+		fmt.Println("Usage: nfactl log-level [-addr host:port] [<component|*> <level>]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 0 && fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("expected no arguments or <component> <level>")
+	}
+
+	conn, err := grpc.Dial(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to admin API: %w", err)
+	}
+	defer conn.Close()
+	client := nfa_admin_v1alpha.NewAdminServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var levels map[string]string
+	if fs.NArg() == 2 {
+		resp, err := client.SetLogLevel(ctx, &nfa_admin_v1alpha.SetLogLevelRequest{
+			Component: fs.Arg(0),
+			Level:     fs.Arg(1),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set log level: %w", err)
+		}
+		levels = resp.Levels
+	} else {
+		resp, err := client.GetLogLevels(ctx, &nfa_admin_v1alpha.GetLogLevelsRequest{})
+		if err != nil {
+			return fmt.Errorf("failed to get log levels: %w", err)
+		}
+		levels = resp.Levels
+	}
+
+	names := make([]string, 0, len(levels))
+	for name := range levels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("%-12s %s\n", name, levels[name])
+	}
+	return nil
+}
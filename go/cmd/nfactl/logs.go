@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	nfa_debug_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/debug/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// runLogs streams the logs and runtime stats of a provider serving the debug
+// service (the nfa.debug.stream_logs intent) until interrupted
+func runLogs(args []string) error {
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	addr := fs.String("addr", "", "Address of the provider's intent server (required)")
+	tokenFile := fs.String("token-file", "", "File holding the provider's debug token; defaults to $NFA_DEBUG_TOKEN")
+	useTLS := fs.Bool("tls", false, "Connect over TLS, verified against the system roots or -ca-file")
+	caFile := fs.String("ca-file", "", "CA certificates to verify the provider with")
+	level := fs.String("level", "info", "Minimum level: debug, info, warn or error")
+	components := fs.String("component", "", "Comma-separated components to show; empty shows all")
+	backlog := fs.Uint("backlog", 100, "Recent records to show before following new ones")
+	stats := fs.Duration("stats", 0, "Interval between runtime stats, e.g. 10s; 0 shows none")
+	format := fs.String("format", "text", "Output format: text or json")
+	fs.Usage = func() {
+		fmt.Println("Usage: nfactl logs -addr host:port [-token-file file] [-tls] [-level info] [-component a,b] [-backlog 100] [-stats 10s] [-format text|json]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *addr == "" || (*format != "text" && *format != "json") {
+		fs.Usage()
+		return fmt.Errorf("-addr is required")
+	}
+
+	token := os.Getenv("NFA_DEBUG_TOKEN")
+	if *tokenFile != "" {
+		data, err := os.ReadFile(*tokenFile)
+		if err != nil {
+			return fmt.Errorf("failed to read token: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	creds := insecure.NewCredentials()
+	if *useTLS || *caFile != "" {
+		config := &tls.Config{MinVersion: tls.VersionTLS12}
+		if *caFile != "" {
+			pem, err := os.ReadFile(*caFile)
+			if err != nil {
+				return fmt.Errorf("failed to read CA file: %w", err)
+			}
+			config.RootCAs = x509.NewCertPool()
+			if !config.RootCAs.AppendCertsFromPEM(pem) {
+				return fmt.Errorf("no certificates found in %s", *caFile)
+			}
+		}
+		creds = credentials.NewTLS(config)
+	} else if token != "" {
+		fmt.Fprintln(os.Stderr, "Warning: sending the debug token without TLS")
+	}
+
+	conn, err := grpc.Dial(*addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return fmt.Errorf("failed to connect to provider: %w", err)
+	}
+	defer conn.Close()
+	client := nfa_debug_v1alpha.NewDebugClient(conn)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	}
+	req := &nfa_debug_v1alpha.StreamLogsRequest{
+		Backlog:           uint32(*backlog),
+		Level:             *level,
+		StatsIntervalSecs: uint32(stats.Seconds()),
+	}
+	if *components != "" {
+		req.Components = strings.Split(*components, ",")
+	}
+	stream, err := client.StreamLogs(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to stream logs: %w", err)
+	}
+
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("log stream failed: %w", err)
+		}
+		if *format == "json" {
+			data, err := protojson.Marshal(event)
+			if err != nil {
+				return fmt.Errorf("failed to encode event: %w", err)
+			}
+			fmt.Println(string(data))
+			continue
+		}
+		switch e := event.Event.(type) {
+		case *nfa_debug_v1alpha.DebugEvent_Log:
+			printLogRecord(e.Log)
+		case *nfa_debug_v1alpha.DebugEvent_Stats:
+			s := e.Stats
+			fmt.Printf("%s STATS uptime=%s goroutines=%d heap=%dKiB sys=%dKiB gc=%d services=%s draining=%t dropped=%d\n",
+				time.Unix(s.TimeUnix, 0).Format(time.RFC3339), time.Duration(s.UptimeSecs)*time.Second,
+				s.Goroutines, s.HeapAllocBytes/1024, s.SysBytes/1024, s.GcCycles,
+				strings.Join(s.ServiceIds, ","), s.Draining, s.DroppedRecords)
+		}
+	}
+}
+
+func printLogRecord(r *nfa_debug_v1alpha.LogRecord) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %-5s", time.Unix(0, r.TimeUnixNano).Format(time.RFC3339Nano), strings.ToUpper(r.Level))
+	if r.Component != "" {
+		fmt.Fprintf(&b, " [%s]", r.Component)
+	}
+	fmt.Fprintf(&b, " %s", r.Message)
+	keys := make([]string, 0, len(r.Attributes))
+	for k := range r.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%q", k, r.Attributes[k])
+	}
+	fmt.Println(b.String())
+}
@@ -0,0 +1,98 @@
+// nfactl is the operator command line tool for NFA deployments
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `Usage: nfactl <command> [arguments]
+
+Commands:
+  broadcast         Send an intent to every runtime matching a label selector
+  ca                Create bootstrap tokens for the broker's built-in CA or revoke devices
+  catalog           Export or import a namespace's signed intent catalog
+  cluster           Show the leader, log and members of a replicated broker cluster
+  contract docs     Write the reference documentation of a contract as Markdown
+  contract export   Write the contracts registered in a namespace as YAML
+  contract fixtures Generate a Go test table of valid and invalid requests from a contract
+  contract schema   Write the JSON Schema of the contract format
+  config check      Validate configuration files offline
+  config effective  Show the merged configuration and where each value came from
+  deprecations      Show who still calls deprecated action aliases
+  dlq               Inspect, requeue or purge dead-lettered events
+  errors            Show the errors providers' handlers returned, as their runtimes reported them
+  experiment        Run A/B experiments on provider selection and compare results
+  fleet             Drain, purge or retag many providers at once, with dry runs
+  invoke            Invoke an action, e.g. with the parameters of a contract example, and check its result
+  lint              Check contract files for likely mistakes, e.g. in CI
+  logs              Stream a provider's logs and runtime stats for remote debugging
+  log-level         Show or change per-component log levels at runtime
+  report            Generate an SLA report of providers as a table, JSON or CSV
+  storage           Show retained data per store, optionally compacting it first
+  subject           List, export or purge the data held about a user
+  timer             Broadcast an intent once at a future time, list or cancel timers
+  webhook           Manage webhooks for lifecycle events
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "broadcast":
+		err = runBroadcast(os.Args[2:])
+	case "ca":
+		err = runCA(os.Args[2:])
+	case "catalog":
+		err = runCatalog(os.Args[2:])
+	case "cluster":
+		err = runCluster(os.Args[2:])
+	case "config":
+		err = runConfig(os.Args[2:])
+	case "contract":
+		err = runContract(os.Args[2:])
+	case "deprecations":
+		err = runDeprecations(os.Args[2:])
+	case "dlq":
+		err = runDLQ(os.Args[2:])
+	case "errors":
+		err = runErrors(os.Args[2:])
+	case "experiment":
+		err = runExperiment(os.Args[2:])
+	case "fleet":
+		err = runFleet(os.Args[2:])
+	case "invoke":
+		err = runInvoke(os.Args[2:])
+	case "lint":
+		err = runLint(os.Args[2:])
+	case "logs":
+		err = runLogs(os.Args[2:])
+	case "log-level":
+		err = runLogLevel(os.Args[2:])
+	case "report":
+		err = runReport(os.Args[2:])
+	case "storage":
+		err = runStorage(os.Args[2:])
+	case "subject":
+		err = runSubject(os.Args[2:])
+	case "timer":
+		err = runTimer(os.Args[2:])
+	case "webhook":
+		err = runWebhook(os.Args[2:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
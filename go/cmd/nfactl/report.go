@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/sla"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func runReport(args []string) error {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Admin API address of the broker")
+	namespace := fs.String("namespace", "", "Report only providers of this namespace")
+	provider := fs.String("provider", "", "Report only this service ID")
+	since := fs.Duration("since", 24*time.Hour, "Length of the reporting period, ending now")
+	bucket := fs.Duration("bucket", sla.DefaultBucket, "Resolution of the violation timeline")
+	format := fs.String("format", "table", "Output format: table, json or csv")
+	output := fs.String("o", "", "Write the report to a file instead of stdout")
+	fs.Usage = func() {
+		fmt.Println("Usage: nfactl report [-addr host:port] [-namespace ns] [-provider id] [-since 24h] [-format table|json|csv] [-o file]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *format != "table" && *format != "json" && *format != "csv" {
+		fs.Usage()
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	conn, err := grpc.Dial(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to admin API: %w", err)
+	}
+	defer conn.Close()
+	client := nfa_admin_v1alpha.NewAdminServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	end := time.Now()
+	report, err := client.GetSLAReport(ctx, &nfa_admin_v1alpha.GetSLAReportRequest{
+		Namespace:  *namespace,
+		ServiceId:  *provider,
+		StartUnix:  end.Add(-*since).Unix(),
+		EndUnix:    end.Unix(),
+		BucketSecs: uint32(bucket.Seconds()),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get SLA report: %w", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("failed to create report file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "json":
+		data, err := protojson.MarshalOptions{Multiline: true}.Marshal(report)
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	case "csv":
+		return sla.WriteCSV(w, report)
+	}
+
+	if len(report.Providers) == 0 {
+		fmt.Fprintln(w, "No provider activity in the period")
+		return nil
+	}
+	fmt.Fprintf(w, "%-16s %-32s %8s %9s %9s %9s %9s  %s\n", "NAMESPACE", "PROVIDER", "REQUESTS", "AVAIL", "P50", "P90", "P99", "SLA")
+	for _, p := range report.Providers {
+		verdict := "met"
+		if !p.Met {
+			verdict = fmt.Sprintf("missed (%d violations)", len(p.Violations))
+		}
+		fmt.Fprintf(w, "%-16s %-32s %8d %8.3f%% %7.1fms %7.1fms %7.1fms  %s\n", p.Namespace, p.ServiceId, p.Requests,
+			p.Availability*100, p.LatencyP50Ms, p.LatencyP90Ms, p.LatencyP99Ms, verdict)
+		for _, v := range p.Violations {
+			fmt.Fprintf(w, "    %s - %s  %s\n", time.Unix(v.StartUnix, 0).Format(time.RFC3339),
+				time.Unix(v.EndUnix, 0).Format(time.RFC3339), v.Detail)
+		}
+	}
+	return nil
+}
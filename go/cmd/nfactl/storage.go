@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func runStorage(args []string) error {
+	fs := flag.NewFlagSet("storage", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Admin API address of the broker")
+	compact := fs.Bool("compact", false, "Compact every store according to its retention policy first")
+	fs.Usage = func() {
+		fmt.Println("Usage: nfactl storage [-addr host:port] [-compact]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	conn, err := grpc.Dial(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to admin API: %w", err)
+	}
+	defer conn.Close()
+	client := nfa_admin_v1alpha.NewAdminServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := client.GetStorageUsage(ctx, &nfa_admin_v1alpha.GetStorageUsageRequest{Compact: *compact})
+	if err != nil {
+		return fmt.Errorf("failed to get storage usage: %w", err)
+	}
+
+	fmt.Printf("%-12s %10s %12s %-20s %-10s %12s %10s\n", "STORE", "ENTRIES", "BYTES", "OLDEST", "MAX AGE", "MAX BYTES", "COMPACTED")
+	for _, s := range resp.Stores {
+		oldest := "-"
+		if s.OldestUnix != 0 {
+			oldest = time.Unix(s.OldestUnix, 0).Format(time.DateTime)
+		}
+		maxAge, maxBytes := "-", "-"
+		if s.MaxAgeSecs != 0 {
+			maxAge = (time.Duration(s.MaxAgeSecs) * time.Second).String()
+		}
+		if s.MaxBytes != 0 {
+			maxBytes = fmt.Sprint(s.MaxBytes)
+		}
+		fmt.Printf("%-12s %10d %12d %-20s %-10s %12s %10d\n", s.Name, s.Entries, s.Bytes, oldest, maxAge, maxBytes, s.CompactedEntries)
+	}
+	return nil
+}
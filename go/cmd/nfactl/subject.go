@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/privacy"
+	nfa_privacy_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/privacy/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const subjectUsage = `Usage: nfactl subject <command> [arguments]
+
+Commands:
+  list     List the records the broker holds about a user
+  export   Save everything the broker holds about a user as JSON
+  purge    Erase everything the broker holds about a user and print the receipt
+  receipt  Show the receipt of an earlier purge
+`
+
+func runSubject(args []string) error {
+	if len(args) < 1 {
+		fmt.Print(subjectUsage)
+		return fmt.Errorf("missing subject command")
+	}
+
+	fs := flag.NewFlagSet("subject "+args[0], flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Broker address")
+	switch args[0] {
+	case "list":
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			fmt.Println("Usage: nfactl subject list [-addr host:port] <user-id>")
+			return fmt.Errorf("expected a user ID")
+		}
+		return withPrivacy(*addr, func(ctx context.Context, client *privacy.Client) error {
+			records, err := client.List(ctx, fs.Arg(0))
+			if err != nil {
+				return err
+			}
+			if len(records) == 0 {
+				fmt.Println("No records")
+			}
+			for _, r := range records {
+				category := strings.ToLower(strings.TrimPrefix(r.Category.String(), "DATA_CATEGORY_"))
+				fmt.Printf("%-12s %-12s %-40s %s\n", r.Store, category, r.Id, r.CreateTime.AsTime().Format(time.RFC3339))
+			}
+			return nil
+		})
+
+	case "export":
+		fs.Parse(args[1:])
+		if fs.NArg() != 2 {
+			fmt.Println("Usage: nfactl subject export [-addr host:port] <user-id> <export.json>")
+			return fmt.Errorf("expected a user ID and an output path")
+		}
+		return withPrivacy(*addr, func(ctx context.Context, client *privacy.Client) error {
+			export, err := client.Export(ctx, fs.Arg(0))
+			if err != nil {
+				return err
+			}
+			data, err := protojson.MarshalOptions{Multiline: true}.Marshal(export)
+			if err != nil {
+				return fmt.Errorf("failed to encode export: %w", err)
+			}
+			if err := os.WriteFile(fs.Arg(1), append(data, '\n'), 0o600); err != nil {
+				return fmt.Errorf("failed to write export: %w", err)
+			}
+			fmt.Printf("Exported %d records to %s\n", len(export.Records), fs.Arg(1))
+			return nil
+		})
+
+	case "purge":
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			fmt.Println("Usage: nfactl subject purge [-addr host:port] <user-id>")
+			return fmt.Errorf("expected a user ID")
+		}
+		return withPrivacy(*addr, func(ctx context.Context, client *privacy.Client) error {
+			receipt, err := client.Purge(ctx, fs.Arg(0))
+			if err != nil {
+				return err
+			}
+			printReceipt(receipt)
+			if !receipt.Complete {
+				return fmt.Errorf("purge is incomplete, run it again")
+			}
+			return nil
+		})
+
+	case "receipt":
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			fmt.Println("Usage: nfactl subject receipt [-addr host:port] <receipt-id>")
+			return fmt.Errorf("expected a receipt ID")
+		}
+		return withPrivacy(*addr, func(ctx context.Context, client *privacy.Client) error {
+			receipt, err := client.Receipt(ctx, fs.Arg(0))
+			if err != nil {
+				return err
+			}
+			printReceipt(receipt)
+			return nil
+		})
+
+	default:
+		fmt.Print(subjectUsage)
+		return fmt.Errorf("unknown subject command %q", args[0])
+	}
+}
+
+func printReceipt(receipt *nfa_privacy_v1alpha.PurgeReceipt) {
+	fmt.Printf("Receipt:   %s\n", receipt.Id)
+	fmt.Printf("User:      %s\n", receipt.UserId)
+	fmt.Printf("Completed: %s (complete: %t)\n", receipt.CompleteTime.AsTime().Format(time.RFC3339), receipt.Complete)
+	fmt.Printf("Digest:    %s\n", receipt.Digest)
+	for _, store := range receipt.Stores {
+		if store.Error != "" {
+			fmt.Printf("  %-12s failed: %s\n", store.Store, store.Error)
+		} else {
+			fmt.Printf("  %-12s %d records erased\n", store.Store, store.Purged)
+		}
+	}
+}
+
+func withPrivacy(addr string, fn func(ctx context.Context, client *privacy.Client) error) error {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to broker: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return fn(ctx, privacy.NewClient(conn))
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/internal/cli"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	nfa_timer_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/timer/v1alpha"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/timer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const timerUsage = `Usage: nfactl timer <command> [arguments]
+
+Commands:
+  add     Broadcast an intent once at a future time
+  list    List timers and whether their intent was delivered
+  cancel  Cancel a pending timer
+`
+
+func runTimer(args []string) error {
+	if len(args) < 1 {
+		fmt.Print(timerUsage)
+		return fmt.Errorf("missing timer command")
+	}
+
+	fs := flag.NewFlagSet("timer "+args[0], flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Broker address")
+	switch args[0] {
+	case "add":
+		at := fs.String("at", "", "When to fire, as RFC 3339, e.g. 2026-03-01T07:00:00+01:00")
+		in := fs.Duration("in", 0, "When to fire, as a delay from now, e.g. 8h")
+		maxLateness := fs.Duration("max-lateness", timer.DefaultMaxLateness, "How long after the fire time delivery is still attempted")
+		selector := fs.String("selector", "", "Runtime labels to match, as key=value[,key=value]; empty matches every runtime")
+		params := fs.String("params", "", "Intent parameters, as key=value[,key=value]")
+		payload := fs.String("payload", "", "Intent payload")
+		fs.Usage = func() {
+			fmt.Println("Usage: nfactl timer add [-addr host:port] (-at time | -in delay) [-selector k=v,...] [-params k=v,...] [-payload data] action")
+			fs.PrintDefaults()
+		}
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			fs.Usage()
+			return fmt.Errorf("expected exactly one action")
+		}
+		var fireTime time.Time
+		switch {
+		case *at != "" && *in != 0:
+			return fmt.Errorf("-at and -in are exclusive")
+		case *at != "":
+			t, err := time.Parse(time.RFC3339, *at)
+			if err != nil {
+				return fmt.Errorf("invalid -at: %w", err)
+			}
+			fireTime = t
+		case *in > 0:
+			fireTime = time.Now().Add(*in)
+		default:
+			fs.Usage()
+			return fmt.Errorf("expected -at or -in")
+		}
+		selectorLabels, err := cli.ParsePairs(*selector)
+		if err != nil {
+			return fmt.Errorf("invalid -selector: %w", err)
+		}
+		parameters, err := cli.ParsePairs(*params)
+		if err != nil {
+			return fmt.Errorf("invalid -params: %w", err)
+		}
+		return withTimers(*addr, func(ctx context.Context, client *timer.Client) error {
+			t, err := client.Schedule(ctx, fireTime, *maxLateness, selectorLabels, &nfa_control_v1alpha.Invoke{
+				Action:     fs.Arg(0),
+				Parameters: parameters,
+				Payload:    []byte(*payload),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create timer: %w", err)
+			}
+			fmt.Printf("Created timer %s firing at %s\n", t.Id, t.FireTime.AsTime().Local().Format(time.RFC3339))
+			return nil
+		})
+
+	case "list":
+		pending := fs.Bool("pending", false, "Only list pending timers")
+		fs.Parse(args[1:])
+		return withTimers(*addr, func(ctx context.Context, client *timer.Client) error {
+			state := nfa_timer_v1alpha.TimerState_TIMER_STATE_UNSPECIFIED
+			if *pending {
+				state = nfa_timer_v1alpha.TimerState_TIMER_STATE_PENDING
+			}
+			timers, err := client.List(ctx, state)
+			if err != nil {
+				return fmt.Errorf("failed to list timers: %w", err)
+			}
+			for _, t := range timers {
+				state := strings.ToLower(strings.TrimPrefix(t.State.String(), "TIMER_STATE_"))
+				fmt.Printf("%-24s %-25s %-20s %-9s attempts=%d %s\n",
+					t.Id, t.FireTime.AsTime().Local().Format(time.RFC3339), t.Broadcast.GetIntent().GetAction(), state, t.Attempts, t.LastError)
+			}
+			return nil
+		})
+
+	case "cancel":
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: nfactl timer cancel [-addr host:port] <id>")
+		}
+		return withTimers(*addr, func(ctx context.Context, client *timer.Client) error {
+			if _, err := client.Cancel(ctx, fs.Arg(0)); err != nil {
+				return fmt.Errorf("failed to cancel timer: %w", err)
+			}
+			fmt.Printf("Cancelled timer %s\n", fs.Arg(0))
+			return nil
+		})
+
+	default:
+		fmt.Print(timerUsage)
+		return fmt.Errorf("unknown timer command %q", args[0])
+	}
+}
+
+func withTimers(addr string, fn func(ctx context.Context, client *timer.Client) error) error {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to broker: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return fn(ctx, timer.NewClient(conn))
+}
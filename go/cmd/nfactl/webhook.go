@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	nfa_webhook_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/webhook/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const webhookUsage = `Usage: nfactl webhook <command> [arguments]
+
+Commands:
+  add         Register a webhook for lifecycle events
+  list        List registered webhooks
+  remove      Remove a webhook
+  deliveries  Show recent deliveries and their status
+`
+
+func runWebhook(args []string) error {
+	if len(args) < 1 {
+		fmt.Print(webhookUsage)
+		return fmt.Errorf("missing webhook command")
+	}
+
+	fs := flag.NewFlagSet("webhook "+args[0], flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "Broker address")
+	switch args[0] {
+	case "add":
+		events := fs.String("events", "", "Comma-separated event types, e.g. service.registered,slo.violated (default all)")
+		secret := fs.String("secret", "", "Signing secret (generated when empty)")
+		fs.Usage = func() {
+			fmt.Println("Usage: nfactl webhook add [-addr host:port] [-events types] [-secret s] <url>")
+			fs.PrintDefaults()
+		}
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			fs.Usage()
+			return fmt.Errorf("expected a webhook url")
+		}
+		return withWebhooks(*addr, func(ctx context.Context, client nfa_webhook_v1alpha.WebhookServiceClient) error {
+			req := &nfa_webhook_v1alpha.CreateWebhookRequest{Url: fs.Arg(0), Secret: *secret}
+			if *events != "" {
+				req.Events = strings.Split(*events, ",")
+			}
+			wh, err := client.CreateWebhook(ctx, req)
+			if err != nil {
+				return fmt.Errorf("failed to create webhook: %w", err)
+			}
+			fmt.Printf("Created webhook %s\nSigning secret (shown once): %s\n", wh.Id, wh.Secret)
+			return nil
+		})
+
+	case "list":
+		fs.Parse(args[1:])
+		return withWebhooks(*addr, func(ctx context.Context, client nfa_webhook_v1alpha.WebhookServiceClient) error {
+			resp, err := client.ListWebhooks(ctx, &nfa_webhook_v1alpha.ListWebhooksRequest{})
+			if err != nil {
+				return fmt.Errorf("failed to list webhooks: %w", err)
+			}
+			for _, wh := range resp.Webhooks {
+				events := "*"
+				if len(wh.Events) > 0 {
+					events = strings.Join(wh.Events, ",")
+				}
+				fmt.Printf("%-20s %-48s %s\n", wh.Id, wh.Url, events)
+			}
+			return nil
+		})
+
+	case "remove":
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: nfactl webhook remove [-addr host:port] <id>")
+		}
+		return withWebhooks(*addr, func(ctx context.Context, client nfa_webhook_v1alpha.WebhookServiceClient) error {
+			if _, err := client.DeleteWebhook(ctx, &nfa_webhook_v1alpha.DeleteWebhookRequest{Id: fs.Arg(0)}); err != nil {
+				return fmt.Errorf("failed to remove webhook: %w", err)
+			}
+			fmt.Printf("Removed webhook %s\n", fs.Arg(0))
+			return nil
+		})
+
+	case "deliveries":
+		webhookID := fs.String("webhook", "", "Only show deliveries of this webhook")
+		failed := fs.Bool("failed", false, "Only show failed deliveries")
+		fs.Parse(args[1:])
+		return withWebhooks(*addr, func(ctx context.Context, client nfa_webhook_v1alpha.WebhookServiceClient) error {
+			req := &nfa_webhook_v1alpha.ListDeliveriesRequest{WebhookId: *webhookID}
+			if *failed {
+				req.Status = nfa_webhook_v1alpha.DeliveryStatus_DELIVERY_STATUS_FAILED
+			}
+			resp, err := client.ListDeliveries(ctx, req)
+			if err != nil {
+				return fmt.Errorf("failed to list deliveries: %w", err)
+			}
+			for _, d := range resp.Deliveries {
+				status := strings.ToLower(strings.TrimPrefix(d.Status.String(), "DELIVERY_STATUS_"))
+				fmt.Printf("%-20s %-20s %-20s %-9s attempts=%d %s\n",
+					d.Id, d.WebhookId, d.EventType, status, d.Attempts, d.LastError)
+			}
+			return nil
+		})
+
+	default:
+		fmt.Print(webhookUsage)
+		return fmt.Errorf("unknown webhook command %q", args[0])
+	}
+}
+
+func withWebhooks(addr string, fn func(ctx context.Context, client nfa_webhook_v1alpha.WebhookServiceClient) error) error {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to broker: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return fn(ctx, nfa_webhook_v1alpha.NewWebhookServiceClient(conn))
+}
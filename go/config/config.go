@@ -0,0 +1,242 @@
+// Package config loads and validates configuration shared by the NFA broker,
+// gateway and runtime components
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/BurntSushi/toml"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/bandit"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/metrics"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/policy"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/probe"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/ratelimit"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/retention"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/scale"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/shadow"
+)
+
+// Config is the in-memory representation of an NFA configuration file
+type Config struct {
+	// Profile is the environment profile whose defaults were applied, if any
+	Profile Profile `toml:"-"`
+
+	Broker     BrokerConfig    `toml:"broker"`
+	Gateway    GatewayConfig   `toml:"gateway"`
+	Routing    RoutingConfig   `toml:"routing"`
+	RateLimits RateLimitConfig `toml:"rate_limits"`
+	TLS        TLSConfig       `toml:"tls"`
+	Logging    LoggingConfig   `toml:"logging"`
+	Runtime    RuntimeConfig   `toml:"runtime"`
+	Policy     PolicyConfig    `toml:"policy"`
+	// Probes are synthetic invocations the broker fires at providers
+	Probes  []probe.Probe `toml:"probes,omitempty"`
+	Scaling ScalingConfig `toml:"scaling,omitempty"`
+	// Retention bounds the event logs, analytics and audit data the broker keeps
+	Retention RetentionConfig `toml:"retention,omitempty"`
+	Metrics   MetricsConfig   `toml:"metrics,omitempty"`
+}
+
+type BrokerConfig struct {
+	ListenAddress        string `toml:"listen_address"`
+	MaxConnections       int    `toml:"max_connections"`
+	HeartbeatTimeoutSecs int    `toml:"heartbeat_timeout_secs"`
+	StorageBackend       string `toml:"storage_backend"`
+	// StorageDir is where the "file" and "badger" storage backends of
+	// nfa-refbroker keep the registrations, so they survive restarts
+	StorageDir string `toml:"storage_dir,omitempty"`
+	// StaticContractsDir is a directory of contracts registered at startup as
+	// static providers with fixed endpoints; see broker.Embedded.LoadStatic
+	StaticContractsDir string `toml:"static_contracts_dir,omitempty"`
+	// ClusterNodeID names this node among ClusterPeers, making nfa-refbroker
+	// a member of a cluster replicating its registry; see package cluster
+	ClusterNodeID string `toml:"cluster_node_id,omitempty"`
+	// ClusterPeers are the broker addresses of every node of the cluster,
+	// this one included, by node ID
+	ClusterPeers map[string]string `toml:"cluster_peers,omitempty"`
+	// ClusterDir keeps the replicated log of this node
+	ClusterDir string `toml:"cluster_dir,omitempty"`
+	// TimerDir is where nfa-refbroker keeps the timers, so pending timers
+	// survive restarts; empty keeps them in memory
+	TimerDir string `toml:"timer_dir,omitempty"`
+	// KeyFile holds the keys nfa-refbroker seals the registrations it keeps
+	// in StorageDir or ClusterDir with, see atrest.LoadKeyFile
+	KeyFile string `toml:"key_file,omitempty"`
+}
+
+type GatewayConfig struct {
+	ListenAddress  string `toml:"listen_address"`
+	BrokerAddress  string `toml:"broker_address"`
+	RequestTimeout int    `toml:"request_timeout_secs"`
+}
+
+type RoutingConfig struct {
+	Strategy string             `toml:"strategy"`
+	Weights  map[string]float64 `toml:"weights,omitempty"`
+	// Shadow mirrors broadcast intents to candidate providers for evaluation
+	Shadow []shadow.Rule `toml:"shadow,omitempty"`
+	// Bandit configures the "bandit" strategy
+	Bandit bandit.Config `toml:"bandit,omitempty"`
+}
+
+type RateLimitConfig struct {
+	RequestsPerSecond float64            `toml:"requests_per_second"`
+	Burst             int                `toml:"burst"`
+	PerAction         map[string]float64 `toml:"per_action,omitempty"`
+}
+
+// Limits returns the limits of the intent matches, as passed to
+// ratelimit.Limiter.Set after a reload
+func (c RateLimitConfig) Limits() ratelimit.Limits {
+	return ratelimit.Limits{RequestsPerSecond: c.RequestsPerSecond, Burst: c.Burst, PerAction: c.PerAction}
+}
+
+// ScalingConfig configures the scale signals the broker emits from the demand
+// for each action
+type ScalingConfig struct {
+	WindowSecs   int `toml:"window_secs,omitempty"`
+	IntervalSecs int `toml:"interval_secs,omitempty"`
+	// Command is run for every signal, see scale.CommandSink
+	Command  []string       `toml:"command,omitempty"`
+	Policies []scale.Policy `toml:"policies,omitempty"`
+}
+
+// RetentionConfig sets the retention policy of each store the broker
+// compacts in the background; unset bounds keep the built-in limits
+type RetentionConfig struct {
+	// IntervalSecs is how often stores are compacted; 0 selects retention.DefaultInterval
+	IntervalSecs int              `toml:"interval_secs,omitempty"`
+	Events       retention.Policy `toml:"events,omitempty"`
+	Analytics    retention.Policy `toml:"analytics,omitempty"`
+	Audit        retention.Policy `toml:"audit,omitempty"`
+}
+
+// Policies returns the policy of each store by name, as passed to
+// retention.Manager.SetPolicy after a reload
+func (c RetentionConfig) Policies() map[string]retention.Policy {
+	return map[string]retention.Policy{
+		retention.Events:    c.Events,
+		retention.Analytics: c.Analytics,
+		retention.Audit:     c.Audit,
+	}
+}
+
+// MetricsConfig configures the Prometheus metrics of nfa-refbroker
+type MetricsConfig struct {
+	// ListenAddress serves the metrics at /metrics; empty serves none
+	ListenAddress string `toml:"listen_address,omitempty"`
+	// Limits bound the series of the metrics on large catalogs
+	Limits metrics.Limits `toml:"limits,omitempty"`
+}
+
+type TLSConfig struct {
+	Enabled  bool   `toml:"enabled"`
+	CertFile string `toml:"cert_file,omitempty"`
+	KeyFile  string `toml:"key_file,omitempty"`
+	CAFile   string `toml:"ca_file,omitempty"`
+	// Key is a secret reference to a PEM-encoded private key, used instead of KeyFile
+	Key Secret `toml:"key,omitempty"`
+}
+
+type LoggingConfig struct {
+	Level  string `toml:"level"`
+	Format string `toml:"format"`
+	File   string `toml:"file,omitempty"`
+}
+
+type PolicyConfig struct {
+	// Engine is "builtin" or "opa"
+	Engine string `toml:"engine"`
+	// Bundle is the OPA bundle directory or .tar.gz used by the opa engine
+	Bundle       string        `toml:"bundle,omitempty"`
+	DefaultAllow bool          `toml:"default_allow"`
+	Rules        []policy.Rule `toml:"rules,omitempty"`
+	// Residency classifies the data of actions on top of what contracts
+	// declare; intents are never routed outside the resulting boundary
+	Residency []policy.ResidencyRule `toml:"residency,omitempty"`
+}
+
+type RuntimeConfig struct {
+	BrokerAddress         string `toml:"broker_address"`
+	HeartbeatIntervalSecs int    `toml:"heartbeat_interval_secs"`
+	FeatureFlagsFile      string `toml:"feature_flags_file,omitempty"`
+	AuthToken             Secret `toml:"auth_token,omitempty"`
+}
+
+// Default returns the built-in configuration used when no file is given
+func Default() *Config {
+	return &Config{
+		Broker: BrokerConfig{
+			ListenAddress:        "0.0.0.0:50051",
+			MaxConnections:       1000,
+			HeartbeatTimeoutSecs: 30,
+			StorageBackend:       "memory",
+		},
+		Gateway: GatewayConfig{
+			ListenAddress:  "0.0.0.0:8081",
+			BrokerAddress:  "localhost:50051",
+			RequestTimeout: 10,
+		},
+		Routing: RoutingConfig{
+			Strategy: "round_robin",
+		},
+		RateLimits: RateLimitConfig{
+			RequestsPerSecond: 100,
+			Burst:             200,
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Runtime: RuntimeConfig{
+			BrokerAddress:         "localhost:50051",
+			HeartbeatIntervalSecs: 10,
+		},
+		Policy: PolicyConfig{
+			Engine:       "builtin",
+			DefaultAllow: true,
+		},
+	}
+}
+
+// Load reads a TOML configuration file on top of the defaults of the profile
+// selected via NFA_PROFILE
+func Load(path string) (*Config, error) {
+	profile, err := ActiveProfile()
+	if err != nil {
+		return nil, err
+	}
+	return LoadProfile(path, profile)
+}
+
+// LoadProfile reads a TOML configuration file on top of the given profile's defaults
+func LoadProfile(path string, profile Profile) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return ParseProfile(data, profile)
+}
+
+// Parse decodes TOML configuration data on top of the defaults of the profile
+// selected via NFA_PROFILE
+func Parse(data []byte) (*Config, error) {
+	profile, err := ActiveProfile()
+	if err != nil {
+		return nil, err
+	}
+	return ParseProfile(data, profile)
+}
+
+// ParseProfile decodes TOML configuration data on top of the given profile's defaults
+func ParseProfile(data []byte, profile Profile) (*Config, error) {
+	cfg, err := DefaultFor(profile)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := toml.Decode(string(data), cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return cfg, nil
+}
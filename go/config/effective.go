@@ -0,0 +1,247 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// envOverrides maps environment variables to the configuration keys they set.
+// Names follow .env.example so one environment file serves every component.
+var envOverrides = map[string]string{
+	"NFA_BROKER_LISTEN_ADDRESS":    "broker.listen_address",
+	"NFA_BROKER_MAX_CONNECTIONS":   "broker.max_connections",
+	"NFA_BROKER_HEARTBEAT_TIMEOUT": "broker.heartbeat_timeout_secs",
+	"NFA_STORAGE_BACKEND":          "broker.storage_backend",
+	"NFA_BROKER_STATIC_CONTRACTS":  "broker.static_contracts_dir",
+	"NFA_BROKER_STORAGE_DIR":       "broker.storage_dir",
+	"NFA_BROKER_CLUSTER_NODE_ID":   "broker.cluster_node_id",
+	"NFA_BROKER_CLUSTER_DIR":       "broker.cluster_dir",
+	"NFA_BROKER_TIMER_DIR":         "broker.timer_dir",
+	"NFA_GATEWAY_LISTEN_ADDRESS":   "gateway.listen_address",
+	"NFA_BROKER_ADDRESS":           "runtime.broker_address",
+	"NFA_HEARTBEAT_INTERVAL":       "runtime.heartbeat_interval_secs",
+	"NFA_LOG_LEVEL":                "logging.level",
+	"NFA_LOG_FORMAT":               "logging.format",
+	"NFA_LOG_FILE":                 "logging.file",
+}
+
+// LoadOptions describes the configuration layers to merge, lowest precedence first:
+// defaults, profile, file, environment and explicit overrides (e.g. command-line flags)
+type LoadOptions struct {
+	// Profile selects profile defaults; empty means the NFA_PROFILE value
+	Profile Profile
+	// File is an optional TOML configuration file
+	File string
+	// Overrides maps dotted keys such as "logging.level" to values
+	Overrides map[string]string
+}
+
+// Effective is a merged configuration together with the layer that supplied each key
+type Effective struct {
+	Config  *Config
+	Sources map[string]string // dotted key -> "default", "profile:<name>", "file:<path>", "env:<VAR>" or "flag"
+}
+
+// LoadEffective merges every configuration layer and records where each value came from
+func LoadEffective(opts LoadOptions) (*Effective, error) {
+	profile := opts.Profile
+	if profile == "" {
+		active, err := ActiveProfile()
+		if err != nil {
+			return nil, err
+		}
+		profile = active
+	}
+
+	cfg, err := DefaultFor(profile)
+	if err != nil {
+		return nil, err
+	}
+	eff := &Effective{Config: cfg, Sources: make(map[string]string)}
+	for key := range flatten(Default()) {
+		eff.Sources[key] = "default"
+	}
+	if profile != "" {
+		base := flatten(Default())
+		for key, value := range flatten(cfg) {
+			if base[key] != value {
+				eff.Sources[key] = "profile:" + string(profile)
+			}
+		}
+	}
+
+	if opts.File != "" {
+		data, err := os.ReadFile(opts.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		md, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse config: %w", err)
+		}
+		for _, key := range md.Keys() {
+			if len(key) > 1 {
+				eff.Sources[key.String()] = "file:" + opts.File
+			}
+		}
+	}
+
+	envNames := make([]string, 0, len(envOverrides))
+	for name := range envOverrides {
+		envNames = append(envNames, name)
+	}
+	sort.Strings(envNames)
+	for _, name := range envNames {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		key := envOverrides[name]
+		if err := cfg.Set(key, value); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		eff.Sources[key] = "env:" + name
+	}
+
+	for key, value := range opts.Overrides {
+		if err := cfg.Set(key, value); err != nil {
+			return nil, err
+		}
+		eff.Sources[key] = "flag"
+	}
+	return eff, nil
+}
+
+// Set assigns a value to a dotted key such as "logging.level"
+func (c *Config) Set(key, value string) error {
+	sectionName, fieldName, ok := strings.Cut(key, ".")
+	if !ok {
+		return fmt.Errorf("key %q must have the form section.key", key)
+	}
+	section, ok := fieldByTag(reflect.ValueOf(c).Elem(), sectionName)
+	if !ok {
+		return fmt.Errorf("unknown config section %q", sectionName)
+	}
+	field, ok := fieldByTag(section, fieldName)
+	if !ok {
+		return fmt.Errorf("unknown config key %q", key)
+	}
+
+	if s, ok := field.Addr().Interface().(*Secret); ok {
+		return s.UnmarshalText([]byte(value))
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s: expected an integer, got %q", key, value)
+		}
+		field.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s: expected a number, got %q", key, value)
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: expected true or false, got %q", key, value)
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("%s cannot be overridden with a single value", key)
+	}
+	return nil
+}
+
+// Dump renders the configuration as TOML with secret values redacted
+func (c *Config) Dump() (string, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(c); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Value is a single effective configuration value and its source
+type Value struct {
+	Key    string
+	Value  string // TOML literal, secrets redacted
+	Source string
+}
+
+// Values lists every effective value sorted by key
+func (e *Effective) Values() []Value {
+	flat := flatten(e.Config)
+	values := make([]Value, 0, len(flat))
+	for key, value := range flat {
+		source := e.Sources[key]
+		if source == "" {
+			source = "default"
+		}
+		values = append(values, Value{Key: key, Value: value, Source: source})
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Key < values[j].Key })
+	return values
+}
+
+// Dump renders the effective configuration, annotating each key with its source
+func (e *Effective) Dump() (string, error) {
+	var buf bytes.Buffer
+	if e.Config.Profile != "" {
+		fmt.Fprintf(&buf, "# profile: %s\n", e.Config.Profile)
+	}
+	for _, v := range e.Values() {
+		fmt.Fprintf(&buf, "%s = %s  # %s\n", v.Key, v.Value, v.Source)
+	}
+	return buf.String(), nil
+}
+
+// flatten renders every leaf value as dotted key -> TOML literal, secrets redacted
+func flatten(c *Config) map[string]string {
+	var buf bytes.Buffer
+	toml.NewEncoder(&buf).Encode(c)
+	var tree map[string]interface{}
+	toml.Decode(buf.String(), &tree)
+
+	flat := make(map[string]string)
+	var walk func(prefix string, node map[string]interface{})
+	walk = func(prefix string, node map[string]interface{}) {
+		for key, value := range node {
+			if child, ok := value.(map[string]interface{}); ok {
+				walk(prefix+key+".", child)
+				continue
+			}
+			flat[prefix+key] = tomlLiteral(value)
+		}
+	}
+	walk("", tree)
+	return flat
+}
+
+func tomlLiteral(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(value)
+}
+
+func fieldByTag(v reflect.Value, tag string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if strings.Split(t.Field(i).Tag.Get("toml"), ",")[0] == tag {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
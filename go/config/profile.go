@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// EnvProfile selects the configuration profile applied before any file is loaded
+const EnvProfile = "NFA_PROFILE"
+
+// Profile names a bundle of environment-specific defaults
+type Profile string
+
+const (
+	ProfileDev      Profile = "dev"
+	ProfileStaging  Profile = "staging"
+	ProfileProd     Profile = "prod"
+	ProfileOnDevice Profile = "on-device"
+)
+
+// profiles adjust the built-in defaults for each environment. Values set in a
+// configuration file always take precedence over profile defaults. No profile
+// enables TLS: where the certificates live is up to each deployment, so it is
+// only served with the [tls] section of a configuration file.
+var profiles = map[Profile]func(*Config){
+	ProfileDev: func(c *Config) {
+		c.Logging.Level = "debug"
+		c.Logging.Format = "text"
+		c.TLS.Enabled = false
+		c.Gateway.RequestTimeout = 30
+	},
+	ProfileStaging: func(c *Config) {
+		c.Logging.Level = "debug"
+	},
+	ProfileProd: func(c *Config) {
+		c.Logging.Level = "info"
+		c.Gateway.RequestTimeout = 5
+		c.Policy.DefaultAllow = false
+	},
+	ProfileOnDevice: func(c *Config) {
+		// Constrained hardware on flaky home networks: fewer connections,
+		// more tolerance for missed heartbeats and quieter logs.
+		c.Broker.MaxConnections = 64
+		c.Broker.HeartbeatTimeoutSecs = 90
+		c.Gateway.RequestTimeout = 20
+		c.RateLimits.RequestsPerSecond = 20
+		c.RateLimits.Burst = 40
+		c.Logging.Level = "warn"
+		c.Logging.Format = "text"
+	},
+}
+
+// Profiles returns the names of all known profiles
+func Profiles() []Profile {
+	names := make([]Profile, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// ActiveProfile returns the profile selected via NFA_PROFILE, or an empty
+// profile when the variable is unset
+func ActiveProfile() (Profile, error) {
+	name := Profile(os.Getenv(EnvProfile))
+	if name == "" {
+		return "", nil
+	}
+	if _, ok := profiles[name]; !ok {
+		return "", fmt.Errorf("unknown %s %q, expected one of %v", EnvProfile, name, Profiles())
+	}
+	return name, nil
+}
+
+// DefaultFor returns the built-in defaults adjusted for the given profile.
+// An empty profile yields the plain defaults.
+func DefaultFor(profile Profile) (*Config, error) {
+	cfg := Default()
+	if profile == "" {
+		return cfg, nil
+	}
+	apply, ok := profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q, expected one of %v", profile, Profiles())
+	}
+	apply(cfg)
+	cfg.Profile = profile
+	return cfg, nil
+}
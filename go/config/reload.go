@@ -0,0 +1,216 @@
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// maxAuditEntries bounds the in-memory reload audit trail
+const maxAuditEntries = 100
+
+// AuditEntry records a single configuration reload attempt
+type AuditEntry struct {
+	Time     time.Time
+	Source   string   // "sighup", "admin" or caller supplied
+	Changed  []string // top-level sections that differ from the previous config
+	Rejected bool
+	Error    string
+}
+
+// Reloader holds the active configuration and replaces it atomically on reload.
+// A reload that fails to parse or validate leaves the active configuration untouched.
+type Reloader struct {
+	opts    LoadOptions
+	secrets *SecretResolver
+	current atomic.Pointer[Effective]
+	cert    atomic.Pointer[tls.Certificate]
+
+	reloading sync.Mutex // serializes reloads, their listeners included
+	mu        sync.Mutex // guards listeners/audit
+	listeners []func(old, updated *Config)
+	audit     []AuditEntry
+}
+
+// NewReloader loads and validates the initial configuration from path,
+// resolving secret references with the default file, env and keyring providers
+func NewReloader(path string) (*Reloader, error) {
+	return NewReloaderWithSecrets(path, NewSecretResolver())
+}
+
+// NewReloaderWithSecrets is like NewReloader but resolves secret references
+// with the given resolver, e.g. one with a KMS registered
+func NewReloaderWithSecrets(path string, secrets *SecretResolver) (*Reloader, error) {
+	return NewReloaderWithOptions(LoadOptions{File: path}, secrets)
+}
+
+// NewReloaderWithOptions loads the initial configuration from every layer in
+// opts and logs the redacted effective configuration
+func NewReloaderWithOptions(opts LoadOptions, secrets *SecretResolver) (*Reloader, error) {
+	r := &Reloader{opts: opts, secrets: secrets}
+	eff, cert, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	r.current.Store(eff)
+	r.cert.Store(cert)
+
+	if dump, err := eff.Dump(); err == nil {
+		log.Printf("Effective configuration:\n%s", dump)
+	}
+	return r, nil
+}
+
+// Current returns the active configuration. Callers must not modify it.
+func (r *Reloader) Current() *Config {
+	return r.current.Load().Config
+}
+
+// Effective returns the active configuration with the source of each value
+func (r *Reloader) Effective() *Effective {
+	return r.current.Load()
+}
+
+// OnChange registers a callback invoked after each successful reload
+func (r *Reloader) OnChange(fn func(old, updated *Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+// Reload re-reads every configuration layer and swaps the result in if it is
+// valid. Listeners are called after the swap, one reload at a time, and may
+// call the Reloader, though not Reload.
+func (r *Reloader) Reload(source string) (AuditEntry, error) {
+	r.reloading.Lock()
+	defer r.reloading.Unlock()
+
+	entry := AuditEntry{Time: time.Now(), Source: source}
+	eff, cert, err := r.load()
+	if err != nil {
+		entry.Rejected = true
+		entry.Error = err.Error()
+		r.mu.Lock()
+		r.record(entry)
+		r.mu.Unlock()
+		log.Printf("Configuration reload from %s rejected: %v", source, err)
+		return entry, err
+	}
+
+	old := r.current.Load().Config
+	cfg := eff.Config
+	entry.Changed = changedSections(old, cfg)
+	r.current.Store(eff)
+	r.cert.Store(cert)
+	r.mu.Lock()
+	r.record(entry)
+	listeners := slices.Clone(r.listeners)
+	r.mu.Unlock()
+	log.Printf("Configuration reloaded from %s, changed sections: %v", source, entry.Changed)
+
+	for _, fn := range listeners {
+		fn(old, cfg)
+	}
+	return entry, nil
+}
+
+// AuditLog returns a copy of the recorded reload attempts, oldest first
+func (r *Reloader) AuditLog() []AuditEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]AuditEntry(nil), r.audit...)
+}
+
+// WatchSignals reloads the configuration on every SIGHUP until ctx is done
+func (r *Reloader) WatchSignals(ctx context.Context) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+	defer signal.Stop(sigChan)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigChan:
+			r.Reload("sighup")
+		}
+	}
+}
+
+// GetCertificate serves the most recently loaded TLS certificate, so it can be
+// plugged into tls.Config to pick up rotated certificates without a restart
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert := r.cert.Load()
+	if cert == nil {
+		return nil, fmt.Errorf("tls is not enabled in the current configuration")
+	}
+	return cert, nil
+}
+
+func (r *Reloader) load() (*Effective, *tls.Certificate, error) {
+	eff, err := LoadEffective(r.opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg := eff.Config
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := cfg.ResolveSecrets(context.Background(), r.secrets); err != nil {
+		return nil, nil, err
+	}
+	if !cfg.TLS.Enabled {
+		return eff, nil, nil
+	}
+	cert, err := loadKeyPair(cfg.TLS)
+	if err != nil {
+		cfg.WipeSecrets()
+		return nil, nil, fmt.Errorf("failed to load tls key pair: %w", err)
+	}
+	return eff, &cert, nil
+}
+
+// loadKeyPair loads the certificate with either the resolved key secret or the key file
+func loadKeyPair(c TLSConfig) (tls.Certificate, error) {
+	if !c.Key.IsSet() {
+		return tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	}
+	certPEM, err := os.ReadFile(c.CertFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.X509KeyPair(certPEM, c.Key.Reveal())
+}
+
+func (r *Reloader) record(entry AuditEntry) {
+	r.audit = append(r.audit, entry)
+	if len(r.audit) > maxAuditEntries {
+		r.audit = r.audit[len(r.audit)-maxAuditEntries:]
+	}
+}
+
+// changedSections lists the top-level TOML sections that differ between a and b
+func changedSections(a, b *Config) []string {
+	var changed []string
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	t := va.Type()
+	for i := 0; i < t.NumField(); i++ {
+		section := t.Field(i).Tag.Get("toml")
+		if section == "-" {
+			continue
+		}
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			changed = append(changed, section)
+		}
+	}
+	return changed
+}
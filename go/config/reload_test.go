@@ -0,0 +1,174 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/ratelimit"
+)
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeKeyPair writes a self-signed certificate for name and its key
+func writeKeyPair(t *testing.T, certFile, keyFile, name string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, certFile, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	writeFile(t, keyFile, string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})))
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nfa.toml")
+	writeFile(t, path, "[rate_limits]\nrequests_per_second = 1\nburst = 1\n")
+	r, err := NewReloaderWithOptions(LoadOptions{Profile: ProfileDev, File: path}, NewSecretResolver())
+	if err != nil {
+		t.Fatalf("NewReloaderWithOptions() error = %v", err)
+	}
+	limiter := ratelimit.New(r.Current().RateLimits.Limits())
+	var audited []AuditEntry
+	r.OnChange(func(old, updated *Config) {
+		// Listeners may call the reloader
+		audited = r.AuditLog()
+		limiter.Set(updated.RateLimits.Limits())
+	})
+	if !limiter.Allow("translate") || limiter.Allow("translate") {
+		t.Fatalf("Allow() exceeded the burst of 1 before the reload")
+	}
+
+	writeFile(t, path, "[rate_limits]\nrequests_per_second = 1\nburst = 1\n\n[rate_limits.per_action]\ntranslate = -1\n")
+	if entry, err := r.Reload("test"); err == nil || !entry.Rejected {
+		t.Fatalf("Reload() of an invalid configuration = %+v, %v, want it rejected", entry, err)
+	}
+	if audited != nil || r.Current().RateLimits.Burst != 1 {
+		t.Errorf("rejected reload applied, burst = %d", r.Current().RateLimits.Burst)
+	}
+
+	writeFile(t, path, "[rate_limits]\nrequests_per_second = 1000\nburst = 1000\n")
+	entry, err := r.Reload("test")
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if !slices.Equal(entry.Changed, []string{"rate_limits"}) {
+		t.Errorf("Reload() changed = %v, want rate_limits", entry.Changed)
+	}
+	if len(audited) != 2 || audited[1].Rejected {
+		t.Errorf("AuditLog() in the listener = %+v, want the rejected and the applied reload", audited)
+	}
+	if !limiter.Allow("translate") {
+		t.Errorf("Allow() = false after the reload raised the limits")
+	}
+}
+
+func TestReloadProfiles(t *testing.T) {
+	// No profile needs certificate files without a [tls] section
+	path := filepath.Join(t.TempDir(), "nfa.toml")
+	writeFile(t, path, "")
+	for _, profile := range Profiles() {
+		r, err := NewReloaderWithOptions(LoadOptions{Profile: profile, File: path}, NewSecretResolver())
+		if err != nil {
+			t.Errorf("NewReloaderWithOptions() of profile %s error = %v", profile, err)
+			continue
+		}
+		if r.Current().TLS.Enabled {
+			t.Errorf("profile %s enables TLS, want it left to the configuration file", profile)
+		}
+	}
+}
+
+func TestReloadRotatesCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeKeyPair(t, certFile, keyFile, "before")
+	path := filepath.Join(dir, "nfa.toml")
+	writeFile(t, path, "[tls]\nenabled = true\ncert_file = \""+certFile+"\"\nkey_file = \""+keyFile+"\"\n")
+	r, err := NewReloaderWithOptions(LoadOptions{Profile: ProfileDev, File: path}, NewSecretResolver())
+	if err != nil {
+		t.Fatalf("NewReloaderWithOptions() error = %v", err)
+	}
+	subject := func() string {
+		cert, err := r.GetCertificate(nil)
+		if err != nil {
+			t.Fatalf("GetCertificate() error = %v", err)
+		}
+		parsed, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed.Subject.CommonName
+	}
+	if got := subject(); got != "before" {
+		t.Fatalf("GetCertificate() = %s, want before", got)
+	}
+
+	writeKeyPair(t, certFile, keyFile, "after")
+	if _, err := r.Reload("test"); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := subject(); got != "after" {
+		t.Errorf("GetCertificate() after the reload = %s, want after", got)
+	}
+}
+
+func TestReloaderRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nfa.toml")
+	writeFile(t, path, "")
+	r, err := NewReloaderWithOptions(LoadOptions{Profile: ProfileDev, File: path}, NewSecretResolver())
+	if err != nil {
+		t.Fatalf("NewReloaderWithOptions() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := r.Reload("test"); err != nil {
+			t.Fatalf("Reload() error = %v", err)
+		}
+	}
+	start := time.Now().Add(-time.Hour)
+	r.mu.Lock()
+	for i := range r.audit {
+		r.audit[i].Time = start.Add(time.Duration(i) * time.Minute)
+	}
+	r.mu.Unlock()
+	if usage := r.Usage(); usage.Entries != 3 || !usage.Oldest.Equal(start) || usage.Bytes <= 0 {
+		t.Fatalf("Usage() = %+v, want the 3 reloads", usage)
+	}
+	if removed := r.Compact(start.Add(time.Minute), 0); removed != 1 {
+		t.Errorf("Compact() by age removed %d, want 1", removed)
+	}
+	if removed := r.Compact(time.Time{}, r.Usage().Bytes/2); removed != 1 {
+		t.Errorf("Compact() by size removed %d, want 1", removed)
+	}
+	if log := r.AuditLog(); len(log) != 1 || !log[0].Time.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("AuditLog() = %+v, want the latest reload", log)
+	}
+}
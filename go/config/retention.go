@@ -0,0 +1,54 @@
+package config
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/retention"
+)
+
+var _ retention.Store = (*Reloader)(nil)
+
+// Usage implements retention.Store over the reload audit trail
+func (r *Reloader) Usage() retention.Usage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	usage := retention.Usage{Entries: len(r.audit)}
+	for _, e := range r.audit {
+		usage.Bytes += auditEntrySize(e)
+	}
+	if len(r.audit) > 0 {
+		usage.Oldest = r.audit[0].Time
+	}
+	return usage
+}
+
+// Compact implements retention.Store, on top of the bound of maxAuditEntries
+func (r *Reloader) Compact(before time.Time, maxBytes int64) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	drop := 0
+	for drop < len(r.audit) && !before.IsZero() && r.audit[drop].Time.Before(before) {
+		drop++
+	}
+	if maxBytes > 0 {
+		var size int64
+		for _, e := range r.audit[drop:] {
+			size += auditEntrySize(e)
+		}
+		for ; drop < len(r.audit) && size > maxBytes; drop++ {
+			size -= auditEntrySize(r.audit[drop])
+		}
+	}
+	r.audit = r.audit[drop:]
+	return drop
+}
+
+// auditEntrySize approximates the memory held by an audit entry
+func auditEntrySize(e AuditEntry) int64 {
+	size := int64(unsafe.Sizeof(e)) + int64(len(e.Source)+len(e.Error))
+	for _, section := range e.Changed {
+		size += int64(unsafe.Sizeof(section)) + int64(len(section))
+	}
+	return size
+}
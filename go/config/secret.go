@@ -0,0 +1,258 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const redacted = "[REDACTED]"
+
+// Secret is a configuration value that must not be stored in plain config.
+// The file holds a reference such as "file:///etc/nfa/token",
+// "env://NFA_TOKEN", "keyring://nfa/broker-token" or "kms://key-id?file=/path";
+// the value is resolved into memory at load time and never printed.
+type Secret struct {
+	ref   string
+	value []byte
+}
+
+// UnmarshalText records the secret reference without resolving it
+func (s *Secret) UnmarshalText(text []byte) error {
+	s.ref = string(text)
+	s.value = nil
+	return nil
+}
+
+// MarshalText keeps configuration dumps free of secret values
+func (s Secret) MarshalText() ([]byte, error) {
+	if s.ref == "" {
+		return nil, nil
+	}
+	return []byte(redacted), nil
+}
+
+func (s Secret) String() string {
+	return redacted
+}
+
+func (s Secret) GoString() string {
+	return redacted
+}
+
+// LogValue redacts the secret in structured logs
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
+
+// IsSet reports whether a reference was configured
+func (s Secret) IsSet() bool {
+	return s.ref != ""
+}
+
+// Ref returns the secret reference, which is safe to display
+func (s Secret) Ref() string {
+	return s.ref
+}
+
+// Reveal returns the resolved secret value. It is nil until resolved.
+func (s Secret) Reveal() []byte {
+	return s.value
+}
+
+// Wipe zeroes the resolved value in memory
+func (s *Secret) Wipe() {
+	for i := range s.value {
+		s.value[i] = 0
+	}
+	s.value = nil
+}
+
+// KMS decrypts ciphertext with a key managed by an external key management service
+type KMS interface {
+	Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error)
+}
+
+// SecretProvider resolves references for one URL scheme
+type SecretProvider interface {
+	Resolve(ctx context.Context, ref *url.URL) ([]byte, error)
+}
+
+// SecretResolver dispatches secret references to providers by scheme
+type SecretResolver struct {
+	mu        sync.RWMutex
+	providers map[string]SecretProvider
+}
+
+// NewSecretResolver creates a resolver with the file, env and keyring providers.
+// Use RegisterKMS to enable kms:// references.
+func NewSecretResolver() *SecretResolver {
+	return &SecretResolver{
+		providers: map[string]SecretProvider{
+			"file":    fileProvider{},
+			"env":     envProvider{},
+			"keyring": keyringProvider{},
+		},
+	}
+}
+
+// Register adds or replaces the provider for a scheme
+func (r *SecretResolver) Register(scheme string, provider SecretProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[scheme] = provider
+}
+
+// RegisterKMS enables kms://<key-id>?file=<path> and kms://<key-id>?ciphertext=<base64> references
+func (r *SecretResolver) RegisterKMS(kms KMS) {
+	r.Register("kms", kmsProvider{kms: kms})
+}
+
+// Resolve resolves a single secret reference
+func (r *SecretResolver) Resolve(ctx context.Context, ref string) ([]byte, error) {
+	u, err := url.Parse(ref)
+	if err != nil || u.Scheme == "" {
+		return nil, fmt.Errorf("secret must be a reference such as file://, env://, keyring:// or kms://")
+	}
+	r.mu.RLock()
+	provider, ok := r.providers[u.Scheme]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no secret provider for scheme %q", u.Scheme)
+	}
+	value, err := provider.Resolve(ctx, u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s secret: %w", u.Scheme, err)
+	}
+	return value, nil
+}
+
+// ResolveSecrets resolves every configured Secret field in place
+func (c *Config) ResolveSecrets(ctx context.Context, resolver *SecretResolver) error {
+	return forEachSecret(c, func(field string, s *Secret) error {
+		value, err := resolver.Resolve(ctx, s.ref)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		s.value = value
+		return nil
+	})
+}
+
+// WipeSecrets zeroes every resolved secret, e.g. after a configuration is replaced
+func (c *Config) WipeSecrets() {
+	forEachSecret(c, func(field string, s *Secret) error {
+		s.Wipe()
+		return nil
+	})
+}
+
+// forEachSecret calls fn for every Secret field that has a reference configured
+func forEachSecret(c *Config, fn func(field string, s *Secret) error) error {
+	secretType := reflect.TypeOf(Secret{})
+	cv := reflect.ValueOf(c).Elem()
+	for i := 0; i < cv.NumField(); i++ {
+		section := cv.Field(i)
+		if section.Kind() != reflect.Struct {
+			continue
+		}
+		sectionName := cv.Type().Field(i).Tag.Get("toml")
+		for j := 0; j < section.NumField(); j++ {
+			if section.Field(j).Type() != secretType {
+				continue
+			}
+			s := section.Field(j).Addr().Interface().(*Secret)
+			if !s.IsSet() {
+				continue
+			}
+			key := strings.Split(section.Type().Field(j).Tag.Get("toml"), ",")[0]
+			if err := fn(sectionName+"."+key, s); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type fileProvider struct{}
+
+func (fileProvider) Resolve(ctx context.Context, ref *url.URL) ([]byte, error) {
+	data, err := os.ReadFile(ref.Path)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(data, "\r\n"), nil
+}
+
+type envProvider struct{}
+
+func (envProvider) Resolve(ctx context.Context, ref *url.URL) ([]byte, error) {
+	value, ok := os.LookupEnv(ref.Host)
+	if !ok {
+		return nil, fmt.Errorf("environment variable %s is not set", ref.Host)
+	}
+	return []byte(value), nil
+}
+
+// keyringProvider reads keyring://<service>/<account> from the OS keyring via
+// the platform's command line tool, avoiding a cgo dependency
+type keyringProvider struct{}
+
+func (keyringProvider) Resolve(ctx context.Context, ref *url.URL) ([]byte, error) {
+	service, account := ref.Host, strings.TrimPrefix(ref.Path, "/")
+	if service == "" || account == "" {
+		return nil, fmt.Errorf("expected keyring://<service>/<account>")
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "linux":
+		cmd = exec.CommandContext(ctx, "secret-tool", "lookup", "service", service, "account", account)
+	default:
+		return nil, fmt.Errorf("os keyring is not supported on %s", runtime.GOOS)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("keyring lookup for %s/%s failed: %w", service, account, err)
+	}
+	return bytes.TrimRight(out, "\r\n"), nil
+}
+
+type kmsProvider struct {
+	kms KMS
+}
+
+func (p kmsProvider) Resolve(ctx context.Context, ref *url.URL) ([]byte, error) {
+	keyID := ref.Host + ref.Path
+	query := ref.Query()
+
+	var ciphertext []byte
+	switch {
+	case query.Get("file") != "":
+		data, err := os.ReadFile(query.Get("file"))
+		if err != nil {
+			return nil, err
+		}
+		ciphertext = data
+	case query.Get("ciphertext") != "":
+		data, err := base64.StdEncoding.DecodeString(query.Get("ciphertext"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ciphertext: %w", err)
+		}
+		ciphertext = data
+	default:
+		return nil, fmt.Errorf("kms reference needs a file or ciphertext parameter")
+	}
+	return p.kms.Decrypt(ctx, keyID, ciphertext)
+}
@@ -0,0 +1,353 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/policy"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/retention"
+)
+
+var (
+	validLogLevels         = []string{"debug", "info", "warn", "error"}
+	validLogFormats        = []string{"json", "text"}
+	validRoutingStrategies = []string{"round_robin", "weighted", "least_loaded", "random", "bandit"}
+	validPolicyEngines     = []string{"builtin", "opa"}
+	validPolicyEffects     = []string{"allow", "deny"}
+	validResidencies       = []string{policy.ResidencyOnDevice, policy.ResidencyRegion}
+)
+
+// externalSections are read by the Rust broker and scheduler. They are accepted
+// here so one file can configure the whole deployment.
+var externalSections = []string{
+	"broker.redis", "broker.postgres",
+	"scheduler", "storage", "network", "monitoring", "tracing", "auth", "dev",
+}
+
+// FieldError describes one invalid configuration value and how to fix it
+type FieldError struct {
+	Field   string
+	Message string
+	Hint    string
+}
+
+func (e FieldError) Error() string {
+	msg := e.Field + ": " + e.Message
+	if e.Field == "" {
+		msg = e.Message
+	}
+	if e.Hint != "" {
+		msg += " (" + e.Hint + ")"
+	}
+	return msg
+}
+
+// ValidationErrors collects every problem found in a configuration
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	lines := make([]string, len(v))
+	for i, e := range v {
+		lines[i] = e.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Validate checks that the configuration is internally consistent. The returned
+// error is a ValidationErrors listing every problem, not just the first one.
+func (c *Config) Validate() error {
+	var errs ValidationErrors
+	add := func(field, message, hint string) {
+		errs = append(errs, FieldError{Field: field, Message: message, Hint: hint})
+	}
+
+	checkAddress(add, "broker.listen_address", c.Broker.ListenAddress)
+	if c.Broker.MaxConnections <= 0 {
+		add("broker.max_connections", "must be greater than 0", "the default is 1000")
+	}
+	if c.Broker.HeartbeatTimeoutSecs <= 0 {
+		add("broker.heartbeat_timeout_secs", "must be greater than 0", "the default is 30")
+	}
+	checkAddress(add, "gateway.listen_address", c.Gateway.ListenAddress)
+	checkAddress(add, "gateway.broker_address", c.Gateway.BrokerAddress)
+	if c.Gateway.RequestTimeout <= 0 {
+		add("gateway.request_timeout_secs", "must be greater than 0", "")
+	}
+	if !contains(validRoutingStrategies, c.Routing.Strategy) {
+		add("routing.strategy", fmt.Sprintf("unknown strategy %q", c.Routing.Strategy), oneOf(validRoutingStrategies))
+	}
+	for target, weight := range c.Routing.Weights {
+		if weight < 0 {
+			add("routing.weights."+target, "must not be negative", "")
+		}
+	}
+	if len(c.Routing.Weights) > 0 && c.Routing.Strategy != "weighted" {
+		add("routing.weights", "weights are ignored unless routing.strategy is \"weighted\"", "")
+	}
+	if err := c.Routing.Bandit.Validate(); err != nil {
+		add("routing.bandit", err.Error(), "")
+	}
+	for i, rule := range c.Routing.Shadow {
+		field := fmt.Sprintf("routing.shadow[%d]", i)
+		if len(rule.Selector) == 0 {
+			add(field+".selector", "is required", "select the candidate runtimes by label")
+		}
+		if rule.SampleRate < 0 || rule.SampleRate > 1 {
+			add(field+".sample_rate", fmt.Sprintf("rate %v is not between 0 and 1", rule.SampleRate), "use 0 to mirror every intent")
+		}
+	}
+	if c.RateLimits.RequestsPerSecond < 0 {
+		add("rate_limits.requests_per_second", "must not be negative", "use 0 to disable rate limiting")
+	}
+	if c.RateLimits.Burst < 0 {
+		add("rate_limits.burst", "must not be negative", "")
+	}
+	for action, rps := range c.RateLimits.PerAction {
+		if rps < 0 {
+			add("rate_limits.per_action."+action, "must not be negative", "")
+		}
+	}
+	if c.TLS.Enabled {
+		if c.TLS.CertFile == "" {
+			add("tls.cert_file", "is required when tls is enabled", "set tls.enabled = false for plaintext")
+		}
+		if c.TLS.KeyFile == "" && !c.TLS.Key.IsSet() {
+			add("tls.key_file", "is required when tls is enabled", "or set tls.key to a secret reference")
+		}
+	}
+	forEachSecret(c, func(field string, s *Secret) error {
+		if u, err := url.Parse(s.Ref()); err != nil || u.Scheme == "" {
+			add(field, "must be a secret reference, not a literal value",
+				"use file://, env://, keyring:// or kms:// so secrets stay out of config files")
+		}
+		return nil
+	})
+	if !contains(validLogLevels, c.Logging.Level) {
+		add("logging.level", fmt.Sprintf("unknown level %q", c.Logging.Level), oneOf(validLogLevels))
+	}
+	if !contains(validLogFormats, c.Logging.Format) {
+		add("logging.format", fmt.Sprintf("unknown format %q", c.Logging.Format), oneOf(validLogFormats))
+	}
+	if !contains(validPolicyEngines, c.Policy.Engine) {
+		add("policy.engine", fmt.Sprintf("unknown engine %q", c.Policy.Engine), oneOf(validPolicyEngines))
+	}
+	if c.Policy.Engine == "opa" && c.Policy.Bundle == "" {
+		add("policy.bundle", "is required when policy.engine is \"opa\"", "point it at an OPA bundle directory or .tar.gz")
+	}
+	if c.Policy.Engine == "opa" && len(c.Policy.Rules) > 0 {
+		add("policy.rules", "built-in rules are ignored by the opa engine", "express them in the bundle instead")
+	}
+	for i, rule := range c.Policy.Rules {
+		if !contains(validPolicyEffects, rule.Effect) {
+			add(fmt.Sprintf("policy.rules[%d].effect", i), fmt.Sprintf("unknown effect %q", rule.Effect), oneOf(validPolicyEffects))
+		}
+	}
+	for i, rule := range c.Policy.Residency {
+		field := fmt.Sprintf("policy.residency[%d]", i)
+		if !contains(validResidencies, rule.Residency) {
+			add(field+".residency", fmt.Sprintf("unknown residency %q", rule.Residency), oneOf(validResidencies))
+		} else if rule.Residency == policy.ResidencyRegion && len(rule.Regions) == 0 {
+			add(field+".regions", "is required when residency is \"region\"", "list the regions the data may be processed in")
+		}
+	}
+	probes := make(map[string]bool)
+	for i, p := range c.Probes {
+		field := fmt.Sprintf("probes[%d]", i)
+		if err := p.Validate(); err != nil {
+			add(field, err.Error(), "")
+		} else if probes[p.Name] {
+			add(field+".name", fmt.Sprintf("duplicate probe %q", p.Name), "probe names must be unique")
+		}
+		probes[p.Name] = true
+	}
+	if c.Scaling.WindowSecs < 0 || c.Scaling.IntervalSecs < 0 {
+		add("scaling", "window_secs and interval_secs must not be negative", "use 0 for the defaults")
+	}
+	scaled := make(map[string]bool)
+	for i, p := range c.Scaling.Policies {
+		field := fmt.Sprintf("scaling.policies[%d]", i)
+		if err := p.Validate(); err != nil {
+			add(field, err.Error(), "")
+		} else if scaled[p.Action] {
+			add(field+".action", fmt.Sprintf("duplicate policy for %q", p.Action), "each action has one scaling policy")
+		}
+		scaled[p.Action] = true
+	}
+	if c.Retention.IntervalSecs < 0 {
+		add("retention.interval_secs", "must not be negative", "use 0 for the default")
+	}
+	policies := c.Retention.Policies()
+	for _, name := range []string{retention.Events, retention.Analytics, retention.Audit} {
+		if err := policies[name].Validate(); err != nil {
+			add("retention."+name, err.Error(), "use 0 for no bound")
+		}
+	}
+	if c.Metrics.ListenAddress != "" {
+		checkAddress(add, "metrics.listen_address", c.Metrics.ListenAddress)
+	}
+	if err := c.Metrics.Limits.Validate(); err != nil {
+		add("metrics.limits", err.Error(), "")
+	}
+	checkAddress(add, "runtime.broker_address", c.Runtime.BrokerAddress)
+	if c.Runtime.HeartbeatIntervalSecs <= 0 {
+		add("runtime.heartbeat_interval_secs", "must be greater than 0", "the default is 10")
+	} else if c.Runtime.HeartbeatIntervalSecs >= c.Broker.HeartbeatTimeoutSecs {
+		add("runtime.heartbeat_interval_secs",
+			fmt.Sprintf("interval %ds is not shorter than broker.heartbeat_timeout_secs (%ds)",
+				c.Runtime.HeartbeatIntervalSecs, c.Broker.HeartbeatTimeoutSecs),
+			"the broker would expire healthy services; keep the interval well below the timeout")
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// CheckFile parses and validates a configuration file without applying it.
+// Unknown keys are reported alongside validation errors.
+func CheckFile(path string, profile Profile) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	return Check(data, profile)
+}
+
+// Check parses and validates configuration data without applying it
+func Check(data []byte, profile Profile) error {
+	cfg, err := DefaultFor(profile)
+	if err != nil {
+		return err
+	}
+	md, err := toml.Decode(string(data), cfg)
+	if err != nil {
+		var perr toml.ParseError
+		if errors.As(err, &perr) {
+			return ValidationErrors{{Message: perr.ErrorWithPosition()}}
+		}
+		return ValidationErrors{{Message: err.Error()}}
+	}
+
+	var errs ValidationErrors
+	known := knownKeys()
+	var reported []string
+	for _, key := range md.Undecoded() {
+		name := key.String()
+		if isExternal(name) || hasPrefix(name, reported) {
+			continue
+		}
+		reported = append(reported, name)
+		errs = append(errs, FieldError{Field: name, Message: "unknown key", Hint: suggest(name, known)})
+	}
+	if verr, ok := cfg.Validate().(ValidationErrors); ok {
+		errs = append(errs, verr...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func checkAddress(add func(field, message, hint string), field, addr string) {
+	if addr == "" {
+		add(field, "cannot be empty", "expected host:port")
+		return
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		add(field, fmt.Sprintf("%q is not a valid address", addr), "expected host:port, e.g. 0.0.0.0:50051")
+	}
+}
+
+func isExternal(key string) bool {
+	return hasPrefix(key, externalSections)
+}
+
+// hasPrefix reports whether key equals or is nested below one of the prefixes
+func hasPrefix(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if key == prefix || strings.HasPrefix(key, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// knownKeys lists every dotted key the Config struct understands
+func knownKeys() []string {
+	var keys []string
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		section := strings.Split(t.Field(i).Tag.Get("toml"), ",")[0]
+		if section == "-" {
+			continue
+		}
+		keys = append(keys, section)
+		st := t.Field(i).Type
+		if st.Kind() == reflect.Slice {
+			st = st.Elem() // a list of tables such as [[probes]]
+		}
+		if st.Kind() != reflect.Struct {
+			continue
+		}
+		for j := 0; j < st.NumField(); j++ {
+			keys = append(keys, section+"."+strings.Split(st.Field(j).Tag.Get("toml"), ",")[0])
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// suggest returns a "did you mean" hint for the closest known key
+func suggest(key string, known []string) string {
+	best, bestDist := "", 4
+	for _, candidate := range known {
+		if d := editDistance(key, candidate); d < bestDist {
+			best, bestDist = candidate, d
+		}
+	}
+	if best == "" {
+		return "remove it or check the documentation for supported keys"
+	}
+	return fmt.Sprintf("did you mean %q?", best)
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func oneOf(values []string) string {
+	return "expected one of: " + strings.Join(values, ", ")
+}
+
+func contains(values []string, v string) bool {
+	for _, candidate := range values {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCheckProbes(t *testing.T) {
+	valid := `
+[[probes]]
+name = "translate"
+action = "translate_text"
+interval_secs = 30
+
+[probes.expect]
+contains = "hola"
+`
+	if err := Check([]byte(valid), ProfileDev); err != nil {
+		t.Fatalf("Check() of valid probes error = %v", err)
+	}
+
+	tests := []struct {
+		name, data string
+		want       FieldError
+	}{
+		{
+			name: "misspelled key",
+			data: "[[probes]]\nname = \"translate\"\nacton = \"translate_text\"\n",
+			want: FieldError{Field: "probes.acton", Message: "unknown key", Hint: `did you mean "probes.action"?`},
+		},
+		{
+			name: "missing action",
+			data: "[[probes]]\nname = \"translate\"\n",
+			want: FieldError{Field: "probes[0]", Message: "probe translate: action is required"},
+		},
+		{
+			name: "duplicate name",
+			data: "[[probes]]\nname = \"translate\"\naction = \"a\"\n\n[[probes]]\nname = \"translate\"\naction = \"b\"\n",
+			want: FieldError{Field: "probes[1].name", Message: `duplicate probe "translate"`, Hint: "probe names must be unique"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs ValidationErrors
+			if err := Check([]byte(tt.data), ProfileDev); !errors.As(err, &errs) {
+				t.Fatalf("Check() error = %v, want ValidationErrors", err)
+			}
+			for _, e := range errs {
+				if e == tt.want {
+					return
+				}
+			}
+			t.Errorf("Check() = %v, want %v among the errors", errs, tt.want)
+		})
+	}
+}
+
+func TestCheckMetricsLimits(t *testing.T) {
+	valid := `
+[metrics]
+listen_address = "0.0.0.0:9464"
+
+[metrics.limits]
+labels = ["action"]
+top_k = 50
+
+[[metrics.limits.buckets.action]]
+pattern = "translate_*"
+name = "translate"
+`
+	if err := Check([]byte(valid), ProfileDev); err != nil {
+		t.Fatalf("Check() of valid metrics limits error = %v", err)
+	}
+	cfg, err := ParseProfile([]byte(valid), ProfileDev)
+	if err != nil {
+		t.Fatalf("ParseProfile() error = %v", err)
+	}
+	if limits := cfg.Metrics.Limits; limits.TopK != 50 || limits.Buckets["action"][0].Name != "translate" {
+		t.Errorf("metrics limits = %+v, want the top 50 and the translate bucket", limits)
+	}
+
+	var errs ValidationErrors
+	if err := Check([]byte("[metrics.limits]\nlabels = [\"consumer\"]\n"), ProfileDev); !errors.As(err, &errs) {
+		t.Fatalf("Check() error = %v, want ValidationErrors", err)
+	}
+	want := FieldError{Field: "metrics.limits", Message: `unknown label "consumer", expected one of [contract service_id method action]`}
+	if !slices.Contains(errs, want) {
+		t.Errorf("Check() = %v, want %v among the errors", errs, want)
+	}
+}
@@ -0,0 +1,42 @@
+// nfa-kafka-connector bridges NFA pub/sub topics and Kafka topics
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/connector/kafka"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func main() {
+	configPath := flag.String("config", "config/kafka-connector.toml", "Connector configuration file")
+	flag.Parse()
+
+	cfg, err := kafka.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	conn, err := grpc.Dial(cfg.BrokerAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Failed to connect to broker: %v", err)
+	}
+	defer conn.Close()
+
+	connector, err := kafka.NewConnector(cfg, conn)
+	if err != nil {
+		log.Fatalf("Failed to create connector: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	log.Printf("Kafka connector running %d export and %d import routes", len(cfg.Export), len(cfg.Import))
+	connector.Run(ctx)
+	log.Printf("Kafka connector stopped")
+}
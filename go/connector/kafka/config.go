@@ -0,0 +1,98 @@
+// Package kafka bridges NFA pub/sub topics and Kafka topics, so enterprise
+// data pipelines can consume ambient intent events and feed events back in.
+// Export routes copy events of an NFA topic to a Kafka topic through a
+// durable subscription; import routes publish the records of a Kafka topic to
+// an NFA topic. A Mapping describes how events and records translate.
+package kafka
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config describes the Kafka clusters and routes served by a connector
+type Config struct {
+	// BrokerAddress is the NFA broker serving the pub/sub API
+	BrokerAddress string `toml:"broker_address"`
+	// KafkaBrokers are the bootstrap addresses of the Kafka cluster
+	KafkaBrokers []string `toml:"kafka_brokers"`
+	// ClientID identifies the connector to Kafka
+	ClientID string `toml:"client_id"`
+
+	Export []ExportRoute `toml:"export"`
+	Import []ImportRoute `toml:"import"`
+}
+
+// ExportRoute copies the events of an NFA topic to a Kafka topic
+type ExportRoute struct {
+	// Topic is the NFA topic to export
+	Topic string `toml:"topic"`
+	// Subscription is the durable NFA subscription used to read the topic;
+	// it keeps the export position across connector restarts
+	Subscription string `toml:"subscription"`
+	// KafkaTopic is the destination Kafka topic
+	KafkaTopic string `toml:"kafka_topic"`
+	// Filter only exports events whose attributes contain every entry
+	Filter  map[string]string `toml:"filter,omitempty"`
+	Mapping Mapping           `toml:"mapping"`
+}
+
+// ImportRoute publishes the records of a Kafka topic to an NFA topic
+type ImportRoute struct {
+	// KafkaTopic is the source Kafka topic
+	KafkaTopic string `toml:"kafka_topic"`
+	// GroupID is the Kafka consumer group whose committed offsets keep the
+	// import position across connector restarts
+	GroupID string `toml:"group_id"`
+	// Topic is the destination NFA topic
+	Topic   string  `toml:"topic"`
+	Mapping Mapping `toml:"mapping"`
+}
+
+// LoadConfig reads a connector configuration file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read connector config: %w", err)
+	}
+	cfg := &Config{
+		BrokerAddress: "localhost:50051",
+		ClientID:      "nfa-kafka-connector",
+	}
+	if _, err := toml.Decode(string(data), cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse connector config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate checks that the configuration describes at least one complete route
+func (c *Config) Validate() error {
+	if len(c.KafkaBrokers) == 0 {
+		return fmt.Errorf("kafka_brokers is required")
+	}
+	if len(c.Export) == 0 && len(c.Import) == 0 {
+		return fmt.Errorf("at least one export or import route is required")
+	}
+	for i, route := range c.Export {
+		if route.Topic == "" || route.Subscription == "" || route.KafkaTopic == "" {
+			return fmt.Errorf("export[%d]: topic, subscription and kafka_topic are required", i)
+		}
+		if err := route.Mapping.Validate(); err != nil {
+			return fmt.Errorf("export[%d]: %w", i, err)
+		}
+	}
+	for i, route := range c.Import {
+		if route.KafkaTopic == "" || route.GroupID == "" || route.Topic == "" {
+			return fmt.Errorf("import[%d]: kafka_topic, group_id and topic are required", i)
+		}
+		if err := route.Mapping.Validate(); err != nil {
+			return fmt.Errorf("import[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/control"
+	nfa_pubsub_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/pubsub/v1alpha"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pubsub"
+	kafkago "github.com/segmentio/kafka-go"
+	"google.golang.org/grpc"
+)
+
+const (
+	retryInitial = time.Second
+	retryMax     = 30 * time.Second
+)
+
+// Connector runs the export and import routes of a Config
+type Connector struct {
+	cfg    *Config
+	pubsub *pubsub.Client
+	client nfa_pubsub_v1alpha.PubSubServiceClient
+}
+
+// NewConnector creates a connector using an existing NFA broker connection
+func NewConnector(cfg *Config, cc grpc.ClientConnInterface) (*Connector, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Connector{
+		cfg:    cfg,
+		pubsub: pubsub.NewClient(cc),
+		client: nfa_pubsub_v1alpha.NewPubSubServiceClient(cc),
+	}, nil
+}
+
+// Run serves every route until ctx is cancelled. Routes retry transient
+// failures of either side on their own, so one unavailable topic does not
+// stop the others.
+func (c *Connector) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, route := range c.cfg.Export {
+		wg.Add(1)
+		go func(route ExportRoute) {
+			defer wg.Done()
+			c.runExport(ctx, route)
+		}(route)
+	}
+	for _, route := range c.cfg.Import {
+		wg.Add(1)
+		go func(route ImportRoute) {
+			defer wg.Done()
+			c.runImport(ctx, route)
+		}(route)
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+func (c *Connector) runExport(ctx context.Context, route ExportRoute) {
+	writer := &kafkago.Writer{
+		Addr:         kafkago.TCP(c.cfg.KafkaBrokers...),
+		Topic:        route.KafkaTopic,
+		Balancer:     &kafkago.Hash{},
+		RequiredAcks: kafkago.RequireAll,
+		Transport:    &kafkago.Transport{ClientID: c.cfg.ClientID},
+	}
+	defer writer.Close()
+
+	handler := func(ctx context.Context, event *nfa_pubsub_v1alpha.Event) error {
+		if !control.MatchLabels(route.Filter, event.Attributes) {
+			return nil
+		}
+		msg, err := route.Mapping.Encode(event)
+		if err != nil {
+			// Unencodable events would fail forever; skip them instead of blocking the route
+			log.Printf("Kafka export %s: %v", route.Topic, err)
+			return nil
+		}
+		if err := writer.WriteMessages(ctx, msg); err != nil {
+			log.Printf("Kafka export %s: failed to write to %s: %v", route.Topic, route.KafkaTopic, err)
+			return err
+		}
+		return nil
+	}
+
+	retry(ctx, func() error {
+		_, err := c.pubsub.CreateSubscription(ctx, &nfa_pubsub_v1alpha.CreateSubscriptionRequest{
+			Name:  route.Subscription,
+			Topic: route.Topic,
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("Exporting %s to Kafka topic %s", route.Topic, route.KafkaTopic)
+		if err := c.pubsub.Subscribe(ctx, route.Subscription, handler); err != nil {
+			return err
+		}
+		return fmt.Errorf("subscription %s ended", route.Subscription)
+	})
+}
+
+func (c *Connector) runImport(ctx context.Context, route ImportRoute) {
+	reader := kafkago.NewReader(kafkago.ReaderConfig{
+		Brokers: c.cfg.KafkaBrokers,
+		GroupID: route.GroupID,
+		Topic:   route.KafkaTopic,
+		Dialer:  &kafkago.Dialer{ClientID: c.cfg.ClientID, Timeout: 10 * time.Second, DualStack: true},
+	})
+	defer reader.Close()
+	log.Printf("Importing Kafka topic %s to %s", route.KafkaTopic, route.Topic)
+
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Kafka import %s: failed to fetch record: %v", route.KafkaTopic, err)
+			if !sleep(ctx, retryInitial) {
+				return
+			}
+			continue
+		}
+
+		req, err := route.Mapping.Decode(route.Topic, msg)
+		if err != nil {
+			log.Printf("Kafka import %s: skipping record: %v", route.KafkaTopic, err)
+		} else {
+			// The offset is committed only after the event is published, so
+			// records are imported at least once
+			retry(ctx, func() error {
+				_, err := c.client.Publish(ctx, req)
+				if err != nil {
+					log.Printf("Kafka import %s: failed to publish to %s: %v", route.KafkaTopic, route.Topic, err)
+				}
+				return err
+			})
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if err := reader.CommitMessages(ctx, msg); err != nil && ctx.Err() == nil {
+			log.Printf("Kafka import %s: failed to commit offset %d: %v", route.KafkaTopic, msg.Offset, err)
+		}
+	}
+}
+
+// retry calls fn with exponential backoff until it returns nil or ctx is cancelled
+func retry(ctx context.Context, fn func() error) {
+	backoff := retryInitial
+	for {
+		err := fn()
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		log.Printf("Kafka connector: %v, retrying in %s", err, backoff)
+		if !sleep(ctx, backoff) {
+			return
+		}
+		if backoff *= 2; backoff > retryMax {
+			backoff = retryMax
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
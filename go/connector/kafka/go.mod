@@ -0,0 +1,25 @@
+module github.com/neuro-fluidic-architecture/nfa-core/go/connector/kafka
+
+go 1.21
+
+require (
+	github.com/BurntSushi/toml v1.3.2
+	github.com/neuro-fluidic-architecture/nfa-core/go v0.0.0
+	github.com/segmentio/kafka-go v0.4.47
+	google.golang.org/grpc v1.59.0
+)
+
+require (
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/klauspost/compress v1.16.0 // indirect
+	github.com/pierrec/lz4/v4 v4.1.15 // indirect
+	github.com/stretchr/testify v1.8.4 // indirect
+	golang.org/x/net v0.17.0 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+	golang.org/x/text v0.13.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20230913181813-007df8e322eb // indirect
+	google.golang.org/protobuf v1.31.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/neuro-fluidic-architecture/nfa-core/go => ../..
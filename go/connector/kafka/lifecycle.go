@@ -0,0 +1,41 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	nfa_pubsub_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/pubsub/v1alpha"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pubsub"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/webhook"
+)
+
+// LifecycleTopic is the pub/sub topic lifecycle events are mirrored to
+const LifecycleTopic = "nfa.lifecycle"
+
+// EventTypeAttribute carries the lifecycle event type, so export routes can
+// filter on it
+const EventTypeAttribute = "nfa.event_type"
+
+// MirrorLifecycle publishes every lifecycle event emitted by d to
+// LifecycleTopic on broker, where export routes can forward them to Kafka.
+// Events of one service share an ordering key and keep their order.
+func MirrorLifecycle(d *webhook.Dispatcher, broker *pubsub.Broker) {
+	d.OnEmit(func(event webhook.Event) {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return
+		}
+		req := &nfa_pubsub_v1alpha.PublishRequest{
+			Topic:      LifecycleTopic,
+			Payload:    payload,
+			Attributes: map[string]string{EventTypeAttribute: event.Type},
+		}
+		if serviceID, ok := event.Data["service_id"].(string); ok {
+			req.OrderingKey = serviceID
+		}
+		if _, err := broker.Publish(context.Background(), req); err != nil {
+			log.Printf("Failed to mirror lifecycle event %s: %v", event.ID, err)
+		}
+	})
+}
@@ -0,0 +1,247 @@
+package kafka
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	nfa_pubsub_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/pubsub/v1alpha"
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+// Record value formats
+const (
+	// FormatEnvelope wraps the event in a JSON object carrying its topic,
+	// sequence, ordering key, publish time, attributes and payload
+	FormatEnvelope = "envelope"
+	// FormatRaw uses the payload as the record value and carries attributes in headers
+	FormatRaw = "raw"
+)
+
+// Record key sources
+const (
+	// KeyOrderingKey maps the event ordering key to the record key, so Kafka
+	// partitions preserve the per-key order of the NFA topic
+	KeyOrderingKey = "ordering_key"
+	// KeyNone leaves records unkeyed
+	KeyNone = "none"
+
+	// keyAttribute maps the named attribute to the record key, e.g. "attribute:device_id"
+	keyAttribute = "attribute:"
+)
+
+// Envelope field names, which Mapping.Fields can rename
+const (
+	fieldTopic           = "topic"
+	fieldSequence        = "sequence"
+	fieldOrderingKey     = "ordering_key"
+	fieldPublishTime     = "publish_time"
+	fieldAttributes      = "attributes"
+	fieldPayload         = "payload"
+	fieldPayloadEncoding = "payload_encoding"
+)
+
+var envelopeFields = []string{
+	fieldTopic, fieldSequence, fieldOrderingKey, fieldPublishTime,
+	fieldAttributes, fieldPayload, fieldPayloadEncoding,
+}
+
+// Mapping describes how NFA events translate to Kafka records and back
+type Mapping struct {
+	// Format is FormatEnvelope (default) or FormatRaw
+	Format string `toml:"format"`
+	// Key is KeyOrderingKey (default), KeyNone or "attribute:<name>"
+	Key string `toml:"key"`
+	// HeaderPrefix is prepended to attribute names to form record header
+	// names. On import only headers with the prefix become attributes.
+	HeaderPrefix string `toml:"header_prefix"`
+	// Fields renames envelope fields to match the schema of the pipeline,
+	// e.g. {payload = "data", publish_time = "ts"}
+	Fields map[string]string `toml:"fields,omitempty"`
+}
+
+// Validate checks the format, key source and field renames
+func (m Mapping) Validate() error {
+	switch m.Format {
+	case "", FormatEnvelope, FormatRaw:
+	default:
+		return fmt.Errorf("unknown mapping format %q", m.Format)
+	}
+	switch {
+	case m.Key == "", m.Key == KeyOrderingKey, m.Key == KeyNone:
+	case strings.HasPrefix(m.Key, keyAttribute) && len(m.Key) > len(keyAttribute):
+	default:
+		return fmt.Errorf("mapping key must be %q, %q or \"attribute:<name>\", got %q", KeyOrderingKey, KeyNone, m.Key)
+	}
+	for field := range m.Fields {
+		if !isEnvelopeField(field) {
+			return fmt.Errorf("unknown envelope field %q", field)
+		}
+	}
+	targets := make(map[string]string)
+	for _, field := range envelopeFields {
+		name := m.field(field)
+		if name == "" {
+			return fmt.Errorf("envelope field %s cannot be renamed to an empty name", field)
+		}
+		if other, ok := targets[name]; ok {
+			return fmt.Errorf("envelope fields %s and %s both map to %q", other, field, name)
+		}
+		targets[name] = field
+	}
+	return nil
+}
+
+// Encode converts an NFA event into a Kafka record
+func (m Mapping) Encode(event *nfa_pubsub_v1alpha.Event) (kafkago.Message, error) {
+	msg := kafkago.Message{
+		Key:  m.encodeKey(event),
+		Time: event.PublishTime.AsTime(),
+	}
+
+	if m.Format == FormatRaw {
+		msg.Value = event.Payload
+		for name, value := range event.Attributes {
+			msg.Headers = append(msg.Headers, kafkago.Header{Key: m.HeaderPrefix + name, Value: []byte(value)})
+		}
+		return msg, nil
+	}
+
+	envelope := map[string]interface{}{
+		m.field(fieldTopic):       event.Topic,
+		m.field(fieldSequence):    event.Sequence,
+		m.field(fieldPublishTime): event.PublishTime.AsTime().UTC().Format(time.RFC3339Nano),
+	}
+	if event.OrderingKey != "" {
+		envelope[m.field(fieldOrderingKey)] = event.OrderingKey
+	}
+	if len(event.Attributes) > 0 {
+		envelope[m.field(fieldAttributes)] = event.Attributes
+	}
+	if json.Valid(event.Payload) {
+		envelope[m.field(fieldPayload)] = json.RawMessage(event.Payload)
+	} else if len(event.Payload) > 0 {
+		envelope[m.field(fieldPayload)] = base64.StdEncoding.EncodeToString(event.Payload)
+		envelope[m.field(fieldPayloadEncoding)] = "base64"
+	}
+	value, err := json.Marshal(envelope)
+	if err != nil {
+		return msg, fmt.Errorf("failed to encode event %d of %s: %w", event.Sequence, event.Topic, err)
+	}
+	msg.Value = value
+	return msg, nil
+}
+
+// Decode converts a Kafka record into a publish request for topic
+func (m Mapping) Decode(topic string, msg kafkago.Message) (*nfa_pubsub_v1alpha.PublishRequest, error) {
+	req := &nfa_pubsub_v1alpha.PublishRequest{
+		Topic:      topic,
+		Attributes: make(map[string]string),
+	}
+
+	if m.Format == FormatRaw {
+		req.Payload = msg.Value
+		for _, header := range msg.Headers {
+			if name, ok := strings.CutPrefix(header.Key, m.HeaderPrefix); ok && name != "" {
+				req.Attributes[name] = string(header.Value)
+			}
+		}
+	} else {
+		var envelope map[string]json.RawMessage
+		if err := json.Unmarshal(msg.Value, &envelope); err != nil {
+			return nil, fmt.Errorf("record at offset %d is not a JSON envelope: %w", msg.Offset, err)
+		}
+		if raw, ok := envelope[m.field(fieldAttributes)]; ok {
+			if err := json.Unmarshal(raw, &req.Attributes); err != nil {
+				return nil, fmt.Errorf("record at offset %d has invalid attributes: %w", msg.Offset, err)
+			}
+		}
+		if raw, ok := envelope[m.field(fieldOrderingKey)]; ok {
+			json.Unmarshal(raw, &req.OrderingKey)
+		}
+		payload, err := m.decodePayload(envelope)
+		if err != nil {
+			return nil, fmt.Errorf("record at offset %d: %w", msg.Offset, err)
+		}
+		req.Payload = payload
+	}
+
+	m.decodeKey(req, msg.Key)
+	return req, nil
+}
+
+func (m Mapping) decodePayload(envelope map[string]json.RawMessage) ([]byte, error) {
+	raw, ok := envelope[m.field(fieldPayload)]
+	if !ok {
+		return nil, nil
+	}
+	var encoding string
+	if enc, ok := envelope[m.field(fieldPayloadEncoding)]; ok {
+		json.Unmarshal(enc, &encoding)
+	}
+	switch encoding {
+	case "":
+		return raw, nil
+	case "base64":
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, fmt.Errorf("base64 payload must be a string")
+		}
+		payload, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 payload: %w", err)
+		}
+		return payload, nil
+	default:
+		return nil, fmt.Errorf("unknown payload encoding %q", encoding)
+	}
+}
+
+func (m Mapping) encodeKey(event *nfa_pubsub_v1alpha.Event) []byte {
+	switch {
+	case m.Key == KeyNone:
+		return nil
+	case strings.HasPrefix(m.Key, keyAttribute):
+		if value, ok := event.Attributes[strings.TrimPrefix(m.Key, keyAttribute)]; ok {
+			return []byte(value)
+		}
+		return nil
+	default:
+		if event.OrderingKey == "" {
+			return nil
+		}
+		return []byte(event.OrderingKey)
+	}
+}
+
+func (m Mapping) decodeKey(req *nfa_pubsub_v1alpha.PublishRequest, key []byte) {
+	if len(key) == 0 {
+		return
+	}
+	switch {
+	case m.Key == KeyNone:
+	case strings.HasPrefix(m.Key, keyAttribute):
+		req.Attributes[strings.TrimPrefix(m.Key, keyAttribute)] = string(key)
+	default:
+		req.OrderingKey = string(key)
+	}
+}
+
+// field returns the envelope name of a field after renames
+func (m Mapping) field(name string) string {
+	if renamed, ok := m.Fields[name]; ok {
+		return renamed
+	}
+	return name
+}
+
+func isEnvelopeField(name string) bool {
+	for _, field := range envelopeFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package control
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const defaultBroadcastTimeout = 10 * time.Second
+
+// maxBatchSize bounds the intents of one BroadcastBatch call
+const maxBatchSize = 100
+
+// traceHeaders are the metadata keys of the W3C trace context and baggage
+var traceHeaders = []string{"traceparent", "tracestate", "baggage"}
+
+type targetResult struct {
+	runtimeID string
+	result    *nfa_control_v1alpha.CommandResult
+}
+
+// Broadcast implements the Broadcast RPC. The intent is queued for every
+// connected runtime matching the selector, and the call returns once every
+// target has answered or the timeout expires, with one status per target.
+// When shadow rules are set, a copy of the intent is also sent to the shadow
+// runtimes they select; their results are recorded but never returned.
+func (h *Hub) Broadcast(ctx context.Context, req *nfa_control_v1alpha.BroadcastRequest) (*nfa_control_v1alpha.BroadcastResponse, error) {
+	if req.Intent == nil || req.Intent.Action == "" {
+		return nil, status.Error(codes.InvalidArgument, "intent with an action is required")
+	}
+	timeout := defaultBroadcastTimeout
+	if req.TimeoutSecs > 0 {
+		timeout = time.Duration(req.TimeoutSecs) * time.Second
+	}
+
+	propagateTrace(ctx, req.Intent)
+	cmd := &nfa_control_v1alpha.Command{
+		Command: &nfa_control_v1alpha.Command_Invoke{Invoke: req.Intent},
+	}
+	statuses := make(map[string]*nfa_control_v1alpha.TargetStatus)
+
+	h.mu.Lock()
+	h.assignCommandID(cmd)
+	for _, sess := range h.sessions {
+		if !MatchLabels(req.Selector, sess.labels) {
+			continue
+		}
+		target := &nfa_control_v1alpha.TargetStatus{
+			RuntimeId: sess.runtimeID,
+			State:     nfa_control_v1alpha.TargetState_TARGET_STATE_TIMED_OUT,
+		}
+		if !enqueueCommand(sess, cmd) {
+			target.State = nfa_control_v1alpha.TargetState_TARGET_STATE_UNDELIVERED
+		}
+		statuses[sess.runtimeID] = target
+	}
+	results := make(chan targetResult, len(statuses))
+	h.broadcasts[cmd.CommandId] = results
+	sent := time.Now()
+	mirrored := h.mirror(req.Intent, statuses)
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		delete(h.broadcasts, cmd.CommandId)
+		h.mu.Unlock()
+	}()
+
+	waiting := 0
+	for _, target := range statuses {
+		if target.State == nfa_control_v1alpha.TargetState_TARGET_STATE_TIMED_OUT {
+			waiting++
+		}
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for waiting > 0 {
+		select {
+		case r := <-results:
+			target, ok := statuses[r.runtimeID]
+			if !ok || target.State != nfa_control_v1alpha.TargetState_TARGET_STATE_TIMED_OUT {
+				continue
+			}
+			target.State = nfa_control_v1alpha.TargetState_TARGET_STATE_SUCCEEDED
+			if !r.result.Success {
+				target.State = nfa_control_v1alpha.TargetState_TARGET_STATE_FAILED
+				target.Error = r.result.Error
+			}
+			target.Output = r.result.Output
+			target.Provenance = r.result.Provenance
+			target.Fulfillment = brokered(r.result.Fulfillment, time.Since(sent))
+			waiting--
+		case <-timer.C:
+			waiting = 0
+		case <-ctx.Done():
+			waiting = 0
+		}
+	}
+
+	resp := &nfa_control_v1alpha.BroadcastResponse{CommandId: cmd.CommandId}
+	for _, target := range statuses {
+		resp.Targets = append(resp.Targets, target)
+	}
+	sort.Slice(resp.Targets, func(i, j int) bool { return resp.Targets[i].RuntimeId < resp.Targets[j].RuntimeId })
+	if mirrored != nil {
+		go h.collectShadow(mirrored, resp.Targets, timeout)
+	}
+	return resp, nil
+}
+
+// BroadcastBatch implements the BroadcastBatch RPC. Every request is
+// broadcast as by Broadcast, all of them concurrently, so the call takes as
+// long as the slowest. Results are in the order of the requests; a refused
+// request fails alone, with its error in its result.
+func (h *Hub) BroadcastBatch(ctx context.Context, req *nfa_control_v1alpha.BroadcastBatchRequest) (*nfa_control_v1alpha.BroadcastBatchResponse, error) {
+	if len(req.Requests) > maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "batch of %d intents exceeds the maximum of %d", len(req.Requests), maxBatchSize)
+	}
+	results := make([]*nfa_control_v1alpha.BroadcastResult, len(req.Requests))
+	var wg sync.WaitGroup
+	for i, r := range req.Requests {
+		wg.Add(1)
+		go func(i int, r *nfa_control_v1alpha.BroadcastRequest) {
+			defer wg.Done()
+			resp, err := h.Broadcast(ctx, r)
+			if err != nil {
+				results[i] = &nfa_control_v1alpha.BroadcastResult{Error: status.Convert(err).Message()}
+				return
+			}
+			results[i] = &nfa_control_v1alpha.BroadcastResult{Response: resp}
+		}(i, r)
+	}
+	wg.Wait()
+	return &nfa_control_v1alpha.BroadcastBatchResponse{Results: results}, nil
+}
+
+// brokered adds the broker's hop to the fulfillment reported by a runtime.
+// Time the intent spent on the control stream beyond the runtime's
+// processing time counts as queue time.
+func brokered(f *nfa_intent_v1alpha.Fulfillment, elapsed time.Duration) *nfa_intent_v1alpha.Fulfillment {
+	if f == nil {
+		return nil
+	}
+	out := proto.Clone(f).(*nfa_intent_v1alpha.Fulfillment)
+	out.Hops++
+	if total := uint64(elapsed.Milliseconds()); total > out.ProcessingTimeMs {
+		out.QueueTimeMs = total - out.ProcessingTimeMs
+	}
+	return out
+}
+
+// propagateTrace copies the trace context of the broadcaster's request into
+// intent, unless it carries one already, so the runtimes handling it
+// continue the broadcaster's trace
+func propagateTrace(ctx context.Context, intent *nfa_control_v1alpha.Invoke) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(intent.TraceContext) > 0 {
+		return
+	}
+	for _, key := range traceHeaders {
+		if values := md.Get(key); len(values) > 0 {
+			if intent.TraceContext == nil {
+				intent.TraceContext = make(map[string]string)
+			}
+			intent.TraceContext[key] = values[0]
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package control
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/fulfillment"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc"
+)
+
+// Handlers react to messages pushed by the broker. A returned error is
+// reported back to the broker as a rejected ack or failed command result.
+// Commands without a handler are reported as unsupported.
+type Handlers struct {
+	OnConfig     func(fragment *nfa_control_v1alpha.ConfigFragment) error
+	OnDrain      func(drain *nfa_control_v1alpha.Drain) error
+	OnReRegister func(reRegister *nfa_control_v1alpha.ReRegister) error
+	OnRevoke     func(revoke *nfa_control_v1alpha.Revoke) error
+	// OnGoAway is told that the broker is shutting down; the stream ends
+	// after it
+	OnGoAway func(goAway *nfa_control_v1alpha.GoAway) error
+	// OnInvoke handles an intent broadcast to this runtime; its output is
+	// returned to the broadcaster. It may complete the invoke's parameters;
+	// for a debug invoke the provenance it records in invoke.Provenance is
+	// returned as well.
+	OnInvoke func(invoke *nfa_control_v1alpha.Invoke) ([]byte, error)
+}
+
+// Client is the runtime side of the control plane
+type Client struct {
+	client nfa_control_v1alpha.ControlServiceClient
+}
+
+// NewClient creates a control client on an existing broker connection
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{
+		client: nfa_control_v1alpha.NewControlServiceClient(cc),
+	}
+}
+
+// Run opens the control stream and dispatches broker messages until the
+// stream ends, the broker goes away or ctx is cancelled
+func (c *Client) Run(ctx context.Context, hello *nfa_control_v1alpha.Hello, handlers Handlers) error {
+	stream, err := c.client.Connect(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open control stream: %w", err)
+	}
+	defer stream.CloseSend()
+
+	err = stream.Send(&nfa_control_v1alpha.RuntimeMessage{
+		Message: &nfa_control_v1alpha.RuntimeMessage_Hello{Hello: hello},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send hello: %w", err)
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("control stream failed: %w", err)
+		}
+
+		var reply *nfa_control_v1alpha.RuntimeMessage
+		switch m := msg.Message.(type) {
+		case *nfa_control_v1alpha.BrokerMessage_ConfigUpdate:
+			ack := &nfa_control_v1alpha.ConfigAck{Version: m.ConfigUpdate.Version, Applied: true}
+			if err := handlers.config(m.ConfigUpdate.Fragment); err != nil {
+				ack.Applied = false
+				ack.Error = err.Error()
+			}
+			reply = &nfa_control_v1alpha.RuntimeMessage{
+				Message: &nfa_control_v1alpha.RuntimeMessage_ConfigAck{ConfigAck: ack},
+			}
+		case *nfa_control_v1alpha.BrokerMessage_Command:
+			if m.Command.GetGoAway() != nil {
+				// The broker has ended the stream and takes no result
+				handlers.command(m.Command)
+				return nil
+			}
+			result := &nfa_control_v1alpha.CommandResult{CommandId: m.Command.CommandId, Success: true}
+			start := time.Now()
+			output, err := handlers.command(m.Command)
+			if err != nil {
+				result.Success = false
+				result.Error = err.Error()
+			}
+			result.Output = output
+			if invoke := m.Command.GetInvoke(); invoke != nil {
+				result.Fulfillment = &nfa_intent_v1alpha.Fulfillment{
+					FulfillmentId:    fulfillment.NewID(),
+					ServiceId:        firstServiceID(hello),
+					ProcessingTimeMs: uint64(time.Since(start).Milliseconds()),
+					Hops:             1,
+				}
+				if invoke.Debug {
+					result.Provenance = invoke.Provenance
+				}
+			}
+			reply = &nfa_control_v1alpha.RuntimeMessage{
+				Message: &nfa_control_v1alpha.RuntimeMessage_CommandResult{CommandResult: result},
+			}
+		default:
+			continue
+		}
+
+		if err := stream.Send(reply); err != nil {
+			return fmt.Errorf("failed to reply on control stream: %w", err)
+		}
+	}
+}
+
+func firstServiceID(hello *nfa_control_v1alpha.Hello) string {
+	if len(hello.ServiceIds) == 0 {
+		return ""
+	}
+	return hello.ServiceIds[0]
+}
+
+func (h Handlers) config(fragment *nfa_control_v1alpha.ConfigFragment) error {
+	if h.OnConfig == nil {
+		return fmt.Errorf("config updates are not supported")
+	}
+	return h.OnConfig(fragment)
+}
+
+func (h Handlers) command(cmd *nfa_control_v1alpha.Command) ([]byte, error) {
+	switch c := cmd.Command.(type) {
+	case *nfa_control_v1alpha.Command_Drain:
+		if h.OnDrain != nil {
+			return nil, h.OnDrain(c.Drain)
+		}
+	case *nfa_control_v1alpha.Command_ReRegister:
+		if h.OnReRegister != nil {
+			return nil, h.OnReRegister(c.ReRegister)
+		}
+	case *nfa_control_v1alpha.Command_Revoke:
+		if h.OnRevoke != nil {
+			return nil, h.OnRevoke(c.Revoke)
+		}
+	case *nfa_control_v1alpha.Command_Invoke:
+		if h.OnInvoke != nil {
+			return h.OnInvoke(c.Invoke)
+		}
+	case *nfa_control_v1alpha.Command_GoAway:
+		if h.OnGoAway != nil {
+			return nil, h.OnGoAway(c.GoAway)
+		}
+	}
+	return nil, fmt.Errorf("unsupported command %T", cmd.Command)
+}
@@ -0,0 +1,417 @@
+// Package control implements the control stream between the Intent Broker and
+// connected runtimes, used to push configuration, commands (drain,
+// re-register, revoke, go away) and broadcast intents to providers without
+// waiting for their heartbeats
+package control
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/shadow"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// sendBuffer bounds the number of undelivered messages queued per runtime
+const sendBuffer = 16
+
+// maxCommandResults bounds the number of command results kept for inspection
+const maxCommandResults = 1000
+
+// ErrNotConnected is returned when a command targets a runtime without an open control stream
+var ErrNotConnected = errors.New("runtime is not connected")
+
+// Hub is the broker side of the control plane. It tracks connected runtimes
+// and pushes label-scoped configuration fragments to them. Fragments are
+// retained, so runtimes that connect later still receive them.
+type Hub struct {
+	nfa_control_v1alpha.UnimplementedControlServiceServer
+	nfa_control_v1alpha.UnimplementedBroadcastServiceServer
+
+	mu        sync.Mutex
+	sessions  map[string]*session
+	fragments map[string]*scopedFragment
+	version   uint64
+
+	commandSeq   uint64
+	results      map[string]*nfa_control_v1alpha.CommandResult
+	resultOrder  []string
+	resultNotify func(runtimeID string, result *nfa_control_v1alpha.CommandResult)
+	broadcasts   map[string]chan targetResult // command ID -> results of a broadcast in progress
+
+	shadowRules    []shadow.Rule
+	shadowRecorder *shadow.Recorder
+
+	goAway *nfa_control_v1alpha.Command // set once the broker shuts down
+	gone   chan struct{}                // closed when the last stream has ended after goAway
+}
+
+type session struct {
+	runtimeID    string
+	labels       map[string]string
+	send         chan *nfa_control_v1alpha.BrokerMessage
+	goAway       chan *nfa_control_v1alpha.BrokerMessage
+	ackedVersion uint64
+}
+
+type scopedFragment struct {
+	selector map[string]string
+	fragment *nfa_control_v1alpha.ConfigFragment
+	version  uint64
+}
+
+// SessionInfo describes a connected runtime
+type SessionInfo struct {
+	RuntimeID    string
+	Labels       map[string]string
+	AckedVersion uint64
+}
+
+// NewHub creates an empty control hub
+func NewHub() *Hub {
+	return &Hub{
+		sessions:   make(map[string]*session),
+		fragments:  make(map[string]*scopedFragment),
+		results:    make(map[string]*nfa_control_v1alpha.CommandResult),
+		broadcasts: make(map[string]chan targetResult),
+	}
+}
+
+// OnCommandResult registers a callback for command results reported by runtimes
+func (h *Hub) OnCommandResult(fn func(runtimeID string, result *nfa_control_v1alpha.CommandResult)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.resultNotify = fn
+}
+
+// SetShadow mirrors broadcast intents to the runtimes selected by rules, see
+// Broadcast. Their results are recorded in rec; nil rules disable mirroring.
+func (h *Hub) SetShadow(rules []shadow.Rule, rec *shadow.Recorder) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.shadowRules = rules
+	h.shadowRecorder = rec
+}
+
+// Register registers the control and broadcast services on a gRPC server
+func (h *Hub) Register(registrar grpc.ServiceRegistrar) {
+	nfa_control_v1alpha.RegisterControlServiceServer(registrar, h)
+	nfa_control_v1alpha.RegisterBroadcastServiceServer(registrar, h)
+}
+
+// Connect implements the control stream RPC
+func (h *Hub) Connect(stream nfa_control_v1alpha.ControlService_ConnectServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	hello := first.GetHello()
+	if hello == nil || hello.RuntimeId == "" {
+		return status.Error(codes.InvalidArgument, "first control message must be a hello with a runtime id")
+	}
+
+	sess := &session{
+		runtimeID: hello.RuntimeId,
+		labels:    hello.Labels,
+		send:      make(chan *nfa_control_v1alpha.BrokerMessage, sendBuffer),
+		goAway:    make(chan *nfa_control_v1alpha.BrokerMessage, 1),
+	}
+	if !h.attach(sess) {
+		return status.Error(codes.Unavailable, "broker is shutting down")
+	}
+	defer h.detach(sess)
+	logging.Logger(logging.Control).Info("control stream opened", "runtime", sess.runtimeID)
+
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			switch m := msg.Message.(type) {
+			case *nfa_control_v1alpha.RuntimeMessage_ConfigAck:
+				h.recordAck(sess, m.ConfigAck)
+			case *nfa_control_v1alpha.RuntimeMessage_CommandResult:
+				h.recordResult(sess, m.CommandResult)
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-recvErr:
+			return nil
+		case msg := <-sess.send:
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		case msg := <-sess.goAway:
+			// Sent ahead of queued messages, which the runtime no longer needs
+			return stream.Send(msg)
+		}
+	}
+}
+
+// GoAway tells every runtime on a control stream that the broker is shutting
+// down and ends their streams once it is sent; later streams are refused.
+// It returns the number of runtimes notified and a channel closed when
+// every stream has ended, to stop the broker without cutting one off.
+func (h *Hub) GoAway(goAway *nfa_control_v1alpha.GoAway) (int, <-chan struct{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.goAway == nil {
+		h.goAway = &nfa_control_v1alpha.Command{
+			Command: &nfa_control_v1alpha.Command_GoAway{GoAway: goAway},
+		}
+		h.assignCommandID(h.goAway)
+		h.gone = make(chan struct{})
+	}
+	msg := &nfa_control_v1alpha.BrokerMessage{
+		Message: &nfa_control_v1alpha.BrokerMessage_Command{Command: h.goAway},
+	}
+	notified := 0
+	for _, sess := range h.sessions {
+		select {
+		case sess.goAway <- msg:
+			notified++
+		default: // already notified
+		}
+	}
+	if len(h.sessions) == 0 {
+		closeOnce(h.gone)
+	}
+	logging.Logger(logging.Control).Info("broker going away", "notified", notified)
+	return notified, h.gone
+}
+
+func closeOnce(ch chan struct{}) {
+	select {
+	case <-ch:
+	default:
+		close(ch)
+	}
+}
+
+// PushConfig stores a named fragment for runtimes matching selector and sends
+// it to those currently connected. An empty selector matches every runtime.
+// It returns the number of runtimes the fragment was queued for.
+func (h *Hub) PushConfig(name string, selector map[string]string, fragment *nfa_control_v1alpha.ConfigFragment) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.version++
+	scoped := &scopedFragment{selector: selector, fragment: fragment, version: h.version}
+	h.fragments[name] = scoped
+
+	queued := 0
+	for _, sess := range h.sessions {
+		if MatchLabels(selector, sess.labels) && enqueue(sess, scoped) {
+			queued++
+		}
+	}
+	return queued
+}
+
+// SendCommand pushes a command to one runtime. A command ID is assigned when
+// empty; it is returned so the caller can look up the result later.
+func (h *Hub) SendCommand(runtimeID string, cmd *nfa_control_v1alpha.Command) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sess, ok := h.sessions[runtimeID]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotConnected, runtimeID)
+	}
+	h.assignCommandID(cmd)
+	if !enqueueCommand(sess, cmd) {
+		return "", fmt.Errorf("control stream to runtime %s is full", runtimeID)
+	}
+	return cmd.CommandId, nil
+}
+
+// BroadcastCommand pushes a command to every connected runtime matching
+// selector and returns the IDs of the runtimes it was queued for
+func (h *Hub) BroadcastCommand(selector map[string]string, cmd *nfa_control_v1alpha.Command) (string, []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.assignCommandID(cmd)
+	var targets []string
+	for _, sess := range h.sessions {
+		if MatchLabels(selector, sess.labels) && enqueueCommand(sess, cmd) {
+			targets = append(targets, sess.runtimeID)
+		}
+	}
+	sort.Strings(targets)
+	return cmd.CommandId, targets
+}
+
+// CommandResult returns the result a runtime reported for a command, if any
+func (h *Hub) CommandResult(commandID string) (*nfa_control_v1alpha.CommandResult, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	result, ok := h.results[commandID]
+	return result, ok
+}
+
+// RemoveConfig stops distributing a named fragment to newly connecting runtimes
+func (h *Hub) RemoveConfig(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.fragments, name)
+}
+
+// Sessions lists the connected runtimes sorted by runtime ID
+func (h *Hub) Sessions() []SessionInfo {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	infos := make([]SessionInfo, 0, len(h.sessions))
+	for _, sess := range h.sessions {
+		infos = append(infos, SessionInfo{
+			RuntimeID:    sess.runtimeID,
+			Labels:       sess.labels,
+			AckedVersion: sess.ackedVersion,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].RuntimeID < infos[j].RuntimeID })
+	return infos
+}
+
+// MatchLabels reports whether every selector entry is present in labels
+func MatchLabels(selector, labels map[string]string) bool {
+	for key, value := range selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// attach adds a session, unless the broker is going away
+func (h *Hub) attach(sess *session) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.goAway != nil {
+		return false
+	}
+	if prev, ok := h.sessions[sess.runtimeID]; ok {
+		logging.Logger(logging.Control).Info("runtime reconnected, replacing its control stream", "runtime", prev.runtimeID)
+	}
+	h.sessions[sess.runtimeID] = sess
+
+	// Replay retained fragments in the order they were pushed
+	var matching []*scopedFragment
+	for _, scoped := range h.fragments {
+		if MatchLabels(scoped.selector, sess.labels) {
+			matching = append(matching, scoped)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool { return matching[i].version < matching[j].version })
+	for _, scoped := range matching {
+		enqueue(sess, scoped)
+	}
+	return true
+}
+
+func (h *Hub) detach(sess *session) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.sessions[sess.runtimeID] == sess {
+		delete(h.sessions, sess.runtimeID)
+	}
+	if h.goAway != nil && len(h.sessions) == 0 {
+		closeOnce(h.gone)
+	}
+	logging.Logger(logging.Control).Info("control stream closed", "runtime", sess.runtimeID)
+}
+
+func (h *Hub) recordAck(sess *session, ack *nfa_control_v1alpha.ConfigAck) {
+	if !ack.Applied {
+		logging.Logger(logging.Control).Warn("config rejected", "runtime", sess.runtimeID, "version", ack.Version, "error", ack.Error)
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if ack.Version > sess.ackedVersion {
+		sess.ackedVersion = ack.Version
+	}
+}
+
+func (h *Hub) recordResult(sess *session, result *nfa_control_v1alpha.CommandResult) {
+	if !result.Success {
+		logging.Logger(logging.Control).Warn("command failed", "runtime", sess.runtimeID, "command", result.CommandId, "error", result.Error)
+	}
+
+	h.mu.Lock()
+	if _, seen := h.results[result.CommandId]; !seen {
+		h.resultOrder = append(h.resultOrder, result.CommandId)
+	}
+	h.results[result.CommandId] = result
+	for len(h.resultOrder) > maxCommandResults {
+		delete(h.results, h.resultOrder[0])
+		h.resultOrder = h.resultOrder[1:]
+	}
+	notify := h.resultNotify
+	if results, ok := h.broadcasts[result.CommandId]; ok {
+		select {
+		case results <- targetResult{runtimeID: sess.runtimeID, result: result}:
+		default:
+		}
+	}
+	h.mu.Unlock()
+
+	if notify != nil {
+		notify(sess.runtimeID, result)
+	}
+}
+
+// assignCommandID gives a command a broker-unique ID if it has none; mu must be held
+func (h *Hub) assignCommandID(cmd *nfa_control_v1alpha.Command) {
+	if cmd.CommandId == "" {
+		h.commandSeq++
+		cmd.CommandId = fmt.Sprintf("cmd-%d", h.commandSeq)
+	}
+}
+
+func enqueueCommand(sess *session, cmd *nfa_control_v1alpha.Command) bool {
+	msg := &nfa_control_v1alpha.BrokerMessage{
+		Message: &nfa_control_v1alpha.BrokerMessage_Command{Command: cmd},
+	}
+	select {
+	case sess.send <- msg:
+		return true
+	default:
+		logging.Logger(logging.Control).Warn("control stream full, command dropped", "runtime", sess.runtimeID, "command", cmd.CommandId)
+		return false
+	}
+}
+
+// enqueue queues a fragment without blocking the caller on a slow runtime
+func enqueue(sess *session, scoped *scopedFragment) bool {
+	msg := &nfa_control_v1alpha.BrokerMessage{
+		Message: &nfa_control_v1alpha.BrokerMessage_ConfigUpdate{
+			ConfigUpdate: &nfa_control_v1alpha.ConfigUpdate{
+				Version:  scoped.version,
+				Fragment: scoped.fragment,
+			},
+		},
+	}
+	select {
+	case sess.send <- msg:
+		return true
+	default:
+		logging.Logger(logging.Control).Warn("control stream full, config dropped", "runtime", sess.runtimeID, "version", scoped.version)
+		return false
+	}
+}
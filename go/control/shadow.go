@@ -0,0 +1,126 @@
+package control
+
+import (
+	"time"
+
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/shadow"
+	"google.golang.org/protobuf/proto"
+)
+
+// shadowBroadcast is a copy of a broadcast intent sent to shadow targets
+type shadowBroadcast struct {
+	commandID string
+	action    string
+	sent      time.Time
+	targets   map[string]bool
+	results   chan targetResult
+	rec       *shadow.Recorder
+}
+
+// mirror sends a shadow copy of a broadcast intent to the runtimes selected by
+// the shadow rules that are not among the primary targets, and returns nil
+// when the intent is not mirrored. Synthetic probes are never mirrored; mu
+// must be held.
+func (h *Hub) mirror(intent *nfa_control_v1alpha.Invoke, primary map[string]*nfa_control_v1alpha.TargetStatus) *shadowBroadcast {
+	if h.shadowRecorder == nil || intent.Synthetic {
+		return nil
+	}
+	var cmd *nfa_control_v1alpha.Command
+	targets := make(map[string]bool)
+	for _, rule := range h.shadowRules {
+		if !rule.Matches(intent.Action) || !rule.Sample() {
+			continue
+		}
+		for _, sess := range h.sessions {
+			if _, ok := primary[sess.runtimeID]; ok || targets[sess.runtimeID] || !MatchLabels(rule.Selector, sess.labels) {
+				continue
+			}
+			if cmd == nil {
+				invoke := proto.Clone(intent).(*nfa_control_v1alpha.Invoke)
+				invoke.Shadow = true
+				cmd = &nfa_control_v1alpha.Command{
+					Command: &nfa_control_v1alpha.Command_Invoke{Invoke: invoke},
+				}
+				h.assignCommandID(cmd)
+			}
+			if enqueueCommand(sess, cmd) {
+				targets[sess.runtimeID] = true
+			}
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+	sb := &shadowBroadcast{
+		commandID: cmd.CommandId,
+		action:    intent.Action,
+		sent:      time.Now(),
+		targets:   targets,
+		results:   make(chan targetResult, len(targets)),
+		rec:       h.shadowRecorder,
+	}
+	h.broadcasts[sb.commandID] = sb.results
+	return sb
+}
+
+// collectShadow records the results of shadow targets against the primary
+// response until every shadow target answered or the timeout expires. The
+// first successful primary target is the reference, or without one the first
+// failure; primary must be ordered by runtime ID.
+func (h *Hub) collectShadow(sb *shadowBroadcast, primary []*nfa_control_v1alpha.TargetStatus, timeout time.Duration) {
+	defer func() {
+		h.mu.Lock()
+		delete(h.broadcasts, sb.commandID)
+		h.mu.Unlock()
+	}()
+
+	reference := shadow.Result{Action: sb.action, PrimaryError: "no primary target answered"}
+	for _, target := range primary {
+		if target.State == nfa_control_v1alpha.TargetState_TARGET_STATE_SUCCEEDED {
+			reference.PrimaryOutput = target.Output
+			reference.PrimaryError = ""
+			break
+		}
+	}
+	if reference.PrimaryError != "" {
+		for _, target := range primary {
+			if target.State == nfa_control_v1alpha.TargetState_TARGET_STATE_FAILED {
+				reference.PrimaryError = target.Error
+				break
+			}
+		}
+	}
+
+	pending := len(sb.targets)
+	timer := time.NewTimer(time.Until(sb.sent.Add(timeout)))
+	defer timer.Stop()
+	for pending > 0 {
+		select {
+		case r := <-sb.results:
+			if !sb.targets[r.runtimeID] {
+				continue
+			}
+			delete(sb.targets, r.runtimeID)
+			pending--
+			res := reference
+			res.Candidate = r.runtimeID
+			res.CandidateLatency = time.Since(sb.sent)
+			if r.result.Success {
+				res.CandidateOutput = r.result.Output
+			} else {
+				res.CandidateError = r.result.Error
+			}
+			sb.rec.Record(res)
+		case <-timer.C:
+			for runtimeID := range sb.targets {
+				res := reference
+				res.Candidate = runtimeID
+				res.CandidateLatency = timeout
+				res.CandidateError = "timed out"
+				sb.rec.Record(res)
+			}
+			return
+		}
+	}
+}
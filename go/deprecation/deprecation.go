@@ -0,0 +1,260 @@
+// Package deprecation tracks who still calls deprecated actions. A contract
+// that renames an action keeps the old name as an alias; the broker records
+// every match of an alias with the consumer that made it, and reports calls,
+// call rates and when each consumer was last seen, so operators know whom to
+// migrate before the alias is removed. Reports are served by the admin
+// service, `nfactl deprecations` and, as JSON for dashboards, Handler.
+package deprecation
+
+import (
+	"encoding/csv"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Defaults of report parameters
+const (
+	DefaultPeriod    = 7 * 24 * time.Hour
+	DefaultRetention = 7 * 24 * time.Hour
+)
+
+const (
+	// bucketSize is the resolution at which calls are counted
+	bucketSize = time.Minute
+	// maxConsumers bounds the consumers kept per alias; the least recently
+	// seen is forgotten first
+	maxConsumers = 10000
+)
+
+// Use is one match of a deprecated alias
+type Use struct {
+	Time time.Time
+	// Alias is the deprecated name the consumer called and Action the
+	// action it resolved to
+	Alias, Action string
+	Consumer      string
+}
+
+// Tracker records the uses of deprecated aliases and builds reports from them
+type Tracker struct {
+	retention time.Duration
+	now       func() time.Time
+
+	mu      sync.Mutex
+	aliases map[string]*alias
+}
+
+type alias struct {
+	action    string
+	consumers map[string]*consumer
+}
+
+// consumer counts the calls of an alias by one consumer
+type consumer struct {
+	first, last time.Time
+	buckets     []bucket // oldest first
+}
+
+type bucket struct {
+	start time.Time
+	calls uint64
+}
+
+// NewTracker creates a tracker counting calls for retention; 0 counts them
+// for DefaultRetention. First and last calls are kept for as long as the
+// tracker runs.
+func NewTracker(retention time.Duration) *Tracker {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &Tracker{
+		retention: retention,
+		now:       time.Now,
+		aliases:   make(map[string]*alias),
+	}
+}
+
+// Observe records a use
+func (t *Tracker) Observe(u Use) {
+	if u.Time.IsZero() {
+		u.Time = t.now()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	a, ok := t.aliases[u.Alias]
+	if !ok {
+		a = &alias{consumers: make(map[string]*consumer)}
+		t.aliases[u.Alias] = a
+	}
+	a.action = u.Action
+	c, ok := a.consumers[u.Consumer]
+	if !ok {
+		if len(a.consumers) >= maxConsumers {
+			a.forgetOldest()
+		}
+		c = &consumer{first: u.Time}
+		a.consumers[u.Consumer] = c
+	}
+	if u.Time.After(c.last) {
+		c.last = u.Time
+	}
+	start := u.Time.Truncate(bucketSize)
+	if n := len(c.buckets); n > 0 && c.buckets[n-1].start.Equal(start) {
+		c.buckets[n-1].calls++
+	} else {
+		c.buckets = append(c.buckets, bucket{start: start, calls: 1})
+	}
+	cutoff := t.now().Add(-t.retention)
+	drop := sort.Search(len(c.buckets), func(i int) bool { return c.buckets[i].start.After(cutoff) })
+	c.buckets = c.buckets[drop:]
+}
+
+func (a *alias) forgetOldest() {
+	var oldest string
+	for name, c := range a.consumers {
+		if oldest == "" || c.last.Before(a.consumers[oldest].last) {
+			oldest = name
+		}
+	}
+	delete(a.consumers, oldest)
+}
+
+// Query selects what a report covers
+type Query struct {
+	// Alias restricts the report to one deprecated action; empty covers all
+	Alias string
+	// Start and End bound the period; zero values default to the
+	// DefaultPeriod ending now
+	Start, End time.Time
+}
+
+// Report builds a deprecation report. Aliases are sorted by name and their
+// consumers by calls, most first; consumers without calls in the period are
+// left out.
+func (t *Tracker) Report(q Query) *nfa_admin_v1alpha.DeprecationReport {
+	now := t.now()
+	if q.End.IsZero() {
+		q.End = now
+	}
+	if q.Start.IsZero() {
+		q.Start = q.End.Add(-DefaultPeriod)
+	}
+	minutes := q.End.Sub(q.Start).Minutes()
+
+	report := &nfa_admin_v1alpha.DeprecationReport{
+		StartUnix:     q.Start.Unix(),
+		EndUnix:       q.End.Unix(),
+		GeneratedUnix: now.Unix(),
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for name, a := range t.aliases {
+		if q.Alias != "" && name != q.Alias {
+			continue
+		}
+		action := &nfa_admin_v1alpha.DeprecatedAction{Alias: name, Action: a.action}
+		for consumerName, c := range a.consumers {
+			calls := c.calls(q.Start, q.End)
+			if calls == 0 {
+				continue
+			}
+			action.Calls += calls
+			action.LastSeenUnix = max(action.LastSeenUnix, c.last.Unix())
+			action.Consumers = append(action.Consumers, &nfa_admin_v1alpha.ConsumerUsage{
+				Consumer:       consumerName,
+				Calls:          calls,
+				CallsPerMinute: rate(calls, minutes),
+				FirstSeenUnix:  c.first.Unix(),
+				LastSeenUnix:   c.last.Unix(),
+			})
+		}
+		if action.Calls == 0 {
+			continue
+		}
+		action.CallsPerMinute = rate(action.Calls, minutes)
+		sort.Slice(action.Consumers, func(i, j int) bool {
+			ci, cj := action.Consumers[i], action.Consumers[j]
+			if ci.Calls != cj.Calls {
+				return ci.Calls > cj.Calls
+			}
+			return ci.Consumer < cj.Consumer
+		})
+		report.Actions = append(report.Actions, action)
+	}
+	sort.Slice(report.Actions, func(i, j int) bool { return report.Actions[i].Alias < report.Actions[j].Alias })
+	return report
+}
+
+// calls counts the calls in the buckets starting in [start, end)
+func (c *consumer) calls(start, end time.Time) uint64 {
+	var n uint64
+	for _, b := range c.buckets {
+		if !b.start.Before(start.Truncate(bucketSize)) && b.start.Before(end) {
+			n += b.calls
+		}
+	}
+	return n
+}
+
+func rate(calls uint64, minutes float64) float64 {
+	if minutes <= 0 {
+		return 0
+	}
+	return float64(calls) / minutes
+}
+
+// Handler serves the report of the last ?since duration, DefaultPeriod by
+// default, as JSON, or of one alias with ?alias=name. Dashboards read it with
+// a JSON data source.
+func (t *Tracker) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := Query{Alias: r.URL.Query().Get("alias")}
+		if since := r.URL.Query().Get("since"); since != "" {
+			d, err := time.ParseDuration(since)
+			if err != nil || d <= 0 {
+				http.Error(w, "since must be a positive duration, e.g. 24h", http.StatusBadRequest)
+				return
+			}
+			q.End = t.now()
+			q.Start = q.End.Add(-d)
+		}
+		data, err := protojson.Marshal(t.Report(q))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+}
+
+// WriteCSV writes a report as CSV, one row per alias and consumer
+func WriteCSV(w io.Writer, report *nfa_admin_v1alpha.DeprecationReport) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"alias", "action", "consumer", "calls", "calls_per_minute",
+		"first_seen", "last_seen",
+	})
+	for _, a := range report.Actions {
+		for _, c := range a.Consumers {
+			cw.Write([]string{
+				a.Alias,
+				a.Action,
+				c.Consumer,
+				strconv.FormatUint(c.Calls, 10),
+				strconv.FormatFloat(c.CallsPerMinute, 'f', 3, 64),
+				time.Unix(c.FirstSeenUnix, 0).UTC().Format(time.RFC3339),
+				time.Unix(c.LastSeenUnix, 0).UTC().Format(time.RFC3339),
+			})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
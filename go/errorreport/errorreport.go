@@ -0,0 +1,245 @@
+// Package errorreport keeps the errors providers' handlers returned, as
+// their runtimes summarize them. Runtimes deduplicate errors by action and
+// status code and send a summary per kind on an interval; the broker adds
+// them up per service, so operators see which providers fail, how often and
+// why without collecting their logs. Reports are served by the admin
+// service, `nfactl errors` and, as JSON for dashboards, Handler.
+package errorreport
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// DefaultRetention is how long an error kind is kept after it was last
+// reported
+const DefaultRetention = 24 * time.Hour
+
+const (
+	// MaxSummaries bounds the summaries taken from one report; those
+	// beyond count as dropped
+	MaxSummaries = 100
+	// MaxSampleBytes bounds the sample message kept per kind
+	MaxSampleBytes = 512
+	// maxKinds bounds the error kinds kept; the least recently seen is
+	// forgotten first
+	maxKinds = 10000
+)
+
+// Summary is the errors of one kind a service returned since its last
+// report
+type Summary struct {
+	// Action is the intent action, or the full method of requests to
+	// other services
+	Action string
+	// Code is the name of the gRPC status code, e.g. Internal
+	Code   string
+	Count  uint64
+	Sample string
+	// First and last error of the kind in the report
+	FirstSeen, LastSeen time.Time
+}
+
+// key identifies an error kind of a service
+type key struct {
+	serviceID, action, code string
+}
+
+type kind struct {
+	count       uint64
+	sample      string
+	first, last time.Time
+}
+
+// Tracker adds up the error summaries runtimes report and builds reports
+// from them
+type Tracker struct {
+	retention time.Duration
+	now       func() time.Time
+
+	mu      sync.Mutex
+	kinds   map[key]*kind
+	dropped uint64
+}
+
+// NewTracker creates a tracker keeping error kinds for retention after they
+// were last reported; 0 keeps them for DefaultRetention
+func NewTracker(retention time.Duration) *Tracker {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &Tracker{
+		retention: retention,
+		now:       time.Now,
+		kinds:     make(map[key]*kind),
+	}
+}
+
+// Record adds a report of serviceID: its summaries, of which only the first
+// MaxSummaries are kept, and the errors the runtime dropped
+func (t *Tracker) Record(serviceID string, summaries []Summary, dropped uint64) {
+	now := t.now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expire(now)
+	t.dropped += dropped
+	for i, s := range summaries {
+		if i >= MaxSummaries {
+			t.dropped += s.Count
+			continue
+		}
+		if s.Count == 0 {
+			continue
+		}
+		if s.LastSeen.IsZero() || s.LastSeen.After(now) {
+			s.LastSeen = now
+		}
+		if s.FirstSeen.IsZero() || s.FirstSeen.After(s.LastSeen) {
+			s.FirstSeen = s.LastSeen
+		}
+		k := key{serviceID: serviceID, action: s.Action, code: s.Code}
+		e, ok := t.kinds[k]
+		if !ok {
+			if len(t.kinds) >= maxKinds {
+				t.forgetOldest()
+			}
+			e = &kind{first: s.FirstSeen}
+			t.kinds[k] = e
+		}
+		e.count += s.Count
+		e.sample = Truncate(s.Sample)
+		e.first = minTime(e.first, s.FirstSeen)
+		if s.LastSeen.After(e.last) {
+			e.last = s.LastSeen
+		}
+	}
+}
+
+// Forget drops the errors of a service that is gone
+func (t *Tracker) Forget(serviceID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for k := range t.kinds {
+		if k.serviceID == serviceID {
+			delete(t.kinds, k)
+		}
+	}
+}
+
+// expire drops the kinds not reported within the retention; mu must be
+// held
+func (t *Tracker) expire(now time.Time) {
+	cutoff := now.Add(-t.retention)
+	for k, e := range t.kinds {
+		if e.last.Before(cutoff) {
+			delete(t.kinds, k)
+		}
+	}
+}
+
+func (t *Tracker) forgetOldest() {
+	var oldest key
+	found := false
+	for k, e := range t.kinds {
+		if !found || e.last.Before(t.kinds[oldest].last) {
+			oldest, found = k, true
+		}
+	}
+	delete(t.kinds, oldest)
+}
+
+// Query selects what a report covers
+type Query struct {
+	// ServiceID restricts the report to one service; empty covers all
+	ServiceID string
+	// Since leaves out kinds last seen before it; zero covers all retained
+	Since time.Time
+}
+
+// Report builds an error report, most errors first
+func (t *Tracker) Report(q Query) *nfa_admin_v1alpha.ErrorReport {
+	now := t.now()
+	report := &nfa_admin_v1alpha.ErrorReport{GeneratedUnix: now.Unix()}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expire(now)
+	report.Dropped = t.dropped
+	for k, e := range t.kinds {
+		if q.ServiceID != "" && k.serviceID != q.ServiceID || e.last.Before(q.Since) {
+			continue
+		}
+		report.Errors = append(report.Errors, &nfa_admin_v1alpha.ServiceError{
+			ServiceId:     k.serviceID,
+			Action:        k.action,
+			Code:          k.code,
+			Count:         e.count,
+			SampleMessage: e.sample,
+			FirstSeenUnix: e.first.Unix(),
+			LastSeenUnix:  e.last.Unix(),
+		})
+	}
+	sort.Slice(report.Errors, func(i, j int) bool {
+		ei, ej := report.Errors[i], report.Errors[j]
+		if ei.Count != ej.Count {
+			return ei.Count > ej.Count
+		}
+		if ei.ServiceId != ej.ServiceId {
+			return ei.ServiceId < ej.ServiceId
+		}
+		if ei.Action != ej.Action {
+			return ei.Action < ej.Action
+		}
+		return ei.Code < ej.Code
+	})
+	return report
+}
+
+// Handler serves the report as JSON, of the errors seen within ?since, e.g.
+// 1h, and of one service with ?service_id=id. Dashboards read it with a JSON
+// data source.
+func (t *Tracker) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := Query{ServiceID: r.URL.Query().Get("service_id")}
+		if since := r.URL.Query().Get("since"); since != "" {
+			d, err := time.ParseDuration(since)
+			if err != nil || d <= 0 {
+				http.Error(w, "since must be a positive duration, e.g. 1h", http.StatusBadRequest)
+				return
+			}
+			q.Since = t.now().Add(-d)
+		}
+		data, err := protojson.Marshal(t.Report(q))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+}
+
+// Truncate shortens a sample message to MaxSampleBytes, without splitting a
+// UTF-8 character
+func Truncate(msg string) string {
+	if len(msg) <= MaxSampleBytes {
+		return msg
+	}
+	cut := MaxSampleBytes
+	for cut > 0 && !utf8.RuneStart(msg[cut]) {
+		cut--
+	}
+	return msg[:cut]
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,57 @@
+package experiment
+
+import (
+	"context"
+	"fmt"
+
+	nfa_analytics_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/analytics/v1alpha"
+	"google.golang.org/grpc"
+)
+
+// Client manages experiments on a broker
+type Client struct {
+	client nfa_analytics_v1alpha.AnalyticsServiceClient
+}
+
+// NewClient creates an analytics client on an existing broker connection
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{
+		client: nfa_analytics_v1alpha.NewAnalyticsServiceClient(cc),
+	}
+}
+
+// Create starts an experiment
+func (c *Client) Create(ctx context.Context, exp *nfa_analytics_v1alpha.Experiment) (*nfa_analytics_v1alpha.Experiment, error) {
+	created, err := c.client.CreateExperiment(ctx, &nfa_analytics_v1alpha.CreateExperimentRequest{Experiment: exp})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create experiment: %w", err)
+	}
+	return created, nil
+}
+
+// List returns every experiment of the broker
+func (c *Client) List(ctx context.Context) ([]*nfa_analytics_v1alpha.Experiment, error) {
+	resp, err := c.client.ListExperiments(ctx, &nfa_analytics_v1alpha.ListExperimentsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list experiments: %w", err)
+	}
+	return resp.Experiments, nil
+}
+
+// Stop ends a running experiment
+func (c *Client) Stop(ctx context.Context, name string) (*nfa_analytics_v1alpha.Experiment, error) {
+	exp, err := c.client.StopExperiment(ctx, &nfa_analytics_v1alpha.StopExperimentRequest{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to stop experiment %s: %w", name, err)
+	}
+	return exp, nil
+}
+
+// Results summarizes the metrics of every variant of an experiment
+func (c *Client) Results(ctx context.Context, name string) (*nfa_analytics_v1alpha.ExperimentResults, error) {
+	results, err := c.client.GetExperimentResults(ctx, &nfa_analytics_v1alpha.GetExperimentResultsRequest{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get results of experiment %s: %w", name, err)
+	}
+	return results, nil
+}
@@ -0,0 +1,52 @@
+package experiment
+
+import (
+	"context"
+	"log"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/control"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/policy"
+)
+
+// Engine is a policy.Engine that routes the intents taking part in an
+// experiment to the providers of their variant. The wrapped engine decides
+// authorization and orders the candidates; the experiment then keeps the
+// candidates matching the variant's selector, or all of them when none does.
+type Engine struct {
+	engine  policy.Engine
+	manager *Manager
+}
+
+// NewEngine wraps engine with the experiments of manager
+func NewEngine(engine policy.Engine, manager *Manager) *Engine {
+	return &Engine{engine: engine, manager: manager}
+}
+
+// Authorize implements policy.Engine
+func (e *Engine) Authorize(ctx context.Context, input policy.Input) (policy.Decision, error) {
+	return e.engine.Authorize(ctx, input)
+}
+
+// Route implements policy.Engine
+func (e *Engine) Route(ctx context.Context, input policy.Input, candidates []policy.Candidate) ([]policy.Candidate, error) {
+	routed, err := e.engine.Route(ctx, input, candidates)
+	if err != nil || len(routed) == 0 {
+		return routed, err
+	}
+	assignment, ok := e.manager.Assign(input)
+	if !ok {
+		return routed, nil
+	}
+	var selected []policy.Candidate
+	for _, c := range routed {
+		if control.MatchLabels(assignment.Selector, c.Labels) {
+			selected = append(selected, c)
+		}
+	}
+	if len(selected) == 0 {
+		log.Printf("No provider of %s serves variant %s of experiment %s, routing normally",
+			input.Action, assignment.Variant, assignment.Experiment)
+		return routed, nil
+	}
+	return selected, nil
+}
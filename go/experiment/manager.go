@@ -0,0 +1,307 @@
+// Package experiment runs A/B experiments on provider selection. An
+// experiment splits the users or sessions invoking some actions into cohorts
+// by hashing their ID, routes each cohort to the providers of one variant and
+// collects metrics per variant, so providers can be compared on live traffic
+// beyond what raw routing weights allow. Experiments are managed through the
+// analytics service, which also serves their result summaries.
+package experiment
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/policy"
+	nfa_analytics_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/analytics/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Standard metrics: the latency of the provider's response, 1 for a failed
+// invocation, 0 otherwise, and the user feedback score from 0 (bad) to 1
+// (good) reported through the feedback service
+const (
+	MetricLatency  = "latency_ms"
+	MetricError    = "error"
+	MetricFeedback = "feedback"
+)
+
+// Manager holds the broker's experiments and implements the analytics service
+type Manager struct {
+	nfa_analytics_v1alpha.UnimplementedAnalyticsServiceServer
+
+	mu          sync.Mutex
+	experiments map[string]*experiment
+	now         func() time.Time
+}
+
+type experiment struct {
+	def      *nfa_analytics_v1alpha.Experiment
+	variants map[string]*variantStats
+}
+
+type variantStats struct {
+	assignments uint64
+	metrics     map[string]*nfa_analytics_v1alpha.MetricSummary
+}
+
+// Assignment is the variant an intent was assigned to
+type Assignment struct {
+	Experiment string
+	Variant    string
+	// Selector selects the providers serving the variant
+	Selector map[string]string
+}
+
+// NewManager creates a manager without experiments
+func NewManager() *Manager {
+	return &Manager{
+		experiments: make(map[string]*experiment),
+		now:         time.Now,
+	}
+}
+
+// Register registers the analytics service on a gRPC server
+func (m *Manager) Register(registrar grpc.ServiceRegistrar) {
+	nfa_analytics_v1alpha.RegisterAnalyticsServiceServer(registrar, m)
+}
+
+// CreateExperiment implements the CreateExperiment RPC
+func (m *Manager) CreateExperiment(ctx context.Context, req *nfa_analytics_v1alpha.CreateExperimentRequest) (*nfa_analytics_v1alpha.Experiment, error) {
+	if err := validate(req.Experiment); err != nil {
+		return nil, err
+	}
+	def := proto.Clone(req.Experiment).(*nfa_analytics_v1alpha.Experiment)
+	def.StartTime = timestamppb.New(m.now())
+	def.EndTime = nil
+	def.State = nfa_analytics_v1alpha.ExperimentState_EXPERIMENT_STATE_RUNNING
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.experiments[def.Name]; ok {
+		return nil, status.Errorf(codes.AlreadyExists, "experiment %s already exists", def.Name)
+	}
+	exp := &experiment{def: def, variants: make(map[string]*variantStats)}
+	for _, v := range def.Variants {
+		exp.variants[v.Name] = &variantStats{metrics: make(map[string]*nfa_analytics_v1alpha.MetricSummary)}
+	}
+	m.experiments[def.Name] = exp
+	return proto.Clone(def).(*nfa_analytics_v1alpha.Experiment), nil
+}
+
+// ListExperiments implements the ListExperiments RPC
+func (m *Manager) ListExperiments(ctx context.Context, req *nfa_analytics_v1alpha.ListExperimentsRequest) (*nfa_analytics_v1alpha.ListExperimentsResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	resp := &nfa_analytics_v1alpha.ListExperimentsResponse{}
+	for _, exp := range m.sorted() {
+		resp.Experiments = append(resp.Experiments, proto.Clone(exp.def).(*nfa_analytics_v1alpha.Experiment))
+	}
+	return resp, nil
+}
+
+// StopExperiment implements the StopExperiment RPC
+func (m *Manager) StopExperiment(ctx context.Context, req *nfa_analytics_v1alpha.StopExperimentRequest) (*nfa_analytics_v1alpha.Experiment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	exp, err := m.lookup(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if exp.def.State == nfa_analytics_v1alpha.ExperimentState_EXPERIMENT_STATE_RUNNING {
+		exp.def.State = nfa_analytics_v1alpha.ExperimentState_EXPERIMENT_STATE_STOPPED
+		exp.def.EndTime = timestamppb.New(m.now())
+	}
+	return proto.Clone(exp.def).(*nfa_analytics_v1alpha.Experiment), nil
+}
+
+// GetExperimentResults implements the GetExperimentResults RPC
+func (m *Manager) GetExperimentResults(ctx context.Context, req *nfa_analytics_v1alpha.GetExperimentResultsRequest) (*nfa_analytics_v1alpha.ExperimentResults, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	exp, err := m.lookup(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	results := &nfa_analytics_v1alpha.ExperimentResults{
+		Experiment: proto.Clone(exp.def).(*nfa_analytics_v1alpha.Experiment),
+	}
+	for _, v := range exp.def.Variants {
+		stats := exp.variants[v.Name]
+		summary := &nfa_analytics_v1alpha.VariantSummary{Variant: v.Name, Assignments: stats.assignments}
+		for _, name := range exp.def.Metrics {
+			metric, ok := stats.metrics[name]
+			if !ok {
+				metric = &nfa_analytics_v1alpha.MetricSummary{Name: name}
+			}
+			summary.Metrics = append(summary.Metrics, proto.Clone(metric).(*nfa_analytics_v1alpha.MetricSummary))
+		}
+		results.Variants = append(results.Variants, summary)
+	}
+	return results, nil
+}
+
+// Assign returns the variant serving an intent, from the earliest running
+// experiment covering its action. Intents without the user or session ID the
+// experiment assigns cohorts by do not take part. The assignment is counted
+// in the experiment's results.
+func (m *Manager) Assign(input policy.Input) (Assignment, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	exp, v := m.assign(input)
+	if v == nil {
+		return Assignment{}, false
+	}
+	exp.variants[v.Name].assignments++
+	return Assignment{Experiment: exp.def.Name, Variant: v.Name, Selector: v.Selector}, true
+}
+
+// Observe records a value of a metric for the variant an intent was assigned
+// to. Metrics the experiment does not compare are ignored.
+func (m *Manager) Observe(input policy.Input, metric string, value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	exp, v := m.assign(input)
+	if v == nil || !contains(exp.def.Metrics, metric) {
+		return
+	}
+	stats := exp.variants[v.Name]
+	summary, ok := stats.metrics[metric]
+	if !ok {
+		summary = &nfa_analytics_v1alpha.MetricSummary{Name: metric, Min: value, Max: value}
+		stats.metrics[metric] = summary
+	}
+	summary.Count++
+	summary.Mean += (value - summary.Mean) / float64(summary.Count)
+	summary.Min = math.Min(summary.Min, value)
+	summary.Max = math.Max(summary.Max, value)
+}
+
+// assign finds the experiment and variant of an intent; mu must be held
+func (m *Manager) assign(input policy.Input) (*experiment, *nfa_analytics_v1alpha.Variant) {
+	for _, exp := range m.sorted() {
+		if exp.def.State != nfa_analytics_v1alpha.ExperimentState_EXPERIMENT_STATE_RUNNING || !matchAny(exp.def.Actions, input.Action) {
+			continue
+		}
+		key := input.User
+		if exp.def.CohortKey == nfa_analytics_v1alpha.CohortKey_COHORT_KEY_SESSION {
+			key = input.Session
+		}
+		if key == "" {
+			return nil, nil
+		}
+		return exp, cohort(exp.def, key)
+	}
+	return nil, nil
+}
+
+// sorted completes the experiments whose duration elapsed and returns every
+// experiment ordered by start time; mu must be held
+func (m *Manager) sorted() []*experiment {
+	now := m.now()
+	out := make([]*experiment, 0, len(m.experiments))
+	for _, exp := range m.experiments {
+		def := exp.def
+		if def.State == nfa_analytics_v1alpha.ExperimentState_EXPERIMENT_STATE_RUNNING && def.Duration != nil {
+			if end := def.StartTime.AsTime().Add(def.Duration.AsDuration()); !now.Before(end) {
+				def.State = nfa_analytics_v1alpha.ExperimentState_EXPERIMENT_STATE_COMPLETED
+				def.EndTime = timestamppb.New(end)
+			}
+		}
+		out = append(out, exp)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		ti, tj := out[i].def.StartTime.AsTime(), out[j].def.StartTime.AsTime()
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return out[i].def.Name < out[j].def.Name
+	})
+	return out
+}
+
+// lookup returns an experiment by name; mu must be held
+func (m *Manager) lookup(name string) (*experiment, error) {
+	m.sorted()
+	exp, ok := m.experiments[name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "experiment %s not found", name)
+	}
+	return exp, nil
+}
+
+// cohort assigns a user or session to a variant. The hash is salted with the
+// experiment name so cohorts are independent between experiments, and stable
+// so a user keeps their variant for the whole experiment.
+func cohort(def *nfa_analytics_v1alpha.Experiment, key string) *nfa_analytics_v1alpha.Variant {
+	sum := sha256.Sum256([]byte(def.Name + "\x00" + key))
+	var total float64
+	for _, v := range def.Variants {
+		total += v.Weight
+	}
+	point := float64(binary.BigEndian.Uint64(sum[:8])) / float64(math.MaxUint64) * total
+	for _, v := range def.Variants {
+		if point < v.Weight {
+			return v
+		}
+		point -= v.Weight
+	}
+	return def.Variants[len(def.Variants)-1]
+}
+
+func validate(def *nfa_analytics_v1alpha.Experiment) error {
+	if def == nil || def.Name == "" {
+		return status.Error(codes.InvalidArgument, "experiment with a name is required")
+	}
+	if len(def.Variants) < 2 {
+		return status.Errorf(codes.InvalidArgument, "experiment %s needs at least two variants", def.Name)
+	}
+	seen := make(map[string]bool)
+	for _, v := range def.Variants {
+		if v.Name == "" || seen[v.Name] {
+			return status.Errorf(codes.InvalidArgument, "variant names of experiment %s must be unique and not empty", def.Name)
+		}
+		seen[v.Name] = true
+		if v.Weight <= 0 || math.IsInf(v.Weight, 0) || math.IsNaN(v.Weight) {
+			return status.Errorf(codes.InvalidArgument, "variant %s of experiment %s needs a positive weight", v.Name, def.Name)
+		}
+	}
+	for _, pattern := range def.Actions {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid action pattern %q: %v", pattern, err)
+		}
+	}
+	if def.Duration != nil && def.Duration.AsDuration() <= 0 {
+		return status.Errorf(codes.InvalidArgument, "duration of experiment %s must be positive", def.Name)
+	}
+	return nil
+}
+
+func matchAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+package experiment
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/policy"
+	nfa_analytics_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/analytics/v1alpha"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func newExperiment(t *testing.T, m *Manager, name string, cohortKey nfa_analytics_v1alpha.CohortKey, weights ...float64) {
+	t.Helper()
+	def := &nfa_analytics_v1alpha.Experiment{
+		Name:      name,
+		Actions:   []string{"translate_*"},
+		CohortKey: cohortKey,
+		Metrics:   []string{MetricLatency},
+	}
+	for i, w := range weights {
+		def.Variants = append(def.Variants, &nfa_analytics_v1alpha.Variant{
+			Name:     fmt.Sprintf("v%d", i),
+			Selector: map[string]string{"model": fmt.Sprintf("m%d", i)},
+			Weight:   w,
+		})
+	}
+	if _, err := m.CreateExperiment(context.Background(), &nfa_analytics_v1alpha.CreateExperimentRequest{Experiment: def}); err != nil {
+		t.Fatalf("CreateExperiment() error = %v", err)
+	}
+}
+
+func TestAssignIsDeterministic(t *testing.T) {
+	m := NewManager()
+	newExperiment(t, m, "models", nfa_analytics_v1alpha.CohortKey_COHORT_KEY_USER, 1, 1)
+	other := NewManager()
+	newExperiment(t, other, "models", nfa_analytics_v1alpha.CohortKey_COHORT_KEY_USER, 1, 1)
+
+	for i := 0; i < 100; i++ {
+		input := policy.Input{Action: "translate_text", User: fmt.Sprintf("user-%d", i), Session: fmt.Sprintf("session-%d", i)}
+		first, ok := m.Assign(input)
+		if !ok {
+			t.Fatalf("Assign(%s) = false, want an assignment", input.User)
+		}
+		// The session does not matter to cohorts by user
+		input.Session = "another"
+		if again, _ := m.Assign(input); again.Variant != first.Variant {
+			t.Errorf("Assign(%s) = %s, then %s, want the same variant", input.User, first.Variant, again.Variant)
+		}
+		// Nor does the broker assigning it
+		if elsewhere, _ := other.Assign(input); elsewhere.Variant != first.Variant {
+			t.Errorf("Assign(%s) = %s on another manager, want %s", input.User, elsewhere.Variant, first.Variant)
+		}
+		if first.Experiment != "models" || first.Selector["model"] != "m"+first.Variant[1:] {
+			t.Errorf("Assign(%s) = %+v, want the selector of its variant", input.User, first)
+		}
+	}
+}
+
+func TestAssignSkipsIntents(t *testing.T) {
+	m := NewManager()
+	newExperiment(t, m, "models", nfa_analytics_v1alpha.CohortKey_COHORT_KEY_SESSION, 1, 1)
+	for _, input := range []policy.Input{
+		{Action: "summarize", Session: "s1"},
+		{Action: "translate_text", User: "u1"},
+	} {
+		if a, ok := m.Assign(input); ok {
+			t.Errorf("Assign(%+v) = %+v, want no assignment", input, a)
+		}
+	}
+
+	if _, err := m.StopExperiment(context.Background(), &nfa_analytics_v1alpha.StopExperimentRequest{Name: "models"}); err != nil {
+		t.Fatalf("StopExperiment() error = %v", err)
+	}
+	if a, ok := m.Assign(policy.Input{Action: "translate_text", Session: "s1"}); ok {
+		t.Errorf("Assign() after StopExperiment = %+v, want no assignment", a)
+	}
+}
+
+func TestAssignSplitsTraffic(t *testing.T) {
+	for _, weights := range [][]float64{{1, 1}, {3, 1}, {1, 2, 7}, {0.05, 0.95}} {
+		m := NewManager()
+		newExperiment(t, m, "split", nfa_analytics_v1alpha.CohortKey_COHORT_KEY_USER, weights...)
+		const users = 20000
+		counts := make(map[string]int)
+		for i := 0; i < users; i++ {
+			a, _ := m.Assign(policy.Input{Action: "translate_text", User: fmt.Sprintf("user-%d", i)})
+			counts[a.Variant]++
+		}
+		var total float64
+		for _, w := range weights {
+			total += w
+		}
+		for i, w := range weights {
+			want := w / total
+			got := float64(counts[fmt.Sprintf("v%d", i)]) / users
+			if math.Abs(got-want) > 0.015 {
+				t.Errorf("weights %v: variant v%d got %.3f of the users, want %.3f", weights, i, got, want)
+			}
+		}
+
+		results, err := m.GetExperimentResults(context.Background(), &nfa_analytics_v1alpha.GetExperimentResultsRequest{Name: "split"})
+		if err != nil {
+			t.Fatalf("GetExperimentResults() error = %v", err)
+		}
+		for _, v := range results.Variants {
+			if v.Assignments != uint64(counts[v.Variant]) {
+				t.Errorf("weights %v: results count %d assignments of %s, want %d", weights, v.Assignments, v.Variant, counts[v.Variant])
+			}
+		}
+	}
+}
+
+func TestObserveAndComplete(t *testing.T) {
+	m := NewManager()
+	start := time.Unix(1700000000, 0)
+	m.now = func() time.Time { return start }
+	def := &nfa_analytics_v1alpha.Experiment{
+		Name:     "timed",
+		Metrics:  []string{MetricLatency},
+		Duration: durationpb.New(time.Hour),
+		Variants: []*nfa_analytics_v1alpha.Variant{{Name: "a", Weight: 1}, {Name: "b", Weight: 1}},
+	}
+	if _, err := m.CreateExperiment(context.Background(), &nfa_analytics_v1alpha.CreateExperimentRequest{Experiment: def}); err != nil {
+		t.Fatalf("CreateExperiment() error = %v", err)
+	}
+	input := policy.Input{Action: "anything", User: "u1"}
+	a, _ := m.Assign(input)
+	for _, v := range []float64{100, 300} {
+		m.Observe(input, MetricLatency, v)
+	}
+	m.Observe(input, MetricError, 1) // not compared
+
+	m.now = func() time.Time { return start.Add(time.Hour) }
+	if _, ok := m.Assign(input); ok {
+		t.Error("Assign() after the duration = true, want the experiment completed")
+	}
+	results, err := m.GetExperimentResults(context.Background(), &nfa_analytics_v1alpha.GetExperimentResultsRequest{Name: "timed"})
+	if err != nil {
+		t.Fatalf("GetExperimentResults() error = %v", err)
+	}
+	if results.Experiment.State != nfa_analytics_v1alpha.ExperimentState_EXPERIMENT_STATE_COMPLETED {
+		t.Errorf("state = %v, want completed", results.Experiment.State)
+	}
+	for _, v := range results.Variants {
+		if len(v.Metrics) != 1 {
+			t.Fatalf("variant %s metrics = %v, want latency only", v.Variant, v.Metrics)
+		}
+		metric := v.Metrics[0]
+		if v.Variant != a.Variant {
+			if metric.Count != 0 {
+				t.Errorf("variant %s latency = %v, want no observations", v.Variant, metric)
+			}
+			continue
+		}
+		if metric.Count != 2 || metric.Mean != 200 || metric.Min != 100 || metric.Max != 300 {
+			t.Errorf("variant %s latency = %v, want 2 observations of mean 200 from 100 to 300", v.Variant, metric)
+		}
+	}
+}
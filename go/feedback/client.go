@@ -0,0 +1,39 @@
+package feedback
+
+import (
+	"context"
+	"fmt"
+
+	nfa_feedback_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/feedback/v1alpha"
+	"google.golang.org/grpc"
+)
+
+// Client submits and lists feedback on a broker
+type Client struct {
+	client nfa_feedback_v1alpha.FeedbackServiceClient
+}
+
+// NewClient creates a feedback client on an existing broker connection
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{
+		client: nfa_feedback_v1alpha.NewFeedbackServiceClient(cc),
+	}
+}
+
+// Submit records feedback on a fulfillment
+func (c *Client) Submit(ctx context.Context, req *nfa_feedback_v1alpha.SubmitFeedbackRequest) (*nfa_feedback_v1alpha.Feedback, error) {
+	f, err := c.client.SubmitFeedback(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit feedback on fulfillment %s: %w", req.FulfillmentId, err)
+	}
+	return f, nil
+}
+
+// List returns the recorded feedback matching req, most recent first
+func (c *Client) List(ctx context.Context, req *nfa_feedback_v1alpha.ListFeedbackRequest) ([]*nfa_feedback_v1alpha.Feedback, error) {
+	resp, err := c.client.ListFeedback(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feedback: %w", err)
+	}
+	return resp.Feedback, nil
+}
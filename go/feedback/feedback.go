@@ -0,0 +1,277 @@
+// Package feedback collects user feedback on fulfilled intents: thumbs up or
+// down and correction text, keyed by the fulfillment ID every fulfillment
+// carries. Feedback is appended to the broker's event log and reported to
+// listeners, which feed it to routing strategies and experiment analytics so
+// user satisfaction flows back into provider ranking.
+package feedback
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"sync"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/bandit"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/experiment"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/policy"
+	nfa_feedback_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/feedback/v1alpha"
+	nfa_privacy_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/privacy/v1alpha"
+	nfa_pubsub_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/pubsub/v1alpha"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pubsub"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Topic is the event log topic feedback is published to
+const Topic = "nfa.feedback"
+
+// Attributes of feedback events besides pubsub.AttributeUserID
+const (
+	AttributeFulfillmentID = "nfa.fulfillment_id"
+	AttributeServiceID     = "nfa.service_id"
+)
+
+const (
+	// maxFulfillments bounds the fulfillments remembered for attributing
+	// feedback to their provider
+	maxFulfillments = 10000
+	// maxFeedback bounds the feedback kept for listing
+	maxFeedback = 1000
+)
+
+// Publisher appends events to the event log; *pubsub.Broker implements it
+type Publisher interface {
+	Publish(ctx context.Context, req *nfa_pubsub_v1alpha.PublishRequest) (*nfa_pubsub_v1alpha.PublishResponse, error)
+}
+
+// Listener is called for every feedback submitted. input is the intent of the
+// fulfillment, and zero when the fulfillment is unknown to the service.
+type Listener func(f *nfa_feedback_v1alpha.Feedback, input policy.Input)
+
+// Service implements the feedback service
+type Service struct {
+	nfa_feedback_v1alpha.UnimplementedFeedbackServiceServer
+
+	events Publisher
+
+	mu           sync.Mutex
+	fulfillments map[string]fulfillment
+	order        []string // fulfillment IDs, oldest first
+	feedback     []*nfa_feedback_v1alpha.Feedback
+	listeners    []Listener
+}
+
+type fulfillment struct {
+	serviceID string
+	input     policy.Input
+}
+
+// NewService creates a feedback service publishing to events, which may be
+// nil to keep feedback in memory only
+func NewService(events Publisher) *Service {
+	return &Service{
+		events:       events,
+		fulfillments: make(map[string]fulfillment),
+	}
+}
+
+// Register registers the feedback service on a gRPC server
+func (s *Service) Register(registrar grpc.ServiceRegistrar) {
+	nfa_feedback_v1alpha.RegisterFeedbackServiceServer(registrar, s)
+}
+
+// OnFeedback registers a listener for submitted feedback
+func (s *Service) OnFeedback(fn Listener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// Record remembers that the provider serviceID fulfilled an intent, so that
+// feedback on fulfillmentID is attributed to it
+func (s *Service) Record(fulfillmentID, serviceID string, input policy.Input) {
+	if fulfillmentID == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.fulfillments[fulfillmentID]; !ok {
+		s.order = append(s.order, fulfillmentID)
+	}
+	s.fulfillments[fulfillmentID] = fulfillment{serviceID: serviceID, input: input}
+	if len(s.order) > maxFulfillments {
+		delete(s.fulfillments, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+// SubmitFeedback implements the SubmitFeedback RPC. Feedback on fulfillments
+// the service does not know, e.g. served through another broker, is accepted
+// but not attributed to a provider.
+func (s *Service) SubmitFeedback(ctx context.Context, req *nfa_feedback_v1alpha.SubmitFeedbackRequest) (*nfa_feedback_v1alpha.Feedback, error) {
+	if req.FulfillmentId == "" {
+		return nil, status.Error(codes.InvalidArgument, "fulfillment_id is required")
+	}
+	if _, ok := nfa_feedback_v1alpha.Rating_name[int32(req.Rating)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown rating %d", req.Rating)
+	}
+	if req.Rating == nfa_feedback_v1alpha.Rating_RATING_UNSPECIFIED && req.Correction == "" {
+		return nil, status.Error(codes.InvalidArgument, "a rating or a correction is required")
+	}
+
+	s.mu.Lock()
+	ful := s.fulfillments[req.FulfillmentId]
+	s.mu.Unlock()
+	f := &nfa_feedback_v1alpha.Feedback{
+		Id:            "fb-" + randomHex(8),
+		FulfillmentId: req.FulfillmentId,
+		Rating:        req.Rating,
+		Correction:    req.Correction,
+		UserId:        req.UserId,
+		ServiceId:     ful.serviceID,
+		Action:        ful.input.Action,
+		CreateTime:    timestamppb.Now(),
+	}
+
+	if s.events != nil {
+		payload, err := proto.Marshal(f)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to encode feedback: %v", err)
+		}
+		attributes := map[string]string{AttributeFulfillmentID: f.FulfillmentId}
+		if f.UserId != "" {
+			attributes[pubsub.AttributeUserID] = f.UserId
+		}
+		if f.ServiceId != "" {
+			attributes[AttributeServiceID] = f.ServiceId
+		}
+		if _, err := s.events.Publish(ctx, &nfa_pubsub_v1alpha.PublishRequest{
+			Topic:      Topic,
+			Payload:    payload,
+			Attributes: attributes,
+		}); err != nil {
+			return nil, status.Errorf(codes.Unavailable, "failed to append feedback to the event log: %v", err)
+		}
+	}
+
+	s.mu.Lock()
+	s.feedback = append(s.feedback, f)
+	if len(s.feedback) > maxFeedback {
+		s.feedback = s.feedback[1:]
+	}
+	listeners := append([]Listener(nil), s.listeners...)
+	s.mu.Unlock()
+
+	if f.ServiceId == "" {
+		log.Printf("Feedback %s refers to unknown fulfillment %s", f.Id, f.FulfillmentId)
+	}
+	for _, fn := range listeners {
+		fn(proto.Clone(f).(*nfa_feedback_v1alpha.Feedback), ful.input)
+	}
+	return proto.Clone(f).(*nfa_feedback_v1alpha.Feedback), nil
+}
+
+// ListFeedback implements the ListFeedback RPC
+func (s *Service) ListFeedback(ctx context.Context, req *nfa_feedback_v1alpha.ListFeedbackRequest) (*nfa_feedback_v1alpha.ListFeedbackResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp := &nfa_feedback_v1alpha.ListFeedbackResponse{}
+	for i := len(s.feedback) - 1; i >= 0; i-- {
+		f := s.feedback[i]
+		if (req.ServiceId != "" && f.ServiceId != req.ServiceId) ||
+			(req.Action != "" && f.Action != req.Action) ||
+			(req.FulfillmentId != "" && f.FulfillmentId != req.FulfillmentId) {
+			continue
+		}
+		resp.Feedback = append(resp.Feedback, proto.Clone(f).(*nfa_feedback_v1alpha.Feedback))
+		if req.Limit > 0 && len(resp.Feedback) == int(req.Limit) {
+			break
+		}
+	}
+	return resp, nil
+}
+
+// Score rates feedback from 0 (bad) to 1 (good). A correction without a
+// rating scores 0, since the user had to fix the result.
+func Score(f *nfa_feedback_v1alpha.Feedback) float64 {
+	if f.Rating == nfa_feedback_v1alpha.Rating_RATING_THUMBS_UP {
+		return 1
+	}
+	return 0
+}
+
+// BanditListener reports feedback on known fulfillments to a bandit
+func BanditListener(b *bandit.Bandit) Listener {
+	return func(f *nfa_feedback_v1alpha.Feedback, input policy.Input) {
+		if f.ServiceId != "" {
+			b.Feedback(input, f.ServiceId, Score(f))
+		}
+	}
+}
+
+// ExperimentListener records feedback on known fulfillments as the
+// experiment.MetricFeedback metric of the variant they were assigned to
+func ExperimentListener(m *experiment.Manager) Listener {
+	return func(f *nfa_feedback_v1alpha.Feedback, input policy.Input) {
+		if f.ServiceId != "" {
+			m.Observe(input, experiment.MetricFeedback, Score(f))
+		}
+	}
+}
+
+// Name implements privacy.Store
+func (s *Service) Name() string { return "feedback" }
+
+// Records implements privacy.Store for the feedback kept for listing
+func (s *Service) Records(ctx context.Context, userID string) ([]*nfa_privacy_v1alpha.SubjectRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var records []*nfa_privacy_v1alpha.SubjectRecord
+	for _, f := range s.feedback {
+		if f.UserId != userID {
+			continue
+		}
+		data, err := protojson.Marshal(f)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, &nfa_privacy_v1alpha.SubjectRecord{
+			Store:       s.Name(),
+			Category:    nfa_privacy_v1alpha.DataCategory_DATA_CATEGORY_EVENTS,
+			Id:          f.Id,
+			CreateTime:  f.CreateTime,
+			Data:        data,
+			ContentType: "application/json",
+		})
+	}
+	return records, nil
+}
+
+// Purge implements privacy.Store for the feedback kept for listing; the
+// feedback events in the event log are purged with the other events
+func (s *Service) Purge(ctx context.Context, userID string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []string
+	kept := s.feedback[:0]
+	for _, f := range s.feedback {
+		if f.UserId == userID {
+			ids = append(ids, f.Id)
+			continue
+		}
+		kept = append(kept, f)
+	}
+	s.feedback = kept
+	return ids, nil
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
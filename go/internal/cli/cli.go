@@ -0,0 +1,25 @@
+// Package cli holds helpers shared by the NFA command line programs. It is
+// not part of the public API.
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParsePairs parses a comma-separated list of key=value pairs, as used for
+// labels, selectors and parameters on the command line
+func ParsePairs(s string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	if strings.TrimSpace(s) == "" {
+		return pairs, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%q is not a key=value pair", pair)
+		}
+		pairs[key] = value
+	}
+	return pairs, nil
+}
@@ -0,0 +1,160 @@
+// Package logging provides per-component structured loggers whose levels can
+// be changed at runtime, so a single subsystem can be switched to debug
+// logging in production without restarting the process
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Well-known components
+const (
+	Matcher  = "matcher"
+	Registry = "registry"
+	Health   = "health"
+	Control  = "control"
+	Storage  = "storage"
+	Security = "security"
+	Server   = "server"
+)
+
+type component struct {
+	level  *slog.LevelVar
+	logger *slog.Logger
+}
+
+var (
+	mu           sync.Mutex
+	output       io.Writer = os.Stderr
+	format                 = "text"
+	defaultLevel           = slog.LevelInfo
+	components             = make(map[string]*component)
+)
+
+// Configure sets the output format ("json" or "text") and resets every
+// component to the given level. Call it at startup, before loggers are cached,
+// since previously returned loggers keep their original format.
+func Configure(logFormat, level string) error {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	if logFormat != "json" && logFormat != "text" {
+		return fmt.Errorf("unknown log format %q", logFormat)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	format = logFormat
+	defaultLevel = lvl
+	for name, c := range components {
+		c.level.Set(lvl)
+		c.logger = newLogger(name, c.level)
+	}
+	return nil
+}
+
+// Logger returns the logger for a component, creating it on first use
+func Logger(name string) *slog.Logger {
+	mu.Lock()
+	defer mu.Unlock()
+	return lookup(name).logger
+}
+
+// SetLevel changes the level of one known component, or of every one when
+// name is "*"
+func SetLevel(name, level string) error {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if name == "*" {
+		defaultLevel = lvl
+		for _, c := range components {
+			c.level.Set(lvl)
+		}
+		return nil
+	}
+	c, ok := components[name]
+	if !ok {
+		return fmt.Errorf("unknown component %q, expected * or one of %s", name, strings.Join(names(), ", "))
+	}
+	c.level.Set(lvl)
+	return nil
+}
+
+// Levels returns the current level of every known component
+func Levels() map[string]string {
+	mu.Lock()
+	defer mu.Unlock()
+	levels := make(map[string]string, len(components))
+	for name, c := range components {
+		levels[name] = strings.ToLower(c.level.Level().String())
+	}
+	return levels
+}
+
+// Components returns the names of all known components, sorted
+func Components() []string {
+	mu.Lock()
+	defer mu.Unlock()
+	return names()
+}
+
+// names returns the names of all known components, sorted; mu must be held
+func names() []string {
+	sorted := make([]string, 0, len(components))
+	for name := range components {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// ParseLevel converts a level name (debug, info, warn, error) to a slog level
+func ParseLevel(level string) (slog.Level, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", level)
+	}
+	return lvl, nil
+}
+
+// lookup returns a component, registering it at the default level; mu must be held
+func lookup(name string) *component {
+	if c, ok := components[name]; ok {
+		return c
+	}
+	level := new(slog.LevelVar)
+	level.Set(defaultLevel)
+	c := &component{level: level, logger: newLogger(name, level)}
+	components[name] = c
+	return c
+}
+
+func newLogger(name string, level *slog.LevelVar) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	if format == "json" {
+		handler = slog.NewJSONHandler(output, opts)
+	} else {
+		handler = slog.NewTextHandler(output, opts)
+	}
+	return slog.New(handler).With("component", name)
+}
+
+func init() {
+	// Register the well-known components so they show up in Levels()
+	for _, name := range []string{Matcher, Registry, Health, Control, Storage, Security, Server} {
+		lookup(name)
+	}
+}
@@ -0,0 +1,113 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// capture sends the output of the loggers to a buffer until the test ends
+func capture(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	mu.Lock()
+	prev := output
+	output = &buf
+	mu.Unlock()
+	if err := Configure("text", "info"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		mu.Lock()
+		output = prev
+		mu.Unlock()
+		Configure("text", "info")
+	})
+	return &buf
+}
+
+func TestComponents(t *testing.T) {
+	got := Components()
+	for _, want := range []string{Matcher, Registry, Health, Control, Storage, Security, Server} {
+		if !slices.Contains(got, want) {
+			t.Errorf("Components() = %v, want %s among them", got, want)
+		}
+	}
+	if !slices.IsSorted(got) {
+		t.Errorf("Components() = %v, want them sorted", got)
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	buf := capture(t)
+	if err := SetLevel(Storage, "debug"); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	Logger(Storage).Debug("compacting")
+	Logger(Registry).Debug("registering")
+	if out := buf.String(); !strings.Contains(out, "compacting") || strings.Contains(out, "registering") {
+		t.Errorf("output = %q, want debug logs of storage only", out)
+	}
+	if levels := Levels(); levels[Storage] != "debug" || levels[Registry] != "info" {
+		t.Errorf("Levels() = %v, want storage at debug and registry at info", levels)
+	}
+
+	if err := SetLevel("*", "error"); err != nil {
+		t.Fatalf("SetLevel(*) error = %v", err)
+	}
+	for name, level := range Levels() {
+		if level != "error" {
+			t.Errorf("Levels()[%s] = %s after SetLevel(*), want error", name, level)
+		}
+	}
+}
+
+func TestSetLevelRejects(t *testing.T) {
+	capture(t)
+	before := Components()
+	tests := []struct {
+		name, component, level string
+	}{
+		{"unknown component", "registy", "debug"},
+		{"empty component", "", "debug"},
+		{"unknown level", Storage, "verbose"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetLevel(tt.component, tt.level); err == nil {
+				t.Errorf("SetLevel(%q, %q) error = nil, want an error", tt.component, tt.level)
+			}
+		})
+	}
+	if after := Components(); !slices.Equal(after, before) {
+		t.Errorf("Components() after rejected calls = %v, want %v", after, before)
+	}
+	if level := Levels()[Storage]; level != "info" {
+		t.Errorf("storage level = %s after a rejected call, want info", level)
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	buf := capture(t)
+	if err := Configure("json", "warn"); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	Logger(Security).Info("ignored")
+	Logger(Security).Warn("device revoked", "device", "kitchen-hub")
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("output %q is not one JSON entry: %v", buf.String(), err)
+	}
+	if entry["msg"] != "device revoked" || entry["component"] != Security || entry["device"] != "kitchen-hub" {
+		t.Errorf("entry = %v, want the warning with its component and attributes", entry)
+	}
+
+	if err := Configure("xml", "info"); err == nil {
+		t.Errorf("Configure() of an unknown format error = nil")
+	}
+	if err := Configure("text", "loud"); err == nil {
+		t.Errorf("Configure() of an unknown level error = nil")
+	}
+}
@@ -0,0 +1,94 @@
+// Package metrics defines the limits on the series of the metrics the
+// runtime and the broker export. They are part of the configuration file,
+// see config.MetricsConfig, and applied by the Prometheus exporter of
+// package metrics/prometheus.
+package metrics
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+)
+
+// OtherValue replaces the label values that Limits does not export
+// individually
+const OtherValue = "other"
+
+// LimitedLabels are the labels whose values come from contracts, services
+// and callers, and so grow with the catalog. The result, code and state
+// labels have a fixed set of values and are always exported.
+var LimitedLabels = []string{"contract", "service_id", "method", "action"}
+
+// Limits bound the number of series the metrics export on large catalogs.
+// A value of a limited label is first replaced with the name of its bucket,
+// if any, then with OtherValue unless the allowlist and the top K of its
+// label both admit it. The zero Limits exports every value.
+type Limits struct {
+	// Labels lists the limited labels exported, out of contract, service_id,
+	// method and action; the others are removed and their series summed.
+	// Nil exports all of them.
+	Labels []string `toml:"labels,omitempty"`
+	// Allow lists, by label, the values exported individually as
+	// path.Match patterns, e.g. {"action": ["lights_*"]}; labels not listed
+	// export every value
+	Allow map[string][]string `toml:"allow,omitempty"`
+	// Buckets aggregate, by label, the values matching a pattern into one,
+	// e.g. every "translate_*" action into "translate"
+	Buckets map[string][]Bucket `toml:"buckets,omitempty"`
+	// TopK, when positive, exports at most TopK values of each limited
+	// label: those seen most often recently, elected at every scrape. The
+	// others are counted under OtherValue, and the series of a value
+	// leaving the top are removed, so aggregate with sum(rate(...)).
+	TopK int `toml:"top_k,omitempty"`
+	// DurationBuckets are the upper bounds of the duration histograms in
+	// seconds; nil selects prometheus.DefBuckets
+	DurationBuckets []float64 `toml:"duration_buckets,omitempty"`
+}
+
+// Bucket aggregates the values of a label matching Pattern, a path.Match
+// pattern, into Name
+type Bucket struct {
+	Pattern string `toml:"pattern"`
+	Name    string `toml:"name"`
+}
+
+// Validate reports unknown labels, malformed patterns and negative bounds,
+// e.g. after loading Limits from a configuration file
+func (l Limits) Validate() error {
+	for _, label := range l.Labels {
+		if !slices.Contains(LimitedLabels, label) {
+			return fmt.Errorf("unknown label %q, expected one of %v", label, LimitedLabels)
+		}
+	}
+	for label, patterns := range l.Allow {
+		if !slices.Contains(LimitedLabels, label) {
+			return fmt.Errorf("allow: unknown label %q, expected one of %v", label, LimitedLabels)
+		}
+		for _, p := range patterns {
+			if _, err := path.Match(p, ""); err != nil {
+				return fmt.Errorf("allow: invalid pattern %q for %s: %w", p, label, err)
+			}
+		}
+	}
+	for label, buckets := range l.Buckets {
+		if !slices.Contains(LimitedLabels, label) {
+			return fmt.Errorf("buckets: unknown label %q, expected one of %v", label, LimitedLabels)
+		}
+		for _, b := range buckets {
+			if _, err := path.Match(b.Pattern, ""); err != nil {
+				return fmt.Errorf("buckets: invalid pattern %q for %s: %w", b.Pattern, label, err)
+			}
+			if b.Name == "" {
+				return fmt.Errorf("buckets: pattern %q for %s has no name", b.Pattern, label)
+			}
+		}
+	}
+	if l.TopK < 0 {
+		return fmt.Errorf("top_k must not be negative")
+	}
+	if !sort.Float64sAreSorted(l.DurationBuckets) {
+		return fmt.Errorf("duration_buckets must be in increasing order")
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/status"
+)
+
+// BrokerMetrics records the measurements of an embedded broker, passed to
+// broker.WithMetrics:
+//
+//	nfa_broker_requests_total{method,code}                  RPCs handled by the broker
+//	nfa_broker_request_duration_seconds{method}             handler latency of those RPCs
+//	nfa_broker_registrations_total{contract,result}         contract registrations
+//	nfa_broker_heartbeats_total{service_id,result}          heartbeats received
+//	nfa_broker_matches_total{action,result}                 intents matched, by action
+//	nfa_broker_match_duration_seconds{action}               matching latency
+//
+// The broker sees the heartbeats of every service and the actions of every
+// consumer, so its labels grow fastest of all; WithLimits bounds them as
+// those of Metrics.
+type BrokerMetrics struct {
+	limiter         *limiter
+	requests        *prom.CounterVec
+	requestDuration *prom.HistogramVec
+	registrations   *prom.CounterVec
+	heartbeats      *prom.CounterVec
+	matches         *prom.CounterVec
+	matchDuration   *prom.HistogramVec
+}
+
+var (
+	_ broker.Metrics = (*BrokerMetrics)(nil)
+	_ prom.Collector = (*BrokerMetrics)(nil)
+)
+
+// NewBroker returns BrokerMetrics with no measurements recorded
+func NewBroker(opts ...Option) *BrokerMetrics {
+	l := newLimiterFor(opts)
+	m := &BrokerMetrics{limiter: l}
+	buckets := l.durationBuckets()
+
+	m.requests = prom.NewCounterVec(prom.CounterOpts{
+		Namespace: "nfa", Subsystem: "broker", Name: "requests_total",
+		Help: "RPCs handled by the broker, by method and gRPC status code.",
+	}, l.names(requestLabels))
+	m.requestDuration = prom.NewHistogramVec(prom.HistogramOpts{
+		Namespace: "nfa", Subsystem: "broker", Name: "request_duration_seconds",
+		Help:    "Time the broker took to handle RPCs, by method.",
+		Buckets: buckets,
+	}, l.names(requestDurationLabels))
+	m.registrations = prom.NewCounterVec(prom.CounterOpts{
+		Namespace: "nfa", Subsystem: "broker", Name: "registrations_total",
+		Help: "Contract registrations handled by the broker, by contract and result.",
+	}, l.names(registrationLabels))
+	m.heartbeats = prom.NewCounterVec(prom.CounterOpts{
+		Namespace: "nfa", Subsystem: "broker", Name: "heartbeats_total",
+		Help: "Heartbeats received by the broker, by service ID and result.",
+	}, l.names(heartbeatLabels))
+	m.matches = prom.NewCounterVec(prom.CounterOpts{
+		Namespace: "nfa", Subsystem: "broker", Name: "matches_total",
+		Help: "Intents matched by the broker, by action and result.",
+	}, l.names(intentLabels))
+	m.matchDuration = prom.NewHistogramVec(prom.HistogramOpts{
+		Namespace: "nfa", Subsystem: "broker", Name: "match_duration_seconds",
+		Help:    "Time the broker took to match intents, by action.",
+		Buckets: buckets,
+	}, l.names(intentDurationLabels))
+
+	l.track(m.requests, requestLabels)
+	l.track(m.requestDuration, requestDurationLabels)
+	l.track(m.registrations, registrationLabels)
+	l.track(m.heartbeats, heartbeatLabels)
+	l.track(m.matches, intentLabels)
+	l.track(m.matchDuration, intentDurationLabels)
+	return m
+}
+
+func (m *BrokerMetrics) collectors() []prom.Collector {
+	return []prom.Collector{m.requests, m.requestDuration, m.registrations, m.heartbeats, m.matches, m.matchDuration}
+}
+
+// Describe implements prometheus.Collector
+func (m *BrokerMetrics) Describe(ch chan<- *prom.Desc) {
+	for _, c := range m.collectors() {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector, electing the top K values as
+// Metrics.Collect does
+func (m *BrokerMetrics) Collect(ch chan<- prom.Metric) {
+	for _, c := range m.collectors() {
+		c.Collect(ch)
+	}
+	m.limiter.Lock()
+	m.limiter.rank()
+	m.limiter.Unlock()
+}
+
+// Request implements broker.Metrics
+func (m *BrokerMetrics) Request(method string, duration time.Duration, err error) {
+	m.limiter.Lock()
+	defer m.limiter.Unlock()
+	m.requests.WithLabelValues(m.limiter.values(requestLabels, method, status.Code(err).String())...).Inc()
+	m.requestDuration.WithLabelValues(m.limiter.values(requestDurationLabels, method)...).Observe(duration.Seconds())
+}
+
+// Registration implements broker.Metrics
+func (m *BrokerMetrics) Registration(contract string, err error) {
+	m.limiter.Lock()
+	defer m.limiter.Unlock()
+	m.registrations.WithLabelValues(m.limiter.values(registrationLabels, contract, result(err))...).Inc()
+}
+
+// Heartbeat implements broker.Metrics
+func (m *BrokerMetrics) Heartbeat(serviceID string, err error) {
+	m.limiter.Lock()
+	defer m.limiter.Unlock()
+	m.heartbeats.WithLabelValues(m.limiter.values(heartbeatLabels, serviceID, result(err))...).Inc()
+}
+
+// Match implements broker.Metrics
+func (m *BrokerMetrics) Match(action string, duration time.Duration, err error) {
+	m.limiter.Lock()
+	defer m.limiter.Unlock()
+	m.matches.WithLabelValues(m.limiter.values(intentLabels, action, result(err))...).Inc()
+	m.matchDuration.WithLabelValues(m.limiter.values(intentDurationLabels, action)...).Observe(duration.Seconds())
+}
+
+// Handler serves m in the Prometheus exposition format, together with the
+// Go runtime and process metrics
+func (m *BrokerMetrics) Handler() http.Handler {
+	return handler(m)
+}
+
+// ListenAndServe serves Handler at /metrics on addr until ctx ends
+func (m *BrokerMetrics) ListenAndServe(ctx context.Context, addr string) error {
+	return listenAndServe(ctx, addr, m.Handler())
+}
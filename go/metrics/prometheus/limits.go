@@ -0,0 +1,206 @@
+package prometheus
+
+import (
+	"path"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/metrics"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// OtherValue replaces the label values that Limits does not export
+// individually
+const OtherValue = metrics.OtherValue
+
+// Limits bound the number of series the metrics export, see metrics.Limits
+type Limits = metrics.Limits
+
+// Bucket aggregates the values of a label matching a pattern into one
+type Bucket = metrics.Bucket
+
+// trackedPerKept is how many values of a label TopK ranks for each value it
+// exports, so that values gaining traffic can replace the current top
+const trackedPerKept = 8
+
+// limitedLabels are the labels whose values Limits bounds
+var limitedLabels = metrics.LimitedLabels
+
+// deleter is a metric vector whose series can be removed
+type deleter interface {
+	DeletePartialMatch(labels prom.Labels) int
+}
+
+// limiter applies Limits to the label values of measurements. Its methods
+// are called with it locked, so that TopK does not evict a value between
+// its ranking and the update of its series.
+type limiter struct {
+	sync.Mutex
+	limits Limits
+	// rankings of the limited labels by name, when TopK is positive
+	rankings map[string]*ranking
+	// vectors exporting each limited label, whose series of the values
+	// leaving the top K are removed
+	vectors map[string][]deleter
+}
+
+func newLimiter(limits Limits) *limiter {
+	l := &limiter{limits: limits, vectors: make(map[string][]deleter)}
+	if limits.TopK > 0 {
+		l.rankings = make(map[string]*ranking)
+		for _, label := range limitedLabels {
+			l.rankings[label] = &ranking{counts: make(map[string]float64), top: make(map[string]bool)}
+		}
+	}
+	return l
+}
+
+// durationBuckets returns the buckets of the duration histograms
+func (l *limiter) durationBuckets() []float64 {
+	if l.limits.DurationBuckets != nil {
+		return l.limits.DurationBuckets
+	}
+	return prom.DefBuckets
+}
+
+// exported reports whether label is exported
+func (l *limiter) exported(label string) bool {
+	return l.limits.Labels == nil || !slices.Contains(limitedLabels, label) || slices.Contains(l.limits.Labels, label)
+}
+
+// names returns the label names of a vector with labels that are exported
+func (l *limiter) names(labels []string) []string {
+	var names []string
+	for _, label := range labels {
+		if l.exported(label) {
+			names = append(names, label)
+		}
+	}
+	return names
+}
+
+// track registers vec, created with labels, for the removal of the series
+// of values leaving the top K
+func (l *limiter) track(vec deleter, labels []string) {
+	for _, label := range l.names(labels) {
+		if slices.Contains(limitedLabels, label) {
+			l.vectors[label] = append(l.vectors[label], vec)
+		}
+	}
+}
+
+// values returns the exported values of labels for a measurement with
+// values
+func (l *limiter) values(labels []string, values ...string) []string {
+	var exported []string
+	for i, label := range labels {
+		if !l.exported(label) {
+			continue
+		}
+		if slices.Contains(limitedLabels, label) {
+			exported = append(exported, l.value(label, values[i]))
+		} else {
+			exported = append(exported, values[i])
+		}
+	}
+	return exported
+}
+
+func (l *limiter) value(label, value string) string {
+	for _, b := range l.limits.Buckets[label] {
+		if ok, _ := path.Match(b.Pattern, value); ok {
+			value = b.Name
+			break
+		}
+	}
+	if patterns, ok := l.limits.Allow[label]; ok && !slices.ContainsFunc(patterns, func(p string) bool {
+		ok, _ := path.Match(p, value)
+		return ok
+	}) {
+		return OtherValue
+	}
+	if r := l.rankings[label]; r != nil && !r.observe(value, l.limits.TopK) {
+		return OtherValue
+	}
+	return value
+}
+
+// rank elects the top K values of every label from their recent counts and
+// removes the series of the values that left it
+func (l *limiter) rank() {
+	for label, r := range l.rankings {
+		for _, value := range r.rank(l.limits.TopK) {
+			for _, vec := range l.vectors[label] {
+				vec.DeletePartialMatch(prom.Labels{label: value})
+			}
+		}
+	}
+}
+
+// ranking tracks how often the values of a label are seen, with the
+// space-saving algorithm: at most trackedPerKept*K values are counted, and a
+// new value replaces the least seen one and inherits its count, so values
+// seen often are never missed.
+type ranking struct {
+	counts map[string]float64
+	// top are the values exported individually
+	top map[string]bool
+}
+
+// observe counts value and reports whether it is exported individually.
+// Values are admitted to the top while it has room; after that they enter
+// it at the next rank.
+func (r *ranking) observe(value string, k int) bool {
+	if _, ok := r.counts[value]; !ok && len(r.counts) >= trackedPerKept*k {
+		least, min := "", 0.0
+		for v, c := range r.counts {
+			if !r.top[v] && (least == "" || c < min) {
+				least, min = v, c
+			}
+		}
+		delete(r.counts, least)
+		r.counts[value] = min
+	}
+	r.counts[value]++
+	if r.top[value] {
+		return true
+	}
+	if len(r.top) < k {
+		r.top[value] = true
+		return true
+	}
+	return false
+}
+
+// rank replaces the top with the k values counted most, halves every count
+// so that the next rank favours recent traffic, and returns the values that
+// left the top
+func (r *ranking) rank(k int) []string {
+	values := make([]string, 0, len(r.counts))
+	for v, c := range r.counts {
+		values = append(values, v)
+		r.counts[v] = c / 2
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if r.counts[values[i]] != r.counts[values[j]] {
+			return r.counts[values[i]] > r.counts[values[j]]
+		}
+		return values[i] < values[j]
+	})
+	if len(values) > k {
+		values = values[:k]
+	}
+	top := make(map[string]bool, len(values))
+	for _, v := range values {
+		top[v] = true
+	}
+	var evicted []string
+	for v := range r.top {
+		if !top[v] {
+			evicted = append(evicted, v)
+		}
+	}
+	r.top = top
+	return evicted
+}
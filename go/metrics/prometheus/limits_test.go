@@ -0,0 +1,253 @@
+package prometheus
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// gather collects c as a scrape does, ranking the top K, and returns the
+// label values of the series of each metric, e.g.
+// "action=lights_on,result=ok", sorted
+func gather(t *testing.T, c prom.Collector) map[string][]string {
+	t.Helper()
+	registry := prom.NewRegistry()
+	registry.MustRegister(c)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	series := make(map[string][]string)
+	for _, family := range families {
+		for _, m := range family.Metric {
+			var pairs []string
+			for _, label := range m.Label {
+				pairs = append(pairs, label.GetName()+"="+label.GetValue())
+			}
+			series[family.GetName()] = append(series[family.GetName()], strings.Join(pairs, ","))
+		}
+		sort.Strings(series[family.GetName()])
+	}
+	return series
+}
+
+func TestRankingTopK(t *testing.T) {
+	// Steps observe a value, or elect the top with "|"
+	tests := []struct {
+		name         string
+		k            int
+		steps        []string
+		wantExported []string
+		wantEvicted  []string
+		wantTop      []string
+	}{
+		{
+			name:         "admitted while the top has room",
+			k:            2,
+			steps:        []string{"a", "b", "c", "a", "c"},
+			wantExported: []string{"a", "b", "a"},
+			wantTop:      []string{"a", "b"},
+		},
+		{
+			name:         "most seen elected",
+			k:            2,
+			steps:        []string{"a", "b", "c", "c", "c", "|", "b", "c", "a"},
+			wantExported: []string{"a", "b", "c", "a"},
+			wantEvicted:  []string{"b"},
+			wantTop:      []string{"a", "c"},
+		},
+		{
+			name:         "recent traffic outranks old",
+			k:            1,
+			steps:        []string{"a", "a", "a", "a", "|", "b", "b", "b", "|", "a", "b"},
+			wantExported: []string{"a", "a", "a", "a", "b"},
+			wantEvicted:  []string{"a"},
+			wantTop:      []string{"b"},
+		},
+		{
+			name:         "ties elected by value",
+			k:            1,
+			steps:        []string{"b", "a", "|", "a"},
+			wantExported: []string{"b", "a"},
+			wantEvicted:  []string{"b"},
+			wantTop:      []string{"a"},
+		},
+		{
+			name:         "value seen often not missed past the tracked values",
+			k:            1,
+			steps:        []string{"a", "v1", "v2", "v3", "v4", "v5", "v6", "v7", "v8", "v9", "hot", "hot", "hot", "|"},
+			wantExported: []string{"a"},
+			wantEvicted:  []string{"a"},
+			wantTop:      []string{"hot"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ranking{counts: make(map[string]float64), top: make(map[string]bool)}
+			var exported, evicted []string
+			for _, step := range tt.steps {
+				if step == "|" {
+					evicted = append(evicted, r.rank(tt.k)...)
+					continue
+				}
+				if r.observe(step, tt.k) {
+					exported = append(exported, step)
+				}
+				if len(r.counts) > trackedPerKept*tt.k {
+					t.Fatalf("%d values counted, want at most %d", len(r.counts), trackedPerKept*tt.k)
+				}
+			}
+			var top []string
+			for v := range r.top {
+				top = append(top, v)
+			}
+			sort.Strings(top)
+			sort.Strings(evicted)
+			if !slices.Equal(exported, tt.wantExported) {
+				t.Errorf("exported %v, want %v", exported, tt.wantExported)
+			}
+			if !slices.Equal(evicted, tt.wantEvicted) {
+				t.Errorf("rank() evicted %v, want %v", evicted, tt.wantEvicted)
+			}
+			if !slices.Equal(top, tt.wantTop) {
+				t.Errorf("top = %v, want %v", top, tt.wantTop)
+			}
+		})
+	}
+}
+
+func TestLimiterValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits Limits
+		labels []string
+		values [][]string
+		want   [][]string
+	}{
+		{
+			name:   "no limits",
+			labels: intentLabels,
+			values: [][]string{{"lights_on", "ok"}},
+			want:   [][]string{{"lights_on", "ok"}},
+		},
+		{
+			name:   "removed label",
+			limits: Limits{Labels: []string{"action"}},
+			labels: []string{"service_id", "action", "result"},
+			values: [][]string{{"svc-1", "lights_on", "ok"}},
+			want:   [][]string{{"lights_on", "ok"}},
+		},
+		{
+			name:   "bucket",
+			limits: Limits{Buckets: map[string][]Bucket{"action": {{Pattern: "translate_*", Name: "translate"}}}},
+			labels: intentLabels,
+			values: [][]string{{"translate_text", "ok"}, {"translate_speech", "error"}, {"lights_on", "ok"}},
+			want:   [][]string{{"translate", "ok"}, {"translate", "error"}, {"lights_on", "ok"}},
+		},
+		{
+			name:   "allowlist",
+			limits: Limits{Allow: map[string][]string{"action": {"lights_*"}}},
+			labels: intentLabels,
+			values: [][]string{{"lights_on", "ok"}, {"tv_on", "ok"}},
+			want:   [][]string{{"lights_on", "ok"}, {OtherValue, "ok"}},
+		},
+		{
+			name: "allowlist of buckets",
+			limits: Limits{
+				Buckets: map[string][]Bucket{"action": {{Pattern: "translate_*", Name: "translate"}}},
+				Allow:   map[string][]string{"action": {"translate"}},
+			},
+			labels: intentLabels,
+			values: [][]string{{"translate_text", "ok"}, {"translate", "ok"}, {"transcribe", "ok"}},
+			want:   [][]string{{"translate", "ok"}, {"translate", "ok"}, {OtherValue, "ok"}},
+		},
+		{
+			name:   "other beyond the top K",
+			limits: Limits{TopK: 1},
+			labels: intentLabels,
+			values: [][]string{{"lights_on", "ok"}, {"tv_on", "error"}, {"lights_on", "error"}},
+			want:   [][]string{{"lights_on", "ok"}, {OtherValue, "error"}, {"lights_on", "error"}},
+		},
+		{
+			name:   "top K per label",
+			limits: Limits{TopK: 1},
+			labels: []string{"service_id", "action"},
+			values: [][]string{{"svc-1", "lights_on"}, {"svc-1", "tv_on"}, {"svc-2", "lights_on"}},
+			want:   [][]string{{"svc-1", "lights_on"}, {"svc-1", OtherValue}, {OtherValue, "lights_on"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLimiter(tt.limits)
+			for i, values := range tt.values {
+				if got := l.values(tt.labels, values...); !slices.Equal(got, tt.want[i]) {
+					t.Errorf("values(%v) = %v, want %v", values, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCollectRemovesEvictedSeries(t *testing.T) {
+	m := New(WithLimits(Limits{TopK: 1}))
+	const name = "nfa_server_intents_total"
+	intent := func(action string, n int) {
+		for i := 0; i < n; i++ {
+			m.Intent(action, time.Millisecond, nil)
+		}
+	}
+
+	intent("lights_on", 1)
+	if got := gather(t, m)[name]; !slices.Equal(got, []string{"action=lights_on,result=ok"}) {
+		t.Errorf("series = %v, want lights_on", got)
+	}
+	intent("tv_on", 3)
+	want := []string{"action=lights_on,result=ok", "action=other,result=ok"}
+	if got := gather(t, m)[name]; !slices.Equal(got, want) {
+		t.Errorf("series with tv_on beyond the top = %v, want %v", got, want)
+	}
+	// tv_on took the top at the last scrape, which removed lights_on
+	if got := gather(t, m)[name]; !slices.Equal(got, []string{"action=other,result=ok"}) {
+		t.Errorf("series after the rank = %v, want only other", got)
+	}
+	intent("tv_on", 1)
+	intent("lights_on", 1)
+	want = []string{"action=other,result=ok", "action=tv_on,result=ok"}
+	if got := gather(t, m)[name]; !slices.Equal(got, want) {
+		t.Errorf("series = %v, want %v", got, want)
+	}
+}
+
+func TestBrokerMetricsLimits(t *testing.T) {
+	m := NewBroker(WithLimits(Limits{
+		Labels:  []string{"contract", "action"},
+		Buckets: map[string][]Bucket{"action": {{Pattern: "translate_*", Name: "translate"}}},
+		TopK:    1,
+	}))
+	m.Registration("translator", nil)
+	m.Registration("lights", errors.New("rejected"))
+	m.Heartbeat("translator-1", nil)
+	m.Heartbeat("lights-1", nil)
+	m.Match("translate_text", time.Millisecond, nil)
+	m.Match("translate_speech", time.Millisecond, nil)
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"nfa_broker_registrations_total", []string{"contract=other,result=error", "contract=translator,result=ok"}},
+		{"nfa_broker_heartbeats_total", []string{"result=ok"}},
+		{"nfa_broker_matches_total", []string{"action=translate,result=ok"}},
+	}
+	series := gather(t, m)
+	for _, tt := range tests {
+		if got := series[tt.name]; !slices.Equal(got, tt.want) {
+			t.Errorf("%s series = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+// Package prometheus exports the measurements of the intent runtime and
+// intent server as Prometheus metrics, and those of the embedded broker, see
+// BrokerMetrics:
+//
+//	nfa_runtime_registrations_total{contract,result}        contract registrations
+//	nfa_runtime_heartbeats_total{service_id,result}         heartbeats sent to the broker
+//	nfa_runtime_broker_connection_state{state}              1 for the current state of the broker connection
+//	nfa_server_requests_total{method,code}                  RPCs handled by the intent server
+//	nfa_server_request_duration_seconds{method}             handler latency of those RPCs
+//	nfa_server_intents_total{action,result}                 unary intent requests by action
+//	nfa_server_intent_duration_seconds{action}              handler latency of those requests
+//
+// Metrics is passed to runtime.WithMetrics and is a prometheus.Collector the
+// host application registers with its own registry, or serves with Handler
+// or ListenAndServe.
+//
+// The contract, service_id, method and action labels grow with the catalog
+// and the callers. WithLimits, or the [metrics.limits] section of the
+// configuration file, keeps the series of large deployments bounded:
+// it drops those labels, aggregates or allowlists their values and exports
+// only the top K values of each, see Limits.
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/runtime"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	resultOK    = "ok"
+	resultError = "error"
+)
+
+// connectionStates are the states of the broker connection gauge
+var connectionStates = []connectivity.State{
+	connectivity.Idle,
+	connectivity.Connecting,
+	connectivity.Ready,
+	connectivity.TransientFailure,
+	connectivity.Shutdown,
+}
+
+// Label names of the metrics, before Limits removes any
+var (
+	registrationLabels    = []string{"contract", "result"}
+	heartbeatLabels       = []string{"service_id", "result"}
+	requestLabels         = []string{"method", "code"}
+	requestDurationLabels = []string{"method"}
+	intentLabels          = []string{"action", "result"}
+	intentDurationLabels  = []string{"action"}
+)
+
+// Metrics records the measurements of the runtimes and servers it is passed
+// to. Several runtimes and servers may share one.
+type Metrics struct {
+	limiter         *limiter
+	registrations   *prom.CounterVec
+	heartbeats      *prom.CounterVec
+	connection      *prom.GaugeVec
+	requests        *prom.CounterVec
+	requestDuration *prom.HistogramVec
+	intents         *prom.CounterVec
+	intentDuration  *prom.HistogramVec
+}
+
+var (
+	_ runtime.Metrics           = (*Metrics)(nil)
+	_ runtime.IntentMetrics     = (*Metrics)(nil)
+	_ runtime.ConnectionMetrics = (*Metrics)(nil)
+	_ prom.Collector            = (*Metrics)(nil)
+)
+
+// Option configures Metrics and BrokerMetrics
+type Option func(*options)
+
+type options struct {
+	limits Limits
+}
+
+// WithLimits bounds the series of the metrics with limits. Like the
+// constructors of the Prometheus client, New and NewBroker panic when
+// limits are invalid; check limits read from a file with Limits.Validate.
+func WithLimits(limits Limits) Option {
+	return func(o *options) {
+		if err := limits.Validate(); err != nil {
+			panic(fmt.Sprintf("invalid metrics limits: %v", err))
+		}
+		o.limits = limits
+	}
+}
+
+// newLimiterFor returns the limiter of the metrics configured by opts
+func newLimiterFor(opts []Option) *limiter {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return newLimiter(o.limits)
+}
+
+// New returns Metrics with no measurements recorded
+func New(opts ...Option) *Metrics {
+	l := newLimiterFor(opts)
+	m := &Metrics{limiter: l}
+	buckets := l.durationBuckets()
+
+	m.registrations = prom.NewCounterVec(prom.CounterOpts{
+		Namespace: "nfa", Subsystem: "runtime", Name: "registrations_total",
+		Help: "Contract registrations with the broker, by contract and result.",
+	}, l.names(registrationLabels))
+	m.heartbeats = prom.NewCounterVec(prom.CounterOpts{
+		Namespace: "nfa", Subsystem: "runtime", Name: "heartbeats_total",
+		Help: "Heartbeats sent to the broker, by service ID and result.",
+	}, l.names(heartbeatLabels))
+	m.connection = prom.NewGaugeVec(prom.GaugeOpts{
+		Namespace: "nfa", Subsystem: "runtime", Name: "broker_connection_state",
+		Help: "State of the broker connection: 1 for the current state, 0 for the others.",
+	}, []string{"state"})
+	m.requests = prom.NewCounterVec(prom.CounterOpts{
+		Namespace: "nfa", Subsystem: "server", Name: "requests_total",
+		Help: "RPCs handled by the intent server, by method and gRPC status code.",
+	}, l.names(requestLabels))
+	m.requestDuration = prom.NewHistogramVec(prom.HistogramOpts{
+		Namespace: "nfa", Subsystem: "server", Name: "request_duration_seconds",
+		Help:    "Time the intent server took to handle RPCs, by method.",
+		Buckets: buckets,
+	}, l.names(requestDurationLabels))
+	m.intents = prom.NewCounterVec(prom.CounterOpts{
+		Namespace: "nfa", Subsystem: "server", Name: "intents_total",
+		Help: "Intent requests handled by the intent server, by action and result.",
+	}, l.names(intentLabels))
+	m.intentDuration = prom.NewHistogramVec(prom.HistogramOpts{
+		Namespace: "nfa", Subsystem: "server", Name: "intent_duration_seconds",
+		Help:    "Time the intent server took to handle intent requests, by action.",
+		Buckets: buckets,
+	}, l.names(intentDurationLabels))
+
+	l.track(m.registrations, registrationLabels)
+	l.track(m.heartbeats, heartbeatLabels)
+	l.track(m.requests, requestLabels)
+	l.track(m.requestDuration, requestDurationLabels)
+	l.track(m.intents, intentLabels)
+	l.track(m.intentDuration, intentDurationLabels)
+	return m
+}
+
+func (m *Metrics) collectors() []prom.Collector {
+	return []prom.Collector{m.registrations, m.heartbeats, m.connection, m.requests, m.requestDuration, m.intents, m.intentDuration}
+}
+
+// Describe implements prometheus.Collector
+func (m *Metrics) Describe(ch chan<- *prom.Desc) {
+	for _, c := range m.collectors() {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector. With a top K limit, every
+// collection then elects the values exported until the next one and removes
+// the series of the values that left the top, so they are scraped one last
+// time.
+func (m *Metrics) Collect(ch chan<- prom.Metric) {
+	for _, c := range m.collectors() {
+		c.Collect(ch)
+	}
+	m.limiter.Lock()
+	m.limiter.rank()
+	m.limiter.Unlock()
+}
+
+// Registration implements runtime.Metrics
+func (m *Metrics) Registration(contract string, err error) {
+	m.limiter.Lock()
+	defer m.limiter.Unlock()
+	m.registrations.WithLabelValues(m.limiter.values(registrationLabels, contract, result(err))...).Inc()
+}
+
+// Heartbeat implements runtime.Metrics
+func (m *Metrics) Heartbeat(serviceID string, err error) {
+	m.limiter.Lock()
+	defer m.limiter.Unlock()
+	m.heartbeats.WithLabelValues(m.limiter.values(heartbeatLabels, serviceID, result(err))...).Inc()
+}
+
+// Request implements runtime.Metrics
+func (m *Metrics) Request(method string, duration time.Duration, err error) {
+	m.limiter.Lock()
+	defer m.limiter.Unlock()
+	m.requests.WithLabelValues(m.limiter.values(requestLabels, method, status.Code(err).String())...).Inc()
+	m.requestDuration.WithLabelValues(m.limiter.values(requestDurationLabels, method)...).Observe(duration.Seconds())
+}
+
+// Intent implements runtime.IntentMetrics
+func (m *Metrics) Intent(action string, duration time.Duration, err error) {
+	m.limiter.Lock()
+	defer m.limiter.Unlock()
+	m.intents.WithLabelValues(m.limiter.values(intentLabels, action, result(err))...).Inc()
+	m.intentDuration.WithLabelValues(m.limiter.values(intentDurationLabels, action)...).Observe(duration.Seconds())
+}
+
+// BrokerConnection implements runtime.ConnectionMetrics
+func (m *Metrics) BrokerConnection(state connectivity.State) {
+	for _, s := range connectionStates {
+		value := 0.0
+		if s == state {
+			value = 1
+		}
+		m.connection.WithLabelValues(s.String()).Set(value)
+	}
+}
+
+// Handler serves m in the Prometheus exposition format, together with the
+// Go runtime and process metrics
+func (m *Metrics) Handler() http.Handler {
+	return handler(m)
+}
+
+// ListenAndServe serves Handler at /metrics on addr until ctx ends, for
+// hosts without an HTTP server of their own
+func (m *Metrics) ListenAndServe(ctx context.Context, addr string) error {
+	return listenAndServe(ctx, addr, m.Handler())
+}
+
+func handler(c prom.Collector) http.Handler {
+	registry := prom.NewRegistry()
+	registry.MustRegister(c, collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+func listenAndServe(ctx context.Context, addr string, h http.Handler) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", h)
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	stop := context.AfterFunc(ctx, func() { server.Close() })
+	defer stop()
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return ctx.Err()
+}
+
+func result(err error) string {
+	if err != nil {
+		return resultError
+	}
+	return resultOK
+}
@@ -0,0 +1,187 @@
+package adminclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	nfa_analytics_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/analytics/v1alpha"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Experiment splits the users or sessions invoking some actions into
+// cohorts, each routed to the providers of one variant
+type Experiment struct {
+	Name string
+	// Actions taking part, as path.Match patterns; empty means every action
+	Actions  []string
+	Variants []Variant
+	// What cohorts are assigned by
+	CohortKey CohortKey
+	// Metrics compared between variants, e.g. "latency_ms" or "error"
+	Metrics []string
+	// How long the experiment runs; zero means until stopped
+	Duration time.Duration
+
+	// Set by the broker
+	Start, End time.Time
+	State      ExperimentState
+}
+
+// Variant is the share of an experiment's cohorts routed to some providers
+type Variant struct {
+	Name string
+	// Labels of the providers serving this variant's cohort
+	Selector map[string]string
+	// Share of the cohorts assigned to this variant, relative to the other
+	// variants
+	Weight float64
+}
+
+// CohortKey is what an experiment assigns cohorts by
+type CohortKey string
+
+const (
+	CohortUser    CohortKey = "user"
+	CohortSession CohortKey = "session"
+)
+
+// ExperimentState is the lifecycle state of an experiment
+type ExperimentState string
+
+const (
+	ExperimentRunning   ExperimentState = "running"
+	ExperimentCompleted ExperimentState = "completed"
+	ExperimentStopped   ExperimentState = "stopped"
+)
+
+// ExperimentResults summarizes the metrics of every variant of an experiment
+type ExperimentResults struct {
+	Experiment Experiment
+	Variants   []VariantSummary
+}
+
+// VariantSummary is the metrics of one variant
+type VariantSummary struct {
+	Variant string
+	// Intents routed under this variant
+	Assignments uint64
+	Metrics     []MetricSummary
+}
+
+// MetricSummary summarizes the values of a metric
+type MetricSummary struct {
+	Name           string
+	Count          uint64
+	Mean, Min, Max float64
+}
+
+// CreateExperiment starts an experiment and returns it as the broker
+// started it; names are unique among the broker's experiments
+func (c *Client) CreateExperiment(ctx context.Context, exp Experiment) (*Experiment, error) {
+	created, err := c.analytics.CreateExperiment(ctx, &nfa_analytics_v1alpha.CreateExperimentRequest{Experiment: exp.toProto()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create experiment %s: %w", exp.Name, err)
+	}
+	out := experimentFromProto(created)
+	return &out, nil
+}
+
+// Experiments returns every experiment of the broker, running or finished
+func (c *Client) Experiments(ctx context.Context) ([]Experiment, error) {
+	resp, err := c.analytics.ListExperiments(ctx, &nfa_analytics_v1alpha.ListExperimentsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list experiments: %w", err)
+	}
+	exps := make([]Experiment, len(resp.Experiments))
+	for i, exp := range resp.Experiments {
+		exps[i] = experimentFromProto(exp)
+	}
+	return exps, nil
+}
+
+// StopExperiment ends an experiment before its duration elapses; its
+// results are kept
+func (c *Client) StopExperiment(ctx context.Context, name string) (*Experiment, error) {
+	stopped, err := c.analytics.StopExperiment(ctx, &nfa_analytics_v1alpha.StopExperimentRequest{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to stop experiment %s: %w", name, err)
+	}
+	out := experimentFromProto(stopped)
+	return &out, nil
+}
+
+// ExperimentResults summarizes the metrics of every variant of an experiment
+func (c *Client) ExperimentResults(ctx context.Context, name string) (*ExperimentResults, error) {
+	resp, err := c.analytics.GetExperimentResults(ctx, &nfa_analytics_v1alpha.GetExperimentResultsRequest{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get results of experiment %s: %w", name, err)
+	}
+	results := &ExperimentResults{
+		Experiment: experimentFromProto(resp.Experiment),
+		Variants:   make([]VariantSummary, len(resp.Variants)),
+	}
+	for i, v := range resp.Variants {
+		summary := VariantSummary{Variant: v.Variant, Assignments: v.Assignments}
+		for _, m := range v.Metrics {
+			summary.Metrics = append(summary.Metrics, MetricSummary{Name: m.Name, Count: m.Count, Mean: m.Mean, Min: m.Min, Max: m.Max})
+		}
+		results.Variants[i] = summary
+	}
+	return results, nil
+}
+
+// toProto converts the definition of the experiment; the fields set by the
+// broker are left out
+func (e Experiment) toProto() *nfa_analytics_v1alpha.Experiment {
+	pb := &nfa_analytics_v1alpha.Experiment{
+		Name:    e.Name,
+		Actions: e.Actions,
+		Metrics: e.Metrics,
+	}
+	if e.CohortKey == CohortSession {
+		pb.CohortKey = nfa_analytics_v1alpha.CohortKey_COHORT_KEY_SESSION
+	} else if e.CohortKey == CohortUser {
+		pb.CohortKey = nfa_analytics_v1alpha.CohortKey_COHORT_KEY_USER
+	}
+	if e.Duration > 0 {
+		pb.Duration = durationpb.New(e.Duration)
+	}
+	for _, v := range e.Variants {
+		pb.Variants = append(pb.Variants, &nfa_analytics_v1alpha.Variant{Name: v.Name, Selector: v.Selector, Weight: v.Weight})
+	}
+	return pb
+}
+
+func experimentFromProto(pb *nfa_analytics_v1alpha.Experiment) Experiment {
+	e := Experiment{
+		Name:      pb.GetName(),
+		Actions:   pb.GetActions(),
+		Metrics:   pb.GetMetrics(),
+		CohortKey: CohortUser,
+	}
+	if pb.GetCohortKey() == nfa_analytics_v1alpha.CohortKey_COHORT_KEY_SESSION {
+		e.CohortKey = CohortSession
+	}
+	if pb.GetDuration() != nil {
+		e.Duration = pb.GetDuration().AsDuration()
+	}
+	if pb.GetStartTime() != nil {
+		e.Start = pb.GetStartTime().AsTime()
+	}
+	if pb.GetEndTime() != nil {
+		e.End = pb.GetEndTime().AsTime()
+	}
+	switch pb.GetState() {
+	case nfa_analytics_v1alpha.ExperimentState_EXPERIMENT_STATE_RUNNING:
+		e.State = ExperimentRunning
+	case nfa_analytics_v1alpha.ExperimentState_EXPERIMENT_STATE_COMPLETED:
+		e.State = ExperimentCompleted
+	case nfa_analytics_v1alpha.ExperimentState_EXPERIMENT_STATE_STOPPED:
+		e.State = ExperimentStopped
+	}
+	for _, v := range pb.GetVariants() {
+		e.Variants = append(e.Variants, Variant{Name: v.Name, Selector: v.Selector, Weight: v.Weight})
+	}
+	return e
+}
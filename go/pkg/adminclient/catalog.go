@@ -0,0 +1,84 @@
+package adminclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_catalog_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/catalog/v1alpha"
+	"google.golang.org/protobuf/proto"
+)
+
+// Catalog is everything that defines how a namespace serves intents
+type Catalog struct {
+	Namespace string
+	// Contracts of the namespace's intents
+	Contracts []*contract.IntentContract
+	// Authorization rules, evaluated in order
+	Policies []PolicyRule
+	// Routing weight per provider, used by the weighted routing strategy
+	RoutingWeights map[string]float64
+	Exported       time.Time
+}
+
+// PolicyRule allows or denies consumers to call actions
+type PolicyRule struct {
+	// "allow" or "deny"
+	Effect    string
+	Actions   []string
+	Consumers []string
+}
+
+// Catalog returns the catalog of a namespace. It is read from the bundle
+// the broker exports without checking its signature, which guards bundles
+// moved between brokers rather than read from one; see nfactl catalog for
+// signed exports.
+func (c *Client) Catalog(ctx context.Context, namespace string) (*Catalog, error) {
+	bundle, err := c.catalog.ExportCatalog(ctx, &nfa_catalog_v1alpha.ExportCatalogRequest{Namespace: namespace})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export catalog of %s: %w", namespace, err)
+	}
+	pb := &nfa_catalog_v1alpha.Catalog{}
+	if err := proto.Unmarshal(bundle.Catalog, pb); err != nil {
+		return nil, fmt.Errorf("failed to parse catalog of %s: %w", namespace, err)
+	}
+	catalog := &Catalog{
+		Namespace:      pb.Namespace,
+		Policies:       make([]PolicyRule, len(pb.Policies)),
+		RoutingWeights: pb.RoutingWeights,
+	}
+	if pb.ExportTime != nil {
+		catalog.Exported = pb.ExportTime.AsTime()
+	}
+	for _, cpb := range pb.Contracts {
+		ic, err := contract.FromProto(cpb)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse catalog of %s: %w", namespace, err)
+		}
+		catalog.Contracts = append(catalog.Contracts, ic)
+	}
+	for i, rule := range pb.Policies {
+		catalog.Policies[i] = PolicyRule{Effect: rule.Effect, Actions: rule.Actions, Consumers: rule.Consumers}
+	}
+	return catalog, nil
+}
+
+// Intents returns the contracts of the intents of a namespace
+func (c *Client) Intents(ctx context.Context, namespace string) ([]*contract.IntentContract, error) {
+	catalog, err := c.Catalog(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return catalog.Contracts, nil
+}
+
+// Policies returns the authorization rules of a namespace, in the order
+// they are evaluated
+func (c *Client) Policies(ctx context.Context, namespace string) ([]PolicyRule, error) {
+	catalog, err := c.Catalog(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return catalog.Policies, nil
+}
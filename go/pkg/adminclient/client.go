@@ -0,0 +1,221 @@
+// Package adminclient is a typed Go client of the broker's administration
+// and query APIs: services and their SLAs, the intents and policies of a
+// namespace, experiments, configuration and devices. Platform teams build
+// portals and automation on it instead of calling the generated gRPC
+// clients; results are plain Go values with times and durations rather than
+// protobuf messages.
+package adminclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+	nfa_analytics_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/analytics/v1alpha"
+	nfa_catalog_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/catalog/v1alpha"
+	"google.golang.org/grpc"
+)
+
+// Client calls the administration APIs of a broker. Calls need the
+// credentials of an operator, passed with the connection.
+type Client struct {
+	admin     nfa_admin_v1alpha.AdminServiceClient
+	analytics nfa_analytics_v1alpha.AnalyticsServiceClient
+	catalog   nfa_catalog_v1alpha.CatalogServiceClient
+	broker    *broker.Client
+}
+
+// NewClient creates an admin client on an existing broker connection
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{
+		admin:     nfa_admin_v1alpha.NewAdminServiceClient(cc),
+		analytics: nfa_analytics_v1alpha.NewAnalyticsServiceClient(cc),
+		catalog:   nfa_catalog_v1alpha.NewCatalogServiceClient(cc),
+		broker:    broker.NewClient(cc),
+	}
+}
+
+// ConfigReload is the outcome of a configuration reload
+type ConfigReload struct {
+	Success bool
+	Message string
+	// Sections of the configuration that changed
+	ChangedSections []string
+}
+
+// ConfigChange is a past configuration reload attempt
+type ConfigChange struct {
+	Time            time.Time
+	Source          string
+	ChangedSections []string
+	// Rejected reloads leave the configuration unchanged; Error tells why
+	Rejected bool
+	Error    string
+}
+
+// ConfigValue is a setting of the effective configuration
+type ConfigValue struct {
+	Key string
+	// TOML literal; secret values are always redacted
+	Value string
+	// default, profile:<name>, file:<path>, env:<VAR> or flag
+	Source string
+}
+
+// EffectiveConfig is the configuration the broker runs with
+type EffectiveConfig struct {
+	Profile string
+	Values  []ConfigValue
+}
+
+// ReloadConfig makes the broker reload its configuration from disk; invalid
+// files are rejected as a whole
+func (c *Client) ReloadConfig(ctx context.Context) (*ConfigReload, error) {
+	resp, err := c.admin.ReloadConfig(ctx, &nfa_admin_v1alpha.ReloadConfigRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload config: %w", err)
+	}
+	return &ConfigReload{Success: resp.Success, Message: resp.Message, ChangedSections: resp.ChangedSections}, nil
+}
+
+// ConfigChanges returns the past configuration reload attempts
+func (c *Client) ConfigChanges(ctx context.Context) ([]ConfigChange, error) {
+	resp, err := c.admin.ListConfigChanges(ctx, &nfa_admin_v1alpha.ListConfigChangesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list config changes: %w", err)
+	}
+	changes := make([]ConfigChange, len(resp.Changes))
+	for i, ch := range resp.Changes {
+		changes[i] = ConfigChange{
+			Time:            unixTime(ch.TimestampUnix),
+			Source:          ch.Source,
+			ChangedSections: ch.ChangedSections,
+			Rejected:        ch.Rejected,
+			Error:           ch.Error,
+		}
+	}
+	return changes, nil
+}
+
+// EffectiveConfig returns the redacted configuration of the broker and the
+// source of each value
+func (c *Client) EffectiveConfig(ctx context.Context) (*EffectiveConfig, error) {
+	resp, err := c.admin.GetEffectiveConfig(ctx, &nfa_admin_v1alpha.GetEffectiveConfigRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get effective config: %w", err)
+	}
+	config := &EffectiveConfig{Profile: resp.Profile, Values: make([]ConfigValue, len(resp.Values))}
+	for i, v := range resp.Values {
+		config.Values[i] = ConfigValue{Key: v.Key, Value: v.Value, Source: v.Source}
+	}
+	return config, nil
+}
+
+// SetLogLevel changes the log level of a component, or of all with "*", and
+// returns the levels of every component
+func (c *Client) SetLogLevel(ctx context.Context, component, level string) (map[string]string, error) {
+	resp, err := c.admin.SetLogLevel(ctx, &nfa_admin_v1alpha.SetLogLevelRequest{Component: component, Level: level})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set log level of %s: %w", component, err)
+	}
+	return resp.Levels, nil
+}
+
+// LogLevels returns the log level of every component
+func (c *Client) LogLevels(ctx context.Context) (map[string]string, error) {
+	resp, err := c.admin.GetLogLevels(ctx, &nfa_admin_v1alpha.GetLogLevelsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log levels: %w", err)
+	}
+	return resp.Levels, nil
+}
+
+// StoreUsage is the data held by a retained store of the broker
+type StoreUsage struct {
+	// Name of the store, e.g. "events", "analytics" or "audit"
+	Name    string
+	Entries uint64
+	// Approximate size of the entries
+	Bytes int64
+	// Time of the oldest entry; zero when the store is empty
+	Oldest time.Time
+	// Retention policy; zero means unbounded
+	MaxAge   time.Duration
+	MaxBytes int64
+	// Entries removed by compaction since the broker started
+	CompactedEntries uint64
+	LastCompaction   time.Time
+}
+
+// StorageUsage reports the data held by each retained store and its
+// retention policy. With compact every store is compacted first.
+func (c *Client) StorageUsage(ctx context.Context, compact bool) ([]StoreUsage, error) {
+	resp, err := c.admin.GetStorageUsage(ctx, &nfa_admin_v1alpha.GetStorageUsageRequest{Compact: compact})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage usage: %w", err)
+	}
+	stores := make([]StoreUsage, len(resp.Stores))
+	for i, s := range resp.Stores {
+		stores[i] = StoreUsage{
+			Name:             s.Name,
+			Entries:          s.Entries,
+			Bytes:            s.Bytes,
+			Oldest:           unixTime(s.OldestUnix),
+			MaxAge:           time.Duration(s.MaxAgeSecs) * time.Second,
+			MaxBytes:         s.MaxBytes,
+			CompactedEntries: s.CompactedEntries,
+			LastCompaction:   unixTime(s.LastCompactionUnix),
+		}
+	}
+	return stores, nil
+}
+
+// BootstrapToken enrolls a device with the broker's built-in CA once
+type BootstrapToken struct {
+	Token string
+	// Hash of the CA certificate runtimes pin when enrolling
+	CAHash  string
+	Expires time.Time
+}
+
+// CreateBootstrapToken creates a bootstrap token for device of tenant; an
+// empty device lets the runtime name itself and a zero ttl means one hour
+func (c *Client) CreateBootstrapToken(ctx context.Context, tenant, device string, ttl time.Duration) (*BootstrapToken, error) {
+	resp, err := c.admin.CreateBootstrapToken(ctx, &nfa_admin_v1alpha.CreateBootstrapTokenRequest{
+		Tenant:  tenant,
+		Device:  device,
+		TtlSecs: uint32(ttl / time.Second),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bootstrap token: %w", err)
+	}
+	return &BootstrapToken{Token: resp.Token, CAHash: resp.CaHash, Expires: unixTime(resp.ExpiresUnix)}, nil
+}
+
+// RevokeDevice stops renewing the certificates of a device enrolled with the
+// built-in CA and refuses its calls
+func (c *Client) RevokeDevice(ctx context.Context, tenant, device string) error {
+	if _, err := c.admin.RevokeDevice(ctx, &nfa_admin_v1alpha.RevokeDeviceRequest{Tenant: tenant, Device: device}); err != nil {
+		return fmt.Errorf("failed to revoke device %s: %w", device, err)
+	}
+	return nil
+}
+
+// unixTime converts seconds since the epoch; 0 converts to the zero time
+func unixTime(secs int64) time.Time {
+	if secs == 0 {
+		return time.Time{}
+	}
+	return time.Unix(secs, 0)
+}
+
+// unixSecs converts a time to seconds since the epoch; the zero time
+// converts to 0, which the broker reads as its default
+func unixSecs(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
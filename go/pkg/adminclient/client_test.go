@@ -0,0 +1,247 @@
+package adminclient
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+	nfa_analytics_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/analytics/v1alpha"
+	nfa_catalog_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/catalog/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const translatorContract = `
+version: v1alpha
+kind: IntentContract
+metadata:
+  name: translator
+spec:
+  intentPatterns:
+    - pattern:
+        action: translate_text
+  implementation:
+    endpoint:
+      type: grpc
+      port: 50052
+`
+
+// fakeBroker answers the admin APIs with canned data
+type fakeBroker struct {
+	nfa_admin_v1alpha.UnimplementedAdminServiceServer
+	nfa_analytics_v1alpha.UnimplementedAnalyticsServiceServer
+	nfa_catalog_v1alpha.UnimplementedCatalogServiceServer
+
+	catalog   *nfa_catalog_v1alpha.Catalog
+	retagged  *nfa_admin_v1alpha.RetagServicesRequest
+	created   *nfa_analytics_v1alpha.Experiment
+	reportReq *nfa_admin_v1alpha.GetSLAReportRequest
+}
+
+func (f *fakeBroker) GetSLAReport(_ context.Context, req *nfa_admin_v1alpha.GetSLAReportRequest) (*nfa_admin_v1alpha.SLAReport, error) {
+	f.reportReq = req
+	return &nfa_admin_v1alpha.SLAReport{
+		StartUnix: 1000,
+		EndUnix:   2000,
+		Providers: []*nfa_admin_v1alpha.ProviderSLA{{
+			ServiceId:          "translator-1",
+			Requests:           10,
+			Availability:       0.9,
+			LatencyP99Ms:       12.5,
+			LatencyObjectiveMs: 10,
+			Violations:         []*nfa_admin_v1alpha.SLAViolation{{StartUnix: 1200, EndUnix: 1500, Kind: "latency"}},
+		}},
+	}, nil
+}
+
+func (f *fakeBroker) RetagServices(req *nfa_admin_v1alpha.RetagServicesRequest, stream nfa_admin_v1alpha.AdminService_RetagServicesServer) error {
+	f.retagged = req
+	for i, id := range []string{"a", "b"} {
+		outcome := nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_PLANNED
+		if i == 1 {
+			outcome = nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_SKIPPED
+		}
+		if err := stream.Send(&nfa_admin_v1alpha.BulkProgress{ServiceId: id, Outcome: outcome, Completed: uint32(i + 1), Total: 2}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeBroker) ExportCatalog(context.Context, *nfa_catalog_v1alpha.ExportCatalogRequest) (*nfa_catalog_v1alpha.CatalogBundle, error) {
+	data, err := proto.Marshal(f.catalog)
+	if err != nil {
+		return nil, err
+	}
+	return &nfa_catalog_v1alpha.CatalogBundle{Catalog: data, KeyId: "test"}, nil
+}
+
+func (f *fakeBroker) CreateExperiment(_ context.Context, req *nfa_analytics_v1alpha.CreateExperimentRequest) (*nfa_analytics_v1alpha.Experiment, error) {
+	f.created = req.Experiment
+	exp := proto.Clone(req.Experiment).(*nfa_analytics_v1alpha.Experiment)
+	exp.StartTime = timestamppb.New(time.Unix(3000, 0))
+	exp.State = nfa_analytics_v1alpha.ExperimentState_EXPERIMENT_STATE_RUNNING
+	return exp, nil
+}
+
+func dialFake(t *testing.T, f *fakeBroker) *Client {
+	t.Helper()
+	listener := bufconn.Listen(1 << 16)
+	server := grpc.NewServer()
+	nfa_admin_v1alpha.RegisterAdminServiceServer(server, f)
+	nfa_analytics_v1alpha.RegisterAnalyticsServiceServer(server, f)
+	nfa_catalog_v1alpha.RegisterCatalogServiceServer(server, f)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewClient(conn)
+}
+
+func TestSLAReport(t *testing.T) {
+	f := &fakeBroker{}
+	client := dialFake(t, f)
+	report, err := client.SLAReport(context.Background(), SLAQuery{Namespace: "prod", Start: time.Unix(1000, 0), Bucket: time.Minute})
+	if err != nil {
+		t.Fatalf("SLAReport() error = %v", err)
+	}
+	if f.reportReq.Namespace != "prod" || f.reportReq.StartUnix != 1000 || f.reportReq.EndUnix != 0 || f.reportReq.BucketSecs != 60 {
+		t.Errorf("request = %v, want namespace prod from 1000 in 60s buckets", f.reportReq)
+	}
+	want := ProviderSLA{
+		ServiceID:        "translator-1",
+		Requests:         10,
+		Availability:     0.9,
+		LatencyP99:       12500 * time.Microsecond,
+		LatencyObjective: 10 * time.Millisecond,
+		Violations:       []SLAViolation{{Start: time.Unix(1200, 0), End: time.Unix(1500, 0), Kind: "latency"}},
+	}
+	if len(report.Providers) != 1 || !reflect.DeepEqual(report.Providers[0], want) {
+		t.Errorf("providers = %+v, want %+v", report.Providers, want)
+	}
+	if !report.Start.Equal(time.Unix(1000, 0)) || !report.Generated.IsZero() {
+		t.Errorf("period = %v, generated %v, want from 1000 and an unset generation time", report.Start, report.Generated)
+	}
+}
+
+func TestRetagServices(t *testing.T) {
+	f := &fakeBroker{}
+	client := dialFake(t, f)
+	var seen []string
+	progress, err := client.RetagServices(context.Background(), RetagRequest{
+		Selector: map[string]string{"tier": "edge"},
+		Set:      map[string]string{"zone": "eu"},
+		DryRun:   true,
+	}, func(p BulkProgress) { seen = append(seen, p.ServiceID) })
+	if err != nil {
+		t.Fatalf("RetagServices() error = %v", err)
+	}
+	if !f.retagged.DryRun || f.retagged.SetLabels["zone"] != "eu" {
+		t.Errorf("request = %v, want a dry run setting zone", f.retagged)
+	}
+	want := []BulkProgress{
+		{ServiceID: "a", Outcome: BulkPlanned, Completed: 1, Total: 2},
+		{ServiceID: "b", Outcome: BulkSkipped, Completed: 2, Total: 2},
+	}
+	if !reflect.DeepEqual(progress, want) {
+		t.Errorf("progress = %+v, want %+v", progress, want)
+	}
+	if !reflect.DeepEqual(seen, []string{"a", "b"}) {
+		t.Errorf("reported progress of %v, want a and b", seen)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	c, err := contract.ParseIntentContract([]byte(translatorContract))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	f := &fakeBroker{catalog: &nfa_catalog_v1alpha.Catalog{
+		Namespace: "prod",
+		Contracts: []*nfa_intent_v1alpha.IntentContract{c.ToProto()},
+		Policies:  []*nfa_catalog_v1alpha.PolicyRule{{Effect: "deny", Actions: []string{"translate_*"}, Consumers: []string{"kiosk"}}},
+	}}
+	client := dialFake(t, f)
+	ctx := context.Background()
+	intents, err := client.Intents(ctx, "prod")
+	if err != nil {
+		t.Fatalf("Intents() error = %v", err)
+	}
+	if len(intents) != 1 || intents[0].Metadata.Name != "translator" {
+		t.Errorf("intents = %v, want the translator contract", intents)
+	}
+	policies, err := client.Policies(ctx, "prod")
+	if err != nil {
+		t.Fatalf("Policies() error = %v", err)
+	}
+	want := []PolicyRule{{Effect: "deny", Actions: []string{"translate_*"}, Consumers: []string{"kiosk"}}}
+	if !reflect.DeepEqual(policies, want) {
+		t.Errorf("policies = %+v, want %+v", policies, want)
+	}
+}
+
+func TestCreateExperiment(t *testing.T) {
+	f := &fakeBroker{}
+	client := dialFake(t, f)
+	exp := Experiment{
+		Name:      "faster-model",
+		Actions:   []string{"translate_*"},
+		Variants:  []Variant{{Name: "a", Weight: 1}, {Name: "b", Selector: map[string]string{"model": "v2"}, Weight: 1}},
+		CohortKey: CohortSession,
+		Duration:  time.Hour,
+	}
+	created, err := client.CreateExperiment(context.Background(), exp)
+	if err != nil {
+		t.Fatalf("CreateExperiment() error = %v", err)
+	}
+	if f.created.CohortKey != nfa_analytics_v1alpha.CohortKey_COHORT_KEY_SESSION || !proto.Equal(f.created.Duration, durationpb.New(time.Hour)) {
+		t.Errorf("sent %v, want session cohorts for an hour", f.created)
+	}
+	want := exp
+	want.Start = time.Unix(3000, 0).UTC()
+	want.State = ExperimentRunning
+	if !reflect.DeepEqual(*created, want) {
+		t.Errorf("created = %+v, want %+v", *created, want)
+	}
+}
+
+func TestProviders(t *testing.T) {
+	b := broker.NewEmbedded()
+	defer b.Close()
+	conn, err := grpc.Dial(broker.EmbeddedTarget, b.DialOptions()...)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	c, err := contract.ParseIntentContract([]byte(translatorContract))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	ctx := context.Background()
+	serviceID, err := broker.NewClient(conn).Register(ctx, c)
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	providers, current, err := NewClient(conn).Providers(ctx, "translate_text", "")
+	if err != nil {
+		t.Fatalf("Providers() error = %v", err)
+	}
+	if !reflect.DeepEqual(providers, []string{serviceID}) || current != "" {
+		t.Errorf("Providers() = %v, %q, want [%s] under its current name", providers, current, serviceID)
+	}
+}
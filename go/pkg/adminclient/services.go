@@ -0,0 +1,357 @@
+package adminclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+)
+
+// SLAQuery selects the providers and period of an SLA report
+type SLAQuery struct {
+	// Restrict the report to a namespace or a provider; empty covers all
+	Namespace string
+	ServiceID string
+	// Reporting period; End defaults to now and Start to 24 hours before End
+	Start, End time.Time
+	// Resolution of the violation timeline, defaults to 5 minutes
+	Bucket time.Duration
+}
+
+// SLAReport tells how providers did against the QoS their contracts declare
+type SLAReport struct {
+	Start, End time.Time
+	Generated  time.Time
+	Providers  []ProviderSLA
+}
+
+// ProviderSLA is the availability and latency of a provider over the period
+// of a report
+type ProviderSLA struct {
+	Namespace    string
+	ServiceID    string
+	Requests     uint64
+	Failures     uint64
+	Availability float64
+	LatencyP50   time.Duration
+	LatencyP90   time.Duration
+	LatencyP99   time.Duration
+	// Objectives declared in the provider's contract QoS; zero when
+	// undeclared. The latency objective applies to the 99th percentile.
+	LatencyObjective      time.Duration
+	AvailabilityObjective float64
+	// Whether the provider met its objectives over the whole period
+	Met        bool
+	Violations []SLAViolation
+}
+
+// SLAViolation is a span of time in which a provider missed an objective
+type SLAViolation struct {
+	Start, End time.Time
+	// "availability" or "latency"
+	Kind   string
+	Detail string
+}
+
+// SLAReport reports the availability and latency of the providers q selects
+func (c *Client) SLAReport(ctx context.Context, q SLAQuery) (*SLAReport, error) {
+	resp, err := c.admin.GetSLAReport(ctx, &nfa_admin_v1alpha.GetSLAReportRequest{
+		Namespace:  q.Namespace,
+		ServiceId:  q.ServiceID,
+		StartUnix:  unixSecs(q.Start),
+		EndUnix:    unixSecs(q.End),
+		BucketSecs: uint32(q.Bucket / time.Second),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SLA report: %w", err)
+	}
+	report := &SLAReport{
+		Start:     unixTime(resp.StartUnix),
+		End:       unixTime(resp.EndUnix),
+		Generated: unixTime(resp.GeneratedUnix),
+		Providers: make([]ProviderSLA, len(resp.Providers)),
+	}
+	for i, p := range resp.Providers {
+		sla := ProviderSLA{
+			Namespace:             p.Namespace,
+			ServiceID:             p.ServiceId,
+			Requests:              p.Requests,
+			Failures:              p.Failures,
+			Availability:          p.Availability,
+			LatencyP50:            millis(p.LatencyP50Ms),
+			LatencyP90:            millis(p.LatencyP90Ms),
+			LatencyP99:            millis(p.LatencyP99Ms),
+			LatencyObjective:      millis(p.LatencyObjectiveMs),
+			AvailabilityObjective: p.AvailabilityObjective,
+			Met:                   p.Met,
+		}
+		for _, v := range p.Violations {
+			sla.Violations = append(sla.Violations, SLAViolation{
+				Start:  unixTime(v.StartUnix),
+				End:    unixTime(v.EndUnix),
+				Kind:   v.Kind,
+				Detail: v.Detail,
+			})
+		}
+		report.Providers[i] = sla
+	}
+	return report, nil
+}
+
+// DeprecatedAction is the use of a deprecated action alias over the period
+// of a deprecation report
+type DeprecatedAction struct {
+	// The deprecated name consumers called
+	Alias string
+	// The action it resolves to, which consumers should switch to
+	Action         string
+	Calls          uint64
+	CallsPerMinute float64
+	LastSeen       time.Time
+	// Consumers that called the alias in the period, most calls first
+	Consumers []ConsumerUsage
+}
+
+// ConsumerUsage is the use of a deprecated alias by one consumer
+type ConsumerUsage struct {
+	// Name the consumer sent in the nfa-consumer metadata, or its device ID
+	// or network address
+	Consumer       string
+	Calls          uint64
+	CallsPerMinute float64
+	// First and last call of the alias by the consumer, also outside the
+	// period
+	FirstSeen, LastSeen time.Time
+}
+
+// DeprecationReport reports who still called deprecated actions between
+// start and end, to tell when an alias can be removed. An empty alias
+// covers every deprecated action; end defaults to now and start to 7 days
+// before end.
+func (c *Client) DeprecationReport(ctx context.Context, alias string, start, end time.Time) ([]DeprecatedAction, error) {
+	resp, err := c.admin.GetDeprecationReport(ctx, &nfa_admin_v1alpha.GetDeprecationReportRequest{
+		Alias:     alias,
+		StartUnix: unixSecs(start),
+		EndUnix:   unixSecs(end),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deprecation report: %w", err)
+	}
+	actions := make([]DeprecatedAction, len(resp.Actions))
+	for i, a := range resp.Actions {
+		action := DeprecatedAction{
+			Alias:          a.Alias,
+			Action:         a.Action,
+			Calls:          a.Calls,
+			CallsPerMinute: a.CallsPerMinute,
+			LastSeen:       unixTime(a.LastSeenUnix),
+		}
+		for _, u := range a.Consumers {
+			action.Consumers = append(action.Consumers, ConsumerUsage{
+				Consumer:       u.Consumer,
+				Calls:          u.Calls,
+				CallsPerMinute: u.CallsPerMinute,
+				FirstSeen:      unixTime(u.FirstSeenUnix),
+				LastSeen:       unixTime(u.LastSeenUnix),
+			})
+		}
+		actions[i] = action
+	}
+	return actions, nil
+}
+
+// ServiceError is the errors of one kind a service returned, as its runtime
+// reported them
+type ServiceError struct {
+	ServiceID string
+	// Intent action, or the full method of requests to other services
+	Action string
+	// Name of the gRPC status code, e.g. Internal
+	Code  string
+	Count uint64
+	// Message of the most recently reported error of this kind
+	Sample              string
+	FirstSeen, LastSeen time.Time
+}
+
+// ErrorReport returns the errors providers' handlers returned, most first,
+// and how many errors runtimes counted without summarizing them. An empty
+// serviceID covers every service and a zero since every error the broker
+// retained.
+func (c *Client) ErrorReport(ctx context.Context, serviceID string, since time.Time) ([]ServiceError, uint64, error) {
+	resp, err := c.admin.GetErrorReport(ctx, &nfa_admin_v1alpha.GetErrorReportRequest{
+		ServiceId: serviceID,
+		SinceUnix: unixSecs(since),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get error report: %w", err)
+	}
+	errs := make([]ServiceError, len(resp.Errors))
+	for i, e := range resp.Errors {
+		errs[i] = ServiceError{
+			ServiceID: e.ServiceId,
+			Action:    e.Action,
+			Code:      e.Code,
+			Count:     e.Count,
+			Sample:    e.SampleMessage,
+			FirstSeen: unixTime(e.FirstSeenUnix),
+			LastSeen:  unixTime(e.LastSeenUnix),
+		}
+	}
+	return errs, resp.Dropped, nil
+}
+
+// Providers returns the IDs of the live services serving action in mode,
+// unary when empty, best first, and the current name of the action when
+// action is a deprecated alias of it; see broker.Client.MatchAction
+func (c *Client) Providers(ctx context.Context, action string, mode contract.StreamingMode) ([]string, string, error) {
+	return c.broker.MatchAction(ctx, action, mode)
+}
+
+// BulkOutcome is the outcome of a bulk operation for one service
+type BulkOutcome string
+
+const (
+	// BulkPlanned is reported by dry runs for services that would change
+	BulkPlanned BulkOutcome = "planned"
+	BulkDone    BulkOutcome = "done"
+	BulkFailed  BulkOutcome = "failed"
+	// BulkSkipped services needed no change
+	BulkSkipped BulkOutcome = "skipped"
+)
+
+// BulkProgress is the outcome of a bulk operation for one service, reported
+// as the broker processes it
+type BulkProgress struct {
+	ServiceID string
+	Outcome   BulkOutcome
+	// What was or would be changed, or why it failed
+	Detail string
+	// Services processed so far, this one included, out of Total
+	Completed, Total int
+}
+
+// DrainRequest drains every provider of a namespace
+type DrainRequest struct {
+	// Services whose nfa.namespace label has this value are drained
+	Namespace   string
+	GracePeriod time.Duration
+	Reason      string
+	// Report the providers that would be drained without draining them
+	DryRun bool
+}
+
+// PurgeRequest removes the registrations of providers that stopped sending
+// heartbeats
+type PurgeRequest struct {
+	// Registrations without a heartbeat for this long are stale; zero means
+	// those the broker no longer considers live
+	StaleAfter time.Duration
+	// Report the registrations that would be removed without removing them
+	DryRun bool
+}
+
+// RetagRequest changes the labels of every service matching a selector
+type RetagRequest struct {
+	// Services whose labels contain every entry are retagged; required
+	Selector map[string]string
+	Set      map[string]string
+	Remove   []string
+	// Report the new labels without changing them
+	DryRun bool
+}
+
+// DrainNamespace drains the providers of a namespace, calling progress, if
+// not nil, with the outcome for each as the broker processes it, and
+// returns all outcomes
+func (c *Client) DrainNamespace(ctx context.Context, req DrainRequest, progress func(BulkProgress)) ([]BulkProgress, error) {
+	stream, err := c.admin.DrainNamespace(ctx, &nfa_admin_v1alpha.DrainNamespaceRequest{
+		Namespace:       req.Namespace,
+		GracePeriodSecs: uint32(req.GracePeriod / time.Second),
+		Reason:          req.Reason,
+		DryRun:          req.DryRun,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to drain namespace %s: %w", req.Namespace, err)
+	}
+	return collectProgress(stream, "failed to drain namespace "+req.Namespace, progress)
+}
+
+// PurgeStaleRegistrations removes stale registrations, reporting like
+// DrainNamespace
+func (c *Client) PurgeStaleRegistrations(ctx context.Context, req PurgeRequest, progress func(BulkProgress)) ([]BulkProgress, error) {
+	stream, err := c.admin.PurgeStaleRegistrations(ctx, &nfa_admin_v1alpha.PurgeStaleRegistrationsRequest{
+		StaleAfterSecs: uint32(req.StaleAfter / time.Second),
+		DryRun:         req.DryRun,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge stale registrations: %w", err)
+	}
+	return collectProgress(stream, "failed to purge stale registrations", progress)
+}
+
+// RetagServices changes the labels of the services req selects, reporting
+// like DrainNamespace
+func (c *Client) RetagServices(ctx context.Context, req RetagRequest, progress func(BulkProgress)) ([]BulkProgress, error) {
+	stream, err := c.admin.RetagServices(ctx, &nfa_admin_v1alpha.RetagServicesRequest{
+		Selector:     req.Selector,
+		SetLabels:    req.Set,
+		RemoveLabels: req.Remove,
+		DryRun:       req.DryRun,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retag services: %w", err)
+	}
+	return collectProgress(stream, "failed to retag services", progress)
+}
+
+// collectProgress receives the outcomes of a bulk operation until the
+// broker ends the stream
+func collectProgress(stream interface {
+	Recv() (*nfa_admin_v1alpha.BulkProgress, error)
+}, op string, progress func(BulkProgress)) ([]BulkProgress, error) {
+	var all []BulkProgress
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return all, nil
+		}
+		if err != nil {
+			return all, fmt.Errorf("%s: %w", op, err)
+		}
+		p := BulkProgress{
+			ServiceID: msg.ServiceId,
+			Outcome:   bulkOutcome(msg.Outcome),
+			Detail:    msg.Detail,
+			Completed: int(msg.Completed),
+			Total:     int(msg.Total),
+		}
+		if progress != nil {
+			progress(p)
+		}
+		all = append(all, p)
+	}
+}
+
+func bulkOutcome(o nfa_admin_v1alpha.BulkOutcome) BulkOutcome {
+	switch o {
+	case nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_PLANNED:
+		return BulkPlanned
+	case nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_DONE:
+		return BulkDone
+	case nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_FAILED:
+		return BulkFailed
+	case nfa_admin_v1alpha.BulkOutcome_BULK_OUTCOME_SKIPPED:
+		return BulkSkipped
+	}
+	return ""
+}
+
+// millis converts a duration in fractional milliseconds
+func millis(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
@@ -0,0 +1,314 @@
+// Package broker is the Go client of the Intent Broker API, used by
+// runtimes to register intent contracts and by applications to find the
+// services that serve an intent. Embedded runs a broker in-process.
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/errorreport"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrUnavailable is wrapped by errors caused by the broker being unreachable;
+	// the call can be retried once the broker is back
+	ErrUnavailable = errors.New("broker unavailable")
+	// ErrServiceIDConflict is wrapped when a stable service ID is held by
+	// another live instance
+	ErrServiceIDConflict = errors.New("service id held by a live instance")
+	// ErrUnauthenticated is wrapped when the broker requires credentials that
+	// were not presented or rejects them
+	ErrUnauthenticated = errors.New("not authenticated by broker")
+	// ErrNotRegistered is wrapped when the broker does not know a service,
+	// e.g. because it restarted and lost its registrations
+	ErrNotRegistered = errors.New("service not registered with broker")
+)
+
+// Instance identifies a service instance across restarts
+type Instance struct {
+	// Key is a stable identifier of the instance, such as a device serial.
+	// When empty the broker assigns a fresh service ID on every registration.
+	Key string
+	// TakeOver replaces a live registration holding the same service ID
+	TakeOver bool
+}
+
+// StableServiceID returns the service ID the broker assigns to a contract
+// registered with an instance key: the name followed by the FNV-1a hash of
+// name and key
+func StableServiceID(name, instanceKey string) string {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write([]byte(instanceKey))
+	return fmt.Sprintf("%s-%016x", name, h.Sum64())
+}
+
+// Client calls the Intent Broker
+type Client struct {
+	client nfa_broker_v1alpha.IntentBrokerClient
+
+	mu       sync.Mutex
+	protocol Protocol // negotiated at the last registration
+}
+
+// NewClient creates a broker client on an existing broker connection
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{
+		client: nfa_broker_v1alpha.NewIntentBrokerClient(cc),
+	}
+}
+
+// Register registers an intent contract and returns the assigned service ID
+func (c *Client) Register(ctx context.Context, intentContract *contract.IntentContract) (string, error) {
+	return c.RegisterInstance(ctx, intentContract, Instance{})
+}
+
+// RegisterInstance registers an intent contract for an instance with a stable
+// identity and returns its service ID. The registration negotiates the
+// protocol version and features, see Protocol. The contract is converted to
+// the schema version the broker preferred at the last registration, or
+// v1alpha before the first.
+func (c *Client) RegisterInstance(ctx context.Context, intentContract *contract.IntentContract, instance Instance) (string, error) {
+	converted, err := intentContract.AsVersion(c.Protocol().ContractVersion())
+	if err != nil {
+		return "", fmt.Errorf("failed to register intent: %w", err)
+	}
+	resp, err := c.client.RegisterIntent(ctx, &nfa_broker_v1alpha.RegisterIntentRequest{
+		Contract:        converted.ToProto(),
+		InstanceKey:     instance.Key,
+		TakeOver:        instance.TakeOver,
+		ProtocolVersion: ProtocolVersion,
+		Features:        Features,
+	})
+	if err != nil {
+		if status.Code(err) == codes.FailedPrecondition {
+			return "", fmt.Errorf("failed to register intent: %w: %w", ErrIncompatible, err)
+		}
+		return "", callError("failed to register intent", err)
+	}
+	if !resp.Success {
+		return "", fmt.Errorf("broker rejected contract %s: %s", intentContract.Metadata.Name, resp.Message)
+	}
+	protocol, err := negotiated(resp.ProtocolVersion, resp.Features, resp.ContractVersions)
+	if err != nil {
+		return "", fmt.Errorf("failed to register intent: %w", err)
+	}
+	c.mu.Lock()
+	c.protocol = protocol
+	c.mu.Unlock()
+	return resp.ServiceId, nil
+}
+
+// Protocol returns the protocol negotiated at the last registration; its
+// version is 0 before the first
+func (c *Client) Protocol() Protocol {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.protocol
+}
+
+// Match returns the IDs of the services serving action in the given
+// streaming mode; an empty mode matches unary services
+func (c *Client) Match(ctx context.Context, action string, mode contract.StreamingMode) ([]string, error) {
+	serviceIDs, _, err := c.MatchAction(ctx, action, mode)
+	return serviceIDs, err
+}
+
+// MatchAction is Match that also returns the current name of the action when
+// action is a deprecated alias of it, and an empty string otherwise
+func (c *Client) MatchAction(ctx context.Context, action string, mode contract.StreamingMode) ([]string, string, error) {
+	resp, err := c.client.MatchIntent(ctx, &nfa_broker_v1alpha.IntentMatchRequest{
+		Pattern: &nfa_intent_v1alpha.IntentPattern{
+			Pattern: &nfa_intent_v1alpha.IntentPattern_Pattern{Action: action},
+		},
+		Streaming: mode.ToProto(),
+	})
+	if err != nil {
+		return nil, "", callError("failed to match intent "+action, err)
+	}
+	return resp.ServiceIds, resp.Action, nil
+}
+
+// Heartbeat reports that a registered service is alive
+func (c *Client) Heartbeat(ctx context.Context, serviceID string) error {
+	_, err := c.Renew(ctx, serviceID, 0)
+	return err
+}
+
+// Lease is the broker's answer to a heartbeat
+type Lease struct {
+	// TTL is how long the service stays live without another heartbeat,
+	// counted on the broker's clock from the heartbeat; 0 when the broker
+	// predates leases
+	TTL time.Duration
+	// BrokerTime is the broker's wall clock when it answered, zero when the
+	// broker predates leases. It may be skewed from the local clock and is
+	// only meant for estimating the skew.
+	BrokerTime time.Time
+}
+
+// Capacity is the load a provider can take, advertised with its heartbeats
+type Capacity struct {
+	// ConcurrencyLimit is how many requests the provider currently runs at
+	// once; 0 when it sets no limit
+	ConcurrencyLimit int
+	// InFlight counts the requests running or queued
+	InFlight int
+	// QueueDepth counts the requests of InFlight waiting for a slot
+	QueueDepth int
+	// P95Latency is the 95th percentile latency of recent unary requests
+	P95Latency time.Duration
+	// CPUPressure and MemoryPressure are the load on the provider's host,
+	// from 0 (idle) to 1 (saturated)
+	CPUPressure    float64
+	MemoryPressure float64
+}
+
+// Utilization is how busy the provider is, from 0 (idle) to 1 (saturated):
+// the share of its concurrency limit in use or the pressure on its host,
+// whichever is higher. Queued requests count against the limit, so an
+// overloaded provider is above 1.
+func (c Capacity) Utilization() float64 {
+	u := max(c.CPUPressure, c.MemoryPressure)
+	if c.ConcurrencyLimit > 0 {
+		u = max(u, float64(c.InFlight)/float64(c.ConcurrencyLimit))
+	}
+	return u
+}
+
+// ToProto converts the capacity to its protobuf form
+func (c Capacity) ToProto() *nfa_broker_v1alpha.Capacity {
+	return &nfa_broker_v1alpha.Capacity{
+		ConcurrencyLimit: uint32(c.ConcurrencyLimit),
+		InFlight:         uint32(c.InFlight),
+		CpuPressure:      c.CPUPressure,
+		MemoryPressure:   c.MemoryPressure,
+		QueueDepth:       uint32(c.QueueDepth),
+		P95LatencyMs:     uint32(c.P95Latency.Milliseconds()),
+	}
+}
+
+// CapacityFromProto converts a capacity in protobuf form
+func CapacityFromProto(pb *nfa_broker_v1alpha.Capacity) Capacity {
+	return Capacity{
+		ConcurrencyLimit: int(pb.GetConcurrencyLimit()),
+		InFlight:         int(pb.GetInFlight()),
+		QueueDepth:       int(pb.GetQueueDepth()),
+		P95Latency:       time.Duration(pb.GetP95LatencyMs()) * time.Millisecond,
+		CPUPressure:      pb.GetCpuPressure(),
+		MemoryPressure:   pb.GetMemoryPressure(),
+	}
+}
+
+// Renew sends a heartbeat for a registered service, reporting the round trip
+// of the previous one so the broker can allow for it, and returns the lease
+// the broker granted
+func (c *Client) Renew(ctx context.Context, serviceID string, roundTrip time.Duration) (Lease, error) {
+	return c.RenewWithCapacity(ctx, serviceID, roundTrip, nil)
+}
+
+// RenewWithCapacity is Renew that also advertises the capacity of the
+// service, when not nil
+func (c *Client) RenewWithCapacity(ctx context.Context, serviceID string, roundTrip time.Duration, capacity *Capacity) (Lease, error) {
+	req := &nfa_broker_v1alpha.HeartbeatRequest{
+		ServiceId:   serviceID,
+		RoundTripMs: uint32(roundTrip.Milliseconds()),
+	}
+	if capacity != nil {
+		req.Capacity = capacity.ToProto()
+	}
+	resp, err := c.client.Heartbeat(ctx, req)
+	if err != nil {
+		return Lease{}, callError("failed to send heartbeat", err)
+	}
+	lease := Lease{TTL: time.Duration(resp.LeaseMs) * time.Millisecond}
+	if resp.BrokerTimeUnixMs != 0 {
+		lease.BrokerTime = time.UnixMilli(resp.BrokerTimeUnixMs)
+	}
+	return lease, nil
+}
+
+// Unregister removes a registered service
+func (c *Client) Unregister(ctx context.Context, serviceID string) error {
+	resp, err := c.client.UnregisterIntent(ctx, &nfa_broker_v1alpha.UnregisterIntentRequest{
+		ServiceId: serviceID,
+	})
+	if err != nil {
+		return callError("failed to unregister service "+serviceID, err)
+	}
+	if !resp.Success {
+		return fmt.Errorf("failed to unregister service %s: %s", serviceID, resp.Message)
+	}
+	return nil
+}
+
+// ReportOutcome reports whether a call to the service serviceID for action
+// succeeded, so the broker ranks services that keep failing last. Report
+// only failures of the provider, not calls it rightly refused.
+func (c *Client) ReportOutcome(ctx context.Context, serviceID, action string, success bool) error {
+	_, err := c.client.ReportOutcome(ctx, &nfa_broker_v1alpha.ReportOutcomeRequest{
+		ServiceId: serviceID,
+		Action:    action,
+		Success:   success,
+	})
+	if err != nil {
+		return callError("failed to report outcome for service "+serviceID, err)
+	}
+	return nil
+}
+
+// ReportErrors reports summaries of the errors the handlers of the service
+// serviceID returned since the last report, and how many errors of further
+// kinds were dropped, for the broker's error report
+func (c *Client) ReportErrors(ctx context.Context, serviceID string, summaries []errorreport.Summary, dropped uint64) error {
+	req := &nfa_broker_v1alpha.ReportErrorsRequest{
+		ServiceId: serviceID,
+		Errors:    make([]*nfa_broker_v1alpha.ErrorSummary, len(summaries)),
+		Dropped:   dropped,
+	}
+	for i, s := range summaries {
+		req.Errors[i] = &nfa_broker_v1alpha.ErrorSummary{
+			Action:          s.Action,
+			Code:            s.Code,
+			Count:           s.Count,
+			SampleMessage:   s.Sample,
+			FirstSeenUnixMs: s.FirstSeen.UnixMilli(),
+			LastSeenUnixMs:  s.LastSeen.UnixMilli(),
+		}
+	}
+	if _, err := c.client.ReportErrors(ctx, req); err != nil {
+		return callError("failed to report errors for service "+serviceID, err)
+	}
+	return nil
+}
+
+// callError wraps a failed broker call, marking transport failures with
+// ErrUnavailable, RPCs the broker lacks with ErrUnsupported and missing or
+// rejected credentials with ErrUnauthenticated
+func callError(op string, err error) error {
+	switch status.Code(err) {
+	case codes.Unavailable:
+		return fmt.Errorf("%s: %w: %w", op, ErrUnavailable, err)
+	case codes.AlreadyExists:
+		return fmt.Errorf("%s: %w: %w", op, ErrServiceIDConflict, err)
+	case codes.Unimplemented:
+		return fmt.Errorf("%s: %w: %w", op, ErrUnsupported, err)
+	case codes.Unauthenticated:
+		return fmt.Errorf("%s: %w: %w", op, ErrUnauthenticated, err)
+	case codes.NotFound:
+		return fmt.Errorf("%s: %w: %w", op, ErrNotRegistered, err)
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,38 @@
+package broker
+
+import (
+	"context"
+
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ConsumerKey is the metadata key naming the consumer of a match request,
+// e.g. in deprecation reports
+const ConsumerKey = "nfa-consumer"
+
+// WithConsumer names the consumer of the match requests made with ctx
+func WithConsumer(ctx context.Context, name string) context.Context {
+	if name == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, ConsumerKey, name)
+}
+
+// consumerOf names the consumer of a match request: the name it sent, else
+// the device ID of its context, else its network address
+func consumerOf(ctx context.Context, req *nfa_broker_v1alpha.IntentMatchRequest) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if names := md.Get(ConsumerKey); len(names) > 0 && names[0] != "" {
+			return names[0]
+		}
+	}
+	if device := req.GetContext().GetDeviceId(); device != "" {
+		return "device:" + device
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return "unknown"
+}
@@ -0,0 +1,914 @@
+package broker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/control"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/deprecation"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/errorreport"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/interactivity"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/matching"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/protoconv"
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/timer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// EmbeddedTarget is the dial target of an embedded broker; connections reach
+// it through the dialer of Embedded.DialOptions
+const EmbeddedTarget = "passthrough:///nfa-embedded-broker"
+
+// livenessTimeout is how long a registration stays live without heartbeats,
+// as in the standalone broker, unless WithLivenessTimeout sets another
+const livenessTimeout = 30 * time.Second
+
+// maxRoundTripAllowance caps the extension of a lease for the round trip a
+// runtime reports, so a bogus report cannot keep a dead service live
+const maxRoundTripAllowance = 5 * time.Second
+
+// embeddedFeatures are the optional features an embedded broker serves
+var embeddedFeatures = []string{FeatureControl, FeatureStreaming, FeatureTakeOver, FeatureOutcomes, FeatureTimers, FeatureErrorReports}
+
+// embeddedBufferSize is the size of the in-memory connection buffers
+const embeddedBufferSize = 1 << 20
+
+// Embedded is an in-process Intent Broker for single-binary deployments and
+// tests. It serves the v1alpha and v1 IntentBroker APIs, the control and
+// broadcast services and the timer service, keeping timers in memory unless
+// WithTimerDir gives them a directory, over an in-memory connection, so runtimes and clients in the same process use
+// it exactly like a standalone broker:
+//
+//	b := broker.NewEmbedded()
+//	defer b.Close()
+//	rt := runtime.NewIntentRuntime(broker.EmbeddedTarget, runtime.WithDialOptions(b.DialOptions()...))
+type Embedded struct {
+	nfa_broker_v1alpha.UnimplementedIntentBrokerServer
+
+	listener *bufconn.Listener
+	server   *grpc.Server
+	self     *grpc.ClientConn // used by the v1 shim
+	hub      *control.Hub
+	timers   *timer.Scheduler
+	timerDir string
+	stop     context.CancelFunc // stops the timers and the reaper
+
+	// liveness is how long registrations stay live without heartbeats
+	liveness time.Duration
+	// reapAfter is how long expired registrations are kept; 0 keeps them
+	reapAfter time.Duration
+	// store persists the registrations, if set with WithStore
+	store Store
+	// serverOptions and registrars are those of WithServerOptions and
+	// WithService
+	serverOptions []grpc.ServerOption
+	registrars    []func(grpc.ServiceRegistrar)
+	// metrics records the RPCs, if set with WithMetrics
+	metrics Metrics
+	// lifecycle is told of the registry changes, if set with WithLifecycle
+	lifecycle Lifecycle
+	// observeOutcome is passed the reported outcomes, if set with WithOutcomes
+	observeOutcome func(Outcome)
+	// writes orders the store writes of each registration
+	writes writeLocks
+
+	mu           sync.Mutex
+	services     map[string]*registration
+	aliases      map[AliasUsage]uint64
+	outcomes     map[outcomeKey]outcomeStats
+	deprecations *deprecation.Tracker
+	errorReports *errorreport.Tracker
+	now          func() time.Time
+}
+
+// AliasUsage is a deprecated action alias and the action it forwards to
+type AliasUsage struct {
+	Alias, Action string
+}
+
+type registration struct {
+	contract      *nfa_intent_v1alpha.IntentContract
+	parsed        *contract.IntentContract // contract in its internal form, for matching
+	lastHeartbeat time.Time
+	// expires is when the lease ends without another heartbeat, on the
+	// broker's clock
+	expires time.Time
+	// capacity is the one advertised with the last heartbeat, if any
+	capacity *Capacity
+	// static registrations are loaded with LoadStatic and never expire
+	static bool
+	// stored is the lease end last written to the store
+	stored time.Time
+	// expired reports that the lifecycle was told the lease ended
+	expired bool
+}
+
+// pingPolicy accepts the keepalive pings runtimes send on idle connections,
+// as often as gRPC clients send them
+var pingPolicy = keepalive.EnforcementPolicy{
+	MinTime:             10 * time.Second,
+	PermitWithoutStream: true,
+}
+
+// EmbeddedOption configures an embedded broker
+type EmbeddedOption func(*Embedded)
+
+// WithLivenessTimeout sets how long a registration stays live without
+// heartbeats, 30s by default, e.g. longer for devices on slow links
+func WithLivenessTimeout(d time.Duration) EmbeddedOption {
+	return func(b *Embedded) {
+		b.liveness = d
+	}
+}
+
+// WithReapAfter removes registrations whose lease expired more than d ago,
+// as UnregisterIntent would, so a long-running broker does not keep every
+// runtime that ever registered. By default expired registrations are kept
+// until Remove.
+func WithReapAfter(d time.Duration) EmbeddedOption {
+	return func(b *Embedded) {
+		b.reapAfter = d
+	}
+}
+
+// WithStore persists the registrations in s, so they survive restarts of
+// the broker once Restore loads them back. Registrations are written when
+// they change and leases every half liveness timeout, so a stored lease
+// does not end before the next is written.
+func WithStore(s Store) EmbeddedOption {
+	return func(b *Embedded) {
+		b.store = s
+	}
+}
+
+// WithServerOptions adds options to the broker's gRPC server, e.g. the
+// interceptor of a cluster node forwarding writes to the leader
+func WithServerOptions(opts ...grpc.ServerOption) EmbeddedOption {
+	return func(b *Embedded) {
+		b.serverOptions = append(b.serverOptions, opts...)
+	}
+}
+
+// WithTimerDir keeps the timers in dir, so pending timers survive restarts
+// of the broker. When the timers in dir cannot be loaded they are kept in
+// memory and the failure is logged.
+func WithTimerDir(dir string) EmbeddedOption {
+	return func(b *Embedded) {
+		b.timerDir = dir
+	}
+}
+
+// WithTransportCredentials secures the connections to the listeners of Serve
+// with creds, e.g. TLS. In-process connections, those of Dial and of the v1
+// shim, stay in plaintext.
+func WithTransportCredentials(creds credentials.TransportCredentials) EmbeddedOption {
+	return WithServerOptions(grpc.Creds(exceptInProcess{creds}))
+}
+
+// exceptInProcess skips the handshake of its credentials on the in-process
+// connections of the broker
+type exceptInProcess struct {
+	credentials.TransportCredentials
+}
+
+func (c exceptInProcess) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	if conn.LocalAddr().Network() == "bufconn" {
+		return insecure.NewCredentials().ServerHandshake(conn)
+	}
+	return c.TransportCredentials.ServerHandshake(conn)
+}
+
+func (c exceptInProcess) Clone() credentials.TransportCredentials {
+	return exceptInProcess{c.TransportCredentials.Clone()}
+}
+
+// WithService serves another service with the broker, registered by
+// register, e.g. the Register method of a cluster node
+func WithService(register func(grpc.ServiceRegistrar)) EmbeddedOption {
+	return func(b *Embedded) {
+		b.registrars = append(b.registrars, register)
+	}
+}
+
+// NewEmbedded starts an embedded broker. Close stops it.
+func NewEmbedded(opts ...EmbeddedOption) *Embedded {
+	b := &Embedded{
+		liveness:     livenessTimeout,
+		listener:     bufconn.Listen(embeddedBufferSize),
+		hub:          control.NewHub(),
+		services:     make(map[string]*registration),
+		aliases:      make(map[AliasUsage]uint64),
+		outcomes:     make(map[outcomeKey]outcomeStats),
+		deprecations: deprecation.NewTracker(0),
+		errorReports: errorreport.NewTracker(0),
+		now:          time.Now,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	serverOptions := append([]grpc.ServerOption{grpc.KeepaliveEnforcementPolicy(pingPolicy)}, b.serverOptions...)
+	if b.metrics != nil {
+		serverOptions = append(serverOptions, grpc.ChainUnaryInterceptor(b.unaryMetrics), grpc.ChainStreamInterceptor(b.streamMetrics))
+	}
+	b.server = grpc.NewServer(serverOptions...)
+	// Dialing is lazy, so the shim's connection can be created before serving
+	b.self, _ = grpc.Dial(EmbeddedTarget, b.DialOptions()...)
+	nfa_broker_v1alpha.RegisterIntentBrokerServer(b.server, b)
+	protoconv.NewBrokerV1(b.self).Register(b.server)
+	b.hub.Register(b.server)
+	var err error
+	if b.timers, err = timer.NewScheduler(b.timerDir, b.hub); err != nil {
+		logging.Logger(logging.Storage).Error("timers kept in memory", "dir", b.timerDir, "error", err)
+		// A scheduler without a directory cannot fail
+		b.timers, _ = timer.NewScheduler("", b.hub)
+	}
+	b.timers.Register(b.server)
+	for _, register := range b.registrars {
+		register(b.server)
+	}
+	var ctx context.Context
+	ctx, b.stop = context.WithCancel(context.Background())
+	go b.timers.Run(ctx)
+	if b.reapAfter > 0 {
+		go b.reap(ctx)
+	}
+	if b.lifecycle != nil {
+		go b.watchLeases(ctx)
+	}
+	go b.server.Serve(b.listener)
+	return b
+}
+
+// Serve accepts connections on lis as well, for runtimes and clients in
+// other processes, until Close or Shutdown. It returns nil once the broker
+// is stopped.
+//
+//	lis, err := net.Listen("tcp", ":50051")
+//	...
+//	go b.Serve(lis)
+func (b *Embedded) Serve(lis net.Listener) error {
+	if err := b.server.Serve(lis); !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+// DialOptions returns the options connecting to the broker at EmbeddedTarget
+func (b *Embedded) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return b.listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+}
+
+// Dial opens a client connection to the broker
+func (b *Embedded) Dial() (*grpc.ClientConn, error) {
+	return grpc.Dial(EmbeddedTarget, b.DialOptions()...)
+}
+
+// Hub returns the control hub, to push configuration, send commands and
+// broadcast intents to the runtimes connected to the broker
+func (b *Embedded) Hub() *control.Hub {
+	return b.hub
+}
+
+// Close stops the broker and closes every connection to it
+func (b *Embedded) Close() {
+	b.stop()
+	b.server.Stop()
+	b.self.Close()
+}
+
+// Shutdown stops the broker gracefully: runtimes on a control stream are sent
+// goAway, telling them when to expect the broker back and whether to fail
+// over, and in-flight calls finish. When ctx ends first the broker is stopped
+// at once, as by Close, and ctx's error returned.
+func (b *Embedded) Shutdown(ctx context.Context, goAway *nfa_control_v1alpha.GoAway) error {
+	b.stop()
+	_, gone := b.hub.GoAway(goAway)
+	stopped := make(chan struct{})
+	go func() {
+		select {
+		case <-gone:
+		case <-ctx.Done():
+		}
+		b.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		b.self.Close()
+		return nil
+	case <-ctx.Done():
+		b.Close()
+		<-stopped
+		return ctx.Err()
+	}
+}
+
+// RegisterIntent implements the RegisterIntent RPC
+func (b *Embedded) RegisterIntent(ctx context.Context, req *nfa_broker_v1alpha.RegisterIntentRequest) (*nfa_broker_v1alpha.RegisterIntentResponse, error) {
+	name := req.GetContract().GetMetadata().GetName()
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "contract with a name is required")
+	}
+	version, err := NegotiateVersion(req.ProtocolVersion)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	serviceID := StableServiceID(name, req.InstanceKey)
+	if req.InstanceKey == "" {
+		serviceID = name + "-" + randomID()
+	}
+
+	unlock := b.writes.lock(serviceID)
+	defer unlock()
+	b.mu.Lock()
+	existing, resumed := b.services[serviceID]
+	live := resumed && b.live(existing)
+	b.mu.Unlock()
+	if live && !req.TakeOver {
+		return nil, status.Errorf(codes.AlreadyExists,
+			"service id %s is held by a live instance; retry after %s or set take_over", serviceID, b.liveness)
+	}
+	parsed, err := contract.FromProto(req.Contract)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	now := b.now()
+	reg := &registration{
+		contract:      proto.Clone(req.Contract).(*nfa_intent_v1alpha.IntentContract),
+		parsed:        parsed,
+		lastHeartbeat: now,
+		expires:       now.Add(b.liveness),
+	}
+	// Stored before it is registered, so a failed write leaves it out
+	if b.store != nil {
+		if err := b.store.Put(reg.record(serviceID)); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to store registration: %v", err)
+		}
+		reg.stored = reg.expires
+	}
+	b.mu.Lock()
+	b.services[serviceID] = reg
+	b.mu.Unlock()
+	logging.Logger(logging.Registry).Info("service registered", "service_id", serviceID, "contract", name, "resumed", resumed)
+	if b.lifecycle != nil {
+		b.lifecycle.Registered(serviceID, name, resumed)
+	}
+	message := "Service registered successfully"
+	if resumed {
+		message = "Service re-registered with its previous id"
+	}
+	return &nfa_broker_v1alpha.RegisterIntentResponse{
+		ServiceId:        serviceID,
+		Success:          true,
+		Message:          message,
+		Resumed:          resumed,
+		ProtocolVersion:  version,
+		Features:         embeddedFeatures,
+		ContractVersions: contract.Versions,
+	}, nil
+}
+
+// MatchIntent implements the MatchIntent RPC. Live services are matched as
+// by package matching: those serving the action in the requested streaming
+// mode and admitting the parameters of the request's pattern, if it has
+// any, match, most specific first, then cheapest first, then by service ID.
+// The cost of a service is its utilization, by the capacity it advertises
+// with its heartbeats, plus its failure rate, by the outcomes consumers
+// report, see SuccessRate, so failing half its calls costs a service as
+// much as being half busy. An action that is an alias of another matches
+// the services of that action, whose name is returned so the consumer can
+// switch to it. Interactive requests, see package interactivity, get
+// services with a high QoS priority first and background requests those
+// with a low one, leaving the fast services to the users waiting on them.
+func (b *Embedded) MatchIntent(ctx context.Context, req *nfa_broker_v1alpha.IntentMatchRequest) (*nfa_broker_v1alpha.IntentMatchResponse, error) {
+	action := req.GetPattern().GetPattern().GetAction()
+	if action == "" {
+		return nil, status.Error(codes.InvalidArgument, "action is required")
+	}
+	streaming, err := contract.StreamingModeFromProto(req.Streaming)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	intent := matching.Intent{Action: action, Streaming: streaming}
+	if params := req.GetPattern().GetPattern().GetParameters(); len(params) > 0 {
+		intent.Parameters = params
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	services := make([]matching.Service, 0, len(b.services))
+	for id, reg := range b.services {
+		if !b.live(reg) {
+			continue
+		}
+		services = append(services, matching.Service{
+			ID:       id,
+			Contract: reg.parsed,
+			Cost:     utilization(reg) + 1 - b.successRate(id, reg.currentAction(action)),
+		})
+	}
+	resp := &nfa_broker_v1alpha.IntentMatchResponse{}
+	for _, c := range matching.Match(intent, services).Candidates {
+		resp.ServiceIds = append(resp.ServiceIds, c.ServiceID)
+		if c.Action != "" {
+			resp.Action = c.Action
+		}
+	}
+	if class := interactivity.Incoming(ctx); class != interactivity.Unspecified {
+		rank := func(id string) int {
+			return routingRank(b.services[id].contract.GetSpec().GetQualityOfService(), class)
+		}
+		sort.SliceStable(resp.ServiceIds, func(i, j int) bool {
+			return rank(resp.ServiceIds[i]) < rank(resp.ServiceIds[j])
+		})
+	}
+	if resp.Action != "" {
+		b.aliases[AliasUsage{Alias: action, Action: resp.Action}]++
+		b.deprecations.Observe(deprecation.Use{
+			Time:     b.now(),
+			Alias:    action,
+			Action:   resp.Action,
+			Consumer: consumerOf(ctx, req),
+		})
+	}
+	return resp, nil
+}
+
+// currentAction returns the action of the registration that action names,
+// directly or as an alias; action itself when it names none
+func (reg *registration) currentAction(action string) string {
+	for _, p := range reg.parsed.Spec.IntentPatterns {
+		if p.Pattern.Action == action || slices.Contains(p.Aliases, action) {
+			return p.Pattern.Action
+		}
+	}
+	return action
+}
+
+// utilization is the load a service advertised with its last heartbeat,
+// see Capacity.Utilization; services advertising none count as idle
+func utilization(reg *registration) float64 {
+	if reg.capacity == nil {
+		return 0
+	}
+	return reg.capacity.Utilization()
+}
+
+// routingRank orders the services matched for a request of class: by the
+// priority their contract's QoS declares, highest first for interactive
+// requests and lowest first for background ones
+func routingRank(qos *nfa_intent_v1alpha.QualityOfService, class interactivity.Class) int {
+	rank := 1
+	switch strings.ToLower(qos.GetPriority()) {
+	case "high":
+		rank = 0
+	case "low":
+		rank = 2
+	}
+	if class == interactivity.Background {
+		return 2 - rank
+	}
+	return rank
+}
+
+// AliasMatches returns how many matches each alias has resolved, to tell
+// when an alias is no longer used and can be removed from the contract
+func (b *Embedded) AliasMatches() map[AliasUsage]uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return maps.Clone(b.aliases)
+}
+
+// Deprecations returns the tracker of the consumers still calling aliases,
+// to report with admin.Server.SetDeprecations
+func (b *Embedded) Deprecations() *deprecation.Tracker {
+	return b.deprecations
+}
+
+// Heartbeat implements the Heartbeat RPC
+func (b *Embedded) Heartbeat(ctx context.Context, req *nfa_broker_v1alpha.HeartbeatRequest) (*nfa_broker_v1alpha.HeartbeatResponse, error) {
+	b.mu.Lock()
+	reg, ok := b.services[req.ServiceId]
+	if !ok {
+		b.mu.Unlock()
+		return nil, status.Errorf(codes.NotFound, "service %s is not registered", req.ServiceId)
+	}
+	// Leases are measured on the broker's clock only; the runtime reports a
+	// duration, never a time, so its clock may be arbitrarily wrong
+	allowance := min(time.Duration(req.RoundTripMs)*time.Millisecond, maxRoundTripAllowance)
+	now := b.now()
+	reg.lastHeartbeat = now
+	reg.expires = now.Add(b.liveness + allowance)
+	reg.expired = false
+	reg.capacity = nil
+	if c := req.GetCapacity(); c != nil {
+		capacity := CapacityFromProto(c)
+		reg.capacity = &capacity
+	}
+	due := reg.expires.Sub(reg.stored) >= b.liveness/2
+	b.mu.Unlock()
+	if due {
+		// A failed write is retried with the next heartbeat
+		b.persist(req.ServiceId)
+	}
+	return &nfa_broker_v1alpha.HeartbeatResponse{
+		Acknowledged:     true,
+		LeaseMs:          uint32((b.liveness + allowance).Milliseconds()),
+		BrokerTimeUnixMs: now.UnixMilli(),
+	}, nil
+}
+
+// UnregisterIntent implements the UnregisterIntent RPC
+func (b *Embedded) UnregisterIntent(ctx context.Context, req *nfa_broker_v1alpha.UnregisterIntentRequest) (*nfa_broker_v1alpha.UnregisterIntentResponse, error) {
+	b.mu.Lock()
+	reg, ok := b.services[req.ServiceId]
+	if !ok {
+		b.mu.Unlock()
+		return &nfa_broker_v1alpha.UnregisterIntentResponse{
+			Success: false,
+			Message: "service " + req.ServiceId + " is not registered",
+		}, nil
+	}
+	if reg.static {
+		b.mu.Unlock()
+		return &nfa_broker_v1alpha.UnregisterIntentResponse{
+			Success: false,
+			Message: "service " + req.ServiceId + " is a static provider",
+		}, nil
+	}
+	delete(b.services, req.ServiceId)
+	b.forgetOutcomes(req.ServiceId)
+	b.errorReports.Forget(req.ServiceId)
+	b.mu.Unlock()
+	b.persist(req.ServiceId)
+	b.unregistered(ReasonUnregistered, removal{req.ServiceId, reg.contract.GetMetadata().GetName()})
+	return &nfa_broker_v1alpha.UnregisterIntentResponse{Success: true, Message: "Service unregistered"}, nil
+}
+
+// ServiceInfo describes a registration of an embedded broker
+type ServiceInfo struct {
+	ServiceID string
+	// Contract is the name of the registered contract
+	Contract      string
+	Labels        map[string]string
+	LastHeartbeat time.Time
+	// LeaseExpires is when the service stops being live without another heartbeat
+	LeaseExpires time.Time
+	// Live reports whether the lease of the service has not expired
+	Live bool
+	// Capacity is the one the service advertised with its last heartbeat;
+	// nil when it advertises none
+	Capacity *Capacity
+	// Static reports whether the service was loaded with LoadStatic
+	Static bool
+}
+
+// Services lists the registrations sorted by service ID
+func (b *Embedded) Services() []ServiceInfo {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	infos := make([]ServiceInfo, 0, len(b.services))
+	for id, reg := range b.services {
+		infos = append(infos, ServiceInfo{
+			ServiceID:     id,
+			Contract:      reg.contract.GetMetadata().GetName(),
+			Labels:        maps.Clone(reg.contract.GetMetadata().GetLabels()),
+			LastHeartbeat: reg.lastHeartbeat,
+			LeaseExpires:  reg.expires,
+			Live:          b.live(reg),
+			Capacity:      reg.capacity,
+			Static:        reg.static,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ServiceID < infos[j].ServiceID })
+	return infos
+}
+
+// Contract returns a copy of the contract registered as serviceID
+func (b *Embedded) Contract(serviceID string) (*nfa_intent_v1alpha.IntentContract, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	reg, ok := b.services[serviceID]
+	if !ok {
+		return nil, false
+	}
+	return proto.Clone(reg.contract).(*nfa_intent_v1alpha.IntentContract), true
+}
+
+// Remove deletes a registration and reports whether it existed
+func (b *Embedded) Remove(serviceID string) bool {
+	b.mu.Lock()
+	reg, ok := b.services[serviceID]
+	delete(b.services, serviceID)
+	b.forgetOutcomes(serviceID)
+	b.errorReports.Forget(serviceID)
+	b.mu.Unlock()
+	b.persist(serviceID)
+	if ok {
+		b.unregistered(ReasonRemoved, removal{serviceID, reg.contract.GetMetadata().GetName()})
+	}
+	return ok
+}
+
+// SetLabels replaces the labels of a registered contract
+func (b *Embedded) SetLabels(serviceID string, labels map[string]string) error {
+	unlock := b.writes.lock(serviceID)
+	defer unlock()
+	b.mu.Lock()
+	reg, ok := b.services[serviceID]
+	if !ok {
+		b.mu.Unlock()
+		return fmt.Errorf("service %s is not registered", serviceID)
+	}
+	// The labels apply only once stored, so a failed write leaves the
+	// registration as it was
+	contract := proto.Clone(reg.contract).(*nfa_intent_v1alpha.IntentContract)
+	if contract.Metadata == nil {
+		contract.Metadata = &nfa_intent_v1alpha.Metadata{}
+	}
+	contract.Metadata.Labels = maps.Clone(labels)
+	rec := reg.record(serviceID)
+	rec.Contract = contract
+	b.mu.Unlock()
+	if b.store != nil && !reg.static {
+		if err := b.store.Put(rec); err != nil {
+			return fmt.Errorf("failed to store labels: %w", err)
+		}
+	}
+	b.mu.Lock()
+	reg.contract = contract
+	reg.stored = maxTime(reg.stored, rec.Expires)
+	b.mu.Unlock()
+	return nil
+}
+
+// reap removes the registrations expired for longer than reapAfter, every
+// liveness timeout, until ctx ends
+func (b *Embedded) reap(ctx context.Context) {
+	ticker := time.NewTicker(b.liveness)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.reapExpired()
+		}
+	}
+}
+
+// reapExpired removes the registrations expired for longer than reapAfter
+// and returns their service IDs
+func (b *Embedded) reapExpired() []string {
+	b.mu.Lock()
+	cutoff := b.now().Add(-b.reapAfter)
+	var reaped []string
+	var removals []removal
+	for id, reg := range b.services {
+		if !reg.static && reg.expires.Before(cutoff) {
+			delete(b.services, id)
+			b.forgetOutcomes(id)
+			b.errorReports.Forget(id)
+			reaped = append(reaped, id)
+			removals = append(removals, removal{id, reg.contract.GetMetadata().GetName()})
+		}
+	}
+	b.mu.Unlock()
+	for _, id := range reaped {
+		b.persist(id)
+	}
+	b.unregistered(ReasonReaped, removals...)
+	return reaped
+}
+
+// Restore registers again the registrations of the store of WithStore, as
+// they were when the broker stopped, and returns their service IDs sorted.
+// Leases are extended by the liveness timeout, so runtimes resume their
+// heartbeats before they expire. Records expired for longer than the
+// reap-after delay of WithReapAfter are compacted: deleted from the store,
+// as far as it allows, rather than restored. Registrations already present, e.g. static ones,
+// are kept. Nothing is restored when any record fails to load.
+func (b *Embedded) Restore() ([]string, error) {
+	if b.store == nil {
+		return nil, nil
+	}
+	recs, err := b.store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load registrations: %w", err)
+	}
+	b.mu.Lock()
+	now := b.now()
+	var errs []error
+	var expired []string
+	restored := make(map[string]*registration)
+	for _, rec := range recs {
+		if b.reapAfter > 0 && rec.Expires.Before(now.Add(-b.reapAfter)) {
+			expired = append(expired, rec.ServiceID)
+			continue
+		}
+		if _, ok := b.services[rec.ServiceID]; ok {
+			continue
+		}
+		parsed, err := contract.FromProto(rec.Contract)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", rec.ServiceID, err))
+			continue
+		}
+		restored[rec.ServiceID] = &registration{
+			contract:      rec.Contract,
+			parsed:        parsed,
+			lastHeartbeat: rec.LastHeartbeat,
+			expires:       maxTime(rec.Expires, now.Add(b.liveness)),
+			stored:        rec.Expires,
+		}
+	}
+	if len(errs) > 0 {
+		b.mu.Unlock()
+		return nil, errors.Join(errs...)
+	}
+	ids := make([]string, 0, len(restored))
+	for id, reg := range restored {
+		b.services[id] = reg
+		ids = append(ids, id)
+	}
+	b.mu.Unlock()
+	for _, id := range expired {
+		b.persist(id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// ApplyStored registers or updates a registration committed to a replicated
+// store, e.g. a cluster, by this broker or another. The lease is only ever
+// extended, as the broker may have renewed it since, so applying a record
+// again changes nothing.
+func (b *Embedded) ApplyStored(rec StoredRegistration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	reg, ok := b.services[rec.ServiceID]
+	if ok && reg.static {
+		return
+	}
+	parsed, err := contract.FromProto(rec.Contract)
+	if err != nil {
+		// The broker that stored it parsed it
+		return
+	}
+	if !ok {
+		reg = &registration{}
+		b.services[rec.ServiceID] = reg
+	}
+	reg.contract = proto.Clone(rec.Contract).(*nfa_intent_v1alpha.IntentContract)
+	reg.parsed = parsed
+	reg.lastHeartbeat = maxTime(reg.lastHeartbeat, rec.LastHeartbeat)
+	reg.expires = maxTime(reg.expires, rec.Expires)
+	reg.stored = maxTime(reg.stored, rec.Expires)
+	reg.expired = reg.expired && !b.live(reg)
+}
+
+// ApplyDeleted removes a registration deleted from a replicated store, as
+// ApplyStored
+func (b *Embedded) ApplyDeleted(serviceID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if reg, ok := b.services[serviceID]; ok && !reg.static {
+		delete(b.services, serviceID)
+		b.forgetOutcomes(serviceID)
+		b.errorReports.Forget(serviceID)
+	}
+}
+
+// persist writes the registration of serviceID to the store, if any, as it
+// is once the writes before it are done, or deletes it once it is removed;
+// static registrations are not written. mu must not be held: a replicated
+// store waits for the write to commit. Failures are logged: a lease is
+// written again with a later heartbeat, and a registration left behind by
+// a failed delete is compacted by the next Restore.
+func (b *Embedded) persist(serviceID string) {
+	if b.store == nil {
+		return
+	}
+	if err := b.write(serviceID); err != nil {
+		logging.Logger(logging.Storage).Warn("registration not stored", "service_id", serviceID, "error", err)
+	}
+}
+
+// write is persist, returning the failure of the store
+func (b *Embedded) write(serviceID string) error {
+	unlock := b.writes.lock(serviceID)
+	defer unlock()
+	b.mu.Lock()
+	reg, ok := b.services[serviceID]
+	var rec StoredRegistration
+	if ok {
+		rec = reg.record(serviceID)
+	}
+	b.mu.Unlock()
+	switch {
+	case !ok:
+		return b.store.Delete(serviceID)
+	case reg.static:
+		return nil
+	}
+	if err := b.store.Put(rec); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	reg.stored = maxTime(reg.stored, rec.Expires)
+	b.mu.Unlock()
+	return nil
+}
+
+// record returns the stored form of a registration; mu must be held
+func (reg *registration) record(serviceID string) StoredRegistration {
+	return StoredRegistration{
+		ServiceID:     serviceID,
+		Contract:      reg.contract,
+		LastHeartbeat: reg.lastHeartbeat,
+		Expires:       reg.expires,
+	}
+}
+
+// writeLocks are locks by service ID, held around the store writes of a
+// registration so they are made one at a time, in order
+type writeLocks struct {
+	mu    sync.Mutex
+	locks map[string]*writeLock
+}
+
+type writeLock struct {
+	sync.Mutex
+	// holders are the writes holding or waiting for the lock
+	holders int
+}
+
+// lock locks the writes of serviceID and returns the function unlocking
+// them
+func (w *writeLocks) lock(serviceID string) func() {
+	w.mu.Lock()
+	if w.locks == nil {
+		w.locks = make(map[string]*writeLock)
+	}
+	l, ok := w.locks[serviceID]
+	if !ok {
+		l = &writeLock{}
+		w.locks[serviceID] = l
+	}
+	l.holders++
+	w.mu.Unlock()
+	l.Lock()
+	return func() {
+		l.Unlock()
+		w.mu.Lock()
+		if l.holders--; l.holders == 0 {
+			delete(w.locks, serviceID)
+		}
+		w.mu.Unlock()
+	}
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// live reports whether a registration is static or its lease has not
+// expired; mu must be held
+func (b *Embedded) live(reg *registration) bool {
+	return reg.static || b.now().Before(reg.expires)
+}
+
+func randomID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,821 @@
+package broker
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/control"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/deprecation"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/interactivity"
+	nfa_broker_v1 "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1"
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	nfa_timer_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/timer/v1alpha"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/timer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// fakeClock is the broker's clock, advanced by tests
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func registerService(t *testing.T, b *Embedded) string {
+	t.Helper()
+	resp, err := b.RegisterIntent(context.Background(), &nfa_broker_v1alpha.RegisterIntentRequest{
+		Contract: &nfa_intent_v1alpha.IntentContract{Metadata: &nfa_intent_v1alpha.Metadata{Name: "translator"}},
+	})
+	if err != nil {
+		t.Fatalf("RegisterIntent() error = %v", err)
+	}
+	return resp.ServiceId
+}
+
+func isLive(b *Embedded, serviceID string) bool {
+	for _, svc := range b.Services() {
+		if svc.ServiceID == serviceID {
+			return svc.Live
+		}
+	}
+	return false
+}
+
+func TestHeartbeatLeaseIsBrokerRelative(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	b := NewEmbedded()
+	defer b.Close()
+	b.now = clock.now
+	id := registerService(t, b)
+
+	// The runtime reports only a round trip, never its time: a device whose
+	// clock is hours off is treated like any other
+	resp, err := b.Heartbeat(context.Background(), &nfa_broker_v1alpha.HeartbeatRequest{ServiceId: id, RoundTripMs: 400})
+	if err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	wantLease := livenessTimeout + 400*time.Millisecond
+	if got := time.Duration(resp.LeaseMs) * time.Millisecond; got != wantLease {
+		t.Errorf("lease = %v, want %v", got, wantLease)
+	}
+	if got := time.UnixMilli(resp.BrokerTimeUnixMs); !got.Equal(clock.t) {
+		t.Errorf("broker time = %v, want %v", got, clock.t)
+	}
+
+	clock.advance(wantLease - time.Millisecond)
+	if !isLive(b, id) {
+		t.Errorf("service expired before its lease, including the round trip allowance, ended")
+	}
+	clock.advance(time.Millisecond)
+	if isLive(b, id) {
+		t.Errorf("service live after its lease ended")
+	}
+}
+
+func TestHeartbeatRoundTripAllowanceIsCapped(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	b := NewEmbedded()
+	defer b.Close()
+	b.now = clock.now
+	id := registerService(t, b)
+
+	resp, err := b.Heartbeat(context.Background(), &nfa_broker_v1alpha.HeartbeatRequest{ServiceId: id, RoundTripMs: 3600 * 1000})
+	if err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	if got, want := time.Duration(resp.LeaseMs)*time.Millisecond, livenessTimeout+maxRoundTripAllowance; got != want {
+		t.Errorf("lease = %v, want %v", got, want)
+	}
+	clock.advance(livenessTimeout + maxRoundTripAllowance)
+	if isLive(b, id) {
+		t.Errorf("service live after the capped lease ended")
+	}
+}
+
+func TestReapExpired(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	b := NewEmbedded(WithLivenessTimeout(time.Minute), WithReapAfter(time.Hour))
+	defer b.Close()
+	b.now = clock.now
+	expired := registerService(t, b)
+	clock.advance(30 * time.Minute)
+	live := registerService(t, b)
+
+	clock.advance(30 * time.Second)
+	if isLive(b, expired) || !isLive(b, live) {
+		t.Fatalf("Services() = %+v, want %s expired after the liveness timeout", b.Services(), expired)
+	}
+	if reaped := b.reapExpired(); len(reaped) != 0 {
+		t.Errorf("reapExpired() = %v, want nothing expired for an hour", reaped)
+	}
+	clock.advance(31 * time.Minute)
+	if reaped := b.reapExpired(); !slices.Equal(reaped, []string{expired}) {
+		t.Errorf("reapExpired() = %v, want %s", reaped, expired)
+	}
+	if services := b.Services(); len(services) != 1 || services[0].ServiceID != live {
+		t.Errorf("Services() = %+v, want only %s", services, live)
+	}
+}
+
+func TestServe(t *testing.T) {
+	b := NewEmbedded()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	served := make(chan error, 1)
+	go func() { served <- b.Serve(lis) }()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	id := registerService(t, b)
+	if _, err := NewClient(conn).Renew(context.Background(), id, 0); err != nil {
+		t.Errorf("Renew() over TCP error = %v", err)
+	}
+
+	b.Close()
+	if err := <-served; err != nil {
+		t.Errorf("Serve() after Close error = %v, want nil", err)
+	}
+}
+
+func TestServeWithTransportCredentials(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	serverCreds := credentials.NewServerTLSFromCert(&tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key})
+	b := NewEmbedded(WithTransportCredentials(serverCreds))
+	defer b.Close()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	go b.Serve(lis)
+
+	// In-process connections stay in plaintext
+	id := registerService(t, b)
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(pool, "")))
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	if _, err := NewClient(conn).Renew(context.Background(), id, 0); err != nil {
+		t.Errorf("Renew() over TLS error = %v", err)
+	}
+	// The v1 shim forwards over an in-process connection
+	if _, err := nfa_broker_v1.NewIntentBrokerClient(conn).Heartbeat(context.Background(), &nfa_broker_v1.HeartbeatRequest{ServiceId: id}); err != nil {
+		t.Errorf("v1 Heartbeat() over TLS error = %v", err)
+	}
+	plain, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer plain.Close()
+	if _, err := NewClient(plain).Renew(context.Background(), id, 0); err == nil {
+		t.Errorf("Renew() in plaintext over TCP succeeded, want it refused")
+	}
+}
+
+func TestTimerDir(t *testing.T) {
+	dir := t.TempDir()
+	schedule := func(b *Embedded) (*timer.Client, func()) {
+		conn, err := b.Dial()
+		if err != nil {
+			t.Fatalf("Dial() error = %v", err)
+		}
+		return timer.NewClient(conn), func() { conn.Close() }
+	}
+	b := NewEmbedded(WithTimerDir(dir))
+	client, done := schedule(b)
+	created, err := client.Schedule(context.Background(), time.Now().Add(time.Hour), 0, nil, &nfa_control_v1alpha.Invoke{Action: "brew"})
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	done()
+	b.Close()
+
+	b = NewEmbedded(WithTimerDir(dir))
+	defer b.Close()
+	client, done = schedule(b)
+	defer done()
+	timers, err := client.List(context.Background(), nfa_timer_v1alpha.TimerState_TIMER_STATE_PENDING)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(timers) != 1 || timers[0].Id != created.Id {
+		t.Errorf("List() after a restart = %v, want %s", timers, created.Id)
+	}
+}
+
+func TestRenewThroughClient(t *testing.T) {
+	b := NewEmbedded()
+	defer b.Close()
+	id := registerService(t, b)
+	conn, err := b.Dial()
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+
+	lease, err := NewClient(conn).Renew(context.Background(), id, 250*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Renew() error = %v", err)
+	}
+	if want := livenessTimeout + 250*time.Millisecond; lease.TTL != want {
+		t.Errorf("TTL = %v, want %v", lease.TTL, want)
+	}
+	if lease.BrokerTime.IsZero() {
+		t.Errorf("broker time is zero")
+	}
+}
+
+func TestShutdownSendsGoAway(t *testing.T) {
+	b := NewEmbedded()
+	defer b.Close()
+	conn, err := b.Dial()
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+
+	got := make(chan *nfa_control_v1alpha.GoAway, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- control.NewClient(conn).Run(context.Background(), &nfa_control_v1alpha.Hello{RuntimeId: "rt-1"}, control.Handlers{
+			OnGoAway: func(g *nfa_control_v1alpha.GoAway) error {
+				got <- g
+				return nil
+			},
+		})
+	}()
+	for len(b.Hub().Sessions()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	back := time.Now().Add(time.Minute).Unix()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := b.Shutdown(ctx, &nfa_control_v1alpha.GoAway{Reason: "upgrade", ExpectedBackUnix: back}); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Run() error = %v, want nil after GoAway", err)
+	}
+	select {
+	case g := <-got:
+		if g.Reason != "upgrade" || g.ExpectedBackUnix != back || g.FailOver {
+			t.Errorf("GoAway = %v, want reason upgrade back at %d", g, back)
+		}
+	default:
+		t.Errorf("runtime was not sent GoAway")
+	}
+}
+
+func TestRegisterInPreferredContractVersion(t *testing.T) {
+	b := NewEmbedded()
+	defer b.Close()
+	conn, err := b.Dial()
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	client := NewClient(conn)
+
+	c := &contract.IntentContract{
+		Version:  contract.VersionV1Alpha,
+		Kind:     "IntentContract",
+		Metadata: contract.ContractMetadata{Name: "translator"},
+	}
+	version := func(id string) string {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return b.services[id].contract.GetVersion()
+	}
+	// Before negotiation every broker is assumed to take v1alpha only
+	first, err := client.Register(context.Background(), c)
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if got := version(first); got != contract.VersionV1Alpha {
+		t.Errorf("first registration version = %q, want %q", got, contract.VersionV1Alpha)
+	}
+	if got := client.Protocol().ContractVersion(); got != contract.VersionV1Beta {
+		t.Errorf("ContractVersion() = %q, want the broker's preferred %q", got, contract.VersionV1Beta)
+	}
+	second, err := client.Register(context.Background(), c)
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if got := version(second); got != contract.VersionV1Beta {
+		t.Errorf("second registration version = %q, want %q", got, contract.VersionV1Beta)
+	}
+	if c.Version != contract.VersionV1Alpha {
+		t.Errorf("registering changed the contract's version to %q", c.Version)
+	}
+}
+
+func TestMatchOrdersByInteractivity(t *testing.T) {
+	b := NewEmbedded()
+	defer b.Close()
+	register := func(name, priority string) string {
+		resp, err := b.RegisterIntent(context.Background(), &nfa_broker_v1alpha.RegisterIntentRequest{
+			Contract: &nfa_intent_v1alpha.IntentContract{
+				Metadata: &nfa_intent_v1alpha.Metadata{Name: name},
+				Spec: &nfa_intent_v1alpha.IntentSpec{
+					IntentPatterns: []*nfa_intent_v1alpha.IntentPattern{{
+						Pattern: &nfa_intent_v1alpha.IntentPattern_Pattern{Action: "translate_text"},
+					}},
+					QualityOfService: &nfa_intent_v1alpha.QualityOfService{Priority: priority},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("RegisterIntent() error = %v", err)
+		}
+		return resp.ServiceId
+	}
+	low, normal, high := register("batch", "low"), register("default", ""), register("fast", "high")
+
+	conn, err := b.Dial()
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	client := NewClient(conn)
+	for _, tc := range []struct {
+		class interactivity.Class
+		want  []string
+	}{
+		{interactivity.Interactive, []string{high, normal, low}},
+		{interactivity.Background, []string{low, normal, high}},
+	} {
+		got, err := client.Match(interactivity.With(context.Background(), tc.class), "translate_text", "")
+		if err != nil {
+			t.Fatalf("Match() error = %v", err)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%v match = %v, want %v", tc.class, got, tc.want)
+		}
+	}
+}
+
+func TestMatchHonorsConstraints(t *testing.T) {
+	b := NewEmbedded()
+	defer b.Close()
+	register := func(name string, constraints *nfa_intent_v1alpha.IntentPattern_Constraints) string {
+		resp, err := b.RegisterIntent(context.Background(), &nfa_broker_v1alpha.RegisterIntentRequest{
+			Contract: &nfa_intent_v1alpha.IntentContract{
+				Metadata: &nfa_intent_v1alpha.Metadata{Name: name},
+				Spec: &nfa_intent_v1alpha.IntentSpec{
+					IntentPatterns: []*nfa_intent_v1alpha.IntentPattern{{
+						Pattern:     &nfa_intent_v1alpha.IntentPattern_Pattern{Action: "translate_text"},
+						Constraints: constraints,
+					}},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("RegisterIntent() error = %v", err)
+		}
+		return resp.ServiceId
+	}
+	strict := register("strict", &nfa_intent_v1alpha.IntentPattern_Constraints{RequiredParameters: []string{"text"}})
+	lenient := register("lenient", nil)
+
+	for _, tc := range []struct {
+		params map[string]*nfa_intent_v1alpha.Value
+		want   []string
+	}{
+		// Without parameters services are matched on the action alone
+		{nil, []string{lenient, strict}},
+		{map[string]*nfa_intent_v1alpha.Value{"text": contract.ValueToProto("hello")}, []string{lenient, strict}},
+		{map[string]*nfa_intent_v1alpha.Value{"to": contract.ValueToProto("fr")}, []string{lenient}},
+	} {
+		resp, err := b.MatchIntent(context.Background(), &nfa_broker_v1alpha.IntentMatchRequest{
+			Pattern: &nfa_intent_v1alpha.IntentPattern{Pattern: &nfa_intent_v1alpha.IntentPattern_Pattern{
+				Action:     "translate_text",
+				Parameters: tc.params,
+			}},
+		})
+		if err != nil {
+			t.Fatalf("MatchIntent() error = %v", err)
+		}
+		if got := resp.ServiceIds; !slices.Equal(got, tc.want) {
+			t.Errorf("match of %v = %v, want %v", tc.params, got, tc.want)
+		}
+	}
+}
+
+func TestMatchOrdersByLoad(t *testing.T) {
+	b := NewEmbedded()
+	defer b.Close()
+	conn, err := b.Dial()
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	client := NewClient(conn)
+	ctx := context.Background()
+	register := func(name string, capacity *Capacity) string {
+		resp, err := b.RegisterIntent(ctx, &nfa_broker_v1alpha.RegisterIntentRequest{
+			Contract: &nfa_intent_v1alpha.IntentContract{
+				Metadata: &nfa_intent_v1alpha.Metadata{Name: name},
+				Spec: &nfa_intent_v1alpha.IntentSpec{
+					IntentPatterns: []*nfa_intent_v1alpha.IntentPattern{{
+						Pattern: &nfa_intent_v1alpha.IntentPattern_Pattern{Action: "translate_text"},
+					}},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("RegisterIntent() error = %v", err)
+		}
+		if _, err := client.RenewWithCapacity(ctx, resp.ServiceId, 0, capacity); err != nil {
+			t.Fatalf("RenewWithCapacity() error = %v", err)
+		}
+		return resp.ServiceId
+	}
+	busy := register("busy", &Capacity{ConcurrencyLimit: 4, InFlight: 6, QueueDepth: 2, P95Latency: 800 * time.Millisecond})
+	hot := register("hot", &Capacity{CPUPressure: 0.9, MemoryPressure: 0.2})
+	idle := register("idle", &Capacity{ConcurrencyLimit: 4, InFlight: 1, CPUPressure: 0.1})
+
+	got, err := client.Match(ctx, "translate_text", "")
+	if err != nil {
+		t.Fatalf("Match() error = %v", err)
+	}
+	if want := []string{idle, hot, busy}; !slices.Equal(got, want) {
+		t.Errorf("match = %v, want least loaded first %v", got, want)
+	}
+	for _, svc := range b.Services() {
+		if svc.ServiceID == busy && (svc.Capacity == nil || svc.Capacity.QueueDepth != 2 || svc.Capacity.P95Latency != 800*time.Millisecond) {
+			t.Errorf("advertised capacity = %+v, want the queue depth and latency sent", svc.Capacity)
+		}
+	}
+}
+
+func TestMatchOrdersByOutcomes(t *testing.T) {
+	b := NewEmbedded()
+	defer b.Close()
+	now := time.Now()
+	b.now = func() time.Time { return now }
+	conn, err := b.Dial()
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	client := NewClient(conn)
+	ctx := context.Background()
+	register := func(name string) string {
+		resp, err := b.RegisterIntent(ctx, &nfa_broker_v1alpha.RegisterIntentRequest{
+			Contract: &nfa_intent_v1alpha.IntentContract{
+				Metadata: &nfa_intent_v1alpha.Metadata{Name: name},
+				Spec: &nfa_intent_v1alpha.IntentSpec{
+					IntentPatterns: []*nfa_intent_v1alpha.IntentPattern{{
+						Pattern: &nfa_intent_v1alpha.IntentPattern_Pattern{Action: "translate_text"},
+						Aliases: []string{"translate"},
+					}},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("RegisterIntent() error = %v", err)
+		}
+		return resp.ServiceId
+	}
+	flaky, reliable := register("a-flaky"), register("b-reliable")
+	for i := 0; i < 10; i++ {
+		// Outcomes of the alias count for the action
+		if err := client.ReportOutcome(ctx, flaky, "translate", i%5 == 0); err != nil {
+			t.Fatalf("ReportOutcome() error = %v", err)
+		}
+		if err := client.ReportOutcome(ctx, reliable, "translate_text", true); err != nil {
+			t.Fatalf("ReportOutcome() error = %v", err)
+		}
+	}
+	if rate := b.SuccessRate(flaky, "translate_text"); rate < 0.3 || rate > 0.4 {
+		t.Errorf("SuccessRate() of 2 successes in 10 = %v, want 4/12 with the prior", rate)
+	}
+	got, err := client.Match(ctx, "translate_text", "")
+	if err != nil {
+		t.Fatalf("Match() error = %v", err)
+	}
+	if want := []string{reliable, flaky}; !slices.Equal(got, want) {
+		t.Errorf("match = %v, want the reliable service first %v", got, want)
+	}
+
+	// Old failures fade, so a recovered service moves back up
+	now = now.Add(time.Hour)
+	if rate := b.SuccessRate(flaky, "translate_text"); rate < 0.99 {
+		t.Errorf("SuccessRate() an hour later = %v, want about 1", rate)
+	}
+
+	if err := client.ReportOutcome(ctx, "gone", "translate_text", false); !errors.Is(err, ErrNotRegistered) {
+		t.Errorf("ReportOutcome() for an unknown service error = %v, want ErrNotRegistered", err)
+	}
+	b.Remove(flaky)
+	if rate := b.SuccessRate(flaky, "translate_text"); rate != 1 {
+		t.Errorf("SuccessRate() of a removed service = %v, want 1", rate)
+	}
+}
+
+func TestDeprecationReportNamesConsumers(t *testing.T) {
+	b := NewEmbedded()
+	defer b.Close()
+	_, err := b.RegisterIntent(context.Background(), &nfa_broker_v1alpha.RegisterIntentRequest{
+		Contract: &nfa_intent_v1alpha.IntentContract{
+			Metadata: &nfa_intent_v1alpha.Metadata{Name: "translator"},
+			Spec: &nfa_intent_v1alpha.IntentSpec{
+				IntentPatterns: []*nfa_intent_v1alpha.IntentPattern{{
+					Pattern: &nfa_intent_v1alpha.IntentPattern_Pattern{Action: "translate_text"},
+					Aliases: []string{"translate"},
+				}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterIntent() error = %v", err)
+	}
+
+	conn, err := b.Dial()
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	client := NewClient(conn)
+	for _, name := range []string{"chat-app", "chat-app", "mail-app"} {
+		if _, err := client.Match(WithConsumer(context.Background(), name), "translate", ""); err != nil {
+			t.Fatalf("Match() error = %v", err)
+		}
+	}
+	if _, err := client.Match(WithConsumer(context.Background(), "new-app"), "translate_text", ""); err != nil {
+		t.Fatalf("Match() error = %v", err)
+	}
+
+	report := b.Deprecations().Report(deprecation.Query{})
+	if len(report.Actions) != 1 {
+		t.Fatalf("report has %d actions, want 1", len(report.Actions))
+	}
+	a := report.Actions[0]
+	if a.Alias != "translate" || a.Action != "translate_text" || a.Calls != 3 {
+		t.Errorf("action = %s -> %s with %d calls, want translate -> translate_text with 3", a.Alias, a.Action, a.Calls)
+	}
+	var got []string
+	for _, c := range a.Consumers {
+		got = append(got, c.Consumer)
+	}
+	if want := []string{"chat-app", "mail-app"}; !slices.Equal(got, want) {
+		t.Errorf("consumers = %v, want %v", got, want)
+	}
+}
+
+func TestLoadStatic(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"translator.yaml": `
+version: v1alpha
+kind: IntentContract
+metadata:
+  name: translator
+spec:
+  intentPatterns:
+    - pattern:
+        action: translate_text
+  implementation:
+    endpoint:
+      type: grpc
+      host: 10.0.0.5
+      port: 50052
+`,
+		"summarizer.json": `{
+  "version": "v1alpha",
+  "kind": "IntentContract",
+  "metadata": {"name": "summarizer"},
+  "spec": {
+    "intentPatterns": [{"pattern": {"action": "summarize_text"}}],
+    "implementation": {"endpoint": {"type": "http", "url": "http://10.0.0.6:8080/summarize"}}
+  }
+}`,
+		"README.md": "not a contract",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	b := NewEmbedded()
+	defer b.Close()
+	b.now = clock.now
+
+	ids, err := b.LoadStatic(dir)
+	if err != nil {
+		t.Fatalf("LoadStatic() error = %v", err)
+	}
+	summarizer, translator := StableServiceID("summarizer", StaticInstanceKey), StableServiceID("translator", StaticInstanceKey)
+	if want := []string{summarizer, translator}; !slices.Equal(ids, want) {
+		t.Fatalf("LoadStatic() = %v, want %v", ids, want)
+	}
+
+	// Static providers never send heartbeats
+	clock.advance(time.Hour)
+	for _, id := range ids {
+		if !isLive(b, id) {
+			t.Errorf("static provider %s not live without heartbeats", id)
+		}
+	}
+	resp, err := b.MatchIntent(context.Background(), &nfa_broker_v1alpha.IntentMatchRequest{
+		Pattern: &nfa_intent_v1alpha.IntentPattern{Pattern: &nfa_intent_v1alpha.IntentPattern_Pattern{Action: "translate_text"}},
+	})
+	if err != nil {
+		t.Fatalf("MatchIntent() error = %v", err)
+	}
+	if got := resp.ServiceIds; !slices.Equal(got, []string{translator}) {
+		t.Errorf("match = %v, want %v", got, []string{translator})
+	}
+	for id, want := range map[string]string{translator: "10.0.0.5:50052", summarizer: "http://10.0.0.6:8080/summarize"} {
+		if got, ok := b.StaticEndpoint(id); !ok || got != want {
+			t.Errorf("StaticEndpoint(%s) = %q, %v, want %q", id, got, ok, want)
+		}
+	}
+	unregister, err := b.UnregisterIntent(context.Background(), &nfa_broker_v1alpha.UnregisterIntentRequest{ServiceId: translator})
+	if err != nil {
+		t.Fatalf("UnregisterIntent() error = %v", err)
+	}
+	if unregister.Success || !isLive(b, translator) {
+		t.Errorf("runtime unregistered a static provider")
+	}
+
+	// A contract without a fixed endpoint fails the whole directory
+	if err := os.WriteFile(filepath.Join(dir, "zzz.yaml"), []byte(`
+version: v1alpha
+kind: IntentContract
+metadata:
+  name: detector
+spec:
+  intentPatterns:
+    - pattern:
+        action: detect_language
+  implementation:
+    endpoint:
+      type: grpc
+      port: 50053
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.LoadStatic(dir); err == nil {
+		t.Errorf("LoadStatic() loaded a contract without a host")
+	}
+	if _, ok := b.StaticEndpoint(StableServiceID("detector", StaticInstanceKey)); ok {
+		t.Errorf("detector registered despite the failed load")
+	}
+}
+
+func TestRegisterStatic(t *testing.T) {
+	b := NewEmbedded()
+	defer b.Close()
+	port := 50052
+	static := func(name, host string) *contract.IntentContract {
+		return &contract.IntentContract{
+			Version:  "v1alpha",
+			Kind:     "IntentContract",
+			Metadata: contract.ContractMetadata{Name: name},
+			Spec: contract.IntentSpec{
+				IntentPatterns: []contract.IntentPattern{{Pattern: contract.Pattern{Action: name}}},
+				Implementation: contract.Implementation{Endpoint: contract.Endpoint{Type: "grpc", Host: host, Port: &port}},
+			},
+		}
+	}
+	ids, err := b.RegisterStatic(static("translator", "10.0.0.5"), static("summarizer", "10.0.0.6"))
+	if err != nil {
+		t.Fatalf("RegisterStatic() error = %v", err)
+	}
+	translator := StableServiceID("translator", StaticInstanceKey)
+	if want := []string{translator, StableServiceID("summarizer", StaticInstanceKey)}; !slices.Equal(ids, want) {
+		t.Fatalf("RegisterStatic() = %v, want %v", ids, want)
+	}
+	if c, ok := b.Contract(translator); !ok || c.Metadata.Name != "translator" {
+		t.Errorf("Contract(%s) = %v, %v, want the translator contract", translator, c, ok)
+	}
+
+	// A static provider of the same name is replaced, unless any contract is invalid
+	if _, err := b.RegisterStatic(static("translator", "10.0.0.7"), static("detector", "")); err == nil {
+		t.Errorf("RegisterStatic() registered a contract without a host")
+	}
+	if got, _ := b.StaticEndpoint(translator); got != "10.0.0.5:50052" {
+		t.Errorf("StaticEndpoint() after a failed RegisterStatic = %s, want it unchanged", got)
+	}
+	if _, err := b.RegisterStatic(static("translator", "10.0.0.7")); err != nil {
+		t.Fatalf("RegisterStatic() error = %v", err)
+	}
+	if got, _ := b.StaticEndpoint(translator); got != "10.0.0.7:50052" || len(b.Services()) != 2 {
+		t.Errorf("StaticEndpoint() = %s with %d services, want the replaced endpoint", got, len(b.Services()))
+	}
+}
+
+// recordingMetrics records the calls of Metrics as strings
+type recordingMetrics struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (m *recordingMetrics) record(call string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, call)
+}
+
+func (m *recordingMetrics) Request(method string, _ time.Duration, err error) {
+	m.record(fmt.Sprintf("request %s %v", method, status.Code(err)))
+}
+
+func (m *recordingMetrics) Registration(contract string, err error) {
+	m.record(fmt.Sprintf("registration %s %v", contract, status.Code(err)))
+}
+
+func (m *recordingMetrics) Heartbeat(serviceID string, err error) {
+	m.record(fmt.Sprintf("heartbeat %s %v", serviceID, status.Code(err)))
+}
+
+func (m *recordingMetrics) Match(action string, _ time.Duration, err error) {
+	m.record(fmt.Sprintf("match %s %v", action, status.Code(err)))
+}
+
+func TestMetrics(t *testing.T) {
+	metrics := &recordingMetrics{}
+	b := NewEmbedded(WithMetrics(metrics))
+	defer b.Close()
+	conn, err := b.Dial()
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	client := nfa_broker_v1alpha.NewIntentBrokerClient(conn)
+	ctx := context.Background()
+
+	resp, err := client.RegisterIntent(ctx, &nfa_broker_v1alpha.RegisterIntentRequest{
+		Contract:    &nfa_intent_v1alpha.IntentContract{Metadata: &nfa_intent_v1alpha.Metadata{Name: "translator"}},
+		InstanceKey: "1",
+	})
+	if err != nil {
+		t.Fatalf("RegisterIntent() error = %v", err)
+	}
+	client.Heartbeat(ctx, &nfa_broker_v1alpha.HeartbeatRequest{ServiceId: resp.ServiceId})
+	client.Heartbeat(ctx, &nfa_broker_v1alpha.HeartbeatRequest{ServiceId: "unknown"})
+	client.MatchIntent(ctx, &nfa_broker_v1alpha.IntentMatchRequest{Pattern: &nfa_intent_v1alpha.IntentPattern{
+		Pattern: &nfa_intent_v1alpha.IntentPattern_Pattern{Action: "translate_text"},
+	}})
+
+	method := "/" + nfa_broker_v1alpha.IntentBroker_ServiceDesc.ServiceName + "/"
+	want := []string{
+		"request " + method + "RegisterIntent OK",
+		"registration translator OK",
+		"request " + method + "Heartbeat OK",
+		"heartbeat " + resp.ServiceId + " OK",
+		"request " + method + "Heartbeat NotFound",
+		"heartbeat unknown NotFound",
+		"request " + method + "MatchIntent OK",
+		"match translate_text OK",
+	}
+	if !slices.Equal(metrics.calls, want) {
+		t.Errorf("metrics calls = %q, want %q", metrics.calls, want)
+	}
+}
@@ -0,0 +1,53 @@
+package broker
+
+import (
+	"context"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/errorreport"
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReportErrors implements the ReportErrors RPC. Summaries beyond
+// errorreport.MaxSummaries count as dropped.
+func (b *Embedded) ReportErrors(ctx context.Context, req *nfa_broker_v1alpha.ReportErrorsRequest) (*nfa_broker_v1alpha.ReportErrorsResponse, error) {
+	if req.ServiceId == "" {
+		return nil, status.Error(codes.InvalidArgument, "service id is required")
+	}
+	b.mu.Lock()
+	_, ok := b.services[req.ServiceId]
+	b.mu.Unlock()
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "service %s is not registered", req.ServiceId)
+	}
+	summaries := make([]errorreport.Summary, len(req.Errors))
+	for i, e := range req.Errors {
+		summaries[i] = errorreport.Summary{
+			Action:    e.Action,
+			Code:      e.Code,
+			Count:     e.Count,
+			Sample:    e.SampleMessage,
+			FirstSeen: unixMillis(e.FirstSeenUnixMs),
+			LastSeen:  unixMillis(e.LastSeenUnixMs),
+		}
+	}
+	b.errorReports.Record(req.ServiceId, summaries, req.Dropped)
+	return &nfa_broker_v1alpha.ReportErrorsResponse{}, nil
+}
+
+// ErrorReports returns the tracker of the errors runtimes reported, to
+// report with admin.Server.SetErrorReports
+func (b *Embedded) ErrorReports() *errorreport.Tracker {
+	return b.errorReports
+}
+
+// unixMillis converts milliseconds since the epoch to a time, 0 to the zero
+// time
+func unixMillis(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
@@ -0,0 +1,96 @@
+package broker
+
+import (
+	"context"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+)
+
+// Reasons a registration is removed, see Lifecycle.Unregistered
+const (
+	// ReasonUnregistered is a deregistration by the service's runtime
+	ReasonUnregistered = "unregistered"
+	// ReasonRemoved is a removal by an operator, see Embedded.Remove
+	ReasonRemoved = "removed"
+	// ReasonReaped is the removal of a registration expired for longer than
+	// the reap-after delay, see WithReapAfter
+	ReasonReaped = "reaped"
+)
+
+// Lifecycle is told of the changes to the registrations of an embedded
+// broker, e.g. to deliver them as the events of package webhook. Its
+// methods are called once the change is made, without the broker's lock
+// held, so they may call the broker. Registrations and removals a cluster
+// node applies from its leader are told by the leader only; every node
+// tells of the leases ending.
+type Lifecycle interface {
+	// Registered is called for every registration; resumed reports that
+	// the service registered again with its previous ID
+	Registered(serviceID, contract string, resumed bool)
+	// Unregistered is called when a registration is removed, for reason
+	Unregistered(serviceID, contract, reason string)
+	// Expired is called when the lease of a service ends without a
+	// heartbeat, once until the service heartbeats or registers again
+	Expired(serviceID, contract string)
+}
+
+// WithLifecycle tells l of the changes to the registrations
+func WithLifecycle(l Lifecycle) EmbeddedOption {
+	return func(b *Embedded) {
+		b.lifecycle = l
+	}
+}
+
+// removal is a registration removed from the registry, to tell the
+// lifecycle of
+type removal struct {
+	serviceID, contract string
+}
+
+// unregistered logs removals for reason and tells the lifecycle, if any, of
+// them; mu must not be held
+func (b *Embedded) unregistered(reason string, removals ...removal) {
+	for _, r := range removals {
+		logging.Logger(logging.Registry).Info("service unregistered", "service_id", r.serviceID, "contract", r.contract, "reason", reason)
+		if b.lifecycle != nil {
+			b.lifecycle.Unregistered(r.serviceID, r.contract, reason)
+		}
+	}
+}
+
+// watchLeases tells the lifecycle of the leases ending, four times per
+// liveness timeout, until ctx ends
+func (b *Embedded) watchLeases(ctx context.Context) {
+	ticker := time.NewTicker(b.liveness / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.expireLeases()
+		}
+	}
+}
+
+// expireLeases tells the lifecycle of the leases ended since the last call
+// and returns their service IDs
+func (b *Embedded) expireLeases() []string {
+	b.mu.Lock()
+	var expired []removal
+	for id, reg := range b.services {
+		if !reg.expired && !b.live(reg) {
+			reg.expired = true
+			expired = append(expired, removal{id, reg.contract.GetMetadata().GetName()})
+		}
+	}
+	b.mu.Unlock()
+	ids := make([]string, 0, len(expired))
+	for _, e := range expired {
+		logging.Logger(logging.Registry).Info("lease expired", "service_id", e.serviceID, "contract", e.contract)
+		b.lifecycle.Expired(e.serviceID, e.contract)
+		ids = append(ids, e.serviceID)
+	}
+	return ids
+}
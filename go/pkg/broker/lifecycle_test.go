@@ -0,0 +1,100 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+)
+
+// recordingLifecycle records the changes it is told of
+type recordingLifecycle struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *recordingLifecycle) record(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLifecycle) Registered(serviceID, contract string, resumed bool) {
+	l.record("registered %s %s %t", serviceID, contract, resumed)
+}
+
+func (l *recordingLifecycle) Unregistered(serviceID, contract, reason string) {
+	l.record("unregistered %s %s %s", serviceID, contract, reason)
+}
+
+func (l *recordingLifecycle) Expired(serviceID, contract string) {
+	l.record("expired %s %s", serviceID, contract)
+}
+
+func (l *recordingLifecycle) take() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	events := l.events
+	l.events = nil
+	return events
+}
+
+func TestLifecycle(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	l := &recordingLifecycle{}
+	b := NewEmbedded(WithLifecycle(l), WithLivenessTimeout(time.Minute), WithReapAfter(time.Hour))
+	defer b.Close()
+	b.now = clock.now
+	ctx := context.Background()
+
+	unregistered := registerService(t, b)
+	removed := registerService(t, b)
+	reaped := registerService(t, b)
+	if _, err := b.UnregisterIntent(ctx, &nfa_broker_v1alpha.UnregisterIntentRequest{ServiceId: unregistered}); err != nil {
+		t.Fatalf("UnregisterIntent() error = %v", err)
+	}
+	b.Remove(removed)
+	b.Remove(removed)
+	want := []string{
+		"registered " + unregistered + " translator false",
+		"registered " + removed + " translator false",
+		"registered " + reaped + " translator false",
+		"unregistered " + unregistered + " translator unregistered",
+		"unregistered " + removed + " translator removed",
+	}
+	if got := l.take(); !slices.Equal(got, want) {
+		t.Errorf("events = %q, want %q", got, want)
+	}
+
+	if expired := b.expireLeases(); len(expired) != 0 {
+		t.Errorf("expireLeases() = %v, want nothing before the liveness timeout", expired)
+	}
+	clock.advance(time.Minute)
+	if expired := b.expireLeases(); !slices.Equal(expired, []string{reaped}) {
+		t.Errorf("expireLeases() = %v, want %s", expired, reaped)
+	}
+	if expired := b.expireLeases(); len(expired) != 0 {
+		t.Errorf("expireLeases() again = %v, want a lease told of once", expired)
+	}
+	if _, err := b.Heartbeat(ctx, &nfa_broker_v1alpha.HeartbeatRequest{ServiceId: reaped}); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	clock.advance(time.Minute)
+	if expired := b.expireLeases(); !slices.Equal(expired, []string{reaped}) {
+		t.Errorf("expireLeases() after a heartbeat = %v, want %s again", expired, reaped)
+	}
+	clock.advance(time.Hour + time.Second)
+	b.reapExpired()
+	want = []string{
+		"expired " + reaped + " translator",
+		"expired " + reaped + " translator",
+		"unregistered " + reaped + " translator reaped",
+	}
+	if got := l.take(); !slices.Equal(got, want) {
+		t.Errorf("events = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package broker
+
+import (
+	"context"
+	"time"
+
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	"google.golang.org/grpc"
+)
+
+// Metrics records the RPCs an embedded broker handles, e.g. as the
+// Prometheus metrics of package metrics/prometheus. Calls of the v1 API are
+// recorded as well as the v1alpha calls they are translated into, and calls
+// a cluster node forwards to its leader are recorded by the leader.
+type Metrics interface {
+	// Request records an RPC of any of the broker's services
+	Request(method string, duration time.Duration, err error)
+	// Registration records a registration of contract; err is nil on success
+	Registration(contract string, err error)
+	// Heartbeat records a heartbeat received from serviceID
+	Heartbeat(serviceID string, err error)
+	// Match records the matching of an intent for action
+	Match(action string, duration time.Duration, err error)
+}
+
+// WithMetrics records the RPCs the broker handles in m
+func WithMetrics(m Metrics) EmbeddedOption {
+	return func(b *Embedded) {
+		b.metrics = m
+	}
+}
+
+func (b *Embedded) unaryMetrics(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := b.now()
+	resp, err := handler(ctx, req)
+	elapsed := b.now().Sub(start)
+	b.metrics.Request(info.FullMethod, elapsed, err)
+	switch req := req.(type) {
+	case *nfa_broker_v1alpha.RegisterIntentRequest:
+		b.metrics.Registration(req.GetContract().GetMetadata().GetName(), err)
+	case *nfa_broker_v1alpha.HeartbeatRequest:
+		b.metrics.Heartbeat(req.ServiceId, err)
+	case *nfa_broker_v1alpha.IntentMatchRequest:
+		b.metrics.Match(req.GetPattern().GetPattern().GetAction(), elapsed, err)
+	}
+	return resp, err
+}
+
+func (b *Embedded) streamMetrics(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := b.now()
+	err := handler(srv, stream)
+	b.metrics.Request(info.FullMethod, b.now().Sub(start), err)
+	return err
+}
@@ -0,0 +1,154 @@
+package broker
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// outcomeHalfLife is how long until a reported outcome counts half as
+// much, so a provider that recovers moves back up within minutes
+const outcomeHalfLife = 5 * time.Minute
+
+// outcomePrior is the weight of the successes every service starts with,
+// so a few early failures do not bury a new service
+const outcomePrior = 2
+
+// LabelNamespace is the contract label naming the namespace of a service
+const LabelNamespace = "nfa.namespace"
+
+// Outcome is the outcome of a call to a provider a consumer reported
+type Outcome struct {
+	Time      time.Time
+	ServiceID string
+	// Namespace is the LabelNamespace label of the provider's contract
+	Namespace string
+	// Action is the current name of the action called
+	Action string
+	// Contract is the provider's registered contract, not to be modified
+	Contract *nfa_intent_v1alpha.IntentContract
+	Success  bool
+}
+
+// WithOutcomes passes the outcomes consumers report to observe, e.g. to
+// measure providers against their objectives with package sla. It is called
+// without the broker's lock held, on the goroutine of the report.
+func WithOutcomes(observe func(Outcome)) EmbeddedOption {
+	return func(b *Embedded) {
+		b.observeOutcome = observe
+	}
+}
+
+// outcomeKey identifies the outcomes of an action of a service, by the
+// current name of the action
+type outcomeKey struct {
+	serviceID, action string
+}
+
+// outcomeStats are the exponentially decayed counts of the outcomes
+// consumers reported
+type outcomeStats struct {
+	successes, failures float64
+	updated             time.Time
+}
+
+// decayed returns the counts as of now
+func (s outcomeStats) decayed(now time.Time) (successes, failures float64) {
+	factor := 1.0
+	if elapsed := now.Sub(s.updated); elapsed > 0 {
+		factor = math.Exp2(-elapsed.Seconds() / outcomeHalfLife.Seconds())
+	}
+	return s.successes * factor, s.failures * factor
+}
+
+// successRate is the share of successful calls as of now, weighted towards
+// success while few outcomes are recent
+func (s outcomeStats) successRate(now time.Time) float64 {
+	successes, failures := s.decayed(now)
+	return (successes + outcomePrior) / (successes + failures + outcomePrior)
+}
+
+// ReportOutcome implements the ReportOutcome RPC. Outcomes of an alias
+// count for the action it names.
+func (b *Embedded) ReportOutcome(ctx context.Context, req *nfa_broker_v1alpha.ReportOutcomeRequest) (*nfa_broker_v1alpha.ReportOutcomeResponse, error) {
+	if req.ServiceId == "" || req.Action == "" {
+		return nil, status.Error(codes.InvalidArgument, "service id and action are required")
+	}
+	b.mu.Lock()
+	reg, ok := b.services[req.ServiceId]
+	if !ok {
+		b.mu.Unlock()
+		return nil, status.Errorf(codes.NotFound, "service %s is not registered", req.ServiceId)
+	}
+	action := ""
+	for _, p := range reg.contract.GetSpec().GetIntentPatterns() {
+		if current := p.GetPattern().GetAction(); current == req.Action || slices.Contains(p.Aliases, req.Action) {
+			action = current
+			break
+		}
+	}
+	if action == "" {
+		b.mu.Unlock()
+		return nil, status.Errorf(codes.NotFound, "service %s does not serve %s", req.ServiceId, req.Action)
+	}
+
+	key := outcomeKey{serviceID: req.ServiceId, action: action}
+	now := b.now()
+	stats := b.outcomes[key]
+	stats.successes, stats.failures = stats.decayed(now)
+	stats.updated = now
+	if req.Success {
+		stats.successes++
+	} else {
+		stats.failures++
+	}
+	b.outcomes[key] = stats
+	contract := reg.contract
+	b.mu.Unlock()
+	if b.observeOutcome != nil {
+		b.observeOutcome(Outcome{
+			Time:      now,
+			ServiceID: req.ServiceId,
+			Namespace: contract.GetMetadata().GetLabels()[LabelNamespace],
+			Action:    action,
+			Contract:  contract,
+			Success:   req.Success,
+		})
+	}
+	return &nfa_broker_v1alpha.ReportOutcomeResponse{}, nil
+}
+
+// SuccessRate returns the share of calls to action of serviceID that
+// consumers reported successful, decayed so recent outcomes count most: 1
+// for a service without reports, falling towards the share of successes as
+// outcomes are reported
+func (b *Embedded) SuccessRate(serviceID, action string) float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.successRate(serviceID, action)
+}
+
+// successRate is SuccessRate with mu held
+func (b *Embedded) successRate(serviceID, action string) float64 {
+	stats, ok := b.outcomes[outcomeKey{serviceID: serviceID, action: action}]
+	if !ok {
+		return 1
+	}
+	return stats.successRate(b.now())
+}
+
+// forgetOutcomes drops the outcomes of a service that is gone; mu must be
+// held
+func (b *Embedded) forgetOutcomes(serviceID string) {
+	for key := range b.outcomes {
+		if key.serviceID == serviceID {
+			delete(b.outcomes, key)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package broker
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+)
+
+// Protocol versions of the registration handshake. Version 1 is spoken by
+// runtimes and brokers predating negotiation.
+const (
+	ProtocolVersion    uint32 = 2
+	MinProtocolVersion uint32 = 1
+)
+
+// Optional broker features negotiated at registration
+const (
+	// FeatureControl is the control stream of the control service
+	FeatureControl = "control"
+	// FeatureEvents is the pub/sub service
+	FeatureEvents = "events"
+	// FeatureBlobs is the blob service
+	FeatureBlobs = "blobs"
+	// FeatureTakeOver is taking over a live stable service ID
+	FeatureTakeOver = "take_over"
+	// FeatureStreaming is matching intents by streaming mode
+	FeatureStreaming = "streaming"
+	// FeatureResidency is routing within the data residency of an intent
+	FeatureResidency = "residency"
+	// FeatureOutcomes is ranking services by the outcomes consumers report
+	FeatureOutcomes = "outcomes"
+	// FeatureTimers is the timer service, broadcasting intents at a future time
+	FeatureTimers = "timers"
+	// FeatureErrorReports is collecting the error summaries of runtimes
+	FeatureErrorReports = "error_reports"
+)
+
+// Features lists every optional feature this client can use
+var Features = []string{FeatureControl, FeatureEvents, FeatureBlobs, FeatureTakeOver, FeatureStreaming, FeatureResidency, FeatureOutcomes, FeatureTimers, FeatureErrorReports}
+
+var (
+	// ErrUnsupported is wrapped when the broker does not serve a feature or
+	// RPC, typically because it is older than the runtime
+	ErrUnsupported = errors.New("not supported by broker")
+	// ErrIncompatible is wrapped when the runtime and the broker share no
+	// protocol version
+	ErrIncompatible = errors.New("incompatible broker protocol version")
+)
+
+// Protocol is what a runtime and a broker agreed on at registration
+type Protocol struct {
+	Version uint32
+	// Features are the optional features the broker serves; nil when the
+	// broker predates negotiation
+	Features []string
+	// ContractVersions are the contract schema versions the broker accepts,
+	// preferred first; nil when it accepts v1alpha only
+	ContractVersions []string
+}
+
+// Negotiated reports whether the broker took part in negotiation
+func (p Protocol) Negotiated() bool {
+	return p.Version >= 2
+}
+
+// Supports reports whether the broker serves feature. Brokers predating
+// negotiation do not report features, so every feature is assumed; calls to
+// one they lack fail with ErrUnsupported.
+func (p Protocol) Supports(feature string) bool {
+	if !p.Negotiated() {
+		return true
+	}
+	i := sort.SearchStrings(p.Features, feature)
+	return i < len(p.Features) && p.Features[i] == feature
+}
+
+// ContractVersion returns the contract schema version to register contracts
+// in: the first of the broker's versions this client knows, or v1alpha,
+// which every broker accepts
+func (p Protocol) ContractVersion() string {
+	for _, version := range p.ContractVersions {
+		if slices.Contains(contract.Versions, version) {
+			return version
+		}
+	}
+	return contract.VersionV1Alpha
+}
+
+// NegotiateVersion returns the protocol version a broker speaks with a
+// runtime requesting version requested, 0 meaning 1, or an error wrapping
+// ErrIncompatible when the runtime is too old
+func NegotiateVersion(requested uint32) (uint32, error) {
+	if requested == 0 {
+		requested = 1
+	}
+	if requested < MinProtocolVersion {
+		return 0, fmt.Errorf("%w: runtime speaks %d, broker supports %d to %d", ErrIncompatible, requested, MinProtocolVersion, ProtocolVersion)
+	}
+	return min(requested, ProtocolVersion), nil
+}
+
+// negotiated returns the protocol a broker answered a registration with
+func negotiated(version uint32, features, contractVersions []string) (Protocol, error) {
+	if version == 0 {
+		return Protocol{Version: 1}, nil
+	}
+	if version < MinProtocolVersion || version > ProtocolVersion {
+		return Protocol{}, fmt.Errorf("%w: broker speaks %d, runtime supports %d to %d", ErrIncompatible, version, MinProtocolVersion, ProtocolVersion)
+	}
+	sorted := append([]string{}, features...)
+	sort.Strings(sorted)
+	return Protocol{Version: version, Features: sorted, ContractVersions: contractVersions}, nil
+}
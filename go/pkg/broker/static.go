@@ -0,0 +1,166 @@
+package broker
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+)
+
+// StaticInstanceKey is the instance key of the service IDs of static
+// providers: a static contract named n is registered as
+// StableServiceID(n, StaticInstanceKey)
+const StaticInstanceKey = "static"
+
+// LoadStatic registers the contracts in dir as static providers, for
+// air-gapped or fully static installations whose providers do not register
+// themselves. Every .yaml, .yml and .json file in dir is a contract, which
+// must be valid and declare a fixed endpoint: a gRPC host and port, or an
+// HTTP URL. Static providers are live without heartbeats and runtimes cannot
+// unregister them; Remove can. Their service IDs stay the same across
+// restarts and are returned in file name order. Nothing is registered when
+// any file fails to load.
+func (b *Embedded) LoadStatic(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read static contracts: %w", err)
+	}
+	var errs []error
+	contracts := make(map[string]*contract.IntentContract)
+	var ids []string
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		c, err := contract.LoadFile(path)
+		if err == nil {
+			err = c.Validate()
+		}
+		if err == nil {
+			_, err = staticEndpoint(c.Spec.Implementation.Endpoint)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		id := StableServiceID(c.Metadata.Name, StaticInstanceKey)
+		if _, ok := contracts[id]; ok {
+			errs = append(errs, fmt.Errorf("%s: another static contract is named %s", path, c.Metadata.Name))
+			continue
+		}
+		contracts[id] = c
+		ids = append(ids, id)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	b.addStatic(contracts)
+	return ids, nil
+}
+
+// RegisterStatic registers contracts as static providers, as LoadStatic does
+// for the contracts of a directory, e.g. those of an imported catalog. A
+// static provider of the same name is replaced. Their service IDs are
+// returned in the order of contracts; nothing is registered when any
+// contract is invalid.
+func (b *Embedded) RegisterStatic(contracts ...*contract.IntentContract) ([]string, error) {
+	var errs []error
+	byID := make(map[string]*contract.IntentContract)
+	ids := make([]string, 0, len(contracts))
+	for _, c := range contracts {
+		err := c.Validate()
+		if err == nil {
+			_, err = staticEndpoint(c.Spec.Implementation.Endpoint)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.Metadata.Name, err))
+			continue
+		}
+		id := StableServiceID(c.Metadata.Name, StaticInstanceKey)
+		if _, ok := byID[id]; ok {
+			errs = append(errs, fmt.Errorf("%s: another contract has the same name", c.Metadata.Name))
+			continue
+		}
+		byID[id] = c
+		ids = append(ids, id)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	b.addStatic(byID)
+	return ids, nil
+}
+
+// addStatic registers valid contracts, indexed by service ID, as static
+// providers
+func (b *Embedded) addStatic(contracts map[string]*contract.IntentContract) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	for id, c := range contracts {
+		b.services[id] = &registration{
+			contract:      c.ToProto(),
+			parsed:        c,
+			lastHeartbeat: now,
+			static:        true,
+		}
+	}
+}
+
+// StaticEndpoint returns the fixed endpoint of the static provider
+// serviceID, "host:port" for gRPC or the URL for HTTP, so a consumer can
+// dial it without asking the provider
+func (b *Embedded) StaticEndpoint(serviceID string) (string, bool) {
+	b.mu.Lock()
+	reg, ok := b.services[serviceID]
+	static := ok && reg.static
+	b.mu.Unlock()
+	if !static {
+		return "", false
+	}
+	e, ok := b.Endpoint(serviceID)
+	if !ok {
+		return "", false
+	}
+	endpoint, err := staticEndpoint(e)
+	return endpoint, err == nil
+}
+
+// Endpoint returns the endpoint declared by the contract of serviceID, static
+// or registered, to resolve with package endpoint
+func (b *Embedded) Endpoint(serviceID string) (contract.Endpoint, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	reg, ok := b.services[serviceID]
+	if !ok {
+		return contract.Endpoint{}, false
+	}
+	c, err := contract.FromProto(reg.contract)
+	if err != nil {
+		return contract.Endpoint{}, false
+	}
+	return c.Spec.Implementation.Endpoint, true
+}
+
+// staticEndpoint returns the address of a fixed endpoint, and an error when
+// the endpoint is not fixed
+func staticEndpoint(e contract.Endpoint) (string, error) {
+	switch {
+	case e.URL != "":
+		return e.URL, nil
+	case e.Host != "" && e.Port != nil:
+		return net.JoinHostPort(e.Host, strconv.Itoa(*e.Port)), nil
+	}
+	return "", fmt.Errorf("static provider needs a fixed endpoint: set implementation.endpoint.host and port, or url")
+}
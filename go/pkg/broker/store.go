@@ -0,0 +1,317 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/atrest"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// StoreSchemaVersion is the version of the records DirStore writes. Records
+// of older versions are upgraded by storeMigrations when loaded.
+const StoreSchemaVersion = 1
+
+// storeFileSuffix is the extension of the record files of a DirStore
+const storeFileSuffix = ".json"
+
+// RegistryNamespace is the atrest namespace registrations are sealed in
+const RegistryNamespace = "registry"
+
+// Store persists the registrations of an embedded broker so they survive
+// restarts; see WithStore. Static registrations are not stored, LoadStatic
+// loads them again. Implementations must be safe for concurrent use: the
+// broker calls them without its lock held, as a replicated store waits for
+// its writes to commit, and orders the writes of each registration itself.
+// Besides MemoryStore and
+// DirStore, module store/badger keeps them in an embedded Badger database;
+// other stores, such as BoltDB or SQLite ones, only have to implement these
+// three methods, encoding records with EncodeRecord.
+type Store interface {
+	// Load returns every stored registration
+	Load() ([]StoredRegistration, error)
+	// Put creates or replaces the registration of rec.ServiceID
+	Put(rec StoredRegistration) error
+	// Delete removes a registration; deleting an unknown one is not an error
+	Delete(serviceID string) error
+}
+
+// StoredRegistration is the persistent state of a registration. Capacity
+// and outcomes are not stored: heartbeats and consumers report them again.
+type StoredRegistration struct {
+	ServiceID     string
+	Contract      *nfa_intent_v1alpha.IntentContract
+	LastHeartbeat time.Time
+	// Expires is the end of the lease; heartbeats are written at most once
+	// per liveness timeout, so it may lag the lease by that much
+	Expires time.Time
+}
+
+// MemoryStore is an in-memory Store, for tests and for brokers restarted
+// within one process
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]StoredRegistration
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]StoredRegistration)}
+}
+
+// Load implements Store
+func (m *MemoryStore) Load() ([]StoredRegistration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	recs := make([]StoredRegistration, 0, len(m.records))
+	for _, rec := range m.records {
+		rec.Contract = proto.Clone(rec.Contract).(*nfa_intent_v1alpha.IntentContract)
+		recs = append(recs, rec)
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].ServiceID < recs[j].ServiceID })
+	return recs, nil
+}
+
+// Put implements Store
+func (m *MemoryStore) Put(rec StoredRegistration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec.Contract = proto.Clone(rec.Contract).(*nfa_intent_v1alpha.IntentContract)
+	m.records[rec.ServiceID] = rec
+	return nil
+}
+
+// Delete implements Store
+func (m *MemoryStore) Delete(serviceID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, serviceID)
+	return nil
+}
+
+// DirStore is a Store keeping one JSON file per registration in a
+// directory, each replaced atomically, so a crash loses at most the write
+// in progress. Every record carries its schema version: records written by
+// an older broker are upgraded when loaded and records of a newer one fail
+// to load rather than being misread.
+type DirStore struct {
+	dir    string
+	sealer *atrest.Sealer
+}
+
+// DirStoreOption configures a DirStore
+type DirStoreOption func(*DirStore)
+
+// SealedWith encrypts the records with the keys of sealer, in
+// RegistryNamespace, so a copy of the directory exposes no contract.
+// Records written before sealing was enabled are sealed when loaded.
+func SealedWith(sealer *atrest.Sealer) DirStoreOption {
+	return func(d *DirStore) {
+		d.sealer = sealer
+	}
+}
+
+// storedRecord is the format of the files of a DirStore
+type storedRecord struct {
+	SchemaVersion int             `json:"schema_version"`
+	ServiceID     string          `json:"service_id"`
+	Contract      json.RawMessage `json:"contract"`
+	LastHeartbeat time.Time       `json:"last_heartbeat"`
+	Expires       time.Time       `json:"expires"`
+}
+
+// storeMigration upgrades a record, decoded as its raw fields, from one
+// schema version to the next
+type storeMigration func(fields map[string]json.RawMessage) error
+
+// storeMigrations upgrade records by the version they upgrade from: a change
+// of the record format increments StoreSchemaVersion and adds the migration
+// from the previous version here
+var storeMigrations = map[int]storeMigration{}
+
+// NewDirStore creates a store in dir, creating the directory if needed
+func NewDirStore(dir string, opts ...DirStoreOption) (*DirStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create registry directory: %w", err)
+	}
+	d := &DirStore{dir: dir}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d, nil
+}
+
+// Load implements Store. Records of an older schema version are rewritten
+// in the current one.
+func (d *DirStore) Load() ([]StoredRegistration, error) {
+	files, err := os.ReadDir(d.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry directory: %w", err)
+	}
+	var recs []StoredRegistration
+	for _, f := range files {
+		name := f.Name()
+		if strings.HasPrefix(name, "tmp-") {
+			// Left over by a write interrupted before its rename
+			os.Remove(filepath.Join(d.dir, name))
+			continue
+		}
+		if f.IsDir() || !strings.HasSuffix(name, storeFileSuffix) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(d.dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load registration %s: %w", name, err)
+		}
+		rec, upgraded, err := DecodeRecord(data, d.sealer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load registration %s: %w", name, err)
+		}
+		if upgraded {
+			if err := d.Put(rec); err != nil {
+				return nil, fmt.Errorf("failed to upgrade registration %s: %w", name, err)
+			}
+		}
+		recs = append(recs, rec)
+	}
+	return recs, nil
+}
+
+// Put implements Store
+func (d *DirStore) Put(rec StoredRegistration) error {
+	data, err := EncodeRecord(rec, d.sealer)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(d.dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), d.path(rec.ServiceID))
+}
+
+// Delete implements Store
+func (d *DirStore) Delete(serviceID string) error {
+	if err := os.Remove(d.path(serviceID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path returns the file of a registration; service IDs embed contract
+// names, so they are escaped
+func (d *DirStore) path(serviceID string) string {
+	return filepath.Join(d.dir, url.PathEscape(serviceID)+storeFileSuffix)
+}
+
+// EncodeRecord encodes a registration in the format of the current schema
+// version, sealed with sealer in RegistryNamespace unless it is nil. Stores
+// other than DirStore use it and DecodeRecord to share its schema
+// migrations and sealing.
+func EncodeRecord(rec StoredRegistration, sealer *atrest.Sealer) ([]byte, error) {
+	c, err := protojson.Marshal(rec.Contract)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(storedRecord{
+		SchemaVersion: StoreSchemaVersion,
+		ServiceID:     rec.ServiceID,
+		Contract:      c,
+		LastHeartbeat: rec.LastHeartbeat,
+		Expires:       rec.Expires,
+	})
+	if err != nil || sealer == nil {
+		return data, err
+	}
+	if data, err = sealer.Seal(context.Background(), RegistryNamespace, data); err != nil {
+		return nil, fmt.Errorf("failed to seal registration: %w", err)
+	}
+	return data, nil
+}
+
+// DecodeRecord decodes a record of EncodeRecord, opening it with sealer if
+// it is sealed and upgrading it from an older schema version, and reports
+// whether it has to be written again: because it was upgraded, or because
+// it was written before sealing was enabled
+func DecodeRecord(data []byte, sealer *atrest.Sealer) (StoredRegistration, bool, error) {
+	sealed := atrest.IsSealed(data)
+	if sealed {
+		if sealer == nil {
+			return StoredRegistration{}, false, errors.New("record is sealed, but the store has no keys")
+		}
+		var err error
+		if data, err = sealer.Open(context.Background(), RegistryNamespace, data); err != nil {
+			return StoredRegistration{}, false, err
+		}
+	}
+	rec, upgraded, err := decodeRecord(data, StoreSchemaVersion, storeMigrations)
+	return rec, upgraded || (sealer != nil && !sealed), err
+}
+
+// decodeRecord decodes a record, applying migrations from its schema
+// version up to version, and reports whether it was upgraded
+func decodeRecord(data []byte, version int, migrations map[int]storeMigration) (StoredRegistration, bool, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return StoredRegistration{}, false, err
+	}
+	var from int
+	if err := json.Unmarshal(fields["schema_version"], &from); err != nil {
+		return StoredRegistration{}, false, fmt.Errorf("invalid schema version: %w", err)
+	}
+	if from > version {
+		return StoredRegistration{}, false, fmt.Errorf("schema version %d is newer than %d, written by a newer broker", from, version)
+	}
+	for v := from; v < version; v++ {
+		migrate, ok := migrations[v]
+		if !ok {
+			return StoredRegistration{}, false, fmt.Errorf("no migration from schema version %d", v)
+		}
+		if err := migrate(fields); err != nil {
+			return StoredRegistration{}, false, fmt.Errorf("failed to migrate from schema version %d: %w", v, err)
+		}
+	}
+	if from < version {
+		var err error
+		if data, err = json.Marshal(fields); err != nil {
+			return StoredRegistration{}, false, err
+		}
+	}
+	var r storedRecord
+	if err := json.Unmarshal(data, &r); err != nil {
+		return StoredRegistration{}, false, err
+	}
+	c := &nfa_intent_v1alpha.IntentContract{}
+	if err := protojson.Unmarshal(r.Contract, c); err != nil {
+		return StoredRegistration{}, false, fmt.Errorf("invalid contract: %w", err)
+	}
+	return StoredRegistration{
+		ServiceID:     r.ServiceID,
+		Contract:      c,
+		LastHeartbeat: r.LastHeartbeat,
+		Expires:       r.Expires,
+	}, from < version, nil
+}
@@ -0,0 +1,259 @@
+package broker
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/atrest"
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+)
+
+func TestRestoreFromDirStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewDirStore(dir)
+	if err != nil {
+		t.Fatalf("NewDirStore() error = %v", err)
+	}
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	b := NewEmbedded(WithStore(store))
+	b.now = clock.now
+	kept := registerService(t, b)
+	gone := registerService(t, b)
+	if err := b.SetLabels(kept, map[string]string{"env": "prod"}); err != nil {
+		t.Fatalf("SetLabels() error = %v", err)
+	}
+	if resp, _ := b.UnregisterIntent(context.Background(), &nfa_broker_v1alpha.UnregisterIntentRequest{ServiceId: gone}); !resp.Success {
+		t.Fatalf("UnregisterIntent() = %+v", resp)
+	}
+	b.Close()
+
+	// A restart, with the broker down for longer than the lease
+	clock.advance(time.Minute)
+	if store, err = NewDirStore(dir); err != nil {
+		t.Fatalf("NewDirStore() error = %v", err)
+	}
+	b = NewEmbedded(WithStore(store))
+	defer b.Close()
+	b.now = clock.now
+	ids, err := b.Restore()
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if !slices.Equal(ids, []string{kept}) {
+		t.Fatalf("Restore() = %v, want %s", ids, kept)
+	}
+	services := b.Services()
+	if len(services) != 1 || !services[0].Live || !maps.Equal(services[0].Labels, map[string]string{"env": "prod"}) {
+		t.Errorf("Services() = %+v, want %s live with its labels", services, kept)
+	}
+	if _, err := b.Heartbeat(context.Background(), &nfa_broker_v1alpha.HeartbeatRequest{ServiceId: kept}); err != nil {
+		t.Errorf("Heartbeat() error = %v, want the restored lease renewed", err)
+	}
+}
+
+func TestRestoreCompactsExpired(t *testing.T) {
+	store := NewMemoryStore()
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	b := NewEmbedded(WithStore(store), WithReapAfter(time.Hour))
+	b.now = clock.now
+	expired := registerService(t, b)
+	clock.advance(2 * time.Hour)
+	live := registerService(t, b)
+	b.Close()
+
+	b = NewEmbedded(WithStore(store), WithReapAfter(time.Hour))
+	defer b.Close()
+	b.now = clock.now
+	ids, err := b.Restore()
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if !slices.Equal(ids, []string{live}) {
+		t.Errorf("Restore() = %v, want only %s", ids, live)
+	}
+	recs, _ := store.Load()
+	if len(recs) != 1 || recs[0].ServiceID != live {
+		t.Errorf("Load() = %+v, want %s compacted", recs, expired)
+	}
+}
+
+func TestDecodeRecordMigrates(t *testing.T) {
+	// Version 1 of a record format that renamed lease_end to expires in
+	// version 2
+	migrations := map[int]storeMigration{
+		1: func(fields map[string]json.RawMessage) error {
+			fields["expires"] = fields["lease_end"]
+			delete(fields, "lease_end")
+			return nil
+		},
+	}
+	old := []byte(`{"schema_version":1,"service_id":"translator-1","contract":{"metadata":{"name":"translator"}},"lease_end":"2026-01-01T12:00:00Z"}`)
+	rec, upgraded, err := decodeRecord(old, 2, migrations)
+	if err != nil {
+		t.Fatalf("decodeRecord() error = %v", err)
+	}
+	want := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	if !upgraded || rec.ServiceID != "translator-1" || rec.Contract.GetMetadata().GetName() != "translator" || !rec.Expires.Equal(want) {
+		t.Errorf("decodeRecord() = %+v, %v, want the record upgraded", rec, upgraded)
+	}
+
+	if _, _, err := decodeRecord(old, 0, nil); err == nil {
+		t.Error("decodeRecord() of a newer schema version succeeded, want an error")
+	}
+	if _, _, err := decodeRecord(old, 3, migrations); err == nil {
+		t.Error("decodeRecord() without a migration succeeded, want an error")
+	}
+}
+
+func TestApplyStored(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	b := NewEmbedded()
+	defer b.Close()
+	b.now = clock.now
+	id := registerService(t, b)
+	renewed := b.Services()[0].LeaseExpires
+
+	// A replicated record older than the lease the broker renewed
+	b.ApplyStored(StoredRegistration{
+		ServiceID: id,
+		Contract:  &nfa_intent_v1alpha.IntentContract{Metadata: &nfa_intent_v1alpha.Metadata{Name: "translator", Labels: map[string]string{"env": "prod"}}},
+		Expires:   renewed.Add(-time.Second),
+	})
+	services := b.Services()
+	if len(services) != 1 || !services[0].LeaseExpires.Equal(renewed) || services[0].Labels["env"] != "prod" {
+		t.Errorf("Services() = %+v, want the labels applied and the lease kept", services)
+	}
+	b.ApplyDeleted(id)
+	if services := b.Services(); len(services) != 0 {
+		t.Errorf("Services() = %+v, want none after ApplyDeleted", services)
+	}
+}
+
+func TestDirStoreSealed(t *testing.T) {
+	dir := t.TempDir()
+	keyfile := filepath.Join(t.TempDir(), "keys")
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, atrest.KeySize))
+	if err := os.WriteFile(keyfile, []byte("k1 "+key+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := atrest.LoadKeyFile(keyfile)
+	if err != nil {
+		t.Fatalf("LoadKeyFile() error = %v", err)
+	}
+	rec := StoredRegistration{
+		ServiceID: "translator-1",
+		Contract:  &nfa_intent_v1alpha.IntentContract{Metadata: &nfa_intent_v1alpha.Metadata{Name: "translator"}},
+		Expires:   time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	// A record written before sealing was enabled is sealed when loaded
+	plain, _ := NewDirStore(dir)
+	if err := plain.Put(rec); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	sealed, _ := NewDirStore(dir, SealedWith(atrest.NewSealer(keys)))
+	recs, err := sealed.Load()
+	if err != nil || len(recs) != 1 || recs[0].Contract.Metadata.Name != "translator" || !recs[0].Expires.Equal(rec.Expires) {
+		t.Fatalf("Load() of a plaintext record = %+v, %v, want it", recs, err)
+	}
+	data, _ := os.ReadFile(plain.path(rec.ServiceID))
+	if !atrest.IsSealed(data) || bytes.Contains(data, []byte("translator")) {
+		t.Errorf("record after Load = %q, want it sealed", data)
+	}
+	if recs, err = sealed.Load(); err != nil || len(recs) != 1 {
+		t.Errorf("Load() of the sealed record = %+v, %v, want it", recs, err)
+	}
+
+	if _, err := plain.Load(); err == nil {
+		t.Errorf("Load() of sealed records without keys succeeded, want an error")
+	}
+}
+
+// failingStore is a MemoryStore whose writes fail while fail is set
+type failingStore struct {
+	*MemoryStore
+	fail bool
+}
+
+func (s *failingStore) Put(rec StoredRegistration) error {
+	if s.fail {
+		return errors.New("disk full")
+	}
+	return s.MemoryStore.Put(rec)
+}
+
+func TestSetLabelsStoreFailure(t *testing.T) {
+	store := &failingStore{MemoryStore: NewMemoryStore()}
+	b := NewEmbedded(WithStore(store))
+	defer b.Close()
+	id := registerService(t, b)
+	if err := b.SetLabels(id, map[string]string{"env": "staging"}); err != nil {
+		t.Fatalf("SetLabels() error = %v", err)
+	}
+
+	store.fail = true
+	if err := b.SetLabels(id, map[string]string{"env": "prod"}); err == nil {
+		t.Fatalf("SetLabels() with a failing store succeeded, want an error")
+	}
+	if services := b.Services(); services[0].Labels["env"] != "staging" {
+		t.Errorf("labels after a failed SetLabels = %v, want them unchanged", services[0].Labels)
+	}
+	recs, _ := store.Load()
+	if got := recs[0].Contract.GetMetadata().GetLabels()["env"]; got != "staging" {
+		t.Errorf("stored env label = %q, want staging", got)
+	}
+}
+
+// blockingStore is a MemoryStore whose writes wait for release, as those of
+// a replicated store wait for a commit
+type blockingStore struct {
+	*MemoryStore
+	writing chan string
+	release chan struct{}
+}
+
+func (s *blockingStore) Put(rec StoredRegistration) error {
+	s.writing <- rec.ServiceID
+	<-s.release
+	return s.MemoryStore.Put(rec)
+}
+
+func TestStoreWritesWithoutLock(t *testing.T) {
+	store := &blockingStore{MemoryStore: NewMemoryStore(), writing: make(chan string), release: make(chan struct{})}
+	b := NewEmbedded(WithStore(store))
+	defer b.Close()
+	registered := make(chan string)
+	go func() {
+		resp, _ := b.RegisterIntent(context.Background(), &nfa_broker_v1alpha.RegisterIntentRequest{
+			Contract: &nfa_intent_v1alpha.IntentContract{Metadata: &nfa_intent_v1alpha.Metadata{Name: "translator"}},
+		})
+		registered <- resp.GetServiceId()
+	}()
+	<-store.writing
+
+	// The broker serves while the write waits, without the registration
+	done := make(chan []ServiceInfo)
+	go func() { done <- b.Services() }()
+	select {
+	case services := <-done:
+		if len(services) != 0 {
+			t.Errorf("Services() while the registration is written = %v, want none", services)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Services() blocked by a store write")
+	}
+	close(store.release)
+	if id := <-registered; !isLive(b, id) {
+		t.Errorf("service %s not registered once written", id)
+	}
+}
@@ -0,0 +1,237 @@
+package contract
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+)
+
+var (
+	// ErrUnknownAction is returned by Admit for an action the contract does not declare
+	ErrUnknownAction = errors.New("action not declared by contract")
+	// ErrConstraintViolated is returned by Admit for parameters outside the
+	// declared constraints
+	ErrConstraintViolated = errors.New("parameters violate contract constraints")
+)
+
+// Admit checks that a request for action with parameters matches one of the
+// contract's intent patterns: the action, or one of its aliases, is declared,
+// every required parameter without a default is present and every
+// constrained parameter satisfies its constraint, see ParameterConstraint.Check.
+// Parameters without constraints are not checked. Parameters outside the
+// constraints fail with a *ViolationError listing all of them.
+func (c *IntentContract) Admit(action string, params map[string]*nfa_intent_v1alpha.Value) error {
+	var violation error
+	for _, p := range c.Spec.IntentPatterns {
+		if p.Pattern.Action != action && !slices.Contains(p.Aliases, action) {
+			continue
+		}
+		err := p.Admit(params)
+		if err == nil {
+			return nil
+		}
+		if violation == nil {
+			violation = err
+		}
+	}
+	if violation == nil {
+		return fmt.Errorf("%w: %s does not declare %q", ErrUnknownAction, c.Metadata.Name, action)
+	}
+	return violation
+}
+
+// Violation is a parameter outside the constraints of an intent pattern
+type Violation struct {
+	Parameter   string
+	Description string
+}
+
+// ViolationError lists the parameters of a request outside the constraints
+// of the intent pattern it was checked against, sorted by name. It matches
+// ErrConstraintViolated with errors.Is.
+type ViolationError struct {
+	Action     string
+	Violations []Violation
+}
+
+func (e *ViolationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = fmt.Sprintf("parameter %s: %s", v.Parameter, v.Description)
+	}
+	return fmt.Sprintf("%v: %s: %s", ErrConstraintViolated, e.Action, strings.Join(msgs, "; "))
+}
+
+// Is reports whether target is ErrConstraintViolated
+func (e *ViolationError) Is(target error) bool {
+	return target == ErrConstraintViolated
+}
+
+// Admit checks params against the constraints of the pattern alone, as
+// IntentContract.Admit does for the patterns declaring an action
+func (p IntentPattern) Admit(params map[string]*nfa_intent_v1alpha.Value) error {
+	if p.Constraints == nil {
+		return nil
+	}
+	var violations []Violation
+	for _, name := range p.Constraints.RequiredParameters {
+		if params[name] != nil {
+			continue
+		}
+		if pc, ok := p.Constraints.ParameterConstraints[name]; ok && pc.Default != nil {
+			continue
+		}
+		violations = append(violations, Violation{Parameter: name, Description: "required but missing"})
+	}
+	for name, pc := range p.Constraints.ParameterConstraints {
+		value := params[name]
+		if value == nil {
+			continue
+		}
+		if err := pc.Check(value); err != nil {
+			violations = append(violations, Violation{Parameter: name, Description: err.Error()})
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	sort.Slice(violations, func(i, j int) bool { return violations[i].Parameter < violations[j].Parameter })
+	return &ViolationError{Action: p.Pattern.Action, Violations: violations}
+}
+
+// Check reports whether value satisfies the constraint: it has the declared
+// type, lies within the declared range or enum values, matches the pattern,
+// has a length within bounds, and its items and properties satisfy their
+// own constraints, with every required property present unless it has a
+// default
+func (pc ParameterConstraint) Check(value *nfa_intent_v1alpha.Value) error {
+	switch pc.Type {
+	case "string":
+		if _, ok := value.Value.(*nfa_intent_v1alpha.Value_StringValue); !ok {
+			return errors.New("expected a string")
+		}
+	case "number", "integer":
+		v, ok := value.Value.(*nfa_intent_v1alpha.Value_NumberValue)
+		if !ok {
+			return errors.New("expected a number")
+		}
+		if pc.Type == "integer" && v.NumberValue != math.Trunc(v.NumberValue) {
+			return fmt.Errorf("expected an integer, got %v", v.NumberValue)
+		}
+	case "boolean":
+		if _, ok := value.Value.(*nfa_intent_v1alpha.Value_BoolValue); !ok {
+			return errors.New("expected a boolean")
+		}
+	case "array":
+		if _, ok := value.Value.(*nfa_intent_v1alpha.Value_ListValue); !ok {
+			return errors.New("expected an array")
+		}
+	case "object":
+		if _, ok := value.Value.(*nfa_intent_v1alpha.Value_StructValue); !ok {
+			return errors.New("expected an object")
+		}
+	}
+	if len(pc.EnumValues) > 0 {
+		v, ok := value.Value.(*nfa_intent_v1alpha.Value_StringValue)
+		if !ok || !slices.Contains(pc.EnumValues, v.StringValue) {
+			return fmt.Errorf("expected one of %v", pc.EnumValues)
+		}
+	}
+	if pc.Min != nil || pc.Max != nil {
+		v, ok := value.Value.(*nfa_intent_v1alpha.Value_NumberValue)
+		if !ok {
+			return errors.New("expected a number")
+		}
+		if pc.Min != nil && v.NumberValue < *pc.Min {
+			return fmt.Errorf("%v is below the minimum %v", v.NumberValue, *pc.Min)
+		}
+		if pc.Max != nil && v.NumberValue > *pc.Max {
+			return fmt.Errorf("%v is above the maximum %v", v.NumberValue, *pc.Max)
+		}
+	}
+	if pc.Pattern != "" {
+		v, ok := value.Value.(*nfa_intent_v1alpha.Value_StringValue)
+		if !ok {
+			return errors.New("expected a string")
+		}
+		re, err := compilePattern(pc.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		if !re.MatchString(v.StringValue) {
+			return fmt.Errorf("%q does not match the pattern %s", v.StringValue, pc.Pattern)
+		}
+	}
+	if pc.MinLength != nil || pc.MaxLength != nil {
+		var length int
+		switch v := value.Value.(type) {
+		case *nfa_intent_v1alpha.Value_StringValue:
+			length = utf8.RuneCountInString(v.StringValue)
+		case *nfa_intent_v1alpha.Value_ListValue:
+			length = len(v.ListValue.GetValues())
+		default:
+			return errors.New("expected a string or an array")
+		}
+		if pc.MinLength != nil && length < *pc.MinLength {
+			return fmt.Errorf("length %d is below the minimum %d", length, *pc.MinLength)
+		}
+		if pc.MaxLength != nil && length > *pc.MaxLength {
+			return fmt.Errorf("length %d is above the maximum %d", length, *pc.MaxLength)
+		}
+	}
+	if pc.Items != nil {
+		v, ok := value.Value.(*nfa_intent_v1alpha.Value_ListValue)
+		if !ok {
+			return errors.New("expected an array")
+		}
+		for i, item := range v.ListValue.GetValues() {
+			if err := pc.Items.Check(item); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+	}
+	if len(pc.Properties) > 0 || len(pc.Required) > 0 {
+		v, ok := value.Value.(*nfa_intent_v1alpha.Value_StructValue)
+		if !ok {
+			return errors.New("expected an object")
+		}
+		fields := v.StructValue.GetFields()
+		for _, name := range pc.Required {
+			if fields[name] == nil && pc.Properties[name].Default == nil {
+				return fmt.Errorf("property %s is required", name)
+			}
+		}
+		for name, property := range pc.Properties {
+			if field := fields[name]; field != nil {
+				if err := property.Check(field); err != nil {
+					return fmt.Errorf("property %s: %w", name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// patterns caches compiled constraint patterns, which are checked on every
+// admitted request
+var patterns sync.Map // string -> *regexp.Regexp
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
@@ -0,0 +1,651 @@
+// Package contract defines intent contracts, the YAML documents in which a
+// service declares the intents it serves, and converts them to the protobuf
+// form registered with the Intent Broker
+package contract
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalid is wrapped by every error reporting a malformed or invalid contract
+var ErrInvalid = errors.New("invalid intent contract")
+
+// IntentContract represents the internal structure of an intent contract.
+// Version is the schema version the contract is written in, see Versions;
+// the other fields are the same for every version and laid out as in v1alpha.
+type IntentContract struct {
+	Version  string           `yaml:"version"`
+	Kind     string           `yaml:"kind"`
+	Metadata ContractMetadata `yaml:"metadata"`
+	Spec     IntentSpec       `yaml:"spec"`
+}
+
+type ContractMetadata struct {
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+}
+
+type IntentSpec struct {
+	IntentPatterns   []IntentPattern   `yaml:"intentPatterns"`
+	Implementation   Implementation    `yaml:"implementation"`
+	QualityOfService *QualityOfService `yaml:"qualityOfService,omitempty"`
+}
+
+type IntentPattern struct {
+	Pattern     Pattern             `yaml:"pattern"`
+	Constraints *PatternConstraints `yaml:"constraints,omitempty"`
+	Streaming   StreamingMode       `yaml:"streaming,omitempty"`
+	// Classification restricts which providers the intent may be routed to
+	Classification *DataClassification `yaml:"classification,omitempty"`
+	// Aliases are former names of the action. The broker forwards intents
+	// using them to this action, so renaming an action does not break
+	// consumers, and counts their use until they can be removed.
+	Aliases []string `yaml:"aliases,omitempty"`
+	// Mappings derive the parameters the provider expects from those
+	// consumers send, applied in order before the constraints are checked
+	Mappings []ParameterMapping `yaml:"mappings,omitempty"`
+	// Results describes the responses of the provider
+	Results *ResultSchema `yaml:"results,omitempty"`
+	// Examples are named requests of the pattern and their expected
+	// responses
+	Examples []Example `yaml:"examples,omitempty"`
+}
+
+// ToProto converts the pattern to protobuf format. Inline parameters of
+// types ValueToProto does not convert are left out.
+func (p IntentPattern) ToProto() *nfa_intent_v1alpha.IntentPattern {
+	out := &nfa_intent_v1alpha.IntentPattern{
+		Pattern:        &nfa_intent_v1alpha.IntentPattern_Pattern{Action: p.Pattern.Action},
+		Constraints:    p.Constraints.ToProto(),
+		Streaming:      p.Streaming.ToProto(),
+		Classification: p.Classification.ToProto(),
+		Aliases:        p.Aliases,
+		Results:        p.Results.ToProto(),
+	}
+	for _, m := range p.Mappings {
+		out.Mappings = append(out.Mappings, m.ToProto())
+	}
+	for _, e := range p.Examples {
+		out.Examples = append(out.Examples, e.ToProto())
+	}
+	if len(p.Pattern.Parameters) > 0 {
+		out.Pattern.Parameters = make(map[string]*nfa_intent_v1alpha.Value, len(p.Pattern.Parameters))
+		for name, v := range p.Pattern.Parameters {
+			if value := ValueToProto(v); value != nil {
+				out.Pattern.Parameters[name] = value
+			}
+		}
+	}
+	return out
+}
+
+// StreamingMode describes how an intent is invoked; empty means unary
+type StreamingMode string
+
+const (
+	StreamingUnary  StreamingMode = "unary"
+	StreamingServer StreamingMode = "server"
+	StreamingClient StreamingMode = "client"
+	StreamingBidi   StreamingMode = "bidi"
+)
+
+// Validate checks that the mode is one of the known streaming modes
+func (m StreamingMode) Validate() error {
+	switch m {
+	case "", StreamingUnary, StreamingServer, StreamingClient, StreamingBidi:
+		return nil
+	}
+	return fmt.Errorf("unknown streaming mode %q, expected unary, server, client or bidi", m)
+}
+
+// ToProto converts the mode to its protobuf enum
+func (m StreamingMode) ToProto() nfa_intent_v1alpha.StreamingMode {
+	switch m {
+	case StreamingServer:
+		return nfa_intent_v1alpha.StreamingMode_STREAMING_MODE_SERVER_STREAM
+	case StreamingClient:
+		return nfa_intent_v1alpha.StreamingMode_STREAMING_MODE_CLIENT_STREAM
+	case StreamingBidi:
+		return nfa_intent_v1alpha.StreamingMode_STREAMING_MODE_BIDI
+	default:
+		return nfa_intent_v1alpha.StreamingMode_STREAMING_MODE_UNARY
+	}
+}
+
+// Residency restricts where the data of an intent may be processed; empty
+// means unrestricted
+type Residency string
+
+const (
+	// ResidencyOnDevice keeps intents on the consumer's device: only
+	// providers with the same nfa.device label may serve them
+	ResidencyOnDevice Residency = "on-device"
+	// ResidencyRegion allows only providers whose nfa.region label is one of
+	// the classification's regions
+	ResidencyRegion Residency = "region"
+)
+
+// DataClassification declares the residency requirements of an intent's data
+type DataClassification struct {
+	Residency Residency `yaml:"residency"`
+	Regions   []string  `yaml:"regions,omitempty"`
+}
+
+// Validate checks the residency and that region restrictions name a region
+func (d *DataClassification) Validate() error {
+	switch d.Residency {
+	case "", ResidencyOnDevice:
+		if len(d.Regions) > 0 {
+			return fmt.Errorf("regions are only allowed with residency %q", ResidencyRegion)
+		}
+		return nil
+	case ResidencyRegion:
+		if len(d.Regions) == 0 {
+			return fmt.Errorf("residency %q requires at least one region", ResidencyRegion)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown residency %q, expected on-device or region", d.Residency)
+}
+
+// ToProto converts the classification to protobuf format
+func (d *DataClassification) ToProto() *nfa_intent_v1alpha.DataClassification {
+	if d == nil {
+		return nil
+	}
+	out := &nfa_intent_v1alpha.DataClassification{Regions: d.Regions}
+	switch d.Residency {
+	case ResidencyOnDevice:
+		out.Residency = nfa_intent_v1alpha.Residency_RESIDENCY_ON_DEVICE
+	case ResidencyRegion:
+		out.Residency = nfa_intent_v1alpha.Residency_RESIDENCY_REGION
+	}
+	return out
+}
+
+type Pattern struct {
+	Action     string                 `yaml:"action"`
+	Parameters map[string]interface{} `yaml:",inline"`
+}
+
+type PatternConstraints struct {
+	RequiredParameters   []string                       `yaml:"requiredParameters,omitempty"`
+	ParameterConstraints map[string]ParameterConstraint `yaml:"parameterConstraints,omitempty"`
+}
+
+// ToProto converts the constraints to protobuf format
+func (pc *PatternConstraints) ToProto() *nfa_intent_v1alpha.IntentPattern_Constraints {
+	if pc == nil {
+		return nil
+	}
+	out := &nfa_intent_v1alpha.IntentPattern_Constraints{RequiredParameters: pc.RequiredParameters}
+	if len(pc.ParameterConstraints) > 0 {
+		out.ParameterConstraints = make(map[string]*nfa_intent_v1alpha.ParameterConstraint, len(pc.ParameterConstraints))
+		for name, c := range pc.ParameterConstraints {
+			out.ParameterConstraints[name] = c.ToProto()
+		}
+	}
+	return out
+}
+
+// ParameterConstraint constrains the values of a parameter. Type is one of
+// "string", "number", "integer", "boolean", "array" and "object"; the other
+// fields apply to the types noted on them.
+type ParameterConstraint struct {
+	Type       string   `yaml:"type,omitempty"`
+	EnumValues []string `yaml:"enumValues,omitempty"`
+	// Min and Max bound numbers
+	Min *float64 `yaml:"min,omitempty"`
+	Max *float64 `yaml:"max,omitempty"`
+	// Pattern is a regular expression, in RE2 syntax, that strings must match
+	Pattern string `yaml:"pattern,omitempty"`
+	// MinLength and MaxLength bound the characters of a string or the items
+	// of an array
+	MinLength *int `yaml:"minLength,omitempty"`
+	MaxLength *int `yaml:"maxLength,omitempty"`
+	// Items constrains every item of an array
+	Items *ParameterConstraint `yaml:"items,omitempty"`
+	// Properties constrains the properties of an object; undeclared
+	// properties are not checked
+	Properties map[string]ParameterConstraint `yaml:"properties,omitempty"`
+	// Required lists the properties an object must have, unless their
+	// constraint has a default; the others are optional
+	Required []string `yaml:"required,omitempty"`
+	// Sensitivity marks parameters whose values are redacted in logs,
+	// traces, audit events and analytics
+	Sensitivity Sensitivity `yaml:"sensitivity,omitempty"`
+	// Default is used when neither the request nor the context supplies the parameter
+	Default interface{} `yaml:"default,omitempty"`
+}
+
+// kind returns the type of the constraint, the declared one or the one its
+// other fields imply
+func (pc ParameterConstraint) kind() string {
+	switch {
+	case pc.Type != "":
+		return pc.Type
+	case len(pc.EnumValues) > 0, pc.Pattern != "":
+		return "string"
+	case pc.Min != nil || pc.Max != nil:
+		return "number"
+	case pc.Items != nil:
+		return "array"
+	case len(pc.Properties) > 0 || len(pc.Required) > 0:
+		return "object"
+	case pc.MinLength != nil || pc.MaxLength != nil:
+		return "string"
+	}
+	return ""
+}
+
+// ToProto converts the constraint to protobuf format. Enum values take
+// precedence over the constraints of the type; a string type without either
+// converts to an empty string constraint.
+func (pc ParameterConstraint) ToProto() *nfa_intent_v1alpha.ParameterConstraint {
+	out := &nfa_intent_v1alpha.ParameterConstraint{
+		Sensitivity:  pc.Sensitivity.ToProto(),
+		DefaultValue: ValueToProto(pc.Default),
+	}
+	switch kind := pc.kind(); {
+	case len(pc.EnumValues) > 0:
+		out.Constraint = &nfa_intent_v1alpha.ParameterConstraint_EnumConstraint{
+			EnumConstraint: &nfa_intent_v1alpha.EnumConstraint{Values: pc.EnumValues},
+		}
+	case kind == "number" || kind == "integer":
+		out.Constraint = &nfa_intent_v1alpha.ParameterConstraint_NumberConstraint{
+			NumberConstraint: &nfa_intent_v1alpha.NumberConstraint{Min: pc.Min, Max: pc.Max},
+		}
+	case kind == "string":
+		c := &nfa_intent_v1alpha.StringConstraint{MinLength: toUint32(pc.MinLength), MaxLength: toUint32(pc.MaxLength)}
+		if pc.Pattern != "" {
+			c.Pattern = &pc.Pattern
+		}
+		out.Constraint = &nfa_intent_v1alpha.ParameterConstraint_StringConstraint{StringConstraint: c}
+	case kind == "array":
+		c := &nfa_intent_v1alpha.ArrayConstraint{MinLength: toUint32(pc.MinLength), MaxLength: toUint32(pc.MaxLength)}
+		if pc.Items != nil {
+			c.Items = pc.Items.ToProto()
+		}
+		out.Constraint = &nfa_intent_v1alpha.ParameterConstraint_ArrayConstraint{ArrayConstraint: c}
+	case kind == "object":
+		c := &nfa_intent_v1alpha.ObjectConstraint{Required: pc.Required}
+		if len(pc.Properties) > 0 {
+			c.Properties = make(map[string]*nfa_intent_v1alpha.ParameterConstraint, len(pc.Properties))
+			for name, property := range pc.Properties {
+				c.Properties[name] = property.ToProto()
+			}
+		}
+		out.Constraint = &nfa_intent_v1alpha.ParameterConstraint_ObjectConstraint{ObjectConstraint: c}
+	}
+	return out
+}
+
+// toUint32 converts an optional length; negative lengths, which Validate
+// rejects, convert to 0
+func toUint32(n *int) *uint32 {
+	if n == nil {
+		return nil
+	}
+	v := uint32(max(*n, 0))
+	return &v
+}
+
+// Validate checks that the constraint is consistent: a known type, fields
+// that apply to it, bounds in order, a valid pattern, required properties
+// among the declared ones, if any, and a default that satisfies the constraint
+func (pc ParameterConstraint) Validate() error {
+	if err := pc.Sensitivity.Validate(); err != nil {
+		return err
+	}
+	kind := pc.kind()
+	switch kind {
+	case "", "string", "number", "integer", "boolean", "array", "object":
+	default:
+		return fmt.Errorf("unknown type %q, expected string, number, integer, boolean, array or object", pc.Type)
+	}
+	applies := func(field string, set bool, kinds ...string) error {
+		if set && !slices.Contains(kinds, kind) {
+			return fmt.Errorf("%s does not apply to type %s", field, kind)
+		}
+		return nil
+	}
+	if err := errors.Join(
+		applies("enumValues", len(pc.EnumValues) > 0, "string"),
+		applies("min", pc.Min != nil, "number", "integer"),
+		applies("max", pc.Max != nil, "number", "integer"),
+		applies("pattern", pc.Pattern != "", "string"),
+		applies("minLength", pc.MinLength != nil, "string", "array"),
+		applies("maxLength", pc.MaxLength != nil, "string", "array"),
+		applies("items", pc.Items != nil, "array"),
+		applies("properties", len(pc.Properties) > 0, "object"),
+		applies("required", len(pc.Required) > 0, "object"),
+	); err != nil {
+		return err
+	}
+	if pc.Min != nil && pc.Max != nil && *pc.Min > *pc.Max {
+		return fmt.Errorf("min %v is above max %v", *pc.Min, *pc.Max)
+	}
+	if pc.MinLength != nil && *pc.MinLength < 0 || pc.MaxLength != nil && *pc.MaxLength < 0 {
+		return fmt.Errorf("minLength and maxLength must not be negative")
+	}
+	if pc.MinLength != nil && pc.MaxLength != nil && *pc.MinLength > *pc.MaxLength {
+		return fmt.Errorf("minLength %d is above maxLength %d", *pc.MinLength, *pc.MaxLength)
+	}
+	if pc.Pattern != "" {
+		if _, err := compilePattern(pc.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	if pc.Items != nil {
+		if err := pc.Items.Validate(); err != nil {
+			return fmt.Errorf("items: %w", err)
+		}
+	}
+	names := make([]string, 0, len(pc.Properties))
+	for name := range pc.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := pc.Properties[name].Validate(); err != nil {
+			return fmt.Errorf("property %s: %w", name, err)
+		}
+	}
+	for _, name := range pc.Required {
+		if _, ok := pc.Properties[name]; !ok && len(pc.Properties) > 0 {
+			return fmt.Errorf("required property %s is not declared in properties", name)
+		}
+	}
+	if pc.Default != nil {
+		if err := pc.Check(ValueToProto(pc.Default)); err != nil {
+			return fmt.Errorf("default %v: %w", pc.Default, err)
+		}
+	}
+	return nil
+}
+
+// ValueToProto converts a value decoded from YAML to protobuf format; nil and
+// values of other types convert to nil
+func ValueToProto(v interface{}) *nfa_intent_v1alpha.Value {
+	switch v := v.(type) {
+	case string:
+		return &nfa_intent_v1alpha.Value{Value: &nfa_intent_v1alpha.Value_StringValue{StringValue: v}}
+	case bool:
+		return &nfa_intent_v1alpha.Value{Value: &nfa_intent_v1alpha.Value_BoolValue{BoolValue: v}}
+	case int:
+		return &nfa_intent_v1alpha.Value{Value: &nfa_intent_v1alpha.Value_NumberValue{NumberValue: float64(v)}}
+	case float64:
+		return &nfa_intent_v1alpha.Value{Value: &nfa_intent_v1alpha.Value_NumberValue{NumberValue: v}}
+	case []interface{}:
+		list := &nfa_intent_v1alpha.ListValue{}
+		for _, item := range v {
+			if value := ValueToProto(item); value != nil {
+				list.Values = append(list.Values, value)
+			}
+		}
+		return &nfa_intent_v1alpha.Value{Value: &nfa_intent_v1alpha.Value_ListValue{ListValue: list}}
+	case map[string]interface{}:
+		fields := make(map[string]*nfa_intent_v1alpha.Value, len(v))
+		for key, item := range v {
+			if value := ValueToProto(item); value != nil {
+				fields[key] = value
+			}
+		}
+		return &nfa_intent_v1alpha.Value{Value: &nfa_intent_v1alpha.Value_StructValue{StructValue: &nfa_intent_v1alpha.StructValue{Fields: fields}}}
+	}
+	return nil
+}
+
+// Sensitivity classifies parameter values that must not leave the service
+// receiving them in clear text; empty means the value is not sensitive
+type Sensitivity string
+
+const (
+	// SensitivityPII marks personally identifiable information such as
+	// email addresses, phone numbers or locations
+	SensitivityPII Sensitivity = "pii"
+	// SensitivitySensitive marks credentials and other confidential values
+	SensitivitySensitive Sensitivity = "sensitive"
+)
+
+// Validate checks that the sensitivity is one of the known classes
+func (s Sensitivity) Validate() error {
+	switch s {
+	case "", SensitivityPII, SensitivitySensitive:
+		return nil
+	}
+	return fmt.Errorf("unknown sensitivity %q, expected pii or sensitive", s)
+}
+
+// ToProto converts the sensitivity to its protobuf enum
+func (s Sensitivity) ToProto() nfa_intent_v1alpha.Sensitivity {
+	switch s {
+	case SensitivityPII:
+		return nfa_intent_v1alpha.Sensitivity_SENSITIVITY_PII
+	case SensitivitySensitive:
+		return nfa_intent_v1alpha.Sensitivity_SENSITIVITY_SENSITIVE
+	default:
+		return nfa_intent_v1alpha.Sensitivity_SENSITIVITY_UNSPECIFIED
+	}
+}
+
+// SensitivityFromProto converts a protobuf sensitivity; unknown values are
+// treated as sensitive so that newer classes are never logged in clear text
+func SensitivityFromProto(s nfa_intent_v1alpha.Sensitivity) Sensitivity {
+	switch s {
+	case nfa_intent_v1alpha.Sensitivity_SENSITIVITY_UNSPECIFIED:
+		return ""
+	case nfa_intent_v1alpha.Sensitivity_SENSITIVITY_PII:
+		return SensitivityPII
+	default:
+		return SensitivitySensitive
+	}
+}
+
+type Implementation struct {
+	Endpoint  Endpoint              `yaml:"endpoint"`
+	Resources []ResourceRequirement `yaml:"resources,omitempty"`
+}
+
+// ToProto converts the implementation to protobuf format
+func (i Implementation) ToProto() *nfa_intent_v1alpha.Implementation {
+	out := &nfa_intent_v1alpha.Implementation{Endpoint: i.Endpoint.ToProto()}
+	for _, r := range i.Resources {
+		out.Resources = append(out.Resources, &nfa_intent_v1alpha.ResourceRequirement{
+			Type:  r.Type,
+			Units: r.Units,
+			Kind:  r.Kind,
+		})
+	}
+	return out
+}
+
+type Endpoint struct {
+	Type string `yaml:"type"`
+	// Host is the fixed host of a provider that does not register itself,
+	// e.g. a static provider; see broker.Embedded.LoadStatic. Besides an
+	// address it may be a name that package endpoint resolves.
+	Host      string `yaml:"host,omitempty"`
+	Port      *int   `yaml:"port,omitempty"`
+	Procedure string `yaml:"procedure,omitempty"`
+	URL       string `yaml:"url,omitempty"`
+	// Codec names the serialization of the messages of requests to the
+	// endpoint by its gRPC content subtype, e.g. CodecJSON for a provider
+	// built around a JSON API; empty means protobuf. Consumers dialing the
+	// endpoint with runtime.ResolvingDialer use it.
+	Codec string `yaml:"codec,omitempty"`
+	// Exec is the local command of an "exec" endpoint, run by the runtime
+	// for each intent; see runtime.ExecHandler. It stays on the device:
+	// ToProto leaves it out, so the broker never learns it.
+	Exec *ExecCommand `yaml:"exec,omitempty"`
+}
+
+// Codecs of endpoints built into the runtime, see Endpoint.Codec
+const (
+	CodecProto   = "proto"
+	CodecJSON    = "json"
+	CodecMsgpack = "msgpack"
+)
+
+// ToProto converts the endpoint to protobuf format. The host, port and
+// procedure become a gRPC address and the URL an HTTP address; a gRPC address
+// is preferred when both are set.
+func (e Endpoint) ToProto() *nfa_intent_v1alpha.Endpoint {
+	out := &nfa_intent_v1alpha.Endpoint{Type: e.Type, Codec: e.Codec}
+	switch {
+	case e.Host != "" || e.Port != nil || e.Procedure != "":
+		grpc := &nfa_intent_v1alpha.GrpcAddress{Host: e.Host, Procedure: e.Procedure}
+		if e.Port != nil {
+			grpc.Port = uint32(*e.Port)
+		}
+		out.Address = &nfa_intent_v1alpha.Endpoint_Grpc{Grpc: grpc}
+	case e.URL != "":
+		out.Address = &nfa_intent_v1alpha.Endpoint_Http{Http: &nfa_intent_v1alpha.HttpAddress{Url: e.URL}}
+	}
+	return out
+}
+
+type ResourceRequirement struct {
+	Type  string `yaml:"type"`
+	Units string `yaml:"units"`
+	Kind  string `yaml:"kind,omitempty"`
+}
+
+type QualityOfService struct {
+	Latency      string `yaml:"latency,omitempty"`
+	Availability string `yaml:"availability,omitempty"`
+	Priority     string `yaml:"priority,omitempty"`
+}
+
+// ToProto converts the QoS to protobuf format
+func (q *QualityOfService) ToProto() *nfa_intent_v1alpha.QualityOfService {
+	if q == nil {
+		return nil
+	}
+	return &nfa_intent_v1alpha.QualityOfService{
+		Latency:      q.Latency,
+		Availability: q.Availability,
+		Priority:     q.Priority,
+	}
+}
+
+// ParseIntentContract parses YAML data into an IntentContract. JSON is
+// accepted too, being YAML; ParseIntentContractJSON checks it is JSON. Keys
+// the schema does not define are rejected; see ParseOptions to allow them.
+func ParseIntentContract(data []byte) (*IntentContract, error) {
+	return ParseOptions{}.Parse(data)
+}
+
+// MarshalYAML renders a contract as YAML that ParseIntentContract reads
+// back, e.g. to write out a contract fetched from the broker with FromProto.
+// Maps are rendered with sorted keys, so the output diffs cleanly against
+// the source file. The contract is written in the schema of its version.
+func MarshalYAML(c *IntentContract) ([]byte, error) {
+	var doc interface{} = c
+	if c.Version == VersionV1Beta {
+		doc = ToV1Beta(c)
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to marshal contract: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to marshal contract: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// LoadFile reads and parses an intent contract file, as JSON when its
+// extension is .json and as YAML otherwise
+func LoadFile(path string) (*IntentContract, error) {
+	return ParseOptions{}.LoadFile(path)
+}
+
+// ToProto converts the internal contract to protobuf format
+func (c *IntentContract) ToProto() *nfa_intent_v1alpha.IntentContract {
+	spec := &nfa_intent_v1alpha.IntentSpec{
+		Implementation:   c.Spec.Implementation.ToProto(),
+		QualityOfService: c.Spec.QualityOfService.ToProto(),
+	}
+	for _, p := range c.Spec.IntentPatterns {
+		spec.IntentPatterns = append(spec.IntentPatterns, p.ToProto())
+	}
+	return &nfa_intent_v1alpha.IntentContract{
+		Version: c.Version,
+		Kind:    c.Kind,
+		Metadata: &nfa_intent_v1alpha.Metadata{
+			Name:        c.Metadata.Name,
+			Description: c.Metadata.Description,
+			Labels:      c.Metadata.Labels,
+		},
+		Spec: spec,
+	}
+}
+
+// Validate checks if the contract is valid
+func (c *IntentContract) Validate() error {
+	if !slices.Contains(Versions, c.Version) {
+		return fmt.Errorf("%w: unsupported version: %s", ErrInvalid, c.Version)
+	}
+	if c.Kind != "IntentContract" {
+		return fmt.Errorf("%w: invalid kind: %s", ErrInvalid, c.Kind)
+	}
+	if c.Metadata.Name == "" {
+		return fmt.Errorf("%w: metadata name is required", ErrInvalid)
+	}
+	if len(c.Spec.IntentPatterns) == 0 {
+		return fmt.Errorf("%w: at least one intent pattern is required", ErrInvalid)
+	}
+	examples := make(map[string]bool)
+	actions := make(map[string]bool, len(c.Spec.IntentPatterns))
+	for _, p := range c.Spec.IntentPatterns {
+		actions[p.Pattern.Action] = true
+	}
+	for i, p := range c.Spec.IntentPatterns {
+		for _, alias := range p.Aliases {
+			if alias == "" || actions[alias] {
+				return fmt.Errorf("%w: intent pattern %d (%s): alias %q must be a former action name, not empty or an action of the contract", ErrInvalid, i, p.Pattern.Action, alias)
+			}
+		}
+		if err := p.Streaming.Validate(); err != nil {
+			return fmt.Errorf("%w: intent pattern %d (%s): %w", ErrInvalid, i, p.Pattern.Action, err)
+		}
+		if p.Classification != nil {
+			if err := p.Classification.Validate(); err != nil {
+				return fmt.Errorf("%w: intent pattern %d (%s) classification: %w", ErrInvalid, i, p.Pattern.Action, err)
+			}
+		}
+		for _, m := range p.Mappings {
+			if err := m.Validate(); err != nil {
+				return fmt.Errorf("%w: intent pattern %d (%s): %w", ErrInvalid, i, p.Pattern.Action, err)
+			}
+		}
+		if p.Results != nil {
+			if err := p.Results.Validate(); err != nil {
+				return fmt.Errorf("%w: intent pattern %d (%s) results: %w", ErrInvalid, i, p.Pattern.Action, err)
+			}
+		}
+		if p.Constraints != nil {
+			for name, pc := range p.Constraints.ParameterConstraints {
+				if err := pc.Validate(); err != nil {
+					return fmt.Errorf("%w: intent pattern %d (%s), parameter %s: %w", ErrInvalid, i, p.Pattern.Action, name, err)
+				}
+			}
+		}
+		// Examples are checked against the constraints, so last
+		if err := c.validateExamples(p, examples); err != nil {
+			return fmt.Errorf("%w: intent pattern %d (%s): %w", ErrInvalid, i, p.Pattern.Action, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,1322 @@
+package contract
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+const fullContract = `
+version: v1alpha
+kind: IntentContract
+metadata:
+  name: translator
+  description: Translates text
+  labels:
+    team: language
+spec:
+  intentPatterns:
+    - pattern:
+        action: translate_text
+        domain: language
+        priority: 2
+        fast: true
+        tags: [mt, text]
+        options:
+          formal: false
+      constraints:
+        requiredParameters: [text, target_language]
+        parameterConstraints:
+          text:
+            type: string
+            sensitivity: pii
+          target_language:
+            enumValues: [en, zh, fr]
+            default: en
+          max_length:
+            type: integer
+            min: 1
+            max: 5000
+      streaming: server
+      classification:
+        residency: region
+        regions: [eu-west]
+      aliases: [translate]
+  implementation:
+    endpoint:
+      type: grpc
+      port: 50052
+      procedure: TranslateText
+    resources:
+      - type: cpu
+        units: "2"
+      - type: accelerator
+        units: "1"
+        kind: npu
+  qualityOfService:
+    latency: "<=150ms"
+    availability: "99.5%"
+    priority: high
+`
+
+func number(v float64) *nfa_intent_v1alpha.Value {
+	return &nfa_intent_v1alpha.Value{Value: &nfa_intent_v1alpha.Value_NumberValue{NumberValue: v}}
+}
+
+func str(v string) *nfa_intent_v1alpha.Value {
+	return &nfa_intent_v1alpha.Value{Value: &nfa_intent_v1alpha.Value_StringValue{StringValue: v}}
+}
+
+func boolean(v bool) *nfa_intent_v1alpha.Value {
+	return &nfa_intent_v1alpha.Value{Value: &nfa_intent_v1alpha.Value_BoolValue{BoolValue: v}}
+}
+
+func TestToProtoRoundTrip(t *testing.T) {
+	c, err := ParseIntentContract([]byte(fullContract))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	min, max := 1.0, 5000.0
+	want := &nfa_intent_v1alpha.IntentContract{
+		Version: "v1alpha",
+		Kind:    "IntentContract",
+		Metadata: &nfa_intent_v1alpha.Metadata{
+			Name:        "translator",
+			Description: "Translates text",
+			Labels:      map[string]string{"team": "language"},
+		},
+		Spec: &nfa_intent_v1alpha.IntentSpec{
+			IntentPatterns: []*nfa_intent_v1alpha.IntentPattern{{
+				Pattern: &nfa_intent_v1alpha.IntentPattern_Pattern{
+					Action: "translate_text",
+					Parameters: map[string]*nfa_intent_v1alpha.Value{
+						"domain":   str("language"),
+						"priority": number(2),
+						"fast":     boolean(true),
+						"tags": {Value: &nfa_intent_v1alpha.Value_ListValue{ListValue: &nfa_intent_v1alpha.ListValue{
+							Values: []*nfa_intent_v1alpha.Value{str("mt"), str("text")},
+						}}},
+						"options": {Value: &nfa_intent_v1alpha.Value_StructValue{StructValue: &nfa_intent_v1alpha.StructValue{
+							Fields: map[string]*nfa_intent_v1alpha.Value{"formal": boolean(false)},
+						}}},
+					},
+				},
+				Constraints: &nfa_intent_v1alpha.IntentPattern_Constraints{
+					RequiredParameters: []string{"text", "target_language"},
+					ParameterConstraints: map[string]*nfa_intent_v1alpha.ParameterConstraint{
+						"text": {
+							Constraint:  &nfa_intent_v1alpha.ParameterConstraint_StringConstraint{StringConstraint: &nfa_intent_v1alpha.StringConstraint{}},
+							Sensitivity: nfa_intent_v1alpha.Sensitivity_SENSITIVITY_PII,
+						},
+						"target_language": {
+							Constraint:   &nfa_intent_v1alpha.ParameterConstraint_EnumConstraint{EnumConstraint: &nfa_intent_v1alpha.EnumConstraint{Values: []string{"en", "zh", "fr"}}},
+							DefaultValue: str("en"),
+						},
+						"max_length": {
+							Constraint: &nfa_intent_v1alpha.ParameterConstraint_NumberConstraint{NumberConstraint: &nfa_intent_v1alpha.NumberConstraint{Min: &min, Max: &max}},
+						},
+					},
+				},
+				Streaming: nfa_intent_v1alpha.StreamingMode_STREAMING_MODE_SERVER_STREAM,
+				Classification: &nfa_intent_v1alpha.DataClassification{
+					Residency: nfa_intent_v1alpha.Residency_RESIDENCY_REGION,
+					Regions:   []string{"eu-west"},
+				},
+				Aliases: []string{"translate"},
+			}},
+			Implementation: &nfa_intent_v1alpha.Implementation{
+				Endpoint: &nfa_intent_v1alpha.Endpoint{
+					Type:    "grpc",
+					Address: &nfa_intent_v1alpha.Endpoint_Grpc{Grpc: &nfa_intent_v1alpha.GrpcAddress{Port: 50052, Procedure: "TranslateText"}},
+				},
+				Resources: []*nfa_intent_v1alpha.ResourceRequirement{
+					{Type: "cpu", Units: "2"},
+					{Type: "accelerator", Units: "1", Kind: "npu"},
+				},
+			},
+			QualityOfService: &nfa_intent_v1alpha.QualityOfService{
+				Latency:      "<=150ms",
+				Availability: "99.5%",
+				Priority:     "high",
+			},
+		},
+	}
+
+	got := c.ToProto()
+	if !proto.Equal(got, want) {
+		t.Fatalf("ToProto() = %v\nwant %v", got, want)
+	}
+
+	// The broker receives the contract over the wire
+	data, err := proto.Marshal(got)
+	if err != nil {
+		t.Fatalf("proto.Marshal() error = %v", err)
+	}
+	decoded := &nfa_intent_v1alpha.IntentContract{}
+	if err := proto.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("proto.Unmarshal() error = %v", err)
+	}
+	if !proto.Equal(decoded, want) {
+		t.Errorf("decoded contract = %v\nwant %v", decoded, want)
+	}
+}
+
+func TestToProtoOptionalFields(t *testing.T) {
+	c, err := ParseIntentContract([]byte(`
+version: v1alpha
+kind: IntentContract
+metadata:
+  name: webhook
+spec:
+  intentPatterns:
+    - pattern:
+        action: notify
+  implementation:
+    endpoint:
+      type: http
+      url: https://example.com/notify
+`))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+
+	got := c.ToProto()
+	p := got.GetSpec().GetIntentPatterns()
+	if len(p) != 1 || p[0].GetPattern().GetAction() != "notify" {
+		t.Fatalf("intent patterns = %v, want one for notify", p)
+	}
+	if p[0].Constraints != nil || p[0].Classification != nil || p[0].Pattern.Parameters != nil {
+		t.Errorf("pattern = %v, want no constraints, classification or parameters", p[0])
+	}
+	if p[0].Streaming != nfa_intent_v1alpha.StreamingMode_STREAMING_MODE_UNARY {
+		t.Errorf("streaming = %v, want unary", p[0].Streaming)
+	}
+	if got.Spec.QualityOfService != nil {
+		t.Errorf("quality of service = %v, want nil", got.Spec.QualityOfService)
+	}
+	if url := got.Spec.GetImplementation().GetEndpoint().GetHttp().GetUrl(); url != "https://example.com/notify" {
+		t.Errorf("endpoint URL = %q, want https://example.com/notify", url)
+	}
+}
+
+func TestFromProtoRoundTrip(t *testing.T) {
+	c, err := ParseIntentContract([]byte(fullContract))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	want := c.ToProto()
+
+	back, err := FromProto(want)
+	if err != nil {
+		t.Fatalf("FromProto() error = %v", err)
+	}
+	if err := back.Validate(); err != nil {
+		t.Errorf("Validate() of converted contract error = %v", err)
+	}
+	if got := back.ToProto(); !proto.Equal(got, want) {
+		t.Errorf("ToProto(FromProto()) = %v\nwant %v", got, want)
+	}
+
+	// Exported YAML parses back to the registered contract
+	data, err := MarshalYAML(back)
+	if err != nil {
+		t.Fatalf("MarshalYAML() error = %v", err)
+	}
+	parsed, err := ParseIntentContract(data)
+	if err != nil {
+		t.Fatalf("ParseIntentContract() of marshalled contract error = %v\n%s", err, data)
+	}
+	if got := parsed.ToProto(); !proto.Equal(got, want) {
+		t.Errorf("contract parsed from\n%s\n= %v\nwant %v", data, got, want)
+	}
+}
+
+func TestFromProtoUnknownEnum(t *testing.T) {
+	pb := &nfa_intent_v1alpha.IntentContract{
+		Metadata: &nfa_intent_v1alpha.Metadata{Name: "future"},
+		Spec: &nfa_intent_v1alpha.IntentSpec{
+			IntentPatterns: []*nfa_intent_v1alpha.IntentPattern{{
+				Pattern:   &nfa_intent_v1alpha.IntentPattern_Pattern{Action: "stream"},
+				Streaming: nfa_intent_v1alpha.StreamingMode(42),
+			}},
+		},
+	}
+	if _, err := FromProto(pb); !errors.Is(err, ErrInvalid) {
+		t.Errorf("FromProto() error = %v, want ErrInvalid", err)
+	}
+}
+
+// fieldErrors collects the field errors an error wraps
+func fieldErrors(err error) []*FieldError {
+	if fieldErr, ok := err.(*FieldError); ok {
+		return []*FieldError{fieldErr}
+	}
+	var errs []*FieldError
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, fieldErrors(e)...)
+		}
+	}
+	return errs
+}
+
+func TestParseRejectsUnknownFields(t *testing.T) {
+	_, err := ParseIntentContract([]byte(`
+version: v1alpha
+kind: IntentContract
+metadata:
+  name: translator
+spec:
+  intentPattern:
+    - pattern:
+        action: translate_text
+  intentPatterns:
+    - pattern:
+        action: translate_text
+        constraints:
+          requiredParameters: [text]
+      requiredParameters: [text]
+`))
+	if !errors.Is(err, ErrInvalid) {
+		t.Fatalf("ParseIntentContract() error = %v, want ErrInvalid", err)
+	}
+
+	fieldErrs := fieldErrors(err)
+	want := []FieldError{
+		{Line: 7, Column: 3, Path: "spec", Key: "intentPattern", Hint: `did you mean "intentPatterns"?`},
+		{Line: 13, Column: 9, Path: "spec.intentPatterns[0].pattern", Key: "constraints", Hint: "it belongs one level up; check its indentation"},
+		{Line: 15, Column: 7, Path: "spec.intentPatterns[0]", Key: "requiredParameters", Hint: "it belongs in spec.intentPatterns[].constraints"},
+	}
+	if len(fieldErrs) != len(want) {
+		t.Fatalf("field errors = %v, want %d", err, len(want))
+	}
+	for i, got := range fieldErrs {
+		if *got != want[i] {
+			t.Errorf("field error %d = %+v, want %+v", i, *got, want[i])
+		}
+	}
+}
+
+func TestParseAllowUnknownFields(t *testing.T) {
+	data := []byte(`
+version: v1alpha
+kind: IntentContract
+metadata:
+  name: translator
+  owner: language-team
+spec:
+  intentPatterns:
+    - pattern:
+        action: translate_text
+        domain: language
+`)
+	if _, err := ParseIntentContract(data); err == nil {
+		t.Errorf("ParseIntentContract() accepted an unknown key")
+	}
+	c, err := ParseOptions{AllowUnknownFields: true}.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() allowing unknown fields error = %v", err)
+	}
+	if c.Metadata.Name != "translator" || c.Spec.IntentPatterns[0].Pattern.Parameters["domain"] != "language" {
+		t.Errorf("Parse() = %+v, want the known fields and pattern parameters", c)
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal([]byte(fullContract), &doc); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+	data, err := json.MarshalIndent(doc, "", "\t")
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	fromYAML, err := ParseIntentContract([]byte(fullContract))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	want := fromYAML.ToProto()
+
+	c, err := ParseIntentContractJSON(data)
+	if err != nil {
+		t.Fatalf("ParseIntentContractJSON() error = %v", err)
+	}
+	if got := c.ToProto(); !proto.Equal(got, want) {
+		t.Errorf("JSON contract = %v\nwant %v", got, want)
+	}
+
+	// Files are parsed by their extension
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "translator.intent.json")
+	if err := os.WriteFile(jsonPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := LoadFile(jsonPath); err != nil || !proto.Equal(c.ToProto(), want) {
+		t.Errorf("LoadFile(%s) = %v, %v, want the contract", jsonPath, c, err)
+	}
+	yamlAsJSON := filepath.Join(dir, "yaml.json")
+	if err := os.WriteFile(yamlAsJSON, []byte(fullContract), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(yamlAsJSON); !errors.Is(err, ErrInvalid) {
+		t.Errorf("LoadFile() of YAML named .json error = %v, want ErrInvalid", err)
+	}
+}
+
+func TestParseJSONErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name, data, want string
+	}{
+		{"syntax", "{\n  \"version\": \"v1alpha\",\n  \"kind\" \"IntentContract\"\n}", "line 3, column 10"},
+		{"not an object", `["v1alpha"]`, "must be an object"},
+		{"unknown key", "{\n  \"version\": \"v1alpha\",\n  \"metdata\": {}\n}", `line 3, column 3: unknown key "metdata"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseIntentContractJSON([]byte(tc.data))
+			if !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("ParseIntentContractJSON() error = %v, want ErrInvalid mentioning %q", err, tc.want)
+			}
+		})
+	}
+}
+
+const constraintsContract = `
+version: v1alpha
+kind: IntentContract
+metadata:
+  name: scheduler
+spec:
+  intentPatterns:
+    - pattern:
+        action: schedule_meeting
+      constraints:
+        requiredParameters: [title, attendees, slot]
+        parameterConstraints:
+          title:
+            type: string
+            minLength: 1
+            maxLength: 20
+          room:
+            pattern: "^[A-Z][0-9]{3}$"
+          attendees:
+            type: array
+            minLength: 1
+            maxLength: 3
+            items:
+              type: string
+              pattern: "^[^@]+@[^@]+$"
+          slot:
+            type: object
+            required: [start, minutes]
+            properties:
+              start:
+                type: string
+              minutes:
+                type: integer
+                min: 15
+                default: 30
+              remote:
+                type: boolean
+  implementation:
+    endpoint:
+      type: grpc
+      port: 50052
+`
+
+func list(values ...*nfa_intent_v1alpha.Value) *nfa_intent_v1alpha.Value {
+	return &nfa_intent_v1alpha.Value{Value: &nfa_intent_v1alpha.Value_ListValue{ListValue: &nfa_intent_v1alpha.ListValue{Values: values}}}
+}
+
+func object(fields map[string]*nfa_intent_v1alpha.Value) *nfa_intent_v1alpha.Value {
+	return &nfa_intent_v1alpha.Value{Value: &nfa_intent_v1alpha.Value_StructValue{StructValue: &nfa_intent_v1alpha.StructValue{Fields: fields}}}
+}
+
+func TestAdmitNestedConstraints(t *testing.T) {
+	c, err := ParseIntentContract([]byte(constraintsContract))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	valid := func() map[string]*nfa_intent_v1alpha.Value {
+		return map[string]*nfa_intent_v1alpha.Value{
+			"title":     str("Planning"),
+			"room":      str("B204"),
+			"attendees": list(str("ana@example.com"), str("li@example.com")),
+			"slot":      object(map[string]*nfa_intent_v1alpha.Value{"start": str("09:00")}),
+		}
+	}
+	for _, tc := range []struct {
+		name   string
+		change func(map[string]*nfa_intent_v1alpha.Value)
+		want   string
+	}{
+		{"valid", func(map[string]*nfa_intent_v1alpha.Value) {}, ""},
+		{"empty title", func(p map[string]*nfa_intent_v1alpha.Value) { p["title"] = str("") }, "length 0 is below the minimum 1"},
+		{"long title", func(p map[string]*nfa_intent_v1alpha.Value) { p["title"] = str("Quarterly planning review") }, "length 25 is above the maximum 20"},
+		{"title of characters", func(p map[string]*nfa_intent_v1alpha.Value) {
+			p["title"] = str("会议会议会议会议会议会议会议会议会议会议")
+		}, ""},
+		{"room not matching", func(p map[string]*nfa_intent_v1alpha.Value) { p["room"] = str("b204") }, "does not match the pattern"},
+		{"no attendees", func(p map[string]*nfa_intent_v1alpha.Value) { p["attendees"] = list() }, "length 0 is below the minimum 1"},
+		{"invalid attendee", func(p map[string]*nfa_intent_v1alpha.Value) { p["attendees"] = list(str("ana@example.com"), str("li")) }, "item 1: \"li\" does not match"},
+		{"attendees not an array", func(p map[string]*nfa_intent_v1alpha.Value) { p["attendees"] = str("ana@example.com") }, "expected an array"},
+		{"missing start", func(p map[string]*nfa_intent_v1alpha.Value) {
+			p["slot"] = object(map[string]*nfa_intent_v1alpha.Value{"minutes": number(45)})
+		}, "property start is required"},
+		{"short slot", func(p map[string]*nfa_intent_v1alpha.Value) {
+			p["slot"] = object(map[string]*nfa_intent_v1alpha.Value{"start": str("09:00"), "minutes": number(5)})
+		}, "property minutes: 5 is below the minimum 15"},
+		{"undeclared property", func(p map[string]*nfa_intent_v1alpha.Value) {
+			p["slot"] = object(map[string]*nfa_intent_v1alpha.Value{"start": str("09:00"), "note": number(1)})
+		}, ""},
+	} {
+		params := valid()
+		tc.change(params)
+		err := c.Admit("schedule_meeting", params)
+		switch {
+		case tc.want == "" && err != nil:
+			t.Errorf("%s: Admit() error = %v", tc.name, err)
+		case tc.want != "" && (err == nil || !strings.Contains(err.Error(), tc.want)):
+			t.Errorf("%s: Admit() error = %v, want %q", tc.name, err, tc.want)
+		case tc.want != "" && !errors.Is(err, ErrConstraintViolated):
+			t.Errorf("%s: Admit() error = %v, want ErrConstraintViolated", tc.name, err)
+		}
+	}
+}
+
+func TestNestedConstraintsRoundTrip(t *testing.T) {
+	c, err := ParseIntentContract([]byte(constraintsContract))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	want := c.ToProto()
+	back, err := FromProto(want)
+	if err != nil {
+		t.Fatalf("FromProto() error = %v", err)
+	}
+	if err := back.Validate(); err != nil {
+		t.Errorf("Validate() of converted contract error = %v", err)
+	}
+	if got := back.ToProto(); !proto.Equal(got, want) {
+		t.Errorf("ToProto(FromProto()) = %v\nwant %v", got, want)
+	}
+}
+
+func TestValidateParameterConstraints(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		constraint string
+		want       string
+	}{
+		{"min above max", "{type: number, min: 10, max: 1}", "min 10 is above max 1"},
+		{"minLength above maxLength", "{type: string, minLength: 5, maxLength: 2}", "minLength 5 is above maxLength 2"},
+		{"negative length", "{type: string, maxLength: -1}", "minLength and maxLength must not be negative"},
+		{"invalid pattern", `{type: string, pattern: "([a-z"}`, "invalid pattern"},
+		{"unknown type", "{type: text}", `unknown type "text"`},
+		{"range of a string", "{type: string, min: 1}", "min does not apply to type string"},
+		{"items of a string", "{type: string, items: {type: string}}", "items does not apply to type string"},
+		{"invalid items", "{type: array, items: {type: integer, min: 3, max: 2}}", "items: min 3 is above max 2"},
+		{"invalid property", "{type: object, properties: {n: {pattern: '*'}}}", "property n: invalid pattern"},
+		{"undeclared required property", "{type: object, required: [b], properties: {a: {type: string}}}", "required property b is not declared"},
+		{"default outside range", "{type: integer, min: 1, default: 0}", "default 0: 0 is below the minimum 1"},
+		{"default of the wrong type", "{type: array, default: none}", "default none: expected an array"},
+	} {
+		data := strings.Replace(constraintsContract, "          title:\n", "          broken: "+tc.constraint+"\n          title:\n", 1)
+		c, err := ParseIntentContract([]byte(data))
+		if err != nil {
+			t.Fatalf("%s: ParseIntentContract() error = %v", tc.name, err)
+		}
+		err = c.Validate()
+		if err == nil || !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), "parameter broken: "+tc.want) {
+			t.Errorf("%s: Validate() error = %v, want %q", tc.name, err, tc.want)
+		}
+	}
+}
+
+const lintContract = `
+version: v1alpha
+kind: IntentContract
+metadata:
+  name: speaker
+spec:
+  intentPatterns:
+    - pattern:
+        action: speak
+        text: "@text"
+    - pattern:
+        action: speak
+      constraints:
+        requiredParameters: [text, voice, rate]
+        parameterConstraints:
+          rate:
+            type: number
+            default: 1
+  implementation:
+    endpoint:
+      type: http
+      url: http://speaker:8080/speak
+      port: 9090
+  qualityOfService:
+    latency: 10sm
+    availability: "99.5"
+    priority: urgent
+`
+
+func TestLint(t *testing.T) {
+	c, err := ParseIntentContract([]byte(lintContract))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	findings, err := Lint(c)
+	if err != nil {
+		t.Fatalf("Lint() error = %v", err)
+	}
+	var got []string
+	for _, f := range findings {
+		got = append(got, string(f.Severity)+" "+f.Rule+" "+f.Path)
+	}
+	want := []string{
+		"warning missing-description metadata.description",
+		"warning unreachable-pattern spec.intentPatterns[1]",
+		"warning unused-required-parameter spec.intentPatterns[1].constraints.requiredParameters[0]",
+		"warning unused-required-parameter spec.intentPatterns[1].constraints.requiredParameters[1]",
+		"warning required-with-default spec.intentPatterns[1].constraints.requiredParameters[2]",
+		"error suspicious-qos spec.qualityOfService.latency",
+		"error suspicious-qos spec.qualityOfService.availability",
+		"warning suspicious-qos spec.qualityOfService.priority",
+		"error endpoint-mismatch spec.implementation.endpoint",
+		"error endpoint-mismatch spec.implementation.endpoint.port",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Lint() findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	c, err = ParseIntentContract([]byte(fullContract))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	findings, err = Lint(c)
+	if err != nil || len(findings) != 1 || findings[0].Rule != RuleRequiredDefault {
+		t.Errorf("Lint(fullContract) = %v, %v, want only the default of target_language", findings, err)
+	}
+}
+
+const execContract = `
+version: v1alpha
+kind: IntentContract
+metadata:
+  name: lights
+  description: Switches the lights of a room
+spec:
+  intentPatterns:
+    - pattern:
+        action: lights_on
+      constraints:
+        requiredParameters: [room]
+        parameterConstraints:
+          room: {type: string}
+  implementation:
+    endpoint:
+      type: exec
+      port: 9090
+      exec:
+        command: bin/lights
+        args: [--room, "{room}", "--level={level}"]
+        stdin: body
+        timeout: 5
+`
+
+func TestLintExec(t *testing.T) {
+	c, err := ParseIntentContract([]byte(execContract))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	findings, err := Lint(c)
+	if err != nil {
+		t.Fatalf("Lint() error = %v", err)
+	}
+	var got []string
+	for _, f := range findings {
+		got = append(got, string(f.Severity)+" "+f.Path)
+	}
+	want := []string{
+		"warning spec.implementation.endpoint",
+		"error spec.implementation.endpoint.exec.command",
+		"error spec.implementation.endpoint.exec.stdin",
+		"error spec.implementation.endpoint.exec.timeout",
+		"warning spec.implementation.endpoint.exec.args",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Lint() findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	exec := c.Spec.Implementation.Endpoint.Exec
+	if got := exec.Placeholders(); !slices.Equal(got, []string{"room", "level"}) {
+		t.Errorf("Placeholders() = %v, want room and level", got)
+	}
+	args, err := exec.ExpandArgs(map[string]string{"room": "kitchen", "level": "-20"})
+	if err != nil || !slices.Equal(args, []string{"--room", "kitchen", "--level=-20"}) {
+		t.Errorf("ExpandArgs() = %v, %v, want the room and level", args, err)
+	}
+	for _, level := range []string{"-0.5", "-7"} {
+		if _, err := exec.ExpandArgs(map[string]string{"room": "kitchen", "level": level}); err != nil {
+			t.Errorf("ExpandArgs() with level %s error = %v, want the negative number accepted", level, err)
+		}
+	}
+	for _, params := range []map[string]string{
+		{"room": "kitchen"},
+		{"room": "--all", "level": "1"},
+		// ParseFloat would take these for numbers
+		{"room": "-inf", "level": "1"},
+		{"room": "kitchen", "level": "-Infinity"},
+		{"room": "kitchen", "level": "-NaN"},
+		{"room": "kitchen", "level": "-1e5"},
+		{"room": "kitchen", "level": "-0x10"},
+		{"room": "kitchen", "level": "-1."},
+	} {
+		if _, err := exec.ExpandArgs(params); err == nil {
+			t.Errorf("ExpandArgs(%v) succeeded, want an error", params)
+		}
+	}
+}
+
+const betaContract = `
+version: v1beta
+kind: IntentContract
+metadata:
+  name: translator
+spec:
+  intents:
+    - action: translate_text
+      match:
+        domain: language
+      parameters:
+        text: {type: string, required: true, sensitivity: pii}
+        target_language: {enum: [en, zh, fr], default: en}
+        source_language: {required: true}
+      streaming: server
+  implementation:
+    endpoint: {type: grpc, port: 50052}
+  qos:
+    latency: 150ms
+`
+
+// sortRequired sorts the lists of required names, whose order v1beta does
+// not keep
+func sortRequired(c *IntentContract) {
+	var sortConstraint func(pc *ParameterConstraint)
+	sortConstraint = func(pc *ParameterConstraint) {
+		slices.Sort(pc.Required)
+		for name, property := range pc.Properties {
+			sortConstraint(&property)
+			pc.Properties[name] = property
+		}
+	}
+	for _, p := range c.Spec.IntentPatterns {
+		if p.Constraints == nil {
+			continue
+		}
+		slices.Sort(p.Constraints.RequiredParameters)
+		for name, pc := range p.Constraints.ParameterConstraints {
+			sortConstraint(&pc)
+			p.Constraints.ParameterConstraints[name] = pc
+		}
+	}
+}
+
+func TestParseV1Beta(t *testing.T) {
+	c, err := ParseIntentContract([]byte(betaContract))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if c.Version != VersionV1Beta {
+		t.Errorf("Version = %q, want %q", c.Version, VersionV1Beta)
+	}
+	p := c.Spec.IntentPatterns[0]
+	if p.Pattern.Action != "translate_text" || p.Pattern.Parameters["domain"] != "language" || p.Streaming != StreamingServer {
+		t.Errorf("pattern = %+v, want translate_text of domain language, server streaming", p)
+	}
+	if got := p.Constraints.RequiredParameters; !slices.Equal(got, []string{"source_language", "text"}) {
+		t.Errorf("RequiredParameters = %v, want [source_language text]", got)
+	}
+	if _, ok := p.Constraints.ParameterConstraints["source_language"]; ok {
+		t.Errorf("required parameter without constraints got a constraint")
+	}
+	if got := p.Constraints.ParameterConstraints["target_language"]; !slices.Equal(got.EnumValues, []string{"en", "zh", "fr"}) || got.Default != "en" {
+		t.Errorf("target_language constraint = %+v, want enum values with default en", got)
+	}
+	if c.Spec.QualityOfService == nil || c.Spec.QualityOfService.Latency != "150ms" {
+		t.Errorf("QoS = %+v, want latency 150ms", c.Spec.QualityOfService)
+	}
+
+	_, err = ParseIntentContract([]byte(strings.Replace(betaContract, "intents:", "intent:", 1)))
+	if errs := fieldErrors(err); len(errs) != 1 || errs[0].Hint != `did you mean "intents"?` {
+		t.Errorf("misspelt v1beta key: error = %v, want a hint for intents", err)
+	}
+}
+
+func TestConvertV1Beta(t *testing.T) {
+	for name, doc := range map[string]string{"full": fullContract, "constraints": constraintsContract} {
+		alpha, err := ParseIntentContract([]byte(doc))
+		if err != nil {
+			t.Fatalf("%s: ParseIntentContract() error = %v", name, err)
+		}
+		beta, err := alpha.AsVersion(VersionV1Beta)
+		if err != nil {
+			t.Fatalf("%s: AsVersion() error = %v", name, err)
+		}
+		data, err := MarshalYAML(beta)
+		if err != nil {
+			t.Fatalf("%s: MarshalYAML() error = %v", name, err)
+		}
+		if !strings.Contains(string(data), "intents:") {
+			t.Errorf("%s: marshalled v1beta contract has no intents:\n%s", name, data)
+		}
+		back, err := ParseIntentContract(data)
+		if err != nil {
+			t.Fatalf("%s: ParseIntentContract(v1beta) error = %v\n%s", name, err, data)
+		}
+		if err := back.Validate(); err != nil {
+			t.Errorf("%s: Validate() of converted contract error = %v", name, err)
+		}
+		if back.Version != VersionV1Beta {
+			t.Errorf("%s: Version = %q, want %q", name, back.Version, VersionV1Beta)
+		}
+		sortRequired(beta)
+		want := beta.ToProto()
+		if got := back.ToProto(); !proto.Equal(got, want) {
+			t.Errorf("%s: v1alpha -> v1beta -> internal = %v\nwant %v", name, got, want)
+		}
+	}
+	if _, err := (&IntentContract{}).AsVersion("v2"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("AsVersion(v2) error = %v, want ErrInvalid", err)
+	}
+}
+
+// conforms checks the keys of a decoded YAML document against a JSON Schema:
+// the required ones are present and no undeclared one is, recursively
+func conforms(t *testing.T, schema map[string]interface{}, node map[string]interface{}, doc interface{}, path string) {
+	t.Helper()
+	if ref, ok := node["$ref"].(string); ok {
+		node = schema["$defs"].(map[string]interface{})[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{})
+	}
+	switch value := doc.(type) {
+	case map[string]interface{}:
+		properties, _ := node["properties"].(map[string]interface{})
+		required, _ := node["required"].([]interface{})
+		for _, key := range required {
+			if _, ok := value[key.(string)]; !ok {
+				t.Errorf("%s: required key %s missing", path, key)
+			}
+		}
+		for key, v := range value {
+			if property, ok := properties[key]; ok {
+				conforms(t, schema, property.(map[string]interface{}), v, path+"."+key)
+			} else if additional, ok := node["additionalProperties"].(map[string]interface{}); ok {
+				conforms(t, schema, additional, v, path+"."+key)
+			} else if node["additionalProperties"] == false {
+				t.Errorf("%s: key %s not allowed", path, key)
+			}
+		}
+	case []interface{}:
+		if items, ok := node["items"].(map[string]interface{}); ok {
+			for i, item := range value {
+				conforms(t, schema, items, item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	data, err := JSONSchema("")
+	if err != nil {
+		t.Fatalf("JSONSchema() error = %v", err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("schema is not JSON: %v", err)
+	}
+	if n := len(schema["oneOf"].([]interface{})); n != len(Versions) {
+		t.Errorf("schema has %d versions, want %d", n, len(Versions))
+	}
+	defs := schema["$defs"].(map[string]interface{})
+	for name, doc := range map[string]string{"v1alpha": fullContract, "v1beta": betaContract} {
+		var decoded map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &decoded); err != nil {
+			t.Fatal(err)
+		}
+		root := defs[name+".IntentContract"].(map[string]interface{})
+		conforms(t, schema, root, decoded, name)
+		if got := root["properties"].(map[string]interface{})["version"].(map[string]interface{})["const"]; got != name {
+			t.Errorf("%s: version const = %v", name, got)
+		}
+	}
+	items := defs["v1alpha.ParameterConstraint"].(map[string]interface{})["properties"].(map[string]interface{})["items"]
+	if ref := items.(map[string]interface{})["$ref"]; ref != "#/$defs/v1alpha.ParameterConstraint" {
+		t.Errorf("items of a parameter constraint = %v, want a reference to the constraint", items)
+	}
+
+	latency := regexp.MustCompile(latencyPattern)
+	for value, want := range map[string]bool{"<=150ms": true, "1m30s": true, " 2s ": true, "10sm": false, "fast": false} {
+		if latency.MatchString(value) != want {
+			t.Errorf("latency pattern matches %q = %v, want %v", value, !want, want)
+		}
+	}
+	availability := regexp.MustCompile(availabilityPattern)
+	for value, want := range map[string]bool{"99.5%": true, "0.995": true, "high": false} {
+		if availability.MatchString(value) != want {
+			t.Errorf("availability pattern matches %q = %v, want %v", value, !want, want)
+		}
+	}
+
+	one, err := JSONSchema(VersionV1Alpha)
+	if err != nil {
+		t.Fatalf("JSONSchema(v1alpha) error = %v", err)
+	}
+	if strings.Contains(string(one), "v1beta.") {
+		t.Errorf("v1alpha schema defines v1beta types")
+	}
+	if _, err := JSONSchema("v2"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("JSONSchema(v2) error = %v, want ErrInvalid", err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	parse := func() *IntentContract {
+		c, err := ParseIntentContract([]byte(fullContract))
+		if err != nil {
+			t.Fatalf("ParseIntentContract() error = %v", err)
+		}
+		return c
+	}
+	const pattern = "spec.intentPatterns[translate_text{domain=language,fast=true,options=map[formal:false],priority=2,tags=[mt text]}]"
+	tests := []struct {
+		name   string
+		update func(c *IntentContract)
+		want   []string
+		kind   ChangeKind
+	}{
+		{"unchanged", func(c *IntentContract) {}, nil, ""},
+		{"description and labels", func(c *IntentContract) {
+			c.Metadata.Description = "Translates text between languages"
+			c.Metadata.Labels["tier"] = "gold"
+			c.Spec.Implementation.Endpoint.Port = nil
+		}, []string{"compatible metadata.description", "compatible metadata.labels", "compatible spec.implementation"}, ChangeCompatible},
+		{"new pattern and alias", func(c *IntentContract) {
+			c.Spec.IntentPatterns[0].Aliases = append(c.Spec.IntentPatterns[0].Aliases, "translate.text")
+			c.Spec.IntentPatterns = append(c.Spec.IntentPatterns, IntentPattern{Pattern: Pattern{Action: "detect_language"}})
+		}, []string{"additive " + pattern + ".aliases", "additive spec.intentPatterns[detect_language]"}, ChangeAdditive},
+		{"grown enum and relaxed constraints", func(c *IntentContract) {
+			pc := c.Spec.IntentPatterns[0].Constraints
+			target := pc.ParameterConstraints["target_language"]
+			target.EnumValues = append([]string{"de"}, target.EnumValues...)
+			pc.ParameterConstraints["target_language"] = target
+			delete(pc.ParameterConstraints, "max_length")
+			pc.RequiredParameters = pc.RequiredParameters[:1]
+			text := pc.ParameterConstraints["text"]
+			text.Sensitivity = ""
+			pc.ParameterConstraints["text"] = text
+		}, []string{
+			"compatible " + pattern + ".constraints.requiredParameters",
+			"compatible " + pattern + ".constraints.parameterConstraints.max_length",
+			"additive " + pattern + ".constraints.parameterConstraints.target_language.enumValues",
+			"compatible " + pattern + ".constraints.parameterConstraints.text.sensitivity",
+		}, ChangeAdditive},
+		{"removed pattern", func(c *IntentContract) {
+			c.Spec.IntentPatterns[0].Pattern.Parameters["priority"] = 3
+		}, []string{"breaking " + pattern, "additive " + strings.Replace(pattern, "priority=2", "priority=3", 1)}, ChangeBreaking},
+		{"tightened constraints", func(c *IntentContract) {
+			p := &c.Spec.IntentPatterns[0]
+			p.Streaming = StreamingUnary
+			p.Aliases = nil
+			p.Constraints.RequiredParameters = append(p.Constraints.RequiredParameters, "max_length")
+			maxLength := p.Constraints.ParameterConstraints["max_length"]
+			maxLength.Max = nil
+			p.Constraints.ParameterConstraints["max_length"] = maxLength
+			p.Constraints.ParameterConstraints["style"] = ParameterConstraint{Type: "string"}
+		}, []string{
+			"breaking " + pattern + ".streaming",
+			"breaking " + pattern + ".aliases",
+			"breaking " + pattern + ".constraints.requiredParameters",
+			"breaking " + pattern + ".constraints.parameterConstraints.max_length",
+			"breaking " + pattern + ".constraints.parameterConstraints.style",
+		}, ChangeBreaking},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, after := parse(), parse()
+			tt.update(after)
+			changes := Diff(before, after)
+			var got []string
+			for _, c := range changes {
+				got = append(got, string(c.Kind)+" "+c.Path)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Diff() = %q, want %q", got, tt.want)
+			}
+			if changes.Kind() != tt.kind {
+				t.Errorf("Kind() = %q, want %q", changes.Kind(), tt.kind)
+			}
+		})
+	}
+}
+
+const mappingContract = `
+version: v1alpha
+kind: IntentContract
+metadata:
+  name: weather
+spec:
+  intentPatterns:
+    - pattern:
+        action: weather.forecast
+      aliases: [forecast]
+      mappings:
+        - {from: lang, to: language}
+        - {from: temp_f, to: temperature, convert: {fromUnit: F, toUnit: C}}
+        - {from: horizon_ms, to: horizon_ms, convert: {fromUnit: ms, toUnit: s}}
+        - {to: location, template: "{{.city}}, {{.country}}"}
+  implementation:
+    endpoint:
+      type: grpc
+      port: 50052
+`
+
+func TestMapParameters(t *testing.T) {
+	c, err := ParseIntentContract([]byte(mappingContract))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	params := map[string]*nfa_intent_v1alpha.Value{
+		"lang":       ValueToProto("fr"),
+		"temp_f":     ValueToProto(212.0),
+		"horizon_ms": ValueToProto(1500.0),
+		"city":       ValueToProto("Lyon"),
+		"country":    ValueToProto("FR"),
+	}
+	got, err := c.MapParameters("forecast", params)
+	if err != nil {
+		t.Fatalf("MapParameters() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"language": "fr", "temperature": 100.0, "horizon_ms": 1.5, "location": "Lyon, FR", "city": "Lyon", "country": "FR",
+	}
+	if len(got) != len(want) {
+		t.Errorf("MapParameters() = %v, want %v", got, want)
+	}
+	for name, value := range want {
+		if v := ValueFromProto(got[name]); v != value {
+			t.Errorf("parameter %s = %v, want %v", name, v, value)
+		}
+	}
+	if _, ok := params["language"]; ok || len(params) != 5 {
+		t.Errorf("MapParameters() modified its argument")
+	}
+
+	// Parameters the provider's names already have are not mapped, and
+	// mappings whose source is missing are skipped
+	got, err = c.MapParameters("weather.forecast", map[string]*nfa_intent_v1alpha.Value{
+		"lang":     ValueToProto("fr"),
+		"language": ValueToProto("de"),
+		"city":     ValueToProto("Lyon"),
+	})
+	if err != nil {
+		t.Fatalf("MapParameters() error = %v", err)
+	}
+	if got["language"].GetStringValue() != "de" || got["lang"] == nil || got["location"] != nil {
+		t.Errorf("MapParameters() = %v, want language and lang untouched and no location", got)
+	}
+
+	_, err = c.MapParameters("forecast", map[string]*nfa_intent_v1alpha.Value{"temp_f": ValueToProto("hot")})
+	var ve *ViolationError
+	if !errors.As(err, &ve) || len(ve.Violations) != 1 || ve.Violations[0].Parameter != "temp_f" {
+		t.Errorf("MapParameters() with a string to convert error = %v, want a violation of temp_f", err)
+	}
+
+	// Mappings survive the protobuf and v1beta forms
+	back, err := FromProto(c.ToProto())
+	if err != nil {
+		t.Fatalf("FromProto() error = %v", err)
+	}
+	if !slices.EqualFunc(back.Spec.IntentPatterns[0].Mappings, c.Spec.IntentPatterns[0].Mappings, mappingEqual) {
+		t.Errorf("protobuf round trip mappings = %+v", back.Spec.IntentPatterns[0].Mappings)
+	}
+	beta := FromV1Beta(ToV1Beta(c))
+	if !slices.EqualFunc(beta.Spec.IntentPatterns[0].Mappings, c.Spec.IntentPatterns[0].Mappings, mappingEqual) {
+		t.Errorf("v1beta round trip mappings = %+v", beta.Spec.IntentPatterns[0].Mappings)
+	}
+
+	for _, m := range []ParameterMapping{
+		{From: "a"},
+		{To: "b"},
+		{To: "b", From: "a", Template: "{{.a}}"},
+		{To: "b", Template: "{{.a"},
+		{To: "a", From: "a"},
+		{To: "b", From: "a", Convert: &UnitConversion{FromUnit: "ms", ToUnit: "kg"}},
+		{To: "b", From: "a", Convert: &UnitConversion{FromUnit: "parsec", ToUnit: "m"}},
+	} {
+		if err := m.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want an error", m)
+		}
+	}
+}
+
+func mappingEqual(a, b ParameterMapping) bool {
+	return a.To == b.To && a.From == b.From && a.Keep == b.Keep && a.Template == b.Template &&
+		(a.Convert == nil) == (b.Convert == nil) && (a.Convert == nil || *a.Convert == *b.Convert)
+}
+
+const resultsContract = `
+version: v1alpha
+kind: IntentContract
+metadata:
+  name: translator
+spec:
+  intentPatterns:
+    - pattern:
+        action: translate.text
+      aliases: [translate]
+      results:
+        description: The translation
+        required: [name]
+        fields:
+          name: {type: string, minLength: 1}
+          description: {type: string, enumValues: [formal, casual]}
+  implementation:
+    endpoint:
+      type: grpc
+      port: 50052
+`
+
+func TestCheckResult(t *testing.T) {
+	c, err := ParseIntentContract([]byte(resultsContract))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	// Any message serves as a response; Metadata has name and description
+	fields, err := ResultFields(&nfa_intent_v1alpha.Metadata{Name: "Bonjour", Description: "formal"})
+	if err != nil {
+		t.Fatalf("ResultFields() error = %v", err)
+	}
+	if err := c.CheckResult("translate", fields); err != nil {
+		t.Errorf("CheckResult() of a valid response error = %v", err)
+	}
+	if err := c.CheckResult("detect", nil); err != nil {
+		t.Errorf("CheckResult() of an action without results error = %v", err)
+	}
+
+	// Unset scalar fields read as their zero value
+	fields, err = ResultFields(&nfa_intent_v1alpha.Metadata{Description: "rude"})
+	if err != nil {
+		t.Fatalf("ResultFields() error = %v", err)
+	}
+	err = c.CheckResult("translate.text", fields)
+	var re *ResultError
+	if !errors.Is(err, ErrInvalidResult) || !errors.As(err, &re) {
+		t.Fatalf("CheckResult() error = %v, want a ResultError", err)
+	}
+	var got []string
+	for _, v := range re.Violations {
+		got = append(got, v.Parameter)
+	}
+	if want := []string{"description", "name"}; re.Action != "translate.text" || !slices.Equal(got, want) {
+		t.Errorf("violations of %s = %v, want %v", re.Action, got, want)
+	}
+	if err := c.CheckResult("translate", map[string]*nfa_intent_v1alpha.Value{}); err == nil {
+		t.Errorf("CheckResult() without a required field = nil, want an error")
+	}
+
+	// The schema survives the protobuf form, as far as it carries types,
+	// and the v1beta form
+	want := c.Spec.IntentPatterns[0].Results
+	back, err := FromProto(c.ToProto())
+	if err != nil {
+		t.Fatalf("FromProto() error = %v", err)
+	}
+	if got := back.Spec.IntentPatterns[0].Results; !proto.Equal(got.ToProto(), want.ToProto()) {
+		t.Errorf("protobuf round trip results = %+v, want %+v", got, want)
+	}
+	if got := FromV1Beta(ToV1Beta(c)).Spec.IntentPatterns[0].Results; !reflect.DeepEqual(got, want) {
+		t.Errorf("v1beta round trip results = %+v, want %+v", got, want)
+	}
+
+	c.Spec.IntentPatterns[0].Results.Fields["name"] = ParameterConstraint{Type: "string", Default: "hello"}
+	if err := c.Validate(); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Validate() of a result field with a default = %v, want ErrInvalid", err)
+	}
+}
+
+func TestDiffResults(t *testing.T) {
+	before, err := ParseIntentContract([]byte(resultsContract))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	const path = "spec.intentPatterns[translate.text].results"
+	tests := []struct {
+		name    string
+		results func(r *ResultSchema) *ResultSchema
+		want    []string
+	}{
+		{"description", func(r *ResultSchema) *ResultSchema {
+			r.Description = "Translated text"
+			return r
+		}, []string{"compatible " + path + ".description"}},
+		{"new and newly required fields", func(r *ResultSchema) *ResultSchema {
+			r.Fields["labels"] = ParameterConstraint{Type: "object"}
+			r.Required = append(r.Required, "description")
+			return r
+		}, []string{"additive " + path + ".required", "additive " + path + ".fields.labels"}},
+		{"removed and optional fields", func(r *ResultSchema) *ResultSchema {
+			delete(r.Fields, "description")
+			r.Required = nil
+			return r
+		}, []string{"breaking " + path + ".fields.description", "breaking " + path + ".required"}},
+		{"changed constraint", func(r *ResultSchema) *ResultSchema {
+			r.Fields["name"] = ParameterConstraint{Type: "number"}
+			return r
+		}, []string{"breaking " + path + ".fields.name"}},
+		{"removed schema", func(r *ResultSchema) *ResultSchema { return nil }, []string{"breaking " + path}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after, err := ParseIntentContract([]byte(resultsContract))
+			if err != nil {
+				t.Fatalf("ParseIntentContract() error = %v", err)
+			}
+			p := &after.Spec.IntentPatterns[0]
+			p.Results = tt.results(p.Results)
+			var got []string
+			for _, c := range Diff(before, after) {
+				got = append(got, string(c.Kind)+" "+c.Path)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Diff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+const examplesContract = `
+version: v1alpha
+kind: IntentContract
+metadata:
+  name: translator
+  description: Translates text
+spec:
+  intentPatterns:
+    - pattern:
+        action: translate.text
+      mappings:
+        - {from: lang, to: targetLanguage}
+      constraints:
+        requiredParameters: [text, targetLanguage]
+        parameterConstraints:
+          targetLanguage: {enumValues: [en, fr]}
+          text: {type: string, maxLength: 5000}
+      results:
+        required: [name]
+        fields:
+          name: {type: string, minLength: 1}
+      examples:
+        - name: greeting
+          description: Translate a greeting to French
+          parameters: {text: Hello, lang: fr}
+          result: {name: Bonjour}
+        - name: no-result
+          parameters: {text: Hi, targetLanguage: en}
+  implementation:
+    endpoint:
+      type: grpc
+      port: 50052
+`
+
+func TestExamples(t *testing.T) {
+	c, err := ParseIntentContract([]byte(examplesContract))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	action, e, err := c.Example("greeting")
+	if err != nil || action != "translate.text" || e.Parameters["lang"] != "fr" {
+		t.Fatalf("Example() = %s, %+v, %v", action, e, err)
+	}
+	if _, _, err := c.Example("farewell"); !errors.Is(err, ErrUnknownExample) {
+		t.Errorf("Example() of an unknown name error = %v, want ErrUnknownExample", err)
+	}
+
+	fields, err := ResultFields(&nfa_intent_v1alpha.Metadata{Name: "Bonjour", Description: "formal"})
+	if err != nil {
+		t.Fatalf("ResultFields() error = %v", err)
+	}
+	if err := e.CheckResult(fields); err != nil {
+		t.Errorf("CheckResult() of the expected result error = %v", err)
+	}
+	var re *ResultError
+	if err := e.CheckResult(map[string]*nfa_intent_v1alpha.Value{"name": ValueToProto("Salut")}); !errors.As(err, &re) ||
+		len(re.Violations) != 1 || re.Violations[0].Parameter != "name" {
+		t.Errorf("CheckResult() of another result error = %v, want a violation of name", err)
+	}
+
+	// Examples survive the protobuf and v1beta forms
+	want := c.Spec.IntentPatterns[0].Examples
+	back, err := FromProto(c.ToProto())
+	if err != nil {
+		t.Fatalf("FromProto() error = %v", err)
+	}
+	if got := back.Spec.IntentPatterns[0].Examples; len(got) != 2 || !proto.Equal(got[0].ToProto(), want[0].ToProto()) {
+		t.Errorf("protobuf round trip examples = %+v, want %+v", got, want)
+	}
+	if got := FromV1Beta(ToV1Beta(c)).Spec.IntentPatterns[0].Examples; !reflect.DeepEqual(got, want) {
+		t.Errorf("v1beta round trip examples = %+v, want %+v", got, want)
+	}
+
+	docs := string(Markdown(c))
+	for _, section := range []string{"# translator", "## translate.text", "### Parameters", "| `targetLanguage` | string | yes | one of `en`, `fr` |",
+		"### Results", "#### greeting", "Translate a greeting to French", `"lang": "fr"`, "Expected result:", "#### no-result"} {
+		if !strings.Contains(docs, section) {
+			t.Errorf("Markdown() lacks %q:\n%s", section, docs)
+		}
+	}
+
+	for _, tt := range []struct {
+		name    string
+		example Example
+	}{
+		{"unnamed", Example{Parameters: map[string]interface{}{"text": "Hi", "lang": "en"}}},
+		{"duplicate name", Example{Name: "greeting", Parameters: map[string]interface{}{"text": "Hi", "lang": "en"}}},
+		{"parameters violating a constraint", Example{Name: "german", Parameters: map[string]interface{}{"text": "Hi", "lang": "de"}}},
+		{"missing parameter", Example{Name: "empty"}},
+		{"result violating the schema", Example{Name: "blank", Parameters: map[string]interface{}{"text": "Hi", "lang": "en"}, Result: map[string]interface{}{"name": ""}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := ParseIntentContract([]byte(examplesContract))
+			p := &c.Spec.IntentPatterns[0]
+			p.Examples = append(p.Examples, tt.example)
+			if err := c.Validate(); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Validate() error = %v, want ErrInvalid", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,379 @@
+// Package contracttest derives table-driven test cases from an intent
+// contract: for every intent pattern, requests with valid parameters and
+// requests breaking each declared constraint, along with the outcome the
+// contract prescribes. Provider teams run them against their handlers, or
+// generate Go test tables from them with WriteGo or
+// "nfactl contract fixtures", instead of writing validation tests by hand.
+package contracttest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Case is a request for an action and the outcome the contract prescribes
+type Case struct {
+	Name   string
+	Action string
+	// Parameters holds string, float64, bool, []interface{} and
+	// map[string]interface{} values
+	Parameters map[string]interface{}
+	// Want is nil for a request the contract admits, otherwise
+	// contract.ErrUnknownAction or contract.ErrConstraintViolated
+	Want error
+	// Results is the result schema of the action for an admitted request,
+	// nil when the contract declares none
+	Results *contract.ResultSchema
+}
+
+// Request returns the intent request of the case
+func (c Case) Request() *nfa_intent_v1alpha.IntentRequest {
+	req := &nfa_intent_v1alpha.IntentRequest{
+		Action:     c.Action,
+		Parameters: make(map[string]*nfa_intent_v1alpha.Value, len(c.Parameters)),
+	}
+	for name, value := range c.Parameters {
+		req.Parameters[name] = contract.ValueToProto(value)
+	}
+	return req
+}
+
+// Check reports whether err, returned for the request of the case, matches
+// the outcome the contract prescribes. Besides the contract errors it accepts
+// the status codes an intent server created with runtime.WithAdmission
+// rejects requests with: Unimplemented and InvalidArgument.
+func (c Case) Check(err error) error {
+	switch {
+	case c.Want == nil && err != nil:
+		return fmt.Errorf("%s: want the request admitted, got %v", c.Name, err)
+	case c.Want != nil && err == nil:
+		return fmt.Errorf("%s: want %v, got the request admitted", c.Name, c.Want)
+	case c.Want != nil && !errors.Is(err, c.Want) && status.Code(err) != rejectCodes[c.Want]:
+		return fmt.Errorf("%s: want %v, got %v", c.Name, c.Want, err)
+	}
+	return nil
+}
+
+// CheckResult reports whether resp, the response to the request of the case,
+// has the fields the result schema of the action prescribes, see
+// contract.ResultSchema.Check. Every response passes without a schema.
+func (c Case) CheckResult(resp proto.Message) error {
+	if c.Results == nil {
+		return nil
+	}
+	fields, err := contract.ResultFields(resp)
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.Name, err)
+	}
+	if err := c.Results.Check(fields); err != nil {
+		return fmt.Errorf("%s: %w", c.Name, err)
+	}
+	return nil
+}
+
+// rejectCodes maps contract errors to the status codes of rejected requests
+var rejectCodes = map[error]codes.Code{
+	contract.ErrUnknownAction:      codes.Unimplemented,
+	contract.ErrConstraintViolated: codes.InvalidArgument,
+}
+
+// Cases derives the test cases of every intent pattern of c: a request with
+// valid values for the required and constrained parameters, requests at the
+// bounds of numeric ranges and lengths and with every enum value, and
+// requests missing a required parameter or property, outside a range or
+// length, outside the enum values or of the wrong type, including items of
+// arrays and properties of objects. Aliases and an undeclared action get a case each. The outcome
+// of every case is the one IntentContract.Admit, the check applied by
+// runtime.WithAdmission, decides, and admitted cases carry the result schema
+// of their action.
+func Cases(c *contract.IntentContract) []Case {
+	var cases []Case
+	add := func(name, action string, params map[string]interface{}) {
+		want := outcome(c.Admit(action, Case{Parameters: params}.Request().Parameters))
+		var results *contract.ResultSchema
+		if want == nil {
+			results = c.Results(action)
+		}
+		cases = append(cases, Case{
+			Name:       name,
+			Action:     action,
+			Parameters: params,
+			Want:       want,
+			Results:    results,
+		})
+	}
+
+	declared := make(map[string]bool)
+	for _, p := range c.Spec.IntentPatterns {
+		declared[p.Pattern.Action] = true
+		for _, alias := range p.Aliases {
+			declared[alias] = true
+		}
+	}
+	for _, p := range c.Spec.IntentPatterns {
+		action := p.Pattern.Action
+		valid := validParameters(p)
+		add(action+"/valid", action, valid)
+		for _, alias := range p.Aliases {
+			add(action+"/alias "+alias, alias, valid)
+		}
+		if p.Constraints == nil {
+			continue
+		}
+		for _, name := range p.Constraints.RequiredParameters {
+			if pc, ok := p.Constraints.ParameterConstraints[name]; ok && pc.Default != nil {
+				continue
+			}
+			params := clone(valid)
+			delete(params, name)
+			add(action+"/missing "+name, action, params)
+		}
+		for _, name := range sortedKeys(p.Constraints.ParameterConstraints) {
+			for _, variant := range variants(p.Constraints.ParameterConstraints[name]) {
+				params := clone(valid)
+				params[name] = variant.value
+				add(action+"/"+name+" "+variant.name, action, params)
+			}
+		}
+	}
+
+	unknown := "undeclared_action"
+	for declared[unknown] {
+		unknown += "_"
+	}
+	add("unknown action", unknown, nil)
+	return uniqueNames(cases)
+}
+
+// outcome reduces an error of Admit to its sentinel
+func outcome(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, contract.ErrUnknownAction):
+		return contract.ErrUnknownAction
+	default:
+		return contract.ErrConstraintViolated
+	}
+}
+
+// validParameters returns values satisfying the constraints of p for its
+// required and constrained parameters, leaving out optional ones no value
+// was found for, e.g. strings with a pattern
+func validParameters(p contract.IntentPattern) map[string]interface{} {
+	params := make(map[string]interface{})
+	if p.Constraints == nil {
+		return params
+	}
+	for _, name := range p.Constraints.RequiredParameters {
+		params[name] = validValue(p.Constraints.ParameterConstraints[name])
+	}
+	for name, pc := range p.Constraints.ParameterConstraints {
+		v := validValue(pc)
+		if _, required := params[name]; required || pc.Check(contract.ValueToProto(v)) == nil {
+			params[name] = v
+		}
+	}
+	return params
+}
+
+// validValue returns a value satisfying pc, its default if it has one. A
+// string with a pattern may not match it.
+func validValue(pc contract.ParameterConstraint) interface{} {
+	if pc.Default != nil {
+		// normalized, e.g. integers to float64
+		return contract.ValueFromProto(contract.ValueToProto(pc.Default))
+	}
+	switch {
+	case len(pc.EnumValues) > 0:
+		return pc.EnumValues[0]
+	case pc.Min != nil || pc.Max != nil || pc.Type == "number" || pc.Type == "integer":
+		v := 1.0
+		switch {
+		case pc.Min != nil:
+			v = *pc.Min
+			if pc.Type == "integer" {
+				v = math.Ceil(v)
+			}
+		case pc.Max != nil:
+			v = *pc.Max
+			if pc.Type == "integer" {
+				v = math.Floor(v)
+			}
+		}
+		return v
+	case pc.Type == "boolean":
+		return true
+	case pc.Type == "array" || pc.Items != nil:
+		return arrayOf(pc, validLength(pc, 1))
+	case pc.Type == "object" || len(pc.Properties) > 0 || len(pc.Required) > 0:
+		object := make(map[string]interface{}, len(pc.Properties))
+		for name, property := range pc.Properties {
+			object[name] = validValue(property)
+		}
+		return object
+	default:
+		return stringOf(validLength(pc, len("example")))
+	}
+}
+
+// validLength returns the length closest to n within the bounds of pc
+func validLength(pc contract.ParameterConstraint, n int) int {
+	if pc.MaxLength != nil {
+		n = min(n, *pc.MaxLength)
+	}
+	if pc.MinLength != nil {
+		n = max(n, *pc.MinLength)
+	}
+	return n
+}
+
+// arrayOf returns an array of n valid items of the array constraint pc
+func arrayOf(pc contract.ParameterConstraint, n int) []interface{} {
+	var item contract.ParameterConstraint
+	if pc.Items != nil {
+		item = *pc.Items
+	}
+	items := make([]interface{}, n)
+	for i := range items {
+		items[i] = validValue(item)
+	}
+	return items
+}
+
+// stringOf returns a string of n characters
+func stringOf(n int) string {
+	return strings.Repeat("example", n/len("example")+1)[:n]
+}
+
+// variant is a named value for a parameter
+type variant struct {
+	name  string
+	value interface{}
+}
+
+// variants returns the values worth testing for a parameter with constraint
+// pc besides its valid value: range and length bounds, enum values, and
+// values outside the range, the length bounds, the enum values or the type.
+// Arrays get the variants of their items, and objects the variants of their
+// properties and one without each required property.
+func variants(pc contract.ParameterConstraint) []variant {
+	var vs []variant
+	for _, v := range pc.EnumValues[min(1, len(pc.EnumValues)):] {
+		vs = append(vs, variant{"enum value " + v, v})
+	}
+	if len(pc.EnumValues) > 0 {
+		other := "not_an_enum_value"
+		for slices.Contains(pc.EnumValues, other) {
+			other += "_"
+		}
+		vs = append(vs, variant{"not an enum value", other})
+	}
+	if pc.Min != nil {
+		vs = append(vs, variant{"at minimum", *pc.Min}, variant{"below minimum", *pc.Min - 1})
+	}
+	if pc.Max != nil {
+		vs = append(vs, variant{"at maximum", *pc.Max}, variant{"above maximum", *pc.Max + 1})
+	}
+	array := pc.Type == "array" || pc.Items != nil
+	ofLength := func(n int) interface{} {
+		if array {
+			return arrayOf(pc, n)
+		}
+		return stringOf(n)
+	}
+	if pc.MinLength != nil {
+		vs = append(vs, variant{"at minimum length", ofLength(*pc.MinLength)})
+		if *pc.MinLength > 0 {
+			vs = append(vs, variant{"below minimum length", ofLength(*pc.MinLength - 1)})
+		}
+	}
+	if pc.MaxLength != nil {
+		vs = append(vs, variant{"at maximum length", ofLength(*pc.MaxLength)}, variant{"above maximum length", ofLength(*pc.MaxLength + 1)})
+	}
+	if pc.Items != nil {
+		for _, item := range variants(*pc.Items) {
+			items := arrayOf(pc, validLength(pc, 1))
+			if len(items) == 0 {
+				break
+			}
+			items[0] = item.value
+			vs = append(vs, variant{"item " + item.name, items})
+		}
+	}
+	object, _ := validValue(pc).(map[string]interface{})
+	for _, name := range pc.Required {
+		if object == nil || pc.Properties[name].Default != nil {
+			break
+		}
+		missing := clone(object)
+		delete(missing, name)
+		vs = append(vs, variant{"missing property " + name, missing})
+	}
+	for _, name := range sortedKeys(pc.Properties) {
+		if object == nil {
+			break
+		}
+		for _, property := range variants(pc.Properties[name]) {
+			changed := clone(object)
+			changed[name] = property.value
+			vs = append(vs, variant{"property " + name + " " + property.name, changed})
+		}
+	}
+	switch pc.Type {
+	case "integer":
+		if v, ok := validValue(pc).(float64); ok {
+			vs = append(vs, variant{"fractional", v + 0.5})
+		}
+		vs = append(vs, variant{"wrong type", "1"})
+	case "number":
+		vs = append(vs, variant{"wrong type", "1"})
+	case "string":
+		vs = append(vs, variant{"wrong type", 1.0})
+	case "boolean":
+		vs = append(vs, variant{"wrong type", "true"})
+	case "array", "object":
+		vs = append(vs, variant{"wrong type", "example"})
+	}
+	return vs
+}
+
+// uniqueNames numbers cases whose names repeat, e.g. of two patterns with
+// the same action
+func uniqueNames(cases []Case) []Case {
+	seen := make(map[string]int)
+	for i := range cases {
+		seen[cases[i].Name]++
+		if n := seen[cases[i].Name]; n > 1 {
+			cases[i].Name = fmt.Sprintf("%s #%d", cases[i].Name, n)
+		}
+	}
+	return cases
+}
+
+func clone(params map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		out[k] = v
+	}
+	return out
+}
+
+func sortedKeys(m map[string]contract.ParameterConstraint) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
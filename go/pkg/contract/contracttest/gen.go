@@ -0,0 +1,194 @@
+package contracttest
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+)
+
+// GoOptions names what WriteGo generates
+type GoOptions struct {
+	// Package is the package clause of the file
+	Package string
+	// Var is the name of the generated []contracttest.Case variable
+	Var string
+	// Source names the contract in the generated header, e.g. its file
+	Source string
+}
+
+// WriteGo writes cases as a gofmt-ed Go file declaring them as a table, to
+// be checked in next to the provider's tests and regenerated with
+// go:generate when the contract changes
+func WriteGo(w io.Writer, cases []Case, opts GoOptions) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by nfactl contract fixtures from %s. DO NOT EDIT.\n\n", opts.Source)
+	fmt.Fprintf(&buf, "package %s\n\n", opts.Package)
+	buf.WriteString("import (\n")
+	for _, c := range cases {
+		if c.Want != nil || c.Results != nil {
+			buf.WriteString("\t\"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract\"\n")
+			break
+		}
+	}
+	buf.WriteString("\t\"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract/contracttest\"\n)\n\n")
+
+	fmt.Fprintf(&buf, "var %s = []contracttest.Case{\n", opts.Var)
+	for _, c := range cases {
+		buf.WriteString("{\n")
+		fmt.Fprintf(&buf, "Name: %s,\n", strconv.Quote(c.Name))
+		fmt.Fprintf(&buf, "Action: %s,\n", strconv.Quote(c.Action))
+		if len(c.Parameters) > 0 {
+			fmt.Fprintf(&buf, "Parameters: %s,\n", goParameters(c.Parameters))
+		}
+		switch c.Want {
+		case nil:
+		case contract.ErrUnknownAction:
+			buf.WriteString("Want: contract.ErrUnknownAction,\n")
+		case contract.ErrConstraintViolated:
+			buf.WriteString("Want: contract.ErrConstraintViolated,\n")
+		default:
+			return fmt.Errorf("case %s: unsupported outcome %v", c.Name, c.Want)
+		}
+		if c.Results != nil {
+			fmt.Fprintf(&buf, "Results: %s,\n", goResults(c.Results))
+		}
+		buf.WriteString("},\n")
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format generated cases: %w", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// Ptr returns a pointer to v, for the bounds of generated result schemas
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// goResults renders a result schema as a pointer literal
+func goResults(r *contract.ResultSchema) string {
+	var fields []string
+	if r.Description != "" {
+		fields = append(fields, "Description: "+strconv.Quote(r.Description))
+	}
+	if len(r.Required) > 0 {
+		fields = append(fields, "Required: "+goStrings(r.Required))
+	}
+	if len(r.Fields) > 0 {
+		fields = append(fields, "Fields: "+goConstraints(r.Fields))
+	}
+	return "&contract.ResultSchema{" + strings.Join(fields, ", ") + "}"
+}
+
+// goConstraints renders constraints as a map literal with sorted keys
+func goConstraints(constraints map[string]contract.ParameterConstraint) string {
+	names := make([]string, 0, len(constraints))
+	for name := range constraints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = strconv.Quote(name) + ": " + goConstraint(constraints[name])
+	}
+	return "map[string]contract.ParameterConstraint{" + strings.Join(pairs, ", ") + "}"
+}
+
+// goConstraint renders a constraint as a struct literal, leaving out its
+// default, which result fields do not have
+func goConstraint(pc contract.ParameterConstraint) string {
+	var fields []string
+	add := func(name, value string) {
+		fields = append(fields, name+": "+value)
+	}
+	if pc.Type != "" {
+		add("Type", strconv.Quote(pc.Type))
+	}
+	if len(pc.EnumValues) > 0 {
+		add("EnumValues", goStrings(pc.EnumValues))
+	}
+	if pc.Min != nil {
+		add("Min", "contracttest.Ptr("+goValue(*pc.Min)+")")
+	}
+	if pc.Max != nil {
+		add("Max", "contracttest.Ptr("+goValue(*pc.Max)+")")
+	}
+	if pc.Pattern != "" {
+		add("Pattern", strconv.Quote(pc.Pattern))
+	}
+	if pc.MinLength != nil {
+		add("MinLength", "contracttest.Ptr("+strconv.Itoa(*pc.MinLength)+")")
+	}
+	if pc.MaxLength != nil {
+		add("MaxLength", "contracttest.Ptr("+strconv.Itoa(*pc.MaxLength)+")")
+	}
+	if pc.Items != nil {
+		add("Items", "&"+goConstraint(*pc.Items))
+	}
+	if len(pc.Properties) > 0 {
+		add("Properties", goConstraints(pc.Properties))
+	}
+	if len(pc.Required) > 0 {
+		add("Required", goStrings(pc.Required))
+	}
+	if pc.Sensitivity != "" {
+		add("Sensitivity", strconv.Quote(string(pc.Sensitivity)))
+	}
+	return "contract.ParameterConstraint{" + strings.Join(fields, ", ") + "}"
+}
+
+func goStrings(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
+
+// goParameters renders params as a map literal with sorted keys
+func goParameters(params map[string]interface{}) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = strconv.Quote(name) + ": " + goValue(params[name])
+	}
+	return "map[string]interface{}{" + strings.Join(pairs, ", ") + "}"
+}
+
+// goValue renders a parameter value as a Go literal of the same type
+func goValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		s := strconv.FormatFloat(v, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".e") { // keep integral values float64
+			s += ".0"
+		}
+		return s
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = goValue(item)
+		}
+		return "[]interface{}{" + strings.Join(items, ", ") + "}"
+	case map[string]interface{}:
+		return goParameters(v)
+	default:
+		return fmt.Sprintf("%#v", v)
+	}
+}
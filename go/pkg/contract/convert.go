@@ -0,0 +1,266 @@
+package contract
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract/v1beta"
+)
+
+// Contract schema versions
+const (
+	VersionV1Alpha = "v1alpha"
+	VersionV1Beta  = v1beta.Version
+)
+
+// Versions lists the contract schema versions this package parses and
+// converts between, newest first
+var Versions = []string{VersionV1Beta, VersionV1Alpha}
+
+// AsVersion returns a copy of the contract labelled with another schema
+// version, sharing its patterns. Both versions describe the same contracts,
+// so only the version key and the form MarshalYAML writes change; the
+// protobuf form differs only in its version field.
+func (c *IntentContract) AsVersion(version string) (*IntentContract, error) {
+	if !slices.Contains(Versions, version) {
+		return nil, fmt.Errorf("%w: unsupported version: %s", ErrInvalid, version)
+	}
+	converted := *c
+	converted.Version = version
+	return &converted, nil
+}
+
+// ToV1Beta converts a contract to the v1beta schema, whatever its version.
+// Required parameters become parameters marked required, with no other
+// constraint unless they had one.
+func ToV1Beta(c *IntentContract) *v1beta.IntentContract {
+	out := &v1beta.IntentContract{
+		Version: VersionV1Beta,
+		Kind:    c.Kind,
+		Metadata: v1beta.Metadata{
+			Name:        c.Metadata.Name,
+			Description: c.Metadata.Description,
+			Labels:      c.Metadata.Labels,
+		},
+		Spec: v1beta.Spec{
+			Implementation: v1beta.Implementation{Endpoint: endpointToV1Beta(c.Spec.Implementation.Endpoint)},
+		},
+	}
+	for _, r := range c.Spec.Implementation.Resources {
+		out.Spec.Implementation.Resources = append(out.Spec.Implementation.Resources, v1beta.ResourceRequirement(r))
+	}
+	if q := c.Spec.QualityOfService; q != nil {
+		out.Spec.QoS = &v1beta.QualityOfService{Latency: q.Latency, Availability: q.Availability, Priority: q.Priority}
+	}
+	for _, p := range c.Spec.IntentPatterns {
+		intent := v1beta.Intent{
+			Action:    p.Pattern.Action,
+			Match:     p.Pattern.Parameters,
+			Streaming: string(p.Streaming),
+			Aliases:   p.Aliases,
+		}
+		for _, m := range p.Mappings {
+			mapping := v1beta.ParameterMapping{To: m.To, From: m.From, Keep: m.Keep, Template: m.Template}
+			if m.Convert != nil {
+				convert := v1beta.UnitConversion(*m.Convert)
+				mapping.Convert = &convert
+			}
+			intent.Mappings = append(intent.Mappings, mapping)
+		}
+		for _, e := range p.Examples {
+			intent.Examples = append(intent.Examples, v1beta.Example(e))
+		}
+		if r := p.Results; r != nil {
+			// Fields convert as the properties of an object
+			fields := parameterToV1Beta(ParameterConstraint{Properties: r.Fields, Required: r.Required})
+			intent.Results = &v1beta.Results{Description: r.Description, Fields: fields.Properties}
+		}
+		if p.Classification != nil {
+			intent.Classification = &v1beta.DataClassification{
+				Residency: string(p.Classification.Residency),
+				Regions:   p.Classification.Regions,
+			}
+		}
+		if p.Constraints != nil {
+			intent.Parameters = make(map[string]v1beta.Parameter)
+			for name, pc := range p.Constraints.ParameterConstraints {
+				intent.Parameters[name] = parameterToV1Beta(pc)
+			}
+			for _, name := range p.Constraints.RequiredParameters {
+				param := intent.Parameters[name]
+				param.Required = true
+				intent.Parameters[name] = param
+			}
+		}
+		out.Spec.Intents = append(out.Spec.Intents, intent)
+	}
+	return out
+}
+
+func endpointToV1Beta(e Endpoint) v1beta.Endpoint {
+	out := v1beta.Endpoint{Type: e.Type, Host: e.Host, Port: e.Port, Procedure: e.Procedure, URL: e.URL, Codec: e.Codec}
+	if e.Exec != nil {
+		exec := v1beta.ExecCommand(*e.Exec)
+		out.Exec = &exec
+	}
+	return out
+}
+
+func parameterToV1Beta(pc ParameterConstraint) v1beta.Parameter {
+	out := v1beta.Parameter{
+		Type:        pc.Type,
+		Enum:        pc.EnumValues,
+		Min:         pc.Min,
+		Max:         pc.Max,
+		Pattern:     pc.Pattern,
+		MinLength:   pc.MinLength,
+		MaxLength:   pc.MaxLength,
+		Sensitivity: string(pc.Sensitivity),
+		Default:     pc.Default,
+	}
+	if pc.Items != nil {
+		items := parameterToV1Beta(*pc.Items)
+		out.Items = &items
+	}
+	if len(pc.Properties) > 0 || len(pc.Required) > 0 {
+		out.Properties = make(map[string]v1beta.Parameter, len(pc.Properties))
+		for name, property := range pc.Properties {
+			out.Properties[name] = parameterToV1Beta(property)
+		}
+		for _, name := range pc.Required {
+			property := out.Properties[name]
+			property.Required = true
+			out.Properties[name] = property
+		}
+	}
+	return out
+}
+
+// FromV1Beta converts a v1beta contract to its internal form, keeping its
+// version. Required parameters are listed by name; a required parameter
+// without other constraints gets no parameter constraint, as in v1alpha,
+// while a required property of an object keeps an empty one, since v1alpha
+// requires the required properties of an object to be declared.
+func FromV1Beta(c *v1beta.IntentContract) *IntentContract {
+	out := &IntentContract{
+		Version: c.Version,
+		Kind:    c.Kind,
+		Metadata: ContractMetadata{
+			Name:        c.Metadata.Name,
+			Description: c.Metadata.Description,
+			Labels:      c.Metadata.Labels,
+		},
+		Spec: IntentSpec{
+			Implementation: Implementation{Endpoint: endpointFromV1Beta(c.Spec.Implementation.Endpoint)},
+		},
+	}
+	for _, r := range c.Spec.Implementation.Resources {
+		out.Spec.Implementation.Resources = append(out.Spec.Implementation.Resources, ResourceRequirement(r))
+	}
+	if q := c.Spec.QoS; q != nil {
+		out.Spec.QualityOfService = &QualityOfService{Latency: q.Latency, Availability: q.Availability, Priority: q.Priority}
+	}
+	for _, intent := range c.Spec.Intents {
+		p := IntentPattern{
+			Pattern:   Pattern{Action: intent.Action, Parameters: intent.Match},
+			Streaming: StreamingMode(intent.Streaming),
+			Aliases:   intent.Aliases,
+		}
+		for _, m := range intent.Mappings {
+			mapping := ParameterMapping{To: m.To, From: m.From, Keep: m.Keep, Template: m.Template}
+			if m.Convert != nil {
+				convert := UnitConversion(*m.Convert)
+				mapping.Convert = &convert
+			}
+			p.Mappings = append(p.Mappings, mapping)
+		}
+		for _, e := range intent.Examples {
+			p.Examples = append(p.Examples, Example(e))
+		}
+		if r := intent.Results; r != nil {
+			fields := parameterFromV1Beta(v1beta.Parameter{Properties: r.Fields})
+			p.Results = &ResultSchema{Description: r.Description, Required: fields.Required, Fields: fields.Properties}
+		}
+		if intent.Classification != nil {
+			p.Classification = &DataClassification{
+				Residency: Residency(intent.Classification.Residency),
+				Regions:   intent.Classification.Regions,
+			}
+		}
+		if len(intent.Parameters) > 0 {
+			p.Constraints = &PatternConstraints{}
+			for _, name := range sortedKeys(intent.Parameters) {
+				param := intent.Parameters[name]
+				pc := parameterFromV1Beta(param)
+				if param.Required {
+					p.Constraints.RequiredParameters = append(p.Constraints.RequiredParameters, name)
+					param.Required = false
+					if isZero(param) {
+						continue
+					}
+				}
+				if p.Constraints.ParameterConstraints == nil {
+					p.Constraints.ParameterConstraints = make(map[string]ParameterConstraint)
+				}
+				p.Constraints.ParameterConstraints[name] = pc
+			}
+		}
+		out.Spec.IntentPatterns = append(out.Spec.IntentPatterns, p)
+	}
+	return out
+}
+
+func endpointFromV1Beta(e v1beta.Endpoint) Endpoint {
+	out := Endpoint{Type: e.Type, Host: e.Host, Port: e.Port, Procedure: e.Procedure, URL: e.URL, Codec: e.Codec}
+	if e.Exec != nil {
+		exec := ExecCommand(*e.Exec)
+		out.Exec = &exec
+	}
+	return out
+}
+
+func parameterFromV1Beta(p v1beta.Parameter) ParameterConstraint {
+	out := ParameterConstraint{
+		Type:        p.Type,
+		EnumValues:  p.Enum,
+		Min:         p.Min,
+		Max:         p.Max,
+		Pattern:     p.Pattern,
+		MinLength:   p.MinLength,
+		MaxLength:   p.MaxLength,
+		Sensitivity: Sensitivity(p.Sensitivity),
+		Default:     p.Default,
+	}
+	if p.Items != nil {
+		items := parameterFromV1Beta(*p.Items)
+		out.Items = &items
+	}
+	if len(p.Properties) > 0 {
+		out.Properties = make(map[string]ParameterConstraint, len(p.Properties))
+		for _, name := range sortedKeys(p.Properties) {
+			property := p.Properties[name]
+			if property.Required {
+				out.Required = append(out.Required, name)
+			}
+			out.Properties[name] = parameterFromV1Beta(property)
+		}
+	}
+	return out
+}
+
+// isZero reports whether a parameter has no constraint at all
+func isZero(p v1beta.Parameter) bool {
+	return p.Type == "" && len(p.Enum) == 0 && p.Min == nil && p.Max == nil && p.Pattern == "" &&
+		p.MinLength == nil && p.MaxLength == nil && p.Items == nil && len(p.Properties) == 0 &&
+		p.Sensitivity == "" && p.Default == nil
+}
+
+func sortedKeys(m map[string]v1beta.Parameter) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,354 @@
+package contract
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ErrBreakingChange is returned when a contract update would break consumers
+// of the registered version
+var ErrBreakingChange = errors.New("breaking contract change")
+
+// ChangeKind classifies a change between two versions of a contract by its
+// effect on consumers of the old version
+type ChangeKind string
+
+const (
+	// ChangeCompatible changes nothing consumers depend on, e.g. the
+	// description, labels, endpoint or QoS
+	ChangeCompatible ChangeKind = "compatible"
+	// ChangeAdditive serves more requests than before, e.g. a new intent
+	// pattern, alias or enum value
+	ChangeAdditive ChangeKind = "additive"
+	// ChangeBreaking refuses or handles differently requests the old version
+	// served, e.g. a removed pattern or a changed constraint
+	ChangeBreaking ChangeKind = "breaking"
+)
+
+// Change is a difference Diff found between two versions of a contract
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	// Path locates the change in the contract, e.g.
+	// "spec.intentPatterns[translate.text].constraints.requiredParameters"
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s: %s", c.Kind, c.Path, c.Message)
+}
+
+// Changes lists the differences between two versions of a contract
+type Changes []Change
+
+// Kind returns the kind of the most disruptive change, breaking over
+// additive over compatible; it is empty when there are no changes
+func (cs Changes) Kind() ChangeKind {
+	var kind ChangeKind
+	for _, c := range cs {
+		if c.Kind == ChangeBreaking {
+			return ChangeBreaking
+		}
+		if c.Kind == ChangeAdditive || kind == "" {
+			kind = c.Kind
+		}
+	}
+	return kind
+}
+
+// Breaking returns the breaking changes
+func (cs Changes) Breaking() Changes {
+	var out Changes
+	for _, c := range cs {
+		if c.Kind == ChangeBreaking {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func (cs Changes) String() string {
+	lines := make([]string, len(cs))
+	for i, c := range cs {
+		lines[i] = c.String()
+	}
+	return strings.Join(lines, "; ")
+}
+
+// Diff compares a contract with the version replacing it, so providers can
+// be upgraded without breaking consumers. Intent patterns are matched by
+// action and inline parameters. Removing a pattern, an alias or a parameter
+// mapping, changing its streaming mode, data classification or mappings,
+// adding a required parameter and adding or tightening a parameter
+// constraint are breaking; new patterns, aliases, mappings and enum values
+// are additive; everything else,
+// including relaxed constraints, is compatible. Result schemas compare the
+// other way round: removing a result field or no longer requiring one is
+// breaking, adding or requiring one is additive. Renaming the contract is
+// breaking, since the broker derives service IDs from the name.
+func Diff(before, after *IntentContract) Changes {
+	var d differ
+	if before.Metadata.Name != after.Metadata.Name {
+		d.add(ChangeBreaking, "metadata.name", "renamed from %q to %q", before.Metadata.Name, after.Metadata.Name)
+	}
+	if before.Version != after.Version {
+		d.add(ChangeCompatible, "version", "schema version changed from %s to %s", before.Version, after.Version)
+	}
+	if before.Metadata.Description != after.Metadata.Description {
+		d.add(ChangeCompatible, "metadata.description", "description changed")
+	}
+	if !maps.Equal(before.Metadata.Labels, after.Metadata.Labels) {
+		d.add(ChangeCompatible, "metadata.labels", "labels changed")
+	}
+
+	old := patternsByKey(before.Spec.IntentPatterns)
+	updated := patternsByKey(after.Spec.IntentPatterns)
+	for _, key := range sortedPatternKeys(old) {
+		path := "spec.intentPatterns[" + key + "]"
+		if p, ok := updated[key]; ok {
+			d.pattern(path, old[key], p)
+		} else {
+			d.add(ChangeBreaking, path, "intent pattern removed")
+		}
+	}
+	for _, key := range sortedPatternKeys(updated) {
+		if _, ok := old[key]; !ok {
+			d.add(ChangeAdditive, "spec.intentPatterns["+key+"]", "intent pattern added")
+		}
+	}
+
+	if !reflect.DeepEqual(before.Spec.Implementation, after.Spec.Implementation) {
+		d.add(ChangeCompatible, "spec.implementation", "implementation changed")
+	}
+	if !reflect.DeepEqual(before.Spec.QualityOfService, after.Spec.QualityOfService) {
+		d.add(ChangeCompatible, "spec.qualityOfService", "quality of service changed")
+	}
+	return d.changes
+}
+
+type differ struct {
+	changes Changes
+}
+
+func (d *differ) add(kind ChangeKind, path, format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{kind, path, fmt.Sprintf(format, args...)})
+}
+
+func (d *differ) pattern(path string, before, after IntentPattern) {
+	if before.Streaming.orUnary() != after.Streaming.orUnary() {
+		d.add(ChangeBreaking, path+".streaming", "streaming mode changed from %s to %s", before.Streaming.orUnary(), after.Streaming.orUnary())
+	}
+	if !reflect.DeepEqual(before.Classification, after.Classification) {
+		if after.Classification == nil {
+			d.add(ChangeCompatible, path+".classification", "data classification removed")
+		} else {
+			d.add(ChangeBreaking, path+".classification", "data classification changed")
+		}
+	}
+	for _, alias := range before.Aliases {
+		if !slices.Contains(after.Aliases, alias) {
+			d.add(ChangeBreaking, path+".aliases", "alias %s removed", alias)
+		}
+	}
+	for _, alias := range after.Aliases {
+		if !slices.Contains(before.Aliases, alias) {
+			d.add(ChangeAdditive, path+".aliases", "alias %s added", alias)
+		}
+	}
+	d.mappings(path+".mappings", before.Mappings, after.Mappings)
+	d.results(path+".results", before.Results, after.Results)
+
+	var oldC, newC PatternConstraints
+	if before.Constraints != nil {
+		oldC = *before.Constraints
+	}
+	if after.Constraints != nil {
+		newC = *after.Constraints
+	}
+	for _, name := range newC.RequiredParameters {
+		if !slices.Contains(oldC.RequiredParameters, name) {
+			d.add(ChangeBreaking, path+".constraints.requiredParameters", "parameter %s is now required", name)
+		}
+	}
+	for _, name := range oldC.RequiredParameters {
+		if !slices.Contains(newC.RequiredParameters, name) {
+			d.add(ChangeCompatible, path+".constraints.requiredParameters", "parameter %s is no longer required", name)
+		}
+	}
+	for _, name := range sortedConstraintKeys(oldC.ParameterConstraints, newC.ParameterConstraints) {
+		constraintPath := path + ".constraints.parameterConstraints." + name
+		pc, had := oldC.ParameterConstraints[name]
+		updated, has := newC.ParameterConstraints[name]
+		switch {
+		case !had:
+			d.add(ChangeBreaking, constraintPath, "constraint added")
+		case !has:
+			d.add(ChangeCompatible, constraintPath, "constraint removed")
+		default:
+			d.constraint(constraintPath, pc, updated)
+		}
+	}
+}
+
+// mappings compares parameter mappings by their target: a new mapping
+// serves consumers the old version did not, a removed or changed one stops
+// serving some it did
+func (d *differ) mappings(path string, before, after []ParameterMapping) {
+	for _, m := range before {
+		i := slices.IndexFunc(after, func(n ParameterMapping) bool { return n.To == m.To })
+		switch {
+		case i < 0:
+			d.add(ChangeBreaking, path, "mapping to %s removed", m.To)
+		case !reflect.DeepEqual(m, after[i]):
+			d.add(ChangeBreaking, path, "mapping to %s changed", m.To)
+		}
+	}
+	for _, m := range after {
+		if !slices.ContainsFunc(before, func(n ParameterMapping) bool { return n.To == m.To }) {
+			d.add(ChangeAdditive, path, "mapping to %s added", m.To)
+		}
+	}
+}
+
+// results compares result schemas from the consumer's side: fields that
+// responses no longer declare, guarantee or constrain as before break
+// consumers relying on them, while new and newly required fields only
+// add to what they get back
+func (d *differ) results(path string, before, after *ResultSchema) {
+	switch {
+	case before == nil && after == nil:
+		return
+	case before == nil:
+		d.add(ChangeAdditive, path, "result schema added")
+		return
+	case after == nil:
+		d.add(ChangeBreaking, path, "result schema removed")
+		return
+	}
+	if before.Description != after.Description {
+		d.add(ChangeCompatible, path+".description", "description changed")
+	}
+	declared := func(r *ResultSchema, name string) bool {
+		_, ok := r.Fields[name]
+		return ok || slices.Contains(r.Required, name)
+	}
+	names := sortedConstraintKeys(before.Fields, after.Fields)
+	for _, name := range append(slices.Clone(before.Required), after.Required...) {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fieldPath := path + ".fields." + name
+		switch had, has := declared(before, name), declared(after, name); {
+		case !has:
+			d.add(ChangeBreaking, fieldPath, "result field removed")
+			continue
+		case !had:
+			d.add(ChangeAdditive, fieldPath, "result field added")
+			continue
+		}
+		required, requires := slices.Contains(before.Required, name), slices.Contains(after.Required, name)
+		if required && !requires {
+			d.add(ChangeBreaking, path+".required", "result field %s is no longer required", name)
+		} else if requires && !required {
+			d.add(ChangeAdditive, path+".required", "result field %s is now required", name)
+		}
+		pc, constrained := before.Fields[name]
+		updated, constrains := after.Fields[name]
+		switch {
+		case !constrained && constrains:
+			d.add(ChangeCompatible, fieldPath, "constraint added")
+		case constrained && !constrains:
+			d.add(ChangeBreaking, fieldPath, "constraint removed")
+		case constrained && !reflect.DeepEqual(pc, updated):
+			d.add(ChangeBreaking, fieldPath, "constraint changed")
+		}
+	}
+}
+
+// constraint classifies the change of a parameter constraint: sensitivity
+// only affects redaction, and a grown enum accepts every value it did
+func (d *differ) constraint(path string, before, after ParameterConstraint) {
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+	oldC, newC := before, after
+	oldC.Sensitivity, newC.Sensitivity = "", ""
+	if reflect.DeepEqual(oldC, newC) {
+		d.add(ChangeCompatible, path+".sensitivity", "sensitivity changed from %q to %q", before.Sensitivity, after.Sensitivity)
+		return
+	}
+	oldC.EnumValues, newC.EnumValues = nil, nil
+	if reflect.DeepEqual(oldC, newC) && len(before.EnumValues) > 0 {
+		if len(after.EnumValues) == 0 {
+			d.add(ChangeCompatible, path+".enumValues", "enum restriction removed")
+			return
+		}
+		var added []string
+		for _, v := range after.EnumValues {
+			if !slices.Contains(before.EnumValues, v) {
+				added = append(added, v)
+			}
+		}
+		if len(added) == 0 && len(after.EnumValues) == len(before.EnumValues) {
+			return // reordered
+		}
+		if len(after.EnumValues)-len(added) == len(before.EnumValues) {
+			d.add(ChangeAdditive, path+".enumValues", "enum values added: %s", strings.Join(added, ", "))
+			return
+		}
+	}
+	d.add(ChangeBreaking, path, "constraint changed")
+}
+
+// patternsByKey indexes patterns by their action and inline parameters,
+// e.g. "translate.text{to=@targetLanguage}"; the first of duplicates wins
+func patternsByKey(patterns []IntentPattern) map[string]IntentPattern {
+	out := make(map[string]IntentPattern, len(patterns))
+	for _, p := range patterns {
+		key := p.Pattern.Action
+		if len(p.Pattern.Parameters) > 0 {
+			params := make([]string, 0, len(p.Pattern.Parameters))
+			for name, value := range p.Pattern.Parameters {
+				params = append(params, fmt.Sprintf("%s=%v", name, value))
+			}
+			sort.Strings(params)
+			key += "{" + strings.Join(params, ",") + "}"
+		}
+		if _, ok := out[key]; !ok {
+			out[key] = p
+		}
+	}
+	return out
+}
+
+func sortedPatternKeys(m map[string]IntentPattern) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedConstraintKeys returns the parameter names constrained in a or b
+func sortedConstraintKeys(a, b map[string]ParameterConstraint) []string {
+	var keys []string
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
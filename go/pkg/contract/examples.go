@@ -0,0 +1,142 @@
+package contract
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrUnknownExample is returned by IntentContract.Example for a name no
+// intent pattern of the contract declares
+var ErrUnknownExample = errors.New("unknown contract example")
+
+// Example is a named set of parameters of an intent pattern and the
+// response the provider is expected to give, shown by documentation and API
+// explorers and sent by "nfactl invoke -example"
+type Example struct {
+	// Name is unique within the contract
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	// Parameters are those a consumer sends, before the pattern's mappings
+	Parameters map[string]interface{} `yaml:"parameters,omitempty"`
+	// Result holds the fields of the expected response by their proto
+	// names; fields it does not list are not compared
+	Result map[string]interface{} `yaml:"result,omitempty"`
+}
+
+// ParametersProto returns the parameters of the example in protobuf format
+func (e Example) ParametersProto() map[string]*nfa_intent_v1alpha.Value {
+	return valuesToProto(e.Parameters)
+}
+
+// CheckResult compares the fields of a response, as returned by
+// ResultFields, with the expected result: every field the example lists
+// must be present with the same value. Mismatches fail with a *ResultError
+// listing all of them.
+func (e Example) CheckResult(fields map[string]*nfa_intent_v1alpha.Value) error {
+	var violations []Violation
+	for name, want := range e.Result {
+		got, ok := fields[name]
+		switch {
+		case !ok:
+			violations = append(violations, Violation{Parameter: name, Description: "expected by the example but missing"})
+		case !proto.Equal(got, ValueToProto(want)):
+			violations = append(violations, Violation{Parameter: name, Description: fmt.Sprintf("is %v, the example expects %v", ValueFromProto(got), want)})
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	sort.Slice(violations, func(i, j int) bool { return violations[i].Parameter < violations[j].Parameter })
+	return &ResultError{Violations: violations}
+}
+
+// Example returns the example named name and the action of the intent
+// pattern declaring it, or an error wrapping ErrUnknownExample
+func (c *IntentContract) Example(name string) (string, Example, error) {
+	for _, p := range c.Spec.IntentPatterns {
+		for _, e := range p.Examples {
+			if e.Name == name {
+				return p.Pattern.Action, e, nil
+			}
+		}
+	}
+	return "", Example{}, fmt.Errorf("%w: %s", ErrUnknownExample, name)
+}
+
+// validateExamples checks that the examples of p are named and that p
+// admits their parameters and its result schema, if any, their results.
+// seen holds the names of the examples of the contract checked so far.
+func (c *IntentContract) validateExamples(p IntentPattern, seen map[string]bool) error {
+	for _, e := range p.Examples {
+		if e.Name == "" {
+			return fmt.Errorf("example name is required")
+		}
+		if seen[e.Name] {
+			return fmt.Errorf("another example is named %s", e.Name)
+		}
+		seen[e.Name] = true
+		params, err := c.MapParameters(p.Pattern.Action, e.ParametersProto())
+		if err == nil {
+			err = p.Admit(params)
+		}
+		if err != nil {
+			return fmt.Errorf("example %s: %w", e.Name, err)
+		}
+		if p.Results != nil && e.Result != nil {
+			if err := p.Results.Check(valuesToProto(e.Result)); err != nil {
+				return fmt.Errorf("example %s: result: %w", e.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ToProto converts the example to protobuf format
+func (e Example) ToProto() *nfa_intent_v1alpha.IntentExample {
+	return &nfa_intent_v1alpha.IntentExample{
+		Name:        e.Name,
+		Description: e.Description,
+		Parameters:  valuesToProto(e.Parameters),
+		Result:      valuesToProto(e.Result),
+	}
+}
+
+// ExampleFromProto converts an example in protobuf format
+func ExampleFromProto(pb *nfa_intent_v1alpha.IntentExample) Example {
+	return Example{
+		Name:        pb.GetName(),
+		Description: pb.GetDescription(),
+		Parameters:  valuesFromProto(pb.GetParameters()),
+		Result:      valuesFromProto(pb.GetResult()),
+	}
+}
+
+// valuesToProto converts values decoded from YAML with ValueToProto,
+// leaving out those it does not convert; no values convert to nil
+func valuesToProto(values map[string]interface{}) map[string]*nfa_intent_v1alpha.Value {
+	if len(values) == 0 {
+		return nil
+	}
+	out := make(map[string]*nfa_intent_v1alpha.Value, len(values))
+	for name, v := range values {
+		if value := ValueToProto(v); value != nil {
+			out[name] = value
+		}
+	}
+	return out
+}
+
+func valuesFromProto(values map[string]*nfa_intent_v1alpha.Value) map[string]interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	out := make(map[string]interface{}, len(values))
+	for name, v := range values {
+		out[name] = ValueFromProto(v)
+	}
+	return out
+}
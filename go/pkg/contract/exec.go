@@ -0,0 +1,87 @@
+package contract
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ExecCommand is a local command serving intents, e.g. a device script. It
+// is run directly, never through a shell, so parameter values cannot inject
+// commands.
+type ExecCommand struct {
+	// Command is the absolute path of the program
+	Command string `yaml:"command"`
+	// Args are the arguments of the program; {name} in an argument stands
+	// for the value of parameter name
+	Args []string `yaml:"args,omitempty"`
+	// Stdin is what the program reads: ExecStdinPayload, ExecStdinParameters,
+	// or nothing when empty
+	Stdin string `yaml:"stdin,omitempty"`
+	// Timeout after which the program is killed, a duration such as "5s";
+	// empty means 10 seconds
+	Timeout string `yaml:"timeout,omitempty"`
+	// Env names the variables of the runtime's environment passed to the
+	// program, which gets no others
+	Env []string `yaml:"env,omitempty"`
+	// MaxOutputBytes caps the output of the program, which is killed when
+	// it writes more; 0 means 64 KiB
+	MaxOutputBytes int `yaml:"maxOutputBytes,omitempty"`
+}
+
+// What the program of an exec endpoint reads, see ExecCommand.Stdin
+const (
+	// ExecStdinPayload is the payload of the intent
+	ExecStdinPayload = "payload"
+	// ExecStdinParameters are the parameters of the intent as a JSON object
+	ExecStdinParameters = "parameters"
+)
+
+// execNumber matches the plain decimal numbers a dash may start, unlike
+// values such as "-inf" or "-1e5" that ParseFloat accepts too
+var execNumber = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// execPlaceholder matches the {name} placeholders of exec arguments
+var execPlaceholder = regexp.MustCompile(`\{([A-Za-z0-9_.-]+)\}`)
+
+// Placeholders returns the parameters the arguments refer to, in the order
+// they first appear
+func (c *ExecCommand) Placeholders() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, arg := range c.Args {
+		for _, m := range execPlaceholder.FindAllStringSubmatch(arg, -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				names = append(names, m[1])
+			}
+		}
+	}
+	return names
+}
+
+// ExpandArgs returns the arguments with their placeholders replaced by the
+// values of parameters. A parameter missing from parameters is an error, and
+// so is a value starting with "-" that is not a number, which the program
+// could take for an option.
+func (c *ExecCommand) ExpandArgs(parameters map[string]string) ([]string, error) {
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		var err error
+		args[i] = execPlaceholder.ReplaceAllStringFunc(arg, func(placeholder string) string {
+			name := placeholder[1 : len(placeholder)-1]
+			value, ok := parameters[name]
+			switch {
+			case !ok:
+				err = fmt.Errorf("parameter %s of argument %d is missing", name, i)
+			case strings.HasPrefix(value, "-") && !execNumber.MatchString(value):
+				err = fmt.Errorf("parameter %s of argument %d starts with a dash", name, i)
+			}
+			return value
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
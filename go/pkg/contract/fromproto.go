@@ -0,0 +1,206 @@
+package contract
+
+import (
+	"fmt"
+
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+)
+
+// FromProto converts a contract in protobuf format, e.g. one fetched from
+// the broker, back to its internal form. It is the inverse of ToProto except
+// where the protobuf form carries less: a unary streaming mode is left
+// empty, and a parameter's type is "string" or "number" as its constraint
+// implies, so "integer" and "boolean" types are lost. Enum values unknown to
+// this version fail with ErrInvalid.
+func FromProto(pb *nfa_intent_v1alpha.IntentContract) (*IntentContract, error) {
+	c := &IntentContract{
+		Version: pb.GetVersion(),
+		Kind:    pb.GetKind(),
+		Metadata: ContractMetadata{
+			Name:        pb.GetMetadata().GetName(),
+			Description: pb.GetMetadata().GetDescription(),
+			Labels:      pb.GetMetadata().GetLabels(),
+		},
+	}
+	spec := pb.GetSpec()
+	for i, p := range spec.GetIntentPatterns() {
+		pattern, err := IntentPatternFromProto(p)
+		if err != nil {
+			return nil, fmt.Errorf("%w: intent pattern %d (%s): %w", ErrInvalid, i, p.GetPattern().GetAction(), err)
+		}
+		c.Spec.IntentPatterns = append(c.Spec.IntentPatterns, pattern)
+	}
+
+	endpoint := spec.GetImplementation().GetEndpoint()
+	c.Spec.Implementation.Endpoint = Endpoint{Type: endpoint.GetType(), Codec: endpoint.GetCodec()}
+	switch address := endpoint.GetAddress().(type) {
+	case *nfa_intent_v1alpha.Endpoint_Grpc:
+		if address.Grpc.GetPort() != 0 {
+			port := int(address.Grpc.GetPort())
+			c.Spec.Implementation.Endpoint.Port = &port
+		}
+		c.Spec.Implementation.Endpoint.Host = address.Grpc.GetHost()
+		c.Spec.Implementation.Endpoint.Procedure = address.Grpc.GetProcedure()
+	case *nfa_intent_v1alpha.Endpoint_Http:
+		c.Spec.Implementation.Endpoint.URL = address.Http.GetUrl()
+	}
+	for _, r := range spec.GetImplementation().GetResources() {
+		c.Spec.Implementation.Resources = append(c.Spec.Implementation.Resources, ResourceRequirement{
+			Type:  r.GetType(),
+			Units: r.GetUnits(),
+			Kind:  r.GetKind(),
+		})
+	}
+
+	if qos := spec.GetQualityOfService(); qos != nil {
+		c.Spec.QualityOfService = &QualityOfService{
+			Latency:      qos.GetLatency(),
+			Availability: qos.GetAvailability(),
+			Priority:     qos.GetPriority(),
+		}
+	}
+	return c, nil
+}
+
+// IntentPatternFromProto converts an intent pattern in protobuf format
+func IntentPatternFromProto(pb *nfa_intent_v1alpha.IntentPattern) (IntentPattern, error) {
+	p := IntentPattern{
+		Pattern: Pattern{Action: pb.GetPattern().GetAction()},
+		Aliases: pb.GetAliases(),
+		Results: ResultSchemaFromProto(pb.GetResults()),
+	}
+	for _, m := range pb.GetMappings() {
+		p.Mappings = append(p.Mappings, ParameterMappingFromProto(m))
+	}
+	for _, e := range pb.GetExamples() {
+		p.Examples = append(p.Examples, ExampleFromProto(e))
+	}
+	if params := pb.GetPattern().GetParameters(); len(params) > 0 {
+		p.Pattern.Parameters = make(map[string]interface{}, len(params))
+		for name, value := range params {
+			p.Pattern.Parameters[name] = ValueFromProto(value)
+		}
+	}
+
+	streaming, err := StreamingModeFromProto(pb.GetStreaming())
+	if err != nil {
+		return p, err
+	}
+	p.Streaming = streaming
+
+	if cl := pb.GetClassification(); cl != nil {
+		p.Classification = &DataClassification{Regions: cl.GetRegions()}
+		switch cl.GetResidency() {
+		case nfa_intent_v1alpha.Residency_RESIDENCY_UNRESTRICTED:
+		case nfa_intent_v1alpha.Residency_RESIDENCY_ON_DEVICE:
+			p.Classification.Residency = ResidencyOnDevice
+		case nfa_intent_v1alpha.Residency_RESIDENCY_REGION:
+			p.Classification.Residency = ResidencyRegion
+		default:
+			return p, fmt.Errorf("unknown residency %d", cl.GetResidency())
+		}
+	}
+
+	if constraints := pb.GetConstraints(); constraints != nil {
+		p.Constraints = &PatternConstraints{RequiredParameters: constraints.GetRequiredParameters()}
+		if pcs := constraints.GetParameterConstraints(); len(pcs) > 0 {
+			p.Constraints.ParameterConstraints = make(map[string]ParameterConstraint, len(pcs))
+			for name, pc := range pcs {
+				p.Constraints.ParameterConstraints[name] = ParameterConstraintFromProto(pc)
+			}
+		}
+	}
+	return p, nil
+}
+
+// StreamingModeFromProto converts a streaming mode enum; unary converts to
+// the empty mode
+func StreamingModeFromProto(m nfa_intent_v1alpha.StreamingMode) (StreamingMode, error) {
+	switch m {
+	case nfa_intent_v1alpha.StreamingMode_STREAMING_MODE_UNARY:
+		return "", nil
+	case nfa_intent_v1alpha.StreamingMode_STREAMING_MODE_SERVER_STREAM:
+		return StreamingServer, nil
+	case nfa_intent_v1alpha.StreamingMode_STREAMING_MODE_CLIENT_STREAM:
+		return StreamingClient, nil
+	case nfa_intent_v1alpha.StreamingMode_STREAMING_MODE_BIDI:
+		return StreamingBidi, nil
+	}
+	return "", fmt.Errorf("unknown streaming mode %d", m)
+}
+
+// ParameterConstraintFromProto converts a parameter constraint in protobuf
+// format. A number constraint without bounds converts to type "number", and
+// string, array and object constraints to their type; bounds, patterns and
+// enum values imply their type.
+func ParameterConstraintFromProto(pb *nfa_intent_v1alpha.ParameterConstraint) ParameterConstraint {
+	pc := ParameterConstraint{
+		Sensitivity: SensitivityFromProto(pb.GetSensitivity()),
+		Default:     ValueFromProto(pb.GetDefaultValue()),
+	}
+	switch c := pb.GetConstraint().(type) {
+	case *nfa_intent_v1alpha.ParameterConstraint_StringConstraint:
+		pc.Type = "string"
+		pc.Pattern = c.StringConstraint.GetPattern()
+		pc.MinLength, pc.MaxLength = fromUint32(c.StringConstraint.MinLength), fromUint32(c.StringConstraint.MaxLength)
+	case *nfa_intent_v1alpha.ParameterConstraint_NumberConstraint:
+		pc.Min, pc.Max = c.NumberConstraint.Min, c.NumberConstraint.Max
+		if pc.Min == nil && pc.Max == nil {
+			pc.Type = "number"
+		}
+	case *nfa_intent_v1alpha.ParameterConstraint_EnumConstraint:
+		pc.EnumValues = c.EnumConstraint.GetValues()
+	case *nfa_intent_v1alpha.ParameterConstraint_ArrayConstraint:
+		pc.Type = "array"
+		pc.MinLength, pc.MaxLength = fromUint32(c.ArrayConstraint.MinLength), fromUint32(c.ArrayConstraint.MaxLength)
+		if items := c.ArrayConstraint.GetItems(); items != nil {
+			itemConstraint := ParameterConstraintFromProto(items)
+			pc.Items = &itemConstraint
+		}
+	case *nfa_intent_v1alpha.ParameterConstraint_ObjectConstraint:
+		pc.Type = "object"
+		pc.Required = c.ObjectConstraint.GetRequired()
+		if properties := c.ObjectConstraint.GetProperties(); len(properties) > 0 {
+			pc.Properties = make(map[string]ParameterConstraint, len(properties))
+			for name, property := range properties {
+				pc.Properties[name] = ParameterConstraintFromProto(property)
+			}
+		}
+	}
+	return pc
+}
+
+func fromUint32(n *uint32) *int {
+	if n == nil {
+		return nil
+	}
+	v := int(*n)
+	return &v
+}
+
+// ValueFromProto converts a value in protobuf format to a string, float64,
+// bool, []interface{} or map[string]interface{}, the inverse of ValueToProto.
+// nil converts to nil.
+func ValueFromProto(v *nfa_intent_v1alpha.Value) interface{} {
+	switch v := v.GetValue().(type) {
+	case *nfa_intent_v1alpha.Value_StringValue:
+		return v.StringValue
+	case *nfa_intent_v1alpha.Value_NumberValue:
+		return v.NumberValue
+	case *nfa_intent_v1alpha.Value_BoolValue:
+		return v.BoolValue
+	case *nfa_intent_v1alpha.Value_ListValue:
+		list := make([]interface{}, 0, len(v.ListValue.GetValues()))
+		for _, item := range v.ListValue.GetValues() {
+			list = append(list, ValueFromProto(item))
+		}
+		return list
+	case *nfa_intent_v1alpha.Value_StructValue:
+		fields := make(map[string]interface{}, len(v.StructValue.GetFields()))
+		for key, item := range v.StructValue.GetFields() {
+			fields[key] = ValueFromProto(item)
+		}
+		return fields
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ParseIntentContractJSON parses JSON data into an IntentContract. Keys are
+// those of the YAML form, e.g. "intentPatterns", and are checked as strictly
+// as ParseIntentContract checks YAML; see ParseOptions to allow unknown ones.
+func ParseIntentContractJSON(data []byte) (*IntentContract, error) {
+	return ParseOptions{}.ParseJSON(data)
+}
+
+// ParseJSON parses JSON data into an IntentContract. Unlike Parse, which also
+// accepts JSON since JSON is YAML, it rejects anything that is not a JSON
+// object, e.g. a YAML file passed by mistake, with the line and column of
+// the syntax error.
+func (o ParseOptions) ParseJSON(data []byte) (*IntentContract, error) {
+	if err := checkJSON(data); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	return o.Parse(data)
+}
+
+// isJSONFile reports whether path names a JSON contract, by its extension
+func isJSONFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+// checkJSON checks that data is a single JSON object
+func checkJSON(data []byte) error {
+	var v interface{}
+	err := json.Unmarshal(data, &v)
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line, column := position(data, syntaxErr.Offset)
+		return fmt.Errorf("json: line %d, column %d: %w", line, column, err)
+	}
+	if err != nil {
+		return fmt.Errorf("json: %w", err)
+	}
+	if _, ok := v.(map[string]interface{}); !ok {
+		return fmt.Errorf("json: contract must be an object")
+	}
+	return nil
+}
+
+// position returns the line and column, starting at 1, of the byte before
+// offset, where encoding/json reports a syntax error
+func position(data []byte, offset int64) (line, column int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:max(offset-1, 0)]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
@@ -0,0 +1,275 @@
+package contract
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Severity ranks a lint finding
+type Severity string
+
+const (
+	// SeverityWarning marks a contract that works but likely not as intended
+	SeverityWarning Severity = "warning"
+	// SeverityError marks a contract that registers but that providers or
+	// the broker cannot honour, e.g. an endpoint that cannot be dialed
+	SeverityError Severity = "error"
+)
+
+// Lint rules, the Rule of the findings they report
+const (
+	RuleMissingDescription = "missing-description"
+	RuleUnreachablePattern = "unreachable-pattern"
+	RuleUnusedRequired     = "unused-required-parameter"
+	RuleRequiredDefault    = "required-with-default"
+	RuleQoS                = "suspicious-qos"
+	RuleEndpoint           = "endpoint-mismatch"
+)
+
+// Finding is a problem Lint found in a contract
+type Finding struct {
+	Severity Severity `json:"severity"`
+	Rule     string   `json:"rule"`
+	// Path locates the problem in the contract, e.g. "spec.qualityOfService.latency"
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s [%s]", f.Severity, f.Path, f.Message, f.Rule)
+}
+
+// Lint checks a contract for problems Validate accepts: a missing
+// description, intent patterns no request can reach, required parameters
+// that no pattern or constraint refers to, QoS values the broker cannot
+// parse, e.g. latency "10sm", endpoints whose type, port and URL disagree,
+// and exec commands the runtime refuses to run. A contract failing Validate
+// is not linted; its error is returned instead.
+func Lint(c *IntentContract) ([]Finding, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	var l linter
+	if strings.TrimSpace(c.Metadata.Description) == "" {
+		l.warn(RuleMissingDescription, "metadata.description", "the contract has no description")
+	}
+	for i, p := range c.Spec.IntentPatterns {
+		path := fmt.Sprintf("spec.intentPatterns[%d]", i)
+		l.reachable(path, c.Spec.IntentPatterns[:i], p)
+		l.required(path, p)
+	}
+	l.qos(c.Spec.QualityOfService)
+	l.endpoint(c.Spec.Implementation.Endpoint, c.Spec.IntentPatterns)
+	return l.findings, nil
+}
+
+type linter struct {
+	findings []Finding
+}
+
+func (l *linter) warn(rule, path, format string, args ...interface{}) {
+	l.findings = append(l.findings, Finding{SeverityWarning, rule, path, fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) fail(rule, path, format string, args ...interface{}) {
+	l.findings = append(l.findings, Finding{SeverityError, rule, path, fmt.Sprintf(format, args...)})
+}
+
+// reachable reports a pattern that an earlier one always admits first, see
+// IntentContract.Admit, and aliases an earlier pattern already claims
+func (l *linter) reachable(path string, earlier []IntentPattern, p IntentPattern) {
+	for j, e := range earlier {
+		if e.Streaming.orUnary() != p.Streaming.orUnary() {
+			continue
+		}
+		if e.Pattern.Action == p.Pattern.Action && e.admitsAll() {
+			l.warn(RuleUnreachablePattern, path, "action %s is always matched by intent pattern %d, which has no constraints", p.Pattern.Action, j)
+			return
+		}
+		for _, alias := range p.Aliases {
+			if slices.Contains(e.Aliases, alias) {
+				l.warn(RuleUnreachablePattern, path+".aliases", "alias %s is already an alias of %s in intent pattern %d", alias, e.Pattern.Action, j)
+			}
+		}
+	}
+}
+
+// admitsAll reports whether the pattern admits any parameters
+func (p IntentPattern) admitsAll() bool {
+	return p.Constraints == nil ||
+		(len(p.Constraints.RequiredParameters) == 0 && len(p.Constraints.ParameterConstraints) == 0)
+}
+
+func (m StreamingMode) orUnary() StreamingMode {
+	if m == "" {
+		return StreamingUnary
+	}
+	return m
+}
+
+// required reports required parameters neither constrained nor referred to
+// by a placeholder, e.g. "to: @targetLanguage", and required parameters that
+// are never missing because their constraint has a default
+func (l *linter) required(path string, p IntentPattern) {
+	if p.Constraints == nil {
+		return
+	}
+	used := make(map[string]bool)
+	for _, v := range p.Pattern.Parameters {
+		if s, ok := v.(string); ok && strings.HasPrefix(s, "@") {
+			used[strings.TrimPrefix(s, "@")] = true
+		}
+	}
+	seen := make(map[string]bool)
+	for i, name := range p.Constraints.RequiredParameters {
+		at := fmt.Sprintf("%s.constraints.requiredParameters[%d]", path, i)
+		if seen[name] {
+			l.warn(RuleUnusedRequired, at, "parameter %s is listed more than once", name)
+			continue
+		}
+		seen[name] = true
+		pc, constrained := p.Constraints.ParameterConstraints[name]
+		switch {
+		case !constrained && !used[name]:
+			l.warn(RuleUnusedRequired, at, "parameter %s has no constraint and no pattern parameter refers to it", name)
+		case pc.Default != nil:
+			l.warn(RuleRequiredDefault, at, "parameter %s has a default, so it is never missing", name)
+		}
+	}
+}
+
+// qos reports QoS values the broker and the SLA reports cannot parse or
+// that make little sense
+func (l *linter) qos(q *QualityOfService) {
+	if q == nil {
+		return
+	}
+	if q.Latency != "" {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimLeft(q.Latency, "<= ")))
+		switch {
+		case err != nil:
+			l.fail(RuleQoS, "spec.qualityOfService.latency", "%q is not a duration such as 150ms", q.Latency)
+		case d <= 0:
+			l.fail(RuleQoS, "spec.qualityOfService.latency", "latency %s must be positive", q.Latency)
+		case d > time.Minute:
+			l.warn(RuleQoS, "spec.qualityOfService.latency", "latency %s is longer than a minute", q.Latency)
+		}
+	}
+	if q.Availability != "" {
+		text := strings.TrimSpace(q.Availability)
+		percent := strings.HasSuffix(text, "%")
+		v, err := strconv.ParseFloat(strings.TrimSuffix(text, "%"), 64)
+		switch {
+		case err != nil || v < 0 || v > 100:
+			l.fail(RuleQoS, "spec.qualityOfService.availability", "%q is not a ratio such as 0.995 or a percentage such as 99.5%%", q.Availability)
+		case !percent && v > 1:
+			l.fail(RuleQoS, "spec.qualityOfService.availability", "%q is read as a ratio above 1; write %s%%", q.Availability, text)
+		case percent && v < 1 && v > 0:
+			l.warn(RuleQoS, "spec.qualityOfService.availability", "%q is below 1 percent; did you mean %g%%", q.Availability, v*100)
+		}
+	}
+	switch strings.ToLower(q.Priority) {
+	case "", "low", "normal", "high":
+	default:
+		l.warn(RuleQoS, "spec.qualityOfService.priority", "priority %q is routed as normal; use low, normal or high", q.Priority)
+	}
+}
+
+// endpoint reports endpoints whose type and address fields disagree, and
+// exec commands the runtime refuses to run
+func (l *linter) endpoint(e Endpoint, patterns []IntentPattern) {
+	const path = "spec.implementation.endpoint"
+	if e.Port != nil && (*e.Port < 1 || *e.Port > 65535) {
+		l.fail(RuleEndpoint, path+".port", "port %d is outside 1-65535", *e.Port)
+	}
+	if e.Exec != nil && strings.ToLower(e.Type) != "exec" {
+		l.warn(RuleEndpoint, path+".exec", "command of a %s endpoint is ignored", e.Type)
+	}
+	switch strings.ToLower(e.Type) {
+	case "grpc":
+		if e.Port == nil {
+			l.fail(RuleEndpoint, path+".port", "gRPC endpoint has no port")
+		}
+		if e.URL != "" {
+			l.warn(RuleEndpoint, path+".url", "URL of a gRPC endpoint is ignored")
+		}
+	case "http":
+		if e.URL == "" {
+			l.fail(RuleEndpoint, path+".url", "HTTP endpoint has no URL")
+			return
+		}
+		u, err := url.Parse(e.URL)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			l.fail(RuleEndpoint, path+".url", "%q is not an http or https URL", e.URL)
+			return
+		}
+		if e.Procedure != "" {
+			l.warn(RuleEndpoint, path+".procedure", "procedure of an HTTP endpoint is ignored")
+		}
+		if e.Host != "" || e.Port != nil {
+			// ToProto prefers a gRPC address over the URL when both are set
+			l.fail(RuleEndpoint, path, "host and port make the HTTP endpoint a gRPC address; put them in the URL")
+		}
+		if e.Port != nil && u.Port() != "" && u.Port() != strconv.Itoa(*e.Port) {
+			l.fail(RuleEndpoint, path+".port", "port %d does not match the URL port %s", *e.Port, u.Port())
+		}
+	case "exec":
+		if e.Exec == nil {
+			l.fail(RuleEndpoint, path+".exec", "exec endpoint has no command")
+			return
+		}
+		if e.Host != "" || e.Port != nil || e.URL != "" {
+			l.warn(RuleEndpoint, path, "address of an exec endpoint is ignored; the runtime runs the command itself")
+		}
+		l.exec(path+".exec", *e.Exec, patterns)
+	default:
+		l.warn(RuleEndpoint, path+".type", "unknown endpoint type %q; use grpc, http or exec", e.Type)
+	}
+}
+
+// exec reports commands the runtime refuses to run, and arguments referring
+// to parameters that no pattern declares
+func (l *linter) exec(at string, c ExecCommand, patterns []IntentPattern) {
+	// Contracts may be linted on another OS than the device's
+	if !path.IsAbs(c.Command) && !filepath.IsAbs(c.Command) {
+		l.fail(RuleEndpoint, at+".command", "%q is not an absolute path; commands are not looked up in PATH", c.Command)
+	}
+	switch c.Stdin {
+	case "", ExecStdinPayload, ExecStdinParameters:
+	default:
+		l.fail(RuleEndpoint, at+".stdin", "unknown stdin %q; use payload or parameters", c.Stdin)
+	}
+	if c.Timeout != "" {
+		if d, err := time.ParseDuration(c.Timeout); err != nil || d <= 0 {
+			l.fail(RuleEndpoint, at+".timeout", "%q is not a positive duration such as 5s", c.Timeout)
+		}
+	}
+	if c.MaxOutputBytes < 0 {
+		l.fail(RuleEndpoint, at+".maxOutputBytes", "output cap %d is negative", c.MaxOutputBytes)
+	}
+	declared := make(map[string]bool)
+	for _, p := range patterns {
+		for name := range p.Pattern.Parameters {
+			declared[name] = true
+		}
+		if p.Constraints != nil {
+			for name := range p.Constraints.ParameterConstraints {
+				declared[name] = true
+			}
+			for _, name := range p.Constraints.RequiredParameters {
+				declared[name] = true
+			}
+		}
+	}
+	for _, name := range c.Placeholders() {
+		if !declared[name] {
+			l.warn(RuleEndpoint, at+".args", "parameter %s is not declared by any intent pattern", name)
+		}
+	}
+}
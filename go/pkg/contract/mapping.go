@@ -0,0 +1,235 @@
+package contract
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+)
+
+// ParameterMapping derives a parameter the provider expects from the
+// parameters consumers send, so providers whose parameter names or units
+// differ from their consumers' can serve them without code changes. It
+// either renames the consumer parameter From to To, optionally converting
+// its unit, or renders Template into To. A request that already has To is
+// left as is.
+type ParameterMapping struct {
+	// To is the parameter the provider expects
+	To string `yaml:"to"`
+	// From is the consumer parameter renamed to To; it is removed from the
+	// request unless Keep is set
+	From string `yaml:"from,omitempty"`
+	Keep bool   `yaml:"keep,omitempty"`
+	// Convert converts the number From holds
+	Convert *UnitConversion `yaml:"convert,omitempty"`
+	// Template is a Go text/template rendering To from the parameters, e.g.
+	// "{{.city}}, {{.country}}"; it is skipped when a parameter it refers to
+	// is missing
+	Template string `yaml:"template,omitempty"`
+}
+
+// UnitConversion converts a number between two units of the same quantity,
+// e.g. "ms" and "s" or "F" and "C", or by Scale and Offset: value*Scale+Offset.
+// Known units are ns, us, ms, s, min, h; mm, cm, m, km, in, ft, mi; mg, g,
+// kg, oz, lb; B, KB, MB, GB, KiB, MiB, GiB; C, F, K.
+type UnitConversion struct {
+	FromUnit string   `yaml:"fromUnit,omitempty"`
+	ToUnit   string   `yaml:"toUnit,omitempty"`
+	Scale    *float64 `yaml:"scale,omitempty"`
+	Offset   float64  `yaml:"offset,omitempty"`
+}
+
+// unit is a unit of a quantity: a value v in it is v*scale+offset in the
+// quantity's base unit
+type unit struct {
+	quantity      string
+	scale, offset float64
+}
+
+var units = map[string]unit{
+	"ns": {"time", 1e-9, 0}, "us": {"time", 1e-6, 0}, "µs": {"time", 1e-6, 0}, "ms": {"time", 1e-3, 0},
+	"s": {"time", 1, 0}, "min": {"time", 60, 0}, "h": {"time", 3600, 0},
+	"mm": {"length", 1e-3, 0}, "cm": {"length", 1e-2, 0}, "m": {"length", 1, 0}, "km": {"length", 1e3, 0},
+	"in": {"length", 0.0254, 0}, "ft": {"length", 0.3048, 0}, "mi": {"length", 1609.344, 0},
+	"mg": {"mass", 1e-6, 0}, "g": {"mass", 1e-3, 0}, "kg": {"mass", 1, 0},
+	"oz": {"mass", 0.028349523125, 0}, "lb": {"mass", 0.45359237, 0},
+	"B": {"data", 1, 0}, "KB": {"data", 1e3, 0}, "MB": {"data", 1e6, 0}, "GB": {"data", 1e9, 0},
+	"KiB": {"data", 1 << 10, 0}, "MiB": {"data", 1 << 20, 0}, "GiB": {"data", 1 << 30, 0},
+	"K": {"temperature", 1, 0}, "C": {"temperature", 1, 273.15}, "F": {"temperature", 5.0 / 9, 273.15 - 32*5.0/9},
+}
+
+// Validate checks that the conversion names two known units of the same
+// quantity, or has a scale
+func (u *UnitConversion) Validate() error {
+	if u.Scale != nil {
+		if u.FromUnit != "" || u.ToUnit != "" {
+			return fmt.Errorf("conversion has both units and a scale")
+		}
+		return nil
+	}
+	from, ok := units[u.FromUnit]
+	if !ok {
+		return fmt.Errorf("unknown unit %q", u.FromUnit)
+	}
+	to, ok := units[u.ToUnit]
+	if !ok {
+		return fmt.Errorf("unknown unit %q", u.ToUnit)
+	}
+	if from.quantity != to.quantity {
+		return fmt.Errorf("cannot convert %s (%s) to %s (%s)", u.FromUnit, from.quantity, u.ToUnit, to.quantity)
+	}
+	return nil
+}
+
+// Apply converts a value; the conversion must be valid. Results are rounded
+// to 12 significant digits, dropping the noise of floating point factors,
+// e.g. 212F is 100C rather than 100.00000000000006C.
+func (u *UnitConversion) Apply(v float64) float64 {
+	var out float64
+	if u.Scale != nil {
+		out = v*(*u.Scale) + u.Offset
+	} else {
+		from, to := units[u.FromUnit], units[u.ToUnit]
+		out = (v*from.scale + from.offset - to.offset) / to.scale
+	}
+	rounded, err := strconv.ParseFloat(strconv.FormatFloat(out, 'g', 12, 64), 64)
+	if err != nil {
+		return out
+	}
+	return rounded
+}
+
+// Validate checks that the mapping either renames or renders a template
+func (m ParameterMapping) Validate() error {
+	if m.To == "" {
+		return fmt.Errorf("mapping target is required")
+	}
+	switch {
+	case m.From == "" && m.Template == "":
+		return fmt.Errorf("mapping to %s needs from or template", m.To)
+	case m.From != "" && m.Template != "":
+		return fmt.Errorf("mapping to %s has both from and template", m.To)
+	case m.Template != "":
+		if m.Convert != nil || m.Keep {
+			return fmt.Errorf("mapping to %s: convert and keep apply to from only", m.To)
+		}
+		if _, err := template.New(m.To).Parse(m.Template); err != nil {
+			return fmt.Errorf("mapping to %s: invalid template: %w", m.To, err)
+		}
+	case m.From == m.To && m.Convert == nil:
+		return fmt.Errorf("mapping renames %s to itself", m.To)
+	}
+	if m.Convert != nil {
+		if err := m.Convert.Validate(); err != nil {
+			return fmt.Errorf("mapping to %s: %w", m.To, err)
+		}
+	}
+	return nil
+}
+
+// MapParameters applies the mappings of the first intent pattern declaring
+// action, or an alias of it, that has mappings, in order, and returns the
+// parameters the provider expects; params is not modified. A mapping whose
+// source is missing is skipped. A conversion of a value that is not a
+// number, or a template that fails, fails with a *ViolationError.
+func (c *IntentContract) MapParameters(action string, params map[string]*nfa_intent_v1alpha.Value) (map[string]*nfa_intent_v1alpha.Value, error) {
+	var mappings []ParameterMapping
+	for _, p := range c.Spec.IntentPatterns {
+		if len(p.Mappings) > 0 && (p.Pattern.Action == action || slices.Contains(p.Aliases, action)) {
+			mappings = p.Mappings
+			break
+		}
+	}
+	if len(mappings) == 0 {
+		return params, nil
+	}
+
+	out := make(map[string]*nfa_intent_v1alpha.Value, len(params)+len(mappings))
+	for name, value := range params {
+		out[name] = value
+	}
+	var violations []Violation
+	for _, m := range mappings {
+		if _, ok := out[m.To]; ok && m.From != m.To {
+			continue
+		}
+		if m.Template != "" {
+			value, ok, err := m.render(out)
+			if err != nil {
+				violations = append(violations, Violation{Parameter: m.To, Description: err.Error()})
+			} else if ok {
+				out[m.To] = value
+			}
+			continue
+		}
+		value, ok := out[m.From]
+		if !ok {
+			continue
+		}
+		if m.Convert != nil {
+			n, isNumber := value.GetValue().(*nfa_intent_v1alpha.Value_NumberValue)
+			if !isNumber {
+				violations = append(violations, Violation{Parameter: m.From, Description: "must be a number to convert"})
+				continue
+			}
+			value = ValueToProto(m.Convert.Apply(n.NumberValue))
+		}
+		if !m.Keep {
+			delete(out, m.From)
+		}
+		out[m.To] = value
+	}
+	if len(violations) > 0 {
+		sort.Slice(violations, func(i, j int) bool { return violations[i].Parameter < violations[j].Parameter })
+		return nil, &ViolationError{Action: action, Violations: violations}
+	}
+	return out, nil
+}
+
+// render executes the template of a mapping over params; ok is false when
+// it refers to a missing parameter
+func (m ParameterMapping) render(params map[string]*nfa_intent_v1alpha.Value) (value *nfa_intent_v1alpha.Value, ok bool, err error) {
+	tmpl, err := template.New(m.To).Option("missingkey=error").Parse(m.Template)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid template: %w", err)
+	}
+	data := make(map[string]interface{}, len(params))
+	for name, v := range params {
+		data[name] = ValueFromProto(v)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		if strings.Contains(err.Error(), "map has no entry for key") {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("template failed: %w", err)
+	}
+	return ValueToProto(b.String()), true, nil
+}
+
+// ToProto converts the mapping to protobuf format
+func (m ParameterMapping) ToProto() *nfa_intent_v1alpha.ParameterMapping {
+	out := &nfa_intent_v1alpha.ParameterMapping{To: m.To, From: m.From, Keep: m.Keep, Template: m.Template}
+	if m.Convert != nil {
+		out.Convert = &nfa_intent_v1alpha.UnitConversion{
+			FromUnit: m.Convert.FromUnit,
+			ToUnit:   m.Convert.ToUnit,
+			Scale:    m.Convert.Scale,
+			Offset:   m.Convert.Offset,
+		}
+	}
+	return out
+}
+
+// ParameterMappingFromProto converts a mapping in protobuf format
+func ParameterMappingFromProto(pb *nfa_intent_v1alpha.ParameterMapping) ParameterMapping {
+	m := ParameterMapping{To: pb.GetTo(), From: pb.GetFrom(), Keep: pb.GetKeep(), Template: pb.GetTemplate()}
+	if c := pb.GetConvert(); c != nil {
+		m.Convert = &UnitConversion{FromUnit: c.GetFromUnit(), ToUnit: c.GetToUnit(), Scale: c.Scale, Offset: c.GetOffset()}
+	}
+	return m
+}
@@ -0,0 +1,171 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Markdown renders the reference documentation of a contract: for every
+// intent pattern its parameters, its results and its examples, with the
+// request and the expected response of each as JSON. "nfactl contract docs"
+// writes it for a contract file.
+func Markdown(c *IntentContract) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n\n", c.Metadata.Name)
+	if c.Metadata.Description != "" {
+		fmt.Fprintf(&buf, "%s\n\n", c.Metadata.Description)
+	}
+	if q := c.Spec.QualityOfService; q != nil && (q.Latency != "" || q.Availability != "") {
+		var promises []string
+		if q.Latency != "" {
+			promises = append(promises, "latency "+q.Latency)
+		}
+		if q.Availability != "" {
+			promises = append(promises, "availability "+q.Availability)
+		}
+		fmt.Fprintf(&buf, "Quality of service: %s.\n\n", strings.Join(promises, ", "))
+	}
+
+	for _, p := range c.Spec.IntentPatterns {
+		fmt.Fprintf(&buf, "## %s\n\n", p.Pattern.Action)
+		if p.Streaming != "" && p.Streaming != StreamingUnary {
+			fmt.Fprintf(&buf, "Streaming: %s.\n\n", p.Streaming)
+		}
+		if len(p.Aliases) > 0 {
+			fmt.Fprintf(&buf, "Deprecated names: %s.\n\n", codeList(p.Aliases))
+		}
+		if literals := literalParameters(p); len(literals) > 0 {
+			fmt.Fprintf(&buf, "Matches requests with %s.\n\n", strings.Join(literals, ", "))
+		}
+
+		var required []string
+		var constraints map[string]ParameterConstraint
+		if p.Constraints != nil {
+			required, constraints = p.Constraints.RequiredParameters, p.Constraints.ParameterConstraints
+		}
+		if len(required) > 0 || len(constraints) > 0 {
+			buf.WriteString("### Parameters\n\n")
+			writeFields(&buf, required, constraints)
+		}
+		if r := p.Results; r != nil {
+			buf.WriteString("### Results\n\n")
+			if r.Description != "" {
+				fmt.Fprintf(&buf, "%s\n\n", r.Description)
+			}
+			if len(r.Required) > 0 || len(r.Fields) > 0 {
+				writeFields(&buf, r.Required, r.Fields)
+			}
+		}
+		if len(p.Examples) > 0 {
+			buf.WriteString("### Examples\n\n")
+		}
+		for _, e := range p.Examples {
+			fmt.Fprintf(&buf, "#### %s\n\n", e.Name)
+			if e.Description != "" {
+				fmt.Fprintf(&buf, "%s\n\n", e.Description)
+			}
+			buf.WriteString("Parameters:\n\n")
+			writeJSON(&buf, e.Parameters)
+			if e.Result != nil {
+				buf.WriteString("Expected result:\n\n")
+				writeJSON(&buf, e.Result)
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+// writeFields writes a table of the parameters or result fields with
+// constraints or required, by name
+func writeFields(buf *bytes.Buffer, required []string, constraints map[string]ParameterConstraint) {
+	names := make([]string, 0, len(constraints)+len(required))
+	for name := range constraints {
+		names = append(names, name)
+	}
+	for _, name := range required {
+		if _, ok := constraints[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	buf.WriteString("| Name | Type | Required | Constraints |\n|------|------|----------|-------------|\n")
+	for _, name := range names {
+		pc := constraints[name]
+		isRequired := "no"
+		for _, r := range required {
+			if r == name {
+				isRequired = "yes"
+			}
+		}
+		kind := pc.kind()
+		if kind == "" {
+			kind = "any"
+		}
+		fmt.Fprintf(buf, "| `%s` | %s | %s | %s |\n", name, kind, isRequired, describeConstraint(pc))
+	}
+	buf.WriteString("\n")
+}
+
+// describeConstraint summarizes the bounds of a constraint in words
+func describeConstraint(pc ParameterConstraint) string {
+	var parts []string
+	if len(pc.EnumValues) > 0 {
+		parts = append(parts, "one of "+codeList(pc.EnumValues))
+	}
+	if pc.Min != nil {
+		parts = append(parts, fmt.Sprintf("at least %v", *pc.Min))
+	}
+	if pc.Max != nil {
+		parts = append(parts, fmt.Sprintf("at most %v", *pc.Max))
+	}
+	if pc.MinLength != nil {
+		parts = append(parts, fmt.Sprintf("length at least %d", *pc.MinLength))
+	}
+	if pc.MaxLength != nil {
+		parts = append(parts, fmt.Sprintf("length at most %d", *pc.MaxLength))
+	}
+	if pc.Pattern != "" {
+		parts = append(parts, "matches `"+strings.ReplaceAll(pc.Pattern, "|", `\|`)+"`")
+	}
+	if pc.Default != nil {
+		parts = append(parts, fmt.Sprintf("defaults to `%v`", pc.Default))
+	}
+	if pc.Sensitivity != "" {
+		parts = append(parts, string(pc.Sensitivity))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// literalParameters returns the literal inline parameters of p a request
+// must match, e.g. "`room` = `kitchen`", sorted
+func literalParameters(p IntentPattern) []string {
+	var literals []string
+	for name, value := range p.Pattern.Parameters {
+		if s, ok := value.(string); ok && strings.HasPrefix(s, "@") {
+			continue
+		}
+		literals = append(literals, fmt.Sprintf("`%s` = `%v`", name, value))
+	}
+	sort.Strings(literals)
+	return literals
+}
+
+func codeList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = "`" + v + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func writeJSON(buf *bytes.Buffer, values map[string]interface{}) {
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	// Values decoded from YAML and FromProto always marshal
+	data, _ := json.MarshalIndent(values, "", "  ")
+	fmt.Fprintf(buf, "```json\n%s\n```\n\n", data)
+}
@@ -0,0 +1,179 @@
+package contract
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrInvalidResult is returned by ResultSchema.Check for a response outside
+// the schema
+var ErrInvalidResult = errors.New("response violates contract result schema")
+
+// ResultSchema describes the responses of an intent pattern by the proto
+// names of their fields, so consumers know what they get back before
+// calling. Providers may check their responses against it, see
+// CheckResult.
+type ResultSchema struct {
+	Description string `yaml:"description,omitempty"`
+	// Required lists the fields every response has
+	Required []string `yaml:"required,omitempty"`
+	// Fields constrains the fields of responses, as parameter constraints
+	// constrain parameters; undeclared fields are not checked
+	Fields map[string]ParameterConstraint `yaml:"fields,omitempty"`
+}
+
+// Validate checks the constraints of the fields
+func (r *ResultSchema) Validate() error {
+	for _, name := range r.Required {
+		if name == "" {
+			return fmt.Errorf("required result field name is empty")
+		}
+	}
+	for name, pc := range r.Fields {
+		if pc.Default != nil {
+			return fmt.Errorf("result field %s: defaults apply to parameters only", name)
+		}
+		if err := pc.Validate(); err != nil {
+			return fmt.Errorf("result field %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ResultError lists the fields of a response outside the result schema of
+// the intent pattern it was checked against, sorted by name. It matches
+// ErrInvalidResult with errors.Is.
+type ResultError struct {
+	Action     string
+	Violations []Violation
+}
+
+func (e *ResultError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = fmt.Sprintf("field %s: %s", v.Parameter, v.Description)
+	}
+	if e.Action == "" {
+		return fmt.Sprintf("%v: %s", ErrInvalidResult, strings.Join(msgs, "; "))
+	}
+	return fmt.Sprintf("%v: %s: %s", ErrInvalidResult, e.Action, strings.Join(msgs, "; "))
+}
+
+// Is reports whether target is ErrInvalidResult
+func (e *ResultError) Is(target error) bool {
+	return target == ErrInvalidResult
+}
+
+// Results returns the result schema of the first intent pattern declaring
+// action, or an alias of it, that has one, or nil
+func (c *IntentContract) Results(action string) *ResultSchema {
+	for _, p := range c.Spec.IntentPatterns {
+		if p.Results != nil && (p.Pattern.Action == action || slices.Contains(p.Aliases, action)) {
+			return p.Results
+		}
+	}
+	return nil
+}
+
+// Check checks the fields of a response against the schema: every required
+// field is present and every constrained field satisfies its constraint,
+// see ParameterConstraint.Check. Responses outside the schema fail with a
+// *ResultError listing all their violations.
+func (r *ResultSchema) Check(fields map[string]*nfa_intent_v1alpha.Value) error {
+	var violations []Violation
+	for _, name := range r.Required {
+		if fields[name] == nil {
+			violations = append(violations, Violation{Parameter: name, Description: "required but missing"})
+		}
+	}
+	for name, pc := range r.Fields {
+		if value := fields[name]; value != nil {
+			if err := pc.Check(value); err != nil {
+				violations = append(violations, Violation{Parameter: name, Description: err.Error()})
+			}
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	sort.Slice(violations, func(i, j int) bool { return violations[i].Parameter < violations[j].Parameter })
+	return &ResultError{Violations: violations}
+}
+
+// CheckResult checks the fields of a response to action against the
+// result schema of its intent pattern, see ResultSchema.Check. Responses to
+// actions without a result schema pass.
+func (c *IntentContract) CheckResult(action string, fields map[string]*nfa_intent_v1alpha.Value) error {
+	schema := c.Results(action)
+	if schema == nil {
+		return nil
+	}
+	err := schema.Check(fields)
+	var resultErr *ResultError
+	if errors.As(err, &resultErr) {
+		resultErr.Action = action
+	}
+	return err
+}
+
+// ResultFields returns the fields of a response message by their proto
+// names, in the form CheckResult checks: scalar fields are always present,
+// with their zero value when unset, and message fields when set. Values are
+// those of the message's JSON mapping, so 64-bit integers are strings and
+// enums their names.
+func ResultFields(msg proto.Message) (map[string]*nfa_intent_v1alpha.Value, error) {
+	data, err := protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response fields: %w", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to read response fields: %w", err)
+	}
+	fields := make(map[string]*nfa_intent_v1alpha.Value, len(decoded))
+	for name, v := range decoded {
+		if value := ValueToProto(v); value != nil {
+			fields[name] = value
+		}
+	}
+	return fields, nil
+}
+
+// ToProto converts the schema to protobuf format
+func (r *ResultSchema) ToProto() *nfa_intent_v1alpha.ResultSchema {
+	if r == nil {
+		return nil
+	}
+	out := &nfa_intent_v1alpha.ResultSchema{Description: r.Description, Required: r.Required}
+	if len(r.Fields) > 0 {
+		out.Fields = make(map[string]*nfa_intent_v1alpha.ParameterConstraint, len(r.Fields))
+		for name, pc := range r.Fields {
+			out.Fields[name] = pc.ToProto()
+		}
+	}
+	return out
+}
+
+// ResultSchemaFromProto converts a result schema in protobuf format; nil
+// converts to nil
+func ResultSchemaFromProto(pb *nfa_intent_v1alpha.ResultSchema) *ResultSchema {
+	if pb == nil {
+		return nil
+	}
+	r := &ResultSchema{Description: pb.GetDescription(), Required: pb.GetRequired()}
+	if fields := pb.GetFields(); len(fields) > 0 {
+		r.Fields = make(map[string]ParameterConstraint, len(fields))
+		for name, pc := range fields {
+			r.Fields[name] = ParameterConstraintFromProto(pc)
+		}
+	}
+	return r
+}
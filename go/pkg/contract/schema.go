@@ -0,0 +1,296 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract/v1beta"
+)
+
+// schemaDialect is the JSON Schema draft JSONSchema emits
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Patterns of the QoS values and exec timeouts Lint parses, in the common
+// subset of RE2 and ECMAScript regular expressions that editors evaluate
+const (
+	latencyPattern      = `^\s*(<=\s*)?([0-9]+(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+\s*$`
+	durationPattern     = `^([0-9]+(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+$`
+	availabilityPattern = `^\s*[0-9]+(\.[0-9]+)?%?\s*$`
+)
+
+// schemaRequired lists the keys each type of the schema requires, by type
+// name; types of both versions with the same name mean the same
+var schemaRequired = map[string][]string{
+	"IntentContract":      {"version", "kind", "metadata", "spec"},
+	"ContractMetadata":    {"name"},
+	"Metadata":            {"name"},
+	"IntentSpec":          {"intentPatterns", "implementation"},
+	"Spec":                {"intents", "implementation"},
+	"IntentPattern":       {"pattern"},
+	"Pattern":             {"action"},
+	"Intent":              {"action"},
+	"Implementation":      {"endpoint"},
+	"Endpoint":            {"type"},
+	"ResourceRequirement": {"type", "units"},
+	"ParameterMapping":    {"to"},
+	"ExecCommand":         {"command"},
+	"Example":             {"name"},
+}
+
+// schemaKeywords adds keywords to the schema of a key, by type name and key
+var schemaKeywords = map[string]map[string]interface{}{
+	"IntentContract.kind": {"const": "IntentContract"},
+	"ContractMetadata.name": {
+		"minLength":   1,
+		"description": "Name of the contract, from which the broker derives service IDs",
+	},
+	"Metadata.name": {
+		"minLength":   1,
+		"description": "Name of the contract, from which the broker derives service IDs",
+	},
+	"IntentSpec.intentPatterns": {"minItems": 1, "description": "Intents the service serves"},
+	"Spec.intents":              {"minItems": 1, "description": "Intents the service serves"},
+	"Pattern.action":            {"minLength": 1, "description": "Action of the intent, e.g. translate.text"},
+	"Intent.action":             {"minLength": 1, "description": "Action of the intent, e.g. translate.text"},
+	"Intent.match": {
+		"description": `Inline parameters of the pattern; "@name" refers to a request parameter`,
+	},
+	"IntentPattern.streaming": streamingKeywords,
+	"Intent.streaming":        streamingKeywords,
+	"IntentPattern.aliases":   aliasesKeywords,
+	"Intent.aliases":          aliasesKeywords,
+	"PatternConstraints.requiredParameters": {
+		"uniqueItems": true,
+		"description": "Parameters every request must supply, unless their constraint has a default",
+	},
+	"ParameterConstraint.type":        typeKeywords,
+	"Parameter.type":                  typeKeywords,
+	"ParameterConstraint.enumValues":  {"minItems": 1, "description": "Values a string parameter may take"},
+	"Parameter.enum":                  {"minItems": 1, "description": "Values a string parameter may take"},
+	"ParameterConstraint.pattern":     patternKeywords,
+	"Parameter.pattern":               patternKeywords,
+	"ParameterConstraint.minLength":   lengthKeywords,
+	"ParameterConstraint.maxLength":   lengthKeywords,
+	"Parameter.minLength":             lengthKeywords,
+	"Parameter.maxLength":             lengthKeywords,
+	"ParameterConstraint.sensitivity": sensitivityKeywords,
+	"Parameter.sensitivity":           sensitivityKeywords,
+	"ParameterConstraint.default":     {"description": "Value used when neither the request nor its context supplies the parameter"},
+	"Parameter.default":               {"description": "Value used when neither the request nor its context supplies the parameter"},
+	"ParameterConstraint.required":    {"uniqueItems": true, "description": "Properties an object must have, unless their constraint has a default"},
+	"Parameter.required":              {"description": "Whether requests must supply the parameter, or an object the property"},
+	"IntentPattern.mappings":          mappingsKeywords,
+	"Intent.mappings":                 mappingsKeywords,
+	"IntentPattern.results":           resultsKeywords,
+	"Intent.results":                  resultsKeywords,
+	"ResultSchema.required":           {"uniqueItems": true, "description": "Fields every response has"},
+	"ResultSchema.fields":             {"description": "Constraints on the fields of responses, by their proto names"},
+	"Results.fields":                  {"description": "Constraints on the fields of responses, by their proto names"},
+	"IntentPattern.examples":          examplesKeywords,
+	"Intent.examples":                 examplesKeywords,
+	"Example.name":                    {"minLength": 1, "description": "Name of the example, unique within the contract"},
+	"Example.parameters":              {"description": "Parameters a consumer sends, which the intent's constraints must admit"},
+	"Example.result":                  {"description": "Fields of the expected response by their proto names; unlisted fields are not compared"},
+	"ParameterMapping.to":             {"minLength": 1, "description": "Parameter the provider expects"},
+	"ParameterMapping.from":           {"description": "Consumer parameter renamed to the target, removed unless keep is set"},
+	"ParameterMapping.template": {
+		"description": "Go text/template rendering the target from the parameters, e.g. {{.city}}",
+	},
+	"UnitConversion.fromUnit": unitKeywords,
+	"UnitConversion.toUnit":   unitKeywords,
+	"UnitConversion.scale":    {"description": "Factor of a conversion without units: value*scale+offset"},
+	"DataClassification.residency": {
+		"enum":        []string{"", string(ResidencyOnDevice), string(ResidencyRegion)},
+		"description": "Where the data of the intent may be processed; empty means unrestricted",
+	},
+	"DataClassification.regions": {"description": `Regions of providers allowed with residency "region", by their nfa.region label`},
+	"Endpoint.type":              {"enum": []string{"grpc", "http", "exec"}},
+	"Endpoint.port":              {"minimum": 1, "maximum": 65535},
+	"Endpoint.url":               {"format": "uri", "description": "URL of an HTTP endpoint"},
+	"Endpoint.host":              {"description": "Fixed host of a provider that does not register itself"},
+	"Endpoint.codec": {
+		"pattern":     "^[a-z0-9.+_-]*$",
+		"description": `Serialization of request messages by gRPC content subtype, e.g. "json" or "msgpack"; empty means protobuf`,
+	},
+	"Endpoint.exec":       {"description": "Local command of an exec endpoint, run by the runtime for each intent"},
+	"ExecCommand.command": {"minLength": 1, "description": "Absolute path of the program, run without a shell"},
+	"ExecCommand.args":    {"description": "Arguments of the program; {name} stands for the value of parameter name"},
+	"ExecCommand.stdin": {
+		"enum":        []string{"", ExecStdinPayload, ExecStdinParameters},
+		"description": "What the program reads: the payload, the parameters as a JSON object, or nothing",
+	},
+	"ExecCommand.timeout": {
+		"pattern":     durationPattern,
+		"description": `Time after which the program is killed, e.g. "5s"; defaults to 10s`,
+	},
+	"ExecCommand.env":            {"uniqueItems": true, "description": "Variables of the runtime's environment passed to the program, which gets no others"},
+	"ExecCommand.maxOutputBytes": {"minimum": 0, "description": "Cap on the output of the program; defaults to 64 KiB"},
+	"QualityOfService.latency": {
+		"pattern":     latencyPattern,
+		"description": `Latency the service promises, a duration such as "150ms" or "<=150ms"`,
+	},
+	"QualityOfService.availability": {
+		"pattern":     availabilityPattern,
+		"description": `Availability the service promises, a ratio such as 0.995 or a percentage such as "99.5%"`,
+	},
+	"QualityOfService.priority": {
+		"enum":        []string{"low", "normal", "high"},
+		"description": "Priority of the service's requests; interactive requests prefer high priority services",
+	},
+}
+
+var (
+	streamingKeywords = map[string]interface{}{
+		"enum":        []string{string(StreamingUnary), string(StreamingServer), string(StreamingClient), string(StreamingBidi)},
+		"description": "Streaming mode of the intent; empty means unary",
+	}
+	aliasesKeywords = map[string]interface{}{
+		"uniqueItems": true,
+		"items":       map[string]interface{}{"type": "string", "minLength": 1},
+		"description": "Former names of the action, still served until consumers move off them",
+	}
+	resultsKeywords = map[string]interface{}{
+		"description": "Responses of the provider, so consumers know what they get back",
+	}
+	examplesKeywords = map[string]interface{}{
+		"description": "Named requests and their expected responses, for documentation, API explorers and nfactl invoke -example",
+	}
+	mappingsKeywords = map[string]interface{}{
+		"description": "Derive the parameters the provider expects from those consumers send, in order",
+	}
+	unitKeywords = map[string]interface{}{
+		"enum": unitNames(),
+	}
+	typeKeywords = map[string]interface{}{
+		"enum": []string{"string", "number", "integer", "boolean", "array", "object"},
+	}
+	patternKeywords = map[string]interface{}{
+		"format":      "regex",
+		"description": "Regular expression, in RE2 syntax, that string values must match",
+	}
+	lengthKeywords = map[string]interface{}{
+		"minimum":     0,
+		"description": "Bound on the characters of a string or the items of an array",
+	}
+	sensitivityKeywords = map[string]interface{}{
+		"enum":        []string{string(SensitivityPII), string(SensitivitySensitive)},
+		"description": "Marks values redacted in logs, traces, audit events and analytics",
+	}
+)
+
+// JSONSchema returns a JSON Schema (draft 2020-12) of contracts of a schema
+// version, or of every version in Versions when version is empty, for
+// editors and CI validators checking contracts without this package. It
+// covers the structure, the known values of enumerated fields and the
+// format of QoS values; checks across fields, e.g. that a default
+// satisfies its constraint, are left to Validate and Lint.
+func JSONSchema(version string) ([]byte, error) {
+	roots := map[string]reflect.Type{
+		VersionV1Alpha: reflect.TypeOf(IntentContract{}),
+		VersionV1Beta:  reflect.TypeOf(v1beta.IntentContract{}),
+	}
+	versions := Versions
+	if version != "" {
+		if !slices.Contains(Versions, version) {
+			return nil, fmt.Errorf("%w: unsupported version: %s", ErrInvalid, version)
+		}
+		versions = []string{version}
+	}
+
+	defs := make(map[string]interface{})
+	var refs []interface{}
+	for _, v := range versions {
+		b := schemaBuilder{version: v, defs: defs}
+		refs = append(refs, b.schema(roots[v]))
+	}
+	schema := map[string]interface{}{
+		"$schema": schemaDialect,
+		"title":   "NFA intent contract",
+		"$defs":   defs,
+	}
+	if len(refs) == 1 {
+		schema["title"] = "NFA intent contract " + versions[0]
+		schema["$ref"] = refs[0].(map[string]interface{})["$ref"]
+	} else {
+		schema["oneOf"] = refs
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// schemaBuilder derives the schema of a version from its Go types, adding a
+// definition per struct type to defs under the version, e.g.
+// "v1alpha.Endpoint"
+type schemaBuilder struct {
+	version string
+	defs    map[string]interface{}
+}
+
+func (b schemaBuilder) schema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		name := b.version + "." + t.Name()
+		if _, ok := b.defs[name]; !ok {
+			b.defs[name] = nil // parameter constraints nest
+			b.defs[name] = b.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + name}
+	}
+	return map[string]interface{}{} // any value, e.g. a default
+}
+
+// object returns the schema of a struct type, which rejects keys it does
+// not define, as ParseIntentContract does, unless it has an inline map
+func (b schemaBuilder) object(t reflect.Type) map[string]interface{} {
+	fields, inline := yamlFields(t)
+	properties := make(map[string]interface{}, len(fields))
+	for key, field := range fields {
+		s := b.schema(field)
+		for keyword, value := range schemaKeywords[t.Name()+"."+key] {
+			s[keyword] = value
+		}
+		properties[key] = s
+	}
+	if t.Name() == "IntentContract" {
+		properties["version"].(map[string]interface{})["const"] = b.version
+	}
+	out := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": inline,
+	}
+	if required, ok := schemaRequired[t.Name()]; ok {
+		out["required"] = required
+	}
+	return out
+}
+
+// unitNames returns the units UnitConversion knows, sorted
+func unitNames() []string {
+	names := make([]string, 0, len(units))
+	for name := range units {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,304 @@
+package contract
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract/v1beta"
+	"gopkg.in/yaml.v3"
+)
+
+// ParseOptions changes how contracts are parsed. The zero value parses
+// strictly, like ParseIntentContract.
+type ParseOptions struct {
+	// AllowUnknownFields ignores keys the contract schema does not define,
+	// as parsing did before it was strict, e.g. for contracts written for a
+	// newer version of the schema
+	AllowUnknownFields bool
+}
+
+// Parse parses YAML data, or JSON, into an IntentContract. Contracts in the
+// v1beta schema are converted to the internal form, keeping their version;
+// any other version is parsed as v1alpha, which Validate checks. Unless unknown fields are
+// allowed, keys the schema does not define fail with ErrInvalid and a
+// *FieldError per key, so a typo such as "intentPattern:" is reported where
+// it is instead of leaving the contract without patterns.
+func (o ParseOptions) Parse(data []byte) (*IntentContract, error) {
+	var contract IntentContract
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	if doc.Kind == 0 {
+		return &contract, nil // empty document
+	}
+	var target interface{} = &contract
+	var beta v1beta.IntentContract
+	if documentVersion(&doc) == VersionV1Beta {
+		target = &beta
+	}
+	if !o.AllowUnknownFields {
+		var errs []error
+		root := reflect.TypeOf(target).Elem()
+		checkKeys(&doc, root, root, "", nil, &errs)
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("%w: %w", ErrInvalid, errors.Join(errs...))
+		}
+	}
+	if err := doc.Decode(target); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	if target == &beta {
+		return FromV1Beta(&beta), nil
+	}
+	return &contract, nil
+}
+
+// documentVersion returns the value of the top-level version key of a
+// contract document, or an empty string without one
+func documentVersion(doc *yaml.Node) string {
+	node := doc
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return ""
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "version" {
+			return node.Content[i+1].Value
+		}
+	}
+	return ""
+}
+
+// LoadFile reads and parses an intent contract file, as JSON when its
+// extension is .json and as YAML otherwise
+func (o ParseOptions) LoadFile(path string) (*IntentContract, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contract file: %w", err)
+	}
+	parse := o.Parse
+	if isJSONFile(path) {
+		parse = o.ParseJSON
+	}
+	contract, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse contract: %w", err)
+	}
+	return contract, nil
+}
+
+// FieldError reports a key of a contract that the schema does not define
+// where it appears
+type FieldError struct {
+	// Line and Column locate the key in the YAML document, starting at 1
+	Line, Column int
+	// Path is where the key appears, e.g. "spec.intentPatterns[0]"
+	Path string
+	Key  string
+	// Hint suggests a fix: the key meant, or where the key belongs
+	Hint string
+}
+
+func (e *FieldError) Error() string {
+	where := e.Path
+	if where == "" {
+		where = "the contract"
+	}
+	msg := fmt.Sprintf("line %d, column %d: unknown key %q in %s", e.Line, e.Column, e.Key, where)
+	if e.Hint != "" {
+		msg += " (" + e.Hint + ")"
+	}
+	return msg
+}
+
+// checkKeys reports the keys of mapping nodes that type t does not define,
+// descending into the nodes of the fields it does. root is the contract type
+// of the schema version, for hints. parentKeys are the keys of the mapping
+// enclosing node, which a key of an inline map most likely belongs to when
+// it matches one.
+func checkKeys(node *yaml.Node, root, t reflect.Type, path string, parentKeys []string, errs *[]error) {
+	if node.Kind == yaml.DocumentNode {
+		for _, child := range node.Content {
+			checkKeys(child, root, t, path, parentKeys, errs)
+		}
+		return
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			checkKeys(item, root, t.Elem(), fmt.Sprintf("%s[%d]", path, i), parentKeys, errs)
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			checkKeys(node.Content[i+1], root, t.Elem(), join(path, node.Content[i].Value), nil, errs)
+		}
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields, inline := yamlFields(t)
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if field, ok := fields[key.Value]; ok {
+				checkKeys(value, root, field, join(path, key.Value), keys, errs)
+				continue
+			}
+			hint := ""
+			if inline {
+				// Any other key is a parameter of the pattern, unless it is
+				// a key of the enclosing mapping indented one level too deep
+				if !contains(parentKeys, key.Value) {
+					continue
+				}
+				hint = "it belongs one level up; check its indentation"
+			} else {
+				hint = keyHint(key.Value, keys, root)
+			}
+			*errs = append(*errs, &FieldError{
+				Line:   key.Line,
+				Column: key.Column,
+				Path:   path,
+				Key:    key.Value,
+				Hint:   hint,
+			})
+		}
+	}
+}
+
+// yamlFields returns the keys a struct type defines and the type of each,
+// and whether it has an inline map accepting any other key
+func yamlFields(t reflect.Type) (fields map[string]reflect.Type, inline bool) {
+	fields = make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if strings.Contains(opts, "inline") {
+			if f.Type.Kind() == reflect.Map {
+				inline = true
+				continue
+			}
+			inner, innerInline := yamlFields(f.Type)
+			for k, v := range inner {
+				fields[k] = v
+			}
+			inline = inline || innerInline
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields, inline
+}
+
+// keyHint suggests the known key closest to an unknown one, or where in the
+// schema of root the key belongs when it is defined elsewhere
+func keyHint(key string, known []string, root reflect.Type) string {
+	best, bestDist := "", 3
+	for _, candidate := range known {
+		if d := editDistance(strings.ToLower(key), strings.ToLower(candidate)); d < bestDist {
+			best, bestDist = candidate, d
+		}
+	}
+	if best != "" {
+		return fmt.Sprintf("did you mean %q?", best)
+	}
+	if where, ok := schemaKeys(root)[key]; ok {
+		return "it belongs in " + where
+	}
+	return ""
+}
+
+// schemaKeys maps every key of the contract schema of root to the first
+// place it is defined, e.g. "requiredParameters" to
+// "spec.intentPatterns[].constraints"
+func schemaKeys(root reflect.Type) map[string]string {
+	keys := make(map[string]string)
+	seen := make(map[reflect.Type]bool) // parameter constraints nest
+	var walk func(t reflect.Type, path string)
+	walk = func(t reflect.Type, path string) {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Slice:
+			walk(t.Elem(), path+"[]")
+		case reflect.Map:
+			walk(t.Elem(), path+".<name>")
+		case reflect.Struct:
+			if seen[t] {
+				return
+			}
+			seen[t] = true
+			fields, _ := yamlFields(t)
+			names := make([]string, 0, len(fields))
+			for name := range fields {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				if _, ok := keys[name]; !ok {
+					keys[name] = strings.TrimPrefix(path, ".")
+					if keys[name] == "" {
+						keys[name] = "the top level"
+					}
+				}
+				walk(fields[name], path+"."+name)
+			}
+		}
+	}
+	walk(root, "")
+	return keys
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func contains(values []string, v string) bool {
+	for _, candidate := range values {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
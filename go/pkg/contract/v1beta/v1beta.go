@@ -0,0 +1,157 @@
+// Package v1beta defines the v1beta schema of intent contracts. It describes
+// the same contracts as v1alpha in a flatter form: an intent names its action
+// directly, and each parameter declares whether it is required next to its
+// constraint:
+//
+//	version: v1beta
+//	kind: IntentContract
+//	metadata:
+//	  name: translator
+//	spec:
+//	  intents:
+//	    - action: translate.text
+//	      match:
+//	        to: "@targetLanguage"
+//	      parameters:
+//	        text: {type: string, required: true, maxLength: 5000}
+//	        targetLanguage: {enum: [en, fr], default: en}
+//	  implementation:
+//	    endpoint: {type: grpc, port: 50051}
+//	  qos:
+//	    latency: 150ms
+//
+// Package contract parses either version and converts between them; the
+// types here only carry the document.
+package v1beta
+
+// Version is the version key of v1beta contracts
+const Version = "v1beta"
+
+// IntentContract is an intent contract in the v1beta schema
+type IntentContract struct {
+	Version  string   `yaml:"version"`
+	Kind     string   `yaml:"kind"`
+	Metadata Metadata `yaml:"metadata"`
+	Spec     Spec     `yaml:"spec"`
+}
+
+type Metadata struct {
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+}
+
+type Spec struct {
+	Intents        []Intent          `yaml:"intents"`
+	Implementation Implementation    `yaml:"implementation"`
+	QoS            *QualityOfService `yaml:"qos,omitempty"`
+}
+
+// Intent declares an action the service serves, the v1alpha intent pattern
+type Intent struct {
+	Action string `yaml:"action"`
+	// Match holds the inline parameters of the v1alpha pattern, e.g.
+	// "to: @targetLanguage"
+	Match map[string]interface{} `yaml:"match,omitempty"`
+	// Parameters constrain the parameters of requests by name
+	Parameters map[string]Parameter `yaml:"parameters,omitempty"`
+	// Streaming is unary, server, client or bidi; empty means unary
+	Streaming      string              `yaml:"streaming,omitempty"`
+	Classification *DataClassification `yaml:"classification,omitempty"`
+	Aliases        []string            `yaml:"aliases,omitempty"`
+	// Mappings derive provider parameters from consumer ones, as in v1alpha
+	Mappings []ParameterMapping `yaml:"mappings,omitempty"`
+	Results  *Results           `yaml:"results,omitempty"`
+	Examples []Example          `yaml:"examples,omitempty"`
+}
+
+// Example is a named request of an intent and its expected response, as in
+// v1alpha
+type Example struct {
+	Name        string                 `yaml:"name"`
+	Description string                 `yaml:"description,omitempty"`
+	Parameters  map[string]interface{} `yaml:"parameters,omitempty"`
+	Result      map[string]interface{} `yaml:"result,omitempty"`
+}
+
+// Results describes the responses of an intent, the v1alpha result schema
+// with fields marked required as parameters are
+type Results struct {
+	Description string               `yaml:"description,omitempty"`
+	Fields      map[string]Parameter `yaml:"fields,omitempty"`
+}
+
+// Parameter constrains a parameter, or a property of an object parameter.
+// The fields are those of the v1alpha parameter constraint, with enumValues
+// renamed enum, and Required replacing the lists of required names.
+type Parameter struct {
+	Required    bool                 `yaml:"required,omitempty"`
+	Type        string               `yaml:"type,omitempty"`
+	Enum        []string             `yaml:"enum,omitempty"`
+	Min         *float64             `yaml:"min,omitempty"`
+	Max         *float64             `yaml:"max,omitempty"`
+	Pattern     string               `yaml:"pattern,omitempty"`
+	MinLength   *int                 `yaml:"minLength,omitempty"`
+	MaxLength   *int                 `yaml:"maxLength,omitempty"`
+	Items       *Parameter           `yaml:"items,omitempty"`
+	Properties  map[string]Parameter `yaml:"properties,omitempty"`
+	Sensitivity string               `yaml:"sensitivity,omitempty"`
+	Default     interface{}          `yaml:"default,omitempty"`
+}
+
+type ParameterMapping struct {
+	To       string          `yaml:"to"`
+	From     string          `yaml:"from,omitempty"`
+	Keep     bool            `yaml:"keep,omitempty"`
+	Convert  *UnitConversion `yaml:"convert,omitempty"`
+	Template string          `yaml:"template,omitempty"`
+}
+
+type UnitConversion struct {
+	FromUnit string   `yaml:"fromUnit,omitempty"`
+	ToUnit   string   `yaml:"toUnit,omitempty"`
+	Scale    *float64 `yaml:"scale,omitempty"`
+	Offset   float64  `yaml:"offset,omitempty"`
+}
+
+type DataClassification struct {
+	Residency string   `yaml:"residency"`
+	Regions   []string `yaml:"regions,omitempty"`
+}
+
+type Implementation struct {
+	Endpoint  Endpoint              `yaml:"endpoint"`
+	Resources []ResourceRequirement `yaml:"resources,omitempty"`
+}
+
+type Endpoint struct {
+	Type      string `yaml:"type"`
+	Host      string `yaml:"host,omitempty"`
+	Port      *int   `yaml:"port,omitempty"`
+	Procedure string `yaml:"procedure,omitempty"`
+	URL       string `yaml:"url,omitempty"`
+	Codec     string `yaml:"codec,omitempty"`
+	// Exec is the local command of an exec endpoint
+	Exec *ExecCommand `yaml:"exec,omitempty"`
+}
+
+type ExecCommand struct {
+	Command        string   `yaml:"command"`
+	Args           []string `yaml:"args,omitempty"`
+	Stdin          string   `yaml:"stdin,omitempty"`
+	Timeout        string   `yaml:"timeout,omitempty"`
+	Env            []string `yaml:"env,omitempty"`
+	MaxOutputBytes int      `yaml:"maxOutputBytes,omitempty"`
+}
+
+type ResourceRequirement struct {
+	Type  string `yaml:"type"`
+	Units string `yaml:"units"`
+	Kind  string `yaml:"kind,omitempty"`
+}
+
+type QualityOfService struct {
+	Latency      string `yaml:"latency,omitempty"`
+	Availability string `yaml:"availability,omitempty"`
+	Priority     string `yaml:"priority,omitempty"`
+}
@@ -0,0 +1,102 @@
+// Package endpoint maps the endpoints declared in provider contracts to
+// addresses a consumer can dial. A contract may name its provider by a DNS
+// hostname, a Kubernetes service or a device ID instead of an address; a
+// Resolver turns the declaration into "host:port" for gRPC or a URL for HTTP
+// when the provider is invoked, see runtime.ResolvingDialer.
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+)
+
+// ErrUnresolved is returned by a Resolver for endpoints it does not know, so
+// that a Chain tries the next one
+var ErrUnresolved = errors.New("endpoint not resolved")
+
+// Resolver maps an endpoint declaration to a dialable address: "host:port"
+// for a gRPC endpoint and a URL for an HTTP one
+type Resolver interface {
+	Resolve(ctx context.Context, e contract.Endpoint) (string, error)
+}
+
+// HostFunc resolves the host of an endpoint to an address, either a host or
+// "host:port". Its Resolve keeps the declared port when the address has none
+// and the path of HTTP endpoints.
+type HostFunc func(ctx context.Context, host string) (string, error)
+
+// Resolve implements Resolver
+func (f HostFunc) Resolve(ctx context.Context, e contract.Endpoint) (string, error) {
+	if e.URL != "" {
+		return resolveURL(ctx, e.URL, f)
+	}
+	if e.Host == "" {
+		return "", fmt.Errorf("%w: endpoint declares no host", ErrUnresolved)
+	}
+	addr, err := f(ctx, e.Host)
+	if err != nil {
+		return "", err
+	}
+	if _, _, err := net.SplitHostPort(addr); err == nil {
+		return addr, nil
+	}
+	if e.Port == nil {
+		return "", fmt.Errorf("endpoint %s declares no port", e.Host)
+	}
+	return net.JoinHostPort(addr, strconv.Itoa(*e.Port)), nil
+}
+
+// resolveURL replaces the host of an HTTP endpoint's URL with its address
+func resolveURL(ctx context.Context, raw string, f HostFunc) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint url: %w", err)
+	}
+	addr, err := f(ctx, u.Hostname())
+	if err != nil {
+		return "", err
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		switch port := u.Port(); {
+		case port != "":
+			addr = net.JoinHostPort(addr, port)
+		case strings.Contains(addr, ":"):
+			addr = "[" + addr + "]" // IPv6
+		}
+	}
+	u.Host = addr
+	return u.String(), nil
+}
+
+// Chain resolves with each of its resolvers in turn, until one resolves the
+// endpoint or fails with an error other than ErrUnresolved
+type Chain []Resolver
+
+// Resolve implements Resolver
+func (c Chain) Resolve(ctx context.Context, e contract.Endpoint) (string, error) {
+	for _, r := range c {
+		addr, err := r.Resolve(ctx, e)
+		if !errors.Is(err, ErrUnresolved) {
+			return addr, err
+		}
+	}
+	return "", fmt.Errorf("%w: no resolver knows %s", ErrUnresolved, describe(e))
+}
+
+// describe names an endpoint in errors
+func describe(e contract.Endpoint) string {
+	if e.URL != "" {
+		return e.URL
+	}
+	if e.Host != "" {
+		return e.Host
+	}
+	return "the endpoint"
+}
@@ -0,0 +1,89 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+)
+
+// registry is a device registry backed by a map
+type registry map[string]string
+
+func (r registry) DeviceAddress(ctx context.Context, deviceID string) (string, error) {
+	if addr, ok := r[deviceID]; ok {
+		return addr, nil
+	}
+	return "", fmt.Errorf("%w: unknown device %s", ErrUnresolved, deviceID)
+}
+
+func grpcEndpoint(host string, port int) contract.Endpoint {
+	return contract.Endpoint{Type: "grpc", Host: host, Port: &port}
+}
+
+func TestResolve(t *testing.T) {
+	resolver := Chain{
+		Static{"translator.local": "10.0.0.5", "summarizer.local": "10.0.0.6:9000"},
+		Devices{Registry: registry{"kitchen-speaker": "192.168.1.20", "hallway": "fe80::1"}},
+		DNS{},
+	}
+	for _, tc := range []struct {
+		name     string
+		endpoint contract.Endpoint
+		want     string
+	}{
+		{"static host", grpcEndpoint("translator.local", 50052), "10.0.0.5:50052"},
+		{"static host and port", grpcEndpoint("summarizer.local", 50052), "10.0.0.6:9000"},
+		{"device", grpcEndpoint("kitchen-speaker.device", 50052), "192.168.1.20:50052"},
+		{"IP address", grpcEndpoint("10.1.2.3", 50052), "10.1.2.3:50052"},
+		{"static URL", contract.Endpoint{Type: "http", URL: "http://translator.local:8080/translate"}, "http://10.0.0.5:8080/translate"},
+		{"device URL", contract.Endpoint{Type: "http", URL: "https://hallway.device/v1"}, "https://[fe80::1]/v1"},
+	} {
+		got, err := resolver.Resolve(context.Background(), tc.endpoint)
+		if err != nil {
+			t.Errorf("%s: Resolve() error = %v", tc.name, err)
+		} else if got != tc.want {
+			t.Errorf("%s: Resolve() = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	resolver := Chain{
+		Static{"translator.local": "10.0.0.5"},
+		Devices{Registry: registry{}},
+	}
+	for _, tc := range []struct {
+		name       string
+		endpoint   contract.Endpoint
+		unresolved bool
+	}{
+		{"no host", contract.Endpoint{Type: "grpc", Port: new(int)}, true},
+		{"unknown device", grpcEndpoint("attic.device", 50052), true},
+		{"unmapped host", grpcEndpoint("detector.local", 50052), true},
+		{"no port", contract.Endpoint{Type: "grpc", Host: "translator.local"}, false},
+	} {
+		_, err := resolver.Resolve(context.Background(), tc.endpoint)
+		if err == nil {
+			t.Errorf("%s: Resolve() succeeded", tc.name)
+		} else if errors.Is(err, ErrUnresolved) != tc.unresolved {
+			t.Errorf("%s: Resolve() error = %v, unresolved %v", tc.name, err, tc.unresolved)
+		}
+	}
+}
+
+func TestDNSDomain(t *testing.T) {
+	d := DNS{Domain: "svc.cluster.local"}
+	for host, want := range map[string]string{
+		"translator":                           "translator.svc.cluster.local",
+		"translator.default":                   "translator.default.svc.cluster.local",
+		"translator.default.svc.cluster.local": "translator.default.svc.cluster.local",
+		"translator.example.com":               "translator.example.com",
+	} {
+		if got := d.qualify(host); got != want {
+			t.Errorf("qualify(%s) = %s, want %s", host, got, want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+)
+
+// DeviceSuffix marks the host of an endpoint as a device ID: a provider on
+// the device "kitchen-speaker" is declared with the host
+// "kitchen-speaker.device"
+const DeviceSuffix = ".device"
+
+// DNS resolves endpoint hosts with DNS. IP addresses are returned as they
+// are and unknown hosts are ErrUnresolved. Kubernetes services are DNS names
+// too: set Domain, e.g. "svc.cluster.local", to declare them as
+// "<service>.<namespace>".
+type DNS struct {
+	// Resolver looks hosts up; nil means net.DefaultResolver
+	Resolver *net.Resolver
+	// Domain is appended to hosts with at most one dot
+	Domain string
+}
+
+// Resolve implements Resolver
+func (d DNS) Resolve(ctx context.Context, e contract.Endpoint) (string, error) {
+	return HostFunc(d.lookup).Resolve(ctx, e)
+}
+
+func (d DNS) lookup(ctx context.Context, host string) (string, error) {
+	if net.ParseIP(host) != nil {
+		return host, nil
+	}
+	if strings.HasSuffix(host, DeviceSuffix) {
+		return "", fmt.Errorf("%w: %s is a device", ErrUnresolved, host)
+	}
+	host = d.qualify(host)
+	r := d.Resolver
+	if r == nil {
+		r = net.DefaultResolver
+	}
+	addrs, err := r.LookupHost(ctx, host)
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return "", fmt.Errorf("%w: %w", ErrUnresolved, err)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	return addrs[0], nil
+}
+
+// qualify appends the domain to a host with at most one dot
+func (d DNS) qualify(host string) string {
+	if d.Domain == "" || strings.Count(host, ".") > 1 {
+		return host
+	}
+	return host + "." + strings.Trim(d.Domain, ".")
+}
+
+// Static resolves endpoint hosts from a fixed map of hosts to addresses,
+// either hosts or "host:port", for installations without DNS. Hosts missing
+// from the map are ErrUnresolved.
+type Static map[string]string
+
+// Resolve implements Resolver
+func (s Static) Resolve(ctx context.Context, e contract.Endpoint) (string, error) {
+	return HostFunc(func(ctx context.Context, host string) (string, error) {
+		if addr, ok := s[host]; ok {
+			return addr, nil
+		}
+		return "", fmt.Errorf("%w: %s is not mapped", ErrUnresolved, host)
+	}).Resolve(ctx, e)
+}
+
+// DeviceRegistry knows the current address of each device, e.g. a fleet
+// inventory
+type DeviceRegistry interface {
+	// DeviceAddress returns the address, a host or "host:port", of the
+	// device deviceID. It returns an error wrapping ErrUnresolved for
+	// devices it does not know.
+	DeviceAddress(ctx context.Context, deviceID string) (string, error)
+}
+
+// Devices resolves endpoint hosts ending in DeviceSuffix with a device
+// registry, so contracts can name the device a provider runs on while its
+// address changes. Other hosts are ErrUnresolved.
+type Devices struct {
+	Registry DeviceRegistry
+}
+
+// Resolve implements Resolver
+func (d Devices) Resolve(ctx context.Context, e contract.Endpoint) (string, error) {
+	return HostFunc(func(ctx context.Context, host string) (string, error) {
+		deviceID, ok := strings.CutSuffix(host, DeviceSuffix)
+		if !ok || deviceID == "" {
+			return "", fmt.Errorf("%w: %s is not a device", ErrUnresolved, host)
+		}
+		addr, err := d.Registry.DeviceAddress(ctx, deviceID)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve device %s: %w", deviceID, err)
+		}
+		return addr, nil
+	}).Resolve(ctx, e)
+}
@@ -0,0 +1,179 @@
+// Package fulfillment describes how an invocation was fulfilled: which
+// provider served it, how long it was queued and processed, how many NFA
+// components it passed and what it is estimated to cost. Providers return it
+// in the trailing metadata of every response, see runtime.IntentServer, so
+// client apps can display and log fulfillment quality.
+package fulfillment
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/interactivity"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// Metadata keys. Components forwarding an invocation pass on the time it
+// entered NFA and the hops so far; the provider returns its Info in the trailer.
+const (
+	TrailerKey     = "nfa-fulfillment-bin"
+	HopsKey        = "nfa-hops"
+	EnqueueTimeKey = "nfa-enqueue-time" // Unix time in nanoseconds
+)
+
+// Info is the fulfillment of one invocation
+type Info struct {
+	// ID identifies the fulfillment, e.g. for user feedback on it
+	ID             string
+	ServiceID      string
+	QueueTime      time.Duration
+	ProcessingTime time.Duration
+	// Hops counts the NFA components the invocation passed, 1 when the
+	// client called the provider directly
+	Hops int
+	// CostUnits is the provider's estimate, in the scheduler's cost units
+	CostUnits float64
+}
+
+// ToProto converts the fulfillment to its protobuf form
+func (i Info) ToProto() *nfa_intent_v1alpha.Fulfillment {
+	return &nfa_intent_v1alpha.Fulfillment{
+		FulfillmentId:    i.ID,
+		ServiceId:        i.ServiceID,
+		QueueTimeMs:      uint64(i.QueueTime.Milliseconds()),
+		ProcessingTimeMs: uint64(i.ProcessingTime.Milliseconds()),
+		Hops:             uint32(i.Hops),
+		CostUnits:        i.CostUnits,
+	}
+}
+
+// FromProto converts a protobuf fulfillment
+func FromProto(f *nfa_intent_v1alpha.Fulfillment) *Info {
+	return &Info{
+		ID:             f.GetFulfillmentId(),
+		ServiceID:      f.GetServiceId(),
+		QueueTime:      time.Duration(f.GetQueueTimeMs()) * time.Millisecond,
+		ProcessingTime: time.Duration(f.GetProcessingTimeMs()) * time.Millisecond,
+		Hops:           int(f.GetHops()),
+		CostUnits:      f.GetCostUnits(),
+	}
+}
+
+// FromTrailer extracts the fulfillment from the trailing metadata of a
+// response, as received with grpc.Trailer. It returns false when the
+// provider sent none.
+func FromTrailer(md metadata.MD) (*Info, bool) {
+	values := md.Get(TrailerKey)
+	if len(values) == 0 {
+		return nil, false
+	}
+	f := &nfa_intent_v1alpha.Fulfillment{}
+	if err := proto.Unmarshal([]byte(values[0]), f); err != nil {
+		return nil, false
+	}
+	return FromProto(f), true
+}
+
+// NewID returns a new fulfillment ID
+func NewID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// Tracker measures the fulfillment of an invocation while its handler runs
+type Tracker struct {
+	mu    sync.Mutex
+	info  Info
+	start time.Time
+}
+
+type trackerKey struct{}
+
+// Start begins tracking an invocation received at now. The queue time and
+// hops are derived from the metadata of upstream components, if any.
+func Start(ctx context.Context, serviceID string, now time.Time) (context.Context, *Tracker) {
+	t := &Tracker{info: Info{ID: NewID(), ServiceID: serviceID, Hops: 1}, start: now}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(HopsKey); len(values) > 0 {
+			if hops, err := strconv.Atoi(values[0]); err == nil && hops > 0 {
+				t.info.Hops = hops + 1
+			}
+		}
+		if values := md.Get(EnqueueTimeKey); len(values) > 0 {
+			if nanos, err := strconv.ParseInt(values[0], 10, 64); err == nil {
+				if queued := now.Sub(time.Unix(0, nanos)); queued > 0 {
+					t.info.QueueTime = queued
+				}
+			}
+		}
+	}
+	return context.WithValue(ctx, trackerKey{}, t), t
+}
+
+// Finish completes tracking at now and returns the fulfillment
+func (t *Tracker) Finish(now time.Time) Info {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.info.ProcessingTime = now.Sub(t.start)
+	return t.info
+}
+
+// ReportCost adds units to the estimated cost of the invocation handled
+// under ctx. Handlers call it, e.g. with the tokens or accelerator time they
+// used; it has no effect outside a tracked invocation.
+func ReportCost(ctx context.Context, units float64) {
+	t, ok := ctx.Value(trackerKey{}).(*Tracker)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.info.CostUnits += units
+}
+
+// Trailer returns the trailing metadata carrying the fulfillment
+func Trailer(info Info) (metadata.MD, error) {
+	data, err := proto.Marshal(info.ToProto())
+	if err != nil {
+		return nil, err
+	}
+	return metadata.Pairs(TrailerKey, string(data)), nil
+}
+
+// SetTrailer sends the fulfillment in the trailing metadata of the response
+// to the unary RPC handled under ctx
+func SetTrailer(ctx context.Context, info Info) error {
+	md, err := Trailer(info)
+	if err != nil {
+		return err
+	}
+	return grpc.SetTrailer(ctx, md)
+}
+
+// Forward returns a context for calling the next component of an invocation.
+// It passes on the time the invocation entered NFA, or now for a client
+// starting one, the hops so far and the invocation's interactivity class.
+func Forward(ctx context.Context, now time.Time) context.Context {
+	enqueued := strconv.FormatInt(now.UnixNano(), 10)
+	hops := 0
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(EnqueueTimeKey); len(values) > 0 {
+			enqueued = values[0]
+		}
+	}
+	if t, ok := ctx.Value(trackerKey{}).(*Tracker); ok {
+		t.mu.Lock()
+		hops = t.info.Hops
+		t.mu.Unlock()
+	}
+	ctx = interactivity.Forward(ctx)
+	return metadata.AppendToOutgoingContext(ctx, EnqueueTimeKey, enqueued, HopsKey, strconv.Itoa(hops))
+}
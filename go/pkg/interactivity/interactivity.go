@@ -0,0 +1,118 @@
+// Package interactivity tags invocations as interactive, with a user waiting
+// on the result, or background, such as syncs and prefetches nobody waits
+// on. Consumers tag the context of a call, the tag travels in its metadata
+// through the broker to the provider, and both use it to serve interactive
+// work first: the broker orders matches by it and intent servers with a
+// concurrency limit queue by it, see runtime.WithConcurrencyLimit.
+package interactivity
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// Key is the metadata key carrying the class of an invocation
+const Key = "nfa-interactivity"
+
+// Class is how interactive an invocation is
+type Class int
+
+// Classes of invocations. Untagged invocations are Unspecified and served after interactive
+// and before background ones.
+const (
+	Unspecified Class = iota
+	Interactive
+	Background
+)
+
+// Urgency ranks c for serving: 0 for interactive, 1 for unspecified and 2
+// for background invocations
+func (c Class) Urgency() int {
+	switch c {
+	case Interactive:
+		return 0
+	case Background:
+		return 2
+	}
+	return 1
+}
+
+func (c Class) String() string {
+	switch c {
+	case Interactive:
+		return "interactive"
+	case Background:
+		return "background"
+	}
+	return "unspecified"
+}
+
+// Parse returns the class named s, as returned by String. Unknown names are
+// Unspecified, so a newer consumer's classes do not fail older providers.
+func Parse(s string) Class {
+	switch s {
+	case "interactive":
+		return Interactive
+	case "background":
+		return Background
+	}
+	return Unspecified
+}
+
+// With returns a context whose outgoing calls carry class c, replacing a
+// class set before
+func With(ctx context.Context, c Class) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	if c == Unspecified {
+		md.Delete(Key)
+	} else {
+		md.Set(Key, c.String())
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// WithInteractive tags the outgoing calls of ctx as interactive
+func WithInteractive(ctx context.Context) context.Context {
+	return With(ctx, Interactive)
+}
+
+// WithBackground tags the outgoing calls of ctx as background
+func WithBackground(ctx context.Context) context.Context {
+	return With(ctx, Background)
+}
+
+// Incoming returns the class of the call handled under ctx
+func Incoming(ctx context.Context) Class {
+	return fromMetadata(metadata.FromIncomingContext(ctx))
+}
+
+// Outgoing returns the class the outgoing calls of ctx carry
+func Outgoing(ctx context.Context) Class {
+	return fromMetadata(metadata.FromOutgoingContext(ctx))
+}
+
+// Forward returns a context for calling the next component of the call
+// handled under ctx, carrying on its class. A class already set on the
+// outgoing calls of ctx is kept.
+func Forward(ctx context.Context) context.Context {
+	if Outgoing(ctx) != Unspecified {
+		return ctx
+	}
+	if c := Incoming(ctx); c != Unspecified {
+		return With(ctx, c)
+	}
+	return ctx
+}
+
+func fromMetadata(md metadata.MD, ok bool) Class {
+	if !ok {
+		return Unspecified
+	}
+	values := md.Get(Key)
+	if len(values) == 0 {
+		return Unspecified
+	}
+	return Parse(values[0])
+}
@@ -0,0 +1,199 @@
+// Package matching decides which registered services can serve an intent
+// and in which order to try them. Brokers, test harnesses and local routers
+// share it, so an intent matches the same services everywhere.
+//
+// A service matches when one of its intent patterns declares the intent's
+// action, or an alias of it, in the intent's streaming mode, and admits its
+// parameters: after the contract's parameter mappings, every required
+// parameter is present and every constrained one satisfies its constraint,
+// see contract.IntentPattern.Admit. Inline parameters of the pattern with a
+// literal value, e.g. "room: kitchen", must equal the intent's parameter of
+// that name when the intent supplies it; "@name" placeholders match any
+// value.
+//
+// Candidates are ranked by specificity, the literal inline parameters the
+// intent supplied with the same value, highest first, so a provider
+// declared for the kitchen wins over a generic one for an intent about the
+// kitchen. Candidates equally specific are ordered by their cost, lowest
+// first, and then by service ID.
+package matching
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/protobuf/proto"
+)
+
+// Service is a registered service whose contract is matched
+type Service struct {
+	ID       string
+	Contract *contract.IntentContract
+	// Cost orders services matching an intent equally well, lowest first,
+	// e.g. their load
+	Cost float64
+}
+
+// Intent is the intent services are matched for
+type Intent struct {
+	Action string
+	// Parameters of the intent. Nil matches on the action alone, e.g. to
+	// resolve the providers of an action before its parameters are known;
+	// a non-nil map, even an empty one, must be admitted by the pattern.
+	Parameters map[string]*nfa_intent_v1alpha.Value
+	// Streaming is the mode the intent is invoked in; empty means unary
+	Streaming contract.StreamingMode
+}
+
+// Candidate is a service that can serve an intent
+type Candidate struct {
+	ServiceID string
+	// Action is the current name of the action when the intent used an
+	// alias of it, empty otherwise
+	Action string
+	// Pattern is the intent pattern of the service that matched; the most
+	// specific one when several did
+	Pattern     contract.IntentPattern
+	Specificity int
+	Cost        float64
+}
+
+// Rejection is a service declaring the action in the intent's streaming
+// mode whose patterns do not admit the intent's parameters
+type Rejection struct {
+	ServiceID string
+	// Err is the *contract.ViolationError of the first pattern declaring
+	// the action
+	Err error
+}
+
+// Result lists the candidates of an intent, ranked, and the services
+// rejected because of its parameters, sorted by service ID
+type Result struct {
+	Candidates []Candidate
+	Rejected   []Rejection
+}
+
+// ServiceIDs returns the IDs of the candidates in their ranked order
+func (r Result) ServiceIDs() []string {
+	ids := make([]string, len(r.Candidates))
+	for i, c := range r.Candidates {
+		ids[i] = c.ServiceID
+	}
+	return ids
+}
+
+// Match returns the services that can serve intent, ranked as described in
+// the package documentation
+func Match(intent Intent, services []Service) Result {
+	var result Result
+	for _, s := range services {
+		candidate, declared, err := matchService(intent, s)
+		switch {
+		case err == nil && declared:
+			result.Candidates = append(result.Candidates, candidate)
+		case err != nil:
+			result.Rejected = append(result.Rejected, Rejection{ServiceID: s.ID, Err: err})
+		}
+	}
+	sort.Slice(result.Candidates, func(i, j int) bool {
+		a, b := result.Candidates[i], result.Candidates[j]
+		if a.Specificity != b.Specificity {
+			return a.Specificity > b.Specificity
+		}
+		if a.Cost != b.Cost {
+			return a.Cost < b.Cost
+		}
+		return a.ServiceID < b.ServiceID
+	})
+	sort.Slice(result.Rejected, func(i, j int) bool {
+		return result.Rejected[i].ServiceID < result.Rejected[j].ServiceID
+	})
+	return result
+}
+
+// matchService returns the candidate of the most specific pattern of s
+// admitting intent. declared is false when no pattern of s declares the
+// action in the intent's mode; err is the violation of the first pattern
+// that does when none admits the intent.
+func matchService(intent Intent, s Service) (candidate Candidate, declared bool, err error) {
+	params := intent.Parameters
+	if params != nil {
+		if params, err = s.Contract.MapParameters(intent.Action, params); err != nil {
+			return Candidate{}, true, err
+		}
+	}
+	var violation error
+	found := false
+	for _, p := range s.Contract.Spec.IntentPatterns {
+		if orUnary(p.Streaming) != orUnary(intent.Streaming) {
+			continue
+		}
+		if p.Pattern.Action != intent.Action && !slices.Contains(p.Aliases, intent.Action) {
+			continue
+		}
+		declared = true
+		specificity := 0
+		if params != nil {
+			var err error
+			if specificity, err = matchInline(p, params); err == nil {
+				err = p.Admit(params)
+			}
+			if err != nil {
+				if violation == nil {
+					violation = err
+				}
+				continue
+			}
+		}
+		if found && specificity <= candidate.Specificity {
+			continue
+		}
+		found = true
+		candidate = Candidate{ServiceID: s.ID, Pattern: p, Specificity: specificity, Cost: s.Cost}
+		if p.Pattern.Action != intent.Action {
+			candidate.Action = p.Pattern.Action
+		}
+	}
+	if !found {
+		return Candidate{}, declared, violation
+	}
+	return candidate, true, nil
+}
+
+// matchInline checks the literal inline parameters of p against params and
+// returns how many params supplied with the same value
+func matchInline(p contract.IntentPattern, params map[string]*nfa_intent_v1alpha.Value) (int, error) {
+	specificity := 0
+	var violations []contract.Violation
+	for name, literal := range p.Pattern.Parameters {
+		if s, ok := literal.(string); ok && strings.HasPrefix(s, "@") {
+			continue
+		}
+		value, ok := params[name]
+		if !ok {
+			continue
+		}
+		if want := contract.ValueToProto(literal); want == nil || !proto.Equal(value, want) {
+			violations = append(violations, contract.Violation{Parameter: name, Description: fmt.Sprintf("must be %v", literal)})
+			continue
+		}
+		specificity++
+	}
+	if len(violations) > 0 {
+		sort.Slice(violations, func(i, j int) bool { return violations[i].Parameter < violations[j].Parameter })
+		return 0, &contract.ViolationError{Action: p.Pattern.Action, Violations: violations}
+	}
+	return specificity, nil
+}
+
+func orUnary(m contract.StreamingMode) contract.StreamingMode {
+	if m == "" {
+		return contract.StreamingUnary
+	}
+	return m
+}
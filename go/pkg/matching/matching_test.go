@@ -0,0 +1,140 @@
+package matching
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+)
+
+// lightsContract returns a contract named name with the intent patterns in
+// the YAML of patterns
+func lightsContract(t *testing.T, name, patterns string) *contract.IntentContract {
+	t.Helper()
+	c, err := contract.ParseIntentContract([]byte(fmt.Sprintf(`
+version: v1alpha
+kind: IntentContract
+metadata:
+  name: %s
+spec:
+  intentPatterns:
+%s
+  implementation:
+    endpoint:
+      type: grpc
+      port: 50052
+`, name, patterns)))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	return c
+}
+
+func TestMatch(t *testing.T) {
+	services := []Service{
+		{ID: "generic", Cost: 0.1, Contract: lightsContract(t, "generic", `
+    - pattern:
+        action: lights_on
+        room: "@room"
+      aliases: [switch_on]
+      constraints:
+        requiredParameters: [room]
+        parameterConstraints:
+          brightness: {type: number, min: 0, max: 100}`)},
+		{ID: "kitchen", Cost: 0.5, Contract: lightsContract(t, "kitchen", `
+    - pattern:
+        action: lights_on
+        room: kitchen
+      mappings:
+        - {from: level, to: brightness}
+      constraints:
+        parameterConstraints:
+          brightness: {type: number, max: 50}`)},
+		{ID: "busy", Cost: 0.9, Contract: lightsContract(t, "busy", `
+    - pattern:
+        action: lights_on`)},
+		{ID: "streaming", Contract: lightsContract(t, "streaming", `
+    - pattern:
+        action: lights_on
+      streaming: server`)},
+	}
+	str := func(s string) *nfa_intent_v1alpha.Value { return contract.ValueToProto(s) }
+	num := func(n float64) *nfa_intent_v1alpha.Value { return contract.ValueToProto(n) }
+
+	for _, tt := range []struct {
+		name     string
+		intent   Intent
+		want     []string
+		rejected []string
+	}{
+		{
+			name:   "action only",
+			intent: Intent{Action: "lights_on"},
+			want:   []string{"generic", "kitchen", "busy"},
+		},
+		{
+			name:   "specific first",
+			intent: Intent{Action: "lights_on", Parameters: map[string]*nfa_intent_v1alpha.Value{"room": str("kitchen")}},
+			want:   []string{"kitchen", "generic", "busy"},
+		},
+		{
+			name:     "other room",
+			intent:   Intent{Action: "lights_on", Parameters: map[string]*nfa_intent_v1alpha.Value{"room": str("hall")}},
+			want:     []string{"generic", "busy"},
+			rejected: []string{"kitchen"},
+		},
+		{
+			name:     "required parameter missing",
+			intent:   Intent{Action: "lights_on", Parameters: map[string]*nfa_intent_v1alpha.Value{}},
+			want:     []string{"kitchen", "busy"},
+			rejected: []string{"generic"},
+		},
+		{
+			name: "mapped parameter outside its constraint",
+			intent: Intent{Action: "lights_on", Parameters: map[string]*nfa_intent_v1alpha.Value{
+				"room": str("kitchen"), "level": num(80),
+			}},
+			want:     []string{"generic", "busy"},
+			rejected: []string{"kitchen"},
+		},
+		{
+			name:   "streaming",
+			intent: Intent{Action: "lights_on", Streaming: contract.StreamingServer},
+			want:   []string{"streaming"},
+		},
+		{
+			name:   "alias",
+			intent: Intent{Action: "switch_on", Parameters: map[string]*nfa_intent_v1alpha.Value{"room": str("hall")}},
+			want:   []string{"generic"},
+		},
+		{
+			name:   "unknown action",
+			intent: Intent{Action: "lights_off"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Match(tt.intent, services)
+			if got := result.ServiceIDs(); !slices.Equal(got, tt.want) {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+			var rejected []string
+			for _, r := range result.Rejected {
+				if !errors.Is(r.Err, contract.ErrConstraintViolated) {
+					t.Errorf("rejection of %s error = %v, want ErrConstraintViolated", r.ServiceID, r.Err)
+				}
+				rejected = append(rejected, r.ServiceID)
+			}
+			if !slices.Equal(rejected, tt.rejected) {
+				t.Errorf("rejected = %v, want %v", rejected, tt.rejected)
+			}
+		})
+	}
+
+	result := Match(Intent{Action: "switch_on"}, services)
+	if len(result.Candidates) != 1 || result.Candidates[0].Action != "lights_on" {
+		t.Errorf("Match() of an alias = %+v, want the current action", result.Candidates)
+	}
+}
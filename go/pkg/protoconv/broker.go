@@ -0,0 +1,100 @@
+package protoconv
+
+import (
+	"context"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/interactivity"
+	nfa_broker_v1 "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1"
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// BrokerV1 serves the v1 IntentBroker API by forwarding every call to a
+// broker that only speaks v1alpha, so clients can move to v1 before the
+// broker does
+type BrokerV1 struct {
+	nfa_broker_v1.UnimplementedIntentBrokerServer
+
+	upstream nfa_broker_v1alpha.IntentBrokerClient
+}
+
+// NewBrokerV1 creates a v1 shim in front of the v1alpha broker behind cc
+func NewBrokerV1(cc grpc.ClientConnInterface) *BrokerV1 {
+	return &BrokerV1{upstream: nfa_broker_v1alpha.NewIntentBrokerClient(cc)}
+}
+
+// Register registers the v1 broker service on a gRPC server
+func (b *BrokerV1) Register(registrar grpc.ServiceRegistrar) {
+	nfa_broker_v1.RegisterIntentBrokerServer(registrar, b)
+}
+
+// RegisterIntent implements the v1 RegisterIntent RPC
+func (b *BrokerV1) RegisterIntent(ctx context.Context, req *nfa_broker_v1.RegisterIntentRequest) (*nfa_broker_v1.RegisterIntentResponse, error) {
+	return forward(ctx, req, &nfa_broker_v1alpha.RegisterIntentRequest{}, &nfa_broker_v1.RegisterIntentResponse{}, b.upstream.RegisterIntent)
+}
+
+// MatchIntent implements the v1 MatchIntent RPC
+func (b *BrokerV1) MatchIntent(ctx context.Context, req *nfa_broker_v1.IntentMatchRequest) (*nfa_broker_v1.IntentMatchResponse, error) {
+	return forward(ctx, req, &nfa_broker_v1alpha.IntentMatchRequest{}, &nfa_broker_v1.IntentMatchResponse{}, b.upstream.MatchIntent)
+}
+
+// Heartbeat implements the v1 Heartbeat RPC
+func (b *BrokerV1) Heartbeat(ctx context.Context, req *nfa_broker_v1.HeartbeatRequest) (*nfa_broker_v1.HeartbeatResponse, error) {
+	return forward(ctx, req, &nfa_broker_v1alpha.HeartbeatRequest{}, &nfa_broker_v1.HeartbeatResponse{}, b.upstream.Heartbeat)
+}
+
+// UnregisterIntent implements the v1 UnregisterIntent RPC
+func (b *BrokerV1) UnregisterIntent(ctx context.Context, req *nfa_broker_v1.UnregisterIntentRequest) (*nfa_broker_v1.UnregisterIntentResponse, error) {
+	return forward(ctx, req, &nfa_broker_v1alpha.UnregisterIntentRequest{}, &nfa_broker_v1.UnregisterIntentResponse{}, b.upstream.UnregisterIntent)
+}
+
+// ReportOutcome implements the v1 ReportOutcome RPC
+func (b *BrokerV1) ReportOutcome(ctx context.Context, req *nfa_broker_v1.ReportOutcomeRequest) (*nfa_broker_v1.ReportOutcomeResponse, error) {
+	return forward(ctx, req, &nfa_broker_v1alpha.ReportOutcomeRequest{}, &nfa_broker_v1.ReportOutcomeResponse{}, b.upstream.ReportOutcome)
+}
+
+// ReportErrors implements the v1 ReportErrors RPC
+func (b *BrokerV1) ReportErrors(ctx context.Context, req *nfa_broker_v1.ReportErrorsRequest) (*nfa_broker_v1.ReportErrorsResponse, error) {
+	return forward(ctx, req, &nfa_broker_v1alpha.ReportErrorsRequest{}, &nfa_broker_v1.ReportErrorsResponse{}, b.upstream.ReportErrors)
+}
+
+// forward converts a v1 request to v1alpha, calls the upstream method and
+// converts its response back. Upstream errors are returned unchanged so
+// clients see the broker's status codes. The interactivity class and the
+// consumer name of the call are passed on, so the broker routes and reports
+// it as if called directly.
+func forward[Req, UpReq, UpResp, Resp proto.Message](
+	ctx context.Context,
+	req Req, upReq UpReq, resp Resp,
+	call func(context.Context, UpReq, ...grpc.CallOption) (UpResp, error),
+) (Resp, error) {
+	var zero Resp
+	if err := Convert(req, upReq); err != nil {
+		return zero, status.Error(codes.InvalidArgument, err.Error())
+	}
+	upResp, err := call(forwardConsumer(interactivity.Forward(ctx)), upReq)
+	if err != nil {
+		return zero, err
+	}
+	if err := Convert(upResp, resp); err != nil {
+		return zero, status.Error(codes.Internal, err.Error())
+	}
+	return resp, nil
+}
+
+// consumerKey is broker.ConsumerKey, which this package cannot import
+const consumerKey = "nfa-consumer"
+
+// forwardConsumer passes the consumer name of the call handled under ctx on
+// to the call made with the returned context
+func forwardConsumer(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if names := md.Get(consumerKey); len(names) > 0 {
+		return metadata.AppendToOutgoingContext(ctx, consumerKey, names[0])
+	}
+	return ctx
+}
@@ -0,0 +1,80 @@
+// Package protoconv converts messages between API versions of the NFA
+// protocols. While a version is being superseded its messages keep the field
+// numbers of their successor, so conversion is a lossless wire round trip;
+// see docs/proto-versioning.md for the migration path.
+package protoconv
+
+import (
+	"fmt"
+
+	nfa_intent_v1 "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/protobuf/proto"
+)
+
+// Convert copies src into dst through the wire format. Both messages must
+// describe the same fields; unknown fields are carried over so nothing is
+// silently dropped between versions.
+func Convert(src, dst proto.Message) error {
+	data, err := proto.Marshal(src)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", src.ProtoReflect().Descriptor().FullName(), err)
+	}
+	if err := proto.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("failed to convert to %s: %w", dst.ProtoReflect().Descriptor().FullName(), err)
+	}
+	return nil
+}
+
+// convert allocates a T and converts src into it; nil converts to nil
+func convert[T proto.Message](src proto.Message, dst T) (T, error) {
+	if src == nil || !src.ProtoReflect().IsValid() {
+		var zero T
+		return zero, nil
+	}
+	if err := Convert(src, dst); err != nil {
+		var zero T
+		return zero, err
+	}
+	return dst, nil
+}
+
+// IntentContractToV1 converts a v1alpha contract to v1
+func IntentContractToV1(c *nfa_intent_v1alpha.IntentContract) (*nfa_intent_v1.IntentContract, error) {
+	return convert(c, &nfa_intent_v1.IntentContract{})
+}
+
+// IntentContractFromV1 converts a v1 contract to v1alpha
+func IntentContractFromV1(c *nfa_intent_v1.IntentContract) (*nfa_intent_v1alpha.IntentContract, error) {
+	return convert(c, &nfa_intent_v1alpha.IntentContract{})
+}
+
+// IntentPatternToV1 converts a v1alpha pattern to v1
+func IntentPatternToV1(p *nfa_intent_v1alpha.IntentPattern) (*nfa_intent_v1.IntentPattern, error) {
+	return convert(p, &nfa_intent_v1.IntentPattern{})
+}
+
+// IntentPatternFromV1 converts a v1 pattern to v1alpha
+func IntentPatternFromV1(p *nfa_intent_v1.IntentPattern) (*nfa_intent_v1alpha.IntentPattern, error) {
+	return convert(p, &nfa_intent_v1alpha.IntentPattern{})
+}
+
+// IntentContextToV1 converts a v1alpha request context to v1
+func IntentContextToV1(c *nfa_intent_v1alpha.IntentContext) (*nfa_intent_v1.IntentContext, error) {
+	return convert(c, &nfa_intent_v1.IntentContext{})
+}
+
+// IntentContextFromV1 converts a v1 request context to v1alpha
+func IntentContextFromV1(c *nfa_intent_v1.IntentContext) (*nfa_intent_v1alpha.IntentContext, error) {
+	return convert(c, &nfa_intent_v1alpha.IntentContext{})
+}
+
+// IntentRequestToV1 converts a v1alpha intent request to v1
+func IntentRequestToV1(r *nfa_intent_v1alpha.IntentRequest) (*nfa_intent_v1.IntentRequest, error) {
+	return convert(r, &nfa_intent_v1.IntentRequest{})
+}
+
+// IntentRequestFromV1 converts a v1 intent request to v1alpha
+func IntentRequestFromV1(r *nfa_intent_v1.IntentRequest) (*nfa_intent_v1alpha.IntentRequest, error) {
+	return convert(r, &nfa_intent_v1alpha.IntentRequest{})
+}
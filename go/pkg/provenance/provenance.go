@@ -0,0 +1,192 @@
+// Package provenance records which component supplied each parameter of an
+// intent: the user, the context store, a contract default or the previous
+// step of a pipeline. Components that fill in parameters record them in a
+// Trace, which travels with the intent and is returned in debug responses so
+// an unexpected value can be traced to its source.
+package provenance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+)
+
+// Source is a kind of component supplying parameter values
+type Source string
+
+// Sources, from lowest to highest precedence. A value from a higher
+// precedence source replaces one from a lower precedence source.
+const (
+	SourceContract Source = "contract"
+	SourceContext  Source = "context"
+	SourcePipeline Source = "pipeline"
+	SourceUser     Source = "user"
+)
+
+var precedence = map[Source]int{
+	SourceContract: 1,
+	SourceContext:  2,
+	SourcePipeline: 3,
+	SourceUser:     4,
+}
+
+// Origin describes where a parameter value came from
+type Origin struct {
+	Source Source
+	// Detail locates the value within its source, e.g. a preference key,
+	// contract name or pipeline step
+	Detail string
+	// Overridden lists the sources that also supplied the parameter but lost
+	// to Source
+	Overridden []Source
+}
+
+func (o *Origin) String() string {
+	s := string(o.Source)
+	if o.Detail != "" {
+		s += " (" + o.Detail + ")"
+	}
+	if len(o.Overridden) > 0 {
+		names := make([]string, len(o.Overridden))
+		for i, src := range o.Overridden {
+			names[i] = string(src)
+		}
+		s += ", overrides " + strings.Join(names, ", ")
+	}
+	return s
+}
+
+// Trace maps parameter names to the origin of their values
+type Trace map[string]*Origin
+
+// Record notes that source supplied a value for a parameter and reports
+// whether that value should be used, i.e. no higher precedence source
+// supplied one before. Callers set the value only when Record returns true.
+func (t Trace) Record(name string, source Source, detail string) bool {
+	prev, ok := t[name]
+	if !ok {
+		t[name] = &Origin{Source: source, Detail: detail}
+		return true
+	}
+	if precedence[source] < precedence[prev.Source] {
+		prev.Overridden = append(prev.Overridden, source)
+		return false
+	}
+	overridden := append(prev.Overridden, prev.Source)
+	t[name] = &Origin{Source: source, Detail: detail, Overridden: overridden}
+	return true
+}
+
+// Strings returns the origin of every parameter as text, ordered by name,
+// for debug output
+func (t Trace) Strings() []string {
+	names := make([]string, 0, len(t))
+	for name := range t {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := make([]string, len(names))
+	for i, name := range names {
+		lines[i] = fmt.Sprintf("%s: %s", name, t[name])
+	}
+	return lines
+}
+
+// ToProto converts the trace to its protobuf form
+func (t Trace) ToProto() map[string]*nfa_intent_v1alpha.ParameterProvenance {
+	if len(t) == 0 {
+		return nil
+	}
+	out := make(map[string]*nfa_intent_v1alpha.ParameterProvenance, len(t))
+	for name, o := range t {
+		p := &nfa_intent_v1alpha.ParameterProvenance{Source: sourceToProto(o.Source), Origin: o.Detail}
+		for _, src := range o.Overridden {
+			p.Overridden = append(p.Overridden, sourceToProto(src))
+		}
+		out[name] = p
+	}
+	return out
+}
+
+// FromProto converts a protobuf provenance map to a trace
+func FromProto(m map[string]*nfa_intent_v1alpha.ParameterProvenance) Trace {
+	t := make(Trace, len(m))
+	for name, p := range m {
+		o := &Origin{Source: sourceFromProto(p.GetSource()), Detail: p.GetOrigin()}
+		for _, src := range p.GetOverridden() {
+			o.Overridden = append(o.Overridden, sourceFromProto(src))
+		}
+		t[name] = o
+	}
+	return t
+}
+
+func sourceToProto(s Source) nfa_intent_v1alpha.ParameterSource {
+	switch s {
+	case SourceContract:
+		return nfa_intent_v1alpha.ParameterSource_PARAMETER_SOURCE_CONTRACT_DEFAULT
+	case SourceContext:
+		return nfa_intent_v1alpha.ParameterSource_PARAMETER_SOURCE_CONTEXT_STORE
+	case SourcePipeline:
+		return nfa_intent_v1alpha.ParameterSource_PARAMETER_SOURCE_PIPELINE_STEP
+	case SourceUser:
+		return nfa_intent_v1alpha.ParameterSource_PARAMETER_SOURCE_USER_INPUT
+	default:
+		return nfa_intent_v1alpha.ParameterSource_PARAMETER_SOURCE_UNSPECIFIED
+	}
+}
+
+func sourceFromProto(s nfa_intent_v1alpha.ParameterSource) Source {
+	switch s {
+	case nfa_intent_v1alpha.ParameterSource_PARAMETER_SOURCE_CONTRACT_DEFAULT:
+		return SourceContract
+	case nfa_intent_v1alpha.ParameterSource_PARAMETER_SOURCE_CONTEXT_STORE:
+		return SourceContext
+	case nfa_intent_v1alpha.ParameterSource_PARAMETER_SOURCE_PIPELINE_STEP:
+		return SourcePipeline
+	case nfa_intent_v1alpha.ParameterSource_PARAMETER_SOURCE_USER_INPUT:
+		return SourceUser
+	default:
+		return ""
+	}
+}
+
+// Resolve completes the parameters of an intent request in place. Parameters
+// sent by the user are kept; missing parameters that have a default are taken
+// from the context's preferences, or else from defaults, typically the
+// contract's parameter defaults named by contractName. Preferences for other
+// parameters are not added. Provenance already recorded in the request, e.g.
+// by a pipeline, is kept. The trace is stored in the request when it asks for
+// debug output, and returned either way.
+func Resolve(req *nfa_intent_v1alpha.IntentRequest, contractName string, defaults map[string]*nfa_intent_v1alpha.Value) Trace {
+	trace := FromProto(req.Provenance)
+	if req.Parameters == nil {
+		req.Parameters = make(map[string]*nfa_intent_v1alpha.Value)
+	}
+	for name := range req.Parameters {
+		if _, ok := trace[name]; !ok {
+			trace.Record(name, SourceUser, "")
+		}
+	}
+	for name, value := range req.GetContext().GetPreferences() {
+		if _, declared := defaults[name]; !declared {
+			if _, sent := req.Parameters[name]; !sent {
+				continue
+			}
+		}
+		if trace.Record(name, SourceContext, "preferences."+name) {
+			req.Parameters[name] = value
+		}
+	}
+	for name, value := range defaults {
+		if trace.Record(name, SourceContract, contractName) {
+			req.Parameters[name] = value
+		}
+	}
+	if req.Debug {
+		req.Provenance = trace.ToProto()
+	}
+	return trace
+}
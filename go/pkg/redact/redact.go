@@ -0,0 +1,321 @@
+// Package redact masks the values of sensitive intent parameters before they
+// are written to logs, traces, audit events or analytics. Which parameters
+// are sensitive is declared once, in the sensitivity of a contract's
+// parameter constraints, and the runtime, broker and gateway all redact with
+// the rules derived from it, so a value masked in one place is masked
+// everywhere.
+package redact
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+)
+
+// Keys of the action and parameters in event, audit and analytics records
+// redacted with Redactor.Data
+const (
+	KeyAction     = "action"
+	KeyParameters = "parameters"
+)
+
+// Mask returns the placeholder that replaces a value of the given sensitivity
+func Mask(s contract.Sensitivity) string {
+	return "[REDACTED:" + string(s) + "]"
+}
+
+// Rules maps an action to the sensitivity of each of its sensitive
+// parameters. Values nested in object and array parameters are named by
+// their path: "card.number" is the number property of the card parameter
+// and "cards[].number" that of every item of the cards parameter.
+type Rules map[string]map[string]contract.Sensitivity
+
+// FromContract derives the rules declared by a contract
+func FromContract(c *contract.IntentContract) Rules {
+	rules := Rules{}
+	for _, p := range c.Spec.IntentPatterns {
+		if p.Constraints == nil {
+			continue
+		}
+		for name, pc := range p.Constraints.ParameterConstraints {
+			rules.addConstraint(p.Pattern.Action, name, pc)
+		}
+	}
+	return rules
+}
+
+// FromProto derives the rules declared by a registered contract
+func FromProto(c *nfa_intent_v1alpha.IntentContract) Rules {
+	rules := Rules{}
+	for _, p := range c.GetSpec().GetIntentPatterns() {
+		for name, pc := range p.GetConstraints().GetParameterConstraints() {
+			rules.addProto(p.GetPattern().GetAction(), name, pc)
+		}
+	}
+	return rules
+}
+
+// addConstraint adds the sensitivity of the value at path and of the values
+// nested in it; those of a sensitive value are masked with it
+func (r Rules) addConstraint(action, path string, pc contract.ParameterConstraint) {
+	if pc.Sensitivity != "" {
+		r.add(action, path, pc.Sensitivity)
+		return
+	}
+	for name, property := range pc.Properties {
+		r.addConstraint(action, path+"."+name, property)
+	}
+	if pc.Items != nil {
+		r.addConstraint(action, path+"[]", *pc.Items)
+	}
+}
+
+// addProto is addConstraint for a registered contract
+func (r Rules) addProto(action, path string, pc *nfa_intent_v1alpha.ParameterConstraint) {
+	if s := contract.SensitivityFromProto(pc.GetSensitivity()); s != "" {
+		r.add(action, path, s)
+		return
+	}
+	for name, property := range pc.GetObjectConstraint().GetProperties() {
+		r.addProto(action, path+"."+name, property)
+	}
+	if items := pc.GetArrayConstraint().GetItems(); items != nil {
+		r.addProto(action, path+"[]", items)
+	}
+}
+
+func (r Rules) add(action, parameter string, s contract.Sensitivity) {
+	if s == "" {
+		return
+	}
+	if r[action] == nil {
+		r[action] = make(map[string]contract.Sensitivity)
+	}
+	if r[action][parameter] != contract.SensitivitySensitive {
+		r[action][parameter] = s
+	}
+}
+
+// Redactor redacts parameters with the rules of a set of contracts. When
+// several contracts serve the same action, a parameter is redacted if any of
+// them marks it. A nil Redactor redacts nothing. It is safe for concurrent use.
+type Redactor struct {
+	mu        sync.RWMutex
+	contracts map[string]Rules
+	merged    Rules
+}
+
+// New creates a redactor without rules
+func New() *Redactor {
+	return &Redactor{
+		contracts: make(map[string]Rules),
+		merged:    Rules{},
+	}
+}
+
+// Set installs or replaces the rules of a contract
+func (r *Redactor) Set(contractName string, rules Rules) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.contracts[contractName] = rules
+	r.merge()
+}
+
+// Remove drops the rules of a contract, e.g. when its service unregisters
+func (r *Redactor) Remove(contractName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.contracts, contractName)
+	r.merge()
+}
+
+// merge rebuilds the rules of all contracts; mu must be held
+func (r *Redactor) merge() {
+	names := make([]string, 0, len(r.contracts))
+	for name := range r.contracts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	merged := Rules{}
+	for _, name := range names {
+		for action, params := range r.contracts[name] {
+			for param, s := range params {
+				merged.add(action, param, s)
+			}
+		}
+	}
+	r.merged = merged
+}
+
+// Sensitivity returns the sensitivity of a parameter of action, or of a
+// value nested in one by its path, empty when it may be recorded in clear
+// text
+func (r *Redactor) Sensitivity(action, parameter string) contract.Sensitivity {
+	if r == nil {
+		return ""
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.merged[action][parameter]
+}
+
+// rules returns the sensitive parameters of action
+func (r *Redactor) rules(action string) map[string]contract.Sensitivity {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.merged[action]
+}
+
+// Strings returns a copy of string parameters, such as those of a broadcast
+// Invoke or trace span attributes, with sensitive values masked
+func (r *Redactor) Strings(action string, params map[string]string) map[string]string {
+	rules := r.rules(action)
+	if len(rules) == 0 || params == nil {
+		return params
+	}
+	out := make(map[string]string, len(params))
+	for k, v := range params {
+		if s, ok := rules[k]; ok {
+			v = Mask(s)
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// Values returns a copy of the parameters of an IntentRequest with sensitive
+// values, nested ones included, replaced by their mask
+func (r *Redactor) Values(action string, params map[string]*nfa_intent_v1alpha.Value) map[string]*nfa_intent_v1alpha.Value {
+	rules := r.rules(action)
+	if len(rules) == 0 || params == nil {
+		return params
+	}
+	out := make(map[string]*nfa_intent_v1alpha.Value, len(params))
+	for k, v := range params {
+		out[k] = maskValue(rules, k, v)
+	}
+	return out
+}
+
+// maskValue masks v, the value at path, or the sensitive values nested in it
+func maskValue(rules map[string]contract.Sensitivity, path string, v *nfa_intent_v1alpha.Value) *nfa_intent_v1alpha.Value {
+	if s, ok := rules[path]; ok {
+		return &nfa_intent_v1alpha.Value{Value: &nfa_intent_v1alpha.Value_StringValue{StringValue: Mask(s)}}
+	}
+	if !nested(rules, path) {
+		return v
+	}
+	switch value := v.GetValue().(type) {
+	case *nfa_intent_v1alpha.Value_StructValue:
+		fields := make(map[string]*nfa_intent_v1alpha.Value, len(value.StructValue.GetFields()))
+		for k, field := range value.StructValue.GetFields() {
+			fields[k] = maskValue(rules, path+"."+k, field)
+		}
+		return &nfa_intent_v1alpha.Value{Value: &nfa_intent_v1alpha.Value_StructValue{StructValue: &nfa_intent_v1alpha.StructValue{Fields: fields}}}
+	case *nfa_intent_v1alpha.Value_ListValue:
+		items := make([]*nfa_intent_v1alpha.Value, len(value.ListValue.GetValues()))
+		for i, item := range value.ListValue.GetValues() {
+			items[i] = maskValue(rules, path+"[]", item)
+		}
+		return &nfa_intent_v1alpha.Value{Value: &nfa_intent_v1alpha.Value_ListValue{ListValue: &nfa_intent_v1alpha.ListValue{Values: items}}}
+	}
+	return v
+}
+
+// Map returns a copy of decoded JSON parameters with sensitive values
+// replaced by their mask. Nested values of a sensitive parameter are masked
+// as a whole.
+func (r *Redactor) Map(action string, params map[string]interface{}) map[string]interface{} {
+	rules := r.rules(action)
+	if len(rules) == 0 || params == nil {
+		return params
+	}
+	out := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		out[k] = maskJSON(rules, k, v)
+	}
+	return out
+}
+
+// maskJSON is maskValue for decoded JSON values
+func maskJSON(rules map[string]contract.Sensitivity, path string, v interface{}) interface{} {
+	if s, ok := rules[path]; ok {
+		return Mask(s)
+	}
+	if !nested(rules, path) {
+		return v
+	}
+	switch value := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for k, field := range value {
+			out[k] = maskJSON(rules, path+"."+k, field)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, item := range value {
+			out[i] = maskJSON(rules, path+"[]", item)
+		}
+		return out
+	}
+	return v
+}
+
+// nested reports whether values nested in the one at path are sensitive
+func nested(rules map[string]contract.Sensitivity, path string) bool {
+	for p := range rules {
+		if strings.HasPrefix(p, path+".") || strings.HasPrefix(p, path+"[]") {
+			return true
+		}
+	}
+	return false
+}
+
+// Data returns a copy of an event, audit or analytics record whose
+// parameters are redacted. Records carry the intent's action under
+// KeyAction and its parameters under KeyParameters; records without them
+// are returned unchanged.
+func (r *Redactor) Data(data map[string]interface{}) map[string]interface{} {
+	action, _ := data[KeyAction].(string)
+	if action == "" || len(r.rules(action)) == 0 {
+		return data
+	}
+	out := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		out[k] = v
+	}
+	switch params := data[KeyParameters].(type) {
+	case map[string]interface{}:
+		out[KeyParameters] = r.Map(action, params)
+	case map[string]string:
+		out[KeyParameters] = r.Strings(action, params)
+	case map[string]*nfa_intent_v1alpha.Value:
+		out[KeyParameters] = r.Values(action, params)
+	}
+	return out
+}
+
+// Attr returns the parameters of action as a log attribute group named
+// "parameters", with sensitive values masked
+func (r *Redactor) Attr(action string, params map[string]string) slog.Attr {
+	params = r.Strings(action, params)
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	args := make([]interface{}, 0, 2*len(keys))
+	for _, k := range keys {
+		args = append(args, k, params[k])
+	}
+	return slog.Group(KeyParameters, args...)
+}
@@ -0,0 +1,252 @@
+package redact
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/protobuf/proto"
+)
+
+const paymentsContract = `
+version: v1alpha
+kind: IntentContract
+metadata:
+  name: payments
+spec:
+  intentPatterns:
+    - pattern:
+        action: pay
+      constraints:
+        parameterConstraints:
+          amount:
+            type: number
+          email:
+            type: string
+            sensitivity: pii
+          card:
+            type: object
+            properties:
+              number:
+                type: string
+                sensitivity: sensitive
+              holder:
+                type: string
+                sensitivity: pii
+              brand:
+                type: string
+          recipients:
+            type: array
+            items:
+              type: object
+              properties:
+                iban:
+                  type: string
+                  sensitivity: sensitive
+                name:
+                  type: string
+          tags:
+            type: array
+            items:
+              type: string
+              sensitivity: pii
+          token:
+            type: object
+            sensitivity: sensitive
+            properties:
+              secret:
+                type: string
+                sensitivity: pii
+  implementation:
+    endpoint:
+      type: grpc
+      host: localhost
+      port: 50052
+`
+
+func parseContract(t *testing.T, data string) *contract.IntentContract {
+	t.Helper()
+	c, err := contract.ParseIntentContract([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	return c
+}
+
+func TestFromContract(t *testing.T) {
+	c := parseContract(t, paymentsContract)
+	want := Rules{"pay": {
+		"email":             contract.SensitivityPII,
+		"card.number":       contract.SensitivitySensitive,
+		"card.holder":       contract.SensitivityPII,
+		"recipients[].iban": contract.SensitivitySensitive,
+		"tags[]":            contract.SensitivityPII,
+		// The properties of a sensitive object are masked with it
+		"token": contract.SensitivitySensitive,
+	}}
+	if got := FromContract(c); !reflect.DeepEqual(got, want) {
+		t.Errorf("FromContract() = %v, want %v", got, want)
+	}
+	if got := FromProto(c.ToProto()); !reflect.DeepEqual(got, want) {
+		t.Errorf("FromProto() = %v, want %v", got, want)
+	}
+}
+
+// redactionTests are parameters of the pay action and their redacted copies
+var redactionTests = []struct {
+	name   string
+	params map[string]interface{}
+	want   map[string]interface{}
+}{
+	{
+		name:   "flat",
+		params: map[string]interface{}{"amount": 12.5, "email": "ada@example.com"},
+		want:   map[string]interface{}{"amount": 12.5, "email": "[REDACTED:pii]"},
+	},
+	{
+		name: "nested struct",
+		params: map[string]interface{}{"card": map[string]interface{}{
+			"number": "4111111111111111", "holder": "Ada Lovelace", "brand": "visa",
+		}},
+		want: map[string]interface{}{"card": map[string]interface{}{
+			"number": "[REDACTED:sensitive]", "holder": "[REDACTED:pii]", "brand": "visa",
+		}},
+	},
+	{
+		name: "list of structs",
+		params: map[string]interface{}{"recipients": []interface{}{
+			map[string]interface{}{"iban": "DE89370400440532013000", "name": "Ada"},
+			map[string]interface{}{"iban": "GB29NWBK60161331926819", "name": "Charles"},
+		}},
+		want: map[string]interface{}{"recipients": []interface{}{
+			map[string]interface{}{"iban": "[REDACTED:sensitive]", "name": "Ada"},
+			map[string]interface{}{"iban": "[REDACTED:sensitive]", "name": "Charles"},
+		}},
+	},
+	{
+		name:   "list of values",
+		params: map[string]interface{}{"tags": []interface{}{"home", "ada"}},
+		want:   map[string]interface{}{"tags": []interface{}{"[REDACTED:pii]", "[REDACTED:pii]"}},
+	},
+	{
+		name:   "sensitive struct",
+		params: map[string]interface{}{"token": map[string]interface{}{"secret": "s3cr3t", "expires": 60.0}},
+		want:   map[string]interface{}{"token": "[REDACTED:sensitive]"},
+	},
+	{
+		name:   "unexpected shape",
+		params: map[string]interface{}{"card": "4111111111111111", "recipients": "Ada"},
+		want:   map[string]interface{}{"card": "4111111111111111", "recipients": "Ada"},
+	},
+}
+
+func TestMap(t *testing.T) {
+	r := New()
+	r.Set("payments", FromContract(parseContract(t, paymentsContract)))
+	for _, tt := range redactionTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Map("pay", tt.params); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Map() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	// Other actions are not redacted
+	params := map[string]interface{}{"email": "ada@example.com"}
+	if got := r.Map("refund", params); !reflect.DeepEqual(got, params) {
+		t.Errorf("Map() of another action = %v, want it unchanged", got)
+	}
+}
+
+func TestValues(t *testing.T) {
+	r := New()
+	r.Set("payments", FromProto(parseContract(t, paymentsContract).ToProto()))
+	for _, tt := range redactionTests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := make(map[string]*nfa_intent_v1alpha.Value, len(tt.params))
+			for k, v := range tt.params {
+				params[k] = contract.ValueToProto(v)
+			}
+			original := make(map[string]*nfa_intent_v1alpha.Value, len(params))
+			for k, v := range params {
+				original[k] = proto.Clone(v).(*nfa_intent_v1alpha.Value)
+			}
+			got := r.Values("pay", params)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Values() = %v, want %v", got, tt.want)
+			}
+			for k, want := range tt.want {
+				if !proto.Equal(got[k], contract.ValueToProto(want)) {
+					t.Errorf("Values()[%s] = %v, want %v", k, got[k], want)
+				}
+			}
+			// The parameters themselves are left as they were
+			for k, v := range params {
+				if !proto.Equal(v, original[k]) {
+					t.Errorf("Values() changed parameter %s to %v", k, v)
+				}
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	r := New()
+	r.Set("payments", FromContract(parseContract(t, paymentsContract)))
+	r.Set("receipts", Rules{"pay": {"email": contract.SensitivitySensitive, "card.brand": contract.SensitivityPII}})
+	for _, tt := range []struct {
+		parameter string
+		want      contract.Sensitivity
+	}{
+		// Sensitive wins over pii, whichever contract declares it
+		{"email", contract.SensitivitySensitive},
+		{"card.brand", contract.SensitivityPII},
+		{"card.number", contract.SensitivitySensitive},
+		{"amount", ""},
+	} {
+		if got := r.Sensitivity("pay", tt.parameter); got != tt.want {
+			t.Errorf("Sensitivity(pay, %s) = %q, want %q", tt.parameter, got, tt.want)
+		}
+	}
+
+	r.Remove("receipts")
+	if got := r.Sensitivity("pay", "email"); got != contract.SensitivityPII {
+		t.Errorf("Sensitivity(pay, email) after Remove = %q, want pii", got)
+	}
+	var none *Redactor
+	if got := none.Strings("pay", map[string]string{"email": "ada@example.com"}); got["email"] != "ada@example.com" {
+		t.Errorf("nil Redactor Strings() = %v, want it unchanged", got)
+	}
+}
+
+func TestData(t *testing.T) {
+	r := New()
+	r.Set("payments", FromContract(parseContract(t, paymentsContract)))
+	for _, tt := range []struct {
+		name string
+		data map[string]interface{}
+		want map[string]interface{}
+	}{
+		{
+			name: "json parameters",
+			data: map[string]interface{}{KeyAction: "pay", KeyParameters: map[string]interface{}{"tags": []interface{}{"ada"}}},
+			want: map[string]interface{}{KeyAction: "pay", KeyParameters: map[string]interface{}{"tags": []interface{}{"[REDACTED:pii]"}}},
+		},
+		{
+			name: "string parameters",
+			data: map[string]interface{}{KeyAction: "pay", KeyParameters: map[string]string{"email": "ada@example.com", "amount": "3"}},
+			want: map[string]interface{}{KeyAction: "pay", KeyParameters: map[string]string{"email": "[REDACTED:pii]", "amount": "3"}},
+		},
+		{
+			name: "without action",
+			data: map[string]interface{}{KeyParameters: map[string]string{"email": "ada@example.com"}},
+			want: map[string]interface{}{KeyParameters: map[string]string{"email": "ada@example.com"}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Data(tt.data); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Data() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
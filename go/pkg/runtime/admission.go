@@ -0,0 +1,209 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/protoconv"
+	nfa_intent_v1 "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// violationField prefixes the parameter names in the field violations of
+// rejected requests
+const violationField = "parameters."
+
+// UnaryAdmission returns an interceptor rejecting intent requests that do
+// not match c, for gRPC servers not created with NewIntentServer, which use
+// WithAdmission instead. Of the options only WithLogger and
+// WithResultValidation apply.
+func UnaryAdmission(c *contract.IntentContract, opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return admission{contract: c, opts: &o}.unary
+}
+
+// StreamAdmission is UnaryAdmission for streams: every intent request
+// received on a stream is checked
+func StreamAdmission(c *contract.IntentContract, opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return admission{contract: c, opts: &o}.stream
+}
+
+// Violations returns the parameters an intent request was rejected for, from
+// the error of a call to a provider checking its contract, or nil when err
+// is not such a rejection
+func Violations(err error) []contract.Violation {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.InvalidArgument {
+		return nil
+	}
+	var violations []contract.Violation
+	for _, detail := range st.Details() {
+		br, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, fv := range br.FieldViolations {
+			violations = append(violations, contract.Violation{
+				Parameter:   strings.TrimPrefix(fv.Field, violationField),
+				Description: fv.Description,
+			})
+		}
+	}
+	return violations
+}
+
+// admission rejects intent requests that do not match a contract. Requests
+// of other types pass unchecked.
+type admission struct {
+	contract *contract.IntentContract
+	opts     *options
+}
+
+func (a admission) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.admit(info.FullMethod, req); err != nil {
+		return nil, err
+	}
+	resp, err := handler(ctx, req)
+	if err != nil || !a.opts.checkResults {
+		return resp, err
+	}
+	if err := a.checkResult(info.FullMethod, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// checkResult checks the response to an intent request against the result
+// schema of its action and returns the status error to replace it with,
+// only when WithResultValidation enforces the schema
+func (a admission) checkResult(method string, req, resp interface{}) error {
+	action := intentAction(req)
+	msg, ok := resp.(proto.Message)
+	if !ok || action == "" || a.contract.Results(action) == nil {
+		return nil
+	}
+	fields, err := contract.ResultFields(msg)
+	if err == nil {
+		err = a.contract.CheckResult(action, fields)
+	}
+	if err == nil {
+		return nil
+	}
+	a.opts.log(logging.Matcher).Warn("intent response outside the result schema", "method", method, "action", action, "error", err)
+	if !a.opts.enforceResults {
+		return nil
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func (a admission) stream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &admittedStream{ServerStream: stream, admission: a, method: info.FullMethod})
+}
+
+// admittedStream checks the messages received on a stream
+type admittedStream struct {
+	grpc.ServerStream
+	admission admission
+	method    string
+}
+
+func (s *admittedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.admission.admit(s.method, m)
+}
+
+// admit maps the parameters of msg and checks it against the contract when
+// it is an intent request, of either proto version, and returns the status
+// error to reject it with.
+// Parameters outside the constraints are listed as field violations of a
+// BadRequest detail, see Violations.
+func (a admission) admit(method string, msg interface{}) error {
+	var req *nfa_intent_v1alpha.IntentRequest
+	v1, _ := msg.(*nfa_intent_v1.IntentRequest)
+	switch m := msg.(type) {
+	case *nfa_intent_v1alpha.IntentRequest:
+		req = m
+	case *nfa_intent_v1.IntentRequest:
+		converted, err := protoconv.IntentRequestFromV1(m)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "malformed intent request: %v", err)
+		}
+		req = converted
+	default:
+		return nil
+	}
+
+	err := a.mapParameters(req, v1)
+	if err == nil {
+		err = a.contract.Admit(req.Action, req.Parameters)
+	}
+	if err == nil {
+		return nil
+	}
+	a.opts.log(logging.Matcher).Info("intent request rejected", "method", method, "action", req.Action, "error", err)
+	if errors.Is(err, contract.ErrUnknownAction) {
+		return status.Error(codes.Unimplemented, err.Error())
+	}
+	st := status.New(codes.InvalidArgument, err.Error())
+	var ve *contract.ViolationError
+	if !errors.As(err, &ve) {
+		return st.Err()
+	}
+	br := &errdetails.BadRequest{}
+	for _, v := range ve.Violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       violationField + v.Parameter,
+			Description: v.Description,
+		})
+	}
+	if detailed, err := st.WithDetails(br); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// intentAction returns the action of msg when it is an intent request, of
+// either proto version, and an empty string otherwise
+func intentAction(msg interface{}) string {
+	switch m := msg.(type) {
+	case *nfa_intent_v1alpha.IntentRequest:
+		return m.Action
+	case *nfa_intent_v1.IntentRequest:
+		return m.Action
+	}
+	return ""
+}
+
+// mapParameters replaces the parameters of req with those the contract's
+// mappings derive, also in v1, the message req was converted from, if any
+func (a admission) mapParameters(req *nfa_intent_v1alpha.IntentRequest, v1 *nfa_intent_v1.IntentRequest) error {
+	mapped, err := a.contract.MapParameters(req.Action, req.Parameters)
+	if err != nil {
+		return err
+	}
+	if maps.Equal(mapped, req.Parameters) {
+		return nil
+	}
+	req.Parameters = mapped
+	if v1 != nil {
+		converted, err := protoconv.IntentRequestToV1(req)
+		if err != nil {
+			return fmt.Errorf("failed to convert mapped parameters: %w", err)
+		}
+		v1.Parameters = converted.Parameters
+	}
+	return nil
+}
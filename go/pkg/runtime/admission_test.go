@@ -0,0 +1,175 @@
+package runtime
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const admissionContract = `
+version: v1alpha
+kind: IntentContract
+metadata:
+  name: speaker
+spec:
+  intentPatterns:
+    - pattern:
+        action: speak
+      constraints:
+        requiredParameters: [text, voice]
+        parameterConstraints:
+          text:
+            type: string
+            minLength: 1
+          rate:
+            type: number
+            min: 0.5
+            max: 2
+  implementation:
+    endpoint:
+      type: grpc
+      port: 50052
+`
+
+func TestUnaryAdmissionListsViolations(t *testing.T) {
+	c, err := contract.ParseIntentContract([]byte(admissionContract))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	interceptor := UnaryAdmission(c)
+	info := &grpc.UnaryServerInfo{FullMethod: "/speaker.Speaker/Speak"}
+	handled := false
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		handled = true
+		return nil, nil
+	}
+
+	_, err = interceptor(context.Background(), &nfa_intent_v1alpha.IntentRequest{
+		Action: "speak",
+		Parameters: map[string]*nfa_intent_v1alpha.Value{
+			"text": {Value: &nfa_intent_v1alpha.Value_StringValue{StringValue: ""}},
+			"rate": {Value: &nfa_intent_v1alpha.Value_NumberValue{NumberValue: 3}},
+		},
+	}, info, handler)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("error = %v, want InvalidArgument", err)
+	}
+	if handled {
+		t.Errorf("rejected request reached the handler")
+	}
+	var got []string
+	for _, v := range Violations(err) {
+		got = append(got, v.Parameter)
+	}
+	if want := []string{"rate", "text", "voice"}; !slices.Equal(got, want) {
+		t.Errorf("violations = %v, want %v", got, want)
+	}
+
+	_, err = interceptor(context.Background(), &nfa_intent_v1alpha.IntentRequest{Action: "shout"}, info, handler)
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("undeclared action error = %v, want Unimplemented", err)
+	}
+	if Violations(err) != nil {
+		t.Errorf("undeclared action has violations %v", Violations(err))
+	}
+}
+
+func TestAdmissionMapsParameters(t *testing.T) {
+	c, err := contract.ParseIntentContract([]byte(admissionContract))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	percent := 0.01
+	c.Spec.IntentPatterns[0].Mappings = []contract.ParameterMapping{
+		{From: "message", To: "text"},
+		{From: "speed_pct", To: "rate", Convert: &contract.UnitConversion{Scale: &percent}},
+		{To: "voice", Template: "{{.lang}}-standard"},
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	interceptor := UnaryAdmission(c)
+	info := &grpc.UnaryServerInfo{FullMethod: "/speaker.Speaker/Speak"}
+	var got map[string]*nfa_intent_v1alpha.Value
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		got = req.(*nfa_intent_v1alpha.IntentRequest).Parameters
+		return nil, nil
+	}
+
+	_, err = interceptor(context.Background(), &nfa_intent_v1alpha.IntentRequest{
+		Action: "speak",
+		Parameters: map[string]*nfa_intent_v1alpha.Value{
+			"message":   contract.ValueToProto("hello"),
+			"speed_pct": contract.ValueToProto(150.0),
+			"lang":      contract.ValueToProto("en"),
+		},
+	}, info, handler)
+	if err != nil {
+		t.Fatalf("request with consumer parameters rejected: %v", err)
+	}
+	if got["text"].GetStringValue() != "hello" || got["rate"].GetNumberValue() != 1.5 || got["voice"].GetStringValue() != "en-standard" {
+		t.Errorf("handler received %v, want mapped text, rate and voice", got)
+	}
+	if _, ok := got["message"]; ok {
+		t.Errorf("renamed parameter message was kept")
+	}
+
+	_, err = interceptor(context.Background(), &nfa_intent_v1alpha.IntentRequest{
+		Action: "speak",
+		Parameters: map[string]*nfa_intent_v1alpha.Value{
+			"message":   contract.ValueToProto("hello"),
+			"voice":     contract.ValueToProto("en-standard"),
+			"speed_pct": contract.ValueToProto("fast"),
+		},
+	}, info, handler)
+	if violations := Violations(err); len(violations) != 1 || violations[0].Parameter != "speed_pct" {
+		t.Errorf("unconvertible parameter: violations = %v (error %v), want speed_pct", violations, err)
+	}
+}
+
+func TestAdmissionChecksResults(t *testing.T) {
+	c, err := contract.ParseIntentContract([]byte(admissionContract))
+	if err != nil {
+		t.Fatalf("ParseIntentContract() error = %v", err)
+	}
+	minLength := 1
+	c.Spec.IntentPatterns[0].Results = &contract.ResultSchema{
+		Required: []string{"name"},
+		Fields:   map[string]contract.ParameterConstraint{"name": {Type: "string", MinLength: &minLength}},
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/speaker.Speaker/Speak"}
+	req := &nfa_intent_v1alpha.IntentRequest{
+		Action: "speak",
+		Parameters: map[string]*nfa_intent_v1alpha.Value{
+			"text":  contract.ValueToProto("hello"),
+			"voice": contract.ValueToProto("en"),
+		},
+	}
+	respond := func(name string) grpc.UnaryHandler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			return &nfa_intent_v1alpha.Metadata{Name: name}, nil
+		}
+	}
+
+	// Without result validation any response passes
+	if resp, err := UnaryAdmission(c)(context.Background(), req, info, respond("")); err != nil || resp == nil {
+		t.Errorf("unchecked response = %v, %v, want it returned", resp, err)
+	}
+	// Logged only, unless enforced
+	if resp, err := UnaryAdmission(c, WithResultValidation(false))(context.Background(), req, info, respond("")); err != nil || resp == nil {
+		t.Errorf("response outside the schema, not enforced = %v, %v, want it returned", resp, err)
+	}
+	enforced := UnaryAdmission(c, WithResultValidation(true))
+	if resp, err := enforced(context.Background(), req, info, respond("")); status.Code(err) != codes.Internal || resp != nil {
+		t.Errorf("response outside the schema, enforced = %v, %v, want Internal", resp, err)
+	}
+	if _, err := enforced(context.Background(), req, info, respond("Ada")); err != nil {
+		t.Errorf("response within the schema error = %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package runtime
+
+import (
+	"context"
+	goruntime "runtime"
+	"sync"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/runtime/resources"
+)
+
+const (
+	// tuneInterval is how often an adaptive server measures the host
+	tuneInterval = 5 * time.Second
+	// highPressure is the host pressure above which an adaptive server
+	// lowers its concurrency limit
+	highPressure = 0.9
+	// lowPressure is the host pressure below which an adaptive server with
+	// every slot busy raises its concurrency limit
+	lowPressure = 0.7
+	// requestsPerCPU bounds the concurrency limit of an adaptive server
+	// without an explicit maximum
+	requestsPerCPU = 4
+)
+
+// WithAdaptiveConcurrency makes an intent server tune its concurrency limit
+// to the pressure on the host's CPUs and memory, between 1 and max requests,
+// instead of a limit set with WithConcurrencyLimit. The limit starts at max,
+// shrinks by a quarter while the host is saturated and grows by one while
+// the host has headroom and every slot is busy. With max 0 the limit is at
+// most 4 requests per CPU. Requests over the limit queue as with
+// WithConcurrencyLimit. A server with a service ID advertises its limit and
+// load with the heartbeats of its runtime in this process.
+func WithAdaptiveConcurrency(max int) Option {
+	return func(o *options) {
+		if max <= 0 {
+			max = requestsPerCPU * goruntime.NumCPU()
+		}
+		o.adaptiveConcurrency = max
+	}
+}
+
+// concurrencyTuner adjusts the limit of a request queue to host pressure
+type concurrencyTuner struct {
+	queue   *requestQueue
+	max     int
+	measure func() (resources.Pressure, error)
+
+	mu       sync.Mutex
+	pressure resources.Pressure // last measured
+
+	start sync.Once
+	ctx   context.Context
+	stop  context.CancelFunc
+}
+
+func newConcurrencyTuner(queue *requestQueue, max int) *concurrencyTuner {
+	ctx, stop := context.WithCancel(context.Background())
+	return &concurrencyTuner{
+		queue:   queue,
+		max:     max,
+		measure: resources.MeasurePressure,
+		ctx:     ctx,
+		stop:    stop,
+	}
+}
+
+// tune measures the host once and adjusts the limit, returning the new one
+func (t *concurrencyTuner) tune() (int, error) {
+	pressure, err := t.measure()
+	if err != nil {
+		return 0, err
+	}
+	t.mu.Lock()
+	t.pressure = pressure
+	t.mu.Unlock()
+
+	limit, inFlight := t.queue.load()
+	switch p := pressure.Max(); {
+	case p >= highPressure:
+		limit = max(1, min(limit-1, limit*3/4))
+	case p < lowPressure && inFlight >= limit:
+		limit = min(t.max, limit+1)
+	}
+	t.queue.setLimit(limit)
+	return limit, nil
+}
+
+// capacity returns the limit, load and host pressure to advertise
+func (t *concurrencyTuner) capacity() broker.Capacity {
+	limit, inFlight := t.queue.load()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return broker.Capacity{
+		ConcurrencyLimit: limit,
+		InFlight:         inFlight,
+		CPUPressure:      t.pressure.CPU,
+		MemoryPressure:   t.pressure.Memory,
+	}
+}
+
+// startTuning starts tuning the concurrency limit of an adaptive server,
+// once, until Stop
+func (s *IntentServer) startTuning() {
+	if s.tuner == nil {
+		return
+	}
+	s.tuner.start.Do(func() { go s.tuneLoop() })
+}
+
+func (s *IntentServer) tuneLoop() {
+	log := s.opts.log(logging.Health)
+	for {
+		previous, _ := s.queue.load()
+		limit, err := s.tuner.tune()
+		if err != nil {
+			log.Warn("failed to measure host pressure", "error", err)
+		} else if limit != previous {
+			log.Info("concurrency limit changed", "limit", limit, "previous", previous)
+		}
+		select {
+		case <-s.tuner.ctx.Done():
+			return
+		case <-s.opts.clock.After(tuneInterval):
+		}
+	}
+}
+
+// Capacity returns the concurrency limit, load and host pressure of a server
+// created with WithAdaptiveConcurrency, and false for other servers. Load
+// returns what heartbeats advertise, for every server.
+func (s *IntentServer) Capacity() (broker.Capacity, bool) {
+	if s.tuner == nil {
+		return broker.Capacity{}, false
+	}
+	return s.tuner.capacity(), true
+}
+
+// localCapacity returns the load of the intent server of serviceID in this
+// process, or nil
+func localCapacity(serviceID string) *broker.Capacity {
+	local.Lock()
+	s, ok := local.servers[serviceID]
+	local.Unlock()
+	if !ok {
+		return nil
+	}
+	load := s.Load()
+	return &load
+}
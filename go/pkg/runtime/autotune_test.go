@@ -0,0 +1,89 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/interactivity"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/runtime/resources"
+)
+
+func TestConcurrencyTuner(t *testing.T) {
+	q := newRequestQueue(8)
+	tuner := newConcurrencyTuner(q, 8)
+	defer tuner.stop()
+	var pressure resources.Pressure
+	tuner.measure = func() (resources.Pressure, error) { return pressure, nil }
+	tune := func() int {
+		t.Helper()
+		limit, err := tuner.tune()
+		if err != nil {
+			t.Fatalf("tune() error = %v", err)
+		}
+		return limit
+	}
+
+	// A saturated host shrinks the limit by a quarter, down to 1
+	pressure = resources.Pressure{CPU: 0.95, Memory: 0.4}
+	for _, want := range []int{6, 4, 3, 2, 1, 1} {
+		if got := tune(); got != want {
+			t.Fatalf("limit under pressure = %d, want %d", got, want)
+		}
+	}
+
+	// With headroom the limit only grows while every slot is busy
+	pressure = resources.Pressure{CPU: 0.2, Memory: 0.9}
+	if got := tune(); got != 1 {
+		t.Errorf("limit with memory pressure = %d, want 1", got)
+	}
+	pressure = resources.Pressure{CPU: 0.2, Memory: 0.3}
+	if got := tune(); got != 1 {
+		t.Errorf("limit of an idle server = %d, want 1", got)
+	}
+	for i := 0; i < 8; i++ {
+		if err := q.acquire(context.Background(), interactivity.Unspecified); err != nil {
+			t.Fatalf("acquire() error = %v", err)
+		}
+		tune()
+	}
+	if limit, inFlight := q.load(); limit != 8 || inFlight != 8 {
+		t.Errorf("load of a busy server = %d, %d, want the maximum 8 running", limit, inFlight)
+	}
+
+	capacity := tuner.capacity()
+	if capacity.ConcurrencyLimit != 8 || capacity.InFlight != 8 || capacity.CPUPressure != 0.2 || capacity.MemoryPressure != 0.3 {
+		t.Errorf("capacity() = %+v", capacity)
+	}
+}
+
+func TestHeartbeatAdvertisesCapacity(t *testing.T) {
+	b := broker.NewEmbedded()
+	defer b.Close()
+	r := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...))
+	defer r.Close()
+	ctx := context.Background()
+	if err := r.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	serviceID, err := r.RegisterFromBytes(ctx, []byte(leaseContract))
+	if err != nil {
+		t.Fatalf("RegisterFromBytes() error = %v", err)
+	}
+	server := NewIntentServer(0, WithServiceID(serviceID), WithAdaptiveConcurrency(3))
+	defer server.Stop()
+
+	if err := r.Heartbeat(ctx); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	for _, svc := range b.Services() {
+		if svc.ServiceID != serviceID {
+			continue
+		}
+		if svc.Capacity == nil || svc.Capacity.ConcurrencyLimit != 3 {
+			t.Errorf("advertised capacity = %+v, want a limit of 3", svc.Capacity)
+		}
+		return
+	}
+	t.Errorf("service %s not registered", serviceID)
+}
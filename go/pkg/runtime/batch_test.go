@@ -0,0 +1,90 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+)
+
+func TestInvokeBatch(t *testing.T) {
+	b := broker.NewEmbedded()
+	defer b.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Two widgets, answering slowly so the batch only takes as long as the
+	// slowest intent if they are delivered concurrently
+	for _, room := range []string{"kitchen", "hall"} {
+		widget := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...))
+		defer widget.Close()
+		if err := widget.Connect(); err != nil {
+			t.Fatalf("Connect() error = %v", err)
+		}
+		// Registered, so each control stream has its own runtime ID
+		if _, err := widget.RegisterFromBytes(ctx, []byte(leaseContract)); err != nil {
+			t.Fatalf("RegisterFromBytes() error = %v", err)
+		}
+		room := room
+		widget.OnBroadcast(func(invoke *nfa_control_v1alpha.Invoke) ([]byte, error) {
+			time.Sleep(200 * time.Millisecond)
+			if invoke.Action == "fail" {
+				return nil, errors.New("cannot")
+			}
+			return []byte(room + ":" + invoke.Action), nil
+		})
+		go widget.StartControlStream(ctx, map[string]string{"room": room})
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(b.Hub().Sessions()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(b.Hub().Sessions()) < 2 {
+		t.Fatalf("%d control streams open, want 2", len(b.Hub().Sessions()))
+	}
+
+	r := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...))
+	defer r.Close()
+	if _, err := r.InvokeBatch(ctx, nil); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("InvokeBatch() before Connect error = %v, want ErrNotConnected", err)
+	}
+	if err := r.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	start := time.Now()
+	results, err := r.InvokeBatch(ctx, []BatchIntent{
+		{Selector: map[string]string{"room": "kitchen"}, Intent: &nfa_control_v1alpha.Invoke{Action: "weather"}},
+		{Selector: map[string]string{"room": "hall"}, Intent: &nfa_control_v1alpha.Invoke{Action: "calendar"}},
+		{Intent: &nfa_control_v1alpha.Invoke{}},
+		{Selector: map[string]string{"room": "hall"}, Intent: &nfa_control_v1alpha.Invoke{Action: "fail"}},
+	})
+	if err != nil {
+		t.Fatalf("InvokeBatch() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("InvokeBatch() took %v, want the intents delivered concurrently", elapsed)
+	}
+	if len(results) != 4 {
+		t.Fatalf("InvokeBatch() returned %d results, want 4", len(results))
+	}
+
+	for i, want := range []string{"kitchen:weather", "hall:calendar"} {
+		res := results[i]
+		if res.Err != nil || len(res.Targets) != 1 {
+			t.Fatalf("result %d = %+v, want one target", i, res)
+		}
+		if got := string(res.Targets[0].Output); got != want {
+			t.Errorf("result %d output = %q, want %q", i, got, want)
+		}
+	}
+	if results[2].Err == nil {
+		t.Errorf("result of an intent without an action has no error")
+	}
+	if targets := results[3].Targets; len(targets) != 1 || targets[0].State != nfa_control_v1alpha.TargetState_TARGET_STATE_FAILED {
+		t.Errorf("result of a failing intent = %+v, want one failed target", results[3])
+	}
+}
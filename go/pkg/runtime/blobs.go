@@ -0,0 +1,45 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/blob"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+)
+
+// UploadBlob stores a large payload on the broker's blob service and returns
+// the reference to pass in intent parameters instead of the data
+func (r *IntentRuntime) UploadBlob(ctx context.Context, data io.Reader, contentType string, ttl time.Duration) (string, error) {
+	if err := r.ready(); err != nil {
+		return "", err
+	}
+	if err := r.require(broker.FeatureBlobs); err != nil {
+		return "", err
+	}
+	ctx, cancel := r.bind(ctx)
+	defer cancel()
+	uploaded, err := blob.NewClient(r.conn).Upload(ctx, data, contentType, ttl)
+	if err != nil {
+		return "", unsupported(broker.FeatureBlobs, err)
+	}
+	return uploaded.Ref, nil
+}
+
+// FetchBlob writes the payload behind a blob reference received in an intent to w
+func (r *IntentRuntime) FetchBlob(ctx context.Context, ref string, w io.Writer) error {
+	if err := r.ready(); err != nil {
+		return err
+	}
+	if err := r.require(broker.FeatureBlobs); err != nil {
+		return err
+	}
+	ctx, cancel := r.bind(ctx)
+	defer cancel()
+	if _, ok := blob.ParseRef(ref); !ok {
+		return fmt.Errorf("%q is not a blob reference", ref)
+	}
+	return unsupported(broker.FeatureBlobs, blob.NewClient(r.conn).Download(ctx, ref, w))
+}
@@ -0,0 +1,113 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_capabilities_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/capabilities/v1alpha"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CapabilitiesServer implements the capabilities service from the contracts
+// registered by a runtime and its current feature flags
+type CapabilitiesServer struct {
+	nfa_capabilities_v1alpha.UnimplementedCapabilitiesServer
+
+	runtime *IntentRuntime
+	// serviceID limits the response to the contract registered under it
+	serviceID string
+}
+
+// NewCapabilitiesServer creates a capabilities server for runtime, reporting
+// the actions of every contract it registered. An IntentServer created with
+// WithCapabilities registers one itself, limited to the contract of the
+// service set with WithServiceID.
+func NewCapabilitiesServer(runtime *IntentRuntime) *CapabilitiesServer {
+	return &CapabilitiesServer{
+		runtime: runtime,
+	}
+}
+
+// GetCapabilities implements the GetCapabilities RPC. It fails with
+// Unavailable until the runtime has registered a contract, and with NotFound
+// for an action no reported contract declares. The response names the first
+// reported contract and its service ID.
+func (c *CapabilitiesServer) GetCapabilities(ctx context.Context, req *nfa_capabilities_v1alpha.GetCapabilitiesRequest) (*nfa_capabilities_v1alpha.CapabilitiesResponse, error) {
+	r := c.runtime
+	var regs []registration
+	for _, reg := range r.registered() {
+		if c.serviceID == "" || reg.serviceID == c.serviceID {
+			regs = append(regs, reg)
+		}
+	}
+	if len(regs) == 0 {
+		return nil, status.Error(codes.Unavailable, "no contract registered yet")
+	}
+
+	resp := &nfa_capabilities_v1alpha.CapabilitiesResponse{
+		Contract:  regs[0].contract.Metadata.Name,
+		ServiceId: regs[0].serviceID,
+		Draining:  r.Draining(),
+	}
+	for _, reg := range regs {
+		resp.Actions = append(resp.Actions, actionCapabilities(reg.contract, req.Action)...)
+	}
+	if req.Action != "" && len(resp.Actions) == 0 {
+		return nil, status.Errorf(codes.NotFound, "contract %s does not declare action %s", resp.Contract, req.Action)
+	}
+
+	for _, f := range r.flags.Snapshot() {
+		resp.FeatureFlags = append(resp.FeatureFlags, &nfa_control_v1alpha.FeatureFlag{
+			Name:       f.Name,
+			Enabled:    f.Enabled,
+			Percentage: f.Percentage,
+		})
+	}
+	return resp, nil
+}
+
+// actionCapabilities describes the actions of intentContract, or only action
+// when it is not empty
+func actionCapabilities(intentContract *contract.IntentContract, action string) []*nfa_capabilities_v1alpha.ActionCapability {
+	var actions []*nfa_capabilities_v1alpha.ActionCapability
+	for _, p := range intentContract.Spec.IntentPatterns {
+		if action != "" && p.Pattern.Action != action {
+			continue
+		}
+		capability := &nfa_capabilities_v1alpha.ActionCapability{
+			Action:    p.Pattern.Action,
+			Streaming: p.Streaming.ToProto(),
+			Results:   p.Results.ToProto(),
+		}
+		if p.Constraints != nil {
+			capability.RequiredParameters = p.Constraints.RequiredParameters
+			capability.Parameters = make(map[string]*nfa_intent_v1alpha.ParameterConstraint, len(p.Constraints.ParameterConstraints))
+			for name, pc := range p.Constraints.ParameterConstraints {
+				capability.Parameters[name] = pc.ToProto()
+			}
+		}
+		actions = append(actions, capability)
+	}
+	return actions
+}
+
+// Capabilities asks the provider serviceID, over ProviderConn, what it
+// supports for action, or for every action when action is empty. Consumers
+// call it before committing to a long session with the provider.
+func (r *IntentRuntime) Capabilities(ctx context.Context, serviceID, action string) (*nfa_capabilities_v1alpha.CapabilitiesResponse, error) {
+	cc, err := r.ProviderConn(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := r.bind(ctx)
+	defer cancel()
+	resp, err := nfa_capabilities_v1alpha.NewCapabilitiesClient(cc).GetCapabilities(ctx, &nfa_capabilities_v1alpha.GetCapabilitiesRequest{Action: action})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get capabilities of %s: %w", serviceID, err)
+	}
+	return resp, nil
+}
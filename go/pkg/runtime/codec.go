@@ -0,0 +1,122 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec serializes the messages of requests to providers and their
+// responses. Its name is the gRPC content subtype it is negotiated by:
+// providers declare it as the codec of their contract's endpoint, consumers
+// dialing the endpoint with ResolvingDialer encode their requests with it,
+// and intent servers answer in the codec of each request. Protobuf is the
+// default; JSON and MessagePack are built in.
+type Codec interface {
+	Name() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// RegisterCodec makes c available to consumers and intent servers under
+// its name, replacing a codec of the same name. It is not safe for
+// concurrent use; call it from an init function.
+func RegisterCodec(c Codec) {
+	encoding.RegisterCodec(c)
+}
+
+// LookupCodec returns the codec registered under name, or nil
+func LookupCodec(name string) Codec {
+	return encoding.GetCodec(name)
+}
+
+func init() {
+	RegisterCodec(jsonCodec{})
+	RegisterCodec(msgpackCodec{})
+}
+
+// Messages travel in their protobuf JSON mapping with proto field names,
+// as result schemas name them; unknown fields of newer providers are
+// ignored
+var (
+	jsonMarshal   = protojson.MarshalOptions{UseProtoNames: true}
+	jsonUnmarshal = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// jsonCodec encodes messages as JSON
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string { return contract.CodecJSON }
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	m, err := codecMessage(v)
+	if err != nil {
+		return nil, err
+	}
+	return jsonMarshal.Marshal(m)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	m, err := codecMessage(v)
+	if err != nil {
+		return err
+	}
+	return jsonUnmarshal.Unmarshal(data, m)
+}
+
+// msgpackCodec encodes messages as MessagePack, with the structure of
+// their JSON mapping
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string { return contract.CodecMsgpack }
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	m, err := codecMessage(v)
+	if err != nil {
+		return nil, err
+	}
+	data, err := jsonMarshal.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := writeMsgpack(&buf, value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	m, err := codecMessage(v)
+	if err != nil {
+		return err
+	}
+	value, err := readMsgpack(data)
+	if err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("msgpack: %w", err)
+	}
+	return jsonUnmarshal.Unmarshal(encoded, m)
+}
+
+func codecMessage(v interface{}) (proto.Message, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("cannot encode %T, not a protobuf message", v)
+	}
+	return m, nil
+}
@@ -0,0 +1,124 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/endpoint"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCodecsRoundTrip(t *testing.T) {
+	req := &nfa_intent_v1alpha.IntentRequest{
+		Action: "translate_text",
+		Parameters: map[string]*nfa_intent_v1alpha.Value{
+			"text":  contract.ValueToProto("héllo " + strings.Repeat("x", 300)),
+			"count": contract.ValueToProto(-40000.0),
+			"ratio": contract.ValueToProto(0.25),
+			"tags":  contract.ValueToProto([]interface{}{"a", true, nil}),
+		},
+		Debug: true,
+	}
+	for _, name := range []string{contract.CodecProto, contract.CodecJSON, contract.CodecMsgpack} {
+		t.Run(name, func(t *testing.T) {
+			codec := LookupCodec(name)
+			if codec == nil {
+				t.Fatalf("codec %s is not registered", name)
+			}
+			data, err := codec.Marshal(req)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			got := &nfa_intent_v1alpha.IntentRequest{}
+			if err := codec.Unmarshal(data, got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !proto.Equal(got, req) {
+				t.Errorf("round trip = %v, want %v", got, req)
+			}
+		})
+	}
+}
+
+func TestMsgpackReadsBinary(t *testing.T) {
+	// {"action": "lights_on", "payload": bin8 [1 2 3], "synthetic": true},
+	// as written by another implementation
+	data := []byte{0x83,
+		0xa6, 'a', 'c', 't', 'i', 'o', 'n', 0xa9, 'l', 'i', 'g', 'h', 't', 's', '_', 'o', 'n',
+		0xa7, 'p', 'a', 'y', 'l', 'o', 'a', 'd', 0xc4, 3, 1, 2, 3,
+		0xa9, 's', 'y', 'n', 't', 'h', 'e', 't', 'i', 'c', 0xc3,
+	}
+	got := &nfa_control_v1alpha.Invoke{}
+	if err := LookupCodec(contract.CodecMsgpack).Unmarshal(data, got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	want := &nfa_control_v1alpha.Invoke{Action: "lights_on", Payload: []byte{1, 2, 3}, Synthetic: true}
+	if !proto.Equal(got, want) {
+		t.Errorf("Unmarshal() = %v, want %v", got, want)
+	}
+	for _, truncated := range [][]byte{data[:len(data)-1], {0xdc, 0xff}, {0xd9}} {
+		if err := LookupCodec(contract.CodecMsgpack).Unmarshal(truncated, &nfa_control_v1alpha.Invoke{}); err == nil {
+			t.Errorf("Unmarshal(% x) succeeded, want an error", truncated)
+		}
+	}
+}
+
+// countingCodec is the JSON codec under another name, counting its use
+type countingCodec struct {
+	jsonCodec
+	calls *atomic.Int32
+}
+
+func (countingCodec) Name() string { return "test-json" }
+
+func (c countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.calls.Add(1)
+	return c.jsonCodec.Marshal(v)
+}
+
+func TestResolvingDialerNegotiatesCodec(t *testing.T) {
+	var calls atomic.Int32
+	RegisterCodec(countingCodec{calls: &calls})
+
+	server := NewIntentServer(0)
+	if err := server.Listen(); err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	go server.Start()
+	defer server.Stop()
+	port := server.GetPort()
+
+	e := contract.Endpoint{Type: "grpc", Host: "provider", Port: &port, Codec: "test-json"}
+	loopback := endpoint.HostFunc(func(context.Context, string) (string, error) { return "127.0.0.1", nil })
+	endpoints := func(context.Context, string) (contract.Endpoint, error) { return e, nil }
+	dial := ResolvingDialer(endpoints, loopback, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	ctx := context.Background()
+	cc, err := dial(ctx, "provider-1")
+	if err != nil {
+		t.Fatalf("dial() error = %v", err)
+	}
+	resp, err := grpc_health_v1.NewHealthClient(cc).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Errorf("status = %v, want SERVING", resp.Status)
+	}
+	// The request by the consumer and the response by the server
+	if n := calls.Load(); n != 2 {
+		t.Errorf("codec encoded %d messages, want 2", n)
+	}
+
+	e.Codec = "yaml"
+	if _, err := dial(ctx, "provider-1"); err == nil || !strings.Contains(err.Error(), "not registered") {
+		t.Errorf("dial() with an unknown codec error = %v, want not registered", err)
+	}
+}
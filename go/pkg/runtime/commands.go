@@ -0,0 +1,166 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/provenance"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	otelcodes "go.opentelemetry.io/otel/codes"
+)
+
+// OnDrain registers a callback invoked when the broker asks the runtime to
+// drain, e.g. to finish in-flight work within the grace period
+func (r *IntentRuntime) OnDrain(fn func(*nfa_control_v1alpha.Drain)) {
+	r.drainHandlers = append(r.drainHandlers, fn)
+}
+
+// OnRevoke registers a callback invoked when the broker revokes service
+// registrations of the runtime; a revoke without a service ID covers all of them
+func (r *IntentRuntime) OnRevoke(fn func(*nfa_control_v1alpha.Revoke)) {
+	r.revokeHandlers = append(r.revokeHandlers, fn)
+}
+
+// OnBroadcast registers the handler for intents broadcast by the broker to
+// every runtime matching a selector. Its output is reported back to the
+// broadcaster as this runtime's result.
+func (r *IntentRuntime) OnBroadcast(fn func(*nfa_control_v1alpha.Invoke) ([]byte, error)) {
+	r.invokeHandler = fn
+}
+
+// OnGoAway registers a callback invoked when the broker announces it is
+// shutting down, e.g. to move to another broker when GoAway.FailOver is set.
+// The runtime itself waits for the broker to come back.
+func (r *IntentRuntime) OnGoAway(fn func(*nfa_control_v1alpha.GoAway)) {
+	r.goAwayHandlers = append(r.goAwayHandlers, fn)
+}
+
+// BrokerGoneAway returns the notice of a broker that announced its shutdown,
+// until the runtime reconnects to it, and nil otherwise
+func (r *IntentRuntime) BrokerGoneAway() *nfa_control_v1alpha.GoAway {
+	return r.goAway.Load()
+}
+
+// Draining reports whether the broker has asked the runtime to stop taking new work
+func (r *IntentRuntime) Draining() bool {
+	return r.draining.Load()
+}
+
+func (r *IntentRuntime) handleDrain(drain *nfa_control_v1alpha.Drain) error {
+	r.draining.Store(true)
+	r.opts.log(logging.Control).Info("draining requested by broker",
+		"grace_period_secs", drain.GracePeriodSecs, "reason", drain.Reason)
+	for _, fn := range r.drainHandlers {
+		fn(drain)
+	}
+	return nil
+}
+
+// handleReRegister registers every contract again, reading those registered
+// from a file anew
+func (r *IntentRuntime) handleReRegister(reRegister *nfa_control_v1alpha.ReRegister) error {
+	regs := r.contracts()
+	if len(regs) == 0 {
+		return fmt.Errorf("no contract has been registered")
+	}
+	r.opts.log(logging.Control).Info("re-registration requested by broker", "reason", reRegister.Reason)
+	var errs []error
+	for _, reg := range regs {
+		var err error
+		if reg.contractPath != "" {
+			_, err = r.registerFromFile(r.ctx, reg.contractPath)
+		} else {
+			_, err = r.register(r.ctx, reg.contract, "")
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	r.draining.Store(false)
+	return nil
+}
+
+func (r *IntentRuntime) handleRevoke(revoke *nfa_control_v1alpha.Revoke) error {
+	if !r.forget(revoke.ServiceId) && revoke.ServiceId != "" {
+		return fmt.Errorf("service %s is not registered by this runtime", revoke.ServiceId)
+	}
+	r.opts.log(logging.Control).Warn("registration revoked by broker",
+		"service_id", revoke.ServiceId, "reason", revoke.Reason)
+	for _, fn := range r.revokeHandlers {
+		fn(revoke)
+	}
+	return nil
+}
+
+func (r *IntentRuntime) handleGoAway(goAway *nfa_control_v1alpha.GoAway) error {
+	r.goAway.Store(goAway)
+	attrs := []any{"reason", goAway.Reason, "fail_over", goAway.FailOver}
+	if goAway.ExpectedBackUnix > 0 {
+		attrs = append(attrs, "expected_back", time.Unix(goAway.ExpectedBackUnix, 0))
+	}
+	r.opts.log(logging.Control).Warn("broker is shutting down", attrs...)
+	for _, fn := range r.goAwayHandlers {
+		fn(goAway)
+	}
+	return nil
+}
+
+func (r *IntentRuntime) handleInvoke(invoke *nfa_control_v1alpha.Invoke) ([]byte, error) {
+	if r.invokeHandler == nil {
+		return nil, fmt.Errorf("runtime does not accept broadcast intents")
+	}
+	trace := r.completeParameters(invoke)
+	r.opts.log(logging.Control).Info("broadcast intent received", "action", invoke.Action,
+		"shadow", invoke.Shadow, "synthetic", invoke.Synthetic, r.redactor.Attr(invoke.Action, invoke.Parameters))
+	if invoke.Debug {
+		// Returned to the broadcaster with the result
+		invoke.Provenance = trace.ToProto()
+	}
+	if r.opts.tracer == nil {
+		return r.invokeHandler(invoke)
+	}
+	span := r.opts.startBroadcastSpan(invoke)
+	defer span.End()
+	out, err := r.invokeHandler(invoke)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	return out, err
+}
+
+// completeParameters fills in the parameters of invoke that the broadcaster
+// left out with the defaults of the registered contracts and returns the
+// provenance of every parameter
+func (r *IntentRuntime) completeParameters(invoke *nfa_control_v1alpha.Invoke) provenance.Trace {
+	trace := provenance.FromProto(invoke.Provenance)
+	for name := range invoke.Parameters {
+		if _, ok := trace[name]; !ok {
+			trace.Record(name, provenance.SourceUser, "")
+		}
+	}
+	for _, reg := range r.contracts() {
+		for _, p := range reg.contract.Spec.IntentPatterns {
+			if p.Pattern.Action != invoke.Action || p.Constraints == nil {
+				continue
+			}
+			for name, pc := range p.Constraints.ParameterConstraints {
+				if pc.Default == nil {
+					continue
+				}
+				if trace.Record(name, provenance.SourceContract, reg.contract.Metadata.Name) {
+					if invoke.Parameters == nil {
+						invoke.Parameters = make(map[string]string)
+					}
+					invoke.Parameters[name] = fmt.Sprint(pc.Default)
+				}
+			}
+		}
+	}
+	return trace
+}
@@ -0,0 +1,118 @@
+package runtime
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// clientCert locates the client certificate of WithMTLS
+type clientCert struct {
+	certFile, keyFile, caFile string
+}
+
+// WithMTLS dials the broker over TLS presenting the client certificate in
+// certFile and keyFile, and verifies the broker against the CA certificates
+// in caFile, or the system roots when caFile is empty. It combines with
+// WithTLS, whose configuration is used as the base. It only applies to the
+// runtime's broker connection; Connect fails if the files cannot be loaded.
+func WithMTLS(certFile, keyFile, caFile string) Option {
+	return func(o *options) {
+		o.clientCert = &clientCert{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	}
+}
+
+// WithTokenAuth sends token as a bearer token with every call to the broker.
+// Tokens are only sent over TLS, so it requires WithTLS, WithMTLS or
+// WithEnrollment.
+func WithTokenAuth(token string) Option {
+	return func(o *options) {
+		o.token = token
+	}
+}
+
+// secure reports whether the broker connection uses TLS
+func (o *options) secure() bool {
+	return o.tls != nil || o.clientCert != nil || o.enrollment != nil
+}
+
+// brokerDialOptions returns the options of the runtime's broker connection
+// to target; the certificate of WithEnrollment is renewed until ctx ends
+func (o *options) brokerDialOptions(ctx context.Context, target string) ([]grpc.DialOption, error) {
+	creds := o.transportCredentials()
+	if o.enrollment != nil {
+		config, err := o.enrollment.tlsConfig(ctx, target, o)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(config)
+	} else if o.clientCert != nil {
+		config, err := o.clientCert.load(o.tls)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(config)
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds), o.keepalive.dialOption()}
+	if o.token != "" {
+		if !o.secure() {
+			return nil, fmt.Errorf("token authentication requires TLS to the broker: add WithTLS, WithMTLS or WithEnrollment")
+		}
+		opts = append(opts, grpc.WithPerRPCCredentials(bearerToken(o.token)))
+	}
+	if o.tracer != nil {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(o.unaryClientTracing))
+	}
+	return append(opts, o.dialOpts...), nil
+}
+
+// load builds the client TLS configuration on top of base, which may be nil
+func (c *clientCert) load(base *tls.Config) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if base != nil {
+		config = base.Clone()
+	}
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+	config.Certificates = append(config.Certificates, cert)
+	if c.caFile != "" {
+		pem, err := os.ReadFile(c.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read broker CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in broker CA %s", c.caFile)
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// bearerToken authenticates calls with a bearer token
+type bearerToken string
+
+func (t bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+func (bearerToken) RequireTransportSecurity() bool {
+	return true
+}
+
+// insecureHint explains the error of a plaintext connection reaching a broker
+// that serves TLS, which closes the connection before the HTTP/2 preface
+func (o *options) insecureHint(err error) error {
+	if err == nil || o.secure() || !strings.Contains(err.Error(), "server preface") {
+		return err
+	}
+	return fmt.Errorf("%w (the broker may require TLS, see WithTLS and WithMTLS)", err)
+}
@@ -0,0 +1,327 @@
+package runtime
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	goruntime "runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	nfa_debug_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/debug/v1alpha"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// DebugStreamLogsAction is the standard intent served by the debug service,
+// see WithDebug
+const DebugStreamLogsAction = "nfa.debug.stream_logs"
+
+// DefaultLogBufferSize is the number of records a LogBuffer keeps by default
+const DefaultLogBufferSize = 1000
+
+// subscriberBuffer bounds the records queued for a slow debug stream
+const subscriberBuffer = 256
+
+// processStart is when the process started, for the uptime in runtime stats
+var processStart = time.Now()
+
+// LogBuffer is a slog handler keeping the recent records of the process for
+// the debug service, passing every record on to another handler. Records are
+// kept at info level and above, and at debug level while a debug stream asks
+// for it. Use it as the handler of the logger given to WithLogger.
+type LogBuffer struct {
+	core  *logCore
+	next  slog.Handler
+	attrs []slog.Attr
+	group string
+}
+
+type logCore struct {
+	size  int
+	level atomic.Int64 // lowest level kept
+
+	mu          sync.Mutex
+	records     []*nfa_debug_v1alpha.LogRecord // ring
+	start       int
+	subscribers map[*logSubscriber]struct{}
+}
+
+type logSubscriber struct {
+	level   slog.Level
+	records chan *nfa_debug_v1alpha.LogRecord
+	dropped atomic.Uint64
+}
+
+// NewLogBuffer creates a buffer keeping the last size records, or
+// DefaultLogBufferSize when size is 0, passing them on to next unless it is nil
+func NewLogBuffer(next slog.Handler, size int) *LogBuffer {
+	if size <= 0 {
+		size = DefaultLogBufferSize
+	}
+	core := &logCore{size: size, subscribers: make(map[*logSubscriber]struct{})}
+	core.level.Store(int64(slog.LevelInfo))
+	return &LogBuffer{core: core, next: next}
+}
+
+// Enabled implements slog.Handler
+func (b *LogBuffer) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= slog.Level(b.core.level.Load()) || (b.next != nil && b.next.Enabled(ctx, level))
+}
+
+// Handle implements slog.Handler
+func (b *LogBuffer) Handle(ctx context.Context, r slog.Record) error {
+	var err error
+	if b.next != nil && b.next.Enabled(ctx, r.Level) {
+		err = b.next.Handle(ctx, r)
+	}
+	if r.Level >= slog.Level(b.core.level.Load()) {
+		b.core.add(r.Level, b.record(r))
+	}
+	return err
+}
+
+// WithAttrs implements slog.Handler
+func (b *LogBuffer) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *b
+	for _, a := range attrs {
+		if b.group != "" {
+			a.Key = b.group + "." + a.Key
+		}
+		c.attrs = append(slices.Clip(c.attrs), a)
+	}
+	if b.next != nil {
+		c.next = b.next.WithAttrs(attrs)
+	}
+	return &c
+}
+
+// WithGroup implements slog.Handler
+func (b *LogBuffer) WithGroup(name string) slog.Handler {
+	c := *b
+	if b.group != "" {
+		name = b.group + "." + name
+	}
+	c.group = name
+	if b.next != nil {
+		c.next = b.next.WithGroup(name)
+	}
+	return &c
+}
+
+// record converts r, with the attributes of the handler, to its protobuf form
+func (b *LogBuffer) record(r slog.Record) *nfa_debug_v1alpha.LogRecord {
+	out := &nfa_debug_v1alpha.LogRecord{
+		TimeUnixNano: r.Time.UnixNano(),
+		Level:        strings.ToLower(r.Level.String()),
+		Message:      r.Message,
+		Attributes:   make(map[string]string),
+	}
+	add := func(a slog.Attr) {
+		if a.Key == "component" && out.Component == "" {
+			out.Component = a.Value.String()
+			return
+		}
+		out.Attributes[a.Key] = a.Value.Resolve().String()
+	}
+	for _, a := range b.attrs {
+		add(a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		if b.group != "" {
+			a.Key = b.group + "." + a.Key
+		}
+		add(a)
+		return true
+	})
+	return out
+}
+
+func (c *logCore) add(level slog.Level, rec *nfa_debug_v1alpha.LogRecord) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.records) < c.size {
+		c.records = append(c.records, rec)
+	} else {
+		c.records[c.start] = rec
+		c.start = (c.start + 1) % c.size
+	}
+	for s := range c.subscribers {
+		if level < s.level {
+			continue
+		}
+		select {
+		case s.records <- rec:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+}
+
+// recent returns the last n records, oldest first
+func (c *logCore) recent(n int) []*nfa_debug_v1alpha.LogRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n = min(n, len(c.records))
+	out := make([]*nfa_debug_v1alpha.LogRecord, 0, n)
+	for i := len(c.records) - n; i < len(c.records); i++ {
+		out = append(out, c.records[(c.start+i)%len(c.records)])
+	}
+	return out
+}
+
+func (c *logCore) subscribe(level slog.Level) *logSubscriber {
+	s := &logSubscriber{level: level, records: make(chan *nfa_debug_v1alpha.LogRecord, subscriberBuffer)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subscribers[s] = struct{}{}
+	c.updateLevel()
+	return s
+}
+
+func (c *logCore) unsubscribe(s *logSubscriber) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.subscribers, s)
+	c.updateLevel()
+}
+
+// updateLevel keeps info records and above, and lower ones a subscriber
+// asks for; mu must be held
+func (c *logCore) updateLevel() {
+	level := slog.LevelInfo
+	for s := range c.subscribers {
+		level = min(level, s.level)
+	}
+	c.level.Store(int64(level))
+}
+
+// Authorizer decides whether the caller of ctx may use the debug service;
+// the error it returns, a status error or not, rejects the call
+type Authorizer func(ctx context.Context) error
+
+// TokenAuthorizer authorizes calls bearing token, as sent by WithTokenAuth
+// and nfactl logs -token
+func TokenAuthorizer(token string) Authorizer {
+	return func(ctx context.Context) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return status.Error(codes.Unauthenticated, "bearer token required")
+		}
+		got, ok := strings.CutPrefix(values[0], "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			return status.Error(codes.PermissionDenied, "invalid bearer token")
+		}
+		return nil
+	}
+}
+
+// DebugServer implements the debug service, streaming the records of a
+// LogBuffer and the stats of the process and a runtime to authorized callers
+type DebugServer struct {
+	nfa_debug_v1alpha.UnimplementedDebugServer
+
+	runtime   *IntentRuntime
+	logs      *LogBuffer
+	authorize Authorizer
+}
+
+// NewDebugServer creates a debug server for the records of logs. Calls are
+// rejected unless authorize accepts them, all of them when it is nil.
+// runtime, if not nil, adds its services to the stats. An IntentServer
+// created with WithDebug registers one itself.
+func NewDebugServer(runtime *IntentRuntime, logs *LogBuffer, authorize Authorizer) *DebugServer {
+	return &DebugServer{runtime: runtime, logs: logs, authorize: authorize}
+}
+
+// StreamLogs implements the StreamLogs RPC
+func (d *DebugServer) StreamLogs(req *nfa_debug_v1alpha.StreamLogsRequest, stream nfa_debug_v1alpha.Debug_StreamLogsServer) error {
+	ctx := stream.Context()
+	if d.authorize == nil {
+		return status.Error(codes.PermissionDenied, "debug service has no authorizer")
+	}
+	if err := d.authorize(ctx); err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	level := slog.LevelInfo
+	if req.Level != "" {
+		if err := level.UnmarshalText([]byte(req.Level)); err != nil {
+			return status.Errorf(codes.InvalidArgument, "unknown level %q, expected debug, info, warn or error", req.Level)
+		}
+	}
+	wanted := func(rec *nfa_debug_v1alpha.LogRecord) bool {
+		return len(req.Components) == 0 || slices.Contains(req.Components, rec.Component)
+	}
+	send := func(rec *nfa_debug_v1alpha.LogRecord) error {
+		return stream.Send(&nfa_debug_v1alpha.DebugEvent{Event: &nfa_debug_v1alpha.DebugEvent_Log{Log: rec}})
+	}
+
+	// Subscribe before sending the backlog, so no record falls in between
+	sub := d.logs.core.subscribe(level)
+	defer d.logs.core.unsubscribe(sub)
+	for _, rec := range d.logs.core.recent(int(req.Backlog)) {
+		var l slog.Level
+		if l.UnmarshalText([]byte(rec.Level)) == nil && l >= level && wanted(rec) {
+			if err := send(rec); err != nil {
+				return err
+			}
+		}
+	}
+
+	var stats <-chan time.Time
+	if req.StatsIntervalSecs > 0 {
+		ticker := time.NewTicker(time.Duration(req.StatsIntervalSecs) * time.Second)
+		defer ticker.Stop()
+		stats = ticker.C
+		if err := d.sendStats(stream, sub); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case rec := <-sub.records:
+			if !wanted(rec) {
+				continue
+			}
+			if err := send(rec); err != nil {
+				return err
+			}
+		case <-stats:
+			if err := d.sendStats(stream, sub); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (d *DebugServer) sendStats(stream nfa_debug_v1alpha.Debug_StreamLogsServer, sub *logSubscriber) error {
+	var mem goruntime.MemStats
+	goruntime.ReadMemStats(&mem)
+	now := time.Now()
+	stats := &nfa_debug_v1alpha.RuntimeStats{
+		TimeUnix:       now.Unix(),
+		UptimeSecs:     uint64(now.Sub(processStart).Seconds()),
+		Goroutines:     uint32(goruntime.NumGoroutine()),
+		HeapAllocBytes: mem.HeapAlloc,
+		SysBytes:       mem.Sys,
+		GcCycles:       mem.NumGC,
+		DroppedRecords: sub.dropped.Load(),
+	}
+	if d.runtime != nil {
+		for _, reg := range d.runtime.registered() {
+			stats.ServiceIds = append(stats.ServiceIds, reg.serviceID)
+		}
+		stats.Draining = d.runtime.Draining()
+	}
+	return stream.Send(&nfa_debug_v1alpha.DebugEvent{Event: &nfa_debug_v1alpha.DebugEvent_Stats{Stats: stats}})
+}
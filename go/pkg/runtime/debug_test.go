@@ -0,0 +1,83 @@
+package runtime
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"testing"
+
+	nfa_debug_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/debug/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestDebugStreamLogs(t *testing.T) {
+	logs := NewLogBuffer(nil, 3)
+	logger := slog.New(logs).With("component", "matcher")
+	logger.Debug("not kept without a debug stream")
+	for _, msg := range []string{"one", "two", "three", "four"} {
+		logger.Info(msg, "n", len(msg))
+	}
+
+	listener := bufconn.Listen(1 << 16)
+	server := grpc.NewServer()
+	nfa_debug_v1alpha.RegisterDebugServer(server, NewDebugServer(nil, logs, TokenAuthorizer("secret")))
+	go server.Serve(listener)
+	defer server.Stop()
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	client := nfa_debug_v1alpha.NewDebugClient(conn)
+
+	for token, want := range map[string]codes.Code{"": codes.Unauthenticated, "guess": codes.PermissionDenied} {
+		ctx := context.Background()
+		if token != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+		}
+		stream, err := client.StreamLogs(ctx, &nfa_debug_v1alpha.StreamLogsRequest{Backlog: 10})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if status.Code(err) != want {
+			t.Errorf("token %q: error = %v, want %v", token, err, want)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret"))
+	defer cancel()
+	stream, err := client.StreamLogs(ctx, &nfa_debug_v1alpha.StreamLogsRequest{Backlog: 10, Level: "debug"})
+	if err != nil {
+		t.Fatalf("StreamLogs() error = %v", err)
+	}
+	recv := func() *nfa_debug_v1alpha.LogRecord {
+		t.Helper()
+		event, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		return event.GetLog()
+	}
+	// The buffer keeps the last three records
+	for _, want := range []string{"two", "three", "four"} {
+		if got := recv(); got.GetMessage() != want || got.GetComponent() != "matcher" || got.GetLevel() != "info" {
+			t.Errorf("backlog record = %v, want info %q of matcher", got, want)
+		}
+	}
+	// The stream, subscribed before its backlog was sent, asks for debug
+	// records, so they are kept while it lasts
+	if !logs.Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatalf("debug records not kept during a debug stream")
+	}
+	logger.Debug("now kept", "key", "value")
+	if got := recv(); got.GetMessage() != "now kept" || got.GetAttributes()["key"] != "value" {
+		t.Errorf("new record = %v, want debug record with key=value", got)
+	}
+}
@@ -0,0 +1,43 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/endpoint"
+	"google.golang.org/grpc"
+)
+
+// Endpoints looks up the endpoint declared by the contract of the provider
+// serviceID, e.g. with broker.Embedded.Endpoint
+type Endpoints func(ctx context.Context, serviceID string) (contract.Endpoint, error)
+
+// ResolvingDialer returns a Dialer for WithProviderDialer that looks up the
+// endpoint of a provider when it is first invoked, resolves it to an address
+// with r and dials that address with opts. Requests on the connection are
+// encoded with the codec the endpoint declares, see Codec. Providers with
+// HTTP endpoints cannot be dialed with gRPC and fail, as do those declaring
+// a codec that is not registered.
+func ResolvingDialer(endpoints Endpoints, r endpoint.Resolver, opts ...grpc.DialOption) Dialer {
+	return func(ctx context.Context, serviceID string) (grpc.ClientConnInterface, error) {
+		e, err := endpoints(ctx, serviceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up endpoint: %w", err)
+		}
+		if e.URL != "" {
+			return nil, fmt.Errorf("endpoint %s is not a gRPC endpoint", e.URL)
+		}
+		if e.Codec != "" {
+			if LookupCodec(e.Codec) == nil {
+				return nil, fmt.Errorf("endpoint codec %s is not registered", e.Codec)
+			}
+			opts = append(opts[:len(opts):len(opts)], grpc.WithDefaultCallOptions(grpc.CallContentSubtype(e.Codec)))
+		}
+		addr, err := r.Resolve(ctx, e)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve endpoint: %w", err)
+		}
+		return grpc.DialContext(ctx, addr, opts...)
+	}
+}
@@ -0,0 +1,366 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/ca"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	nfa_ca_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/ca/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Files of an enrolled runtime in the directory of WithEnrollment
+const (
+	enrolledCertFile = "cert.pem"
+	enrolledKeyFile  = "key.pem"
+	enrolledCAFile   = "ca.pem"
+)
+
+// enrollRetry is how long the runtime waits after a failed renewal
+const enrollRetry = time.Minute
+
+// enrollment obtains and renews the client certificate of WithEnrollment
+type enrollment struct {
+	dir, token, caHash string
+
+	// set by tlsConfig
+	target string
+	base   *tls.Config
+	log    *slog.Logger
+	clock  Clock
+
+	renewing sync.Once
+
+	mu   sync.Mutex
+	cert *tls.Certificate
+	ca   *x509.Certificate
+}
+
+// WithEnrollment dials the broker over TLS with a client certificate issued
+// by the broker's built-in CA (package ca), kept in dir with its key and the
+// CA certificate. A runtime without a certificate in dir enrolls with the
+// one-time bootstrap token, verifying the broker against the CA whose hash
+// is caHash (ca.Authority.Hash); once enrolled the token is not needed. The
+// certificate is renewed with the broker when two thirds of its lifetime
+// have passed. It combines with WithTLS, whose configuration is used as the
+// base, and replaces WithMTLS.
+func WithEnrollment(dir, token, caHash string) Option {
+	return func(o *options) {
+		o.enrollment = &enrollment{dir: dir, token: token, caHash: caHash}
+	}
+}
+
+// tlsConfig loads the enrolled certificate, if any, and returns the TLS
+// configuration of the broker connection, which enrolls or renews during
+// the handshake when needed. Renewal also runs in the background until ctx
+// ends.
+func (e *enrollment) tlsConfig(ctx context.Context, target string, o *options) (*tls.Config, error) {
+	e.target, e.base, e.log, e.clock = target, o.tls, o.log(logging.Security), o.clock
+	if err := e.load(); err != nil {
+		return nil, err
+	}
+	if e.cert == nil && e.token == "" {
+		return nil, fmt.Errorf("runtime is not enrolled in %s: a bootstrap token is required", e.dir)
+	}
+	if e.cert == nil && e.caHash == "" && (e.base == nil || e.base.RootCAs == nil) {
+		e.log.Warn("Enrolling without a pinned CA hash, the broker is verified against the system roots")
+	}
+	config := e.baseConfig()
+	config.GetClientCertificate = func(cri *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return e.clientCertificate(cri.Context())
+	}
+	config.VerifyConnection = func(cs tls.ConnectionState) error {
+		e.mu.Lock()
+		caCert := e.ca
+		e.mu.Unlock()
+		return verifyBroker(cs, caCert, e.caHash, e.base)
+	}
+	e.renewing.Do(func() { go e.renew(ctx) })
+	return config, nil
+}
+
+// baseConfig returns a copy of the base configuration verifying the broker
+// in VerifyConnection, since the CA may only be known by its hash
+func (e *enrollment) baseConfig() *tls.Config {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if e.base != nil {
+		config = e.base.Clone()
+	}
+	config.InsecureSkipVerify = true
+	return config
+}
+
+// verifyBroker verifies the broker's certificate chain against caCert, the
+// CA of an enrolled runtime, or else the chain certificate with the pinned
+// hash, or else the roots of the base configuration
+func verifyBroker(cs tls.ConnectionState, caCert *x509.Certificate, caHash string, base *tls.Config) error {
+	if len(cs.PeerCertificates) == 0 {
+		return fmt.Errorf("broker presented no certificate")
+	}
+	intermediates := x509.NewCertPool()
+	var roots *x509.CertPool
+	if base != nil {
+		roots = base.RootCAs
+	}
+	if caCert != nil {
+		roots = x509.NewCertPool()
+		roots.AddCert(caCert)
+	} else if caHash != "" {
+		roots = x509.NewCertPool()
+		pinned := false
+		for _, cert := range cs.PeerCertificates {
+			if cert.IsCA && ca.Hash(cert) == caHash {
+				roots.AddCert(cert)
+				pinned = true
+			}
+		}
+		if !pinned {
+			return fmt.Errorf("broker CA does not match the pinned hash %s", caHash)
+		}
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       cs.ServerName,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+// load reads an enrolled certificate from the directory, if there is one
+func (e *enrollment) load() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	cert, err := tls.LoadX509KeyPair(filepath.Join(e.dir, enrolledCertFile), filepath.Join(e.dir, enrolledKeyFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load enrolled certificate: %w", err)
+	}
+	data, err := os.ReadFile(filepath.Join(e.dir, enrolledCAFile))
+	if err != nil {
+		return fmt.Errorf("failed to read broker CA: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return fmt.Errorf("no certificate found in %s", filepath.Join(e.dir, enrolledCAFile))
+	}
+	caCert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse broker CA: %w", err)
+	}
+	if e.caHash != "" && ca.Hash(caCert) != e.caHash {
+		return fmt.Errorf("broker CA in %s does not match the pinned hash %s", e.dir, e.caHash)
+	}
+	e.cert, e.ca = &cert, caCert
+	return nil
+}
+
+// clientCertificate returns the certificate to present, enrolling or
+// renewing it first when needed. A certificate that failed to renew is
+// still presented until it expires.
+func (e *enrollment) clientCertificate(ctx context.Context) (*tls.Certificate, error) {
+	if err := e.refresh(ctx); err != nil {
+		e.mu.Lock()
+		cert := e.cert
+		e.mu.Unlock()
+		if cert == nil || !e.clock.Now().Before(cert.Leaf.NotAfter) {
+			return nil, err
+		}
+		e.log.Warn("Certificate renewal failed, presenting the current certificate", "error", err)
+		return cert, nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.cert, nil
+}
+
+// refresh enrolls when there is no valid certificate, and renews the
+// certificate when two thirds of its lifetime have passed
+func (e *enrollment) refresh(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := e.clock.Now()
+	switch {
+	case e.cert == nil || !now.Before(e.cert.Leaf.NotAfter):
+		if e.token == "" {
+			return fmt.Errorf("no valid enrolled certificate: a bootstrap token is required")
+		}
+		return e.issue(ctx, e.token)
+	case !now.Before(renewalTime(e.cert.Leaf)):
+		return e.issue(ctx, "")
+	}
+	return nil
+}
+
+// renew refreshes the certificate in the background until ctx ends. It
+// waits at least enrollRetry between attempts, so a device clock far ahead
+// of the broker's does not renew in a loop.
+func (e *enrollment) renew(ctx context.Context) {
+	for {
+		wait := enrollRetry
+		e.mu.Lock()
+		if e.cert != nil {
+			wait = max(renewalTime(e.cert.Leaf).Sub(e.clock.Now()), enrollRetry)
+		}
+		e.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.clock.After(wait):
+		}
+		e.mu.Lock()
+		enrolled := e.cert != nil
+		e.mu.Unlock()
+		if !enrolled {
+			continue // enrollment happens when the runtime connects
+		}
+		if err := e.refresh(ctx); err != nil {
+			e.log.Warn("Certificate renewal failed", "error", err, "retry_in", enrollRetry)
+		}
+	}
+}
+
+// issue asks the broker's CA for a certificate for a new key, with the
+// bootstrap token or, when token is empty, the current certificate, and
+// stores it; mu must be held
+func (e *enrollment) issue(ctx context.Context, token string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate key: %w", err)
+	}
+	hostname, _ := os.Hostname()
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: hostname},
+	}, key)
+	if err != nil {
+		return fmt.Errorf("failed to create certificate request: %w", err)
+	}
+
+	config := e.baseConfig()
+	caCert := e.ca
+	config.VerifyConnection = func(cs tls.ConnectionState) error {
+		return verifyBroker(cs, caCert, e.caHash, e.base)
+	}
+	if token == "" {
+		config.Certificates = []tls.Certificate{*e.cert}
+	}
+	conn, err := grpc.Dial(e.target, grpc.WithTransportCredentials(credentials.NewTLS(config)))
+	if err != nil {
+		return fmt.Errorf("failed to connect to broker CA: %w", err)
+	}
+	defer conn.Close()
+	resp, err := nfa_ca_v1alpha.NewCertificateAuthorityClient(conn).IssueCertificate(ctx, &nfa_ca_v1alpha.IssueCertificateRequest{
+		Csr:            csr,
+		BootstrapToken: token,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to obtain certificate: %w", err)
+	}
+
+	leaf, err := x509.ParseCertificate(resp.Certificate)
+	if err != nil {
+		return fmt.Errorf("failed to parse issued certificate: %w", err)
+	}
+	issuer, err := x509.ParseCertificate(resp.CaCertificate)
+	if err != nil {
+		return fmt.Errorf("failed to parse broker CA: %w", err)
+	}
+	if e.caHash != "" && ca.Hash(issuer) != e.caHash {
+		return fmt.Errorf("broker CA does not match the pinned hash %s", e.caHash)
+	}
+	if e.ca != nil && !bytes.Equal(issuer.Raw, e.ca.Raw) {
+		return fmt.Errorf("broker CA changed: enroll again with a new bootstrap token")
+	}
+	if err := leaf.CheckSignatureFrom(issuer); err != nil {
+		return fmt.Errorf("issued certificate is not signed by the broker CA: %w", err)
+	}
+	if err := e.store(key, leaf, issuer); err != nil {
+		return err
+	}
+	e.cert = &tls.Certificate{Certificate: [][]byte{leaf.Raw}, PrivateKey: key, Leaf: leaf}
+	e.ca = issuer
+	if token != "" {
+		e.log.Info("Enrolled with the broker CA", "tenant", resp.Tenant, "device", resp.Device, "not_after", leaf.NotAfter)
+	} else {
+		e.log.Info("Renewed client certificate", "not_after", leaf.NotAfter)
+	}
+	return nil
+}
+
+// store writes the key, certificate and CA to the directory, each file
+// replaced atomically
+func (e *enrollment) store(key *ecdsa.PrivateKey, cert, caCert *x509.Certificate) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to marshal key: %w", err)
+	}
+	if err := os.MkdirAll(e.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create enrollment directory: %w", err)
+	}
+	files := []struct {
+		name  string
+		block *pem.Block
+		mode  os.FileMode
+	}{
+		// the key first, so a certificate on disk never lacks its key
+		{enrolledKeyFile, &pem.Block{Type: "PRIVATE KEY", Bytes: der}, 0o600},
+		{enrolledCertFile, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}, 0o644},
+		{enrolledCAFile, &pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw}, 0o644},
+	}
+	for _, f := range files {
+		if err := writeFileAtomic(filepath.Join(e.dir, f.name), pem.EncodeToMemory(f.block), f.mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFileAtomic(path string, data []byte, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// renewalTime returns when a certificate should be renewed: once two thirds
+// of its lifetime have passed
+func renewalTime(cert *x509.Certificate) time.Time {
+	return cert.NotBefore.Add(cert.NotAfter.Sub(cert.NotBefore) * 2 / 3)
+}
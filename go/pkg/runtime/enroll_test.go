@@ -0,0 +1,131 @@
+package runtime
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/ca"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// serveWithCA serves an embedded broker over TLS with the authority's
+// certificates, requiring enrolled devices as a broker with the built-in CA
+// does
+func serveWithCA(t *testing.T, a *ca.Authority) string {
+	t.Helper()
+	config, err := a.ServerTLSConfig("127.0.0.1")
+	if err != nil {
+		t.Fatalf("ServerTLSConfig() error = %v", err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := broker.NewEmbedded()
+	server := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(config)),
+		grpc.UnaryInterceptor(a.UnaryInterceptor()),
+		grpc.StreamInterceptor(a.StreamInterceptor()),
+	)
+	a.Register(server)
+	nfa_broker_v1alpha.RegisterIntentBrokerServer(server, b)
+	go server.Serve(lis)
+	t.Cleanup(func() {
+		server.Stop()
+		b.Close()
+	})
+	return lis.Addr().String()
+}
+
+func TestEnrollment(t *testing.T) {
+	a, err := ca.New("test CA")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	addr := serveWithCA(t, a)
+	dir := filepath.Join(t.TempDir(), "enrollment")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	register := func(opts ...Option) error {
+		r := NewIntentRuntime(addr, opts...)
+		defer r.Close()
+		if err := r.Connect(); err != nil {
+			return err
+		}
+		_, err := r.RegisterFromBytes(ctx, []byte(leaseContract))
+		return err
+	}
+	readCert := func() *tls.Certificate {
+		t.Helper()
+		cert, err := tls.LoadX509KeyPair(filepath.Join(dir, enrolledCertFile), filepath.Join(dir, enrolledKeyFile))
+		if err != nil {
+			t.Fatalf("no enrolled certificate: %v", err)
+		}
+		return &cert
+	}
+
+	if err := register(WithEnrollment(dir, "", a.Hash())); err == nil {
+		t.Fatalf("runtime without a certificate or token connected")
+	}
+
+	token, err := a.CreateToken("home", "kitchen-hub", time.Hour)
+	if err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+	if err := register(WithEnrollment(dir, token, "sha256:0000")); err == nil {
+		t.Fatalf("runtime enrolled with a broker whose CA does not match the pin")
+	}
+	if err := register(WithEnrollment(dir, token, a.Hash())); err != nil {
+		t.Fatalf("register after enrolling: %v", err)
+	}
+	enrolled := readCert()
+	if id, _ := ca.IdentityOf(enrolled.Leaf); id != (ca.Identity{Tenant: "home", Device: "kitchen-hub"}) {
+		t.Errorf("enrolled as %v, want home/kitchen-hub", id)
+	}
+	if info, err := os.Stat(filepath.Join(dir, enrolledKeyFile)); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("key file mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+
+	// The token is used up, and no longer needed
+	if err := register(WithEnrollment(t.TempDir(), token, a.Hash())); err == nil {
+		t.Errorf("bootstrap token enrolled twice")
+	}
+	if err := register(WithEnrollment(dir, "", a.Hash())); err != nil {
+		t.Fatalf("register with the enrolled certificate: %v", err)
+	}
+
+	// Past two thirds of its lifetime the certificate is renewed with the
+	// current one
+	clock := skewedClock{skew: 20 * time.Hour}
+	if err := register(WithEnrollment(dir, "", ""), WithClock(clock)); err != nil {
+		t.Fatalf("register while renewing: %v", err)
+	}
+	renewed := readCert()
+	if renewed.Leaf.SerialNumber.Cmp(enrolled.Leaf.SerialNumber) == 0 {
+		t.Errorf("certificate was not renewed")
+	}
+	if id, _ := ca.IdentityOf(renewed.Leaf); id.Device != "kitchen-hub" {
+		t.Errorf("renewed certificate identifies %v", id)
+	}
+
+	// A revoked device is refused renewals and calls
+	a.Revoke(ca.Identity{Tenant: "home", Device: "kitchen-hub"})
+	err = register(WithEnrollment(dir, "", a.Hash()), WithClock(clock))
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("register after revocation: error = %v, want PermissionDenied", err)
+	}
+	if readCert().Leaf.SerialNumber.Cmp(renewed.Leaf.SerialNumber) != 0 {
+		t.Errorf("certificate of a revoked device was renewed")
+	}
+}
@@ -0,0 +1,250 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/errorreport"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultErrorReportInterval is how often an intent server reports the
+	// errors of its handlers unless WithErrorReports sets an interval
+	defaultErrorReportInterval = time.Minute
+	// maxErrorKinds bounds the error kinds summarized per report; errors of
+	// further kinds are only counted
+	maxErrorKinds = 32
+	// grpcServicePrefix starts the methods of gRPC's own services, such as
+	// health checks and reflection, whose errors are not reported
+	grpcServicePrefix = "/grpc."
+)
+
+// WithErrorReports makes an intent server summarize the errors its handlers
+// return and report them to the broker through runtime every interval, one
+// minute when 0, so operators see errors fleet-wide without collecting logs.
+// Errors are deduplicated by action and status code into a count and the
+// message of the first, truncated; a report summarizes at most 32 kinds and
+// counts errors of further kinds as dropped. Cancelled requests are not
+// reported, nor those of gRPC's health and reflection services. The server
+// needs a service ID, see WithServiceID, and reports from Serve until Stop,
+// which sends the last report. Reporting stops when the broker does not
+// collect error reports.
+func WithErrorReports(runtime *IntentRuntime, interval time.Duration) Option {
+	return func(o *options) {
+		if interval <= 0 {
+			interval = defaultErrorReportInterval
+		}
+		o.errorReports = runtime
+		o.errorReportInterval = interval
+	}
+}
+
+// ReportErrors reports summaries of the errors the handlers of serviceID
+// returned, and how many errors of further kinds were dropped, to the
+// broker. Intent servers with WithErrorReports report on their own; call it
+// for handlers served otherwise. It fails with ErrUnsupported when the
+// broker does not collect error reports.
+func (r *IntentRuntime) ReportErrors(ctx context.Context, serviceID string, summaries []errorreport.Summary, dropped uint64) error {
+	if err := r.ready(); err != nil {
+		return err
+	}
+	if err := r.require(broker.FeatureErrorReports); err != nil {
+		return err
+	}
+	ctx, cancel := r.bind(ctx)
+	defer cancel()
+	return r.client.ReportErrors(ctx, serviceID, summaries, dropped)
+}
+
+// errorKind identifies the errors deduplicated into one summary
+type errorKind struct {
+	action, code string
+}
+
+// errorReporter summarizes the errors of a server's handlers and reports
+// them on an interval
+type errorReporter struct {
+	runtime   *IntentRuntime
+	serviceID string
+	interval  time.Duration
+	clock     Clock
+
+	mu      sync.Mutex
+	kinds   map[errorKind]*errorreport.Summary
+	dropped uint64
+
+	start sync.Once
+	ctx   context.Context
+	stop  context.CancelFunc
+	done  chan struct{}
+}
+
+func newErrorReporter(o *options) *errorReporter {
+	ctx, stop := context.WithCancel(context.Background())
+	return &errorReporter{
+		runtime:   o.errorReports,
+		serviceID: o.serviceID,
+		interval:  o.errorReportInterval,
+		clock:     o.clock,
+		kinds:     make(map[errorKind]*errorreport.Summary),
+		ctx:       ctx,
+		stop:      stop,
+		done:      make(chan struct{}),
+	}
+}
+
+// observe counts an error a handler returned for action
+func (e *errorReporter) observe(action string, err error) {
+	if err == nil {
+		return
+	}
+	st := status.Convert(err)
+	if st.Code() == codes.Canceled {
+		return
+	}
+	now := e.clock.Now()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	kind := errorKind{action: action, code: st.Code().String()}
+	s, ok := e.kinds[kind]
+	if !ok {
+		if len(e.kinds) >= maxErrorKinds {
+			e.dropped++
+			return
+		}
+		s = &errorreport.Summary{
+			Action:    action,
+			Code:      st.Code().String(),
+			Sample:    errorreport.Truncate(st.Message()),
+			FirstSeen: now,
+		}
+		e.kinds[kind] = s
+	}
+	s.Count++
+	s.LastSeen = now
+}
+
+// take returns the summaries since the last report and starts new ones
+func (e *errorReporter) take() ([]errorreport.Summary, uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	summaries := make([]errorreport.Summary, 0, len(e.kinds))
+	for _, s := range e.kinds {
+		summaries = append(summaries, *s)
+	}
+	dropped := e.dropped
+	e.kinds = make(map[errorKind]*errorreport.Summary)
+	e.dropped = 0
+	return summaries, dropped
+}
+
+// restore merges summaries that could not be reported back into the next
+// report, within its limit of kinds
+func (e *errorReporter) restore(summaries []errorreport.Summary, dropped uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.dropped += dropped
+	for _, s := range summaries {
+		kind := errorKind{action: s.Action, code: s.Code}
+		current, ok := e.kinds[kind]
+		if !ok {
+			if len(e.kinds) >= maxErrorKinds {
+				e.dropped += s.Count
+				continue
+			}
+			restored := s
+			e.kinds[kind] = &restored
+			continue
+		}
+		current.Count += s.Count
+		current.Sample = s.Sample
+		current.FirstSeen = s.FirstSeen
+	}
+}
+
+// report sends the summaries since the last report, if any, keeping them
+// for the next when the broker cannot be reached
+func (e *errorReporter) report() error {
+	summaries, dropped := e.take()
+	if len(summaries) == 0 && dropped == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), outcomeReportTimeout)
+	defer cancel()
+	err := e.runtime.ReportErrors(ctx, e.serviceID, summaries, dropped)
+	if err != nil && !errors.Is(err, ErrUnsupported) {
+		e.restore(summaries, dropped)
+	}
+	return err
+}
+
+// startReporting reports the errors of a server with WithErrorReports,
+// once, until Stop
+func (s *IntentServer) startReporting() {
+	if s.errors == nil {
+		return
+	}
+	s.errors.start.Do(func() { go s.reportLoop() })
+}
+
+func (s *IntentServer) reportLoop() {
+	defer close(s.errors.done)
+	log := s.opts.log(logging.Server)
+	for {
+		select {
+		case <-s.errors.ctx.Done():
+			return
+		case <-s.opts.clock.After(s.errors.interval):
+		}
+		if err := s.errors.report(); errors.Is(err, ErrUnsupported) {
+			log.Info("broker does not collect error reports, not reporting errors")
+			return
+		} else if err != nil {
+			log.Debug("error report failed", "error", err)
+		}
+	}
+}
+
+// stopReporting stops the report loop and sends the errors since the last
+// report
+func (s *IntentServer) stopReporting() {
+	if s.errors == nil {
+		return
+	}
+	s.errors.stop()
+	// A loop that never started has nothing to wait for
+	s.errors.start.Do(func() { close(s.errors.done) })
+	<-s.errors.done
+	if err := s.errors.report(); err != nil && !errors.Is(err, ErrUnsupported) {
+		s.opts.log(logging.Server).Debug("error report failed", "error", err)
+	}
+}
+
+func (s *IntentServer) unaryErrors(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if strings.HasPrefix(info.FullMethod, grpcServicePrefix) {
+		return resp, err
+	}
+	action := intentAction(req)
+	if action == "" {
+		action = info.FullMethod
+	}
+	s.errors.observe(action, err)
+	return resp, err
+}
+
+func (s *IntentServer) streamErrors(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	err := handler(srv, stream)
+	if !strings.HasPrefix(info.FullMethod, grpcServicePrefix) {
+		s.errors.observe(info.FullMethod, err)
+	}
+	return err
+}
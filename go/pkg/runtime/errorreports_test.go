@@ -0,0 +1,86 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/errorreport"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServerReportsErrors(t *testing.T) {
+	b := broker.NewEmbedded()
+	defer b.Close()
+	ctx := context.Background()
+	provider := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...))
+	defer provider.Close()
+	if err := provider.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	serviceID, err := provider.RegisterFromBytes(ctx, []byte(leaseContract))
+	if err != nil {
+		t.Fatalf("RegisterFromBytes() error = %v", err)
+	}
+
+	server := NewIntentServer(0, WithServiceID(serviceID), WithErrorReports(provider, 0))
+	call := func(method string, req interface{}, err error) {
+		info := &grpc.UnaryServerInfo{FullMethod: method}
+		server.unaryErrors(ctx, req, info, func(context.Context, interface{}) (interface{}, error) {
+			return nil, err
+		})
+	}
+	req := &nfa_intent_v1alpha.IntentRequest{Action: "translate_text"}
+	for i := 0; i < 3; i++ {
+		call("/test.Provider/Handle", req, status.Errorf(codes.Internal, "database down %d", i))
+	}
+	call("/test.Provider/Handle", req, nil)
+	call("/test.Provider/Handle", req, status.Error(codes.Canceled, "consumer left"))
+	call("/grpc.health.v1.Health/Check", &nfa_intent_v1alpha.IntentContext{}, status.Error(codes.NotFound, "unknown service"))
+	// Stop sends the errors since the last report
+	server.Stop()
+
+	report := b.ErrorReports().Report(errorreport.Query{ServiceID: serviceID})
+	if len(report.Errors) != 1 {
+		t.Fatalf("Report() = %v, want one kind of error", report.Errors)
+	}
+	e := report.Errors[0]
+	if e.Action != "translate_text" || e.Code != "Internal" || e.Count != 3 || e.SampleMessage != "database down 0" {
+		t.Errorf("Report() = %v, want 3 Internal errors of translate_text with the first message", e)
+	}
+
+	b.Remove(serviceID)
+	if report := b.ErrorReports().Report(errorreport.Query{}); len(report.Errors) != 0 {
+		t.Errorf("Report() after Remove = %v, want the service's errors forgotten", report.Errors)
+	}
+}
+
+func TestErrorReporterLimitsKinds(t *testing.T) {
+	o := newOptions([]Option{WithErrorReports(nil, 0)})
+	e := newErrorReporter(&o)
+	for i := 0; i < maxErrorKinds+8; i++ {
+		e.observe(fmt.Sprintf("action_%d", i), status.Error(codes.Internal, "failed"))
+	}
+	e.observe("action_0", status.Error(codes.Internal, "failed again"))
+	summaries, dropped := e.take()
+	if len(summaries) != maxErrorKinds || dropped != 8 {
+		t.Fatalf("take() = %d summaries, %d dropped, want %d and 8", len(summaries), dropped, maxErrorKinds)
+	}
+
+	// A report that failed is sent with the next
+	e.observe("action_0", status.Error(codes.Internal, "failed once more"))
+	e.restore(summaries, dropped)
+	summaries, dropped = e.take()
+	for _, s := range summaries {
+		if s.Action == "action_0" && (s.Count != 3 || s.Sample != "failed") {
+			t.Errorf("restored summary = %+v, want 3 errors with the first message", s)
+		}
+	}
+	if len(summaries) != maxErrorKinds || dropped != 8 {
+		t.Errorf("take() after restore = %d summaries, %d dropped, want %d and 8", len(summaries), dropped, maxErrorKinds)
+	}
+}
@@ -0,0 +1,49 @@
+package runtime
+
+import (
+	"errors"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+)
+
+// Sentinel errors wrapped by the runtime's errors; test with errors.Is
+var (
+	// ErrNotConnected means the call needs a broker connection and Connect
+	// has not been called, or the runtime has been closed
+	ErrNotConnected = errors.New("not connected to broker")
+	// ErrDraining means the broker asked the runtime to stop taking new work
+	ErrDraining = errors.New("runtime is draining")
+	// ErrContractInvalid means a contract failed to parse or validate
+	ErrContractInvalid = contract.ErrInvalid
+	// ErrBrokerUnavailable means the broker could not be reached; the call
+	// can be retried
+	ErrBrokerUnavailable = broker.ErrUnavailable
+	// ErrServiceIDConflict means another live instance holds the stable
+	// service ID; see WithTakeOver
+	ErrServiceIDConflict = broker.ErrServiceIDConflict
+	// ErrUnauthenticated means the broker requires credentials the runtime
+	// did not present, or rejected them; see WithTokenAuth and WithMTLS
+	ErrUnauthenticated = broker.ErrUnauthenticated
+	// ErrNotRegistered means the broker does not know the service, e.g.
+	// because it restarted; see IntentRuntime.StartSupervisor
+	ErrNotRegistered = broker.ErrNotRegistered
+	// ErrUnsupported means the broker does not serve a feature the call
+	// needs, typically because it is older than the runtime; see
+	// IntentRuntime.BrokerProtocol
+	ErrUnsupported = broker.ErrUnsupported
+	// ErrIncompatible means the runtime and the broker share no protocol
+	// version; one of them must be upgraded
+	ErrIncompatible = broker.ErrIncompatible
+	// ErrBreakingChange means a contract update would break consumers of the
+	// registered version; see IntentRuntime.UpdateContract
+	ErrBreakingChange = contract.ErrBreakingChange
+	// ErrNoDialer means a provider outside this process was requested
+	// without WithProviderDialer
+	ErrNoDialer = errors.New("no provider dialer configured")
+	// ErrNoProvider means the broker matched no provider to the intent
+	ErrNoProvider = errors.New("no provider serves the intent")
+	// ErrProviderUnavailable means no provider of the intent could be
+	// reached; the call can be retried
+	ErrProviderUnavailable = errors.New("no provider of the intent is reachable")
+)
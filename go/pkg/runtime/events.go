@@ -0,0 +1,29 @@
+package runtime
+
+import (
+	"context"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pubsub"
+)
+
+// Publish emits an event on a broker-managed topic such as
+// "nfa.home.door_opened" and returns its sequence number
+func (r *IntentRuntime) Publish(ctx context.Context, topic string, payload []byte, attributes map[string]string) (uint64, error) {
+	if err := r.ready(); err != nil {
+		return 0, err
+	}
+	if err := r.require(broker.FeatureEvents); err != nil {
+		return 0, err
+	}
+	ctx, cancel := r.bind(ctx)
+	defer cancel()
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	if serviceID := r.primaryServiceID(); serviceID != "" {
+		attributes["nfa.service_id"] = serviceID
+	}
+	seq, err := pubsub.NewClient(r.conn).Publish(ctx, topic, payload, attributes)
+	return seq, unsupported(broker.FeatureEvents, err)
+}
@@ -0,0 +1,190 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+)
+
+// Limits of exec endpoints that do not set their own
+const (
+	DefaultExecTimeout   = 10 * time.Second
+	DefaultExecMaxOutput = 64 << 10
+)
+
+// execStderrLimit caps the standard error of a command kept for its error
+const execStderrLimit = 4 << 10
+
+// ExecHandler returns a handler for OnBroadcast running the command of the
+// exec endpoint of c for each intent of one of its actions, e.g. a device
+// script. The command is run without a shell, with only the variables of
+// its Env and NFA_ACTION, the action of the intent, in its environment. It
+// is killed when it outlives its timeout or writes more than its output
+// cap. Its output is the result of the intent; a command exiting with an
+// error fails it, with the start of its standard error. The parameters of
+// an intent are admitted against the contract before its arguments are
+// expanded, see contract.IntentContract.Admit, so a command never runs
+// with a value outside the declared constraints. Shadow copies and
+// synthetic probes are answered with no output, without running the
+// command, since its side effects would be real.
+func ExecHandler(c *contract.IntentContract) (func(*nfa_control_v1alpha.Invoke) ([]byte, error), error) {
+	e := c.Spec.Implementation.Endpoint
+	if !strings.EqualFold(e.Type, "exec") || e.Exec == nil {
+		return nil, fmt.Errorf("contract %s has no exec endpoint", c.Metadata.Name)
+	}
+	command := *e.Exec
+	if !filepath.IsAbs(command.Command) {
+		return nil, fmt.Errorf("exec command %q is not an absolute path", command.Command)
+	}
+	timeout := DefaultExecTimeout
+	if command.Timeout != "" {
+		d, err := time.ParseDuration(command.Timeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid exec timeout %q", command.Timeout)
+		}
+		timeout = d
+	}
+	maxOutput := DefaultExecMaxOutput
+	if command.MaxOutputBytes > 0 {
+		maxOutput = command.MaxOutputBytes
+	}
+	switch command.Stdin {
+	case "", contract.ExecStdinPayload, contract.ExecStdinParameters:
+	default:
+		return nil, fmt.Errorf("unknown exec stdin %q", command.Stdin)
+	}
+	actions := make(map[string]bool)
+	for _, p := range c.Spec.IntentPatterns {
+		actions[p.Pattern.Action] = true
+		for _, alias := range p.Aliases {
+			actions[alias] = true
+		}
+	}
+
+	return func(invoke *nfa_control_v1alpha.Invoke) ([]byte, error) {
+		if !actions[invoke.Action] {
+			return nil, fmt.Errorf("contract %s does not serve action %s", c.Metadata.Name, invoke.Action)
+		}
+		if invoke.Shadow || invoke.Synthetic {
+			return nil, nil
+		}
+		if err := c.Admit(invoke.Action, execValues(c, invoke.Action, invoke.Parameters)); err != nil {
+			return nil, err
+		}
+		args, err := command.ExpandArgs(invoke.Parameters)
+		if err != nil {
+			return nil, err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, command.Command, args...)
+		cmd.Env = execEnv(command.Env, invoke.Action)
+		// Children holding the output open do not keep the intent waiting
+		cmd.WaitDelay = time.Second
+		switch command.Stdin {
+		case contract.ExecStdinPayload:
+			cmd.Stdin = bytes.NewReader(invoke.Payload)
+		case contract.ExecStdinParameters:
+			data, err := json.Marshal(invoke.Parameters)
+			if err != nil {
+				return nil, err
+			}
+			cmd.Stdin = bytes.NewReader(data)
+		}
+		stdout := &cappedBuffer{limit: maxOutput, overflow: cancel}
+		stderr := &cappedBuffer{limit: execStderrLimit}
+		cmd.Stdout, cmd.Stderr = stdout, stderr
+
+		err = cmd.Run()
+		switch {
+		case stdout.overflowed:
+			return nil, fmt.Errorf("%s wrote more than %d bytes", command.Command, maxOutput)
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return nil, fmt.Errorf("%s timed out after %v", command.Command, timeout)
+		case err != nil:
+			if msg := strings.TrimSpace(stderr.buf.String()); msg != "" {
+				return nil, fmt.Errorf("%s failed: %w: %s", command.Command, err, msg)
+			}
+			return nil, fmt.Errorf("%s failed: %w", command.Command, err)
+		}
+		return stdout.buf.Bytes(), nil
+	}, nil
+}
+
+// execValues converts the parameters of an intent, which are strings, to the
+// types the patterns of c declaring action constrain them to, so they can be
+// admitted. Values that do not parse as their type are kept as strings, for
+// Admit to reject.
+func execValues(c *contract.IntentContract, action string, params map[string]string) map[string]*nfa_intent_v1alpha.Value {
+	types := make(map[string]string)
+	for _, p := range c.Spec.IntentPatterns {
+		if p.Constraints == nil || (p.Pattern.Action != action && !slices.Contains(p.Aliases, action)) {
+			continue
+		}
+		for name, pc := range p.Constraints.ParameterConstraints {
+			types[name] = pc.Type
+		}
+	}
+	values := make(map[string]*nfa_intent_v1alpha.Value, len(params))
+	for name, value := range params {
+		values[name] = &nfa_intent_v1alpha.Value{Value: &nfa_intent_v1alpha.Value_StringValue{StringValue: value}}
+		switch types[name] {
+		case "number", "integer":
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				values[name] = &nfa_intent_v1alpha.Value{Value: &nfa_intent_v1alpha.Value_NumberValue{NumberValue: f}}
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(value); err == nil {
+				values[name] = &nfa_intent_v1alpha.Value{Value: &nfa_intent_v1alpha.Value_BoolValue{BoolValue: b}}
+			}
+		}
+	}
+	return values
+}
+
+// execEnv returns the environment of a command: the allowed variables of
+// the runtime's environment and the action it runs for
+func execEnv(allowed []string, action string) []string {
+	env := []string{"NFA_ACTION=" + action}
+	for _, name := range allowed {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
+}
+
+// cappedBuffer keeps the first limit bytes written to it and discards the
+// rest, calling overflow once when it first does. It does not embed its
+// buffer, whose ReadFrom would bypass the limit.
+type cappedBuffer struct {
+	buf        bytes.Buffer
+	limit      int
+	overflow   func()
+	overflowed bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); len(p) > room {
+		b.buf.Write(p[:max(room, 0)])
+		if !b.overflowed && b.overflow != nil {
+			b.overflow()
+		}
+		b.overflowed = true
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
@@ -0,0 +1,154 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+)
+
+// TestExecHelper is the command of the exec tests, run in a subprocess
+func TestExecHelper(t *testing.T) {
+	if os.Getenv("NFA_EXEC_HELPER") == "" {
+		return
+	}
+	args := os.Args[len(os.Args)-2:]
+	switch args[0] {
+	case "echo":
+		stdin, _ := io.ReadAll(os.Stdin)
+		fmt.Printf("%s %s %s secret=%q", os.Getenv("NFA_ACTION"), args[1], stdin, os.Getenv("NFA_EXEC_SECRET"))
+	case "fail":
+		fmt.Fprint(os.Stderr, "no such room")
+		os.Exit(2)
+	case "sleep":
+		time.Sleep(time.Minute)
+	case "flood":
+		fmt.Print(strings.Repeat("x", 1<<20))
+	case "touch":
+		os.WriteFile(args[1], nil, 0o600)
+	}
+	os.Exit(0)
+}
+
+func execContract(t *testing.T, command contract.ExecCommand) *contract.IntentContract {
+	t.Helper()
+	t.Setenv("NFA_EXEC_HELPER", "1")
+	t.Setenv("NFA_EXEC_SECRET", "hunter2")
+	command.Command = os.Args[0]
+	command.Args = append([]string{"-test.run=^TestExecHelper$", "--"}, command.Args...)
+	command.Env = append(command.Env, "NFA_EXEC_HELPER")
+	return &contract.IntentContract{
+		Metadata: contract.ContractMetadata{Name: "lights"},
+		Spec: contract.IntentSpec{
+			IntentPatterns: []contract.IntentPattern{{Pattern: contract.Pattern{Action: "lights_on"}}},
+			Implementation: contract.Implementation{Endpoint: contract.Endpoint{Type: "exec", Exec: &command}},
+		},
+	}
+}
+
+func TestExecHandler(t *testing.T) {
+	handler, err := ExecHandler(execContract(t, contract.ExecCommand{
+		Args:  []string{"echo", "room={room}"},
+		Stdin: contract.ExecStdinPayload,
+	}))
+	if err != nil {
+		t.Fatalf("ExecHandler() error = %v", err)
+	}
+	invoke := &nfa_control_v1alpha.Invoke{Action: "lights_on", Parameters: map[string]string{"room": "kitchen"}, Payload: []byte("dim")}
+	out, err := handler(invoke)
+	if err != nil {
+		t.Fatalf("handler() error = %v", err)
+	}
+	// NFA_EXEC_SECRET is not allowed
+	if want := `lights_on room=kitchen dim secret=""`; string(out) != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+
+	for name, invoke := range map[string]*nfa_control_v1alpha.Invoke{
+		"other action": {Action: "lights_off", Parameters: map[string]string{"room": "kitchen"}},
+		"missing":      {Action: "lights_on"},
+		"option":       {Action: "lights_on", Parameters: map[string]string{"room": "--all"}},
+	} {
+		if _, err := handler(invoke); err == nil {
+			t.Errorf("%s: handler() succeeded, want an error", name)
+		}
+	}
+	if out, err := handler(&nfa_control_v1alpha.Invoke{Action: "lights_on", Synthetic: true}); out != nil || err != nil {
+		t.Errorf("synthetic probe = %q, %v, want no output without running the command", out, err)
+	}
+}
+
+func TestExecHandlerAdmission(t *testing.T) {
+	dir := t.TempDir()
+	c := execContract(t, contract.ExecCommand{Args: []string{"touch", filepath.Join(dir, "{room}-{level}")}})
+	c.Spec.IntentPatterns[0].Aliases = []string{"lamp_on"}
+	c.Spec.IntentPatterns[0].Constraints = &contract.PatternConstraints{
+		ParameterConstraints: map[string]contract.ParameterConstraint{
+			"room":  {Type: "string", EnumValues: []string{"kitchen", "hall"}},
+			"level": {Type: "string", Pattern: "^[0-9]+$"},
+		},
+	}
+	handler, err := ExecHandler(c)
+	if err != nil {
+		t.Fatalf("ExecHandler() error = %v", err)
+	}
+	for name, params := range map[string]map[string]string{
+		"enum":    {"room": "garage", "level": "20"},
+		"pattern": {"room": "kitchen", "level": "20;reboot"},
+	} {
+		_, err := handler(&nfa_control_v1alpha.Invoke{Action: "lights_on", Parameters: params})
+		if !errors.Is(err, contract.ErrConstraintViolated) {
+			t.Errorf("%s: handler() error = %v, want a constraint violation", name, err)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("command ran for parameters outside the constraints, wrote %v", entries)
+	}
+
+	// The alias of the action is served
+	if _, err := handler(&nfa_control_v1alpha.Invoke{Action: "lamp_on", Parameters: map[string]string{"room": "hall", "level": "20"}}); err != nil {
+		t.Fatalf("handler() of an alias error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "hall-20")); err != nil {
+		t.Errorf("command did not run for the alias: %v", err)
+	}
+}
+
+func TestExecHandlerSandbox(t *testing.T) {
+	for _, tt := range []struct {
+		command contract.ExecCommand
+		want    string
+	}{
+		{contract.ExecCommand{Args: []string{"fail", ""}}, "no such room"},
+		{contract.ExecCommand{Args: []string{"sleep", ""}, Timeout: "200ms"}, "timed out after 200ms"},
+		{contract.ExecCommand{Args: []string{"flood", ""}, MaxOutputBytes: 1024}, "more than 1024 bytes"},
+	} {
+		t.Run(tt.command.Args[0], func(t *testing.T) {
+			handler, err := ExecHandler(execContract(t, tt.command))
+			if err != nil {
+				t.Fatalf("ExecHandler() error = %v", err)
+			}
+			start := time.Now()
+			_, err = handler(&nfa_control_v1alpha.Invoke{Action: "lights_on"})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("handler() error = %v, want %q", err, tt.want)
+			}
+			if elapsed := time.Since(start); elapsed > 10*time.Second {
+				t.Errorf("handler() took %v", elapsed)
+			}
+		})
+	}
+
+	c := execContract(t, contract.ExecCommand{})
+	c.Spec.Implementation.Endpoint.Exec.Command = "lights"
+	if _, err := ExecHandler(c); err == nil {
+		t.Errorf("ExecHandler() with a relative command succeeded, want an error")
+	}
+}
@@ -0,0 +1,168 @@
+// Package flags provides feature flags for the intent runtime. Flags come from
+// static configuration and can be overridden at runtime by the broker.
+package flags
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Well-known experimental behaviors gated by flags
+const (
+	// LocalResolutionCache keeps the providers an action resolved to in the
+	// runtime for a few seconds instead of asking the broker every time;
+	// traffic is split by action
+	LocalResolutionCache = "local_resolution_cache"
+)
+
+// Flag describes whether a behavior is enabled and for which share of traffic
+type Flag struct {
+	Name    string `yaml:"name"`
+	Enabled bool   `yaml:"enabled"`
+	// Percentage limits an enabled flag to a share of traffic (0-100).
+	// Nil means the flag applies to all traffic.
+	Percentage *float64 `yaml:"percentage,omitempty"`
+}
+
+// Validate checks that the flag definition is well-formed
+func (f Flag) Validate() error {
+	if f.Name == "" {
+		return fmt.Errorf("flag name is required")
+	}
+	if f.Percentage != nil && (*f.Percentage < 0 || *f.Percentage > 100) {
+		return fmt.Errorf("flag %s: percentage must be between 0 and 100", f.Name)
+	}
+	return nil
+}
+
+type flagFile struct {
+	Flags []Flag `yaml:"flags"`
+}
+
+// LoadFile reads static flag definitions from a YAML file
+func LoadFile(path string) ([]Flag, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read flags file: %w", err)
+	}
+	var file flagFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse flags file: %w", err)
+	}
+	for _, f := range file.Flags {
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return file.Flags, nil
+}
+
+// Set holds static flags and broker-pushed overrides. Overrides win over
+// static definitions with the same name.
+type Set struct {
+	mu        sync.RWMutex
+	static    map[string]Flag
+	overrides map[string]Flag
+}
+
+// NewSet creates a flag set from static definitions
+func NewSet(static []Flag) *Set {
+	s := &Set{
+		static:    make(map[string]Flag),
+		overrides: make(map[string]Flag),
+	}
+	s.SetStatic(static)
+	return s
+}
+
+// SetStatic replaces the static flag definitions
+func (s *Set) SetStatic(static []Flag) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.static = make(map[string]Flag, len(static))
+	for _, f := range static {
+		s.static[f.Name] = f
+	}
+}
+
+// ApplyOverrides replaces the current broker-pushed overrides
+func (s *Set) ApplyOverrides(overrides []Flag) error {
+	for _, f := range overrides {
+		if err := f.Validate(); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides = make(map[string]Flag, len(overrides))
+	for _, f := range overrides {
+		s.overrides[f.Name] = f
+	}
+	return nil
+}
+
+// Enabled reports whether a flag is enabled for the whole deployment.
+// Flags rolled out to a percentage of traffic are not considered enabled.
+func (s *Set) Enabled(name string) bool {
+	f, ok := s.lookup(name)
+	if !ok || !f.Enabled {
+		return false
+	}
+	return f.Percentage == nil || *f.Percentage >= 100
+}
+
+// EnabledFor reports whether a flag is enabled for the given traffic key
+// (e.g. a session or user ID). The same key always lands in the same bucket.
+func (s *Set) EnabledFor(name, key string) bool {
+	f, ok := s.lookup(name)
+	if !ok || !f.Enabled {
+		return false
+	}
+	if f.Percentage == nil {
+		return true
+	}
+	return bucket(name, key) < *f.Percentage
+}
+
+// Snapshot returns the effective flags sorted by name
+func (s *Set) Snapshot() []Flag {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	merged := make(map[string]Flag, len(s.static)+len(s.overrides))
+	for name, f := range s.static {
+		merged[name] = f
+	}
+	for name, f := range s.overrides {
+		merged[name] = f
+	}
+	result := make([]Flag, 0, len(merged))
+	for _, f := range merged {
+		result = append(result, f)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+func (s *Set) lookup(name string) (Flag, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if f, ok := s.overrides[name]; ok {
+		return f, true
+	}
+	f, ok := s.static[name]
+	return f, ok
+}
+
+// bucket maps a flag/key pair to a stable value in [0, 100)
+func bucket(name, key string) float64 {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return float64(h.Sum32()%10000) / 100
+}
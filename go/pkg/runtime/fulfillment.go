@@ -0,0 +1,45 @@
+package runtime
+
+import (
+	"context"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/fulfillment"
+	"google.golang.org/grpc"
+)
+
+// unaryFulfillment returns the fulfillment of every unary RPC in the trailer
+// of its response. Handlers add to the estimated cost with
+// fulfillment.ReportCost.
+func (s *IntentServer) unaryFulfillment(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, tracker := fulfillment.Start(ctx, s.opts.serviceID, s.opts.clock.Now())
+	resp, err := handler(ctx, req)
+	if terr := fulfillment.SetTrailer(ctx, tracker.Finish(s.opts.clock.Now())); terr != nil {
+		s.opts.log(logging.Server).Warn("failed to send fulfillment", "method", info.FullMethod, "error", terr)
+	}
+	return resp, err
+}
+
+// streamFulfillment returns the fulfillment of every streaming RPC in the
+// trailer sent when the stream ends
+func (s *IntentServer) streamFulfillment(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, tracker := fulfillment.Start(stream.Context(), s.opts.serviceID, s.opts.clock.Now())
+	err := handler(srv, &trackedStream{ServerStream: stream, ctx: ctx})
+	md, terr := fulfillment.Trailer(tracker.Finish(s.opts.clock.Now()))
+	if terr != nil {
+		s.opts.log(logging.Server).Warn("failed to send fulfillment", "method", info.FullMethod, "error", terr)
+	} else {
+		stream.SetTrailer(md)
+	}
+	return err
+}
+
+// trackedStream exposes the fulfillment tracker to stream handlers
+type trackedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *trackedStream) Context() context.Context {
+	return s.ctx
+}
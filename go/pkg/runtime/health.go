@@ -0,0 +1,231 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// HealthChecker implements gRPC health check service
+type HealthChecker struct {
+	runtime *IntentRuntime
+}
+
+// NewHealthChecker creates a new health checker
+func NewHealthChecker(runtime *IntentRuntime) *HealthChecker {
+	return &HealthChecker{
+		runtime: runtime,
+	}
+}
+
+// Check implements the health check RPC
+func (h *HealthChecker) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	if h.runtime.Health(ctx) != nil {
+		return &grpc_health_v1.HealthCheckResponse{
+			Status: grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+		}, nil
+	}
+	return &grpc_health_v1.HealthCheckResponse{
+		Status: grpc_health_v1.HealthCheckResponse_SERVING,
+	}, nil
+}
+
+// Watch implements the health watch RPC. It sends the current status, then
+// re-checks periodically until the client goes away or the runtime is
+// closed, at which point a final NOT_SERVING status is sent.
+func (h *HealthChecker) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	ctx := stream.Context()
+	for {
+		status, err := h.Check(ctx, req)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(status); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-h.runtime.ctx.Done():
+			return stream.Send(&grpc_health_v1.HealthCheckResponse{
+				Status: grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+			})
+		case <-h.runtime.opts.clock.After(5 * time.Second):
+		}
+	}
+}
+
+// HealthOption configures StartHealthReporting
+type HealthOption func(*healthOptions)
+
+type healthOptions struct {
+	interval         time.Duration
+	jitter           float64
+	failureThreshold int
+}
+
+// WithHeartbeatInterval sets the heartbeat interval, e.g. longer than the
+// default on low-power devices. The broker may still change it later, and
+// leases too short for it still shorten it.
+func WithHeartbeatInterval(interval time.Duration) HealthOption {
+	return func(o *healthOptions) {
+		o.interval = interval
+	}
+}
+
+// WithHeartbeatJitter shortens each wait between heartbeats by a random part
+// of up to fraction, between 0 and 1, so a fleet started together does not
+// beat in lockstep. Waits are never lengthened, so leases stay safe.
+func WithHeartbeatJitter(fraction float64) HealthOption {
+	return func(o *healthOptions) {
+		o.jitter = min(max(fraction, 0), 1)
+	}
+}
+
+// WithFailureThreshold makes StartHealthReporting give up with an error once
+// n heartbeats in a row of a service have failed. Without it heartbeats are
+// retried indefinitely, which suits runtimes that also run StartSupervisor.
+func WithFailureThreshold(n int) HealthOption {
+	return func(o *healthOptions) {
+		o.failureThreshold = n
+	}
+}
+
+// StartHealthReporting sends periodic heartbeats to the broker for every
+// registered service, including contracts registered after it started, and
+// none while none is registered or all are deregistered or revoked. It
+// blocks until ctx is cancelled, the runtime is closed or Shutdown begins,
+// and then returns nil; cancel ctx to stop it. It fails with ErrNotConnected
+// before Connect, and with the last heartbeat error once the threshold of
+// WithFailureThreshold is reached.
+func (r *IntentRuntime) StartHealthReporting(ctx context.Context, opts ...HealthOption) error {
+	if err := r.ready(); err != nil {
+		return err
+	}
+	var o healthOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.interval > 0 {
+		r.SetHeartbeatInterval(o.interval)
+	}
+	ctx, cancel := r.bindLoop(ctx)
+	defer cancel()
+
+	failures := make(map[string]int)
+	for {
+		// Re-read the interval each cycle so broker-pushed changes and
+		// shorter leases take effect
+		delay := r.heartbeatDelay()
+		if o.jitter > 0 {
+			delay -= time.Duration(rand.Float64() * o.jitter * float64(delay))
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.opts.clock.After(delay):
+		}
+		for _, reg := range r.registered() {
+			err := r.sendHeartbeat(ctx, reg.serviceID)
+			if ctx.Err() != nil {
+				return nil
+			}
+			r.opts.metrics.Heartbeat(reg.serviceID, err)
+			if err == nil {
+				delete(failures, reg.serviceID)
+				r.opts.log(logging.Health).Debug("heartbeat sent", "service_id", reg.serviceID)
+				continue
+			}
+			r.notifyUnregistered(err)
+			failures[reg.serviceID]++
+			if o.failureThreshold > 0 && failures[reg.serviceID] >= o.failureThreshold {
+				r.opts.log(logging.Health).Error("giving up heartbeats", "service_id", reg.serviceID, "failures", failures[reg.serviceID], "error", err)
+				return fmt.Errorf("%d heartbeats in a row for %s failed: %w", failures[reg.serviceID], reg.serviceID, err)
+			}
+			if r.goAway.Load() != nil {
+				// Expected while the broker is away
+				r.opts.log(logging.Health).Debug("heartbeat failed, broker is away", "service_id", reg.serviceID, "error", err)
+				continue
+			}
+			r.opts.log(logging.Health).Warn("heartbeat failed", "service_id", reg.serviceID, "error", err)
+		}
+	}
+}
+
+// Health returns nil while the runtime is connected to the broker and not
+// draining, and the reason it cannot serve otherwise
+func (r *IntentRuntime) Health(ctx context.Context) error {
+	if err := r.ready(); err != nil {
+		return err
+	}
+	if r.Draining() {
+		return ErrDraining
+	}
+	if state := r.conn.GetState(); state != connectivity.Ready {
+		if goAway := r.goAway.Load(); goAway != nil {
+			return fmt.Errorf("%w: broker went away: %s", ErrBrokerUnavailable, goAway.Reason)
+		}
+		return fmt.Errorf("%w: connection is %s", ErrBrokerUnavailable, state)
+	}
+	return nil
+}
+
+// HeartbeatInterval returns the current heartbeat interval
+func (r *IntentRuntime) HeartbeatInterval() time.Duration {
+	return time.Duration(r.heartbeatInterval.Load())
+}
+
+// SetHeartbeatInterval changes the heartbeat interval, effective from the next beat
+func (r *IntentRuntime) SetHeartbeatInterval(interval time.Duration) {
+	r.heartbeatInterval.Store(int64(interval))
+}
+
+// Heartbeat sends one heartbeat for every registered service, for callers
+// that schedule heartbeats themselves instead of using StartHealthReporting.
+// Each heartbeat gives up at ctx's deadline, or after defaultHeartbeatTimeout
+// without one. The errors of failed heartbeats are joined.
+func (r *IntentRuntime) Heartbeat(ctx context.Context) error {
+	regs := r.registered()
+	if len(regs) == 0 {
+		return fmt.Errorf("%w: no service has been registered", ErrNotRegistered)
+	}
+	ctx, cancel := r.bind(ctx)
+	defer cancel()
+	var errs []error
+	for _, reg := range regs {
+		if err := r.sendHeartbeat(ctx, reg.serviceID); err != nil {
+			errs = append(errs, fmt.Errorf("heartbeat for %s: %w", reg.serviceID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// defaultHeartbeatTimeout bounds a heartbeat whose context has no deadline
+const defaultHeartbeatTimeout = 5 * time.Second
+
+func (r *IntentRuntime) sendHeartbeat(ctx context.Context, serviceID string) error {
+	if err := r.ready(); err != nil {
+		return err
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultHeartbeatTimeout)
+		defer cancel()
+	}
+
+	sent := r.opts.clock.Now()
+	lease, err := r.client.RenewWithCapacity(ctx, serviceID, r.RoundTrip(), r.advertisedLoad(serviceID))
+	if err != nil {
+		return err
+	}
+	r.observeLease(serviceID, sent, r.opts.clock.Now(), lease)
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,144 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+)
+
+// fastClock records the waits asked of it and ends each after a millisecond
+type fastClock struct {
+	mu     sync.Mutex
+	delays []time.Duration
+}
+
+func (c *fastClock) Now() time.Time { return time.Now() }
+
+func (c *fastClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	c.delays = append(c.delays, d)
+	c.mu.Unlock()
+	return time.After(time.Millisecond)
+}
+
+func (c *fastClock) waits() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.delays...)
+}
+
+// countingMetrics counts heartbeats
+type countingMetrics struct {
+	noopMetrics
+	mu         sync.Mutex
+	heartbeats int
+}
+
+func (m *countingMetrics) Heartbeat(string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.heartbeats++
+}
+
+func (m *countingMetrics) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.heartbeats
+}
+
+func TestStartHealthReporting(t *testing.T) {
+	b := broker.NewEmbedded()
+	defer b.Close()
+	clock := &fastClock{}
+	metrics := &countingMetrics{}
+	r := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...), WithClock(clock), WithMetrics(metrics))
+	defer r.Close()
+
+	if err := r.StartHealthReporting(context.Background()); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("StartHealthReporting() before Connect error = %v, want ErrNotConnected", err)
+	}
+	if err := r.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	// Started before registration, it waits for a service instead of
+	// returning
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- r.StartHealthReporting(ctx, WithHeartbeatInterval(time.Minute), WithHeartbeatJitter(0.5))
+	}()
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case err := <-done:
+		t.Fatalf("StartHealthReporting() returned %v before registration", err)
+	default:
+	}
+	if _, err := r.RegisterFromBytes(ctx, []byte(leaseContract)); err != nil {
+		t.Fatalf("RegisterFromBytes() error = %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for metrics.count() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if metrics.count() < 2 {
+		t.Fatalf("%d heartbeats sent after registration, want at least 2", metrics.count())
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("StartHealthReporting() after cancel error = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("StartHealthReporting() did not stop when its context was cancelled")
+	}
+
+	if got := r.HeartbeatInterval(); got != time.Minute {
+		t.Errorf("HeartbeatInterval() = %v, want 1m", got)
+	}
+	// Jitter only shortens the waits, which the lease may shorten further,
+	// and spreads them
+	waits := clock.waits()
+	spread := false
+	for _, d := range waits {
+		if d <= 0 || d > time.Minute {
+			t.Errorf("waited %v between heartbeats, want up to 1m", d)
+		}
+		spread = spread || d != waits[0]
+	}
+	if !spread {
+		t.Errorf("waits %v between heartbeats are not jittered", waits)
+	}
+}
+
+func TestStartHealthReportingFailureThreshold(t *testing.T) {
+	b := broker.NewEmbedded()
+	r := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...), WithClock(&fastClock{}))
+	defer r.Close()
+	if err := r.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if _, err := r.RegisterFromBytes(context.Background(), []byte(leaseContract)); err != nil {
+		t.Fatalf("RegisterFromBytes() error = %v", err)
+	}
+	b.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- r.StartHealthReporting(context.Background(), WithFailureThreshold(3))
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Errorf("StartHealthReporting() with the broker gone error = nil, want the heartbeat error")
+		}
+	case <-time.After(20 * time.Second):
+		t.Fatalf("StartHealthReporting() did not give up after 3 failed heartbeats")
+	}
+}
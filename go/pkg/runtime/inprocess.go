@@ -0,0 +1,417 @@
+package runtime
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// local holds the intent servers of this process by service ID
+var local = struct {
+	sync.Mutex
+	servers map[string]*IntentServer
+}{servers: make(map[string]*IntentServer)}
+
+func registerLocal(serviceID string, s *IntentServer) {
+	local.Lock()
+	defer local.Unlock()
+	local.servers[serviceID] = s
+}
+
+func unregisterLocal(serviceID string, s *IntentServer) {
+	local.Lock()
+	defer local.Unlock()
+	if local.servers[serviceID] == s {
+		delete(local.servers, serviceID)
+	}
+}
+
+// LocalConn returns an in-process connection to the provider serviceID when
+// its intent server lives in this process, as with an embedded broker. It
+// returns false otherwise, and the caller dials the provider's endpoint:
+//
+//	cc, ok := runtime.LocalConn(serviceID)
+//	if !ok {
+//		cc, err = grpc.Dial(endpoint, ...)
+//	}
+//	client := translator.NewTranslatorClient(cc)
+func LocalConn(serviceID string) (grpc.ClientConnInterface, bool) {
+	local.Lock()
+	defer local.Unlock()
+	s, ok := local.servers[serviceID]
+	if !ok {
+		return nil, false
+	}
+	return s.InProcessConn(), true
+}
+
+// InProcessConn returns a connection calling the server's handlers directly.
+// Requests skip serialization and the network but pass the same interceptors
+// as requests over gRPC, so metrics and fulfillment trailers are unchanged.
+// Messages are copied, so neither side sees the other's later changes.
+func (s *IntentServer) InProcessConn() grpc.ClientConnInterface {
+	return &inProcessConn{server: s}
+}
+
+type inProcessConn struct {
+	server *IntentServer
+}
+
+// Invoke calls a unary handler
+func (c *inProcessConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	impl, desc, err := c.server.lookup(method)
+	if err != nil {
+		return err
+	}
+	var handler *grpc.MethodDesc
+	for i := range desc.Methods {
+		if desc.Methods[i].MethodName == methodName(method) {
+			handler = &desc.Methods[i]
+		}
+	}
+	if handler == nil {
+		return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+	}
+	in, ok := args.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "in-process request of %s is not a protobuf message", method)
+	}
+	out, ok := reply.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "in-process response of %s is not a protobuf message", method)
+	}
+
+	transport := &inProcessTransport{method: method}
+	ctx = grpc.NewContextWithServerTransportStream(incoming(ctx), transport)
+	dec := func(v interface{}) error {
+		m, ok := v.(proto.Message)
+		if !ok {
+			return status.Errorf(codes.Internal, "in-process request of %s is not a protobuf message", method)
+		}
+		proto.Merge(m, in)
+		return nil
+	}
+	resp, err := handler.Handler(impl, ctx, dec, chainUnary(c.server.unary))
+	transport.deliver(opts)
+	if err != nil {
+		return toStatus(err)
+	}
+	proto.Reset(out)
+	if m, ok := resp.(proto.Message); ok {
+		proto.Merge(out, m)
+	}
+	return nil
+}
+
+// NewStream starts a streaming handler in its own goroutine; messages are
+// passed to it over channels
+func (c *inProcessConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	impl, svc, err := c.server.lookup(method)
+	if err != nil {
+		return nil, err
+	}
+	var handler *grpc.StreamDesc
+	for i := range svc.Streams {
+		if svc.Streams[i].StreamName == methodName(method) {
+			handler = &svc.Streams[i]
+		}
+	}
+	if handler == nil {
+		return nil, status.Errorf(codes.Unimplemented, "unknown method %s", method)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	p := &pipe{
+		transport: inProcessTransport{method: method},
+		toServer:  make(chan proto.Message),
+		toClient:  make(chan proto.Message),
+		header:    make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	server := &inProcessServerStream{pipe: p, ctx: grpc.NewContextWithServerTransportStream(incoming(ctx), &p.transport)}
+	client := &inProcessClientStream{pipe: p, ctx: ctx, opts: opts}
+	info := &grpc.StreamServerInfo{
+		FullMethod:     method,
+		IsClientStream: handler.ClientStreams,
+		IsServerStream: handler.ServerStreams,
+	}
+	go func() {
+		defer cancel()
+		err := chainStream(c.server.stream)(impl, server, info, handler.Handler)
+		if err != nil {
+			p.err = toStatus(err)
+		}
+		p.sendHeader()
+		close(p.done)
+	}()
+	return client, nil
+}
+
+// lookup finds the implementation and descriptor of the service of a full
+// method name, "/package.Service/Method"
+func (s *IntentServer) lookup(method string) (interface{}, *grpc.ServiceDesc, error) {
+	name := strings.TrimPrefix(method, "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[:i]
+	}
+	desc, ok := s.descs[name]
+	if !ok {
+		return nil, nil, status.Errorf(codes.Unimplemented, "unknown service %s", name)
+	}
+	return s.services[name], desc, nil
+}
+
+func methodName(method string) string {
+	return method[strings.LastIndex(method, "/")+1:]
+}
+
+// incoming turns the caller's outgoing metadata into the handler's incoming
+// metadata
+func incoming(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return metadata.NewIncomingContext(ctx, md.Copy())
+}
+
+// toStatus converts a handler error as gRPC would
+func toStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if ctxErr := status.FromContextError(err); ctxErr.Code() != codes.Unknown {
+		return ctxErr.Err()
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
+
+func chainUnary(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], handler
+			handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+func chainStream(interceptors []grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], handler
+			handler = func(srv interface{}, stream grpc.ServerStream) error {
+				return interceptor(srv, stream, info, next)
+			}
+		}
+		return handler(srv, stream)
+	}
+}
+
+// inProcessTransport collects the headers and trailers a handler sets
+type inProcessTransport struct {
+	method string
+
+	mu      sync.Mutex
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+func (t *inProcessTransport) Method() string {
+	return t.method
+}
+
+func (t *inProcessTransport) SetHeader(md metadata.MD) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.header = metadata.Join(t.header, md)
+	return nil
+}
+
+func (t *inProcessTransport) SendHeader(md metadata.MD) error {
+	return t.SetHeader(md)
+}
+
+func (t *inProcessTransport) SetTrailer(md metadata.MD) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.trailer = metadata.Join(t.trailer, md)
+	return nil
+}
+
+// deliver fills the grpc.Header and grpc.Trailer call options
+func (t *inProcessTransport) deliver(opts []grpc.CallOption) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case grpc.HeaderCallOption:
+			*o.HeaderAddr = t.header.Copy()
+		case grpc.TrailerCallOption:
+			*o.TrailerAddr = t.trailer.Copy()
+		}
+	}
+}
+
+// pipe connects the two ends of an in-process stream. The channels are
+// unbuffered, so every message sent before the handler returns is received
+// before the client sees the end of the stream.
+type pipe struct {
+	transport inProcessTransport
+	toServer  chan proto.Message
+	toClient  chan proto.Message
+	closeSend sync.Once
+	header    chan struct{} // closed once headers are sent
+	sendOnce  sync.Once
+	done      chan struct{} // closed when the handler returned
+	err       error         // handler error, set before done is closed
+}
+
+func (p *pipe) sendHeader() {
+	p.sendOnce.Do(func() { close(p.header) })
+}
+
+type inProcessServerStream struct {
+	*pipe
+	ctx context.Context
+}
+
+func (s *inProcessServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *inProcessServerStream) SetHeader(md metadata.MD) error {
+	return s.transport.SetHeader(md)
+}
+
+func (s *inProcessServerStream) SendHeader(md metadata.MD) error {
+	s.transport.SetHeader(md)
+	s.sendHeader()
+	return nil
+}
+
+func (s *inProcessServerStream) SetTrailer(md metadata.MD) {
+	s.transport.SetTrailer(md)
+}
+
+func (s *inProcessServerStream) SendMsg(m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Error(codes.Internal, "in-process stream message is not a protobuf message")
+	}
+	s.sendHeader()
+	select {
+	case s.toClient <- proto.Clone(msg):
+		return nil
+	case <-s.ctx.Done():
+		return status.FromContextError(s.ctx.Err()).Err()
+	}
+}
+
+func (s *inProcessServerStream) RecvMsg(m interface{}) error {
+	select {
+	case msg, ok := <-s.toServer:
+		if !ok {
+			return io.EOF
+		}
+		return merge(m, msg)
+	case <-s.ctx.Done():
+		return status.FromContextError(s.ctx.Err()).Err()
+	}
+}
+
+type inProcessClientStream struct {
+	*pipe
+	ctx  context.Context
+	opts []grpc.CallOption
+}
+
+func (s *inProcessClientStream) Header() (metadata.MD, error) {
+	select {
+	case <-s.header:
+	case <-s.ctx.Done():
+		return nil, status.FromContextError(s.ctx.Err()).Err()
+	}
+	s.transport.mu.Lock()
+	defer s.transport.mu.Unlock()
+	return s.transport.header.Copy(), nil
+}
+
+func (s *inProcessClientStream) Trailer() metadata.MD {
+	select {
+	case <-s.done:
+	default:
+		return nil
+	}
+	s.transport.mu.Lock()
+	defer s.transport.mu.Unlock()
+	return s.transport.trailer.Copy()
+}
+
+func (s *inProcessClientStream) CloseSend() error {
+	s.closeSend.Do(func() { close(s.toServer) })
+	return nil
+}
+
+func (s *inProcessClientStream) Context() context.Context {
+	return s.ctx
+}
+
+// SendMsg returns io.EOF once the handler returned; RecvMsg then returns its
+// status
+func (s *inProcessClientStream) SendMsg(m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Error(codes.Internal, "in-process stream message is not a protobuf message")
+	}
+	select {
+	case s.toServer <- proto.Clone(msg):
+		return nil
+	case <-s.done:
+		return io.EOF
+	case <-s.ctx.Done():
+		return status.FromContextError(s.ctx.Err()).Err()
+	}
+}
+
+func (s *inProcessClientStream) RecvMsg(m interface{}) error {
+	select {
+	case msg := <-s.toClient:
+		return merge(m, msg)
+	case <-s.done:
+		return s.finish()
+	case <-s.ctx.Done():
+		// The context is also cancelled once the handler returned
+		select {
+		case <-s.done:
+			return s.finish()
+		default:
+			return status.FromContextError(s.ctx.Err()).Err()
+		}
+	}
+}
+
+// finish reports the end of the stream after the handler returned
+func (s *inProcessClientStream) finish() error {
+	s.transport.deliver(s.opts)
+	if s.err != nil {
+		return s.err
+	}
+	return io.EOF
+}
+
+func merge(dst interface{}, src proto.Message) error {
+	m, ok := dst.(proto.Message)
+	if !ok {
+		return status.Error(codes.Internal, "in-process stream message is not a protobuf message")
+	}
+	proto.Reset(m)
+	proto.Merge(m, src)
+	return nil
+}
@@ -0,0 +1,96 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxInvokeAttempts bounds the providers an intent is tried on
+const maxInvokeAttempts = 3
+
+// IntentClient invokes intents for consumer applications, so they need not
+// resolve and dial providers themselves. It resolves the action with the
+// broker, connects to the providers in the broker's order and calls the
+// provider's RPC, moving on to the next provider while one is unreachable.
+// It shares the resolutions, prefetching, cached connections, dialer and
+// outcome reports of its runtime, see Resolve and ProviderConn.
+//
+// Errors wrap ErrNoProvider when no provider serves the action and
+// ErrProviderUnavailable when none could be reached; an error of the
+// provider, e.g. InvalidArgument, is returned wrapped with its status.
+type IntentClient struct {
+	rt *IntentRuntime
+}
+
+// NewIntentClient returns a client invoking intents through rt, which must
+// be connected to the broker and needs WithProviderDialer for providers
+// outside this process
+func NewIntentClient(rt *IntentRuntime) *IntentClient {
+	return &IntentClient{rt: rt}
+}
+
+// Invoke calls method, the full name of the provider's unary RPC such as
+// "/translator.Translator/TranslateText", with req on a provider of action
+// and decodes its response into reply. It returns the ID of the provider
+// that answered. At most three providers are tried, and only a provider
+// that is Unavailable or cannot be dialed is skipped: other errors may
+// have come after the provider acted on the request.
+func (c *IntentClient) Invoke(ctx context.Context, action, method string, req, reply interface{}, opts ...grpc.CallOption) (string, error) {
+	serviceIDs, err := c.rt.Resolve(ctx, action, contract.StreamingUnary)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", action, err)
+	}
+	if len(serviceIDs) == 0 {
+		return "", fmt.Errorf("failed to invoke %s: %w", action, ErrNoProvider)
+	}
+	var lastErr error
+	for i, serviceID := range serviceIDs {
+		if i == maxInvokeAttempts || ctx.Err() != nil {
+			break
+		}
+		cc, err := c.rt.ProviderConn(ctx, serviceID)
+		if errors.Is(err, ErrNotConnected) || errors.Is(err, ErrNoDialer) {
+			return "", err
+		}
+		if err == nil {
+			err = cc.Invoke(ctx, method, req, reply, opts...)
+			if intentAction(req) == "" {
+				// The connection reports intent requests only
+				go c.rt.reportOutcome(serviceID, action, err)
+			}
+			if err == nil {
+				return serviceID, nil
+			}
+			if status.Code(err) != codes.Unavailable {
+				return serviceID, fmt.Errorf("failed to invoke %s on %s: %w", action, serviceID, err)
+			}
+			c.rt.providers.evict(serviceID)
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = ctx.Err()
+	}
+	return "", fmt.Errorf("failed to invoke %s: %w: %w", action, ErrProviderUnavailable, lastErr)
+}
+
+// InvokeIntent is Invoke with an IntentRequest of action and parameters, for
+// providers whose RPC takes one. Parameters are converted with
+// contract.ValueToProto.
+func (c *IntentClient) InvokeIntent(ctx context.Context, action, method string, parameters map[string]interface{}, reply interface{}, opts ...grpc.CallOption) (string, error) {
+	req := &nfa_intent_v1alpha.IntentRequest{Action: action}
+	if len(parameters) > 0 {
+		req.Parameters = make(map[string]*nfa_intent_v1alpha.Value, len(parameters))
+		for name, value := range parameters {
+			req.Parameters[name] = contract.ValueToProto(value)
+		}
+	}
+	return c.Invoke(ctx, action, method, req, reply, opts...)
+}
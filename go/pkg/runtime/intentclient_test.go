@@ -0,0 +1,111 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// echoConn answers every call with the error of fail, or with its request
+// when fail returns nil
+type echoConn struct {
+	grpc.ClientConnInterface
+	fail func() error
+}
+
+func (c echoConn) Invoke(_ context.Context, _ string, args, reply interface{}, _ ...grpc.CallOption) error {
+	if err := c.fail(); err != nil {
+		return err
+	}
+	proto.Merge(reply.(proto.Message), args.(proto.Message))
+	return nil
+}
+
+func TestIntentClient(t *testing.T) {
+	b := broker.NewEmbedded()
+	defer b.Close()
+	ctx := context.Background()
+	var serviceIDs []string
+	for i := 0; i < 2; i++ {
+		provider := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...))
+		defer provider.Close()
+		if err := provider.Connect(); err != nil {
+			t.Fatalf("Connect() error = %v", err)
+		}
+		serviceID, err := provider.RegisterFromBytes(ctx, []byte(leaseContract))
+		if err != nil {
+			t.Fatalf("RegisterFromBytes() error = %v", err)
+		}
+		serviceIDs = append(serviceIDs, serviceID)
+	}
+
+	// Providers answer with their error, or echo the request without one
+	var mu sync.Mutex
+	errs := make(map[string]error)
+	dials := make(map[string]int)
+	consumer := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...),
+		WithProviderDialer(func(ctx context.Context, serviceID string) (grpc.ClientConnInterface, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			dials[serviceID]++
+			return echoConn{fail: func() error {
+				mu.Lock()
+				defer mu.Unlock()
+				return errs[serviceID]
+			}}, nil
+		}))
+	defer consumer.Close()
+	if err := consumer.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	client := NewIntentClient(consumer)
+	const method = "/test.Provider/Handle"
+	invoke := func() (string, error) {
+		reply := &nfa_intent_v1alpha.IntentRequest{}
+		id, err := client.InvokeIntent(ctx, "translate_text", method, map[string]interface{}{"text": "hello"}, reply)
+		if err == nil && (reply.Action != "translate_text" || reply.Parameters["text"].GetStringValue() != "hello") {
+			t.Errorf("reply = %v, want the request echoed", reply)
+		}
+		return id, err
+	}
+
+	unreachable, healthy := serviceIDs[0], serviceIDs[1]
+	mu.Lock()
+	errs[unreachable] = status.Error(codes.Unavailable, "connection refused")
+	mu.Unlock()
+	for i := 0; i < 2; i++ {
+		if id, err := invoke(); err != nil || id != healthy {
+			t.Fatalf("InvokeIntent() = %q, %v, want an answer of %s", id, err, healthy)
+		}
+	}
+	mu.Lock()
+	if dials[healthy] != 1 || dials[unreachable] > 2 {
+		t.Errorf("dials = %v, want the healthy provider's connection kept", dials)
+	}
+	errs[healthy] = status.Error(codes.Unavailable, "connection refused")
+	mu.Unlock()
+	if _, err := invoke(); !errors.Is(err, ErrProviderUnavailable) || status.Code(err) != codes.Unavailable {
+		t.Errorf("InvokeIntent() with no reachable provider error = %v, want ErrProviderUnavailable", err)
+	}
+
+	// A refused request is not tried on another provider
+	mu.Lock()
+	errs[healthy] = status.Error(codes.InvalidArgument, "text too long")
+	errs[unreachable] = status.Error(codes.InvalidArgument, "text too long")
+	mu.Unlock()
+	if _, err := invoke(); errors.Is(err, ErrProviderUnavailable) || status.Code(err) != codes.InvalidArgument {
+		t.Errorf("InvokeIntent() of a refused request error = %v, want InvalidArgument", err)
+	}
+
+	if _, err := client.Invoke(ctx, "summarize", method, &nfa_intent_v1alpha.IntentRequest{}, &nfa_intent_v1alpha.IntentRequest{}); !errors.Is(err, ErrNoProvider) {
+		t.Errorf("Invoke() of an unserved action error = %v, want ErrNoProvider", err)
+	}
+}
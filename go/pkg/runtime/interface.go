@@ -0,0 +1,120 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+)
+
+// Runtime is what applications need from an intent runtime. IntentRuntime
+// is the gRPC implementation; tests can substitute a fake, and other
+// transports (NATS, in-process) can provide their own. Accept a Runtime
+// rather than an *IntentRuntime in code that should work with either.
+type Runtime interface {
+	// Connect prepares the connection to the broker
+	Connect() error
+	// Register validates and registers a contract and returns the assigned service ID
+	Register(ctx context.Context, intentContract *contract.IntentContract) (string, error)
+	// RegisterFromFile loads a YAML or JSON contract and registers it
+	RegisterFromFile(contractPath string) (string, error)
+	// Invoke delivers an intent to the runtimes whose labels match selector
+	// and returns the status reported by each of them
+	Invoke(ctx context.Context, selector map[string]string, intent *nfa_control_v1alpha.Invoke) ([]*nfa_control_v1alpha.TargetStatus, error)
+	// Health returns nil while the runtime can serve intents, and the reason otherwise
+	Health(ctx context.Context) error
+	// Close stops background work and releases the connection
+	Close() error
+}
+
+var _ Runtime = (*IntentRuntime)(nil)
+
+// Invoke delivers an intent through the broker's broadcast service to the
+// runtimes matching selector. The broker waits for results until ctx's
+// deadline, or its default timeout when ctx has none.
+func (r *IntentRuntime) Invoke(ctx context.Context, selector map[string]string, intent *nfa_control_v1alpha.Invoke) ([]*nfa_control_v1alpha.TargetStatus, error) {
+	if err := r.ready(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := r.bind(ctx)
+	defer cancel()
+
+	req := &nfa_control_v1alpha.BroadcastRequest{
+		Selector:    selector,
+		Intent:      intent,
+		TimeoutSecs: broadcastTimeout(ctx),
+	}
+	resp, err := nfa_control_v1alpha.NewBroadcastServiceClient(r.conn).Broadcast(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to invoke %s: %w", intent.GetAction(), err)
+	}
+	return resp.Targets, nil
+}
+
+// BatchIntent is an intent of InvokeBatch and the selector of its targets
+type BatchIntent struct {
+	Selector map[string]string
+	Intent   *nfa_control_v1alpha.Invoke
+}
+
+// BatchResult is the outcome of a BatchIntent: the status reported by each
+// target, or why the broker refused the intent
+type BatchResult struct {
+	Targets []*nfa_control_v1alpha.TargetStatus
+	Err     error
+}
+
+// InvokeBatch delivers independent intents in one call to the broker, e.g.
+// to refresh many widgets at once. The broker delivers them concurrently and
+// waits for results as Invoke does; the result of intents[i] is the i-th.
+// An intent the broker refuses fails alone, in its result. Brokers that
+// predate batches fail with ErrUnsupported.
+func (r *IntentRuntime) InvokeBatch(ctx context.Context, intents []BatchIntent) ([]BatchResult, error) {
+	if err := r.ready(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := r.bind(ctx)
+	defer cancel()
+
+	req := &nfa_control_v1alpha.BroadcastBatchRequest{}
+	timeout := broadcastTimeout(ctx)
+	for _, in := range intents {
+		req.Requests = append(req.Requests, &nfa_control_v1alpha.BroadcastRequest{
+			Selector:    in.Selector,
+			Intent:      in.Intent,
+			TimeoutSecs: timeout,
+		})
+	}
+	resp, err := nfa_control_v1alpha.NewBroadcastServiceClient(r.conn).BroadcastBatch(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to invoke batch of %d intents: %w", len(intents), unsupported("batch invocation", err))
+	}
+	if len(resp.Results) != len(intents) {
+		return nil, fmt.Errorf("broker answered %d of %d intents", len(resp.Results), len(intents))
+	}
+	results := make([]BatchResult, len(intents))
+	for i, res := range resp.Results {
+		if res.Error != "" {
+			results[i].Err = fmt.Errorf("failed to invoke %s: %s", intents[i].Intent.GetAction(), res.Error)
+			continue
+		}
+		results[i].Targets = res.GetResponse().GetTargets()
+	}
+	return results, nil
+}
+
+// broadcastTimeout is how long the broker waits for the results of a
+// broadcast made with ctx; zero, its default, without a deadline
+func broadcastTimeout(ctx context.Context) uint32 {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0
+	}
+	// Leave the broker time to answer before the caller gives up
+	if wait := time.Until(deadline) - time.Second; wait >= time.Second {
+		return uint32(wait / time.Second)
+	}
+	return 1
+}
@@ -0,0 +1,87 @@
+package runtime
+
+import (
+	"math"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// MinKeepaliveTime is the shortest ping interval gRPC clients use, and how
+// often intent servers accept pings from them
+const MinKeepaliveTime = 10 * time.Second
+
+// Keepalive configures the pings that keep idle connections open and detect
+// dead ones, and how long an intent server keeps a connection. Home routers
+// and carrier NATs drop idle connections after a few minutes without
+// telling either end, so both ends ping well within that.
+type Keepalive struct {
+	// Time is how long a connection may be idle before a ping checks it; 0
+	// disables pings. The runtime pings at most every MinKeepaliveTime.
+	Time time.Duration
+	// Timeout is how long an unanswered ping waits before the connection is
+	// closed as dead; 0 waits 20 seconds
+	Timeout time.Duration
+	// MaxConnectionAge closes an intent server's connections once they are
+	// this old, so clients reconnect, e.g. to a new address after a network
+	// change; 0 keeps connections indefinitely. The runtime ignores it.
+	MaxConnectionAge time.Duration
+	// MaxConnectionAgeGrace is how long requests in flight on a connection
+	// closed for its age may still take; 0 waits for them indefinitely
+	MaxConnectionAgeGrace time.Duration
+}
+
+// DefaultKeepalive returns the keepalive used unless WithKeepalive is given:
+// pings after 30 seconds idle, answered within 10, and connections that are
+// never closed for their age
+func DefaultKeepalive() Keepalive {
+	return Keepalive{
+		Time:    30 * time.Second,
+		Timeout: 10 * time.Second,
+	}
+}
+
+// WithKeepalive replaces the default keepalive of the runtime's broker
+// connection and of the intent server's connections. Start from
+// DefaultKeepalive to change single settings.
+func WithKeepalive(k Keepalive) Option {
+	return func(o *options) {
+		o.keepalive = k
+	}
+}
+
+// dialOption returns the keepalive of a client connection. Pings are sent
+// without RPCs in flight too, since an idle connection is the one a NAT drops.
+func (k Keepalive) dialOption() grpc.DialOption {
+	return grpc.WithKeepaliveParams(keepalive.ClientParameters{
+		Time:                forever(k.Time),
+		Timeout:             k.Timeout,
+		PermitWithoutStream: true,
+	})
+}
+
+// serverOptions return the keepalive of a server's connections, accepting
+// pings from clients as often as they may send them
+func (k Keepalive) serverOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:                  forever(k.Time),
+			Timeout:               k.Timeout,
+			MaxConnectionAge:      forever(k.MaxConnectionAge),
+			MaxConnectionAgeGrace: forever(k.MaxConnectionAgeGrace),
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             MinKeepaliveTime,
+			PermitWithoutStream: true,
+		}),
+	}
+}
+
+// forever maps 0, which gRPC replaces with its own defaults, to no limit
+func forever(d time.Duration) time.Duration {
+	if d == 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return d
+}
@@ -0,0 +1,114 @@
+package runtime
+
+import (
+	"sync"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+)
+
+// maxClockSkew is the skew from the broker's clock above which a warning is
+// logged. Leases do not depend on the clocks agreeing, but timestamps in
+// logs and events of the device are off by the skew.
+const maxClockSkew = 2 * time.Second
+
+// leases tracks the leases the broker granted and what heartbeats measured
+// on the way: the round trip to the broker and the skew of its clock. Every
+// deadline is a local time derived from local measurements; times from the
+// broker are only used to estimate the skew.
+type leases struct {
+	mu      sync.Mutex
+	expires map[string]time.Time // service ID -> end of its lease, local time
+	ttl     time.Duration        // last TTL granted, 0 before the first lease
+	// roundTrip is smoothed over heartbeats like TCP's round-trip estimate
+	roundTrip time.Duration
+	skew      time.Duration
+	skewed    bool // a skew above maxClockSkew was logged
+}
+
+// observeLease records the lease granted to a heartbeat for serviceID sent
+// at sent and answered at received, both on the runtime's clock. The lease
+// is counted from sent, before the broker started counting, so it ends no
+// later than the broker's whatever the skew.
+func (r *IntentRuntime) observeLease(serviceID string, sent, received time.Time, lease broker.Lease) {
+	rtt := received.Sub(sent)
+	l := &r.leases
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.roundTrip == 0 {
+		l.roundTrip = rtt
+	} else {
+		l.roundTrip = (7*l.roundTrip + rtt) / 8
+	}
+	if lease.TTL > 0 {
+		if l.expires == nil {
+			l.expires = make(map[string]time.Time)
+		}
+		l.expires[serviceID] = sent.Add(lease.TTL)
+		l.ttl = lease.TTL
+	}
+	if lease.BrokerTime.IsZero() {
+		return
+	}
+	// Assuming symmetric delay, the broker answered halfway through the round trip
+	l.skew = lease.BrokerTime.Sub(sent.Add(rtt / 2))
+	large := l.skew > maxClockSkew || l.skew < -maxClockSkew
+	if large && !l.skewed {
+		r.opts.log(logging.Health).Warn("clock is skewed from the broker's", "skew", l.skew, "round_trip", rtt)
+	}
+	l.skewed = large
+}
+
+// dropLease forgets the lease of a service that is no longer registered
+func (r *IntentRuntime) dropLease(serviceID string) {
+	r.leases.mu.Lock()
+	defer r.leases.mu.Unlock()
+	if serviceID == "" {
+		r.leases.expires = nil
+		return
+	}
+	delete(r.leases.expires, serviceID)
+}
+
+// LeaseExpiry returns when the lease of a registered service ends without
+// another heartbeat, on the runtime's clock. ok is false before the first
+// heartbeat and with brokers predating leases.
+func (r *IntentRuntime) LeaseExpiry(serviceID string) (expires time.Time, ok bool) {
+	r.leases.mu.Lock()
+	defer r.leases.mu.Unlock()
+	expires, ok = r.leases.expires[serviceID]
+	return expires, ok
+}
+
+// ClockSkew returns how far the broker's clock is estimated to be ahead of
+// the runtime's, negative when it is behind, and 0 before the first
+// heartbeat answered by a broker reporting its time
+func (r *IntentRuntime) ClockSkew() time.Duration {
+	r.leases.mu.Lock()
+	defer r.leases.mu.Unlock()
+	return r.leases.skew
+}
+
+// RoundTrip returns the smoothed round trip of heartbeats to the broker
+func (r *IntentRuntime) RoundTrip() time.Duration {
+	r.leases.mu.Lock()
+	defer r.leases.mu.Unlock()
+	return r.leases.roundTrip
+}
+
+// heartbeatDelay is the heartbeat interval, shortened when the broker grants
+// leases too short for it: heartbeats are then sent at half the lease, less
+// the round trip, so one lost heartbeat does not expire the service
+func (r *IntentRuntime) heartbeatDelay() time.Duration {
+	interval := r.HeartbeatInterval()
+	r.leases.mu.Lock()
+	defer r.leases.mu.Unlock()
+	if r.leases.ttl == 0 {
+		return interval
+	}
+	if d := r.leases.ttl/2 - r.leases.roundTrip; d > 0 && d < interval {
+		return d
+	}
+	return interval
+}
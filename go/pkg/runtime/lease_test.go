@@ -0,0 +1,102 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+)
+
+const leaseContract = `
+version: v1alpha
+kind: IntentContract
+metadata:
+  name: translator
+spec:
+  intentPatterns:
+    - pattern:
+        action: translate_text
+  implementation:
+    endpoint:
+      type: grpc
+      port: 50052
+`
+
+// skewedClock is a device clock that is off from the broker's by skew
+type skewedClock struct{ skew time.Duration }
+
+func (c skewedClock) Now() time.Time                         { return time.Now().Add(c.skew) }
+func (c skewedClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func TestHeartbeatWithSkewedClock(t *testing.T) {
+	for _, skew := range []time.Duration{-6 * time.Hour, -40 * time.Second, 0, 40 * time.Second, 6 * time.Hour} {
+		t.Run(skew.String(), func(t *testing.T) {
+			b := broker.NewEmbedded()
+			defer b.Close()
+			clock := skewedClock{skew: skew}
+			r := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...), WithClock(clock))
+			defer r.Close()
+			ctx := context.Background()
+			if err := r.Connect(); err != nil {
+				t.Fatalf("Connect() error = %v", err)
+			}
+			serviceID, err := r.RegisterFromBytes(ctx, []byte(leaseContract))
+			if err != nil {
+				t.Fatalf("RegisterFromBytes() error = %v", err)
+			}
+
+			sent := clock.Now()
+			if err := r.Heartbeat(ctx); err != nil {
+				t.Fatalf("Heartbeat() error = %v", err)
+			}
+			received := clock.Now()
+
+			// The lease is counted on the device's own clock, from when the
+			// heartbeat was sent, so it is unaffected by the skew
+			expires, ok := r.LeaseExpiry(serviceID)
+			if !ok {
+				t.Fatalf("no lease after a heartbeat")
+			}
+			if remaining := expires.Sub(received); remaining <= 25*time.Second || remaining > 31*time.Second {
+				t.Errorf("lease ends in %v on the device's clock, want about 30s", remaining)
+			}
+			if expires.After(sent.Add(31 * time.Second)) {
+				t.Errorf("lease ends at %v, later than the broker's timeout after sending at %v", expires, sent)
+			}
+
+			// The broker's clock is the real one, so it is -skew ahead
+			if got := r.ClockSkew(); got < -skew-time.Second || got > -skew+time.Second {
+				t.Errorf("ClockSkew() = %v, want about %v", got, -skew)
+			}
+			if r.RoundTrip() <= 0 {
+				t.Errorf("RoundTrip() = %v, want a measured round trip", r.RoundTrip())
+			}
+
+			for _, svc := range b.Services() {
+				if svc.ServiceID == serviceID && !svc.Live {
+					t.Errorf("broker considers the service of a skewed device expired")
+				}
+			}
+		})
+	}
+}
+
+func TestHeartbeatDelayFitsLease(t *testing.T) {
+	r := NewIntentRuntime("unused")
+	r.SetHeartbeatInterval(10 * time.Second)
+	if got := r.heartbeatDelay(); got != 10*time.Second {
+		t.Errorf("heartbeatDelay() without a lease = %v, want the interval", got)
+	}
+
+	now := time.Now()
+	r.observeLease("svc", now, now.Add(100*time.Millisecond), broker.Lease{TTL: 30 * time.Second})
+	if got := r.heartbeatDelay(); got != 10*time.Second {
+		t.Errorf("heartbeatDelay() with a long lease = %v, want the interval", got)
+	}
+
+	r.observeLease("svc", now, now.Add(100*time.Millisecond), broker.Lease{TTL: 12 * time.Second})
+	if got, want := r.heartbeatDelay(), 6*time.Second-100*time.Millisecond; got != want {
+		t.Errorf("heartbeatDelay() with a short lease = %v, want %v", got, want)
+	}
+}
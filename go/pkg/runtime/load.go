@@ -0,0 +1,127 @@
+package runtime
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/runtime/resources"
+)
+
+// latencyWindow is how many recent unary requests the advertised p95
+// latency covers
+const latencyWindow = 256
+
+// LoadCollector adds to the load a service advertises with its heartbeats,
+// e.g. the depth of an application's own work queue. It is called before
+// every heartbeat of serviceID with the load gathered so far, and must not
+// block.
+type LoadCollector func(serviceID string, load *broker.Capacity)
+
+// WithLoadCollector adds a collector to the load the runtime's services
+// advertise with their heartbeats, which the broker routes by. Services
+// whose intent server runs in this process advertise its requests in
+// flight, queue depth, p95 latency and, with WithAdaptiveConcurrency, its
+// limit and host pressure without any collector; collectors run after, in
+// the order added.
+func WithLoadCollector(collector LoadCollector) Option {
+	return func(o *options) {
+		o.loadCollectors = append(o.loadCollectors, collector)
+	}
+}
+
+// CollectHostPressure is a LoadCollector advertising the pressure on the
+// host's CPUs and memory, for services whose intent server does not measure
+// it with WithAdaptiveConcurrency. It adds nothing where pressure cannot be
+// measured.
+func CollectHostPressure(serviceID string, load *broker.Capacity) {
+	if load.CPUPressure != 0 || load.MemoryPressure != 0 {
+		return
+	}
+	pressure, err := resources.MeasurePressure()
+	if err != nil {
+		return
+	}
+	load.CPUPressure = pressure.CPU
+	load.MemoryPressure = pressure.Memory
+}
+
+// serverLoad tracks the requests an intent server runs and the latency of
+// recent unary ones
+type serverLoad struct {
+	mu        sync.Mutex
+	inFlight  int
+	latencies []time.Duration // ring of up to latencyWindow
+	next      int
+}
+
+func (l *serverLoad) begin() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight++
+}
+
+// end finishes a request, recording the latency of unary ones; streams last
+// as long as their clients keep them open
+func (l *serverLoad) end(latency time.Duration, unary bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+	if !unary {
+		return
+	}
+	if len(l.latencies) < latencyWindow {
+		l.latencies = append(l.latencies, latency)
+		return
+	}
+	l.latencies[l.next] = latency
+	l.next = (l.next + 1) % latencyWindow
+}
+
+// snapshot returns the requests in flight and the p95 latency of recent
+// unary requests
+func (l *serverLoad) snapshot() (inFlight int, p95 time.Duration) {
+	l.mu.Lock()
+	latencies := slices.Clone(l.latencies)
+	inFlight = l.inFlight
+	l.mu.Unlock()
+	if len(latencies) == 0 {
+		return inFlight, 0
+	}
+	slices.Sort(latencies)
+	return inFlight, latencies[(len(latencies)*95+99)/100-1]
+}
+
+// Load returns the load of the server as advertised in heartbeats: its
+// requests in flight and queued, the p95 latency of recent unary requests
+// and, with WithConcurrencyLimit or WithAdaptiveConcurrency, its limit
+func (s *IntentServer) Load() broker.Capacity {
+	var load broker.Capacity
+	if capacity, ok := s.Capacity(); ok {
+		load = capacity
+	} else if s.queue != nil {
+		load.ConcurrencyLimit, _ = s.queue.load()
+	}
+	load.InFlight, load.P95Latency = s.load.snapshot()
+	if s.queue != nil {
+		load.QueueDepth = s.queue.queued()
+	}
+	return load
+}
+
+// advertisedLoad returns the load serviceID advertises with its next
+// heartbeat, nil when there is none to advertise
+func (r *IntentRuntime) advertisedLoad(serviceID string) *broker.Capacity {
+	load := localCapacity(serviceID)
+	if len(r.opts.loadCollectors) == 0 {
+		return load
+	}
+	if load == nil {
+		load = &broker.Capacity{}
+	}
+	for _, collect := range r.opts.loadCollectors {
+		collect(serviceID, load)
+	}
+	return load
+}
@@ -0,0 +1,78 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+)
+
+func TestServerLoad(t *testing.T) {
+	var l serverLoad
+	if inFlight, p95 := l.snapshot(); inFlight != 0 || p95 != 0 {
+		t.Errorf("snapshot() of an idle server = %d, %v, want 0, 0", inFlight, p95)
+	}
+	// The window keeps the latest requests: 1ms to 100ms after 300 slow ones
+	for i := 0; i < 300; i++ {
+		l.begin()
+		l.end(time.Hour, true)
+	}
+	for i := 1; i <= latencyWindow; i++ {
+		l.begin()
+		l.end(time.Duration(i%100+1)*time.Millisecond, true)
+	}
+	l.begin()
+	l.begin()
+	l.end(time.Hour, false) // a stream
+	inFlight, p95 := l.snapshot()
+	if inFlight != 1 {
+		t.Errorf("in flight = %d, want 1", inFlight)
+	}
+	if p95 < 90*time.Millisecond || p95 > 100*time.Millisecond {
+		t.Errorf("p95 = %v, want about 95ms", p95)
+	}
+}
+
+func TestHeartbeatAdvertisesLoad(t *testing.T) {
+	b := broker.NewEmbedded()
+	defer b.Close()
+	collected := ""
+	r := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...),
+		WithLoadCollector(func(serviceID string, load *broker.Capacity) {
+			collected = serviceID
+			load.QueueDepth += 5
+		}))
+	defer r.Close()
+	ctx := context.Background()
+	if err := r.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	serviceID, err := r.RegisterFromBytes(ctx, []byte(leaseContract))
+	if err != nil {
+		t.Fatalf("RegisterFromBytes() error = %v", err)
+	}
+	server := NewIntentServer(0, WithServiceID(serviceID), WithConcurrencyLimit(2))
+	defer server.Stop()
+	server.load.begin()
+	server.load.begin()
+	server.load.end(40*time.Millisecond, true)
+
+	if err := r.Heartbeat(ctx); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	if collected != serviceID {
+		t.Errorf("collector called for %q, want %q", collected, serviceID)
+	}
+	for _, svc := range b.Services() {
+		if svc.ServiceID != serviceID {
+			continue
+		}
+		want := broker.Capacity{ConcurrencyLimit: 2, InFlight: 1, QueueDepth: 5, P95Latency: 40 * time.Millisecond}
+		if svc.Capacity == nil || *svc.Capacity != want {
+			t.Errorf("advertised load = %+v, want %+v", svc.Capacity, want)
+		}
+		return
+	}
+	t.Errorf("service %s not registered", serviceID)
+}
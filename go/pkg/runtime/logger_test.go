@@ -0,0 +1,71 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// records returns the JSON records written with message msg
+func (b *syncBuffer) records(msg string) []map[string]interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var out []map[string]interface{}
+	for _, line := range strings.Split(b.buf.String(), "\n") {
+		var record map[string]interface{}
+		if json.Unmarshal([]byte(line), &record) == nil && record["msg"] == msg {
+			out = append(out, record)
+		}
+	}
+	return out
+}
+
+func TestWithLogger(t *testing.T) {
+	b := broker.NewEmbedded()
+	defer b.Close()
+	out := &syncBuffer{}
+	logger := slog.New(slog.NewJSONHandler(out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	r := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...), WithLogger(logger))
+	defer r.Close()
+	if err := r.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	serviceID, err := r.RegisterFromBytes(context.Background(), []byte(leaseContract))
+	if err != nil {
+		t.Fatalf("RegisterFromBytes() error = %v", err)
+	}
+	registered := out.records("service registered")
+	if len(registered) != 1 {
+		t.Fatalf("%d registration records, want 1", len(registered))
+	}
+	if rec := registered[0]; rec["level"] != "INFO" || rec["component"] != "registry" ||
+		rec["broker_address"] != broker.EmbeddedTarget || rec["service_id"] != serviceID {
+		t.Errorf("registration record = %v, want the component, broker address and service ID", rec)
+	}
+
+	server := NewIntentServer(0, WithLogger(logger), WithServiceID(serviceID))
+	server.Stop()
+	stopped := out.records("intent server stopped")
+	if len(stopped) != 1 || stopped[0]["component"] != "server" || stopped[0]["service_id"] != serviceID {
+		t.Errorf("server records = %v, want one with the server's service ID", stopped)
+	}
+}
@@ -0,0 +1,90 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// recordingMetrics implements Metrics and its extensions
+type recordingMetrics struct {
+	noopMetrics
+	mu      sync.Mutex
+	intents []string
+	states  []connectivity.State
+}
+
+func (m *recordingMetrics) Intent(action string, _ time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		action += " failed"
+	}
+	m.intents = append(m.intents, action)
+}
+
+func (m *recordingMetrics) BrokerConnection(state connectivity.State) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states = append(m.states, state)
+}
+
+func (m *recordingMetrics) lastState() connectivity.State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.states) == 0 {
+		return connectivity.Idle
+	}
+	return m.states[len(m.states)-1]
+}
+
+func TestMetricsExtensions(t *testing.T) {
+	m := &recordingMetrics{}
+	server := NewIntentServer(0, WithMetrics(m))
+	defer server.Stop()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Provider/Handle"}
+	for _, req := range []interface{}{
+		&nfa_intent_v1alpha.IntentRequest{Action: "translate_text"},
+		&nfa_intent_v1alpha.IntentContext{}, // not an intent request
+		&nfa_intent_v1alpha.IntentRequest{Action: "summarize"},
+	} {
+		server.unaryMetrics(context.Background(), req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			if r, ok := req.(*nfa_intent_v1alpha.IntentRequest); ok && r.Action == "summarize" {
+				return nil, errors.New("overloaded")
+			}
+			return req, nil
+		})
+	}
+	if want := []string{"translate_text", "summarize failed"}; len(m.intents) != 2 || m.intents[0] != want[0] || m.intents[1] != want[1] {
+		t.Errorf("intents = %v, want %v", m.intents, want)
+	}
+
+	b := broker.NewEmbedded()
+	defer b.Close()
+	r := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...), WithMetrics(m))
+	if err := r.ConnectCtx(context.Background()); err != nil {
+		t.Fatalf("ConnectCtx() error = %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for m.lastState() != connectivity.Ready && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if state := m.lastState(); state != connectivity.Ready {
+		t.Errorf("last broker connection state = %v, want READY", state)
+	}
+	r.Close()
+	deadline = time.Now().Add(5 * time.Second)
+	for m.lastState() != connectivity.Shutdown && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if state := m.lastState(); state != connectivity.Shutdown {
+		t.Errorf("broker connection state after Close = %v, want SHUTDOWN", state)
+	}
+}
@@ -0,0 +1,255 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// The MessagePack subset of the msgpack codec: the values of JSON
+// documents. Binary values written by other implementations are read as
+// base64 strings, as the JSON mapping of bytes fields expects them.
+
+var errMsgpackTruncated = errors.New("msgpack: unexpected end of data")
+
+// writeMsgpack encodes a decoded JSON value whose numbers are json.Number;
+// map keys are sorted so equal values encode the same
+func writeMsgpack(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if v {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case json.Number:
+		if i, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			writeMsgpackInt(buf, i)
+			return nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return fmt.Errorf("msgpack: %w", err)
+		}
+		buf.WriteByte(0xcb)
+		buf.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+	case string:
+		writeMsgpackHeader(buf, len(v), 0xa0, 32, 0xd9, 0xda, 0xdb)
+		buf.WriteString(v)
+	case []interface{}:
+		writeMsgpackHeader(buf, len(v), 0x90, 16, 0, 0xdc, 0xdd)
+		for _, item := range v {
+			if err := writeMsgpack(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		writeMsgpackHeader(buf, len(v), 0x80, 16, 0, 0xde, 0xdf)
+		for _, k := range keys {
+			writeMsgpack(buf, k)
+			if err := writeMsgpack(buf, v[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("msgpack: cannot encode %T", v)
+	}
+	return nil
+}
+
+func writeMsgpackInt(buf *bytes.Buffer, i int64) {
+	switch {
+	case i >= 0 && i < 128:
+		buf.WriteByte(byte(i))
+	case i < 0 && i >= -32:
+		buf.WriteByte(byte(int8(i)))
+	default:
+		buf.WriteByte(0xd3)
+		buf.Write(binary.BigEndian.AppendUint64(nil, uint64(i)))
+	}
+}
+
+// writeMsgpackHeader writes the type and length of a string, array or map:
+// fix is the type of lengths below fixLimit, and t8, t16 and t32 those of
+// 8, 16 and 32-bit lengths; t8 is 0 for types without one
+func writeMsgpackHeader(buf *bytes.Buffer, n int, fix byte, fixLimit int, t8, t16, t32 byte) {
+	switch {
+	case n < fixLimit:
+		buf.WriteByte(fix | byte(n))
+	case t8 != 0 && n <= math.MaxUint8:
+		buf.WriteByte(t8)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(t16)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		buf.WriteByte(t32)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+}
+
+// readMsgpack decodes data to the value json.Marshal encodes as the same
+// document
+func readMsgpack(data []byte) (interface{}, error) {
+	r := msgpackReader{data: data}
+	v, err := r.value()
+	if err != nil {
+		return nil, err
+	}
+	if len(r.data) > 0 {
+		return nil, fmt.Errorf("msgpack: %d bytes after the value", len(r.data))
+	}
+	return v, nil
+}
+
+type msgpackReader struct {
+	data []byte
+}
+
+func (r *msgpackReader) next(n int) ([]byte, error) {
+	if n < 0 || len(r.data) < n {
+		return nil, errMsgpackTruncated
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b, nil
+}
+
+// uint reads a big-endian unsigned integer of size bytes
+func (r *msgpackReader) uint(size int) (uint64, error) {
+	b, err := r.next(size)
+	if err != nil {
+		return 0, err
+	}
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u, nil
+}
+
+func (r *msgpackReader) value() (interface{}, error) {
+	b, err := r.next(1)
+	if err != nil {
+		return nil, err
+	}
+	t := b[0]
+	switch {
+	case t <= 0x7f:
+		return int64(t), nil
+	case t >= 0xe0:
+		return int64(int8(t)), nil
+	case t&0xe0 == 0xa0:
+		return r.str(int(t & 0x1f))
+	case t&0xf0 == 0x90:
+		return r.array(int(t & 0x0f))
+	case t&0xf0 == 0x80:
+		return r.object(int(t & 0x0f))
+	}
+	switch t {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return r.uint(1 << (t - 0xcc))
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (t - 0xd0)
+		u, err := r.uint(size)
+		if err != nil {
+			return nil, err
+		}
+		shift := 64 - 8*size // sign-extend
+		return int64(u<<shift) >> shift, nil
+	case 0xca:
+		u, err := r.uint(4)
+		return float64(math.Float32frombits(uint32(u))), err
+	case 0xcb:
+		u, err := r.uint(8)
+		return math.Float64frombits(u), err
+	case 0xd9, 0xda, 0xdb:
+		n, err := r.uint(1 << (t - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return r.str(int(n))
+	case 0xc4, 0xc5, 0xc6:
+		n, err := r.uint(1 << (t - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		b, err := r.next(int(n))
+		return base64.StdEncoding.EncodeToString(b), err
+	case 0xdc, 0xdd:
+		n, err := r.uint(2 << (t - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return r.array(int(n))
+	case 0xde, 0xdf:
+		n, err := r.uint(2 << (t - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return r.object(int(n))
+	}
+	return nil, fmt.Errorf("msgpack: unsupported type 0x%02x", t)
+}
+
+func (r *msgpackReader) str(n int) (string, error) {
+	b, err := r.next(n)
+	return string(b), err
+}
+
+func (r *msgpackReader) array(n int) ([]interface{}, error) {
+	if n > len(r.data) { // every item takes a byte at least
+		return nil, errMsgpackTruncated
+	}
+	items := make([]interface{}, n)
+	for i := range items {
+		v, err := r.value()
+		if err != nil {
+			return nil, err
+		}
+		items[i] = v
+	}
+	return items, nil
+}
+
+func (r *msgpackReader) object(n int) (map[string]interface{}, error) {
+	if 2*n > len(r.data) {
+		return nil, errMsgpackTruncated
+	}
+	m := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		k, err := r.value()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, fmt.Errorf("msgpack: map key of type %T, want a string", k)
+		}
+		v, err := r.value()
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+	return m, nil
+}
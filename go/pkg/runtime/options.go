@@ -0,0 +1,274 @@
+package runtime
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Option configures an IntentRuntime or an IntentServer. Options that do not
+// apply to a constructor are ignored by it.
+type Option func(*options)
+
+type options struct {
+	logger     *slog.Logger
+	logAttrs   []any // added to every record, see WithLogger
+	tls        *tls.Config
+	clientCert *clientCert
+	enrollment *enrollment
+	token      string
+	metrics    Metrics
+	tracer     trace.Tracer // nil unless WithTracerProvider
+	clock      Clock
+	keepalive  Keepalive
+
+	instance  broker.Instance
+	serviceID string
+	consumer  string
+	dialOpts  []grpc.DialOption
+
+	dialer            Dialer
+	prefetchThreshold float64
+
+	loadCollectors      []LoadCollector
+	capabilities        *IntentRuntime
+	debugLogs           *LogBuffer
+	debugAuthorize      Authorizer
+	admission           *contract.IntentContract
+	checkResults        bool
+	enforceResults      bool
+	concurrencyLimit    int
+	adaptiveConcurrency int // maximum limit
+	errorReports        *IntentRuntime
+	errorReportInterval time.Duration
+}
+
+// Dialer connects to the provider serviceID, e.g. by looking up its endpoint
+// in a service registry
+type Dialer func(ctx context.Context, serviceID string) (grpc.ClientConnInterface, error)
+
+// Clock tells time for heartbeats and health probes, so tests can drive them
+// without waiting
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// Metrics receives measurements from the runtime and the intent server.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// Registration records a contract registration; err is nil on success
+	Registration(contract string, err error)
+	// Heartbeat records a heartbeat sent to the broker
+	Heartbeat(serviceID string, err error)
+	// Request records an RPC handled by the intent server
+	Request(method string, duration time.Duration, err error)
+}
+
+// IntentMetrics is implemented by Metrics that also record the intent
+// requests handled by the intent server by their action. Intent is called
+// after Request for every unary intent request.
+type IntentMetrics interface {
+	Intent(action string, duration time.Duration, err error)
+}
+
+// ConnectionMetrics is implemented by Metrics that also record the state of
+// the runtime's broker connection. BrokerConnection is called when the
+// runtime connects, on every change of state, and with Shutdown once it is
+// closed.
+type ConnectionMetrics interface {
+	BrokerConnection(state connectivity.State)
+}
+
+// WithLogger sends the runtime's structured logs to logger instead of the
+// per-component loggers of the logging package, e.g. a zap or zerolog logger
+// behind a slog.Handler; its handler decides which levels are written. Each
+// record carries a "component" attribute, records of a runtime its
+// "broker_address" and those of an intent server with a service ID its
+// "service_id".
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithTLS secures connections with config: the runtime dials the broker over
+// TLS and the intent server serves TLS. Without it both use plaintext. See
+// also WithMTLS and WithTokenAuth.
+func WithTLS(config *tls.Config) Option {
+	return func(o *options) {
+		o.tls = config
+	}
+}
+
+// WithMetrics reports registrations, heartbeats and handled requests to
+// metrics, and intent requests and the broker connection state when it
+// implements IntentMetrics or ConnectionMetrics
+func WithMetrics(metrics Metrics) Option {
+	return func(o *options) {
+		o.metrics = metrics
+	}
+}
+
+// WithClock replaces the system clock
+func WithClock(clock Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// WithInstanceKey gives the runtime a stable identity, such as a device
+// serial. The broker derives the service ID from the contract name and key,
+// so the ID, and the metrics and sessions tied to it, survive restarts.
+func WithInstanceKey(key string) Option {
+	return func(o *options) {
+		o.instance.Key = key
+	}
+}
+
+// WithTakeOver lets registration replace a live registration holding the
+// same stable service ID, e.g. when restarting faster than the broker's
+// liveness timeout. Without it such a registration fails with
+// ErrServiceIDConflict.
+func WithTakeOver() Option {
+	return func(o *options) {
+		o.instance.TakeOver = true
+	}
+}
+
+// WithServiceID names the service an intent server fulfills requests for, as
+// returned by registration, in the fulfillment sent with every response
+func WithServiceID(id string) Option {
+	return func(o *options) {
+		o.serviceID = id
+	}
+}
+
+// WithConsumerName names the runtime to the broker when it resolves intents,
+// so deprecation reports tell which application still calls a deprecated
+// action. Without it the broker names the runtime by its network address.
+func WithConsumerName(name string) Option {
+	return func(o *options) {
+		o.consumer = name
+	}
+}
+
+// WithCapabilities makes an intent server serve the capabilities service from
+// the contract registered by runtime and its feature flags, so consumers can
+// check what the provider supports before invoking it
+func WithCapabilities(runtime *IntentRuntime) Option {
+	return func(o *options) {
+		o.capabilities = runtime
+	}
+}
+
+// WithDebug makes an intent server serve the debug service, implementing the
+// standard intent nfa.debug.stream_logs: authorized operators stream the
+// records of logs and the process stats, e.g. with nfactl logs. Calls
+// authorize rejects fail; a nil authorize rejects all. With WithCapabilities
+// the stats name the runtime's services.
+func WithDebug(logs *LogBuffer, authorize Authorizer) Option {
+	return func(o *options) {
+		o.debugLogs = logs
+		o.debugAuthorize = authorize
+	}
+}
+
+// WithAdmission makes an intent server reject intent requests that match no
+// intent pattern of c, before they reach handler code: an action c does not
+// declare fails with Unimplemented, parameters outside its constraints with
+// InvalidArgument. See IntentContract.Admit.
+func WithAdmission(c *contract.IntentContract) Option {
+	return func(o *options) {
+		o.admission = c
+	}
+}
+
+// WithResultValidation makes an intent server created with WithAdmission
+// check the responses of unary intent requests against the result schema
+// of their action, see IntentContract.CheckResult. Responses outside it are
+// logged and, if enforce is set, replaced with an Internal error, so
+// consumers never see them. Streamed responses are not checked.
+func WithResultValidation(enforce bool) Option {
+	return func(o *options) {
+		o.checkResults = true
+		o.enforceResults = enforce
+	}
+}
+
+// WithDialOptions adds options to the runtime's connection to the broker, e.g.
+// the dialer of an embedded broker. They are applied after the credentials.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *options) {
+		o.dialOpts = append(o.dialOpts, opts...)
+	}
+}
+
+// WithProviderDialer connects ProviderConn to providers outside this process
+func WithProviderDialer(dial Dialer) Option {
+	return func(o *options) {
+		o.dialer = dial
+	}
+}
+
+// WithPrefetch enables predictive prefetch: the runtime learns which action
+// the application resolves after each one and, once the next action is at
+// least threshold likely (0 to 1), resolves it ahead of time and, with
+// WithProviderDialer, dials its providers
+func WithPrefetch(threshold float64) Option {
+	return func(o *options) {
+		o.prefetchThreshold = threshold
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		metrics:   noopMetrics{},
+		clock:     systemClock{},
+		keepalive: DefaultKeepalive(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// log returns the logger of a component
+func (o *options) log(component string) *slog.Logger {
+	logger := logging.Logger(component)
+	if o.logger != nil {
+		logger = o.logger.With("component", component)
+	}
+	if len(o.logAttrs) > 0 {
+		logger = logger.With(o.logAttrs...)
+	}
+	return logger
+}
+
+func (o *options) transportCredentials() credentials.TransportCredentials {
+	if o.tls != nil {
+		return credentials.NewTLS(o.tls)
+	}
+	return insecure.NewCredentials()
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+type noopMetrics struct{}
+
+func (noopMetrics) Registration(string, error)           {}
+func (noopMetrics) Heartbeat(string, error)              {}
+func (noopMetrics) Request(string, time.Duration, error) {}
@@ -0,0 +1,82 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// outcomeReportTimeout bounds the report of a call's outcome, sent in the
+// background
+const outcomeReportTimeout = 5 * time.Second
+
+// ReportOutcome reports the outcome of a call to action of the provider
+// serviceID to the broker, which ranks providers that keep failing last:
+// callErr is the error the call returned. Only failures of the provider
+// count, e.g. Unavailable, DeadlineExceeded or Internal; calls the provider
+// refused for the consumer's fault, such as InvalidArgument, and cancelled
+// ones are not reported. Unary intent requests sent on a connection from
+// ProviderConn are reported automatically; call it for streams and
+// providers reached otherwise. It fails with ErrUnsupported when the broker
+// does not rank by outcomes.
+func (r *IntentRuntime) ReportOutcome(ctx context.Context, serviceID, action string, callErr error) error {
+	if err := r.ready(); err != nil {
+		return err
+	}
+	if callErr != nil && !providerFault(callErr) {
+		return nil
+	}
+	// Consumers that never registered have not negotiated features, so
+	// they find out from the broker's answer
+	if protocol := r.BrokerProtocol(); r.providers.noOutcomes.Load() || protocol.Version > 0 && !protocol.Supports(broker.FeatureOutcomes) {
+		return r.require(broker.FeatureOutcomes)
+	}
+	ctx, cancel := r.bind(ctx)
+	defer cancel()
+	err := r.client.ReportOutcome(ctx, serviceID, action, callErr == nil)
+	if errors.Is(err, ErrUnsupported) {
+		r.providers.noOutcomes.Store(true)
+	}
+	return err
+}
+
+// providerFault reports whether a call failed for a reason of the
+// provider rather than of the consumer's request
+func providerFault(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown, codes.DataLoss:
+		return true
+	}
+	return false
+}
+
+// outcomeConn is a provider connection reporting the outcomes of the unary
+// intent requests sent on it
+type outcomeConn struct {
+	grpc.ClientConnInterface
+	runtime   *IntentRuntime
+	serviceID string
+}
+
+func (c outcomeConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	err := c.ClientConnInterface.Invoke(ctx, method, args, reply, opts...)
+	if action := intentAction(args); action != "" {
+		go c.runtime.reportOutcome(c.serviceID, action, err)
+	}
+	return err
+}
+
+// reportOutcome is ReportOutcome in the background, logging failures
+func (r *IntentRuntime) reportOutcome(serviceID, action string, callErr error) {
+	ctx, cancel := context.WithTimeout(r.ctx, outcomeReportTimeout)
+	defer cancel()
+	if err := r.ReportOutcome(ctx, serviceID, action, callErr); err != nil && !errors.Is(err, ErrUnsupported) {
+		r.opts.log(logging.Matcher).Debug("outcome report failed", "service_id", serviceID, "action", action, "error", err)
+	}
+}
@@ -0,0 +1,77 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// failingConn answers every call with err
+type failingConn struct {
+	grpc.ClientConnInterface
+	err error
+}
+
+func (c failingConn) Invoke(context.Context, string, interface{}, interface{}, ...grpc.CallOption) error {
+	return c.err
+}
+
+func TestProviderConnReportsOutcomes(t *testing.T) {
+	b := broker.NewEmbedded()
+	defer b.Close()
+	ctx := context.Background()
+	provider := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...))
+	defer provider.Close()
+	if err := provider.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	serviceID, err := provider.RegisterFromBytes(ctx, []byte(leaseContract))
+	if err != nil {
+		t.Fatalf("RegisterFromBytes() error = %v", err)
+	}
+	const action = "translate_text"
+
+	callErr := status.Error(codes.Unavailable, "overloaded")
+	consumer := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...),
+		WithProviderDialer(func(ctx context.Context, serviceID string) (grpc.ClientConnInterface, error) {
+			return failingConn{err: callErr}, nil
+		}))
+	defer consumer.Close()
+	if err := consumer.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	cc, err := consumer.ProviderConn(ctx, serviceID)
+	if err != nil {
+		t.Fatalf("ProviderConn() error = %v", err)
+	}
+	req := &nfa_intent_v1alpha.IntentRequest{Action: action}
+	for i := 0; i < 4; i++ {
+		if err := cc.Invoke(ctx, "/test.Provider/Handle", req, &nfa_intent_v1alpha.IntentRequest{}); err != callErr {
+			t.Fatalf("Invoke() error = %v, want the provider's", err)
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for b.SuccessRate(serviceID, action) > 0.4 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if rate := b.SuccessRate(serviceID, action); rate > 0.4 {
+		t.Errorf("success rate after 4 failed calls = %v, want 2/6 with the prior", rate)
+	}
+
+	// Refusals of the consumer's request are not the provider's fault
+	if err := consumer.ReportOutcome(ctx, serviceID, action, status.Error(codes.InvalidArgument, "bad")); err != nil {
+		t.Errorf("ReportOutcome() of a refused request error = %v", err)
+	}
+	if err := consumer.ReportOutcome(ctx, serviceID, action, nil); err != nil {
+		t.Errorf("ReportOutcome() of a success error = %v", err)
+	}
+	if rate := b.SuccessRate(serviceID, action); rate < 0.4 || rate > 0.5 {
+		t.Errorf("success rate after another success = %v, want 3/7", rate)
+	}
+}
@@ -0,0 +1,280 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/interactivity"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/runtime/flags"
+	"google.golang.org/grpc"
+)
+
+const (
+	// sequenceGap is the longest pause between two resolutions that still
+	// counts as the application invoking one action after the other
+	sequenceGap = 30 * time.Second
+	// minObservations is how often an action must have been followed by
+	// another before its successors are predicted
+	minObservations = 5
+	// prefetchTTL is how long a prefetched or cached resolution is used
+	prefetchTTL = 10 * time.Second
+	// prefetchTimeout bounds the resolution and dialing of a prediction
+	prefetchTimeout = 5 * time.Second
+)
+
+// intentKey identifies what an application resolves. The class is part of
+// it since the broker orders providers by it.
+type intentKey struct {
+	action string
+	mode   contract.StreamingMode
+	class  interactivity.Class
+}
+
+type resolution struct {
+	serviceIDs []string
+	at         time.Time
+}
+
+// providers holds the resolutions and provider connections of a consumer
+// runtime, and the usage statistics that predict its next resolution
+type providers struct {
+	mu       sync.Mutex
+	conns    map[string]grpc.ClientConnInterface
+	resolved map[intentKey]resolution // prefetched or cached
+
+	last   intentKey
+	lastAt time.Time
+	next   map[intentKey]map[intentKey]int // counts of each successor
+	totals map[intentKey]int
+
+	aliases map[string]bool // deprecated actions already warned about
+
+	// noOutcomes is set once the broker refused an outcome report
+	noOutcomes atomic.Bool
+}
+
+func newProviders() *providers {
+	return &providers{
+		conns:    make(map[string]grpc.ClientConnInterface),
+		resolved: make(map[intentKey]resolution),
+		next:     make(map[intentKey]map[intentKey]int),
+		totals:   make(map[intentKey]int),
+		aliases:  make(map[string]bool),
+	}
+}
+
+// deprecated warns once when the broker resolved action as an alias of the
+// action current
+func (r *IntentRuntime) deprecated(action, current string) {
+	if current == "" || current == action {
+		return
+	}
+	r.providers.mu.Lock()
+	warned := r.providers.aliases[action]
+	r.providers.aliases[action] = true
+	r.providers.mu.Unlock()
+	if !warned {
+		r.opts.log(logging.Matcher).Warn("action is a deprecated alias, use its current name",
+			"action", action, "current", current)
+	}
+}
+
+// Resolve returns the IDs of the services serving action in the given
+// streaming mode. With WithPrefetch, intents predicted to follow are
+// resolved in the background, and a prefetched resolution is returned
+// without a broker round trip. With the flags.LocalResolutionCache flag
+// enabled for action, resolutions are also kept and reused for 10 seconds.
+// Tag ctx with package interactivity to have the
+// broker order the services for interactive or background use.
+func (r *IntentRuntime) Resolve(ctx context.Context, action string, mode contract.StreamingMode) ([]string, error) {
+	if err := r.ready(); err != nil {
+		return nil, err
+	}
+	key := intentKey{action: action, mode: mode, class: interactivity.Outgoing(ctx)}
+	now := r.opts.clock.Now()
+	cache := r.flags.EnabledFor(flags.LocalResolutionCache, action)
+	serviceIDs, ok := r.providers.observe(key, now, r.opts.prefetchThreshold > 0, cache)
+	if !ok {
+		ctx, cancel := r.bind(ctx)
+		defer cancel()
+		ctx = broker.WithConsumer(ctx, r.opts.consumer)
+		var current string
+		var err error
+		if serviceIDs, current, err = r.client.MatchAction(ctx, action, mode); err != nil {
+			return nil, err
+		}
+		r.deprecated(action, current)
+		if cache {
+			r.providers.store(key, serviceIDs, now)
+		}
+	}
+	if r.opts.prefetchThreshold > 0 {
+		for _, next := range r.providers.predict(key, r.opts.prefetchThreshold) {
+			go r.prefetch(next)
+		}
+	}
+	return serviceIDs, nil
+}
+
+// ProviderConn returns a connection to the provider serviceID: a prefetched
+// or earlier connection, an in-process connection when the provider runs in
+// this process, or a new one from the WithProviderDialer dialer. The runtime
+// keeps the connection and closes it on Close. The outcomes of unary intent
+// requests sent on it are reported to the broker, see ReportOutcome.
+func (r *IntentRuntime) ProviderConn(ctx context.Context, serviceID string) (grpc.ClientConnInterface, error) {
+	if r.ctx.Err() != nil {
+		return nil, ErrNotConnected
+	}
+	ctx, cancel := r.bind(ctx)
+	defer cancel()
+	cc, err := r.providers.conn(ctx, serviceID, r.opts.dialer)
+	if err != nil {
+		return nil, err
+	}
+	if r.opts.tracer != nil {
+		cc = tracedConn{ClientConnInterface: cc, opts: &r.opts}
+	}
+	return outcomeConn{ClientConnInterface: cc, runtime: r, serviceID: serviceID}, nil
+}
+
+// prefetch resolves a predicted intent and dials its providers
+func (r *IntentRuntime) prefetch(key intentKey) {
+	ctx, cancel := context.WithTimeout(r.ctx, prefetchTimeout)
+	defer cancel()
+	ctx = broker.WithConsumer(interactivity.With(ctx, key.class), r.opts.consumer)
+	serviceIDs, current, err := r.client.MatchAction(ctx, key.action, key.mode)
+	if err != nil {
+		r.opts.log(logging.Matcher).Debug("prefetch failed", "action", key.action, "error", err)
+		return
+	}
+	r.deprecated(key.action, current)
+	r.providers.store(key, serviceIDs, r.opts.clock.Now())
+	if r.opts.dialer == nil {
+		return
+	}
+	for _, id := range serviceIDs {
+		if _, err := r.providers.conn(ctx, id, r.opts.dialer); err != nil {
+			r.opts.log(logging.Matcher).Debug("prefetch failed", "service_id", id, "error", err)
+		}
+	}
+}
+
+// observe records a resolution of key at now and returns its prefetched or
+// cached result, if fresh. Statistics are only kept when learn is set, and
+// the result is kept for later resolutions when cache is.
+func (p *providers) observe(key intentKey, now time.Time, learn, cache bool) ([]string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if learn {
+		if !p.lastAt.IsZero() && now.Sub(p.lastAt) <= sequenceGap {
+			if p.next[p.last] == nil {
+				p.next[p.last] = make(map[intentKey]int)
+			}
+			p.next[p.last][key]++
+			p.totals[p.last]++
+		}
+		p.last, p.lastAt = key, now
+	}
+	res, ok := p.resolved[key]
+	if !ok {
+		return nil, false
+	}
+	if now.Sub(res.at) > prefetchTTL {
+		delete(p.resolved, key)
+		return nil, false
+	}
+	if !cache {
+		delete(p.resolved, key)
+	}
+	return res.serviceIDs, true
+}
+
+// predict returns the intents that followed key at least threshold of the
+// time and are not prefetched yet
+func (p *providers) predict(key intentKey, threshold float64) []intentKey {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	total := p.totals[key]
+	if total < minObservations {
+		return nil
+	}
+	var likely []intentKey
+	for next, count := range p.next[key] {
+		if _, prefetched := p.resolved[next]; prefetched {
+			continue
+		}
+		if float64(count)/float64(total) >= threshold {
+			likely = append(likely, next)
+		}
+	}
+	return likely
+}
+
+func (p *providers) store(key intentKey, serviceIDs []string, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resolved[key] = resolution{serviceIDs: serviceIDs, at: now}
+}
+
+func (p *providers) conn(ctx context.Context, serviceID string, dial Dialer) (grpc.ClientConnInterface, error) {
+	p.mu.Lock()
+	cc, ok := p.conns[serviceID]
+	p.mu.Unlock()
+	if ok {
+		return cc, nil
+	}
+	if cc, ok := LocalConn(serviceID); ok {
+		return cc, nil
+	}
+	if dial == nil {
+		return nil, fmt.Errorf("failed to connect to provider %s: %w", serviceID, ErrNoDialer)
+	}
+	cc, err := dial(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to provider %s: %w", serviceID, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if existing, ok := p.conns[serviceID]; ok {
+		// Dialed concurrently, e.g. by a prefetch
+		closeConn(cc)
+		return existing, nil
+	}
+	p.conns[serviceID] = cc
+	return cc, nil
+}
+
+// evict closes and forgets the connection to serviceID, e.g. once the
+// provider became unreachable on it, so the next call dials it anew
+func (p *providers) evict(serviceID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if cc, ok := p.conns[serviceID]; ok {
+		closeConn(cc)
+		delete(p.conns, serviceID)
+	}
+}
+
+// close closes every provider connection
+func (p *providers) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id, cc := range p.conns {
+		closeConn(cc)
+		delete(p.conns, id)
+	}
+}
+
+func closeConn(cc grpc.ClientConnInterface) {
+	if c, ok := cc.(io.Closer); ok {
+		c.Close()
+	}
+}
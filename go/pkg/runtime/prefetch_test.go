@@ -0,0 +1,44 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/runtime/flags"
+)
+
+func TestResolveWithLocalResolutionCache(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		b := broker.NewEmbedded()
+		ctx := context.Background()
+		provider := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...))
+		if err := provider.Connect(); err != nil {
+			t.Fatalf("Connect() error = %v", err)
+		}
+		serviceID, err := provider.RegisterFromBytes(ctx, []byte(leaseContract))
+		if err != nil {
+			t.Fatalf("RegisterFromBytes() error = %v", err)
+		}
+
+		consumer := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...))
+		consumer.Flags().SetStatic([]flags.Flag{{Name: flags.LocalResolutionCache, Enabled: enabled}})
+		if err := consumer.Connect(); err != nil {
+			t.Fatalf("Connect() error = %v", err)
+		}
+		if ids, err := consumer.Resolve(ctx, "translate_text", contract.StreamingUnary); err != nil || len(ids) != 1 || ids[0] != serviceID {
+			t.Fatalf("Resolve() = %v, %v, want %s", ids, err, serviceID)
+		}
+
+		// Only a cached resolution outlives the provider
+		b.Remove(serviceID)
+		ids, _ := consumer.Resolve(ctx, "translate_text", contract.StreamingUnary)
+		if cached := len(ids) == 1 && ids[0] == serviceID; cached != enabled {
+			t.Errorf("Resolve() with the cache enabled %v = %v after the provider left, want it cached %v", enabled, ids, enabled)
+		}
+		consumer.Close()
+		provider.Close()
+		b.Close()
+	}
+}
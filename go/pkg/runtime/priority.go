@@ -0,0 +1,145 @@
+package runtime
+
+import (
+	"context"
+	"sync"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/interactivity"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// WithConcurrencyLimit makes an intent server run at most n requests at a
+// time. Further requests wait in a queue ordered by their interactivity
+// class, see package interactivity: interactive requests first, background
+// requests last, each class in arrival order. A request whose context ends
+// while it waits fails without running.
+func WithConcurrencyLimit(n int) Option {
+	return func(o *options) {
+		o.concurrencyLimit = n
+	}
+}
+
+// requestQueue admits requests up to a limit, queueing the rest by class
+type requestQueue struct {
+	mu      sync.Mutex
+	limit   int
+	running int
+	waiting [3][]chan struct{} // by urgency
+}
+
+func newRequestQueue(limit int) *requestQueue {
+	return &requestQueue{limit: limit}
+}
+
+// acquire waits until a request of class c may run. It fails when ctx ends
+// first.
+func (q *requestQueue) acquire(ctx context.Context, c interactivity.Class) error {
+	q.mu.Lock()
+	if q.running < q.limit {
+		q.running++
+		q.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	urgency := c.Urgency()
+	q.waiting[urgency] = append(q.waiting[urgency], ready)
+	q.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, w := range q.waiting[urgency] {
+		if w == ready {
+			q.waiting[urgency] = append(q.waiting[urgency][:i], q.waiting[urgency][i+1:]...)
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+	// Admitted while giving up: pass the slot on
+	q.releaseLocked()
+	return status.FromContextError(ctx.Err()).Err()
+}
+
+// release ends a running request, admitting the most urgent waiting one
+func (q *requestQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.releaseLocked()
+}
+
+// releaseLocked hands the slot of a finished request to the most urgent
+// waiting one, unless the limit was lowered below the running requests
+func (q *requestQueue) releaseLocked() {
+	if q.running > q.limit || !q.admitLocked() {
+		q.running--
+	}
+}
+
+// admitLocked lets the most urgent waiting request run, if any
+func (q *requestQueue) admitLocked() bool {
+	for urgency, waiting := range q.waiting {
+		if len(waiting) > 0 {
+			close(waiting[0])
+			q.waiting[urgency] = waiting[1:]
+			return true
+		}
+	}
+	return false
+}
+
+// setLimit changes how many requests run at once. Raising it admits waiting
+// requests right away; lowering it lets running ones finish.
+func (q *requestQueue) setLimit(limit int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.limit = limit
+	for q.running < q.limit && q.admitLocked() {
+		q.running++
+	}
+}
+
+// load returns the current limit, and how many requests run or wait
+func (q *requestQueue) load() (limit, inFlight int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	inFlight = q.running
+	for _, waiting := range q.waiting {
+		inFlight += len(waiting)
+	}
+	return q.limit, inFlight
+}
+
+// queued returns how many requests wait for a slot
+func (q *requestQueue) queued() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := 0
+	for _, waiting := range q.waiting {
+		n += len(waiting)
+	}
+	return n
+}
+
+// unaryQueue runs unary requests within the concurrency limit
+func (s *IntentServer) unaryQueue(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.queue.acquire(ctx, interactivity.Incoming(ctx)); err != nil {
+		return nil, err
+	}
+	defer s.queue.release()
+	return handler(ctx, req)
+}
+
+// streamQueue runs streaming requests within the concurrency limit, each
+// holding its slot until the stream ends
+func (s *IntentServer) streamQueue(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := stream.Context()
+	if err := s.queue.acquire(ctx, interactivity.Incoming(ctx)); err != nil {
+		return err
+	}
+	defer s.queue.release()
+	return handler(srv, stream)
+}
@@ -0,0 +1,79 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/interactivity"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequestQueueServesInteractiveFirst(t *testing.T) {
+	q := newRequestQueue(1)
+	ctx := context.Background()
+	if err := q.acquire(ctx, interactivity.Background); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	// Queue one request of each class, least urgent first
+	order := make(chan interactivity.Class, 3)
+	for _, class := range []interactivity.Class{interactivity.Background, interactivity.Unspecified, interactivity.Interactive} {
+		class := class
+		go func() {
+			if err := q.acquire(ctx, class); err != nil {
+				t.Errorf("acquire(%v) error = %v", class, err)
+				return
+			}
+			order <- class
+			q.release()
+		}()
+		waitQueued(t, q, class)
+	}
+
+	q.release()
+	for _, want := range []interactivity.Class{interactivity.Interactive, interactivity.Unspecified, interactivity.Background} {
+		if got := <-order; got != want {
+			t.Errorf("served %v, want %v", got, want)
+		}
+	}
+}
+
+func TestRequestQueueCancelledWhileWaiting(t *testing.T) {
+	q := newRequestQueue(1)
+	if err := q.acquire(context.Background(), interactivity.Unspecified); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := q.acquire(ctx, interactivity.Interactive)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("acquire() error = %v, want DeadlineExceeded", err)
+	}
+
+	// The cancelled request gave up its place in the queue
+	q.release()
+	if err := q.acquire(context.Background(), interactivity.Background); err != nil {
+		t.Errorf("acquire() after release error = %v", err)
+	}
+	if q.running != 1 || len(q.waiting[interactivity.Interactive.Urgency()]) != 0 {
+		t.Errorf("running = %d, waiting = %v, want one running and none waiting", q.running, q.waiting)
+	}
+}
+
+// waitQueued waits until a request of class waits in q
+func waitQueued(t *testing.T, q *requestQueue, class interactivity.Class) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		q.mu.Lock()
+		n := len(q.waiting[class.Urgency()])
+		q.mu.Unlock()
+		if n > 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("%v request not queued", class)
+}
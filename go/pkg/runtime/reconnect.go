@@ -0,0 +1,222 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	"google.golang.org/grpc/connectivity"
+)
+
+const (
+	// reconnectMinBackoff is the first delay between recovery attempts
+	reconnectMinBackoff = time.Second
+	// reconnectMaxBackoff caps the delay between recovery attempts
+	reconnectMaxBackoff = 30 * time.Second
+)
+
+// OnReconnect registers a callback invoked when the supervisor has restored
+// the connection to the broker after losing it
+func (r *IntentRuntime) OnReconnect(fn func()) {
+	r.reconnectHandlers = append(r.reconnectHandlers, fn)
+}
+
+// OnReRegister registers a callback invoked with the service ID when the
+// supervisor has registered a contract again with a broker that lost it,
+// e.g. after a restart, once per contract. Without a stable instance key the
+// ID is new.
+func (r *IntentRuntime) OnReRegister(fn func(serviceID string)) {
+	r.reRegisterHandlers = append(r.reRegisterHandlers, fn)
+}
+
+// StartSupervisor keeps the runtime registered across broker restarts. When
+// the connection to the broker is lost, or a heartbeat finds the service
+// unknown to the broker, it reconnects with exponential backoff and registers
+// again every contract whose service the broker no longer knows. A service
+// revoked by the broker or deregistered is not registered
+// again. It blocks until ctx is cancelled, the runtime is closed or Shutdown
+// begins.
+func (r *IntentRuntime) StartSupervisor(ctx context.Context) {
+	if r.ready() != nil {
+		return
+	}
+	ctx, cancel := r.bindLoop(ctx)
+	defer cancel()
+
+	for r.awaitLoss(ctx) {
+		if state := r.conn.GetState(); state != connectivity.Ready {
+			if goAway := r.goAway.Load(); goAway != nil {
+				r.opts.log(logging.Health).Info("broker went away", "state", state, "reason", goAway.Reason)
+				if !r.awaitBack(ctx, goAway) {
+					return
+				}
+			} else {
+				r.opts.log(logging.Health).Warn("connection to broker lost", "state", state)
+			}
+			if !r.reconnect(ctx) {
+				return
+			}
+			r.goAway.Store(nil)
+			r.opts.log(logging.Health).Info("reconnected to broker")
+			for _, fn := range r.reconnectHandlers {
+				fn()
+			}
+		}
+		if !r.reRegister(ctx) {
+			return
+		}
+	}
+}
+
+// watchConnection reports every state of the broker connection to m until
+// the runtime is closed, and then reports it shut down
+func (r *IntentRuntime) watchConnection(m ConnectionMetrics) {
+	state := r.conn.GetState()
+	m.BrokerConnection(state)
+	for r.conn.WaitForStateChange(r.ctx, state) {
+		state = r.conn.GetState()
+		m.BrokerConnection(state)
+	}
+	if state != connectivity.Shutdown {
+		m.BrokerConnection(connectivity.Shutdown)
+	}
+}
+
+// awaitLoss blocks until the broker connection leaves the ready state or a
+// heartbeat finds the service unregistered, and reports false when ctx ends
+func (r *IntentRuntime) awaitLoss(ctx context.Context) bool {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	lost := make(chan bool, 1)
+	go func() {
+		for state := r.conn.GetState(); state == connectivity.Ready; state = r.conn.GetState() {
+			if !r.conn.WaitForStateChange(ctx, state) {
+				lost <- false
+				return
+			}
+		}
+		lost <- true
+	}()
+	select {
+	case ok := <-lost:
+		return ok
+	case <-r.unregistered:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// reconnect asks the connection to reconnect until it is ready, waiting
+// between attempts, and reports false when ctx ends first
+func (r *IntentRuntime) reconnect(ctx context.Context) bool {
+	for delay := reconnectMinBackoff; ; delay = min(2*delay, reconnectMaxBackoff) {
+		r.conn.ResetConnectBackoff()
+		r.conn.Connect()
+		if r.awaitReady(ctx, jitter(delay)) {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		r.opts.log(logging.Health).Debug("broker still unreachable", "state", r.conn.GetState())
+	}
+}
+
+// awaitBack waits until a broker that went away expects to be back, and
+// reports false when ctx ends first
+func (r *IntentRuntime) awaitBack(ctx context.Context, goAway *nfa_control_v1alpha.GoAway) bool {
+	if goAway.ExpectedBackUnix <= 0 {
+		return true
+	}
+	d := time.Unix(goAway.ExpectedBackUnix, 0).Sub(r.opts.clock.Now())
+	if d <= 0 {
+		return true
+	}
+	select {
+	case <-r.opts.clock.After(d):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// awaitReady waits up to d for the connection to become ready
+func (r *IntentRuntime) awaitReady(ctx context.Context, d time.Duration) bool {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-r.opts.clock.After(d):
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	for state := r.conn.GetState(); state != connectivity.Ready; state = r.conn.GetState() {
+		if !r.conn.WaitForStateChange(ctx, state) {
+			return false
+		}
+	}
+	return true
+}
+
+// reRegister registers again every contract whose service the broker no
+// longer knows, retrying with backoff. Deregistered and revoked services are
+// skipped. It reports false when ctx ends first.
+func (r *IntentRuntime) reRegister(ctx context.Context) bool {
+	for _, reg := range r.registered() {
+		if !r.reRegisterOne(ctx, reg) {
+			return false
+		}
+	}
+	return true
+}
+
+// reRegisterOne registers the contract of reg again, unless a heartbeat shows
+// the broker still knows its service
+func (r *IntentRuntime) reRegisterOne(ctx context.Context, reg registration) bool {
+	err := r.sendHeartbeat(ctx, reg.serviceID)
+	if err == nil {
+		return true
+	}
+	r.opts.log(logging.Health).Info("registration lost, registering again", "service_id", reg.serviceID, "error", err)
+	for delay := reconnectMinBackoff; ; delay = min(2*delay, reconnectMaxBackoff) {
+		serviceID, err := r.register(ctx, reg.contract, reg.contractPath)
+		if err == nil {
+			for _, fn := range r.reRegisterHandlers {
+				fn(serviceID)
+			}
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		r.opts.log(logging.Health).Warn("re-registration failed", "contract", reg.contract.Metadata.Name, "error", err)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-r.opts.clock.After(jitter(delay)):
+		}
+	}
+}
+
+// notifyUnregistered wakes the supervisor when a heartbeat shows the broker
+// no longer knows the service
+func (r *IntentRuntime) notifyUnregistered(err error) {
+	if !errors.Is(err, ErrNotRegistered) {
+		return
+	}
+	select {
+	case r.unregistered <- struct{}{}:
+	default:
+	}
+}
+
+// jitter spreads d over [d/2, d), so runtimes reconnecting after the same
+// broker restart do not retry in lockstep
+func jitter(d time.Duration) time.Duration {
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)))
+}
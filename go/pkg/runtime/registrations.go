@@ -0,0 +1,97 @@
+package runtime
+
+import (
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+)
+
+// registration is a contract registered by the runtime and the service ID
+// the broker assigned to it
+type registration struct {
+	// serviceID is empty once the service is deregistered or revoked
+	serviceID string
+	contract  *contract.IntentContract
+	// contractPath is set when the contract was registered from a file,
+	// which is read again when the broker asks for re-registration
+	contractPath string
+}
+
+// ServiceIDs returns the service IDs of the contracts registered by the
+// runtime, in registration order. Deregistered and revoked services are left
+// out.
+func (r *IntentRuntime) ServiceIDs() []string {
+	var ids []string
+	for _, reg := range r.registered() {
+		ids = append(ids, reg.serviceID)
+	}
+	return ids
+}
+
+// record stores the registration of c under serviceID. Registering a
+// contract with the name of a registered one replaces that registration; the
+// service ID it held is returned.
+func (r *IntentRuntime) record(c *contract.IntentContract, contractPath, serviceID string) (replaced string) {
+	r.regMu.Lock()
+	defer r.regMu.Unlock()
+	reg := registration{serviceID: serviceID, contract: c, contractPath: contractPath}
+	for i, existing := range r.registrations {
+		if existing.contract.Metadata.Name == c.Metadata.Name {
+			if contractPath == "" {
+				reg.contractPath = existing.contractPath
+			}
+			r.registrations[i] = reg
+			if existing.serviceID != "" && existing.serviceID != serviceID {
+				r.dropLease(existing.serviceID)
+			}
+			return existing.serviceID
+		}
+	}
+	r.registrations = append(r.registrations, reg)
+	return ""
+}
+
+// registered returns the registrations whose service is registered
+func (r *IntentRuntime) registered() []registration {
+	r.regMu.Lock()
+	defer r.regMu.Unlock()
+	var regs []registration
+	for _, reg := range r.registrations {
+		if reg.serviceID != "" {
+			regs = append(regs, reg)
+		}
+	}
+	return regs
+}
+
+// contracts returns every registered contract, including those of
+// deregistered or revoked services
+func (r *IntentRuntime) contracts() []registration {
+	r.regMu.Lock()
+	defer r.regMu.Unlock()
+	return append([]registration(nil), r.registrations...)
+}
+
+// forget clears the service ID of the registration holding serviceID, or of
+// every registration when serviceID is empty, so it is no longer sent
+// heartbeats or registered again, and reports whether one was cleared
+func (r *IntentRuntime) forget(serviceID string) bool {
+	r.regMu.Lock()
+	defer r.regMu.Unlock()
+	found := false
+	for i, reg := range r.registrations {
+		if reg.serviceID != "" && (serviceID == "" || reg.serviceID == serviceID) {
+			r.registrations[i].serviceID = ""
+			found = true
+		}
+	}
+	r.dropLease(serviceID)
+	return found
+}
+
+// primaryServiceID returns the service ID of the first registered contract,
+// which identifies the runtime where a single ID is needed
+func (r *IntentRuntime) primaryServiceID() string {
+	if regs := r.registered(); len(regs) > 0 {
+		return regs[0].serviceID
+	}
+	return ""
+}
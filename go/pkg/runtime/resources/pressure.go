@@ -0,0 +1,34 @@
+package resources
+
+// Pressure is how loaded the device is, each part from 0 (idle) to 1
+// (saturated). Parts that cannot be measured on a platform are 0.
+type Pressure struct {
+	CPU    float64
+	Memory float64
+}
+
+// Max returns the higher of the CPU and memory pressure
+func (p Pressure) Max() float64 {
+	return max(p.CPU, p.Memory)
+}
+
+// MeasurePressure measures the current load on the device's CPUs and memory
+func MeasurePressure() (Pressure, error) {
+	cpu, err := cpuPressure()
+	if err != nil {
+		return Pressure{}, err
+	}
+	info := &Info{}
+	if err := detectMemory(info); err != nil {
+		return Pressure{}, err
+	}
+	return Pressure{CPU: cpu, Memory: memoryPressure(info)}, nil
+}
+
+// memoryPressure is the share of memory not available to new allocations
+func memoryPressure(info *Info) float64 {
+	if info.MemoryTotal == 0 || info.MemoryAvailable > info.MemoryTotal {
+		return 0
+	}
+	return 1 - float64(info.MemoryAvailable)/float64(info.MemoryTotal)
+}
@@ -0,0 +1,64 @@
+// Package resources detects the compute resources of the device a runtime
+// runs on (CPU, memory and NPUs) so they can be reported to the broker.
+// Detection is implemented per platform; where a resource cannot be
+// detected it is reported as zero or empty rather than failing.
+package resources
+
+import (
+	"runtime"
+	"strconv"
+)
+
+// Labels added to a runtime's control stream hello, so the broker can target
+// runtimes by platform
+const (
+	LabelOS   = "nfa.os"
+	LabelArch = "nfa.arch"
+	LabelNPU  = "nfa.npu"
+)
+
+// Info describes the resources of the local device
+type Info struct {
+	OS   string
+	Arch string
+	CPUs int
+	// MemoryTotal and MemoryAvailable are in bytes; zero when unknown
+	MemoryTotal     uint64
+	MemoryAvailable uint64
+	NPUs            []NPU
+}
+
+// NPU is a neural processing unit or other inference accelerator
+type NPU struct {
+	Vendor string
+	Model  string
+	// Path is the device node or other OS handle of the accelerator
+	Path string
+}
+
+// Detect reports the resources of the local device
+func Detect() (*Info, error) {
+	info := &Info{
+		OS:   runtime.GOOS,
+		Arch: runtime.GOARCH,
+		CPUs: runtime.NumCPU(),
+	}
+	if err := detectMemory(info); err != nil {
+		return nil, err
+	}
+	npus, err := detectNPUs()
+	if err != nil {
+		return nil, err
+	}
+	info.NPUs = npus
+	return info, nil
+}
+
+// Labels returns the platform labels of the device
+func (i *Info) Labels() map[string]string {
+	return map[string]string{
+		LabelOS:   i.OS,
+		LabelArch: i.Arch,
+		LabelNPU:  strconv.FormatBool(len(i.NPUs) > 0),
+	}
+}
@@ -0,0 +1,159 @@
+package resources
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// npuDevices maps device node patterns to the accelerators that create them
+var npuDevices = []struct {
+	pattern string
+	vendor  string
+	model   string
+}{
+	{"/dev/apex_*", "Google", "Edge TPU"},
+	{"/dev/hailo[0-9]*", "Hailo", "Hailo"},
+	{"/dev/davinci[0-9]*", "Huawei", "Ascend"},
+	{"/dev/accel/accel[0-9]*", "", "Compute accelerator"},
+}
+
+func detectMemory(info *Info) error {
+	return detectMemoryFrom("/", info)
+}
+
+func detectNPUs() ([]NPU, error) {
+	return detectNPUsFrom("/")
+}
+
+func cpuPressure() (float64, error) {
+	return cpuPressureFrom("/", runtime.NumCPU())
+}
+
+// cpuPressureFrom reads the share of the last 10 seconds runnable tasks
+// waited for a CPU from /proc/pressure/cpu below root. Kernels without
+// pressure stall information report the 1 minute load average per CPU from
+// /proc/loadavg instead, capped at 1.
+func cpuPressureFrom(root string, cpus int) (float64, error) {
+	if data, err := os.ReadFile(filepath.Join(root, "proc", "pressure", "cpu")); err == nil {
+		return parseCPUPressure(string(data))
+	}
+	data, err := os.ReadFile(filepath.Join(root, "proc", "loadavg"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read CPU load: %w", err)
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("malformed loadavg %q", data)
+	}
+	load, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed loadavg %q: %w", data, err)
+	}
+	return min(load/float64(max(cpus, 1)), 1), nil
+}
+
+// parseCPUPressure returns the "some avg10" percentage of pressure stall
+// information as a fraction
+func parseCPUPressure(psi string) (float64, error) {
+	for _, line := range strings.Split(psi, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != "some" {
+			continue
+		}
+		for _, field := range fields[1:] {
+			if value, ok := strings.CutPrefix(field, "avg10="); ok {
+				percent, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return 0, fmt.Errorf("malformed CPU pressure %q: %w", line, err)
+				}
+				return min(percent/100, 1), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("CPU pressure has no some avg10")
+}
+
+// detectMemoryFrom reads /proc/meminfo below root
+func detectMemoryFrom(root string, info *Info) error {
+	f, err := os.Open(filepath.Join(root, "proc", "meminfo"))
+	if err != nil {
+		return fmt.Errorf("failed to read memory info: %w", err)
+	}
+	defer f.Close()
+
+	total, available, err := parseMeminfo(f)
+	if err != nil {
+		return err
+	}
+	info.MemoryTotal = total
+	info.MemoryAvailable = available
+	return nil
+}
+
+// parseMeminfo returns MemTotal and MemAvailable in bytes
+func parseMeminfo(r io.Reader) (uint64, uint64, error) {
+	var total, available uint64
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		var target *uint64
+		switch key {
+		case "MemTotal":
+			target = &total
+		case "MemAvailable":
+			target = &available
+		default:
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			return 0, 0, fmt.Errorf("malformed meminfo line %q", scanner.Text())
+		}
+		kb, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("malformed meminfo line %q: %w", scanner.Text(), err)
+		}
+		*target = kb * 1024
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, fmt.Errorf("failed to read memory info: %w", err)
+	}
+	if total == 0 {
+		return 0, 0, fmt.Errorf("meminfo has no MemTotal")
+	}
+	return total, available, nil
+}
+
+// detectNPUsFrom looks for accelerator device nodes below root
+func detectNPUsFrom(root string) ([]NPU, error) {
+	var npus []NPU
+	for _, dev := range npuDevices {
+		paths, err := filepath.Glob(filepath.Join(root, dev.pattern))
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan for NPUs: %w", err)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to scan for NPUs: %w", err)
+			}
+			npus = append(npus, NPU{
+				Vendor: dev.vendor,
+				Model:  dev.model,
+				Path:   "/" + filepath.ToSlash(rel),
+			})
+		}
+	}
+	return npus, nil
+}
@@ -0,0 +1,123 @@
+package resources
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const meminfo = `MemTotal:        8024380 kB
+MemFree:          512000 kB
+MemAvailable:    4012190 kB
+Buffers:          102400 kB
+`
+
+func TestParseMeminfo(t *testing.T) {
+	total, available, err := parseMeminfo(strings.NewReader(meminfo))
+	if err != nil {
+		t.Fatalf("parseMeminfo() error = %v", err)
+	}
+	if total != 8024380*1024 || available != 4012190*1024 {
+		t.Errorf("parseMeminfo() = %d, %d", total, available)
+	}
+}
+
+func TestParseMeminfoMalformed(t *testing.T) {
+	for _, input := range []string{"", "MemTotal: lots kB\n", "MemTotal:\n"} {
+		if _, _, err := parseMeminfo(strings.NewReader(input)); err == nil {
+			t.Errorf("parseMeminfo(%q) succeeded, want error", input)
+		}
+	}
+}
+
+func TestDetectMemoryFrom(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "proc", "meminfo"), meminfo)
+
+	info := &Info{}
+	if err := detectMemoryFrom(root, info); err != nil {
+		t.Fatalf("detectMemoryFrom() error = %v", err)
+	}
+	if info.MemoryTotal != 8024380*1024 {
+		t.Errorf("MemoryTotal = %d", info.MemoryTotal)
+	}
+}
+
+func TestDetectNPUsFrom(t *testing.T) {
+	root := t.TempDir()
+	for _, dev := range []string{"dev/hailo0", "dev/apex_0", "dev/davinci0", "dev/davinci_manager", "dev/accel/accel0", "dev/null"} {
+		writeFile(t, filepath.Join(root, dev), "")
+	}
+
+	npus, err := detectNPUsFrom(root)
+	if err != nil {
+		t.Fatalf("detectNPUsFrom() error = %v", err)
+	}
+	var paths []string
+	for _, npu := range npus {
+		paths = append(paths, npu.Path)
+	}
+	want := "/dev/apex_0 /dev/hailo0 /dev/davinci0 /dev/accel/accel0"
+	if got := strings.Join(paths, " "); got != want {
+		t.Errorf("NPU paths = %q, want %q", got, want)
+	}
+	if npus[1].Vendor != "Hailo" {
+		t.Errorf("vendor of %s = %q, want Hailo", npus[1].Path, npus[1].Vendor)
+	}
+}
+
+func TestDetectNPUsFromEmpty(t *testing.T) {
+	npus, err := detectNPUsFrom(t.TempDir())
+	if err != nil || len(npus) != 0 {
+		t.Errorf("detectNPUsFrom(empty) = %v, %v", npus, err)
+	}
+}
+
+func TestDetectLinuxMemory(t *testing.T) {
+	info, err := Detect()
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if info.MemoryTotal == 0 {
+		t.Error("MemoryTotal = 0, want the size of /proc/meminfo's MemTotal")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCPUPressureFrom(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "proc", "loadavg"), "6.00 3.10 2.05 3/467 12345\n")
+	if got, err := cpuPressureFrom(root, 8); err != nil || got != 0.75 {
+		t.Errorf("cpuPressureFrom() from loadavg = %v, %v, want 0.75", got, err)
+	}
+	if got, _ := cpuPressureFrom(root, 4); got != 1 {
+		t.Errorf("cpuPressureFrom() of an overloaded device = %v, want 1", got)
+	}
+
+	// Pressure stall information takes precedence over the load average
+	writeFile(t, filepath.Join(root, "proc", "pressure", "cpu"),
+		"some avg10=42.50 avg60=10.00 avg300=2.00 total=123456\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n")
+	if got, err := cpuPressureFrom(root, 8); err != nil || got != 0.425 {
+		t.Errorf("cpuPressureFrom() from PSI = %v, %v, want 0.425", got, err)
+	}
+}
+
+func TestMeasureLinuxPressure(t *testing.T) {
+	p, err := MeasurePressure()
+	if err != nil {
+		t.Fatalf("MeasurePressure() error = %v", err)
+	}
+	if p.CPU < 0 || p.CPU > 1 || p.Memory <= 0 || p.Memory > 1 {
+		t.Errorf("MeasurePressure() = %+v, want parts between 0 and 1 and some memory in use", p)
+	}
+}
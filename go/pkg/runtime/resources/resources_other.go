@@ -0,0 +1,17 @@
+//go:build !linux && !windows
+
+package resources
+
+// detectMemory leaves memory unknown on platforms without an implementation
+func detectMemory(info *Info) error {
+	return nil
+}
+
+func detectNPUs() ([]NPU, error) {
+	return nil, nil
+}
+
+// cpuPressure reports no CPU pressure on platforms without an implementation
+func cpuPressure() (float64, error) {
+	return 0, nil
+}
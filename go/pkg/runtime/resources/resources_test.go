@@ -0,0 +1,37 @@
+package resources
+
+import (
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	info, err := Detect()
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if info.OS != runtime.GOOS || info.Arch != runtime.GOARCH {
+		t.Errorf("platform = %s/%s, want %s/%s", info.OS, info.Arch, runtime.GOOS, runtime.GOARCH)
+	}
+	if info.CPUs < 1 {
+		t.Errorf("CPUs = %d, want at least 1", info.CPUs)
+	}
+	if info.MemoryAvailable > info.MemoryTotal {
+		t.Errorf("available memory %d exceeds total %d", info.MemoryAvailable, info.MemoryTotal)
+	}
+}
+
+func TestLabels(t *testing.T) {
+	info := &Info{OS: "linux", Arch: "arm64", NPUs: []NPU{{Vendor: "Hailo", Path: "/dev/hailo0"}}}
+	labels := info.Labels()
+	want := map[string]string{LabelOS: "linux", LabelArch: "arm64", LabelNPU: strconv.FormatBool(true)}
+	for key, value := range want {
+		if labels[key] != value {
+			t.Errorf("label %s = %q, want %q", key, labels[key], value)
+		}
+	}
+	if got := (&Info{}).Labels()[LabelNPU]; got != "false" {
+		t.Errorf("label %s without NPUs = %q, want \"false\"", LabelNPU, got)
+	}
+}
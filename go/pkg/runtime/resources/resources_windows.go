@@ -0,0 +1,46 @@
+package resources
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var procGlobalMemoryStatusEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GlobalMemoryStatusEx")
+
+// memoryStatusEx mirrors MEMORYSTATUSEX
+type memoryStatusEx struct {
+	length               uint32
+	memoryLoad           uint32
+	totalPhys            uint64
+	availPhys            uint64
+	totalPageFile        uint64
+	availPageFile        uint64
+	totalVirtual         uint64
+	availVirtual         uint64
+	availExtendedVirtual uint64
+}
+
+func detectMemory(info *Info) error {
+	status := memoryStatusEx{}
+	status.length = uint32(unsafe.Sizeof(status))
+	ok, _, err := procGlobalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&status)))
+	if ok == 0 {
+		return fmt.Errorf("failed to read memory info: %w", err)
+	}
+	info.MemoryTotal = status.totalPhys
+	info.MemoryAvailable = status.availPhys
+	return nil
+}
+
+// detectNPUs reports no NPUs: Windows exposes accelerators through vendor
+// runtimes (DirectML, OpenVINO) rather than device nodes the runtime can see
+func detectNPUs() ([]NPU, error) {
+	return nil, nil
+}
+
+// cpuPressure reports no CPU pressure: Windows has no load average, and the
+// processor time counters need sampling over an interval
+func cpuPressure() (float64, error) {
+	return 0, nil
+}
@@ -0,0 +1,13 @@
+package resources
+
+import "testing"
+
+func TestDetectWindowsMemory(t *testing.T) {
+	info := &Info{}
+	if err := detectMemory(info); err != nil {
+		t.Fatalf("detectMemory() error = %v", err)
+	}
+	if info.MemoryTotal == 0 || info.MemoryAvailable > info.MemoryTotal {
+		t.Errorf("memory = %d available of %d total", info.MemoryAvailable, info.MemoryTotal)
+	}
+}
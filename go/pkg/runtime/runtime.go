@@ -0,0 +1,423 @@
+// Package runtime connects intent services to the Intent Broker: it
+// registers their contracts, reports health, follows the broker's control
+// stream and hosts the gRPC server of the service implementations
+package runtime
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "sync"
+    "sync/atomic"
+    "time"
+
+    "github.com/neuro-fluidic-architecture/nfa-core/go/control"
+    "github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+    "github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+    "github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+    "github.com/neuro-fluidic-architecture/nfa-core/go/pkg/redact"
+    "github.com/neuro-fluidic-architecture/nfa-core/go/pkg/runtime/flags"
+    "github.com/neuro-fluidic-architecture/nfa-core/go/pkg/runtime/resources"
+    nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/connectivity"
+    "google.golang.org/grpc/status"
+)
+
+// IntentRuntime 负责向Intent Broker注册服务并处理意图请求
+type IntentRuntime struct {
+    brokerAddress string
+    conn          *grpc.ClientConn
+    client        *broker.Client
+    flags         *flags.Set
+    redactor      *redact.Redactor
+    opts          options
+
+    // ctx 是运行时的生命周期上下文，Close时取消，所有后台循环都随之退出
+    ctx    context.Context
+    cancel context.CancelFunc
+    // loops 是ctx的子上下文，Shutdown注销服务前取消，停止心跳和监督循环
+    loops     context.Context
+    stopLoops context.CancelFunc
+
+    heartbeatInterval atomic.Int64 // time.Duration, adjustable by the broker
+    leases            leases       // Broker授予的租约，以及心跳测得的往返时间和时钟偏差
+    configHandlers    []func(*nfa_control_v1alpha.ConfigFragment)
+
+    // registrations 按注册顺序保存已注册的契约及其服务ID，同名契约再次注册时替换
+    regMu          sync.Mutex
+    registrations  []registration
+    draining       atomic.Bool
+    drainHandlers  []func(*nfa_control_v1alpha.Drain)
+    revokeHandlers []func(*nfa_control_v1alpha.Revoke)
+    invokeHandler  func(*nfa_control_v1alpha.Invoke) ([]byte, error)
+    goAwayHandlers []func(*nfa_control_v1alpha.GoAway)
+    // goAway 是Broker关闭前发来的通知，重新连上Broker后清除
+    goAway atomic.Pointer[nfa_control_v1alpha.GoAway]
+
+    reconnectHandlers  []func()
+    reRegisterHandlers []func(serviceID string)
+    unregistered       chan struct{} // 心跳发现Broker已不知道本服务时通知监督循环
+
+    providers *providers // 作为消费者时解析到的提供者连接及预取统计
+}
+
+// defaultHeartbeatInterval 默认心跳间隔
+const defaultHeartbeatInterval = 10 * time.Second
+
+// NewIntentRuntime 创建新的运行时实例，可通过Option配置日志、TLS、指标和时钟
+func NewIntentRuntime(brokerAddress string, opts ...Option) *IntentRuntime {
+    ctx, cancel := context.WithCancel(context.Background())
+    r := &IntentRuntime{
+        brokerAddress: brokerAddress,
+        flags:         flags.NewSet(nil),
+        redactor:      redact.New(),
+        opts:          newOptions(opts),
+        ctx:           ctx,
+        cancel:        cancel,
+        providers:     newProviders(),
+        unregistered:  make(chan struct{}, 1),
+    }
+    r.opts.logAttrs = append(r.opts.logAttrs, "broker_address", brokerAddress)
+    r.loops, r.stopLoops = context.WithCancel(ctx)
+    r.heartbeatInterval.Store(int64(defaultHeartbeatInterval))
+    return r
+}
+
+// Flags 返回运行时的特性开关集合
+func (r *IntentRuntime) Flags() *flags.Set {
+    return r.flags
+}
+
+// Redactor 返回按已注册契约中参数敏感级别脱敏的Redactor，
+// 应用在写日志、追踪、审计和分析数据前应使用它处理意图参数
+func (r *IntentRuntime) Redactor() *redact.Redactor {
+    return r.redactor
+}
+
+// LoadFeatureFlags 从YAML文件加载静态特性开关
+func (r *IntentRuntime) LoadFeatureFlags(path string) error {
+    static, err := flags.LoadFile(path)
+    if err != nil {
+        return err
+    }
+    r.flags.SetStatic(static)
+    return nil
+}
+
+// Connect 连接到Intent Broker，按WithTLS、WithMTLS、WithEnrollment和WithTokenAuth配置凭据。
+// 连接在首次调用时才建立，需要确认Broker可达时使用ConnectCtx
+func (r *IntentRuntime) Connect() error {
+    dialOpts, err := r.opts.brokerDialOptions(r.ctx, r.brokerAddress)
+    if err != nil {
+        return err
+    }
+    conn, err := grpc.Dial(r.brokerAddress, dialOpts...)
+    if err != nil {
+        return fmt.Errorf("failed to connect to broker: %w", err)
+    }
+    r.conn = conn
+    r.client = broker.NewClient(conn)
+    if m, ok := r.opts.metrics.(ConnectionMetrics); ok {
+        go r.watchConnection(m)
+    }
+    return nil
+}
+
+// ConnectCtx 连接到Intent Broker并等待连接就绪，ctx结束（超时或取消）时
+// 关闭连接并返回包装ErrBrokerUnavailable的错误
+func (r *IntentRuntime) ConnectCtx(ctx context.Context) error {
+    if err := r.Connect(); err != nil {
+        return err
+    }
+    ctx, cancel := r.bind(ctx)
+    defer cancel()
+
+    r.conn.Connect()
+    for state := r.conn.GetState(); state != connectivity.Ready; state = r.conn.GetState() {
+        if !r.conn.WaitForStateChange(ctx, state) {
+            r.conn.Close()
+            r.conn, r.client = nil, nil
+            return fmt.Errorf("failed to connect to broker: %w: connection is %s: %w", ErrBrokerUnavailable, state, ctx.Err())
+        }
+    }
+    return nil
+}
+
+// RegisterFromFile 从契约文件注册意图契约，扩展名为.json时按JSON解析，否则按YAML解析。
+// 运行时关闭时注册请求随之取消
+func (r *IntentRuntime) RegisterFromFile(contractPath string) (string, error) {
+    return r.registerFromFile(r.ctx, contractPath)
+}
+
+// RegisterFromFileCtx 与RegisterFromFile相同，但注册请求还受ctx的截止时间和取消控制，
+// ctx携带的元数据（如追踪信息）随请求发送
+func (r *IntentRuntime) RegisterFromFileCtx(ctx context.Context, contractPath string) (string, error) {
+    return r.registerFromFile(ctx, contractPath)
+}
+
+func (r *IntentRuntime) registerFromFile(ctx context.Context, contractPath string) (string, error) {
+    if err := r.ready(); err != nil {
+        return "", err
+    }
+
+    // 解析并校验契约
+    intentContract, err := contract.LoadFile(contractPath)
+    if err != nil {
+        return "", err
+    }
+    if err := intentContract.Validate(); err != nil {
+        return "", fmt.Errorf("invalid contract %s: %w", contractPath, err)
+    }
+
+    return r.register(ctx, intentContract, contractPath)
+}
+
+// RegisterFromBytes 解析、校验并注册YAML（或JSON）格式的意图契约，
+// 适用于通过go:embed嵌入或启动时生成、不在磁盘上的契约
+func (r *IntentRuntime) RegisterFromBytes(ctx context.Context, data []byte) (string, error) {
+    if err := r.ready(); err != nil {
+        return "", err
+    }
+    intentContract, err := contract.ParseIntentContract(data)
+    if err != nil {
+        return "", err
+    }
+    return r.Register(ctx, intentContract)
+}
+
+// Register 校验并注册意图契约，返回Broker分配的服务ID。
+// 一个运行时可注册多个契约，每个契约有自己的服务ID；同名契约再次注册时替换原注册
+func (r *IntentRuntime) Register(ctx context.Context, intentContract *contract.IntentContract) (string, error) {
+    if err := r.ready(); err != nil {
+        return "", err
+    }
+    if err := intentContract.Validate(); err != nil {
+        return "", fmt.Errorf("invalid contract %s: %w", intentContract.Metadata.Name, err)
+    }
+    return r.register(ctx, intentContract, "")
+}
+
+// UpdateContract 以新版本替换已注册的同名契约，用于长期运行的服务提供者滚动升级。
+// 与当前版本相比有破坏性变更（删除意图模式、新增必需参数、收紧参数约束等，见contract.Diff）时
+// 不注册并返回包装ErrBreakingChange的错误，列出这些变更；force为true时仍然注册。
+// 没有同名契约时等同于Register
+func (r *IntentRuntime) UpdateContract(ctx context.Context, intentContract *contract.IntentContract, force bool) (string, error) {
+    if err := r.ready(); err != nil {
+        return "", err
+    }
+    if err := intentContract.Validate(); err != nil {
+        return "", fmt.Errorf("invalid contract %s: %w", intentContract.Metadata.Name, err)
+    }
+    for _, reg := range r.contracts() {
+        if reg.contract.Metadata.Name != intentContract.Metadata.Name {
+            continue
+        }
+        changes := contract.Diff(reg.contract, intentContract)
+        if breaking := changes.Breaking(); len(breaking) > 0 {
+            if !force {
+                return "", fmt.Errorf("%w to contract %s: %s", ErrBreakingChange, intentContract.Metadata.Name, breaking)
+            }
+            r.opts.log(logging.Registry).Warn("forcing breaking contract update", "contract", intentContract.Metadata.Name, "changes", breaking.String())
+        }
+        break
+    }
+    return r.register(ctx, intentContract, "")
+}
+
+// register 向Broker注册已校验的契约，contractPath为契约文件路径（未从文件注册时为空）
+func (r *IntentRuntime) register(ctx context.Context, intentContract *contract.IntentContract, contractPath string) (string, error) {
+    ctx, cancel := r.bind(ctx)
+    defer cancel()
+
+    serviceID, err := r.client.RegisterInstance(ctx, intentContract, r.opts.instance)
+    r.opts.metrics.Registration(intentContract.Metadata.Name, err)
+    if err != nil {
+        return "", r.opts.insecureHint(err)
+    }
+
+    // 同名契约的旧服务ID不再发送心跳，立即注销而不是等待存活超时
+    if replaced := r.record(intentContract, contractPath, serviceID); replaced != "" && replaced != serviceID {
+        if err := r.client.Unregister(ctx, replaced); err != nil {
+            r.opts.log(logging.Registry).Debug("failed to unregister replaced service", "service_id", replaced, "error", err)
+        }
+    }
+    r.redactor.Set(intentContract.Metadata.Name, redact.FromContract(intentContract))
+    r.opts.log(logging.Registry).Info("service registered", "service_id", serviceID, "contract", intentContract.Metadata.Name)
+    return serviceID, nil
+}
+
+// BrokerProtocol 返回最近一次注册时与Broker协商的协议版本和特性，注册前版本为0
+func (r *IntentRuntime) BrokerProtocol() broker.Protocol {
+    if r.client == nil {
+        return broker.Protocol{}
+    }
+    return r.client.Protocol()
+}
+
+// require 在Broker协商后未提供feature时返回包装ErrUnsupported的错误，
+// 避免调用以含义不明的Unimplemented失败
+func (r *IntentRuntime) require(feature string) error {
+    if !r.BrokerProtocol().Supports(feature) {
+        return fmt.Errorf("%w: %s", ErrUnsupported, feature)
+    }
+    return nil
+}
+
+// unsupported 将Broker未实现的调用（如协商前的旧Broker）标记为ErrUnsupported
+func unsupported(feature string, err error) error {
+    if status.Code(err) == codes.Unimplemented {
+        return fmt.Errorf("%w: %s: %w", ErrUnsupported, feature, err)
+    }
+    return err
+}
+
+// OnConfigUpdate 注册Broker下发配置片段时的回调，
+// 应用可在回调中处理路由偏好等运行时未直接管理的配置
+func (r *IntentRuntime) OnConfigUpdate(fn func(*nfa_control_v1alpha.ConfigFragment)) {
+    r.configHandlers = append(r.configHandlers, fn)
+}
+
+// StartControlStream 打开与Broker之间的控制流，应用按标签下发的配置片段，
+// 并执行Broker推送的排空、重新注册和吊销命令。
+// Broker关闭前发来GoAway时控制流结束并返回nil，监督循环等到Broker预计恢复时再重连。
+// 平台标签（nfa.os、nfa.arch、nfa.npu）会自动补充到labels中，调用方设置的同名标签优先。
+// 该方法阻塞直到控制流结束、ctx被取消或运行时关闭
+func (r *IntentRuntime) StartControlStream(ctx context.Context, labels map[string]string) error {
+    if err := r.ready(); err != nil {
+        return err
+    }
+    if err := r.require(broker.FeatureControl); err != nil {
+        return err
+    }
+    ctx, cancel := r.bind(ctx)
+    defer cancel()
+
+    runtimeID := r.primaryServiceID()
+    if runtimeID == "" {
+        hostname, err := os.Hostname()
+        if err != nil {
+            return fmt.Errorf("failed to determine runtime id: %w", err)
+        }
+        runtimeID = hostname
+    }
+
+    hello := &nfa_control_v1alpha.Hello{
+        RuntimeId: runtimeID,
+        Labels:    r.platformLabels(labels),
+    }
+    hello.ServiceIds = r.ServiceIDs()
+    err := control.NewClient(r.conn).Run(ctx, hello, control.Handlers{
+        OnConfig:     r.applyConfigFragment,
+        OnDrain:      r.handleDrain,
+        OnReRegister: r.handleReRegister,
+        OnRevoke:     r.handleRevoke,
+        OnInvoke:     r.handleInvoke,
+        OnGoAway:     r.handleGoAway,
+    })
+    return unsupported(broker.FeatureControl, err)
+}
+
+// Resources 检测并返回本机的CPU、内存和NPU资源
+func (r *IntentRuntime) Resources() (*resources.Info, error) {
+    return resources.Detect()
+}
+
+// platformLabels 返回补充了平台标签的labels副本，检测失败时只记录日志
+func (r *IntentRuntime) platformLabels(labels map[string]string) map[string]string {
+    merged := make(map[string]string, len(labels)+3)
+    info, err := r.Resources()
+    if err != nil {
+        r.opts.log(logging.Control).Warn("resource detection failed", "error", err)
+    } else {
+        for key, value := range info.Labels() {
+            merged[key] = value
+        }
+    }
+    for key, value := range labels {
+        merged[key] = value
+    }
+    return merged
+}
+
+// applyConfigFragment 应用Broker下发的配置片段
+func (r *IntentRuntime) applyConfigFragment(fragment *nfa_control_v1alpha.ConfigFragment) error {
+    if fragment.HeartbeatIntervalSecs != nil {
+        if *fragment.HeartbeatIntervalSecs == 0 {
+            return fmt.Errorf("heartbeat interval must be greater than 0")
+        }
+        r.SetHeartbeatInterval(time.Duration(*fragment.HeartbeatIntervalSecs) * time.Second)
+    }
+
+    for component, level := range fragment.LogLevels {
+        if err := logging.SetLevel(component, level); err != nil {
+            return err
+        }
+    }
+
+    if len(fragment.FeatureFlags) > 0 {
+        overrides := make([]flags.Flag, 0, len(fragment.FeatureFlags))
+        for _, f := range fragment.FeatureFlags {
+            overrides = append(overrides, flags.Flag{
+                Name:       f.Name,
+                Enabled:    f.Enabled,
+                Percentage: f.Percentage,
+            })
+        }
+        if err := r.flags.ApplyOverrides(overrides); err != nil {
+            return err
+        }
+    }
+
+    for _, fn := range r.configHandlers {
+        fn(fragment)
+    }
+    r.opts.log(logging.Control).Info("applied config fragment from broker")
+    return nil
+}
+
+// StartHealthCheck 启动健康检查循环
+func (r *IntentRuntime) StartHealthCheck() {
+    // 实现健康检查逻辑
+    // 定期向Broker报告服务状态
+}
+
+// Close 取消生命周期上下文并关闭运行时连接及提供者连接，
+// 健康报告、控制流等后台循环随之退出
+func (r *IntentRuntime) Close() error {
+    r.cancel()
+    r.providers.close()
+    if r.conn != nil {
+        return r.conn.Close()
+    }
+    return nil
+}
+
+// ready 在Connect尚未调用或运行时已关闭时返回ErrNotConnected
+func (r *IntentRuntime) ready() error {
+    if r.conn == nil || r.ctx.Err() != nil {
+        return ErrNotConnected
+    }
+    return nil
+}
+
+// bind 派生一个在ctx结束或运行时关闭时都会取消的上下文
+func (r *IntentRuntime) bind(ctx context.Context) (context.Context, context.CancelFunc) {
+    return bindTo(ctx, r.ctx)
+}
+
+// bindLoop 与bind相同，但Shutdown开始时即取消，用于心跳和监督循环
+func (r *IntentRuntime) bindLoop(ctx context.Context) (context.Context, context.CancelFunc) {
+    return bindTo(ctx, r.loops)
+}
+
+// bindTo 派生一个在ctx或parent结束时取消的上下文
+func bindTo(ctx, parent context.Context) (context.Context, context.CancelFunc) {
+    ctx, cancel := context.WithCancel(ctx)
+    stop := context.AfterFunc(parent, cancel)
+    return ctx, func() {
+        stop()
+        cancel()
+    }
+}
@@ -0,0 +1,227 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+	nfa_capabilities_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/capabilities/v1alpha"
+	nfa_debug_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/debug/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// IntentServer hosts the gRPC server for intent service implementations
+type IntentServer struct {
+	server   *grpc.Server
+	services map[string]interface{} // service name -> implementation
+	descs    map[string]*grpc.ServiceDesc
+	port     int
+	lis      net.Listener
+	opts     options
+	queue    *requestQueue // with WithConcurrencyLimit or WithAdaptiveConcurrency
+	tuner    *concurrencyTuner
+	load     serverLoad
+	errors   *errorReporter // with WithErrorReports
+
+	// Interceptors applied to every request, over the network or in-process
+	unary  []grpc.UnaryServerInterceptor
+	stream []grpc.StreamServerInterceptor
+}
+
+// IntentServer is a grpc.ServiceRegistrar, so generated RegisterXxxServer
+// functions accept it directly
+var _ grpc.ServiceRegistrar = (*IntentServer)(nil)
+
+// NewIntentServer creates a new intent server. WithTLS serves TLS and
+// WithMetrics records every handled request. Every response carries the
+// fulfillment of its request in the trailer, naming the service set with
+// WithServiceID. A server with a service ID can also be called in-process,
+// see LocalConn. WithCapabilities adds the capabilities service,
+// WithDebug the debug service,
+// WithAdmission rejects requests not matching the contract,
+// WithConcurrencyLimit queues requests by interactivity,
+// WithAdaptiveConcurrency tunes the limit to the host, WithKeepalive
+// changes how connections are kept alive, WithTracerProvider traces
+// every request and WithErrorReports reports handler errors to the broker.
+func NewIntentServer(port int, opts ...Option) *IntentServer {
+	s := &IntentServer{
+		services: make(map[string]interface{}),
+		descs:    make(map[string]*grpc.ServiceDesc),
+		port:     port,
+		opts:     newOptions(opts),
+	}
+	if s.opts.serviceID != "" {
+		s.opts.logAttrs = append(s.opts.logAttrs, "service_id", s.opts.serviceID)
+	}
+	s.unary = []grpc.UnaryServerInterceptor{s.unaryMetrics, s.unaryFulfillment}
+	s.stream = []grpc.StreamServerInterceptor{s.streamMetrics, s.streamFulfillment}
+	if s.opts.tracer != nil {
+		s.unary = append([]grpc.UnaryServerInterceptor{s.unaryTracing}, s.unary...)
+		s.stream = append([]grpc.StreamServerInterceptor{s.streamTracing}, s.stream...)
+	}
+	if s.opts.errorReports != nil && s.opts.serviceID != "" {
+		s.errors = newErrorReporter(&s.opts)
+		s.unary = append(s.unary, s.unaryErrors)
+		s.stream = append(s.stream, s.streamErrors)
+	}
+	if s.opts.admission != nil {
+		a := admission{contract: s.opts.admission, opts: &s.opts}
+		s.unary = append(s.unary, a.unary)
+		s.stream = append(s.stream, a.stream)
+	}
+	if s.opts.adaptiveConcurrency > 0 {
+		s.queue = newRequestQueue(s.opts.adaptiveConcurrency)
+		s.tuner = newConcurrencyTuner(s.queue, s.opts.adaptiveConcurrency)
+	} else if s.opts.concurrencyLimit > 0 {
+		s.queue = newRequestQueue(s.opts.concurrencyLimit)
+	}
+	if s.queue != nil {
+		s.unary = append(s.unary, s.unaryQueue)
+		s.stream = append(s.stream, s.streamQueue)
+	}
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.unary...),
+		grpc.ChainStreamInterceptor(s.stream...),
+	}
+	serverOpts = append(serverOpts, s.opts.keepalive.serverOptions()...)
+	if s.opts.tls != nil {
+		serverOpts = append(serverOpts, grpc.Creds(s.opts.transportCredentials()))
+	}
+	s.server = grpc.NewServer(serverOpts...)
+	if s.opts.capabilities != nil {
+		capabilities := NewCapabilitiesServer(s.opts.capabilities)
+		capabilities.serviceID = s.opts.serviceID
+		s.RegisterService(&nfa_capabilities_v1alpha.Capabilities_ServiceDesc, capabilities)
+	}
+	if s.opts.debugLogs != nil {
+		debug := NewDebugServer(s.opts.capabilities, s.opts.debugLogs, s.opts.debugAuthorize)
+		s.RegisterService(&nfa_debug_v1alpha.Debug_ServiceDesc, debug)
+	}
+	if s.opts.serviceID != "" {
+		registerLocal(s.opts.serviceID, s)
+	}
+	return s
+}
+
+// RegisterService registers a service implementation
+func (s *IntentServer) RegisterService(desc *grpc.ServiceDesc, impl interface{}) {
+	s.server.RegisterService(desc, impl)
+	s.services[desc.ServiceName] = impl
+	s.descs[desc.ServiceName] = desc
+	s.opts.log(logging.Server).Info("registered gRPC service", "service", desc.ServiceName)
+}
+
+// Listen binds the server's port without serving yet. With port 0 the
+// kernel assigns a free port, which GetPort and Addr return afterwards, so it
+// can be advertised before Start or Serve.
+func (s *IntentServer) Listen() error {
+	if s.lis != nil {
+		return nil
+	}
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", s.port))
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	s.setListener(lis)
+	return nil
+}
+
+// Start serves on the port bound by Listen, listening first if needed
+func (s *IntentServer) Start() error {
+	if err := s.Listen(); err != nil {
+		return err
+	}
+	return s.Serve(s.lis)
+}
+
+// Serve serves on lis, e.g. a listener inherited from a supervisor
+func (s *IntentServer) Serve(lis net.Listener) error {
+	// A listener bound by Listen is recorded already, and GetPort may be
+	// read concurrently once it is
+	if lis != s.lis {
+		s.setListener(lis)
+	}
+
+	// Register health service
+	healthServer := health.NewServer()
+	grpc_health_v1.RegisterHealthServer(s.server, healthServer)
+	
+	// Register reflection service
+	reflection.Register(s.server)
+
+	s.opts.log(logging.Server).Info("intent server listening", "port", s.port)
+	s.startTuning()
+	s.startReporting()
+	
+	// Update health status for all services
+	for serviceName := range s.services {
+		healthServer.SetServingStatus(serviceName, grpc_health_v1.HealthCheckResponse_SERVING)
+	}
+
+	return s.server.Serve(lis)
+}
+
+// Stop gracefully stops the server
+func (s *IntentServer) Stop() {
+	s.opts.log(logging.Server).Info("intent server shutting down")
+	if s.opts.serviceID != "" {
+		unregisterLocal(s.opts.serviceID, s)
+	}
+	if s.tuner != nil {
+		s.tuner.stop()
+	}
+	s.server.GracefulStop()
+	s.stopReporting()
+	s.opts.log(logging.Server).Info("intent server stopped")
+}
+
+// GetPort returns the server port: the bound port once listening, the
+// configured one before
+func (s *IntentServer) GetPort() int {
+	return s.port
+}
+
+// Addr returns the address the server listens on, or nil before Listen
+func (s *IntentServer) Addr() net.Addr {
+	if s.lis == nil {
+		return nil
+	}
+	return s.lis.Addr()
+}
+
+// setListener records the listener and the port it is bound to
+func (s *IntentServer) setListener(lis net.Listener) {
+	s.lis = lis
+	if addr, ok := lis.Addr().(*net.TCPAddr); ok {
+		s.port = addr.Port
+	}
+}
+
+func (s *IntentServer) unaryMetrics(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := s.opts.clock.Now()
+	s.load.begin()
+	resp, err := handler(ctx, req)
+	elapsed := s.opts.clock.Now().Sub(start)
+	s.load.end(elapsed, true)
+	s.opts.metrics.Request(info.FullMethod, elapsed, err)
+	if m, ok := s.opts.metrics.(IntentMetrics); ok {
+		if action := intentAction(req); action != "" {
+			m.Intent(action, elapsed, err)
+		}
+	}
+	return resp, err
+}
+
+func (s *IntentServer) streamMetrics(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := s.opts.clock.Now()
+	s.load.begin()
+	err := handler(srv, stream)
+	elapsed := s.opts.clock.Now().Sub(start)
+	s.load.end(elapsed, false)
+	s.opts.metrics.Request(info.FullMethod, elapsed, err)
+	return err
+}
@@ -0,0 +1,97 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+)
+
+// Deregister tells the broker every registered service is going away, so it
+// stops routing intents to them instead of waiting for the liveness timeout.
+// Heartbeats of the running StartHealthReporting pause until a contract is
+// registered again. The errors of services that failed to deregister are
+// joined; those services stay registered.
+func (r *IntentRuntime) Deregister(ctx context.Context) error {
+	if err := r.ready(); err != nil {
+		return err
+	}
+	regs := r.registered()
+	if len(regs) == 0 {
+		return fmt.Errorf("%w: no service has been registered", ErrNotRegistered)
+	}
+	var errs []error
+	for _, reg := range regs {
+		if err := r.DeregisterService(ctx, reg.serviceID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DeregisterService deregisters one of the services registered by the
+// runtime, leaving the others registered
+func (r *IntentRuntime) DeregisterService(ctx context.Context, serviceID string) error {
+	if err := r.ready(); err != nil {
+		return err
+	}
+	if !slices.Contains(r.ServiceIDs(), serviceID) {
+		return fmt.Errorf("%w: %s is not registered by this runtime", ErrNotRegistered, serviceID)
+	}
+	ctx, cancel := r.bind(ctx)
+	defer cancel()
+	if err := r.client.Unregister(ctx, serviceID); err != nil {
+		return err
+	}
+	r.opts.log(logging.Health).Info("service deregistered", "service_id", serviceID)
+	r.forget(serviceID)
+	return nil
+}
+
+// Shutdown detaches the runtime from the broker and stops servers in an
+// order that loses no requests: it stops heartbeats and the supervisor, so
+// no service is registered again, reports the runtime as draining,
+// deregisters every service so the broker routes no new intents to them, lets
+// servers finish in-flight requests and closes the runtime. When ctx ends
+// first, servers are stopped without waiting. The remaining steps run even if
+// one fails; their errors are joined.
+func (r *IntentRuntime) Shutdown(ctx context.Context, servers ...*IntentServer) error {
+	var errs []error
+	r.stopLoops()
+	r.draining.Store(true)
+	if r.ready() == nil && len(r.registered()) > 0 {
+		if err := r.Deregister(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, s := range servers {
+		if err := s.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown stops the server gracefully, waiting for in-flight requests until
+// ctx ends, at which point the remaining ones are cancelled and ctx's error
+// is returned
+func (s *IntentServer) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		<-stopped
+		return fmt.Errorf("server stopped before in-flight requests finished: %w", ctx.Err())
+	}
+}
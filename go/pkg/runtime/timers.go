@@ -0,0 +1,59 @@
+package runtime
+
+import (
+	"context"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	nfa_timer_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/timer/v1alpha"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/timer"
+)
+
+// ScheduleIntent asks the broker to broadcast intent once at fireTime to the
+// runtimes matching selector, e.g. to start the coffee machine at 7am, and
+// returns the pending timer. The broker keeps the timer across its restarts
+// and retries the broadcast until a target handles it, for at most
+// maxLateness after fireTime; zero selects timer.DefaultMaxLateness.
+// Providers may receive the intent more than once.
+func (r *IntentRuntime) ScheduleIntent(ctx context.Context, fireTime time.Time, maxLateness time.Duration, selector map[string]string, intent *nfa_control_v1alpha.Invoke) (*nfa_timer_v1alpha.Timer, error) {
+	if err := r.ready(); err != nil {
+		return nil, err
+	}
+	if err := r.require(broker.FeatureTimers); err != nil {
+		return nil, err
+	}
+	ctx, cancel := r.bind(ctx)
+	defer cancel()
+	t, err := timer.NewClient(r.conn).Schedule(ctx, fireTime, maxLateness, selector, intent)
+	return t, unsupported(broker.FeatureTimers, err)
+}
+
+// Timers lists the broker's timers in state, or every timer when state is
+// unspecified, ordered by fire time. Finished timers are listed for a day.
+func (r *IntentRuntime) Timers(ctx context.Context, state nfa_timer_v1alpha.TimerState) ([]*nfa_timer_v1alpha.Timer, error) {
+	if err := r.ready(); err != nil {
+		return nil, err
+	}
+	if err := r.require(broker.FeatureTimers); err != nil {
+		return nil, err
+	}
+	ctx, cancel := r.bind(ctx)
+	defer cancel()
+	timers, err := timer.NewClient(r.conn).List(ctx, state)
+	return timers, unsupported(broker.FeatureTimers, err)
+}
+
+// CancelTimer cancels a pending timer created by ScheduleIntent
+func (r *IntentRuntime) CancelTimer(ctx context.Context, id string) error {
+	if err := r.ready(); err != nil {
+		return err
+	}
+	if err := r.require(broker.FeatureTimers); err != nil {
+		return err
+	}
+	ctx, cancel := r.bind(ctx)
+	defer cancel()
+	_, err := timer.NewClient(r.conn).Cancel(ctx, id)
+	return unsupported(broker.FeatureTimers, err)
+}
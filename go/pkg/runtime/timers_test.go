@@ -0,0 +1,102 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	nfa_timer_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/timer/v1alpha"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestScheduleIntent(t *testing.T) {
+	b := broker.NewEmbedded()
+	defer b.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...))
+	defer r.Close()
+	if _, err := r.ScheduleIntent(ctx, time.Now(), 0, nil, &nfa_control_v1alpha.Invoke{Action: "brew"}); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("ScheduleIntent() before Connect error = %v, want ErrNotConnected", err)
+	}
+	if err := r.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	// The coffee machine connects after the timer is due: the timer is
+	// retried until a target handles it
+	fired, err := r.ScheduleIntent(ctx, time.Now().Add(50*time.Millisecond), time.Minute,
+		map[string]string{"device": "coffee"}, &nfa_control_v1alpha.Invoke{Action: "brew", Parameters: map[string]string{"size": "large"}})
+	if err != nil {
+		t.Fatalf("ScheduleIntent() error = %v", err)
+	}
+	later, err := r.ScheduleIntent(ctx, time.Now().Add(time.Hour), 0, nil, &nfa_control_v1alpha.Invoke{Action: "brew"})
+	if err != nil {
+		t.Fatalf("ScheduleIntent() error = %v", err)
+	}
+	if later.MaxLatenessSecs != 3600 || later.State != nfa_timer_v1alpha.TimerState_TIMER_STATE_PENDING {
+		t.Errorf("timer = %v, want a pending timer with the default max lateness", later)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	brewed := make(chan string, 2)
+	machine := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...))
+	defer machine.Close()
+	if err := machine.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if _, err := machine.RegisterFromBytes(ctx, []byte(leaseContract)); err != nil {
+		t.Fatalf("RegisterFromBytes() error = %v", err)
+	}
+	machine.OnBroadcast(func(invoke *nfa_control_v1alpha.Invoke) ([]byte, error) {
+		brewed <- invoke.Parameters["size"]
+		return []byte("brewing"), nil
+	})
+	go machine.StartControlStream(ctx, map[string]string{"device": "coffee"})
+	select {
+	case size := <-brewed:
+		if size != "large" {
+			t.Errorf("brewed %q, want large", size)
+		}
+	case <-time.After(15 * time.Second):
+		t.Fatalf("the timer did not fire once the machine connected")
+	}
+
+	if err := r.CancelTimer(ctx, later.Id); err != nil {
+		t.Fatalf("CancelTimer() error = %v", err)
+	}
+	if err := r.CancelTimer(ctx, later.Id); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("CancelTimer() of a cancelled timer error = %v, want FailedPrecondition", err)
+	}
+
+	var timers []*nfa_timer_v1alpha.Timer
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if timers, err = r.Timers(ctx, nfa_timer_v1alpha.TimerState_TIMER_STATE_UNSPECIFIED); err != nil {
+			t.Fatalf("Timers() error = %v", err)
+		}
+		if len(timers) == 2 && timers[0].State == nfa_timer_v1alpha.TimerState_TIMER_STATE_DELIVERED {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(timers) != 2 || timers[0].Id != fired.Id || timers[1].Id != later.Id {
+		t.Fatalf("Timers() = %v, want both timers by fire time", timers)
+	}
+	if got := timers[0]; got.State != nfa_timer_v1alpha.TimerState_TIMER_STATE_DELIVERED || got.Attempts < 2 ||
+		len(got.Targets) != 1 || string(got.Targets[0].Output) != "brewing" {
+		t.Errorf("fired timer = %v, want it delivered after a retry", got)
+	}
+	if got := timers[1]; got.State != nfa_timer_v1alpha.TimerState_TIMER_STATE_CANCELLED || got.FinishTime == nil {
+		t.Errorf("cancelled timer = %v", got)
+	}
+	pending, err := r.Timers(ctx, nfa_timer_v1alpha.TimerState_TIMER_STATE_PENDING)
+	if err != nil || len(pending) != 0 {
+		t.Errorf("pending Timers() = %v, %v, want none", pending, err)
+	}
+}
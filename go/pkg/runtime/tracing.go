@@ -0,0 +1,172 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// tracerName is the instrumentation scope of the runtime's spans
+const tracerName = "github.com/neuro-fluidic-architecture/nfa-core/go/pkg/runtime"
+
+// propagator carries the W3C trace context and baggage of requests in their
+// gRPC metadata, and of broadcast intents in Invoke.TraceContext
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// WithTracerProvider traces the runtime and the intent server with spans of
+// tp, e.g. the SDK provider of the application's OpenTelemetry setup:
+//   - a runtime traces its unary calls to the broker, such as RegisterIntent
+//     and Heartbeat, and its calls on connections from ProviderConn, passing
+//     the trace context to the broker and providers in the W3C traceparent
+//     header
+//   - an intent server traces every request it handles, continuing the trace
+//     of the caller's traceparent, and names the action of intent requests
+//   - a runtime traces the broadcast intents it handles, continuing the trace
+//     of the broadcaster
+//
+// Without it nothing is traced.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracer = tp.Tracer(tracerName)
+	}
+}
+
+// metadataCarrier adapts gRPC metadata to the propagator
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// rpcAttributes returns the semantic convention attributes of a call of
+// method, "/package.Service/Method", and the span name of the call
+func rpcAttributes(method string) (string, []attribute.KeyValue) {
+	name := strings.TrimPrefix(method, "/")
+	service, rpc, _ := strings.Cut(name, "/")
+	return name, []attribute.KeyValue{
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.service", service),
+		attribute.String("rpc.method", rpc),
+	}
+}
+
+// endSpan ends span with the status of err; fault tells which errors are
+// failures of the traced side
+func endSpan(span trace.Span, err error, fault func(error) bool) {
+	st := status.Convert(err)
+	span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(st.Code())))
+	if err != nil && fault(err) {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, st.Message())
+	}
+	span.End()
+}
+
+func anyError(error) bool { return true }
+
+// unaryClientTracing traces a unary call and passes its trace context to
+// the server
+func (o *options) unaryClientTracing(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, span := o.startClientSpan(ctx, method, req)
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	endSpan(span, err, anyError)
+	return err
+}
+
+func (o *options) startClientSpan(ctx context.Context, method string, req interface{}) (context.Context, trace.Span) {
+	name, attrs := rpcAttributes(method)
+	if action := intentAction(req); action != "" {
+		attrs = append(attrs, attribute.String("nfa.action", action))
+	}
+	ctx, span := o.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	propagator.Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+// tracedConn is a provider connection tracing the unary calls sent on it
+type tracedConn struct {
+	grpc.ClientConnInterface
+	opts *options
+}
+
+func (c tracedConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	ctx, span := c.opts.startClientSpan(ctx, method, args)
+	err := c.ClientConnInterface.Invoke(ctx, method, args, reply, opts...)
+	endSpan(span, err, anyError)
+	return err
+}
+
+// startServerSpan starts the span of a request to method handled by the
+// intent server, continuing the trace of the caller
+func (s *IntentServer) startServerSpan(ctx context.Context, method string, req interface{}) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = propagator.Extract(ctx, metadataCarrier(md))
+	name, attrs := rpcAttributes(method)
+	if s.opts.serviceID != "" {
+		attrs = append(attrs, attribute.String("nfa.service_id", s.opts.serviceID))
+	}
+	if action := intentAction(req); action != "" {
+		attrs = append(attrs, attribute.String("nfa.action", action))
+	}
+	return s.opts.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+}
+
+// unaryTracing traces a request; only failures of the provider mark the
+// span as failed, see ReportOutcome
+func (s *IntentServer) unaryTracing(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, span := s.startServerSpan(ctx, info.FullMethod, req)
+	resp, err := handler(ctx, req)
+	endSpan(span, err, providerFault)
+	return resp, err
+}
+
+func (s *IntentServer) streamTracing(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, span := s.startServerSpan(stream.Context(), info.FullMethod, nil)
+	err := handler(srv, &tracedStream{ServerStream: stream, ctx: ctx})
+	endSpan(span, err, providerFault)
+	return err
+}
+
+// tracedStream exposes the span of a stream to its handler
+type tracedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tracedStream) Context() context.Context {
+	return s.ctx
+}
+
+// startBroadcastSpan starts the span of a broadcast intent handled by the
+// runtime, continuing the trace of the broadcaster
+func (o *options) startBroadcastSpan(invoke *nfa_control_v1alpha.Invoke) trace.Span {
+	ctx := propagator.Extract(context.Background(), propagation.MapCarrier(invoke.TraceContext))
+	_, span := o.tracer.Start(ctx, "broadcast "+invoke.Action, trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.String("nfa.action", invoke.Action), attribute.Bool("nfa.shadow", invoke.Shadow)))
+	return span
+}
@@ -0,0 +1,118 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	nfa_capabilities_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/capabilities/v1alpha"
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// findSpan returns the first ended span named name
+func findSpan(rec *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	for _, span := range rec.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func TestWithTracerProvider(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	b := broker.NewEmbedded()
+	defer b.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	provider := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...), WithTracerProvider(tp))
+	defer provider.Close()
+	if err := provider.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	serviceID, err := provider.RegisterFromBytes(ctx, []byte(leaseContract))
+	if err != nil {
+		t.Fatalf("RegisterFromBytes() error = %v", err)
+	}
+	if err := provider.Heartbeat(ctx); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	for _, name := range []string{"nfa.broker.v1alpha.IntentBroker/RegisterIntent", "nfa.broker.v1alpha.IntentBroker/Heartbeat"} {
+		if span := findSpan(rec, name); span == nil || span.SpanKind() != trace.SpanKindClient {
+			t.Errorf("no client span %s", name)
+		}
+	}
+
+	// A consumer's call continues its trace on the provider's server
+	server := NewIntentServer(0, WithServiceID(serviceID), WithCapabilities(provider), WithTracerProvider(tp))
+	defer server.Stop()
+	consumer := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...), WithTracerProvider(tp),
+		WithProviderDialer(func(ctx context.Context, serviceID string) (grpc.ClientConnInterface, error) {
+			return server.InProcessConn(), nil
+		}))
+	defer consumer.Close()
+	if err := consumer.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	cc, err := consumer.ProviderConn(ctx, serviceID)
+	if err != nil {
+		t.Fatalf("ProviderConn() error = %v", err)
+	}
+	callCtx, parent := tp.Tracer("test").Start(ctx, "consumer")
+	_, err = nfa_capabilities_v1alpha.NewCapabilitiesClient(cc).GetCapabilities(callCtx, &nfa_capabilities_v1alpha.GetCapabilitiesRequest{})
+	parent.End()
+	if err != nil {
+		t.Fatalf("GetCapabilities() error = %v", err)
+	}
+	const method = "nfa.capabilities.v1alpha.Capabilities/GetCapabilities"
+	var client, handled sdktrace.ReadOnlySpan
+	for _, span := range rec.Ended() {
+		switch {
+		case span.Name() == method && span.SpanKind() == trace.SpanKindClient:
+			client = span
+		case span.Name() == method && span.SpanKind() == trace.SpanKindServer:
+			handled = span
+		}
+	}
+	if client == nil || handled == nil {
+		t.Fatalf("spans of %s: client %v, server %v, want both", method, client, handled)
+	}
+	if client.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("client span parent = %v, want the consumer's span", client.Parent().SpanID())
+	}
+	if handled.Parent().SpanID() != client.SpanContext().SpanID() || !handled.Parent().IsRemote() {
+		t.Errorf("server span parent = %v, want the remote client span %v", handled.Parent().SpanID(), client.SpanContext().SpanID())
+	}
+
+	// A broadcast continues the broadcaster's trace on the runtimes handling it
+	provider.OnBroadcast(func(*nfa_control_v1alpha.Invoke) ([]byte, error) {
+		return []byte("ok"), nil
+	})
+	go provider.StartControlStream(ctx, map[string]string{"room": "hall"})
+	deadline := time.Now().Add(5 * time.Second)
+	for len(b.Hub().Sessions()) < 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	callCtx, parent = tp.Tracer("test").Start(ctx, "broadcaster")
+	_, err = consumer.InvokeBatch(callCtx, []BatchIntent{
+		{Selector: map[string]string{"room": "hall"}, Intent: &nfa_control_v1alpha.Invoke{Action: "lights_on"}},
+	})
+	parent.End()
+	if err != nil {
+		t.Fatalf("InvokeBatch() error = %v", err)
+	}
+	span := findSpan(rec, "broadcast lights_on")
+	if span == nil {
+		t.Fatalf("no span of the broadcast intent")
+	}
+	if span.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+		t.Errorf("broadcast span trace = %v, want the broadcaster's %v", span.SpanContext().TraceID(), parent.SpanContext().TraceID())
+	}
+}
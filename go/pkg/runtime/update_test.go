@@ -0,0 +1,58 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+)
+
+func TestUpdateContract(t *testing.T) {
+	b := broker.NewEmbedded()
+	defer b.Close()
+	r := NewIntentRuntime(broker.EmbeddedTarget, WithDialOptions(b.DialOptions()...))
+	defer r.Close()
+	if err := r.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	ctx := context.Background()
+	if _, err := r.RegisterFromBytes(ctx, []byte(leaseContract)); err != nil {
+		t.Fatalf("RegisterFromBytes() error = %v", err)
+	}
+	parse := func() *contract.IntentContract {
+		c, err := contract.ParseIntentContract([]byte(leaseContract))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	additive := parse()
+	additive.Metadata.Description = "Translates text"
+	additive.Spec.IntentPatterns = append(additive.Spec.IntentPatterns, contract.IntentPattern{
+		Pattern: contract.Pattern{Action: "detect_language"},
+	})
+	if _, err := r.UpdateContract(ctx, additive, false); err != nil {
+		t.Fatalf("UpdateContract() with additive changes error = %v", err)
+	}
+
+	breaking := parse()
+	breaking.Spec.IntentPatterns[0].Streaming = contract.StreamingServer
+	if _, err := r.UpdateContract(ctx, breaking, false); !errors.Is(err, ErrBreakingChange) {
+		t.Fatalf("UpdateContract() with breaking changes error = %v, want ErrBreakingChange", err)
+	}
+	if got := r.contracts()[0].contract; got != additive {
+		t.Errorf("refused update replaced the registered contract")
+	}
+	if _, err := r.UpdateContract(ctx, breaking, true); err != nil {
+		t.Fatalf("forced UpdateContract() error = %v", err)
+	}
+	if got := r.contracts()[0].contract; got != breaking {
+		t.Errorf("forced update did not replace the registered contract")
+	}
+	if ids := r.ServiceIDs(); len(ids) != 1 {
+		t.Errorf("ServiceIDs() = %v, want the updated service only", ids)
+	}
+}
@@ -6,24 +6,22 @@ import (
 	"log"
 	"os"
 
-	"github.com/neuro-fluidic-architecture/nfa-core/go/runtime"
-	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
-	"google.golang.org/grpc"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/runtime"
 )
 
 // ExampleService 是一个示例意图服务实现
 type ExampleService struct {
-	nfa_intent_v1alpha.UnimplementedExampleServiceServer
+	UnimplementedExampleServiceServer
 }
 
 // ProcessExample 处理示例意图
-func (s *ExampleService) ProcessExample(ctx context.Context, req *nfa_intent_v1alpha.ExampleRequest) (*nfa_intent_v1alpha.ExampleResponse, error) {
+func (s *ExampleService) ProcessExample(ctx context.Context, req *ExampleRequest) (*ExampleResponse, error) {
 	log.Printf("Processing example request: %s", req.Input)
 	
 	// 简单的处理逻辑
 	result := fmt.Sprintf("Processed: %s (length: %d)", req.Input, len(req.Input))
 	
-	return &nfa_intent_v1alpha.ExampleResponse{
+	return &ExampleResponse{
 		Result:    result,
 		Processed: true,
 		Metadata:  map[string]string{"input_length": fmt.Sprintf("%d", len(req.Input))},
@@ -33,16 +31,16 @@ func (s *ExampleService) ProcessExample(ctx context.Context, req *nfa_intent_v1a
 func main() {
 	// 连接到Broker
 	brokerAddr := getEnv("NFA_BROKER_ADDRESS", "localhost:50051")
-	runtime := runtime.NewIntentRuntime(brokerAddr)
+	rt := runtime.NewIntentRuntime(brokerAddr)
 	
-	if err := runtime.Connect(); err != nil {
+	if err := rt.Connect(); err != nil {
 		log.Fatalf("Failed to connect to broker: %v", err)
 	}
-	defer runtime.Close()
+	defer rt.Close()
 	
 	// 注册意图服务
 	contractPath := getEnv("NFA_SERVICE_CONTRACT_PATH", "example.intent.yaml")
-	serviceID, err := runtime.RegisterFromFile(contractPath)
+	serviceID, err := rt.RegisterFromFile(contractPath)
 	if err != nil {
 		log.Fatalf("Failed to register service: %v", err)
 	}
@@ -50,14 +48,14 @@ func main() {
 	log.Printf("Service registered with ID: %s", serviceID)
 	
 	// 创建gRPC服务器
-	server := runtime.NewIntentServer(50052)
+	server := runtime.NewIntentServer(50052, runtime.WithServiceID(serviceID), runtime.WithCapabilities(rt))
 	exampleService := &ExampleService{}
 	
 	// 注册示例服务
-	nfa_intent_v1alpha.RegisterExampleServiceServer(server.Server, exampleService)
+	RegisterExampleServiceServer(server, exampleService)
 	
 	// 启动健康报告
-	go runtime.StartHealthReporting()
+	go rt.StartHealthReporting(context.Background())
 	
 	// 启动服务器
 	log.Printf("Starting example service on port %d", server.GetPort())
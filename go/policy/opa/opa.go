@@ -0,0 +1,176 @@
+// Package opa evaluates broker authorization and routing policies from an
+// OPA bundle in-process, for deployments that standardize on Rego.
+//
+// The bundle must define:
+//
+//	data.nfa.authz.allow       boolean
+//	data.nfa.authz.reason      optional string explaining the decision
+//	data.nfa.routing.order     optional array of service IDs, most preferred first
+//
+// Authorization queries receive the policy.Input as input; routing queries
+// receive {"request": <policy.Input>, "candidates": [<policy.Candidate>...]}.
+package opa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/policy"
+	"github.com/open-policy-agent/opa/loader"
+	"github.com/open-policy-agent/opa/rego"
+)
+
+const (
+	allowQuery  = "data.nfa.authz.allow"
+	reasonQuery = "data.nfa.authz.reason"
+	routeQuery  = "data.nfa.routing.order"
+)
+
+// Engine implements policy.Engine on top of an OPA bundle
+type Engine struct {
+	bundlePath string
+
+	mu     sync.RWMutex
+	allow  rego.PreparedEvalQuery
+	reason rego.PreparedEvalQuery
+	route  rego.PreparedEvalQuery
+}
+
+var _ policy.Engine = (*Engine)(nil)
+
+// Load compiles the bundle at path (a directory or .tar.gz bundle)
+func Load(ctx context.Context, bundlePath string) (*Engine, error) {
+	e := &Engine{bundlePath: bundlePath}
+	if err := e.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Reload reads the bundle once and recompiles the queries from it. On failure
+// the previously loaded policies stay active.
+func (e *Engine) Reload(ctx context.Context) error {
+	b, err := loader.NewFileLoader().AsBundle(e.bundlePath)
+	if err != nil {
+		return fmt.Errorf("failed to load policy bundle %s: %w", e.bundlePath, err)
+	}
+	prepare := func(query string) (rego.PreparedEvalQuery, error) {
+		return rego.New(
+			rego.Query(query),
+			rego.ParsedBundle(e.bundlePath, b),
+		).PrepareForEval(ctx)
+	}
+
+	allow, err := prepare(allowQuery)
+	if err != nil {
+		return fmt.Errorf("failed to load policy bundle %s: %w", e.bundlePath, err)
+	}
+	reason, err := prepare(reasonQuery)
+	if err != nil {
+		return fmt.Errorf("failed to load policy bundle %s: %w", e.bundlePath, err)
+	}
+	route, err := prepare(routeQuery)
+	if err != nil {
+		return fmt.Errorf("failed to load policy bundle %s: %w", e.bundlePath, err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.allow, e.reason, e.route = allow, reason, route
+	return nil
+}
+
+// Authorize implements policy.Engine. An undefined allow rule denies.
+func (e *Engine) Authorize(ctx context.Context, input policy.Input) (policy.Decision, error) {
+	doc, err := toDocument(input)
+	if err != nil {
+		return policy.Decision{}, err
+	}
+
+	e.mu.RLock()
+	allowQ, reasonQ := e.allow, e.reason
+	e.mu.RUnlock()
+
+	value, err := evalOne(ctx, allowQ, doc)
+	if err != nil {
+		return policy.Decision{}, err
+	}
+	allowed, _ := value.(bool)
+
+	decision := policy.Decision{Allowed: allowed}
+	if value, err := evalOne(ctx, reasonQ, doc); err == nil {
+		decision.Reason, _ = value.(string)
+	}
+	if decision.Reason == "" && !allowed {
+		decision.Reason = "denied by policy bundle"
+	}
+	return decision, nil
+}
+
+// Route implements policy.Engine. When the bundle defines no routing order the
+// candidates are returned unchanged; IDs not in the order are dropped.
+func (e *Engine) Route(ctx context.Context, input policy.Input, candidates []policy.Candidate) ([]policy.Candidate, error) {
+	doc, err := toDocument(map[string]interface{}{
+		"request":    input,
+		"candidates": candidates,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.RLock()
+	routeQ := e.route
+	e.mu.RUnlock()
+
+	value, err := evalOne(ctx, routeQ, doc)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return candidates, nil
+	}
+	order, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array of service IDs", routeQuery)
+	}
+
+	byID := make(map[string]policy.Candidate, len(candidates))
+	for _, c := range candidates {
+		byID[c.ServiceID] = c
+	}
+	routed := make([]policy.Candidate, 0, len(order))
+	for _, id := range order {
+		if c, ok := byID[fmt.Sprint(id)]; ok {
+			routed = append(routed, c)
+		}
+	}
+	return routed, nil
+}
+
+// evalOne evaluates a query, returning nil when the result is undefined
+func evalOne(ctx context.Context, query rego.PreparedEvalQuery, input interface{}) (interface{}, error) {
+	rs, err := query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return nil, fmt.Errorf("policy evaluation failed: %w", err)
+	}
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		return nil, nil
+	}
+	return rs[0].Expressions[0].Value, nil
+}
+
+// toDocument converts Go values to plain JSON types so Rego sees field names
+// as declared in the json tags
+func toDocument(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
@@ -0,0 +1,166 @@
+package opa
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/policy"
+)
+
+const authzPolicy = `package nfa.authz
+
+default allow = false
+
+allow {
+	input.action == "lights_on"
+}
+
+allow {
+	input.action == "unlock"
+	input.consumer == "owner"
+}
+
+reason = "only the owner unlocks" {
+	input.action == "unlock"
+	input.consumer != "owner"
+}
+`
+
+// routingPolicy prefers the candidates of the consumer's room, then the
+// heaviest, and leaves out those under maintenance
+const routingPolicy = `package nfa.routing
+
+order = array.concat(by_weight(local), by_weight(others))
+
+eligible = [c | c := input.candidates[_]; not c.labels.maintenance]
+
+local = [c | c := eligible[_]; c.labels.room == input.request.labels.room]
+
+others = [c | c := eligible[_]; c.labels.room != input.request.labels.room]
+
+by_weight(cs) = [ranked[i][1] | ranked[i]] {
+	ranked := sort([[0 - c.weight, c.service_id] | c := cs[_]])
+}
+`
+
+// writeBundle writes a bundle directory with the given Rego modules
+func writeBundle(t *testing.T, modules map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, module := range modules {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(module), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestAuthorize(t *testing.T) {
+	ctx := context.Background()
+	e, err := Load(ctx, writeBundle(t, map[string]string{"authz.rego": authzPolicy}))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	for _, tt := range []struct {
+		input policy.Input
+		want  policy.Decision
+	}{
+		{policy.Input{Consumer: "guest", Action: "lights_on"}, policy.Decision{Allowed: true}},
+		{policy.Input{Consumer: "owner", Action: "unlock"}, policy.Decision{Allowed: true}},
+		{policy.Input{Consumer: "guest", Action: "unlock"}, policy.Decision{Reason: "only the owner unlocks"}},
+		// Without a reason rule the engine explains the denial itself
+		{policy.Input{Consumer: "guest", Action: "open_garage"}, policy.Decision{Reason: "denied by policy bundle"}},
+	} {
+		got, err := e.Authorize(ctx, tt.input)
+		if err != nil {
+			t.Fatalf("Authorize(%+v) error = %v", tt.input, err)
+		}
+		if got != tt.want {
+			t.Errorf("Authorize(%+v) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestAuthorizeUndefined(t *testing.T) {
+	ctx := context.Background()
+	e, err := Load(ctx, writeBundle(t, map[string]string{"routing.rego": routingPolicy}))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got, err := e.Authorize(ctx, policy.Input{Consumer: "owner", Action: "lights_on"})
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	if got.Allowed || got.Reason != "denied by policy bundle" {
+		t.Errorf("Authorize() without an allow rule = %+v, want a denial", got)
+	}
+}
+
+func TestRoute(t *testing.T) {
+	ctx := context.Background()
+	candidates := []policy.Candidate{
+		{ServiceID: "kitchen-1", Labels: map[string]string{"room": "kitchen"}, Weight: 1},
+		{ServiceID: "hall-1", Labels: map[string]string{"room": "hall"}, Weight: 3},
+		{ServiceID: "kitchen-2", Labels: map[string]string{"room": "kitchen"}, Weight: 2},
+		{ServiceID: "hall-2", Labels: map[string]string{"room": "hall", "maintenance": "true"}, Weight: 5},
+	}
+	input := policy.Input{Consumer: "owner", Action: "lights_on", Labels: map[string]string{"room": "kitchen"}}
+
+	e, err := Load(ctx, writeBundle(t, map[string]string{"routing.rego": routingPolicy}))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got, err := e.Route(ctx, input, candidates)
+	if err != nil {
+		t.Fatalf("Route() error = %v", err)
+	}
+	want := []string{"kitchen-2", "kitchen-1", "hall-1"}
+	if len(got) != len(want) {
+		t.Fatalf("Route() = %+v, want %v", got, want)
+	}
+	for i, c := range got {
+		if c.ServiceID != want[i] {
+			t.Errorf("Route()[%d] = %s, want %s", i, c.ServiceID, want[i])
+		}
+	}
+
+	// Without a routing order the candidates are returned unchanged
+	e, err = Load(ctx, writeBundle(t, map[string]string{"authz.rego": authzPolicy}))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got, err := e.Route(ctx, input, candidates); err != nil || len(got) != len(candidates) || got[0].ServiceID != "kitchen-1" {
+		t.Errorf("Route() without an order = %+v, %v, want the candidates unchanged", got, err)
+	}
+}
+
+func TestReload(t *testing.T) {
+	ctx := context.Background()
+	dir := writeBundle(t, map[string]string{"authz.rego": authzPolicy})
+	e, err := Load(ctx, dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "authz.rego"), []byte("package nfa.authz\n\nallow {"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Reload(ctx); err == nil {
+		t.Errorf("Reload() of an invalid bundle succeeded, want an error")
+	}
+	// The previous policies stay active
+	if got, err := e.Authorize(ctx, policy.Input{Action: "lights_on"}); err != nil || !got.Allowed {
+		t.Errorf("Authorize() after a failed reload = %+v, %v, want it allowed", got, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "authz.rego"), []byte("package nfa.authz\n\nallow = true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Reload(ctx); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got, err := e.Authorize(ctx, policy.Input{Action: "unlock"}); err != nil || !got.Allowed {
+		t.Errorf("Authorize() after a reload = %+v, %v, want it allowed", got, err)
+	}
+}
@@ -0,0 +1,111 @@
+// Package policy defines the authorization and routing policy engines used by
+// the Intent Broker. The built-in engine evaluates simple allow/deny rules;
+// package policy/opa provides an alternative backed by an OPA bundle.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+)
+
+// Input describes an intent request being authorized or routed
+type Input struct {
+	Consumer   string                 `json:"consumer"`
+	Namespace  string                 `json:"namespace,omitempty"`
+	Action     string                 `json:"action"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Labels     map[string]string      `json:"labels,omitempty"`
+	// User and Session identify the end user on whose behalf the intent is
+	// invoked, as in the intent's context
+	User    string `json:"user,omitempty"`
+	Session string `json:"session,omitempty"`
+	// Classification is the data classification declared by the contracts
+	// serving the action, see ResidencyGuard
+	Classification *Classification `json:"classification,omitempty"`
+}
+
+// Candidate is a provider that could serve an intent
+type Candidate struct {
+	ServiceID string            `json:"service_id"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Weight    float64           `json:"weight"`
+}
+
+// Decision is the outcome of an authorization check
+type Decision struct {
+	Allowed bool
+	Reason  string
+}
+
+// Engine evaluates authorization and routing policies
+type Engine interface {
+	// Authorize decides whether the consumer may invoke the intent
+	Authorize(ctx context.Context, input Input) (Decision, error)
+	// Route filters and orders candidates, most preferred first
+	Route(ctx context.Context, input Input, candidates []Candidate) ([]Candidate, error)
+}
+
+// Rule allows or denies actions for consumers. Actions and consumers are
+// path.Match patterns; an empty list matches everything.
+type Rule struct {
+	Effect    string   `toml:"effect" yaml:"effect"` // "allow" or "deny"
+	Actions   []string `toml:"actions,omitempty" yaml:"actions,omitempty"`
+	Consumers []string `toml:"consumers,omitempty" yaml:"consumers,omitempty"`
+}
+
+// Builtin is the default policy engine. Rules are evaluated in order and the
+// first matching rule wins; routing orders candidates by descending weight.
+type Builtin struct {
+	Rules        []Rule
+	DefaultAllow bool
+}
+
+// NewBuiltin creates the built-in engine
+func NewBuiltin(rules []Rule, defaultAllow bool) *Builtin {
+	return &Builtin{
+		Rules:        rules,
+		DefaultAllow: defaultAllow,
+	}
+}
+
+// Authorize implements Engine
+func (b *Builtin) Authorize(ctx context.Context, input Input) (Decision, error) {
+	for i, rule := range b.Rules {
+		if !matchAny(rule.Actions, input.Action) || !matchAny(rule.Consumers, input.Consumer) {
+			continue
+		}
+		if rule.Effect == "deny" {
+			return Decision{Allowed: false, Reason: ruleReason(i, rule)}, nil
+		}
+		return Decision{Allowed: true, Reason: ruleReason(i, rule)}, nil
+	}
+	if b.DefaultAllow {
+		return Decision{Allowed: true, Reason: "allowed by default"}, nil
+	}
+	return Decision{Allowed: false, Reason: "no rule allows this intent"}, nil
+}
+
+// Route implements Engine
+func (b *Builtin) Route(ctx context.Context, input Input, candidates []Candidate) ([]Candidate, error) {
+	ordered := append([]Candidate(nil), candidates...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Weight > ordered[j].Weight })
+	return ordered, nil
+}
+
+func matchAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func ruleReason(index int, rule Rule) string {
+	return fmt.Sprintf("rule %d (%s)", index, rule.Effect)
+}
@@ -0,0 +1,218 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Labels compared when enforcing residency: consumers and providers carry the
+// device they run on and the region they are deployed in
+const (
+	LabelDevice = "nfa.device"
+	LabelRegion = "nfa.region"
+)
+
+// Residency classes, matching contract.Residency
+const (
+	ResidencyOnDevice = "on-device"
+	ResidencyRegion   = "region"
+)
+
+// maxRefusals bounds the in-memory refusal audit trail
+const maxRefusals = 100
+
+// ErrNoCompliantProvider is returned by ResidencyGuard.Route when every
+// candidate lies outside the boundary the intent's data may not leave
+var ErrNoCompliantProvider = errors.New("no provider within the allowed data boundary")
+
+// Classification restricts which providers may serve an intent. It is
+// declared by contracts (contract.DataClassification) and by broker policy.
+type Classification struct {
+	Residency string   `json:"residency,omitempty"`
+	Regions   []string `json:"regions,omitempty"`
+}
+
+// ResidencyRule classifies the data of the matching actions in broker policy,
+// in addition to the classification declared by contracts. Actions are
+// path.Match patterns; an empty list matches everything.
+type ResidencyRule struct {
+	Actions   []string `toml:"actions,omitempty" yaml:"actions,omitempty"`
+	Residency string   `toml:"residency" yaml:"residency"`
+	Regions   []string `toml:"regions,omitempty" yaml:"regions,omitempty"`
+}
+
+// Refusal records a provider the guard refused to route an intent to
+type Refusal struct {
+	Time      time.Time
+	Consumer  string
+	Action    string
+	ServiceID string
+	Residency string
+	Reason    string
+}
+
+// ResidencyGuard wraps an engine and removes the candidates outside an
+// intent's data boundary before the engine orders them. Every refused
+// candidate is logged and recorded for audit.
+type ResidencyGuard struct {
+	engine Engine
+	rules  []ResidencyRule
+
+	mu        sync.Mutex
+	refusals  []Refusal // oldest first
+	listeners []func(Refusal)
+}
+
+var _ Engine = (*ResidencyGuard)(nil)
+
+// NewResidencyGuard creates a guard enforcing the classification of
+// input.Classification and of the given rules in front of engine
+func NewResidencyGuard(engine Engine, rules []ResidencyRule) *ResidencyGuard {
+	return &ResidencyGuard{
+		engine: engine,
+		rules:  rules,
+	}
+}
+
+// OnRefusal registers a callback invoked for every refused candidate, e.g. to
+// forward refusals to an audit webhook
+func (g *ResidencyGuard) OnRefusal(fn func(Refusal)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.listeners = append(g.listeners, fn)
+}
+
+// AuditLog returns a copy of the recorded refusals, oldest first
+func (g *ResidencyGuard) AuditLog() []Refusal {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]Refusal(nil), g.refusals...)
+}
+
+// Classify returns the effective classification of an intent: the strictest
+// of its declared classification and every matching rule, or nil when the
+// intent is unrestricted
+func (g *ResidencyGuard) Classify(input Input) *Classification {
+	cls := input.Classification
+	for _, rule := range g.rules {
+		if matchAny(rule.Actions, input.Action) {
+			cls = cls.merge(&Classification{Residency: rule.Residency, Regions: rule.Regions})
+		}
+	}
+	return cls
+}
+
+// Authorize implements Engine
+func (g *ResidencyGuard) Authorize(ctx context.Context, input Input) (Decision, error) {
+	input.Classification = g.Classify(input)
+	return g.engine.Authorize(ctx, input)
+}
+
+// Route implements Engine
+func (g *ResidencyGuard) Route(ctx context.Context, input Input, candidates []Candidate) ([]Candidate, error) {
+	cls := g.Classify(input)
+	if cls == nil {
+		return g.engine.Route(ctx, input, candidates)
+	}
+	input.Classification = cls
+
+	allowed := make([]Candidate, 0, len(candidates))
+	for _, c := range candidates {
+		if reason := cls.refuse(input.Labels, c.Labels); reason != "" {
+			g.refuse(Refusal{
+				Time:      time.Now(),
+				Consumer:  input.Consumer,
+				Action:    input.Action,
+				ServiceID: c.ServiceID,
+				Residency: cls.Residency,
+				Reason:    reason,
+			})
+			continue
+		}
+		allowed = append(allowed, c)
+	}
+	if len(allowed) == 0 && len(candidates) > 0 {
+		return nil, fmt.Errorf("%w: %s is %s", ErrNoCompliantProvider, input.Action, cls)
+	}
+	return g.engine.Route(ctx, input, allowed)
+}
+
+func (g *ResidencyGuard) refuse(r Refusal) {
+	log.Printf("Refused to route %s from %s to %s: %s", r.Action, r.Consumer, r.ServiceID, r.Reason)
+
+	g.mu.Lock()
+	g.refusals = append(g.refusals, r)
+	if len(g.refusals) > maxRefusals {
+		g.refusals = g.refusals[1:]
+	}
+	listeners := append([]func(Refusal){}, g.listeners...)
+	g.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(r)
+	}
+}
+
+// merge returns the stricter of two classifications. On-device wins over a
+// region restriction, and two region restrictions allow only the regions
+// both allow.
+func (c *Classification) merge(other *Classification) *Classification {
+	switch {
+	case other == nil || other.Residency == "":
+		return c
+	case c == nil || c.Residency == "":
+		return other
+	case c.Residency == ResidencyOnDevice:
+		return c
+	case other.Residency == ResidencyOnDevice:
+		return other
+	}
+	merged := &Classification{Residency: ResidencyRegion, Regions: []string{}}
+	for _, region := range c.Regions {
+		if contains(other.Regions, region) {
+			merged.Regions = append(merged.Regions, region)
+		}
+	}
+	return merged
+}
+
+// refuse returns why a provider with the given labels may not serve a
+// consumer with the given labels, or "" when it may
+func (c *Classification) refuse(consumer, provider map[string]string) string {
+	switch c.Residency {
+	case ResidencyOnDevice:
+		device := consumer[LabelDevice]
+		if device == "" {
+			return "consumer does not report its device (" + LabelDevice + ")"
+		}
+		if provider[LabelDevice] != device {
+			return fmt.Sprintf("provider is not on the consumer's device %s", device)
+		}
+	case ResidencyRegion:
+		if region := provider[LabelRegion]; !contains(c.Regions, region) {
+			return fmt.Sprintf("provider region %q is not one of %s", region, strings.Join(c.Regions, ", "))
+		}
+	}
+	return ""
+}
+
+func (c *Classification) String() string {
+	if c.Residency == ResidencyRegion {
+		return "restricted to regions " + strings.Join(c.Regions, ", ")
+	}
+	return c.Residency + "-only"
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
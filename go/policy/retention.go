@@ -0,0 +1,51 @@
+package policy
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/retention"
+)
+
+var _ retention.Store = (*ResidencyGuard)(nil)
+
+// Usage implements retention.Store over the refusal audit trail
+func (g *ResidencyGuard) Usage() retention.Usage {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	usage := retention.Usage{Entries: len(g.refusals)}
+	for _, r := range g.refusals {
+		usage.Bytes += refusalSize(r)
+	}
+	if len(g.refusals) > 0 {
+		usage.Oldest = g.refusals[0].Time
+	}
+	return usage
+}
+
+// Compact implements retention.Store, on top of the bound of maxRefusals.
+// Refusals already forwarded to OnRefusal listeners are not affected.
+func (g *ResidencyGuard) Compact(before time.Time, maxBytes int64) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	drop := 0
+	for drop < len(g.refusals) && !before.IsZero() && g.refusals[drop].Time.Before(before) {
+		drop++
+	}
+	if maxBytes > 0 {
+		var size int64
+		for _, r := range g.refusals[drop:] {
+			size += refusalSize(r)
+		}
+		for ; drop < len(g.refusals) && size > maxBytes; drop++ {
+			size -= refusalSize(g.refusals[drop])
+		}
+	}
+	g.refusals = g.refusals[drop:]
+	return drop
+}
+
+// refusalSize approximates the memory held by a refusal
+func refusalSize(r Refusal) int64 {
+	return int64(unsafe.Sizeof(r)) + int64(len(r.Consumer)+len(r.Action)+len(r.ServiceID)+len(r.Residency)+len(r.Reason))
+}
@@ -0,0 +1,57 @@
+package privacy
+
+import (
+	"context"
+	"fmt"
+
+	nfa_privacy_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/privacy/v1alpha"
+	"google.golang.org/grpc"
+)
+
+// Client issues data subject requests to a broker
+type Client struct {
+	client nfa_privacy_v1alpha.DataSubjectServiceClient
+}
+
+// NewClient creates a data subject client on an existing broker connection
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{
+		client: nfa_privacy_v1alpha.NewDataSubjectServiceClient(cc),
+	}
+}
+
+// List returns the records held about a user, without their data
+func (c *Client) List(ctx context.Context, userID string) ([]*nfa_privacy_v1alpha.SubjectRecord, error) {
+	resp, err := c.client.ListSubjectData(ctx, &nfa_privacy_v1alpha.SubjectRequest{UserId: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subject data: %w", err)
+	}
+	return resp.Records, nil
+}
+
+// Export returns the records held about a user, including their data
+func (c *Client) Export(ctx context.Context, userID string) (*nfa_privacy_v1alpha.SubjectExport, error) {
+	export, err := c.client.ExportSubjectData(ctx, &nfa_privacy_v1alpha.SubjectRequest{UserId: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export subject data: %w", err)
+	}
+	return export, nil
+}
+
+// Purge erases the records held about a user and returns the receipt
+func (c *Client) Purge(ctx context.Context, userID string) (*nfa_privacy_v1alpha.PurgeReceipt, error) {
+	receipt, err := c.client.PurgeSubjectData(ctx, &nfa_privacy_v1alpha.SubjectRequest{UserId: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge subject data: %w", err)
+	}
+	return receipt, nil
+}
+
+// Receipt looks up the receipt of an earlier purge
+func (c *Client) Receipt(ctx context.Context, id string) (*nfa_privacy_v1alpha.PurgeReceipt, error) {
+	receipt, err := c.client.GetPurgeReceipt(ctx, &nfa_privacy_v1alpha.GetPurgeReceiptRequest{Id: id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipt %s: %w", id, err)
+	}
+	return receipt, nil
+}
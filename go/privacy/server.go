@@ -0,0 +1,182 @@
+// Package privacy serves data subject requests: it enumerates, exports and
+// erases everything the broker's stores hold about one user (sessions,
+// events, memories, preferences) and issues a receipt for every purge.
+// Each persistence layer takes part by implementing Store.
+package privacy
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"sort"
+	"sync"
+
+	nfa_privacy_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/privacy/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxReceipts bounds the purge receipts kept for lookup
+const maxReceipts = 1000
+
+// Store is a persistence layer holding data about users
+type Store interface {
+	// Name identifies the store in records and receipts
+	Name() string
+	// Records returns the records held about a user, including their data
+	Records(ctx context.Context, userID string) ([]*nfa_privacy_v1alpha.SubjectRecord, error)
+	// Purge erases the records held about a user and returns their IDs
+	Purge(ctx context.Context, userID string) ([]string, error)
+}
+
+// Server implements the data subject service over a set of stores
+type Server struct {
+	nfa_privacy_v1alpha.UnimplementedDataSubjectServiceServer
+
+	stores []Store
+
+	mu       sync.Mutex
+	receipts map[string]*nfa_privacy_v1alpha.PurgeReceipt
+	order    []string // receipt IDs, oldest first
+}
+
+// NewServer creates a data subject server covering the given stores
+func NewServer(stores ...Store) *Server {
+	return &Server{
+		stores:   stores,
+		receipts: make(map[string]*nfa_privacy_v1alpha.PurgeReceipt),
+	}
+}
+
+// Register registers the data subject service on a gRPC server
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	nfa_privacy_v1alpha.RegisterDataSubjectServiceServer(registrar, s)
+}
+
+// ListSubjectData implements the ListSubjectData RPC
+func (s *Server) ListSubjectData(ctx context.Context, req *nfa_privacy_v1alpha.SubjectRequest) (*nfa_privacy_v1alpha.ListSubjectDataResponse, error) {
+	records, err := s.records(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		r.Data = nil
+	}
+	return &nfa_privacy_v1alpha.ListSubjectDataResponse{Records: records}, nil
+}
+
+// ExportSubjectData implements the ExportSubjectData RPC
+func (s *Server) ExportSubjectData(ctx context.Context, req *nfa_privacy_v1alpha.SubjectRequest) (*nfa_privacy_v1alpha.SubjectExport, error) {
+	records, err := s.records(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Exported %d records of a data subject", len(records))
+	return &nfa_privacy_v1alpha.SubjectExport{
+		UserId:     req.UserId,
+		Records:    records,
+		ExportTime: timestamppb.Now(),
+	}, nil
+}
+
+// PurgeSubjectData implements the PurgeSubjectData RPC. A store that fails
+// does not stop the others; the receipt then reports the purge as incomplete
+// and the request can be repeated.
+func (s *Server) PurgeSubjectData(ctx context.Context, req *nfa_privacy_v1alpha.SubjectRequest) (*nfa_privacy_v1alpha.PurgeReceipt, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	receipt := &nfa_privacy_v1alpha.PurgeReceipt{
+		Id:          "rcpt-" + randomHex(8),
+		UserId:      req.UserId,
+		RequestTime: timestamppb.Now(),
+		Complete:    true,
+	}
+	var erased []string
+	for _, store := range s.stores {
+		result := &nfa_privacy_v1alpha.StorePurgeResult{Store: store.Name()}
+		ids, err := store.Purge(ctx, req.UserId)
+		if err != nil {
+			result.Error = err.Error()
+			receipt.Complete = false
+			log.Printf("Purge %s failed in store %s: %v", receipt.Id, store.Name(), err)
+		}
+		result.Purged = uint32(len(ids))
+		for _, id := range ids {
+			erased = append(erased, store.Name()+"/"+id)
+		}
+		receipt.Stores = append(receipt.Stores, result)
+	}
+	receipt.Digest = digest(erased)
+	receipt.CompleteTime = timestamppb.Now()
+
+	s.mu.Lock()
+	s.receipts[receipt.Id] = receipt
+	s.order = append(s.order, receipt.Id)
+	if len(s.order) > maxReceipts {
+		delete(s.receipts, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.mu.Unlock()
+
+	log.Printf("Purge %s erased %d records, complete: %t", receipt.Id, len(erased), receipt.Complete)
+	return proto.Clone(receipt).(*nfa_privacy_v1alpha.PurgeReceipt), nil
+}
+
+// GetPurgeReceipt implements the GetPurgeReceipt RPC
+func (s *Server) GetPurgeReceipt(ctx context.Context, req *nfa_privacy_v1alpha.GetPurgeReceiptRequest) (*nfa_privacy_v1alpha.PurgeReceipt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	receipt, ok := s.receipts[req.Id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "receipt %s not found", req.Id)
+	}
+	return proto.Clone(receipt).(*nfa_privacy_v1alpha.PurgeReceipt), nil
+}
+
+// records collects the records of a user from every store, ordered by store and ID
+func (s *Server) records(ctx context.Context, userID string) ([]*nfa_privacy_v1alpha.SubjectRecord, error) {
+	if userID == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	var records []*nfa_privacy_v1alpha.SubjectRecord
+	for _, store := range s.stores {
+		found, err := store.Records(ctx, userID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to read store %s: %v", store.Name(), err)
+		}
+		for _, r := range found {
+			r.Store = store.Name()
+		}
+		records = append(records, found...)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Store != records[j].Store {
+			return records[i].Store < records[j].Store
+		}
+		return records[i].Id < records[j].Id
+	})
+	return records, nil
+}
+
+// digest returns the hex SHA-256 of the sorted record references
+func digest(refs []string) string {
+	sort.Strings(refs)
+	h := sha256.New()
+	for _, ref := range refs {
+		h.Write([]byte(ref))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,138 @@
+package privacy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	nfa_privacy_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/privacy/v1alpha"
+	nfa_pubsub_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/pubsub/v1alpha"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pubsub"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// failingStore holds records it cannot purge
+type failingStore struct{ *MemoryStore }
+
+func (failingStore) Purge(ctx context.Context, userID string) ([]string, error) {
+	return nil, errors.New("disk full")
+}
+
+func newSubjectServer(t *testing.T) *Server {
+	t.Helper()
+	ctx := context.Background()
+	events := pubsub.NewBroker(0)
+	for _, user := range []string{"u1", "u2", "u1"} {
+		_, err := events.Publish(ctx, &nfa_pubsub_v1alpha.PublishRequest{
+			Topic:      "nfa.home.door_opened",
+			Payload:    []byte("opened by " + user),
+			Attributes: map[string]string{pubsub.AttributeUserID: user},
+		})
+		if err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	sessions := NewMemoryStore("sessions", nfa_privacy_v1alpha.DataCategory_DATA_CATEGORY_SESSIONS)
+	sessions.Put("u1", "s1", []byte(`{"lang":"de"}`), "application/json")
+	sessions.Put("u2", "s2", []byte(`{"lang":"fr"}`), "application/json")
+	return NewServer(Events(events), sessions)
+}
+
+func TestExportSubjectData(t *testing.T) {
+	s := newSubjectServer(t)
+	ctx := context.Background()
+	export, err := s.ExportSubjectData(ctx, &nfa_privacy_v1alpha.SubjectRequest{UserId: "u1"})
+	if err != nil {
+		t.Fatalf("ExportSubjectData() error = %v", err)
+	}
+	var got []string
+	for _, r := range export.Records {
+		got = append(got, r.Store+"/"+r.Id)
+		if len(r.Data) == 0 {
+			t.Errorf("exported record %s/%s has no data", r.Store, r.Id)
+		}
+	}
+	want := []string{"events/nfa.home.door_opened/1", "events/nfa.home.door_opened/3", "sessions/s1"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ExportSubjectData() records = %v, want %v", got, want)
+	}
+	var event struct {
+		Payload []byte `json:"payload"`
+	}
+	if err := json.Unmarshal(export.Records[0].Data, &event); err != nil || string(event.Payload) != "opened by u1" {
+		t.Errorf("exported event = %s, %v, want its payload", export.Records[0].Data, err)
+	}
+
+	list, err := s.ListSubjectData(ctx, &nfa_privacy_v1alpha.SubjectRequest{UserId: "u1"})
+	if err != nil || len(list.Records) != 3 {
+		t.Fatalf("ListSubjectData() = %v, %v, want 3 records", list, err)
+	}
+	for _, r := range list.Records {
+		if r.Data != nil {
+			t.Errorf("listed record %s/%s has data, want it left out", r.Store, r.Id)
+		}
+	}
+
+	if _, err := s.ExportSubjectData(ctx, &nfa_privacy_v1alpha.SubjectRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ExportSubjectData() without a user error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestPurgeSubjectData(t *testing.T) {
+	s := newSubjectServer(t)
+	ctx := context.Background()
+	receipt, err := s.PurgeSubjectData(ctx, &nfa_privacy_v1alpha.SubjectRequest{UserId: "u1"})
+	if err != nil {
+		t.Fatalf("PurgeSubjectData() error = %v", err)
+	}
+	if !receipt.Complete || len(receipt.Stores) != 2 || receipt.Stores[0].Purged != 2 || receipt.Stores[1].Purged != 1 {
+		t.Errorf("PurgeSubjectData() = %v, want a complete purge of 2 events and 1 session", receipt)
+	}
+	want := digest([]string{"events/nfa.home.door_opened/1", "events/nfa.home.door_opened/3", "sessions/s1"})
+	if receipt.Digest != want {
+		t.Errorf("receipt digest = %s, want the digest of the erased records %s", receipt.Digest, want)
+	}
+
+	// Nothing of u1 is left, and everything of u2 is
+	export, err := s.ExportSubjectData(ctx, &nfa_privacy_v1alpha.SubjectRequest{UserId: "u1"})
+	if err != nil || len(export.Records) != 0 {
+		t.Errorf("ExportSubjectData(u1) after the purge = %v, %v, want no records", export, err)
+	}
+	export, err = s.ExportSubjectData(ctx, &nfa_privacy_v1alpha.SubjectRequest{UserId: "u2"})
+	if err != nil || len(export.Records) != 2 {
+		t.Errorf("ExportSubjectData(u2) after purging u1 = %v, %v, want its 2 records", export, err)
+	}
+
+	stored, err := s.GetPurgeReceipt(ctx, &nfa_privacy_v1alpha.GetPurgeReceiptRequest{Id: receipt.Id})
+	if err != nil || stored.Digest != receipt.Digest {
+		t.Errorf("GetPurgeReceipt() = %v, %v, want the receipt", stored, err)
+	}
+	again, err := s.PurgeSubjectData(ctx, &nfa_privacy_v1alpha.SubjectRequest{UserId: "u1"})
+	if err != nil || !again.Complete || again.Stores[0].Purged != 0 || again.Stores[1].Purged != 0 {
+		t.Errorf("PurgeSubjectData() repeated = %v, %v, want a complete purge of nothing", again, err)
+	}
+}
+
+func TestPurgeSubjectDataIncomplete(t *testing.T) {
+	ctx := context.Background()
+	sessions := NewMemoryStore("sessions", nfa_privacy_v1alpha.DataCategory_DATA_CATEGORY_SESSIONS)
+	sessions.Put("u1", "s1", nil, "")
+	broken := failingStore{NewMemoryStore("preferences", nfa_privacy_v1alpha.DataCategory_DATA_CATEGORY_PREFERENCES)}
+	broken.Put("u1", "p1", nil, "")
+	s := NewServer(broken, sessions)
+
+	receipt, err := s.PurgeSubjectData(ctx, &nfa_privacy_v1alpha.SubjectRequest{UserId: "u1"})
+	if err != nil {
+		t.Fatalf("PurgeSubjectData() error = %v", err)
+	}
+	if receipt.Complete || receipt.Stores[0].Error != "disk full" || receipt.Stores[1].Purged != 1 {
+		t.Errorf("PurgeSubjectData() = %v, want the failing store reported and the other purged", receipt)
+	}
+	list, _ := s.ListSubjectData(ctx, &nfa_privacy_v1alpha.SubjectRequest{UserId: "u1"})
+	if len(list.Records) != 1 || list.Records[0].Store != "preferences" {
+		t.Errorf("ListSubjectData() after the incomplete purge = %v, want the preferences left", list.Records)
+	}
+}
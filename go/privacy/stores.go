@@ -0,0 +1,121 @@
+package privacy
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	nfa_privacy_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/privacy/v1alpha"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pubsub"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Events returns the store of a pub/sub broker's retained events. Events are
+// attributed to a user by their pubsub.AttributeUserID attribute; purged
+// events are replaced by tombstones.
+func Events(b *pubsub.Broker) Store {
+	return eventStore{broker: b}
+}
+
+type eventStore struct {
+	broker *pubsub.Broker
+}
+
+func (eventStore) Name() string { return "events" }
+
+func (s eventStore) Records(ctx context.Context, userID string) ([]*nfa_privacy_v1alpha.SubjectRecord, error) {
+	events := s.broker.SubjectEvents(userID)
+	records := make([]*nfa_privacy_v1alpha.SubjectRecord, 0, len(events))
+	for id, event := range events {
+		data, err := json.Marshal(map[string]interface{}{
+			"topic":      event.Topic,
+			"sequence":   event.Sequence,
+			"attributes": event.Attributes,
+			"payload":    event.Payload,
+		})
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, &nfa_privacy_v1alpha.SubjectRecord{
+			Category:    nfa_privacy_v1alpha.DataCategory_DATA_CATEGORY_EVENTS,
+			Id:          id,
+			CreateTime:  event.PublishTime,
+			Data:        data,
+			ContentType: "application/json",
+		})
+	}
+	return records, nil
+}
+
+func (s eventStore) Purge(ctx context.Context, userID string) ([]string, error) {
+	return s.broker.EraseSubject(userID), nil
+}
+
+// MemoryStore is an in-memory Store of one category of user data, for tests
+// and for brokers keeping sessions or preferences in memory
+type MemoryStore struct {
+	name     string
+	category nfa_privacy_v1alpha.DataCategory
+
+	mu      sync.Mutex
+	records map[string]map[string]*nfa_privacy_v1alpha.SubjectRecord // user -> id -> record
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore(name string, category nfa_privacy_v1alpha.DataCategory) *MemoryStore {
+	return &MemoryStore{
+		name:     name,
+		category: category,
+		records:  make(map[string]map[string]*nfa_privacy_v1alpha.SubjectRecord),
+	}
+}
+
+// Put stores or replaces a record about a user
+func (m *MemoryStore) Put(userID, id string, data []byte, contentType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.records[userID] == nil {
+		m.records[userID] = make(map[string]*nfa_privacy_v1alpha.SubjectRecord)
+	}
+	m.records[userID][id] = &nfa_privacy_v1alpha.SubjectRecord{
+		Category:    m.category,
+		Id:          id,
+		CreateTime:  timestamppb.Now(),
+		Data:        data,
+		ContentType: contentType,
+	}
+}
+
+// Name implements Store
+func (m *MemoryStore) Name() string { return m.name }
+
+// Records implements Store
+func (m *MemoryStore) Records(ctx context.Context, userID string) ([]*nfa_privacy_v1alpha.SubjectRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	records := make([]*nfa_privacy_v1alpha.SubjectRecord, 0, len(m.records[userID]))
+	for _, r := range m.records[userID] {
+		records = append(records, &nfa_privacy_v1alpha.SubjectRecord{
+			Category:    r.Category,
+			Id:          r.Id,
+			CreateTime:  r.CreateTime,
+			Data:        append([]byte(nil), r.Data...),
+			ContentType: r.ContentType,
+		})
+	}
+	return records, nil
+}
+
+// Purge implements Store
+func (m *MemoryStore) Purge(ctx context.Context, userID string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ids := make([]string, 0, len(m.records[userID]))
+	for id := range m.records[userID] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	delete(m.records, userID)
+	return ids, nil
+}
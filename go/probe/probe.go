@@ -0,0 +1,327 @@
+// Package probe runs synthetic monitoring: the broker periodically fires
+// canary invocations, an action with example parameters, at every provider
+// selected by a probe and validates the answers. The results feed provider
+// health and SLO tracking with active measurements, so a broken provider is
+// noticed before real traffic fails on it.
+package probe
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+)
+
+// Defaults of optional probe settings
+const (
+	DefaultIntervalSecs     = 60
+	DefaultTimeoutSecs      = 10
+	DefaultFailureThreshold = 3
+	DefaultWindow           = 100
+)
+
+// Probe is a synthetic invocation fired periodically at every runtime
+// matching Selector
+type Probe struct {
+	Name       string            `toml:"name" yaml:"name"`
+	Action     string            `toml:"action" yaml:"action"`
+	Parameters map[string]string `toml:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Payload    string            `toml:"payload,omitempty" yaml:"payload,omitempty"`
+	// Selector selects the runtimes to probe; empty probes every runtime
+	Selector     map[string]string `toml:"selector,omitempty" yaml:"selector,omitempty"`
+	IntervalSecs int               `toml:"interval_secs,omitempty" yaml:"interval_secs,omitempty"`
+	TimeoutSecs  int               `toml:"timeout_secs,omitempty" yaml:"timeout_secs,omitempty"`
+	Expect       Expectation       `toml:"expect,omitempty" yaml:"expect,omitempty"`
+	// FailureThreshold is the number of consecutive failures after which a
+	// provider is reported unhealthy
+	FailureThreshold int `toml:"failure_threshold,omitempty" yaml:"failure_threshold,omitempty"`
+	// SLO is the target success ratio over the last Window results, e.g. 0.99;
+	// 0 disables SLO tracking
+	SLO    float64 `toml:"slo,omitempty" yaml:"slo,omitempty"`
+	Window int     `toml:"window,omitempty" yaml:"window,omitempty"`
+}
+
+// Expectation validates the answer to a probe. A successful answer passes
+// when it meets every condition set.
+type Expectation struct {
+	// Contains is a substring of the output
+	Contains string `toml:"contains,omitempty" yaml:"contains,omitempty"`
+	// Matches is a regular expression the output must match
+	Matches string `toml:"matches,omitempty" yaml:"matches,omitempty"`
+	// MaxLatencyMs bounds the time to answer
+	MaxLatencyMs int `toml:"max_latency_ms,omitempty" yaml:"max_latency_ms,omitempty"`
+}
+
+// Validate checks that the probe can run
+func (p *Probe) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("probe name is required")
+	}
+	if p.Action == "" {
+		return fmt.Errorf("probe %s: action is required", p.Name)
+	}
+	if p.IntervalSecs < 0 || p.TimeoutSecs < 0 || p.FailureThreshold < 0 || p.Window < 0 || p.Expect.MaxLatencyMs < 0 {
+		return fmt.Errorf("probe %s: intervals, timeouts, thresholds and windows must not be negative", p.Name)
+	}
+	if p.SLO < 0 || p.SLO > 1 {
+		return fmt.Errorf("probe %s: slo %v is not between 0 and 1", p.Name, p.SLO)
+	}
+	if p.Expect.Matches != "" {
+		if _, err := regexp.Compile(p.Expect.Matches); err != nil {
+			return fmt.Errorf("probe %s: invalid expect.matches: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// Result is the outcome of one probe at one runtime
+type Result struct {
+	Time      time.Time
+	Probe     string
+	RuntimeID string
+	Success   bool
+	// Error explains a failure: the provider's error, a timeout or a failed
+	// expectation
+	Error   string
+	Latency time.Duration
+}
+
+// Status is the health of one runtime as measured by one probe
+type Status struct {
+	Probe     string
+	RuntimeID string
+	Healthy   bool
+	// ConsecutiveFailures counts the failures since the last success
+	ConsecutiveFailures int
+	// Availability is the success ratio over the probe's window
+	Availability float64
+	// SLOMet reports whether Availability meets the probe's SLO; always true
+	// without an SLO
+	SLOMet bool
+	Last   Result
+}
+
+// Broadcaster sends an intent to the runtimes matching a selector and
+// collects their results, as control.Hub does
+type Broadcaster interface {
+	Broadcast(ctx context.Context, req *nfa_control_v1alpha.BroadcastRequest) (*nfa_control_v1alpha.BroadcastResponse, error)
+}
+
+// Prober fires the configured probes and tracks their results
+type Prober struct {
+	broadcaster Broadcaster
+	probes      []Probe
+	matchers    map[string]*regexp.Regexp
+
+	mu        sync.Mutex
+	statuses  map[[2]string]*tracker // probe, runtime ID -> status
+	listeners []func(Result)
+	changes   []func(Status)
+}
+
+type tracker struct {
+	status  Status
+	history []bool // most recent last
+}
+
+// NewProber creates a prober firing probes through broadcaster. Probes are
+// expected to be valid.
+func NewProber(broadcaster Broadcaster, probes []Probe) *Prober {
+	p := &Prober{
+		broadcaster: broadcaster,
+		probes:      probes,
+		matchers:    make(map[string]*regexp.Regexp),
+		statuses:    make(map[[2]string]*tracker),
+	}
+	for _, probe := range probes {
+		if probe.Expect.Matches != "" {
+			p.matchers[probe.Name] = regexp.MustCompile(probe.Expect.Matches)
+		}
+	}
+	return p
+}
+
+// OnResult registers a callback invoked with every probe result
+func (p *Prober) OnResult(fn func(Result)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.listeners = append(p.listeners, fn)
+}
+
+// OnHealthChange registers a callback invoked when a runtime becomes
+// unhealthy or recovers, or its SLO starts or stops being met
+func (p *Prober) OnHealthChange(fn func(Status)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.changes = append(p.changes, fn)
+}
+
+// Run fires every probe at its interval until ctx is cancelled
+func (p *Prober) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, probe := range p.probes {
+		wg.Add(1)
+		go func(probe Probe) {
+			defer wg.Done()
+			interval := time.Duration(orDefault(probe.IntervalSecs, DefaultIntervalSecs)) * time.Second
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				p.Fire(ctx, probe)
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(probe)
+	}
+	wg.Wait()
+}
+
+// Fire runs a probe once at every runtime it selects and returns the results
+func (p *Prober) Fire(ctx context.Context, probe Probe) []Result {
+	timeout := orDefault(probe.TimeoutSecs, DefaultTimeoutSecs)
+	start := time.Now()
+	resp, err := p.broadcaster.Broadcast(ctx, &nfa_control_v1alpha.BroadcastRequest{
+		Selector: probe.Selector,
+		Intent: &nfa_control_v1alpha.Invoke{
+			Action:     probe.Action,
+			Parameters: probe.Parameters,
+			Payload:    []byte(probe.Payload),
+			Synthetic:  true,
+		},
+		TimeoutSecs: uint32(timeout),
+	})
+	if err != nil {
+		log.Printf("Failed to fire probe %s: %v", probe.Name, err)
+		return nil
+	}
+	elapsed := time.Since(start)
+
+	results := make([]Result, 0, len(resp.Targets))
+	for _, target := range resp.Targets {
+		res := Result{Time: start, Probe: probe.Name, RuntimeID: target.RuntimeId, Latency: elapsed}
+		if f := target.Fulfillment; f != nil {
+			res.Latency = time.Duration(f.QueueTimeMs+f.ProcessingTimeMs) * time.Millisecond
+		}
+		switch target.State {
+		case nfa_control_v1alpha.TargetState_TARGET_STATE_SUCCEEDED:
+			res.Error = p.check(probe, target.Output, res.Latency)
+		case nfa_control_v1alpha.TargetState_TARGET_STATE_FAILED:
+			res.Error = target.Error
+		default:
+			res.Error = strings.ToLower(strings.TrimPrefix(target.State.String(), "TARGET_STATE_"))
+		}
+		res.Success = res.Error == ""
+		p.record(probe, res)
+		results = append(results, res)
+	}
+	return results
+}
+
+// Statuses returns the status of every probed runtime, ordered by probe and
+// runtime ID
+func (p *Prober) Statuses() []Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]Status, 0, len(p.statuses))
+	for _, t := range p.statuses {
+		out = append(out, t.status)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Probe != out[j].Probe {
+			return out[i].Probe < out[j].Probe
+		}
+		return out[i].RuntimeID < out[j].RuntimeID
+	})
+	return out
+}
+
+// Healthy reports whether every probe of a runtime passes; runtimes without
+// probe results are healthy
+func (p *Prober) Healthy(runtimeID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for key, t := range p.statuses {
+		if key[1] == runtimeID && !t.status.Healthy {
+			return false
+		}
+	}
+	return true
+}
+
+// check validates a successful answer and returns why it fails, if it does
+func (p *Prober) check(probe Probe, output []byte, latency time.Duration) string {
+	if probe.Expect.Contains != "" && !strings.Contains(string(output), probe.Expect.Contains) {
+		return fmt.Sprintf("output does not contain %q", probe.Expect.Contains)
+	}
+	if re := p.matchers[probe.Name]; re != nil && !re.Match(output) {
+		return fmt.Sprintf("output does not match %q", probe.Expect.Matches)
+	}
+	if max := time.Duration(probe.Expect.MaxLatencyMs) * time.Millisecond; max > 0 && latency > max {
+		return fmt.Sprintf("latency %s exceeds %s", latency, max)
+	}
+	return ""
+}
+
+func (p *Prober) record(probe Probe, res Result) {
+	p.mu.Lock()
+	key := [2]string{probe.Name, res.RuntimeID}
+	t := p.statuses[key]
+	if t == nil {
+		t = &tracker{status: Status{Probe: probe.Name, RuntimeID: res.RuntimeID, Healthy: true, SLOMet: true}}
+		p.statuses[key] = t
+	}
+	before := t.status
+
+	t.history = append(t.history, res.Success)
+	if window := orDefault(probe.Window, DefaultWindow); len(t.history) > window {
+		t.history = t.history[len(t.history)-window:]
+	}
+	passed := 0
+	for _, ok := range t.history {
+		if ok {
+			passed++
+		}
+	}
+	t.status.Availability = float64(passed) / float64(len(t.history))
+	t.status.SLOMet = t.status.Availability >= probe.SLO
+	if res.Success {
+		t.status.ConsecutiveFailures = 0
+	} else {
+		t.status.ConsecutiveFailures++
+	}
+	t.status.Healthy = t.status.ConsecutiveFailures < orDefault(probe.FailureThreshold, DefaultFailureThreshold)
+	t.status.Last = res
+	status := t.status
+	listeners := append([]func(Result){}, p.listeners...)
+	var changes []func(Status)
+	if status.Healthy != before.Healthy || status.SLOMet != before.SLOMet {
+		changes = append(changes, p.changes...)
+	}
+	p.mu.Unlock()
+
+	if !res.Success {
+		log.Printf("Probe %s failed at runtime %s: %s", probe.Name, res.RuntimeID, res.Error)
+	}
+	for _, fn := range listeners {
+		fn(res)
+	}
+	for _, fn := range changes {
+		fn(status)
+	}
+}
+
+func orDefault(value, def int) int {
+	if value > 0 {
+		return value
+	}
+	return def
+}
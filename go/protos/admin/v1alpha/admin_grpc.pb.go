@@ -0,0 +1,710 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: admin/v1alpha/admin.proto
+
+package admin
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	AdminService_ReloadConfig_FullMethodName            = "/nfa.admin.v1alpha.AdminService/ReloadConfig"
+	AdminService_ListConfigChanges_FullMethodName       = "/nfa.admin.v1alpha.AdminService/ListConfigChanges"
+	AdminService_GetEffectiveConfig_FullMethodName      = "/nfa.admin.v1alpha.AdminService/GetEffectiveConfig"
+	AdminService_SetLogLevel_FullMethodName             = "/nfa.admin.v1alpha.AdminService/SetLogLevel"
+	AdminService_GetLogLevels_FullMethodName            = "/nfa.admin.v1alpha.AdminService/GetLogLevels"
+	AdminService_GetSLAReport_FullMethodName            = "/nfa.admin.v1alpha.AdminService/GetSLAReport"
+	AdminService_DrainNamespace_FullMethodName          = "/nfa.admin.v1alpha.AdminService/DrainNamespace"
+	AdminService_PurgeStaleRegistrations_FullMethodName = "/nfa.admin.v1alpha.AdminService/PurgeStaleRegistrations"
+	AdminService_RetagServices_FullMethodName           = "/nfa.admin.v1alpha.AdminService/RetagServices"
+	AdminService_GetStorageUsage_FullMethodName         = "/nfa.admin.v1alpha.AdminService/GetStorageUsage"
+	AdminService_GetDeprecationReport_FullMethodName    = "/nfa.admin.v1alpha.AdminService/GetDeprecationReport"
+	AdminService_GetErrorReport_FullMethodName          = "/nfa.admin.v1alpha.AdminService/GetErrorReport"
+	AdminService_CreateBootstrapToken_FullMethodName    = "/nfa.admin.v1alpha.AdminService/CreateBootstrapToken"
+	AdminService_RevokeDevice_FullMethodName            = "/nfa.admin.v1alpha.AdminService/RevokeDevice"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminServiceClient interface {
+	// Reload configuration from disk, rejecting invalid files atomically
+	ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error)
+	// List past configuration reload attempts
+	ListConfigChanges(ctx context.Context, in *ListConfigChangesRequest, opts ...grpc.CallOption) (*ListConfigChangesResponse, error)
+	// Show the redacted effective configuration and the source of each value
+	GetEffectiveConfig(ctx context.Context, in *GetEffectiveConfigRequest, opts ...grpc.CallOption) (*GetEffectiveConfigResponse, error)
+	// Change the log level of one component ("*" for all) at runtime
+	SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*SetLogLevelResponse, error)
+	// Get the current log level of every component
+	GetLogLevels(ctx context.Context, in *GetLogLevelsRequest, opts ...grpc.CallOption) (*GetLogLevelsResponse, error)
+	// Report availability and latency of providers against their declared QoS
+	GetSLAReport(ctx context.Context, in *GetSLAReportRequest, opts ...grpc.CallOption) (*SLAReport, error)
+	// Drain every provider of a namespace, streaming the outcome per provider
+	DrainNamespace(ctx context.Context, in *DrainNamespaceRequest, opts ...grpc.CallOption) (AdminService_DrainNamespaceClient, error)
+	// Remove registrations whose providers stopped sending heartbeats
+	PurgeStaleRegistrations(ctx context.Context, in *PurgeStaleRegistrationsRequest, opts ...grpc.CallOption) (AdminService_PurgeStaleRegistrationsClient, error)
+	// Set or remove labels of every service matching a selector
+	RetagServices(ctx context.Context, in *RetagServicesRequest, opts ...grpc.CallOption) (AdminService_RetagServicesClient, error)
+	// Report the data held by each retained store, e.g. event logs, analytics
+	// and audit trails, and its retention policy
+	GetStorageUsage(ctx context.Context, in *GetStorageUsageRequest, opts ...grpc.CallOption) (*GetStorageUsageResponse, error)
+	// Report who still calls deprecated actions, to tell when an alias can be
+	// removed
+	GetDeprecationReport(ctx context.Context, in *GetDeprecationReportRequest, opts ...grpc.CallOption) (*DeprecationReport, error)
+	// Report the errors providers' handlers returned, as their runtimes
+	// summarized them, by service, action and status code
+	GetErrorReport(ctx context.Context, in *GetErrorReportRequest, opts ...grpc.CallOption) (*ErrorReport, error)
+	// Create a one-time bootstrap token enrolling a device with the broker's
+	// built-in CA
+	CreateBootstrapToken(ctx context.Context, in *CreateBootstrapTokenRequest, opts ...grpc.CallOption) (*CreateBootstrapTokenResponse, error)
+	// Stop renewing the certificates of a device enrolled with the built-in
+	// CA and refuse its calls
+	RevokeDevice(ctx context.Context, in *RevokeDeviceRequest, opts ...grpc.CallOption) (*RevokeDeviceResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error) {
+	out := new(ReloadConfigResponse)
+	err := c.cc.Invoke(ctx, AdminService_ReloadConfig_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListConfigChanges(ctx context.Context, in *ListConfigChangesRequest, opts ...grpc.CallOption) (*ListConfigChangesResponse, error) {
+	out := new(ListConfigChangesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListConfigChanges_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetEffectiveConfig(ctx context.Context, in *GetEffectiveConfigRequest, opts ...grpc.CallOption) (*GetEffectiveConfigResponse, error) {
+	out := new(GetEffectiveConfigResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetEffectiveConfig_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*SetLogLevelResponse, error) {
+	out := new(SetLogLevelResponse)
+	err := c.cc.Invoke(ctx, AdminService_SetLogLevel_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetLogLevels(ctx context.Context, in *GetLogLevelsRequest, opts ...grpc.CallOption) (*GetLogLevelsResponse, error) {
+	out := new(GetLogLevelsResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetLogLevels_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetSLAReport(ctx context.Context, in *GetSLAReportRequest, opts ...grpc.CallOption) (*SLAReport, error) {
+	out := new(SLAReport)
+	err := c.cc.Invoke(ctx, AdminService_GetSLAReport_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DrainNamespace(ctx context.Context, in *DrainNamespaceRequest, opts ...grpc.CallOption) (AdminService_DrainNamespaceClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AdminService_ServiceDesc.Streams[0], AdminService_DrainNamespace_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &adminServiceDrainNamespaceClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AdminService_DrainNamespaceClient interface {
+	Recv() (*BulkProgress, error)
+	grpc.ClientStream
+}
+
+type adminServiceDrainNamespaceClient struct {
+	grpc.ClientStream
+}
+
+func (x *adminServiceDrainNamespaceClient) Recv() (*BulkProgress, error) {
+	m := new(BulkProgress)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *adminServiceClient) PurgeStaleRegistrations(ctx context.Context, in *PurgeStaleRegistrationsRequest, opts ...grpc.CallOption) (AdminService_PurgeStaleRegistrationsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AdminService_ServiceDesc.Streams[1], AdminService_PurgeStaleRegistrations_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &adminServicePurgeStaleRegistrationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AdminService_PurgeStaleRegistrationsClient interface {
+	Recv() (*BulkProgress, error)
+	grpc.ClientStream
+}
+
+type adminServicePurgeStaleRegistrationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *adminServicePurgeStaleRegistrationsClient) Recv() (*BulkProgress, error) {
+	m := new(BulkProgress)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *adminServiceClient) RetagServices(ctx context.Context, in *RetagServicesRequest, opts ...grpc.CallOption) (AdminService_RetagServicesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AdminService_ServiceDesc.Streams[2], AdminService_RetagServices_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &adminServiceRetagServicesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AdminService_RetagServicesClient interface {
+	Recv() (*BulkProgress, error)
+	grpc.ClientStream
+}
+
+type adminServiceRetagServicesClient struct {
+	grpc.ClientStream
+}
+
+func (x *adminServiceRetagServicesClient) Recv() (*BulkProgress, error) {
+	m := new(BulkProgress)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *adminServiceClient) GetStorageUsage(ctx context.Context, in *GetStorageUsageRequest, opts ...grpc.CallOption) (*GetStorageUsageResponse, error) {
+	out := new(GetStorageUsageResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetStorageUsage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetDeprecationReport(ctx context.Context, in *GetDeprecationReportRequest, opts ...grpc.CallOption) (*DeprecationReport, error) {
+	out := new(DeprecationReport)
+	err := c.cc.Invoke(ctx, AdminService_GetDeprecationReport_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetErrorReport(ctx context.Context, in *GetErrorReportRequest, opts ...grpc.CallOption) (*ErrorReport, error) {
+	out := new(ErrorReport)
+	err := c.cc.Invoke(ctx, AdminService_GetErrorReport_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) CreateBootstrapToken(ctx context.Context, in *CreateBootstrapTokenRequest, opts ...grpc.CallOption) (*CreateBootstrapTokenResponse, error) {
+	out := new(CreateBootstrapTokenResponse)
+	err := c.cc.Invoke(ctx, AdminService_CreateBootstrapToken_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RevokeDevice(ctx context.Context, in *RevokeDeviceRequest, opts ...grpc.CallOption) (*RevokeDeviceResponse, error) {
+	out := new(RevokeDeviceResponse)
+	err := c.cc.Invoke(ctx, AdminService_RevokeDevice_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility
+type AdminServiceServer interface {
+	// Reload configuration from disk, rejecting invalid files atomically
+	ReloadConfig(context.Context, *ReloadConfigRequest) (*ReloadConfigResponse, error)
+	// List past configuration reload attempts
+	ListConfigChanges(context.Context, *ListConfigChangesRequest) (*ListConfigChangesResponse, error)
+	// Show the redacted effective configuration and the source of each value
+	GetEffectiveConfig(context.Context, *GetEffectiveConfigRequest) (*GetEffectiveConfigResponse, error)
+	// Change the log level of one component ("*" for all) at runtime
+	SetLogLevel(context.Context, *SetLogLevelRequest) (*SetLogLevelResponse, error)
+	// Get the current log level of every component
+	GetLogLevels(context.Context, *GetLogLevelsRequest) (*GetLogLevelsResponse, error)
+	// Report availability and latency of providers against their declared QoS
+	GetSLAReport(context.Context, *GetSLAReportRequest) (*SLAReport, error)
+	// Drain every provider of a namespace, streaming the outcome per provider
+	DrainNamespace(*DrainNamespaceRequest, AdminService_DrainNamespaceServer) error
+	// Remove registrations whose providers stopped sending heartbeats
+	PurgeStaleRegistrations(*PurgeStaleRegistrationsRequest, AdminService_PurgeStaleRegistrationsServer) error
+	// Set or remove labels of every service matching a selector
+	RetagServices(*RetagServicesRequest, AdminService_RetagServicesServer) error
+	// Report the data held by each retained store, e.g. event logs, analytics
+	// and audit trails, and its retention policy
+	GetStorageUsage(context.Context, *GetStorageUsageRequest) (*GetStorageUsageResponse, error)
+	// Report who still calls deprecated actions, to tell when an alias can be
+	// removed
+	GetDeprecationReport(context.Context, *GetDeprecationReportRequest) (*DeprecationReport, error)
+	// Report the errors providers' handlers returned, as their runtimes
+	// summarized them, by service, action and status code
+	GetErrorReport(context.Context, *GetErrorReportRequest) (*ErrorReport, error)
+	// Create a one-time bootstrap token enrolling a device with the broker's
+	// built-in CA
+	CreateBootstrapToken(context.Context, *CreateBootstrapTokenRequest) (*CreateBootstrapTokenResponse, error)
+	// Stop renewing the certificates of a device enrolled with the built-in
+	// CA and refuse its calls
+	RevokeDevice(context.Context, *RevokeDeviceRequest) (*RevokeDeviceResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServiceServer struct {
+}
+
+func (UnimplementedAdminServiceServer) ReloadConfig(context.Context, *ReloadConfigRequest) (*ReloadConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadConfig not implemented")
+}
+func (UnimplementedAdminServiceServer) ListConfigChanges(context.Context, *ListConfigChangesRequest) (*ListConfigChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListConfigChanges not implemented")
+}
+func (UnimplementedAdminServiceServer) GetEffectiveConfig(context.Context, *GetEffectiveConfigRequest) (*GetEffectiveConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEffectiveConfig not implemented")
+}
+func (UnimplementedAdminServiceServer) SetLogLevel(context.Context, *SetLogLevelRequest) (*SetLogLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
+}
+func (UnimplementedAdminServiceServer) GetLogLevels(context.Context, *GetLogLevelsRequest) (*GetLogLevelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLogLevels not implemented")
+}
+func (UnimplementedAdminServiceServer) GetSLAReport(context.Context, *GetSLAReportRequest) (*SLAReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSLAReport not implemented")
+}
+func (UnimplementedAdminServiceServer) DrainNamespace(*DrainNamespaceRequest, AdminService_DrainNamespaceServer) error {
+	return status.Errorf(codes.Unimplemented, "method DrainNamespace not implemented")
+}
+func (UnimplementedAdminServiceServer) PurgeStaleRegistrations(*PurgeStaleRegistrationsRequest, AdminService_PurgeStaleRegistrationsServer) error {
+	return status.Errorf(codes.Unimplemented, "method PurgeStaleRegistrations not implemented")
+}
+func (UnimplementedAdminServiceServer) RetagServices(*RetagServicesRequest, AdminService_RetagServicesServer) error {
+	return status.Errorf(codes.Unimplemented, "method RetagServices not implemented")
+}
+func (UnimplementedAdminServiceServer) GetStorageUsage(context.Context, *GetStorageUsageRequest) (*GetStorageUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStorageUsage not implemented")
+}
+func (UnimplementedAdminServiceServer) GetDeprecationReport(context.Context, *GetDeprecationReportRequest) (*DeprecationReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeprecationReport not implemented")
+}
+func (UnimplementedAdminServiceServer) GetErrorReport(context.Context, *GetErrorReportRequest) (*ErrorReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetErrorReport not implemented")
+}
+func (UnimplementedAdminServiceServer) CreateBootstrapToken(context.Context, *CreateBootstrapTokenRequest) (*CreateBootstrapTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBootstrapToken not implemented")
+}
+func (UnimplementedAdminServiceServer) RevokeDevice(context.Context, *RevokeDeviceRequest) (*RevokeDeviceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeDevice not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ReloadConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ReloadConfig(ctx, req.(*ReloadConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListConfigChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConfigChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListConfigChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListConfigChanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListConfigChanges(ctx, req.(*ListConfigChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetEffectiveConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEffectiveConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetEffectiveConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetEffectiveConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetEffectiveConfig(ctx, req.(*GetEffectiveConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLogLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetLogLevel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetLogLevel(ctx, req.(*SetLogLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetLogLevels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLogLevelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetLogLevels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetLogLevels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetLogLevels(ctx, req.(*GetLogLevelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetSLAReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSLAReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetSLAReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetSLAReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetSLAReport(ctx, req.(*GetSLAReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DrainNamespace_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DrainNamespaceRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServiceServer).DrainNamespace(m, &adminServiceDrainNamespaceServer{stream})
+}
+
+type AdminService_DrainNamespaceServer interface {
+	Send(*BulkProgress) error
+	grpc.ServerStream
+}
+
+type adminServiceDrainNamespaceServer struct {
+	grpc.ServerStream
+}
+
+func (x *adminServiceDrainNamespaceServer) Send(m *BulkProgress) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AdminService_PurgeStaleRegistrations_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PurgeStaleRegistrationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServiceServer).PurgeStaleRegistrations(m, &adminServicePurgeStaleRegistrationsServer{stream})
+}
+
+type AdminService_PurgeStaleRegistrationsServer interface {
+	Send(*BulkProgress) error
+	grpc.ServerStream
+}
+
+type adminServicePurgeStaleRegistrationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *adminServicePurgeStaleRegistrationsServer) Send(m *BulkProgress) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AdminService_RetagServices_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RetagServicesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServiceServer).RetagServices(m, &adminServiceRetagServicesServer{stream})
+}
+
+type AdminService_RetagServicesServer interface {
+	Send(*BulkProgress) error
+	grpc.ServerStream
+}
+
+type adminServiceRetagServicesServer struct {
+	grpc.ServerStream
+}
+
+func (x *adminServiceRetagServicesServer) Send(m *BulkProgress) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AdminService_GetStorageUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStorageUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetStorageUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetStorageUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetStorageUsage(ctx, req.(*GetStorageUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetDeprecationReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeprecationReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetDeprecationReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetDeprecationReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetDeprecationReport(ctx, req.(*GetDeprecationReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetErrorReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetErrorReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetErrorReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetErrorReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetErrorReport(ctx, req.(*GetErrorReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_CreateBootstrapToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBootstrapTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).CreateBootstrapToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_CreateBootstrapToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).CreateBootstrapToken(ctx, req.(*CreateBootstrapTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RevokeDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RevokeDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RevokeDevice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RevokeDevice(ctx, req.(*RevokeDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "nfa.admin.v1alpha.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ReloadConfig",
+			Handler:    _AdminService_ReloadConfig_Handler,
+		},
+		{
+			MethodName: "ListConfigChanges",
+			Handler:    _AdminService_ListConfigChanges_Handler,
+		},
+		{
+			MethodName: "GetEffectiveConfig",
+			Handler:    _AdminService_GetEffectiveConfig_Handler,
+		},
+		{
+			MethodName: "SetLogLevel",
+			Handler:    _AdminService_SetLogLevel_Handler,
+		},
+		{
+			MethodName: "GetLogLevels",
+			Handler:    _AdminService_GetLogLevels_Handler,
+		},
+		{
+			MethodName: "GetSLAReport",
+			Handler:    _AdminService_GetSLAReport_Handler,
+		},
+		{
+			MethodName: "GetStorageUsage",
+			Handler:    _AdminService_GetStorageUsage_Handler,
+		},
+		{
+			MethodName: "GetDeprecationReport",
+			Handler:    _AdminService_GetDeprecationReport_Handler,
+		},
+		{
+			MethodName: "GetErrorReport",
+			Handler:    _AdminService_GetErrorReport_Handler,
+		},
+		{
+			MethodName: "CreateBootstrapToken",
+			Handler:    _AdminService_CreateBootstrapToken_Handler,
+		},
+		{
+			MethodName: "RevokeDevice",
+			Handler:    _AdminService_RevokeDevice_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "DrainNamespace",
+			Handler:       _AdminService_DrainNamespace_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "PurgeStaleRegistrations",
+			Handler:       _AdminService_PurgeStaleRegistrations_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "RetagServices",
+			Handler:       _AdminService_RetagServices_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "admin/v1alpha/admin.proto",
+}
@@ -0,0 +1,327 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: blob/v1alpha/blob.proto
+
+package blob
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	BlobService_Upload_FullMethodName          = "/nfa.blob.v1alpha.BlobService/Upload"
+	BlobService_Download_FullMethodName        = "/nfa.blob.v1alpha.BlobService/Download"
+	BlobService_Stat_FullMethodName            = "/nfa.blob.v1alpha.BlobService/Stat"
+	BlobService_Delete_FullMethodName          = "/nfa.blob.v1alpha.BlobService/Delete"
+	BlobService_CreateSignedURL_FullMethodName = "/nfa.blob.v1alpha.BlobService/CreateSignedURL"
+)
+
+// BlobServiceClient is the client API for BlobService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BlobServiceClient interface {
+	// Upload a blob. The first message carries the header, the following
+	// messages carry the data in order.
+	Upload(ctx context.Context, opts ...grpc.CallOption) (BlobService_UploadClient, error)
+	// Download a blob in chunks, starting at offset to resume an interrupted download
+	Download(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (BlobService_DownloadClient, error)
+	Stat(ctx context.Context, in *StatBlobRequest, opts ...grpc.CallOption) (*Blob, error)
+	Delete(ctx context.Context, in *DeleteBlobRequest, opts ...grpc.CallOption) (*DeleteBlobResponse, error)
+	// Create a time-limited HTTP URL to upload (PUT) or download (GET) a blob
+	CreateSignedURL(ctx context.Context, in *CreateSignedURLRequest, opts ...grpc.CallOption) (*SignedURL, error)
+}
+
+type blobServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBlobServiceClient(cc grpc.ClientConnInterface) BlobServiceClient {
+	return &blobServiceClient{cc}
+}
+
+func (c *blobServiceClient) Upload(ctx context.Context, opts ...grpc.CallOption) (BlobService_UploadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BlobService_ServiceDesc.Streams[0], BlobService_Upload_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blobServiceUploadClient{stream}
+	return x, nil
+}
+
+type BlobService_UploadClient interface {
+	Send(*UploadRequest) error
+	CloseAndRecv() (*Blob, error)
+	grpc.ClientStream
+}
+
+type blobServiceUploadClient struct {
+	grpc.ClientStream
+}
+
+func (x *blobServiceUploadClient) Send(m *UploadRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *blobServiceUploadClient) CloseAndRecv() (*Blob, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(Blob)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *blobServiceClient) Download(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (BlobService_DownloadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BlobService_ServiceDesc.Streams[1], BlobService_Download_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blobServiceDownloadClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BlobService_DownloadClient interface {
+	Recv() (*BlobChunk, error)
+	grpc.ClientStream
+}
+
+type blobServiceDownloadClient struct {
+	grpc.ClientStream
+}
+
+func (x *blobServiceDownloadClient) Recv() (*BlobChunk, error) {
+	m := new(BlobChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *blobServiceClient) Stat(ctx context.Context, in *StatBlobRequest, opts ...grpc.CallOption) (*Blob, error) {
+	out := new(Blob)
+	err := c.cc.Invoke(ctx, BlobService_Stat_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobServiceClient) Delete(ctx context.Context, in *DeleteBlobRequest, opts ...grpc.CallOption) (*DeleteBlobResponse, error) {
+	out := new(DeleteBlobResponse)
+	err := c.cc.Invoke(ctx, BlobService_Delete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobServiceClient) CreateSignedURL(ctx context.Context, in *CreateSignedURLRequest, opts ...grpc.CallOption) (*SignedURL, error) {
+	out := new(SignedURL)
+	err := c.cc.Invoke(ctx, BlobService_CreateSignedURL_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BlobServiceServer is the server API for BlobService service.
+// All implementations must embed UnimplementedBlobServiceServer
+// for forward compatibility
+type BlobServiceServer interface {
+	// Upload a blob. The first message carries the header, the following
+	// messages carry the data in order.
+	Upload(BlobService_UploadServer) error
+	// Download a blob in chunks, starting at offset to resume an interrupted download
+	Download(*DownloadRequest, BlobService_DownloadServer) error
+	Stat(context.Context, *StatBlobRequest) (*Blob, error)
+	Delete(context.Context, *DeleteBlobRequest) (*DeleteBlobResponse, error)
+	// Create a time-limited HTTP URL to upload (PUT) or download (GET) a blob
+	CreateSignedURL(context.Context, *CreateSignedURLRequest) (*SignedURL, error)
+	mustEmbedUnimplementedBlobServiceServer()
+}
+
+// UnimplementedBlobServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedBlobServiceServer struct {
+}
+
+func (UnimplementedBlobServiceServer) Upload(BlobService_UploadServer) error {
+	return status.Errorf(codes.Unimplemented, "method Upload not implemented")
+}
+func (UnimplementedBlobServiceServer) Download(*DownloadRequest, BlobService_DownloadServer) error {
+	return status.Errorf(codes.Unimplemented, "method Download not implemented")
+}
+func (UnimplementedBlobServiceServer) Stat(context.Context, *StatBlobRequest) (*Blob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stat not implemented")
+}
+func (UnimplementedBlobServiceServer) Delete(context.Context, *DeleteBlobRequest) (*DeleteBlobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedBlobServiceServer) CreateSignedURL(context.Context, *CreateSignedURLRequest) (*SignedURL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSignedURL not implemented")
+}
+func (UnimplementedBlobServiceServer) mustEmbedUnimplementedBlobServiceServer() {}
+
+// UnsafeBlobServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BlobServiceServer will
+// result in compilation errors.
+type UnsafeBlobServiceServer interface {
+	mustEmbedUnimplementedBlobServiceServer()
+}
+
+func RegisterBlobServiceServer(s grpc.ServiceRegistrar, srv BlobServiceServer) {
+	s.RegisterService(&BlobService_ServiceDesc, srv)
+}
+
+func _BlobService_Upload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BlobServiceServer).Upload(&blobServiceUploadServer{stream})
+}
+
+type BlobService_UploadServer interface {
+	SendAndClose(*Blob) error
+	Recv() (*UploadRequest, error)
+	grpc.ServerStream
+}
+
+type blobServiceUploadServer struct {
+	grpc.ServerStream
+}
+
+func (x *blobServiceUploadServer) SendAndClose(m *Blob) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *blobServiceUploadServer) Recv() (*UploadRequest, error) {
+	m := new(UploadRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _BlobService_Download_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlobServiceServer).Download(m, &blobServiceDownloadServer{stream})
+}
+
+type BlobService_DownloadServer interface {
+	Send(*BlobChunk) error
+	grpc.ServerStream
+}
+
+type blobServiceDownloadServer struct {
+	grpc.ServerStream
+}
+
+func (x *blobServiceDownloadServer) Send(m *BlobChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BlobService_Stat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatBlobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobServiceServer).Stat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlobService_Stat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobServiceServer).Stat(ctx, req.(*StatBlobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteBlobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlobService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobServiceServer).Delete(ctx, req.(*DeleteBlobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobService_CreateSignedURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSignedURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobServiceServer).CreateSignedURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlobService_CreateSignedURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobServiceServer).CreateSignedURL(ctx, req.(*CreateSignedURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BlobService_ServiceDesc is the grpc.ServiceDesc for BlobService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BlobService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "nfa.blob.v1alpha.BlobService",
+	HandlerType: (*BlobServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Stat",
+			Handler:    _BlobService_Stat_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _BlobService_Delete_Handler,
+		},
+		{
+			MethodName: "CreateSignedURL",
+			Handler:    _BlobService_CreateSignedURL_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Upload",
+			Handler:       _BlobService_Upload_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Download",
+			Handler:       _BlobService_Download_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "blob/v1alpha/blob.proto",
+}
@@ -0,0 +1,310 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: broker/v1/broker.proto
+
+package broker
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	IntentBroker_RegisterIntent_FullMethodName   = "/nfa.broker.v1.IntentBroker/RegisterIntent"
+	IntentBroker_MatchIntent_FullMethodName      = "/nfa.broker.v1.IntentBroker/MatchIntent"
+	IntentBroker_Heartbeat_FullMethodName        = "/nfa.broker.v1.IntentBroker/Heartbeat"
+	IntentBroker_UnregisterIntent_FullMethodName = "/nfa.broker.v1.IntentBroker/UnregisterIntent"
+	IntentBroker_ReportOutcome_FullMethodName    = "/nfa.broker.v1.IntentBroker/ReportOutcome"
+	IntentBroker_ReportErrors_FullMethodName     = "/nfa.broker.v1.IntentBroker/ReportErrors"
+)
+
+// IntentBrokerClient is the client API for IntentBroker service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type IntentBrokerClient interface {
+	// Register a new intent service
+	RegisterIntent(ctx context.Context, in *RegisterIntentRequest, opts ...grpc.CallOption) (*RegisterIntentResponse, error)
+	// Match an intent to available services
+	MatchIntent(ctx context.Context, in *IntentMatchRequest, opts ...grpc.CallOption) (*IntentMatchResponse, error)
+	// Heartbeat from services
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
+	// Unregister a service
+	UnregisterIntent(ctx context.Context, in *UnregisterIntentRequest, opts ...grpc.CallOption) (*UnregisterIntentResponse, error)
+	// Report the outcome of a call a consumer made to a matched service, so
+	// the broker ranks chronically failing services last
+	ReportOutcome(ctx context.Context, in *ReportOutcomeRequest, opts ...grpc.CallOption) (*ReportOutcomeResponse, error)
+	// Report summaries of the errors a provider's handlers returned since
+	// its last report, for fleet-wide error visibility
+	ReportErrors(ctx context.Context, in *ReportErrorsRequest, opts ...grpc.CallOption) (*ReportErrorsResponse, error)
+}
+
+type intentBrokerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIntentBrokerClient(cc grpc.ClientConnInterface) IntentBrokerClient {
+	return &intentBrokerClient{cc}
+}
+
+func (c *intentBrokerClient) RegisterIntent(ctx context.Context, in *RegisterIntentRequest, opts ...grpc.CallOption) (*RegisterIntentResponse, error) {
+	out := new(RegisterIntentResponse)
+	err := c.cc.Invoke(ctx, IntentBroker_RegisterIntent_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *intentBrokerClient) MatchIntent(ctx context.Context, in *IntentMatchRequest, opts ...grpc.CallOption) (*IntentMatchResponse, error) {
+	out := new(IntentMatchResponse)
+	err := c.cc.Invoke(ctx, IntentBroker_MatchIntent_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *intentBrokerClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	out := new(HeartbeatResponse)
+	err := c.cc.Invoke(ctx, IntentBroker_Heartbeat_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *intentBrokerClient) UnregisterIntent(ctx context.Context, in *UnregisterIntentRequest, opts ...grpc.CallOption) (*UnregisterIntentResponse, error) {
+	out := new(UnregisterIntentResponse)
+	err := c.cc.Invoke(ctx, IntentBroker_UnregisterIntent_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *intentBrokerClient) ReportOutcome(ctx context.Context, in *ReportOutcomeRequest, opts ...grpc.CallOption) (*ReportOutcomeResponse, error) {
+	out := new(ReportOutcomeResponse)
+	err := c.cc.Invoke(ctx, IntentBroker_ReportOutcome_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *intentBrokerClient) ReportErrors(ctx context.Context, in *ReportErrorsRequest, opts ...grpc.CallOption) (*ReportErrorsResponse, error) {
+	out := new(ReportErrorsResponse)
+	err := c.cc.Invoke(ctx, IntentBroker_ReportErrors_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IntentBrokerServer is the server API for IntentBroker service.
+// All implementations must embed UnimplementedIntentBrokerServer
+// for forward compatibility
+type IntentBrokerServer interface {
+	// Register a new intent service
+	RegisterIntent(context.Context, *RegisterIntentRequest) (*RegisterIntentResponse, error)
+	// Match an intent to available services
+	MatchIntent(context.Context, *IntentMatchRequest) (*IntentMatchResponse, error)
+	// Heartbeat from services
+	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
+	// Unregister a service
+	UnregisterIntent(context.Context, *UnregisterIntentRequest) (*UnregisterIntentResponse, error)
+	// Report the outcome of a call a consumer made to a matched service, so
+	// the broker ranks chronically failing services last
+	ReportOutcome(context.Context, *ReportOutcomeRequest) (*ReportOutcomeResponse, error)
+	// Report summaries of the errors a provider's handlers returned since
+	// its last report, for fleet-wide error visibility
+	ReportErrors(context.Context, *ReportErrorsRequest) (*ReportErrorsResponse, error)
+	mustEmbedUnimplementedIntentBrokerServer()
+}
+
+// UnimplementedIntentBrokerServer must be embedded to have forward compatible implementations.
+type UnimplementedIntentBrokerServer struct {
+}
+
+func (UnimplementedIntentBrokerServer) RegisterIntent(context.Context, *RegisterIntentRequest) (*RegisterIntentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterIntent not implemented")
+}
+func (UnimplementedIntentBrokerServer) MatchIntent(context.Context, *IntentMatchRequest) (*IntentMatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MatchIntent not implemented")
+}
+func (UnimplementedIntentBrokerServer) Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (UnimplementedIntentBrokerServer) UnregisterIntent(context.Context, *UnregisterIntentRequest) (*UnregisterIntentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnregisterIntent not implemented")
+}
+func (UnimplementedIntentBrokerServer) ReportOutcome(context.Context, *ReportOutcomeRequest) (*ReportOutcomeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportOutcome not implemented")
+}
+func (UnimplementedIntentBrokerServer) ReportErrors(context.Context, *ReportErrorsRequest) (*ReportErrorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportErrors not implemented")
+}
+func (UnimplementedIntentBrokerServer) mustEmbedUnimplementedIntentBrokerServer() {}
+
+// UnsafeIntentBrokerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IntentBrokerServer will
+// result in compilation errors.
+type UnsafeIntentBrokerServer interface {
+	mustEmbedUnimplementedIntentBrokerServer()
+}
+
+func RegisterIntentBrokerServer(s grpc.ServiceRegistrar, srv IntentBrokerServer) {
+	s.RegisterService(&IntentBroker_ServiceDesc, srv)
+}
+
+func _IntentBroker_RegisterIntent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterIntentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IntentBrokerServer).RegisterIntent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IntentBroker_RegisterIntent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IntentBrokerServer).RegisterIntent(ctx, req.(*RegisterIntentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IntentBroker_MatchIntent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IntentMatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IntentBrokerServer).MatchIntent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IntentBroker_MatchIntent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IntentBrokerServer).MatchIntent(ctx, req.(*IntentMatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IntentBroker_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IntentBrokerServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IntentBroker_Heartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IntentBrokerServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IntentBroker_UnregisterIntent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnregisterIntentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IntentBrokerServer).UnregisterIntent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IntentBroker_UnregisterIntent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IntentBrokerServer).UnregisterIntent(ctx, req.(*UnregisterIntentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IntentBroker_ReportOutcome_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportOutcomeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IntentBrokerServer).ReportOutcome(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IntentBroker_ReportOutcome_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IntentBrokerServer).ReportOutcome(ctx, req.(*ReportOutcomeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IntentBroker_ReportErrors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportErrorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IntentBrokerServer).ReportErrors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IntentBroker_ReportErrors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IntentBrokerServer).ReportErrors(ctx, req.(*ReportErrorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// IntentBroker_ServiceDesc is the grpc.ServiceDesc for IntentBroker service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var IntentBroker_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "nfa.broker.v1.IntentBroker",
+	HandlerType: (*IntentBrokerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterIntent",
+			Handler:    _IntentBroker_RegisterIntent_Handler,
+		},
+		{
+			MethodName: "MatchIntent",
+			Handler:    _IntentBroker_MatchIntent_Handler,
+		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _IntentBroker_Heartbeat_Handler,
+		},
+		{
+			MethodName: "UnregisterIntent",
+			Handler:    _IntentBroker_UnregisterIntent_Handler,
+		},
+		{
+			MethodName: "ReportOutcome",
+			Handler:    _IntentBroker_ReportOutcome_Handler,
+		},
+		{
+			MethodName: "ReportErrors",
+			Handler:    _IntentBroker_ReportErrors_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "broker/v1/broker.proto",
+}
@@ -0,0 +1,111 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: ca/v1alpha/ca.proto
+
+package ca
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	CertificateAuthority_IssueCertificate_FullMethodName = "/nfa.ca.v1alpha.CertificateAuthority/IssueCertificate"
+)
+
+// CertificateAuthorityClient is the client API for CertificateAuthority service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CertificateAuthorityClient interface {
+	// Issues a certificate for the key that signed the request
+	IssueCertificate(ctx context.Context, in *IssueCertificateRequest, opts ...grpc.CallOption) (*IssueCertificateResponse, error)
+}
+
+type certificateAuthorityClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCertificateAuthorityClient(cc grpc.ClientConnInterface) CertificateAuthorityClient {
+	return &certificateAuthorityClient{cc}
+}
+
+func (c *certificateAuthorityClient) IssueCertificate(ctx context.Context, in *IssueCertificateRequest, opts ...grpc.CallOption) (*IssueCertificateResponse, error) {
+	out := new(IssueCertificateResponse)
+	err := c.cc.Invoke(ctx, CertificateAuthority_IssueCertificate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CertificateAuthorityServer is the server API for CertificateAuthority service.
+// All implementations must embed UnimplementedCertificateAuthorityServer
+// for forward compatibility
+type CertificateAuthorityServer interface {
+	// Issues a certificate for the key that signed the request
+	IssueCertificate(context.Context, *IssueCertificateRequest) (*IssueCertificateResponse, error)
+	mustEmbedUnimplementedCertificateAuthorityServer()
+}
+
+// UnimplementedCertificateAuthorityServer must be embedded to have forward compatible implementations.
+type UnimplementedCertificateAuthorityServer struct {
+}
+
+func (UnimplementedCertificateAuthorityServer) IssueCertificate(context.Context, *IssueCertificateRequest) (*IssueCertificateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IssueCertificate not implemented")
+}
+func (UnimplementedCertificateAuthorityServer) mustEmbedUnimplementedCertificateAuthorityServer() {}
+
+// UnsafeCertificateAuthorityServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CertificateAuthorityServer will
+// result in compilation errors.
+type UnsafeCertificateAuthorityServer interface {
+	mustEmbedUnimplementedCertificateAuthorityServer()
+}
+
+func RegisterCertificateAuthorityServer(s grpc.ServiceRegistrar, srv CertificateAuthorityServer) {
+	s.RegisterService(&CertificateAuthority_ServiceDesc, srv)
+}
+
+func _CertificateAuthority_IssueCertificate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueCertificateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CertificateAuthorityServer).IssueCertificate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CertificateAuthority_IssueCertificate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CertificateAuthorityServer).IssueCertificate(ctx, req.(*IssueCertificateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CertificateAuthority_ServiceDesc is the grpc.ServiceDesc for CertificateAuthority service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CertificateAuthority_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "nfa.ca.v1alpha.CertificateAuthority",
+	HandlerType: (*CertificateAuthorityServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "IssueCertificate",
+			Handler:    _CertificateAuthority_IssueCertificate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ca/v1alpha/ca.proto",
+}
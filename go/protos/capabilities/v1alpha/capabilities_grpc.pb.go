@@ -0,0 +1,109 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: capabilities/v1alpha/capabilities.proto
+
+package capabilities
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Capabilities_GetCapabilities_FullMethodName = "/nfa.capabilities.v1alpha.Capabilities/GetCapabilities"
+)
+
+// CapabilitiesClient is the client API for Capabilities service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CapabilitiesClient interface {
+	GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*CapabilitiesResponse, error)
+}
+
+type capabilitiesClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCapabilitiesClient(cc grpc.ClientConnInterface) CapabilitiesClient {
+	return &capabilitiesClient{cc}
+}
+
+func (c *capabilitiesClient) GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*CapabilitiesResponse, error) {
+	out := new(CapabilitiesResponse)
+	err := c.cc.Invoke(ctx, Capabilities_GetCapabilities_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CapabilitiesServer is the server API for Capabilities service.
+// All implementations must embed UnimplementedCapabilitiesServer
+// for forward compatibility
+type CapabilitiesServer interface {
+	GetCapabilities(context.Context, *GetCapabilitiesRequest) (*CapabilitiesResponse, error)
+	mustEmbedUnimplementedCapabilitiesServer()
+}
+
+// UnimplementedCapabilitiesServer must be embedded to have forward compatible implementations.
+type UnimplementedCapabilitiesServer struct {
+}
+
+func (UnimplementedCapabilitiesServer) GetCapabilities(context.Context, *GetCapabilitiesRequest) (*CapabilitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCapabilities not implemented")
+}
+func (UnimplementedCapabilitiesServer) mustEmbedUnimplementedCapabilitiesServer() {}
+
+// UnsafeCapabilitiesServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CapabilitiesServer will
+// result in compilation errors.
+type UnsafeCapabilitiesServer interface {
+	mustEmbedUnimplementedCapabilitiesServer()
+}
+
+func RegisterCapabilitiesServer(s grpc.ServiceRegistrar, srv CapabilitiesServer) {
+	s.RegisterService(&Capabilities_ServiceDesc, srv)
+}
+
+func _Capabilities_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CapabilitiesServer).GetCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Capabilities_GetCapabilities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CapabilitiesServer).GetCapabilities(ctx, req.(*GetCapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Capabilities_ServiceDesc is the grpc.ServiceDesc for Capabilities service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Capabilities_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "nfa.capabilities.v1alpha.Capabilities",
+	HandlerType: (*CapabilitiesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _Capabilities_GetCapabilities_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "capabilities/v1alpha/capabilities.proto",
+}
@@ -0,0 +1,276 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: control/v1alpha/control.proto
+
+package control
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ControlService_Connect_FullMethodName = "/nfa.control.v1alpha.ControlService/Connect"
+)
+
+// ControlServiceClient is the client API for ControlService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ControlServiceClient interface {
+	// Open a long-lived control stream. The runtime must send Hello first.
+	Connect(ctx context.Context, opts ...grpc.CallOption) (ControlService_ConnectClient, error)
+}
+
+type controlServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewControlServiceClient(cc grpc.ClientConnInterface) ControlServiceClient {
+	return &controlServiceClient{cc}
+}
+
+func (c *controlServiceClient) Connect(ctx context.Context, opts ...grpc.CallOption) (ControlService_ConnectClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ControlService_ServiceDesc.Streams[0], ControlService_Connect_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlServiceConnectClient{stream}
+	return x, nil
+}
+
+type ControlService_ConnectClient interface {
+	Send(*RuntimeMessage) error
+	Recv() (*BrokerMessage, error)
+	grpc.ClientStream
+}
+
+type controlServiceConnectClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlServiceConnectClient) Send(m *RuntimeMessage) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *controlServiceConnectClient) Recv() (*BrokerMessage, error) {
+	m := new(BrokerMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ControlServiceServer is the server API for ControlService service.
+// All implementations must embed UnimplementedControlServiceServer
+// for forward compatibility
+type ControlServiceServer interface {
+	// Open a long-lived control stream. The runtime must send Hello first.
+	Connect(ControlService_ConnectServer) error
+	mustEmbedUnimplementedControlServiceServer()
+}
+
+// UnimplementedControlServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedControlServiceServer struct {
+}
+
+func (UnimplementedControlServiceServer) Connect(ControlService_ConnectServer) error {
+	return status.Errorf(codes.Unimplemented, "method Connect not implemented")
+}
+func (UnimplementedControlServiceServer) mustEmbedUnimplementedControlServiceServer() {}
+
+// UnsafeControlServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ControlServiceServer will
+// result in compilation errors.
+type UnsafeControlServiceServer interface {
+	mustEmbedUnimplementedControlServiceServer()
+}
+
+func RegisterControlServiceServer(s grpc.ServiceRegistrar, srv ControlServiceServer) {
+	s.RegisterService(&ControlService_ServiceDesc, srv)
+}
+
+func _ControlService_Connect_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ControlServiceServer).Connect(&controlServiceConnectServer{stream})
+}
+
+type ControlService_ConnectServer interface {
+	Send(*BrokerMessage) error
+	Recv() (*RuntimeMessage, error)
+	grpc.ServerStream
+}
+
+type controlServiceConnectServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlServiceConnectServer) Send(m *BrokerMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *controlServiceConnectServer) Recv() (*RuntimeMessage, error) {
+	m := new(RuntimeMessage)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ControlService_ServiceDesc is the grpc.ServiceDesc for ControlService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ControlService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "nfa.control.v1alpha.ControlService",
+	HandlerType: (*ControlServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Connect",
+			Handler:       _ControlService_Connect_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "control/v1alpha/control.proto",
+}
+
+const (
+	BroadcastService_Broadcast_FullMethodName      = "/nfa.control.v1alpha.BroadcastService/Broadcast"
+	BroadcastService_BroadcastBatch_FullMethodName = "/nfa.control.v1alpha.BroadcastService/BroadcastBatch"
+)
+
+// BroadcastServiceClient is the client API for BroadcastService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BroadcastServiceClient interface {
+	// Deliver the intent and wait for each target's result until the timeout
+	Broadcast(ctx context.Context, in *BroadcastRequest, opts ...grpc.CallOption) (*BroadcastResponse, error)
+	// Broadcast several independent intents concurrently in one call, e.g. to
+	// refresh many widgets at once
+	BroadcastBatch(ctx context.Context, in *BroadcastBatchRequest, opts ...grpc.CallOption) (*BroadcastBatchResponse, error)
+}
+
+type broadcastServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBroadcastServiceClient(cc grpc.ClientConnInterface) BroadcastServiceClient {
+	return &broadcastServiceClient{cc}
+}
+
+func (c *broadcastServiceClient) Broadcast(ctx context.Context, in *BroadcastRequest, opts ...grpc.CallOption) (*BroadcastResponse, error) {
+	out := new(BroadcastResponse)
+	err := c.cc.Invoke(ctx, BroadcastService_Broadcast_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *broadcastServiceClient) BroadcastBatch(ctx context.Context, in *BroadcastBatchRequest, opts ...grpc.CallOption) (*BroadcastBatchResponse, error) {
+	out := new(BroadcastBatchResponse)
+	err := c.cc.Invoke(ctx, BroadcastService_BroadcastBatch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BroadcastServiceServer is the server API for BroadcastService service.
+// All implementations must embed UnimplementedBroadcastServiceServer
+// for forward compatibility
+type BroadcastServiceServer interface {
+	// Deliver the intent and wait for each target's result until the timeout
+	Broadcast(context.Context, *BroadcastRequest) (*BroadcastResponse, error)
+	// Broadcast several independent intents concurrently in one call, e.g. to
+	// refresh many widgets at once
+	BroadcastBatch(context.Context, *BroadcastBatchRequest) (*BroadcastBatchResponse, error)
+	mustEmbedUnimplementedBroadcastServiceServer()
+}
+
+// UnimplementedBroadcastServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedBroadcastServiceServer struct {
+}
+
+func (UnimplementedBroadcastServiceServer) Broadcast(context.Context, *BroadcastRequest) (*BroadcastResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Broadcast not implemented")
+}
+func (UnimplementedBroadcastServiceServer) BroadcastBatch(context.Context, *BroadcastBatchRequest) (*BroadcastBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BroadcastBatch not implemented")
+}
+func (UnimplementedBroadcastServiceServer) mustEmbedUnimplementedBroadcastServiceServer() {}
+
+// UnsafeBroadcastServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BroadcastServiceServer will
+// result in compilation errors.
+type UnsafeBroadcastServiceServer interface {
+	mustEmbedUnimplementedBroadcastServiceServer()
+}
+
+func RegisterBroadcastServiceServer(s grpc.ServiceRegistrar, srv BroadcastServiceServer) {
+	s.RegisterService(&BroadcastService_ServiceDesc, srv)
+}
+
+func _BroadcastService_Broadcast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadcastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BroadcastServiceServer).Broadcast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BroadcastService_Broadcast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BroadcastServiceServer).Broadcast(ctx, req.(*BroadcastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BroadcastService_BroadcastBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadcastBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BroadcastServiceServer).BroadcastBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BroadcastService_BroadcastBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BroadcastServiceServer).BroadcastBatch(ctx, req.(*BroadcastBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BroadcastService_ServiceDesc is the grpc.ServiceDesc for BroadcastService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BroadcastService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "nfa.control.v1alpha.BroadcastService",
+	HandlerType: (*BroadcastServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Broadcast",
+			Handler:    _BroadcastService_Broadcast_Handler,
+		},
+		{
+			MethodName: "BroadcastBatch",
+			Handler:    _BroadcastService_BroadcastBatch_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "control/v1alpha/control.proto",
+}
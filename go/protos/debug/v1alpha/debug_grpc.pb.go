@@ -0,0 +1,140 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: debug/v1alpha/debug.proto
+
+package debug
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Debug_StreamLogs_FullMethodName = "/nfa.debug.v1alpha.Debug/StreamLogs"
+)
+
+// DebugClient is the client API for Debug service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DebugClient interface {
+	// Streams the provider's recent and new log records, and its runtime
+	// stats at an interval, until the client cancels
+	StreamLogs(ctx context.Context, in *StreamLogsRequest, opts ...grpc.CallOption) (Debug_StreamLogsClient, error)
+}
+
+type debugClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDebugClient(cc grpc.ClientConnInterface) DebugClient {
+	return &debugClient{cc}
+}
+
+func (c *debugClient) StreamLogs(ctx context.Context, in *StreamLogsRequest, opts ...grpc.CallOption) (Debug_StreamLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Debug_ServiceDesc.Streams[0], Debug_StreamLogs_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &debugStreamLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Debug_StreamLogsClient interface {
+	Recv() (*DebugEvent, error)
+	grpc.ClientStream
+}
+
+type debugStreamLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *debugStreamLogsClient) Recv() (*DebugEvent, error) {
+	m := new(DebugEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DebugServer is the server API for Debug service.
+// All implementations must embed UnimplementedDebugServer
+// for forward compatibility
+type DebugServer interface {
+	// Streams the provider's recent and new log records, and its runtime
+	// stats at an interval, until the client cancels
+	StreamLogs(*StreamLogsRequest, Debug_StreamLogsServer) error
+	mustEmbedUnimplementedDebugServer()
+}
+
+// UnimplementedDebugServer must be embedded to have forward compatible implementations.
+type UnimplementedDebugServer struct {
+}
+
+func (UnimplementedDebugServer) StreamLogs(*StreamLogsRequest, Debug_StreamLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamLogs not implemented")
+}
+func (UnimplementedDebugServer) mustEmbedUnimplementedDebugServer() {}
+
+// UnsafeDebugServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DebugServer will
+// result in compilation errors.
+type UnsafeDebugServer interface {
+	mustEmbedUnimplementedDebugServer()
+}
+
+func RegisterDebugServer(s grpc.ServiceRegistrar, srv DebugServer) {
+	s.RegisterService(&Debug_ServiceDesc, srv)
+}
+
+func _Debug_StreamLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DebugServer).StreamLogs(m, &debugStreamLogsServer{stream})
+}
+
+type Debug_StreamLogsServer interface {
+	Send(*DebugEvent) error
+	grpc.ServerStream
+}
+
+type debugStreamLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *debugStreamLogsServer) Send(m *DebugEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Debug_ServiceDesc is the grpc.ServiceDesc for Debug service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Debug_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "nfa.debug.v1alpha.Debug",
+	HandlerType: (*DebugServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamLogs",
+			Handler:       _Debug_StreamLogs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "debug/v1alpha/debug.proto",
+}
@@ -0,0 +1,3312 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v3.21.12
+// source: intent/v1/intent.proto
+
+package intent
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Residency int32
+
+const (
+	Residency_RESIDENCY_UNRESTRICTED Residency = 0
+	// 只能由与消费者位于同一设备（nfa.device标签）的提供者处理
+	Residency_RESIDENCY_ON_DEVICE Residency = 1
+	// 只能由位于regions中的提供者处理
+	Residency_RESIDENCY_REGION Residency = 2
+)
+
+// Enum value maps for Residency.
+var (
+	Residency_name = map[int32]string{
+		0: "RESIDENCY_UNRESTRICTED",
+		1: "RESIDENCY_ON_DEVICE",
+		2: "RESIDENCY_REGION",
+	}
+	Residency_value = map[string]int32{
+		"RESIDENCY_UNRESTRICTED": 0,
+		"RESIDENCY_ON_DEVICE":    1,
+		"RESIDENCY_REGION":       2,
+	}
+)
+
+func (x Residency) Enum() *Residency {
+	p := new(Residency)
+	*p = x
+	return p
+}
+
+func (x Residency) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Residency) Descriptor() protoreflect.EnumDescriptor {
+	return file_intent_v1_intent_proto_enumTypes[0].Descriptor()
+}
+
+func (Residency) Type() protoreflect.EnumType {
+	return &file_intent_v1_intent_proto_enumTypes[0]
+}
+
+func (x Residency) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Residency.Descriptor instead.
+func (Residency) EnumDescriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{0}
+}
+
+// 意图的调用方式，默认为一元调用
+type StreamingMode int32
+
+const (
+	StreamingMode_STREAMING_MODE_UNARY         StreamingMode = 0
+	StreamingMode_STREAMING_MODE_SERVER_STREAM StreamingMode = 1
+	StreamingMode_STREAMING_MODE_CLIENT_STREAM StreamingMode = 2
+	StreamingMode_STREAMING_MODE_BIDI          StreamingMode = 3
+)
+
+// Enum value maps for StreamingMode.
+var (
+	StreamingMode_name = map[int32]string{
+		0: "STREAMING_MODE_UNARY",
+		1: "STREAMING_MODE_SERVER_STREAM",
+		2: "STREAMING_MODE_CLIENT_STREAM",
+		3: "STREAMING_MODE_BIDI",
+	}
+	StreamingMode_value = map[string]int32{
+		"STREAMING_MODE_UNARY":         0,
+		"STREAMING_MODE_SERVER_STREAM": 1,
+		"STREAMING_MODE_CLIENT_STREAM": 2,
+		"STREAMING_MODE_BIDI":          3,
+	}
+)
+
+func (x StreamingMode) Enum() *StreamingMode {
+	p := new(StreamingMode)
+	*p = x
+	return p
+}
+
+func (x StreamingMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StreamingMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_intent_v1_intent_proto_enumTypes[1].Descriptor()
+}
+
+func (StreamingMode) Type() protoreflect.EnumType {
+	return &file_intent_v1_intent_proto_enumTypes[1]
+}
+
+func (x StreamingMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StreamingMode.Descriptor instead.
+func (StreamingMode) EnumDescriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{1}
+}
+
+// 参数的敏感级别，非UNSPECIFIED的参数值在离开服务时被遮盖
+type Sensitivity int32
+
+const (
+	Sensitivity_SENSITIVITY_UNSPECIFIED Sensitivity = 0
+	// 个人身份信息，如邮箱、电话、位置
+	Sensitivity_SENSITIVITY_PII Sensitivity = 1
+	// 凭据等其他机密值
+	Sensitivity_SENSITIVITY_SENSITIVE Sensitivity = 2
+)
+
+// Enum value maps for Sensitivity.
+var (
+	Sensitivity_name = map[int32]string{
+		0: "SENSITIVITY_UNSPECIFIED",
+		1: "SENSITIVITY_PII",
+		2: "SENSITIVITY_SENSITIVE",
+	}
+	Sensitivity_value = map[string]int32{
+		"SENSITIVITY_UNSPECIFIED": 0,
+		"SENSITIVITY_PII":         1,
+		"SENSITIVITY_SENSITIVE":   2,
+	}
+)
+
+func (x Sensitivity) Enum() *Sensitivity {
+	p := new(Sensitivity)
+	*p = x
+	return p
+}
+
+func (x Sensitivity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Sensitivity) Descriptor() protoreflect.EnumDescriptor {
+	return file_intent_v1_intent_proto_enumTypes[2].Descriptor()
+}
+
+func (Sensitivity) Type() protoreflect.EnumType {
+	return &file_intent_v1_intent_proto_enumTypes[2]
+}
+
+func (x Sensitivity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Sensitivity.Descriptor instead.
+func (Sensitivity) EnumDescriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{2}
+}
+
+// 参数值的来源，按优先级从低到高排列
+type ParameterSource int32
+
+const (
+	ParameterSource_PARAMETER_SOURCE_UNSPECIFIED ParameterSource = 0
+	// 契约中参数约束的默认值
+	ParameterSource_PARAMETER_SOURCE_CONTRACT_DEFAULT ParameterSource = 1
+	// 上下文存储（如用户偏好）中的默认值
+	ParameterSource_PARAMETER_SOURCE_CONTEXT_STORE ParameterSource = 2
+	// 流水线中上一步的输出
+	ParameterSource_PARAMETER_SOURCE_PIPELINE_STEP ParameterSource = 3
+	// 用户在请求中直接提供
+	ParameterSource_PARAMETER_SOURCE_USER_INPUT ParameterSource = 4
+)
+
+// Enum value maps for ParameterSource.
+var (
+	ParameterSource_name = map[int32]string{
+		0: "PARAMETER_SOURCE_UNSPECIFIED",
+		1: "PARAMETER_SOURCE_CONTRACT_DEFAULT",
+		2: "PARAMETER_SOURCE_CONTEXT_STORE",
+		3: "PARAMETER_SOURCE_PIPELINE_STEP",
+		4: "PARAMETER_SOURCE_USER_INPUT",
+	}
+	ParameterSource_value = map[string]int32{
+		"PARAMETER_SOURCE_UNSPECIFIED":      0,
+		"PARAMETER_SOURCE_CONTRACT_DEFAULT": 1,
+		"PARAMETER_SOURCE_CONTEXT_STORE":    2,
+		"PARAMETER_SOURCE_PIPELINE_STEP":    3,
+		"PARAMETER_SOURCE_USER_INPUT":       4,
+	}
+)
+
+func (x ParameterSource) Enum() *ParameterSource {
+	p := new(ParameterSource)
+	*p = x
+	return p
+}
+
+func (x ParameterSource) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ParameterSource) Descriptor() protoreflect.EnumDescriptor {
+	return file_intent_v1_intent_proto_enumTypes[3].Descriptor()
+}
+
+func (ParameterSource) Type() protoreflect.EnumType {
+	return &file_intent_v1_intent_proto_enumTypes[3]
+}
+
+func (x ParameterSource) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ParameterSource.Descriptor instead.
+func (ParameterSource) EnumDescriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{3}
+}
+
+// 意图模式定义
+type IntentPattern struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pattern     *IntentPattern_Pattern     `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	Constraints *IntentPattern_Constraints `protobuf:"bytes,2,opt,name=constraints,proto3" json:"constraints,omitempty"`
+	Streaming   StreamingMode              `protobuf:"varint,3,opt,name=streaming,proto3,enum=nfa.intent.v1.StreamingMode" json:"streaming,omitempty"`
+	// 意图数据的驻留限制，未设置时可路由到任意提供者
+	Classification *DataClassification `protobuf:"bytes,4,opt,name=classification,proto3" json:"classification,omitempty"`
+	// 动作的旧名称：按旧名称发出的意图透明地转发到本动作，重命名动作时不影响现有消费者
+	Aliases []string `protobuf:"bytes,5,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	// 参数映射：按顺序把消费者提供的参数转换为提供者期望的参数，不兼容的参数名和单位无需改代码即可互通
+	Mappings []*ParameterMapping `protobuf:"bytes,6,rep,name=mappings,proto3" json:"mappings,omitempty"`
+	// 响应的结构：提供者返回的字段及其约束，供消费者、代码生成和文档使用，提供者可选择按其校验响应
+	Results *ResultSchema `protobuf:"bytes,7,opt,name=results,proto3" json:"results,omitempty"`
+	// 示例：命名的参数组合及其预期响应，供nfactl invoke -example、文档生成和网关的交互式API浏览器使用
+	Examples []*IntentExample `protobuf:"bytes,8,rep,name=examples,proto3" json:"examples,omitempty"`
+}
+
+func (x *IntentPattern) Reset() {
+	*x = IntentPattern{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IntentPattern) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntentPattern) ProtoMessage() {}
+
+func (x *IntentPattern) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntentPattern.ProtoReflect.Descriptor instead.
+func (*IntentPattern) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *IntentPattern) GetPattern() *IntentPattern_Pattern {
+	if x != nil {
+		return x.Pattern
+	}
+	return nil
+}
+
+func (x *IntentPattern) GetConstraints() *IntentPattern_Constraints {
+	if x != nil {
+		return x.Constraints
+	}
+	return nil
+}
+
+func (x *IntentPattern) GetStreaming() StreamingMode {
+	if x != nil {
+		return x.Streaming
+	}
+	return StreamingMode_STREAMING_MODE_UNARY
+}
+
+func (x *IntentPattern) GetClassification() *DataClassification {
+	if x != nil {
+		return x.Classification
+	}
+	return nil
+}
+
+func (x *IntentPattern) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *IntentPattern) GetMappings() []*ParameterMapping {
+	if x != nil {
+		return x.Mappings
+	}
+	return nil
+}
+
+func (x *IntentPattern) GetResults() *ResultSchema {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *IntentPattern) GetExamples() []*IntentExample {
+	if x != nil {
+		return x.Examples
+	}
+	return nil
+}
+
+// 意图示例：一组消费者参数及提供者对其的预期响应
+type IntentExample struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 在合约内唯一的名称
+	Name        string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description string            `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Parameters  map[string]*Value `protobuf:"bytes,3,rep,name=parameters,proto3" json:"parameters,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// 预期响应的字段，按proto字段名；未列出的字段不做比较
+	Result map[string]*Value `protobuf:"bytes,4,rep,name=result,proto3" json:"result,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *IntentExample) Reset() {
+	*x = IntentExample{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IntentExample) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntentExample) ProtoMessage() {}
+
+func (x *IntentExample) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntentExample.ProtoReflect.Descriptor instead.
+func (*IntentExample) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IntentExample) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *IntentExample) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *IntentExample) GetParameters() map[string]*Value {
+	if x != nil {
+		return x.Parameters
+	}
+	return nil
+}
+
+func (x *IntentExample) GetResult() map[string]*Value {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// 响应结构：按proto字段名描述响应消息的字段
+type ResultSchema struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Description string `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	// 每个响应都带有的字段
+	Required []string `protobuf:"bytes,2,rep,name=required,proto3" json:"required,omitempty"`
+	// 字段名 -> 约束，未声明的字段不做检查
+	Fields map[string]*ParameterConstraint `protobuf:"bytes,3,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *ResultSchema) Reset() {
+	*x = ResultSchema{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResultSchema) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResultSchema) ProtoMessage() {}
+
+func (x *ResultSchema) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResultSchema.ProtoReflect.Descriptor instead.
+func (*ResultSchema) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ResultSchema) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ResultSchema) GetRequired() []string {
+	if x != nil {
+		return x.Required
+	}
+	return nil
+}
+
+func (x *ResultSchema) GetFields() map[string]*ParameterConstraint {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+// 参数映射：由from重命名（可换算单位）或由template生成提供者期望的参数to。
+// 请求已带有to时不做映射
+type ParameterMapping struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	To string `protobuf:"bytes,1,opt,name=to,proto3" json:"to,omitempty"`
+	// 消费者参数名，映射后从请求中删除，keep为true时保留
+	From string `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	Keep bool   `protobuf:"varint,3,opt,name=keep,proto3" json:"keep,omitempty"`
+	// 数值参数的单位换算，仅与from一起使用
+	Convert *UnitConversion `protobuf:"bytes,4,opt,name=convert,proto3" json:"convert,omitempty"`
+	// Go text/template模板，以{{.name}}引用已有参数，结果为字符串
+	Template string `protobuf:"bytes,5,opt,name=template,proto3" json:"template,omitempty"`
+}
+
+func (x *ParameterMapping) Reset() {
+	*x = ParameterMapping{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParameterMapping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParameterMapping) ProtoMessage() {}
+
+func (x *ParameterMapping) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParameterMapping.ProtoReflect.Descriptor instead.
+func (*ParameterMapping) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ParameterMapping) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *ParameterMapping) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ParameterMapping) GetKeep() bool {
+	if x != nil {
+		return x.Keep
+	}
+	return false
+}
+
+func (x *ParameterMapping) GetConvert() *UnitConversion {
+	if x != nil {
+		return x.Convert
+	}
+	return nil
+}
+
+func (x *ParameterMapping) GetTemplate() string {
+	if x != nil {
+		return x.Template
+	}
+	return ""
+}
+
+// 单位换算：按已知单位（如ms到s、F到C）换算，或按value*scale+offset换算
+type UnitConversion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FromUnit string   `protobuf:"bytes,1,opt,name=from_unit,json=fromUnit,proto3" json:"from_unit,omitempty"`
+	ToUnit   string   `protobuf:"bytes,2,opt,name=to_unit,json=toUnit,proto3" json:"to_unit,omitempty"`
+	Scale    *float64 `protobuf:"fixed64,3,opt,name=scale,proto3,oneof" json:"scale,omitempty"`
+	Offset   float64  `protobuf:"fixed64,4,opt,name=offset,proto3" json:"offset,omitempty"`
+}
+
+func (x *UnitConversion) Reset() {
+	*x = UnitConversion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnitConversion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnitConversion) ProtoMessage() {}
+
+func (x *UnitConversion) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnitConversion.ProtoReflect.Descriptor instead.
+func (*UnitConversion) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UnitConversion) GetFromUnit() string {
+	if x != nil {
+		return x.FromUnit
+	}
+	return ""
+}
+
+func (x *UnitConversion) GetToUnit() string {
+	if x != nil {
+		return x.ToUnit
+	}
+	return ""
+}
+
+func (x *UnitConversion) GetScale() float64 {
+	if x != nil && x.Scale != nil {
+		return *x.Scale
+	}
+	return 0
+}
+
+func (x *UnitConversion) GetOffset() float64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// 数据分类：限制意图可以被路由到哪些提供者
+type DataClassification struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Residency Residency `protobuf:"varint,1,opt,name=residency,proto3,enum=nfa.intent.v1.Residency" json:"residency,omitempty"`
+	// RESIDENCY_REGION时允许的区域，与提供者的nfa.region标签比较
+	Regions []string `protobuf:"bytes,2,rep,name=regions,proto3" json:"regions,omitempty"`
+}
+
+func (x *DataClassification) Reset() {
+	*x = DataClassification{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DataClassification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataClassification) ProtoMessage() {}
+
+func (x *DataClassification) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataClassification.ProtoReflect.Descriptor instead.
+func (*DataClassification) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DataClassification) GetResidency() Residency {
+	if x != nil {
+		return x.Residency
+	}
+	return Residency_RESIDENCY_UNRESTRICTED
+}
+
+func (x *DataClassification) GetRegions() []string {
+	if x != nil {
+		return x.Regions
+	}
+	return nil
+}
+
+// 参数约束
+type ParameterConstraint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Constraint:
+	//	*ParameterConstraint_StringConstraint
+	//	*ParameterConstraint_NumberConstraint
+	//	*ParameterConstraint_EnumConstraint
+	//	*ParameterConstraint_ArrayConstraint
+	//	*ParameterConstraint_ObjectConstraint
+	Constraint isParameterConstraint_Constraint `protobuf_oneof:"constraint"`
+	// 参数值在日志、追踪、审计和分析数据中的脱敏方式
+	Sensitivity Sensitivity `protobuf:"varint,4,opt,name=sensitivity,proto3,enum=nfa.intent.v1.Sensitivity" json:"sensitivity,omitempty"`
+	// 请求和上下文都未提供该参数时使用的默认值
+	DefaultValue *Value `protobuf:"bytes,5,opt,name=default_value,json=defaultValue,proto3" json:"default_value,omitempty"`
+}
+
+func (x *ParameterConstraint) Reset() {
+	*x = ParameterConstraint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParameterConstraint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParameterConstraint) ProtoMessage() {}
+
+func (x *ParameterConstraint) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParameterConstraint.ProtoReflect.Descriptor instead.
+func (*ParameterConstraint) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{6}
+}
+
+func (m *ParameterConstraint) GetConstraint() isParameterConstraint_Constraint {
+	if m != nil {
+		return m.Constraint
+	}
+	return nil
+}
+
+func (x *ParameterConstraint) GetStringConstraint() *StringConstraint {
+	if x, ok := x.GetConstraint().(*ParameterConstraint_StringConstraint); ok {
+		return x.StringConstraint
+	}
+	return nil
+}
+
+func (x *ParameterConstraint) GetNumberConstraint() *NumberConstraint {
+	if x, ok := x.GetConstraint().(*ParameterConstraint_NumberConstraint); ok {
+		return x.NumberConstraint
+	}
+	return nil
+}
+
+func (x *ParameterConstraint) GetEnumConstraint() *EnumConstraint {
+	if x, ok := x.GetConstraint().(*ParameterConstraint_EnumConstraint); ok {
+		return x.EnumConstraint
+	}
+	return nil
+}
+
+func (x *ParameterConstraint) GetArrayConstraint() *ArrayConstraint {
+	if x, ok := x.GetConstraint().(*ParameterConstraint_ArrayConstraint); ok {
+		return x.ArrayConstraint
+	}
+	return nil
+}
+
+func (x *ParameterConstraint) GetObjectConstraint() *ObjectConstraint {
+	if x, ok := x.GetConstraint().(*ParameterConstraint_ObjectConstraint); ok {
+		return x.ObjectConstraint
+	}
+	return nil
+}
+
+func (x *ParameterConstraint) GetSensitivity() Sensitivity {
+	if x != nil {
+		return x.Sensitivity
+	}
+	return Sensitivity_SENSITIVITY_UNSPECIFIED
+}
+
+func (x *ParameterConstraint) GetDefaultValue() *Value {
+	if x != nil {
+		return x.DefaultValue
+	}
+	return nil
+}
+
+type isParameterConstraint_Constraint interface {
+	isParameterConstraint_Constraint()
+}
+
+type ParameterConstraint_StringConstraint struct {
+	StringConstraint *StringConstraint `protobuf:"bytes,1,opt,name=string_constraint,json=stringConstraint,proto3,oneof"`
+}
+
+type ParameterConstraint_NumberConstraint struct {
+	NumberConstraint *NumberConstraint `protobuf:"bytes,2,opt,name=number_constraint,json=numberConstraint,proto3,oneof"`
+}
+
+type ParameterConstraint_EnumConstraint struct {
+	EnumConstraint *EnumConstraint `protobuf:"bytes,3,opt,name=enum_constraint,json=enumConstraint,proto3,oneof"`
+}
+
+type ParameterConstraint_ArrayConstraint struct {
+	ArrayConstraint *ArrayConstraint `protobuf:"bytes,6,opt,name=array_constraint,json=arrayConstraint,proto3,oneof"`
+}
+
+type ParameterConstraint_ObjectConstraint struct {
+	ObjectConstraint *ObjectConstraint `protobuf:"bytes,7,opt,name=object_constraint,json=objectConstraint,proto3,oneof"`
+}
+
+func (*ParameterConstraint_StringConstraint) isParameterConstraint_Constraint() {}
+
+func (*ParameterConstraint_NumberConstraint) isParameterConstraint_Constraint() {}
+
+func (*ParameterConstraint_EnumConstraint) isParameterConstraint_Constraint() {}
+
+func (*ParameterConstraint_ArrayConstraint) isParameterConstraint_Constraint() {}
+
+func (*ParameterConstraint_ObjectConstraint) isParameterConstraint_Constraint() {}
+
+type StringConstraint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MinLength *uint32 `protobuf:"varint,1,opt,name=min_length,json=minLength,proto3,oneof" json:"min_length,omitempty"`
+	MaxLength *uint32 `protobuf:"varint,2,opt,name=max_length,json=maxLength,proto3,oneof" json:"max_length,omitempty"`
+	Pattern   *string `protobuf:"bytes,3,opt,name=pattern,proto3,oneof" json:"pattern,omitempty"` // 正则表达式
+}
+
+func (x *StringConstraint) Reset() {
+	*x = StringConstraint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StringConstraint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StringConstraint) ProtoMessage() {}
+
+func (x *StringConstraint) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StringConstraint.ProtoReflect.Descriptor instead.
+func (*StringConstraint) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StringConstraint) GetMinLength() uint32 {
+	if x != nil && x.MinLength != nil {
+		return *x.MinLength
+	}
+	return 0
+}
+
+func (x *StringConstraint) GetMaxLength() uint32 {
+	if x != nil && x.MaxLength != nil {
+		return *x.MaxLength
+	}
+	return 0
+}
+
+func (x *StringConstraint) GetPattern() string {
+	if x != nil && x.Pattern != nil {
+		return *x.Pattern
+	}
+	return ""
+}
+
+type NumberConstraint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Min *float64 `protobuf:"fixed64,1,opt,name=min,proto3,oneof" json:"min,omitempty"`
+	Max *float64 `protobuf:"fixed64,2,opt,name=max,proto3,oneof" json:"max,omitempty"`
+}
+
+func (x *NumberConstraint) Reset() {
+	*x = NumberConstraint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NumberConstraint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NumberConstraint) ProtoMessage() {}
+
+func (x *NumberConstraint) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NumberConstraint.ProtoReflect.Descriptor instead.
+func (*NumberConstraint) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *NumberConstraint) GetMin() float64 {
+	if x != nil && x.Min != nil {
+		return *x.Min
+	}
+	return 0
+}
+
+func (x *NumberConstraint) GetMax() float64 {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return 0
+}
+
+type EnumConstraint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Values []string `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (x *EnumConstraint) Reset() {
+	*x = EnumConstraint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnumConstraint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnumConstraint) ProtoMessage() {}
+
+func (x *EnumConstraint) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnumConstraint.ProtoReflect.Descriptor instead.
+func (*EnumConstraint) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *EnumConstraint) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// 列表参数的约束
+type ArrayConstraint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 每个元素的约束，未设置时不检查元素
+	Items     *ParameterConstraint `protobuf:"bytes,1,opt,name=items,proto3" json:"items,omitempty"`
+	MinLength *uint32              `protobuf:"varint,2,opt,name=min_length,json=minLength,proto3,oneof" json:"min_length,omitempty"` // 最少元素数
+	MaxLength *uint32              `protobuf:"varint,3,opt,name=max_length,json=maxLength,proto3,oneof" json:"max_length,omitempty"` // 最多元素数
+}
+
+func (x *ArrayConstraint) Reset() {
+	*x = ArrayConstraint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArrayConstraint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArrayConstraint) ProtoMessage() {}
+
+func (x *ArrayConstraint) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArrayConstraint.ProtoReflect.Descriptor instead.
+func (*ArrayConstraint) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ArrayConstraint) GetItems() *ParameterConstraint {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ArrayConstraint) GetMinLength() uint32 {
+	if x != nil && x.MinLength != nil {
+		return *x.MinLength
+	}
+	return 0
+}
+
+func (x *ArrayConstraint) GetMaxLength() uint32 {
+	if x != nil && x.MaxLength != nil {
+		return *x.MaxLength
+	}
+	return 0
+}
+
+// 对象参数的约束
+type ObjectConstraint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 各属性的约束，未声明的属性不检查
+	Properties map[string]*ParameterConstraint `protobuf:"bytes,1,rep,name=properties,proto3" json:"properties,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// 必须提供的属性，有默认值的属性除外
+	Required []string `protobuf:"bytes,2,rep,name=required,proto3" json:"required,omitempty"`
+}
+
+func (x *ObjectConstraint) Reset() {
+	*x = ObjectConstraint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ObjectConstraint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObjectConstraint) ProtoMessage() {}
+
+func (x *ObjectConstraint) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObjectConstraint.ProtoReflect.Descriptor instead.
+func (*ObjectConstraint) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ObjectConstraint) GetProperties() map[string]*ParameterConstraint {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+func (x *ObjectConstraint) GetRequired() []string {
+	if x != nil {
+		return x.Required
+	}
+	return nil
+}
+
+// 意图契约
+type IntentContract struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version  string      `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Kind     string      `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Metadata *Metadata   `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Spec     *IntentSpec `protobuf:"bytes,4,opt,name=spec,proto3" json:"spec,omitempty"`
+}
+
+func (x *IntentContract) Reset() {
+	*x = IntentContract{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IntentContract) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntentContract) ProtoMessage() {}
+
+func (x *IntentContract) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntentContract.ProtoReflect.Descriptor instead.
+func (*IntentContract) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *IntentContract) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *IntentContract) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *IntentContract) GetMetadata() *Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *IntentContract) GetSpec() *IntentSpec {
+	if x != nil {
+		return x.Spec
+	}
+	return nil
+}
+
+type Metadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description string            `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Labels      map[string]string `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *Metadata) Reset() {
+	*x = Metadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Metadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metadata) ProtoMessage() {}
+
+func (x *Metadata) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
+func (*Metadata) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Metadata) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Metadata) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Metadata) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type IntentSpec struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IntentPatterns   []*IntentPattern  `protobuf:"bytes,1,rep,name=intent_patterns,json=intentPatterns,proto3" json:"intent_patterns,omitempty"`
+	Implementation   *Implementation   `protobuf:"bytes,2,opt,name=implementation,proto3" json:"implementation,omitempty"`
+	QualityOfService *QualityOfService `protobuf:"bytes,3,opt,name=quality_of_service,json=qualityOfService,proto3" json:"quality_of_service,omitempty"`
+}
+
+func (x *IntentSpec) Reset() {
+	*x = IntentSpec{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IntentSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntentSpec) ProtoMessage() {}
+
+func (x *IntentSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntentSpec.ProtoReflect.Descriptor instead.
+func (*IntentSpec) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *IntentSpec) GetIntentPatterns() []*IntentPattern {
+	if x != nil {
+		return x.IntentPatterns
+	}
+	return nil
+}
+
+func (x *IntentSpec) GetImplementation() *Implementation {
+	if x != nil {
+		return x.Implementation
+	}
+	return nil
+}
+
+func (x *IntentSpec) GetQualityOfService() *QualityOfService {
+	if x != nil {
+		return x.QualityOfService
+	}
+	return nil
+}
+
+type Implementation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Endpoint  *Endpoint              `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	Resources []*ResourceRequirement `protobuf:"bytes,2,rep,name=resources,proto3" json:"resources,omitempty"`
+}
+
+func (x *Implementation) Reset() {
+	*x = Implementation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Implementation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Implementation) ProtoMessage() {}
+
+func (x *Implementation) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Implementation.ProtoReflect.Descriptor instead.
+func (*Implementation) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Implementation) GetEndpoint() *Endpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
+func (x *Implementation) GetResources() []*ResourceRequirement {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+type Endpoint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// Types that are assignable to Address:
+	//	*Endpoint_Grpc
+	//	*Endpoint_Http
+	Address isEndpoint_Address `protobuf_oneof:"address"`
+	// 请求消息的编码，按gRPC内容子类型命名：proto（默认）、json或msgpack
+	Codec string `protobuf:"bytes,4,opt,name=codec,proto3" json:"codec,omitempty"`
+}
+
+func (x *Endpoint) Reset() {
+	*x = Endpoint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Endpoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Endpoint) ProtoMessage() {}
+
+func (x *Endpoint) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Endpoint.ProtoReflect.Descriptor instead.
+func (*Endpoint) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Endpoint) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (m *Endpoint) GetAddress() isEndpoint_Address {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (x *Endpoint) GetGrpc() *GrpcAddress {
+	if x, ok := x.GetAddress().(*Endpoint_Grpc); ok {
+		return x.Grpc
+	}
+	return nil
+}
+
+func (x *Endpoint) GetHttp() *HttpAddress {
+	if x, ok := x.GetAddress().(*Endpoint_Http); ok {
+		return x.Http
+	}
+	return nil
+}
+
+func (x *Endpoint) GetCodec() string {
+	if x != nil {
+		return x.Codec
+	}
+	return ""
+}
+
+type isEndpoint_Address interface {
+	isEndpoint_Address()
+}
+
+type Endpoint_Grpc struct {
+	Grpc *GrpcAddress `protobuf:"bytes,2,opt,name=grpc,proto3,oneof"`
+}
+
+type Endpoint_Http struct {
+	Http *HttpAddress `protobuf:"bytes,3,opt,name=http,proto3,oneof"`
+}
+
+func (*Endpoint_Grpc) isEndpoint_Address() {}
+
+func (*Endpoint_Http) isEndpoint_Address() {}
+
+type GrpcAddress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Port      uint32 `protobuf:"varint,1,opt,name=port,proto3" json:"port,omitempty"`
+	Procedure string `protobuf:"bytes,2,opt,name=procedure,proto3" json:"procedure,omitempty"`
+	// 提供者的固定主机名或IP，用于静态注册的提供者；运行时自行注册时为空
+	Host string `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
+}
+
+func (x *GrpcAddress) Reset() {
+	*x = GrpcAddress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GrpcAddress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrpcAddress) ProtoMessage() {}
+
+func (x *GrpcAddress) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrpcAddress.ProtoReflect.Descriptor instead.
+func (*GrpcAddress) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GrpcAddress) GetPort() uint32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *GrpcAddress) GetProcedure() string {
+	if x != nil {
+		return x.Procedure
+	}
+	return ""
+}
+
+func (x *GrpcAddress) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+type HttpAddress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+}
+
+func (x *HttpAddress) Reset() {
+	*x = HttpAddress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HttpAddress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HttpAddress) ProtoMessage() {}
+
+func (x *HttpAddress) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HttpAddress.ProtoReflect.Descriptor instead.
+func (*HttpAddress) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *HttpAddress) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type ResourceRequirement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type  string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Units string `protobuf:"bytes,2,opt,name=units,proto3" json:"units,omitempty"`
+	Kind  string `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+}
+
+func (x *ResourceRequirement) Reset() {
+	*x = ResourceRequirement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResourceRequirement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceRequirement) ProtoMessage() {}
+
+func (x *ResourceRequirement) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceRequirement.ProtoReflect.Descriptor instead.
+func (*ResourceRequirement) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ResourceRequirement) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ResourceRequirement) GetUnits() string {
+	if x != nil {
+		return x.Units
+	}
+	return ""
+}
+
+func (x *ResourceRequirement) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+type QualityOfService struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Latency      string `protobuf:"bytes,1,opt,name=latency,proto3" json:"latency,omitempty"`
+	Availability string `protobuf:"bytes,2,opt,name=availability,proto3" json:"availability,omitempty"`
+	Priority     string `protobuf:"bytes,3,opt,name=priority,proto3" json:"priority,omitempty"`
+}
+
+func (x *QualityOfService) Reset() {
+	*x = QualityOfService{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QualityOfService) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QualityOfService) ProtoMessage() {}
+
+func (x *QualityOfService) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QualityOfService.ProtoReflect.Descriptor instead.
+func (*QualityOfService) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *QualityOfService) GetLatency() string {
+	if x != nil {
+		return x.Latency
+	}
+	return ""
+}
+
+func (x *QualityOfService) GetAvailability() string {
+	if x != nil {
+		return x.Availability
+	}
+	return ""
+}
+
+func (x *QualityOfService) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+// 通用值类型
+type Value struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Value:
+	//	*Value_StringValue
+	//	*Value_NumberValue
+	//	*Value_BoolValue
+	//	*Value_ListValue
+	//	*Value_StructValue
+	Value isValue_Value `protobuf_oneof:"value"`
+}
+
+func (x *Value) Reset() {
+	*x = Value{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Value) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Value) ProtoMessage() {}
+
+func (x *Value) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Value.ProtoReflect.Descriptor instead.
+func (*Value) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{21}
+}
+
+func (m *Value) GetValue() isValue_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (x *Value) GetStringValue() string {
+	if x, ok := x.GetValue().(*Value_StringValue); ok {
+		return x.StringValue
+	}
+	return ""
+}
+
+func (x *Value) GetNumberValue() float64 {
+	if x, ok := x.GetValue().(*Value_NumberValue); ok {
+		return x.NumberValue
+	}
+	return 0
+}
+
+func (x *Value) GetBoolValue() bool {
+	if x, ok := x.GetValue().(*Value_BoolValue); ok {
+		return x.BoolValue
+	}
+	return false
+}
+
+func (x *Value) GetListValue() *ListValue {
+	if x, ok := x.GetValue().(*Value_ListValue); ok {
+		return x.ListValue
+	}
+	return nil
+}
+
+func (x *Value) GetStructValue() *StructValue {
+	if x, ok := x.GetValue().(*Value_StructValue); ok {
+		return x.StructValue
+	}
+	return nil
+}
+
+type isValue_Value interface {
+	isValue_Value()
+}
+
+type Value_StringValue struct {
+	StringValue string `protobuf:"bytes,1,opt,name=string_value,json=stringValue,proto3,oneof"`
+}
+
+type Value_NumberValue struct {
+	NumberValue float64 `protobuf:"fixed64,2,opt,name=number_value,json=numberValue,proto3,oneof"`
+}
+
+type Value_BoolValue struct {
+	BoolValue bool `protobuf:"varint,3,opt,name=bool_value,json=boolValue,proto3,oneof"`
+}
+
+type Value_ListValue struct {
+	ListValue *ListValue `protobuf:"bytes,4,opt,name=list_value,json=listValue,proto3,oneof"`
+}
+
+type Value_StructValue struct {
+	StructValue *StructValue `protobuf:"bytes,5,opt,name=struct_value,json=structValue,proto3,oneof"`
+}
+
+func (*Value_StringValue) isValue_Value() {}
+
+func (*Value_NumberValue) isValue_Value() {}
+
+func (*Value_BoolValue) isValue_Value() {}
+
+func (*Value_ListValue) isValue_Value() {}
+
+func (*Value_StructValue) isValue_Value() {}
+
+type ListValue struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Values []*Value `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (x *ListValue) Reset() {
+	*x = ListValue{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListValue) ProtoMessage() {}
+
+func (x *ListValue) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListValue.ProtoReflect.Descriptor instead.
+func (*ListValue) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListValue) GetValues() []*Value {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type StructValue struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Fields map[string]*Value `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *StructValue) Reset() {
+	*x = StructValue{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StructValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StructValue) ProtoMessage() {}
+
+func (x *StructValue) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StructValue.ProtoReflect.Descriptor instead.
+func (*StructValue) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *StructValue) GetFields() map[string]*Value {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+// 意图请求上下文
+type IntentContext struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserId      string            `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	DeviceId    string            `protobuf:"bytes,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	SessionId   string            `protobuf:"bytes,3,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Preferences map[string]*Value `protobuf:"bytes,4,rep,name=preferences,proto3" json:"preferences,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *IntentContext) Reset() {
+	*x = IntentContext{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IntentContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntentContext) ProtoMessage() {}
+
+func (x *IntentContext) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntentContext.ProtoReflect.Descriptor instead.
+func (*IntentContext) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *IntentContext) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *IntentContext) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *IntentContext) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *IntentContext) GetPreferences() map[string]*Value {
+	if x != nil {
+		return x.Preferences
+	}
+	return nil
+}
+
+// 意图请求
+type IntentRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Action     string            `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	Parameters map[string]*Value `protobuf:"bytes,2,rep,name=parameters,proto3" json:"parameters,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Context    *IntentContext    `protobuf:"bytes,3,opt,name=context,proto3" json:"context,omitempty"`
+	// 为true时响应附带每个参数的来源，用于排查参数值从何而来
+	Debug bool `protobuf:"varint,4,opt,name=debug,proto3" json:"debug,omitempty"`
+	// 每个参数值的来源，由补全参数的组件（网关、运行时、流水线）填写
+	Provenance map[string]*ParameterProvenance `protobuf:"bytes,5,rep,name=provenance,proto3" json:"provenance,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *IntentRequest) Reset() {
+	*x = IntentRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IntentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntentRequest) ProtoMessage() {}
+
+func (x *IntentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntentRequest.ProtoReflect.Descriptor instead.
+func (*IntentRequest) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *IntentRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *IntentRequest) GetParameters() map[string]*Value {
+	if x != nil {
+		return x.Parameters
+	}
+	return nil
+}
+
+func (x *IntentRequest) GetContext() *IntentContext {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+func (x *IntentRequest) GetDebug() bool {
+	if x != nil {
+		return x.Debug
+	}
+	return false
+}
+
+func (x *IntentRequest) GetProvenance() map[string]*ParameterProvenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return nil
+}
+
+type ParameterProvenance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Source ParameterSource `protobuf:"varint,1,opt,name=source,proto3,enum=nfa.intent.v1.ParameterSource" json:"source,omitempty"`
+	// 来源的具体位置，如偏好键、契约名或流水线步骤名
+	Origin string `protobuf:"bytes,2,opt,name=origin,proto3" json:"origin,omitempty"`
+	// 也提供了该参数但被覆盖的来源
+	Overridden []ParameterSource `protobuf:"varint,3,rep,packed,name=overridden,proto3,enum=nfa.intent.v1.ParameterSource" json:"overridden,omitempty"`
+}
+
+func (x *ParameterProvenance) Reset() {
+	*x = ParameterProvenance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParameterProvenance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParameterProvenance) ProtoMessage() {}
+
+func (x *ParameterProvenance) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParameterProvenance.ProtoReflect.Descriptor instead.
+func (*ParameterProvenance) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ParameterProvenance) GetSource() ParameterSource {
+	if x != nil {
+		return x.Source
+	}
+	return ParameterSource_PARAMETER_SOURCE_UNSPECIFIED
+}
+
+func (x *ParameterProvenance) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *ParameterProvenance) GetOverridden() []ParameterSource {
+	if x != nil {
+		return x.Overridden
+	}
+	return nil
+}
+
+// 一次调用的履行情况，由提供者在响应的gRPC trailer（nfa-fulfillment-bin）中返回，
+// 供客户端展示和记录服务质量
+type Fulfillment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 提供服务的服务ID
+	ServiceId string `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	// 请求进入NFA后等待提供者开始处理的时间
+	QueueTimeMs uint64 `protobuf:"varint,2,opt,name=queue_time_ms,json=queueTimeMs,proto3" json:"queue_time_ms,omitempty"`
+	// 提供者处理请求的时间
+	ProcessingTimeMs uint64 `protobuf:"varint,3,opt,name=processing_time_ms,json=processingTimeMs,proto3" json:"processing_time_ms,omitempty"`
+	// 请求经过的NFA组件数（网关、Broker、运行时），直接调用提供者时为1
+	Hops uint32 `protobuf:"varint,4,opt,name=hops,proto3" json:"hops,omitempty"`
+	// 提供者估算的调用成本，单位与调度器的cost_units相同
+	CostUnits float64 `protobuf:"fixed64,5,opt,name=cost_units,json=costUnits,proto3" json:"cost_units,omitempty"`
+	// 本次履约的唯一ID，用户反馈（FeedbackService）按此ID关联到提供者
+	FulfillmentId string `protobuf:"bytes,6,opt,name=fulfillment_id,json=fulfillmentId,proto3" json:"fulfillment_id,omitempty"`
+}
+
+func (x *Fulfillment) Reset() {
+	*x = Fulfillment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Fulfillment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fulfillment) ProtoMessage() {}
+
+func (x *Fulfillment) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fulfillment.ProtoReflect.Descriptor instead.
+func (*Fulfillment) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Fulfillment) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *Fulfillment) GetQueueTimeMs() uint64 {
+	if x != nil {
+		return x.QueueTimeMs
+	}
+	return 0
+}
+
+func (x *Fulfillment) GetProcessingTimeMs() uint64 {
+	if x != nil {
+		return x.ProcessingTimeMs
+	}
+	return 0
+}
+
+func (x *Fulfillment) GetHops() uint32 {
+	if x != nil {
+		return x.Hops
+	}
+	return 0
+}
+
+func (x *Fulfillment) GetCostUnits() float64 {
+	if x != nil {
+		return x.CostUnits
+	}
+	return 0
+}
+
+func (x *Fulfillment) GetFulfillmentId() string {
+	if x != nil {
+		return x.FulfillmentId
+	}
+	return ""
+}
+
+type IntentPattern_Pattern struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Action     string            `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	Parameters map[string]*Value `protobuf:"bytes,2,rep,name=parameters,proto3" json:"parameters,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *IntentPattern_Pattern) Reset() {
+	*x = IntentPattern_Pattern{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IntentPattern_Pattern) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntentPattern_Pattern) ProtoMessage() {}
+
+func (x *IntentPattern_Pattern) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntentPattern_Pattern.ProtoReflect.Descriptor instead.
+func (*IntentPattern_Pattern) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *IntentPattern_Pattern) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *IntentPattern_Pattern) GetParameters() map[string]*Value {
+	if x != nil {
+		return x.Parameters
+	}
+	return nil
+}
+
+type IntentPattern_Constraints struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RequiredParameters   []string                        `protobuf:"bytes,1,rep,name=required_parameters,json=requiredParameters,proto3" json:"required_parameters,omitempty"`
+	ParameterConstraints map[string]*ParameterConstraint `protobuf:"bytes,2,rep,name=parameter_constraints,json=parameterConstraints,proto3" json:"parameter_constraints,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *IntentPattern_Constraints) Reset() {
+	*x = IntentPattern_Constraints{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_intent_v1_intent_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IntentPattern_Constraints) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntentPattern_Constraints) ProtoMessage() {}
+
+func (x *IntentPattern_Constraints) ProtoReflect() protoreflect.Message {
+	mi := &file_intent_v1_intent_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntentPattern_Constraints.ProtoReflect.Descriptor instead.
+func (*IntentPattern_Constraints) Descriptor() ([]byte, []int) {
+	return file_intent_v1_intent_proto_rawDescGZIP(), []int{0, 1}
+}
+
+func (x *IntentPattern_Constraints) GetRequiredParameters() []string {
+	if x != nil {
+		return x.RequiredParameters
+	}
+	return nil
+}
+
+func (x *IntentPattern_Constraints) GetParameterConstraints() map[string]*ParameterConstraint {
+	if x != nil {
+		return x.ParameterConstraints
+	}
+	return nil
+}
+
+var File_intent_v1_intent_proto protoreflect.FileDescriptor
+
+var file_intent_v1_intent_proto_rawDesc = []byte{
+	0x0a, 0x16, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2f, 0x76, 0x31, 0x2f, 0x69, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x22, 0xe0, 0x07, 0x0a, 0x0d, 0x49, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x12, 0x3e, 0x0a, 0x07, 0x70, 0x61, 0x74,
+	0x74, 0x65, 0x72, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x6e, 0x66, 0x61,
+	0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x2e, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e,
+	0x52, 0x07, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x12, 0x4a, 0x0a, 0x0b, 0x63, 0x6f, 0x6e,
+	0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28,
+	0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x49,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x2e, 0x43, 0x6f, 0x6e,
+	0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x73, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72,
+	0x61, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x3a, 0x0a, 0x09, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69,
+	0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69,
+	0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x09, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e,
+	0x67, 0x12, 0x49, 0x0a, 0x0e, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6e, 0x66, 0x61, 0x2e,
+	0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x43, 0x6c,
+	0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x63, 0x6c,
+	0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07,
+	0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x61,
+	0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x12, 0x3b, 0x0a, 0x08, 0x6d, 0x61, 0x70, 0x70, 0x69, 0x6e,
+	0x67, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74,
+	0x65, 0x72, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x52, 0x08, 0x6d, 0x61, 0x70, 0x70, 0x69,
+	0x6e, 0x67, 0x73, 0x12, 0x35, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x53, 0x63, 0x68, 0x65, 0x6d,
+	0x61, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x38, 0x0a, 0x08, 0x65, 0x78,
+	0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6e,
+	0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x45, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x52, 0x08, 0x65, 0x78, 0x61, 0x6d,
+	0x70, 0x6c, 0x65, 0x73, 0x1a, 0xcc, 0x01, 0x0a, 0x07, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e,
+	0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x54, 0x0a, 0x0a, 0x70, 0x61, 0x72, 0x61,
+	0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x6e,
+	0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x2e, 0x50, 0x61, 0x74, 0x74, 0x65,
+	0x72, 0x6e, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x0a, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x1a, 0x53,
+	0x0a, 0x0f, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x1a, 0xa4, 0x02, 0x0a, 0x0b, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69,
+	0x6e, 0x74, 0x73, 0x12, 0x2f, 0x0a, 0x13, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x5f,
+	0x70, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x12, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65,
+	0x74, 0x65, 0x72, 0x73, 0x12, 0x77, 0x0a, 0x15, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65,
+	0x72, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x42, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72,
+	0x6e, 0x2e, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x73, 0x2e, 0x50, 0x61,
+	0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e,
+	0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x14, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74,
+	0x65, 0x72, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x73, 0x1a, 0x6b, 0x0a,
+	0x19, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72,
+	0x61, 0x69, 0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x38, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6e, 0x66,
+	0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x65, 0x74, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xfb, 0x02, 0x0a, 0x0d, 0x49,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x45, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x4c, 0x0a, 0x0a, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x45, 0x78, 0x61,
+	0x6d, 0x70, 0x6c, 0x65, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73,
+	0x12, 0x40, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x28, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x45, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x2e, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x1a, 0x53, 0x0a, 0x0f, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x4f, 0x0a, 0x0b, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xec, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x72,
+	0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x72,
+	0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x3f, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x53, 0x63,
+	0x68, 0x65, 0x6d, 0x61, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x1a, 0x5d, 0x0a, 0x0b, 0x46, 0x69, 0x65, 0x6c,
+	0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x38, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74,
+	0x65, 0x72, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x9f, 0x01, 0x0a, 0x10, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x65, 0x74, 0x65, 0x72, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x12, 0x0e, 0x0a, 0x02,
+	0x74, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x12, 0x0a, 0x04,
+	0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d,
+	0x12, 0x12, 0x0a, 0x04, 0x6b, 0x65, 0x65, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04,
+	0x6b, 0x65, 0x65, 0x70, 0x12, 0x37, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x6e, 0x69, 0x74, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x12, 0x1a, 0x0a,
+	0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x22, 0x83, 0x01, 0x0a, 0x0e, 0x55, 0x6e,
+	0x69, 0x74, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09,
+	0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x66, 0x72, 0x6f, 0x6d, 0x55, 0x6e, 0x69, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x6f, 0x5f,
+	0x75, 0x6e, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x6f, 0x55, 0x6e,
+	0x69, 0x74, 0x12, 0x19, 0x0a, 0x05, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x01, 0x48, 0x00, 0x52, 0x05, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x16, 0x0a,
+	0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x6f,
+	0x66, 0x66, 0x73, 0x65, 0x74, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x22,
+	0x66, 0x0a, 0x12, 0x44, 0x61, 0x74, 0x61, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x36, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x69, 0x64, 0x65, 0x6e,
+	0x63, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x69, 0x64, 0x65, 0x6e,
+	0x63, 0x79, 0x52, 0x09, 0x72, 0x65, 0x73, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x18, 0x0a,
+	0x07, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xa3, 0x04, 0x0a, 0x13, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x65, 0x74, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x12,
+	0x4e, 0x0a, 0x11, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72,
+	0x61, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6e, 0x66, 0x61,
+	0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e,
+	0x67, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x10, 0x73,
+	0x74, 0x72, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x12,
+	0x4e, 0x0a, 0x11, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72,
+	0x61, 0x69, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6e, 0x66, 0x61,
+	0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x10, 0x6e,
+	0x75, 0x6d, 0x62, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x12,
+	0x48, 0x0a, 0x0f, 0x65, 0x6e, 0x75, 0x6d, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69,
+	0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x43, 0x6f, 0x6e,
+	0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0e, 0x65, 0x6e, 0x75, 0x6d, 0x43,
+	0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x12, 0x4b, 0x0a, 0x10, 0x61, 0x72, 0x72,
+	0x61, 0x79, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x41, 0x72, 0x72, 0x61, 0x79, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61,
+	0x69, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0f, 0x61, 0x72, 0x72, 0x61, 0x79, 0x43, 0x6f, 0x6e, 0x73,
+	0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x12, 0x4e, 0x0a, 0x11, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1f, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69,
+	0x6e, 0x74, 0x48, 0x00, 0x52, 0x10, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x43, 0x6f, 0x6e, 0x73,
+	0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x12, 0x3c, 0x0a, 0x0b, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74,
+	0x69, 0x76, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x6e, 0x66,
+	0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x6e, 0x73,
+	0x69, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x52, 0x0b, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69,
+	0x76, 0x69, 0x74, 0x79, 0x12, 0x39, 0x0a, 0x0d, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6e, 0x66,
+	0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x52, 0x0c, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x42,
+	0x0c, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x22, 0xa3, 0x01,
+	0x0a, 0x10, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69,
+	0x6e, 0x74, 0x12, 0x22, 0x0a, 0x0a, 0x6d, 0x69, 0x6e, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x09, 0x6d, 0x69, 0x6e, 0x4c, 0x65, 0x6e,
+	0x67, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x6c, 0x65,
+	0x6e, 0x67, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x09, 0x6d, 0x61,
+	0x78, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x70, 0x61,
+	0x74, 0x74, 0x65, 0x72, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x07, 0x70,
+	0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6d, 0x69,
+	0x6e, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6d, 0x61, 0x78,
+	0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x70, 0x61, 0x74, 0x74,
+	0x65, 0x72, 0x6e, 0x22, 0x50, 0x0a, 0x10, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x43, 0x6f, 0x6e,
+	0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x12, 0x15, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x03, 0x6d, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x15,
+	0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01, 0x52, 0x03, 0x6d,
+	0x61, 0x78, 0x88, 0x01, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x6d, 0x69, 0x6e, 0x42, 0x06, 0x0a,
+	0x04, 0x5f, 0x6d, 0x61, 0x78, 0x22, 0x28, 0x0a, 0x0e, 0x45, 0x6e, 0x75, 0x6d, 0x43, 0x6f, 0x6e,
+	0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22,
+	0xb1, 0x01, 0x0a, 0x0f, 0x41, 0x72, 0x72, 0x61, 0x79, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61,
+	0x69, 0x6e, 0x74, 0x12, 0x38, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x73,
+	0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x22, 0x0a,
+	0x0a, 0x6d, 0x69, 0x6e, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x48, 0x00, 0x52, 0x09, 0x6d, 0x69, 0x6e, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x88, 0x01,
+	0x01, 0x12, 0x22, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x4c, 0x65, 0x6e, 0x67,
+	0x74, 0x68, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6d, 0x69, 0x6e, 0x5f, 0x6c, 0x65,
+	0x6e, 0x67, 0x74, 0x68, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x6c, 0x65, 0x6e,
+	0x67, 0x74, 0x68, 0x22, 0xe2, 0x01, 0x0a, 0x10, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x43, 0x6f,
+	0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x12, 0x4f, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x70,
+	0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x6e,
+	0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x2e, 0x50, 0x72,
+	0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x70,
+	0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71,
+	0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x71,
+	0x75, 0x69, 0x72, 0x65, 0x64, 0x1a, 0x61, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74,
+	0x69, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x38, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6e, 0x66, 0x61, 0x2e,
+	0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65,
+	0x74, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xa2, 0x01, 0x0a, 0x0e, 0x49, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x33, 0x0a, 0x08, 0x6d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x66,
+	0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x2d,
+	0x0a, 0x04, 0x73, 0x70, 0x65, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6e,
+	0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x53, 0x70, 0x65, 0x63, 0x52, 0x04, 0x73, 0x70, 0x65, 0x63, 0x22, 0xb8, 0x01,
+	0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20,
+	0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x3b, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x23, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x1a, 0x39, 0x0a,
+	0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xe9, 0x01, 0x0a, 0x0a, 0x49, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x53, 0x70, 0x65, 0x63, 0x12, 0x45, 0x0a, 0x0f, 0x69, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x5f, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x52, 0x0e,
+	0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x73, 0x12, 0x45,
+	0x0a, 0x0e, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d, 0x70, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x4d, 0x0a, 0x12, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79,
+	0x5f, 0x6f, 0x66, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1f, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x4f, 0x66, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x52, 0x10, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x4f, 0x66, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x22, 0x87, 0x01, 0x0a, 0x0e, 0x49, 0x6d, 0x70, 0x6c, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x33, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x66, 0x61, 0x2e,
+	0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x52, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x40, 0x0a, 0x09,
+	0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x22, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x09, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x22, 0xa3,
+	0x01, 0x0a, 0x08, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
+	0x30, 0x0a, 0x04, 0x67, 0x72, 0x70, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x72,
+	0x70, 0x63, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x48, 0x00, 0x52, 0x04, 0x67, 0x72, 0x70,
+	0x63, 0x12, 0x30, 0x0a, 0x04, 0x68, 0x74, 0x74, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x48, 0x74, 0x74, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x48, 0x00, 0x52, 0x04, 0x68,
+	0x74, 0x74, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x42, 0x09, 0x0a, 0x07, 0x61, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x22, 0x53, 0x0a, 0x0b, 0x47, 0x72, 0x70, 0x63, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65,
+	0x64, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x63,
+	0x65, 0x64, 0x75, 0x72, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x22, 0x1f, 0x0a, 0x0b, 0x48, 0x74, 0x74,
+	0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x22, 0x53, 0x0a, 0x13, 0x52, 0x65,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x6d, 0x65, 0x6e,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6b,
+	0x69, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22,
+	0x6c, 0x0a, 0x10, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x4f, 0x66, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x22, 0x0a,
+	0x0c, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x79, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x22, 0xf7, 0x01,
+	0x0a, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a, 0x0c, 0x73, 0x74, 0x72, 0x69, 0x6e,
+	0x67, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x0b, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a, 0x0c,
+	0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x01, 0x48, 0x00, 0x52, 0x0b, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x12, 0x1f, 0x0a, 0x0a, 0x62, 0x6f, 0x6f, 0x6c, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x09, 0x62, 0x6f, 0x6f, 0x6c, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x39, 0x0a, 0x0a, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x48, 0x00, 0x52, 0x09, 0x6c, 0x69, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x3f, 0x0a,
+	0x0c, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x48,
+	0x00, 0x52, 0x0b, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x07,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x39, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x73, 0x22, 0x9e, 0x01, 0x0a, 0x0b, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x3e, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x26, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x2e, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x73, 0x1a, 0x4f, 0x0a, 0x0b, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0x8b, 0x02, 0x0a, 0x0d, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x43, 0x6f,
+	0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1b,
+	0x0a, 0x09, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x4f, 0x0a, 0x0b, 0x70, 0x72,
+	0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x2d, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x2e, 0x50, 0x72,
+	0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b,
+	0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x1a, 0x54, 0x0a, 0x10, 0x50,
+	0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0xc9, 0x03, 0x0a, 0x0d, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x4c, 0x0a, 0x0a, 0x70,
+	0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x2c, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x50, 0x61,
+	0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x70,
+	0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x12, 0x36, 0x0a, 0x07, 0x63, 0x6f, 0x6e,
+	0x74, 0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6e, 0x66, 0x61,
+	0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x65, 0x62, 0x75, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x05, 0x64, 0x65, 0x62, 0x75, 0x67, 0x12, 0x4c, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x65,
+	0x6e, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x6e, 0x66,
+	0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e,
+	0x61, 0x6e, 0x63, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x65,
+	0x6e, 0x61, 0x6e, 0x63, 0x65, 0x1a, 0x53, 0x0a, 0x0f, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74,
+	0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6e, 0x66, 0x61, 0x2e,
+	0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x61, 0x0a, 0x0f, 0x50, 0x72,
+	0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x38, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22,
+	0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e,
+	0x63, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xa5, 0x01,
+	0x0a, 0x13, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x76, 0x65,
+	0x6e, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x36, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x53,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f,
+	0x72, 0x69, 0x67, 0x69, 0x6e, 0x12, 0x3e, 0x0a, 0x0a, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64,
+	0x64, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x6e, 0x66, 0x61, 0x2e,
+	0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65,
+	0x74, 0x65, 0x72, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x0a, 0x6f, 0x76, 0x65, 0x72, 0x72,
+	0x69, 0x64, 0x64, 0x65, 0x6e, 0x22, 0xd8, 0x01, 0x0a, 0x0b, 0x46, 0x75, 0x6c, 0x66, 0x69, 0x6c,
+	0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0d, 0x71, 0x75, 0x65, 0x75, 0x65, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x5f, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x71, 0x75, 0x65,
+	0x75, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x4d, 0x73, 0x12, 0x2c, 0x0a, 0x12, 0x70, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x6d, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x69, 0x6e, 0x67,
+	0x54, 0x69, 0x6d, 0x65, 0x4d, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x70, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x68, 0x6f, 0x70, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6f,
+	0x73, 0x74, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09,
+	0x63, 0x6f, 0x73, 0x74, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x66, 0x75, 0x6c,
+	0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x66, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64,
+	0x2a, 0x56, 0x0a, 0x09, 0x52, 0x65, 0x73, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1a, 0x0a,
+	0x16, 0x52, 0x45, 0x53, 0x49, 0x44, 0x45, 0x4e, 0x43, 0x59, 0x5f, 0x55, 0x4e, 0x52, 0x45, 0x53,
+	0x54, 0x52, 0x49, 0x43, 0x54, 0x45, 0x44, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x52, 0x45, 0x53,
+	0x49, 0x44, 0x45, 0x4e, 0x43, 0x59, 0x5f, 0x4f, 0x4e, 0x5f, 0x44, 0x45, 0x56, 0x49, 0x43, 0x45,
+	0x10, 0x01, 0x12, 0x14, 0x0a, 0x10, 0x52, 0x45, 0x53, 0x49, 0x44, 0x45, 0x4e, 0x43, 0x59, 0x5f,
+	0x52, 0x45, 0x47, 0x49, 0x4f, 0x4e, 0x10, 0x02, 0x2a, 0x86, 0x01, 0x0a, 0x0d, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x14, 0x53, 0x54,
+	0x52, 0x45, 0x41, 0x4d, 0x49, 0x4e, 0x47, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x55, 0x4e, 0x41,
+	0x52, 0x59, 0x10, 0x00, 0x12, 0x20, 0x0a, 0x1c, 0x53, 0x54, 0x52, 0x45, 0x41, 0x4d, 0x49, 0x4e,
+	0x47, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x45, 0x52, 0x56, 0x45, 0x52, 0x5f, 0x53, 0x54,
+	0x52, 0x45, 0x41, 0x4d, 0x10, 0x01, 0x12, 0x20, 0x0a, 0x1c, 0x53, 0x54, 0x52, 0x45, 0x41, 0x4d,
+	0x49, 0x4e, 0x47, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x43, 0x4c, 0x49, 0x45, 0x4e, 0x54, 0x5f,
+	0x53, 0x54, 0x52, 0x45, 0x41, 0x4d, 0x10, 0x02, 0x12, 0x17, 0x0a, 0x13, 0x53, 0x54, 0x52, 0x45,
+	0x41, 0x4d, 0x49, 0x4e, 0x47, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x42, 0x49, 0x44, 0x49, 0x10,
+	0x03, 0x2a, 0x5a, 0x0a, 0x0b, 0x53, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79,
+	0x12, 0x1b, 0x0a, 0x17, 0x53, 0x45, 0x4e, 0x53, 0x49, 0x54, 0x49, 0x56, 0x49, 0x54, 0x59, 0x5f,
+	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x13, 0x0a,
+	0x0f, 0x53, 0x45, 0x4e, 0x53, 0x49, 0x54, 0x49, 0x56, 0x49, 0x54, 0x59, 0x5f, 0x50, 0x49, 0x49,
+	0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x53, 0x45, 0x4e, 0x53, 0x49, 0x54, 0x49, 0x56, 0x49, 0x54,
+	0x59, 0x5f, 0x53, 0x45, 0x4e, 0x53, 0x49, 0x54, 0x49, 0x56, 0x45, 0x10, 0x02, 0x2a, 0xc3, 0x01,
+	0x0a, 0x0f, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x53, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x12, 0x20, 0x0a, 0x1c, 0x50, 0x41, 0x52, 0x41, 0x4d, 0x45, 0x54, 0x45, 0x52, 0x5f, 0x53,
+	0x4f, 0x55, 0x52, 0x43, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45,
+	0x44, 0x10, 0x00, 0x12, 0x25, 0x0a, 0x21, 0x50, 0x41, 0x52, 0x41, 0x4d, 0x45, 0x54, 0x45, 0x52,
+	0x5f, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x5f, 0x43, 0x4f, 0x4e, 0x54, 0x52, 0x41, 0x43, 0x54,
+	0x5f, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x01, 0x12, 0x22, 0x0a, 0x1e, 0x50, 0x41,
+	0x52, 0x41, 0x4d, 0x45, 0x54, 0x45, 0x52, 0x5f, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x5f, 0x43,
+	0x4f, 0x4e, 0x54, 0x45, 0x58, 0x54, 0x5f, 0x53, 0x54, 0x4f, 0x52, 0x45, 0x10, 0x02, 0x12, 0x22,
+	0x0a, 0x1e, 0x50, 0x41, 0x52, 0x41, 0x4d, 0x45, 0x54, 0x45, 0x52, 0x5f, 0x53, 0x4f, 0x55, 0x52,
+	0x43, 0x45, 0x5f, 0x50, 0x49, 0x50, 0x45, 0x4c, 0x49, 0x4e, 0x45, 0x5f, 0x53, 0x54, 0x45, 0x50,
+	0x10, 0x03, 0x12, 0x1f, 0x0a, 0x1b, 0x50, 0x41, 0x52, 0x41, 0x4d, 0x45, 0x54, 0x45, 0x52, 0x5f,
+	0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x5f, 0x55, 0x53, 0x45, 0x52, 0x5f, 0x49, 0x4e, 0x50, 0x55,
+	0x54, 0x10, 0x04, 0x42, 0x4b, 0x5a, 0x49, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x6e, 0x65, 0x75, 0x72, 0x6f, 0x2d, 0x66, 0x6c, 0x75, 0x69, 0x64, 0x69, 0x63, 0x2d,
+	0x61, 0x72, 0x63, 0x68, 0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x2f, 0x6e, 0x66, 0x61,
+	0x2d, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x67, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f,
+	0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2f, 0x76, 0x31, 0x3b, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_intent_v1_intent_proto_rawDescOnce sync.Once
+	file_intent_v1_intent_proto_rawDescData = file_intent_v1_intent_proto_rawDesc
+)
+
+func file_intent_v1_intent_proto_rawDescGZIP() []byte {
+	file_intent_v1_intent_proto_rawDescOnce.Do(func() {
+		file_intent_v1_intent_proto_rawDescData = protoimpl.X.CompressGZIP(file_intent_v1_intent_proto_rawDescData)
+	})
+	return file_intent_v1_intent_proto_rawDescData
+}
+
+var file_intent_v1_intent_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_intent_v1_intent_proto_msgTypes = make([]protoimpl.MessageInfo, 41)
+var file_intent_v1_intent_proto_goTypes = []interface{}{
+	(Residency)(0),                    // 0: nfa.intent.v1.Residency
+	(StreamingMode)(0),                // 1: nfa.intent.v1.StreamingMode
+	(Sensitivity)(0),                  // 2: nfa.intent.v1.Sensitivity
+	(ParameterSource)(0),              // 3: nfa.intent.v1.ParameterSource
+	(*IntentPattern)(nil),             // 4: nfa.intent.v1.IntentPattern
+	(*IntentExample)(nil),             // 5: nfa.intent.v1.IntentExample
+	(*ResultSchema)(nil),              // 6: nfa.intent.v1.ResultSchema
+	(*ParameterMapping)(nil),          // 7: nfa.intent.v1.ParameterMapping
+	(*UnitConversion)(nil),            // 8: nfa.intent.v1.UnitConversion
+	(*DataClassification)(nil),        // 9: nfa.intent.v1.DataClassification
+	(*ParameterConstraint)(nil),       // 10: nfa.intent.v1.ParameterConstraint
+	(*StringConstraint)(nil),          // 11: nfa.intent.v1.StringConstraint
+	(*NumberConstraint)(nil),          // 12: nfa.intent.v1.NumberConstraint
+	(*EnumConstraint)(nil),            // 13: nfa.intent.v1.EnumConstraint
+	(*ArrayConstraint)(nil),           // 14: nfa.intent.v1.ArrayConstraint
+	(*ObjectConstraint)(nil),          // 15: nfa.intent.v1.ObjectConstraint
+	(*IntentContract)(nil),            // 16: nfa.intent.v1.IntentContract
+	(*Metadata)(nil),                  // 17: nfa.intent.v1.Metadata
+	(*IntentSpec)(nil),                // 18: nfa.intent.v1.IntentSpec
+	(*Implementation)(nil),            // 19: nfa.intent.v1.Implementation
+	(*Endpoint)(nil),                  // 20: nfa.intent.v1.Endpoint
+	(*GrpcAddress)(nil),               // 21: nfa.intent.v1.GrpcAddress
+	(*HttpAddress)(nil),               // 22: nfa.intent.v1.HttpAddress
+	(*ResourceRequirement)(nil),       // 23: nfa.intent.v1.ResourceRequirement
+	(*QualityOfService)(nil),          // 24: nfa.intent.v1.QualityOfService
+	(*Value)(nil),                     // 25: nfa.intent.v1.Value
+	(*ListValue)(nil),                 // 26: nfa.intent.v1.ListValue
+	(*StructValue)(nil),               // 27: nfa.intent.v1.StructValue
+	(*IntentContext)(nil),             // 28: nfa.intent.v1.IntentContext
+	(*IntentRequest)(nil),             // 29: nfa.intent.v1.IntentRequest
+	(*ParameterProvenance)(nil),       // 30: nfa.intent.v1.ParameterProvenance
+	(*Fulfillment)(nil),               // 31: nfa.intent.v1.Fulfillment
+	(*IntentPattern_Pattern)(nil),     // 32: nfa.intent.v1.IntentPattern.Pattern
+	(*IntentPattern_Constraints)(nil), // 33: nfa.intent.v1.IntentPattern.Constraints
+	nil,                               // 34: nfa.intent.v1.IntentPattern.Pattern.ParametersEntry
+	nil,                               // 35: nfa.intent.v1.IntentPattern.Constraints.ParameterConstraintsEntry
+	nil,                               // 36: nfa.intent.v1.IntentExample.ParametersEntry
+	nil,                               // 37: nfa.intent.v1.IntentExample.ResultEntry
+	nil,                               // 38: nfa.intent.v1.ResultSchema.FieldsEntry
+	nil,                               // 39: nfa.intent.v1.ObjectConstraint.PropertiesEntry
+	nil,                               // 40: nfa.intent.v1.Metadata.LabelsEntry
+	nil,                               // 41: nfa.intent.v1.StructValue.FieldsEntry
+	nil,                               // 42: nfa.intent.v1.IntentContext.PreferencesEntry
+	nil,                               // 43: nfa.intent.v1.IntentRequest.ParametersEntry
+	nil,                               // 44: nfa.intent.v1.IntentRequest.ProvenanceEntry
+}
+var file_intent_v1_intent_proto_depIdxs = []int32{
+	32, // 0: nfa.intent.v1.IntentPattern.pattern:type_name -> nfa.intent.v1.IntentPattern.Pattern
+	33, // 1: nfa.intent.v1.IntentPattern.constraints:type_name -> nfa.intent.v1.IntentPattern.Constraints
+	1,  // 2: nfa.intent.v1.IntentPattern.streaming:type_name -> nfa.intent.v1.StreamingMode
+	9,  // 3: nfa.intent.v1.IntentPattern.classification:type_name -> nfa.intent.v1.DataClassification
+	7,  // 4: nfa.intent.v1.IntentPattern.mappings:type_name -> nfa.intent.v1.ParameterMapping
+	6,  // 5: nfa.intent.v1.IntentPattern.results:type_name -> nfa.intent.v1.ResultSchema
+	5,  // 6: nfa.intent.v1.IntentPattern.examples:type_name -> nfa.intent.v1.IntentExample
+	36, // 7: nfa.intent.v1.IntentExample.parameters:type_name -> nfa.intent.v1.IntentExample.ParametersEntry
+	37, // 8: nfa.intent.v1.IntentExample.result:type_name -> nfa.intent.v1.IntentExample.ResultEntry
+	38, // 9: nfa.intent.v1.ResultSchema.fields:type_name -> nfa.intent.v1.ResultSchema.FieldsEntry
+	8,  // 10: nfa.intent.v1.ParameterMapping.convert:type_name -> nfa.intent.v1.UnitConversion
+	0,  // 11: nfa.intent.v1.DataClassification.residency:type_name -> nfa.intent.v1.Residency
+	11, // 12: nfa.intent.v1.ParameterConstraint.string_constraint:type_name -> nfa.intent.v1.StringConstraint
+	12, // 13: nfa.intent.v1.ParameterConstraint.number_constraint:type_name -> nfa.intent.v1.NumberConstraint
+	13, // 14: nfa.intent.v1.ParameterConstraint.enum_constraint:type_name -> nfa.intent.v1.EnumConstraint
+	14, // 15: nfa.intent.v1.ParameterConstraint.array_constraint:type_name -> nfa.intent.v1.ArrayConstraint
+	15, // 16: nfa.intent.v1.ParameterConstraint.object_constraint:type_name -> nfa.intent.v1.ObjectConstraint
+	2,  // 17: nfa.intent.v1.ParameterConstraint.sensitivity:type_name -> nfa.intent.v1.Sensitivity
+	25, // 18: nfa.intent.v1.ParameterConstraint.default_value:type_name -> nfa.intent.v1.Value
+	10, // 19: nfa.intent.v1.ArrayConstraint.items:type_name -> nfa.intent.v1.ParameterConstraint
+	39, // 20: nfa.intent.v1.ObjectConstraint.properties:type_name -> nfa.intent.v1.ObjectConstraint.PropertiesEntry
+	17, // 21: nfa.intent.v1.IntentContract.metadata:type_name -> nfa.intent.v1.Metadata
+	18, // 22: nfa.intent.v1.IntentContract.spec:type_name -> nfa.intent.v1.IntentSpec
+	40, // 23: nfa.intent.v1.Metadata.labels:type_name -> nfa.intent.v1.Metadata.LabelsEntry
+	4,  // 24: nfa.intent.v1.IntentSpec.intent_patterns:type_name -> nfa.intent.v1.IntentPattern
+	19, // 25: nfa.intent.v1.IntentSpec.implementation:type_name -> nfa.intent.v1.Implementation
+	24, // 26: nfa.intent.v1.IntentSpec.quality_of_service:type_name -> nfa.intent.v1.QualityOfService
+	20, // 27: nfa.intent.v1.Implementation.endpoint:type_name -> nfa.intent.v1.Endpoint
+	23, // 28: nfa.intent.v1.Implementation.resources:type_name -> nfa.intent.v1.ResourceRequirement
+	21, // 29: nfa.intent.v1.Endpoint.grpc:type_name -> nfa.intent.v1.GrpcAddress
+	22, // 30: nfa.intent.v1.Endpoint.http:type_name -> nfa.intent.v1.HttpAddress
+	26, // 31: nfa.intent.v1.Value.list_value:type_name -> nfa.intent.v1.ListValue
+	27, // 32: nfa.intent.v1.Value.struct_value:type_name -> nfa.intent.v1.StructValue
+	25, // 33: nfa.intent.v1.ListValue.values:type_name -> nfa.intent.v1.Value
+	41, // 34: nfa.intent.v1.StructValue.fields:type_name -> nfa.intent.v1.StructValue.FieldsEntry
+	42, // 35: nfa.intent.v1.IntentContext.preferences:type_name -> nfa.intent.v1.IntentContext.PreferencesEntry
+	43, // 36: nfa.intent.v1.IntentRequest.parameters:type_name -> nfa.intent.v1.IntentRequest.ParametersEntry
+	28, // 37: nfa.intent.v1.IntentRequest.context:type_name -> nfa.intent.v1.IntentContext
+	44, // 38: nfa.intent.v1.IntentRequest.provenance:type_name -> nfa.intent.v1.IntentRequest.ProvenanceEntry
+	3,  // 39: nfa.intent.v1.ParameterProvenance.source:type_name -> nfa.intent.v1.ParameterSource
+	3,  // 40: nfa.intent.v1.ParameterProvenance.overridden:type_name -> nfa.intent.v1.ParameterSource
+	34, // 41: nfa.intent.v1.IntentPattern.Pattern.parameters:type_name -> nfa.intent.v1.IntentPattern.Pattern.ParametersEntry
+	35, // 42: nfa.intent.v1.IntentPattern.Constraints.parameter_constraints:type_name -> nfa.intent.v1.IntentPattern.Constraints.ParameterConstraintsEntry
+	25, // 43: nfa.intent.v1.IntentPattern.Pattern.ParametersEntry.value:type_name -> nfa.intent.v1.Value
+	10, // 44: nfa.intent.v1.IntentPattern.Constraints.ParameterConstraintsEntry.value:type_name -> nfa.intent.v1.ParameterConstraint
+	25, // 45: nfa.intent.v1.IntentExample.ParametersEntry.value:type_name -> nfa.intent.v1.Value
+	25, // 46: nfa.intent.v1.IntentExample.ResultEntry.value:type_name -> nfa.intent.v1.Value
+	10, // 47: nfa.intent.v1.ResultSchema.FieldsEntry.value:type_name -> nfa.intent.v1.ParameterConstraint
+	10, // 48: nfa.intent.v1.ObjectConstraint.PropertiesEntry.value:type_name -> nfa.intent.v1.ParameterConstraint
+	25, // 49: nfa.intent.v1.StructValue.FieldsEntry.value:type_name -> nfa.intent.v1.Value
+	25, // 50: nfa.intent.v1.IntentContext.PreferencesEntry.value:type_name -> nfa.intent.v1.Value
+	25, // 51: nfa.intent.v1.IntentRequest.ParametersEntry.value:type_name -> nfa.intent.v1.Value
+	30, // 52: nfa.intent.v1.IntentRequest.ProvenanceEntry.value:type_name -> nfa.intent.v1.ParameterProvenance
+	53, // [53:53] is the sub-list for method output_type
+	53, // [53:53] is the sub-list for method input_type
+	53, // [53:53] is the sub-list for extension type_name
+	53, // [53:53] is the sub-list for extension extendee
+	0,  // [0:53] is the sub-list for field type_name
+}
+
+func init() { file_intent_v1_intent_proto_init() }
+func file_intent_v1_intent_proto_init() {
+	if File_intent_v1_intent_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_intent_v1_intent_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IntentPattern); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IntentExample); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResultSchema); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ParameterMapping); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnitConversion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DataClassification); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ParameterConstraint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StringConstraint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NumberConstraint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnumConstraint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ArrayConstraint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ObjectConstraint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IntentContract); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Metadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IntentSpec); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Implementation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Endpoint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GrpcAddress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HttpAddress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResourceRequirement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QualityOfService); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Value); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListValue); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StructValue); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IntentContext); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IntentRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ParameterProvenance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Fulfillment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IntentPattern_Pattern); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_intent_v1_intent_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IntentPattern_Constraints); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_intent_v1_intent_proto_msgTypes[4].OneofWrappers = []interface{}{}
+	file_intent_v1_intent_proto_msgTypes[6].OneofWrappers = []interface{}{
+		(*ParameterConstraint_StringConstraint)(nil),
+		(*ParameterConstraint_NumberConstraint)(nil),
+		(*ParameterConstraint_EnumConstraint)(nil),
+		(*ParameterConstraint_ArrayConstraint)(nil),
+		(*ParameterConstraint_ObjectConstraint)(nil),
+	}
+	file_intent_v1_intent_proto_msgTypes[7].OneofWrappers = []interface{}{}
+	file_intent_v1_intent_proto_msgTypes[8].OneofWrappers = []interface{}{}
+	file_intent_v1_intent_proto_msgTypes[10].OneofWrappers = []interface{}{}
+	file_intent_v1_intent_proto_msgTypes[16].OneofWrappers = []interface{}{
+		(*Endpoint_Grpc)(nil),
+		(*Endpoint_Http)(nil),
+	}
+	file_intent_v1_intent_proto_msgTypes[21].OneofWrappers = []interface{}{
+		(*Value_StringValue)(nil),
+		(*Value_NumberValue)(nil),
+		(*Value_BoolValue)(nil),
+		(*Value_ListValue)(nil),
+		(*Value_StructValue)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_intent_v1_intent_proto_rawDesc,
+			NumEnums:      4,
+			NumMessages:   41,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_intent_v1_intent_proto_goTypes,
+		DependencyIndexes: file_intent_v1_intent_proto_depIdxs,
+		EnumInfos:         file_intent_v1_intent_proto_enumTypes,
+		MessageInfos:      file_intent_v1_intent_proto_msgTypes,
+	}.Build()
+	File_intent_v1_intent_proto = out.File
+	file_intent_v1_intent_proto_rawDesc = nil
+	file_intent_v1_intent_proto_goTypes = nil
+	file_intent_v1_intent_proto_depIdxs = nil
+}
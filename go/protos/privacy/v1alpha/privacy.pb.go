@@ -0,0 +1,807 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v3.21.12
+// source: privacy/v1alpha/privacy.proto
+
+package privacy
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DataCategory int32
+
+const (
+	DataCategory_DATA_CATEGORY_UNSPECIFIED DataCategory = 0
+	DataCategory_DATA_CATEGORY_SESSIONS    DataCategory = 1
+	DataCategory_DATA_CATEGORY_EVENTS      DataCategory = 2
+	DataCategory_DATA_CATEGORY_MEMORIES    DataCategory = 3
+	DataCategory_DATA_CATEGORY_PREFERENCES DataCategory = 4
+)
+
+// Enum value maps for DataCategory.
+var (
+	DataCategory_name = map[int32]string{
+		0: "DATA_CATEGORY_UNSPECIFIED",
+		1: "DATA_CATEGORY_SESSIONS",
+		2: "DATA_CATEGORY_EVENTS",
+		3: "DATA_CATEGORY_MEMORIES",
+		4: "DATA_CATEGORY_PREFERENCES",
+	}
+	DataCategory_value = map[string]int32{
+		"DATA_CATEGORY_UNSPECIFIED": 0,
+		"DATA_CATEGORY_SESSIONS":    1,
+		"DATA_CATEGORY_EVENTS":      2,
+		"DATA_CATEGORY_MEMORIES":    3,
+		"DATA_CATEGORY_PREFERENCES": 4,
+	}
+)
+
+func (x DataCategory) Enum() *DataCategory {
+	p := new(DataCategory)
+	*p = x
+	return p
+}
+
+func (x DataCategory) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DataCategory) Descriptor() protoreflect.EnumDescriptor {
+	return file_privacy_v1alpha_privacy_proto_enumTypes[0].Descriptor()
+}
+
+func (DataCategory) Type() protoreflect.EnumType {
+	return &file_privacy_v1alpha_privacy_proto_enumTypes[0]
+}
+
+func (x DataCategory) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DataCategory.Descriptor instead.
+func (DataCategory) EnumDescriptor() ([]byte, []int) {
+	return file_privacy_v1alpha_privacy_proto_rawDescGZIP(), []int{0}
+}
+
+type SubjectRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// User identity, as in IntentContext.user_id
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+}
+
+func (x *SubjectRequest) Reset() {
+	*x = SubjectRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_privacy_v1alpha_privacy_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubjectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubjectRequest) ProtoMessage() {}
+
+func (x *SubjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_privacy_v1alpha_privacy_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubjectRequest.ProtoReflect.Descriptor instead.
+func (*SubjectRequest) Descriptor() ([]byte, []int) {
+	return file_privacy_v1alpha_privacy_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SubjectRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type SubjectRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Store holding the record
+	Store    string       `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	Category DataCategory `protobuf:"varint,2,opt,name=category,proto3,enum=nfa.privacy.v1alpha.DataCategory" json:"category,omitempty"`
+	// Identifies the record within its store
+	Id         string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// Record content; only set in exports
+	Data        []byte `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	ContentType string `protobuf:"bytes,6,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+}
+
+func (x *SubjectRecord) Reset() {
+	*x = SubjectRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_privacy_v1alpha_privacy_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubjectRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubjectRecord) ProtoMessage() {}
+
+func (x *SubjectRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_privacy_v1alpha_privacy_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubjectRecord.ProtoReflect.Descriptor instead.
+func (*SubjectRecord) Descriptor() ([]byte, []int) {
+	return file_privacy_v1alpha_privacy_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SubjectRecord) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *SubjectRecord) GetCategory() DataCategory {
+	if x != nil {
+		return x.Category
+	}
+	return DataCategory_DATA_CATEGORY_UNSPECIFIED
+}
+
+func (x *SubjectRecord) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SubjectRecord) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *SubjectRecord) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *SubjectRecord) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+type ListSubjectDataResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Records []*SubjectRecord `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+}
+
+func (x *ListSubjectDataResponse) Reset() {
+	*x = ListSubjectDataResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_privacy_v1alpha_privacy_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListSubjectDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubjectDataResponse) ProtoMessage() {}
+
+func (x *ListSubjectDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_privacy_v1alpha_privacy_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubjectDataResponse.ProtoReflect.Descriptor instead.
+func (*ListSubjectDataResponse) Descriptor() ([]byte, []int) {
+	return file_privacy_v1alpha_privacy_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListSubjectDataResponse) GetRecords() []*SubjectRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+type SubjectExport struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserId     string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Records    []*SubjectRecord       `protobuf:"bytes,2,rep,name=records,proto3" json:"records,omitempty"`
+	ExportTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=export_time,json=exportTime,proto3" json:"export_time,omitempty"`
+}
+
+func (x *SubjectExport) Reset() {
+	*x = SubjectExport{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_privacy_v1alpha_privacy_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubjectExport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubjectExport) ProtoMessage() {}
+
+func (x *SubjectExport) ProtoReflect() protoreflect.Message {
+	mi := &file_privacy_v1alpha_privacy_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubjectExport.ProtoReflect.Descriptor instead.
+func (*SubjectExport) Descriptor() ([]byte, []int) {
+	return file_privacy_v1alpha_privacy_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SubjectExport) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SubjectExport) GetRecords() []*SubjectRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *SubjectExport) GetExportTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExportTime
+	}
+	return nil
+}
+
+type StorePurgeResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Store  string `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	Purged uint32 `protobuf:"varint,2,opt,name=purged,proto3" json:"purged,omitempty"`
+	// Set when the store failed; the purge is then incomplete and can be retried
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *StorePurgeResult) Reset() {
+	*x = StorePurgeResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_privacy_v1alpha_privacy_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StorePurgeResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StorePurgeResult) ProtoMessage() {}
+
+func (x *StorePurgeResult) ProtoReflect() protoreflect.Message {
+	mi := &file_privacy_v1alpha_privacy_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StorePurgeResult.ProtoReflect.Descriptor instead.
+func (*StorePurgeResult) Descriptor() ([]byte, []int) {
+	return file_privacy_v1alpha_privacy_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StorePurgeResult) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *StorePurgeResult) GetPurged() uint32 {
+	if x != nil {
+		return x.Purged
+	}
+	return 0
+}
+
+func (x *StorePurgeResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type PurgeReceipt struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId       string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RequestTime  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=request_time,json=requestTime,proto3" json:"request_time,omitempty"`
+	CompleteTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=complete_time,json=completeTime,proto3" json:"complete_time,omitempty"`
+	Stores       []*StorePurgeResult    `protobuf:"bytes,5,rep,name=stores,proto3" json:"stores,omitempty"`
+	// True when every store erased the user's records
+	Complete bool `protobuf:"varint,6,opt,name=complete,proto3" json:"complete,omitempty"`
+	// Hex SHA-256 over the sorted "store/id" of every erased record, proving
+	// what was erased without retaining it
+	Digest string `protobuf:"bytes,7,opt,name=digest,proto3" json:"digest,omitempty"`
+}
+
+func (x *PurgeReceipt) Reset() {
+	*x = PurgeReceipt{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_privacy_v1alpha_privacy_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PurgeReceipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeReceipt) ProtoMessage() {}
+
+func (x *PurgeReceipt) ProtoReflect() protoreflect.Message {
+	mi := &file_privacy_v1alpha_privacy_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeReceipt.ProtoReflect.Descriptor instead.
+func (*PurgeReceipt) Descriptor() ([]byte, []int) {
+	return file_privacy_v1alpha_privacy_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PurgeReceipt) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PurgeReceipt) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PurgeReceipt) GetRequestTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RequestTime
+	}
+	return nil
+}
+
+func (x *PurgeReceipt) GetCompleteTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompleteTime
+	}
+	return nil
+}
+
+func (x *PurgeReceipt) GetStores() []*StorePurgeResult {
+	if x != nil {
+		return x.Stores
+	}
+	return nil
+}
+
+func (x *PurgeReceipt) GetComplete() bool {
+	if x != nil {
+		return x.Complete
+	}
+	return false
+}
+
+func (x *PurgeReceipt) GetDigest() string {
+	if x != nil {
+		return x.Digest
+	}
+	return ""
+}
+
+type GetPurgeReceiptRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetPurgeReceiptRequest) Reset() {
+	*x = GetPurgeReceiptRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_privacy_v1alpha_privacy_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPurgeReceiptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPurgeReceiptRequest) ProtoMessage() {}
+
+func (x *GetPurgeReceiptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_privacy_v1alpha_privacy_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPurgeReceiptRequest.ProtoReflect.Descriptor instead.
+func (*GetPurgeReceiptRequest) Descriptor() ([]byte, []int) {
+	return file_privacy_v1alpha_privacy_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetPurgeReceiptRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+var File_privacy_v1alpha_privacy_proto protoreflect.FileDescriptor
+
+var file_privacy_v1alpha_privacy_proto_rawDesc = []byte{
+	0x0a, 0x1d, 0x70, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x2f, 0x70, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x13, 0x6e, 0x66, 0x61, 0x2e, 0x70, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x29, 0x0a, 0x0e, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64,
+	0x22, 0xe8, 0x01, 0x0a, 0x0d, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x3d, 0x0a, 0x08, 0x63, 0x61, 0x74, 0x65,
+	0x67, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21, 0x2e, 0x6e, 0x66, 0x61,
+	0x2e, 0x70, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x2e, 0x44, 0x61, 0x74, 0x61, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x52, 0x08, 0x63,
+	0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x3b, 0x0a, 0x0b, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x22, 0x57, 0x0a, 0x17, 0x4c,
+	0x69, 0x73, 0x74, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x70, 0x72,
+	0x69, 0x76, 0x61, 0x63, 0x79, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x53, 0x75,
+	0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x07, 0x72, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x73, 0x22, 0xa3, 0x01, 0x0a, 0x0d, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12,
+	0x3c, 0x0a, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x22, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x70, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x2e, 0x76,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x52, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x3b, 0x0a,
+	0x0b, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a,
+	0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x56, 0x0a, 0x10, 0x53, 0x74,
+	0x6f, 0x72, 0x65, 0x50, 0x75, 0x72, 0x67, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x75, 0x72, 0x67, 0x65, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x70, 0x75, 0x72, 0x67, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x22, 0xaa, 0x02, 0x0a, 0x0c, 0x50, 0x75, 0x72, 0x67, 0x65, 0x52, 0x65, 0x63, 0x65,
+	0x69, 0x70, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x69, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x3d, 0x0a, 0x0c,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x3f, 0x0a, 0x0d, 0x63,
+	0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0c,
+	0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x3d, 0x0a, 0x06,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x6e,
+	0x66, 0x61, 0x2e, 0x70, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x50, 0x75, 0x72, 0x67, 0x65, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x52, 0x06, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63,
+	0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x63,
+	0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22,
+	0x28, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x50, 0x75, 0x72, 0x67, 0x65, 0x52, 0x65, 0x63, 0x65, 0x69,
+	0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x2a, 0x9e, 0x01, 0x0a, 0x0c, 0x44, 0x61,
+	0x74, 0x61, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x1d, 0x0a, 0x19, 0x44, 0x41,
+	0x54, 0x41, 0x5f, 0x43, 0x41, 0x54, 0x45, 0x47, 0x4f, 0x52, 0x59, 0x5f, 0x55, 0x4e, 0x53, 0x50,
+	0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1a, 0x0a, 0x16, 0x44, 0x41, 0x54,
+	0x41, 0x5f, 0x43, 0x41, 0x54, 0x45, 0x47, 0x4f, 0x52, 0x59, 0x5f, 0x53, 0x45, 0x53, 0x53, 0x49,
+	0x4f, 0x4e, 0x53, 0x10, 0x01, 0x12, 0x18, 0x0a, 0x14, 0x44, 0x41, 0x54, 0x41, 0x5f, 0x43, 0x41,
+	0x54, 0x45, 0x47, 0x4f, 0x52, 0x59, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x53, 0x10, 0x02, 0x12,
+	0x1a, 0x0a, 0x16, 0x44, 0x41, 0x54, 0x41, 0x5f, 0x43, 0x41, 0x54, 0x45, 0x47, 0x4f, 0x52, 0x59,
+	0x5f, 0x4d, 0x45, 0x4d, 0x4f, 0x52, 0x49, 0x45, 0x53, 0x10, 0x03, 0x12, 0x1d, 0x0a, 0x19, 0x44,
+	0x41, 0x54, 0x41, 0x5f, 0x43, 0x41, 0x54, 0x45, 0x47, 0x4f, 0x52, 0x59, 0x5f, 0x50, 0x52, 0x45,
+	0x46, 0x45, 0x52, 0x45, 0x4e, 0x43, 0x45, 0x53, 0x10, 0x04, 0x32, 0x97, 0x03, 0x0a, 0x12, 0x44,
+	0x61, 0x74, 0x61, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x64, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x44, 0x61, 0x74, 0x61, 0x12, 0x23, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x70, 0x72, 0x69, 0x76, 0x61,
+	0x63, 0x79, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x53, 0x75, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x6e, 0x66, 0x61, 0x2e,
+	0x70, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5c, 0x0a, 0x11, 0x45, 0x78, 0x70, 0x6f, 0x72,
+	0x74, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x23, 0x2e, 0x6e,
+	0x66, 0x61, 0x2e, 0x70, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x2e, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x22, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x70, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x2e,
+	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x45,
+	0x78, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x5a, 0x0a, 0x10, 0x50, 0x75, 0x72, 0x67, 0x65, 0x53, 0x75,
+	0x62, 0x6a, 0x65, 0x63, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x23, 0x2e, 0x6e, 0x66, 0x61, 0x2e,
+	0x70, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e,
+	0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21,
+	0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x70, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x2e, 0x50, 0x75, 0x72, 0x67, 0x65, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70,
+	0x74, 0x12, 0x61, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x50, 0x75, 0x72, 0x67, 0x65, 0x52, 0x65, 0x63,
+	0x65, 0x69, 0x70, 0x74, 0x12, 0x2b, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x70, 0x72, 0x69, 0x76, 0x61,
+	0x63, 0x79, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x75,
+	0x72, 0x67, 0x65, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x21, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x70, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x2e,
+	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x50, 0x75, 0x72, 0x67, 0x65, 0x52, 0x65, 0x63,
+	0x65, 0x69, 0x70, 0x74, 0x42, 0x52, 0x5a, 0x50, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x6e, 0x65, 0x75, 0x72, 0x6f, 0x2d, 0x66, 0x6c, 0x75, 0x69, 0x64, 0x69, 0x63,
+	0x2d, 0x61, 0x72, 0x63, 0x68, 0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x2f, 0x6e, 0x66,
+	0x61, 0x2d, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x67, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73,
+	0x2f, 0x70, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x3b, 0x70, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_privacy_v1alpha_privacy_proto_rawDescOnce sync.Once
+	file_privacy_v1alpha_privacy_proto_rawDescData = file_privacy_v1alpha_privacy_proto_rawDesc
+)
+
+func file_privacy_v1alpha_privacy_proto_rawDescGZIP() []byte {
+	file_privacy_v1alpha_privacy_proto_rawDescOnce.Do(func() {
+		file_privacy_v1alpha_privacy_proto_rawDescData = protoimpl.X.CompressGZIP(file_privacy_v1alpha_privacy_proto_rawDescData)
+	})
+	return file_privacy_v1alpha_privacy_proto_rawDescData
+}
+
+var file_privacy_v1alpha_privacy_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_privacy_v1alpha_privacy_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_privacy_v1alpha_privacy_proto_goTypes = []interface{}{
+	(DataCategory)(0),               // 0: nfa.privacy.v1alpha.DataCategory
+	(*SubjectRequest)(nil),          // 1: nfa.privacy.v1alpha.SubjectRequest
+	(*SubjectRecord)(nil),           // 2: nfa.privacy.v1alpha.SubjectRecord
+	(*ListSubjectDataResponse)(nil), // 3: nfa.privacy.v1alpha.ListSubjectDataResponse
+	(*SubjectExport)(nil),           // 4: nfa.privacy.v1alpha.SubjectExport
+	(*StorePurgeResult)(nil),        // 5: nfa.privacy.v1alpha.StorePurgeResult
+	(*PurgeReceipt)(nil),            // 6: nfa.privacy.v1alpha.PurgeReceipt
+	(*GetPurgeReceiptRequest)(nil),  // 7: nfa.privacy.v1alpha.GetPurgeReceiptRequest
+	(*timestamppb.Timestamp)(nil),   // 8: google.protobuf.Timestamp
+}
+var file_privacy_v1alpha_privacy_proto_depIdxs = []int32{
+	0,  // 0: nfa.privacy.v1alpha.SubjectRecord.category:type_name -> nfa.privacy.v1alpha.DataCategory
+	8,  // 1: nfa.privacy.v1alpha.SubjectRecord.create_time:type_name -> google.protobuf.Timestamp
+	2,  // 2: nfa.privacy.v1alpha.ListSubjectDataResponse.records:type_name -> nfa.privacy.v1alpha.SubjectRecord
+	2,  // 3: nfa.privacy.v1alpha.SubjectExport.records:type_name -> nfa.privacy.v1alpha.SubjectRecord
+	8,  // 4: nfa.privacy.v1alpha.SubjectExport.export_time:type_name -> google.protobuf.Timestamp
+	8,  // 5: nfa.privacy.v1alpha.PurgeReceipt.request_time:type_name -> google.protobuf.Timestamp
+	8,  // 6: nfa.privacy.v1alpha.PurgeReceipt.complete_time:type_name -> google.protobuf.Timestamp
+	5,  // 7: nfa.privacy.v1alpha.PurgeReceipt.stores:type_name -> nfa.privacy.v1alpha.StorePurgeResult
+	1,  // 8: nfa.privacy.v1alpha.DataSubjectService.ListSubjectData:input_type -> nfa.privacy.v1alpha.SubjectRequest
+	1,  // 9: nfa.privacy.v1alpha.DataSubjectService.ExportSubjectData:input_type -> nfa.privacy.v1alpha.SubjectRequest
+	1,  // 10: nfa.privacy.v1alpha.DataSubjectService.PurgeSubjectData:input_type -> nfa.privacy.v1alpha.SubjectRequest
+	7,  // 11: nfa.privacy.v1alpha.DataSubjectService.GetPurgeReceipt:input_type -> nfa.privacy.v1alpha.GetPurgeReceiptRequest
+	3,  // 12: nfa.privacy.v1alpha.DataSubjectService.ListSubjectData:output_type -> nfa.privacy.v1alpha.ListSubjectDataResponse
+	4,  // 13: nfa.privacy.v1alpha.DataSubjectService.ExportSubjectData:output_type -> nfa.privacy.v1alpha.SubjectExport
+	6,  // 14: nfa.privacy.v1alpha.DataSubjectService.PurgeSubjectData:output_type -> nfa.privacy.v1alpha.PurgeReceipt
+	6,  // 15: nfa.privacy.v1alpha.DataSubjectService.GetPurgeReceipt:output_type -> nfa.privacy.v1alpha.PurgeReceipt
+	12, // [12:16] is the sub-list for method output_type
+	8,  // [8:12] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_privacy_v1alpha_privacy_proto_init() }
+func file_privacy_v1alpha_privacy_proto_init() {
+	if File_privacy_v1alpha_privacy_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_privacy_v1alpha_privacy_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubjectRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_privacy_v1alpha_privacy_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubjectRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_privacy_v1alpha_privacy_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListSubjectDataResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_privacy_v1alpha_privacy_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubjectExport); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_privacy_v1alpha_privacy_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StorePurgeResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_privacy_v1alpha_privacy_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PurgeReceipt); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_privacy_v1alpha_privacy_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPurgeReceiptRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_privacy_v1alpha_privacy_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_privacy_v1alpha_privacy_proto_goTypes,
+		DependencyIndexes: file_privacy_v1alpha_privacy_proto_depIdxs,
+		EnumInfos:         file_privacy_v1alpha_privacy_proto_enumTypes,
+		MessageInfos:      file_privacy_v1alpha_privacy_proto_msgTypes,
+	}.Build()
+	File_privacy_v1alpha_privacy_proto = out.File
+	file_privacy_v1alpha_privacy_proto_rawDesc = nil
+	file_privacy_v1alpha_privacy_proto_goTypes = nil
+	file_privacy_v1alpha_privacy_proto_depIdxs = nil
+}
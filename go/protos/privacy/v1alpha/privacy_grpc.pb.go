@@ -0,0 +1,230 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: privacy/v1alpha/privacy.proto
+
+package privacy
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	DataSubjectService_ListSubjectData_FullMethodName   = "/nfa.privacy.v1alpha.DataSubjectService/ListSubjectData"
+	DataSubjectService_ExportSubjectData_FullMethodName = "/nfa.privacy.v1alpha.DataSubjectService/ExportSubjectData"
+	DataSubjectService_PurgeSubjectData_FullMethodName  = "/nfa.privacy.v1alpha.DataSubjectService/PurgeSubjectData"
+	DataSubjectService_GetPurgeReceipt_FullMethodName   = "/nfa.privacy.v1alpha.DataSubjectService/GetPurgeReceipt"
+)
+
+// DataSubjectServiceClient is the client API for DataSubjectService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DataSubjectServiceClient interface {
+	// Enumerate the records held about a user, without their data
+	ListSubjectData(ctx context.Context, in *SubjectRequest, opts ...grpc.CallOption) (*ListSubjectDataResponse, error)
+	// Export the records held about a user, including their data
+	ExportSubjectData(ctx context.Context, in *SubjectRequest, opts ...grpc.CallOption) (*SubjectExport, error)
+	// Erase the records held about a user from every store and return a
+	// receipt of what was erased
+	PurgeSubjectData(ctx context.Context, in *SubjectRequest, opts ...grpc.CallOption) (*PurgeReceipt, error)
+	// Look up the receipt of an earlier purge
+	GetPurgeReceipt(ctx context.Context, in *GetPurgeReceiptRequest, opts ...grpc.CallOption) (*PurgeReceipt, error)
+}
+
+type dataSubjectServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDataSubjectServiceClient(cc grpc.ClientConnInterface) DataSubjectServiceClient {
+	return &dataSubjectServiceClient{cc}
+}
+
+func (c *dataSubjectServiceClient) ListSubjectData(ctx context.Context, in *SubjectRequest, opts ...grpc.CallOption) (*ListSubjectDataResponse, error) {
+	out := new(ListSubjectDataResponse)
+	err := c.cc.Invoke(ctx, DataSubjectService_ListSubjectData_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataSubjectServiceClient) ExportSubjectData(ctx context.Context, in *SubjectRequest, opts ...grpc.CallOption) (*SubjectExport, error) {
+	out := new(SubjectExport)
+	err := c.cc.Invoke(ctx, DataSubjectService_ExportSubjectData_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataSubjectServiceClient) PurgeSubjectData(ctx context.Context, in *SubjectRequest, opts ...grpc.CallOption) (*PurgeReceipt, error) {
+	out := new(PurgeReceipt)
+	err := c.cc.Invoke(ctx, DataSubjectService_PurgeSubjectData_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataSubjectServiceClient) GetPurgeReceipt(ctx context.Context, in *GetPurgeReceiptRequest, opts ...grpc.CallOption) (*PurgeReceipt, error) {
+	out := new(PurgeReceipt)
+	err := c.cc.Invoke(ctx, DataSubjectService_GetPurgeReceipt_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DataSubjectServiceServer is the server API for DataSubjectService service.
+// All implementations must embed UnimplementedDataSubjectServiceServer
+// for forward compatibility
+type DataSubjectServiceServer interface {
+	// Enumerate the records held about a user, without their data
+	ListSubjectData(context.Context, *SubjectRequest) (*ListSubjectDataResponse, error)
+	// Export the records held about a user, including their data
+	ExportSubjectData(context.Context, *SubjectRequest) (*SubjectExport, error)
+	// Erase the records held about a user from every store and return a
+	// receipt of what was erased
+	PurgeSubjectData(context.Context, *SubjectRequest) (*PurgeReceipt, error)
+	// Look up the receipt of an earlier purge
+	GetPurgeReceipt(context.Context, *GetPurgeReceiptRequest) (*PurgeReceipt, error)
+	mustEmbedUnimplementedDataSubjectServiceServer()
+}
+
+// UnimplementedDataSubjectServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedDataSubjectServiceServer struct {
+}
+
+func (UnimplementedDataSubjectServiceServer) ListSubjectData(context.Context, *SubjectRequest) (*ListSubjectDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSubjectData not implemented")
+}
+func (UnimplementedDataSubjectServiceServer) ExportSubjectData(context.Context, *SubjectRequest) (*SubjectExport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportSubjectData not implemented")
+}
+func (UnimplementedDataSubjectServiceServer) PurgeSubjectData(context.Context, *SubjectRequest) (*PurgeReceipt, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeSubjectData not implemented")
+}
+func (UnimplementedDataSubjectServiceServer) GetPurgeReceipt(context.Context, *GetPurgeReceiptRequest) (*PurgeReceipt, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPurgeReceipt not implemented")
+}
+func (UnimplementedDataSubjectServiceServer) mustEmbedUnimplementedDataSubjectServiceServer() {}
+
+// UnsafeDataSubjectServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DataSubjectServiceServer will
+// result in compilation errors.
+type UnsafeDataSubjectServiceServer interface {
+	mustEmbedUnimplementedDataSubjectServiceServer()
+}
+
+func RegisterDataSubjectServiceServer(s grpc.ServiceRegistrar, srv DataSubjectServiceServer) {
+	s.RegisterService(&DataSubjectService_ServiceDesc, srv)
+}
+
+func _DataSubjectService_ListSubjectData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataSubjectServiceServer).ListSubjectData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataSubjectService_ListSubjectData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataSubjectServiceServer).ListSubjectData(ctx, req.(*SubjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataSubjectService_ExportSubjectData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataSubjectServiceServer).ExportSubjectData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataSubjectService_ExportSubjectData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataSubjectServiceServer).ExportSubjectData(ctx, req.(*SubjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataSubjectService_PurgeSubjectData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataSubjectServiceServer).PurgeSubjectData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataSubjectService_PurgeSubjectData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataSubjectServiceServer).PurgeSubjectData(ctx, req.(*SubjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataSubjectService_GetPurgeReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPurgeReceiptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataSubjectServiceServer).GetPurgeReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataSubjectService_GetPurgeReceipt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataSubjectServiceServer).GetPurgeReceipt(ctx, req.(*GetPurgeReceiptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DataSubjectService_ServiceDesc is the grpc.ServiceDesc for DataSubjectService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DataSubjectService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "nfa.privacy.v1alpha.DataSubjectService",
+	HandlerType: (*DataSubjectServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSubjectData",
+			Handler:    _DataSubjectService_ListSubjectData_Handler,
+		},
+		{
+			MethodName: "ExportSubjectData",
+			Handler:    _DataSubjectService_ExportSubjectData_Handler,
+		},
+		{
+			MethodName: "PurgeSubjectData",
+			Handler:    _DataSubjectService_PurgeSubjectData_Handler,
+		},
+		{
+			MethodName: "GetPurgeReceipt",
+			Handler:    _DataSubjectService_GetPurgeReceipt_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "privacy/v1alpha/privacy.proto",
+}
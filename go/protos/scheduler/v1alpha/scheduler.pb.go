@@ -1,13 +1,13 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.28.1
+// 	protoc-gen-go v1.31.0
 // 	protoc        v3.21.12
-// source: protocols/scheduler/v1alpha/scheduler.proto
+// source: scheduler/v1alpha/scheduler.proto
 
 package scheduler
 
 import (
-	intent "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -21,19 +21,74 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type SchedulingPolicy int32
+
+const (
+	SchedulingPolicy_SCHEDULING_POLICY_UNSPECIFIED       SchedulingPolicy = 0
+	SchedulingPolicy_SCHEDULING_POLICY_PERFORMANCE_FIRST SchedulingPolicy = 1
+	SchedulingPolicy_SCHEDULING_POLICY_ENERGY_EFFICIENT  SchedulingPolicy = 2
+	SchedulingPolicy_SCHEDULING_POLICY_LATENCY_SENSITIVE SchedulingPolicy = 3
+	SchedulingPolicy_SCHEDULING_POLICY_COST_OPTIMIZED    SchedulingPolicy = 4
+)
+
+// Enum value maps for SchedulingPolicy.
+var (
+	SchedulingPolicy_name = map[int32]string{
+		0: "SCHEDULING_POLICY_UNSPECIFIED",
+		1: "SCHEDULING_POLICY_PERFORMANCE_FIRST",
+		2: "SCHEDULING_POLICY_ENERGY_EFFICIENT",
+		3: "SCHEDULING_POLICY_LATENCY_SENSITIVE",
+		4: "SCHEDULING_POLICY_COST_OPTIMIZED",
+	}
+	SchedulingPolicy_value = map[string]int32{
+		"SCHEDULING_POLICY_UNSPECIFIED":       0,
+		"SCHEDULING_POLICY_PERFORMANCE_FIRST": 1,
+		"SCHEDULING_POLICY_ENERGY_EFFICIENT":  2,
+		"SCHEDULING_POLICY_LATENCY_SENSITIVE": 3,
+		"SCHEDULING_POLICY_COST_OPTIMIZED":    4,
+	}
+)
+
+func (x SchedulingPolicy) Enum() *SchedulingPolicy {
+	p := new(SchedulingPolicy)
+	*p = x
+	return p
+}
+
+func (x SchedulingPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SchedulingPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_scheduler_v1alpha_scheduler_proto_enumTypes[0].Descriptor()
+}
+
+func (SchedulingPolicy) Type() protoreflect.EnumType {
+	return &file_scheduler_v1alpha_scheduler_proto_enumTypes[0]
+}
+
+func (x SchedulingPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SchedulingPolicy.Descriptor instead.
+func (SchedulingPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_scheduler_v1alpha_scheduler_proto_rawDescGZIP(), []int{0}
+}
+
 type ScheduleRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	IntentRequest   *intent.IntentRequest `protobuf:"bytes,1,opt,name=intent_request,json=intentRequest,proto3" json:"intent_request,omitempty"`
-	ResourceRequest *ResourceRequest      `protobuf:"bytes,2,opt,name=resource_request,json=resourceRequest,proto3" json:"resource_request,omitempty"`
+	IntentRequest   *v1alpha.IntentRequest `protobuf:"bytes,1,opt,name=intent_request,json=intentRequest,proto3" json:"intent_request,omitempty"`
+	ResourceRequest *ResourceRequest       `protobuf:"bytes,2,opt,name=resource_request,json=resourceRequest,proto3" json:"resource_request,omitempty"`
 }
 
 func (x *ScheduleRequest) Reset() {
 	*x = ScheduleRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protocols_scheduler_v1alpha_scheduler_proto_msgTypes[0]
+		mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[0]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -46,7 +101,7 @@ func (x *ScheduleRequest) String() string {
 func (*ScheduleRequest) ProtoMessage() {}
 
 func (x *ScheduleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protocols_scheduler_v1alpha_scheduler_proto_msgTypes[0]
+	mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[0]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -57,55 +112,1147 @@ func (x *ScheduleRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// 省略其他生成的代码...
+// Deprecated: Use ScheduleRequest.ProtoReflect.Descriptor instead.
+func (*ScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1alpha_scheduler_proto_rawDescGZIP(), []int{0}
+}
 
-// 调度策略枚举
-type SchedulingPolicy int32
+func (x *ScheduleRequest) GetIntentRequest() *v1alpha.IntentRequest {
+	if x != nil {
+		return x.IntentRequest
+	}
+	return nil
+}
 
-const (
-	SchedulingPolicy_SCHEDULING_POLICY_UNSPECIFIED     SchedulingPolicy = 0
-	SchedulingPolicy_SCHEDULING_POLICY_PERFORMANCE_FIRST SchedulingPolicy = 1
-	SchedulingPolicy_SCHEDULING_POLICY_ENERGY_EFFICIENT SchedulingPolicy = 2
-	SchedulingPolicy_SCHEDULING_POLICY_LATENCY_SENSITIVE SchedulingPolicy = 3
-	SchedulingPolicy_SCHEDULING_POLICY_COST_OPTIMIZED   SchedulingPolicy = 4
-)
+func (x *ScheduleRequest) GetResourceRequest() *ResourceRequest {
+	if x != nil {
+		return x.ResourceRequest
+	}
+	return nil
+}
 
-// Enum value maps for SchedulingPolicy.
-var (
-	SchedulingPolicy_name = map[int32]string{
-		0: "SCHEDULING_POLICY_UNSPECIFIED",
-		1: "SCHEDULING_POLICY_PERFORMANCE_FIRST",
-		2: "SCHEDULING_POLICY_ENERGY_EFFICIENT",
-		3: "SCHEDULING_POLICY_LATENCY_SENSITIVE",
-		4: "SCHEDULING_POLICY_COST_OPTIMIZED",
+type ScheduleResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Allocation   *ResourceAllocation `protobuf:"bytes,1,opt,name=allocation,proto3" json:"allocation,omitempty"`
+	ErrorMessage string              `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+}
+
+func (x *ScheduleResponse) Reset() {
+	*x = ScheduleResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	SchedulingPolicy_value = map[string]int32{
-		"SCHEDULING_POLICY_UNSPECIFIED":     0,
-		"SCHEDULING_POLICY_PERFORMANCE_FIRST": 1,
-		"SCHEDULING_POLICY_ENERGY_EFFICIENT": 2,
-		"SCHEDULING_POLICY_LATENCY_SENSITIVE": 3,
-		"SCHEDULING_POLICY_COST_OPTIMIZED":   4,
+}
+
+func (x *ScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleResponse) ProtoMessage() {}
+
+func (x *ScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-)
+	return mi.MessageOf(x)
+}
 
-func (x SchedulingPolicy) Enum() *SchedulingPolicy {
-	p := new(SchedulingPolicy)
-	*p = x
-	return p
+// Deprecated: Use ScheduleResponse.ProtoReflect.Descriptor instead.
+func (*ScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1alpha_scheduler_proto_rawDescGZIP(), []int{1}
 }
 
-func (x SchedulingPolicy) String() string {
-	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+func (x *ScheduleResponse) GetAllocation() *ResourceAllocation {
+	if x != nil {
+		return x.Allocation
+	}
+	return nil
 }
 
-func (SchedulingPolicy) Descriptor() protoreflect.EnumDescriptor {
-	return file_protocols_scheduler_v1alpha_scheduler_proto_enumTypes[0].Descriptor()
+func (x *ScheduleResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
 }
 
-func (SchedulingPolicy) Type() protoreflect.EnumType {
-	return &file_protocols_scheduler_v1alpha_scheduler_proto_enumTypes[0]
+type ResourceStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Can be empty to get all nodes
+	NodeIds []string `protobuf:"bytes,1,rep,name=node_ids,json=nodeIds,proto3" json:"node_ids,omitempty"`
 }
 
-func (x SchedulingPolicy) Number() protoreflect.EnumNumber {
-	return protoreflect.EnumNumber(x)
-}
\ No newline at end of file
+func (x *ResourceStatusRequest) Reset() {
+	*x = ResourceStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResourceStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceStatusRequest) ProtoMessage() {}
+
+func (x *ResourceStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceStatusRequest.ProtoReflect.Descriptor instead.
+func (*ResourceStatusRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1alpha_scheduler_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ResourceStatusRequest) GetNodeIds() []string {
+	if x != nil {
+		return x.NodeIds
+	}
+	return nil
+}
+
+type ResourceStatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nodes []*NodeResourceStatus `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+}
+
+func (x *ResourceStatusResponse) Reset() {
+	*x = ResourceStatusResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResourceStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceStatusResponse) ProtoMessage() {}
+
+func (x *ResourceStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceStatusResponse.ProtoReflect.Descriptor instead.
+func (*ResourceStatusResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1alpha_scheduler_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ResourceStatusResponse) GetNodes() []*NodeResourceStatus {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+type UpdatePolicyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Policy SchedulingPolicy `protobuf:"varint,1,opt,name=policy,proto3,enum=nfa.scheduler.v1alpha.SchedulingPolicy" json:"policy,omitempty"`
+}
+
+func (x *UpdatePolicyRequest) Reset() {
+	*x = UpdatePolicyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdatePolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePolicyRequest) ProtoMessage() {}
+
+func (x *UpdatePolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePolicyRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePolicyRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1alpha_scheduler_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdatePolicyRequest) GetPolicy() SchedulingPolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return SchedulingPolicy_SCHEDULING_POLICY_UNSPECIFIED
+}
+
+type UpdatePolicyResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Success bool   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *UpdatePolicyResponse) Reset() {
+	*x = UpdatePolicyResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdatePolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePolicyResponse) ProtoMessage() {}
+
+func (x *UpdatePolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePolicyResponse.ProtoReflect.Descriptor instead.
+func (*UpdatePolicyResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1alpha_scheduler_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdatePolicyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UpdatePolicyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ResourceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CpuUnits         float64             `protobuf:"fixed64,1,opt,name=cpu_units,json=cpuUnits,proto3" json:"cpu_units,omitempty"`
+	MemoryMb         uint64              `protobuf:"varint,2,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`
+	Accelerator      *AcceleratorRequest `protobuf:"bytes,3,opt,name=accelerator,proto3" json:"accelerator,omitempty"`
+	NetworkBandwidth uint64              `protobuf:"varint,4,opt,name=network_bandwidth,json=networkBandwidth,proto3" json:"network_bandwidth,omitempty"`
+	MaxLatencyMs     uint64              `protobuf:"varint,5,opt,name=max_latency_ms,json=maxLatencyMs,proto3" json:"max_latency_ms,omitempty"`
+}
+
+func (x *ResourceRequest) Reset() {
+	*x = ResourceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResourceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceRequest) ProtoMessage() {}
+
+func (x *ResourceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceRequest.ProtoReflect.Descriptor instead.
+func (*ResourceRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1alpha_scheduler_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ResourceRequest) GetCpuUnits() float64 {
+	if x != nil {
+		return x.CpuUnits
+	}
+	return 0
+}
+
+func (x *ResourceRequest) GetMemoryMb() uint64 {
+	if x != nil {
+		return x.MemoryMb
+	}
+	return 0
+}
+
+func (x *ResourceRequest) GetAccelerator() *AcceleratorRequest {
+	if x != nil {
+		return x.Accelerator
+	}
+	return nil
+}
+
+func (x *ResourceRequest) GetNetworkBandwidth() uint64 {
+	if x != nil {
+		return x.NetworkBandwidth
+	}
+	return 0
+}
+
+func (x *ResourceRequest) GetMaxLatencyMs() uint64 {
+	if x != nil {
+		return x.MaxLatencyMs
+	}
+	return 0
+}
+
+type AcceleratorRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind     string  `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Units    float64 `protobuf:"fixed64,2,opt,name=units,proto3" json:"units,omitempty"`
+	MemoryMb uint64  `protobuf:"varint,3,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`
+}
+
+func (x *AcceleratorRequest) Reset() {
+	*x = AcceleratorRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AcceleratorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceleratorRequest) ProtoMessage() {}
+
+func (x *AcceleratorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceleratorRequest.ProtoReflect.Descriptor instead.
+func (*AcceleratorRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1alpha_scheduler_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AcceleratorRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *AcceleratorRequest) GetUnits() float64 {
+	if x != nil {
+		return x.Units
+	}
+	return 0
+}
+
+func (x *AcceleratorRequest) GetMemoryMb() uint64 {
+	if x != nil {
+		return x.MemoryMb
+	}
+	return 0
+}
+
+type ResourceAllocation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeId             string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	CpuUnits           float64                `protobuf:"fixed64,2,opt,name=cpu_units,json=cpuUnits,proto3" json:"cpu_units,omitempty"`
+	MemoryMb           uint64                 `protobuf:"varint,3,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`
+	Accelerator        *AcceleratorAllocation `protobuf:"bytes,4,opt,name=accelerator,proto3" json:"accelerator,omitempty"`
+	EstimatedLatencyMs uint64                 `protobuf:"varint,5,opt,name=estimated_latency_ms,json=estimatedLatencyMs,proto3" json:"estimated_latency_ms,omitempty"`
+	CostUnits          float64                `protobuf:"fixed64,6,opt,name=cost_units,json=costUnits,proto3" json:"cost_units,omitempty"`
+}
+
+func (x *ResourceAllocation) Reset() {
+	*x = ResourceAllocation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResourceAllocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceAllocation) ProtoMessage() {}
+
+func (x *ResourceAllocation) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceAllocation.ProtoReflect.Descriptor instead.
+func (*ResourceAllocation) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1alpha_scheduler_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ResourceAllocation) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *ResourceAllocation) GetCpuUnits() float64 {
+	if x != nil {
+		return x.CpuUnits
+	}
+	return 0
+}
+
+func (x *ResourceAllocation) GetMemoryMb() uint64 {
+	if x != nil {
+		return x.MemoryMb
+	}
+	return 0
+}
+
+func (x *ResourceAllocation) GetAccelerator() *AcceleratorAllocation {
+	if x != nil {
+		return x.Accelerator
+	}
+	return nil
+}
+
+func (x *ResourceAllocation) GetEstimatedLatencyMs() uint64 {
+	if x != nil {
+		return x.EstimatedLatencyMs
+	}
+	return 0
+}
+
+func (x *ResourceAllocation) GetCostUnits() float64 {
+	if x != nil {
+		return x.CostUnits
+	}
+	return 0
+}
+
+type AcceleratorAllocation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind     string  `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Units    float64 `protobuf:"fixed64,2,opt,name=units,proto3" json:"units,omitempty"`
+	MemoryMb uint64  `protobuf:"varint,3,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`
+}
+
+func (x *AcceleratorAllocation) Reset() {
+	*x = AcceleratorAllocation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AcceleratorAllocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceleratorAllocation) ProtoMessage() {}
+
+func (x *AcceleratorAllocation) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceleratorAllocation.ProtoReflect.Descriptor instead.
+func (*AcceleratorAllocation) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1alpha_scheduler_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AcceleratorAllocation) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *AcceleratorAllocation) GetUnits() float64 {
+	if x != nil {
+		return x.Units
+	}
+	return 0
+}
+
+func (x *AcceleratorAllocation) GetMemoryMb() uint64 {
+	if x != nil {
+		return x.MemoryMb
+	}
+	return 0
+}
+
+type NodeResourceStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeId             string               `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	TotalCpu           float64              `protobuf:"fixed64,2,opt,name=total_cpu,json=totalCpu,proto3" json:"total_cpu,omitempty"`
+	UsedCpu            float64              `protobuf:"fixed64,3,opt,name=used_cpu,json=usedCpu,proto3" json:"used_cpu,omitempty"`
+	TotalMemory        uint64               `protobuf:"varint,4,opt,name=total_memory,json=totalMemory,proto3" json:"total_memory,omitempty"`
+	UsedMemory         uint64               `protobuf:"varint,5,opt,name=used_memory,json=usedMemory,proto3" json:"used_memory,omitempty"`
+	Accelerators       []*AcceleratorStatus `protobuf:"bytes,6,rep,name=accelerators,proto3" json:"accelerators,omitempty"`
+	NetworkBandwidth   uint64               `protobuf:"varint,7,opt,name=network_bandwidth,json=networkBandwidth,proto3" json:"network_bandwidth,omitempty"`
+	AvailableBandwidth uint64               `protobuf:"varint,8,opt,name=available_bandwidth,json=availableBandwidth,proto3" json:"available_bandwidth,omitempty"`
+	AverageLatencyMs   uint64               `protobuf:"varint,9,opt,name=average_latency_ms,json=averageLatencyMs,proto3" json:"average_latency_ms,omitempty"`
+}
+
+func (x *NodeResourceStatus) Reset() {
+	*x = NodeResourceStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NodeResourceStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeResourceStatus) ProtoMessage() {}
+
+func (x *NodeResourceStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeResourceStatus.ProtoReflect.Descriptor instead.
+func (*NodeResourceStatus) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1alpha_scheduler_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *NodeResourceStatus) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *NodeResourceStatus) GetTotalCpu() float64 {
+	if x != nil {
+		return x.TotalCpu
+	}
+	return 0
+}
+
+func (x *NodeResourceStatus) GetUsedCpu() float64 {
+	if x != nil {
+		return x.UsedCpu
+	}
+	return 0
+}
+
+func (x *NodeResourceStatus) GetTotalMemory() uint64 {
+	if x != nil {
+		return x.TotalMemory
+	}
+	return 0
+}
+
+func (x *NodeResourceStatus) GetUsedMemory() uint64 {
+	if x != nil {
+		return x.UsedMemory
+	}
+	return 0
+}
+
+func (x *NodeResourceStatus) GetAccelerators() []*AcceleratorStatus {
+	if x != nil {
+		return x.Accelerators
+	}
+	return nil
+}
+
+func (x *NodeResourceStatus) GetNetworkBandwidth() uint64 {
+	if x != nil {
+		return x.NetworkBandwidth
+	}
+	return 0
+}
+
+func (x *NodeResourceStatus) GetAvailableBandwidth() uint64 {
+	if x != nil {
+		return x.AvailableBandwidth
+	}
+	return 0
+}
+
+func (x *NodeResourceStatus) GetAverageLatencyMs() uint64 {
+	if x != nil {
+		return x.AverageLatencyMs
+	}
+	return 0
+}
+
+type AcceleratorStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind        string  `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	TotalUnits  float64 `protobuf:"fixed64,2,opt,name=total_units,json=totalUnits,proto3" json:"total_units,omitempty"`
+	UsedUnits   float64 `protobuf:"fixed64,3,opt,name=used_units,json=usedUnits,proto3" json:"used_units,omitempty"`
+	TotalMemory uint64  `protobuf:"varint,4,opt,name=total_memory,json=totalMemory,proto3" json:"total_memory,omitempty"`
+	UsedMemory  uint64  `protobuf:"varint,5,opt,name=used_memory,json=usedMemory,proto3" json:"used_memory,omitempty"`
+}
+
+func (x *AcceleratorStatus) Reset() {
+	*x = AcceleratorStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AcceleratorStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceleratorStatus) ProtoMessage() {}
+
+func (x *AcceleratorStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1alpha_scheduler_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceleratorStatus.ProtoReflect.Descriptor instead.
+func (*AcceleratorStatus) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1alpha_scheduler_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AcceleratorStatus) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *AcceleratorStatus) GetTotalUnits() float64 {
+	if x != nil {
+		return x.TotalUnits
+	}
+	return 0
+}
+
+func (x *AcceleratorStatus) GetUsedUnits() float64 {
+	if x != nil {
+		return x.UsedUnits
+	}
+	return 0
+}
+
+func (x *AcceleratorStatus) GetTotalMemory() uint64 {
+	if x != nil {
+		return x.TotalMemory
+	}
+	return 0
+}
+
+func (x *AcceleratorStatus) GetUsedMemory() uint64 {
+	if x != nil {
+		return x.UsedMemory
+	}
+	return 0
+}
+
+var File_scheduler_v1alpha_scheduler_proto protoreflect.FileDescriptor
+
+var file_scheduler_v1alpha_scheduler_proto_rawDesc = []byte{
+	0x0a, 0x21, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x15, 0x6e, 0x66, 0x61, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x1a, 0x1b, 0x69, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xae, 0x01, 0x0a, 0x0f, 0x53, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x48, 0x0a, 0x0e, 0x69,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x51, 0x0a, 0x10, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x26, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x82, 0x01, 0x0a, 0x10, 0x53, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a,
+	0x0a, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x29, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x61, 0x6c,
+	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x32, 0x0a,
+	0x15, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69,
+	0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64,
+	0x73, 0x22, 0x59, 0x0a, 0x16, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x05, 0x6e,
+	0x6f, 0x64, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x6e, 0x66, 0x61,
+	0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x22, 0x56, 0x0a, 0x13,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x27, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x53, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x69, 0x6e, 0x67, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x06, 0x70, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x22, 0x4a, 0x0a, 0x14, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x22, 0xeb, 0x01, 0x0a, 0x0f, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x70, 0x75, 0x5f, 0x75, 0x6e, 0x69, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x63, 0x70, 0x75, 0x55, 0x6e, 0x69, 0x74,
+	0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x6d, 0x62, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x4d, 0x62, 0x12, 0x4b,
+	0x0a, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x6c, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x41, 0x63, 0x63, 0x65,
+	0x6c, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0b,
+	0x61, 0x63, 0x63, 0x65, 0x6c, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x2b, 0x0a, 0x11, 0x6e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x62, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x42,
+	0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x24, 0x0a, 0x0e, 0x6d, 0x61, 0x78, 0x5f,
+	0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0c, 0x6d, 0x61, 0x78, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4d, 0x73, 0x22, 0x5b,
+	0x0a, 0x12, 0x41, 0x63, 0x63, 0x65, 0x6c, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x74,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x1b,
+	0x0a, 0x09, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x6d, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x08, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x4d, 0x62, 0x22, 0x88, 0x02, 0x0a, 0x12,
+	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x63,
+	0x70, 0x75, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08,
+	0x63, 0x70, 0x75, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x65, 0x6d, 0x6f,
+	0x72, 0x79, 0x5f, 0x6d, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x6d, 0x65, 0x6d,
+	0x6f, 0x72, 0x79, 0x4d, 0x62, 0x12, 0x4e, 0x0a, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x6c, 0x65, 0x72,
+	0x61, 0x74, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x6e, 0x66, 0x61,
+	0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x2e, 0x41, 0x63, 0x63, 0x65, 0x6c, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x41, 0x6c,
+	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x6c, 0x65,
+	0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x30, 0x0a, 0x14, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74,
+	0x65, 0x64, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x6d, 0x73, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x12, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x4c, 0x61,
+	0x74, 0x65, 0x6e, 0x63, 0x79, 0x4d, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6f, 0x73, 0x74, 0x5f,
+	0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x63, 0x6f, 0x73,
+	0x74, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x22, 0x5e, 0x0a, 0x15, 0x41, 0x63, 0x63, 0x65, 0x6c, 0x65,
+	0x72, 0x61, 0x74, 0x6f, 0x72, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b,
+	0x69, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x65, 0x6d,
+	0x6f, 0x72, 0x79, 0x5f, 0x6d, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x6d, 0x65,
+	0x6d, 0x6f, 0x72, 0x79, 0x4d, 0x62, 0x22, 0x83, 0x03, 0x0a, 0x12, 0x4e, 0x6f, 0x64, 0x65, 0x52,
+	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x17, 0x0a,
+	0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f,
+	0x63, 0x70, 0x75, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x43, 0x70, 0x75, 0x12, 0x19, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x64, 0x5f, 0x63, 0x70, 0x75, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x75, 0x73, 0x65, 0x64, 0x43, 0x70, 0x75, 0x12, 0x21,
+	0x0a, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4d, 0x65, 0x6d, 0x6f, 0x72,
+	0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x75, 0x73, 0x65, 0x64, 0x5f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x75, 0x73, 0x65, 0x64, 0x4d, 0x65, 0x6d, 0x6f,
+	0x72, 0x79, 0x12, 0x4c, 0x0a, 0x0c, 0x61, 0x63, 0x63, 0x65, 0x6c, 0x65, 0x72, 0x61, 0x74, 0x6f,
+	0x72, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x73,
+	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x2e, 0x41, 0x63, 0x63, 0x65, 0x6c, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x0c, 0x61, 0x63, 0x63, 0x65, 0x6c, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x73,
+	0x12, 0x2b, 0x0a, 0x11, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x62, 0x61, 0x6e, 0x64,
+	0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x6e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x2f, 0x0a,
+	0x13, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x62, 0x61, 0x6e, 0x64, 0x77,
+	0x69, 0x64, 0x74, 0x68, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x61, 0x76, 0x61, 0x69,
+	0x6c, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x2c,
+	0x0a, 0x12, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63,
+	0x79, 0x5f, 0x6d, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x61, 0x76, 0x65, 0x72,
+	0x61, 0x67, 0x65, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4d, 0x73, 0x22, 0xab, 0x01, 0x0a,
+	0x11, 0x41, 0x63, 0x63, 0x65, 0x6c, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f,
+	0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x73, 0x65, 0x64, 0x5f,
+	0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x75, 0x73, 0x65,
+	0x64, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f,
+	0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x75, 0x73, 0x65,
+	0x64, 0x5f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a,
+	0x75, 0x73, 0x65, 0x64, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x2a, 0xd5, 0x01, 0x0a, 0x10, 0x53,
+	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x69, 0x6e, 0x67, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12,
+	0x21, 0x0a, 0x1d, 0x53, 0x43, 0x48, 0x45, 0x44, 0x55, 0x4c, 0x49, 0x4e, 0x47, 0x5f, 0x50, 0x4f,
+	0x4c, 0x49, 0x43, 0x59, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44,
+	0x10, 0x00, 0x12, 0x27, 0x0a, 0x23, 0x53, 0x43, 0x48, 0x45, 0x44, 0x55, 0x4c, 0x49, 0x4e, 0x47,
+	0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59, 0x5f, 0x50, 0x45, 0x52, 0x46, 0x4f, 0x52, 0x4d, 0x41,
+	0x4e, 0x43, 0x45, 0x5f, 0x46, 0x49, 0x52, 0x53, 0x54, 0x10, 0x01, 0x12, 0x26, 0x0a, 0x22, 0x53,
+	0x43, 0x48, 0x45, 0x44, 0x55, 0x4c, 0x49, 0x4e, 0x47, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59,
+	0x5f, 0x45, 0x4e, 0x45, 0x52, 0x47, 0x59, 0x5f, 0x45, 0x46, 0x46, 0x49, 0x43, 0x49, 0x45, 0x4e,
+	0x54, 0x10, 0x02, 0x12, 0x27, 0x0a, 0x23, 0x53, 0x43, 0x48, 0x45, 0x44, 0x55, 0x4c, 0x49, 0x4e,
+	0x47, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59, 0x5f, 0x4c, 0x41, 0x54, 0x45, 0x4e, 0x43, 0x59,
+	0x5f, 0x53, 0x45, 0x4e, 0x53, 0x49, 0x54, 0x49, 0x56, 0x45, 0x10, 0x03, 0x12, 0x24, 0x0a, 0x20,
+	0x53, 0x43, 0x48, 0x45, 0x44, 0x55, 0x4c, 0x49, 0x4e, 0x47, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43,
+	0x59, 0x5f, 0x43, 0x4f, 0x53, 0x54, 0x5f, 0x4f, 0x50, 0x54, 0x49, 0x4d, 0x49, 0x5a, 0x45, 0x44,
+	0x10, 0x04, 0x32, 0xca, 0x02, 0x0a, 0x10, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x5b, 0x0a, 0x08, 0x53, 0x63, 0x68, 0x65, 0x64,
+	0x75, 0x6c, 0x65, 0x12, 0x26, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x53, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x6e, 0x66,
+	0x61, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x70, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2c, 0x2e, 0x6e, 0x66, 0x61, 0x2e,
+	0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x73, 0x63,
+	0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e,
+	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x67, 0x0a, 0x0c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x2a, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x73, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42,
+	0x56, 0x5a, 0x54, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e, 0x65,
+	0x75, 0x72, 0x6f, 0x2d, 0x66, 0x6c, 0x75, 0x69, 0x64, 0x69, 0x63, 0x2d, 0x61, 0x72, 0x63, 0x68,
+	0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x2f, 0x6e, 0x66, 0x61, 0x2d, 0x63, 0x6f, 0x72,
+	0x65, 0x2f, 0x67, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x73, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x3b, 0x73, 0x63,
+	0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_scheduler_v1alpha_scheduler_proto_rawDescOnce sync.Once
+	file_scheduler_v1alpha_scheduler_proto_rawDescData = file_scheduler_v1alpha_scheduler_proto_rawDesc
+)
+
+func file_scheduler_v1alpha_scheduler_proto_rawDescGZIP() []byte {
+	file_scheduler_v1alpha_scheduler_proto_rawDescOnce.Do(func() {
+		file_scheduler_v1alpha_scheduler_proto_rawDescData = protoimpl.X.CompressGZIP(file_scheduler_v1alpha_scheduler_proto_rawDescData)
+	})
+	return file_scheduler_v1alpha_scheduler_proto_rawDescData
+}
+
+var file_scheduler_v1alpha_scheduler_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_scheduler_v1alpha_scheduler_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_scheduler_v1alpha_scheduler_proto_goTypes = []interface{}{
+	(SchedulingPolicy)(0),          // 0: nfa.scheduler.v1alpha.SchedulingPolicy
+	(*ScheduleRequest)(nil),        // 1: nfa.scheduler.v1alpha.ScheduleRequest
+	(*ScheduleResponse)(nil),       // 2: nfa.scheduler.v1alpha.ScheduleResponse
+	(*ResourceStatusRequest)(nil),  // 3: nfa.scheduler.v1alpha.ResourceStatusRequest
+	(*ResourceStatusResponse)(nil), // 4: nfa.scheduler.v1alpha.ResourceStatusResponse
+	(*UpdatePolicyRequest)(nil),    // 5: nfa.scheduler.v1alpha.UpdatePolicyRequest
+	(*UpdatePolicyResponse)(nil),   // 6: nfa.scheduler.v1alpha.UpdatePolicyResponse
+	(*ResourceRequest)(nil),        // 7: nfa.scheduler.v1alpha.ResourceRequest
+	(*AcceleratorRequest)(nil),     // 8: nfa.scheduler.v1alpha.AcceleratorRequest
+	(*ResourceAllocation)(nil),     // 9: nfa.scheduler.v1alpha.ResourceAllocation
+	(*AcceleratorAllocation)(nil),  // 10: nfa.scheduler.v1alpha.AcceleratorAllocation
+	(*NodeResourceStatus)(nil),     // 11: nfa.scheduler.v1alpha.NodeResourceStatus
+	(*AcceleratorStatus)(nil),      // 12: nfa.scheduler.v1alpha.AcceleratorStatus
+	(*v1alpha.IntentRequest)(nil),  // 13: nfa.intent.v1alpha.IntentRequest
+}
+var file_scheduler_v1alpha_scheduler_proto_depIdxs = []int32{
+	13, // 0: nfa.scheduler.v1alpha.ScheduleRequest.intent_request:type_name -> nfa.intent.v1alpha.IntentRequest
+	7,  // 1: nfa.scheduler.v1alpha.ScheduleRequest.resource_request:type_name -> nfa.scheduler.v1alpha.ResourceRequest
+	9,  // 2: nfa.scheduler.v1alpha.ScheduleResponse.allocation:type_name -> nfa.scheduler.v1alpha.ResourceAllocation
+	11, // 3: nfa.scheduler.v1alpha.ResourceStatusResponse.nodes:type_name -> nfa.scheduler.v1alpha.NodeResourceStatus
+	0,  // 4: nfa.scheduler.v1alpha.UpdatePolicyRequest.policy:type_name -> nfa.scheduler.v1alpha.SchedulingPolicy
+	8,  // 5: nfa.scheduler.v1alpha.ResourceRequest.accelerator:type_name -> nfa.scheduler.v1alpha.AcceleratorRequest
+	10, // 6: nfa.scheduler.v1alpha.ResourceAllocation.accelerator:type_name -> nfa.scheduler.v1alpha.AcceleratorAllocation
+	12, // 7: nfa.scheduler.v1alpha.NodeResourceStatus.accelerators:type_name -> nfa.scheduler.v1alpha.AcceleratorStatus
+	1,  // 8: nfa.scheduler.v1alpha.SchedulerService.Schedule:input_type -> nfa.scheduler.v1alpha.ScheduleRequest
+	3,  // 9: nfa.scheduler.v1alpha.SchedulerService.GetResourceStatus:input_type -> nfa.scheduler.v1alpha.ResourceStatusRequest
+	5,  // 10: nfa.scheduler.v1alpha.SchedulerService.UpdatePolicy:input_type -> nfa.scheduler.v1alpha.UpdatePolicyRequest
+	2,  // 11: nfa.scheduler.v1alpha.SchedulerService.Schedule:output_type -> nfa.scheduler.v1alpha.ScheduleResponse
+	4,  // 12: nfa.scheduler.v1alpha.SchedulerService.GetResourceStatus:output_type -> nfa.scheduler.v1alpha.ResourceStatusResponse
+	6,  // 13: nfa.scheduler.v1alpha.SchedulerService.UpdatePolicy:output_type -> nfa.scheduler.v1alpha.UpdatePolicyResponse
+	11, // [11:14] is the sub-list for method output_type
+	8,  // [8:11] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_scheduler_v1alpha_scheduler_proto_init() }
+func file_scheduler_v1alpha_scheduler_proto_init() {
+	if File_scheduler_v1alpha_scheduler_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_scheduler_v1alpha_scheduler_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScheduleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scheduler_v1alpha_scheduler_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScheduleResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scheduler_v1alpha_scheduler_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResourceStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scheduler_v1alpha_scheduler_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResourceStatusResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scheduler_v1alpha_scheduler_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdatePolicyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scheduler_v1alpha_scheduler_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdatePolicyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scheduler_v1alpha_scheduler_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResourceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scheduler_v1alpha_scheduler_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AcceleratorRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scheduler_v1alpha_scheduler_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResourceAllocation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scheduler_v1alpha_scheduler_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AcceleratorAllocation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scheduler_v1alpha_scheduler_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NodeResourceStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scheduler_v1alpha_scheduler_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AcceleratorStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_scheduler_v1alpha_scheduler_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_scheduler_v1alpha_scheduler_proto_goTypes,
+		DependencyIndexes: file_scheduler_v1alpha_scheduler_proto_depIdxs,
+		EnumInfos:         file_scheduler_v1alpha_scheduler_proto_enumTypes,
+		MessageInfos:      file_scheduler_v1alpha_scheduler_proto_msgTypes,
+	}.Build()
+	File_scheduler_v1alpha_scheduler_proto = out.File
+	file_scheduler_v1alpha_scheduler_proto_rawDesc = nil
+	file_scheduler_v1alpha_scheduler_proto_goTypes = nil
+	file_scheduler_v1alpha_scheduler_proto_depIdxs = nil
+}
@@ -0,0 +1,181 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: stream/v1alpha/stream.proto
+
+package stream
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ResumableStreamService_Open_FullMethodName = "/nfa.stream.v1alpha.ResumableStreamService/Open"
+	ResumableStreamService_Ack_FullMethodName  = "/nfa.stream.v1alpha.ResumableStreamService/Ack"
+)
+
+// ResumableStreamServiceClient is the client API for ResumableStreamService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ResumableStreamServiceClient interface {
+	// Start a streaming intent, or resume one when resume_token is set
+	Open(ctx context.Context, in *OpenStreamRequest, opts ...grpc.CallOption) (ResumableStreamService_OpenClient, error)
+	// Checkpoint: chunks up to and including sequence were processed and
+	// need not be retained for replay
+	Ack(ctx context.Context, in *AckStreamRequest, opts ...grpc.CallOption) (*AckStreamResponse, error)
+}
+
+type resumableStreamServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewResumableStreamServiceClient(cc grpc.ClientConnInterface) ResumableStreamServiceClient {
+	return &resumableStreamServiceClient{cc}
+}
+
+func (c *resumableStreamServiceClient) Open(ctx context.Context, in *OpenStreamRequest, opts ...grpc.CallOption) (ResumableStreamService_OpenClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ResumableStreamService_ServiceDesc.Streams[0], ResumableStreamService_Open_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &resumableStreamServiceOpenClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ResumableStreamService_OpenClient interface {
+	Recv() (*StreamChunk, error)
+	grpc.ClientStream
+}
+
+type resumableStreamServiceOpenClient struct {
+	grpc.ClientStream
+}
+
+func (x *resumableStreamServiceOpenClient) Recv() (*StreamChunk, error) {
+	m := new(StreamChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *resumableStreamServiceClient) Ack(ctx context.Context, in *AckStreamRequest, opts ...grpc.CallOption) (*AckStreamResponse, error) {
+	out := new(AckStreamResponse)
+	err := c.cc.Invoke(ctx, ResumableStreamService_Ack_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ResumableStreamServiceServer is the server API for ResumableStreamService service.
+// All implementations must embed UnimplementedResumableStreamServiceServer
+// for forward compatibility
+type ResumableStreamServiceServer interface {
+	// Start a streaming intent, or resume one when resume_token is set
+	Open(*OpenStreamRequest, ResumableStreamService_OpenServer) error
+	// Checkpoint: chunks up to and including sequence were processed and
+	// need not be retained for replay
+	Ack(context.Context, *AckStreamRequest) (*AckStreamResponse, error)
+	mustEmbedUnimplementedResumableStreamServiceServer()
+}
+
+// UnimplementedResumableStreamServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedResumableStreamServiceServer struct {
+}
+
+func (UnimplementedResumableStreamServiceServer) Open(*OpenStreamRequest, ResumableStreamService_OpenServer) error {
+	return status.Errorf(codes.Unimplemented, "method Open not implemented")
+}
+func (UnimplementedResumableStreamServiceServer) Ack(context.Context, *AckStreamRequest) (*AckStreamResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ack not implemented")
+}
+func (UnimplementedResumableStreamServiceServer) mustEmbedUnimplementedResumableStreamServiceServer() {
+}
+
+// UnsafeResumableStreamServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ResumableStreamServiceServer will
+// result in compilation errors.
+type UnsafeResumableStreamServiceServer interface {
+	mustEmbedUnimplementedResumableStreamServiceServer()
+}
+
+func RegisterResumableStreamServiceServer(s grpc.ServiceRegistrar, srv ResumableStreamServiceServer) {
+	s.RegisterService(&ResumableStreamService_ServiceDesc, srv)
+}
+
+func _ResumableStreamService_Open_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(OpenStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ResumableStreamServiceServer).Open(m, &resumableStreamServiceOpenServer{stream})
+}
+
+type ResumableStreamService_OpenServer interface {
+	Send(*StreamChunk) error
+	grpc.ServerStream
+}
+
+type resumableStreamServiceOpenServer struct {
+	grpc.ServerStream
+}
+
+func (x *resumableStreamServiceOpenServer) Send(m *StreamChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ResumableStreamService_Ack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AckStreamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResumableStreamServiceServer).Ack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ResumableStreamService_Ack_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResumableStreamServiceServer).Ack(ctx, req.(*AckStreamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ResumableStreamService_ServiceDesc is the grpc.ServiceDesc for ResumableStreamService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ResumableStreamService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "nfa.stream.v1alpha.ResumableStreamService",
+	HandlerType: (*ResumableStreamServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Ack",
+			Handler:    _ResumableStreamService_Ack_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Open",
+			Handler:       _ResumableStreamService_Open_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "stream/v1alpha/stream.proto",
+}
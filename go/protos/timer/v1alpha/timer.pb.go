@@ -0,0 +1,792 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v3.21.12
+// source: timer/v1alpha/timer.proto
+
+package timer
+
+import (
+	v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type TimerState int32
+
+const (
+	TimerState_TIMER_STATE_UNSPECIFIED TimerState = 0
+	// Waiting for its fire time, or retrying a failed delivery
+	TimerState_TIMER_STATE_PENDING TimerState = 1
+	// At least one target handled the intent
+	TimerState_TIMER_STATE_DELIVERED TimerState = 2
+	// No target handled the intent before the timer's max lateness passed
+	TimerState_TIMER_STATE_FAILED    TimerState = 3
+	TimerState_TIMER_STATE_CANCELLED TimerState = 4
+)
+
+// Enum value maps for TimerState.
+var (
+	TimerState_name = map[int32]string{
+		0: "TIMER_STATE_UNSPECIFIED",
+		1: "TIMER_STATE_PENDING",
+		2: "TIMER_STATE_DELIVERED",
+		3: "TIMER_STATE_FAILED",
+		4: "TIMER_STATE_CANCELLED",
+	}
+	TimerState_value = map[string]int32{
+		"TIMER_STATE_UNSPECIFIED": 0,
+		"TIMER_STATE_PENDING":     1,
+		"TIMER_STATE_DELIVERED":   2,
+		"TIMER_STATE_FAILED":      3,
+		"TIMER_STATE_CANCELLED":   4,
+	}
+)
+
+func (x TimerState) Enum() *TimerState {
+	p := new(TimerState)
+	*p = x
+	return p
+}
+
+func (x TimerState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TimerState) Descriptor() protoreflect.EnumDescriptor {
+	return file_timer_v1alpha_timer_proto_enumTypes[0].Descriptor()
+}
+
+func (TimerState) Type() protoreflect.EnumType {
+	return &file_timer_v1alpha_timer_proto_enumTypes[0]
+}
+
+func (x TimerState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TimerState.Descriptor instead.
+func (TimerState) EnumDescriptor() ([]byte, []int) {
+	return file_timer_v1alpha_timer_proto_rawDescGZIP(), []int{0}
+}
+
+type Timer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The broadcast sent when the timer fires
+	Broadcast *v1alpha.BroadcastRequest `protobuf:"bytes,2,opt,name=broadcast,proto3" json:"broadcast,omitempty"`
+	FireTime  *timestamppb.Timestamp    `protobuf:"bytes,3,opt,name=fire_time,json=fireTime,proto3" json:"fire_time,omitempty"`
+	// How long after fire_time delivery is still attempted
+	MaxLatenessSecs uint32                 `protobuf:"varint,4,opt,name=max_lateness_secs,json=maxLatenessSecs,proto3" json:"max_lateness_secs,omitempty"`
+	State           TimerState             `protobuf:"varint,5,opt,name=state,proto3,enum=nfa.timer.v1alpha.TimerState" json:"state,omitempty"`
+	CreateTime      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// When the timer was delivered, failed or was cancelled
+	FinishTime *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=finish_time,json=finishTime,proto3" json:"finish_time,omitempty"`
+	// Broadcasts sent so far
+	Attempts uint32 `protobuf:"varint,8,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// Why the last broadcast was not handled, e.g. no runtime matched
+	LastError string `protobuf:"bytes,9,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	// Target statuses of the broadcast that delivered the intent
+	Targets []*v1alpha.TargetStatus `protobuf:"bytes,10,rep,name=targets,proto3" json:"targets,omitempty"`
+	// The request_id the timer was created with
+	RequestId string `protobuf:"bytes,11,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+}
+
+func (x *Timer) Reset() {
+	*x = Timer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_timer_v1alpha_timer_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Timer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Timer) ProtoMessage() {}
+
+func (x *Timer) ProtoReflect() protoreflect.Message {
+	mi := &file_timer_v1alpha_timer_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Timer.ProtoReflect.Descriptor instead.
+func (*Timer) Descriptor() ([]byte, []int) {
+	return file_timer_v1alpha_timer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Timer) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Timer) GetBroadcast() *v1alpha.BroadcastRequest {
+	if x != nil {
+		return x.Broadcast
+	}
+	return nil
+}
+
+func (x *Timer) GetFireTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FireTime
+	}
+	return nil
+}
+
+func (x *Timer) GetMaxLatenessSecs() uint32 {
+	if x != nil {
+		return x.MaxLatenessSecs
+	}
+	return 0
+}
+
+func (x *Timer) GetState() TimerState {
+	if x != nil {
+		return x.State
+	}
+	return TimerState_TIMER_STATE_UNSPECIFIED
+}
+
+func (x *Timer) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *Timer) GetFinishTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishTime
+	}
+	return nil
+}
+
+func (x *Timer) GetAttempts() uint32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *Timer) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *Timer) GetTargets() []*v1alpha.TargetStatus {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *Timer) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type CreateTimerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The intent and the selector of its targets
+	Broadcast *v1alpha.BroadcastRequest `protobuf:"bytes,1,opt,name=broadcast,proto3" json:"broadcast,omitempty"`
+	// Types that are assignable to When:
+	//	*CreateTimerRequest_FireTime
+	//	*CreateTimerRequest_DelaySecs
+	When isCreateTimerRequest_When `protobuf_oneof:"when"`
+	// How long after the fire time delivery is still attempted, e.g. while
+	// no target is connected; 0 selects 1 hour
+	MaxLatenessSecs uint32 `protobuf:"varint,4,opt,name=max_lateness_secs,json=maxLatenessSecs,proto3" json:"max_lateness_secs,omitempty"`
+	// Makes retried calls safe: creating a timer with the request_id of an
+	// existing timer returns that timer
+	RequestId string `protobuf:"bytes,5,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+}
+
+func (x *CreateTimerRequest) Reset() {
+	*x = CreateTimerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_timer_v1alpha_timer_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateTimerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTimerRequest) ProtoMessage() {}
+
+func (x *CreateTimerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_timer_v1alpha_timer_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTimerRequest.ProtoReflect.Descriptor instead.
+func (*CreateTimerRequest) Descriptor() ([]byte, []int) {
+	return file_timer_v1alpha_timer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateTimerRequest) GetBroadcast() *v1alpha.BroadcastRequest {
+	if x != nil {
+		return x.Broadcast
+	}
+	return nil
+}
+
+func (m *CreateTimerRequest) GetWhen() isCreateTimerRequest_When {
+	if m != nil {
+		return m.When
+	}
+	return nil
+}
+
+func (x *CreateTimerRequest) GetFireTime() *timestamppb.Timestamp {
+	if x, ok := x.GetWhen().(*CreateTimerRequest_FireTime); ok {
+		return x.FireTime
+	}
+	return nil
+}
+
+func (x *CreateTimerRequest) GetDelaySecs() uint32 {
+	if x, ok := x.GetWhen().(*CreateTimerRequest_DelaySecs); ok {
+		return x.DelaySecs
+	}
+	return 0
+}
+
+func (x *CreateTimerRequest) GetMaxLatenessSecs() uint32 {
+	if x != nil {
+		return x.MaxLatenessSecs
+	}
+	return 0
+}
+
+func (x *CreateTimerRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type isCreateTimerRequest_When interface {
+	isCreateTimerRequest_When()
+}
+
+type CreateTimerRequest_FireTime struct {
+	FireTime *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=fire_time,json=fireTime,proto3,oneof"`
+}
+
+type CreateTimerRequest_DelaySecs struct {
+	DelaySecs uint32 `protobuf:"varint,3,opt,name=delay_secs,json=delaySecs,proto3,oneof"`
+}
+
+func (*CreateTimerRequest_FireTime) isCreateTimerRequest_When() {}
+
+func (*CreateTimerRequest_DelaySecs) isCreateTimerRequest_When() {}
+
+type GetTimerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetTimerRequest) Reset() {
+	*x = GetTimerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_timer_v1alpha_timer_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTimerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTimerRequest) ProtoMessage() {}
+
+func (x *GetTimerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_timer_v1alpha_timer_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTimerRequest.ProtoReflect.Descriptor instead.
+func (*GetTimerRequest) Descriptor() ([]byte, []int) {
+	return file_timer_v1alpha_timer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetTimerRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListTimersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Only timers in this state; unspecified lists every timer
+	State TimerState `protobuf:"varint,1,opt,name=state,proto3,enum=nfa.timer.v1alpha.TimerState" json:"state,omitempty"`
+	// Only timers of this action, when set
+	Action string `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+}
+
+func (x *ListTimersRequest) Reset() {
+	*x = ListTimersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_timer_v1alpha_timer_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListTimersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTimersRequest) ProtoMessage() {}
+
+func (x *ListTimersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_timer_v1alpha_timer_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTimersRequest.ProtoReflect.Descriptor instead.
+func (*ListTimersRequest) Descriptor() ([]byte, []int) {
+	return file_timer_v1alpha_timer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListTimersRequest) GetState() TimerState {
+	if x != nil {
+		return x.State
+	}
+	return TimerState_TIMER_STATE_UNSPECIFIED
+}
+
+func (x *ListTimersRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+type ListTimersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Ordered by fire time
+	Timers []*Timer `protobuf:"bytes,1,rep,name=timers,proto3" json:"timers,omitempty"`
+}
+
+func (x *ListTimersResponse) Reset() {
+	*x = ListTimersResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_timer_v1alpha_timer_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListTimersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTimersResponse) ProtoMessage() {}
+
+func (x *ListTimersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_timer_v1alpha_timer_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTimersResponse.ProtoReflect.Descriptor instead.
+func (*ListTimersResponse) Descriptor() ([]byte, []int) {
+	return file_timer_v1alpha_timer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListTimersResponse) GetTimers() []*Timer {
+	if x != nil {
+		return x.Timers
+	}
+	return nil
+}
+
+type CancelTimerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *CancelTimerRequest) Reset() {
+	*x = CancelTimerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_timer_v1alpha_timer_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelTimerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelTimerRequest) ProtoMessage() {}
+
+func (x *CancelTimerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_timer_v1alpha_timer_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelTimerRequest.ProtoReflect.Descriptor instead.
+func (*CancelTimerRequest) Descriptor() ([]byte, []int) {
+	return file_timer_v1alpha_timer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CancelTimerRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+var File_timer_v1alpha_timer_proto protoreflect.FileDescriptor
+
+var file_timer_v1alpha_timer_proto_rawDesc = []byte{
+	0x0a, 0x19, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2f,
+	0x74, 0x69, 0x6d, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x11, 0x6e, 0x66, 0x61,
+	0x2e, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x1a, 0x1d,
+	0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2f,
+	0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x87,
+	0x04, 0x0a, 0x05, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x43, 0x0a, 0x09, 0x62, 0x72, 0x6f, 0x61,
+	0x64, 0x63, 0x61, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x6e, 0x66,
+	0x61, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x2e, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x52, 0x09, 0x62, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x12, 0x37, 0x0a,
+	0x09, 0x66, 0x69, 0x72, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x66, 0x69,
+	0x72, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x6d, 0x61, 0x78, 0x5f, 0x6c, 0x61,
+	0x74, 0x65, 0x6e, 0x65, 0x73, 0x73, 0x5f, 0x73, 0x65, 0x63, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0f, 0x6d, 0x61, 0x78, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x65, 0x73, 0x73, 0x53, 0x65,
+	0x63, 0x73, 0x12, 0x33, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1d, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x3b, 0x0a, 0x0b, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x3b, 0x0a, 0x0b, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x54, 0x69, 0x6d,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x08, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12, 0x1d, 0x0a,
+	0x0a, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x3b, 0x0a, 0x07,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e,
+	0x6e, 0x66, 0x61, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x22, 0x88, 0x02, 0x0a, 0x12, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x43, 0x0a, 0x09, 0x62, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x25, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61,
+	0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x09, 0x62, 0x72, 0x6f, 0x61, 0x64,
+	0x63, 0x61, 0x73, 0x74, 0x12, 0x39, 0x0a, 0x09, 0x66, 0x69, 0x72, 0x65, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x48, 0x00, 0x52, 0x08, 0x66, 0x69, 0x72, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12,
+	0x1f, 0x0a, 0x0a, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x5f, 0x73, 0x65, 0x63, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x09, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x53, 0x65, 0x63, 0x73,
+	0x12, 0x2a, 0x0a, 0x11, 0x6d, 0x61, 0x78, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x65, 0x73, 0x73,
+	0x5f, 0x73, 0x65, 0x63, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x6d, 0x61, 0x78,
+	0x4c, 0x61, 0x74, 0x65, 0x6e, 0x65, 0x73, 0x73, 0x53, 0x65, 0x63, 0x73, 0x12, 0x1d, 0x0a, 0x0a,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x42, 0x06, 0x0a, 0x04, 0x77,
+	0x68, 0x65, 0x6e, 0x22, 0x21, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x60, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x69,
+	0x6d, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x05, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x6e, 0x66, 0x61,
+	0x2e, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x46, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74,
+	0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30,
+	0x0a, 0x06, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x52, 0x06, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x73,
+	0x22, 0x24, 0x0a, 0x12, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x2a, 0x90, 0x01, 0x0a, 0x0a, 0x54, 0x69, 0x6d, 0x65, 0x72,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x0a, 0x17, 0x54, 0x49, 0x4d, 0x45, 0x52, 0x5f, 0x53,
+	0x54, 0x41, 0x54, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44,
+	0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x54, 0x49, 0x4d, 0x45, 0x52, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x45, 0x5f, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x54,
+	0x49, 0x4d, 0x45, 0x52, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x44, 0x45, 0x4c, 0x49, 0x56,
+	0x45, 0x52, 0x45, 0x44, 0x10, 0x02, 0x12, 0x16, 0x0a, 0x12, 0x54, 0x49, 0x4d, 0x45, 0x52, 0x5f,
+	0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x03, 0x12, 0x19,
+	0x0a, 0x15, 0x54, 0x49, 0x4d, 0x45, 0x52, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x43, 0x41,
+	0x4e, 0x43, 0x45, 0x4c, 0x4c, 0x45, 0x44, 0x10, 0x04, 0x32, 0xd3, 0x02, 0x0a, 0x0c, 0x54, 0x69,
+	0x6d, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4e, 0x0a, 0x0b, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x12, 0x25, 0x2e, 0x6e, 0x66, 0x61, 0x2e,
+	0x74, 0x69, 0x6d, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x18, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x12, 0x48, 0x0a, 0x08, 0x47, 0x65,
+	0x74, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x12, 0x22, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x74, 0x69, 0x6d,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x69,
+	0x6d, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x6e, 0x66, 0x61,
+	0x2e, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x72, 0x12, 0x59, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65,
+	0x72, 0x73, 0x12, 0x24, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x72,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x74,
+	0x69, 0x6d, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x4e, 0x0a, 0x0b, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x12, 0x25,
+	0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x6e, 0x66, 0x61, 0x2e, 0x74, 0x69, 0x6d, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x42,
+	0x4e, 0x5a, 0x4c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e, 0x65,
+	0x75, 0x72, 0x6f, 0x2d, 0x66, 0x6c, 0x75, 0x69, 0x64, 0x69, 0x63, 0x2d, 0x61, 0x72, 0x63, 0x68,
+	0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x2f, 0x6e, 0x66, 0x61, 0x2d, 0x63, 0x6f, 0x72,
+	0x65, 0x2f, 0x67, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x74, 0x69, 0x6d, 0x65,
+	0x72, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x3b, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_timer_v1alpha_timer_proto_rawDescOnce sync.Once
+	file_timer_v1alpha_timer_proto_rawDescData = file_timer_v1alpha_timer_proto_rawDesc
+)
+
+func file_timer_v1alpha_timer_proto_rawDescGZIP() []byte {
+	file_timer_v1alpha_timer_proto_rawDescOnce.Do(func() {
+		file_timer_v1alpha_timer_proto_rawDescData = protoimpl.X.CompressGZIP(file_timer_v1alpha_timer_proto_rawDescData)
+	})
+	return file_timer_v1alpha_timer_proto_rawDescData
+}
+
+var file_timer_v1alpha_timer_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_timer_v1alpha_timer_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_timer_v1alpha_timer_proto_goTypes = []interface{}{
+	(TimerState)(0),                  // 0: nfa.timer.v1alpha.TimerState
+	(*Timer)(nil),                    // 1: nfa.timer.v1alpha.Timer
+	(*CreateTimerRequest)(nil),       // 2: nfa.timer.v1alpha.CreateTimerRequest
+	(*GetTimerRequest)(nil),          // 3: nfa.timer.v1alpha.GetTimerRequest
+	(*ListTimersRequest)(nil),        // 4: nfa.timer.v1alpha.ListTimersRequest
+	(*ListTimersResponse)(nil),       // 5: nfa.timer.v1alpha.ListTimersResponse
+	(*CancelTimerRequest)(nil),       // 6: nfa.timer.v1alpha.CancelTimerRequest
+	(*v1alpha.BroadcastRequest)(nil), // 7: nfa.control.v1alpha.BroadcastRequest
+	(*timestamppb.Timestamp)(nil),    // 8: google.protobuf.Timestamp
+	(*v1alpha.TargetStatus)(nil),     // 9: nfa.control.v1alpha.TargetStatus
+}
+var file_timer_v1alpha_timer_proto_depIdxs = []int32{
+	7,  // 0: nfa.timer.v1alpha.Timer.broadcast:type_name -> nfa.control.v1alpha.BroadcastRequest
+	8,  // 1: nfa.timer.v1alpha.Timer.fire_time:type_name -> google.protobuf.Timestamp
+	0,  // 2: nfa.timer.v1alpha.Timer.state:type_name -> nfa.timer.v1alpha.TimerState
+	8,  // 3: nfa.timer.v1alpha.Timer.create_time:type_name -> google.protobuf.Timestamp
+	8,  // 4: nfa.timer.v1alpha.Timer.finish_time:type_name -> google.protobuf.Timestamp
+	9,  // 5: nfa.timer.v1alpha.Timer.targets:type_name -> nfa.control.v1alpha.TargetStatus
+	7,  // 6: nfa.timer.v1alpha.CreateTimerRequest.broadcast:type_name -> nfa.control.v1alpha.BroadcastRequest
+	8,  // 7: nfa.timer.v1alpha.CreateTimerRequest.fire_time:type_name -> google.protobuf.Timestamp
+	0,  // 8: nfa.timer.v1alpha.ListTimersRequest.state:type_name -> nfa.timer.v1alpha.TimerState
+	1,  // 9: nfa.timer.v1alpha.ListTimersResponse.timers:type_name -> nfa.timer.v1alpha.Timer
+	2,  // 10: nfa.timer.v1alpha.TimerService.CreateTimer:input_type -> nfa.timer.v1alpha.CreateTimerRequest
+	3,  // 11: nfa.timer.v1alpha.TimerService.GetTimer:input_type -> nfa.timer.v1alpha.GetTimerRequest
+	4,  // 12: nfa.timer.v1alpha.TimerService.ListTimers:input_type -> nfa.timer.v1alpha.ListTimersRequest
+	6,  // 13: nfa.timer.v1alpha.TimerService.CancelTimer:input_type -> nfa.timer.v1alpha.CancelTimerRequest
+	1,  // 14: nfa.timer.v1alpha.TimerService.CreateTimer:output_type -> nfa.timer.v1alpha.Timer
+	1,  // 15: nfa.timer.v1alpha.TimerService.GetTimer:output_type -> nfa.timer.v1alpha.Timer
+	5,  // 16: nfa.timer.v1alpha.TimerService.ListTimers:output_type -> nfa.timer.v1alpha.ListTimersResponse
+	1,  // 17: nfa.timer.v1alpha.TimerService.CancelTimer:output_type -> nfa.timer.v1alpha.Timer
+	14, // [14:18] is the sub-list for method output_type
+	10, // [10:14] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_timer_v1alpha_timer_proto_init() }
+func file_timer_v1alpha_timer_proto_init() {
+	if File_timer_v1alpha_timer_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_timer_v1alpha_timer_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Timer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_timer_v1alpha_timer_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateTimerRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_timer_v1alpha_timer_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTimerRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_timer_v1alpha_timer_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListTimersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_timer_v1alpha_timer_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListTimersResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_timer_v1alpha_timer_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelTimerRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_timer_v1alpha_timer_proto_msgTypes[1].OneofWrappers = []interface{}{
+		(*CreateTimerRequest_FireTime)(nil),
+		(*CreateTimerRequest_DelaySecs)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_timer_v1alpha_timer_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_timer_v1alpha_timer_proto_goTypes,
+		DependencyIndexes: file_timer_v1alpha_timer_proto_depIdxs,
+		EnumInfos:         file_timer_v1alpha_timer_proto_enumTypes,
+		MessageInfos:      file_timer_v1alpha_timer_proto_msgTypes,
+	}.Build()
+	File_timer_v1alpha_timer_proto = out.File
+	file_timer_v1alpha_timer_proto_rawDesc = nil
+	file_timer_v1alpha_timer_proto_goTypes = nil
+	file_timer_v1alpha_timer_proto_depIdxs = nil
+}
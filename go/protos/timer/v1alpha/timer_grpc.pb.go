@@ -0,0 +1,224 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: timer/v1alpha/timer.proto
+
+package timer
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	TimerService_CreateTimer_FullMethodName = "/nfa.timer.v1alpha.TimerService/CreateTimer"
+	TimerService_GetTimer_FullMethodName    = "/nfa.timer.v1alpha.TimerService/GetTimer"
+	TimerService_ListTimers_FullMethodName  = "/nfa.timer.v1alpha.TimerService/ListTimers"
+	TimerService_CancelTimer_FullMethodName = "/nfa.timer.v1alpha.TimerService/CancelTimer"
+)
+
+// TimerServiceClient is the client API for TimerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TimerServiceClient interface {
+	CreateTimer(ctx context.Context, in *CreateTimerRequest, opts ...grpc.CallOption) (*Timer, error)
+	GetTimer(ctx context.Context, in *GetTimerRequest, opts ...grpc.CallOption) (*Timer, error)
+	ListTimers(ctx context.Context, in *ListTimersRequest, opts ...grpc.CallOption) (*ListTimersResponse, error)
+	// Cancel a pending timer; cancelling a timer that already finished fails
+	// with FAILED_PRECONDITION
+	CancelTimer(ctx context.Context, in *CancelTimerRequest, opts ...grpc.CallOption) (*Timer, error)
+}
+
+type timerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTimerServiceClient(cc grpc.ClientConnInterface) TimerServiceClient {
+	return &timerServiceClient{cc}
+}
+
+func (c *timerServiceClient) CreateTimer(ctx context.Context, in *CreateTimerRequest, opts ...grpc.CallOption) (*Timer, error) {
+	out := new(Timer)
+	err := c.cc.Invoke(ctx, TimerService_CreateTimer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timerServiceClient) GetTimer(ctx context.Context, in *GetTimerRequest, opts ...grpc.CallOption) (*Timer, error) {
+	out := new(Timer)
+	err := c.cc.Invoke(ctx, TimerService_GetTimer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timerServiceClient) ListTimers(ctx context.Context, in *ListTimersRequest, opts ...grpc.CallOption) (*ListTimersResponse, error) {
+	out := new(ListTimersResponse)
+	err := c.cc.Invoke(ctx, TimerService_ListTimers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timerServiceClient) CancelTimer(ctx context.Context, in *CancelTimerRequest, opts ...grpc.CallOption) (*Timer, error) {
+	out := new(Timer)
+	err := c.cc.Invoke(ctx, TimerService_CancelTimer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TimerServiceServer is the server API for TimerService service.
+// All implementations must embed UnimplementedTimerServiceServer
+// for forward compatibility
+type TimerServiceServer interface {
+	CreateTimer(context.Context, *CreateTimerRequest) (*Timer, error)
+	GetTimer(context.Context, *GetTimerRequest) (*Timer, error)
+	ListTimers(context.Context, *ListTimersRequest) (*ListTimersResponse, error)
+	// Cancel a pending timer; cancelling a timer that already finished fails
+	// with FAILED_PRECONDITION
+	CancelTimer(context.Context, *CancelTimerRequest) (*Timer, error)
+	mustEmbedUnimplementedTimerServiceServer()
+}
+
+// UnimplementedTimerServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedTimerServiceServer struct {
+}
+
+func (UnimplementedTimerServiceServer) CreateTimer(context.Context, *CreateTimerRequest) (*Timer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTimer not implemented")
+}
+func (UnimplementedTimerServiceServer) GetTimer(context.Context, *GetTimerRequest) (*Timer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTimer not implemented")
+}
+func (UnimplementedTimerServiceServer) ListTimers(context.Context, *ListTimersRequest) (*ListTimersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTimers not implemented")
+}
+func (UnimplementedTimerServiceServer) CancelTimer(context.Context, *CancelTimerRequest) (*Timer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelTimer not implemented")
+}
+func (UnimplementedTimerServiceServer) mustEmbedUnimplementedTimerServiceServer() {}
+
+// UnsafeTimerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TimerServiceServer will
+// result in compilation errors.
+type UnsafeTimerServiceServer interface {
+	mustEmbedUnimplementedTimerServiceServer()
+}
+
+func RegisterTimerServiceServer(s grpc.ServiceRegistrar, srv TimerServiceServer) {
+	s.RegisterService(&TimerService_ServiceDesc, srv)
+}
+
+func _TimerService_CreateTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimerServiceServer).CreateTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimerService_CreateTimer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimerServiceServer).CreateTimer(ctx, req.(*CreateTimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimerService_GetTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimerServiceServer).GetTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimerService_GetTimer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimerServiceServer).GetTimer(ctx, req.(*GetTimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimerService_ListTimers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTimersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimerServiceServer).ListTimers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimerService_ListTimers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimerServiceServer).ListTimers(ctx, req.(*ListTimersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimerService_CancelTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelTimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimerServiceServer).CancelTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimerService_CancelTimer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimerServiceServer).CancelTimer(ctx, req.(*CancelTimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TimerService_ServiceDesc is the grpc.ServiceDesc for TimerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TimerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "nfa.timer.v1alpha.TimerService",
+	HandlerType: (*TimerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateTimer",
+			Handler:    _TimerService_CreateTimer_Handler,
+		},
+		{
+			MethodName: "GetTimer",
+			Handler:    _TimerService_GetTimer_Handler,
+		},
+		{
+			MethodName: "ListTimers",
+			Handler:    _TimerService_ListTimers_Handler,
+		},
+		{
+			MethodName: "CancelTimer",
+			Handler:    _TimerService_CancelTimer_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "timer/v1alpha/timer.proto",
+}
@@ -0,0 +1,543 @@
+// Package pubsub implements broker-managed intent topics. Providers publish
+// events to dotted topic names such as "nfa.home.door_opened"; consumers read
+// them through durable subscriptions that keep their cursor across
+// reconnects, with at-least-once delivery and replay from a sequence number.
+package pubsub
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	nfa_pubsub_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/pubsub/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// DefaultRetention is the number of events retained per topic for replay
+	DefaultRetention = 10000
+
+	defaultAckDeadline         = 30 * time.Second
+	defaultMaxOutstanding      = 100
+	defaultMaxDeliveryAttempts = 5
+
+	// redeliveryCheck bounds how late an expired event is redelivered
+	redeliveryCheck = time.Second
+	// lossWarningInterval is how often a subscription losing events to
+	// retention is warned about at most
+	lossWarningInterval = time.Minute
+)
+
+var topicName = regexp.MustCompile(`^[a-z0-9_-]+(\.[a-z0-9_-]+)*$`)
+
+// Broker stores topics and subscriptions and serves the pub/sub API
+type Broker struct {
+	nfa_pubsub_v1alpha.UnimplementedPubSubServiceServer
+
+	retention int
+
+	mu     sync.Mutex
+	topics map[string]*topic
+	subs   map[string]*subscription
+}
+
+type topic struct {
+	name      string
+	events    []*nfa_pubsub_v1alpha.Event // ordered by sequence
+	nextSeq   uint64
+	waiters   map[*subscription]struct{}
+	unordered bool
+}
+
+type subscription struct {
+	name        string
+	topic       string
+	ackDeadline time.Duration
+
+	// next is the next sequence number never delivered on this subscription
+	next uint64
+	// inflight holds delivered but unacknowledged events and their redelivery deadline
+	inflight map[uint64]time.Time
+	attempts map[uint64]uint32
+	notify   chan struct{}
+
+	maxAttempts uint32
+	// held keeps requeued events that retention may already have trimmed from the topic
+	held        map[uint64]*nfa_pubsub_v1alpha.Event
+	deadLetters []*nfa_pubsub_v1alpha.DeadLetter
+
+	// lost counts the events retention trimmed before they were acknowledged
+	// since the last warning, at lostWarned
+	lost       uint64
+	lostWarned time.Time
+
+	// Per ordering key, the one event in flight and the events queued behind it
+	busy    map[string]uint64
+	waiting map[string][]uint64
+	keyOf   map[uint64]string
+}
+
+// NewBroker creates a pub/sub broker retaining up to retention events per
+// topic; zero selects DefaultRetention
+func NewBroker(retention int) *Broker {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &Broker{
+		retention: retention,
+		topics:    make(map[string]*topic),
+		subs:      make(map[string]*subscription),
+	}
+}
+
+// Register registers the pub/sub service on a gRPC server
+func (b *Broker) Register(registrar grpc.ServiceRegistrar) {
+	nfa_pubsub_v1alpha.RegisterPubSubServiceServer(registrar, b)
+}
+
+// Publish implements the Publish RPC
+func (b *Broker) Publish(ctx context.Context, req *nfa_pubsub_v1alpha.PublishRequest) (*nfa_pubsub_v1alpha.PublishResponse, error) {
+	if !topicName.MatchString(req.Topic) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid topic %q, expected dotted lowercase segments such as nfa.home.door_opened", req.Topic)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t := b.topic(req.Topic)
+	event := &nfa_pubsub_v1alpha.Event{
+		Topic:       t.name,
+		Sequence:    t.nextSeq,
+		Payload:     req.Payload,
+		Attributes:  req.Attributes,
+		PublishTime: timestamppb.Now(),
+		OrderingKey: req.OrderingKey,
+	}
+	t.nextSeq++
+	t.events = append(t.events, event)
+	if len(t.events) > b.retention {
+		b.trim(t)
+	}
+	for sub := range t.waiters {
+		sub.wake()
+	}
+	return &nfa_pubsub_v1alpha.PublishResponse{Sequence: event.Sequence}, nil
+}
+
+// ConfigureTopic implements the ConfigureTopic RPC. Relaxing a topic to
+// unordered delivery raises throughput when consumers do not depend on
+// per-session ordering.
+func (b *Broker) ConfigureTopic(ctx context.Context, req *nfa_pubsub_v1alpha.ConfigureTopicRequest) (*nfa_pubsub_v1alpha.Topic, error) {
+	if !topicName.MatchString(req.Topic) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid topic %q", req.Topic)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t := b.topic(req.Topic)
+	t.unordered = req.Ordering == nfa_pubsub_v1alpha.DeliveryOrdering_DELIVERY_ORDERING_UNORDERED
+	for sub := range t.waiters {
+		sub.wake()
+	}
+
+	ordering := nfa_pubsub_v1alpha.DeliveryOrdering_DELIVERY_ORDERING_ORDERED
+	if t.unordered {
+		ordering = nfa_pubsub_v1alpha.DeliveryOrdering_DELIVERY_ORDERING_UNORDERED
+	}
+	return &nfa_pubsub_v1alpha.Topic{
+		Name:         t.name,
+		Ordering:     ordering,
+		NextSequence: t.nextSeq,
+		Retained:     uint64(len(t.events)),
+	}, nil
+}
+
+// CreateSubscription implements the CreateSubscription RPC
+func (b *Broker) CreateSubscription(ctx context.Context, req *nfa_pubsub_v1alpha.CreateSubscriptionRequest) (*nfa_pubsub_v1alpha.Subscription, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "subscription name is required")
+	}
+	if !topicName.MatchString(req.Topic) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid topic %q", req.Topic)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if sub, ok := b.subs[req.Name]; ok {
+		if sub.topic != req.Topic {
+			return nil, status.Errorf(codes.AlreadyExists, "subscription %s already exists on topic %s", req.Name, sub.topic)
+		}
+		return b.describe(sub), nil
+	}
+
+	t := b.topic(req.Topic)
+	sub := &subscription{
+		name:        req.Name,
+		topic:       req.Topic,
+		ackDeadline: defaultAckDeadline,
+		next:        t.nextSeq,
+		inflight:    make(map[uint64]time.Time),
+		attempts:    make(map[uint64]uint32),
+		notify:      make(chan struct{}, 1),
+		maxAttempts: defaultMaxDeliveryAttempts,
+		held:        make(map[uint64]*nfa_pubsub_v1alpha.Event),
+		busy:        make(map[string]uint64),
+		waiting:     make(map[string][]uint64),
+		keyOf:       make(map[uint64]string),
+	}
+	if req.MaxDeliveryAttempts > 0 {
+		sub.maxAttempts = req.MaxDeliveryAttempts
+	}
+	if req.AckDeadlineSecs > 0 {
+		sub.ackDeadline = time.Duration(req.AckDeadlineSecs) * time.Second
+	}
+	if req.FromBeginning {
+		sub.next = t.firstSeq()
+	}
+	b.subs[req.Name] = sub
+	return b.describe(sub), nil
+}
+
+// DeleteSubscription implements the DeleteSubscription RPC
+func (b *Broker) DeleteSubscription(ctx context.Context, req *nfa_pubsub_v1alpha.DeleteSubscriptionRequest) (*nfa_pubsub_v1alpha.DeleteSubscriptionResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub, ok := b.subs[req.Name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "subscription %s not found", req.Name)
+	}
+	delete(b.subs, req.Name)
+	delete(b.topics[sub.topic].waiters, sub)
+	sub.wake()
+	return &nfa_pubsub_v1alpha.DeleteSubscriptionResponse{}, nil
+}
+
+// ListSubscriptions implements the ListSubscriptions RPC
+func (b *Broker) ListSubscriptions(ctx context.Context, req *nfa_pubsub_v1alpha.ListSubscriptionsRequest) (*nfa_pubsub_v1alpha.ListSubscriptionsResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	resp := &nfa_pubsub_v1alpha.ListSubscriptionsResponse{}
+	for _, sub := range b.subs {
+		if req.Topic == "" || sub.topic == req.Topic {
+			resp.Subscriptions = append(resp.Subscriptions, b.describe(sub))
+		}
+	}
+	sort.Slice(resp.Subscriptions, func(i, j int) bool {
+		return resp.Subscriptions[i].Name < resp.Subscriptions[j].Name
+	})
+	return resp, nil
+}
+
+// Subscribe implements the Subscribe RPC. Delivery resumes from the
+// subscription cursor, so events in flight when a previous stream broke are
+// delivered again.
+func (b *Broker) Subscribe(req *nfa_pubsub_v1alpha.SubscribeRequest, stream nfa_pubsub_v1alpha.PubSubService_SubscribeServer) error {
+	maxOutstanding := int(req.MaxOutstanding)
+	if maxOutstanding == 0 {
+		maxOutstanding = defaultMaxOutstanding
+	}
+
+	b.mu.Lock()
+	sub, ok := b.subs[req.Subscription]
+	if !ok {
+		b.mu.Unlock()
+		return status.Errorf(codes.NotFound, "subscription %s not found", req.Subscription)
+	}
+	// A new stream takes over everything the previous one left unacknowledged
+	for seq := range sub.inflight {
+		sub.inflight[seq] = time.Time{}
+	}
+	b.topics[sub.topic].waiters[sub] = struct{}{}
+	b.mu.Unlock()
+
+	defer func() {
+		b.mu.Lock()
+		if t, ok := b.topics[sub.topic]; ok {
+			delete(t.waiters, sub)
+		}
+		b.mu.Unlock()
+	}()
+
+	ticker := time.NewTicker(redeliveryCheck)
+	defer ticker.Stop()
+	for {
+		events, err := b.deliverable(sub, maxOutstanding)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-sub.notify:
+		case <-ticker.C:
+		}
+	}
+}
+
+// Ack implements the Ack RPC
+func (b *Broker) Ack(ctx context.Context, req *nfa_pubsub_v1alpha.AckRequest) (*nfa_pubsub_v1alpha.AckResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub, ok := b.subs[req.Subscription]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "subscription %s not found", req.Subscription)
+	}
+	for _, seq := range req.Sequences {
+		sub.forget(seq)
+	}
+	sub.wake()
+	return &nfa_pubsub_v1alpha.AckResponse{Cursor: sub.cursor()}, nil
+}
+
+// Seek implements the Seek RPC. Events from sequence onwards are delivered
+// again, including ones that were already acknowledged.
+func (b *Broker) Seek(ctx context.Context, req *nfa_pubsub_v1alpha.SeekRequest) (*nfa_pubsub_v1alpha.SeekResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub, ok := b.subs[req.Subscription]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "subscription %s not found", req.Subscription)
+	}
+	t := b.topics[sub.topic]
+	if req.Sequence < t.firstSeq() {
+		return nil, status.Errorf(codes.OutOfRange, "sequence %d is no longer retained, oldest is %d", req.Sequence, t.firstSeq())
+	}
+	if req.Sequence > t.nextSeq {
+		return nil, status.Errorf(codes.OutOfRange, "sequence %d has not been published, next is %d", req.Sequence, t.nextSeq)
+	}
+
+	sub.next = req.Sequence
+	sub.inflight = make(map[uint64]time.Time)
+	sub.attempts = make(map[uint64]uint32)
+	sub.held = make(map[uint64]*nfa_pubsub_v1alpha.Event)
+	sub.busy = make(map[string]uint64)
+	sub.waiting = make(map[string][]uint64)
+	sub.keyOf = make(map[uint64]string)
+	sub.wake()
+	return &nfa_pubsub_v1alpha.SeekResponse{Cursor: sub.cursor()}, nil
+}
+
+// deliverable returns expired in-flight events followed by new ones, up to
+// maxOutstanding unacknowledged events, and marks them in flight. Expired
+// events that used up their delivery attempts are dead-lettered instead.
+// On ordered topics an event whose ordering key already has an event in
+// flight waits until that one is acknowledged or dead-lettered, so a session
+// is only ever served by one delivery at a time.
+func (b *Broker) deliverable(sub *subscription, maxOutstanding int) ([]*nfa_pubsub_v1alpha.Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subs[sub.name] != sub {
+		return nil, status.Errorf(codes.NotFound, "subscription %s was deleted", sub.name)
+	}
+	t := b.topics[sub.topic]
+	now := time.Now()
+
+	var seqs []uint64
+	for seq, deadline := range sub.inflight {
+		if now.Before(deadline) {
+			continue
+		}
+		if sub.attempts[seq] >= sub.maxAttempts {
+			if event := sub.lookup(t, seq); event != nil {
+				sub.deadLetter(event)
+			}
+			sub.forget(seq)
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	for len(sub.inflight)+len(sub.keyOf)-len(sub.busy) < maxOutstanding && sub.next < t.nextSeq {
+		seq := sub.next
+		sub.next++
+		if event := t.event(seq); event != nil && event.OrderingKey != "" && !t.unordered {
+			key := event.OrderingKey
+			sub.keyOf[seq] = key
+			if _, busy := sub.busy[key]; busy {
+				sub.waiting[key] = append(sub.waiting[key], seq)
+				continue
+			}
+			sub.busy[key] = seq
+		}
+		seqs = append(seqs, seq)
+		sub.inflight[seq] = time.Time{}
+	}
+
+	events := make([]*nfa_pubsub_v1alpha.Event, 0, len(seqs))
+	for _, seq := range seqs {
+		event := sub.lookup(t, seq)
+		if event == nil {
+			// Trimmed by retention before it could be delivered
+			sub.forget(seq)
+			continue
+		}
+		sub.inflight[seq] = now.Add(sub.ackDeadline)
+		sub.attempts[seq]++
+		delivery := proto.Clone(event).(*nfa_pubsub_v1alpha.Event)
+		delivery.DeliveryAttempt = sub.attempts[seq]
+		events = append(events, delivery)
+	}
+	return events, nil
+}
+
+// topic returns the named topic, creating it if needed; mu must be held
+func (b *Broker) topic(name string) *topic {
+	t, ok := b.topics[name]
+	if !ok {
+		t = &topic{name: name, nextSeq: 1, waiters: make(map[*subscription]struct{})}
+		b.topics[name] = t
+	}
+	return t
+}
+
+// describe renders a subscription; mu must be held
+func (b *Broker) describe(sub *subscription) *nfa_pubsub_v1alpha.Subscription {
+	cursor := sub.cursor()
+	return &nfa_pubsub_v1alpha.Subscription{
+		Name:                sub.name,
+		Topic:               sub.topic,
+		AckDeadlineSecs:     uint32(sub.ackDeadline / time.Second),
+		Cursor:              cursor,
+		Backlog:             b.topics[sub.topic].nextSeq - cursor,
+		MaxDeliveryAttempts: sub.maxAttempts,
+		DeadLetters:         uint64(len(sub.deadLetters)),
+	}
+}
+
+// cursor is the lowest sequence number not yet acknowledged
+func (s *subscription) cursor() uint64 {
+	cursor := s.next
+	for seq := range s.inflight {
+		if seq < cursor {
+			cursor = seq
+		}
+	}
+	for seq := range s.keyOf {
+		if seq < cursor {
+			cursor = seq
+		}
+	}
+	return cursor
+}
+
+// lookup finds an event in the topic or, once trimmed, among requeued events
+func (s *subscription) lookup(t *topic, seq uint64) *nfa_pubsub_v1alpha.Event {
+	if event := t.event(seq); event != nil {
+		return event
+	}
+	return s.held[seq]
+}
+
+// forget drops all delivery state of an event and lets the next event with
+// the same ordering key through
+func (s *subscription) forget(seq uint64) {
+	delete(s.inflight, seq)
+	delete(s.attempts, seq)
+	delete(s.held, seq)
+
+	key, ok := s.keyOf[seq]
+	if !ok {
+		return
+	}
+	delete(s.keyOf, seq)
+	if s.busy[key] != seq {
+		// Acknowledged before it was delivered, e.g. by a consumer that seeked
+		queue := s.waiting[key]
+		for i, waiting := range queue {
+			if waiting == seq {
+				s.waiting[key] = append(queue[:i:i], queue[i+1:]...)
+				break
+			}
+		}
+		return
+	}
+	delete(s.busy, key)
+	if queue := s.waiting[key]; len(queue) > 0 {
+		s.busy[key] = queue[0]
+		s.inflight[queue[0]] = time.Time{} // due immediately
+		if len(queue) == 1 {
+			delete(s.waiting, key)
+		} else {
+			s.waiting[key] = queue[1:]
+		}
+		s.wake()
+	}
+}
+
+func (s *subscription) wake() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// trim drops the oldest event of t, warning about subscriptions that had not
+// acknowledged it. Losses are added up per subscription and warned about at
+// most every lossWarningInterval, since a subscription that fell behind loses
+// an event on every publish.
+func (b *Broker) trim(t *topic) {
+	dropped := t.events[0]
+	t.events = t.events[1:]
+	now := time.Now()
+	for _, sub := range b.subs {
+		if sub.topic != t.name || !sub.needs(dropped.Sequence) {
+			continue
+		}
+		sub.lost++
+		if now.Sub(sub.lostWarned) >= lossWarningInterval {
+			log.Printf("Subscription %s lost %d unacknowledged events of topic %s to retention, the latest %d",
+				sub.name, sub.lost, t.name, dropped.Sequence)
+			sub.lost = 0
+			sub.lostWarned = now
+		}
+	}
+}
+
+// needs reports whether an event of the topic is still to be delivered or
+// acknowledged, other than from the requeued events the subscription holds
+func (s *subscription) needs(seq uint64) bool {
+	if seq >= s.next {
+		return true
+	}
+	if _, held := s.held[seq]; held {
+		return false
+	}
+	_, inflight := s.inflight[seq]
+	_, queued := s.keyOf[seq]
+	return inflight || queued
+}
+
+func (t *topic) firstSeq() uint64 {
+	if len(t.events) == 0 {
+		return t.nextSeq
+	}
+	return t.events[0].Sequence
+}
+
+func (t *topic) event(seq uint64) *nfa_pubsub_v1alpha.Event {
+	first := t.firstSeq()
+	if seq < first || seq >= t.nextSeq {
+		return nil
+	}
+	return t.events[seq-first]
+}
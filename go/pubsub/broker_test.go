@@ -0,0 +1,251 @@
+package pubsub
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	nfa_pubsub_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/pubsub/v1alpha"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const testTopic = "nfa.home.door_opened"
+
+// newTestBroker creates a broker with a subscription "s" of testTopic
+func newTestBroker(t *testing.T, retention int, maxAttempts uint32) (*Broker, *subscription) {
+	t.Helper()
+	b := NewBroker(retention)
+	if _, err := b.CreateSubscription(context.Background(), &nfa_pubsub_v1alpha.CreateSubscriptionRequest{
+		Name: "s", Topic: testTopic, MaxDeliveryAttempts: maxAttempts,
+	}); err != nil {
+		t.Fatalf("CreateSubscription() error = %v", err)
+	}
+	return b, b.subs["s"]
+}
+
+// publishKeys publishes an event per ordering key, empty for none
+func publishKeys(t *testing.T, b *Broker, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		if _, err := b.Publish(context.Background(), &nfa_pubsub_v1alpha.PublishRequest{Topic: testTopic, OrderingKey: key}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+}
+
+// deliver returns the sequence numbers and delivery attempts of the events
+// deliverable on sub
+func deliver(t *testing.T, b *Broker, sub *subscription) ([]uint64, []uint32) {
+	t.Helper()
+	events, err := b.deliverable(sub, 100)
+	if err != nil {
+		t.Fatalf("deliverable() error = %v", err)
+	}
+	var seqs []uint64
+	var attempts []uint32
+	for _, event := range events {
+		seqs = append(seqs, event.Sequence)
+		attempts = append(attempts, event.DeliveryAttempt)
+	}
+	return seqs, attempts
+}
+
+func ack(t *testing.T, b *Broker, seqs ...uint64) uint64 {
+	t.Helper()
+	resp, err := b.Ack(context.Background(), &nfa_pubsub_v1alpha.AckRequest{Subscription: "s", Sequences: seqs})
+	if err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+	return resp.Cursor
+}
+
+func TestOrderingKeys(t *testing.T) {
+	b, sub := newTestBroker(t, 0, 0)
+	publishKeys(t, b, "k1", "k1", "k2", "", "k1")
+
+	// One event per key is in flight at a time
+	if seqs, _ := deliver(t, b, sub); !slices.Equal(seqs, []uint64{1, 3, 4}) {
+		t.Fatalf("deliverable() = %v, want 1, 3 and 4", seqs)
+	}
+	if sub.busy["k1"] != 1 || !slices.Equal(sub.waiting["k1"], []uint64{2, 5}) || sub.keyOf[5] != "k1" {
+		t.Errorf("k1 busy with %d, waiting %v, want 1 with 2 and 5 waiting", sub.busy["k1"], sub.waiting["k1"])
+	}
+	if seqs, _ := deliver(t, b, sub); len(seqs) != 0 {
+		t.Errorf("deliverable() again = %v, want nothing until an ack", seqs)
+	}
+
+	// An ack lets the next event of its key through, and the cursor stays at
+	// the oldest unacknowledged event
+	if cursor := ack(t, b, 1, 3, 4); cursor != 2 {
+		t.Errorf("Ack() cursor = %d, want 2", cursor)
+	}
+	if seqs, _ := deliver(t, b, sub); !slices.Equal(seqs, []uint64{2}) {
+		t.Errorf("deliverable() after the ack = %v, want 2", seqs)
+	}
+
+	// An event acknowledged while it waits, e.g. after a seek, leaves the queue
+	ack(t, b, 5)
+	if _, queued := sub.keyOf[5]; queued || !slices.Equal(sub.waiting["k1"], []uint64{}) {
+		t.Errorf("k1 waiting %v after acking 5, want it gone", sub.waiting["k1"])
+	}
+	if cursor := ack(t, b, 2); cursor != 6 {
+		t.Errorf("Ack() cursor = %d, want 6", cursor)
+	}
+	if len(sub.busy) != 0 || len(sub.keyOf) != 0 {
+		t.Errorf("busy %v, keys %v after acking everything, want none", sub.busy, sub.keyOf)
+	}
+}
+
+func TestOrderingKeysUnordered(t *testing.T) {
+	b, sub := newTestBroker(t, 0, 0)
+	if _, err := b.ConfigureTopic(context.Background(), &nfa_pubsub_v1alpha.ConfigureTopicRequest{
+		Topic: testTopic, Ordering: nfa_pubsub_v1alpha.DeliveryOrdering_DELIVERY_ORDERING_UNORDERED,
+	}); err != nil {
+		t.Fatalf("ConfigureTopic() error = %v", err)
+	}
+	publishKeys(t, b, "k1", "k1", "k1")
+	if seqs, _ := deliver(t, b, sub); !slices.Equal(seqs, []uint64{1, 2, 3}) {
+		t.Errorf("deliverable() on an unordered topic = %v, want every event", seqs)
+	}
+}
+
+func TestRedeliveryAndDeadLetters(t *testing.T) {
+	b, sub := newTestBroker(t, 0, 2)
+	ctx := context.Background()
+	publishKeys(t, b, "k1", "k1", "")
+
+	deliver(t, b, sub)
+	ack(t, b, 3)
+	if seqs, _ := deliver(t, b, sub); len(seqs) != 0 {
+		t.Errorf("deliverable() before the deadline = %v, want nothing", seqs)
+	}
+	expire(b, sub)
+	if seqs, attempts := deliver(t, b, sub); !slices.Equal(seqs, []uint64{1}) || !slices.Equal(attempts, []uint32{2}) {
+		t.Errorf("deliverable() after the deadline = %v, attempts %v, want 1 on its second attempt", seqs, attempts)
+	}
+
+	// Out of attempts, the event is dead-lettered and the next of its key is
+	// delivered, by the same or the next pass
+	expire(b, sub)
+	seqs, attempts := deliver(t, b, sub)
+	next, nextAttempts := deliver(t, b, sub)
+	seqs, attempts = append(seqs, next...), append(attempts, nextAttempts...)
+	if !slices.Equal(seqs, []uint64{2}) || !slices.Equal(attempts, []uint32{1}) {
+		t.Errorf("deliverable() after the last attempt = %v, attempts %v, want 2 on its first", seqs, attempts)
+	}
+	dead, err := b.ListDeadLetters(ctx, &nfa_pubsub_v1alpha.ListDeadLettersRequest{Subscription: "s"})
+	if err != nil || len(dead.DeadLetters) != 1 {
+		t.Fatalf("ListDeadLetters() = %v, %v, want one dead letter", dead, err)
+	}
+	if dl := dead.DeadLetters[0]; dl.Id != "s/1" || dl.Event.Sequence != 1 || !strings.Contains(dl.Reason, "2 delivery attempts") {
+		t.Errorf("dead letter = %v, want event 1 after 2 attempts", dl)
+	}
+	if cursor := ack(t, b, 2); cursor != 4 {
+		t.Errorf("Ack() cursor = %d, want 4 past the dead letter", cursor)
+	}
+
+	// A requeued dead letter starts over
+	if _, err := b.RequeueDeadLetters(ctx, &nfa_pubsub_v1alpha.RequeueDeadLettersRequest{Subscription: "s"}); err != nil {
+		t.Fatalf("RequeueDeadLetters() error = %v", err)
+	}
+	if seqs, attempts := deliver(t, b, sub); !slices.Equal(seqs, []uint64{1}) || !slices.Equal(attempts, []uint32{1}) {
+		t.Errorf("deliverable() after the requeue = %v, attempts %v, want 1 on its first", seqs, attempts)
+	}
+}
+
+func TestSeek(t *testing.T) {
+	b, sub := newTestBroker(t, 3, 0)
+	ctx := context.Background()
+	publishKeys(t, b, "k1", "k1", "", "")
+	deliver(t, b, sub)
+
+	seek := func(seq uint64) error {
+		_, err := b.Seek(ctx, &nfa_pubsub_v1alpha.SeekRequest{Subscription: "s", Sequence: seq})
+		return err
+	}
+	// Event 1 was trimmed, 5 is next
+	for _, seq := range []uint64{1, 6} {
+		if err := seek(seq); status.Code(err) != codes.OutOfRange {
+			t.Errorf("Seek(%d) error = %v, want OutOfRange", seq, err)
+		}
+	}
+
+	ack(t, b, 2, 3, 4)
+	if err := seek(2); err != nil {
+		t.Fatalf("Seek(2) error = %v", err)
+	}
+	if seqs, attempts := deliver(t, b, sub); !slices.Equal(seqs, []uint64{2, 3, 4}) || !slices.Equal(attempts, []uint32{1, 1, 1}) {
+		t.Errorf("deliverable() after Seek(2) = %v, attempts %v, want 2, 3 and 4 on their first attempt", seqs, attempts)
+	}
+	if err := seek(5); err != nil {
+		t.Fatalf("Seek(5) error = %v", err)
+	}
+	if cursor := ack(t, b); cursor != 5 || len(sub.inflight) != 0 {
+		t.Errorf("cursor %d with %d in flight after Seek(5), want 5 and none", cursor, len(sub.inflight))
+	}
+}
+
+func TestRetentionTrimsInflight(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	b, sub := newTestBroker(t, 2, 0)
+	publishKeys(t, b, "", "")
+	deliver(t, b, sub)
+	ack(t, b, 1)
+	// Trims the acknowledged event 1 and event 2 in flight
+	publishKeys(t, b, "", "")
+	expire(b, sub)
+	if seqs, _ := deliver(t, b, sub); !slices.Equal(seqs, []uint64{3, 4}) {
+		t.Errorf("deliverable() = %v, want 3 and 4 without the trimmed event", seqs)
+	}
+	if cursor := ack(t, b, 3, 4); cursor != 5 {
+		t.Errorf("Ack() cursor = %d, want 5", cursor)
+	}
+	if got := strings.Count(logs.String(), "lost"); got != 1 || !strings.Contains(logs.String(), "lost 1 unacknowledged events of topic "+testTopic+" to retention, the latest 2") {
+		t.Errorf("logs = %q, want one warning about event 2", logs.String())
+	}
+
+	// A subscription that fell behind is warned about once per interval
+	logs.Reset()
+	publishKeys(t, b, "", "", "", "", "", "")
+	if logs.Len() != 0 {
+		t.Errorf("logs = %q, want no warning within the interval", logs.String())
+	}
+	sub.lostWarned = time.Now().Add(-lossWarningInterval)
+	publishKeys(t, b, "")
+	if !strings.Contains(logs.String(), "lost 5 unacknowledged events") {
+		t.Errorf("logs = %q, want the 5 events lost since the last warning", logs.String())
+	}
+}
+
+func TestCompact(t *testing.T) {
+	b, sub := newTestBroker(t, 100, 0)
+	publishKeys(t, b, "", "", "", "", "")
+	// Events 1 and 2 were published an hour ago
+	now := time.Now()
+	for _, event := range b.topics[testTopic].events[:2] {
+		event.PublishTime = timestamppb.New(now.Add(-time.Hour))
+	}
+	if usage := b.Usage(); usage.Entries != 5 || !usage.Oldest.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("Usage() = %+v, want 5 events from an hour ago", usage)
+	}
+	if removed := b.Compact(now.Add(-time.Minute), 0); removed != 2 {
+		t.Errorf("Compact() by age removed %d, want 2", removed)
+	}
+	size := b.Usage().Bytes / 3
+	if removed := b.Compact(time.Time{}, 2*size); removed != 1 {
+		t.Errorf("Compact() by size removed %d, want 1", removed)
+	}
+	if seqs, _ := deliver(t, b, sub); !slices.Equal(seqs, []uint64{4, 5}) {
+		t.Errorf("deliverable() = %v, want the 2 latest events", seqs)
+	}
+}
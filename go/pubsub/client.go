@@ -0,0 +1,104 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	nfa_pubsub_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/pubsub/v1alpha"
+	"google.golang.org/grpc"
+)
+
+// Handler processes one delivered event. The event is acknowledged when the
+// handler returns nil and redelivered after the ack deadline otherwise.
+type Handler func(ctx context.Context, event *nfa_pubsub_v1alpha.Event) error
+
+// Client publishes events and consumes subscriptions on a broker
+type Client struct {
+	client nfa_pubsub_v1alpha.PubSubServiceClient
+}
+
+// NewClient creates a pub/sub client on an existing broker connection
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{
+		client: nfa_pubsub_v1alpha.NewPubSubServiceClient(cc),
+	}
+}
+
+// Publish appends an event to a topic and returns its sequence number
+func (c *Client) Publish(ctx context.Context, topic string, payload []byte, attributes map[string]string) (uint64, error) {
+	return c.PublishOrdered(ctx, topic, "", payload, attributes)
+}
+
+// PublishOrdered appends an event that is delivered after every earlier
+// event with the same ordering key, typically a session ID
+func (c *Client) PublishOrdered(ctx context.Context, topic, orderingKey string, payload []byte, attributes map[string]string) (uint64, error) {
+	resp, err := c.client.Publish(ctx, &nfa_pubsub_v1alpha.PublishRequest{
+		Topic:       topic,
+		Payload:     payload,
+		Attributes:  attributes,
+		OrderingKey: orderingKey,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to publish to %s: %w", topic, err)
+	}
+	return resp.Sequence, nil
+}
+
+// CreateSubscription creates a durable subscription, or returns the existing
+// one with the same name
+func (c *Client) CreateSubscription(ctx context.Context, req *nfa_pubsub_v1alpha.CreateSubscriptionRequest) (*nfa_pubsub_v1alpha.Subscription, error) {
+	sub, err := c.client.CreateSubscription(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscription %s: %w", req.Name, err)
+	}
+	return sub, nil
+}
+
+// Seek rewinds or advances a subscription to replay from sequence
+func (c *Client) Seek(ctx context.Context, subscription string, sequence uint64) error {
+	_, err := c.client.Seek(ctx, &nfa_pubsub_v1alpha.SeekRequest{
+		Subscription: subscription,
+		Sequence:     sequence,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to seek subscription %s: %w", subscription, err)
+	}
+	return nil
+}
+
+// Subscribe streams events of a subscription to handler until the stream
+// ends or ctx is cancelled. Calling it again after a failure resumes from the
+// subscription's cursor.
+func (c *Client) Subscribe(ctx context.Context, subscription string, handler Handler) error {
+	stream, err := c.client.Subscribe(ctx, &nfa_pubsub_v1alpha.SubscribeRequest{
+		Subscription: subscription,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", subscription, err)
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("subscription %s failed: %w", subscription, err)
+		}
+
+		if err := handler(ctx, event); err != nil {
+			continue
+		}
+		_, err = c.client.Ack(ctx, &nfa_pubsub_v1alpha.AckRequest{
+			Subscription: subscription,
+			Sequences:    []uint64{event.Sequence},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to ack event %d on %s: %w", event.Sequence, subscription, err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	nfa_pubsub_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/pubsub/v1alpha"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxDeadLetters bounds the dead-letter store of each subscription
+const maxDeadLetters = 10000
+
+// ListDeadLetters implements the ListDeadLetters RPC
+func (b *Broker) ListDeadLetters(ctx context.Context, req *nfa_pubsub_v1alpha.ListDeadLettersRequest) (*nfa_pubsub_v1alpha.ListDeadLettersResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var subs []*subscription
+	if req.Subscription != "" {
+		sub, ok := b.subs[req.Subscription]
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "subscription %s not found", req.Subscription)
+		}
+		subs = append(subs, sub)
+	} else {
+		for _, sub := range b.subs {
+			subs = append(subs, sub)
+		}
+		sort.Slice(subs, func(i, j int) bool { return subs[i].name < subs[j].name })
+	}
+
+	resp := &nfa_pubsub_v1alpha.ListDeadLettersResponse{}
+	for _, sub := range subs {
+		resp.DeadLetters = append(resp.DeadLetters, sub.deadLetters...)
+	}
+	return resp, nil
+}
+
+// RequeueDeadLetters implements the RequeueDeadLetters RPC. Requeued events
+// start over with a full set of delivery attempts.
+func (b *Broker) RequeueDeadLetters(ctx context.Context, req *nfa_pubsub_v1alpha.RequeueDeadLettersRequest) (*nfa_pubsub_v1alpha.RequeueDeadLettersResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub, ok := b.subs[req.Subscription]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "subscription %s not found", req.Subscription)
+	}
+	requeued := sub.takeDeadLetters(req.Ids)
+	for _, dl := range requeued {
+		seq := dl.Event.Sequence
+		sub.held[seq] = dl.Event
+		sub.inflight[seq] = time.Time{} // due immediately
+		sub.attempts[seq] = 0
+	}
+	if len(requeued) > 0 {
+		log.Printf("Requeued %d dead letters on subscription %s", len(requeued), sub.name)
+		sub.wake()
+	}
+	return &nfa_pubsub_v1alpha.RequeueDeadLettersResponse{Requeued: uint32(len(requeued))}, nil
+}
+
+// PurgeDeadLetters implements the PurgeDeadLetters RPC
+func (b *Broker) PurgeDeadLetters(ctx context.Context, req *nfa_pubsub_v1alpha.PurgeDeadLettersRequest) (*nfa_pubsub_v1alpha.PurgeDeadLettersResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub, ok := b.subs[req.Subscription]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "subscription %s not found", req.Subscription)
+	}
+	purged := sub.takeDeadLetters(req.Ids)
+	return &nfa_pubsub_v1alpha.PurgeDeadLettersResponse{Purged: uint32(len(purged))}, nil
+}
+
+// deadLetter moves an event that exhausted its delivery attempts to the
+// dead-letter store; broker mu must be held
+func (s *subscription) deadLetter(event *nfa_pubsub_v1alpha.Event) {
+	dl := &nfa_pubsub_v1alpha.DeadLetter{
+		Id:               fmt.Sprintf("%s/%d", s.name, event.Sequence),
+		Subscription:     s.name,
+		Event:            event,
+		Reason:           fmt.Sprintf("not acknowledged after %d delivery attempts", s.attempts[event.Sequence]),
+		DeadLetteredTime: timestamppb.Now(),
+	}
+	s.deadLetters = append(s.deadLetters, dl)
+	if len(s.deadLetters) > maxDeadLetters {
+		log.Printf("Dead-letter store of subscription %s is full, dropping %s", s.name, s.deadLetters[0].Id)
+		s.deadLetters = s.deadLetters[1:]
+	}
+	log.Printf("Dead-lettered event %d of topic %s on subscription %s", event.Sequence, event.Topic, s.name)
+}
+
+// takeDeadLetters removes and returns the dead letters with the given IDs,
+// or all of them when ids is empty
+func (s *subscription) takeDeadLetters(ids []string) []*nfa_pubsub_v1alpha.DeadLetter {
+	if len(ids) == 0 {
+		taken := s.deadLetters
+		s.deadLetters = nil
+		return taken
+	}
+
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	var taken, kept []*nfa_pubsub_v1alpha.DeadLetter
+	for _, dl := range s.deadLetters {
+		if wanted[dl.Id] {
+			taken = append(taken, dl)
+		} else {
+			kept = append(kept, dl)
+		}
+	}
+	s.deadLetters = kept
+	return taken
+}
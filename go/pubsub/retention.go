@@ -0,0 +1,63 @@
+package pubsub
+
+import (
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/retention"
+	"google.golang.org/protobuf/proto"
+)
+
+var _ retention.Store = (*Broker)(nil)
+
+// Usage implements retention.Store over the events retained by all topics
+func (b *Broker) Usage() retention.Usage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var usage retention.Usage
+	for _, t := range b.topics {
+		for _, event := range t.events {
+			usage.Entries++
+			usage.Bytes += int64(proto.Size(event))
+		}
+		if len(t.events) > 0 {
+			if oldest := t.events[0].PublishTime.AsTime(); usage.Oldest.IsZero() || oldest.Before(usage.Oldest) {
+				usage.Oldest = oldest
+			}
+		}
+	}
+	return usage
+}
+
+// Compact implements retention.Store. Events are trimmed oldest first across
+// all topics, like the per-topic count retention of NewBroker; subscriptions
+// that had not acknowledged a trimmed event are warned about.
+func (b *Broker) Compact(before time.Time, maxBytes int64) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	removed := 0
+	var size int64
+	for _, t := range b.topics {
+		for len(t.events) > 0 && !before.IsZero() && t.events[0].PublishTime.AsTime().Before(before) {
+			b.trim(t)
+			removed++
+		}
+		for _, event := range t.events {
+			size += int64(proto.Size(event))
+		}
+	}
+	for maxBytes > 0 && size > maxBytes {
+		var oldest *topic
+		for _, t := range b.topics {
+			if len(t.events) > 0 && (oldest == nil || t.events[0].PublishTime.AsTime().Before(oldest.events[0].PublishTime.AsTime())) {
+				oldest = t
+			}
+		}
+		if oldest == nil {
+			break
+		}
+		size -= int64(proto.Size(oldest.events[0]))
+		b.trim(oldest)
+		removed++
+	}
+	return removed
+}
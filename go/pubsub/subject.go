@@ -0,0 +1,90 @@
+package pubsub
+
+import (
+	"fmt"
+
+	nfa_pubsub_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/pubsub/v1alpha"
+	"google.golang.org/protobuf/proto"
+)
+
+// Attributes identifying the user an event is about, and marking events
+// whose content was erased on the user's request
+const (
+	AttributeUserID = "nfa.user_id"
+	AttributeErased = "nfa.erased"
+)
+
+// EventID identifies an event across topics as "<topic>/<sequence>"
+func EventID(event *nfa_pubsub_v1alpha.Event) string {
+	return fmt.Sprintf("%s/%d", event.Topic, event.Sequence)
+}
+
+// SubjectEvents returns copies of the retained events about a user, including
+// those held by subscriptions and their dead-letter stores, by EventID
+func (b *Broker) SubjectEvents(userID string) map[string]*nfa_pubsub_v1alpha.Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	events := make(map[string]*nfa_pubsub_v1alpha.Event)
+	b.eachEvent(func(event *nfa_pubsub_v1alpha.Event) *nfa_pubsub_v1alpha.Event {
+		if event.Attributes[AttributeUserID] == userID {
+			events[EventID(event)] = proto.Clone(event).(*nfa_pubsub_v1alpha.Event)
+		}
+		return event
+	})
+	return events
+}
+
+// EraseSubject replaces every retained event about a user with a tombstone
+// that keeps its topic, sequence and publish time but no payload or
+// attributes, so subscription cursors stay valid. It returns the IDs of the
+// erased events.
+func (b *Broker) EraseSubject(userID string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	erased := make(map[string]bool)
+	b.eachEvent(func(event *nfa_pubsub_v1alpha.Event) *nfa_pubsub_v1alpha.Event {
+		if event.Attributes[AttributeUserID] != userID {
+			return event
+		}
+		erased[EventID(event)] = true
+		return &nfa_pubsub_v1alpha.Event{
+			Topic:       event.Topic,
+			Sequence:    event.Sequence,
+			PublishTime: event.PublishTime,
+			Attributes:  map[string]string{AttributeErased: "true"},
+		}
+	})
+	ids := make([]string, 0, len(erased))
+	for id := range erased {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// eachEvent calls fn with every stored event and stores the event it
+// returns in its place; b.mu must be held
+func (b *Broker) eachEvent(fn func(*nfa_pubsub_v1alpha.Event) *nfa_pubsub_v1alpha.Event) {
+	for _, t := range b.topics {
+		for i, event := range t.events {
+			t.events[i] = fn(event)
+		}
+	}
+	for _, sub := range b.subs {
+		for seq, event := range sub.held {
+			sub.held[seq] = fn(event)
+		}
+		for i, dl := range sub.deadLetters {
+			if dl.Event == nil {
+				continue
+			}
+			// Dead letters may be referenced by an earlier listing, replace rather than modify them
+			if event := fn(dl.Event); event != dl.Event {
+				dl = proto.Clone(dl).(*nfa_pubsub_v1alpha.DeadLetter)
+				dl.Event = event
+				sub.deadLetters[i] = dl
+			}
+		}
+	}
+}
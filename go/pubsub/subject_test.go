@@ -0,0 +1,104 @@
+package pubsub
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	nfa_pubsub_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/pubsub/v1alpha"
+)
+
+func TestEraseSubject(t *testing.T) {
+	ctx := context.Background()
+	b := NewBroker(3)
+	publish := func(topic, user, payload string) {
+		t.Helper()
+		_, err := b.Publish(ctx, &nfa_pubsub_v1alpha.PublishRequest{
+			Topic:      topic,
+			Payload:    []byte(payload),
+			Attributes: map[string]string{AttributeUserID: user},
+		})
+		if err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	if _, err := b.CreateSubscription(ctx, &nfa_pubsub_v1alpha.CreateSubscriptionRequest{
+		Name: "audit", Topic: "nfa.home.door_opened", MaxDeliveryAttempts: 1,
+	}); err != nil {
+		t.Fatalf("CreateSubscription() error = %v", err)
+	}
+	publish("nfa.home.door_opened", "u1", "front door")
+	publish("nfa.home.door_opened", "u1", "back door")
+	publish("nfa.home.door_opened", "u2", "garage")
+	publish("nfa.home.light", "u1", "kitchen")
+
+	// Both events of u1 exhaust their attempts; one is requeued and held by
+	// the subscription once retention trimmed it from the topic
+	sub := b.subs["audit"]
+	if events, _ := b.deliverable(sub, 10); len(events) != 3 {
+		t.Fatalf("deliverable() = %d events, want 3", len(events))
+	}
+	b.Ack(ctx, &nfa_pubsub_v1alpha.AckRequest{Subscription: "audit", Sequences: []uint64{3}})
+	expire(b, sub)
+	b.deliverable(sub, 0)
+	b.RequeueDeadLetters(ctx, &nfa_pubsub_v1alpha.RequeueDeadLettersRequest{Subscription: "audit", Ids: []string{"audit/1"}})
+	for i := 0; i < 3; i++ {
+		publish("nfa.home.door_opened", "u2", "garage")
+	}
+	listed, _ := b.ListDeadLetters(ctx, &nfa_pubsub_v1alpha.ListDeadLettersRequest{Subscription: "audit"})
+
+	want := []string{"nfa.home.door_opened/1", "nfa.home.door_opened/2", "nfa.home.light/1"}
+	if got := keys(b.SubjectEvents("u1")); !slices.Equal(got, want) {
+		t.Fatalf("SubjectEvents(u1) = %v, want %v", got, want)
+	}
+	erased := b.EraseSubject("u1")
+	sort.Strings(erased)
+	if !slices.Equal(erased, want) {
+		t.Errorf("EraseSubject(u1) = %v, want %v", erased, want)
+	}
+	if got := b.SubjectEvents("u1"); len(got) != 0 {
+		t.Errorf("SubjectEvents(u1) after EraseSubject = %v, want none", keys(got))
+	}
+	if got := b.SubjectEvents("u2"); len(got) != 3 {
+		t.Errorf("SubjectEvents(u2) after EraseSubject(u1) = %v, want the 3 retained events", keys(got))
+	}
+
+	// Tombstones keep their place in the held events, dead letters and topics
+	tombstone := func(name string, event *nfa_pubsub_v1alpha.Event) {
+		t.Helper()
+		if event == nil || len(event.Payload) != 0 || len(event.Attributes) != 1 || event.Attributes[AttributeErased] != "true" {
+			t.Errorf("%s = %v, want a tombstone", name, event)
+		}
+	}
+	tombstone("held event", sub.held[1])
+	dead, _ := b.ListDeadLetters(ctx, &nfa_pubsub_v1alpha.ListDeadLettersRequest{Subscription: "audit"})
+	tombstone("dead letter", dead.DeadLetters[0].Event)
+	tombstone("topic event", b.topics["nfa.home.light"].event(1))
+	if string(listed.DeadLetters[0].Event.Payload) != "back door" {
+		t.Errorf("dead letter listed before EraseSubject = %v, want it unchanged", listed.DeadLetters[0].Event)
+	}
+	events, _ := b.deliverable(sub, 10)
+	if len(events) != 4 || events[0].Sequence != 1 || len(events[0].Payload) != 0 {
+		t.Errorf("deliverable() after EraseSubject = %v, want the tombstone of the held event and 3 new events", events)
+	}
+}
+
+// expire makes every event in flight on sub due for redelivery
+func expire(b *Broker, sub *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for seq := range sub.inflight {
+		sub.inflight[seq] = time.Now().Add(-time.Second)
+	}
+}
+
+func keys(events map[string]*nfa_pubsub_v1alpha.Event) []string {
+	ids := make([]string, 0, len(events))
+	for id := range events {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
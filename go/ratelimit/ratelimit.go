@@ -0,0 +1,128 @@
+// Package ratelimit bounds the rate of the intent matches a broker serves,
+// overall and per action, with token buckets whose limits change at runtime,
+// e.g. on a configuration reload
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Limits are the rates a Limiter allows, in requests per second; a rate of 0
+// is unlimited
+type Limits struct {
+	RequestsPerSecond float64
+	// Burst is how many requests may exceed RequestsPerSecond at once; 0
+	// allows a second's worth
+	Burst int
+	// PerAction bounds the matches of single actions on top of
+	// RequestsPerSecond, each bursting a second's worth
+	PerAction map[string]float64
+}
+
+// bucket holds the tokens of one rate, refilled as time passes
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens earned since the last refill at rate, up to burst
+func (b *bucket) refill(now time.Time, rate, burst float64) {
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * rate
+	} else {
+		b.tokens = burst
+	}
+	b.tokens = math.Min(b.tokens, burst)
+	b.last = now
+}
+
+// Limiter allows requests as long as the tokens of the overall rate and of
+// their action last. Its methods are safe for concurrent use.
+type Limiter struct {
+	mu        sync.Mutex
+	limits    Limits
+	all       bucket
+	perAction map[string]*bucket
+	now       func() time.Time
+}
+
+// New returns a Limiter allowing limits
+func New(limits Limits) *Limiter {
+	l := &Limiter{now: time.Now}
+	l.Set(limits)
+	return l
+}
+
+// Set replaces the limits. Buckets keep their tokens, up to the new burst.
+func (l *Limiter) Set(limits Limits) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limits = limits
+	perAction := make(map[string]*bucket, len(limits.PerAction))
+	for action := range limits.PerAction {
+		if b, ok := l.perAction[action]; ok {
+			perAction[action] = b
+		} else {
+			perAction[action] = &bucket{}
+		}
+	}
+	l.perAction = perAction
+}
+
+// Limits returns the current limits
+func (l *Limiter) Limits() Limits {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limits
+}
+
+// Allow takes a token for a match of action, reporting whether one was left
+func (l *Limiter) Allow(action string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	limited := func(b *bucket, rate float64, burst int) bool {
+		if rate <= 0 {
+			return false
+		}
+		if burst <= 0 {
+			burst = int(math.Max(math.Ceil(rate), 1))
+		}
+		b.refill(now, rate, float64(burst))
+		return b.tokens < 1
+	}
+	perAction, rate := l.perAction[action], l.limits.PerAction[action]
+	if limited(&l.all, l.limits.RequestsPerSecond, l.limits.Burst) || perAction != nil && limited(perAction, rate, 0) {
+		return false
+	}
+	if l.limits.RequestsPerSecond > 0 {
+		l.all.tokens--
+	}
+	if perAction != nil && rate > 0 {
+		perAction.tokens--
+	}
+	return true
+}
+
+// UnaryServerInterceptor fails the intent matches exceeding the limits with
+// RESOURCE_EXHAUSTED. Install it on the broker with broker.WithServerOptions.
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		match, ok := req.(*nfa_broker_v1alpha.IntentMatchRequest)
+		if !ok || info.FullMethod != nfa_broker_v1alpha.IntentBroker_MatchIntent_FullMethodName {
+			return handler(ctx, req)
+		}
+		if action := match.GetPattern().GetPattern().GetAction(); !l.Allow(action) {
+			return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded for intent matches of %q", action)
+		}
+		return handler(ctx, req)
+	}
+}
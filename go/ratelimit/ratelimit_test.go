@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// allowed counts the matches of action l allows out of n
+func allowed(l *Limiter, action string, n int) int {
+	count := 0
+	for i := 0; i < n; i++ {
+		if l.Allow(action) {
+			count++
+		}
+	}
+	return count
+}
+
+func TestAllow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := New(Limits{RequestsPerSecond: 10, Burst: 5, PerAction: map[string]float64{"translate": 2}})
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if got := allowed(l, "translate", 10); got != 2 {
+			t.Errorf("Allow() allowed %d of translate, want a second's worth of its rate of 2", got)
+		}
+		if got := allowed(l, "summarize", 10); got != 3 {
+			t.Errorf("Allow() allowed %d of summarize, want the 3 tokens left of the burst of 5", got)
+		}
+		now = now.Add(time.Second)
+	}
+}
+
+func TestAllowUnlimited(t *testing.T) {
+	l := New(Limits{})
+	if got := allowed(l, "translate", 1000); got != 1000 {
+		t.Errorf("Allow() allowed %d of 1000 without limits, want all", got)
+	}
+}
+
+func TestSet(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := New(Limits{RequestsPerSecond: 1, Burst: 1})
+	l.now = func() time.Time { return now }
+	if got := allowed(l, "translate", 3); got != 1 {
+		t.Fatalf("Allow() allowed %d, want 1", got)
+	}
+
+	l.Set(Limits{RequestsPerSecond: 100, Burst: 50})
+	now = now.Add(time.Second)
+	if got := allowed(l, "translate", 100); got != 50 {
+		t.Errorf("Allow() allowed %d after raising the limits, want the new burst of 50", got)
+	}
+	l.Set(Limits{})
+	if got := allowed(l, "translate", 100); got != 100 {
+		t.Errorf("Allow() allowed %d after lifting the limits, want all", got)
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	l := New(Limits{RequestsPerSecond: 1000, Burst: 1000, PerAction: map[string]float64{"translate": 0.001}})
+	b := broker.NewEmbedded(broker.WithServerOptions(grpc.UnaryInterceptor(l.UnaryServerInterceptor())))
+	defer b.Close()
+	conn, err := b.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := nfa_broker_v1alpha.NewIntentBrokerClient(conn)
+	match := func(action string) error {
+		_, err := client.MatchIntent(context.Background(), &nfa_broker_v1alpha.IntentMatchRequest{
+			Pattern: &nfa_intent_v1alpha.IntentPattern{Pattern: &nfa_intent_v1alpha.IntentPattern_Pattern{Action: action}},
+		})
+		return err
+	}
+
+	if err := match("translate"); err != nil {
+		t.Fatalf("MatchIntent() error = %v", err)
+	}
+	if err := match("translate"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("MatchIntent() over the limit error = %v, want RESOURCE_EXHAUSTED", err)
+	}
+	if err := match("summarize"); err != nil {
+		t.Errorf("MatchIntent() of another action error = %v, want nil", err)
+	}
+	// Heartbeats and registrations are never limited
+	if _, err := client.Heartbeat(context.Background(), &nfa_broker_v1alpha.HeartbeatRequest{ServiceId: "unknown"}); status.Code(err) == codes.ResourceExhausted {
+		t.Errorf("Heartbeat() error = %v, want it not limited", err)
+	}
+}
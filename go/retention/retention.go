@@ -0,0 +1,189 @@
+// Package retention bounds the data a long-running broker keeps, such as
+// event logs, analytics samples and audit trails, by age and size. A Manager
+// compacts every store it knows in the background and reports their usage,
+// so brokers on edge hardware do not exhaust memory or disk.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/logging"
+)
+
+// DefaultInterval is how often Run compacts when given no interval
+const DefaultInterval = time.Minute
+
+// Names of the stores a broker retains
+const (
+	Events    = "events"
+	Analytics = "analytics"
+	Audit     = "audit"
+)
+
+// Policy bounds the data of a store; zero values mean no bound
+type Policy struct {
+	// MaxAgeSecs removes entries older than this many seconds
+	MaxAgeSecs int `toml:"max_age_secs,omitempty"`
+	// MaxBytes removes the oldest entries until the store holds at most this many bytes
+	MaxBytes int64 `toml:"max_bytes,omitempty"`
+}
+
+// MaxAge returns the age bound as a duration
+func (p Policy) MaxAge() time.Duration {
+	return time.Duration(p.MaxAgeSecs) * time.Second
+}
+
+// Validate checks that the bounds are not negative
+func (p Policy) Validate() error {
+	if p.MaxAgeSecs < 0 {
+		return fmt.Errorf("max_age_secs must not be negative, got %d", p.MaxAgeSecs)
+	}
+	if p.MaxBytes < 0 {
+		return fmt.Errorf("max_bytes must not be negative, got %d", p.MaxBytes)
+	}
+	return nil
+}
+
+// Usage is the amount of data a store holds
+type Usage struct {
+	Entries int
+	// Bytes is the approximate size of the entries
+	Bytes int64
+	// Oldest is the time of the oldest entry, zero when the store is empty
+	Oldest time.Time
+}
+
+// Store is data a Manager compacts
+type Store interface {
+	// Usage returns the amount of data held
+	Usage() Usage
+	// Compact removes the entries older than before, unless before is zero,
+	// then the oldest entries until at most maxBytes remain, unless maxBytes
+	// is zero, and returns the number of entries removed
+	Compact(before time.Time, maxBytes int64) int
+}
+
+// Status is the usage of a store and its compaction history
+type Status struct {
+	Name   string
+	Policy Policy
+	Usage  Usage
+	// Removed counts the entries compaction removed since the store was added
+	Removed        uint64
+	LastCompaction time.Time
+}
+
+// Manager compacts stores according to their policies
+type Manager struct {
+	now func() time.Time
+
+	mu     sync.Mutex
+	stores []*managed // in the order they were added
+}
+
+type managed struct {
+	name           string
+	store          Store
+	policy         Policy
+	removed        uint64
+	lastCompaction time.Time
+}
+
+// NewManager creates a manager without stores
+func NewManager() *Manager {
+	return &Manager{now: time.Now}
+}
+
+// Add puts store under policy as name, e.g. "events"; adding a name again
+// replaces the store
+func (m *Manager) Add(name string, store Store, policy Policy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, s := range m.stores {
+		if s.name == name {
+			s.store, s.policy = store, policy
+			return
+		}
+	}
+	m.stores = append(m.stores, &managed{name: name, store: store, policy: policy})
+}
+
+// SetPolicy changes the policy of a store, e.g. after a configuration
+// reload, effective from the next compaction
+func (m *Manager) SetPolicy(name string, policy Policy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, s := range m.stores {
+		if s.name == name {
+			s.policy = policy
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown store %q", name)
+}
+
+// Compact compacts every store once and returns their status afterwards
+func (m *Manager) Compact() []Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	for _, s := range m.stores {
+		var before time.Time
+		if s.policy.MaxAgeSecs > 0 {
+			before = now.Add(-s.policy.MaxAge())
+		}
+		removed := s.store.Compact(before, s.policy.MaxBytes)
+		s.removed += uint64(removed)
+		s.lastCompaction = now
+		if removed > 0 {
+			logging.Logger(logging.Storage).Info("storage compacted", "store", s.name, "removed", removed)
+		}
+	}
+	return m.status()
+}
+
+// Status returns the usage and compaction history of every store, in the
+// order they were added
+func (m *Manager) Status() []Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status()
+}
+
+func (m *Manager) status() []Status {
+	out := make([]Status, len(m.stores))
+	for i, s := range m.stores {
+		out[i] = Status{
+			Name:           s.name,
+			Policy:         s.policy,
+			Usage:          s.store.Usage(),
+			Removed:        s.removed,
+			LastCompaction: s.lastCompaction,
+		}
+	}
+	return out
+}
+
+// Run compacts every interval, or DefaultInterval when interval is zero,
+// until ctx is cancelled
+func (m *Manager) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Compact()
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package retention
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeStore holds entries of 10 bytes, oldest first
+type fakeStore struct {
+	times []time.Time
+}
+
+func (s *fakeStore) Usage() Usage {
+	usage := Usage{Entries: len(s.times), Bytes: int64(10 * len(s.times))}
+	if len(s.times) > 0 {
+		usage.Oldest = s.times[0]
+	}
+	return usage
+}
+
+func (s *fakeStore) Compact(before time.Time, maxBytes int64) int {
+	drop := 0
+	for drop < len(s.times) && !before.IsZero() && s.times[drop].Before(before) {
+		drop++
+	}
+	for maxBytes > 0 && int64(10*(len(s.times)-drop)) > maxBytes {
+		drop++
+	}
+	s.times = s.times[drop:]
+	return drop
+}
+
+// newFakeStore creates a store with an entry every minute up to now
+func newFakeStore(now time.Time, entries int) *fakeStore {
+	s := &fakeStore{}
+	for i := entries; i > 0; i-- {
+		s.times = append(s.times, now.Add(-time.Duration(i)*time.Minute))
+	}
+	return s
+}
+
+func TestCompact(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := NewManager()
+	m.now = func() time.Time { return now }
+	events, audit, unbounded := newFakeStore(now, 10), newFakeStore(now, 10), newFakeStore(now, 10)
+	m.Add(Events, events, Policy{MaxAgeSecs: 300})
+	m.Add(Audit, audit, Policy{MaxBytes: 30})
+	m.Add(Analytics, unbounded, Policy{})
+
+	status := m.Compact()
+	want := []struct {
+		name    string
+		entries int
+		removed uint64
+	}{
+		{Events, 5, 5},
+		{Audit, 3, 7},
+		{Analytics, 10, 0},
+	}
+	if len(status) != len(want) {
+		t.Fatalf("Compact() = %+v, want %d stores", status, len(want))
+	}
+	for i, s := range status {
+		if s.Name != want[i].name || s.Usage.Entries != want[i].entries || s.Removed != want[i].removed || !s.LastCompaction.Equal(now) {
+			t.Errorf("Compact() status %d = %+v, want %s with %d entries, %d removed", i, s, want[i].name, want[i].entries, want[i].removed)
+		}
+	}
+	if oldest := status[0].Usage.Oldest; !oldest.Equal(now.Add(-5 * time.Minute)) {
+		t.Errorf("oldest event = %v, want 5 minutes old", oldest)
+	}
+
+	// Removals add up across compactions
+	now = now.Add(time.Minute)
+	if status := m.Status(); status[0].Removed != 5 || !status[0].LastCompaction.Equal(now.Add(-time.Minute)) {
+		t.Errorf("Status() = %+v, want the previous compaction", status[0])
+	}
+	if status := m.Compact(); status[0].Removed != 6 {
+		t.Errorf("Compact() removed %d events in total, want 6", status[0].Removed)
+	}
+}
+
+func TestSetPolicy(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := NewManager()
+	m.now = func() time.Time { return now }
+	events := newFakeStore(now, 10)
+	m.Add(Events, events, Policy{})
+
+	if err := m.SetPolicy(Events, Policy{MaxBytes: -1}); err == nil {
+		t.Errorf("SetPolicy() with a negative bound succeeded, want an error")
+	}
+	if err := m.SetPolicy(Audit, Policy{MaxAgeSecs: 60}); err == nil {
+		t.Errorf("SetPolicy() of an unknown store succeeded, want an error")
+	}
+	if err := m.SetPolicy(Events, Policy{MaxBytes: 20}); err != nil {
+		t.Fatalf("SetPolicy() error = %v", err)
+	}
+	if status := m.Compact(); status[0].Usage.Entries != 2 || status[0].Policy.MaxBytes != 20 {
+		t.Errorf("Compact() = %+v, want 2 events left under the new policy", status[0])
+	}
+
+	// Adding a name again replaces the store
+	m.Add(Events, newFakeStore(now, 1), Policy{})
+	if status := m.Status(); len(status) != 1 || status[0].Usage.Entries != 1 {
+		t.Errorf("Status() = %+v, want the replacing store only", status)
+	}
+}
+
+func TestRun(t *testing.T) {
+	m := NewManager()
+	events := newFakeStore(time.Now(), 10)
+	m.Add(Events, events, Policy{MaxBytes: 10})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		m.Run(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for m.Status()[0].Usage.Entries != 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+	if status := m.Status(); status[0].Usage.Entries != 1 {
+		t.Errorf("Status() after Run = %+v, want the store compacted", status[0])
+	}
+}
@@ -0,0 +1,346 @@
+// Package scale tracks the demand for every action and emits scale signals,
+// so rarely used providers can scale to zero and be warmed ahead of predicted
+// demand. Signals go to sinks: webhooks, a command launching the provider's
+// container image, or an HTTP endpoint read by a Kubernetes external metrics
+// adapter for the HPA.
+package scale
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Defaults of optional settings
+const (
+	DefaultWindow   = time.Minute
+	DefaultIdleSecs = 900
+)
+
+// Reasons of scale signals
+const (
+	// ReasonDemand scales to the current request rate
+	ReasonDemand = "demand"
+	// ReasonPredicted warms providers ahead of the demand expected soon
+	ReasonPredicted = "predicted"
+	// ReasonIdle scales to zero after no demand for the idle period
+	ReasonIdle = "idle"
+)
+
+const (
+	// maxEvents bounds the requests kept per action for the current rate
+	maxEvents = 100000
+	// slots is the number of hour-of-week slots of the demand history
+	slots = 7 * 24
+	// smoothing weighs the latest hour against the history of its slot
+	smoothing = 0.5
+)
+
+// Policy describes how the providers of an action scale
+type Policy struct {
+	Action string `toml:"action" yaml:"action"`
+	// TargetRPS is the request rate one replica handles
+	TargetRPS   float64 `toml:"target_rps" yaml:"target_rps"`
+	MinReplicas int     `toml:"min_replicas,omitempty" yaml:"min_replicas,omitempty"`
+	// MaxReplicas caps the replicas; 0 means no cap
+	MaxReplicas int `toml:"max_replicas,omitempty" yaml:"max_replicas,omitempty"`
+	// IdleSecs is how long the action must see no requests before its
+	// providers scale to zero; 0 uses DefaultIdleSecs
+	IdleSecs int `toml:"idle_secs,omitempty" yaml:"idle_secs,omitempty"`
+	// WarmAheadSecs is how far ahead predicted demand warms providers; 0
+	// disables prediction
+	WarmAheadSecs int `toml:"warm_ahead_secs,omitempty" yaml:"warm_ahead_secs,omitempty"`
+	// Image is the provider's container image, passed to launch commands
+	Image string `toml:"image,omitempty" yaml:"image,omitempty"`
+}
+
+// Validate checks that the policy can be applied
+func (p *Policy) Validate() error {
+	if p.Action == "" {
+		return fmt.Errorf("action is required")
+	}
+	if p.TargetRPS <= 0 {
+		return fmt.Errorf("action %s: target_rps must be greater than 0", p.Action)
+	}
+	if p.MinReplicas < 0 || p.MaxReplicas < 0 || p.IdleSecs < 0 || p.WarmAheadSecs < 0 {
+		return fmt.Errorf("action %s: replicas and durations must not be negative", p.Action)
+	}
+	if p.MaxReplicas > 0 && p.MinReplicas > p.MaxReplicas {
+		return fmt.Errorf("action %s: min_replicas %d exceeds max_replicas %d", p.Action, p.MinReplicas, p.MaxReplicas)
+	}
+	return nil
+}
+
+// Signal asks for the providers of an action to be scaled
+type Signal struct {
+	Time     time.Time
+	Action   string
+	Replicas int
+	Previous int
+	// Demand is the current request rate per second
+	Demand float64
+	// Predicted is the request rate per second expected WarmAheadSecs from now
+	Predicted float64
+	Reason    string
+	Image     string
+}
+
+// Sink receives scale signals
+type Sink interface {
+	Scale(ctx context.Context, s Signal) error
+}
+
+// SinkFunc adapts a function to a Sink
+type SinkFunc func(ctx context.Context, s Signal) error
+
+// Scale calls f
+func (f SinkFunc) Scale(ctx context.Context, s Signal) error {
+	return f(ctx, s)
+}
+
+// Status is the demand and desired replicas of an action
+type Status struct {
+	Action    string  `json:"action"`
+	Demand    float64 `json:"demand_rps"`
+	Predicted float64 `json:"predicted_rps"`
+	Replicas  int     `json:"replicas"`
+	LastSeen  int64   `json:"last_seen_unix,omitempty"`
+}
+
+// Tracker measures the demand for the actions with a policy and emits a
+// signal to its sinks whenever their desired replicas change
+type Tracker struct {
+	policies map[string]Policy
+	window   time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	actions map[string]*demand
+	sinks   []Sink
+}
+
+type demand struct {
+	events   []time.Time // requests within the window, oldest first
+	lastSeen time.Time
+	replicas int
+
+	history [slots]float64 // smoothed requests per hour-of-week
+	hour    int64          // Unix hour being counted
+	count   float64        // requests in hour
+}
+
+// NewTracker creates a tracker measuring the current rate over window; 0
+// uses DefaultWindow. Policies are expected to be valid. Every action starts
+// at its minimum replicas.
+func NewTracker(policies []Policy, window time.Duration) *Tracker {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	t := &Tracker{
+		policies: make(map[string]Policy, len(policies)),
+		window:   window,
+		now:      time.Now,
+		actions:  make(map[string]*demand, len(policies)),
+	}
+	for _, p := range policies {
+		t.policies[p.Action] = p
+		t.actions[p.Action] = &demand{replicas: p.MinReplicas}
+	}
+	return t
+}
+
+// AddSink sends signals to sink
+func (t *Tracker) AddSink(sink Sink) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sinks = append(t.sinks, sink)
+}
+
+// Observe records a request for action. A request for an action scaled to
+// zero signals a scale-up at once, without waiting for the next evaluation.
+func (t *Tracker) Observe(action string) {
+	t.mu.Lock()
+	d, ok := t.actions[action]
+	if !ok {
+		t.mu.Unlock()
+		return
+	}
+	now := t.now()
+	d.roll(now)
+	d.count++
+	d.lastSeen = now
+	d.events = append(d.events, now)
+	if over := len(d.events) - maxEvents; over > 0 {
+		d.events = d.events[over:]
+	}
+	var signal *Signal
+	if d.replicas == 0 {
+		signal = t.evaluate(action, d, now)
+	}
+	sinks := t.sinks
+	t.mu.Unlock()
+
+	if signal != nil {
+		go emit(context.Background(), sinks, *signal)
+	}
+}
+
+// Evaluate recomputes the desired replicas of every action and sends the
+// changes to the sinks. It returns the signals sent.
+func (t *Tracker) Evaluate(ctx context.Context) []Signal {
+	t.mu.Lock()
+	now := t.now()
+	var signals []Signal
+	for action, d := range t.actions {
+		d.roll(now)
+		if s := t.evaluate(action, d, now); s != nil {
+			signals = append(signals, *s)
+		}
+	}
+	sinks := t.sinks
+	t.mu.Unlock()
+
+	sort.Slice(signals, func(i, j int) bool { return signals[i].Action < signals[j].Action })
+	for _, s := range signals {
+		emit(ctx, sinks, s)
+	}
+	return signals
+}
+
+// Run evaluates every interval until ctx is cancelled
+func (t *Tracker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			t.Evaluate(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Statuses returns the demand and desired replicas of every action, ordered
+// by action
+func (t *Tracker) Statuses() []Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	out := make([]Status, 0, len(t.actions))
+	for action, d := range t.actions {
+		d.roll(now)
+		s := Status{
+			Action:    action,
+			Demand:    t.rate(d, now),
+			Predicted: t.predict(action, d, now),
+			Replicas:  d.replicas,
+		}
+		if !d.lastSeen.IsZero() {
+			s.LastSeen = d.lastSeen.Unix()
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Action < out[j].Action })
+	return out
+}
+
+// evaluate updates the desired replicas of an action and returns the signal
+// if they changed; mu must be held
+func (t *Tracker) evaluate(action string, d *demand, now time.Time) *Signal {
+	p := t.policies[action]
+	rate := t.rate(d, now)
+	predicted := t.predict(action, d, now)
+
+	replicas := int(math.Ceil(rate / p.TargetRPS))
+	reason := ReasonDemand
+	if warm := int(math.Ceil(predicted / p.TargetRPS)); warm > replicas {
+		replicas, reason = warm, ReasonPredicted
+	}
+	if replicas == 0 && !d.lastSeen.IsZero() && now.Sub(d.lastSeen) < time.Duration(orDefault(p.IdleSecs, DefaultIdleSecs))*time.Second {
+		// Recently used providers stay warm until the idle period passes
+		replicas = 1
+	}
+	if replicas < p.MinReplicas {
+		replicas = p.MinReplicas
+	}
+	if p.MaxReplicas > 0 && replicas > p.MaxReplicas {
+		replicas = p.MaxReplicas
+	}
+	if replicas == 0 {
+		reason = ReasonIdle
+	}
+	if replicas == d.replicas {
+		return nil
+	}
+	s := &Signal{
+		Time:      now,
+		Action:    action,
+		Replicas:  replicas,
+		Previous:  d.replicas,
+		Demand:    rate,
+		Predicted: predicted,
+		Reason:    reason,
+		Image:     p.Image,
+	}
+	d.replicas = replicas
+	return s
+}
+
+// rate returns the requests per second over the window; mu must be held
+func (t *Tracker) rate(d *demand, now time.Time) float64 {
+	cutoff := now.Add(-t.window)
+	drop := sort.Search(len(d.events), func(i int) bool { return d.events[i].After(cutoff) })
+	d.events = d.events[drop:]
+	return float64(len(d.events)) / t.window.Seconds()
+}
+
+// predict returns the request rate expected WarmAheadSecs from now, from the
+// history of the same hour of the week; mu must be held
+func (t *Tracker) predict(action string, d *demand, now time.Time) float64 {
+	ahead := t.policies[action].WarmAheadSecs
+	if ahead <= 0 {
+		return 0
+	}
+	at := now.Add(time.Duration(ahead) * time.Second)
+	return d.history[slot(at.Unix()/3600)] / 3600
+}
+
+// roll folds the requests of the hours that ended into the history
+func (d *demand) roll(now time.Time) {
+	hour := now.Unix() / 3600
+	if d.hour == 0 {
+		d.hour = hour
+	}
+	for h := d.hour; h < hour && h < d.hour+slots; h++ {
+		i := slot(h)
+		d.history[i] = smoothing*d.count + (1-smoothing)*d.history[i]
+		d.count = 0
+	}
+	d.hour = hour
+}
+
+// slot returns the hour-of-week slot of a Unix hour
+func slot(hour int64) int {
+	return int(hour % slots)
+}
+
+func emit(ctx context.Context, sinks []Sink, s Signal) {
+	log.Printf("Scaling providers of %s from %d to %d replicas (%s, %.2f rps)", s.Action, s.Previous, s.Replicas, s.Reason, s.Demand)
+	for _, sink := range sinks {
+		if err := sink.Scale(ctx, s); err != nil {
+			log.Printf("Failed to send scale signal for %s: %v", s.Action, err)
+		}
+	}
+}
+
+func orDefault(value, def int) int {
+	if value > 0 {
+		return value
+	}
+	return def
+}
@@ -0,0 +1,91 @@
+package scale
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/webhook"
+)
+
+// WebhookSink delivers signals as provider.scale events to the webhooks of d
+func WebhookSink(d *webhook.Dispatcher) Sink {
+	return SinkFunc(func(ctx context.Context, s Signal) error {
+		d.Emit(webhook.EventProviderScale, map[string]interface{}{
+			"action":        s.Action,
+			"replicas":      s.Replicas,
+			"previous":      s.Previous,
+			"demand_rps":    s.Demand,
+			"predicted_rps": s.Predicted,
+			"reason":        s.Reason,
+			"image":         s.Image,
+		})
+		return nil
+	})
+}
+
+// CommandSink runs command for every signal, e.g. to launch or stop the
+// provider's container image. The placeholders {action}, {image}, {replicas}
+// and {previous} in its arguments are replaced, and the signal is also passed
+// in the NFA_SCALE_* environment variables:
+//
+//	scale.CommandSink("docker", "compose", "up", "-d", "--scale", "translator={replicas}")
+func CommandSink(command ...string) Sink {
+	return SinkFunc(func(ctx context.Context, s Signal) error {
+		if len(command) == 0 {
+			return fmt.Errorf("no scale command")
+		}
+		replacer := strings.NewReplacer(
+			"{action}", s.Action,
+			"{image}", s.Image,
+			"{replicas}", strconv.Itoa(s.Replicas),
+			"{previous}", strconv.Itoa(s.Previous),
+		)
+		args := make([]string, len(command))
+		for i, arg := range command {
+			args[i] = replacer.Replace(arg)
+		}
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Env = append(os.Environ(),
+			"NFA_SCALE_ACTION="+s.Action,
+			"NFA_SCALE_IMAGE="+s.Image,
+			"NFA_SCALE_REPLICAS="+strconv.Itoa(s.Replicas),
+			"NFA_SCALE_PREVIOUS="+strconv.Itoa(s.Previous),
+			"NFA_SCALE_REASON="+s.Reason,
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("scale command failed: %w: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	})
+}
+
+// Handler serves the status of every action as JSON, or of one action with
+// ?action=name. A Kubernetes external metrics adapter, such as KEDA's
+// metrics-api scaler, reads the replicas field so the HPA follows the
+// tracker's demand, down to zero.
+func (t *Tracker) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statuses := t.Statuses()
+		var body interface{} = statuses
+		if action := r.URL.Query().Get("action"); action != "" {
+			body = nil
+			for _, s := range statuses {
+				if s.Action == action {
+					body = s
+				}
+			}
+			if body == nil {
+				http.Error(w, fmt.Sprintf("no scaling policy for action %q", action), http.StatusNotFound)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(body)
+	})
+}
@@ -0,0 +1,80 @@
+package shadow
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/policy"
+)
+
+// candidateTimeout bounds the time a shadow engine may take to route an intent
+const candidateTimeout = 5 * time.Second
+
+// Engine is a policy.Engine that routes with a primary engine and replays every
+// routing decision on a candidate engine, e.g. a new matcher version. Only the
+// primary's decisions are used; the candidate's are compared with them in the
+// background and recorded under the candidate's name.
+type Engine struct {
+	primary   policy.Engine
+	candidate policy.Engine
+	name      string
+	rule      Rule
+	rec       *Recorder
+}
+
+// NewEngine creates an engine shadowing primary with candidate for the
+// actions and sample rate of rule; its selector is ignored
+func NewEngine(primary, candidate policy.Engine, name string, rule Rule, rec *Recorder) *Engine {
+	return &Engine{
+		primary:   primary,
+		candidate: candidate,
+		name:      name,
+		rule:      rule,
+		rec:       rec,
+	}
+}
+
+// Authorize implements policy.Engine with the primary engine
+func (e *Engine) Authorize(ctx context.Context, input policy.Input) (policy.Decision, error) {
+	return e.primary.Authorize(ctx, input)
+}
+
+// Route implements policy.Engine. Outputs are the preferred service IDs, so
+// the candidate matches when it would route to the same provider.
+func (e *Engine) Route(ctx context.Context, input policy.Input, candidates []policy.Candidate) ([]policy.Candidate, error) {
+	routed, err := e.primary.Route(ctx, input, candidates)
+	if !e.rule.Matches(input.Action) || !e.rule.Sample() {
+		return routed, err
+	}
+
+	res := Result{Time: time.Now(), Action: input.Action, Candidate: e.name}
+	if err != nil {
+		res.PrimaryError = err.Error()
+	} else {
+		res.PrimaryOutput = preferred(routed)
+	}
+	shadowed := append([]policy.Candidate(nil), candidates...)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), candidateTimeout)
+		defer cancel()
+		start := time.Now()
+		out, err := e.candidate.Route(ctx, input, shadowed)
+		res.CandidateLatency = time.Since(start)
+		if err != nil {
+			log.Printf("Shadow engine %s failed to route %s: %v", e.name, input.Action, err)
+			res.CandidateError = err.Error()
+		} else {
+			res.CandidateOutput = preferred(out)
+		}
+		e.rec.Record(res)
+	}()
+	return routed, err
+}
+
+func preferred(candidates []policy.Candidate) []byte {
+	if len(candidates) == 0 {
+		return nil
+	}
+	return []byte(candidates[0].ServiceID)
+}
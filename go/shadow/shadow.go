@@ -0,0 +1,180 @@
+// Package shadow evaluates new providers and matcher versions against live
+// traffic. Intents are also sent to a shadow candidate whose responses are
+// recorded and compared with the primary's, but never returned to the user.
+package shadow
+
+import (
+	"bytes"
+	"math/rand"
+	"path"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxResults bounds the in-memory shadow results kept for inspection
+const maxResults = 1000
+
+// Rule mirrors the intents of matching actions to the runtimes matching
+// Selector. Actions are path.Match patterns; an empty list matches every
+// action. SampleRate is the fraction of intents mirrored, 0 meaning all.
+type Rule struct {
+	Actions    []string          `toml:"actions,omitempty" yaml:"actions,omitempty"`
+	Selector   map[string]string `toml:"selector" yaml:"selector"`
+	SampleRate float64           `toml:"sample_rate,omitempty" yaml:"sample_rate,omitempty"`
+}
+
+// Matches reports whether the rule applies to action
+func (r Rule) Matches(action string) bool {
+	if len(r.Actions) == 0 {
+		return true
+	}
+	for _, pattern := range r.Actions {
+		if ok, _ := path.Match(pattern, action); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Sample decides whether to mirror one intent
+func (r Rule) Sample() bool {
+	return r.SampleRate <= 0 || r.SampleRate >= 1 || rand.Float64() < r.SampleRate
+}
+
+// Result compares the response of a shadow candidate with the primary's
+type Result struct {
+	Time   time.Time
+	Action string
+	// Candidate identifies the shadow target, e.g. a runtime ID or matcher version
+	Candidate string
+	// Match is true when the candidate answered like the primary
+	Match            bool
+	PrimaryOutput    []byte
+	CandidateOutput  []byte
+	PrimaryError     string
+	CandidateError   string
+	CandidateLatency time.Duration
+}
+
+// Stats summarizes the shadow results of one action and candidate
+type Stats struct {
+	Action     string
+	Candidate  string
+	Total      int
+	Mismatches int
+	Errors     int
+	// MeanLatency is the mean latency of the candidate's responses
+	MeanLatency time.Duration
+}
+
+// Comparator decides whether a candidate's output matches the primary's
+type Comparator func(primary, candidate []byte) bool
+
+// Recorder keeps shadow results and per-candidate statistics
+type Recorder struct {
+	compare Comparator
+
+	mu        sync.Mutex
+	results   []Result // oldest first
+	stats     map[[2]string]*stats
+	listeners []func(Result)
+}
+
+type stats struct {
+	total, mismatches, errors int
+	latency                   time.Duration
+}
+
+// NewRecorder creates a recorder comparing outputs with compare; nil compares
+// them byte for byte
+func NewRecorder(compare Comparator) *Recorder {
+	if compare == nil {
+		compare = bytes.Equal
+	}
+	return &Recorder{
+		compare: compare,
+		stats:   make(map[[2]string]*stats),
+	}
+}
+
+// OnResult registers a callback invoked with every recorded result, e.g. to
+// export results for offline analysis
+func (r *Recorder) OnResult(fn func(Result)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+// Record compares a candidate's response with the primary's and stores the
+// result. Match is set by the recorder: responses match when both failed or
+// both succeeded with matching outputs.
+func (r *Recorder) Record(res Result) {
+	if res.Time.IsZero() {
+		res.Time = time.Now()
+	}
+	switch {
+	case res.PrimaryError != "" || res.CandidateError != "":
+		res.Match = res.PrimaryError != "" && res.CandidateError != ""
+	default:
+		res.Match = r.compare(res.PrimaryOutput, res.CandidateOutput)
+	}
+
+	r.mu.Lock()
+	r.results = append(r.results, res)
+	if len(r.results) > maxResults {
+		r.results = r.results[1:]
+	}
+	key := [2]string{res.Action, res.Candidate}
+	s := r.stats[key]
+	if s == nil {
+		s = &stats{}
+		r.stats[key] = s
+	}
+	s.total++
+	if !res.Match {
+		s.mismatches++
+	}
+	if res.CandidateError != "" {
+		s.errors++
+	}
+	s.latency += res.CandidateLatency
+	listeners := append([]func(Result){}, r.listeners...)
+	r.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(res)
+	}
+}
+
+// Results returns a copy of the recorded results, oldest first
+func (r *Recorder) Results() []Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Result(nil), r.results...)
+}
+
+// Stats returns the statistics of every action and candidate, ordered by
+// action and candidate
+func (r *Recorder) Stats() []Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Stats, 0, len(r.stats))
+	for key, s := range r.stats {
+		out = append(out, Stats{
+			Action:      key[0],
+			Candidate:   key[1],
+			Total:       s.total,
+			Mismatches:  s.mismatches,
+			Errors:      s.errors,
+			MeanLatency: s.latency / time.Duration(s.total),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Action != out[j].Action {
+			return out[i].Action < out[j].Action
+		}
+		return out[i].Candidate < out[j].Candidate
+	})
+	return out
+}
@@ -0,0 +1,77 @@
+package sla
+
+import (
+	"sort"
+	"time"
+	"unsafe"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/retention"
+)
+
+var _ retention.Store = (*Recorder)(nil)
+
+// Usage implements retention.Store over the samples of all providers
+func (r *Recorder) Usage() retention.Usage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var usage retention.Usage
+	for _, samples := range r.samples {
+		for _, s := range samples {
+			usage.Entries++
+			usage.Bytes += sampleSize(s)
+		}
+		if len(samples) > 0 && (usage.Oldest.IsZero() || samples[0].Time.Before(usage.Oldest)) {
+			usage.Oldest = samples[0].Time
+		}
+	}
+	return usage
+}
+
+// Compact implements retention.Store, on top of the retention the recorder
+// was created with. The oldest samples across all providers go first.
+func (r *Recorder) Compact(before time.Time, maxBytes int64) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	removed := 0
+	var size int64
+	for id, samples := range r.samples {
+		if !before.IsZero() {
+			drop := sort.Search(len(samples), func(i int) bool { return !samples[i].Time.Before(before) })
+			samples = samples[drop:]
+			removed += drop
+		}
+		if len(samples) == 0 {
+			delete(r.samples, id)
+			continue
+		}
+		r.samples[id] = samples
+		for _, s := range samples {
+			size += sampleSize(s)
+		}
+	}
+	for maxBytes > 0 && size > maxBytes {
+		oldest := ""
+		for id, samples := range r.samples {
+			if oldest == "" || samples[0].Time.Before(r.samples[oldest][0].Time) {
+				oldest = id
+			}
+		}
+		if oldest == "" {
+			break
+		}
+		samples := r.samples[oldest]
+		size -= sampleSize(samples[0])
+		removed++
+		if len(samples) == 1 {
+			delete(r.samples, oldest)
+		} else {
+			r.samples[oldest] = samples[1:]
+		}
+	}
+	return removed
+}
+
+// sampleSize approximates the memory held by a sample
+func sampleSize(s Sample) int64 {
+	return int64(unsafe.Sizeof(s)) + int64(len(s.Namespace)+len(s.ServiceID))
+}
@@ -0,0 +1,353 @@
+// Package sla measures providers against the QoS declared in their contracts
+// and generates SLA reports: availability, latency percentiles and a timeline
+// of the periods in which an objective was missed. Reports are served by the
+// admin service and exported as JSON or CSV by `nfactl report`.
+package sla
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+)
+
+// Defaults of report parameters
+const (
+	DefaultPeriod    = 24 * time.Hour
+	DefaultBucket    = 5 * time.Minute
+	DefaultRetention = 7 * 24 * time.Hour
+)
+
+// maxSamples bounds the samples kept per provider
+const maxSamples = 100000
+
+// Sample is the outcome of one invocation of a provider, measured from real
+// traffic or a synthetic probe. A zero Latency is unknown, e.g. for the
+// outcomes consumers report, and the sample counts towards availability
+// only.
+type Sample struct {
+	Time      time.Time
+	Namespace string
+	ServiceID string
+	Latency   time.Duration
+	Success   bool
+}
+
+// Objective is the QoS a provider declared
+type Objective struct {
+	// Latency bounds the 99th percentile; 0 means none
+	Latency time.Duration
+	// Availability is the minimum success ratio, e.g. 0.995; 0 means none
+	Availability float64
+}
+
+// ObjectiveFromQoS parses the QoS of a contract, e.g. latency "150ms" and
+// availability "99.5%"
+func ObjectiveFromQoS(qos *contract.QualityOfService) (Objective, error) {
+	var o Objective
+	if qos == nil {
+		return o, nil
+	}
+	if qos.Latency != "" {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimLeft(qos.Latency, "<= ")))
+		if err != nil {
+			return o, fmt.Errorf("invalid latency %q: %w", qos.Latency, err)
+		}
+		o.Latency = d
+	}
+	if qos.Availability != "" {
+		text := strings.TrimSpace(qos.Availability)
+		scale := 1.0
+		if strings.HasSuffix(text, "%") {
+			text = strings.TrimSuffix(text, "%")
+			scale = 100
+		}
+		v, err := strconv.ParseFloat(text, 64)
+		if err != nil || v < 0 || v/scale > 1 {
+			return o, fmt.Errorf("invalid availability %q", qos.Availability)
+		}
+		o.Availability = v / scale
+	}
+	return o, nil
+}
+
+// Recorder keeps recent samples of every provider and builds reports from them
+type Recorder struct {
+	retention time.Duration
+	now       func() time.Time
+
+	mu         sync.Mutex
+	samples    map[string][]Sample // service ID -> samples, oldest first
+	objectives map[string]Objective
+}
+
+// NewRecorder creates a recorder keeping samples for retention; 0 keeps them
+// for DefaultRetention
+func NewRecorder(retention time.Duration) *Recorder {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &Recorder{
+		retention:  retention,
+		now:        time.Now,
+		samples:    make(map[string][]Sample),
+		objectives: make(map[string]Objective),
+	}
+}
+
+// SetObjective declares the objective of a provider, typically from
+// ObjectiveFromQoS when it registers
+func (r *Recorder) SetObjective(serviceID string, o Objective) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.objectives[serviceID] = o
+}
+
+// Observe records a sample
+func (r *Recorder) Observe(s Sample) {
+	if s.Time.IsZero() {
+		s.Time = r.now()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	samples := append(r.samples[s.ServiceID], s)
+	cutoff := r.now().Add(-r.retention)
+	drop := sort.Search(len(samples), func(i int) bool { return samples[i].Time.After(cutoff) })
+	if over := len(samples) - maxSamples; over > drop {
+		drop = over
+	}
+	r.samples[s.ServiceID] = samples[drop:]
+}
+
+// ObserveOutcome records an outcome a consumer reported to an embedded
+// broker, with broker.WithOutcomes, as a sample without latency. The
+// objective of the provider is set from the QoS of its contract; a QoS that
+// does not parse leaves it unchanged.
+func (r *Recorder) ObserveOutcome(o broker.Outcome) {
+	if qos := o.Contract.GetSpec().GetQualityOfService(); qos != nil {
+		objective, err := ObjectiveFromQoS(&contract.QualityOfService{Latency: qos.Latency, Availability: qos.Availability})
+		if err == nil {
+			r.SetObjective(o.ServiceID, objective)
+		}
+	}
+	r.Observe(Sample{Time: o.Time, Namespace: o.Namespace, ServiceID: o.ServiceID, Success: o.Success})
+}
+
+// Query selects what a report covers
+type Query struct {
+	// Namespace and ServiceID restrict the report; empty covers all
+	Namespace string
+	ServiceID string
+	// Start and End bound the period; zero values default to the DefaultPeriod
+	// ending now
+	Start, End time.Time
+	// Bucket is the resolution of the violation timeline
+	Bucket time.Duration
+}
+
+// Report builds an SLA report
+func (r *Recorder) Report(q Query) *nfa_admin_v1alpha.SLAReport {
+	now := r.now()
+	if q.End.IsZero() {
+		q.End = now
+	}
+	if q.Start.IsZero() {
+		q.Start = q.End.Add(-DefaultPeriod)
+	}
+	if q.Bucket <= 0 {
+		q.Bucket = DefaultBucket
+	}
+
+	r.mu.Lock()
+	selected := make(map[string][]Sample)
+	for id, samples := range r.samples {
+		if q.ServiceID != "" && id != q.ServiceID {
+			continue
+		}
+		for _, s := range samples {
+			if s.Time.Before(q.Start) || !s.Time.Before(q.End) || (q.Namespace != "" && s.Namespace != q.Namespace) {
+				continue
+			}
+			selected[id] = append(selected[id], s)
+		}
+	}
+	objectives := make(map[string]Objective, len(selected))
+	for id := range selected {
+		objectives[id] = r.objectives[id]
+	}
+	r.mu.Unlock()
+
+	report := &nfa_admin_v1alpha.SLAReport{
+		StartUnix:     q.Start.Unix(),
+		EndUnix:       q.End.Unix(),
+		GeneratedUnix: now.Unix(),
+	}
+	for id, samples := range selected {
+		report.Providers = append(report.Providers, provider(id, samples, objectives[id], q))
+	}
+	sort.Slice(report.Providers, func(i, j int) bool {
+		a, b := report.Providers[i], report.Providers[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.ServiceId < b.ServiceId
+	})
+	return report
+}
+
+// RunPeriodic builds a report for every period as it ends and passes it to
+// fn, until ctx is cancelled
+func (r *Recorder) RunPeriodic(ctx context.Context, period time.Duration, fn func(*nfa_admin_v1alpha.SLAReport)) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			end := r.now()
+			fn(r.Report(Query{Start: end.Add(-period), End: end}))
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func provider(serviceID string, samples []Sample, o Objective, q Query) *nfa_admin_v1alpha.ProviderSLA {
+	p := &nfa_admin_v1alpha.ProviderSLA{
+		Namespace:             samples[0].Namespace,
+		ServiceId:             serviceID,
+		LatencyObjectiveMs:    ms(o.Latency),
+		AvailabilityObjective: o.Availability,
+	}
+	var latencies []time.Duration
+	p.Requests, p.Failures, latencies = summarize(samples)
+	p.Availability = float64(p.Requests-p.Failures) / float64(p.Requests)
+	p.LatencyP50Ms = ms(percentile(latencies, 0.50))
+	p.LatencyP90Ms = ms(percentile(latencies, 0.90))
+	p.LatencyP99Ms = ms(percentile(latencies, 0.99))
+	p.Met = !missed(o, p.Availability, percentile(latencies, 0.99))
+
+	// Buckets are aligned to multiples of their size, so timelines of
+	// different reports line up
+	buckets := make(map[int64][]Sample)
+	for _, s := range samples {
+		key := s.Time.Truncate(q.Bucket).UnixNano()
+		buckets[key] = append(buckets[key], s)
+	}
+	keys := make([]int64, 0, len(buckets))
+	for key := range buckets {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	open := make(map[string]*nfa_admin_v1alpha.SLAViolation)
+	for _, key := range keys {
+		start := time.Unix(0, key)
+		end := start.Add(q.Bucket)
+		if start.Before(q.Start) {
+			start = q.Start
+		}
+		if end.After(q.End) {
+			end = q.End
+		}
+		requests, failures, latencies := summarize(buckets[key])
+		availability := float64(requests-failures) / float64(requests)
+		p99 := percentile(latencies, 0.99)
+		found := map[string]string{}
+		if o.Availability > 0 && availability < o.Availability {
+			found["availability"] = fmt.Sprintf("availability %.2f%% below %.2f%%", availability*100, o.Availability*100)
+		}
+		if o.Latency > 0 && p99 > o.Latency {
+			found["latency"] = fmt.Sprintf("p99 latency %s above %s", p99, o.Latency)
+		}
+		for _, kind := range []string{"availability", "latency"} {
+			v := open[kind]
+			detail, ok := found[kind]
+			switch {
+			case ok && v != nil && v.EndUnix == start.Unix():
+				v.EndUnix = end.Unix()
+			case ok:
+				v = &nfa_admin_v1alpha.SLAViolation{StartUnix: start.Unix(), EndUnix: end.Unix(), Kind: kind, Detail: detail}
+				p.Violations = append(p.Violations, v)
+				open[kind] = v
+			default:
+				delete(open, kind)
+			}
+		}
+	}
+	return p
+}
+
+// summarize counts samples and returns their known latencies, sorted
+func summarize(samples []Sample) (requests, failures uint64, latencies []time.Duration) {
+	latencies = make([]time.Duration, 0, len(samples))
+	for _, s := range samples {
+		requests++
+		if !s.Success {
+			failures++
+		}
+		if s.Latency > 0 {
+			latencies = append(latencies, s.Latency)
+		}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	return requests, failures, latencies
+}
+
+func missed(o Objective, availability float64, p99 time.Duration) bool {
+	return (o.Availability > 0 && availability < o.Availability) || (o.Latency > 0 && p99 > o.Latency)
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// WriteCSV writes a report as CSV, one row per provider. Violations are
+// summarized by their count.
+func WriteCSV(w io.Writer, report *nfa_admin_v1alpha.SLAReport) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"namespace", "service_id", "requests", "failures", "availability",
+		"latency_p50_ms", "latency_p90_ms", "latency_p99_ms",
+		"latency_objective_ms", "availability_objective", "met", "violations",
+	})
+	for _, p := range report.Providers {
+		cw.Write([]string{
+			p.Namespace,
+			p.ServiceId,
+			strconv.FormatUint(p.Requests, 10),
+			strconv.FormatUint(p.Failures, 10),
+			strconv.FormatFloat(p.Availability, 'f', 5, 64),
+			strconv.FormatFloat(p.LatencyP50Ms, 'f', 3, 64),
+			strconv.FormatFloat(p.LatencyP90Ms, 'f', 3, 64),
+			strconv.FormatFloat(p.LatencyP99Ms, 'f', 3, 64),
+			strconv.FormatFloat(p.LatencyObjectiveMs, 'f', 3, 64),
+			strconv.FormatFloat(p.AvailabilityObjective, 'f', 5, 64),
+			strconv.FormatBool(p.Met),
+			strconv.Itoa(len(p.Violations)),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
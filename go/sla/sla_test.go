@@ -0,0 +1,213 @@
+package sla
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/contract"
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+	for _, tt := range []struct {
+		sorted []time.Duration
+		p      float64
+		want   time.Duration
+	}{
+		{nil, 0.5, 0},
+		{sorted[:1], 0.99, time.Millisecond},
+		{sorted, 0, time.Millisecond},
+		{sorted, 0.50, 50 * time.Millisecond},
+		{sorted, 0.90, 90 * time.Millisecond},
+		{sorted, 0.99, 99 * time.Millisecond},
+		{sorted, 1, 100 * time.Millisecond},
+		{sorted[:3], 0.50, 2 * time.Millisecond},
+	} {
+		if got := percentile(tt.sorted, tt.p); got != tt.want {
+			t.Errorf("percentile(%d latencies, %v) = %v, want %v", len(tt.sorted), tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestObjectiveFromQoS(t *testing.T) {
+	for _, tt := range []struct {
+		qos     *contract.QualityOfService
+		want    Objective
+		wantErr bool
+	}{
+		{nil, Objective{}, false},
+		{&contract.QualityOfService{Latency: "<= 150ms", Availability: "99.5%"}, Objective{Latency: 150 * time.Millisecond, Availability: 0.995}, false},
+		{&contract.QualityOfService{Availability: "0.9"}, Objective{Availability: 0.9}, false},
+		{&contract.QualityOfService{Latency: "fast"}, Objective{}, true},
+		{&contract.QualityOfService{Availability: "101%"}, Objective{}, true},
+	} {
+		got, err := ObjectiveFromQoS(tt.qos)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+			t.Errorf("ObjectiveFromQoS(%+v) = %+v, %v, want %+v, error %v", tt.qos, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// testRecorder is a recorder whose clock is at now
+func testRecorder(now time.Time) *Recorder {
+	r := NewRecorder(0)
+	r.now = func() time.Time { return now }
+	return r
+}
+
+func TestReportViolations(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	r := testRecorder(start.Add(time.Hour))
+	r.SetObjective("lights-1", Objective{Latency: 100 * time.Millisecond, Availability: 0.9})
+	// Buckets 0 and 1 are slow, bucket 2 fails, bucket 3 meets the objective
+	for bucket, sample := range []Sample{
+		{Latency: 200 * time.Millisecond, Success: true},
+		{Latency: 300 * time.Millisecond, Success: true},
+		{Latency: 50 * time.Millisecond},
+		{Latency: 50 * time.Millisecond, Success: true},
+	} {
+		for i := 0; i < 10; i++ {
+			sample.Time = start.Add(time.Duration(bucket)*DefaultBucket + time.Duration(i)*time.Second)
+			sample.Namespace = "home"
+			sample.ServiceID = "lights-1"
+			r.Observe(sample)
+		}
+	}
+	r.Observe(Sample{Time: start, Namespace: "office", ServiceID: "printer-1", Latency: time.Second, Success: true})
+
+	report := r.Report(Query{Start: start, End: start.Add(time.Hour)})
+	if len(report.Providers) != 2 || report.Providers[0].ServiceId != "lights-1" {
+		t.Fatalf("Report() = %v, want lights then printer", report.Providers)
+	}
+	p := report.Providers[0]
+	if p.Requests != 40 || p.Failures != 10 || p.Availability != 0.75 || p.Met {
+		t.Errorf("provider = %v, want 40 requests, 10 failures and the objective missed", p)
+	}
+	if p.LatencyP50Ms != 50 || p.LatencyP99Ms != 300 || p.LatencyObjectiveMs != 100 {
+		t.Errorf("latencies = p50 %v p99 %v, objective %v, want 50, 300 and 100", p.LatencyP50Ms, p.LatencyP99Ms, p.LatencyObjectiveMs)
+	}
+	want := []*nfa_admin_v1alpha.SLAViolation{
+		{StartUnix: start.Unix(), EndUnix: start.Add(2 * DefaultBucket).Unix(), Kind: "latency"},
+		{StartUnix: start.Add(2 * DefaultBucket).Unix(), EndUnix: start.Add(3 * DefaultBucket).Unix(), Kind: "availability"},
+	}
+	if len(p.Violations) != len(want) {
+		t.Fatalf("violations = %v, want %v", p.Violations, want)
+	}
+	for i, v := range p.Violations {
+		if v.StartUnix != want[i].StartUnix || v.EndUnix != want[i].EndUnix || v.Kind != want[i].Kind || v.Detail == "" {
+			t.Errorf("violation %d = %v, want %v with a detail", i, v, want[i])
+		}
+	}
+	// Without an objective nothing is missed
+	if printer := report.Providers[1]; !printer.Met || len(printer.Violations) != 0 {
+		t.Errorf("provider without objective = %v, want it met", printer)
+	}
+
+	if report := r.Report(Query{Namespace: "office", Start: start, End: start.Add(time.Hour)}); len(report.Providers) != 1 {
+		t.Errorf("Report() of a namespace = %v, want only its provider", report.Providers)
+	}
+}
+
+func TestObserveOutcome(t *testing.T) {
+	r := NewRecorder(0)
+	b := broker.NewEmbedded(broker.WithOutcomes(r.ObserveOutcome))
+	defer b.Close()
+	ctx := context.Background()
+	resp, err := b.RegisterIntent(ctx, &nfa_broker_v1alpha.RegisterIntentRequest{
+		Contract: &nfa_intent_v1alpha.IntentContract{
+			Metadata: &nfa_intent_v1alpha.Metadata{Name: "lights", Labels: map[string]string{broker.LabelNamespace: "home"}},
+			Spec: &nfa_intent_v1alpha.IntentSpec{
+				IntentPatterns:   []*nfa_intent_v1alpha.IntentPattern{{Pattern: &nfa_intent_v1alpha.IntentPattern_Pattern{Action: "lights_on"}}},
+				QualityOfService: &nfa_intent_v1alpha.QualityOfService{Availability: "99%"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterIntent() error = %v", err)
+	}
+	for _, success := range []bool{true, true, true, false} {
+		if _, err := b.ReportOutcome(ctx, &nfa_broker_v1alpha.ReportOutcomeRequest{ServiceId: resp.ServiceId, Action: "lights_on", Success: success}); err != nil {
+			t.Fatalf("ReportOutcome() error = %v", err)
+		}
+	}
+
+	report := r.Report(Query{})
+	if len(report.Providers) != 1 {
+		t.Fatalf("Report() = %v, want the provider", report.Providers)
+	}
+	p := report.Providers[0]
+	if p.Namespace != "home" || p.Requests != 4 || p.Availability != 0.75 || p.AvailabilityObjective != 0.99 || p.Met {
+		t.Errorf("provider = %v, want 4 outcomes in home missing 99%%", p)
+	}
+	// Outcomes carry no latency
+	if p.LatencyP99Ms != 0 {
+		t.Errorf("p99 latency = %v, want none", p.LatencyP99Ms)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, &nfa_admin_v1alpha.SLAReport{Providers: []*nfa_admin_v1alpha.ProviderSLA{{
+		Namespace:             "home",
+		ServiceId:             "lights-1",
+		Requests:              40,
+		Failures:              10,
+		Availability:          0.75,
+		LatencyP50Ms:          50,
+		LatencyP90Ms:          200,
+		LatencyP99Ms:          300,
+		LatencyObjectiveMs:    100,
+		AvailabilityObjective: 0.9,
+		Violations:            []*nfa_admin_v1alpha.SLAViolation{{Kind: "latency"}, {Kind: "availability"}},
+	}}})
+	if err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("WriteCSV() wrote invalid CSV: %v", err)
+	}
+	want := []string{"home", "lights-1", "40", "10", "0.75000", "50.000", "200.000", "300.000", "100.000", "0.90000", "false", "2"}
+	if len(rows) != 2 || len(rows[0]) != len(want) || rows[0][0] != "namespace" {
+		t.Fatalf("WriteCSV() = %v, want a header and a row", rows)
+	}
+	for i, field := range rows[1] {
+		if field != want[i] {
+			t.Errorf("column %s = %q, want %q", rows[0][i], field, want[i])
+		}
+	}
+}
+
+func TestCompact(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	r := testRecorder(start.Add(time.Hour))
+	for i := 0; i < 10; i++ {
+		for _, id := range []string{"lights-1", "blinds-1"} {
+			r.Observe(Sample{Time: start.Add(time.Duration(i) * time.Minute), ServiceID: id, Success: true})
+		}
+	}
+	if usage := r.Usage(); usage.Entries != 20 || !usage.Oldest.Equal(start) || usage.Bytes <= 0 {
+		t.Fatalf("Usage() = %+v, want 20 samples from the start", usage)
+	}
+	// Samples of both providers older than 5 minutes go first
+	if removed := r.Compact(start.Add(5*time.Minute), 0); removed != 10 {
+		t.Errorf("Compact() by age removed %d, want 10", removed)
+	}
+	size := r.Usage().Bytes / 10
+	if removed := r.Compact(time.Time{}, 4*size); removed != 6 {
+		t.Errorf("Compact() by size removed %d, want 6", removed)
+	}
+	if usage := r.Usage(); usage.Entries != 4 || !usage.Oldest.Equal(start.Add(8*time.Minute)) {
+		t.Errorf("Usage() = %+v, want the 4 latest samples", usage)
+	}
+}
@@ -0,0 +1,155 @@
+// Package badger keeps the registrations of an embedded broker in a Badger
+// database, an embedded key-value store, so they survive restarts of the
+// broker without a separate database server:
+//
+//	store, err := badger.Open("/var/lib/nfa/registry")
+//	defer store.Close()
+//	b := broker.NewEmbedded(broker.WithStore(store))
+//	ids, err := b.Restore()
+//
+// Records have the format of broker.DirStore, with its schema versions and
+// migrations, and are sealed with the keys of WithSealer if set. Expired
+// registrations are deleted by the broker, see broker.Embedded.Restore; the
+// space they took is reclaimed in the background.
+package badger
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	badgerdb "github.com/dgraph-io/badger/v3"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/atrest"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+)
+
+// keyPrefix prefixes the keys of the registrations, by service ID
+const keyPrefix = "registration/"
+
+// gcInterval is how often the value log is garbage collected, reclaiming
+// the space of deleted and replaced records
+const gcInterval = 10 * time.Minute
+
+// gcDiscardRatio is the share of a value log file that must be stale for
+// the garbage collection to rewrite it
+const gcDiscardRatio = 0.5
+
+// Store is a broker.Store in a Badger database
+type Store struct {
+	db     *badgerdb.DB
+	sealer *atrest.Sealer
+	stop   chan struct{}
+	done   sync.WaitGroup
+}
+
+var _ broker.Store = (*Store)(nil)
+
+// Option configures a Store
+type Option func(*Store)
+
+// WithSealer encrypts the records with the keys of sealer, in
+// broker.RegistryNamespace. Records written before sealing was enabled are
+// sealed when loaded.
+func WithSealer(sealer *atrest.Sealer) Option {
+	return func(s *Store) {
+		s.sealer = sealer
+	}
+}
+
+// Open opens the database in dir, creating it if needed. Close releases it.
+func Open(dir string, opts ...Option) (*Store, error) {
+	db, err := badgerdb.Open(badgerdb.DefaultOptions(dir).
+		WithSyncWrites(true).
+		WithLoggingLevel(badgerdb.WARNING))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open registry database: %w", err)
+	}
+	s := &Store{db: db, stop: make(chan struct{})}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.done.Add(1)
+	go s.collectGarbage()
+	return s, nil
+}
+
+// Load implements broker.Store. Records of an older schema version, or
+// written before sealing was enabled, are rewritten.
+func (s *Store) Load() ([]broker.StoredRegistration, error) {
+	var recs, rewrite []broker.StoredRegistration
+	err := s.db.View(func(txn *badgerdb.Txn) error {
+		it := txn.NewIterator(badgerdb.IteratorOptions{Prefix: []byte(keyPrefix)})
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			key := it.Item().Key()
+			data, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return fmt.Errorf("failed to load registration %s: %w", key, err)
+			}
+			rec, upgraded, err := broker.DecodeRecord(data, s.sealer)
+			if err != nil {
+				return fmt.Errorf("failed to load registration %s: %w", key, err)
+			}
+			if upgraded {
+				rewrite = append(rewrite, rec)
+			}
+			recs = append(recs, rec)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range rewrite {
+		if err := s.Put(rec); err != nil {
+			return nil, fmt.Errorf("failed to upgrade registration %s: %w", rec.ServiceID, err)
+		}
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].ServiceID < recs[j].ServiceID })
+	return recs, nil
+}
+
+// Put implements broker.Store
+func (s *Store) Put(rec broker.StoredRegistration) error {
+	data, err := broker.EncodeRecord(rec, s.sealer)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(txn *badgerdb.Txn) error {
+		return txn.Set([]byte(keyPrefix+rec.ServiceID), data)
+	})
+}
+
+// Delete implements broker.Store
+func (s *Store) Delete(serviceID string) error {
+	return s.db.Update(func(txn *badgerdb.Txn) error {
+		return txn.Delete([]byte(keyPrefix + serviceID))
+	})
+}
+
+// Close stops the garbage collection and closes the database
+func (s *Store) Close() error {
+	close(s.stop)
+	s.done.Wait()
+	return s.db.Close()
+}
+
+// collectGarbage reclaims the space of stale records every gcInterval until
+// Close
+func (s *Store) collectGarbage() {
+	defer s.done.Done()
+	ticker := time.NewTicker(gcInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			// Each run rewrites at most one file, and fails once none is
+			// stale enough
+			for s.db.RunValueLogGC(gcDiscardRatio) == nil {
+			}
+		}
+	}
+}
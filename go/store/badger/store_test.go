@@ -0,0 +1,154 @@
+package badger
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	badgerdb "github.com/dgraph-io/badger/v3"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/atrest"
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+)
+
+func open(t *testing.T, dir string, opts ...Option) *Store {
+	t.Helper()
+	s, err := Open(dir, opts...)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	return s
+}
+
+func register(t *testing.T, b *broker.Embedded, name string) string {
+	t.Helper()
+	resp, err := b.RegisterIntent(context.Background(), &nfa_broker_v1alpha.RegisterIntentRequest{
+		Contract: &nfa_intent_v1alpha.IntentContract{Metadata: &nfa_intent_v1alpha.Metadata{Name: name}},
+	})
+	if err != nil {
+		t.Fatalf("RegisterIntent() error = %v", err)
+	}
+	return resp.ServiceId
+}
+
+func TestRestore(t *testing.T) {
+	dir := t.TempDir()
+	s := open(t, dir)
+	b := broker.NewEmbedded(broker.WithStore(s))
+	kept := register(t, b, "translator")
+	gone := register(t, b, "lights")
+	if err := b.SetLabels(kept, map[string]string{"env": "prod"}); err != nil {
+		t.Fatalf("SetLabels() error = %v", err)
+	}
+	b.Remove(gone)
+	b.Close()
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	s = open(t, dir)
+	defer s.Close()
+	b = broker.NewEmbedded(broker.WithStore(s))
+	defer b.Close()
+	ids, err := b.Restore()
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if !slices.Equal(ids, []string{kept}) {
+		t.Fatalf("Restore() = %v, want %s", ids, kept)
+	}
+	if services := b.Services(); len(services) != 1 || !services[0].Live || services[0].Labels["env"] != "prod" {
+		t.Errorf("Services() = %+v, want %s live with its labels", services, kept)
+	}
+}
+
+func TestPutLoadDelete(t *testing.T) {
+	s := open(t, t.TempDir())
+	defer s.Close()
+	expires := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, id := range []string{"b-1", "a-1", "a/2"} {
+		err := s.Put(broker.StoredRegistration{
+			ServiceID: id,
+			Contract:  &nfa_intent_v1alpha.IntentContract{Metadata: &nfa_intent_v1alpha.Metadata{Name: id[:1]}},
+			Expires:   expires,
+		})
+		if err != nil {
+			t.Fatalf("Put(%s) error = %v", id, err)
+		}
+	}
+	if err := s.Delete("b-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := s.Delete("unknown"); err != nil {
+		t.Errorf("Delete() of an unknown registration error = %v, want nil", err)
+	}
+	recs, err := s.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	var ids []string
+	for _, rec := range recs {
+		ids = append(ids, rec.ServiceID)
+		if rec.Contract.Metadata.Name != "a" || !rec.Expires.Equal(expires) {
+			t.Errorf("Load() record = %+v, want it as put", rec)
+		}
+	}
+	if !slices.Equal(ids, []string{"a-1", "a/2"}) {
+		t.Errorf("Load() = %v, want a-1 and a/2", ids)
+	}
+}
+
+func TestSealed(t *testing.T) {
+	keyfile := filepath.Join(t.TempDir(), "keys")
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, atrest.KeySize))
+	if err := os.WriteFile(keyfile, []byte("k1 "+key+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := atrest.LoadKeyFile(keyfile)
+	if err != nil {
+		t.Fatalf("LoadKeyFile() error = %v", err)
+	}
+	rec := broker.StoredRegistration{
+		ServiceID: "translator-1",
+		Contract:  &nfa_intent_v1alpha.IntentContract{Metadata: &nfa_intent_v1alpha.Metadata{Name: "translator"}},
+	}
+	value := func(s *Store) []byte {
+		var data []byte
+		s.db.View(func(txn *badgerdb.Txn) error {
+			item, err := txn.Get([]byte(keyPrefix + rec.ServiceID))
+			if err == nil {
+				data, err = item.ValueCopy(nil)
+			}
+			return err
+		})
+		return data
+	}
+
+	// A record written before sealing was enabled is sealed when loaded
+	dir := t.TempDir()
+	s := open(t, dir)
+	if err := s.Put(rec); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	s.Close()
+	s = open(t, dir, WithSealer(atrest.NewSealer(keys)))
+	if recs, err := s.Load(); err != nil || len(recs) != 1 || recs[0].Contract.Metadata.Name != "translator" {
+		t.Fatalf("Load() of a plaintext record = %+v, %v, want it", recs, err)
+	}
+	if data := value(s); !atrest.IsSealed(data) || bytes.Contains(data, []byte("translator")) {
+		t.Errorf("record after Load = %q, want it sealed", data)
+	}
+	s.Close()
+
+	s = open(t, dir)
+	defer s.Close()
+	if _, err := s.Load(); err == nil {
+		t.Errorf("Load() of sealed records without keys succeeded, want an error")
+	}
+}
@@ -0,0 +1,128 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	nfa_stream_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/stream/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultCheckpointEvery is how many chunks are processed between acks; it
+	// must stay well below the server window or the producer stalls
+	defaultCheckpointEvery = 16
+
+	maxResumeAttempts = 5
+	resumeBackoff     = 500 * time.Millisecond
+)
+
+// ChunkHandler processes one chunk of a stream
+type ChunkHandler func(chunk *nfa_stream_v1alpha.StreamChunk) error
+
+// Client consumes resumable streams, reconnecting after transient failures
+type Client struct {
+	client nfa_stream_v1alpha.ResumableStreamServiceClient
+}
+
+// NewClient creates a stream client on an existing connection to a provider or gateway
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{
+		client: nfa_stream_v1alpha.NewResumableStreamServiceClient(cc),
+	}
+}
+
+// Stream runs a streaming intent and passes every chunk to fn exactly once,
+// in order. When the connection drops mid-stream it reopens the stream with
+// the last resume token and continues after the last chunk received.
+// Processed chunks are acknowledged periodically so the provider can release them.
+func (c *Client) Stream(ctx context.Context, action string, request []byte, fn ChunkHandler) error {
+	req := &nfa_stream_v1alpha.OpenStreamRequest{Action: action, Request: request}
+	var last *nfa_stream_v1alpha.StreamChunk
+	attempts := 0
+
+	for {
+		if last != nil {
+			req = &nfa_stream_v1alpha.OpenStreamRequest{ResumeToken: last.ResumeToken}
+		}
+		done, err := c.receive(ctx, req, fn, &last)
+		if done {
+			return err
+		}
+		if !resumable(err) || last == nil || attempts >= maxResumeAttempts {
+			return fmt.Errorf("stream %s failed: %w", action, err)
+		}
+
+		attempts++
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(resumeBackoff * time.Duration(attempts)):
+		}
+	}
+}
+
+// receive reads one connection's worth of chunks. It reports done when the
+// stream finished or failed in a way that resuming cannot fix.
+func (c *Client) receive(ctx context.Context, req *nfa_stream_v1alpha.OpenStreamRequest, fn ChunkHandler, last **nfa_stream_v1alpha.StreamChunk) (bool, error) {
+	stream, err := c.client.Open(ctx, req)
+	if err != nil {
+		return false, err
+	}
+
+	sinceCheckpoint := 0
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return false, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return ctx.Err() != nil, err
+		}
+		if *last != nil && chunk.Sequence <= (*last).Sequence {
+			continue // replayed chunk that was already handled
+		}
+		*last = chunk
+
+		if chunk.Final {
+			c.checkpoint(ctx, chunk)
+			if chunk.Error != "" {
+				return true, fmt.Errorf("stream producer failed: %s", chunk.Error)
+			}
+			return true, nil
+		}
+		if err := fn(chunk); err != nil {
+			return true, err
+		}
+
+		sinceCheckpoint++
+		if sinceCheckpoint >= defaultCheckpointEvery {
+			c.checkpoint(ctx, chunk)
+			sinceCheckpoint = 0
+		}
+	}
+}
+
+// checkpoint acknowledges processing up to chunk; failures only delay the
+// provider releasing memory, so they are ignored
+func (c *Client) checkpoint(ctx context.Context, chunk *nfa_stream_v1alpha.StreamChunk) {
+	c.client.Ack(ctx, &nfa_stream_v1alpha.AckStreamRequest{
+		StreamId: chunk.StreamId,
+		Sequence: chunk.Sequence,
+	})
+}
+
+func resumable(err error) bool {
+	if err == io.ErrUnexpectedEOF {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal:
+		return true
+	}
+	return false
+}
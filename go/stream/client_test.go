@@ -0,0 +1,117 @@
+package stream
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	nfa_stream_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/stream/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// dropper fails the first Open call with UNAVAILABLE after sending a number
+// of chunks, as a connection lost mid-stream would
+type dropper struct {
+	after int
+
+	mu      sync.Mutex
+	opens   []*nfa_stream_v1alpha.OpenStreamRequest
+	dropped bool
+}
+
+func (d *dropper) intercept(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	d.mu.Lock()
+	drop := !d.dropped
+	d.dropped = true
+	d.mu.Unlock()
+	return handler(srv, &droppingStream{ServerStream: ss, dropper: d, drop: drop})
+}
+
+type droppingStream struct {
+	grpc.ServerStream
+	dropper *dropper
+	drop    bool
+	sent    int
+}
+
+func (s *droppingStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if req, ok := m.(*nfa_stream_v1alpha.OpenStreamRequest); ok {
+		s.dropper.mu.Lock()
+		s.dropper.opens = append(s.dropper.opens, req)
+		s.dropper.mu.Unlock()
+	}
+	return nil
+}
+
+func (s *droppingStream) SendMsg(m interface{}) error {
+	if s.drop && s.sent == s.dropper.after {
+		return status.Error(codes.Unavailable, "connection lost")
+	}
+	s.sent++
+	return s.ServerStream.SendMsg(m)
+}
+
+func TestStreamReconnects(t *testing.T) {
+	s := NewServer(0, 0)
+	s.Handle("count", counter(40))
+	d := &dropper{after: 10}
+	c := NewClient(dial(t, s, grpc.StreamInterceptor(d.intercept)))
+
+	var got []int
+	err := c.Stream(context.Background(), "count", nil, func(chunk *nfa_stream_v1alpha.StreamChunk) error {
+		n, err := strconv.Atoi(string(chunk.Payload))
+		if err != nil {
+			return err
+		}
+		got = append(got, n)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	if len(got) != 40 {
+		t.Fatalf("Stream() handled %d chunks, want 40", len(got))
+	}
+	for i, n := range got {
+		if n != i+1 {
+			t.Fatalf("chunk %d = %d, want every chunk once and in order", i, n)
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.opens) != 2 || d.opens[0].Action != "count" {
+		t.Fatalf("Open() calls = %v, want the stream opened then resumed", d.opens)
+	}
+	// The client resumes after the last chunk it received before the drop
+	id, seq, err := ParseResumeToken(d.opens[1].ResumeToken)
+	if err != nil || id == "" || seq != 10 {
+		t.Errorf("resume token = %q, %d, %v, want chunk 10", id, seq, err)
+	}
+}
+
+func TestStreamExpired(t *testing.T) {
+	// The stream expires before the client backs off and resumes
+	s := NewServer(time.Millisecond, 0)
+	s.Handle("count", counter(40))
+	c := NewClient(dial(t, s, grpc.StreamInterceptor((&dropper{after: 5}).intercept)))
+
+	handled := 0
+	err := c.Stream(context.Background(), "count", nil, func(*nfa_stream_v1alpha.StreamChunk) error {
+		handled++
+		return nil
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Stream() error = %v, want NOT_FOUND", err)
+	}
+	if handled != 5 {
+		t.Errorf("Stream() handled %d chunks, want the 5 before the drop", handled)
+	}
+}
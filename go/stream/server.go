@@ -0,0 +1,358 @@
+// Package stream implements resumable result streams for long-running
+// streaming intents. Providers write chunks through a Writer; the server
+// numbers them, retains unacknowledged chunks and lets a consumer that
+// reconnects continue from its last resume token.
+package stream
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	nfa_stream_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/stream/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultResumeWindow is how long a stream survives without a connected consumer
+	DefaultResumeWindow = 2 * time.Minute
+
+	// DefaultWindow is the number of unacknowledged chunks retained before
+	// the producer is blocked. Consumers must checkpoint more often than this.
+	DefaultWindow = 256
+)
+
+// Producer generates the chunks of a streaming intent. ctx is cancelled when
+// the consumer does not resume within the resume window.
+type Producer func(ctx context.Context, req *nfa_stream_v1alpha.OpenStreamRequest, w *Writer) error
+
+// Server serves resumable streams for registered producers
+type Server struct {
+	nfa_stream_v1alpha.UnimplementedResumableStreamServiceServer
+
+	resumeWindow time.Duration
+	window       int
+
+	mu        sync.Mutex
+	producers map[string]Producer
+	streams   map[string]*resumableStream
+}
+
+type resumableStream struct {
+	id     string
+	cancel context.CancelFunc
+	window int
+
+	mu       sync.Mutex
+	changed  chan struct{} // closed and replaced whenever the stream changes
+	chunks   []*nfa_stream_v1alpha.StreamChunk
+	nextSeq  uint64
+	done     bool
+	attached int
+	writer   uint64 // generation of the connection allowed to send chunks
+	expiry   *time.Timer
+}
+
+// NewServer creates a stream server; zero values select DefaultResumeWindow
+// and DefaultWindow
+func NewServer(resumeWindow time.Duration, window int) *Server {
+	if resumeWindow <= 0 {
+		resumeWindow = DefaultResumeWindow
+	}
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	return &Server{
+		resumeWindow: resumeWindow,
+		window:       window,
+		producers:    make(map[string]Producer),
+		streams:      make(map[string]*resumableStream),
+	}
+}
+
+// Register registers the stream service on a gRPC server
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	nfa_stream_v1alpha.RegisterResumableStreamServiceServer(registrar, s)
+}
+
+// Handle registers the producer for a streaming intent action
+func (s *Server) Handle(action string, producer Producer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.producers[action] = producer
+}
+
+// Open implements the Open RPC
+func (s *Server) Open(req *nfa_stream_v1alpha.OpenStreamRequest, out nfa_stream_v1alpha.ResumableStreamService_OpenServer) error {
+	var st *resumableStream
+	from := uint64(1)
+
+	if req.ResumeToken != "" {
+		id, seq, err := ParseResumeToken(req.ResumeToken)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		s.mu.Lock()
+		st = s.streams[id]
+		s.mu.Unlock()
+		if st == nil {
+			return status.Errorf(codes.NotFound, "stream %s has expired, restart the intent", id)
+		}
+		// Everything up to the token was received
+		if err := st.ack(seq); err != nil {
+			return err
+		}
+		from = seq + 1
+	} else {
+		s.mu.Lock()
+		producer, ok := s.producers[req.Action]
+		s.mu.Unlock()
+		if !ok {
+			return status.Errorf(codes.Unimplemented, "no stream producer for action %s", req.Action)
+		}
+		st = s.start(req, producer)
+	}
+
+	generation := st.attach()
+	defer st.detach(s.resumeWindow, func() { s.expire(st) })
+
+	for {
+		chunks, changed, err := st.pending(from, generation)
+		if err != nil {
+			return err
+		}
+		for _, chunk := range chunks {
+			if err := out.Send(chunk); err != nil {
+				return err
+			}
+			from = chunk.Sequence + 1
+			if chunk.Final {
+				return nil
+			}
+		}
+
+		select {
+		case <-out.Context().Done():
+			return nil
+		case <-changed:
+		}
+	}
+}
+
+// Ack implements the Ack RPC
+func (s *Server) Ack(ctx context.Context, req *nfa_stream_v1alpha.AckStreamRequest) (*nfa_stream_v1alpha.AckStreamResponse, error) {
+	s.mu.Lock()
+	st := s.streams[req.StreamId]
+	s.mu.Unlock()
+	if st == nil {
+		return nil, status.Errorf(codes.NotFound, "stream %s not found", req.StreamId)
+	}
+	if err := st.ack(req.Sequence); err != nil {
+		return nil, err
+	}
+	return &nfa_stream_v1alpha.AckStreamResponse{}, nil
+}
+
+func (s *Server) start(req *nfa_stream_v1alpha.OpenStreamRequest, producer Producer) *resumableStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	st := &resumableStream{
+		id:      newStreamID(),
+		cancel:  cancel,
+		window:  s.window,
+		changed: make(chan struct{}),
+		nextSeq: 1,
+	}
+	s.mu.Lock()
+	s.streams[st.id] = st
+	s.mu.Unlock()
+
+	go func() {
+		err := producer(ctx, req, &Writer{stream: st, ctx: ctx})
+		st.finish(err)
+	}()
+	return st
+}
+
+func (s *Server) expire(st *resumableStream) {
+	s.mu.Lock()
+	if s.streams[st.id] == st {
+		delete(s.streams, st.id)
+	}
+	s.mu.Unlock()
+	st.cancel()
+
+	st.mu.Lock()
+	done := st.done
+	st.mu.Unlock()
+	if !done {
+		log.Printf("Stream %s expired without a consumer", st.id)
+	}
+}
+
+// Writer appends chunks to a resumable stream
+type Writer struct {
+	stream *resumableStream
+	ctx    context.Context
+}
+
+// Send appends a chunk and returns its sequence number. It blocks while the
+// retention window is full of unacknowledged chunks.
+func (w *Writer) Send(payload []byte) (uint64, error) {
+	st := w.stream
+	for {
+		st.mu.Lock()
+		if st.done {
+			st.mu.Unlock()
+			return 0, fmt.Errorf("stream %s is closed", st.id)
+		}
+		if len(st.chunks) < st.window {
+			seq := st.append(payload, false, "")
+			st.mu.Unlock()
+			return seq, nil
+		}
+		changed := st.changed
+		st.mu.Unlock()
+
+		select {
+		case <-w.ctx.Done():
+			return 0, w.ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// pending returns retained chunks from sequence from onwards and a channel
+// closed on the next change. Only the most recent connection receives
+// chunks, so a consumer never sees two interleaved copies of the stream.
+func (st *resumableStream) pending(from, generation uint64) ([]*nfa_stream_v1alpha.StreamChunk, chan struct{}, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if generation != st.writer {
+		return nil, nil, status.Errorf(codes.Aborted, "stream %s was resumed on another connection", st.id)
+	}
+	if len(st.chunks) > 0 && from < st.chunks[0].Sequence {
+		return nil, nil, status.Errorf(codes.OutOfRange, "chunk %d of stream %s was already acknowledged", from, st.id)
+	}
+	var chunks []*nfa_stream_v1alpha.StreamChunk
+	for _, chunk := range st.chunks {
+		if chunk.Sequence >= from {
+			chunks = append(chunks, chunk)
+		}
+	}
+	return chunks, st.changed, nil
+}
+
+// ack drops retained chunks up to and including seq
+func (st *resumableStream) ack(seq uint64) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if seq >= st.nextSeq {
+		return status.Errorf(codes.OutOfRange, "chunk %d of stream %s has not been sent", seq, st.id)
+	}
+	n := 0
+	for n < len(st.chunks) && st.chunks[n].Sequence <= seq {
+		n++
+	}
+	st.chunks = st.chunks[n:]
+	st.notify()
+	return nil
+}
+
+func (st *resumableStream) finish(err error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.done {
+		return
+	}
+	message := ""
+	if err != nil {
+		message = err.Error()
+	}
+	st.append(nil, true, message)
+	st.done = true
+}
+
+// append adds a chunk; mu must be held
+func (st *resumableStream) append(payload []byte, final bool, errMessage string) uint64 {
+	seq := st.nextSeq
+	st.nextSeq++
+	st.chunks = append(st.chunks, &nfa_stream_v1alpha.StreamChunk{
+		StreamId:    st.id,
+		Sequence:    seq,
+		Payload:     payload,
+		Final:       final,
+		Error:       errMessage,
+		ResumeToken: ResumeToken(st.id, seq),
+	})
+	st.notify()
+	return seq
+}
+
+// notify wakes everyone waiting on the stream; mu must be held
+func (st *resumableStream) notify() {
+	close(st.changed)
+	st.changed = make(chan struct{})
+}
+
+// attach makes a new connection the stream's only writer and returns its generation
+func (st *resumableStream) attach() uint64 {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.attached++
+	if st.expiry != nil {
+		st.expiry.Stop()
+		st.expiry = nil
+	}
+	st.writer++
+	st.notify() // wake a superseded connection so it ends
+	return st.writer
+}
+
+// detach starts the resume window once the last consumer is gone
+func (st *resumableStream) detach(resumeWindow time.Duration, expire func()) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.attached--
+	if st.attached == 0 {
+		st.expiry = time.AfterFunc(resumeWindow, expire)
+	}
+}
+
+// ResumeToken encodes the position of a chunk in a stream
+func ResumeToken(streamID string, sequence uint64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(streamID + ":" + strconv.FormatUint(sequence, 10)))
+}
+
+// ParseResumeToken decodes a token created by ResumeToken
+func ParseResumeToken(token string) (string, uint64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", 0, fmt.Errorf("malformed resume token")
+	}
+	id, seq, ok := strings.Cut(string(data), ":")
+	if !ok {
+		return "", 0, fmt.Errorf("malformed resume token")
+	}
+	sequence, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("malformed resume token")
+	}
+	return id, sequence, nil
+}
+
+func newStreamID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,149 @@
+package stream
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	nfa_stream_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/stream/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial serves s in memory and returns a connection to it
+func dial(t *testing.T, s *Server, opts ...grpc.ServerOption) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(opts...)
+	s.Register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// counter produces n chunks holding their numbers
+func counter(n int) Producer {
+	return func(ctx context.Context, req *nfa_stream_v1alpha.OpenStreamRequest, w *Writer) error {
+		for i := 1; i <= n; i++ {
+			if _, err := w.Send([]byte(strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// recvN receives n chunks of a stream
+func recvN(t *testing.T, stream nfa_stream_v1alpha.ResumableStreamService_OpenClient, n int) []*nfa_stream_v1alpha.StreamChunk {
+	t.Helper()
+	chunks := make([]*nfa_stream_v1alpha.StreamChunk, 0, n)
+	for len(chunks) < n {
+		chunk, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+func TestResumeFromAck(t *testing.T) {
+	s := NewServer(0, 0)
+	s.Handle("count", counter(10))
+	client := nfa_stream_v1alpha.NewResumableStreamServiceClient(dial(t, s))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Open(ctx, &nfa_stream_v1alpha.OpenStreamRequest{Action: "count"})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	chunks := recvN(t, stream, 5)
+	id := chunks[0].StreamId
+	if _, err := client.Ack(context.Background(), &nfa_stream_v1alpha.AckStreamRequest{StreamId: id, Sequence: 3}); err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+	cancel()
+
+	// Resuming from the last acknowledged chunk replays the unacknowledged ones
+	stream, err = client.Open(context.Background(), &nfa_stream_v1alpha.OpenStreamRequest{ResumeToken: chunks[2].ResumeToken})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	resumed := recvN(t, stream, 8)
+	for i, chunk := range resumed {
+		if want := uint64(i + 4); chunk.Sequence != want || chunk.StreamId != id {
+			t.Errorf("resumed chunk %d = %v, want sequence %d of %s", i, chunk, want, id)
+		}
+	}
+	if last := resumed[len(resumed)-1]; !last.Final || last.Error != "" {
+		t.Errorf("last chunk = %v, want a final chunk without error", last)
+	}
+
+	// Chunks before the acknowledged one are gone
+	stream, err = client.Open(context.Background(), &nfa_stream_v1alpha.OpenStreamRequest{ResumeToken: chunks[1].ResumeToken})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.OutOfRange {
+		t.Errorf("Recv() resuming before the acknowledged chunk error = %v, want OUT_OF_RANGE", err)
+	}
+}
+
+func TestResumeExpired(t *testing.T) {
+	s := NewServer(10*time.Millisecond, 0)
+	s.Handle("count", counter(10))
+	client := nfa_stream_v1alpha.NewResumableStreamServiceClient(dial(t, s))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Open(ctx, &nfa_stream_v1alpha.OpenStreamRequest{Action: "count"})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	token := recvN(t, stream, 1)[0].ResumeToken
+	cancel()
+
+	// The stream expires once no consumer resumed it within the window
+	var code codes.Code
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		stream, err := client.Open(context.Background(), &nfa_stream_v1alpha.OpenStreamRequest{ResumeToken: token})
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		_, err = stream.Recv()
+		if code = status.Code(err); code == codes.NotFound {
+			break
+		}
+	}
+	if code != codes.NotFound {
+		t.Errorf("Recv() with an expired token = %v, want NOT_FOUND", code)
+	}
+
+	for _, token := range []string{"not base64!", ResumeToken("stream", 1)[:4]} {
+		stream, err := client.Open(context.Background(), &nfa_stream_v1alpha.OpenStreamRequest{ResumeToken: token})
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Recv() with token %q error = %v, want INVALID_ARGUMENT", token, err)
+		}
+	}
+}
+
+func TestParseResumeToken(t *testing.T) {
+	id, seq, err := ParseResumeToken(ResumeToken("abc", 42))
+	if err != nil || id != "abc" || seq != 42 {
+		t.Errorf("ParseResumeToken() = %q, %d, %v, want abc and 42", id, seq, err)
+	}
+}
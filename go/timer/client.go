@@ -0,0 +1,90 @@
+package timer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	nfa_timer_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/timer/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	maxCreateAttempts = 3
+	createBackoff     = 500 * time.Millisecond
+)
+
+// Client creates, lists and cancels timers
+type Client struct {
+	client nfa_timer_v1alpha.TimerServiceClient
+}
+
+// NewClient creates a timer client on an existing connection to the timer service
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{
+		client: nfa_timer_v1alpha.NewTimerServiceClient(cc),
+	}
+}
+
+// Schedule creates a timer broadcasting intent to the runtimes matching
+// selector at fireTime. A zero maxLateness selects DefaultMaxLateness. The
+// call is retried while the broker is unavailable, with a request ID that
+// keeps the retries from creating the timer twice.
+func (c *Client) Schedule(ctx context.Context, fireTime time.Time, maxLateness time.Duration, selector map[string]string, intent *nfa_control_v1alpha.Invoke) (*nfa_timer_v1alpha.Timer, error) {
+	req := &nfa_timer_v1alpha.CreateTimerRequest{
+		Broadcast: &nfa_control_v1alpha.BroadcastRequest{
+			Selector: selector,
+			Intent:   intent,
+		},
+		When:            &nfa_timer_v1alpha.CreateTimerRequest_FireTime{FireTime: timestamppb.New(fireTime)},
+		MaxLatenessSecs: uint32(maxLateness / time.Second),
+		RequestId:       newRequestID(),
+	}
+	var err error
+	for attempt := 0; attempt < maxCreateAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, err
+			case <-time.After(createBackoff << (attempt - 1)):
+			}
+		}
+		var t *nfa_timer_v1alpha.Timer
+		t, err = c.client.CreateTimer(ctx, req)
+		if status.Code(err) != codes.Unavailable {
+			return t, err
+		}
+	}
+	return nil, err
+}
+
+// Get returns a timer
+func (c *Client) Get(ctx context.Context, id string) (*nfa_timer_v1alpha.Timer, error) {
+	return c.client.GetTimer(ctx, &nfa_timer_v1alpha.GetTimerRequest{Id: id})
+}
+
+// List returns the timers in state, or every timer when state is
+// unspecified, ordered by fire time
+func (c *Client) List(ctx context.Context, state nfa_timer_v1alpha.TimerState) ([]*nfa_timer_v1alpha.Timer, error) {
+	resp, err := c.client.ListTimers(ctx, &nfa_timer_v1alpha.ListTimersRequest{State: state})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Timers, nil
+}
+
+// Cancel cancels a pending timer and returns it
+func (c *Client) Cancel(ctx context.Context, id string) (*nfa_timer_v1alpha.Timer, error) {
+	return c.client.CancelTimer(ctx, &nfa_timer_v1alpha.CancelTimerRequest{Id: id})
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,450 @@
+// Package timer implements intents broadcast once at a future time, e.g.
+// "start the coffee machine at 7am". The broker keeps each timer in a file
+// of its timer directory, so pending timers survive its restarts, and fires
+// it through the control hub's broadcast service. A timer is retried while
+// no target handles its intent, until its max lateness has passed.
+package timer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	nfa_timer_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/timer/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// DefaultMaxLateness is how long after its fire time a timer is still
+	// delivered when it sets no max lateness of its own
+	DefaultMaxLateness = time.Hour
+	// Retention is how long finished timers are kept for GetTimer and ListTimers
+	Retention = 24 * time.Hour
+
+	maxDelay           = 366 * 24 * time.Hour
+	minRetryBackoff    = time.Second
+	maxRetryBackoff    = time.Minute
+	timerFileSuffix    = ".timer"
+	timerIDRandomBytes = 8
+)
+
+// Broadcaster delivers the intents of timers; *control.Hub implements it
+type Broadcaster interface {
+	Broadcast(ctx context.Context, req *nfa_control_v1alpha.BroadcastRequest) (*nfa_control_v1alpha.BroadcastResponse, error)
+}
+
+// Scheduler serves the TimerService API and fires the timers it holds while
+// Run is running
+type Scheduler struct {
+	nfa_timer_v1alpha.UnimplementedTimerServiceServer
+
+	dir         string
+	broadcaster Broadcaster
+	now         func() time.Time
+
+	mu       sync.Mutex
+	timers   map[string]*entry
+	requests map[string]string // request ID -> timer ID
+	wake     chan struct{}
+}
+
+type entry struct {
+	timer *nfa_timer_v1alpha.Timer
+	// retry is when a pending timer whose delivery failed is tried again
+	retry   time.Time
+	backoff time.Duration
+	firing  bool
+}
+
+// NewScheduler creates a scheduler delivering timers through b and loads the
+// timers stored under dir. An empty dir keeps timers in memory only, so they
+// are lost when the process ends.
+func NewScheduler(dir string, b Broadcaster) (*Scheduler, error) {
+	s := &Scheduler{
+		dir:         dir,
+		broadcaster: b,
+		now:         time.Now,
+		timers:      make(map[string]*entry),
+		requests:    make(map[string]string),
+		wake:        make(chan struct{}, 1),
+	}
+	if dir == "" {
+		return s, nil
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create timer directory: %w", err)
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read timer directory: %w", err)
+	}
+	for _, f := range files {
+		name := f.Name()
+		if strings.HasPrefix(name, "tmp-") {
+			// Left over by a write interrupted before its rename
+			os.Remove(filepath.Join(dir, name))
+			continue
+		}
+		if f.IsDir() || !strings.HasSuffix(name, timerFileSuffix) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load timer %s: %w", name, err)
+		}
+		t := &nfa_timer_v1alpha.Timer{}
+		if err := proto.Unmarshal(data, t); err != nil {
+			return nil, fmt.Errorf("failed to load timer %s: %w", name, err)
+		}
+		s.timers[t.Id] = &entry{timer: t}
+		if t.RequestId != "" {
+			s.requests[t.RequestId] = t.Id
+		}
+	}
+	s.sweep()
+	return s, nil
+}
+
+// Register registers the timer service on a gRPC server
+func (s *Scheduler) Register(registrar grpc.ServiceRegistrar) {
+	nfa_timer_v1alpha.RegisterTimerServiceServer(registrar, s)
+}
+
+// CreateTimer implements the CreateTimer RPC
+func (s *Scheduler) CreateTimer(ctx context.Context, req *nfa_timer_v1alpha.CreateTimerRequest) (*nfa_timer_v1alpha.Timer, error) {
+	if req.GetBroadcast().GetIntent().GetAction() == "" {
+		return nil, status.Error(codes.InvalidArgument, "broadcast of an intent with an action is required")
+	}
+	now := s.now()
+	var fireTime time.Time
+	switch when := req.When.(type) {
+	case *nfa_timer_v1alpha.CreateTimerRequest_FireTime:
+		if err := when.FireTime.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid fire time: %v", err)
+		}
+		fireTime = when.FireTime.AsTime()
+	case *nfa_timer_v1alpha.CreateTimerRequest_DelaySecs:
+		fireTime = now.Add(time.Duration(when.DelaySecs) * time.Second)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "fire_time or delay_secs is required")
+	}
+	if fireTime.Sub(now) > maxDelay {
+		return nil, status.Errorf(codes.InvalidArgument, "fire time cannot be more than %s ahead", maxDelay)
+	}
+	maxLateness := req.MaxLatenessSecs
+	if maxLateness == 0 {
+		maxLateness = uint32(DefaultMaxLateness / time.Second)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.requests[req.RequestId]; ok && req.RequestId != "" {
+		if e, ok := s.timers[id]; ok {
+			return proto.Clone(e.timer).(*nfa_timer_v1alpha.Timer), nil
+		}
+	}
+	t := &nfa_timer_v1alpha.Timer{
+		Id:              newTimerID(),
+		Broadcast:       proto.Clone(req.Broadcast).(*nfa_control_v1alpha.BroadcastRequest),
+		FireTime:        timestamppb.New(fireTime),
+		MaxLatenessSecs: maxLateness,
+		State:           nfa_timer_v1alpha.TimerState_TIMER_STATE_PENDING,
+		CreateTime:      timestamppb.New(now),
+		RequestId:       req.RequestId,
+	}
+	if err := s.store(t); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store timer: %v", err)
+	}
+	s.timers[t.Id] = &entry{timer: t}
+	if t.RequestId != "" {
+		s.requests[t.RequestId] = t.Id
+	}
+	s.notify()
+	return proto.Clone(t).(*nfa_timer_v1alpha.Timer), nil
+}
+
+// GetTimer implements the GetTimer RPC
+func (s *Scheduler) GetTimer(ctx context.Context, req *nfa_timer_v1alpha.GetTimerRequest) (*nfa_timer_v1alpha.Timer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.timers[req.Id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "timer %s not found", req.Id)
+	}
+	return proto.Clone(e.timer).(*nfa_timer_v1alpha.Timer), nil
+}
+
+// ListTimers implements the ListTimers RPC
+func (s *Scheduler) ListTimers(ctx context.Context, req *nfa_timer_v1alpha.ListTimersRequest) (*nfa_timer_v1alpha.ListTimersResponse, error) {
+	s.mu.Lock()
+	resp := &nfa_timer_v1alpha.ListTimersResponse{}
+	for _, e := range s.timers {
+		if req.State != nfa_timer_v1alpha.TimerState_TIMER_STATE_UNSPECIFIED && e.timer.State != req.State {
+			continue
+		}
+		if req.Action != "" && e.timer.Broadcast.GetIntent().GetAction() != req.Action {
+			continue
+		}
+		resp.Timers = append(resp.Timers, proto.Clone(e.timer).(*nfa_timer_v1alpha.Timer))
+	}
+	s.mu.Unlock()
+	sort.Slice(resp.Timers, func(i, j int) bool {
+		a, b := resp.Timers[i].FireTime.AsTime(), resp.Timers[j].FireTime.AsTime()
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return resp.Timers[i].Id < resp.Timers[j].Id
+	})
+	return resp, nil
+}
+
+// CancelTimer implements the CancelTimer RPC. A timer whose intent is being
+// broadcast is cancelled, but the broadcast is not recalled.
+func (s *Scheduler) CancelTimer(ctx context.Context, req *nfa_timer_v1alpha.CancelTimerRequest) (*nfa_timer_v1alpha.Timer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.timers[req.Id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "timer %s not found", req.Id)
+	}
+	if e.timer.State != nfa_timer_v1alpha.TimerState_TIMER_STATE_PENDING {
+		return nil, status.Errorf(codes.FailedPrecondition, "timer %s is %s", req.Id, stateName(e.timer.State))
+	}
+	t := proto.Clone(e.timer).(*nfa_timer_v1alpha.Timer)
+	t.State = nfa_timer_v1alpha.TimerState_TIMER_STATE_CANCELLED
+	t.FinishTime = timestamppb.New(s.now())
+	if err := s.store(t); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store timer: %v", err)
+	}
+	e.timer = t
+	return proto.Clone(t).(*nfa_timer_v1alpha.Timer), nil
+}
+
+// Run fires due timers until ctx ends, including those whose fire time
+// passed while the broker was down, and returns ctx's error
+func (s *Scheduler) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		s.sweep()
+		due, next := s.due()
+		for _, e := range due {
+			wg.Add(1)
+			go func(e *entry) {
+				defer wg.Done()
+				s.fire(ctx, e)
+			}(e)
+		}
+
+		var wait <-chan time.Time
+		var t *time.Timer
+		if !next.IsZero() {
+			t = time.NewTimer(next.Sub(s.now()))
+			wait = t.C
+		}
+		select {
+		case <-ctx.Done():
+		case <-s.wake:
+		case <-wait:
+		}
+		if t != nil {
+			t.Stop()
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// due marks the pending timers to fire now as firing and returns them, with
+// the time the next one is due; zero when none is
+func (s *Scheduler) due() ([]*entry, time.Time) {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []*entry
+	var next time.Time
+	for _, e := range s.timers {
+		if e.firing || e.timer.State != nfa_timer_v1alpha.TimerState_TIMER_STATE_PENDING {
+			continue
+		}
+		at := e.timer.FireTime.AsTime()
+		if e.retry.After(at) {
+			at = e.retry
+		}
+		if !at.After(now) {
+			e.firing = true
+			due = append(due, e)
+		} else if next.IsZero() || at.Before(next) {
+			next = at
+		}
+	}
+	return due, next
+}
+
+// fire broadcasts the intent of e and records the outcome. A timer past its
+// max lateness fails without being broadcast again.
+func (s *Scheduler) fire(ctx context.Context, e *entry) {
+	s.mu.Lock()
+	t := proto.Clone(e.timer).(*nfa_timer_v1alpha.Timer)
+	s.mu.Unlock()
+
+	deadline := t.FireTime.AsTime().Add(time.Duration(t.MaxLatenessSecs) * time.Second)
+	var targets []*nfa_control_v1alpha.TargetStatus
+	var err error
+	switch {
+	case s.now().After(deadline) && t.Attempts == 0:
+		err = fmt.Errorf("missed its fire time by more than %ds", t.MaxLatenessSecs)
+	case s.now().After(deadline):
+		// Retried until its max lateness; the last failure stands
+		err = errors.New(t.LastError)
+		targets = t.Targets
+	default:
+		targets, err = s.broadcast(ctx, t.Broadcast)
+		if ctx.Err() != nil {
+			// Stopped while broadcasting; the timer fires again on the next run
+			s.mu.Lock()
+			e.firing = false
+			s.mu.Unlock()
+			return
+		}
+		t.Attempts++
+	}
+
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.firing = false
+	if e.timer.State != nfa_timer_v1alpha.TimerState_TIMER_STATE_PENDING {
+		// Cancelled while broadcasting
+		return
+	}
+	switch {
+	case err == nil:
+		t.State = nfa_timer_v1alpha.TimerState_TIMER_STATE_DELIVERED
+		t.LastError = ""
+	case now.After(deadline):
+		t.State = nfa_timer_v1alpha.TimerState_TIMER_STATE_FAILED
+		t.LastError = err.Error()
+	default:
+		t.LastError = err.Error()
+		e.backoff = min(max(2*e.backoff, minRetryBackoff), maxRetryBackoff)
+		e.retry = now.Add(e.backoff)
+	}
+	t.Targets = targets
+	if t.State != nfa_timer_v1alpha.TimerState_TIMER_STATE_PENDING {
+		t.FinishTime = timestamppb.New(now)
+	}
+	if err := s.store(t); err != nil {
+		// Kept in memory; the outcome is lost if the broker stops before
+		// the next successful write
+		t.LastError = fmt.Sprintf("failed to store timer: %v", err)
+	}
+	e.timer = t
+	// Run skipped the timer while it was firing
+	s.notify()
+}
+
+// broadcast sends req and returns the target statuses, with an error unless
+// at least one target handled the intent
+func (s *Scheduler) broadcast(ctx context.Context, req *nfa_control_v1alpha.BroadcastRequest) ([]*nfa_control_v1alpha.TargetStatus, error) {
+	resp, err := s.broadcaster.Broadcast(ctx, proto.Clone(req).(*nfa_control_v1alpha.BroadcastRequest))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Targets) == 0 {
+		return nil, errors.New("no runtime matched the selector")
+	}
+	var failures []string
+	for _, target := range resp.Targets {
+		if target.State == nfa_control_v1alpha.TargetState_TARGET_STATE_SUCCEEDED {
+			return resp.Targets, nil
+		}
+		failure := strings.ToLower(strings.TrimPrefix(target.State.String(), "TARGET_STATE_"))
+		if target.Error != "" {
+			failure += ": " + target.Error
+		}
+		failures = append(failures, target.RuntimeId+" "+failure)
+	}
+	sort.Strings(failures)
+	return resp.Targets, fmt.Errorf("no target handled the intent: %s", strings.Join(failures, "; "))
+}
+
+// sweep forgets the timers that finished longer than Retention ago
+func (s *Scheduler) sweep() {
+	cutoff := s.now().Add(-Retention)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, e := range s.timers {
+		if e.timer.FinishTime != nil && e.timer.FinishTime.AsTime().Before(cutoff) {
+			delete(s.timers, id)
+			delete(s.requests, e.timer.RequestId)
+			if s.dir != "" {
+				os.Remove(s.path(id))
+			}
+		}
+	}
+}
+
+// store writes t to its file, replacing the previous version atomically
+func (s *Scheduler) store(t *nfa_timer_v1alpha.Timer) error {
+	if s.dir == "" {
+		return nil
+	}
+	data, err := proto.Marshal(t)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(t.Id))
+}
+
+// notify wakes Run to reconsider the next due timer
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) path(id string) string {
+	return filepath.Join(s.dir, id+timerFileSuffix)
+}
+
+func stateName(state nfa_timer_v1alpha.TimerState) string {
+	return strings.ToLower(strings.TrimPrefix(state.String(), "TIMER_STATE_"))
+}
+
+func newTimerID() string {
+	b := make([]byte, timerIDRandomBytes)
+	rand.Read(b)
+	return "timer-" + hex.EncodeToString(b)
+}
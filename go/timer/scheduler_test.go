@@ -0,0 +1,286 @@
+package timer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	nfa_control_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/control/v1alpha"
+	nfa_timer_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/timer/v1alpha"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeBroadcaster records the actions broadcast and answers with state
+type fakeBroadcaster struct {
+	mu      sync.Mutex
+	state   nfa_control_v1alpha.TargetState
+	actions []string
+	fired   chan string
+}
+
+func newFakeBroadcaster(state nfa_control_v1alpha.TargetState) *fakeBroadcaster {
+	return &fakeBroadcaster{state: state, fired: make(chan string, 10)}
+}
+
+func (b *fakeBroadcaster) Broadcast(ctx context.Context, req *nfa_control_v1alpha.BroadcastRequest) (*nfa_control_v1alpha.BroadcastResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.actions = append(b.actions, req.Intent.Action)
+	b.fired <- req.Intent.Action
+	return &nfa_control_v1alpha.BroadcastResponse{Targets: []*nfa_control_v1alpha.TargetStatus{
+		{RuntimeId: "rt-1", State: b.state, Error: "offline"},
+	}}, nil
+}
+
+func (b *fakeBroadcaster) calls() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.actions)
+}
+
+func newScheduler(t *testing.T, dir string, b Broadcaster) *Scheduler {
+	t.Helper()
+	s, err := NewScheduler(dir, b)
+	if err != nil {
+		t.Fatalf("NewScheduler() error = %v", err)
+	}
+	return s
+}
+
+func createAt(t *testing.T, s *Scheduler, action string, fireTime time.Time, requestID string) *nfa_timer_v1alpha.Timer {
+	t.Helper()
+	timer, err := s.CreateTimer(context.Background(), &nfa_timer_v1alpha.CreateTimerRequest{
+		Broadcast: &nfa_control_v1alpha.BroadcastRequest{Intent: &nfa_control_v1alpha.Invoke{Action: action}},
+		When:      &nfa_timer_v1alpha.CreateTimerRequest_FireTime{FireTime: timestamppb.New(fireTime)},
+		RequestId: requestID,
+	})
+	if err != nil {
+		t.Fatalf("CreateTimer() error = %v", err)
+	}
+	return timer
+}
+
+func getState(t *testing.T, s *Scheduler, id string) *nfa_timer_v1alpha.Timer {
+	t.Helper()
+	timer, err := s.GetTimer(context.Background(), &nfa_timer_v1alpha.GetTimerRequest{Id: id})
+	if err != nil {
+		t.Fatalf("GetTimer() error = %v", err)
+	}
+	return timer
+}
+
+// waitForState waits until the timer id is in state
+func waitForState(t *testing.T, s *Scheduler, id string, state nfa_timer_v1alpha.TimerState) *nfa_timer_v1alpha.Timer {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		timer := getState(t, s, id)
+		if timer.State == state {
+			return timer
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timer %s is %s, want %s", id, stateName(timer.State), stateName(state))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCreateTimerValidation(t *testing.T) {
+	s := newScheduler(t, "", newFakeBroadcaster(nfa_control_v1alpha.TargetState_TARGET_STATE_SUCCEEDED))
+	intent := &nfa_control_v1alpha.BroadcastRequest{Intent: &nfa_control_v1alpha.Invoke{Action: "brew"}}
+	tests := []struct {
+		name string
+		req  *nfa_timer_v1alpha.CreateTimerRequest
+	}{
+		{"no action", &nfa_timer_v1alpha.CreateTimerRequest{When: &nfa_timer_v1alpha.CreateTimerRequest_DelaySecs{DelaySecs: 1}}},
+		{"no fire time", &nfa_timer_v1alpha.CreateTimerRequest{Broadcast: intent}},
+		{"too far ahead", &nfa_timer_v1alpha.CreateTimerRequest{
+			Broadcast: intent,
+			When:      &nfa_timer_v1alpha.CreateTimerRequest_FireTime{FireTime: timestamppb.New(time.Now().Add(maxDelay + time.Hour))},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.CreateTimer(context.Background(), tt.req); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("CreateTimer() error = %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
+
+func TestCreateTimerOnce(t *testing.T) {
+	s := newScheduler(t, "", newFakeBroadcaster(nfa_control_v1alpha.TargetState_TARGET_STATE_SUCCEEDED))
+	fireTime := time.Now().Add(time.Hour)
+	first := createAt(t, s, "brew", fireTime, "req-1")
+	if again := createAt(t, s, "brew", fireTime, "req-1"); again.Id != first.Id {
+		t.Errorf("CreateTimer() retried = %s, want the timer created first, %s", again.Id, first.Id)
+	}
+	if other := createAt(t, s, "brew", fireTime, "req-2"); other.Id == first.Id {
+		t.Errorf("CreateTimer() with another request ID returned the same timer")
+	}
+	if first.MaxLatenessSecs != uint32(DefaultMaxLateness/time.Second) {
+		t.Errorf("max lateness = %ds, want the default", first.MaxLatenessSecs)
+	}
+}
+
+func TestRunFires(t *testing.T) {
+	b := newFakeBroadcaster(nfa_control_v1alpha.TargetState_TARGET_STATE_SUCCEEDED)
+	s := newScheduler(t, "", b)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	later := createAt(t, s, "lights_off", time.Now().Add(time.Hour), "")
+	soon := createAt(t, s, "brew", time.Now().Add(50*time.Millisecond), "")
+	delivered := waitForState(t, s, soon.Id, nfa_timer_v1alpha.TimerState_TIMER_STATE_DELIVERED)
+	if delivered.Attempts != 1 || delivered.FinishTime == nil || len(delivered.Targets) != 1 {
+		t.Errorf("delivered timer = %+v, want one attempt, a finish time and its target", delivered)
+	}
+	if got := <-b.fired; got != "brew" {
+		t.Errorf("broadcast %s, want brew", got)
+	}
+	if got := getState(t, s, later.Id).State; got != nfa_timer_v1alpha.TimerState_TIMER_STATE_PENDING {
+		t.Errorf("later timer is %s, want pending", stateName(got))
+	}
+}
+
+func TestFireRetriesUntilMaxLateness(t *testing.T) {
+	b := newFakeBroadcaster(nfa_control_v1alpha.TargetState_TARGET_STATE_FAILED)
+	s := newScheduler(t, "", b)
+	now := time.Date(2026, 1, 1, 7, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	timer := createAt(t, s, "brew", now, "")
+	e := s.timers[timer.Id]
+
+	s.fire(context.Background(), e)
+	got := getState(t, s, timer.Id)
+	if got.State != nfa_timer_v1alpha.TimerState_TIMER_STATE_PENDING || !strings.Contains(got.LastError, "rt-1 failed: offline") {
+		t.Fatalf("timer after a failed broadcast = %s %q, want pending with the failure", stateName(got.State), got.LastError)
+	}
+	if due, next := s.due(); len(due) != 0 || !next.Equal(now.Add(minRetryBackoff)) {
+		t.Errorf("due() = %d timers, next %v, want a retry after %v", len(due), next, minRetryBackoff)
+	}
+
+	now = now.Add(DefaultMaxLateness + time.Second)
+	s.fire(context.Background(), e)
+	got = getState(t, s, timer.Id)
+	if got.State != nfa_timer_v1alpha.TimerState_TIMER_STATE_FAILED || got.Attempts != 1 {
+		t.Errorf("timer past its max lateness = %s after %d attempts, want failed without another", stateName(got.State), got.Attempts)
+	}
+	if b.calls() != 1 {
+		t.Errorf("broadcast %d times, want once", b.calls())
+	}
+}
+
+func TestCancelTimer(t *testing.T) {
+	s := newScheduler(t, "", newFakeBroadcaster(nfa_control_v1alpha.TargetState_TARGET_STATE_SUCCEEDED))
+	timer := createAt(t, s, "brew", time.Now().Add(time.Hour), "")
+	cancelled, err := s.CancelTimer(context.Background(), &nfa_timer_v1alpha.CancelTimerRequest{Id: timer.Id})
+	if err != nil || cancelled.State != nfa_timer_v1alpha.TimerState_TIMER_STATE_CANCELLED {
+		t.Fatalf("CancelTimer() = %v, %v, want it cancelled", cancelled, err)
+	}
+	if _, err := s.CancelTimer(context.Background(), &nfa_timer_v1alpha.CancelTimerRequest{Id: timer.Id}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("CancelTimer() again error = %v, want FailedPrecondition", err)
+	}
+	if _, err := s.CancelTimer(context.Background(), &nfa_timer_v1alpha.CancelTimerRequest{Id: "timer-unknown"}); status.Code(err) != codes.NotFound {
+		t.Errorf("CancelTimer() of an unknown timer error = %v, want NotFound", err)
+	}
+	if due, _ := s.due(); len(due) != 0 {
+		t.Errorf("due() = %d timers, want a cancelled timer never fired", len(due))
+	}
+}
+
+func TestListTimers(t *testing.T) {
+	s := newScheduler(t, "", newFakeBroadcaster(nfa_control_v1alpha.TargetState_TARGET_STATE_SUCCEEDED))
+	now := time.Now()
+	third := createAt(t, s, "brew", now.Add(3*time.Hour), "")
+	first := createAt(t, s, "brew", now.Add(time.Hour), "")
+	second := createAt(t, s, "lights_off", now.Add(2*time.Hour), "")
+	s.CancelTimer(context.Background(), &nfa_timer_v1alpha.CancelTimerRequest{Id: second.Id})
+
+	list := func(req *nfa_timer_v1alpha.ListTimersRequest) []string {
+		resp, err := s.ListTimers(context.Background(), req)
+		if err != nil {
+			t.Fatalf("ListTimers() error = %v", err)
+		}
+		var ids []string
+		for _, timer := range resp.Timers {
+			ids = append(ids, timer.Id)
+		}
+		return ids
+	}
+	if got, want := list(&nfa_timer_v1alpha.ListTimersRequest{}), []string{first.Id, second.Id, third.Id}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ListTimers() = %v, want %v by fire time", got, want)
+	}
+	if got := list(&nfa_timer_v1alpha.ListTimersRequest{State: nfa_timer_v1alpha.TimerState_TIMER_STATE_PENDING, Action: "brew"}); len(got) != 2 {
+		t.Errorf("ListTimers() of pending brew timers = %v, want 2", got)
+	}
+}
+
+func TestReopenedSchedulerFires(t *testing.T) {
+	dir := t.TempDir()
+	b := newFakeBroadcaster(nfa_control_v1alpha.TargetState_TARGET_STATE_SUCCEEDED)
+	s := newScheduler(t, dir, b)
+	// Due while the broker is down
+	missed := createAt(t, s, "brew", time.Now().Add(10*time.Millisecond), "req-1")
+	cancelled := createAt(t, s, "lights_off", time.Now().Add(10*time.Millisecond), "")
+	s.CancelTimer(context.Background(), &nfa_timer_v1alpha.CancelTimerRequest{Id: cancelled.Id})
+	// Left over by an interrupted write
+	if err := os.WriteFile(filepath.Join(dir, "tmp-123"), []byte("partial"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	s = newScheduler(t, dir, b)
+	if _, err := os.Stat(filepath.Join(dir, "tmp-123")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("leftover temporary file kept, Stat() error = %v", err)
+	}
+	if again := createAt(t, s, "brew", time.Now(), "req-1"); again.Id != missed.Id {
+		t.Errorf("CreateTimer() with a request ID from before reopening = %s, want %s", again.Id, missed.Id)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+	waitForState(t, s, missed.Id, nfa_timer_v1alpha.TimerState_TIMER_STATE_DELIVERED)
+	if got := getState(t, s, cancelled.Id).State; got != nfa_timer_v1alpha.TimerState_TIMER_STATE_CANCELLED {
+		t.Errorf("cancelled timer is %s after reopening", stateName(got))
+	}
+	cancel()
+
+	// The outcome is kept too
+	s = newScheduler(t, dir, b)
+	if got := getState(t, s, missed.Id); got.State != nfa_timer_v1alpha.TimerState_TIMER_STATE_DELIVERED {
+		t.Errorf("timer is %s after reopening again, want delivered", stateName(got.State))
+	}
+	if b.calls() != 1 {
+		t.Errorf("broadcast %d times, want once", b.calls())
+	}
+}
+
+func TestSweep(t *testing.T) {
+	dir := t.TempDir()
+	s := newScheduler(t, dir, newFakeBroadcaster(nfa_control_v1alpha.TargetState_TARGET_STATE_SUCCEEDED))
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	timer := createAt(t, s, "brew", now.Add(time.Hour), "req-1")
+	s.CancelTimer(context.Background(), &nfa_timer_v1alpha.CancelTimerRequest{Id: timer.Id})
+
+	now = now.Add(Retention + time.Second)
+	s.sweep()
+	if _, err := s.GetTimer(context.Background(), &nfa_timer_v1alpha.GetTimerRequest{Id: timer.Id}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetTimer() after the retention error = %v, want NotFound", err)
+	}
+	if _, err := os.Stat(s.path(timer.Id)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("timer file kept after the retention, Stat() error = %v", err)
+	}
+	if again := createAt(t, s, "brew", now.Add(time.Hour), "req-1"); again.Id == timer.Id {
+		t.Errorf("CreateTimer() reused the request ID of a swept timer")
+	}
+}
@@ -2,10 +2,10 @@ package main
 
 import (
 	"log"
-	"os"
 	"path/filepath"
 
 	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/pluginpb"
 )
 
@@ -173,10 +173,15 @@ func generateService(g *protogen.GeneratedFile, service *protogen.Service) {
 	g.P("// ", service.Desc.Name(), " 服务端接口")
 	g.P("type ", service.GoName, "Server interface {")
 	for _, method := range service.Methods {
-		g.P("\t", method.GoName, "(context.Context, *", method.Input.GoIdent, ") (*", method.Output.GoIdent, ", error)")
+		g.P("\t", serverMethodSignature(g, service, method))
 	}
 	g.P("}")
 	g.P()
+
+	// 为流式方法生成服务端流接口
+	for _, method := range service.Methods {
+		generateServerStream(g, service, method)
+	}
 	
 	// 生成服务端注册函数
 	g.P("func Register", service.GoName, "Server(s grpc.ServiceRegistrar, srv ", service.GoName, "Server) {")
@@ -185,6 +190,81 @@ func generateService(g *protogen.GeneratedFile, service *protogen.Service) {
 	g.P()
 }
 
+// generateClientMethod 生成客户端方法：一元方法直接调用，流式方法返回客户端流
+func generateClientMethod(g *protogen.GeneratedFile, service *protogen.Service, method *protogen.Method) {
+	fullName := "/" + string(service.Desc.FullName()) + "/" + string(method.Desc.Name())
+	input := g.QualifiedGoIdent(method.Input.GoIdent)
+	output := g.QualifiedGoIdent(method.Output.GoIdent)
+	clientStreams := method.Desc.IsStreamingClient()
+	serverStreams := method.Desc.IsStreamingServer()
+
+	switch {
+	case clientStreams:
+		// 客户端流与双向流：调用方通过返回的流收发消息
+		g.P("func (c ", service.GoName, "Client) ", method.GoName, "(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStream, error) {")
+		g.P("\tdesc := &grpc.StreamDesc{StreamName: \"", method.GoName, "\", ClientStreams: true, ServerStreams: ", serverStreams, "}")
+		g.P("	return c.cc.NewStream(ctx, desc, \"", fullName, "\", opts...)")
+		g.P("}")
+	case serverStreams:
+		g.P("func (c ", service.GoName, "Client) ", method.GoName, "(ctx context.Context, in *", input, ", opts ...grpc.CallOption) (grpc.ClientStream, error) {")
+		g.P("\tdesc := &grpc.StreamDesc{StreamName: \"", method.GoName, "\", ServerStreams: true}")
+		g.P("\tstream, err := c.cc.NewStream(ctx, desc, \"", fullName, "\", opts...)")
+		g.P("\tif err != nil {")
+		g.P("\t\treturn nil, err")
+		g.P("\t}")
+		g.P("\tif err := stream.SendMsg(in); err != nil {")
+		g.P("\t\treturn nil, err")
+		g.P("\t}")
+		g.P("\treturn stream, stream.CloseSend()")
+		g.P("}")
+	default:
+		g.P("func (c ", service.GoName, "Client) ", method.GoName, "(ctx context.Context, in *", input, ", opts ...grpc.CallOption) (*", output, ", error) {")
+		g.P("\tout := new(", output, ")")
+		g.P("\tif err := c.cc.Invoke(ctx, \"", fullName, "\", in, out, opts...); err != nil {")
+		g.P("\t\treturn nil, err")
+		g.P("\t}")
+		g.P("\treturn out, nil")
+		g.P("}")
+	}
+	g.P()
+}
+
+// serverMethodSignature 按方法的流式模式生成服务端方法签名
+func serverMethodSignature(g *protogen.GeneratedFile, service *protogen.Service, method *protogen.Method) string {
+	input := g.QualifiedGoIdent(method.Input.GoIdent)
+	output := g.QualifiedGoIdent(method.Output.GoIdent)
+	stream := service.GoName + "_" + method.GoName + "Server"
+	switch {
+	case method.Desc.IsStreamingClient():
+		// 客户端流与双向流：请求和响应都通过流收发
+		return method.GoName + "(" + stream + ") error"
+	case method.Desc.IsStreamingServer():
+		return method.GoName + "(*" + input + ", " + stream + ") error"
+	default:
+		return method.GoName + "(context.Context, *" + input + ") (*" + output + ", error)"
+	}
+}
+
+// generateServerStream 生成流式方法的服务端流接口，一元方法不生成
+func generateServerStream(g *protogen.GeneratedFile, service *protogen.Service, method *protogen.Method) {
+	if !method.Desc.IsStreamingClient() && !method.Desc.IsStreamingServer() {
+		return
+	}
+	g.P("type ", service.GoName, "_", method.GoName, "Server interface {")
+	if method.Desc.IsStreamingServer() {
+		g.P("\tSend(*", method.Output.GoIdent, ") error")
+	}
+	if method.Desc.IsStreamingClient() {
+		if !method.Desc.IsStreamingServer() {
+			g.P("\tSendAndClose(*", method.Output.GoIdent, ") error")
+		}
+		g.P("\tRecv() (*", method.Input.GoIdent, ", error)")
+	}
+	g.P("\tgrpc.ServerStream")
+	g.P("}")
+	g.P()
+}
+
 // 辅助函数
 func goType(g *protogen.GeneratedFile, field *protogen.Field) string {
 	// 实现Go类型映射
@@ -222,13 +302,16 @@ func protocVersion() string {
 }
 
 func getRustPackageOption(file *protogen.File) string {
-	// 从proto文件选项中获取Rust包名
-	for _, option := range file.Proto.Options.ProtoReflect().Range() {
-		if option.Descriptor().FullName() == "rust_package" {
-			return option.String()
+	// 从proto文件选项中获取Rust包名（rust_package扩展选项）
+	var rustPackage string
+	file.Proto.GetOptions().ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Name() == "rust_package" {
+			rustPackage = v.String()
+			return false
 		}
-	}
-	return ""
+		return true
+	})
+	return rustPackage
 }
 
 // Rust代码生成函数（简化实现）
@@ -277,9 +360,9 @@ func rustType(field *protogen.Field) string {
 	case protoreflect.BytesKind:
 		return "Vec<u8>"
 	case protoreflect.MessageKind:
-		return field.Message.Desc.Name()
+		return string(field.Message.Desc.Name())
 	case protoreflect.EnumKind:
-		return field.Enum.Desc.Name()
+		return string(field.Enum.Desc.Name())
 	default:
 		return "()"
 	}
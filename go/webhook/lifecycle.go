@@ -0,0 +1,39 @@
+package webhook
+
+import "github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+
+// Lifecycle emits the registry changes of an embedded broker as events of
+// d, with broker.WithLifecycle: registrations as service.registered,
+// deregistrations, removals and reaping as service.unregistered, and the
+// leases ending without a heartbeat as provider.unhealthy
+func Lifecycle(d *Dispatcher) broker.Lifecycle {
+	return lifecycle{d}
+}
+
+type lifecycle struct {
+	d *Dispatcher
+}
+
+func (l lifecycle) Registered(serviceID, contract string, resumed bool) {
+	l.d.Emit(EventServiceRegistered, map[string]interface{}{
+		"service_id": serviceID,
+		"contract":   contract,
+		"resumed":    resumed,
+	})
+}
+
+func (l lifecycle) Unregistered(serviceID, contract, reason string) {
+	l.d.Emit(EventServiceUnregistered, map[string]interface{}{
+		"service_id": serviceID,
+		"contract":   contract,
+		"reason":     reason,
+	})
+}
+
+func (l lifecycle) Expired(serviceID, contract string) {
+	l.d.Emit(EventProviderUnhealthy, map[string]interface{}{
+		"service_id": serviceID,
+		"contract":   contract,
+		"reason":     "lease_expired",
+	})
+}
@@ -0,0 +1,34 @@
+package webhook
+
+import nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+
+// SLOViolations returns a function emitting slo.violated events of d for
+// the providers of an SLA report that missed their objectives, to pass to
+// sla.Recorder.RunPeriodic
+func SLOViolations(d *Dispatcher) func(*nfa_admin_v1alpha.SLAReport) {
+	return func(report *nfa_admin_v1alpha.SLAReport) {
+		for _, p := range report.Providers {
+			if p.Met {
+				continue
+			}
+			kinds := make([]string, 0, len(p.Violations))
+			details := make([]string, 0, len(p.Violations))
+			for _, v := range p.Violations {
+				kinds = append(kinds, v.Kind)
+				details = append(details, v.Detail)
+			}
+			d.Emit(EventSLOViolated, map[string]interface{}{
+				"service_id":             p.ServiceId,
+				"namespace":              p.Namespace,
+				"start_unix":             report.StartUnix,
+				"end_unix":               report.EndUnix,
+				"availability":           p.Availability,
+				"availability_objective": p.AvailabilityObjective,
+				"latency_p99_ms":         p.LatencyP99Ms,
+				"latency_objective_ms":   p.LatencyObjectiveMs,
+				"violations":             kinds,
+				"details":                details,
+			})
+		}
+	}
+}
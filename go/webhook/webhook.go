@@ -0,0 +1,354 @@
+// Package webhook notifies operator-registered HTTP endpoints of intent
+// lifecycle events. Payloads are signed with a per-webhook HMAC secret,
+// failed deliveries are retried with backoff and every delivery's status is
+// kept for inspection.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/redact"
+	nfa_webhook_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/webhook/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Lifecycle event types
+const (
+	EventServiceRegistered   = "service.registered"
+	EventServiceUnregistered = "service.unregistered"
+	EventProviderUnhealthy   = "provider.unhealthy"
+	EventSLOViolated         = "slo.violated"
+	EventProviderScale       = "provider.scale"
+)
+
+// Headers set on every delivery
+const (
+	HeaderEvent     = "X-NFA-Event"
+	HeaderDelivery  = "X-NFA-Delivery"
+	HeaderTimestamp = "X-NFA-Timestamp"
+	HeaderSignature = "X-NFA-Signature"
+)
+
+const (
+	maxAttempts     = 6
+	initialBackoff  = time.Second
+	maxBackoff      = 5 * time.Minute
+	requestTimeout  = 10 * time.Second
+	maxDeliveries   = 1000
+	maxConcurrent   = 16
+	generatedSecret = 32
+)
+
+// Event is the JSON body posted to webhooks
+type Event struct {
+	ID   string                 `json:"id"`
+	Type string                 `json:"type"`
+	Time time.Time              `json:"time"`
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// Dispatcher stores webhooks and delivers events to them. It implements the
+// WebhookService API.
+type Dispatcher struct {
+	nfa_webhook_v1alpha.UnimplementedWebhookServiceServer
+
+	client *http.Client
+	slots  chan struct{}
+	// sleep waits out the backoff between attempts
+	sleep func(time.Duration)
+
+	mu         sync.Mutex
+	hooks      map[string]*hook
+	deliveries []*nfa_webhook_v1alpha.Delivery // oldest first
+	listeners  []func(Event)
+	redactor   *redact.Redactor
+}
+
+type hook struct {
+	webhook *nfa_webhook_v1alpha.Webhook // without secret
+	secret  []byte
+}
+
+// NewDispatcher creates a dispatcher without webhooks
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		client: &http.Client{Timeout: requestTimeout},
+		slots:  make(chan struct{}, maxConcurrent),
+		sleep:  time.Sleep,
+		hooks:  make(map[string]*hook),
+	}
+}
+
+// Register registers the webhook service on a gRPC server
+func (d *Dispatcher) Register(registrar grpc.ServiceRegistrar) {
+	nfa_webhook_v1alpha.RegisterWebhookServiceServer(registrar, d)
+}
+
+// CreateWebhook implements the CreateWebhook RPC
+func (d *Dispatcher) CreateWebhook(ctx context.Context, req *nfa_webhook_v1alpha.CreateWebhookRequest) (*nfa_webhook_v1alpha.Webhook, error) {
+	u, err := url.Parse(req.Url)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "webhook url must be an absolute http(s) URL, got %q", req.Url)
+	}
+	secret := req.Secret
+	if secret == "" {
+		secret = randomHex(generatedSecret)
+	}
+
+	h := &hook{
+		webhook: &nfa_webhook_v1alpha.Webhook{
+			Id:         "wh-" + randomHex(8),
+			Url:        req.Url,
+			Events:     req.Events,
+			CreateTime: timestamppb.Now(),
+		},
+		secret: []byte(secret),
+	}
+	d.mu.Lock()
+	d.hooks[h.webhook.Id] = h
+	d.mu.Unlock()
+	log.Printf("Registered webhook %s for %s", h.webhook.Id, u.Host)
+
+	created := proto.Clone(h.webhook).(*nfa_webhook_v1alpha.Webhook)
+	created.Secret = secret
+	return created, nil
+}
+
+// ListWebhooks implements the ListWebhooks RPC
+func (d *Dispatcher) ListWebhooks(ctx context.Context, req *nfa_webhook_v1alpha.ListWebhooksRequest) (*nfa_webhook_v1alpha.ListWebhooksResponse, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	resp := &nfa_webhook_v1alpha.ListWebhooksResponse{}
+	for _, h := range d.hooks {
+		resp.Webhooks = append(resp.Webhooks, proto.Clone(h.webhook).(*nfa_webhook_v1alpha.Webhook))
+	}
+	sort.Slice(resp.Webhooks, func(i, j int) bool {
+		return resp.Webhooks[i].CreateTime.AsTime().Before(resp.Webhooks[j].CreateTime.AsTime())
+	})
+	return resp, nil
+}
+
+// DeleteWebhook implements the DeleteWebhook RPC. Deliveries in progress are abandoned.
+func (d *Dispatcher) DeleteWebhook(ctx context.Context, req *nfa_webhook_v1alpha.DeleteWebhookRequest) (*nfa_webhook_v1alpha.DeleteWebhookResponse, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.hooks[req.Id]; !ok {
+		return nil, status.Errorf(codes.NotFound, "webhook %s not found", req.Id)
+	}
+	delete(d.hooks, req.Id)
+	return &nfa_webhook_v1alpha.DeleteWebhookResponse{}, nil
+}
+
+// ListDeliveries implements the ListDeliveries RPC
+func (d *Dispatcher) ListDeliveries(ctx context.Context, req *nfa_webhook_v1alpha.ListDeliveriesRequest) (*nfa_webhook_v1alpha.ListDeliveriesResponse, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	resp := &nfa_webhook_v1alpha.ListDeliveriesResponse{}
+	for i := len(d.deliveries) - 1; i >= 0; i-- {
+		delivery := d.deliveries[i]
+		if req.WebhookId != "" && delivery.WebhookId != req.WebhookId {
+			continue
+		}
+		if req.Status != nfa_webhook_v1alpha.DeliveryStatus_DELIVERY_STATUS_UNSPECIFIED && delivery.Status != req.Status {
+			continue
+		}
+		resp.Deliveries = append(resp.Deliveries, proto.Clone(delivery).(*nfa_webhook_v1alpha.Delivery))
+	}
+	return resp, nil
+}
+
+// OnEmit registers a callback invoked with every emitted event, e.g. to
+// mirror lifecycle events to a pub/sub topic
+func (d *Dispatcher) OnEmit(fn func(Event)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.listeners = append(d.listeners, fn)
+}
+
+// SetRedactor masks sensitive intent parameters in the data of emitted
+// events, see redact.Redactor.Data
+func (d *Dispatcher) SetRedactor(r *redact.Redactor) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.redactor = r
+}
+
+// Emit delivers an event to every webhook subscribed to its type. It returns
+// immediately; deliveries are retried in the background.
+func (d *Dispatcher) Emit(eventType string, data map[string]interface{}) {
+	d.mu.Lock()
+	redactor := d.redactor
+	d.mu.Unlock()
+
+	event := Event{
+		ID:   "evt-" + randomHex(8),
+		Type: eventType,
+		Time: time.Now().UTC(),
+		Data: redactor.Data(data),
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to encode webhook event %s: %v", eventType, err)
+		return
+	}
+
+	// Listeners are called without the lock, so they may call d
+	d.mu.Lock()
+	listeners := slices.Clone(d.listeners)
+	d.mu.Unlock()
+	for _, fn := range listeners {
+		fn(event)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, h := range d.hooks {
+		if !subscribed(h.webhook.Events, eventType) {
+			continue
+		}
+		delivery := &nfa_webhook_v1alpha.Delivery{
+			Id:         "dlv-" + randomHex(8),
+			WebhookId:  h.webhook.Id,
+			EventId:    event.ID,
+			EventType:  eventType,
+			Status:     nfa_webhook_v1alpha.DeliveryStatus_DELIVERY_STATUS_PENDING,
+			CreateTime: timestamppb.Now(),
+		}
+		d.deliveries = append(d.deliveries, delivery)
+		if len(d.deliveries) > maxDeliveries {
+			d.deliveries = d.deliveries[1:]
+		}
+		go d.deliver(h, delivery, body)
+	}
+}
+
+// deliver posts body until it succeeds, fails permanently or runs out of attempts
+func (d *Dispatcher) deliver(h *hook, delivery *nfa_webhook_v1alpha.Delivery, body []byte) {
+	backoff := initialBackoff
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		d.slots <- struct{}{}
+		code, err := d.post(h, delivery, body)
+		<-d.slots
+
+		retry := err != nil || code == http.StatusTooManyRequests || code >= 500
+		d.mu.Lock()
+		delivery.Attempts = uint32(attempt)
+		delivery.LastStatusCode = uint32(code)
+		delivery.LastAttemptTime = timestamppb.Now()
+		delivery.LastError = ""
+		switch {
+		case err != nil:
+			delivery.LastError = err.Error()
+		case code >= 300:
+			delivery.LastError = fmt.Sprintf("endpoint responded %d", code)
+		}
+		_, active := d.hooks[h.webhook.Id]
+		if delivery.LastError == "" {
+			delivery.Status = nfa_webhook_v1alpha.DeliveryStatus_DELIVERY_STATUS_SUCCEEDED
+		} else if !retry || !active || attempt == maxAttempts {
+			delivery.Status = nfa_webhook_v1alpha.DeliveryStatus_DELIVERY_STATUS_FAILED
+		}
+		done := delivery.Status != nfa_webhook_v1alpha.DeliveryStatus_DELIVERY_STATUS_PENDING
+		d.mu.Unlock()
+
+		if done {
+			if delivery.Status == nfa_webhook_v1alpha.DeliveryStatus_DELIVERY_STATUS_FAILED {
+				log.Printf("Webhook %s delivery %s failed after %d attempts: %s", h.webhook.Id, delivery.Id, attempt, delivery.LastError)
+			}
+			return
+		}
+		d.sleep(backoff)
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+func (d *Dispatcher) post(h *hook, delivery *nfa_webhook_v1alpha.Delivery, body []byte) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequest(http.MethodPost, h.webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "nfa-webhook/1")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.Id)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(h.secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// Sign computes the signature header value for a delivery:
+// "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body))
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a delivery's signature and rejects timestamps older than
+// maxAge, so receivers can authenticate deliveries and refuse replays
+func Verify(secret []byte, timestamp string, body []byte, signature string, maxAge time.Duration) error {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s header", HeaderTimestamp)
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > maxAge || age < -maxAge {
+		return fmt.Errorf("delivery timestamp is outside the allowed window")
+	}
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+func subscribed(events []string, eventType string) bool {
+	if len(events) == 0 {
+		return true
+	}
+	for _, e := range events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
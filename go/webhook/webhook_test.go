@@ -0,0 +1,322 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neuro-fluidic-architecture/nfa-core/go/pkg/broker"
+	nfa_admin_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/admin/v1alpha"
+	nfa_broker_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/broker/v1alpha"
+	nfa_intent_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/intent/v1alpha"
+	nfa_webhook_v1alpha "github.com/neuro-fluidic-architecture/nfa-core/go/protos/webhook/v1alpha"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret := []byte("s3cret")
+	body := []byte(`{"type":"service.registered"}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	// echo -n '1700000000.{"type":"service.registered"}' | openssl dgst -sha256 -hmac s3cret
+	want := "sha256=206ffd87192b8e01cb80ad4f1bdf7e30ce7dd38b8e9f3976f34a5bae92cb13f9"
+	if got := Sign(secret, "1700000000", body); got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+	signature := Sign(secret, now, body)
+	if err := Verify(secret, now, body, signature, time.Minute); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	for name, err := range map[string]error{
+		"wrong secret":   Verify([]byte("other"), now, body, signature, time.Minute),
+		"tampered body":  Verify(secret, now, []byte(`{"type":"slo.violated"}`), signature, time.Minute),
+		"other time":     Verify(secret, strconv.FormatInt(time.Now().Unix()-1, 10), body, signature, time.Minute),
+		"replayed":       Verify(secret, old, body, Sign(secret, old, body), time.Minute),
+		"bad timestamp":  Verify(secret, "yesterday", body, signature, time.Minute),
+		"missing prefix": Verify(secret, now, body, strings.TrimPrefix(signature, "sha256="), time.Minute),
+	} {
+		if err == nil {
+			t.Errorf("Verify() with %s succeeded, want an error", name)
+		}
+	}
+}
+
+// receiver is an endpoint answering deliveries with the status codes given,
+// then 200
+type receiver struct {
+	t      *testing.T
+	secret string
+
+	mu       sync.Mutex
+	statuses []int
+	events   []Event
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if err := Verify([]byte(rc.secret), r.Header.Get(HeaderTimestamp), body, r.Header.Get(HeaderSignature), time.Minute); err != nil {
+		rc.t.Errorf("delivery does not verify: %v", err)
+	}
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		rc.t.Errorf("delivery body %s: %v", body, err)
+	}
+	if r.Header.Get(HeaderEvent) != event.Type || !strings.HasPrefix(r.Header.Get(HeaderDelivery), "dlv-") {
+		rc.t.Errorf("delivery headers %v, want the event type and delivery ID", r.Header)
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.events = append(rc.events, event)
+	code := http.StatusOK
+	if len(rc.statuses) > 0 {
+		code, rc.statuses = rc.statuses[0], rc.statuses[1:]
+	}
+	w.WriteHeader(code)
+}
+
+// newTestDispatcher creates a dispatcher with a webhook for url, recording
+// the backoffs it sleeps instead of waiting
+func newTestDispatcher(t *testing.T, url string, events ...string) (*Dispatcher, *nfa_webhook_v1alpha.Webhook, func() []time.Duration) {
+	t.Helper()
+	d := NewDispatcher()
+	var mu sync.Mutex
+	var backoffs []time.Duration
+	d.sleep = func(backoff time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		backoffs = append(backoffs, backoff)
+	}
+	h, err := d.CreateWebhook(context.Background(), &nfa_webhook_v1alpha.CreateWebhookRequest{Url: url, Secret: "s3cret", Events: events})
+	if err != nil {
+		t.Fatalf("CreateWebhook() error = %v", err)
+	}
+	return d, h, func() []time.Duration {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]time.Duration(nil), backoffs...)
+	}
+}
+
+// settled waits for the deliveries of d to succeed or fail and returns them
+func settled(t *testing.T, d *Dispatcher) []*nfa_webhook_v1alpha.Delivery {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, _ := d.ListDeliveries(context.Background(), &nfa_webhook_v1alpha.ListDeliveriesRequest{
+			Status: nfa_webhook_v1alpha.DeliveryStatus_DELIVERY_STATUS_PENDING,
+		})
+		if len(resp.Deliveries) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("deliveries still pending: %v", resp.Deliveries)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	resp, _ := d.ListDeliveries(context.Background(), &nfa_webhook_v1alpha.ListDeliveriesRequest{})
+	return resp.Deliveries
+}
+
+func TestDeliver(t *testing.T) {
+	rc := &receiver{t: t, secret: "s3cret"}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+	d, h, backoffs := newTestDispatcher(t, srv.URL, EventServiceRegistered)
+
+	d.Emit(EventServiceRegistered, map[string]interface{}{"service_id": "svc-1"})
+	d.Emit(EventSLOViolated, nil) // not subscribed
+	deliveries := settled(t, d)
+	if len(deliveries) != 1 {
+		t.Fatalf("deliveries = %v, want one of the subscribed event", deliveries)
+	}
+	dl := deliveries[0]
+	if dl.Status != nfa_webhook_v1alpha.DeliveryStatus_DELIVERY_STATUS_SUCCEEDED || dl.Attempts != 1 || dl.LastStatusCode != 200 || dl.WebhookId != h.Id {
+		t.Errorf("delivery = %v, want it succeeded on the first attempt", dl)
+	}
+	if len(rc.events) != 1 || rc.events[0].ID != dl.EventId || rc.events[0].Data["service_id"] != "svc-1" {
+		t.Errorf("received %v, want the event with its data", rc.events)
+	}
+	if got := backoffs(); len(got) != 0 {
+		t.Errorf("backoffs = %v, want none", got)
+	}
+}
+
+func TestDeliverRetries(t *testing.T) {
+	rc := &receiver{t: t, secret: "s3cret", statuses: []int{503, http.StatusTooManyRequests}}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+	d, _, backoffs := newTestDispatcher(t, srv.URL)
+
+	d.Emit(EventProviderUnhealthy, nil)
+	dl := settled(t, d)[0]
+	if dl.Status != nfa_webhook_v1alpha.DeliveryStatus_DELIVERY_STATUS_SUCCEEDED || dl.Attempts != 3 || dl.LastError != "" {
+		t.Errorf("delivery = %v, want it succeeded on the third attempt", dl)
+	}
+	if got := backoffs(); !slices.Equal(got, []time.Duration{time.Second, 2 * time.Second}) {
+		t.Errorf("backoffs = %v, want 1s then 2s", got)
+	}
+	if len(rc.events) != 3 || rc.events[0].ID != rc.events[2].ID {
+		t.Errorf("received %d events, want the same event 3 times", len(rc.events))
+	}
+}
+
+func TestDeliverFailures(t *testing.T) {
+	t.Run("rejected", func(t *testing.T) {
+		srv := httptest.NewServer(&receiver{t: t, secret: "s3cret", statuses: []int{http.StatusBadRequest}})
+		defer srv.Close()
+		d, _, backoffs := newTestDispatcher(t, srv.URL)
+		d.Emit(EventProviderScale, nil)
+		dl := settled(t, d)[0]
+		if dl.Status != nfa_webhook_v1alpha.DeliveryStatus_DELIVERY_STATUS_FAILED || dl.Attempts != 1 || dl.LastError != "endpoint responded 400" {
+			t.Errorf("delivery = %v, want it failed without a retry", dl)
+		}
+		if got := backoffs(); len(got) != 0 {
+			t.Errorf("backoffs = %v, want none", got)
+		}
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+		d, _, backoffs := newTestDispatcher(t, srv.URL)
+		d.Emit(EventProviderScale, nil)
+		dl := settled(t, d)[0]
+		if dl.Status != nfa_webhook_v1alpha.DeliveryStatus_DELIVERY_STATUS_FAILED || dl.Attempts != maxAttempts || dl.LastStatusCode != 500 {
+			t.Errorf("delivery = %v, want it failed after %d attempts", dl, maxAttempts)
+		}
+		want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second}
+		if got := backoffs(); !slices.Equal(got, want) {
+			t.Errorf("backoffs = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		url := srv.URL
+		srv.Close()
+		d, _, _ := newTestDispatcher(t, url)
+		d.Emit(EventProviderScale, nil)
+		dl := settled(t, d)[0]
+		if dl.Status != nfa_webhook_v1alpha.DeliveryStatus_DELIVERY_STATUS_FAILED || dl.Attempts != maxAttempts || dl.LastStatusCode != 0 || dl.LastError == "" {
+			t.Errorf("delivery = %v, want it failed with the connection error", dl)
+		}
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+		d, h, _ := newTestDispatcher(t, srv.URL)
+		deleted := make(chan struct{})
+		d.sleep = func(time.Duration) { <-deleted }
+		d.Emit(EventProviderScale, nil)
+		if _, err := d.DeleteWebhook(context.Background(), &nfa_webhook_v1alpha.DeleteWebhookRequest{Id: h.Id}); err != nil {
+			t.Fatalf("DeleteWebhook() error = %v", err)
+		}
+		close(deleted)
+		dl := settled(t, d)[0]
+		if dl.Status != nfa_webhook_v1alpha.DeliveryStatus_DELIVERY_STATUS_FAILED || dl.Attempts > 2 {
+			t.Errorf("delivery = %v, want it abandoned once the webhook was deleted", dl)
+		}
+	})
+}
+
+func TestEmitListenerCallsDispatcher(t *testing.T) {
+	d := NewDispatcher()
+	listed := make(chan int, 1)
+	d.OnEmit(func(Event) {
+		resp, _ := d.ListWebhooks(context.Background(), &nfa_webhook_v1alpha.ListWebhooksRequest{})
+		listed <- len(resp.Webhooks)
+	})
+	d.Emit(EventServiceRegistered, nil)
+	select {
+	case <-listed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("listener calling the dispatcher deadlocked")
+	}
+}
+
+func TestBrokerLifecycle(t *testing.T) {
+	rc := &receiver{t: t, secret: "s3cret"}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+	d, _, _ := newTestDispatcher(t, srv.URL)
+	b := broker.NewEmbedded(broker.WithLifecycle(Lifecycle(d)), broker.WithLivenessTimeout(200*time.Millisecond))
+	defer b.Close()
+	conn, err := b.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := nfa_broker_v1alpha.NewIntentBrokerClient(conn)
+	ctx := context.Background()
+	register := func(name string) string {
+		resp, err := client.RegisterIntent(ctx, &nfa_broker_v1alpha.RegisterIntentRequest{
+			Contract: &nfa_intent_v1alpha.IntentContract{Metadata: &nfa_intent_v1alpha.Metadata{Name: name}},
+		})
+		if err != nil {
+			t.Fatalf("RegisterIntent() error = %v", err)
+		}
+		return resp.ServiceId
+	}
+
+	translator := register("translator")
+	if _, err := client.UnregisterIntent(ctx, &nfa_broker_v1alpha.UnregisterIntentRequest{ServiceId: translator}); err != nil {
+		t.Fatalf("UnregisterIntent() error = %v", err)
+	}
+	lights := register("lights")
+	want := []string{
+		EventProviderUnhealthy + " " + lights,
+		EventServiceRegistered + " " + lights,
+		EventServiceRegistered + " " + translator,
+		EventServiceUnregistered + " " + translator,
+	}
+
+	// lights sends no heartbeat, so its lease ends
+	var got []string
+	deadline := time.Now().Add(5 * time.Second)
+	for !slices.Equal(got, want) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		rc.mu.Lock()
+		got = got[:0]
+		for _, event := range rc.events {
+			got = append(got, event.Type+" "+event.Data["service_id"].(string))
+		}
+		rc.mu.Unlock()
+		slices.Sort(got)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("delivered %v, want %v", got, want)
+	}
+}
+
+func TestSLOViolations(t *testing.T) {
+	d := NewDispatcher()
+	var emitted []Event
+	d.OnEmit(func(event Event) { emitted = append(emitted, event) })
+	SLOViolations(d)(&nfa_admin_v1alpha.SLAReport{Providers: []*nfa_admin_v1alpha.ProviderSLA{
+		{ServiceId: "lights-1", Met: true},
+		{
+			ServiceId:  "heating-1",
+			Namespace:  "home",
+			Violations: []*nfa_admin_v1alpha.SLAViolation{{Kind: "availability", Detail: "availability 75.00% below 99.00%"}},
+		},
+	}})
+	if len(emitted) != 1 || emitted[0].Type != EventSLOViolated || emitted[0].Data["service_id"] != "heating-1" {
+		t.Fatalf("emitted %v, want slo.violated for heating-1 only", emitted)
+	}
+	if kinds := emitted[0].Data["violations"].([]string); !slices.Equal(kinds, []string{"availability"}) {
+		t.Errorf("violations = %v, want availability", kinds)
+	}
+}
@@ -0,0 +1,42 @@
+#!/bin/bash
+
+# Report incompatible changes to the stable Go API (go/pkg/...) relative to a
+# base revision, the latest tag by default
+set -e
+
+BASE=${BASE:-$(git describe --tags --abbrev=0 2>/dev/null || echo "")}
+if [ -z "${BASE}" ]; then
+    echo "No base revision; set BASE or create a release tag"
+    exit 1
+fi
+
+APIDIFF="go run golang.org/x/exp/cmd/apidiff@latest"
+ROOT=$(git rev-parse --show-toplevel)
+WORK=$(mktemp -d)
+trap 'git -C "${ROOT}" worktree remove --force "${WORK}/base" >/dev/null 2>&1; rm -rf "${WORK}"' EXIT
+
+git -C "${ROOT}" worktree add --detach "${WORK}/base" "${BASE}" >/dev/null
+
+PACKAGES=$(cd "${ROOT}/go" && go list ./pkg/...)
+STATUS=0
+for pkg in ${PACKAGES}; do
+    dir=${pkg#github.com/neuro-fluidic-architecture/nfa-core/go/}
+    if [ ! -d "${WORK}/base/go/${dir}" ]; then
+        echo "${pkg}: new package"
+        continue
+    fi
+    (cd "${WORK}/base/go" && ${APIDIFF} -w "${WORK}/base.api" "./${dir}")
+    (cd "${ROOT}/go" && ${APIDIFF} -w "${WORK}/head.api" "./${dir}")
+
+    report=$(${APIDIFF} -incompatible "${WORK}/base.api" "${WORK}/head.api")
+    if [ -n "${report}" ]; then
+        echo "${pkg}: incompatible changes since ${BASE}"
+        echo "${report}"
+        STATUS=1
+    fi
+done
+
+if [ ${STATUS} -eq 0 ]; then
+    echo "No incompatible API changes since ${BASE}"
+fi
+exit ${STATUS}